@@ -14,6 +14,8 @@
 package blockchain
 
 import (
+	"github.com/google/uuid"
+
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
 )
@@ -27,6 +29,8 @@ const (
 	TaskFinished   = "Finished"   // task finished
 	TaskFailed     = "Failed"     // task failed
 	TaskRejected   = "Rejected"   // task rejected by one of the Executors
+	TaskCancelled  = "Cancelled"  // task cancelled by the requester during execution
+	TaskTimeout    = "Timeout"    // task cancelled as it exceeded its maximum execution time
 
 	/* Define Task Type stored in Contract */
 	TaskTypeTrain   = "train"   // training task
@@ -36,20 +40,65 @@ const (
 	AlgorithmVLine = "linear-vl"       // linear regression with multiple variables in vertical federated learning
 	AlgorithmVLog  = "logistic-vl"     // logistic regression with multiple variables in vertical federated learning
 	AlgorithmVDnn  = "dnn-paddlefl-vl" // dnn implemented using paddlefl
+	AlgorithmVXgb  = "xgboost-vl"      // xgboost in vertical federated learning
 
 	/* Define Regularization stored in Contract */
 	RegModeL1 = "l1" // L1-norm
 	RegModeL2 = "l2" // L2-norm
 
+	/* Define Evaluation Modes, the names of EvaluationRule */
+	EvalModeRandomSplit = "random" // divide the aligned samples randomly by percentage
+	EvalModeKFold       = "kfold"  // K-fold cross validation, the aligned samples are divided into K folds
+	EvalModeLOO         = "loo"    // leave one out
+
+	/* Define Evaluation Metrics */
+	MetricRMSE      = "RMSE"      // root mean squared error, for regression
+	MetricR2        = "R2"        // coefficient of determination, for regression
+	MetricAccuracy  = "Accuracy"  // for binary classification
+	MetricPrecision = "Precision" // for binary classification
+	MetricRecall    = "Recall"    // for binary classification
+	MetricF1Score   = "F1Score"   // for binary classification
+	MetricAUC       = "AUC"       // area under ROC for binary classification, the points on ROC are reported with it
+
 	/* Define the maximum number of task list query */
 	TaskListMaxNum = 100
 )
 
+// EvalModeListName the mapping of evaluation mode name and value
+var EvalModeListName = map[string]pbCom.EvaluationRule{
+	EvalModeRandomSplit: pbCom.EvaluationRule_ErRandomSplit,
+	EvalModeKFold:       pbCom.EvaluationRule_ErCrossVal,
+	EvalModeLOO:         pbCom.EvaluationRule_ErLOO,
+}
+
+// KFoldSupported the numbers of folds supported in K-fold cross validation
+var KFoldSupported = map[int32]bool{5: true, 10: true}
+
+// PSIAlgorithmSupported the PSI algorithms of vertical learning, 'oprf' is not supported by dnn-paddlefl-vl
+var PSIAlgorithmSupported = map[string]bool{"ecdh": true, "oprf": true, "auto": true}
+
+// EvalMetricsSupported the evaluation metrics supported by each algorithm,
+// all of them are computed if a task specifies no metric
+var EvalMetricsSupported = map[pbCom.Algorithm][]string{
+	pbCom.Algorithm_LINEAR_REGRESSION_VL: {MetricRMSE, MetricR2},
+	pbCom.Algorithm_LOGIC_REGRESSION_VL:  {MetricAccuracy, MetricPrecision, MetricRecall, MetricF1Score, MetricAUC},
+}
+
+// LiveMetricsSupported the metrics of live evaluation of each algorithm, which early stopping is based on
+var LiveMetricsSupported = map[pbCom.Algorithm][]string{
+	pbCom.Algorithm_LINEAR_REGRESSION_VL: {MetricRMSE},
+	pbCom.Algorithm_LOGIC_REGRESSION_VL:  {MetricAccuracy, MetricPrecision, MetricRecall, MetricF1Score},
+}
+
+// MetricsMinimized the metrics that are better when lower, the others are better when higher
+var MetricsMinimized = map[string]bool{MetricRMSE: true}
+
 // VlAlgorithmListName the mapping of vertical algorithm name and value
 var VlAlgorithmListName = map[string]pbCom.Algorithm{
 	AlgorithmVLine: pbCom.Algorithm_LINEAR_REGRESSION_VL,
 	AlgorithmVLog:  pbCom.Algorithm_LOGIC_REGRESSION_VL,
 	AlgorithmVDnn:  pbCom.Algorithm_DNN_PADDLEFL_VL,
+	AlgorithmVXgb:  pbCom.Algorithm_XGBOOST_VL,
 }
 
 // VlAlgorithmListValue the mapping of vertical algorithm value and name
@@ -58,6 +107,17 @@ var VlAlgorithmListValue = map[pbCom.Algorithm]string{
 	pbCom.Algorithm_LINEAR_REGRESSION_VL: AlgorithmVLine,
 	pbCom.Algorithm_LOGIC_REGRESSION_VL:  AlgorithmVLog,
 	pbCom.Algorithm_DNN_PADDLEFL_VL:      AlgorithmVDnn,
+	pbCom.Algorithm_XGBOOST_VL:           AlgorithmVXgb,
+}
+
+// VlAlgorithmParties the number of parties required by each vertical algorithm
+// algorithms based on Paillier are two-party, "dnn-paddlefl-vl" is three-party as PaddleFL MPC uses ABY3,
+// a task with a different number of data sets is rejected when it is published
+var VlAlgorithmParties = map[pbCom.Algorithm]int{
+	pbCom.Algorithm_LINEAR_REGRESSION_VL: 2,
+	pbCom.Algorithm_LOGIC_REGRESSION_VL:  2,
+	pbCom.Algorithm_DNN_PADDLEFL_VL:      3,
+	pbCom.Algorithm_XGBOOST_VL:           2,
 }
 
 // TaskTypeListName the mapping of train task type name and value
@@ -88,10 +148,12 @@ var RegModeListValue = map[pbCom.RegMode]string{
 
 // FLInfo used to parse the content contained in the extra field of the file on the chain,
 // only files that can be parsed can be used for task training or prediction
+// If Checksum is set, executors verify the downloaded file against it and the task fails on mismatch.
 type FLInfo struct {
-	FileType  string `json:"fileType"`  // file type, only supports "csv"
-	Features  string `json:"features"`  // feature list
-	TotalRows int64  `json:"totalRows"` // total number of samples
+	FileType  string `json:"fileType"`           // file type, only supports "csv"
+	Features  string `json:"features"`           // feature list
+	TotalRows int64  `json:"totalRows"`          // total number of samples
+	Checksum  string `json:"checksum,omitempty"` // checksum of the file content, e.g. "sha256:<hex digest>"
 }
 
 // ExecutorNode has access to samples with which to train models or to predict,
@@ -104,6 +166,12 @@ type ExecutorNode struct {
 	PaddleFLAddress string `json:"paddleFLAddress"`
 	PaddleFLRole    int    `json:"paddleFLRole"`
 	RegTime         int64  `json:"regTime"` // node registering time
+	// RotatedTo is the ID of the node after its key is rotated, see RotateKeyOptions. The node keeps
+	// executing the tasks published to it with the old key, and confirms no tasks after GraceUntil,
+	// or once the old key is revoked by the new key, see RevokeKeyOptions.
+	RotatedTo  []byte `json:"rotatedTo,omitempty"`
+	GraceUntil int64  `json:"graceUntil,omitempty"`
+	Revoked    bool   `json:"revoked,omitempty"`
 }
 
 type ExecutorNodes []ExecutorNode
@@ -113,6 +181,15 @@ type FLTask *pbTask.FLTask
 
 type FLTasks []*pbTask.FLTask
 
+// idempotencyNamespace is the namespace of taskIDs derived from idempotency keys
+var idempotencyNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte("PaddleDTX/dai/task/idempotency"))
+
+// IdempotentTaskID derives the taskID from the requester and the idempotency key of a task,
+// so the tasks submitted repeatedly with the same key can't be published twice because of duplicated taskIDs
+func IdempotentTaskID(requester []byte, key string) string {
+	return uuid.NewSHA1(idempotencyNamespace, append(append([]byte{}, requester...), key...)).String()
+}
+
 // PublishFLTaskOptions contains parameters for publishing tasks
 type PublishFLTaskOptions struct {
 	FLTask    FLTask `json:"fLTask"`
@@ -154,8 +231,24 @@ type FLTaskExeStatusOptions struct {
 
 	Signature []byte `json:"signature"`
 
-	ErrMessage string `json:"errMessage"` // for failed task
-	Result     string `json:"result"`     // for finished task
+	ErrMessage string `json:"errMessage"`         // for failed task
+	Result     string `json:"result"`             // for finished task
+	Cancelled  bool   `json:"cancelled"`          // for cancelled task, ErrMessage is the reason
+	TimedOut   bool   `json:"timedOut,omitempty"` // for task cancelled on timeout, ErrMessage is the reason
+
+	PrivacyBudget *pbCom.PrivacyBudget `json:"privacyBudget,omitempty"` // for finished differentially private training task
+
+	BatchResults []*pbTask.BatchPredictResult `json:"batchResults,omitempty"` // for finished batch prediction task
+}
+
+// AnchorAuditOptions contains parameters for anchoring the hash of an audit record written by an executor,
+// which proves the record existed and hasn't been modified since it was anchored
+type AnchorAuditOptions struct {
+	Executor    []byte `json:"executor"`
+	Hash        []byte `json:"hash"`        // SHA256 of the audit record
+	CurrentTime int64  `json:"currentTime"` // time when anchoring the hash
+
+	Signature []byte `json:"signature"` // executor's signature
 }
 
 // AddNodeOptions contains parameters for adding node of Executor
@@ -0,0 +1,369 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/google/uuid"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func TestIdempotentTaskID(t *testing.T) {
+	id := IdempotentTaskID([]byte("requester1"), "key1")
+	if _, err := uuid.Parse(id); err != nil {
+		t.Fatalf("taskID %s is not an uuid: %v", id, err)
+	}
+	if id != IdempotentTaskID([]byte("requester1"), "key1") {
+		t.Error("taskIDs of the same key differ")
+	}
+	if id == IdempotentTaskID([]byte("requester2"), "key1") {
+		t.Error("requesters with the same key share one taskID")
+	}
+	if id == IdempotentTaskID([]byte("requester1"), "key2") {
+		t.Error("different keys share one taskID")
+	}
+}
+
+func TestModelLineage(t *testing.T) {
+	parent := &pbTask.FLTask{
+		TaskID: "model1",
+		Status: TaskFinished,
+		AlgoParam: &pbCom.TaskParams{
+			Algo:     pbCom.Algorithm_LINEAR_REGRESSION_VL,
+			TaskType: pbCom.TaskType_LEARN,
+		},
+		DataSets: []*pbTask.DataForTask{{DataID: "data1"}, {DataID: "data2"}},
+	}
+	task := &pbTask.FLTask{
+		TaskID: "model2",
+		AlgoParam: &pbCom.TaskParams{
+			Algo:        pbCom.Algorithm_LINEAR_REGRESSION_VL,
+			TaskType:    pbCom.TaskType_LEARN,
+			ModelTaskID: "model1",
+		},
+	}
+
+	version, err := NextModelVersion(task, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 {
+		t.Errorf("version of the continued model is %d, expected 2", version)
+	}
+	task.ModelVersion = version
+	l := NewModelLineage(task)
+	if l.ModelID != "model2" || l.Version != 2 || l.ParentModelID != "model1" || l.Algorithm != "linear-vl" {
+		t.Errorf("unexpected lineage %+v", l)
+	}
+	if l := NewModelLineage(parent); l.Version != 1 || len(l.DataIDs) != 2 {
+		t.Errorf("unexpected lineage of the first version %+v", l)
+	}
+
+	task.AlgoParam.Algo = pbCom.Algorithm_LOGIC_REGRESSION_VL
+	if _, err := NextModelVersion(task, parent); err == nil {
+		t.Error("continued from a model trained by another algorithm")
+	}
+	parent.Status = TaskFailed
+	if _, err := NextModelVersion(task, parent); err == nil {
+		t.Error("continued from a failed task")
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	tasks := map[string]FLTask{
+		"train": {TaskID: "train", Requester: []byte("r1"), Status: TaskFinished},
+		"eval":  {TaskID: "eval", Requester: []byte("r1"), Status: TaskProcessing, DependsOn: []string{"train"}},
+		"other": {TaskID: "other", Requester: []byte("r2"), Status: TaskFinished},
+		"fail":  {TaskID: "fail", Requester: []byte("r1"), Status: TaskFailed, ErrMessage: "psi failed"},
+		// published with the ID of "cycle" before it is published
+		"loop": {TaskID: "loop", Requester: []byte("r1"), Status: TaskConfirming, DependsOn: []string{"cycle"}},
+	}
+	getTask := func(id string) (FLTask, error) {
+		if task, ok := tasks[id]; ok {
+			return task, nil
+		}
+		return nil, errorx.New(errorx.ErrCodeNotFound, "task not found")
+	}
+
+	valid := &pbTask.FLTask{TaskID: "predict", Requester: []byte("r1"), DependsOn: []string{"train", "eval"}}
+	if err := CheckTaskDependencies(valid, getTask); err != nil {
+		t.Errorf("expected fan-in of train and eval valid, got %v", err)
+	}
+	for name, dependsOn := range map[string][]string{
+		"notFound":   {"missing"},
+		"duplicated": {"train", "train"},
+		"requester":  {"other"},
+		"failed":     {"fail"},
+		"self":       {"predict"},
+	} {
+		task := &pbTask.FLTask{TaskID: "predict", Requester: []byte("r1"), DependsOn: dependsOn}
+		if err := CheckTaskDependencies(task, getTask); err == nil {
+			t.Errorf("expected error of %s dependencies", name)
+		}
+	}
+	cycle := &pbTask.FLTask{TaskID: "cycle", Requester: []byte("r1"), DependsOn: []string{"eval", "loop"}}
+	if err := CheckTaskDependencies(cycle, getTask); err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("expected cyclic dependencies rejected, got %v", err)
+	}
+
+	// the downstream task waits for the unfinished upstream task, and fails if any upstream task fails
+	if finished, reason, err := CheckDependenciesFinished(valid, getTask); err != nil || finished || reason != "" {
+		t.Errorf("expected the task waiting for eval, got %v, %q, %v", finished, reason, err)
+	}
+	tasks["eval"].Status = TaskFinished
+	if finished, reason, err := CheckDependenciesFinished(valid, getTask); err != nil || !finished || reason != "" {
+		t.Errorf("expected upstream tasks finished, got %v, %q, %v", finished, reason, err)
+	}
+	valid.DependsOn = append(valid.DependsOn, "fail")
+	if _, reason, err := CheckDependenciesFinished(valid, getTask); err != nil || !strings.Contains(reason, "psi failed") {
+		t.Errorf("expected the task failed with the reason of upstream task, got %q, %v", reason, err)
+	}
+}
+
+func TestCheckTaskLabels(t *testing.T) {
+	if err := CheckTaskLabels(map[string]string{"team": "risk", "project/id": "p-1", "cost.center": ""}); err != nil {
+		t.Errorf("expected valid labels, got %v", err)
+	}
+	tooMany := make(map[string]string)
+	for i := 0; i <= TaskLabelsMaxNum; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	for _, labels := range []map[string]string{
+		tooMany,
+		{"": "v"},
+		{"-team": "risk"},
+		{"team name": "risk"},
+		{strings.Repeat("k", TaskLabelKeyMaxLen+1): "v"},
+		{"team": strings.Repeat("v", TaskLabelValueMaxLen+1)},
+	} {
+		if err := CheckTaskLabels(labels); err == nil {
+			t.Errorf("expected labels %v invalid", labels)
+		}
+	}
+
+	labels := map[string]string{"team": "risk", "project": "p1"}
+	if !MatchLabels(labels, nil) || !MatchLabels(labels, map[string]string{"team": "risk"}) {
+		t.Error("expected labels matched")
+	}
+	if MatchLabels(labels, map[string]string{"team": "ads"}) || MatchLabels(labels, map[string]string{"owner": "risk"}) {
+		t.Error("expected labels not matched")
+	}
+
+	parsed, err := ParseLabels(" team=risk, project=p1 ")
+	if err != nil || !reflect.DeepEqual(parsed, labels) {
+		t.Errorf("expected labels parsed, got %v, err: %v", parsed, err)
+	}
+	if parsed, err := ParseLabels(FormatLabels(nil)); err != nil || len(parsed) != 0 {
+		t.Errorf("expected no labels, got %v, err: %v", parsed, err)
+	}
+	for _, s := range []string{"team", "team=risk,=p1", "-team=risk"} {
+		if _, err := ParseLabels(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestColumnPolicy(t *testing.T) {
+	policy := ColumnPolicy{FileID: "file-1", Columns: []string{"id", "x1", "y"}}
+	if err := CheckColumnPolicy(policy); err != nil {
+		t.Errorf("expected valid policy, got %v", err)
+	}
+	for _, p := range []ColumnPolicy{
+		{Columns: []string{"id"}},
+		{FileID: "file-1"},
+		{FileID: "file-1", Columns: []string{"id", ""}},
+		{FileID: "file-1", Columns: []string{"id", "x1", "id"}},
+	} {
+		if err := CheckColumnPolicy(p); err == nil {
+			t.Errorf("expected policy %v invalid", p)
+		}
+	}
+
+	if disallowed := DisallowedColumns(policy, []string{"id", "x1", "y"}); len(disallowed) != 0 {
+		t.Errorf("expected all columns allowed, got %v disallowed", disallowed)
+	}
+	if disallowed := DisallowedColumns(policy, []string{"id", "x2", "x1", "3"}); strings.Join(disallowed, ",") != "x2,3" {
+		t.Errorf("expected x2 and 3 disallowed, got %v", disallowed)
+	}
+}
+
+func TestResultSignature(t *testing.T) {
+	sk, pk, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("id,y\n1,0.5\n")
+	sig, err := SignResult(sk, "t1", 1, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyResultSignature(sig, pk[:], "t1", 1, content); err != nil {
+		t.Fatalf("failed to verify signature: %v", err)
+	}
+	if err := VerifyResultSignature(sig, pk[:], "t1", 1, nil); err != nil {
+		t.Errorf("signature should be verified without the result: %v", err)
+	}
+
+	_, other, _ := ecdsa.GenerateKeyPair()
+	forged := *sig
+	forged.Executor = other[:]
+	cases := map[string]error{
+		"modified result": VerifyResultSignature(sig, pk[:], "t1", 1, []byte("id,y\n1,0.6\n")),
+		"other task":      VerifyResultSignature(sig, pk[:], "t2", 1, content),
+		"other input":     VerifyResultSignature(sig, pk[:], "t1", 0, content),
+		"other node":      VerifyResultSignature(sig, other[:], "t1", 1, content),
+		"forged executor": VerifyResultSignature(&forged, other[:], "t1", 1, content),
+	}
+	for name, err := range cases {
+		if code, _ := errorx.Parse(err); code != errorx.ErrCodeBadSignature {
+			t.Errorf("signature of %s should be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestRotateNode(t *testing.T) {
+	oldKey, oldPub, _ := ecdsa.GenerateKeyPair()
+	newKey, newPub, _ := ecdsa.GenerateKeyPair()
+	node := ExecutorNode{ID: oldPub[:], Name: "executor1", Address: "127.0.0.1:8184", RegTime: 1}
+	opt := RotateKeyOptions{OldID: oldPub[:], NewID: newPub[:], CurrentTime: 10, GraceUntil: 20}
+	if err := SignKeyRotation(&opt, oldKey, newKey); err != nil {
+		t.Fatal(err)
+	}
+	rotated, next, err := RotateNode(node, opt)
+	if err != nil {
+		t.Fatalf("failed to rotate node: %v", err)
+	}
+	expected := ExecutorNode{ID: newPub[:], Name: "executor1", Address: "127.0.0.1:8184", RegTime: 10}
+	if !reflect.DeepEqual(next, expected) {
+		t.Errorf("expected node of the new key %+v, got %+v", expected, next)
+	}
+	if string(rotated.RotatedTo) != string(newPub[:]) || rotated.GraceUntil != 20 || rotated.RegTime != 1 {
+		t.Errorf("expected the old node marked rotated, got %+v", rotated)
+	}
+
+	// the old key confirms tasks in the grace period only
+	if err := CheckNodeConfirming(rotated, 20); err != nil {
+		t.Errorf("expected confirming in the grace period, got %v", err)
+	}
+	if err := CheckNodeConfirming(rotated, 21); err == nil {
+		t.Error("expected confirming rejected after the grace period")
+	}
+	if err := CheckNodeConfirming(next, 21); err != nil {
+		t.Errorf("expected the new key confirming, got %v", err)
+	}
+
+	_, otherPub, _ := ecdsa.GenerateKeyPair()
+	forged := opt
+	forged.NewID = otherPub[:]
+	unsigned := opt
+	unsigned.NewSignature = nil
+	inverted := opt
+	inverted.GraceUntil = 5
+	cases := map[string]struct {
+		node ExecutorNode
+		opt  RotateKeyOptions
+		code string
+	}{
+		"already rotated":  {rotated, opt, errorx.ErrCodeAlreadyUpdate},
+		"other node":       {next, opt, errorx.ErrCodeParam},
+		"forged new key":   {node, forged, errorx.ErrCodeBadSignature},
+		"unsigned new key": {node, unsigned, errorx.ErrCodeBadSignature},
+		"grace before now": {node, inverted, errorx.ErrCodeParam},
+	}
+	for name, c := range cases {
+		if _, _, err := RotateNode(c.node, c.opt); !errorx.Is(err, c.code) {
+			t.Errorf("%s: expected %s error, got %v", name, c.code, err)
+		}
+	}
+}
+
+func TestRevokeNode(t *testing.T) {
+	oldKey, oldPub, _ := ecdsa.GenerateKeyPair()
+	newKey, newPub, _ := ecdsa.GenerateKeyPair()
+	rotated := ExecutorNode{ID: oldPub[:], Name: "executor1", RotatedTo: newPub[:], GraceUntil: 20}
+	opt := RevokeKeyOptions{OldID: oldPub[:], CurrentTime: 21}
+	if err := SignKeyRevocation(&opt, newKey); err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := RevokeNode(rotated, opt)
+	if err != nil {
+		t.Fatalf("failed to revoke node: %v", err)
+	}
+	// the revoked key confirms no tasks, even if it claims a time in the grace period
+	if err := CheckNodeConfirming(revoked, 10); err == nil {
+		t.Error("expected confirming of the revoked key rejected")
+	}
+
+	signedByOld := opt
+	if err := SignKeyRevocation(&signedByOld, oldKey); err != nil {
+		t.Fatal(err)
+	}
+	early := RevokeKeyOptions{OldID: oldPub[:], CurrentTime: 20}
+	if err := SignKeyRevocation(&early, newKey); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		node ExecutorNode
+		opt  RevokeKeyOptions
+		code string
+	}{
+		"already revoked": {revoked, opt, errorx.ErrCodeAlreadyUpdate},
+		"not rotated":     {ExecutorNode{ID: oldPub[:]}, opt, errorx.ErrCodeParam},
+		"signed by old":   {rotated, signedByOld, errorx.ErrCodeBadSignature},
+		"in grace period": {rotated, early, errorx.ErrCodeParam},
+	}
+	for name, c := range cases {
+		if _, err := RevokeNode(c.node, c.opt); !errorx.Is(err, c.code) {
+			t.Errorf("%s: expected %s error, got %v", name, c.code, err)
+		}
+	}
+}
+
+func TestCheckRandomSplit(t *testing.T) {
+	linear, logistic := pbCom.Algorithm_LINEAR_REGRESSION_VL, pbCom.Algorithm_LOGIC_REGRESSION_VL
+	for _, rs := range []*pbCom.RandomSplit{
+		{PercentLO: 30},
+		{TestFraction: 0.25},
+		{TestFraction: 0.25, Strategy: SplitStrategyTime, TimeColumn: "t"},
+	} {
+		if err := CheckRandomSplit(linear, rs); err != nil {
+			t.Errorf("unexpected error for %v: %v", rs, err)
+		}
+	}
+	if err := CheckRandomSplit(logistic, &pbCom.RandomSplit{TestFraction: 0.1, Strategy: SplitStrategyStratified}); err != nil {
+		t.Errorf("unexpected error for stratified split: %v", err)
+	}
+
+	for name, rs := range map[string]*pbCom.RandomSplit{
+		"nil":               nil,
+		"noPercent":         {},
+		"negativeFraction":  {TestFraction: -0.1},
+		"fractionOne":       {TestFraction: 1},
+		"unknownStrategy":   {TestFraction: 0.2, Strategy: "kfold"},
+		"stratifiedRegress": {TestFraction: 0.2, Strategy: SplitStrategyStratified},
+		"timeWithoutColumn": {TestFraction: 0.2, Strategy: SplitStrategyTime},
+		"columnWithoutTime": {TestFraction: 0.2, TimeColumn: "t"},
+	} {
+		if err := CheckRandomSplit(linear, rs); !errorx.Is(err, errorx.ErrCodeParam) {
+			t.Errorf("%s: expected param error, got %v", name, err)
+		}
+	}
+}
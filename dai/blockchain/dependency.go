@@ -0,0 +1,103 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// TaskDependenciesMaxNum the maximum number of upstream tasks a task depends on
+const TaskDependenciesMaxNum = 10
+
+// GetTaskFunc gets a task by taskID, used to walk through the upstream tasks of a task
+type GetTaskFunc func(id string) (FLTask, error)
+
+// TaskEndedUnfinished returns whether the task ended without finishing, the tasks depending on it never start
+func TaskEndedUnfinished(status string) bool {
+	switch status {
+	case TaskFailed, TaskRejected, TaskCancelled, TaskTimeout:
+		return true
+	}
+	return false
+}
+
+// CheckTaskDependencies checks the upstream tasks of task before it is published, they must be published
+// by the same requester and not ended unfinished. A task can only depend on published tasks, and the
+// upstream tasks are walked through to reject cyclic dependencies, so the tasks of a requester form a DAG.
+func CheckTaskDependencies(task FLTask, getTask GetTaskFunc) error {
+	if len(task.DependsOn) > TaskDependenciesMaxNum {
+		return errorx.New(errorx.ErrCodeParam, "a task can depend on at most %d tasks, got %d",
+			TaskDependenciesMaxNum, len(task.DependsOn))
+	}
+	seen := make(map[string]bool, len(task.DependsOn))
+	for _, id := range task.DependsOn {
+		if seen[id] {
+			return errorx.New(errorx.ErrCodeParam, "duplicated upstream task %s", id)
+		}
+		seen[id] = true
+		upstream, err := getTask(id)
+		if err != nil {
+			return errorx.Wrap(err, "failed to get upstream task %s", id)
+		}
+		if !bytes.Equal(upstream.Requester, task.Requester) {
+			return errorx.New(errorx.ErrCodeParam, "upstream task %s is published by another requester", id)
+		}
+		if TaskEndedUnfinished(upstream.Status) {
+			return errorx.New(errorx.ErrCodeParam, "upstream task %s is %s", id, upstream.Status)
+		}
+	}
+
+	// walk through the upstream tasks breadth first, the task is reached again if the dependencies are cyclic
+	visited := make(map[string]bool)
+	queue := append([]string{}, task.DependsOn...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == task.TaskID {
+			return errorx.New(errorx.ErrCodeParam, "cyclic dependencies of task %s", task.TaskID)
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		upstream, err := getTask(id)
+		if err != nil {
+			return errorx.Wrap(err, "failed to get upstream task %s", id)
+		}
+		queue = append(queue, upstream.DependsOn...)
+	}
+	return nil
+}
+
+// CheckDependenciesFinished checks the upstream tasks of task before it starts, returns true if all of them
+// have finished, or the reason if any of them ended unfinished, in which case the task fails
+func CheckDependenciesFinished(task FLTask, getTask GetTaskFunc) (finished bool, reason string, err error) {
+	finished = true
+	for _, id := range task.DependsOn {
+		upstream, err := getTask(id)
+		if err != nil {
+			return false, "", errorx.Wrap(err, "failed to get upstream task %s", id)
+		}
+		if TaskEndedUnfinished(upstream.Status) {
+			return false, fmt.Sprintf("upstream task %s is %s: %s", id, upstream.Status, upstream.ErrMessage), nil
+		}
+		if upstream.Status != TaskFinished {
+			finished = false
+		}
+	}
+	return finished, "", nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+)
+
+// AnchorAudit anchors the hash of an audit record written by a registered Executor, an anchor is never overwritten
+func (x *Xdata) AnchorAudit(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	var opt blockchain.AnchorAuditOptions
+	if len(args) < 1 {
+		return shim.Error("invalid arguments. expecting AnchorAuditOptions")
+	}
+	if err := json.Unmarshal([]byte(args[0]), &opt); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal AnchorAuditOptions").Error())
+	}
+	// only registered executors anchor audit records
+	if resp := x.GetValue(stub, []string{packNodeIndex(opt.Executor)}); len(resp.Payload) == 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeParam, "bad param: executor not registered").Error())
+	}
+	// verify sig
+	msg, err := util.GetSigMessage(opt)
+	if err != nil {
+		return shim.Error(errorx.Internal(err, "failed to get the message to sign").Error())
+	}
+	if err := x.checkSign(opt.Signature, opt.Executor, []byte(msg)); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	s, err := json.Marshal(opt)
+	if err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal AnchorAuditOptions").Error())
+	}
+	index := packAuditIndex(opt.Executor, opt.Hash)
+	if resp := x.GetValue(stub, []string{index}); len(resp.Payload) != 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeAlreadyExists, "duplicated audit hash").Error())
+	}
+	if resp := x.SetValue(stub, []string{index, string(s)}); resp.Status == shim.ERROR {
+		return shim.Error(errorx.New(errorx.ErrCodeWriteBlockchain,
+			"failed to put index-audit on chain: %s", resp.Message).Error())
+	}
+	return shim.Success([]byte("anchored"))
+}
@@ -95,6 +95,106 @@ func (x *Xdata) RegisterExecutorNode(stub shim.ChaincodeStubInterface, args []st
 	return shim.Success(nil)
 }
 
+// RotateExecutorKey rotates the key of an Executor node, the node with the new key takes over the name of the node,
+// and the record of the old key is kept to execute the tasks published to it, see blockchain.RotateKeyOptions
+func (x *Xdata) RotateExecutorKey(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	var opt blockchain.RotateKeyOptions
+	if len(args) < 1 {
+		return shim.Error("invalid arguments. expecting RotateKeyOptions")
+	}
+	// unmarshal opt
+	if err := json.Unmarshal([]byte(args[0]), &opt); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal RotateKeyOptions").Error())
+	}
+	// get the node of the old key
+	resp := x.GetValue(stub, []string{packNodeIndex(opt.OldID)})
+	if len(resp.Payload) == 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeNotFound, "node not found: %s", resp.Message).Error())
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(resp.Payload, &node); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal node").Error())
+	}
+	// verify both signatures
+	rotated, next, err := blockchain.RotateNode(node, opt)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if resp := x.GetValue(stub, []string{packNodeIndex(next.ID)}); len(resp.Payload) != 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeAlreadyExists,
+			"duplicated nodeID").Error())
+	}
+	rs, err := json.Marshal(rotated)
+	if err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to marshal Node").Error())
+	}
+	ns, err := json.Marshal(next)
+	if err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to marshal Node").Error())
+	}
+
+	// the old key is kept in index-node and listIndex-node, marked rotated
+	puts := [][]string{
+		{packNodeIndex(rotated.ID), string(rs)},
+		{packNodeListIndex(rotated), string(rs)},
+		{packNodeIndex(next.ID), string(ns)},
+		{packNodeNameIndex(next.Name), string(ns)},
+		{packNodeListIndex(next), string(ns)},
+	}
+	for _, put := range puts {
+		if resp := x.SetValue(stub, put); resp.Status == shim.ERROR {
+			return shim.Error(errorx.New(errorx.ErrCodeWriteBlockchain, "failed to put %s on chain: %s",
+				put[0], resp.Message).Error())
+		}
+	}
+	return shim.Success(nil)
+}
+
+// RevokeExecutorKey revokes the old key of an Executor node once the grace period of its key rotation ends,
+// the revoked key confirms no tasks, see blockchain.RevokeKeyOptions
+func (x *Xdata) RevokeExecutorKey(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	var opt blockchain.RevokeKeyOptions
+	if len(args) < 1 {
+		return shim.Error("invalid arguments. expecting RevokeKeyOptions")
+	}
+	// unmarshal opt
+	if err := json.Unmarshal([]byte(args[0]), &opt); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal RevokeKeyOptions").Error())
+	}
+	// get the node of the old key
+	resp := x.GetValue(stub, []string{packNodeIndex(opt.OldID)})
+	if len(resp.Payload) == 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeNotFound, "node not found: %s", resp.Message).Error())
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(resp.Payload, &node); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal node").Error())
+	}
+	// verify the signature of the new key
+	revoked, err := blockchain.RevokeNode(node, opt)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	rs, err := json.Marshal(revoked)
+	if err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to marshal Node").Error())
+	}
+	for _, index := range []string{packNodeIndex(revoked.ID), packNodeListIndex(revoked)} {
+		if resp := x.SetValue(stub, []string{index, string(rs)}); resp.Status == shim.ERROR {
+			return shim.Error(errorx.New(errorx.ErrCodeWriteBlockchain, "failed to put %s on chain: %s",
+				index, resp.Message).Error())
+		}
+	}
+	return shim.Success(nil)
+}
+
 // ListExecutorNodes gets all Executor nodes
 func (x *Xdata) ListExecutorNodes(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 	var nodes blockchain.ExecutorNodes
@@ -30,6 +30,8 @@ const (
 	prefixNodeIndex         = "index_executor_node"
 	prefixNodeNameIndex     = "index_executor_name"
 	prefixNodeListIndex     = "index_executor_node_list"
+	prefixAuditIndex        = "index_audit"
+	prefixColumnPolicyIndex = "index_column_policy"
 )
 
 // subByInt64Max return maxInt64 - N
@@ -91,6 +93,17 @@ func packNodeListIndex(node blockchain.ExecutorNode) string {
 	return createCompositeKey(prefixNodeListIndex, attributes)
 }
 
+// packAuditIndex pack index for anchoring the hash of an audit record written by executor
+func packAuditIndex(executor, hash []byte) string {
+	return createCompositeKey(prefixAuditIndex, []string{fmt.Sprintf("%x", executor), fmt.Sprintf("%x", hash)})
+}
+
+// packColumnPolicyIndex pack index for the column policy of file for executor, the hex encoded public key,
+// empty for all executors
+func packColumnPolicyIndex(fileID, executor string) string {
+	return createCompositeKey(prefixColumnPolicyIndex, []string{fileID, executor})
+}
+
 func createCompositeKey(objectType string, attributes []string) string {
 	ck := compositeKeyNamespace + objectType + string(minUnicodeRuneValue)
 	for _, att := range attributes {
@@ -112,6 +112,10 @@ func (x *Xdata) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return x.GetExecutorNodeByID(stub, args)
 	case "GetExecutorNodeByName":
 		return x.GetExecutorNodeByName(stub, args)
+	case "RotateExecutorKey":
+		return x.RotateExecutorKey(stub, args)
+	case "RevokeExecutorKey":
+		return x.RevokeExecutorKey(stub, args)
 	case "PublishTask":
 		return x.PublishTask(stub, args)
 	case "ListTask":
@@ -128,6 +132,12 @@ func (x *Xdata) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return x.StartTask(stub, args)
 	case "FinishTask":
 		return x.FinishTask(stub, args)
+	case "AnchorAudit":
+		return x.AnchorAudit(stub, args)
+	case "PublishColumnPolicy":
+		return x.PublishColumnPolicy(stub, args)
+	case "GetColumnPolicy":
+		return x.GetColumnPolicy(stub, args)
 	default:
 		return shim.Error("Invalid invoke function name.")
 	}
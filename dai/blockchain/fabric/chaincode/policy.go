@@ -0,0 +1,96 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+)
+
+// PublishColumnPolicy publishes the column policy of a sample file, only the owner of the file publishes it,
+// and it replaces the previous policy of the file for the same Executor
+func (x *Xdata) PublishColumnPolicy(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	var opt blockchain.PublishColumnPolicyOptions
+	if len(args) < 1 {
+		return shim.Error("invalid arguments. expecting PublishColumnPolicyOptions")
+	}
+	if err := json.Unmarshal([]byte(args[0]), &opt); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal PublishColumnPolicyOptions").Error())
+	}
+	policy := opt.Policy
+	if err := blockchain.CheckColumnPolicy(policy); err != nil {
+		return shim.Error(err.Error())
+	}
+	// the file is stored by xdb with its ID as the key
+	resp := x.GetValue(stub, []string{policy.FileID})
+	if len(resp.Payload) == 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeNotFound, "file not found: %s", resp.Message).Error())
+	}
+	var f xdbchain.File
+	if err := json.Unmarshal(resp.Payload, &f); err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal, "failed to unmarshal File").Error())
+	}
+	if !bytes.Equal(f.Owner, policy.Owner) {
+		return shim.Error(errorx.New(errorx.ErrCodeParam, "bad param: only the owner of file publishes its column policy").Error())
+	}
+	// verify sig
+	msg, err := util.GetSigMessage(policy)
+	if err != nil {
+		return shim.Error(errorx.Internal(err, "failed to get the message to sign").Error())
+	}
+	if err := x.checkSign(opt.Signature, policy.Owner, []byte(msg)); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	s, err := json.Marshal(policy)
+	if err != nil {
+		return shim.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal ColumnPolicy").Error())
+	}
+	index := packColumnPolicyIndex(policy.FileID, hex.EncodeToString(policy.Executor))
+	if resp := x.SetValue(stub, []string{index, string(s)}); resp.Status == shim.ERROR {
+		return shim.Error(errorx.New(errorx.ErrCodeWriteBlockchain,
+			"failed to put index-columnPolicy on chain: %s", resp.Message).Error())
+	}
+	return shim.Success([]byte("published"))
+}
+
+// GetColumnPolicy gets the column policy of a sample file for an Executor, the arguments are the file ID
+// and the hex encoded public key of the Executor. The policy for all Executors is returned
+// if the Executor has no policy of its own
+func (x *Xdata) GetColumnPolicy(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) < 2 {
+		return shim.Error("invalid arguments. expecting fileID and executor")
+	}
+	if args[1] != "" {
+		if resp := x.GetValue(stub, []string{packColumnPolicyIndex(args[0], args[1])}); len(resp.Payload) != 0 {
+			return shim.Success(resp.Payload)
+		}
+	}
+	resp := x.GetValue(stub, []string{packColumnPolicyIndex(args[0], "")})
+	if len(resp.Payload) == 0 {
+		return shim.Error(errorx.New(errorx.ErrCodeNotFound, "column policy not found: %s", resp.Message).Error())
+	}
+	return shim.Success(resp.Payload)
+}
@@ -16,6 +16,8 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"time"
+
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
@@ -23,6 +25,7 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
 	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 )
@@ -47,6 +50,38 @@ func (x *Xdata) PublishTask(stub shim.ChaincodeStubInterface, args []string) pb.
 	if err := x.checkSign(opt.Signature, t.Requester, []byte(msg)); err != nil {
 		return shim.Error(err.Error())
 	}
+	// check the number of parties supported by the algorithm
+	if parties, ok := blockchain.VlAlgorithmParties[t.AlgoParam.Algo]; ok && len(t.DataSets) != parties {
+		return shim.Error(errorx.New(errorx.ErrCodeParam, "algorithm %s requires %d parties, got %d data sets",
+			blockchain.VlAlgorithmListValue[t.AlgoParam.Algo], parties, len(t.DataSets)).Error())
+	}
+	// tasks with the same idempotency key share one taskID, and duplicated ones are rejected below
+	if t.IdempotencyKey != "" && t.TaskID != blockchain.IdempotentTaskID(t.Requester, t.IdempotencyKey) {
+		return shim.Error(errorx.New(errorx.ErrCodeParam, "taskID %s is not derived from the idempotency key", t.TaskID).Error())
+	}
+	// a training task continuing from a parent model trains its next version
+	if t.AlgoParam.TaskType == pbCom.TaskType_LEARN {
+		t.ModelVersion = 1
+		if t.AlgoParam.ModelTaskID != "" {
+			parent, err := x.getTaskById(stub, t.AlgoParam.ModelTaskID)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			if t.ModelVersion, err = blockchain.NextModelVersion(t, parent); err != nil {
+				return shim.Error(err.Error())
+			}
+		}
+	}
+
+	// the upstream tasks must be published, and the task starts after all of them finish
+	if len(t.DependsOn) > 0 {
+		getTask := func(id string) (blockchain.FLTask, error) {
+			return x.getTaskById(stub, id)
+		}
+		if err := blockchain.CheckTaskDependencies(t, getTask); err != nil {
+			return shim.Error(err.Error())
+		}
+	}
 
 	t.Status = blockchain.TaskConfirming
 
@@ -197,6 +232,12 @@ func (x *Xdata) setTaskConfirmStatus(stub shim.ChaincodeStubInterface, args []st
 	if err := x.checkSign(opt.Signature, opt.Pubkey, []byte(msg)); err != nil {
 		return shim.Error(err.Error())
 	}
+	// a node whose key is rotated confirms no tasks after the grace period, but may reject them
+	if isConfirm {
+		if err := x.checkNodeConfirming(stub, opt.Pubkey); err != nil {
+			return shim.Error(err.Error())
+		}
+	}
 
 	// check status
 	if t.Status != blockchain.TaskConfirming {
@@ -210,6 +251,11 @@ func (x *Xdata) setTaskConfirmStatus(stub shim.ChaincodeStubInterface, args []st
 			if resp := x.GetValue(stub, []string{ds.DataID}); len(resp.Payload) == 0 {
 				return shim.Error(errorx.New(errorx.ErrCodeParam, "bad param:taskId, dataId not exist").Error())
 			}
+			for _, id := range ds.BatchDataIDs {
+				if resp := x.GetValue(stub, []string{id}); len(resp.Payload) == 0 {
+					return shim.Error(errorx.New(errorx.ErrCodeParam, "bad param:taskId, batch dataId %s not exist", id).Error())
+				}
+			}
 
 			// judge task is confirmed
 			if ds.ConfirmedAt > 0 || ds.RejectedAt > 0 {
@@ -275,9 +321,10 @@ func (x *Xdata) StartTask(stub shim.ChaincodeStubInterface, args []string) pb.Re
 	if err := x.checkSign(opt.Signature, t.Requester, []byte(msg)); err != nil {
 		return shim.Error(err.Error())
 	}
-	if t.Status != blockchain.TaskReady && t.Status != blockchain.TaskFailed {
+	if t.Status != blockchain.TaskReady && t.Status != blockchain.TaskFailed && t.Status != blockchain.TaskCancelled &&
+		t.Status != blockchain.TaskTimeout {
 		return shim.Error(errorx.New(errorx.ErrCodeParam,
-			"start task error, task status is not Ready or Failed, taskId: %s, taskStatus: %s", t.TaskID, t.Status).Error())
+			"start task error, task status is not Ready, Failed, Cancelled or Timeout, taskId: %s, taskStatus: %s", t.TaskID, t.Status).Error())
 	}
 
 	// update task status
@@ -346,11 +393,19 @@ func (x *Xdata) setTaskExecuteStatus(stub shim.ChaincodeStubInterface, args []st
 		t.Status = blockchain.TaskFinished
 		t.EndTime = opt.CurrentTime
 		t.Result = opt.Result
+		t.PrivacyBudget = opt.PrivacyBudget
+		t.BatchResults = opt.BatchResults
 
 		if opt.ErrMessage != "" {
 			t.Status = blockchain.TaskFailed
 			t.ErrMessage = opt.ErrMessage
 		}
+		if opt.Cancelled {
+			t.Status = blockchain.TaskCancelled
+		}
+		if opt.TimedOut {
+			t.Status = blockchain.TaskTimeout
+		}
 	} else {
 		if t.Status != blockchain.TaskToProcess {
 			return shim.Error(errorx.New(errorx.ErrCodeParam,
@@ -405,6 +460,24 @@ func (x *Xdata) checkSign(sign, owner, mes []byte) (err error) {
 	return nil
 }
 
+// checkNodeConfirming checks the Executor node is allowed to confirm tasks at the time of the transaction,
+// rather than the time claimed by the node, nodes not registered are allowed as before
+func (x *Xdata) checkNodeConfirming(stub shim.ChaincodeStubInterface, executor []byte) error {
+	resp := x.GetValue(stub, []string{packNodeIndex(executor)})
+	if len(resp.Payload) == 0 {
+		return nil
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(resp.Payload, &node); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal, "fail to unmarshal node")
+	}
+	txTime, err := stub.GetTxTimestamp()
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal, "fail to get the time of the transaction")
+	}
+	return blockchain.CheckNodeConfirming(node, txTime.Seconds*int64(time.Second)+int64(txTime.Nanos))
+}
+
 // checkExecutor used for Executor validity check, only the Executor specified by the Requester can confirm the task
 func (x *Xdata) checkExecutor(executor []byte, dataSets []*pbTask.DataForTask) bool {
 	for _, ds := range dataSets {
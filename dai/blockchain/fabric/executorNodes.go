@@ -39,6 +39,38 @@ func (f *Fabric) RegisterExecutorNode(opt *blockchain.AddNodeOptions) error {
 	return nil
 }
 
+// RotateExecutorKey rotates the key of an Executor node on fabric
+func (f *Fabric) RotateExecutorKey(opt *blockchain.RotateKeyOptions) error {
+	s, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal RotateKeyOptions")
+	}
+	args := [][]byte{s}
+	mName := "RotateExecutorKey"
+
+	if _, err = f.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeExecutorKey revokes the old key of an Executor node on fabric once the grace period of its key rotation ends
+func (f *Fabric) RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error {
+	s, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal RevokeKeyOptions")
+	}
+	args := [][]byte{s}
+	mName := "RevokeExecutorKey"
+
+	if _, err = f.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ListExecutorNodes gets all Executor nodes from fabric
 func (f *Fabric) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
 	var nodes blockchain.ExecutorNodes
@@ -14,9 +14,15 @@
 package fabric
 
 import (
-	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"strings"
+
 	fabricchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain/fabric"
 	xdbconfig "github.com/PaddlePaddle/PaddleDTX/xdb/config"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	sdkconfig "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
 )
 
 type Config struct {
@@ -29,12 +35,24 @@ type Fabric struct {
 
 // New creates a Fabric client used for connecting and requesting blockchain
 func New(conf *config.FabricConf) (*Fabric, error) {
+	orgName := conf.OrgName
+	if conf.MspID != "" {
+		name, err := orgNameByMspID(conf.ConfigFile, conf.MspID)
+		if err != nil {
+			return nil, err
+		}
+		if orgName != "" && !strings.EqualFold(orgName, name) {
+			return nil, errorx.New(errorx.ErrCodeConfig, "mspId %s belongs to organization %s, not %s",
+				conf.MspID, name, orgName)
+		}
+		orgName = name
+	}
 	c := &xdbconfig.FabricConf{
 		ConfigFile: conf.ConfigFile,
 		ChannelID:  conf.ChannelID,
 		Chaincode:  conf.Chaincode,
 		UserName:   conf.UserName,
-		OrgName:    conf.OrgName,
+		OrgName:    orgName,
 	}
 
 	fa, err := fabricchain.New(c)
@@ -44,5 +62,23 @@ func New(conf *config.FabricConf) (*Fabric, error) {
 	return &Fabric{*fa}, nil
 }
 
+// orgNameByMspID finds the organization whose MSP ID is mspID in the connection profile
+func orgNameByMspID(profile, mspID string) (string, error) {
+	backends, err := sdkconfig.FromFile(profile)()
+	if err != nil {
+		return "", errorx.NewCode(err, errorx.ErrCodeConfig, "failed to load fabric connection profile %s", profile)
+	}
+	var orgs map[string]struct{ MSPID string }
+	if err := lookup.New(backends...).UnmarshalKey("organizations", &orgs); err != nil {
+		return "", errorx.NewCode(err, errorx.ErrCodeConfig, "failed to parse organizations in %s", profile)
+	}
+	for name, org := range orgs {
+		if org.MSPID == mspID {
+			return name, nil
+		}
+	}
+	return "", errorx.New(errorx.ErrCodeConfig, "no organization with mspId %s in %s", mspID, profile)
+}
+
 func (f *Fabric) Close() {
 }
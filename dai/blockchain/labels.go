@@ -0,0 +1,97 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+const (
+	// TaskLabelsMaxNum the maximum number of labels of a task
+	TaskLabelsMaxNum = 16
+	// TaskLabelKeyMaxLen the maximum length of the key of a label
+	TaskLabelKeyMaxLen = 63
+	// TaskLabelValueMaxLen the maximum length of the value of a label
+	TaskLabelValueMaxLen = 255
+)
+
+// LabelSelectorKey is the key of the gRPC metadata carrying the label selector of the API token of a request
+// forwarded by the httpserver, formatted by FormatLabels. The request only accesses tasks with all the labels
+// of the selector, and all selectors if there are several.
+const LabelSelectorKey = "x-task-label-selector"
+
+// labelKeyPattern defines the allowed charset of label keys
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+
+// CheckTaskLabels checks the labels of a task, which are metadata attributing the task to teams or projects,
+// such as "team=risk", and never affect the computation
+func CheckTaskLabels(labels map[string]string) error {
+	if len(labels) > TaskLabelsMaxNum {
+		return errorx.New(errorx.ErrCodeParam, "a task can have at most %d labels, got %d", TaskLabelsMaxNum, len(labels))
+	}
+	for k, v := range labels {
+		if len(k) > TaskLabelKeyMaxLen || !labelKeyPattern.MatchString(k) {
+			return errorx.New(errorx.ErrCodeParam, "invalid label key %q, it should be at most %d characters of "+
+				"letters, digits, '.', '_', '/' and '-', starting with a letter or digit", k, TaskLabelKeyMaxLen)
+		}
+		if len(v) > TaskLabelValueMaxLen {
+			return errorx.New(errorx.ErrCodeParam, "value of label %s exceeds %d characters", k, TaskLabelValueMaxLen)
+		}
+	}
+	return nil
+}
+
+// MatchLabels returns whether labels contain all the labels of selector, an empty selector matches all
+func MatchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLabels parses labels formatted as "key=value" pairs with "," as delimiter, like "team=risk,project=p1",
+// an empty string means no labels
+func ParseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, errorx.New(errorx.ErrCodeParam, "invalid label %q, it should be like 'key=value'", pair)
+		}
+		labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	if err := CheckTaskLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// FormatLabels formats labels as "key=value" pairs sorted by keys with "," as delimiter
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
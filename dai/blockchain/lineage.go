@@ -0,0 +1,87 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// ModelLineageSuffix is appended to the model ID as the key of the lineage stored next to the model
+const ModelLineageSuffix = ".lineage"
+
+// ModelLineage describes a version of model, the model of a training task is identified by the taskID.
+// A training task continuing from a finished one, assigned by TaskParams.ModelTaskID, trains the next version
+// of the parent model, and the versions are linked by ParentModelID.
+type ModelLineage struct {
+	ModelID       string             `json:"modelID"`       // ID of the training task
+	Version       int64              `json:"version"`       // starting from 1
+	ParentModelID string             `json:"parentModelID"` // empty if the model is the first version
+	Algorithm     string             `json:"algorithm"`     // algorithm of the model
+	DataIDs       []string           `json:"dataIDs"`       // sample files the model is trained with
+	TrainParams   *pbCom.TrainParams `json:"trainParams"`   // hyperparameters of the training
+	PublishTime   int64              `json:"publishTime"`
+	EndTime       int64              `json:"endTime"`
+	// MetricDelta is the metric against the parent model if the model is trained incrementally
+	MetricDelta *pbCom.MetricDelta `json:"metricDelta,omitempty"`
+	// PrivacyBudget is the differential privacy budget consumed if the model is trained with noise
+	PrivacyBudget *pbCom.PrivacyBudget `json:"privacyBudget,omitempty"`
+	// EarlyStop is the metric of live evaluation tracked if the model is trained with early stopping,
+	// only recorded by the executor of the party with label
+	EarlyStop *pbCom.EarlyStopResult `json:"earlyStop,omitempty"`
+}
+
+// NewModelLineage returns the lineage of the model trained by task
+func NewModelLineage(task FLTask) ModelLineage {
+	l := ModelLineage{
+		ModelID:     task.TaskID,
+		Version:     ModelVersion(task),
+		PublishTime: task.PublishTime,
+		EndTime:     task.EndTime,
+		// recorded by executors when the training task finished
+		PrivacyBudget: task.PrivacyBudget,
+	}
+	if task.AlgoParam != nil {
+		l.ParentModelID = task.AlgoParam.ModelTaskID
+		l.Algorithm = VlAlgorithmListValue[task.AlgoParam.Algo]
+		l.TrainParams = task.AlgoParam.TrainParams
+	}
+	for _, ds := range task.DataSets {
+		l.DataIDs = append(l.DataIDs, ds.DataID)
+	}
+	return l
+}
+
+// ModelVersion returns the version of the model trained by task,
+// models trained before versioning are the first versions
+func ModelVersion(task FLTask) int64 {
+	if task.ModelVersion == 0 {
+		return 1
+	}
+	return task.ModelVersion
+}
+
+// NextModelVersion returns the version of the model to be trained by task continuing from parent,
+// the parent must be a finished training task of the same algorithm
+func NextModelVersion(task, parent FLTask) (int64, error) {
+	if parent.AlgoParam == nil || parent.AlgoParam.TaskType != pbCom.TaskType_LEARN || parent.Status != TaskFinished {
+		return 0, errorx.New(errorx.ErrCodeParam, "parent model %s is not a finished training task", parent.TaskID)
+	}
+	if parent.AlgoParam.Algo != task.AlgoParam.Algo {
+		return 0, errorx.New(errorx.ErrCodeParam, "parent model %s is trained by algorithm %s, got %s", parent.TaskID,
+			VlAlgorithmListValue[parent.AlgoParam.Algo], VlAlgorithmListValue[task.AlgoParam.Algo])
+	}
+	return ModelVersion(parent) + 1, nil
+}
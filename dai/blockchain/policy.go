@@ -0,0 +1,80 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// ColumnPolicyMaxColumns the maximum number of columns allowed by a column policy
+const ColumnPolicyMaxColumns = 4096
+
+// ColumnPolicy is published by the owner of a sample file, and restricts the columns of the file an Executor
+// may use in proxy mode, which is finer-grained than the file authorization. Executors reject the tasks using
+// columns not allowed before downloading the file. A policy for an Executor overrides the one for all Executors,
+// and the latest policy published replaces the previous one.
+type ColumnPolicy struct {
+	FileID     string   `json:"fileID"`
+	Owner      []byte   `json:"owner"`    // owner of the file, who publishes the policy
+	Executor   []byte   `json:"executor"` // Executor the policy applies to, empty for all Executors
+	Columns    []string `json:"columns"`  // columns allowed, including the ID column and the label
+	CreateTime int64    `json:"createTime"`
+}
+
+// PublishColumnPolicyOptions contains parameters for publishing the column policy of a sample file
+type PublishColumnPolicyOptions struct {
+	Policy ColumnPolicy `json:"policy"`
+
+	Signature []byte `json:"signature"` // owner's signature
+}
+
+// CheckColumnPolicy checks the column policy to publish
+func CheckColumnPolicy(policy ColumnPolicy) error {
+	if policy.FileID == "" {
+		return errorx.New(errorx.ErrCodeParam, "missing file ID of column policy")
+	}
+	if len(policy.Columns) == 0 {
+		return errorx.New(errorx.ErrCodeParam, "column policy should allow at least one column")
+	}
+	if len(policy.Columns) > ColumnPolicyMaxColumns {
+		return errorx.New(errorx.ErrCodeParam, "column policy can allow at most %d columns, got %d",
+			ColumnPolicyMaxColumns, len(policy.Columns))
+	}
+	allowed := make(map[string]bool, len(policy.Columns))
+	for _, column := range policy.Columns {
+		if column == "" {
+			return errorx.New(errorx.ErrCodeParam, "empty column name in column policy")
+		}
+		if allowed[column] {
+			return errorx.New(errorx.ErrCodeParam, "duplicated column %s in column policy", column)
+		}
+		allowed[column] = true
+	}
+	return nil
+}
+
+// DisallowedColumns returns the columns not allowed by policy, in the order of columns
+func DisallowedColumns(policy ColumnPolicy, columns []string) []string {
+	allowed := make(map[string]bool, len(policy.Columns))
+	for _, column := range policy.Columns {
+		allowed[column] = true
+	}
+	var disallowed []string
+	for _, column := range columns {
+		if !allowed[column] {
+			disallowed = append(disallowed, column)
+		}
+	}
+	return disallowed
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"bytes"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+)
+
+// RotateKeyOptions contains parameters for rotating the key of an Executor node, signed by both the old key
+// and the new key. The new key takes over the name and addresses of the node, while the old one keeps executing
+// the tasks published to it, and confirms no more tasks after GraceUntil.
+type RotateKeyOptions struct {
+	OldID       []byte `json:"oldID"`
+	NewID       []byte `json:"newID"`
+	CurrentTime int64  `json:"currentTime"` // time when rotating the key
+	GraceUntil  int64  `json:"graceUntil"`  // end of the grace period when both keys are valid
+
+	Signature    []byte `json:"signature"`    // signature of the old key
+	NewSignature []byte `json:"newSignature"` // signature of the new key
+}
+
+// RevokeKeyOptions contains parameters for revoking the old key of an Executor node once the grace period of
+// its key rotation ends, signed by the new key. The grace period is checked against the time claimed by
+// the old key when it confirms tasks, the revoked key confirms no tasks whatever time it claims.
+type RevokeKeyOptions struct {
+	OldID       []byte `json:"oldID"`
+	CurrentTime int64  `json:"currentTime"` // time when revoking the key
+
+	Signature []byte `json:"signature"` // signature of the new key
+}
+
+// KeyRotationMessage returns the message signed by both keys of the key rotation opt
+func KeyRotationMessage(opt RotateKeyOptions) (string, error) {
+	opt.Signature, opt.NewSignature = nil, nil
+	return util.GetSigMessage(opt)
+}
+
+// SignKeyRotation signs the key rotation opt with the old key and the new key
+func SignKeyRotation(opt *RotateKeyOptions, oldKey, newKey ecdsa.PrivateKey) error {
+	msg, err := KeyRotationMessage(*opt)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for key rotation")
+	}
+	sig, err := ecdsa.Sign(oldKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign key rotation with the old key")
+	}
+	newSig, err := ecdsa.Sign(newKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign key rotation with the new key")
+	}
+	opt.Signature, opt.NewSignature = sig[:], newSig[:]
+	return nil
+}
+
+// RotateNode checks the key rotation opt of node, which must be signed by both keys, and returns the record
+// of node marked rotated, and the record of the node with the new key, which is registered at opt.CurrentTime
+func RotateNode(node ExecutorNode, opt RotateKeyOptions) (rotated, next ExecutorNode, err error) {
+	if !bytes.Equal(node.ID, opt.OldID) {
+		return rotated, next, errorx.New(errorx.ErrCodeParam, "bad param: oldID")
+	}
+	if len(node.RotatedTo) > 0 {
+		return rotated, next, errorx.New(errorx.ErrCodeAlreadyUpdate, "key of node %s already rotated to %x", node.Name, node.RotatedTo)
+	}
+	if len(opt.NewID) != ecdsa.PublicKeyLength || bytes.Equal(opt.NewID, opt.OldID) {
+		return rotated, next, errorx.New(errorx.ErrCodeParam, "bad param: newID")
+	}
+	if opt.GraceUntil < opt.CurrentTime {
+		return rotated, next, errorx.New(errorx.ErrCodeParam, "bad param: graceUntil is before currentTime")
+	}
+	msg, err := KeyRotationMessage(opt)
+	if err != nil {
+		return rotated, next, errorx.Internal(err, "failed to get the message to sign")
+	}
+	if err := verifySignature(opt.Signature, opt.OldID, []byte(msg)); err != nil {
+		return rotated, next, errorx.Wrap(err, "bad signature of the old key")
+	}
+	if err := verifySignature(opt.NewSignature, opt.NewID, []byte(msg)); err != nil {
+		return rotated, next, errorx.Wrap(err, "bad signature of the new key")
+	}
+
+	rotated, next = node, node
+	rotated.RotatedTo = opt.NewID
+	rotated.GraceUntil = opt.GraceUntil
+	next.ID = opt.NewID
+	next.RegTime = opt.CurrentTime
+	return rotated, next, nil
+}
+
+// SignKeyRevocation signs the key revocation opt with the new key of the rotation
+func SignKeyRevocation(opt *RevokeKeyOptions, newKey ecdsa.PrivateKey) error {
+	opt.Signature = nil
+	msg, err := util.GetSigMessage(*opt)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for key revocation")
+	}
+	sig, err := ecdsa.Sign(newKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign key revocation with the new key")
+	}
+	opt.Signature = sig[:]
+	return nil
+}
+
+// RevokeNode checks the key revocation opt of node, which must be signed by the key node is rotated to after
+// the grace period of the rotation, and returns the record of node marked revoked
+func RevokeNode(node ExecutorNode, opt RevokeKeyOptions) (ExecutorNode, error) {
+	if !bytes.Equal(node.ID, opt.OldID) {
+		return node, errorx.New(errorx.ErrCodeParam, "bad param: oldID")
+	}
+	if len(node.RotatedTo) == 0 {
+		return node, errorx.New(errorx.ErrCodeParam, "key of node %s is not rotated", node.Name)
+	}
+	if node.Revoked {
+		return node, errorx.New(errorx.ErrCodeAlreadyUpdate, "key of node %s already revoked", node.Name)
+	}
+	if opt.CurrentTime <= node.GraceUntil {
+		return node, errorx.New(errorx.ErrCodeParam, "bad param: the grace period of the rotation has not ended")
+	}
+	sig := opt.Signature
+	opt.Signature = nil
+	msg, err := util.GetSigMessage(opt)
+	if err != nil {
+		return node, errorx.Internal(err, "failed to get the message to sign")
+	}
+	if err := verifySignature(sig, node.RotatedTo, []byte(msg)); err != nil {
+		return node, errorx.Wrap(err, "bad signature of the new key")
+	}
+	node.Revoked = true
+	return node, nil
+}
+
+// CheckNodeConfirming checks the Executor node is allowed to confirm tasks at currentTime, a node whose key
+// is rotated confirms no tasks after the grace period of the rotation, or once its key is revoked
+func CheckNodeConfirming(node ExecutorNode, currentTime int64) error {
+	if node.Revoked {
+		return errorx.New(errorx.ErrCodeParam, "key of executor node %s is rotated to %x and revoked",
+			node.Name, node.RotatedTo)
+	}
+	if len(node.RotatedTo) > 0 && currentTime > node.GraceUntil {
+		return errorx.New(errorx.ErrCodeParam, "key of executor node %s is rotated to %x, and its grace period has ended",
+			node.Name, node.RotatedTo)
+	}
+	return nil
+}
+
+// verifySignature verifies sig of msg is signed by pubkey
+func verifySignature(sig, pubkey, msg []byte) error {
+	if len(sig) != ecdsa.SignatureLength || len(pubkey) != ecdsa.PublicKeyLength {
+		return errorx.New(errorx.ErrCodeBadSignature, "bad signature or public key")
+	}
+	var pk [ecdsa.PublicKeyLength]byte
+	var s [ecdsa.SignatureLength]byte
+	copy(pk[:], pubkey)
+	copy(s[:], sig)
+	if err := ecdsa.Verify(pk, hash.HashUsingSha256(msg), s); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeBadSignature, "failed to verify signature")
+	}
+	return nil
+}
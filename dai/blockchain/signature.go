@@ -0,0 +1,81 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// ResultSignatureSuffix is appended to the name of a prediction result as the key of its signature
+const ResultSignatureSuffix = ".sig"
+
+// ResultSignMessage returns the message signed for the prediction result of the input batchIndex of the task taskID,
+// digest is the SHA256 of the result, the task and the input are signed so that a signature can't be moved to another result
+func ResultSignMessage(taskID string, batchIndex int32, digest []byte) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%x", taskID, batchIndex, digest))
+}
+
+// SignResult signs the prediction result content of the input batchIndex of the task taskID with privateKey
+func SignResult(privateKey ecdsa.PrivateKey, taskID string, batchIndex int32, content []byte) (*pbTask.ResultSignature, error) {
+	digest := sha256.Sum256(content)
+	sig, err := ecdsa.Sign(privateKey, hash.HashUsingSha256(ResultSignMessage(taskID, batchIndex, digest[:])))
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to sign prediction result")
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(privateKey)
+	return &pbTask.ResultSignature{
+		TaskID:     taskID,
+		BatchIndex: batchIndex,
+		Executor:   pubkey[:],
+		Digest:     digest[:],
+		Signature:  sig[:],
+	}, nil
+}
+
+// VerifyResultSignature checks that sig is signed by pubkey for the prediction result content
+// of the input batchIndex of the task taskID, an error describing the mismatch is returned if it's not.
+// The digest signed is trusted if content is nil, e.g. the result is not available to the verifier.
+func VerifyResultSignature(sig *pbTask.ResultSignature, pubkey []byte, taskID string, batchIndex int32, content []byte) error {
+	if sig.TaskID != taskID || sig.BatchIndex != batchIndex {
+		return errorx.New(errorx.ErrCodeBadSignature, "signature is for input %d of task %s", sig.BatchIndex, sig.TaskID)
+	}
+	if !bytes.Equal(sig.Executor, pubkey) {
+		return errorx.New(errorx.ErrCodeBadSignature, "result is signed by %x, not by the node %x", sig.Executor, pubkey)
+	}
+	if content != nil {
+		digest := sha256.Sum256(content)
+		if !bytes.Equal(sig.Digest, digest[:]) {
+			return errorx.New(errorx.ErrCodeBadSignature, "result doesn't match the digest signed, it may have been modified")
+		}
+	}
+	if len(sig.Signature) != ecdsa.SignatureLength || len(pubkey) != ecdsa.PublicKeyLength {
+		return errorx.New(errorx.ErrCodeBadSignature, "bad signature or public key")
+	}
+	var pk [ecdsa.PublicKeyLength]byte
+	var s [ecdsa.SignatureLength]byte
+	copy(pk[:], pubkey)
+	copy(s[:], sig.Signature)
+	if err := ecdsa.Verify(pk, hash.HashUsingSha256(ResultSignMessage(taskID, batchIndex, sig.Digest)), s); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeBadSignature, "failed to verify signature of prediction result")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+const (
+	/* Define Split Strategies of the validation set of Random Split */
+	SplitStrategyRandom     = "random"     // samples are left out randomly, the default
+	SplitStrategyStratified = "stratified" // each class of the label is left out in the same proportion
+	SplitStrategyTime       = "time"       // the latest samples ordered by the time column are left out
+)
+
+// SplitStrategySupported the strategies of choosing the validation set of Random Split
+var SplitStrategySupported = map[string]bool{
+	SplitStrategyRandom:     true,
+	SplitStrategyStratified: true,
+	SplitStrategyTime:       true,
+}
+
+// CheckRandomSplit checks the validation set of Random Split of the algorithm, either percentLO or testFraction,
+// which overrides percentLO, is set, and the strategy is supported, stratified split is for classification only
+func CheckRandomSplit(algo pbCom.Algorithm, rs *pbCom.RandomSplit) error {
+	if rs == nil {
+		return errorx.New(errorx.ErrCodeParam, "parameters of random split are required")
+	}
+	if rs.TestFraction == 0 {
+		if rs.PercentLO <= 0 || rs.PercentLO >= 100 {
+			return errorx.New(errorx.ErrCodeParam, "percentLO of random split should be in (0, 100), got %d", rs.PercentLO)
+		}
+	} else if rs.TestFraction < 0 || rs.TestFraction >= 1 {
+		return errorx.New(errorx.ErrCodeParam, "testFraction of random split should be in (0, 1), got %v", rs.TestFraction)
+	}
+	strategy := rs.Strategy
+	if strategy == "" {
+		strategy = SplitStrategyRandom
+	}
+	if !SplitStrategySupported[strategy] {
+		return errorx.New(errorx.ErrCodeParam, "unsupported split strategy %s, valid options are: random, stratified, time", strategy)
+	}
+	if strategy == SplitStrategyStratified && algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+		return errorx.New(errorx.ErrCodeParam, "stratified split is only supported by classification, such as logistic-vl")
+	}
+	if strategy == SplitStrategyTime && rs.TimeColumn == "" {
+		return errorx.New(errorx.ErrCodeParam, "timeColumn is required by time-ordered split")
+	}
+	if strategy != SplitStrategyTime && rs.TimeColumn != "" {
+		return errorx.New(errorx.ErrCodeParam, "timeColumn is only used by time-ordered split")
+	}
+	return nil
+}
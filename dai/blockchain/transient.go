@@ -0,0 +1,41 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import "strings"
+
+// transientErrors are the messages of errors caused by network or unavailable nodes,
+// contract invocations failed with these errors are not rejected by the chain, and can be retried
+var transientErrors = []string{
+	"connection refused",
+	"connection reset",
+	"i/o timeout",
+	"timeout",
+	"code = Unavailable",
+	"code = DeadlineExceeded",
+	"transport is closing",
+}
+
+// IsTransientError checks whether err is caused by network or unavailable nodes, rather than rejected by the chain
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, e := range transientErrors {
+		if strings.Contains(err.Error(), e) {
+			return true
+		}
+	}
+	return false
+}
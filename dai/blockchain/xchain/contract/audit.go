@@ -0,0 +1,65 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+	"github.com/xuperchain/xuperchain/core/contractsdk/go/code"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+)
+
+// AnchorAudit anchors the hash of an audit record written by a registered Executor, an anchor is never overwritten
+func (x *Xdata) AnchorAudit(ctx code.Context) code.Response {
+	var opt blockchain.AnchorAuditOptions
+	// get opt
+	p, ok := ctx.Args()["opt"]
+	if !ok {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "missing param:opt"))
+	}
+	if err := json.Unmarshal(p, &opt); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal AnchorAuditOptions"))
+	}
+	// only registered executors anchor audit records
+	if _, err := ctx.GetObject([]byte(packNodeIndex(opt.Executor))); err != nil {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "bad param: executor not registered"))
+	}
+	// verify sig
+	msg, err := util.GetSigMessage(opt)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal, "failed to get the message to sign"))
+	}
+	if err := x.checkSign(opt.Signature, opt.Executor, []byte(msg)); err != nil {
+		return code.Error(err)
+	}
+
+	s, err := json.Marshal(opt)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal AnchorAuditOptions"))
+	}
+	index := packAuditIndex(opt.Executor, opt.Hash)
+	if _, err := ctx.GetObject([]byte(index)); err == nil {
+		return code.Error(errorx.New(errorx.ErrCodeAlreadyExists, "duplicated audit hash"))
+	}
+	if err := ctx.PutObject([]byte(index), s); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeWriteBlockchain,
+			"fail to put index-audit on xchain"))
+	}
+	return code.OK([]byte("anchored"))
+}
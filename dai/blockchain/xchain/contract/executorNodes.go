@@ -92,6 +92,113 @@ func (x *Xdata) RegisterExecutorNode(ctx code.Context) code.Response {
 	return code.OK([]byte("added"))
 }
 
+// RotateExecutorKey rotates the key of an Executor node, the node with the new key takes over the name of the node,
+// and the record of the old key is kept to execute the tasks published to it, see blockchain.RotateKeyOptions
+func (x *Xdata) RotateExecutorKey(ctx code.Context) code.Response {
+	var opt blockchain.RotateKeyOptions
+	// get opt
+	p, ok := ctx.Args()["opt"]
+	if !ok {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "missing param:opt"))
+	}
+	// unmarshal opt
+	if err := json.Unmarshal(p, &opt); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal RotateKeyOptions"))
+	}
+	// get the node of the old key
+	s, err := ctx.GetObject([]byte(packNodeIndex(opt.OldID)))
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeNotFound, "node not found"))
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(s, &node); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal node"))
+	}
+	// verify both signatures
+	rotated, next, err := blockchain.RotateNode(node, opt)
+	if err != nil {
+		return code.Error(err)
+	}
+	if _, err := ctx.GetObject([]byte(packNodeIndex(next.ID))); err == nil {
+		return code.Error(errorx.New(errorx.ErrCodeAlreadyExists,
+			"duplicated nodeID"))
+	}
+	rs, err := json.Marshal(rotated)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal Node"))
+	}
+	ns, err := json.Marshal(next)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal Node"))
+	}
+
+	// the old key is kept in index-node and listIndex-node, marked rotated
+	puts := []struct {
+		index string
+		value []byte
+	}{
+		{packNodeIndex(rotated.ID), rs},
+		{packNodeListIndex(rotated), rs},
+		{packNodeIndex(next.ID), ns},
+		{packNodeNameIndex(next.Name), ns},
+		{packNodeListIndex(next), ns},
+	}
+	for _, put := range puts {
+		if err := ctx.PutObject([]byte(put.index), put.value); err != nil {
+			return code.Error(errorx.NewCode(err, errorx.ErrCodeWriteBlockchain,
+				"fail to put %s on xchain", put.index))
+		}
+	}
+	return code.OK([]byte("rotated"))
+}
+
+// RevokeExecutorKey revokes the old key of an Executor node once the grace period of its key rotation ends,
+// the revoked key confirms no tasks, see blockchain.RevokeKeyOptions
+func (x *Xdata) RevokeExecutorKey(ctx code.Context) code.Response {
+	var opt blockchain.RevokeKeyOptions
+	// get opt
+	p, ok := ctx.Args()["opt"]
+	if !ok {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "missing param:opt"))
+	}
+	// unmarshal opt
+	if err := json.Unmarshal(p, &opt); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal RevokeKeyOptions"))
+	}
+	// get the node of the old key
+	s, err := ctx.GetObject([]byte(packNodeIndex(opt.OldID)))
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeNotFound, "node not found"))
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(s, &node); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal node"))
+	}
+	// verify the signature of the new key
+	revoked, err := blockchain.RevokeNode(node, opt)
+	if err != nil {
+		return code.Error(err)
+	}
+	rs, err := json.Marshal(revoked)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal Node"))
+	}
+	for _, index := range []string{packNodeIndex(revoked.ID), packNodeListIndex(revoked)} {
+		if err := ctx.PutObject([]byte(index), rs); err != nil {
+			return code.Error(errorx.NewCode(err, errorx.ErrCodeWriteBlockchain,
+				"fail to put %s on xchain", index))
+		}
+	}
+	return code.OK([]byte("revoked"))
+}
+
 // ListExecutorNodes gets all Executor nodes
 func (x *Xdata) ListExecutorNodes(ctx code.Context) code.Response {
 	var nodes blockchain.ExecutorNodes
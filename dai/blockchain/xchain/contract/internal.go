@@ -27,6 +27,8 @@ const (
 	prefixNodeIndex         = "index_executor_node"
 	prefixNodeNameIndex     = "index_executor_name"
 	prefixNodeListIndex     = "index_executor_node_list"
+	prefixAuditIndex        = "index_audit"
+	prefixColumnPolicyIndex = "index_column_policy"
 )
 
 // subByInt64Max return maxInt64 - N
@@ -87,3 +89,14 @@ func packNodeNameIndex(name string) string {
 func packNodeListIndex(node blockchain.ExecutorNode) string {
 	return fmt.Sprintf("%s/%d/%x", prefixNodeListIndex, subByInt64Max(node.RegTime), node.ID)
 }
+
+// packAuditIndex pack index for anchoring the hash of an audit record written by executor
+func packAuditIndex(executor, hash []byte) string {
+	return fmt.Sprintf("%s/%x/%x", prefixAuditIndex, executor, hash)
+}
+
+// packColumnPolicyIndex pack index for the column policy of file for executor, the hex encoded public key,
+// empty for all executors
+func packColumnPolicyIndex(fileID, executor string) string {
+	return fmt.Sprintf("%s/%s/%s", prefixColumnPolicyIndex, fileID, executor)
+}
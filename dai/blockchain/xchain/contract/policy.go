@@ -0,0 +1,99 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+	"github.com/xuperchain/xuperchain/core/contractsdk/go/code"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+)
+
+// PublishColumnPolicy publishes the column policy of a sample file, only the owner of the file publishes it,
+// and it replaces the previous policy of the file for the same Executor
+func (x *Xdata) PublishColumnPolicy(ctx code.Context) code.Response {
+	var opt blockchain.PublishColumnPolicyOptions
+	// get opt
+	p, ok := ctx.Args()["opt"]
+	if !ok {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "missing param:opt"))
+	}
+	if err := json.Unmarshal(p, &opt); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal PublishColumnPolicyOptions"))
+	}
+	policy := opt.Policy
+	if err := blockchain.CheckColumnPolicy(policy); err != nil {
+		return code.Error(err)
+	}
+	// the file is stored by xdb with its ID as the key
+	fs, err := ctx.GetObject([]byte(policy.FileID))
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeNotFound, "file not found"))
+	}
+	var f xdbchain.File
+	if err := json.Unmarshal(fs, &f); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal, "failed to unmarshal File"))
+	}
+	if !bytes.Equal(f.Owner, policy.Owner) {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "bad param: only the owner of file publishes its column policy"))
+	}
+	// verify sig
+	msg, err := util.GetSigMessage(policy)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal, "failed to get the message to sign"))
+	}
+	if err := x.checkSign(opt.Signature, policy.Owner, []byte(msg)); err != nil {
+		return code.Error(err)
+	}
+
+	s, err := json.Marshal(policy)
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal ColumnPolicy"))
+	}
+	index := packColumnPolicyIndex(policy.FileID, hex.EncodeToString(policy.Executor))
+	if err := ctx.PutObject([]byte(index), s); err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeWriteBlockchain,
+			"fail to put index-columnPolicy on xchain"))
+	}
+	return code.OK([]byte("published"))
+}
+
+// GetColumnPolicy gets the column policy of a sample file for an Executor,
+// the policy for all Executors is returned if the Executor has no policy of its own
+func (x *Xdata) GetColumnPolicy(ctx code.Context) code.Response {
+	fileID, ok := ctx.Args()["fileID"]
+	if !ok {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "missing param:fileID"))
+	}
+	// executor is hex encoded, and empty for the policy of all Executors
+	executor := ctx.Args()["executor"]
+	if len(executor) > 0 {
+		if s, err := ctx.GetObject([]byte(packColumnPolicyIndex(string(fileID), string(executor)))); err == nil {
+			return code.OK(s)
+		}
+	}
+	s, err := ctx.GetObject([]byte(packColumnPolicyIndex(string(fileID), "")))
+	if err != nil {
+		return code.Error(errorx.NewCode(err, errorx.ErrCodeNotFound, "column policy not found"))
+	}
+	return code.OK(s)
+}
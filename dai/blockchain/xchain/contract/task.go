@@ -23,6 +23,7 @@ import (
 	"github.com/xuperchain/xuperchain/core/contractsdk/go/code"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
 	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 )
@@ -50,6 +51,38 @@ func (x *Xdata) PublishTask(ctx code.Context) code.Response {
 	if err := x.checkSign(opt.Signature, t.Requester, []byte(msg)); err != nil {
 		return code.Error(err)
 	}
+	// check the number of parties supported by the algorithm
+	if parties, ok := blockchain.VlAlgorithmParties[t.AlgoParam.Algo]; ok && len(t.DataSets) != parties {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "algorithm %s requires %d parties, got %d data sets",
+			blockchain.VlAlgorithmListValue[t.AlgoParam.Algo], parties, len(t.DataSets)))
+	}
+	// tasks with the same idempotency key share one taskID, and duplicated ones are rejected below
+	if t.IdempotencyKey != "" && t.TaskID != blockchain.IdempotentTaskID(t.Requester, t.IdempotencyKey) {
+		return code.Error(errorx.New(errorx.ErrCodeParam, "taskID %s is not derived from the idempotency key", t.TaskID))
+	}
+	// a training task continuing from a parent model trains its next version
+	if t.AlgoParam.TaskType == pbCom.TaskType_LEARN {
+		t.ModelVersion = 1
+		if t.AlgoParam.ModelTaskID != "" {
+			parent, err := x.getTaskById(ctx, t.AlgoParam.ModelTaskID)
+			if err != nil {
+				return code.Error(err)
+			}
+			if t.ModelVersion, err = blockchain.NextModelVersion(t, parent); err != nil {
+				return code.Error(err)
+			}
+		}
+	}
+
+	// the upstream tasks must be published, and the task starts after all of them finish
+	if len(t.DependsOn) > 0 {
+		getTask := func(id string) (blockchain.FLTask, error) {
+			return x.getTaskById(ctx, id)
+		}
+		if err := blockchain.CheckTaskDependencies(t, getTask); err != nil {
+			return code.Error(err)
+		}
+	}
 
 	t.Status = blockchain.TaskConfirming
 	// marshal fltask
@@ -190,6 +223,12 @@ func (x *Xdata) setTaskConfirmStatus(ctx code.Context, isConfirm bool) code.Resp
 	if err := x.checkSign(opt.Signature, opt.Pubkey, []byte(msg)); err != nil {
 		return code.Error(err)
 	}
+	// a node whose key is rotated confirms no tasks after the grace period, but may reject them
+	if isConfirm {
+		if err := x.checkNodeConfirming(ctx, opt.Pubkey, opt.CurrentTime); err != nil {
+			return code.Error(err)
+		}
+	}
 
 	// check status
 	if t.Status != blockchain.TaskConfirming {
@@ -203,6 +242,11 @@ func (x *Xdata) setTaskConfirmStatus(ctx code.Context, isConfirm bool) code.Resp
 			if _, err := ctx.GetObject([]byte(ds.DataID)); err != nil {
 				return code.Error(errorx.New(errorx.ErrCodeParam, "bad param:taskId, dataId not exist"))
 			}
+			for _, id := range ds.BatchDataIDs {
+				if _, err := ctx.GetObject([]byte(id)); err != nil {
+					return code.Error(errorx.New(errorx.ErrCodeParam, "bad param:taskId, batch dataId %s not exist", id))
+				}
+			}
 			// judge task is confirmed
 			if ds.ConfirmedAt > 0 || ds.RejectedAt > 0 {
 				return code.Error(errorx.New(errorx.ErrCodeAlreadyUpdate, "bad param:taskId, task already confirmed"))
@@ -265,9 +309,10 @@ func (x *Xdata) StartTask(ctx code.Context) code.Response {
 	if err := x.checkSign(opt.Signature, t.Requester, []byte(msg)); err != nil {
 		return code.Error(err)
 	}
-	if t.Status != blockchain.TaskReady && t.Status != blockchain.TaskFailed {
+	if t.Status != blockchain.TaskReady && t.Status != blockchain.TaskFailed && t.Status != blockchain.TaskCancelled &&
+		t.Status != blockchain.TaskTimeout {
 		return code.Error(errorx.New(errorx.ErrCodeParam,
-			"start task error, task status is not Ready or Failed, taskId: %s, taskStatus: %s", t.TaskID, t.Status))
+			"start task error, task status is not Ready, Failed, Cancelled or Timeout, taskId: %s, taskStatus: %s", t.TaskID, t.Status))
 	}
 	// update task status
 	t.Status = blockchain.TaskToProcess
@@ -334,11 +379,19 @@ func (x *Xdata) setTaskExecuteStatus(ctx code.Context, isFinish bool) code.Respo
 		t.Status = blockchain.TaskFinished
 		t.EndTime = opt.CurrentTime
 		t.Result = opt.Result
+		t.PrivacyBudget = opt.PrivacyBudget
+		t.BatchResults = opt.BatchResults
 
 		if opt.ErrMessage != "" {
 			t.Status = blockchain.TaskFailed
 			t.ErrMessage = opt.ErrMessage
 		}
+		if opt.Cancelled {
+			t.Status = blockchain.TaskCancelled
+		}
+		if opt.TimedOut {
+			t.Status = blockchain.TaskTimeout
+		}
 	} else {
 		if t.Status != blockchain.TaskToProcess {
 			return code.Error(errorx.New(errorx.ErrCodeParam,
@@ -394,6 +447,21 @@ func (x *Xdata) checkSign(sign, owner, mes []byte) (err error) {
 	return nil
 }
 
+// checkNodeConfirming checks the Executor node is allowed to confirm tasks at currentTime, nodes not registered
+// are allowed as before. The contract can't read the time of the transaction, so the time claimed by the node
+// is checked, and its old key is revoked by the new key once the grace period ends, see RevokeExecutorKey
+func (x *Xdata) checkNodeConfirming(ctx code.Context, executor []byte, currentTime int64) error {
+	s, err := ctx.GetObject([]byte(packNodeIndex(executor)))
+	if err != nil {
+		return nil
+	}
+	var node blockchain.ExecutorNode
+	if err := json.Unmarshal(s, &node); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal, "fail to unmarshal node")
+	}
+	return blockchain.CheckNodeConfirming(node, currentTime)
+}
+
 // checkExecutor used for Executor validity check, only the Executor specified by the Requester can confirm the task
 func (x *Xdata) checkExecutor(executor []byte, dataSets []*pbTask.DataForTask) bool {
 	for _, ds := range dataSets {
@@ -38,6 +38,40 @@ func (x *XChain) RegisterExecutorNode(opt *blockchain.AddNodeOptions) error {
 	return nil
 }
 
+// RotateExecutorKey rotates the key of an Executor node on xchain
+func (x *XChain) RotateExecutorKey(opt *blockchain.RotateKeyOptions) error {
+	opts, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal RotateKeyOptions")
+	}
+	args := map[string]string{
+		"opt": string(opts),
+	}
+	mName := "RotateExecutorKey"
+	if _, err = x.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeExecutorKey revokes the old key of an Executor node on xchain once the grace period of its key rotation ends
+func (x *XChain) RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error {
+	opts, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal RevokeKeyOptions")
+	}
+	args := map[string]string{
+		"opt": string(opts),
+	}
+	mName := "RevokeExecutorKey"
+	if _, err = x.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ListExecutorNodes gets all Executor nodes from xchain
 func (x *XChain) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
 	var nodes blockchain.ExecutorNodes
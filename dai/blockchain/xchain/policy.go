@@ -0,0 +1,58 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+)
+
+// PublishColumnPolicy publishes the column policy of a sample file on xchain
+func (x *XChain) PublishColumnPolicy(opt *blockchain.PublishColumnPolicyOptions) error {
+	opts, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal PublishColumnPolicyOptions")
+	}
+	args := map[string]string{
+		"opt": string(opts),
+	}
+	mName := "PublishColumnPolicy"
+	if _, err := x.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetColumnPolicy gets the column policy of a sample file for executor from xchain
+func (x *XChain) GetColumnPolicy(fileID string, executor []byte) (policy blockchain.ColumnPolicy, err error) {
+	args := map[string]string{
+		"fileID":   fileID,
+		"executor": hex.EncodeToString(executor),
+	}
+	mName := "GetColumnPolicy"
+	s, err := x.QueryContract(args, mName)
+	if err != nil {
+		return policy, err
+	}
+	if err = json.Unmarshal(s, &policy); err != nil {
+		return policy, errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal ColumnPolicy")
+	}
+	return policy, nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xuper-sdk-go/xchain"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	// DefaultPoolSize is the number of connections to a chain node if 'poolSize' is not set
+	DefaultPoolSize = 4
+	// healthCheckInterval is the interval of checking connections in pools
+	healthCheckInterval = 30 * time.Second
+)
+
+// pools are the connection pools in use, keyed by chain address, and shared by the clients of the same chain node
+var pools = struct {
+	sync.Mutex
+	m map[string]*connPool
+}{m: make(map[string]*connPool)}
+
+// dialer connects to a chain node
+type dialer func(address string) (*xchain.XuperClient, error)
+
+// connPool keeps long-lived connections to a chain node, and contract invocations use them by turns.
+// A connection is replaced if it fails, found by health checks or by transient errors of invocations.
+type connPool struct {
+	address string
+	dial    dialer
+
+	mutex   sync.Mutex
+	clients []*xchain.XuperClient
+	next    int
+	refs    int // the number of XChain clients using the pool
+	stop    chan struct{}
+}
+
+// acquirePool returns the pool of address, creates it with size connections if there isn't one,
+// the pool is closed when all its users release it
+func acquirePool(address string, size int, dial dialer) (*connPool, error) {
+	pools.Lock()
+	defer pools.Unlock()
+
+	if p, ok := pools.m[address]; ok {
+		p.mutex.Lock()
+		p.refs++
+		p.mutex.Unlock()
+		return p, nil
+	}
+	if size <= 0 {
+		size = DefaultPoolSize
+	}
+	p := &connPool{
+		address: address,
+		dial:    dial,
+		refs:    1,
+		stop:    make(chan struct{}),
+	}
+	for i := 0; i < size; i++ {
+		c, err := dial(address)
+		if err != nil {
+			p.closeClients()
+			return nil, err
+		}
+		p.clients = append(p.clients, c)
+	}
+	pools.m[address] = p
+	go p.healthCheck()
+	return p, nil
+}
+
+// get returns a connection by turns
+func (p *connPool) get() *xchain.XuperClient {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	c := p.clients[p.next]
+	p.next = (p.next + 1) % len(p.clients)
+	return c
+}
+
+// reconnect replaces the failed connection c with a new one, no-op if c has been replaced
+func (p *connPool) reconnect(c *xchain.XuperClient) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i := range p.clients {
+		if p.clients[i] != c {
+			continue
+		}
+		n, err := p.dial(p.address)
+		if err != nil {
+			logger.WithError(err).Warnf("failed to reconnect to chain node %s", p.address)
+			return
+		}
+		closeClient(c)
+		p.clients[i] = n
+		logger.Infof("reconnected to chain node %s", p.address)
+		return
+	}
+}
+
+// healthCheck replaces the connections in failure periodically until the pool is closed
+func (p *connPool) healthCheck() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mutex.Lock()
+			clients := append([]*xchain.XuperClient(nil), p.clients...)
+			p.mutex.Unlock()
+			for _, c := range clients {
+				if !healthy(c) {
+					p.reconnect(c)
+				}
+			}
+		}
+	}
+}
+
+// release closes the pool if there are no other users
+func (p *connPool) release() {
+	pools.Lock()
+	defer pools.Unlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.refs--; p.refs > 0 {
+		return
+	}
+	close(p.stop)
+	p.closeClients()
+	delete(pools.m, p.address)
+	logger.Infof("closed connections to chain node %s", p.address)
+}
+
+func (p *connPool) closeClients() {
+	for _, c := range p.clients {
+		closeClient(c)
+	}
+	p.clients = nil
+}
+
+// healthy checks whether the connections of c are usable, idle connections are reconnected on use
+func healthy(c *xchain.XuperClient) bool {
+	for _, conn := range []interface{ GetState() connectivity.State }{c.XchainConn, c.XendorserConn} {
+		if s := conn.GetState(); s == connectivity.TransientFailure || s == connectivity.Shutdown {
+			return false
+		}
+	}
+	return true
+}
+
+func closeClient(c *xchain.XuperClient) {
+	if err := c.XchainConn.Close(); err != nil {
+		logger.WithError(err).Error("failed to close xchain client")
+	}
+	if err := c.XendorserConn.Close(); err != nil {
+		logger.WithError(err).Error("failed to close endorser client")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xuper-sdk-go/xchain"
+
+	xchainblockchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain/xchain"
+)
+
+func TestConnPool(t *testing.T) {
+	address := "127.0.0.1:1"
+	dials := 0
+	dial := func(address string) (*xchain.XuperClient, error) {
+		dials++
+		return xchain.NewXuperClient(address)
+	}
+
+	p, err := acquirePool(address, 2, dial)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	// clients of the same chain node share the pool
+	shared, err := acquirePool(address, 2, dial)
+	if err != nil {
+		t.Fatalf("failed to acquire pool: %v", err)
+	}
+	if shared != p || dials != 2 {
+		t.Fatalf("expected the pool to be shared, got %d dials", dials)
+	}
+
+	c1, c2 := p.get(), p.get()
+	if c1 == c2 || p.get() != c1 {
+		t.Error("expected connections to be used by turns")
+	}
+
+	p.reconnect(c1)
+	if dials != 3 || p.get() == c1 {
+		t.Error("expected the failed connection to be replaced")
+	}
+	// the connection replaced already is not replaced again
+	p.reconnect(c1)
+	if dials != 3 {
+		t.Errorf("unexpected reconnection, got %d dials", dials)
+	}
+
+	p.release()
+	if _, ok := pools.m[address]; !ok {
+		t.Fatal("the pool in use should not be closed")
+	}
+	p.release()
+	if _, ok := pools.m[address]; ok {
+		t.Fatal("the pool without users should be closed")
+	}
+}
+
+func TestPooledCaller(t *testing.T) {
+	address := "127.0.0.1:2"
+	p, err := acquirePool(address, 1, xchain.NewXuperClient)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer p.release()
+	x := &XChain{pool: p}
+
+	c := p.get()
+	rejected := x.pooled(func(xc *xchainblockchain.XChain, args map[string]string, mName string) ([]byte, error) {
+		return nil, errors.New(`{"code":"10004","message":"task not found"}`)
+	})
+	rejected(nil, "GetTaskByID")
+	if p.get() != c {
+		t.Error("connection should be kept if the contract rejects the call")
+	}
+
+	var used *xchain.XuperClient
+	failed := x.pooled(func(xc *xchainblockchain.XChain, args map[string]string, mName string) ([]byte, error) {
+		used = xc.XchainClient
+		return nil, errors.New("rpc error: code = Unavailable desc = connection refused")
+	})
+	failed(nil, "GetTaskByID")
+	if used != c || p.get() == c {
+		t.Error("connection should be replaced on transient errors")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+)
+
+const (
+	// DefaultRetryInterval is the interval before the first retry if 'retryInterval' is not set
+	DefaultRetryInterval = time.Second
+	// maxRetryInterval limits the interval growing by exponential backoff
+	maxRetryInterval = 30 * time.Second
+)
+
+// retryPolicy defines how to retry contract invocations
+type retryPolicy struct {
+	maxRetries int           // the max number of retries, 0 means no retry
+	interval   time.Duration // the interval before the first retry, doubled after each retry
+}
+
+// do calls fn until it succeeds, or retryable returns false, or retries are exhausted.
+// The interval between attempts grows exponentially with jitter.
+func (p retryPolicy) do(mName string, retryable func(error) bool, fn func() ([]byte, error)) ([]byte, error) {
+	interval := p.interval
+	for attempt := 0; ; attempt++ {
+		resp, err := fn()
+		if err == nil || attempt >= p.maxRetries || !retryable(err) {
+			return resp, err
+		}
+		// sleep a random duration in [interval/2, interval) to avoid retrying at the same time
+		wait := interval/2 + time.Duration(rand.Int63n(int64(interval/2)+1))
+		logger.WithError(err).Debugf("failed to call contract method %s, retry %d/%d after %v",
+			mName, attempt+1, p.maxRetries, wait)
+		time.Sleep(wait)
+		if interval *= 2; interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// isRetryableQueryError checks whether a query can be retried. Queries are idempotent, so they are
+// retried on transient errors and on errors without a contract error code. Errors carrying other codes
+// are definitive answers of the contract, such as "task not found", and retrying them makes no sense
+func isRetryableQueryError(err error) bool {
+	if isTransientError(err) {
+		return true
+	}
+	code, _ := errorx.Parse(err)
+	return code == errorx.ErrCodeInternal
+}
+
+// isTransientError checks whether err is caused by network, rather than rejected by the chain
+func isTransientError(err error) bool {
+	return blockchain.IsTransientError(err)
+}
+
+// InvokeContract invokes the contract, retries only if the error is transient
+func (x *XChain) InvokeContract(args map[string]string, mName string) ([]byte, error) {
+	return x.retry.do(mName, isTransientError, func() ([]byte, error) {
+		return x.invoke(args, mName)
+	})
+}
+
+// QueryContract queries the contract, retries unless the contract gives a definitive answer
+func (x *XChain) QueryContract(args map[string]string, mName string) ([]byte, error) {
+	return x.retry.do(mName, isRetryableQueryError, func() ([]byte, error) {
+		return x.query(args, mName)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := retryPolicy{maxRetries: 3, interval: time.Millisecond}
+	transient := errors.New("rpc error: code = Unavailable desc = connection refused")
+	rejected := errors.New(`{"code":"10004","message":"task not found"}`)
+	internal := errors.New(`{"code":"10001","message":"failed to get task from chain"}`)
+
+	cases := []struct {
+		name      string
+		retryable func(error) bool
+		errs      []error // errors returned by each attempt, nil means success
+		calls     int
+		fail      bool
+	}{
+		{"succeedAfterTransientErrors", isTransientError, []error{transient, transient, nil}, 3, false},
+		{"exhausted", isTransientError, []error{transient, transient, transient, transient, nil}, 4, true},
+		{"rejectedNotRetried", isTransientError, []error{rejected, nil}, 1, true},
+		{"queryRetriedOnTransientError", isRetryableQueryError, []error{transient, nil}, 2, false},
+		{"queryRetriedOnInternalError", isRetryableQueryError, []error{internal, nil}, 2, false},
+		{"queryNotFoundNotRetried", isRetryableQueryError, []error{rejected, nil}, 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			calls := 0
+			_, err := policy.do("ListTask", c.retryable, func() ([]byte, error) {
+				err := c.errs[calls]
+				calls++
+				return nil, err
+			})
+			if calls != c.calls {
+				t.Errorf("expected %d calls, got %d", c.calls, calls)
+			}
+			if (err != nil) != c.fail {
+				t.Errorf("expected failure: %v, got error: %v", c.fail, err)
+			}
+		})
+	}
+}
+
+// TestRetryXdbMethods checks that methods of xdb's contract used by executors are retried
+func TestRetryXdbMethods(t *testing.T) {
+	transient := errors.New("rpc error: code = Unavailable desc = connection refused")
+	calls := make(map[string]int)
+	// each method fails once with a transient error, then succeeds
+	caller := func(args map[string]string, mName string) ([]byte, error) {
+		calls[mName]++
+		if calls[mName] == 1 {
+			return nil, transient
+		}
+		if mName == "GetFileByID" {
+			return []byte("{}"), nil
+		}
+		return []byte("[]"), nil
+	}
+	x := &XChain{
+		retry:  retryPolicy{maxRetries: 1, interval: time.Millisecond},
+		invoke: caller,
+		query:  caller,
+	}
+
+	if _, err := x.GetFileByID("file"); err != nil {
+		t.Errorf("GetFileByID failed: %v", err)
+	}
+	if _, err := x.ListNodes(); err != nil {
+		t.Errorf("ListNodes failed: %v", err)
+	}
+	if _, err := x.ListFileAuthApplications(&xdbchain.ListFileAuthOptions{}); err != nil {
+		t.Errorf("ListFileAuthApplications failed: %v", err)
+	}
+	if err := x.PublishFileAuthApplication(&xdbchain.PublishFileAuthOptions{}); err != nil {
+		t.Errorf("PublishFileAuthApplication failed: %v", err)
+	}
+	for _, mName := range []string{"GetFileByID", "ListNodes", "ListFileAuthApplications", "PublishFileAuthApplication"} {
+		if calls[mName] != 2 {
+			t.Errorf("expected %s to be called twice, got %d", mName, calls[mName])
+		}
+	}
+}
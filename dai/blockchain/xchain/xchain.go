@@ -15,6 +15,7 @@ package xchain
 
 import (
 	"github.com/sirupsen/logrus"
+	"github.com/xuperchain/xuper-sdk-go/xchain"
 
 	xchainblockchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain/xchain"
 	xdataconfig "github.com/PaddlePaddle/PaddleDTX/xdb/config"
@@ -24,10 +25,21 @@ import (
 
 var logger = logrus.WithField("module", "xchain")
 
+// XChain wraps the xchain client of xdb, task and node related contract invocations are retried
+// according to 'maxRetries' and 'retryInterval' in XchainConf, and use the pooled connections to 'chainAddress'
 type XChain struct {
 	xchainblockchain.XChain
+	retry retryPolicy
+	pool  *connPool
+
+	// invoke and query call the contract once without retry
+	invoke contractCaller
+	query  contractCaller
 }
 
+// contractCaller calls the contract method mName with args
+type contractCaller func(args map[string]string, mName string) ([]byte, error)
+
 // New creates a XChain client used for connecting and requesting blockchain
 func New(conf *config.XchainConf) (*XChain, error) {
 	config := &xdataconfig.XchainConf{
@@ -41,16 +53,43 @@ func New(conf *config.XchainConf) (*XChain, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &XChain{*xc}, nil
+	// xdb's client validates the configuration and retrieves the account,
+	// and its connection is replaced by the pooled ones
+	closeClient(xc.XchainClient)
+	pool, err := acquirePool(conf.ChainAddress, conf.PoolSize, xchain.NewXuperClient)
+	if err != nil {
+		return nil, err
+	}
+	xc.XchainClient = pool.get()
+	retry := retryPolicy{
+		maxRetries: conf.MaxRetries,
+		interval:   conf.RetryInterval,
+	}
+	if retry.interval <= 0 {
+		retry.interval = DefaultRetryInterval
+	}
+	x := &XChain{XChain: *xc, retry: retry, pool: pool}
+	x.invoke = x.pooled((*xchainblockchain.XChain).InvokeContract)
+	x.query = x.pooled((*xchainblockchain.XChain).QueryContract)
+	return x, nil
 }
 
-// Close closes client
-func (x *XChain) Close() {
-	if err := x.XChain.XchainClient.XchainConn.Close(); err != nil {
-		logger.WithError(err).Error("failed to close xchain client")
-	}
-	if err := x.XChain.XchainClient.XendorserConn.Close(); err != nil {
-		logger.WithError(err).Error("failed to close endorser client")
+// pooled returns the caller which calls the contract by call with a pooled connection,
+// the connection is replaced if the call fails with a transient error
+func (x *XChain) pooled(call func(*xchainblockchain.XChain, map[string]string, string) ([]byte, error)) contractCaller {
+	return func(args map[string]string, mName string) ([]byte, error) {
+		xc := x.XChain
+		xc.XchainClient = x.pool.get()
+		resp, err := call(&xc, args, mName)
+		if err != nil && isTransientError(err) {
+			x.pool.reconnect(xc.XchainClient)
+		}
+		return resp, err
 	}
+}
+
+// Close releases the pooled connections, which are closed if no other client uses them
+func (x *XChain) Close() {
+	x.pool.release()
 	logger.Info("close xchain client")
 }
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xchain
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// The following methods of xdb's XChain are used by executors during every task.
+// They are redefined here, because the promoted methods call xdb's InvokeContract
+// and QueryContract directly, and are never retried.
+
+// GetFileByID gets file by id from xchain
+func (x *XChain) GetFileByID(id string) (xdbchain.File, error) {
+	var f xdbchain.File
+	args := map[string]string{
+		"id":          id,
+		"currentTime": strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+	mName := "GetFileByID"
+	s, err := x.QueryContract(args, mName)
+	if err != nil {
+		return f, err
+	}
+	if err = json.Unmarshal(s, &f); err != nil {
+		return f, errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal File")
+	}
+	return f, nil
+}
+
+// ListNodes gets all storage nodes from xchain
+func (x *XChain) ListNodes() (xdbchain.Nodes, error) {
+	var nodes xdbchain.Nodes
+	args := map[string]string{}
+	mName := "ListNodes"
+	s, err := x.QueryContract(args, mName)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(s, &nodes); err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to unmarshal nodes")
+	}
+	return nodes, nil
+}
+
+// PublishFileAuthApplication publishes file authorization application on xchain
+func (x *XChain) PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error {
+	s, err := json.Marshal(*opt)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal,
+			"failed to marshal PublishFileAuthOptions")
+	}
+	args := map[string]string{
+		"opt": string(s),
+	}
+	mName := "PublishFileAuthApplication"
+	if _, err = x.InvokeContract(args, mName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListFileAuthApplications lists file authorization applications from xchain
+func (x *XChain) ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error) {
+	var fas xdbchain.FileAuthApplications
+
+	opts, err := json.Marshal(*opt)
+	if err != nil {
+		return fas, errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to marshal ListFileAuthOptions")
+	}
+	args := map[string]string{
+		"opt": string(opts),
+	}
+	mName := "ListFileAuthApplications"
+	s, err := x.QueryContract(args, mName)
+	if err != nil {
+		return fas, err
+	}
+	if err = json.Unmarshal(s, &fas); err != nil {
+		return fas, errorx.NewCode(err, errorx.ErrCodeInternal,
+			"fail to unmarshal FileAuthApplications")
+	}
+	return fas, nil
+}
@@ -7,6 +7,10 @@ executorBinary=executor
 executorClientBinary=executor-cli
 requesterClientBinary=requester-cli
 
+# build information embedded into binaries
+versionPkg=github.com/PaddlePaddle/PaddleDTX/dai/util/version
+ldflags="-X $versionPkg.GitCommit=$(git rev-parse --short HEAD) -X $versionPkg.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+
 # use temporary container to compile output, making working image more simplified
 docker run -it --rm \
     -v ${PWD}:/workspace \
@@ -15,8 +19,9 @@ docker run -it --rm \
     -e GONOSUMDB=* \
     -e GOPROXY=https://goproxy.cn \
     -e GO111MODULE=on \
-    golang:1.13.4 bash -c "go build -o ./bin/$executorBinary && go build -o ./bin/$executorClientBinary ./executor/cmd \
-    && go build -o ./bin/$requesterClientBinary ./requester/cmd && chmod 777 ./bin" \
+    golang:1.13.4 bash -c "go build -ldflags '$ldflags' -o ./bin/$executorBinary \
+    && go build -ldflags '$ldflags' -o ./bin/$executorClientBinary ./executor/cmd \
+    && go build -ldflags '$ldflags' -o ./bin/$requesterClientBinary ./requester/cmd && chmod 777 ./bin" \
 
 # build image
 docker rmi -f $mirrorAddr/paddledtx-dai:${VERSION}
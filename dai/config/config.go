@@ -14,11 +14,36 @@
 package config
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+)
+
+// EnvPrefix is the prefix of the environment variables which override sensitive configuration values,
+// e.g. PADDLEDTX_EXECUTOR_PRIVATEKEY overrides 'executor.privateKey'
+const EnvPrefix = "PADDLEDTX"
+
+const (
+	// DefaultConfigPath is the configuration file of the executor if ConfigPathEnv is not set
+	DefaultConfigPath = "conf/config.toml"
+	// ConfigPathEnv is the environment variable of the configuration path of the executor, see InitConfig
+	ConfigPathEnv = EnvPrefix + "_CONFIG"
+	// ConfigPathSeparator separates the files in a configuration path
+	ConfigPathSeparator = ","
+)
 
-	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+const (
+	// RoleExecutor executes tasks, it is the default role of nodes
+	RoleExecutor = "executor"
+	// RoleObserver monitors tasks on the chain and serves queries, but never executes tasks,
+	// nor downloads samples or stores models, e.g. the node of a consortium auditor
+	RoleObserver = "observer"
 )
 
 var (
@@ -36,58 +61,361 @@ type ExecutorConf struct {
 	PrivateKey      string // private key
 	PaddleFLAddress string
 	PaddleFLRole    int
+	Role            string            // "executor" or "observer", the default is "executor"
 	KeyPath         string            // key path, include private key and public key
 	HttpServer      *HttpServerConf   // include executor node's httpserver configuration
 	Mode            *ExecutorModeConf // the task execution type
 	Mpc             *ExecutorMpcConf
 	Storage         *ExecutorStorageConf // model storage and prediction results storage
 	Blockchain      *ExecutorBlockchainConf
+	HotReload       bool             // whether to apply changes of mpc limits and log level without restarting
+	TLS             *TLSConf         // gRPC connections are plaintext if it is not configured
+	ShutdownTimeout time.Duration    // maximum time to wait for tasks in execution on shutdown
+	GrpcReflection  bool             // whether to register the gRPC server reflection service, for tools like grpcurl
+	KeyProvider     *KeyProviderConf // where private keys are read from, the default is the key files under KeyPath
+	Tracing         *TracingConf     // tasks are not traced if it is not configured
+	Callback        *CallbackConf    // how task callbacks are sent, the defaults are used if it is not configured
+	Audit           *AuditConf       // tasks are not audited if it is not configured
+	Pprof           *PprofConf       // profiling endpoints are not served if it is not configured
+	StatsD          *StatsDConf      // metrics are not sent to StatsD if it is not configured
+	Outbox          *OutboxConf      // how task status is buffered in blockchain outages, the defaults are used if not configured
+
+	// RequesterAllowlist restricts the requesters whose tasks are executed, all requesters are allowed if it is not configured
+	RequesterAllowlist *RequesterAllowlistConf
+
+	// PreviousPrivateKey is the private key before the latest key rotation, read from the key files under
+	// the "previous" directory of KeyPath, which executes the tasks published to the node before the rotation
+	PreviousPrivateKey string
+}
+
+// RequesterAllowlistConf defines the requesters allowed to use the executor, identified by their public keys in hex,
+// such as the requesters of consortium partners. Public keys are read from both Requesters and File, which has
+// one public key per line, empty lines and lines starting with '#' are ignored. File is watched and reloaded
+// when it changes, Requesters are reloaded if hotReload is enabled.
+// Tasks published by other requesters are rejected on confirmation, and the requests signed by them to get
+// prediction results, export models or get feature importance are refused.
+type RequesterAllowlistConf struct {
+	Requesters []string
+	File       string
+}
+
+// OutboxConf defines how the terminal status of tasks is buffered while the blockchain is unreachable,
+// so that tasks keep running through a transient outage instead of failing. At most Size updates are buffered,
+// they're recorded when the blockchain comes back, and the status already committed on the chain wins.
+// The interval between attempts to reach the blockchain starts at RetryInterval, and is doubled after each
+// failed attempt up to MaxRetryInterval.
+type OutboxConf struct {
+	Size             int           // the default is 1000
+	RetryInterval    time.Duration // the default is "1s"
+	MaxRetryInterval time.Duration // the default is "1m"
+}
+
+// StatsDConf defines the StatsD server at Host:Port that metrics are sent to over UDP, in addition to or
+// instead of the Prometheus ones exposed by the httpserver. The names of metrics start with Prefix,
+// the default is "paddledtx.executor". Counters and durations are sent when they are recorded,
+// gauges every FlushInterval, the default is "10s".
+type StatsDConf struct {
+	Host          string
+	Port          int
+	Prefix        string
+	FlushInterval time.Duration
+}
+
+// PprofConf serves the endpoints of net/http/pprof under '/debug/pprof/' on Address, separate from the httpserver.
+// The profiles reveal the memory of the executor, so Address is only allowed on the loopback interface
+// unless AllowRemote is true, and the endpoints are closed after Duration, the default is "1h",
+// so that they can't be left exposed by accident.
+type PprofConf struct {
+	Address     string
+	AllowRemote bool
+	Duration    time.Duration
+}
+
+// AuditConf defines the audit log, to which the executor appends a record of each task it confirms or rejects
+// and of the terminal status of each task it executes. The file is append-only and never rotated.
+// If Anchor is true, the hash of each record is also anchored on the blockchain.
+type AuditConf struct {
+	Path   string
+	Anchor bool
+}
+
+// CallbackConf defines how the executor recording the terminal status of a task notifies its callback URL.
+// If Secret is set, the JSON body is signed by HMAC-SHA256 with it, and the signature is sent in the header
+// "X-DAI-Signature" as "sha256=<hex>". A failed notification is retried at most MaxRetries times,
+// the interval before the first retry is RetryInterval, doubled after each retry.
+type CallbackConf struct {
+	Secret        string
+	MaxRetries    int           // the max number of retries, the default is 0, which means no retry
+	RetryInterval time.Duration // the default is "1s"
+	Timeout       time.Duration // timeout of each request, the default is "10s"
+}
+
+// TracingConf defines the OpenTelemetry collector that spans of tasks are exported to by OTLP/gRPC.
+// SampleRate is the fraction of tasks traced, in (0, 1], the default is 1 if it is 0.
+// Tasks started by other executors follow their sampling decisions.
+type TracingConf struct {
+	Endpoint   string
+	Insecure   bool // whether to connect to the collector without TLS
+	SampleRate float64
+}
+
+// KeyProviderConf defines where the private keys not set in the config file are read from.
+// Type is "file" or "vault", the default is "file", which reads the key files under KeyPath.
+// If Type is "vault", KeyPath of the executor, [executor.mode.self] and [executor.storage.xuperdb] is the path of
+// the Vault secret holding the key in its "privateKey" field, e.g. "secret/data/executor1", the keys never touch disk.
+type KeyProviderConf struct {
+	Type  string
+	Vault *VaultConf
+}
+
+// VaultConf defines the HashiCorp Vault server and the credentials to authenticate,
+// if Token is empty, the executor logs in with RoleID and SecretID using the AppRole auth method.
+type VaultConf struct {
+	Address  string
+	Token    string
+	RoleID   string
+	SecretID string
+	CAFile   string        // CA certificates to verify the Vault server, the system roots are used if it is empty
+	Timeout  time.Duration // timeout of requests to Vault, the default is "10s"
+}
+
+// TLSConf defines the certificates used by the TLS connections of gRPC
+// CertFile and KeyFile are the certificate and private key of the local node,
+// CAFile is used to verify the certificates of the other side, the system roots are used if it is empty.
+// If ClientAuth is true, the server requires and verifies client certificates, that is mutual TLS.
+// The certificate is reloaded when CertFile or KeyFile changes, and every ReloadInterval if it's positive,
+// it applies to new connections and established ones are kept.
+type TLSConf struct {
+	CertFile       string
+	KeyFile        string
+	CAFile         string
+	ClientAuth     bool
+	ReloadInterval time.Duration
 }
 
 // HttpServerConf defines the configuration required to start the executor node's httpserver
 // 'AllowCros' decides whether to allow cross-domain requests, the default is false
+// 'MetricsSwitch' decides whether to expose Prometheus metrics on '/metrics', the default is "off"
+// 'RateLimit' limits requests of each client, requests are not limited if it is not configured
+// 'Auth' requires a bearer token for requests, anyone can access the httpserver if it is not configured
 type HttpServerConf struct {
-	Switch      string
-	HttpAddress string
-	HttpPort    string
-	AllowCros   bool
+	Switch        string
+	HttpAddress   string
+	HttpPort      string
+	AllowCros     bool
+	MetricsSwitch string
+	RateLimit     *RateLimitConf
+	Auth          *HttpAuthConf
+}
+
+// HttpAuthConf defines the API tokens accepted in the header 'Authorization: Bearer <token>'.
+// Tokens are read from both Tokens and TokenFile, which has one token per line,
+// empty lines and lines starting with '#' are ignored. TokenFile is watched and reloaded when it changes,
+// Tokens are reloaded if hotReload is enabled. '/healthz' and '/readyz' never require a token.
+type HttpAuthConf struct {
+	Tokens    []string
+	TokenFile string
+}
+
+// RateLimitConf defines token bucket limits of the httpserver, each client has its own bucket.
+// RequestsPerSecond is the rate tokens are refilled, 0 means no limit, Burst is the maximum number
+// of tokens and defaults to RequestsPerSecond rounded up. The limits of Endpoints override the global one.
+// KeyBy is "ip" or "token", the default is "ip", "token" limits the requests with the same API token together,
+// and requests without a valid token by the client IP. '/healthz', '/readyz' and '/metrics' are never limited.
+type RateLimitConf struct {
+	RequestsPerSecond float64
+	Burst             int
+	KeyBy             string
+	Endpoints         []EndpointRateLimitConf
+}
+
+// EndpointRateLimitConf defines the limit of requests with the URL path Path
+type EndpointRateLimitConf struct {
+	Path              string
+	RequestsPerSecond float64
+	Burst             int
 }
 
 // ExecutorModeConf defines the task execution type, such as proxy-execution or self-execution.
 // "Self" is suitable for the executor node and the dataOwner node are the same organization and execute by themselves,
 // and the executor node can download sample files from the dataOwner node without permission application.
+// If TLS is configured, sample files are downloaded over HTTPS, the executor presents its certificate
+// and verifies the certificates of dataOwner and storage nodes against TLS.CAFile.
+// If Proxy is configured, sample files are downloaded through the HTTP proxy, and certificates are still verified.
 type ExecutorModeConf struct {
-	Type string
-	Self *XuperDBConf
+	Type       string
+	Self       *XuperDBConf
+	TLS        *TLSConf        // ClientAuth is ignored, as it only applies to servers
+	Identities []*HostIdentity // expected certificate identities of specific hosts
+	Proxy      *ProxyConf      // HTTP_PROXY and HTTPS_PROXY of environment variables are used if it is not configured
+}
+
+// ProxyConf defines the egress HTTP proxy, e.g. URL "http://10.144.94.20:3128".
+// Username and Password are sent to the proxy with basic authentication if Username is not empty.
+// NoProxy is a comma-separated list of hosts, domains and CIDRs connected directly, the same as NO_PROXY,
+// which is used if NoProxy is empty.
+type ProxyConf struct {
+	URL      string
+	Username string
+	Password string
+	NoProxy  string
+}
+
+// HostIdentity maps the Host of a dataOwner or storage node, in the form of 'host:port',
+// to the Identity its certificate is issued to, which must be a DNS name in the certificate.
+// Other nodes presenting certificates signed by the same CA can not impersonate the host.
+type HostIdentity struct {
+	Host     string
+	Identity string
 }
 
 // ExecutorMpcConf defines the features of the mpc process
+// RpcTimeout, TaskLimitTime and MaxTaskLimitTime are set as durations like "3s" or "1h" in the config file.
+// Each task in execution reserves MaxMemoryMB and MaxCPUCores from the node's budget,
+// a task is not started until the budget is enough, zero values mean no limit. The reservations are for admission only,
+// they don't limit the memory and cpu a task uses. Memory is guarded node-wide, the latest added task is stopped
+// if the executor uses more than NodeMemoryMB.
 type ExecutorMpcConf struct {
 	TrainTaskLimit   int
 	PredictTaskLimit int
-	RpcTimeout       int // rpc request timeout between executor nodes
-	TaskLimitTime    int
+	RpcTimeout       time.Duration // rpc request timeout between executor nodes
+	TaskLimitTime    time.Duration // maximum execution time of a task
+	MaxTaskLimitTime time.Duration // upper bound of the execution time requested by a task
+	MaxMemoryMB      int           // memory reserved by a task, in MB
+	MaxCPUCores      int           // cpu cores reserved by a task
+	NodeMemoryMB     int           // memory budget of all tasks in execution, in MB
+	NodeCPUCores     int           // cpu budget of all tasks in execution, the default is the number of cpus
+	// CheckpointInterval is the number of rounds between two checkpoints of a training task, zero disables checkpoints,
+	// an interrupted task resumes from its last checkpoint when it is started again
+	CheckpointInterval int
+	// QueueSize is the maximum number of tasks waiting for free slots when task limits are reached,
+	// the tasks can't be queued are failed. DefaultPriority is the priority of tasks published without priority.
+	QueueSize       int
+	DefaultPriority int
+	// Compression is the compressor of messages sent to other executors, "gzip" or "snappy",
+	// empty or "none" means no compression. Peers not supporting it get messages uncompressed.
+	Compression string
+	// PSIAlgorithm is the PSI algorithm of tasks published without one, "ecdh", "oprf" or "auto",
+	// the default is "ecdh". All parties of a task should use the same algorithm.
+	PSIAlgorithm string
+	// DefaultAccuracy is the accuracy of the fixed-point encoding of linear-vl and logistic-vl tasks published without
+	// one, the number of decimal digits kept in [1,15], the default is 10. All parties of a task should use the same one.
+	DefaultAccuracy int
+	// MaxSampleFileSizeMB is the maximum size of a sample file downloaded by a task, in MB, zero means no limit.
+	// Tasks using larger sample files fail without downloading them.
+	MaxSampleFileSizeMB int
+	// MaxFeatures is the maximum number of features of a sample file used by a task, zero means no limit.
+	// It is checked against the features recorded on the blockchain, and tasks using more fail without downloading the file.
+	MaxFeatures int
+	// PSIWorkers is the number of goroutines hashing and encrypting sample IDs in PSI, zero means GOMAXPROCS.
+	// The intersection is the same whatever the number is.
+	PSIWorkers int
+	// KernelWorkers is the maximum number of numeric kernel items, such as encrypting IDs in PSI and encrypting or
+	// decrypting gradients of features in training, computed at the same time by all tasks, zero means no limit.
+	// Unlike GOMAXPROCS it only caps the kernels, and tasks share it fairly item by item.
+	KernelWorkers int
+	// KeepaliveTime is the idle time after which connections between executors are pinged, the default is "30s",
+	// KeepaliveTimeout is the time to wait for the ack of a ping before closing the connection, the default is "10s".
+	// If PermitWithoutStream is true, connections are pinged even without active RPCs, which keeps idle connections
+	// alive through the NATs and firewalls dropping them.
+	KeepaliveTime       time.Duration
+	KeepaliveTimeout    time.Duration
+	PermitWithoutStream bool
+	// MaxRecvMsgSizeMB and MaxSendMsgSizeMB are the maximum size of gRPC messages received and sent by the server
+	// and connections to other executors, in MB, the default is 1024. A message is buffered in memory as a whole,
+	// so each concurrent message may take memory of its size.
+	MaxRecvMsgSizeMB int
+	MaxSendMsgSizeMB int
+	// PeakWindow is the window of the peak number of tasks in execution exposed in metrics, the default is "1h"
+	PeakWindow time.Duration
+	// BreakerThreshold is the number of consecutive failed calls to a peer executor, each within BreakerWindow
+	// of the previous one, after which new tasks with the peer are rejected for BreakerCooldown, then a task is
+	// let through to probe the peer. Zero disables the circuit breaker. The defaults of BreakerWindow and
+	// BreakerCooldown are "5m" and "1m".
+	BreakerThreshold int
+	BreakerWindow    time.Duration
+	BreakerCooldown  time.Duration
+	// PSICacheEntries is the maximum number of sample alignments, the intersections of PSI, cached across tasks,
+	// zero disables the cache. A training task of two parties skips PSI if both executors have cached the alignment
+	// of the same samples, which expires after PSICacheTTL, the default is "24h".
+	PSICacheEntries int
+	PSICacheTTL     time.Duration
+	// SpillDir is the directory intermediate data of tasks, such as the aligned samples, is spilled to when the memory
+	// in use by the executor exceeds SpillMemoryThresholdMB, zero threshold disables spilling. Spilled data is removed
+	// when the task finishes or fails.
+	SpillDir               string
+	SpillMemoryThresholdMB int
 }
 
 // ExecutorStorageConf defines the storage used by the executor,
 // include model storage and prediction results storage, and evaluation storage and live evaluation storage.
-// the prediction results storage support 'XuperDB' and 'Local' two storage mode.
+// the prediction results storage support 'XuperDB', 'Local' and 'S3' storage mode,
+// if the type is 'S3', models and evaluation results are also stored in S3.
+// EncryptionKey is the hex encoded 32-byte key encrypting locally stored files with AES-GCM, it is read
+// by the key provider from EncryptionKeyPath if it is empty, and files are stored in plaintext if neither is set.
 type ExecutorStorageConf struct {
 	Type                       string
 	LocalModelStoragePath      string
 	LocalEvaluationStoragePath string
 	LiveEvaluationStoragePath  string // live evaluation results storage path
+	LocalCheckpointStoragePath string // training checkpoints storage path, the default is 'checkpoints' under LocalModelStoragePath
 	XuperDB                    *XuperDBConf
 	Local                      *PredictLocalConf
+	S3                         *S3Conf
+	EncryptionKey              string
+	EncryptionKeyPath          string
+	Retention                  *RetentionConf // local files are kept forever if it is not configured
+	FileNames                  *FileNamesConf // files are named after the IDs of their tasks if it is not configured
+	Download                   *DownloadConf  // sample files are not downloaded again on failures if it is not configured
+}
+
+// DownloadConf defines how the download of a sample file from dataOwner or storage nodes is retried if it fails
+// by network errors, apart from the retries of the blockchain. The whole file is downloaded again in each retry,
+// and the task fails with the last network error once retries are exhausted.
+type DownloadConf struct {
+	MaxRetries    int           // the max number of retries, the default is 0, which means no retry
+	RetryInterval time.Duration // the interval before the first retry, doubled after each retry, the default is "1s"
+}
+
+// FileNamesConf defines the templates of the names of the files stored by the executor, with the placeholders
+// {task_id}, {model_id}, {timestamp} and {type}. The files of a kind are named after the IDs of their tasks
+// if its template is empty.
+type FileNamesConf struct {
+	Model      string // models, and their lineages named with the suffix ".lineage"
+	Evaluation string
+	Checkpoint string
+	Prediction string // prediction results, {task_id} of the input of a batch prediction is suffixed with its index
+}
+
+// RetentionConf defines the retention policy of local checkpoints and prediction results. Files of the tasks
+// ended on the chain are removed if they're older than MaxAge, then the oldest ones are removed while the total size
+// of the files exceeds MaxTotalSizeMB. Files of tasks not ended, in execution or waiting in the queue are never removed.
+type RetentionConf struct {
+	MaxAge         time.Duration // zero means files are not removed by age
+	MaxTotalSizeMB int           // zero means files are not removed by size
+	Interval       time.Duration // how often files are checked, the default is "1h"
 }
 
 // XuperDBConf defines the XuperDB's endpoint, used to upload or download files
+// Files larger than ChunkSizeMB are uploaded in chunks, each chunk is retried on failure, 0 means no chunking.
 type XuperDBConf struct {
-	PrivateKey string
-	Host       string
-	KeyPath    string
-	NameSpace  string
-	ExpireTime int64
+	PrivateKey  string
+	Host        string
+	KeyPath     string
+	NameSpace   string
+	ExpireTime  int64
+	ChunkSizeMB int
+}
+
+// S3Conf defines the endpoint of an S3-compatible object store, such as AWS S3 and MinIO
+// If AccessKey is empty, the default credential chain of aws sdk is used.
+type S3Conf struct {
+	Endpoint  string // leave empty to use AWS S3, e.g. "http://127.0.0.1:9000" for MinIO
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // whether to use path-style addressing, which is required by MinIO
 }
 
 // PredictLocalConf defines the local path of prediction results storage
@@ -95,79 +423,268 @@ type PredictLocalConf struct {
 	LocalPredictStoragePath string
 }
 
+// DefaultBlockchainNetwork is the name of the blockchain network configured by [executor.blockchain] if Name is empty
+const DefaultBlockchainNetwork = "default"
+
 // ExecutorBlockchainConf defines the configuration required to invoke blockchain contracts
+// Type selects the backend, 'xchain' or 'fabric', only the matched sub-section is kept.
+// Networks are the other blockchain networks joined by the executor, e.g. the chains of other consortia,
+// each of which is configured in the same way and named uniquely by Name. Tasks are listed from all networks,
+// and the calls of a task are made on the network it is published on. Networks is ignored by the cli.
 type ExecutorBlockchainConf struct {
-	Type   string
-	Xchain *XchainConf
-	Fabric *FabricConf
+	Name     string
+	Type     string
+	Xchain   *XchainConf
+	Fabric   *FabricConf
+	Networks []*ExecutorBlockchainConf
 }
 
+// NetworkName returns the name of the blockchain network, DefaultBlockchainNetwork if Name is empty
+func (c *ExecutorBlockchainConf) NetworkName() string {
+	if c.Name == "" {
+		return DefaultBlockchainNetwork
+	}
+	return c.Name
+}
+
+// XchainConf defines the configuration required to invoke the contract of XuperChain
+// A failed query is retried at most MaxRetries times, while a failed invocation is retried only
+// if the error is transient, e.g. connection refused or timeout.
 type XchainConf struct {
 	Mnemonic        string
 	ContractName    string
 	ContractAccount string
 	ChainAddress    string
 	ChainName       string
+	MaxRetries      int           // the max number of retries, the default is 0, which means no retry
+	RetryInterval   time.Duration // the interval before the first retry, doubled after each retry
+	PoolSize        int           // the number of connections to ChainAddress shared by clients, the default is 4
 }
 
+// FabricConf defines the configuration required to invoke the chaincode of Hyperledger Fabric
 type FabricConf struct {
-	ConfigFile string
+	ConfigFile string // path of the connection profile used by fabric sdk
 	ChannelID  string
 	Chaincode  string
 	UserName   string
-	OrgName    string
+	OrgName    string // organization of the user, can be omitted if MspID is set
+	MspID      string // MSP ID of the organization, used to find the organization in the connection profile
 }
 
 // Log defines the storage path of the logs generated by the executor node at runtime
+// Format is "text" or "json", json makes every record a single JSON object, the default is "text"
+// The log file is rotated by size, zero values of the rotation settings mean the defaults.
 type Log struct {
-	Level string
-	Path  string
+	Level      string
+	Path       string
+	Format     string
+	MaxSizeMB  int  // max size of the log file before it gets rotated, the default is 100
+	MaxBackups int  // max number of rotated files to retain, the default is 7
+	MaxAgeDays int  // max days to retain rotated files, the default is 30
+	Compress   bool // whether to compress rotated files using gzip
+}
+
+// ConfigPath returns the configuration path of the executor set by ConfigPathEnv, or DefaultConfigPath
+func ConfigPath() string {
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return path
+	}
+	return DefaultConfigPath
 }
 
-// InitConfig parses configuration file
+// InitConfig parses configuration file, and watches it if hotReload is enabled.
+// configPath is a file, a directory or a list of files separated by ConfigPathSeparator, the files are merged
+// in order and the settings of later files override the earlier ones, the files of a directory are merged
+// in the order of their names, e.g. a base config and the overlay of an environment.
+// The merged configuration is validated as a whole.
 func InitConfig(configPath string) error {
-	v := viper.New()
-	v.SetConfigFile(configPath)
-	if err := v.ReadInConfig(); err != nil {
+	v, err := loadConfig(configPath)
+	if err != nil {
 		return err
 	}
+	if executorConf.HotReload {
+		watchConfig(v, configPath)
+	}
+	return nil
+}
+
+// LoadConfig parses configuration file without watching it, used by one-shot commands
+func LoadConfig(configPath string) error {
+	_, err := loadConfig(configPath)
+	return err
+}
+
+// configFiles returns the files of configPath in the order they're merged, the files of a directory are the ones
+// with extensions supported by viper, such as '.toml', sorted by name
+func configFiles(configPath string) ([]string, error) {
+	var files []string
+	for _, path := range strings.Split(configPath, ConfigPathSeparator) {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, entry := range entries {
+			ext := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
+			if !entry.IsDir() && contains(viper.SupportedExts, ext) {
+				names = append(names, entry.Name())
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no config file found in directory %s", path)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config file found in %q", configPath)
+	}
+	return files, nil
+}
+
+// readConfig reads and merges the files of configPath in order, the environment variables
+// take precedence over the config files, empty ones are ignored
+func readConfig(configPath string) (*viper.Viper, error) {
+	files, err := configFiles(configPath)
+	if err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for _, file := range files {
+		v.SetConfigFile(file)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
+	return v, nil
+}
+
+// loadConfig parses and validates configuration files, returns the viper instance of the merged configuration
+func loadConfig(configPath string) (*viper.Viper, error) {
+	v, err := readConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
 	logConf = new(Log)
-	err := v.Sub("log").Unmarshal(logConf)
+	err = unmarshal(v, "log", logConf)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	executorConf = new(ExecutorConf)
-	err = v.Sub("executor").Unmarshal(executorConf)
+	err = unmarshal(v, "executor", executorConf)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// get the private key , if the private key does not exist, read it from 'keyPath'
-	if executorConf.PrivateKey == "" {
-		privateKeyBytes, err := file.ReadFile(executorConf.KeyPath, file.PrivateKeyFileName)
-		if err == nil && len(privateKeyBytes) != 0 {
-			executorConf.PrivateKey = strings.TrimSpace(string(privateKeyBytes))
-		} else {
-			return err
+	// check required fields before they are used by the executor
+	if err := validateExecutorConf(executorConf, configPath); err != nil {
+		return nil, err
+	}
+	// observers neither download samples nor store results, so [executor.mode] and [executor.storage] are ignored
+	if executorConf.Role == RoleObserver {
+		executorConf.Mode, executorConf.Storage = &ExecutorModeConf{}, &ExecutorStorageConf{}
+	}
+	executorConf.Blockchain.selectBackend()
+	// the sub viper does not inherit env bindings, so overrides are applied explicitly
+	applyEnvOverrides(v, executorConf)
+	// get the keys not set, from the files under 'keyPath' or Vault
+	if err := loadKeys(executorConf); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// applyEnvOverrides overrides sensitive values by environment variables, the precedence is
+// env var > config file > key file. The overridable keys are:
+//
+//	PADDLEDTX_EXECUTOR_PRIVATEKEY                   executor.privateKey
+//	PADDLEDTX_EXECUTOR_MODE_SELF_PRIVATEKEY         executor.mode.self.privateKey
+//	PADDLEDTX_EXECUTOR_STORAGE_XUPERDB_PRIVATEKEY   executor.storage.xuperdb.privateKey
+//	PADDLEDTX_EXECUTOR_STORAGE_S3_ACCESSKEY         executor.storage.s3.accessKey
+//	PADDLEDTX_EXECUTOR_STORAGE_S3_SECRETKEY         executor.storage.s3.secretKey
+//	PADDLEDTX_EXECUTOR_STORAGE_ENCRYPTIONKEY        executor.storage.encryptionKey
+//	PADDLEDTX_EXECUTOR_BLOCKCHAIN_XCHAIN_MNEMONIC   executor.blockchain.xchain.mnemonic
+//	PADDLEDTX_EXECUTOR_KEYPROVIDER_VAULT_TOKEN      executor.keyProvider.vault.token
+//	PADDLEDTX_EXECUTOR_KEYPROVIDER_VAULT_SECRETID   executor.keyProvider.vault.secretID
+func applyEnvOverrides(v *viper.Viper, conf *ExecutorConf) {
+	overrides := map[string]*string{
+		"executor.privateKey":            &conf.PrivateKey,
+		"executor.storage.encryptionKey": &conf.Storage.EncryptionKey,
+	}
+	if conf.Mode.Self != nil {
+		overrides["executor.mode.self.privateKey"] = &conf.Mode.Self.PrivateKey
+	}
+	if conf.Storage.XuperDB != nil {
+		overrides["executor.storage.xuperdb.privateKey"] = &conf.Storage.XuperDB.PrivateKey
+	}
+	if conf.Storage.S3 != nil {
+		overrides["executor.storage.s3.accessKey"] = &conf.Storage.S3.AccessKey
+		overrides["executor.storage.s3.secretKey"] = &conf.Storage.S3.SecretKey
+	}
+	if conf.Blockchain.Xchain != nil {
+		overrides["executor.blockchain.xchain.mnemonic"] = &conf.Blockchain.Xchain.Mnemonic
+	}
+	if conf.KeyProvider != nil && conf.KeyProvider.Vault != nil {
+		overrides["executor.keyProvider.vault.token"] = &conf.KeyProvider.Vault.Token
+		overrides["executor.keyProvider.vault.secretID"] = &conf.KeyProvider.Vault.SecretID
+	}
+	for key, field := range overrides {
+		// GetString falls back to the config file value when the env var is unset or empty
+		if value := v.GetString(key); value != "" {
+			*field = value
 		}
 	}
-	return nil
+}
+
+// selectBackend drops the sub-sections not selected by Type,
+// so that the credentials of an unused blockchain are not kept in memory
+func (c *ExecutorBlockchainConf) selectBackend() {
+	switch c.Type {
+	case "xchain":
+		c.Fabric = nil
+	case "fabric":
+		c.Xchain = nil
+	}
+	for _, network := range c.Networks {
+		network.selectBackend()
+	}
 }
 
 // InitCliConfig parses client configuration file. if cli's configuration file is not existed, use executor's configuration file.
+// The configuration path is a file, a directory or a list of files like the one of InitConfig.
 func InitCliConfig(configPath string) error {
-	v := viper.New()
-	v.SetConfigFile(configPath)
-	if err := v.ReadInConfig(); err != nil {
+	v, err := readConfig(configPath)
+	if err != nil {
 		return err
 	}
 	innerV := v.Sub("blockchain")
 	if innerV != nil {
 		// If "blockchain" was existed, cli would use the configuration of cli.
 		cliConf = new(ExecutorBlockchainConf)
-		err := innerV.Unmarshal(cliConf)
+		err := unmarshal(v, "blockchain", cliConf)
 		if err != nil {
 			return err
 		}
+		if err := validateBlockchainConf(cliConf, configPath, "blockchain"); err != nil {
+			return err
+		}
+		cliConf.selectBackend()
 		return nil
 	} else {
 		// If "blockchain" wasn't existed, use the configuration of the executor.
@@ -179,13 +696,18 @@ func InitCliConfig(configPath string) error {
 	}
 }
 
-// GetExecutorConf returns all configuration of the executor
+// GetExecutorConf returns all configuration of the executor.
+// If hot reload is enabled, it returns the latest snapshot.
 func GetExecutorConf() *ExecutorConf {
+	confLock.RLock()
+	defer confLock.RUnlock()
 	return executorConf
 }
 
 // GetLogConf returns log configuration of the executor
 func GetLogConf() *Log {
+	confLock.RLock()
+	defer confLock.RUnlock()
 	return logConf
 }
 
@@ -1,8 +1,21 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
 )
 
 // TestInitConfig
@@ -32,3 +45,669 @@ func TestInitCliConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExecutorConf(t *testing.T) {
+	newConf := func() *ExecutorConf {
+		return &ExecutorConf{
+			ListenAddress: ":8184",
+			PublicAddress: "127.0.0.1:8184",
+			Mode:          &ExecutorModeConf{Type: "Proxy"},
+			Storage:       &ExecutorStorageConf{Type: "Local"},
+			Mpc:           &ExecutorMpcConf{MaxMemoryMB: 1024, NodeMemoryMB: 4096},
+			TLS:           &TLSConf{CertFile: "config.go", KeyFile: "config.go"},
+			Blockchain:    &ExecutorBlockchainConf{Type: "xchain", Xchain: &XchainConf{}},
+		}
+	}
+	if err := validateExecutorConf(newConf(), "config.toml"); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	// downloads sample files over mutual TLS
+	withModeTLS := func(c *ExecutorConf) {
+		c.Mode.TLS = &TLSConf{CertFile: "config.go", KeyFile: "config.go", CAFile: "config.go"}
+		c.Mode.Identities = []*HostIdentity{{Host: "127.0.0.1:8121", Identity: "dataowner1"}}
+	}
+	modeTLSConf := newConf()
+	withModeTLS(modeTLSConf)
+	if err := validateExecutorConf(modeTLSConf, "config.toml"); err != nil {
+		t.Errorf("valid config of executor mode tls rejected: %v", err)
+	}
+	// joins another blockchain network
+	networksConf := newConf()
+	networksConf.Blockchain.Networks = []*ExecutorBlockchainConf{{Name: "consortium2", Type: "xchain", Xchain: &XchainConf{}}}
+	if err := validateExecutorConf(networksConf, "config.toml"); err != nil {
+		t.Errorf("valid config of blockchain networks rejected: %v", err)
+	}
+	// profiling endpoints on the loopback interface are closed after an hour by default
+	pprofConf := newConf()
+	pprofConf.Pprof = &PprofConf{Address: "127.0.0.1:6060"}
+	if err := validateExecutorConf(pprofConf, "config.toml"); err != nil || pprofConf.Pprof.Duration != time.Hour {
+		t.Errorf("valid config of pprof rejected: %v, duration %v", err, pprofConf.Pprof.Duration)
+	}
+	// observers don't need the sections of sample download and storage
+	observerConf := newConf()
+	observerConf.Role, observerConf.Mode, observerConf.Storage = RoleObserver, nil, nil
+	if err := validateExecutorConf(observerConf, "config.toml"); err != nil {
+		t.Errorf("valid config of observer rejected: %v", err)
+	}
+
+	cases := map[string]func(c *ExecutorConf){
+		"emptyListenAddress":   func(c *ExecutorConf) { c.ListenAddress = "" },
+		"invalidListenAddress": func(c *ExecutorConf) { c.ListenAddress = "8184" },
+		"invalidListenPort":    func(c *ExecutorConf) { c.ListenAddress = "127.0.0.1:abc" },
+		"emptyPublicAddress":   func(c *ExecutorConf) { c.PublicAddress = "" },
+		"unknownRole":          func(c *ExecutorConf) { c.Role = "auditor" },
+		"missingMode":          func(c *ExecutorConf) { c.Mode = nil },
+		"unknownModeType":      func(c *ExecutorConf) { c.Mode.Type = "proxy" },
+		"missingStorage":       func(c *ExecutorConf) { c.Storage = nil },
+		"unknownStorageType":   func(c *ExecutorConf) { c.Storage.Type = "IPFS" },
+		"missingBlockchain":    func(c *ExecutorConf) { c.Blockchain = nil },
+		"missingSelf":          func(c *ExecutorConf) { c.Mode = &ExecutorModeConf{Type: "Self"} },
+		"invalidSelfHost": func(c *ExecutorConf) {
+			c.Mode = &ExecutorModeConf{Type: "Self", Self: &XuperDBConf{Host: "10.144.94.17:8121"}}
+		},
+		"missingXuperDB": func(c *ExecutorConf) { c.Storage.Type = "XuperDB" },
+		"missingXchain":  func(c *ExecutorConf) { c.Blockchain.Xchain = nil },
+		"missingS3":      func(c *ExecutorConf) { c.Storage.Type = "S3" },
+		"negativePoolSize": func(c *ExecutorConf) {
+			c.Blockchain.Xchain = &XchainConf{PoolSize: -1}
+		},
+		"missingS3Bucket": func(c *ExecutorConf) {
+			c.Storage = &ExecutorStorageConf{Type: "S3", S3: &S3Conf{Region: "us-east-1"}}
+		},
+		"missingS3SecretKey": func(c *ExecutorConf) {
+			c.Storage = &ExecutorStorageConf{Type: "S3", S3: &S3Conf{Bucket: "dai", Region: "us-east-1", AccessKey: "minio"}}
+		},
+		"missingS3AccessKey": func(c *ExecutorConf) {
+			c.Storage = &ExecutorStorageConf{Type: "S3", S3: &S3Conf{Bucket: "dai", Region: "us-east-1", SecretKey: "minio123"}}
+		},
+		"missingFabric": func(c *ExecutorConf) { c.Blockchain.Type = "fabric" },
+		"unnamedNetwork": func(c *ExecutorConf) {
+			c.Blockchain.Networks = []*ExecutorBlockchainConf{{Type: "xchain", Xchain: &XchainConf{}}}
+		},
+		"duplicatedNetwork": func(c *ExecutorConf) {
+			c.Blockchain.Networks = []*ExecutorBlockchainConf{{Name: DefaultBlockchainNetwork, Type: "xchain", Xchain: &XchainConf{}}}
+		},
+		"networkMissingFabric": func(c *ExecutorConf) {
+			c.Blockchain.Networks = []*ExecutorBlockchainConf{{Name: "consortium2", Type: "fabric"}}
+		},
+		"missingFabricOrg": func(c *ExecutorConf) {
+			c.Blockchain = &ExecutorBlockchainConf{Type: "fabric", Fabric: &FabricConf{
+				ConfigFile: "./conf/fabric/config.yaml", ChannelID: "mychannel", Chaincode: "mycc", UserName: "Admin"}}
+		},
+		"negativeMaxMemory": func(c *ExecutorConf) { c.Mpc = &ExecutorMpcConf{MaxMemoryMB: -1} },
+		"taskMemoryOverBudget": func(c *ExecutorConf) {
+			c.Mpc = &ExecutorMpcConf{MaxMemoryMB: 4096, NodeMemoryMB: 2048}
+		},
+		"negativeMaxSampleFileSize": func(c *ExecutorConf) {
+			c.Mpc = &ExecutorMpcConf{MaxSampleFileSizeMB: -1}
+		},
+		"taskCPUOverBudget": func(c *ExecutorConf) { c.Mpc = &ExecutorMpcConf{MaxCPUCores: 4, NodeCPUCores: 2} },
+		"negativeQueueSize": func(c *ExecutorConf) { c.Mpc = &ExecutorMpcConf{QueueSize: -1} },
+		"zstdCompression":   func(c *ExecutorConf) { c.Mpc.Compression = "zstd" },
+		"unknownPSI":        func(c *ExecutorConf) { c.Mpc.PSIAlgorithm = "rsa" },
+		"negativePSIWorkers": func(c *ExecutorConf) {
+			c.Mpc = &ExecutorMpcConf{PSIWorkers: -1}
+		},
+		"negativeKernelWorkers":  func(c *ExecutorConf) { c.Mpc.KernelWorkers = -1 },
+		"negativeMaxRecvMsgSize": func(c *ExecutorConf) { c.Mpc.MaxRecvMsgSizeMB = -1 },
+		"maxSendMsgSizeOver2GB":  func(c *ExecutorConf) { c.Mpc.MaxSendMsgSizeMB = 2048 },
+		"keepaliveTimeBelow10s":  func(c *ExecutorConf) { c.Mpc.KeepaliveTime = time.Second },
+		"negativeKeepaliveTimeout": func(c *ExecutorConf) {
+			c.Mpc.KeepaliveTimeout = -time.Second
+		},
+		"negativePeakWindow":       func(c *ExecutorConf) { c.Mpc.PeakWindow = -time.Minute },
+		"negativeBreakerThreshold": func(c *ExecutorConf) { c.Mpc.BreakerThreshold = -1 },
+		"negativeBreakerCooldown":  func(c *ExecutorConf) { c.Mpc.BreakerCooldown = -time.Minute },
+		"negativePSICacheEntries":  func(c *ExecutorConf) { c.Mpc.PSICacheEntries = -1 },
+		"negativePSICacheTTL":      func(c *ExecutorConf) { c.Mpc.PSICacheTTL = -time.Hour },
+		"spillDirMissing":          func(c *ExecutorConf) { c.Mpc.SpillMemoryThresholdMB = 4096 },
+		"defaultAccuracyTooLarge":  func(c *ExecutorConf) { c.Mpc.DefaultAccuracy = 16 },
+		"emptyRequesterAllowlist":  func(c *ExecutorConf) { c.RequesterAllowlist = &RequesterAllowlistConf{} },
+		"invalidAllowedRequester": func(c *ExecutorConf) {
+			c.RequesterAllowlist = &RequesterAllowlistConf{Requesters: []string{"not-a-public-key"}}
+		},
+		"maxTaskLimitTimeBelowLimit": func(c *ExecutorConf) {
+			c.Mpc = &ExecutorMpcConf{TaskLimitTime: time.Hour, MaxTaskLimitTime: time.Minute}
+		},
+		"tlsMissingKeyFile": func(c *ExecutorConf) { c.TLS = &TLSConf{CertFile: "config.go"} },
+		"tlsCertFileNotExist": func(c *ExecutorConf) {
+			c.TLS = &TLSConf{CertFile: "executor.crt", KeyFile: "config.go"}
+		},
+		"mtlsMissingCAFile": func(c *ExecutorConf) {
+			c.TLS = &TLSConf{CertFile: "config.go", KeyFile: "config.go", ClientAuth: true}
+		},
+		"modeTLSMissingCAFile": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.TLS.CAFile = ""
+		},
+		"identitiesWithoutTLS": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.TLS = nil
+		},
+		"selfHostNotHTTPS": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.Type = "Self"
+			c.Mode.Self = &XuperDBConf{Host: "http://127.0.0.1:8121"}
+		},
+		"invalidIdentityHost": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.Identities[0].Host = "127.0.0.1"
+		},
+		"emptyIdentity": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.Identities[0].Identity = ""
+		},
+		"duplicateIdentityHost": func(c *ExecutorConf) {
+			withModeTLS(c)
+			c.Mode.Identities = append(c.Mode.Identities, &HostIdentity{Host: "127.0.0.1:8121", Identity: "dataowner2"})
+		},
+		"missingXuperDBNamespace": func(c *ExecutorConf) {
+			c.Storage = &ExecutorStorageConf{Type: "XuperDB", XuperDB: &XuperDBConf{Host: "http://127.0.0.1:8121"}}
+		},
+		"emptyRetention": func(c *ExecutorConf) { c.Storage.Retention = &RetentionConf{} },
+		"negativeRetentionSize": func(c *ExecutorConf) {
+			c.Storage.Retention = &RetentionConf{MaxAge: time.Hour, MaxTotalSizeMB: -1}
+		},
+		"negativeDownloadRetries": func(c *ExecutorConf) {
+			c.Storage.Download = &DownloadConf{MaxRetries: -1}
+		},
+		"negativeDownloadRetryInterval": func(c *ExecutorConf) {
+			c.Storage.Download = &DownloadConf{MaxRetries: 3, RetryInterval: -time.Second}
+		},
+		"unknownFileNamePlaceholder": func(c *ExecutorConf) {
+			c.Storage.FileNames = &FileNamesConf{Model: "{task_id}_{date}.json"}
+		},
+		"fileNameWithoutTaskID": func(c *ExecutorConf) {
+			c.Storage.FileNames = &FileNamesConf{Prediction: "{model_id}.csv"}
+		},
+		"unknownKeyProvider": func(c *ExecutorConf) { c.KeyProvider = &KeyProviderConf{Type: "kms"} },
+		"missingVault":       func(c *ExecutorConf) { c.KeyProvider = &KeyProviderConf{Type: "vault"} },
+		"noTracingEndpoint":  func(c *ExecutorConf) { c.Tracing = &TracingConf{SampleRate: 0.5} },
+		"invalidSampleRate": func(c *ExecutorConf) {
+			c.Tracing = &TracingConf{Endpoint: "127.0.0.1:4317", SampleRate: 1.5}
+		},
+		"negativeCallbackRetries": func(c *ExecutorConf) {
+			c.Callback = &CallbackConf{MaxRetries: -1}
+		},
+		"outboxBackoffInverted": func(c *ExecutorConf) {
+			c.Outbox = &OutboxConf{RetryInterval: time.Minute, MaxRetryInterval: time.Second}
+		},
+		"noAuditPath":       func(c *ExecutorConf) { c.Audit = &AuditConf{Anchor: true} },
+		"pprofNotLoopback":  func(c *ExecutorConf) { c.Pprof = &PprofConf{Address: ":6060"} },
+		"pprofOnListenPort": func(c *ExecutorConf) { c.Pprof = &PprofConf{Address: "127.0.0.1:8184"} },
+		"pprofOnHttpPort": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{Switch: "on", HttpPort: "8080"}
+			c.Pprof = &PprofConf{Address: "localhost:8080"}
+		},
+		"negativePprofDuration": func(c *ExecutorConf) {
+			c.Pprof = &PprofConf{Address: "0.0.0.0:6060", AllowRemote: true, Duration: -time.Minute}
+		},
+		"noStatsDHost":      func(c *ExecutorConf) { c.StatsD = &StatsDConf{Port: 8125} },
+		"invalidStatsDPort": func(c *ExecutorConf) { c.StatsD = &StatsDConf{Host: "127.0.0.1", Port: 70000} },
+		"invalidVaultAddress": func(c *ExecutorConf) {
+			c.KeyProvider = &KeyProviderConf{Type: "vault", Vault: &VaultConf{Address: "127.0.0.1:8200"}}
+		},
+		"negativeRequestsPerSecond": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{RateLimit: &RateLimitConf{RequestsPerSecond: -1}}
+		},
+		"invalidRateLimitPath": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{RateLimit: &RateLimitConf{
+				Endpoints: []EndpointRateLimitConf{{Path: "v1/task/list", RequestsPerSecond: 1}}}}
+		},
+		"invalidProxyURL":   func(c *ExecutorConf) { c.Mode.Proxy = &ProxyConf{URL: "10.144.94.20:3128"} },
+		"authWithoutTokens": func(c *ExecutorConf) { c.HttpServer = &HttpServerConf{Auth: &HttpAuthConf{}} },
+		"rateLimitByTokenWithoutAuth": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{RateLimit: &RateLimitConf{RequestsPerSecond: 1, KeyBy: "token"}}
+		},
+		"unknownRateLimitKey": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{RateLimit: &RateLimitConf{RequestsPerSecond: 1, KeyBy: "user"}}
+		},
+		"duplicateRateLimitPath": func(c *ExecutorConf) {
+			c.HttpServer = &HttpServerConf{RateLimit: &RateLimitConf{Endpoints: []EndpointRateLimitConf{
+				{Path: "/v1/task/list", RequestsPerSecond: 1}, {Path: "/v1/task/list", Burst: 2}}}}
+		},
+	}
+	for name, modify := range cases {
+		t.Run(name, func(t *testing.T) {
+			conf := newConf()
+			modify(conf)
+			err := validateExecutorConf(conf, "config.toml")
+			if err == nil {
+				t.Error("invalid config passed validation")
+				return
+			}
+			t.Logf("got expected error: %v", err)
+		})
+	}
+}
+
+func TestXuperDBConfValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		conf  XuperDBConf
+		valid bool
+	}{
+		{"upload", XuperDBConf{Host: "http://127.0.0.1:8121", NameSpace: "mpc_1", ExpireTime: 72}, true},
+		{"downloadOnly", XuperDBConf{Host: "https://xuperdb.example.com"}, true},
+		{"missingScheme", XuperDBConf{Host: "127.0.0.1:8121"}, false},
+		{"unsupportedScheme", XuperDBConf{Host: "ftp://127.0.0.1:8121"}, false},
+		{"trailingSlash", XuperDBConf{Host: "http://127.0.0.1:8121/"}, false},
+		{"invalidNamespace", XuperDBConf{Host: "http://127.0.0.1:8121", NameSpace: "mpc/1", ExpireTime: 72}, false},
+		{"zeroExpireTime", XuperDBConf{Host: "http://127.0.0.1:8121", NameSpace: "mpc"}, false},
+		{"negativeExpireTime", XuperDBConf{Host: "http://127.0.0.1:8121", NameSpace: "mpc", ExpireTime: -1}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.conf.Validate()
+			if (err == nil) != c.valid {
+				t.Errorf("expected valid: %v, got error: %v", c.valid, err)
+			}
+		})
+	}
+}
+
+func TestInitConfigEnvOverrides(t *testing.T) {
+	path := "./../conf/config.toml"
+	privateKey := "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b"
+
+	os.Setenv("PADDLEDTX_EXECUTOR_PRIVATEKEY", privateKey)
+	os.Setenv("PADDLEDTX_EXECUTOR_BLOCKCHAIN_XCHAIN_MNEMONIC", "")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_PRIVATEKEY")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_BLOCKCHAIN_XCHAIN_MNEMONIC")
+
+	// the private key comes from env, so the key file is not required
+	if err := InitConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	conf := GetExecutorConf()
+	if conf.PrivateKey != privateKey {
+		t.Errorf("private key not overridden by env, got: %s", conf.PrivateKey)
+	}
+	// empty env vars must not clobber values from the config file
+	if conf.Blockchain.Xchain.Mnemonic == "" {
+		t.Error("mnemonic clobbered by empty env var")
+	}
+}
+
+func TestInitConfigVaultKeys(t *testing.T) {
+	privateKey := "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b"
+	encryptionKey := strings.Repeat("ab", 32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/executor1":
+			w.Write([]byte(`{"data":{"data":{"privateKey":"` + privateKey + `"},"metadata":{"version":1}}}`))
+		case "/v1/secret/data/storage":
+			w.Write([]byte(`{"data":{"data":{"encryptionKey":"` + encryptionKey + `"},"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	content, err := ioutil.ReadFile("./../conf/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content = bytes.Replace(content, []byte(`keyPath = "./keys"`), []byte(`keyPath = "secret/data/executor1"`), 1)
+	content = bytes.Replace(content, []byte(`localModelStoragePath = "./models"`),
+		[]byte("localModelStoragePath = \"./models\"\nencryptionKeyPath = \"secret/data/storage\""), 1)
+	content = append(content, []byte("\n[executor.keyProvider]\ntype = \"vault\"\n[executor.keyProvider.vault]\naddress = \""+
+		server.URL+"\"\ntoken = \"s.token\"\n")...)
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	conf := GetExecutorConf()
+	if conf.PrivateKey != privateKey {
+		t.Errorf("private key not read from vault, got: %s", conf.PrivateKey)
+	}
+	if conf.Storage.EncryptionKey != encryptionKey {
+		t.Errorf("encryption key not read from vault, got: %s", conf.Storage.EncryptionKey)
+	}
+
+	// the executor refuses to start if the key can not be read from vault
+	os.Setenv("PADDLEDTX_EXECUTOR_KEYPROVIDER_VAULT_TOKEN", "s.revoked")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_KEYPROVIDER_VAULT_TOKEN")
+	err = LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected error of permission denied, got: %v", err)
+	}
+}
+
+func TestInitConfigPreviousKey(t *testing.T) {
+	privateKey := "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b"
+	previousKey := "0e2a6c0ab4be9cb5ba3a4e44a1a4e03f1dc47e1cbbf2e7e10d4c4a1f1e5d2c3b"
+	keyPath := t.TempDir()
+	if err := file.WriteFile(keyPath, file.PrivateKeyFileName, []byte(privateKey)); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile("./../conf/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content = bytes.Replace(content, []byte(`keyPath = "./keys"`), []byte(`keyPath = "`+keyPath+`"`), 1)
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the key has never been rotated
+	if err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if conf := GetExecutorConf(); conf.PrivateKey != privateKey || conf.PreviousPrivateKey != "" {
+		t.Errorf("expected no previous key, got %q, %q", conf.PrivateKey, conf.PreviousPrivateKey)
+	}
+
+	// the key pair before the rotation is kept in the previous directory
+	if err := file.WriteFile(filepath.Join(keyPath, file.PreviousKeyDirName), file.PrivateKeyFileName, []byte(previousKey+"\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if conf := GetExecutorConf(); conf.PrivateKey != privateKey || conf.PreviousPrivateKey != previousKey {
+		t.Errorf("expected previous key read, got %q, %q", conf.PrivateKey, conf.PreviousPrivateKey)
+	}
+}
+
+func TestInitConfigHotReload(t *testing.T) {
+	content, err := ioutil.ReadFile("./../conf/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content = bytes.Replace(content, []byte("hotReload = false"), []byte("hotReload = true"), 1)
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("PADDLEDTX_EXECUTOR_PRIVATEKEY", "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_PRIVATEKEY")
+	if err := InitConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	oldConf := GetExecutorConf()
+
+	reloaded := make(chan *ExecutorConf, 1)
+	OnReload(func(conf *ExecutorConf, logConf *Log) {
+		select {
+		case reloaded <- conf:
+		default:
+		}
+	})
+
+	// the listen address is not reloadable, the change must be ignored
+	content = bytes.Replace(content, []byte("trainTaskLimit = 100"), []byte("trainTaskLimit = 7"), 1)
+	content = bytes.Replace(content, []byte(`listenAddress = ":8184"`), []byte(`listenAddress = ":8185"`), 1)
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case conf := <-reloaded:
+		if conf != GetExecutorConf() {
+			t.Error("reload handler got a different snapshot")
+		}
+		if conf.Mpc.TrainTaskLimit != 7 {
+			t.Errorf("trainTaskLimit not reloaded, got: %d", conf.Mpc.TrainTaskLimit)
+		}
+		if conf.ListenAddress != oldConf.ListenAddress {
+			t.Errorf("listenAddress should not be reloaded, got: %s", conf.ListenAddress)
+		}
+		if oldConf.Mpc.TrainTaskLimit != 100 {
+			t.Error("old snapshot modified by reload")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file not reloaded")
+	}
+}
+
+func TestInitConfigMerge(t *testing.T) {
+	base, err := ioutil.ReadFile("./../conf/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	basePath := write("00-base.toml", string(base))
+	overlayPath := write("10-overlay.toml", "[executor]\nlistenAddress = \":8185\"\n[executor.mpc]\ntrainTaskLimit = 7\n")
+	os.Setenv("PADDLEDTX_EXECUTOR_PRIVATEKEY", "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_PRIVATEKEY")
+
+	// later files override the earlier ones, the settings not overridden are kept
+	for _, path := range []string{basePath + "," + overlayPath, dir} {
+		if err := LoadConfig(path); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		conf := GetExecutorConf()
+		if conf.ListenAddress != ":8185" || conf.Mpc.TrainTaskLimit != 7 {
+			t.Errorf("%s: overlay not merged, listenAddress %s, trainTaskLimit %d", path, conf.ListenAddress, conf.Mpc.TrainTaskLimit)
+		}
+		if conf.Mpc.PredictTaskLimit != 100 || conf.PublicAddress != "10.144.94.17:8184" {
+			t.Errorf("%s: settings of the base config lost: %+v", path, conf.Mpc)
+		}
+	}
+	if err := LoadConfig(overlayPath + "," + basePath); err != nil {
+		t.Fatal(err)
+	}
+	if GetExecutorConf().ListenAddress != ":8184" {
+		t.Errorf("expected the base config merged last to win, got %s", GetExecutorConf().ListenAddress)
+	}
+
+	// the merged config is validated as a whole
+	if err := LoadConfig(overlayPath); err == nil {
+		t.Error("expected the incomplete overlay alone rejected")
+	}
+	invalid := write("20-invalid.toml", "[executor.mpc]\nqueueSize = -1\n")
+	if err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "executor.mpc.queueSize") {
+		t.Errorf("expected the invalid overlay rejected, got %v", err)
+	}
+	os.Remove(invalid)
+	if err := LoadConfig(basePath + "," + filepath.Join(dir, "missing.toml")); err == nil {
+		t.Error("expected missing config file rejected")
+	}
+	if err := LoadConfig(t.TempDir()); err == nil {
+		t.Error("expected directory without config files rejected")
+	}
+}
+
+func TestReloadedMpcConf(t *testing.T) {
+	current := &ExecutorMpcConf{
+		TrainTaskLimit:   100,
+		PredictTaskLimit: 100,
+		RpcTimeout:       3 * time.Second,
+		TaskLimitTime:    time.Hour,
+	}
+	cases := map[string]struct {
+		reloaded *ExecutorMpcConf
+		expected ExecutorMpcConf
+	}{
+		"valid": {
+			reloaded: &ExecutorMpcConf{TrainTaskLimit: 7, PredictTaskLimit: 8, RpcTimeout: time.Second, TaskLimitTime: time.Minute},
+			expected: ExecutorMpcConf{TrainTaskLimit: 7, PredictTaskLimit: 8, RpcTimeout: time.Second, TaskLimitTime: time.Minute},
+		},
+		"missing": {
+			reloaded: nil,
+			expected: *current,
+		},
+		"invalid": {
+			reloaded: &ExecutorMpcConf{TrainTaskLimit: 0, PredictTaskLimit: -1, RpcTimeout: -time.Second, TaskLimitTime: -time.Minute},
+			expected: *current,
+		},
+		"defaultTimeouts": {
+			reloaded: &ExecutorMpcConf{TrainTaskLimit: 7, PredictTaskLimit: 8},
+			expected: ExecutorMpcConf{TrainTaskLimit: 7, PredictTaskLimit: 8},
+		},
+	}
+	for name, c := range cases {
+		conf := reloadedMpcConf(current, c.reloaded, "config.toml")
+		if *conf != c.expected {
+			t.Errorf("%s: got %+v, expected %+v", name, *conf, c.expected)
+		}
+	}
+	if current.TrainTaskLimit != 100 {
+		t.Error("current mpc config modified")
+	}
+}
+
+func TestUnmarshalDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		`"30s"`: 30 * time.Second,
+		`"2h"`:  2 * time.Hour,
+		`3`:     3 * time.Second, // deprecated bare integer means seconds
+		`"3"`:   3 * time.Second,
+		`3.0`:   3 * time.Second,
+	}
+	for value, expected := range cases {
+		v := viper.New()
+		v.SetConfigType("toml")
+		if err := v.ReadConfig(strings.NewReader("[executor.mpc]\nrpcTimeout = " + value)); err != nil {
+			t.Fatal(err)
+		}
+		conf := new(ExecutorConf)
+		if err := unmarshal(v, "executor", conf); err != nil {
+			t.Errorf("failed to unmarshal %s: %v", value, err)
+			continue
+		}
+		if conf.Mpc.RpcTimeout != expected {
+			t.Errorf("rpcTimeout %s parsed as %v, expected %v", value, conf.Mpc.RpcTimeout, expected)
+		}
+	}
+
+	for _, value := range []string{`"3 seconds"`, `1.5`, `-3`, `"-3s"`, `"-3"`} {
+		v := viper.New()
+		v.SetConfigType("toml")
+		if err := v.ReadConfig(strings.NewReader("[executor.mpc]\nrpcTimeout = " + value)); err != nil {
+			t.Fatal(err)
+		}
+		if err := unmarshal(v, "executor", new(ExecutorConf)); err == nil {
+			t.Errorf("invalid duration %s passed", value)
+		}
+	}
+}
+
+func TestReloadedHttpServerConf(t *testing.T) {
+	current := &HttpServerConf{HttpPort: ":8013", Auth: &HttpAuthConf{Tokens: []string{"token1"}}}
+	conf := reloadedHttpServerConf(current, &HttpServerConf{HttpPort: ":8014",
+		Auth: &HttpAuthConf{Tokens: []string{"token2"}}}, "config.toml")
+	if conf.HttpPort != ":8013" || !reflect.DeepEqual(conf.Auth.Tokens, []string{"token2"}) {
+		t.Errorf("expected only tokens reloaded, got %+v, auth %+v", *conf, *conf.Auth)
+	}
+	for name, reloaded := range map[string]*HttpServerConf{
+		"missing":       nil,
+		"missingAuth":   {HttpPort: ":8013"},
+		"missingTokens": {Auth: &HttpAuthConf{}},
+	} {
+		conf := reloadedHttpServerConf(current, reloaded, "config.toml")
+		if !reflect.DeepEqual(conf, current) {
+			t.Errorf("%s: expected current config kept, got %+v", name, *conf)
+		}
+	}
+	// auth can not be enabled without restarting
+	noAuth := &HttpServerConf{HttpPort: ":8013"}
+	if conf := reloadedHttpServerConf(noAuth, current, "config.toml"); conf.Auth != nil {
+		t.Error("expected auth not enabled by reloading")
+	}
+}
+
+func TestReloadedRequesterAllowlistConf(t *testing.T) {
+	pk1, pk2 := strings.Repeat("01", 64), strings.Repeat("02", 64)
+	current := &RequesterAllowlistConf{Requesters: []string{pk1}}
+	conf := reloadedRequesterAllowlistConf(current, &RequesterAllowlistConf{Requesters: []string{pk2}}, "config.toml")
+	if !reflect.DeepEqual(conf.Requesters, []string{pk2}) {
+		t.Errorf("expected requesters reloaded, got %+v", *conf)
+	}
+	for name, reloaded := range map[string]*RequesterAllowlistConf{
+		"missing":        nil,
+		"empty":          {},
+		"invalidPubkeys": {Requesters: []string{"01"}},
+	} {
+		if conf := reloadedRequesterAllowlistConf(current, reloaded, "config.toml"); !reflect.DeepEqual(conf, current) {
+			t.Errorf("%s: expected current allowlist kept, got %+v", name, conf)
+		}
+	}
+	// the allowlist can not be enabled without restarting
+	if conf := reloadedRequesterAllowlistConf(nil, current, "config.toml"); conf != nil {
+		t.Error("expected allowlist not enabled by reloading")
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	renamedFields["executor.mpc.taskLimit"] = "executor.mpc.trainTaskLimit"
+	defer delete(renamedFields, "executor.mpc.taskLimit")
+
+	old := `
+[executor]
+name = "executor1"
+removedField = "x"
+[executor.mpc]
+    taskLimit = 10
+    rpcTimeout = 3
+    taskLimitTime = 3600
+[executor.mode]
+    type = "Self"
+    [executor.mode.Self]
+        host = "http://127.0.0.1:8121"
+[log]
+level = "debug"
+`
+	migrated, warnings, err := MigrateConfig([]byte(old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) == 0 {
+		t.Error("expected warnings about the changed fields")
+	}
+	v := viper.New()
+	v.SetConfigType("toml")
+	if err := v.ReadConfig(bytes.NewReader(migrated)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"executor.mpc.trainTaskLimit": int64(10),
+		"executor.mpc.rpcTimeout":     "3s",
+		"executor.mpc.taskLimitTime":  "1h",
+		"executor.mpc.queueSize":      int64(100),
+		"executor.shutdownTimeout":    "1m",
+		"executor.mode.type":          "Self",
+		"executor.mode.self.host":     "http://127.0.0.1:8121",
+		"executor.httpserver.switch":  nil, // the section doesn't exist
+		"executor.mpc.taskLimit":      nil,
+		"executor.removedField":       nil,
+		"log.format":                  "text",
+		"log.maxSizeMB":               int64(100),
+	}
+	for key, value := range expected {
+		if got := v.Get(key); got != value {
+			t.Errorf("%s: got %v, expected %v", key, got, value)
+		}
+	}
+
+	// migrating a migrated file changes nothing
+	again, warnings, err := MigrateConfig(migrated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, migrated) || len(warnings) != 0 {
+		t.Errorf("migration is not idempotent, warnings: %v", warnings)
+	}
+
+	if _, _, err := MigrateConfig([]byte("[executor.mpc.rpcTimeout]\n")); err == nil {
+		t.Error("expected error of a table set to a duration field")
+	}
+}
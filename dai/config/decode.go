@@ -0,0 +1,83 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// unmarshal decodes the sub-section key of v into out, durations like "30s" and "2h" are supported
+func unmarshal(v *viper.Viper, key string, out interface{}) error {
+	sub := v.Sub(key)
+	if sub == nil {
+		return fmt.Errorf("[%s] not found in config", key)
+	}
+	return sub.Unmarshal(out, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		mapstructure.StringToSliceHookFunc(","),
+	)))
+}
+
+// durationHook parses time.Duration fields as Go durations.
+// For backward compatibility, a bare integer is treated as seconds, and a deprecation warning is logged.
+// Negative durations and numbers with a fractional part are rejected.
+func durationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	var seconds int64
+	switch d := data.(type) {
+	case string:
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			duration, err := time.ParseDuration(d)
+			if err != nil {
+				return nil, err
+			}
+			if duration < 0 {
+				return nil, fmt.Errorf("negative duration %q", d)
+			}
+			return duration, nil
+		}
+		seconds = n
+	case int:
+		seconds = int64(d)
+	case int64:
+		seconds = d
+	case float64:
+		if d != math.Trunc(d) {
+			return nil, fmt.Errorf("invalid duration %v, set it with a unit like \"1500ms\"", d)
+		}
+		seconds = int64(d)
+	case time.Duration:
+		return d, nil
+	default:
+		return nil, fmt.Errorf("invalid duration %v of type %T", data, data)
+	}
+	if seconds < 0 {
+		return nil, fmt.Errorf("negative duration %d", seconds)
+	}
+	duration := time.Duration(seconds) * time.Second
+	logrus.Warnf("duration %d without unit is deprecated and treated as seconds, please set it as \"%v\"",
+		seconds, duration)
+	return duration, nil
+}
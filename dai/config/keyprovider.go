@@ -0,0 +1,167 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/vault"
+)
+
+const (
+	// KeyProviderFile reads private keys from the key files under KeyPath
+	KeyProviderFile = "file"
+	// KeyProviderVault reads private keys from the secrets located by KeyPath in HashiCorp Vault
+	KeyProviderVault = "vault"
+
+	// fields of Vault secrets holding the private key and the encryption key of local files
+	vaultKeyField           = "privateKey"
+	vaultEncryptionKeyField = "encryptionKey"
+)
+
+// KeyProvider reads the keys not set in the config file
+type KeyProvider interface {
+	// PrivateKey returns the private key located by keyPath
+	PrivateKey(keyPath string) (string, error)
+
+	// EncryptionKey returns the key encrypting locally stored files located by keyPath
+	EncryptionKey(keyPath string) (string, error)
+}
+
+// fileKeyProvider reads the key files under keyPath
+type fileKeyProvider struct{}
+
+func (fileKeyProvider) PrivateKey(keyPath string) (string, error) {
+	return readKeyFile(keyPath, file.PrivateKeyFileName)
+}
+
+func (fileKeyProvider) EncryptionKey(keyPath string) (string, error) {
+	return readKeyFile(keyPath, file.EncryptionKeyFileName)
+}
+
+// readKeyFile reads the key file named fileName under keyPath
+func readKeyFile(keyPath, fileName string) (string, error) {
+	keyBytes, err := file.ReadFile(keyPath, fileName)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(keyBytes))
+	if key == "" {
+		return "", errorx.New(errorx.ErrCodeConfig, "key file %s under %s is empty", fileName, keyPath)
+	}
+	return key, nil
+}
+
+// loadPreviousKey reads the private key before the latest key rotation from the previous directory
+// under keyPath, an empty key is returned if the key has never been rotated
+func loadPreviousKey(keyPath string) (string, error) {
+	previousPath := filepath.Join(keyPath, file.PreviousKeyDirName)
+	existed, err := file.IsFileExisted(previousPath, file.PrivateKeyFileName)
+	if err != nil || !existed {
+		return "", err
+	}
+	privateKey, err := readKeyFile(previousPath, file.PrivateKeyFileName)
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to get the private key before the key rotation from %s", previousPath)
+	}
+	return privateKey, nil
+}
+
+// vaultKeyProvider reads the keys from the secret at keyPath in Vault,
+// the keys are kept in memory only
+type vaultKeyProvider struct {
+	client *vault.Client
+}
+
+func (p *vaultKeyProvider) PrivateKey(keyPath string) (string, error) {
+	return p.client.ReadField(keyPath, vaultKeyField)
+}
+
+func (p *vaultKeyProvider) EncryptionKey(keyPath string) (string, error) {
+	return p.client.ReadField(keyPath, vaultEncryptionKeyField)
+}
+
+// newKeyProvider returns the KeyProvider defined by conf, key files are read if conf is nil
+func newKeyProvider(conf *KeyProviderConf) (KeyProvider, error) {
+	if conf == nil || conf.Type == KeyProviderFile {
+		return fileKeyProvider{}, nil
+	}
+	client, err := vault.NewClient(vault.Options{
+		Address:  conf.Vault.Address,
+		Token:    conf.Vault.Token,
+		RoleID:   conf.Vault.RoleID,
+		SecretID: conf.Vault.SecretID,
+		CAFile:   conf.Vault.CAFile,
+		Timeout:  conf.Vault.Timeout,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to connect vault %s", conf.Vault.Address)
+	}
+	return &vaultKeyProvider{client: client}, nil
+}
+
+// keySetting is a private key setting in the config file, section is the section of the setting
+type keySetting struct {
+	section string
+	key     *string
+	keyPath string
+}
+
+// loadKeys gets the keys not set in the config file or environment variables from the key provider.
+// The keys of the dataOwner in 'Self' mode and XuperDB storage are read from key files when they're used,
+// while they're fetched here if they're in Vault, so that the executor refuses to start if any key is unavailable.
+func loadKeys(conf *ExecutorConf) error {
+	provider, err := newKeyProvider(conf.KeyProvider)
+	if err != nil {
+		return err
+	}
+	settings := []keySetting{{"executor", &conf.PrivateKey, conf.KeyPath}}
+	if _, ok := provider.(*vaultKeyProvider); ok {
+		if conf.Mode.Type == "Self" {
+			settings = append(settings, keySetting{"executor.mode.self", &conf.Mode.Self.PrivateKey, conf.Mode.Self.KeyPath})
+		}
+		if conf.Storage.Type == "XuperDB" {
+			settings = append(settings, keySetting{"executor.storage.xuperdb", &conf.Storage.XuperDB.PrivateKey, conf.Storage.XuperDB.KeyPath})
+		}
+	}
+	for _, s := range settings {
+		if *s.key != "" {
+			continue
+		}
+		privateKey, err := provider.PrivateKey(s.keyPath)
+		if err != nil {
+			return errorx.Wrap(err, "failed to get the private key of [%s] from %s", s.section, s.keyPath)
+		}
+		*s.key = privateKey
+	}
+	// the key pair before the latest rotation is kept under KeyPath by 'executor-cli rotatekey', see loadPreviousKey
+	if _, ok := provider.(fileKeyProvider); ok && conf.PreviousPrivateKey == "" {
+		if conf.PreviousPrivateKey, err = loadPreviousKey(conf.KeyPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.Storage.EncryptionKey == "" && conf.Storage.EncryptionKeyPath != "" {
+		encryptionKey, err := provider.EncryptionKey(conf.Storage.EncryptionKeyPath)
+		if err != nil {
+			return errorx.Wrap(err, "failed to get the encryption key of [executor.storage] from %s", conf.Storage.EncryptionKeyPath)
+		}
+		conf.Storage.EncryptionKey = encryptionKey
+	}
+	return nil
+}
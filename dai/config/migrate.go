@@ -0,0 +1,300 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// migratedHeader is written at the top of the migrated file, as comments of the old file are not kept
+const migratedHeader = "# Migrated by 'executor-cli migrateconf', see conf/config.toml for the descriptions of the fields.\n"
+
+// sections are the top-level tables of the config file and the structs they are decoded into,
+// 'blockchain' is used by the configuration file of the cli
+var sections = map[string]reflect.Type{
+	"executor":   reflect.TypeOf(ExecutorConf{}),
+	"log":        reflect.TypeOf(Log{}),
+	"blockchain": reflect.TypeOf(ExecutorBlockchainConf{}),
+}
+
+// fieldDefault is the documented default of a field, the field is filled only if the table holding it exists,
+// so optional sections are not enabled by the migration
+type fieldDefault struct {
+	key   string
+	value interface{}
+}
+
+// migrationDefaults are the defaults filled into the config file, in the order they're applied
+var migrationDefaults = []fieldDefault{
+	{"executor.role", "executor"},
+	{"executor.hotReload", false},
+	{"executor.shutdownTimeout", "1m"},
+	{"executor.grpcReflection", false},
+	{"executor.httpserver.switch", "on"},
+	{"executor.httpserver.allowCros", false},
+	{"executor.httpserver.metricsSwitch", "off"},
+	{"executor.keyProvider.type", "file"},
+	{"executor.callback.maxRetries", int64(0)},
+	{"executor.callback.retryInterval", "1s"},
+	{"executor.callback.timeout", "10s"},
+	{"executor.audit.anchor", false},
+	{"executor.outbox.size", int64(1000)},
+	{"executor.outbox.retryInterval", "1s"},
+	{"executor.outbox.maxRetryInterval", "1m"},
+	{"executor.mode.type", "Proxy"},
+	{"executor.storage.retention.interval", "1h"},
+	{"executor.storage.download.maxRetries", int64(0)},
+	{"executor.storage.download.retryInterval", "1s"},
+	{"executor.mpc.rpcTimeout", "3s"},
+	{"executor.mpc.taskLimitTime", "2h"},
+	{"executor.mpc.maxTaskLimitTime", "24h"},
+	{"executor.mpc.checkpointInterval", int64(0)},
+	{"executor.mpc.queueSize", int64(100)},
+	{"executor.mpc.defaultPriority", int64(0)},
+	{"executor.mpc.compression", "none"},
+	{"executor.mpc.psiAlgorithm", "ecdh"},
+	{"executor.mpc.defaultAccuracy", int64(10)},
+	{"executor.mpc.maxSampleFileSizeMB", int64(0)},
+	{"executor.mpc.maxFeatures", int64(0)},
+	{"executor.mpc.spillMemoryThresholdMB", int64(0)},
+	{"executor.mpc.psiWorkers", int64(0)},
+	{"executor.mpc.kernelWorkers", int64(0)},
+	{"executor.mpc.keepaliveTime", "30s"},
+	{"executor.mpc.keepaliveTimeout", "10s"},
+	{"executor.mpc.permitWithoutStream", false},
+	{"executor.mpc.maxRecvMsgSizeMB", int64(1024)},
+	{"executor.mpc.maxSendMsgSizeMB", int64(1024)},
+	{"executor.mpc.peakWindow", "1h"},
+	{"executor.mpc.breakerThreshold", int64(0)},
+	{"executor.mpc.breakerWindow", "5m"},
+	{"executor.mpc.breakerCooldown", "1m"},
+	{"executor.mpc.psiCacheEntries", int64(0)},
+	{"executor.mpc.psiCacheTTL", "24h"},
+	{"executor.blockchain.xchain.maxRetries", int64(0)},
+	{"executor.blockchain.xchain.retryInterval", "1s"},
+	{"executor.blockchain.xchain.poolSize", int64(4)},
+	{"log.format", "text"},
+	{"log.maxSizeMB", int64(100)},
+	{"log.maxBackups", int64(7)},
+	{"log.maxAgeDays", int64(30)},
+	{"log.compress", false},
+}
+
+// renamedFields maps the old keys of renamed fields to the new ones, add an entry when a field is renamed
+var renamedFields = map[string]string{}
+
+// MigrateConfig upgrades the executor's configuration file content in to the current version:
+// renamed fields are moved to their new keys, removed fields are dropped, bare integer durations
+// are rewritten with units, and missing fields are filled with the documented defaults.
+// It returns the upgraded content and the warnings about the changed fields. Migrating a migrated file
+// changes nothing, but comments and the order of the fields are not kept.
+func MigrateConfig(in []byte) ([]byte, []string, error) {
+	tree, err := toml.LoadBytes(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf := tree.ToMap()
+
+	var warnings []string
+	warnf := func(format string, a ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, a...))
+	}
+	for _, old := range sortedKeys(renamedFields) {
+		value, ok := lookupKey(conf, old)
+		if !ok {
+			continue
+		}
+		deleteKey(conf, old)
+		if _, ok := lookupKey(conf, renamedFields[old]); ok {
+			warnf("'%s' is renamed to '%s' which is already set, its value %v is dropped", old, renamedFields[old], value)
+			continue
+		}
+		setKey(conf, renamedFields[old], value)
+		warnf("'%s' is renamed to '%s'", old, renamedFields[old])
+	}
+	for _, name := range sortedKeys(conf) {
+		t, ok := sections[strings.ToLower(name)]
+		if !ok {
+			delete(conf, name)
+			warnf("'%s' is removed, it is dropped", name)
+			continue
+		}
+		table, ok := conf[name].(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("'%s' should be a table", name)
+		}
+		if err := migrateTable(table, t, name, warnf); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, d := range migrationDefaults {
+		idx := strings.LastIndex(d.key, ".")
+		parent, ok := lookupKey(conf, d.key[:idx])
+		if _, isTable := parent.(map[string]interface{}); !ok || !isTable {
+			continue
+		}
+		if _, ok := lookupKey(conf, d.key); !ok {
+			setKey(conf, d.key, d.value)
+			warnf("'%s' is not set, it is filled with the default %v", d.key, d.value)
+		}
+	}
+
+	out, err := toml.TreeFromMap(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := out.ToTomlString()
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(migratedHeader + content), warnings, nil
+}
+
+// migrateTable drops the fields of table unknown to struct t, and rewrites bare integer durations with units
+func migrateTable(table map[string]interface{}, t reflect.Type, path string, warnf func(string, ...interface{})) error {
+	for _, name := range sortedKeys(table) {
+		key := path + "." + name
+		field, ok := fieldByName(t, name)
+		if !ok {
+			warnf("'%s' is removed, its value %v is dropped", key, table[name])
+			delete(table, name)
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch value := table[name].(type) {
+		case map[string]interface{}:
+			if ft.Kind() != reflect.Struct {
+				return fmt.Errorf("'%s' should not be a table", key)
+			}
+			if err := migrateTable(value, ft, key, warnf); err != nil {
+				return err
+			}
+		case []interface{}:
+			if ft.Kind() != reflect.Slice {
+				return fmt.Errorf("'%s' should not be an array", key)
+			}
+			et := ft.Elem()
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			for i, v := range value {
+				if item, ok := v.(map[string]interface{}); ok && et.Kind() == reflect.Struct {
+					if err := migrateTable(item, et, fmt.Sprintf("%s[%d]", key, i), warnf); err != nil {
+						return err
+					}
+				}
+			}
+		case int64:
+			if ft == reflect.TypeOf(time.Duration(0)) && value >= 0 {
+				table[name] = formatSeconds(value)
+				warnf("'%s' is a duration without unit, it is rewritten as \"%s\"", key, table[name])
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByName finds the field of struct t by name case-insensitively, the same as decoding the config file
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	return t.FieldByNameFunc(func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+}
+
+// formatSeconds formats seconds as a duration with the largest unit dividing it, e.g. 3600 as "1h"
+func formatSeconds(seconds int64) string {
+	switch {
+	case seconds != 0 && seconds%3600 == 0:
+		return fmt.Sprintf("%dh", seconds/3600)
+	case seconds != 0 && seconds%60 == 0:
+		return fmt.Sprintf("%dm", seconds/60)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// lookupKey gets the value of the dotted key from conf, the keys are matched case-insensitively
+func lookupKey(conf map[string]interface{}, key string) (interface{}, bool) {
+	var value interface{} = conf
+	for _, name := range strings.Split(key, ".") {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = table[matchKey(table, name)]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setKey sets the value of the dotted key, the missing tables are created
+func setKey(conf map[string]interface{}, key string, value interface{}) {
+	names := strings.Split(key, ".")
+	table := conf
+	for _, name := range names[:len(names)-1] {
+		name = matchKey(table, name)
+		sub, ok := table[name].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			table[name] = sub
+		}
+		table = sub
+	}
+	table[matchKey(table, names[len(names)-1])] = value
+}
+
+// deleteKey deletes the dotted key from conf
+func deleteKey(conf map[string]interface{}, key string) {
+	idx := strings.LastIndex(key, ".")
+	parent, ok := lookupKey(conf, key[:idx])
+	if !ok {
+		return
+	}
+	if table, ok := parent.(map[string]interface{}); ok {
+		delete(table, matchKey(table, key[idx+1:]))
+	}
+}
+
+// matchKey returns the key of table equal to name case-insensitively, or name if there isn't one
+func matchKey(table map[string]interface{}, name string) string {
+	if _, ok := table[name]; ok {
+		return name
+	}
+	for k := range table {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
+
+// sortedKeys returns the keys of m in order, so that the warnings are stable
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
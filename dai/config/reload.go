@@ -0,0 +1,232 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ReloadHandler is called after the reloadable settings were swapped,
+// conf and logConf are the new snapshots and must not be modified
+type ReloadHandler func(conf *ExecutorConf, logConf *Log)
+
+// reloadableKeys lists the settings that can be changed without restarting the executor,
+// the keys are in lower case as returned by viper.AllKeys
+var reloadableKeys = map[string]bool{
+	"executor.mpc.traintasklimit":            true,
+	"executor.mpc.predicttasklimit":          true,
+	"executor.mpc.rpctimeout":                true,
+	"executor.mpc.tasklimittime":             true,
+	"executor.httpserver.auth.tokens":        true,
+	"executor.httpserver.auth.tokenfile":     true,
+	"executor.requesterallowlist.requesters": true,
+	"executor.requesterallowlist.file":       true,
+	"log.level":                              true,
+}
+
+var (
+	confLock       sync.RWMutex
+	reloadHandlers []ReloadHandler
+	// startupSettings stores the non-reloadable settings read at startup,
+	// used to warn about changes that need a restart
+	startupSettings map[string]interface{}
+)
+
+// OnReload registers a handler which is called every time the config file is reloaded
+func OnReload(h ReloadHandler) {
+	confLock.Lock()
+	defer confLock.Unlock()
+	reloadHandlers = append(reloadHandlers, h)
+}
+
+// watchConfig starts watching the config files of configPath, changes of the reloadable settings are applied at runtime.
+// The files are merged again when any of them changes, the files added into a watched directory are not watched.
+func watchConfig(v *viper.Viper, configPath string) {
+	startupSettings = nonReloadableSettings(v)
+	files, err := configFiles(configPath)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to watch config %s", configPath)
+		return
+	}
+	for _, file := range files {
+		// each file is watched by a viper instance of its own, as viper watches the last file read
+		w := viper.New()
+		w.SetConfigFile(file)
+		w.OnConfigChange(func(e fsnotify.Event) {
+			merged, err := readConfig(configPath)
+			if err != nil {
+				logrus.WithError(err).Errorf("failed to reload config %s, keep the current one", configPath)
+				return
+			}
+			reloadConfig(merged, configPath)
+		})
+		w.WatchConfig()
+	}
+}
+
+// reloadConfig swaps the reloadable settings of the current snapshot with the ones of v,
+// which is the configuration read again after a config file changes
+func reloadConfig(v *viper.Viper, configPath string) {
+	newLogConf := new(Log)
+	if err := unmarshal(v, "log", newLogConf); err != nil {
+		logrus.WithError(err).Errorf("failed to reload config file %s, keep the current one", configPath)
+		return
+	}
+	newConf := new(ExecutorConf)
+	if err := unmarshal(v, "executor", newConf); err != nil {
+		logrus.WithError(err).Errorf("failed to reload config file %s, keep the current one", configPath)
+		return
+	}
+
+	for _, key := range changedSettings(startupSettings, nonReloadableSettings(v)) {
+		logrus.Warnf("config [%s] changed in file %s, it takes effect after restarting", key, configPath)
+	}
+
+	confLock.Lock()
+	// copy the snapshot, so that the one held by callers is never modified
+	conf := *executorConf
+	conf.Mpc = reloadedMpcConf(conf.Mpc, newConf.Mpc, configPath)
+	conf.HttpServer = reloadedHttpServerConf(conf.HttpServer, newConf.HttpServer, configPath)
+	conf.RequesterAllowlist = reloadedRequesterAllowlistConf(conf.RequesterAllowlist, newConf.RequesterAllowlist, configPath)
+	log := *logConf
+	log.Level = newLogConf.Level
+	executorConf = &conf
+	logConf = &log
+	handlers := make([]ReloadHandler, len(reloadHandlers))
+	copy(handlers, reloadHandlers)
+	confLock.Unlock()
+
+	logrus.Infof("config file %s reloaded, mpc: %+v, log level: %s", configPath, *conf.Mpc, log.Level)
+	for _, h := range handlers {
+		h(&conf, &log)
+	}
+}
+
+// reloadedMpcConf returns the mpc settings to apply, the current value is kept
+// and an error is logged if the reloaded one is missing or invalid
+func reloadedMpcConf(current, reloaded *ExecutorMpcConf, configPath string) *ExecutorMpcConf {
+	var conf ExecutorMpcConf
+	if current != nil {
+		conf = *current
+	}
+	if reloaded == nil {
+		logrus.Errorf("[executor.mpc] not found in config file %s, keep the current one", configPath)
+		return &conf
+	}
+	if reloaded.TrainTaskLimit > 0 {
+		conf.TrainTaskLimit = reloaded.TrainTaskLimit
+	} else {
+		logrus.Errorf("invalid trainTaskLimit %d in config file %s, keep the current one %d",
+			reloaded.TrainTaskLimit, configPath, conf.TrainTaskLimit)
+	}
+	if reloaded.PredictTaskLimit > 0 {
+		conf.PredictTaskLimit = reloaded.PredictTaskLimit
+	} else {
+		logrus.Errorf("invalid predictTaskLimit %d in config file %s, keep the current one %d",
+			reloaded.PredictTaskLimit, configPath, conf.PredictTaskLimit)
+	}
+	if reloaded.RpcTimeout >= 0 {
+		conf.RpcTimeout = reloaded.RpcTimeout
+	} else {
+		logrus.Errorf("invalid rpcTimeout %v in config file %s, keep the current one %v",
+			reloaded.RpcTimeout, configPath, conf.RpcTimeout)
+	}
+	if reloaded.TaskLimitTime >= 0 {
+		conf.TaskLimitTime = reloaded.TaskLimitTime
+	} else {
+		logrus.Errorf("invalid taskLimitTime %v in config file %s, keep the current one %v",
+			reloaded.TaskLimitTime, configPath, conf.TaskLimitTime)
+	}
+	if reloaded.MaxTaskLimitTime >= 0 {
+		conf.MaxTaskLimitTime = reloaded.MaxTaskLimitTime
+	} else {
+		logrus.Errorf("invalid maxTaskLimitTime %v in config file %s, keep the current one %v",
+			reloaded.MaxTaskLimitTime, configPath, conf.MaxTaskLimitTime)
+	}
+	return &conf
+}
+
+// reloadedHttpServerConf returns the httpserver settings to apply, only the tokens of auth are reloaded,
+// the current ones are kept and an error is logged if auth was configured and the reloaded one is missing
+func reloadedHttpServerConf(current, reloaded *HttpServerConf, configPath string) *HttpServerConf {
+	if current == nil || current.Auth == nil {
+		return current
+	}
+	conf := *current
+	if reloaded == nil || reloaded.Auth == nil {
+		logrus.Errorf("[executor.httpserver.auth] not found in config file %s, keep the current one", configPath)
+		return &conf
+	}
+	if len(reloaded.Auth.Tokens) == 0 && reloaded.Auth.TokenFile == "" {
+		logrus.Errorf("neither tokens nor tokenFile of [executor.httpserver.auth] is set in config file %s, "+
+			"keep the current one", configPath)
+		return &conf
+	}
+	auth := *reloaded.Auth
+	conf.Auth = &auth
+	return &conf
+}
+
+// reloadedRequesterAllowlistConf returns the requester allowlist to apply, the current one is kept and an error
+// is logged if the allowlist was configured and the reloaded one is missing or invalid. An allowlist configured
+// after startup is not applied, as it takes effect after restarting.
+func reloadedRequesterAllowlistConf(current, reloaded *RequesterAllowlistConf, configPath string) *RequesterAllowlistConf {
+	if current == nil {
+		return nil
+	}
+	if reloaded == nil {
+		logrus.Errorf("[executor.requesterAllowlist] not found in config file %s, keep the current one", configPath)
+		return current
+	}
+	if err := validateRequesterAllowlistConf(reloaded, configPath); err != nil {
+		logrus.WithError(err).Error("failed to reload the requester allowlist, keep the current one")
+		return current
+	}
+	conf := *reloaded
+	return &conf
+}
+
+// nonReloadableSettings returns all settings except the reloadable ones
+func nonReloadableSettings(v *viper.Viper) map[string]interface{} {
+	settings := make(map[string]interface{})
+	for _, key := range v.AllKeys() {
+		if !reloadableKeys[key] {
+			settings[key] = v.Get(key)
+		}
+	}
+	return settings
+}
+
+// changedSettings returns the sorted keys whose values differ between old and new
+func changedSettings(old, new map[string]interface{}) []string {
+	var keys []string
+	for key, value := range new {
+		if !reflect.DeepEqual(old[key], value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,678 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+var (
+	// roles lists the supported values of 'executor.role'
+	roles = []string{RoleExecutor, RoleObserver}
+	// executionModeTypes lists the supported values of 'executor.mode.type'
+	executionModeTypes = []string{"Proxy", "Self"}
+	// storageTypes lists the supported values of 'executor.storage.type'
+	storageTypes = []string{"Local", "XuperDB", "S3"}
+	// keyProviderTypes lists the supported values of 'executor.keyProvider.type'
+	keyProviderTypes = []string{KeyProviderFile, KeyProviderVault}
+	// rateLimitKeys lists the supported values of 'executor.httpserver.rateLimit.keyBy'
+	rateLimitKeys = []string{"ip", "token"}
+	// compressionTypes lists the supported values of 'executor.mpc.compression'
+	compressionTypes = []string{"none", "gzip", "snappy"}
+	// psiAlgorithms lists the supported values of 'executor.mpc.psiAlgorithm'
+	psiAlgorithms = []string{"ecdh", "oprf", "auto"}
+	// namespacePattern defines the allowed charset of XuperDB namespaces
+	namespacePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// maxMsgSizeMB is the upper bound of 'executor.mpc.maxRecvMsgSizeMB' and 'executor.mpc.maxSendMsgSizeMB',
+// gRPC messages are limited to 2GB
+const maxMsgSizeMB = 2047
+
+// publicKeyLength is the length of the public keys of requesters in 'executor.requesterAllowlist.requesters'
+const publicKeyLength = 64
+
+// maxAccuracy is the upper bound of 'executor.mpc.defaultAccuracy', 10^15 is the largest power of 10 exact in float64
+const maxAccuracy = 15
+
+// validateExecutorConf checks the fields of ExecutorConf right after it is parsed,
+// so that a broken configuration is reported at startup instead of failing deep inside
+// the gRPC setup or the first task. configPath is only used to build the error message.
+func validateExecutorConf(conf *ExecutorConf, configPath string) error {
+	if err := checkHostPort(conf.ListenAddress); err != nil {
+		return configError(configPath, "executor.listenAddress", "%v", err)
+	}
+	if conf.PublicAddress == "" {
+		return configError(configPath, "executor.publicAddress", "can not be empty")
+	}
+	if conf.Role != "" && !contains(roles, conf.Role) {
+		return configError(configPath, "executor.role", "unknown role '%s', supported: %v", conf.Role, roles)
+	}
+	// observers never execute tasks, so the sections of sample download and storage are not required
+	if conf.Role != RoleObserver {
+		if err := validateExecutionConf(conf, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.HttpServer != nil {
+		if err := validateHttpServerConf(conf.HttpServer, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.Mpc != nil {
+		if err := validateMpcConf(conf.Mpc, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.TLS != nil {
+		if err := validateTLSConf(conf.TLS, configPath, "executor.tls"); err != nil {
+			return err
+		}
+	}
+
+	if conf.KeyProvider != nil {
+		if err := validateKeyProviderConf(conf.KeyProvider, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.Tracing != nil {
+		if err := validateTracingConf(conf.Tracing, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.Callback != nil {
+		if conf.Callback.MaxRetries < 0 {
+			return configError(configPath, "executor.callback.maxRetries", "can not be negative")
+		}
+		if conf.Callback.RetryInterval < 0 || conf.Callback.Timeout < 0 {
+			return configError(configPath, "executor.callback", "retryInterval and timeout can not be negative")
+		}
+	}
+
+	if conf.Outbox != nil {
+		if conf.Outbox.Size < 0 {
+			return configError(configPath, "executor.outbox.size", "can not be negative")
+		}
+		if conf.Outbox.RetryInterval < 0 || conf.Outbox.MaxRetryInterval < 0 {
+			return configError(configPath, "executor.outbox", "retryInterval and maxRetryInterval can not be negative")
+		}
+		if conf.Outbox.MaxRetryInterval > 0 && conf.Outbox.MaxRetryInterval < conf.Outbox.RetryInterval {
+			return configError(configPath, "executor.outbox.maxRetryInterval", "can not be less than retryInterval")
+		}
+	}
+
+	if conf.RequesterAllowlist != nil {
+		if err := validateRequesterAllowlistConf(conf.RequesterAllowlist, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.Audit != nil && conf.Audit.Path == "" {
+		return configError(configPath, "executor.audit.path", "is required")
+	}
+
+	if conf.Pprof != nil {
+		if err := validatePprofConf(conf, configPath); err != nil {
+			return err
+		}
+	}
+
+	if conf.StatsD != nil {
+		if conf.StatsD.Host == "" {
+			return configError(configPath, "executor.statsd.host", "is required")
+		}
+		if conf.StatsD.Port <= 0 || conf.StatsD.Port > 65535 {
+			return configError(configPath, "executor.statsd.port", "invalid port %d", conf.StatsD.Port)
+		}
+		if conf.StatsD.FlushInterval < 0 {
+			return configError(configPath, "executor.statsd.flushInterval", "can not be negative")
+		}
+	}
+
+	if conf.Blockchain == nil {
+		return configError(configPath, "executor.blockchain", "section is missing")
+	}
+	if err := validateBlockchainConf(conf.Blockchain, configPath, "executor.blockchain"); err != nil {
+		return err
+	}
+	return validateBlockchainNetworks(conf.Blockchain, configPath)
+}
+
+// validateExecutionConf checks the sections used to execute tasks, that is, sample download and storage
+func validateExecutionConf(conf *ExecutorConf, configPath string) error {
+	if conf.Mode == nil {
+		return configError(configPath, "executor.mode", "section is missing")
+	}
+	if !contains(executionModeTypes, conf.Mode.Type) {
+		return configError(configPath, "executor.mode.type", "unknown type '%s', supported: %v",
+			conf.Mode.Type, executionModeTypes)
+	}
+	if conf.Mode.Type == "Self" {
+		if conf.Mode.Self == nil {
+			return configError(configPath, "executor.mode.self", "section is missing")
+		}
+		if err := conf.Mode.Self.Validate(); err != nil {
+			return errorx.ParseAndWrap(err, "invalid config [executor.mode.self] in file %s", configPath)
+		}
+	}
+	if err := validateModeTLSConf(conf.Mode, configPath); err != nil {
+		return err
+	}
+	if conf.Mode.Proxy != nil {
+		if err := validateProxyConf(conf.Mode.Proxy, configPath, "executor.mode.proxy"); err != nil {
+			return err
+		}
+	}
+
+	if conf.Storage == nil {
+		return configError(configPath, "executor.storage", "section is missing")
+	}
+	if !contains(storageTypes, conf.Storage.Type) {
+		return configError(configPath, "executor.storage.type", "unknown type '%s', supported: %v",
+			conf.Storage.Type, storageTypes)
+	}
+	if conf.Storage.Type == "XuperDB" {
+		if conf.Storage.XuperDB == nil {
+			return configError(configPath, "executor.storage.xuperdb", "section is missing")
+		}
+		// files are uploaded to XuperDB, so the namespace and expiration time are required
+		if conf.Storage.XuperDB.NameSpace == "" {
+			return configError(configPath, "executor.storage.xuperdb.namespace", "can not be empty")
+		}
+		if err := conf.Storage.XuperDB.Validate(); err != nil {
+			return errorx.ParseAndWrap(err, "invalid config [executor.storage.xuperdb] in file %s", configPath)
+		}
+	}
+	if conf.Storage.Type == "S3" {
+		if conf.Storage.S3 == nil {
+			return configError(configPath, "executor.storage.s3", "section is missing")
+		}
+		if conf.Storage.S3.Bucket == "" {
+			return configError(configPath, "executor.storage.s3.bucket", "can not be empty")
+		}
+		if conf.Storage.S3.Region == "" {
+			return configError(configPath, "executor.storage.s3.region", "can not be empty")
+		}
+		// static credentials need both keys, leave both empty to use the default credential chain
+		if conf.Storage.S3.AccessKey != "" && conf.Storage.S3.SecretKey == "" {
+			return configError(configPath, "executor.storage.s3.secretKey", "can not be empty when accessKey is set")
+		}
+		if conf.Storage.S3.AccessKey == "" && conf.Storage.S3.SecretKey != "" {
+			return configError(configPath, "executor.storage.s3.accessKey", "can not be empty when secretKey is set")
+		}
+	}
+	if r := conf.Storage.Retention; r != nil {
+		if r.MaxTotalSizeMB < 0 {
+			return configError(configPath, "executor.storage.retention.maxTotalSizeMB", "can not be negative")
+		}
+		if r.MaxAge == 0 && r.MaxTotalSizeMB == 0 {
+			return configError(configPath, "executor.storage.retention", "either maxAge or maxTotalSizeMB should be set")
+		}
+	}
+	if d := conf.Storage.Download; d != nil {
+		if d.MaxRetries < 0 {
+			return configError(configPath, "executor.storage.download.maxRetries", "can not be negative")
+		}
+		if d.RetryInterval < 0 {
+			return configError(configPath, "executor.storage.download.retryInterval", "can not be negative")
+		}
+	}
+	if n := conf.Storage.FileNames; n != nil {
+		templates := []struct{ field, text, typ string }{
+			{"model", n.Model, "model"},
+			{"evaluation", n.Evaluation, "evaluation"},
+			{"checkpoint", n.Checkpoint, "checkpoint"},
+			{"prediction", n.Prediction, "prediction"},
+		}
+		for _, t := range templates {
+			if t.text == "" {
+				continue
+			}
+			if _, err := file.ParseNameTemplate(t.text, t.typ); err != nil {
+				return configError(configPath, "executor.storage.fileNames."+t.field, "%v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHttpServerConf checks the rate limit and the token source of auth
+func validateHttpServerConf(conf *HttpServerConf, configPath string) error {
+	if conf.Auth != nil && len(conf.Auth.Tokens) == 0 && conf.Auth.TokenFile == "" {
+		return configError(configPath, "executor.httpserver.auth", "tokens or tokenFile is required")
+	}
+	if conf.RateLimit == nil {
+		return nil
+	}
+	if conf.RateLimit.KeyBy == "token" && conf.Auth == nil {
+		return configError(configPath, "executor.httpserver.rateLimit.keyBy",
+			"requests can not be limited by token if [executor.httpserver.auth] is not configured")
+	}
+	return validateRateLimitConf(conf.RateLimit, configPath)
+}
+
+// validateRequesterAllowlistConf checks the public keys of requesters are hex strings of 64 bytes,
+// the ones in the allowlist file are checked when it's loaded
+func validateRequesterAllowlistConf(conf *RequesterAllowlistConf, configPath string) error {
+	const section = "executor.requesterAllowlist"
+	if len(conf.Requesters) == 0 && conf.File == "" {
+		return configError(configPath, section, "requesters or file is required")
+	}
+	for _, r := range conf.Requesters {
+		if pk, err := hex.DecodeString(r); err != nil || len(pk) != publicKeyLength {
+			return configError(configPath, section+".requesters", "invalid public key '%s', it should be a hex string of %d bytes",
+				r, publicKeyLength)
+		}
+	}
+	return nil
+}
+
+// validateRateLimitConf checks the rates and bursts are not negative and each endpoint is set once
+func validateRateLimitConf(conf *RateLimitConf, configPath string) error {
+	const section = "executor.httpserver.rateLimit"
+	if conf.KeyBy != "" && !contains(rateLimitKeys, conf.KeyBy) {
+		return configError(configPath, section+".keyBy", "unknown key '%s', supported: %v", conf.KeyBy, rateLimitKeys)
+	}
+	if conf.RequestsPerSecond < 0 {
+		return configError(configPath, section+".requestsPerSecond", "can not be negative")
+	}
+	if conf.Burst < 0 {
+		return configError(configPath, section+".burst", "can not be negative")
+	}
+	paths := make(map[string]bool)
+	for _, e := range conf.Endpoints {
+		if !strings.HasPrefix(e.Path, "/") {
+			return configError(configPath, section+".endpoints.path", "invalid path '%s', it should start with '/'", e.Path)
+		}
+		if paths[e.Path] {
+			return configError(configPath, section+".endpoints.path", "duplicate path '%s'", e.Path)
+		}
+		paths[e.Path] = true
+		if e.RequestsPerSecond < 0 {
+			return configError(configPath, section+".endpoints.requestsPerSecond", "can not be negative for '%s'", e.Path)
+		}
+		if e.Burst < 0 {
+			return configError(configPath, section+".endpoints.burst", "can not be negative for '%s'", e.Path)
+		}
+	}
+	return nil
+}
+
+// validateTracingConf checks the collector endpoint is set and the sample rate is a fraction
+func validateTracingConf(conf *TracingConf, configPath string) error {
+	if conf.Endpoint == "" {
+		return configError(configPath, "executor.tracing.endpoint", "is required")
+	}
+	if conf.SampleRate < 0 || conf.SampleRate > 1 {
+		return configError(configPath, "executor.tracing.sampleRate", "%v is not between 0 and 1", conf.SampleRate)
+	}
+	if conf.SampleRate == 0 {
+		conf.SampleRate = 1
+	}
+	return nil
+}
+
+// validatePprofConf checks the profiling endpoints listen on a port of their own,
+// on the loopback interface unless they are allowed to be accessed remotely
+func validatePprofConf(conf *ExecutorConf, configPath string) error {
+	pprof := conf.Pprof
+	if err := checkHostPort(pprof.Address); err != nil {
+		return configError(configPath, "executor.pprof.address", "%v", err)
+	}
+	host, port, _ := net.SplitHostPort(pprof.Address)
+	if !pprof.AllowRemote && !isLoopback(host) {
+		return configError(configPath, "executor.pprof.address",
+			"'%s' is not a loopback address, set allowRemote to expose the profiling endpoints to other hosts", pprof.Address)
+	}
+	if _, listenPort, err := net.SplitHostPort(conf.ListenAddress); err == nil && listenPort == port {
+		return configError(configPath, "executor.pprof.address", "port %s is used by listenAddress", port)
+	}
+	if conf.HttpServer != nil && conf.HttpServer.Switch == "on" && conf.HttpServer.HttpPort == port {
+		return configError(configPath, "executor.pprof.address", "port %s is used by the httpserver", port)
+	}
+	if pprof.Duration < 0 {
+		return configError(configPath, "executor.pprof.duration", "can not be negative")
+	}
+	if pprof.Duration == 0 {
+		pprof.Duration = time.Hour
+	}
+	return nil
+}
+
+// isLoopback checks whether host is localhost or a loopback IP
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validateKeyProviderConf checks the key provider, the Vault server is required if keys are read from Vault.
+// The credentials are checked when connecting Vault, as they may be set by environment variables.
+func validateKeyProviderConf(conf *KeyProviderConf, configPath string) error {
+	if conf.Type == "" {
+		conf.Type = KeyProviderFile
+	}
+	if !contains(keyProviderTypes, conf.Type) {
+		return configError(configPath, "executor.keyProvider.type", "unknown type '%s', supported: %v",
+			conf.Type, keyProviderTypes)
+	}
+	if conf.Type != KeyProviderVault {
+		return nil
+	}
+	if conf.Vault == nil {
+		return configError(configPath, "executor.keyProvider.vault", "section is missing")
+	}
+	u, err := url.Parse(conf.Vault.Address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return configError(configPath, "executor.keyProvider.vault.address", "'%s' is not a valid http or https URL",
+			conf.Vault.Address)
+	}
+	if conf.Vault.CAFile != "" {
+		if _, err := os.Stat(conf.Vault.CAFile); err != nil {
+			return configError(configPath, "executor.keyProvider.vault.caFile", "%v", err)
+		}
+	}
+	if conf.Vault.Timeout < 0 {
+		return configError(configPath, "executor.keyProvider.vault.timeout", "can not be negative")
+	}
+	return nil
+}
+
+// validateMpcConf checks the resource limits of tasks and the queue size, a task's ceiling can not exceed the node's budget.
+func validateMpcConf(conf *ExecutorMpcConf, configPath string) error {
+	limits := []struct {
+		key   string
+		value int
+	}{
+		{"maxMemoryMB", conf.MaxMemoryMB},
+		{"maxCPUCores", conf.MaxCPUCores},
+		{"nodeMemoryMB", conf.NodeMemoryMB},
+		{"nodeCPUCores", conf.NodeCPUCores},
+		{"queueSize", conf.QueueSize},
+		{"maxSampleFileSizeMB", conf.MaxSampleFileSizeMB},
+		{"maxFeatures", conf.MaxFeatures},
+		{"psiWorkers", conf.PSIWorkers},
+		{"kernelWorkers", conf.KernelWorkers},
+		{"psiCacheEntries", conf.PSICacheEntries},
+		{"spillMemoryThresholdMB", conf.SpillMemoryThresholdMB},
+		{"maxRecvMsgSizeMB", conf.MaxRecvMsgSizeMB},
+		{"maxSendMsgSizeMB", conf.MaxSendMsgSizeMB},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return configError(configPath, "executor.mpc."+limit.key, "can not be negative")
+		}
+	}
+	if conf.SpillMemoryThresholdMB > 0 && conf.SpillDir == "" {
+		return configError(configPath, "executor.mpc.spillDir", "can not be empty if spillMemoryThresholdMB is set")
+	}
+	if conf.MaxRecvMsgSizeMB > maxMsgSizeMB || conf.MaxSendMsgSizeMB > maxMsgSizeMB {
+		return configError(configPath, "executor.mpc", "maxRecvMsgSizeMB and maxSendMsgSizeMB can not exceed %d", maxMsgSizeMB)
+	}
+	if conf.KeepaliveTime < 0 || conf.KeepaliveTimeout < 0 {
+		return configError(configPath, "executor.mpc", "keepaliveTime and keepaliveTimeout can not be negative")
+	}
+	// gRPC clients ping at most every 10 seconds
+	if conf.KeepaliveTime > 0 && conf.KeepaliveTime < 10*time.Second {
+		return configError(configPath, "executor.mpc.keepaliveTime", "can not be less than 10s")
+	}
+	if conf.PeakWindow < 0 {
+		return configError(configPath, "executor.mpc.peakWindow", "can not be negative")
+	}
+	if conf.BreakerThreshold < 0 {
+		return configError(configPath, "executor.mpc.breakerThreshold", "can not be negative")
+	}
+	if conf.BreakerWindow < 0 {
+		return configError(configPath, "executor.mpc.breakerWindow", "can not be negative")
+	}
+	if conf.BreakerCooldown < 0 {
+		return configError(configPath, "executor.mpc.breakerCooldown", "can not be negative")
+	}
+	if conf.PSICacheTTL < 0 {
+		return configError(configPath, "executor.mpc.psiCacheTTL", "can not be negative")
+	}
+	if conf.NodeMemoryMB > 0 && conf.MaxMemoryMB > conf.NodeMemoryMB {
+		return configError(configPath, "executor.mpc.maxMemoryMB", "can not exceed nodeMemoryMB %d", conf.NodeMemoryMB)
+	}
+	if conf.NodeCPUCores > 0 && conf.MaxCPUCores > conf.NodeCPUCores {
+		return configError(configPath, "executor.mpc.maxCPUCores", "can not exceed nodeCPUCores %d", conf.NodeCPUCores)
+	}
+	if conf.MaxTaskLimitTime < 0 {
+		return configError(configPath, "executor.mpc.maxTaskLimitTime", "can not be negative")
+	}
+	if conf.MaxTaskLimitTime > 0 && conf.MaxTaskLimitTime < conf.TaskLimitTime {
+		return configError(configPath, "executor.mpc.maxTaskLimitTime", "can not be less than taskLimitTime %v", conf.TaskLimitTime)
+	}
+	if conf.Compression != "" && !contains(compressionTypes, conf.Compression) {
+		return configError(configPath, "executor.mpc.compression", "unknown compression '%s', supported: %v",
+			conf.Compression, compressionTypes)
+	}
+	if conf.PSIAlgorithm != "" && !contains(psiAlgorithms, conf.PSIAlgorithm) {
+		return configError(configPath, "executor.mpc.psiAlgorithm", "unknown algorithm '%s', supported: %v",
+			conf.PSIAlgorithm, psiAlgorithms)
+	}
+	if conf.DefaultAccuracy < 0 || conf.DefaultAccuracy > maxAccuracy {
+		return configError(configPath, "executor.mpc.defaultAccuracy", "should be in the range of [1,%d]", maxAccuracy)
+	}
+	return nil
+}
+
+// validateTLSConf checks the certificate files exist, section is the key of conf in the config file.
+// CAFile is required by mutual TLS to verify client certificates.
+func validateTLSConf(conf *TLSConf, configPath, section string) error {
+	files := []struct{ key, value string }{
+		{"certFile", conf.CertFile},
+		{"keyFile", conf.KeyFile},
+		{"caFile", conf.CAFile},
+	}
+	for _, file := range files {
+		if file.value == "" {
+			if file.key == "caFile" && !conf.ClientAuth {
+				continue
+			}
+			return configError(configPath, section+"."+file.key, "can not be empty")
+		}
+		if _, err := os.Stat(file.value); err != nil {
+			return configError(configPath, section+"."+file.key, "%v", err)
+		}
+	}
+	if conf.ReloadInterval < 0 {
+		return configError(configPath, section+".reloadInterval", "should not be negative, got %v", conf.ReloadInterval)
+	}
+	return nil
+}
+
+// validateModeTLSConf checks the TLS settings used to download sample files,
+// CAFile is always required to verify the certificates of dataOwner and storage nodes.
+func validateModeTLSConf(conf *ExecutorModeConf, configPath string) error {
+	if conf.TLS == nil {
+		if len(conf.Identities) != 0 {
+			return configError(configPath, "executor.mode.identities", "requires [executor.mode.tls]")
+		}
+		return nil
+	}
+	if conf.TLS.CAFile == "" {
+		return configError(configPath, "executor.mode.tls.caFile", "can not be empty")
+	}
+	if err := validateTLSConf(conf.TLS, configPath, "executor.mode.tls"); err != nil {
+		return err
+	}
+	if conf.Type == "Self" && !strings.HasPrefix(conf.Self.Host, "https://") {
+		return configError(configPath, "executor.mode.self.host", "'%s' should start with 'https://' when tls is configured",
+			conf.Self.Host)
+	}
+	hosts := make(map[string]bool)
+	for i, id := range conf.Identities {
+		key := fmt.Sprintf("executor.mode.identities[%d]", i)
+		if err := checkHostPort(id.Host); err != nil {
+			return configError(configPath, key+".host", "%v", err)
+		}
+		if id.Identity == "" {
+			return configError(configPath, key+".identity", "can not be empty")
+		}
+		if hosts[id.Host] {
+			return configError(configPath, key+".host", "duplicate host %s", id.Host)
+		}
+		hosts[id.Host] = true
+	}
+	return nil
+}
+
+// validateProxyConf checks the proxy is an http URL with a host, section is the key of conf in the config file
+func validateProxyConf(conf *ProxyConf, configPath, section string) error {
+	u, err := url.Parse(conf.URL)
+	if err != nil || u.Scheme != "http" || u.Host == "" {
+		return configError(configPath, section+".url", "invalid proxy '%s', it should be like 'http://host:port'", conf.URL)
+	}
+	if conf.Username == "" && conf.Password != "" {
+		return configError(configPath, section+".username", "can not be empty when password is set")
+	}
+	return nil
+}
+
+// validateBlockchainConf checks the sub-section selected by Type, section is the key of conf in the config file.
+// Unknown types are reported when the blockchain client is created.
+func validateBlockchainConf(conf *ExecutorBlockchainConf, configPath, section string) error {
+	switch conf.Type {
+	case "xchain":
+		if conf.Xchain == nil {
+			return configError(configPath, section+".xchain", "section is missing, required when type is 'xchain'")
+		}
+		if conf.Xchain.MaxRetries < 0 {
+			return configError(configPath, section+".xchain.maxRetries", "can not be negative")
+		}
+		if conf.Xchain.PoolSize < 0 {
+			return configError(configPath, section+".xchain.poolSize", "can not be negative")
+		}
+	case "fabric":
+		if conf.Fabric == nil {
+			return configError(configPath, section+".fabric", "section is missing, required when type is 'fabric'")
+		}
+		required := []struct{ key, value string }{
+			{"configFile", conf.Fabric.ConfigFile},
+			{"channelId", conf.Fabric.ChannelID},
+			{"chaincode", conf.Fabric.Chaincode},
+			{"userName", conf.Fabric.UserName},
+		}
+		for _, field := range required {
+			if field.value == "" {
+				return configError(configPath, section+".fabric."+field.key, "can not be empty")
+			}
+		}
+		if conf.Fabric.OrgName == "" && conf.Fabric.MspID == "" {
+			return configError(configPath, section+".fabric", "one of 'orgName' and 'mspId' is required")
+		}
+	}
+	return nil
+}
+
+// validateBlockchainNetworks checks the other blockchain networks joined by the executor,
+// each of them should be named uniquely and can not have networks of its own
+func validateBlockchainNetworks(conf *ExecutorBlockchainConf, configPath string) error {
+	names := map[string]bool{conf.NetworkName(): true}
+	for i, network := range conf.Networks {
+		section := fmt.Sprintf("executor.blockchain.networks[%d]", i)
+		if network.Name == "" {
+			return configError(configPath, section+".name", "can not be empty")
+		}
+		if names[network.Name] {
+			return configError(configPath, section+".name", "duplicated network '%s'", network.Name)
+		}
+		names[network.Name] = true
+		if len(network.Networks) > 0 {
+			return configError(configPath, section+".networks", "networks can not be nested")
+		}
+		if err := validateBlockchainConf(network, configPath, section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the XuperDB endpoint. Host must be an http or https URL without path,
+// NameSpace and ExpireTime are only used for uploading, so a download-only endpoint like
+// 'executor.mode.self' may leave both of them unset.
+func (c *XuperDBConf) Validate() error {
+	u, err := url.Parse(c.Host)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errorx.New(errorx.ErrCodeConfig, "host '%s' is not a valid http or https URL", c.Host)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return errorx.New(errorx.ErrCodeConfig, "host '%s' should not contain path, query or trailing slash", c.Host)
+	}
+	if c.ChunkSizeMB < 0 {
+		return errorx.New(errorx.ErrCodeConfig, "chunkSizeMB can not be negative, got %d", c.ChunkSizeMB)
+	}
+	if c.NameSpace == "" && c.ExpireTime == 0 {
+		return nil
+	}
+	if !namespacePattern.MatchString(c.NameSpace) {
+		return errorx.New(errorx.ErrCodeConfig, "namespace '%s' is invalid, only letters, digits, '_' and '-' are allowed",
+			c.NameSpace)
+	}
+	if c.ExpireTime <= 0 {
+		return errorx.New(errorx.ErrCodeConfig, "expireTime must be positive, got %d", c.ExpireTime)
+	}
+	return nil
+}
+
+// checkHostPort checks whether address is in the form of 'host:port', the host can be empty
+func checkHostPort(address string) error {
+	if address == "" {
+		return fmt.Errorf("can not be empty")
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid host:port", address)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("'%s' has an invalid port", address)
+	}
+	return nil
+}
+
+// configError returns a configuration error naming the invalid field and the config file
+func configError(configPath, field, format string, args ...interface{}) error {
+	return errorx.New(errorx.ErrCodeConfig, "invalid config [%s] in file %s: %s",
+		field, configPath, fmt.Sprintf(format, args...))
+}
+
+// contains checks whether target is in list
+func contains(list []string, target string) bool {
+	for _, v := range list {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// Encodings of categorical columns and policies for categories unseen in training,
+// the bucket of unknown is encoded as all indicators 0 in one-hot encoding, and the number of categories in ordinal encoding
+const (
+	EncodingOneHot  = "onehot"  // encodes a column into one indicator column for each category, named column=category
+	EncodingOrdinal = "ordinal" // encodes a category into its index in the sorted categories
+
+	UnknownError  = "error"   // fails the task with an unseen category
+	UnknownBucket = "unknown" // maps an unseen category to the bucket of unknown
+)
+
+// CheckCategoricalParams checks the categorical columns and the way to encode them
+func CheckCategoricalParams(params *pb_common.CategoricalParams) error {
+	switch params.Encoding {
+	case "", EncodingOneHot, EncodingOrdinal:
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "invalid categorical encoding %s, should be %s or %s",
+			params.Encoding, EncodingOneHot, EncodingOrdinal)
+	}
+	switch params.Unknown {
+	case "", UnknownError, UnknownBucket:
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "invalid policy %s for unknown categories, should be %s or %s",
+			params.Unknown, UnknownError, UnknownBucket)
+	}
+	columns := make(map[string]bool, len(params.Columns))
+	for _, column := range params.Columns {
+		if column == "" || columns[column] {
+			return errorx.New(errcodes.ErrCodeParam, "categorical columns should be distinct and not empty")
+		}
+		columns[column] = true
+	}
+	return nil
+}
+
+// EncodeCategorical encodes the categorical columns of params.Categorical in the training samples fileRows,
+// by the categories found in fileRows, or by the categories of params.BaseModel if it is set.
+// The columns held by other parties are skipped, and the mappings of the local ones are returned to be stored with the model.
+func EncodeCategorical(fileRows [][]string, params *pb_common.TrainParams) ([][]string, []*pb_common.CategoryMapping, error) {
+	if params.BaseModel != nil {
+		rows, err := ApplyCategories(fileRows, params.BaseModel.Categories)
+		return rows, params.BaseModel.Categories, err
+	}
+	cp := params.Categorical
+	if cp == nil || len(cp.Columns) == 0 || len(fileRows) == 0 {
+		return fileRows, nil, nil
+	}
+	if err := CheckCategoricalParams(cp); err != nil {
+		return nil, nil, err
+	}
+
+	header := make(map[string]int, len(fileRows[0]))
+	for i, name := range fileRows[0] {
+		header[name] = i
+	}
+	var mappings []*pb_common.CategoryMapping
+	for _, column := range cp.Columns {
+		index, ok := header[column]
+		if !ok {
+			continue
+		}
+		if column == params.Label || column == params.IdName {
+			return nil, nil, errorx.New(errcodes.ErrCodeParam, "label or ID column %s can not be categorical", column)
+		}
+		seen := make(map[string]bool)
+		var categories []string
+		for _, row := range fileRows[1:] {
+			if !seen[row[index]] {
+				seen[row[index]] = true
+				categories = append(categories, row[index])
+			}
+		}
+		sort.Strings(categories)
+		mapping := &pb_common.CategoryMapping{
+			Column:     column,
+			Categories: categories,
+			Encoding:   cp.Encoding,
+			Unknown:    cp.Unknown,
+		}
+		if mapping.Encoding == "" {
+			mapping.Encoding = EncodingOneHot
+		}
+		if mapping.Unknown == "" {
+			mapping.Unknown = UnknownError
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	rows, err := ApplyCategories(fileRows, mappings)
+	return rows, mappings, err
+}
+
+// ApplyCategories encodes the categorical columns of fileRows by mappings, the first row of fileRows is the header,
+// all columns of mappings should be in fileRows, and the other columns are kept as they are
+func ApplyCategories(fileRows [][]string, mappings []*pb_common.CategoryMapping) ([][]string, error) {
+	if len(mappings) == 0 || len(fileRows) == 0 {
+		return fileRows, nil
+	}
+
+	byIndex := make(map[int]*pb_common.CategoryMapping, len(mappings))
+	codes := make(map[int]map[string]int, len(mappings))
+	for _, mapping := range mappings {
+		index := -1
+		for i, name := range fileRows[0] {
+			if name == mapping.Column {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, errorx.New(errcodes.ErrCodeParam, "categorical column %s is missing in samples", mapping.Column)
+		}
+		byIndex[index] = mapping
+		codes[index] = make(map[string]int, len(mapping.Categories))
+		for code, category := range mapping.Categories {
+			codes[index][category] = code
+		}
+	}
+
+	encoded := make([][]string, 0, len(fileRows))
+	var header []string
+	for i, name := range fileRows[0] {
+		mapping, ok := byIndex[i]
+		if !ok || mapping.Encoding == EncodingOrdinal {
+			header = append(header, name)
+			continue
+		}
+		for _, category := range mapping.Categories {
+			header = append(header, name+"="+category)
+		}
+	}
+	encoded = append(encoded, header)
+
+	for _, row := range fileRows[1:] {
+		newRow := make([]string, 0, len(header))
+		for i, value := range row {
+			mapping, ok := byIndex[i]
+			if !ok {
+				newRow = append(newRow, value)
+				continue
+			}
+			code, seen := codes[i][value]
+			if !seen {
+				if mapping.Unknown != UnknownBucket {
+					return nil, errorx.New(errcodes.ErrCodeParam, "category %s of column %s is unseen in training", value, mapping.Column)
+				}
+				code = len(mapping.Categories)
+			}
+			if mapping.Encoding == EncodingOrdinal {
+				newRow = append(newRow, strconv.Itoa(code))
+				continue
+			}
+			for c := range mapping.Categories {
+				if c == code {
+					newRow = append(newRow, "1")
+				} else {
+					newRow = append(newRow, "0")
+				}
+			}
+		}
+		encoded = append(encoded, newRow)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestEncodeCategorical(t *testing.T) {
+	fileRows := [][]string{
+		{"size", "city", "type"},
+		{"80", "sh", "b"},
+		{"120", "bj", "a"},
+		{"60", "sh", "a"},
+	}
+	params := &pb_common.TrainParams{
+		Label:       "price",
+		Categorical: &pb_common.CategoricalParams{Columns: []string{"city", "room"}},
+	}
+
+	rows, mappings, err := EncodeCategorical(fileRows, params)
+	checkErr(err, t)
+	want := [][]string{
+		{"size", "city=bj", "city=sh", "type"},
+		{"80", "0", "1", "b"},
+		{"120", "1", "0", "a"},
+		{"60", "0", "1", "a"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("one-hot encoded rows: %v, want %v", rows, want)
+	}
+	// column room is held by other party
+	if len(mappings) != 1 || mappings[0].Encoding != EncodingOneHot || mappings[0].Unknown != UnknownError ||
+		!reflect.DeepEqual(mappings[0].Categories, []string{"bj", "sh"}) {
+		t.Errorf("unexpected mappings: %v", mappings)
+	}
+
+	params.Categorical = &pb_common.CategoricalParams{Columns: []string{"type"}, Encoding: EncodingOrdinal}
+	rows, _, err = EncodeCategorical(fileRows, params)
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[1:], [][]string{{"80", "sh", "1"}, {"120", "bj", "0"}, {"60", "sh", "0"}}) {
+		t.Errorf("ordinal encoded rows: %v", rows)
+	}
+
+	// incremental training applies the categories of the base model
+	params.BaseModel = &pb_common.TrainModels{Categories: mappings}
+	rows, baseMappings, err := EncodeCategorical(fileRows[:2], params)
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows, want[:2]) || !reflect.DeepEqual(baseMappings, mappings) {
+		t.Errorf("rows encoded by the base model: %v", rows)
+	}
+}
+
+func TestApplyCategories(t *testing.T) {
+	mappings := []*pb_common.CategoryMapping{
+		{Column: "city", Categories: []string{"bj", "sh"}, Encoding: EncodingOneHot, Unknown: UnknownBucket},
+		{Column: "type", Categories: []string{"a", "b"}, Encoding: EncodingOrdinal, Unknown: UnknownBucket},
+	}
+	fileRows := [][]string{{"city", "type"}, {"gz", "c"}, {"bj", "b"}}
+
+	rows, err := ApplyCategories(fileRows, mappings)
+	checkErr(err, t)
+	want := [][]string{{"city=bj", "city=sh", "type"}, {"0", "0", "2"}, {"1", "0", "1"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("unknown categories mapped to the bucket: %v, want %v", rows, want)
+	}
+
+	mappings[0].Unknown = UnknownError
+	if _, err := ApplyCategories(fileRows, mappings); err == nil {
+		t.Error("unseen category should be rejected")
+	}
+	if _, err := ApplyCategories([][]string{{"type"}, {"a"}}, mappings); err == nil {
+		t.Error("missing categorical column should be rejected")
+	}
+}
@@ -137,15 +137,52 @@ func CostFromBytes(encCostByes []byte) (map[int]*big.Int, error) {
 	return encCost, nil
 }
 
-// TrainModelsToBytes convert train models to bytes for transfer and save
-func TrainModelsToBytes(thetas []float64, trainDataSet *ml_common.TrainDataSet, params pb_common.TrainParams) ([]byte, error) {
+// ResidualsToBytes convert the residuals of samples to bytes
+func ResidualsToBytes(residuals map[int]float64) ([]byte, error) {
+	return json.Marshal(residuals)
+}
+
+// ResidualsFromBytes retrieve the residuals of samples from bytes
+func ResidualsFromBytes(residualsBytes []byte) (map[int]float64, error) {
+	residuals := make(map[int]float64)
+	err := json.Unmarshal(residualsBytes, &residuals)
+	if err != nil {
+		return nil, err
+	}
+	return residuals, nil
+}
+
+// TrainModelsToBytes convert train models to bytes for transfer and save,
+// categories are the encodings of the categorical columns returned by EncodeCategorical
+func TrainModelsToBytes(thetas []float64, trainDataSet *ml_common.TrainDataSet, params pb_common.TrainParams,
+	categories []*pb_common.CategoryMapping) ([]byte, error) {
+	summaries := SummarizeTrainDataSet(trainDataSet, params.IsTagPart)
+	return trainModelsToBytes(thetas, trainDataSet, summaries, params, categories)
+}
+
+// SparseTrainModelsToBytes convert train models trained with the sparse train set to bytes for transfer and save
+func SparseTrainModelsToBytes(thetas []float64, trainSet *SparseTrainSet, params pb_common.TrainParams) ([]byte, error) {
+	return trainModelsToBytes(thetas, trainSet.TrainDataSet, trainSet.Summaries, params, nil)
+}
+
+// trainModelsToBytes convert train models to bytes, summaries are the distributions of the features of trainDataSet
+func trainModelsToBytes(thetas []float64, trainDataSet *ml_common.TrainDataSet, summaries map[string]*pb_common.FeatureSummary,
+	params pb_common.TrainParams, categories []*pb_common.CategoryMapping) ([]byte, error) {
 	thetaMap := thetasToMap(thetas, trainDataSet, params.IsTagPart)
 	trainModels := pb_common.TrainModels{
-		Thetas:    thetaMap,
-		Xbars:     trainDataSet.XbarParams,
-		Sigmas:    trainDataSet.SigmaParams,
-		Label:     params.Label,
-		IsTagPart: params.IsTagPart,
+		Thetas:     thetaMap,
+		Xbars:      trainDataSet.XbarParams,
+		Sigmas:     trainDataSet.SigmaParams,
+		Label:      params.Label,
+		IsTagPart:  params.IsTagPart,
+		Scaling:    scalingOf(params),
+		Categories: categories,
+		Summaries:  summaries,
+		Loss:       params.Loss,
+	}
+	// only the party with label has the weight column, it is a feature of the other party if named the same
+	if params.IsTagPart {
+		trainModels.WeightColumn = params.WeightColumn
 	}
 	return json.Marshal(trainModels)
 }
@@ -196,6 +233,28 @@ func PredictResultToBytes(idName string, IDs []string, values []float64) ([]byte
 	return content, nil
 }
 
+// PredictResultWithScoresToBytes convert ID, predict values, classes and raw scores of classification to bytes for storage,
+// predict values stay in the second column, so that the result is read as the one of PredictResultToBytes
+func PredictResultWithScoresToBytes(idName string, IDs []string, values []float64, classes []int, scores []float64) ([]byte, error) {
+	if len(IDs) != len(values) || len(IDs) != len(classes) || len(IDs) != len(scores) {
+		return nil, errorx.New(errcodes.ErrCodeParam, "ID, predict values, classes and scores numbers are not equal")
+	}
+
+	fileRows := make([][]string, len(IDs)+1)
+	// first row [idName, value, class, score], others are predict result
+	fileRows[0] = []string{idName, "value", "class", "score"}
+	for i := 0; i < len(IDs); i++ {
+		fileRows[i+1] = []string{IDs[i], strconv.FormatFloat(values[i], 'g', -1, 64), strconv.Itoa(classes[i]),
+			strconv.FormatFloat(scores[i], 'g', -1, 64)}
+	}
+
+	content, err := json.Marshal(fileRows)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeEncoding, "encode predict results failed: %s", err.Error())
+	}
+	return content, nil
+}
+
 // PredictResultFromBytes retrieve predict values from bytes
 func PredictResultFromBytes(resultBytes []byte) ([][]string, error) {
 	if len(resultBytes) == 0 {
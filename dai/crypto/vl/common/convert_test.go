@@ -206,7 +206,7 @@ func TestTrainModelsConvert(t *testing.T) {
 		Accuracy:  10,
 		IsTagPart: false,
 	}
-	modelsBytes, err := TrainModelsToBytes(thetas, trainDataSet, params)
+	modelsBytes, err := TrainModelsToBytes(thetas, trainDataSet, params, nil)
 	checkErr(err, t)
 
 	newModels, err := TrainModelsFromBytes(modelsBytes)
@@ -241,6 +241,22 @@ func TestPredictResultConvert(t *testing.T) {
 	}
 }
 
+func TestPredictResultWithScoresConvert(t *testing.T) {
+	content, err := PredictResultWithScoresToBytes("id", []string{"1", "2"}, []float64{0.8, 0.3}, []int{1, 0}, []float64{1.4, -0.85})
+	checkErr(err, t)
+
+	rows, err := PredictResultFromBytes(content)
+	checkErr(err, t)
+	expected := [][]string{{"id", "value", "class", "score"}, {"1", "0.8", "1", "1.4"}, {"2", "0.3", "0", "-0.85"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+
+	if _, err := PredictResultWithScoresToBytes("id", []string{"1", "2"}, []float64{0.8, 0.3}, []int{1}, []float64{1.4, -0.85}); err == nil {
+		t.Error("classes of different number should be rejected")
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
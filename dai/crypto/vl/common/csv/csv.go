@@ -18,13 +18,17 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 )
 
-// ReadIDsFromFileRows read ID set from file content, return all rows, ID set, error
+// ReadIDsFromFileRows read ID set from file content, return all rows, ID set, error,
+// sparse rows are kept sparse, see ReadSparseRowsFromFile
 func ReadIDsFromFileRows(fileContent []byte, idName string) ([][]string, []string, error) {
-	rows, err := ReadRowsFromFile(fileContent)
+	rows, err := ReadSparseRowsFromFile(fileContent)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read rows from file: %v", err)
 	}
@@ -35,10 +39,161 @@ func ReadIDsFromFileRows(fileContent []byte, idName string) ([][]string, []strin
 	return rows, IDs, err
 }
 
-// ReadRowsFromFile read all rows from csv file content
+// ReadRowsFromFile read all rows from csv file content, sparse rows are converted into dense ones,
+// see vl_common.DensifyRows
 func ReadRowsFromFile(fileContent []byte) ([][]string, error) {
+	rows, err := ReadSparseRowsFromFile(fileContent)
+	if err != nil {
+		return nil, err
+	}
+	return vl_common.DensifyRows(rows)
+}
+
+// ReadSparseRowsFromFile read all rows from csv file content, the sparse rows are kept as they are,
+// see vl_common.IsSparseRow, and the other rows should have all columns
+func ReadSparseRowsFromFile(fileContent []byte) ([][]string, error) {
 	r := csv.NewReader(bytes.NewReader(fileContent))
-	return r.ReadAll()
+	// sparse rows have variable numbers of cells, the number is checked by CheckRows
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if err := vl_common.CheckRows(rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SelectColumns returns the csv file content made up of columns of fileContent in the order of columns,
+// a column is specified by the name in the first row, or by the 0-based index if no column has the name.
+// Sparse rows are kept sparse with the indices of the selected columns.
+func SelectColumns(fileContent []byte, columns []string) ([]byte, error) {
+	rows, err := ReadSparseRowsFromFile(fileContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows from file: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+
+	header := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		header[name] = i
+	}
+	indices := make([]int, 0, len(columns))
+	// selected maps the index of a column to its index in the selected ones
+	selected := make(map[int]int, len(columns))
+	for _, column := range columns {
+		index, ok := header[column]
+		if !ok {
+			i, err := strconv.Atoi(column)
+			if err != nil || i < 0 || i >= len(rows[0]) {
+				return nil, fmt.Errorf("column %s not found in the file, columns are: %s", column, strings.Join(rows[0], ","))
+			}
+			index = i
+		}
+		if _, ok := selected[index]; ok {
+			return nil, fmt.Errorf("column %s is selected more than once", rows[0][index])
+		}
+		selected[index] = len(indices)
+		indices = append(indices, index)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for r, row := range rows {
+		var newRow []string
+		if r > 0 && vl_common.IsSparseRow(row, len(rows[0])) {
+			newRow = selectSparseColumns(row, len(rows[0]), selected)
+		} else {
+			newRow = make([]string, len(indices))
+			for i, index := range indices {
+				newRow[i] = row[index]
+			}
+		}
+		if err := w.Write(newRow); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// selectSparseColumns returns the sparse row made up of the selected columns of the sparse row,
+// selected maps the index of a column to its index in the selected ones
+func selectSparseColumns(row []string, columnNum int, selected map[int]int) []string {
+	var cells []int
+	values := make(map[int]string)
+	indices, rowValues := vl_common.RowCells(row, columnNum)
+	for i, index := range indices {
+		if newIndex, ok := selected[index]; ok {
+			cells = append(cells, newIndex)
+			values[newIndex] = rowValues[i]
+		}
+	}
+	// a row of all zeros keeps the first selected column, as a sparse row in the file has at least one cell
+	if len(cells) == 0 {
+		return []string{"0:" + vl_common.SparseZero}
+	}
+	sort.Ints(cells)
+	newRow := make([]string, len(cells))
+	for i, index := range cells {
+		newRow[i] = strconv.Itoa(index) + ":" + values[index]
+	}
+	return newRow
+}
+
+// SampleRows returns the csv file content made up of the first row of fileContent and the rows of the samples
+// in the subset of the dry run of the task seed, see vl_common.InDryRunSubset
+func SampleRows(fileContent []byte, idName, seed string, fraction float64) ([]byte, error) {
+	rows, IDs, err := ReadIDsFromFileRows(fileContent, idName)
+	if err != nil {
+		return nil, err
+	}
+	sampled := [][]string{rows[0]}
+	for i, id := range IDs {
+		if vl_common.InDryRunSubset(seed, id, fraction) {
+			sampled = append(sampled, rows[i+1])
+		}
+	}
+	if len(sampled) == 1 {
+		return nil, fmt.Errorf("no sample kept with the fraction %v of %d samples", fraction, len(IDs))
+	}
+	return WriteRows(sampled)
+}
+
+// FilterRows returns the csv file content made up of the first row of fileContent and the rows of the samples
+// whose IDs are in ids, in the order of fileContent
+func FilterRows(fileContent []byte, idName string, ids []string) ([]byte, error) {
+	rows, IDs, err := ReadIDsFromFileRows(fileContent, idName)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		kept[id] = true
+	}
+	filtered := [][]string{rows[0]}
+	for i, id := range IDs {
+		if kept[id] {
+			filtered = append(filtered, rows[i+1])
+		}
+	}
+	if len(filtered)-1 != len(kept) {
+		return nil, fmt.Errorf("%d of %d samples found", len(filtered)-1, len(kept))
+	}
+	return WriteRows(filtered)
+}
+
+// WriteRows encodes rows as csv file content
+func WriteRows(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // WriteRowsToFile write all rows to csv file
@@ -14,9 +14,11 @@
 package csv
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/common/utils"
@@ -50,6 +52,87 @@ func TestCSV(t *testing.T) {
 	}
 }
 
+func TestSelectColumns(t *testing.T) {
+	content := []byte("id,size,floor,price\n1,80,3,100\n2,120,5,180\n")
+
+	selected, err := SelectColumns(content, []string{"id", "price", "1"})
+	checkErr(err, t)
+	rows, err := ReadRowsFromFile(selected)
+	checkErr(err, t)
+	want := [][]string{{"id", "price", "size"}, {"1", "100", "80"}, {"2", "180", "120"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("selected rows: %v, want %v", rows, want)
+	}
+
+	for _, columns := range [][]string{
+		{"id", "room"},
+		{"id", "4"},
+		{"id", "0"},
+	} {
+		if _, err := SelectColumns(content, columns); err == nil {
+			t.Errorf("columns %v should be rejected", columns)
+		}
+	}
+}
+
+func TestSampleRows(t *testing.T) {
+	content := []byte("id,size\n")
+	other := []byte("price,id\n")
+	for i := 0; i < 1000; i++ {
+		content = append(content, []byte(fmt.Sprintf("%d,%d\n", i, i*10))...)
+		// the other party has the samples whose IDs aren't multiples of 3, in another order
+		if id := 999 - i; id%3 != 0 {
+			other = append(other, []byte(fmt.Sprintf("%d,%d\n", i*7, id))...)
+		}
+	}
+
+	sampled, err := SampleRows(content, "id", "task1", 0.2)
+	checkErr(err, t)
+	rows, ids, err := ReadIDsFromFileRows(sampled, "id")
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[0], []string{"id", "size"}) || len(ids) < 150 || len(ids) > 250 {
+		t.Fatalf("header %v, %d of 1000 samples kept with the fraction 0.2", rows[0], len(ids))
+	}
+
+	// both parties keep the same samples of the ones they share
+	otherSampled, err := SampleRows(other, "id", "task1", 0.2)
+	checkErr(err, t)
+	_, otherIDs, err := ReadIDsFromFileRows(otherSampled, "id")
+	checkErr(err, t)
+	kept := make(map[string]bool)
+	for _, id := range ids {
+		kept[id] = true
+	}
+	for _, id := range otherIDs {
+		if !kept[id] {
+			t.Errorf("sample %s kept by one party only", id)
+		}
+		delete(kept, id)
+	}
+	for id := range kept {
+		if i, _ := strconv.Atoi(id); i%3 != 0 {
+			t.Errorf("sample %s kept by one party only", id)
+		}
+	}
+
+	if _, err := SampleRows([]byte("id,size\n1,10\n"), "id", "task1", 1e-9); err == nil {
+		t.Error("empty subset should be rejected")
+	}
+}
+
+func TestFilterRows(t *testing.T) {
+	content := []byte("size,id\n10,1\n20,2\n30,3\n40,4\n")
+	filtered, err := FilterRows(content, "id", []string{"4", "2"})
+	checkErr(err, t)
+	if string(filtered) != "size,id\n20,2\n40,4\n" {
+		t.Errorf("unexpected filtered rows %q", filtered)
+	}
+
+	if _, err := FilterRows(content, "id", []string{"2", "5"}); err == nil {
+		t.Error("missing samples should be rejected")
+	}
+}
+
 func TestPSI(t *testing.T) {
 	idName := "id"
 
@@ -113,3 +196,37 @@ func checkErr(err error, t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestReadSparseRows(t *testing.T) {
+	content := []byte("id,x1,x2,x3\n0:1,3:3.5\n2,0,1,0\n0:3\n")
+	rows, err := ReadRowsFromFile(content)
+	checkErr(err, t)
+	want := [][]string{{"id", "x1", "x2", "x3"}, {"1", "0", "0", "3.5"}, {"2", "0", "1", "0"}, {"3", "0", "0", "0"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows: %v, want %v", rows, want)
+	}
+
+	if _, err := ReadRowsFromFile([]byte("id,x1,x2\n1,0\n")); err == nil {
+		t.Error("rows missing columns should be rejected")
+	}
+}
+
+func TestSparseRowsKept(t *testing.T) {
+	content := []byte("x1,id,x2,x3\n1:a,3:3.5\n0,b,1,0\n")
+
+	rows, ids, err := ReadIDsFromFileRows(content, "id")
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[1], []string{"1:a", "3:3.5"}) || !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("rows: %v, IDs: %v", rows, ids)
+	}
+
+	selected, err := SelectColumns(content, []string{"id", "x3", "x1"})
+	checkErr(err, t)
+	if string(selected) != "id,x3,x1\n0:a,1:3.5\nb,0,0\n" {
+		t.Errorf("unexpected selected rows %q", selected)
+	}
+
+	if _, _, err := ReadIDsFromFileRows([]byte("x1,id\n0:1\n"), "id"); err == nil {
+		t.Error("row omitting the ID should be rejected")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// dpRand generates the Gaussian noise of differential privacy, seeded by crypto/rand
+var dpRand = struct {
+	sync.Mutex
+	*mrand.Rand
+}{Rand: mrand.New(mrand.NewSource(dpSeed()))}
+
+func dpSeed() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// CheckDPParams checks the parameters of differential privacy
+func CheckDPParams(dp *pb_common.DPParams) error {
+	if dp.Epsilon <= 0 {
+		return errorx.New(errcodes.ErrCodeParam, "epsilon of differential privacy should be positive, got %v", dp.Epsilon)
+	}
+	if dp.Delta <= 0 || dp.Delta >= 1 {
+		return errorx.New(errcodes.ErrCodeParam, "delta of differential privacy should be in (0, 1), got %v", dp.Delta)
+	}
+	if dp.ClipNorm <= 0 {
+		return errorx.New(errcodes.ErrCodeParam, "clipNorm of differential privacy should be positive, got %v", dp.ClipNorm)
+	}
+	if dp.Rounds <= 0 {
+		return errorx.New(errcodes.ErrCodeParam, "rounds of differential privacy should be positive, got %d", dp.Rounds)
+	}
+	return nil
+}
+
+// DPNoiseSigma returns the standard deviation of the Gaussian noise added to the gradients in each round.
+// The budget is split evenly among the rounds by basic composition, so that each round is
+// (epsilon/rounds, delta/rounds)-differentially private with the sensitivity of clipNorm.
+func DPNoiseSigma(dp *pb_common.DPParams) float64 {
+	epsilon := dp.Epsilon / float64(dp.Rounds)
+	delta := dp.Delta / float64(dp.Rounds)
+	return dp.ClipNorm * math.Sqrt(2*math.Log(1.25/delta)) / epsilon
+}
+
+// PrivatizeGradient clips grads to the L2 norm of dp.ClipNorm and adds the Gaussian noise of DPNoiseSigma
+func PrivatizeGradient(grads []float64, dp *pb_common.DPParams) ([]float64, error) {
+	if err := CheckDPParams(dp); err != nil {
+		return nil, err
+	}
+
+	var norm float64
+	for _, grad := range grads {
+		norm += grad * grad
+	}
+	scale := 1.0
+	if norm = math.Sqrt(norm); norm > dp.ClipNorm {
+		scale = dp.ClipNorm / norm
+	}
+
+	sigma := DPNoiseSigma(dp)
+	noisy := make([]float64, len(grads))
+	dpRand.Lock()
+	defer dpRand.Unlock()
+	for i, grad := range grads {
+		noisy[i] = grad*scale + dpRand.NormFloat64()*sigma
+	}
+	return noisy, nil
+}
+
+// ConsumedPrivacyBudget returns the budget consumed by rounds of training with noise,
+// nil if differential privacy is disabled
+func ConsumedPrivacyBudget(dp *pb_common.DPParams, rounds int64) *pb_common.PrivacyBudget {
+	if dp == nil || dp.Rounds <= 0 {
+		return nil
+	}
+	if rounds > dp.Rounds {
+		rounds = dp.Rounds
+	}
+	return &pb_common.PrivacyBudget{
+		Epsilon: dp.Epsilon * float64(rounds) / float64(dp.Rounds),
+		Delta:   dp.Delta * float64(rounds) / float64(dp.Rounds),
+		Rounds:  rounds,
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestCheckDPParams(t *testing.T) {
+	checkErr(CheckDPParams(&pb_common.DPParams{Epsilon: 1, Delta: 1e-5, ClipNorm: 1, Rounds: 10}), t)
+
+	for _, dp := range []*pb_common.DPParams{
+		{Epsilon: 0, Delta: 1e-5, ClipNorm: 1, Rounds: 10},
+		{Epsilon: 1, Delta: 1, ClipNorm: 1, Rounds: 10},
+		{Epsilon: 1, Delta: 1e-5, ClipNorm: 0, Rounds: 10},
+		{Epsilon: 1, Delta: 1e-5, ClipNorm: 1, Rounds: 0},
+	} {
+		if err := CheckDPParams(dp); err == nil {
+			t.Errorf("invalid parameters %v should be rejected", dp)
+		}
+	}
+}
+
+func TestPrivatizeGradient(t *testing.T) {
+	dp := &pb_common.DPParams{Epsilon: 1, Delta: 0.1, ClipNorm: 1, Rounds: 1}
+	want := math.Sqrt(2 * math.Log(12.5))
+	if sigma := DPNoiseSigma(dp); math.Abs(sigma-want) > 1e-9 {
+		t.Errorf("sigma of noise: %v, want %v", sigma, want)
+	}
+
+	// noise is negligible with huge epsilon, so that only clipping takes effect
+	dp.Epsilon = 1e12
+	grads, err := PrivatizeGradient([]float64{3, 4}, dp)
+	checkErr(err, t)
+	if math.Abs(grads[0]-0.6) > 1e-6 || math.Abs(grads[1]-0.8) > 1e-6 {
+		t.Errorf("gradients should be clipped to the norm 1: %v", grads)
+	}
+	grads, err = PrivatizeGradient([]float64{0.3, 0.4}, dp)
+	checkErr(err, t)
+	if math.Abs(grads[0]-0.3) > 1e-6 || math.Abs(grads[1]-0.4) > 1e-6 {
+		t.Errorf("gradients within the norm should be kept: %v", grads)
+	}
+
+	// the noise varies from round to round
+	dp.Epsilon = 1
+	grads1, err := PrivatizeGradient([]float64{0.3, 0.4}, dp)
+	checkErr(err, t)
+	grads2, err := PrivatizeGradient([]float64{0.3, 0.4}, dp)
+	checkErr(err, t)
+	if grads1[0] == grads2[0] && grads1[1] == grads2[1] {
+		t.Error("gradients should be added random noise")
+	}
+}
+
+func TestConsumedPrivacyBudget(t *testing.T) {
+	if b := ConsumedPrivacyBudget(nil, 10); b != nil {
+		t.Errorf("budget consumed without differential privacy: %v", b)
+	}
+	dp := &pb_common.DPParams{Epsilon: 2, Delta: 1e-4, ClipNorm: 1, Rounds: 100}
+	b := ConsumedPrivacyBudget(dp, 25)
+	if b.Rounds != 25 || math.Abs(b.Epsilon-0.5) > 1e-9 || math.Abs(b.Delta-2.5e-5) > 1e-12 {
+		t.Errorf("budget consumed by 25 rounds: %v", b)
+	}
+	if b := ConsumedPrivacyBudget(dp, 200); b.Rounds != 100 || b.Epsilon != dp.Epsilon {
+		t.Errorf("budget consumed should not exceed the one of the task: %v", b)
+	}
+}
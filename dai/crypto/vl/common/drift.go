@@ -0,0 +1,158 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"strconv"
+
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// SummarizeTrainDataSet summarizes the original columns of trainDataSet, which are stored with the model
+// to detect drift in prediction. The columns of the tag part start from the third one, after the ID and
+// the intercept, and its last column is the label.
+func SummarizeTrainDataSet(trainDataSet *ml_common.TrainDataSet, isTagPart bool) map[string]*pb_common.FeatureSummary {
+	rows := trainDataSet.OriginalTrainSet
+	if len(rows) == 0 {
+		return nil
+	}
+	offset := 1
+	if isTagPart {
+		offset = 2
+	}
+	summaries := make(map[string]*pb_common.FeatureSummary, len(trainDataSet.FeatureNames))
+	for i, name := range trainDataSet.FeatureNames {
+		column := offset + i
+		if isTagPart && i == len(trainDataSet.FeatureNames)-1 {
+			// the label is always the last column
+			column = len(rows[0]) - 1
+		}
+		values := make([]float64, len(rows))
+		for j, row := range rows {
+			values[j] = row[column]
+		}
+		summaries[name] = Summarize(values)
+	}
+	return summaries
+}
+
+// Summarize returns the mean, standard deviation, minimum and maximum of values
+func Summarize(values []float64) *pb_common.FeatureSummary {
+	if len(values) == 0 {
+		return &pb_common.FeatureSummary{}
+	}
+	s := &pb_common.FeatureSummary{Min: values[0], Max: values[0]}
+	for _, v := range values {
+		s.Mean += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Mean /= float64(len(values))
+	for _, v := range values {
+		s.Std += (v - s.Mean) * (v - s.Mean)
+	}
+	s.Std = math.Sqrt(s.Std / float64(len(values)))
+	return s
+}
+
+// summarizeSparse returns the summary of num values, which are values followed by the zeros omitted in sparse rows
+func summarizeSparse(values []float64, num int) *pb_common.FeatureSummary {
+	zeros := num - len(values)
+	if zeros == 0 {
+		return Summarize(values)
+	}
+	s := &pb_common.FeatureSummary{}
+	for _, v := range values {
+		s.Mean += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Mean /= float64(num)
+	for _, v := range values {
+		s.Std += (v - s.Mean) * (v - s.Mean)
+	}
+	s.Std += float64(zeros) * s.Mean * s.Mean
+	s.Std = math.Sqrt(s.Std / float64(num))
+	return s
+}
+
+// DriftScore returns the shift of the mean of batch from the one of train in standard deviations of train,
+// the columns constant in training are scored by the absolute shift of their means
+func DriftScore(train, batch *pb_common.FeatureSummary) float64 {
+	std := train.Std
+	if std == 0 {
+		std = 1
+	}
+	return math.Abs(batch.Mean-train.Mean) / std
+}
+
+// DetectDrift compares the distributions of the local columns of the samples predicted with the training ones
+// stored with model, predictions are compared with the label on the party with label. fileRows is sample rows,
+// first row is feature list, others are values for each sample. A nil report is returned if model.DriftThreshold
+// is 0 or the model has no training distributions, which is the case of the models trained before.
+func DetectDrift(model *pb_common.TrainModels, fileRows [][]string, predictions []float64) (*pb_common.DriftReport, error) {
+	if model.DriftThreshold <= 0 || len(model.Summaries) == 0 || len(fileRows) < 2 {
+		return nil, nil
+	}
+	report := &pb_common.DriftReport{
+		Threshold: model.DriftThreshold,
+		Scores:    make(map[string]float64),
+		Summaries: make(map[string]*pb_common.FeatureSummary),
+	}
+	add := func(name string, summary *pb_common.FeatureSummary) {
+		score := DriftScore(model.Summaries[name], summary)
+		report.Summaries[name] = summary
+		report.Scores[name] = score
+		if score > model.DriftThreshold {
+			report.Drifted = true
+		}
+	}
+
+	// values of the columns compared, sparse rows only have the values present, the others are 0
+	columns := make(map[int][]float64)
+	for j, name := range fileRows[0] {
+		// the label in the samples to predict is ignored, the predictions are compared instead
+		if _, ok := model.Summaries[name]; !ok || (model.IsTagPart && name == model.Label) {
+			continue
+		}
+		columns[j] = nil
+	}
+	for i := 1; i < len(fileRows); i++ {
+		indices, cells := RowCells(fileRows[i], len(fileRows[0]))
+		for k, j := range indices {
+			if _, ok := columns[j]; !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(cells[k], 64)
+			if err != nil {
+				return nil, errorx.New(errcodes.ErrCodeParam, "failed to parse value of feature %s: %s", fileRows[0][j], err.Error())
+			}
+			columns[j] = append(columns[j], value)
+		}
+	}
+	for j, name := range fileRows[0] {
+		if values, ok := columns[j]; ok {
+			add(name, summarizeSparse(values, len(fileRows)-1))
+		}
+	}
+	if _, ok := model.Summaries[model.Label]; ok && model.IsTagPart && len(predictions) > 0 {
+		add(model.Label, Summarize(predictions))
+	}
+	return report, nil
+}
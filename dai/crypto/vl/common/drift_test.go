@@ -0,0 +1,120 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"testing"
+
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestSummarizeTrainDataSet(t *testing.T) {
+	// rows of the tag part are ID, intercept, features and the label
+	trainDataSet := &ml_common.TrainDataSet{
+		FeatureNames: []string{"a", "b", "y"},
+		OriginalTrainSet: [][]float64{
+			{0, 1, 1, 10, 100},
+			{1, 1, 3, 10, 200},
+		},
+	}
+	summaries := SummarizeTrainDataSet(trainDataSet, true)
+	expected := map[string]*pb_common.FeatureSummary{
+		"a": {Mean: 2, Std: 1, Min: 1, Max: 3},
+		"b": {Mean: 10, Std: 0, Min: 10, Max: 10},
+		"y": {Mean: 150, Std: 50, Min: 100, Max: 200},
+	}
+	for name, e := range expected {
+		s := summaries[name]
+		if s == nil || s.Mean != e.Mean || s.Std != e.Std || s.Min != e.Min || s.Max != e.Max {
+			t.Errorf("expected summary %v of %s, got %v", e, name, s)
+		}
+	}
+
+	// rows of the no-tag part are ID and features
+	trainDataSet = &ml_common.TrainDataSet{
+		FeatureNames:     []string{"c"},
+		OriginalTrainSet: [][]float64{{0, 4}, {1, 6}},
+	}
+	if s := SummarizeTrainDataSet(trainDataSet, false)["c"]; s.Mean != 5 || s.Std != 1 {
+		t.Errorf("unexpected summary %v of c", s)
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	model := &pb_common.TrainModels{
+		Label:     "y",
+		IsTagPart: true,
+		Summaries: map[string]*pb_common.FeatureSummary{
+			"a": {Mean: 2, Std: 1},
+			"b": {Mean: 10, Std: 0},
+			"y": {Mean: 150, Std: 50},
+		},
+		DriftThreshold: 2,
+	}
+	fileRows := [][]string{
+		{"a", "b", "y"},
+		{"1", "10", "0"},
+		{"3", "10", "0"},
+	}
+	report, err := DetectDrift(model, fileRows, []float64{140, 160})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted || len(report.Scores) != 3 || report.Scores["a"] != 0 || report.Scores["y"] != 0 {
+		t.Errorf("expected no drift, got %v", report)
+	}
+
+	// the mean of a shifts by 3 standard deviations, and the one of the constant b by 1
+	fileRows = [][]string{
+		{"a", "b"},
+		{"4", "11"},
+		{"6", "11"},
+	}
+	report, err = DetectDrift(model, fileRows, []float64{150, 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Drifted || math.Abs(report.Scores["a"]-3) > 1e-12 || report.Scores["b"] != 1 || report.Summaries["a"].Mean != 5 {
+		t.Errorf("expected drift of a, got %v", report)
+	}
+
+	// the features omitted in sparse rows are 0
+	fileRows = [][]string{
+		{"a", "b"},
+		{"0:4", "1:11"},
+		{"0:6", "1:11"},
+		{"1:11"},
+		{"0:6", "1:11"},
+	}
+	report, err = DetectDrift(model, fileRows, []float64{150, 150, 150, 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Summaries["a"].Mean != 4 || report.Summaries["a"].Min != 0 || report.Summaries["b"].Mean != 11 {
+		t.Errorf("unexpected summaries of sparse rows %v", report.Summaries)
+	}
+
+	// drift is not detected without threshold or training distributions
+	model.DriftThreshold = 0
+	if report, err := DetectDrift(model, fileRows, nil); report != nil || err != nil {
+		t.Errorf("expected no report without threshold, got %v, %v", report, err)
+	}
+	model.DriftThreshold = 2
+	if _, err := DetectDrift(model, [][]string{{"a"}, {"x"}}, nil); err == nil {
+		t.Error("expected error for invalid values")
+	}
+}
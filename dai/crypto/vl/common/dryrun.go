@@ -0,0 +1,91 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+const (
+	// DefaultDryRunFraction is the fraction of the samples in the subset of the dry run if not set
+	DefaultDryRunFraction = 0.05
+	// DefaultDryRunIterations is the number of iterations of the dry run if not set
+	DefaultDryRunIterations = 2
+	// MaxDryRunIterations is the maximum number of iterations of the dry run, which is meant to be quick
+	MaxDryRunIterations = 10
+
+	// dryRunDivergence is how many times the cost of the initial thetas the cost of the dry run may grow to
+	dryRunDivergence = 2
+)
+
+// CheckDryRunParams checks the parameters of the dry run
+func CheckDryRunParams(dr *pb_common.DryRunParams) error {
+	if dr.Fraction < 0 || dr.Fraction >= 1 {
+		return errorx.New(errcodes.ErrCodeParam, "fraction of dry run should be in (0, 1), got %v", dr.Fraction)
+	}
+	if dr.Iterations < 0 || dr.Iterations > MaxDryRunIterations {
+		return errorx.New(errcodes.ErrCodeParam, "iterations of dry run should be in [1, %d], got %d",
+			MaxDryRunIterations, dr.Iterations)
+	}
+	return nil
+}
+
+// DryRunFraction returns the fraction of the samples in the subset of the dry run
+func DryRunFraction(dr *pb_common.DryRunParams) float64 {
+	if dr.GetFraction() == 0 {
+		return DefaultDryRunFraction
+	}
+	return dr.GetFraction()
+}
+
+// DryRunIterations returns the number of iterations of the dry run
+func DryRunIterations(dr *pb_common.DryRunParams) int64 {
+	if dr.GetIterations() == 0 {
+		return DefaultDryRunIterations
+	}
+	return dr.GetIterations()
+}
+
+// InDryRunSubset returns whether the sample of id is in the subset of the dry run of the task seed.
+// It depends on the hash of seed and id only, so all parties of the task keep the same samples
+// without communication, and the aligned samples of the subset are the subset of the aligned samples
+func InDryRunSubset(seed, id string, fraction float64) bool {
+	h := sha256.Sum256([]byte(seed + "/" + id))
+	return float64(binary.BigEndian.Uint64(h[:8])) < fraction*math.Pow(2, 64)
+}
+
+// CheckDryRunCost fails the dry run if the cost or the thetas become NaN or Inf,
+// or the cost grows beyond dryRunDivergence times initialCost, the cost of the initial thetas
+func CheckDryRunCost(initialCost, cost float64, thetas []float64) error {
+	if math.IsNaN(cost) || math.IsInf(cost, 0) {
+		return errorx.New(errcodes.ErrCodeDryRun, "cost becomes %v", cost)
+	}
+	for i, theta := range thetas {
+		if math.IsNaN(theta) || math.IsInf(theta, 0) {
+			return errorx.New(errcodes.ErrCodeDryRun, "theta %d becomes %v", i, theta)
+		}
+	}
+	// costs of all the losses are non-negative, a zero initial cost can't be improved
+	if initialCost > 0 && cost > dryRunDivergence*initialCost {
+		return errorx.New(errcodes.ErrCodeDryRun, "cost diverges from %v to %v, try a smaller learning rate", initialCost, cost)
+	}
+	return nil
+}
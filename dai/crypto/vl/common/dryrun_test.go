@@ -0,0 +1,85 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestCheckDryRunParams(t *testing.T) {
+	checkErr(CheckDryRunParams(&pb_common.DryRunParams{}), t)
+	checkErr(CheckDryRunParams(&pb_common.DryRunParams{Fraction: 0.1, Iterations: 3}), t)
+
+	for _, dr := range []*pb_common.DryRunParams{
+		{Fraction: -0.1},
+		{Fraction: 1},
+		{Iterations: -1},
+		{Iterations: MaxDryRunIterations + 1},
+	} {
+		if err := CheckDryRunParams(dr); err == nil {
+			t.Errorf("invalid parameters %v should be rejected", dr)
+		}
+	}
+
+	dr := &pb_common.DryRunParams{}
+	if DryRunFraction(dr) != DefaultDryRunFraction || DryRunIterations(dr) != DefaultDryRunIterations {
+		t.Errorf("defaults of dry run: %v, %v", DryRunFraction(dr), DryRunIterations(dr))
+	}
+}
+
+func TestInDryRunSubset(t *testing.T) {
+	n, kept := 10000, 0
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		in := InDryRunSubset("task1", id, 0.1)
+		if in != InDryRunSubset("task1", id, 0.1) {
+			t.Fatalf("sample %s selected inconsistently", id)
+		}
+		// a larger fraction keeps the samples of a smaller one
+		if in && !InDryRunSubset("task1", id, 0.2) {
+			t.Fatalf("sample %s not kept with a larger fraction", id)
+		}
+		if in {
+			kept++
+		}
+	}
+	if kept < 900 || kept > 1100 {
+		t.Errorf("%d of %d samples kept with the fraction 0.1", kept, n)
+	}
+}
+
+func TestCheckDryRunCost(t *testing.T) {
+	checkErr(CheckDryRunCost(1, 0.8, []float64{0.1, -0.2}), t)
+	checkErr(CheckDryRunCost(1, 1.5, []float64{0.1, -0.2}), t)
+	checkErr(CheckDryRunCost(0, 1.5, []float64{0.1, -0.2}), t)
+
+	for _, c := range []struct {
+		cost   float64
+		thetas []float64
+	}{
+		{math.NaN(), []float64{0.1}},
+		{math.Inf(1), []float64{0.1}},
+		{0.8, []float64{math.NaN()}},
+		{0.8, []float64{math.Inf(-1)}},
+		{3, []float64{0.1}},
+	} {
+		if err := CheckDryRunCost(1, c.cost, c.thetas); err == nil {
+			t.Errorf("cost %v with thetas %v should fail the dry run", c.cost, c.thetas)
+		}
+	}
+}
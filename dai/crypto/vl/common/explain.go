@@ -0,0 +1,150 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+const (
+	// BaseColumn is the column of explained predict results holding the prediction of the sample
+	// whose features are all at their means, to which the contributions of features are added
+	BaseColumn = "base"
+	// ContributionPrefix prefixes the columns of explained predict results holding the contributions of features
+	ContributionPrefix = "contrib_"
+)
+
+// FeatureContributions returns the contributions of the local features of model to the local predictions of the
+// samples of fileRows, whose first row is the list of columns, in either dense or sparse rows.
+// The contribution of a feature is theta*(x-xbar)/sigma, the term it adds to the local prediction, so the local
+// prediction of a sample is the sum of its contributions plus the intercept on the party with label. As features are
+// centered by the means of the training samples, it is the Shapley value of the feature of a linear model.
+// features are the features of the model in the order of the columns, and contributions[i][j] is the contribution of
+// features[j] to the prediction of sample i. Only the contributions leave the party, never the values of features.
+func FeatureContributions(fileRows [][]string, model *pb_common.TrainModels) (features []string, contributions [][]float64, err error) {
+	if len(fileRows) == 0 {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "no samples to explain")
+	}
+	header := fileRows[0]
+	// index of each feature in features by its column
+	indices := make(map[int]int)
+	// zero is the contribution of each feature whose value is 0, which sparse rows omit
+	var zero []float64
+	for j, name := range header {
+		theta, ok := model.Thetas[name]
+		// weights of samples are only used in training
+		if !ok || name == "Intercept" || name == model.WeightColumn {
+			continue
+		}
+		indices[j] = len(features)
+		features = append(features, name)
+		zero = append(zero, theta*(0-model.Xbars[name])/model.Sigmas[name])
+	}
+
+	contributions = make([][]float64, len(fileRows)-1)
+	for i := 1; i < len(fileRows); i++ {
+		row := make([]float64, len(features))
+		copy(row, zero)
+		cols, cells := RowCells(fileRows[i], len(header))
+		for k, j := range cols {
+			f, ok := indices[j]
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(cells[k], 64)
+			if err != nil {
+				return nil, nil, errorx.New(errcodes.ErrCodeParam, "failed to parse value of feature %s: %s", header[j], err.Error())
+			}
+			name := features[f]
+			row[f] = model.Thetas[name] * (value - model.Xbars[name]) / model.Sigmas[name]
+		}
+		contributions[i-1] = row
+	}
+	return features, contributions, nil
+}
+
+// FlattenContributions flattens contributions row by row of samples, to be sent to the other party
+func FlattenContributions(contributions [][]float64) []float64 {
+	var flat []float64
+	for _, row := range contributions {
+		flat = append(flat, row...)
+	}
+	return flat
+}
+
+// UnflattenContributions restores the contributions of featureNum features to the predictions of sampleNum samples
+// flattened by FlattenContributions
+func UnflattenContributions(flat []float64, sampleNum, featureNum int) ([][]float64, error) {
+	if len(flat) != sampleNum*featureNum {
+		return nil, errorx.New(errcodes.ErrCodeParam, "expected contributions of %d features to %d samples, got %d values",
+			featureNum, sampleNum, len(flat))
+	}
+	contributions := make([][]float64, sampleNum)
+	for i := range contributions {
+		contributions[i] = flat[i*featureNum : (i+1)*featureNum]
+	}
+	return contributions, nil
+}
+
+// AppendExplanations appends the explanations of the predictions to the predict result content encoded by
+// PredictResultToBytes or PredictResultWithScoresToBytes, which are the column BaseColumn, and a column prefixed with
+// ContributionPrefix for each of features. base plus the contributions of a sample is its prediction, contributions[i]
+// are the ones of the sample of row i+1 of content, in the order of features.
+func AppendExplanations(content []byte, base float64, features []string, contributions [][]float64) ([]byte, error) {
+	var fileRows [][]string
+	if err := json.Unmarshal(content, &fileRows); err != nil {
+		return nil, errorx.New(errcodes.ErrCodeEncoding, "decode predict results failed: %s", err.Error())
+	}
+	if len(fileRows) != len(contributions)+1 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "predict values and explanations numbers are not equal")
+	}
+
+	header := append(fileRows[0], BaseColumn)
+	for _, feature := range features {
+		header = append(header, ContributionPrefix+feature)
+	}
+	fileRows[0] = header
+	baseValue := strconv.FormatFloat(base, 'g', -1, 64)
+	for i, row := range contributions {
+		if len(row) != len(features) {
+			return nil, errorx.New(errcodes.ErrCodeParam, "expected contributions of %d features, got %d", len(features), len(row))
+		}
+		fileRows[i+1] = append(fileRows[i+1], baseValue)
+		for _, c := range row {
+			fileRows[i+1] = append(fileRows[i+1], strconv.FormatFloat(c, 'g', -1, 64))
+		}
+	}
+
+	explained, err := json.Marshal(fileRows)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeEncoding, "encode predict results failed: %s", err.Error())
+	}
+	return explained, nil
+}
+
+// JoinContributions joins the contributions of the local features and the ones of the features of the other party
+// sample by sample, local and other are of the same samples
+func JoinContributions(local, other [][]float64) [][]float64 {
+	joined := make([][]float64, len(local))
+	for i := range local {
+		joined[i] = append(append([]float64{}, local[i]...), other[i]...)
+	}
+	return joined
+}
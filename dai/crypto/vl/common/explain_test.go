@@ -0,0 +1,111 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestFeatureContributions(t *testing.T) {
+	model := &pb_common.TrainModels{
+		Thetas:       map[string]float64{"Intercept": 0.5, "x1": 2, "x2": -1, "w": 3},
+		Xbars:        map[string]float64{"x1": 1, "x2": 4},
+		Sigmas:       map[string]float64{"x1": 2, "x2": 0.5},
+		WeightColumn: "w",
+		IsTagPart:    true,
+	}
+	dense := [][]string{
+		{"id", "x2", "x1", "w"},
+		{"a", "4.5", "3", "2"},
+		{"b", "0", "0", "1"},
+	}
+	features, contributions, err := FeatureContributions(dense, model)
+	checkErr(err, t)
+	// features in the order of the columns, without the weight column
+	if !reflect.DeepEqual(features, []string{"x2", "x1"}) {
+		t.Errorf("features: %v, want [x2 x1]", features)
+	}
+	want := [][]float64{{-1, 2}, {8, -1}}
+	if !reflect.DeepEqual(contributions, want) {
+		t.Errorf("contributions: %v, want %v", contributions, want)
+	}
+
+	// the intercept plus the contributions is the local prediction, omitted cells of sparse rows are 0
+	sparse := SparsifyRows(dense, "id")
+	_, sparseContributions, err := FeatureContributions(sparse, model)
+	checkErr(err, t)
+	if !reflect.DeepEqual(sparseContributions, want) {
+		t.Errorf("contributions of sparse rows: %v, want %v", sparseContributions, want)
+	}
+	predictions, err := PredictSparseRows(sparse, model)
+	checkErr(err, t)
+	for i, row := range contributions {
+		sum := model.Thetas["Intercept"]
+		for _, c := range row {
+			sum += c
+		}
+		if math.Abs(sum-predictions[i]) > 1e-9 {
+			t.Errorf("sample %d: contributions sum to %v, predicted %v", i, sum, predictions[i])
+		}
+	}
+
+	if _, _, err := FeatureContributions([][]string{{"id", "x1"}, {"a", "x"}}, model); err == nil {
+		t.Error("expected error if a value is not a number")
+	}
+}
+
+func TestExplanations(t *testing.T) {
+	local := [][]float64{{1, 2}, {3, 4}}
+	other := [][]float64{{5}, {6}}
+	flat := FlattenContributions(other)
+	if _, err := UnflattenContributions(flat, 2, 2); err == nil {
+		t.Error("expected error if the number of contributions is wrong")
+	}
+	restored, err := UnflattenContributions(flat, 2, 1)
+	checkErr(err, t)
+	joined := JoinContributions(local, restored)
+	if !reflect.DeepEqual(joined, [][]float64{{1, 2, 5}, {3, 4, 6}}) {
+		t.Errorf("joined contributions: %v", joined)
+	}
+	// joining doesn't change the local contributions
+	if !reflect.DeepEqual(local, [][]float64{{1, 2}, {3, 4}}) {
+		t.Errorf("local contributions changed: %v", local)
+	}
+
+	content, err := PredictResultToBytes("id", []string{"a", "b"}, []float64{8.5, 13.5})
+	checkErr(err, t)
+	content, err = AppendExplanations(content, 0.5, []string{"x1", "x2", "y1"}, joined)
+	checkErr(err, t)
+	rows, err := PredictResultFromBytes(content)
+	checkErr(err, t)
+	want := [][]string{
+		{"id", "value", "base", "contrib_x1", "contrib_x2", "contrib_y1"},
+		{"a", "8.5", "0.5", "1", "2", "5"},
+		{"b", "13.5", "0.5", "3", "4", "6"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("explained predict results: %v, want %v", rows, want)
+	}
+
+	if _, err := AppendExplanations(content, 0, []string{"x1"}, joined[:1]); err == nil {
+		t.Error("expected error if the numbers of predictions and explanations are not equal")
+	}
+	if _, err := AppendExplanations(content, 0, []string{"x1"}, joined); err == nil {
+		t.Error("expected error if the numbers of features and contributions are not equal")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// StandardizeByModel scales the original features of dataSet by the scaling parameters
+// of the base model instead of the ones of dataSet, so that the thetas of the base model apply to dataSet
+// in incremental training. Feature unstandardized is kept as it is, such as the label of logistic regression.
+// The features of dataSet should be the same as the ones the base model is trained with.
+func StandardizeByModel(dataSet *ml_common.StandardizedDataSet, model *pb_common.TrainModels, unstandardized string) error {
+	if len(dataSet.OriginalFeatures) != len(model.Xbars) {
+		return errorx.New(errcodes.ErrCodeParam, "the base model is trained with %d features, got %d",
+			len(model.Xbars), len(dataSet.OriginalFeatures))
+	}
+	for _, feature := range dataSet.OriginalFeatures {
+		if _, ok := model.Xbars[feature.FeatureName]; !ok {
+			return errorx.New(errcodes.ErrCodeParam, "feature %s is not in the base model", feature.FeatureName)
+		}
+	}
+	rescale(dataSet, model.Xbars, model.Sigmas, unstandardized)
+	return nil
+}
+
+// ThetasFromModel returns the thetas of the base model in the order of the features of trainDataSet,
+// which is the reverse of thetasToMap, the thetas of the tag part start with the intercept
+func ThetasFromModel(model *pb_common.TrainModels, trainDataSet *ml_common.TrainDataSet, isTagPart bool) ([]float64, error) {
+	names := trainDataSet.FeatureNames
+	var thetas []float64
+	if isTagPart {
+		// the last feature of the tag part is the label
+		names = names[:len(names)-1]
+		thetas = append(thetas, model.Thetas["Intercept"])
+	}
+	for _, name := range names {
+		theta, ok := model.Thetas[name]
+		if !ok {
+			return nil, errorx.New(errcodes.ErrCodeParam, "feature %s is not in the base model", name)
+		}
+		thetas = append(thetas, theta)
+	}
+	if len(thetas) != len(model.Thetas) {
+		return nil, errorx.New(errcodes.ErrCodeParam, "the base model has %d parameters, got %d", len(model.Thetas), len(thetas))
+	}
+	return thetas, nil
+}
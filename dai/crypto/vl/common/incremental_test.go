@@ -0,0 +1,71 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestStandardizeByModel(t *testing.T) {
+	model := &pb_common.TrainModels{
+		Xbars:  map[string]float64{"x": 2, "y": 1},
+		Sigmas: map[string]float64{"x": 4, "y": 2},
+	}
+	dataSet := &ml_common.StandardizedDataSet{
+		OriginalFeatures: []*ml_common.DataFeature{
+			{FeatureName: "x", Sets: map[int]float64{0: 6, 1: -2}},
+			{FeatureName: "y", Sets: map[int]float64{0: 1, 1: 0}},
+		},
+		Features: make([]*ml_common.DataFeature, 2),
+	}
+	err := StandardizeByModel(dataSet, model, "y")
+	checkErr(err, t)
+
+	if !reflect.DeepEqual(dataSet.Features[0].Sets, map[int]float64{0: 1, 1: -1}) {
+		t.Errorf("feature x standardized by the base model: %v", dataSet.Features[0].Sets)
+	}
+	if dataSet.Features[1] != nil {
+		t.Errorf("feature y should be kept as it is, got: %v", dataSet.Features[1])
+	}
+	if !reflect.DeepEqual(dataSet.XbarParams, model.Xbars) || !reflect.DeepEqual(dataSet.SigmaParams, model.Sigmas) {
+		t.Error("means and standard deviations should be the ones of the base model")
+	}
+
+	dataSet.OriginalFeatures[1].FeatureName = "z"
+	if err := StandardizeByModel(dataSet, model, ""); err == nil {
+		t.Error("feature not in the base model should be rejected")
+	}
+}
+
+func TestThetasFromModel(t *testing.T) {
+	trainDataSet := &ml_common.TrainDataSet{FeatureNames: []string{"x", "z", "label"}}
+	thetas := []float64{0.5, 1.5, -2}
+	model := &pb_common.TrainModels{Thetas: thetasToMap(thetas, trainDataSet, true)}
+
+	got, err := ThetasFromModel(model, trainDataSet, true)
+	checkErr(err, t)
+	if !reflect.DeepEqual(got, thetas) {
+		t.Errorf("thetas of tag part: %v, want %v", got, thetas)
+	}
+
+	trainDataSet = &ml_common.TrainDataSet{FeatureNames: []string{"x", "z"}}
+	if _, err := ThetasFromModel(model, trainDataSet, false); err == nil {
+		t.Error("thetas of a different part should be rejected")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// kernelSlots holds the slots of the worker pool shared by the numeric kernels of all tasks on the node,
+// a kernel holds a slot while it computes one item, nil means no limit
+var kernelSlots atomic.Value // chan struct{}
+
+// SetKernelWorkers sets the maximum number of numeric kernel items computed at the same time on the node,
+// whatever the number of tasks is, zero means no limit. It's independent of GOMAXPROCS, and is called
+// before any task runs
+func SetKernelWorkers(n int) {
+	var slots chan struct{}
+	if n > 0 {
+		slots = make(chan struct{}, n)
+	}
+	kernelSlots.Store(slots)
+}
+
+// KernelWorkers returns the maximum number of numeric kernel items computed at the same time, zero means no limit
+func KernelWorkers() int {
+	slots, _ := kernelSlots.Load().(chan struct{})
+	return cap(slots)
+}
+
+// Parallel calls f with 0 to n-1 in at most workers goroutines, zero workers means GOMAXPROCS.
+// Each call of f holds a slot of the kernel worker pool, goroutines waiting for a slot are served
+// in FIFO order, so concurrent tasks share the pool fairly item by item
+func Parallel(n, workers int, f func(i int)) {
+	slots, _ := kernelSlots.Load().(chan struct{})
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if slots != nil && workers > cap(slots) {
+		workers = cap(slots)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	next := make(chan int, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if slots != nil {
+					slots <- struct{}{}
+				}
+				f(i)
+				if slots != nil {
+					<-slots
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKernelWorkers(t *testing.T) {
+	defer SetKernelWorkers(0)
+	SetKernelWorkers(2)
+	if KernelWorkers() != 2 {
+		t.Fatalf("expected 2 kernel workers, got %d", KernelWorkers())
+	}
+
+	// concurrent tasks never compute more items than the kernel workers at the same time
+	var running, peak int32
+	done := make([][]bool, 4)
+	var wg sync.WaitGroup
+	for task := range done {
+		done[task] = make([]bool, 20)
+		wg.Add(1)
+		go func(task int) {
+			defer wg.Done()
+			Parallel(len(done[task]), 0, func(i int) {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				done[task][i] = true
+				atomic.AddInt32(&running, -1)
+			})
+		}(task)
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("expected at most 2 items computed at the same time, got %d", peak)
+	}
+	for task := range done {
+		for i, ok := range done[task] {
+			if !ok {
+				t.Errorf("item %d of task %d not computed", i, task)
+			}
+		}
+	}
+
+	SetKernelWorkers(0)
+	if KernelWorkers() != 0 {
+		t.Fatalf("expected no limit, got %d", KernelWorkers())
+	}
+	sum := int64(0)
+	Parallel(100, 0, func(i int) { atomic.AddInt64(&sum, int64(i)) })
+	if sum != 4950 {
+		t.Errorf("expected sum 4950, got %d", sum)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// Loss functions of linear regression, r is the residual, which is the prediction minus the label
+const (
+	LossSquared  = "squared"  // r^2/2, the default
+	LossHuber    = "huber"    // r^2/2 if |r| <= delta, delta*(|r|-delta/2) otherwise
+	LossQuantile = "quantile" // (1-tau)*r if r > 0, -tau*r otherwise
+)
+
+// CheckLoss checks the loss function and its parameters, nil or empty type means LossSquared
+func CheckLoss(loss *pb_common.LossParams) error {
+	switch loss.GetType() {
+	case "", LossSquared:
+	case LossHuber:
+		if loss.Delta <= 0 || math.IsInf(loss.Delta, 0) {
+			return errorx.New(errcodes.ErrCodeParam, "delta of huber loss should be positive, got %v", loss.Delta)
+		}
+	case LossQuantile:
+		if loss.Tau <= 0 || loss.Tau >= 1 {
+			return errorx.New(errcodes.ErrCodeParam, "tau of quantile loss should be in (0, 1), got %v", loss.Tau)
+		}
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "invalid loss %s, should be %s, %s or %s",
+			loss.Type, LossSquared, LossHuber, LossQuantile)
+	}
+	return nil
+}
+
+// IsSquaredLoss returns whether loss is squared error, which is the case of nil
+func IsSquaredLoss(loss *pb_common.LossParams) bool {
+	return loss.GetType() == "" || loss.GetType() == LossSquared
+}
+
+// LossValue returns twice the loss of residual r, so that it is r^2 for squared error,
+// as the costs of samples are halved in their mean
+func LossValue(loss *pb_common.LossParams, r float64) float64 {
+	switch loss.GetType() {
+	case LossHuber:
+		if math.Abs(r) <= loss.Delta {
+			return r * r
+		}
+		return 2*loss.Delta*math.Abs(r) - loss.Delta*loss.Delta
+	case LossQuantile:
+		if r > 0 {
+			return 2 * (1 - loss.Tau) * r
+		}
+		return -2 * loss.Tau * r
+	default:
+		return r * r
+	}
+}
+
+// PseudoResidual returns the derivative of the loss of residual r, which takes the place of r
+// in the gradients of squared error
+func PseudoResidual(loss *pb_common.LossParams, r float64) float64 {
+	switch loss.GetType() {
+	case LossHuber:
+		return math.Max(-loss.Delta, math.Min(loss.Delta, r))
+	case LossQuantile:
+		if r > 0 {
+			return 1 - loss.Tau
+		}
+		return -loss.Tau
+	default:
+		return r
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestCheckLoss(t *testing.T) {
+	for _, loss := range []*pb_common.LossParams{
+		nil,
+		{},
+		{Type: LossSquared},
+		{Type: LossHuber, Delta: 1},
+		{Type: LossQuantile, Tau: 0.9},
+	} {
+		checkErr(CheckLoss(loss), t)
+	}
+
+	for _, loss := range []*pb_common.LossParams{
+		{Type: "absolute"},
+		{Type: LossHuber},
+		{Type: LossHuber, Delta: -1},
+		{Type: LossQuantile, Tau: 0},
+		{Type: LossQuantile, Tau: 1},
+	} {
+		if err := CheckLoss(loss); err == nil {
+			t.Errorf("invalid loss %v should be rejected", loss)
+		}
+	}
+}
+
+func TestLossValueAndPseudoResidual(t *testing.T) {
+	huber := &pb_common.LossParams{Type: LossHuber, Delta: 1}
+	quantile := &pb_common.LossParams{Type: LossQuantile, Tau: 0.8}
+	cases := []struct {
+		loss   *pb_common.LossParams
+		r      float64
+		value  float64
+		pseudo float64
+	}{
+		{nil, -3, 9, -3},
+		{huber, 0.5, 0.25, 0.5},
+		{huber, -3, 5, -1},
+		{huber, 3, 5, 1},
+		{quantile, 2, 0.8, 0.2},
+		{quantile, -2, 3.2, -0.8},
+	}
+	for _, c := range cases {
+		if v := LossValue(c.loss, c.r); math.Abs(v-c.value) > 1e-9 {
+			t.Errorf("expected loss value %v of %v with %v, got %v", c.value, c.r, c.loss, v)
+		}
+		if p := PseudoResidual(c.loss, c.r); math.Abs(p-c.pseudo) > 1e-9 {
+			t.Errorf("expected pseudo residual %v of %v with %v, got %v", c.pseudo, c.r, c.loss, p)
+		}
+	}
+
+	// the pseudo residual is half the derivative of the loss value
+	for _, r := range []float64{-2.5, -0.3, 0.7, 1.9} {
+		for _, loss := range []*pb_common.LossParams{nil, huber, quantile} {
+			h := 1e-6
+			derivative := (LossValue(loss, r+h) - LossValue(loss, r-h)) / (2 * h)
+			if math.Abs(derivative/2-PseudoResidual(loss, r)) > 1e-6 {
+				t.Errorf("pseudo residual of %v with %v mismatches the loss", r, loss)
+			}
+		}
+	}
+}
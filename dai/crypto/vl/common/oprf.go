@@ -0,0 +1,182 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// OPRF based PSI uses the 2HashDH oblivious pseudo-random function F(k, x) = H2(x, k*H1(x)),
+// where H1 hashes IDs onto the elliptic curve, and H2 hashes the evaluated point into a digest.
+// A party blinds its IDs H1(x)*r with a random scalar r, the other party evaluates the blinded points
+// with its key k, and returns them together with the digests of its own IDs F(k, y).
+// The party removes the blinding by r^-1 to get k*H1(x), and the intersection is the IDs
+// whose digests F(k, x) exist in the digests of the other party.
+// Points and digests are processed by PSI workers in parallel, which speeds up large ID sets.
+
+const (
+	// OPRFDigestSize is the length of digests of OPRF outputs
+	OPRFDigestSize = 16
+
+	hashToCurveDomain = "PaddleDTX-PSI-OPRF"
+)
+
+// GenerateOPRFScalar generates a random scalar used as OPRF key or blinding factor
+func GenerateOPRFScalar() (*big.Int, error) {
+	n := defaultCurve.Params().N
+	for {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// HashToCurve hashes ID onto the default elliptic curve by try-and-increment,
+// nobody knows the discrete logarithm of the result
+func HashToCurve(id string) (x, y *big.Int) {
+	params := defaultCurve.Params()
+	three := big.NewInt(3)
+	for counter := uint32(0); ; counter++ {
+		h := sha256.New()
+		h.Write([]byte(hashToCurveDomain))
+		binary.Write(h, binary.BigEndian, counter)
+		h.Write([]byte(id))
+		digest := h.Sum(nil)
+
+		// y^2 = x^3 - 3x + b
+		x = new(big.Int).SetBytes(digest)
+		x.Mod(x, params.P)
+		y2 := new(big.Int).Exp(x, three, params.P)
+		y2.Sub(y2, new(big.Int).Mul(x, three))
+		y2.Add(y2, params.B)
+		y2.Mod(y2, params.P)
+		y = new(big.Int).ModSqrt(y2, params.P)
+		if y == nil {
+			continue
+		}
+		// select one of the two roots by the digest
+		if y.Bit(0) != uint(digest[0]&1) {
+			y.Sub(params.P, y)
+		}
+		return x, y
+	}
+}
+
+// BlindIDSet hashes IDs onto the curve and blinds them with r, returns compressed points in the order of IDs
+func BlindIDSet(IDs []string, r *big.Int) [][]byte {
+	points := make([][]byte, len(IDs))
+	scalar := r.Bytes()
+	parallel(len(IDs), func(i int) {
+		x, y := HashToCurve(IDs[i])
+		x, y = defaultCurve.ScalarMult(x, y, scalar)
+		points[i] = elliptic.MarshalCompressed(defaultCurve, x, y)
+	})
+	return points
+}
+
+// EvaluateBlindedIDSet multiplies the compressed points blinded by the other party with key k
+func EvaluateBlindedIDSet(points [][]byte, k *big.Int) ([][]byte, error) {
+	return multiplyPoints(points, k)
+}
+
+// UnblindIDSet removes the blinding r from the points evaluated by the other party,
+// and returns digests of the OPRF outputs in the order of IDs
+func UnblindIDSet(IDs []string, points [][]byte, r *big.Int) ([][]byte, error) {
+	if len(points) != len(IDs) {
+		return nil, fmt.Errorf("evaluated %d points for %d IDs", len(points), len(IDs))
+	}
+	rInv := new(big.Int).ModInverse(r, defaultCurve.Params().N)
+	outputs, err := multiplyPoints(points, rInv)
+	if err != nil {
+		return nil, err
+	}
+	digests := make([][]byte, len(IDs))
+	for i := range IDs {
+		digests[i] = oprfDigest(IDs[i], outputs[i])
+	}
+	return digests, nil
+}
+
+// OPRFDigests evaluates OPRF of local IDs with key k, returns digests in the order of IDs
+func OPRFDigests(IDs []string, k *big.Int) [][]byte {
+	digests := make([][]byte, len(IDs))
+	scalar := k.Bytes()
+	parallel(len(IDs), func(i int) {
+		x, y := HashToCurve(IDs[i])
+		x, y = defaultCurve.ScalarMult(x, y, scalar)
+		digests[i] = oprfDigest(IDs[i], elliptic.MarshalCompressed(defaultCurve, x, y))
+	})
+	return digests
+}
+
+// IntersectOPRFDigests returns IDs whose digests exist in the digests of the other party
+func IntersectOPRFDigests(IDs []string, digests [][]byte, otherDigests [][]byte) []string {
+	others := make(map[string]struct{}, len(otherDigests))
+	for _, d := range otherDigests {
+		others[string(d)] = struct{}{}
+	}
+	var intersection []string
+	for i, d := range digests {
+		if _, ok := others[string(d)]; ok {
+			intersection = append(intersection, IDs[i])
+		}
+	}
+	return intersection
+}
+
+// multiplyPoints multiplies compressed points with scalar by PSI workers in parallel
+func multiplyPoints(points [][]byte, scalar *big.Int) ([][]byte, error) {
+	results := make([][]byte, len(points))
+	s := scalar.Bytes()
+	var lock sync.Mutex
+	var err error
+	parallel(len(points), func(i int) {
+		x, y := elliptic.UnmarshalCompressed(defaultCurve, points[i])
+		if x == nil {
+			lock.Lock()
+			err = fmt.Errorf("invalid point at %d", i)
+			lock.Unlock()
+			return
+		}
+		x, y = defaultCurve.ScalarMult(x, y, s)
+		results[i] = elliptic.MarshalCompressed(defaultCurve, x, y)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// oprfDigest computes H2 of ID and its evaluated point
+func oprfDigest(id string, point []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(id))
+	h.Write(point)
+	return h.Sum(nil)[:OPRFDigestSize]
+}
+
+// parallel calls f with 0 to n-1 in goroutines of the number of PSI workers, sharing the kernel worker pool
+func parallel(n int, f func(i int)) {
+	Parallel(n, psiWorkers, f)
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// optimizers and learning rate schedules supported in training
+const (
+	OptimizerSGD     = "sgd"
+	OptimizerAdam    = "adam"
+	OptimizerRMSProp = "rmsprop"
+
+	ScheduleConstant = "constant"
+	ScheduleStep     = "step"
+	ScheduleCosine   = "cosine"
+)
+
+// default hyperparameters of the optimizers
+const (
+	defaultBeta1   = 0.9
+	defaultBeta2   = 0.999
+	defaultRho     = 0.9
+	defaultEpsilon = 1e-8
+)
+
+var (
+	// Optimizers lists the supported optimizers
+	Optimizers = []string{OptimizerSGD, OptimizerAdam, OptimizerRMSProp}
+	// Schedules lists the supported learning rate schedules
+	Schedules = []string{ScheduleConstant, ScheduleStep, ScheduleCosine}
+)
+
+// CheckOptimizerParams checks the optimizer and learning rate schedule, empty ones are SGD and constant
+func CheckOptimizerParams(op *pb_common.OptimizerParams) error {
+	switch op.Optimizer {
+	case "", OptimizerSGD, OptimizerAdam, OptimizerRMSProp:
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "unsupported optimizer %s, valid options are: %s", op.Optimizer,
+			strings.Join(Optimizers, ","))
+	}
+	if op.Beta1 < 0 || op.Beta1 >= 1 || op.Beta2 < 0 || op.Beta2 >= 1 {
+		return errorx.New(errcodes.ErrCodeParam, "beta1 and beta2 of optimizer should be in [0, 1), got %v and %v", op.Beta1, op.Beta2)
+	}
+	if op.Rho < 0 || op.Rho >= 1 {
+		return errorx.New(errcodes.ErrCodeParam, "rho of optimizer should be in [0, 1), got %v", op.Rho)
+	}
+	if op.Epsilon < 0 {
+		return errorx.New(errcodes.ErrCodeParam, "epsilon of optimizer can not be negative, got %v", op.Epsilon)
+	}
+	if (op.Beta1 != 0 || op.Beta2 != 0) && op.Optimizer != OptimizerAdam {
+		return errorx.New(errcodes.ErrCodeParam, "beta1 and beta2 are only supported by adam")
+	}
+	if op.Rho != 0 && op.Optimizer != OptimizerRMSProp {
+		return errorx.New(errcodes.ErrCodeParam, "rho is only supported by rmsprop")
+	}
+	if op.Epsilon != 0 && op.Optimizer != OptimizerAdam && op.Optimizer != OptimizerRMSProp {
+		return errorx.New(errcodes.ErrCodeParam, "epsilon is only supported by adam and rmsprop")
+	}
+
+	switch op.Schedule {
+	case "", ScheduleConstant:
+		if op.StepSize != 0 || op.Gamma != 0 || op.TotalRounds != 0 || op.MinAlpha != 0 {
+			return errorx.New(errcodes.ErrCodeParam, "constant learning rate takes no schedule parameters")
+		}
+	case ScheduleStep:
+		if op.StepSize <= 0 {
+			return errorx.New(errcodes.ErrCodeParam, "stepSize of step decay should be positive, got %d", op.StepSize)
+		}
+		if op.Gamma <= 0 || op.Gamma >= 1 {
+			return errorx.New(errcodes.ErrCodeParam, "gamma of step decay should be in (0, 1), got %v", op.Gamma)
+		}
+		if op.TotalRounds != 0 || op.MinAlpha != 0 {
+			return errorx.New(errcodes.ErrCodeParam, "totalRounds and minAlpha are only supported by cosine decay")
+		}
+	case ScheduleCosine:
+		if op.TotalRounds <= 0 {
+			return errorx.New(errcodes.ErrCodeParam, "totalRounds of cosine decay should be positive, got %d", op.TotalRounds)
+		}
+		if op.MinAlpha < 0 {
+			return errorx.New(errcodes.ErrCodeParam, "minAlpha of cosine decay can not be negative, got %v", op.MinAlpha)
+		}
+		if op.StepSize != 0 || op.Gamma != 0 {
+			return errorx.New(errcodes.ErrCodeParam, "stepSize and gamma are only supported by step decay")
+		}
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "unsupported learning rate schedule %s, valid options are: %s", op.Schedule,
+			strings.Join(Schedules, ","))
+	}
+	return nil
+}
+
+// CheckAlgoOptimizer checks the optimizer of a training task of algo, which is supported by logistic regression
+// and dnn, and RMSProp isn't supported by dnn as PaddleFL MPC doesn't provide it
+func CheckAlgoOptimizer(algo pb_common.Algorithm, op *pb_common.OptimizerParams) error {
+	switch algo {
+	case pb_common.Algorithm_LOGIC_REGRESSION_VL:
+	case pb_common.Algorithm_DNN_PADDLEFL_VL:
+		if op.Optimizer == OptimizerRMSProp {
+			return errorx.New(errcodes.ErrCodeParam, "optimizer rmsprop is not supported by dnn-paddlefl-vl")
+		}
+	default:
+		return errorx.New(errcodes.ErrCodeParam, "optimizer is only supported by logistic-vl and dnn-paddlefl-vl")
+	}
+	return CheckOptimizerParams(op)
+}
+
+// LearningRate returns the learning rate of round scheduled from alpha, alpha is constant if op is nil.
+// Step decay multiplies alpha by gamma every stepSize rounds, and cosine decay anneals alpha to minAlpha
+// in totalRounds rounds, then keeps it at minAlpha.
+func LearningRate(alpha float64, op *pb_common.OptimizerParams, round int) float64 {
+	switch op.GetSchedule() {
+	case ScheduleStep:
+		return alpha * math.Pow(op.Gamma, float64(round/int(op.StepSize)))
+	case ScheduleCosine:
+		if round >= int(op.TotalRounds) {
+			return op.MinAlpha
+		}
+		progress := float64(round) / float64(op.TotalRounds)
+		return op.MinAlpha + (alpha-op.MinAlpha)*(1+math.Cos(math.Pi*progress))/2
+	default:
+		return alpha
+	}
+}
+
+// OptimizerState is the state of an optimizer kept between rounds, persisted with checkpoints
+type OptimizerState struct {
+	M []float64 `json:",omitempty"` // first moment estimates of Adam
+	V []float64 `json:",omitempty"` // second moment estimates of Adam and RMSProp
+}
+
+// Optimizer updates the parameters of a party with the gradients in each round.
+// Each party holds the optimizer of its own parameters, and the optimizers of all parties are
+// built from the same task parameters, so that the updates are applied identically.
+type Optimizer struct {
+	alpha  float64
+	params *pb_common.OptimizerParams
+	state  OptimizerState
+}
+
+// NewOptimizer returns the optimizer of the learning rate alpha and op, which is SGD of constant alpha if nil
+func NewOptimizer(alpha float64, op *pb_common.OptimizerParams) *Optimizer {
+	return &Optimizer{alpha: alpha, params: op}
+}
+
+// State returns the state of the optimizer
+func (o *Optimizer) State() OptimizerState {
+	return o.state
+}
+
+// Restore restores the state of the optimizer from a checkpoint
+func (o *Optimizer) Restore(state OptimizerState) {
+	o.state = state
+}
+
+// Update returns the parameters updated by grads in round, which starts from 0
+func (o *Optimizer) Update(thetas, grads []float64, round int) []float64 {
+	lr := LearningRate(o.alpha, o.params, round)
+	newThetas := make([]float64, len(thetas))
+
+	switch o.params.GetOptimizer() {
+	case OptimizerAdam:
+		beta1, beta2, epsilon := orDefault(o.params.Beta1, defaultBeta1), orDefault(o.params.Beta2, defaultBeta2),
+			orDefault(o.params.Epsilon, defaultEpsilon)
+		o.initState(len(thetas), true)
+		// bias corrections of the moments estimated in round+1 steps
+		step := float64(round + 1)
+		c1, c2 := 1-math.Pow(beta1, step), 1-math.Pow(beta2, step)
+		for i := range thetas {
+			o.state.M[i] = beta1*o.state.M[i] + (1-beta1)*grads[i]
+			o.state.V[i] = beta2*o.state.V[i] + (1-beta2)*grads[i]*grads[i]
+			newThetas[i] = thetas[i] - lr*(o.state.M[i]/c1)/(math.Sqrt(o.state.V[i]/c2)+epsilon)
+		}
+	case OptimizerRMSProp:
+		rho, epsilon := orDefault(o.params.Rho, defaultRho), orDefault(o.params.Epsilon, defaultEpsilon)
+		o.initState(len(thetas), false)
+		for i := range thetas {
+			o.state.V[i] = rho*o.state.V[i] + (1-rho)*grads[i]*grads[i]
+			newThetas[i] = thetas[i] - lr*grads[i]/(math.Sqrt(o.state.V[i])+epsilon)
+		}
+	default:
+		for i := range thetas {
+			newThetas[i] = thetas[i] - lr*grads[i]
+		}
+	}
+	return newThetas
+}
+
+// initState allocates the moment estimates of n parameters if they're not restored
+func (o *Optimizer) initState(n int, firstMoment bool) {
+	if firstMoment && len(o.state.M) != n {
+		o.state.M = make([]float64, n)
+	}
+	if len(o.state.V) != n {
+		o.state.V = make([]float64, n)
+	}
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
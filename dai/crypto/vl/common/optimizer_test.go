@@ -0,0 +1,119 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestCheckOptimizerParams(t *testing.T) {
+	valid := []*pb_common.OptimizerParams{
+		{},
+		{Optimizer: OptimizerAdam, Beta1: 0.8, Epsilon: 1e-6},
+		{Optimizer: OptimizerRMSProp, Rho: 0.95, Schedule: ScheduleStep, StepSize: 10, Gamma: 0.5},
+		{Optimizer: OptimizerSGD, Schedule: ScheduleCosine, TotalRounds: 100, MinAlpha: 0.001},
+	}
+	for _, op := range valid {
+		if err := CheckOptimizerParams(op); err != nil {
+			t.Errorf("expected %v valid, got %v", op, err)
+		}
+	}
+
+	invalid := []*pb_common.OptimizerParams{
+		{Optimizer: "adagrad"},
+		{Optimizer: OptimizerAdam, Beta2: 1},
+		{Optimizer: OptimizerSGD, Beta1: 0.9},
+		{Optimizer: OptimizerAdam, Rho: 0.9},
+		{Epsilon: 1e-8},
+		{Schedule: "exponential"},
+		{Schedule: ScheduleConstant, Gamma: 0.5},
+		{Schedule: ScheduleStep, StepSize: 10},
+		{Schedule: ScheduleStep, StepSize: 10, Gamma: 1},
+		{Schedule: ScheduleCosine},
+		{Schedule: ScheduleCosine, TotalRounds: 10, StepSize: 2},
+	}
+	for _, op := range invalid {
+		if err := CheckOptimizerParams(op); err == nil {
+			t.Errorf("expected %v invalid", op)
+		}
+	}
+
+	if err := CheckAlgoOptimizer(pb_common.Algorithm_DNN_PADDLEFL_VL, &pb_common.OptimizerParams{Optimizer: OptimizerRMSProp}); err == nil {
+		t.Error("expected rmsprop rejected for dnn")
+	}
+	if err := CheckAlgoOptimizer(pb_common.Algorithm_LINEAR_REGRESSION_VL, &pb_common.OptimizerParams{}); err == nil {
+		t.Error("expected optimizer rejected for linear regression")
+	}
+	if err := CheckAlgoOptimizer(pb_common.Algorithm_LOGIC_REGRESSION_VL, &pb_common.OptimizerParams{Optimizer: OptimizerRMSProp}); err != nil {
+		t.Errorf("expected rmsprop valid for logistic regression, got %v", err)
+	}
+}
+
+func TestLearningRate(t *testing.T) {
+	step := &pb_common.OptimizerParams{Schedule: ScheduleStep, StepSize: 10, Gamma: 0.5}
+	cosine := &pb_common.OptimizerParams{Schedule: ScheduleCosine, TotalRounds: 100, MinAlpha: 0.01}
+	cases := []struct {
+		op       *pb_common.OptimizerParams
+		round    int
+		expected float64
+	}{
+		{nil, 50, 0.1},
+		{step, 9, 0.1},
+		{step, 10, 0.05},
+		{step, 25, 0.025},
+		{cosine, 0, 0.1},
+		{cosine, 50, 0.055},
+		{cosine, 100, 0.01},
+		{cosine, 200, 0.01},
+	}
+	for _, c := range cases {
+		if lr := LearningRate(0.1, c.op, c.round); math.Abs(lr-c.expected) > 1e-12 {
+			t.Errorf("expected learning rate %v of round %d by %v, got %v", c.expected, c.round, c.op, lr)
+		}
+	}
+}
+
+func TestOptimizerUpdate(t *testing.T) {
+	// minimizes (x-3)^2, whose gradient is 2(x-3)
+	minimize := func(opt *Optimizer, from, rounds int, thetas []float64) []float64 {
+		for r := from; r < rounds; r++ {
+			thetas = opt.Update(thetas, []float64{2 * (thetas[0] - 3)}, r)
+		}
+		return thetas
+	}
+	for _, op := range []*pb_common.OptimizerParams{
+		nil,
+		{Optimizer: OptimizerAdam},
+		{Optimizer: OptimizerRMSProp, Schedule: ScheduleStep, StepSize: 50, Gamma: 0.5},
+		{Optimizer: OptimizerAdam, Schedule: ScheduleCosine, TotalRounds: 300},
+	} {
+		thetas := minimize(NewOptimizer(0.05, op), 0, 300, []float64{0})
+		if math.Abs(thetas[0]-3) > 0.05 {
+			t.Errorf("expected %v to converge to 3, got %v", op, thetas[0])
+		}
+
+		// training resumed from the state of a checkpoint updates the same as an uninterrupted one
+		opt := NewOptimizer(0.05, op)
+		half := minimize(opt, 0, 150, []float64{0})
+		resumed := NewOptimizer(0.05, op)
+		resumed.Restore(opt.State())
+		if resumedThetas := minimize(resumed, 150, 300, half); !reflect.DeepEqual(resumedThetas, thetas) {
+			t.Errorf("expected resumed %v to update as %v, got %v", op, thetas, resumedThetas)
+		}
+	}
+}
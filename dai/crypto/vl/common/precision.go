@@ -0,0 +1,95 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// The homomorphic computation of vertical linear and logistic regression works on integers, a value is encoded
+// as the fixed-point integer round(value * 10^accuracy) of int64, accuracy is the number of decimal digits kept.
+// A higher accuracy loses less precision but overflows on smaller values.
+const (
+	// DefaultAccuracy is the accuracy of tasks published without one, unless the executor configures another
+	DefaultAccuracy = 10
+	// MinAccuracy and MaxAccuracy are the range of accuracy, 10^15 is the largest power of 10 exact in float64
+	MinAccuracy = 1
+	MaxAccuracy = 15
+
+	// maxExactEncoded is the magnitude of encoded values above which they lose digits in float64 before encoding
+	maxExactEncoded = 1 << 53
+)
+
+// CheckAccuracy checks the accuracy of the fixed-point encoding is in [MinAccuracy, MaxAccuracy]
+func CheckAccuracy(accuracy int64) error {
+	if accuracy < MinAccuracy || accuracy > MaxAccuracy {
+		return errorx.New(errcodes.ErrCodeParam, "invalid accuracy %d, it should be in the range of [%d,%d]",
+			accuracy, MinAccuracy, MaxAccuracy)
+	}
+	return nil
+}
+
+// CheckAccuracyAgreed checks the accuracy of the other party of a task is the same as the local one,
+// zero means the other party doesn't report it, e.g. an executor of an older version
+func CheckAccuracyAgreed(local, other int64, otherParty string) error {
+	if other != 0 && other != local {
+		return errorx.New(errcodes.ErrCodeAccuracyMismatch, "accuracy of party[%s] is %d, but local one is %d",
+			otherParty, other, local)
+	}
+	return nil
+}
+
+// EncodedMagnitude estimates the largest magnitude of the values a party encodes in a round of vertical linear
+// or logistic regression, which are its features, its partial predictions by thetas, and their squares.
+// Rows of trainSet are "id, features..." on the party without label, and "id, 1, features..., label" on the
+// party with label, whose thetas begin with the bias, and whose partial predictions are deviations from labels.
+func EncodedMagnitude(trainSet [][]float64, thetas []float64, isTagPart bool) float64 {
+	var magnitude float64
+	for _, row := range trainSet {
+		if len(row) < 2 {
+			continue
+		}
+		features := row[1:]
+		var part float64
+		if isTagPart {
+			features = row[1 : len(row)-1]
+			part = -row[len(row)-1]
+		}
+		for i, x := range features {
+			if i < len(thetas) {
+				part += thetas[i] * x
+			}
+			magnitude = math.Max(magnitude, math.Abs(x))
+		}
+		magnitude = math.Max(magnitude, math.Max(math.Abs(part), part*part))
+	}
+	return magnitude
+}
+
+// CheckEncodingRange checks the values of magnitude are encoded with accuracy without overflow.
+// lossy is true if they lose digits of the accuracy, which means the encoding approaches its range,
+// the features are better scaled, or the accuracy lowered.
+func CheckEncodingRange(magnitude float64, accuracy int64) (lossy bool, err error) {
+	encoded := magnitude * math.Pow10(int(accuracy))
+	if math.IsNaN(encoded) || encoded >= math.MaxInt64 {
+		return true, errorx.New(errcodes.ErrCodeEncodingOverflow, "value of magnitude %g overflows the fixed-point "+
+			"encoding of accuracy %d, whose range is %g, lower the accuracy or scale the features",
+			magnitude, accuracy, math.MaxInt64/math.Pow10(int(accuracy)))
+	}
+	return encoded > maxExactEncoded, nil
+}
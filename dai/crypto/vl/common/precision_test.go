@@ -0,0 +1,73 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+func TestCheckAccuracy(t *testing.T) {
+	checkErr(CheckAccuracy(MinAccuracy), t)
+	checkErr(CheckAccuracy(DefaultAccuracy), t)
+	checkErr(CheckAccuracy(MaxAccuracy), t)
+	for _, accuracy := range []int64{0, -1, MaxAccuracy + 1} {
+		if err := CheckAccuracy(accuracy); err == nil {
+			t.Errorf("invalid accuracy %d should be rejected", accuracy)
+		}
+	}
+
+	checkErr(CheckAccuracyAgreed(10, 10, "127.0.0.1:8184"), t)
+	// the other party of an older version doesn't report its accuracy
+	checkErr(CheckAccuracyAgreed(10, 0, "127.0.0.1:8184"), t)
+	if err := CheckAccuracyAgreed(10, 8, "127.0.0.1:8184"); !errorx.Is(err, errcodes.ErrCodeAccuracyMismatch) {
+		t.Errorf("expected accuracy mismatch, got %v", err)
+	}
+}
+
+func TestEncodingRange(t *testing.T) {
+	// the party without label: id, features
+	noTag := [][]float64{{1, 0.5, -2}, {2, 3, 1}}
+	// part of sample 2 is 3*1 + 1*2 = 5, whose square is the largest
+	if m := EncodedMagnitude(noTag, []float64{1, 2}, false); m != 25 {
+		t.Errorf("expected magnitude 25, got %v", m)
+	}
+	// the party with label: id, 1, features, label
+	tag := [][]float64{{1, 1, 0.5, 100}, {2, 1, -0.5, 2}}
+	// deviation of sample 1 is 1 + 0.5*2 - 100 = -98
+	if m := EncodedMagnitude(tag, []float64{1, 2}, true); m != 98*98 {
+		t.Errorf("expected magnitude %v, got %v", 98*98, m)
+	}
+
+	lossy, err := CheckEncodingRange(25, DefaultAccuracy)
+	checkErr(err, t)
+	if lossy {
+		t.Error("expected small values encoded without loss")
+	}
+	// 1e6 * 10^10 exceeds 2^53
+	if lossy, err = CheckEncodingRange(1e6, DefaultAccuracy); err != nil || !lossy {
+		t.Errorf("expected values approaching the range lossy, got %v, %v", lossy, err)
+	}
+	// 1e9 * 10^10 exceeds the range of int64
+	if _, err := CheckEncodingRange(1e9, DefaultAccuracy); !errorx.Is(err, errcodes.ErrCodeEncodingOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	// lowering the accuracy fits larger values
+	if _, err := CheckEncodingRange(1e9, 6); err != nil {
+		t.Errorf("expected values fit a lower accuracy, got %v", err)
+	}
+}
@@ -19,16 +19,27 @@ import (
 	"crypto/rand"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/client/service/xchain"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
 	linear_vertical "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/linear_regression/gradient_descent/mpc_vertical"
 )
 
 var (
 	xchainCryptoClient = new(xchain.XchainCryptoClient)
 	defaultCurve       = elliptic.P256()
+
+	// psiWorkers is the number of goroutines hashing and encrypting IDs in PSI, zero means GOMAXPROCS
+	psiWorkers int
 )
 
+// SetPSIWorkers sets the number of goroutines hashing and encrypting IDs in PSI, zero means GOMAXPROCS,
+// the intersection is the same whatever the number is
+func SetPSIWorkers(n int) {
+	psiWorkers = n
+}
+
 // GeneratePSIKeyPair generate ecc private and public key pair for PSI using default elliptic curve
 // key pair is used for ID list encryption and intersection
 func GeneratePSIKeyPair() (*ecdsa.PrivateKey, error) {
@@ -36,22 +47,51 @@ func GeneratePSIKeyPair() (*ecdsa.PrivateKey, error) {
 }
 
 // EncryptSampleIDSet encrypt local sample ID set by own public key
-// IDSet is retrieved from local sample file, encrypted by local public key
+// IDSet is retrieved from local sample file, encrypted by local public key,
+// IDs are hashed and encrypted by PSI workers in parallel
 func EncryptSampleIDSet(IDSet []string, publicKey *ecdsa.PublicKey) ([]byte, error) {
-	encIDs := xchainCryptoClient.PSIEncryptSampleIDSet(IDSet, publicKey)
-	return PSIEncSetToBytes(encIDs)
+	curve := publicKey.Curve
+	points := make([]string, len(IDSet))
+	parallel(len(IDSet), func(i int) {
+		// Hash(ID)*Pub
+		x, y := curve.ScalarMult(publicKey.X, publicKey.Y, hash.HashUsingSha256([]byte(IDSet[i])))
+		points[i] = string(elliptic.Marshal(curve, x, y))
+	})
+
+	// merge in the order of IDs, so the index of duplicated IDs is the last one as before
+	encIDs := make(map[string]int, len(points))
+	for i, p := range points {
+		encIDs[p] = i
+	}
+	return PSIEncSetToBytes(&linear_vertical.EncSet{EncIDs: encIDs})
 }
 
 // ReEncryptIDSet re-encrypt others ID set by own private key
 // encSet is the encryption of ID list, received from other party, already encrypted once
-// encrypt encSet once more using local private key
+// encrypt encSet once more using local private key, by PSI workers in parallel
 func ReEncryptIDSet(encSet []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
 	set, err := PSIEncSetFromBytes(encSet)
 	if err != nil {
 		return nil, err
 	}
-	IDs := xchainCryptoClient.PSIReEncryptIDSet(set, privateKey)
-	return PSIEncSetToBytes(IDs)
+	curve := privateKey.PublicKey.Curve
+	encIDs := make([]string, 0, len(set.EncIDs))
+	for id := range set.EncIDs {
+		encIDs = append(encIDs, id)
+	}
+	points := make([]string, len(encIDs))
+	d := privateKey.D.Bytes()
+	parallel(len(encIDs), func(i int) {
+		x, y := elliptic.Unmarshal(curve, []byte(encIDs[i]))
+		x, y = curve.ScalarMult(x, y, d)
+		points[i] = string(elliptic.Marshal(curve, x, y))
+	})
+
+	reEncIDs := make(map[string]int, len(points))
+	for i, p := range points {
+		reEncIDs[p] = set.EncIDs[encIDs[i]]
+	}
+	return PSIEncSetToBytes(&linear_vertical.EncSet{EncIDs: reEncIDs})
 }
 
 // IntersectTwoParts get intersection of two parts' ID set
@@ -72,7 +112,8 @@ func IntersectTwoParts(sampleID []string, reEncSetLocal []byte, reEncSetOthers [
 }
 
 // RetrieveIDsFromFile retrieve ID set from file rows by id name
-// fileRows is original sample rows, including feature list and sample values
+// fileRows is original sample rows, including feature list and sample values, rows may be sparse, see IsSparseRow,
+// and a sparse row omitting the ID is rejected, as the ID isn't a numeric feature defaulting to 0
 // idName is the name of ID feature, like "id", "card_number"...
 func RetrieveIDsFromFile(fileRows [][]string, idName string) ([]string, error) {
 	if fileRows == nil {
@@ -94,7 +135,11 @@ func RetrieveIDsFromFile(fileRows [][]string, idName string) ([]string, error) {
 	// read from all rows to get ID set
 	var set []string
 	for row := 1; row < len(fileRows); row++ {
-		set = append(set, fileRows[row][featureIndex])
+		id, ok := RowValue(fileRows[row], featureNum, featureIndex)
+		if !ok {
+			return nil, fmt.Errorf("row %d does not contain sample id: %s", row, idName)
+		}
+		set = append(set, id)
 	}
 
 	return set, nil
@@ -133,19 +178,40 @@ func RearrangeFileWithIntersectIDs(fileRows [][]string, idName string, IDs []str
 
 	// read from all rows to remove ID and re-order rows
 	for row := 1; row < len(fileRows); row++ {
-		var newRow []string
-		id := fileRows[row][featureIndex]
+		id, ok := RowValue(fileRows[row], featureNum, featureIndex)
+		if !ok {
+			return nil, fmt.Errorf("row %d does not contain sample id: %s", row, idName)
+		}
 
 		if idx, exist := reOrderedIDs[id]; exist {
-			newRow = append(newRow, fileRows[row][0:featureIndex]...)
-			newRow = append(newRow, fileRows[row][featureIndex+1:]...)
-			intersectRows[idx+1] = newRow
+			intersectRows[idx+1] = removeColumn(fileRows[row], featureNum, featureIndex)
 		}
 	}
 
 	return intersectRows, nil
 }
 
+// removeColumn returns row without column index, row is either a dense row or a sparse row of the file
+// with columnNum columns, the columns after index in a sparse row are moved forward by one
+func removeColumn(row []string, columnNum, index int) []string {
+	var newRow []string
+	if !IsSparseRow(row, columnNum) {
+		newRow = append(newRow, row[0:index]...)
+		return append(newRow, row[index+1:]...)
+	}
+	newRow = make([]string, 0, len(row))
+	for _, cell := range row {
+		i, value, _ := parseSparseCell(cell)
+		switch {
+		case i < index:
+			newRow = append(newRow, cell)
+		case i > index:
+			newRow = append(newRow, strconv.Itoa(i-1)+":"+value)
+		}
+	}
+	return newRow
+}
+
 // reOrderIDSet by ID string ascending order
 func reOrderIDSet(IDs []string) map[string]int {
 	idxMap := make(map[string]int)
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func psiIDs(n, offset int) []string {
+	IDs := make([]string, n)
+	for i := range IDs {
+		IDs[i] = fmt.Sprintf("id%d", i+offset)
+	}
+	// duplicated IDs keep the last index
+	return append(IDs, IDs[0])
+}
+
+func TestPSIWorkers(t *testing.T) {
+	defer SetPSIWorkers(0)
+	privA, err := GeneratePSIKeyPair()
+	checkErr(err, t)
+	privB, err := GeneratePSIKeyPair()
+	checkErr(err, t)
+	IDsA, IDsB := psiIDs(500, 0), psiIDs(500, 200)
+
+	// same as encrypting one by one
+	encA, err := PSIEncSetToBytes(xchainCryptoClient.PSIEncryptSampleIDSet(IDsA, &privA.PublicKey))
+	checkErr(err, t)
+	set, err := PSIEncSetFromBytes(encA)
+	checkErr(err, t)
+	reEncA, err := PSIEncSetToBytes(xchainCryptoClient.PSIReEncryptIDSet(set, privB))
+	checkErr(err, t)
+
+	var intersection []string
+	for _, workers := range []int{1, 3, 16, 0} {
+		SetPSIWorkers(workers)
+		enc, err := EncryptSampleIDSet(IDsA, &privA.PublicKey)
+		checkErr(err, t)
+		if !bytes.Equal(enc, encA) {
+			t.Fatalf("encrypted IDs of %d workers differ", workers)
+		}
+		reEnc, err := ReEncryptIDSet(enc, privB)
+		checkErr(err, t)
+		if !bytes.Equal(reEnc, reEncA) {
+			t.Fatalf("re-encrypted IDs of %d workers differ", workers)
+		}
+
+		encB, err := EncryptSampleIDSet(IDsB, &privB.PublicKey)
+		checkErr(err, t)
+		reEncB, err := ReEncryptIDSet(encB, privA)
+		checkErr(err, t)
+		IDs, err := IntersectTwoParts(IDsA, reEnc, reEncB)
+		checkErr(err, t)
+		sort.Strings(IDs)
+		if len(IDs) != 300 {
+			t.Fatalf("intersection of %d workers has %d IDs, expected 300", workers, len(IDs))
+		}
+		if intersection != nil && !reflect.DeepEqual(IDs, intersection) {
+			t.Fatalf("intersection of %d workers differs", workers)
+		}
+		intersection = IDs
+	}
+}
+
+func BenchmarkEncryptSampleIDSet(b *testing.B) {
+	defer SetPSIWorkers(0)
+	priv, err := GeneratePSIKeyPair()
+	if err != nil {
+		b.Fatal(err)
+	}
+	IDs := psiIDs(1000000, 0)
+	for _, workers := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			SetPSIWorkers(workers)
+			for i := 0; i < b.N; i++ {
+				if _, err := EncryptSampleIDSet(IDs, &priv.PublicKey); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// Feature scaling methods of linear and logistic regression,
+// all of them are stored in the model as xbars and sigmas which apply as (x-xbar)/sigma
+const (
+	ScalingZScore = "zscore" // standardizes features by means and standard deviations, the default
+	ScalingMinMax = "minmax" // rescales features into [0, 1] by minimums and ranges
+	ScalingMaxAbs = "maxabs" // rescales features into [-1, 1] by maximum absolute values, which keeps sparse features sparse
+	ScalingNone   = "none"   // keeps features as they are
+)
+
+// CheckScaling checks if the scaling method is supported, empty means ScalingZScore
+func CheckScaling(scaling string) error {
+	switch scaling {
+	case "", ScalingZScore, ScalingMinMax, ScalingMaxAbs, ScalingNone:
+		return nil
+	}
+	return errorx.New(errcodes.ErrCodeParam, "invalid scaling method %s, should be %s, %s, %s or %s",
+		scaling, ScalingZScore, ScalingMinMax, ScalingMaxAbs, ScalingNone)
+}
+
+// ScaleDataSet rescales the original features of dataSet which has been z-score standardized,
+// and replaces the parameters of dataSet with the ones of the scaling method.
+// Feature unscaled is kept as it is, such as the label of logistic regression.
+func ScaleDataSet(dataSet *ml_common.StandardizedDataSet, scaling string, unscaled string) error {
+	if err := CheckScaling(scaling); err != nil {
+		return err
+	}
+	if scaling == "" || scaling == ScalingZScore {
+		return nil
+	}
+
+	xbars := make(map[string]float64, len(dataSet.OriginalFeatures))
+	sigmas := make(map[string]float64, len(dataSet.OriginalFeatures))
+	for _, feature := range dataSet.OriginalFeatures {
+		xbar, sigma := 0.0, 1.0
+		if scaling == ScalingMinMax && len(feature.Sets) > 0 {
+			min, max := math.Inf(1), math.Inf(-1)
+			for _, value := range feature.Sets {
+				min = math.Min(min, value)
+				max = math.Max(max, value)
+			}
+			xbar = min
+			// a constant feature is scaled to 0
+			if max > min {
+				sigma = max - min
+			}
+		}
+		if scaling == ScalingMaxAbs {
+			sigma = maxAbs(feature.Sets)
+		}
+		xbars[feature.FeatureName] = xbar
+		sigmas[feature.FeatureName] = sigma
+	}
+	rescale(dataSet, xbars, sigmas, unscaled)
+	return nil
+}
+
+// maxAbs returns the maximum absolute value of values, 1 if values are all 0, so that they are scaled to 0
+func maxAbs(values map[int]float64) float64 {
+	max := 0.0
+	for _, value := range values {
+		max = math.Max(max, math.Abs(value))
+	}
+	if max == 0 {
+		return 1
+	}
+	return max
+}
+
+// scalingOf returns the scaling method of training, the one of the base model in incremental training
+func scalingOf(params pb_common.TrainParams) string {
+	scaling := params.Scaling
+	if params.BaseModel != nil {
+		scaling = params.BaseModel.Scaling
+	}
+	if scaling == "" {
+		return ScalingZScore
+	}
+	return scaling
+}
+
+// CheckPredictFeatures checks if the features of the samples to predict match the ones the model is trained with,
+// label and the column of sample weights are the only features allowed besides the ones of the model
+func CheckPredictFeatures(features []string, model *pb_common.TrainModels) error {
+	input := make(map[string]bool, len(features))
+	for _, feature := range features {
+		if input[feature] {
+			return errorx.New(errcodes.ErrCodeParam, "duplicated feature %s in prediction samples", feature)
+		}
+		input[feature] = true
+		if _, ok := model.Thetas[feature]; !ok && feature != model.Label && feature != model.WeightColumn {
+			return errorx.New(errcodes.ErrCodeParam, "feature %s is not in the model", feature)
+		}
+	}
+	for feature := range model.Thetas {
+		if feature == "Intercept" {
+			continue
+		}
+		if !input[feature] {
+			return errorx.New(errcodes.ErrCodeParam, "feature %s of the model is missing in prediction samples", feature)
+		}
+		if _, ok := model.Sigmas[feature]; !ok {
+			return errorx.New(errcodes.ErrCodeParam, "no scaling parameters of feature %s in the model", feature)
+		}
+	}
+	return nil
+}
+
+// rescale replaces the features of dataSet with the original ones applied (x-xbar)/sigma,
+// and replaces the parameters of dataSet with xbars and sigmas
+func rescale(dataSet *ml_common.StandardizedDataSet, xbars, sigmas map[string]float64, unscaled string) {
+	for i, feature := range dataSet.OriginalFeatures {
+		if feature.FeatureName == unscaled {
+			continue
+		}
+		xbar := xbars[feature.FeatureName]
+		sigma := sigmas[feature.FeatureName]
+		sets := make(map[int]float64, len(feature.Sets))
+		for key, value := range feature.Sets {
+			sets[key] = (value - xbar) / sigma
+		}
+		dataSet.Features[i] = &ml_common.DataFeature{FeatureName: feature.FeatureName, Sets: sets}
+	}
+	dataSet.XbarParams = xbars
+	dataSet.SigmaParams = sigmas
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestScaleDataSet(t *testing.T) {
+	newDataSet := func() *ml_common.StandardizedDataSet {
+		return &ml_common.StandardizedDataSet{
+			OriginalFeatures: []*ml_common.DataFeature{
+				{FeatureName: "x", Sets: map[int]float64{0: 2, 1: 6, 2: 4}},
+				{FeatureName: "c", Sets: map[int]float64{0: 3, 1: 3, 2: 3}},
+				{FeatureName: "label", Sets: map[int]float64{0: 1, 1: 0, 2: 1}},
+			},
+			Features: make([]*ml_common.DataFeature, 3),
+		}
+	}
+
+	dataSet := newDataSet()
+	err := ScaleDataSet(dataSet, ScalingMinMax, "label")
+	checkErr(err, t)
+	if !reflect.DeepEqual(dataSet.Features[0].Sets, map[int]float64{0: 0, 1: 1, 2: 0.5}) {
+		t.Errorf("feature x rescaled into [0, 1]: %v", dataSet.Features[0].Sets)
+	}
+	if !reflect.DeepEqual(dataSet.Features[1].Sets, map[int]float64{0: 0, 1: 0, 2: 0}) {
+		t.Errorf("constant feature c should be scaled to 0: %v", dataSet.Features[1].Sets)
+	}
+	if dataSet.Features[2] != nil {
+		t.Errorf("label should be kept as it is, got: %v", dataSet.Features[2])
+	}
+	if dataSet.XbarParams["x"] != 2 || dataSet.SigmaParams["x"] != 4 {
+		t.Errorf("parameters of feature x: %v, %v", dataSet.XbarParams["x"], dataSet.SigmaParams["x"])
+	}
+
+	dataSet = newDataSet()
+	err = ScaleDataSet(dataSet, ScalingNone, "")
+	checkErr(err, t)
+	for i, feature := range dataSet.Features {
+		if !reflect.DeepEqual(feature.Sets, dataSet.OriginalFeatures[i].Sets) {
+			t.Errorf("feature %s should not be scaled: %v", feature.FeatureName, feature.Sets)
+		}
+	}
+
+	dataSet = newDataSet()
+	err = ScaleDataSet(dataSet, ScalingMaxAbs, "label")
+	checkErr(err, t)
+	if !reflect.DeepEqual(dataSet.Features[0].Sets, map[int]float64{0: 2.0 / 6, 1: 1, 2: 4.0 / 6}) {
+		t.Errorf("feature x divided by its maximum absolute value: %v", dataSet.Features[0].Sets)
+	}
+	if dataSet.XbarParams["x"] != 0 || dataSet.SigmaParams["x"] != 6 {
+		t.Errorf("parameters of feature x: %v, %v", dataSet.XbarParams["x"], dataSet.SigmaParams["x"])
+	}
+
+	dataSet = newDataSet()
+	err = ScaleDataSet(dataSet, "", "")
+	checkErr(err, t)
+	if dataSet.Features[0] != nil {
+		t.Error("z-score standardized features should be kept")
+	}
+
+	if err := ScaleDataSet(newDataSet(), "log", ""); err == nil {
+		t.Error("unsupported scaling method should be rejected")
+	}
+}
+
+func TestCheckPredictFeatures(t *testing.T) {
+	model := &pb_common.TrainModels{
+		Thetas: map[string]float64{"Intercept": 1, "x": 2, "z": 3},
+		Xbars:  map[string]float64{"x": 0, "z": 0, "label": 0},
+		Sigmas: map[string]float64{"x": 1, "z": 1, "label": 1},
+		Label:  "label",
+	}
+
+	checkErr(CheckPredictFeatures([]string{"z", "x"}, model), t)
+	checkErr(CheckPredictFeatures([]string{"x", "z", "label"}, model), t)
+
+	for _, features := range [][]string{
+		{"x"},
+		{"x", "z", "y"},
+		{"x", "z", "z"},
+	} {
+		if err := CheckPredictFeatures(features, model); err == nil {
+			t.Errorf("features %v not matching the model should be rejected", features)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SparseZero is the value of the columns omitted in sparse rows.
+// A sample file may have sparse rows besides dense ones, each cell of a sparse row is "index:value",
+// index is the 0-based index of the column in the first row, in ascending order, and the columns omitted are 0.
+// For example, the row "1,0,0,3.5" of the file with columns "id,x1,x2,x3" is "0:1,3:3.5" in the sparse form.
+const SparseZero = "0"
+
+// parseSparseCell parses the cell "index:value" of a sparse row
+func parseSparseCell(cell string) (int, string, bool) {
+	i := strings.IndexByte(cell, ':')
+	if i <= 0 {
+		return 0, "", false
+	}
+	index, err := strconv.Atoi(cell[:i])
+	if err != nil || index < 0 || cell[:i] != strconv.Itoa(index) {
+		return 0, "", false
+	}
+	return index, cell[i+1:], true
+}
+
+// IsSparseRow returns whether row is a sparse row of the file with columnNum columns,
+// which is made up of the cells "index:value" with ascending indices less than columnNum.
+// An empty row is the sparse row of all zeros, which is the case of a sparse row whose only cell,
+// the sample ID, is removed after PSI, while rows in sample files have at least one cell.
+func IsSparseRow(row []string, columnNum int) bool {
+	if len(row) > columnNum {
+		return false
+	}
+	last := -1
+	for _, cell := range row {
+		index, _, ok := parseSparseCell(cell)
+		if !ok || index <= last || index >= columnNum {
+			return false
+		}
+		last = index
+	}
+	return true
+}
+
+// RowCells returns the indices and values of the cells of row, which is either a dense row or a sparse row
+// of the file with columnNum columns, all the columns of a dense row are returned, and only the ones present in a sparse row
+func RowCells(row []string, columnNum int) ([]int, []string) {
+	if !IsSparseRow(row, columnNum) {
+		indices := make([]int, len(row))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, row
+	}
+	indices := make([]int, len(row))
+	values := make([]string, len(row))
+	for i, cell := range row {
+		indices[i], values[i], _ = parseSparseCell(cell)
+	}
+	return indices, values
+}
+
+// RowValue returns the value of column index of row, which is either a dense row or a sparse row
+// of the file with columnNum columns, ok is false if row doesn't have the column, such as a sparse row omitting it,
+// whose value is SparseZero
+func RowValue(row []string, columnNum, index int) (string, bool) {
+	if !IsSparseRow(row, columnNum) {
+		if index < 0 || index >= len(row) {
+			return "", false
+		}
+		return row[index], true
+	}
+	i := sort.Search(len(row), func(i int) bool {
+		cellIndex, _, _ := parseSparseCell(row[i])
+		return cellIndex >= index
+	})
+	if i == len(row) {
+		return SparseZero, false
+	}
+	cellIndex, value, _ := parseSparseCell(row[i])
+	if cellIndex != index {
+		return SparseZero, false
+	}
+	return value, true
+}
+
+// CheckRows checks that each row of fileRows except the first one, which is the list of columns,
+// is either a dense row of all columns or a valid sparse row
+func CheckRows(fileRows [][]string) error {
+	if len(fileRows) == 0 {
+		return nil
+	}
+	columnNum := len(fileRows[0])
+	for r := 1; r < len(fileRows); r++ {
+		if row := fileRows[r]; len(row) != columnNum && !IsSparseRow(row, columnNum) {
+			return fmt.Errorf("row %d has %d columns, while the file has %d columns, and it's not a valid sparse row", r, len(row), columnNum)
+		}
+	}
+	return nil
+}
+
+// HasSparseRows returns whether any row of fileRows except the first one, which is the list of columns, is a sparse row
+func HasSparseRows(fileRows [][]string) bool {
+	if len(fileRows) == 0 {
+		return false
+	}
+	for _, row := range fileRows[1:] {
+		if IsSparseRow(row, len(fileRows[0])) {
+			return true
+		}
+	}
+	return false
+}
+
+// DensifyRows converts the sparse rows of fileRows into dense ones, the first row is the list of columns,
+// dense rows are kept as they are, and rows which are neither sparse nor have all columns are rejected.
+// The omitted columns share the same string SparseZero, so only the non-zero values of sparse rows take memory besides the slices.
+// It is used by the algorithms which compute on dense rows, see SparseTrainSet for the ones which compute on sparse rows.
+func DensifyRows(fileRows [][]string) ([][]string, error) {
+	if err := CheckRows(fileRows); err != nil {
+		return nil, err
+	}
+	if !HasSparseRows(fileRows) {
+		return fileRows, nil
+	}
+	columnNum := len(fileRows[0])
+	rows := make([][]string, len(fileRows))
+	rows[0] = fileRows[0]
+	for r := 1; r < len(fileRows); r++ {
+		row := fileRows[r]
+		if !IsSparseRow(row, columnNum) {
+			rows[r] = row
+			continue
+		}
+		dense := make([]string, columnNum)
+		for i := range dense {
+			dense[i] = SparseZero
+		}
+		for _, cell := range row {
+			index, value, _ := parseSparseCell(cell)
+			dense[index] = value
+		}
+		rows[r] = dense
+	}
+	return rows, nil
+}
+
+// SparsifyRows converts the rows of fileRows into sparse ones, the first row is the list of columns,
+// the values which are numeric 0 are omitted except the ones of column idName, which identifies samples in PSI,
+// so that the file of high-dimensional and sparse samples is much smaller
+func SparsifyRows(fileRows [][]string, idName string) [][]string {
+	if len(fileRows) == 0 {
+		return fileRows
+	}
+	idColumn := -1
+	for i, name := range fileRows[0] {
+		if name == idName {
+			idColumn = i
+		}
+	}
+	rows := make([][]string, len(fileRows))
+	rows[0] = fileRows[0]
+	for r := 1; r < len(fileRows); r++ {
+		var sparse []string
+		for i, value := range fileRows[r] {
+			if v, err := strconv.ParseFloat(value, 64); err == nil && v == 0 && i != idColumn {
+				continue
+			}
+			sparse = append(sparse, strconv.Itoa(i)+":"+value)
+		}
+		// a row of all zeros keeps its first column, as a sparse row has at least one cell
+		if len(sparse) == 0 && len(fileRows[r]) > 0 {
+			sparse = []string{"0:" + fileRows[r][0]}
+		}
+		rows[r] = sparse
+	}
+	return rows
+}
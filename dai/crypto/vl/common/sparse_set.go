@@ -0,0 +1,310 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+	linear_vertical "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/linear_regression/gradient_descent/mpc_vertical"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// SparseSample is a training sample of linear and logistic regression, whose features not in Indices are 0
+type SparseSample struct {
+	ID      int       // ID of the sample in training, which is the one of the dense train set
+	Indices []int     // indices of the non-zero features in the features of the train set, in ascending order
+	Values  []float64 // scaled values of the features of Indices
+	Label   float64   // label on the party with label
+}
+
+// SparseTrainSet is the train set of linear and logistic regression made up of sparse samples, the gradients
+// of a feature are computed and transferred only for the samples of which it is non-zero, and the costs
+// and the intermediate results of samples are computed by their non-zero features.
+// It's used instead of the dense ml_common.TrainDataSet in the cases of UseSparseTrainSet.
+type SparseTrainSet struct {
+	// FeatureNames, XbarParams and SigmaParams are the same as the ones of the dense train set,
+	// the label is the last one of FeatureNames on the party with label, TrainSet and OriginalTrainSet are nil
+	*ml_common.TrainDataSet
+
+	Samples   []*SparseSample
+	Summaries map[string]*pb_common.FeatureSummary // distributions of the original features, see SummarizeTrainDataSet
+}
+
+// UseSparseTrainSet returns whether linear and logistic regression train with SparseTrainSet on the samples fileRows,
+// which is the case that fileRows has sparse rows and the features scaled are still sparse, i.e. scaled by ScalingNone
+// or ScalingMaxAbs, and no categorical column is encoded. Samples are not weighted either, as the weights of samples
+// are normalized over the ones each gradient has, while the gradient of a feature only has the samples it is non-zero.
+func UseSparseTrainSet(fileRows [][]string, params pb_common.TrainParams) bool {
+	if scaling := scalingOf(params); scaling != ScalingNone && scaling != ScalingMaxAbs {
+		return false
+	}
+	if len(params.GetCategorical().GetColumns()) > 0 || len(params.GetBaseModel().GetCategories()) > 0 {
+		return false
+	}
+	return params.WeightColumn == "" && HasSparseRows(fileRows)
+}
+
+// NewSparseTrainSet retrieves the sparse train set from fileRows for tag/no-tag part, fileRows is sample rows,
+// first row is feature list, others are values for each sample, either dense or sparse.
+// The label is 1 if it is params.LabelName and 0 otherwise in logistic regression, and it isn't scaled,
+// the features and the label of linear regression are scaled by params.Scaling, or by params.BaseModel if it is set.
+func NewSparseTrainSet(fileRows [][]string, params pb_common.TrainParams, logistic bool) (*SparseTrainSet, error) {
+	if len(fileRows) < 2 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "no samples to train")
+	}
+	header := fileRows[0]
+	labelColumn := -1
+	var names []string
+	// features maps the index of a column to the index of the feature, the label isn't a feature
+	features := make([]int, len(header))
+	for j, name := range header {
+		if params.IsTagPart && name == params.Label {
+			labelColumn = j
+			continue
+		}
+		features[j] = len(names)
+		names = append(names, name)
+	}
+	if params.IsTagPart {
+		if labelColumn < 0 {
+			return nil, errorx.New(errcodes.ErrCodeParam, "label %s does not exist in samples", params.Label)
+		}
+		names = append(names, params.Label)
+	}
+
+	samples := make([]*SparseSample, len(fileRows)-1)
+	labels := make([]float64, len(samples))
+	for i, row := range fileRows[1:] {
+		sample := &SparseSample{ID: i}
+		label := SparseZero
+		indices, cells := RowCells(row, len(header))
+		for k, j := range indices {
+			if j == labelColumn {
+				label = cells[k]
+				continue
+			}
+			value, err := strconv.ParseFloat(cells[k], 64)
+			if err != nil {
+				return nil, errorx.New(errcodes.ErrCodeParam, "failed to parse value of feature %s of sample %d: %s", header[j], i, err.Error())
+			}
+			if value != 0 {
+				sample.Indices = append(sample.Indices, features[j])
+				sample.Values = append(sample.Values, value)
+			}
+		}
+		if params.IsTagPart {
+			if logistic {
+				if label == params.LabelName {
+					labels[i] = 1
+				}
+			} else if v, err := strconv.ParseFloat(label, 64); err == nil {
+				labels[i] = v
+			} else {
+				return nil, errorx.New(errcodes.ErrCodeParam, "failed to parse label of sample %d: %s", i, err.Error())
+			}
+		}
+		samples[i] = sample
+	}
+
+	set := &SparseTrainSet{
+		TrainDataSet: &ml_common.TrainDataSet{FeatureNames: names},
+		Samples:      samples,
+		Summaries:    summarizeSparseSamples(samples, labels, names, params.IsTagPart),
+	}
+	if err := set.scale(labels, params, logistic); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// summarizeSparseSamples summarizes the original features of samples and their labels on the party with label
+func summarizeSparseSamples(samples []*SparseSample, labels []float64, names []string, isTagPart bool) map[string]*pb_common.FeatureSummary {
+	featureNum := len(names)
+	if isTagPart {
+		featureNum--
+	}
+	values := make([][]float64, featureNum)
+	for _, sample := range samples {
+		for k, f := range sample.Indices {
+			values[f] = append(values[f], sample.Values[k])
+		}
+	}
+	summaries := make(map[string]*pb_common.FeatureSummary, len(names))
+	for f := 0; f < featureNum; f++ {
+		summaries[names[f]] = summarizeSparse(values[f], len(samples))
+	}
+	if isTagPart {
+		summaries[names[featureNum]] = Summarize(labels)
+	}
+	return summaries
+}
+
+// scale scales the features of the samples and labels, and sets the scaling parameters of set as ScaleDataSet does,
+// or as StandardizeByModel does in incremental training. The means are 0 with the scaling methods of sparse samples.
+func (s *SparseTrainSet) scale(labels []float64, params pb_common.TrainParams, logistic bool) error {
+	featureNum := len(s.FeatureNames)
+	if params.IsTagPart {
+		featureNum--
+	}
+	if model := params.BaseModel; model != nil {
+		if len(s.FeatureNames) != len(model.Xbars) {
+			return errorx.New(errcodes.ErrCodeParam, "the base model is trained with %d features, got %d",
+				len(model.Xbars), len(s.FeatureNames))
+		}
+		for _, name := range s.FeatureNames {
+			if _, ok := model.Xbars[name]; !ok {
+				return errorx.New(errcodes.ErrCodeParam, "feature %s is not in the base model", name)
+			}
+		}
+		s.XbarParams, s.SigmaParams = model.Xbars, model.Sigmas
+	} else {
+		s.XbarParams = make(map[string]float64, len(s.FeatureNames))
+		s.SigmaParams = make(map[string]float64, len(s.FeatureNames))
+		maxAbs := make([]float64, len(s.FeatureNames))
+		for _, sample := range s.Samples {
+			for k, f := range sample.Indices {
+				maxAbs[f] = math.Max(maxAbs[f], math.Abs(sample.Values[k]))
+			}
+		}
+		if params.IsTagPart {
+			for _, label := range labels {
+				maxAbs[featureNum] = math.Max(maxAbs[featureNum], math.Abs(label))
+			}
+		}
+		for f, name := range s.FeatureNames {
+			sigma := 1.0
+			if scalingOf(params) == ScalingMaxAbs && maxAbs[f] > 0 {
+				sigma = maxAbs[f]
+			}
+			s.XbarParams[name] = 0
+			s.SigmaParams[name] = sigma
+		}
+	}
+
+	sigmas := make([]float64, featureNum)
+	for f := range sigmas {
+		sigmas[f] = s.SigmaParams[s.FeatureNames[f]]
+	}
+	for i, sample := range s.Samples {
+		for k, f := range sample.Indices {
+			sample.Values[k] /= sigmas[f]
+		}
+		sample.Label = labels[i]
+		// the label of logistic regression is kept as it is
+		if params.IsTagPart && !logistic {
+			label := s.FeatureNames[featureNum]
+			sample.Label = (labels[i] - s.XbarParams[label]) / s.SigmaParams[label]
+		}
+	}
+	return nil
+}
+
+// Dot returns the sum of the products of the features of sample and thetas, the thetas of the features start from offset
+func (sample *SparseSample) Dot(thetas []float64, offset int) float64 {
+	var sum float64
+	for k, f := range sample.Indices {
+		sum += thetas[f+offset] * sample.Values[k]
+	}
+	return sum
+}
+
+// SparseRegCost returns the regularization cost of thetas of the batch of batchSize samples,
+// encoded as an integer of params.Accuracy and its encryption by publicKey, as the ones of the dense train set
+// computed by linear and logistic regression, which are the same
+func SparseRegCost(thetas []float64, batchSize int, params pb_common.TrainParams, publicKey *paillier.PublicKey) (*big.Int, *big.Int, error) {
+	regCost := 0.0
+	switch int(params.RegMode) {
+	case ml_common.RegLasso:
+		regCost = linear_vertical.CalLassoRegCost(thetas, batchSize, params.RegParam)
+	case ml_common.RegRidge:
+		regCost = linear_vertical.CalRidgeRegCost(thetas, batchSize, params.RegParam)
+	}
+	rawRegCost := big.NewInt(int64(math.Round(regCost * math.Pow(10, float64(params.Accuracy)))))
+	encRegCost, err := publicKey.EncryptSupNegNum(rawRegCost)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rawRegCost, encRegCost, nil
+}
+
+// SparseGradientWithReg returns the gradient of theta i, gradSum is the sum of the gradients of the samples
+// of which the feature is non-zero, and the gradients of the others are 0, it's the mean of the gradients
+// of the batch of batchSize samples with the regularization term, as the one of the dense train set
+func SparseGradientWithReg(thetas []float64, gradSum float64, batchSize int, i int, params pb_common.TrainParams) float64 {
+	gradient := gradSum / float64(batchSize)
+	switch int(params.RegMode) {
+	case ml_common.RegLasso:
+		sgnTheta := 0.0
+		switch {
+		case thetas[i] > 0:
+			sgnTheta = 1
+		case thetas[i] < 0:
+			sgnTheta = -1
+		}
+		gradient += params.RegParam * sgnTheta / float64(batchSize)
+	case ml_common.RegRidge:
+		gradient += params.RegParam * thetas[i] / float64(batchSize)
+	}
+	return gradient
+}
+
+// PredictSparseRows returns the local parts of the predictions of linear and logistic regression,
+// which is the sum of thetas times the scaled features of each sample, plus the intercept on the party with label.
+// fileRows is sample rows, first row is feature list, others are values for each sample, either dense or sparse,
+// the features omitted in sparse rows are 0, and the features should have been checked by CheckPredictFeatures.
+func PredictSparseRows(fileRows [][]string, model *pb_common.TrainModels) ([]float64, error) {
+	header := fileRows[0]
+	// base is the prediction of the sample whose features are all 0,
+	// and a non-zero feature adds its value times its coefficient to it
+	base := 0.0
+	if model.IsTagPart {
+		base = model.Thetas["Intercept"]
+	}
+	coefficients := make(map[int]float64)
+	for j, name := range header {
+		theta, ok := model.Thetas[name]
+		// weights of samples are only used in training
+		if !ok || name == "Intercept" || name == model.WeightColumn {
+			continue
+		}
+		base += theta * (0 - model.Xbars[name]) / model.Sigmas[name]
+		coefficients[j] = theta / model.Sigmas[name]
+	}
+
+	predictions := make([]float64, len(fileRows)-1)
+	for i := 1; i < len(fileRows); i++ {
+		prediction := base
+		indices, cells := RowCells(fileRows[i], len(header))
+		for k, j := range indices {
+			coefficient, ok := coefficients[j]
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(cells[k], 64)
+			if err != nil {
+				return nil, errorx.New(errcodes.ErrCodeParam, "failed to parse value of feature %s: %s", header[j], err.Error())
+			}
+			prediction += coefficient * value
+		}
+		predictions[i-1] = prediction
+	}
+	return predictions, nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestSparseRows(t *testing.T) {
+	dense := [][]string{
+		{"id", "x1", "x2", "x3", "label"},
+		{"a", "0", "0", "3.5", "yes"},
+		{"b", "1", "0.0", "0", "no"},
+	}
+	sparse := SparsifyRows(dense, "id")
+	want := [][]string{
+		{"id", "x1", "x2", "x3", "label"},
+		{"0:a", "3:3.5", "4:yes"},
+		{"0:b", "1:1", "4:no"},
+	}
+	if !reflect.DeepEqual(sparse, want) {
+		t.Errorf("sparse rows: %v, want %v", sparse, want)
+	}
+
+	// dense rows are kept as they are, and both kinds can be mixed in a file
+	rows, err := DensifyRows([][]string{dense[0], sparse[1], dense[2]})
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[1], dense[1]) || !reflect.DeepEqual(rows[2], dense[2]) {
+		t.Errorf("dense rows: %v, want %v", rows, dense)
+	}
+
+	for _, row := range [][]string{
+		{"0:a", "3:3.5", "2:1"},  // indices not ascending
+		{"0:a", "5:1"},           // index out of range
+		{"0:a", "x1:1"},          // not an index
+		{"a", "1", "0"},          // dense row missing columns
+		{"0:a", "+1:1", "4:yes"}, // not an index
+	} {
+		if _, err := DensifyRows([][]string{dense[0], row}); err == nil {
+			t.Errorf("row %v should be rejected", row)
+		}
+	}
+}
+
+func TestSparseRowValues(t *testing.T) {
+	row := []string{"0:a", "3:3.5"}
+	if value, ok := RowValue(row, 5, 3); !ok || value != "3.5" {
+		t.Errorf("value of column 3: %s, %v", value, ok)
+	}
+	if value, ok := RowValue(row, 5, 2); ok || value != SparseZero {
+		t.Errorf("omitted column 2 should be 0, got: %s, %v", value, ok)
+	}
+	if value, ok := RowValue([]string{"a", "0", "1"}, 3, 2); !ok || value != "1" {
+		t.Errorf("value of dense column 2: %s, %v", value, ok)
+	}
+
+	indices, cells := RowCells(row, 5)
+	if !reflect.DeepEqual(indices, []int{0, 3}) || !reflect.DeepEqual(cells, []string{"a", "3.5"}) {
+		t.Errorf("cells of sparse row: %v, %v", indices, cells)
+	}
+	// the row of a sample with all features 0 is empty after the ID is removed
+	if indices, _ := RowCells([]string{}, 5); len(indices) != 0 {
+		t.Errorf("empty row should have no cells: %v", indices)
+	}
+}
+
+func TestPSISparseRows(t *testing.T) {
+	rows := [][]string{
+		{"x1", "id", "x2"},
+		{"1:b", "2:2"},
+		{"1", "a", "0"},
+		{"1:c"},
+	}
+	ids, err := RetrieveIDsFromFile(rows, "id")
+	checkErr(err, t)
+	if !reflect.DeepEqual(ids, []string{"b", "a", "c"}) {
+		t.Errorf("IDs: %v", ids)
+	}
+
+	newRows, err := RearrangeFileWithIntersectIDs(rows, "id", []string{"c", "b"})
+	checkErr(err, t)
+	want := [][]string{{"x1", "x2"}, {"1:2"}, {}}
+	if !reflect.DeepEqual(newRows, want) {
+		t.Errorf("rearranged rows: %v, want %v", newRows, want)
+	}
+	dense, err := DensifyRows(newRows)
+	checkErr(err, t)
+	if !reflect.DeepEqual(dense[1:], [][]string{{"0", "2"}, {"0", "0"}}) {
+		t.Errorf("dense rows: %v", dense)
+	}
+
+	// a sparse row omitting the ID is rejected rather than taken as the sample "0"
+	missing := [][]string{rows[0], {"0:1", "2:2"}}
+	if _, err := RetrieveIDsFromFile(missing, "id"); err == nil {
+		t.Error("row omitting the ID should be rejected")
+	}
+	if _, err := RearrangeFileWithIntersectIDs(missing, "id", []string{"0"}); err == nil {
+		t.Error("row omitting the ID should be rejected")
+	}
+}
+
+func TestSparseTrainSet(t *testing.T) {
+	rows := [][]string{
+		{"x1", "x2", "y"},
+		{"0:2", "2:1"},
+		{"1:-4"},
+		{"1", "0", "0.5"},
+	}
+	params := pb_common.TrainParams{IsTagPart: true, Label: "y", Scaling: ScalingMaxAbs}
+	if !UseSparseTrainSet(rows, params) {
+		t.Fatal("sparse rows scaled by maxabs should use the sparse train set")
+	}
+	for _, p := range []pb_common.TrainParams{
+		{IsTagPart: true, Label: "y"},
+		{IsTagPart: true, Label: "y", Scaling: ScalingNone, WeightColumn: "x2"},
+	} {
+		if UseSparseTrainSet(rows, p) {
+			t.Errorf("params %v should use the dense train set", p)
+		}
+	}
+
+	set, err := NewSparseTrainSet(rows, params, false)
+	checkErr(err, t)
+	if !reflect.DeepEqual(set.FeatureNames, []string{"x1", "x2", "y"}) {
+		t.Errorf("feature names: %v", set.FeatureNames)
+	}
+	if set.SigmaParams["x1"] != 2 || set.SigmaParams["x2"] != 4 || set.SigmaParams["y"] != 1 {
+		t.Errorf("sigmas: %v", set.SigmaParams)
+	}
+	want := []*SparseSample{
+		{ID: 0, Indices: []int{0}, Values: []float64{1}, Label: 1},
+		{ID: 1, Indices: []int{1}, Values: []float64{-1}, Label: 0},
+		{ID: 2, Indices: []int{0}, Values: []float64{0.5}, Label: 0.5},
+	}
+	if !reflect.DeepEqual(set.Samples, want) {
+		t.Errorf("samples: %v, want %v", set.Samples, want)
+	}
+	if summary := set.Summaries["x1"]; summary.Mean != 1 || summary.Min != 0 || summary.Max != 2 {
+		t.Errorf("summary of x1: %v", summary)
+	}
+	// the intercept is the first theta of the tag part
+	if dot := set.Samples[1].Dot([]float64{10, 1, 3}, 1); dot != -3 {
+		t.Errorf("dot of sample 1: %v", dot)
+	}
+
+	if _, err := NewSparseTrainSet([][]string{{"x1", "x2"}, {"0:1"}}, params, false); err == nil {
+		t.Error("samples without label should be rejected on the party with label")
+	}
+}
+
+func TestPredictSparseRows(t *testing.T) {
+	model := &pb_common.TrainModels{
+		Thetas:    map[string]float64{"Intercept": 0.5, "x1": 2, "x2": -1},
+		Xbars:     map[string]float64{"x1": 1, "x2": 0, "y": 0},
+		Sigmas:    map[string]float64{"x1": 2, "x2": 4, "y": 1},
+		IsTagPart: true,
+	}
+	rows := [][]string{
+		{"x1", "x2", "y"},
+		{"0:3"},
+		{"0", "8", "1"},
+	}
+	predictions, err := PredictSparseRows(rows, model)
+	checkErr(err, t)
+	// the prediction of the sample is the intercept plus the sum of thetas times the standardized features
+	want := []float64{0.5 + 2*(3-1)/2.0, 0.5 + 2*(0-1)/2.0 - 8/4.0}
+	for i := range want {
+		if math.Abs(predictions[i]-want[i]) > 1e-9 {
+			t.Errorf("prediction of sample %d: %v, want %v", i, predictions[i], want[i])
+		}
+	}
+}
@@ -26,42 +26,69 @@ import (
 // - round is loop round for training task
 // - needCheckReorder indicates whether reorder is needed
 func GetBatchSetBySize(trainSet [][]float64, params pb_common.TrainParams, round int, needCheckReorder bool) ([][]float64, [][]float64) {
-	sampleNum := len(trainSet)
+	order, start, end := batchOf(len(trainSet), params, round, needCheckReorder)
+	if order != nil {
+		newSet := make([][]float64, len(trainSet))
+		for i, idx := range order {
+			newSet[i] = trainSet[idx]
+		}
+		copy(trainSet[0:], newSet)
+	}
+
+	trainSetThisRound := make([][]float64, end-start)
+	copy(trainSetThisRound[0:], trainSet[start:end])
+	return trainSetThisRound, trainSet
+}
+
+// GetSparseBatchBySize get the sparse samples for specific round by batch size, samples are reordered in place
+// in the same way as GetBatchSetBySize reorders the dense train set, so the batches of both are the same
+func GetSparseBatchBySize(samples []*SparseSample, params pb_common.TrainParams, round int, needCheckReorder bool) []*SparseSample {
+	order, start, end := batchOf(len(samples), params, round, needCheckReorder)
+	if order != nil {
+		newSamples := make([]*SparseSample, len(samples))
+		for i, idx := range order {
+			newSamples[i] = samples[idx]
+		}
+		copy(samples[0:], newSamples)
+	}
+
+	batch := make([]*SparseSample, end-start)
+	copy(batch[0:], samples[start:end])
+	return batch
+}
 
+// batchOf returns the samples of round of sampleNum samples, which are from start to end after the samples are
+// rearranged in order, order is nil if the samples are kept as they are
+func batchOf(sampleNum int, params pb_common.TrainParams, round int, needCheckReorder bool) ([]int, int, int) {
 	// if batch size is zero or greater than sample num, return all train set
-	var trainSetThisRound [][]float64
 	if params.BatchSize == 0 || int(params.BatchSize) >= sampleNum {
-		trainSetThisRound = append(trainSetThisRound, trainSet...)
-		return trainSetThisRound, trainSet
+		return nil, 0, sampleNum
 	}
 
-	// number of train set for this round is batch size
-	trainSetThisRound = make([][]float64, params.BatchSize)
-
+	var order []int
 	segmentIdx := round % (sampleNum / int(params.BatchSize))
 	// if this loop just started, check if train set need to be reordered
 	// if all samples already used once, reorder train set and start from the first segment
 	if needCheckReorder && segmentIdx == 0 {
-		// determine if all samples are already used
-		newSet := randTrainSet(trainSet, round)
-		copy(trainSet[0:], newSet)
+		order = randOrder(sampleNum, round, params.Seed)
 	}
 
+	// number of train set for this round is batch size
 	start := segmentIdx * int(params.BatchSize)
-	copy(trainSetThisRound[0:], trainSet[start:start+int(params.BatchSize)])
-
-	return trainSetThisRound, trainSet
+	return order, start, start + int(params.BatchSize)
 }
 
-// randTrainSet rearrange train set in deterministic random order
-func randTrainSet(trainSet [][]float64, round int) [][]float64 {
-	reOrderedSet := make([][]float64, len(trainSet))
+// randOrder returns the indices of sampleNum samples in deterministic random order, seed changes the order if it isn't 0
+func randOrder(sampleNum int, round int, seed int64) []int {
 	// map hash(idx+round) to idx
 	idxHashMap := make(map[string]int)
 	var hashes []string
 
-	for i := 0; i < len(trainSet); i++ {
+	for i := 0; i < sampleNum; i++ {
 		msg := fmt.Sprintf("%d+%d", i, round)
+		if seed != 0 {
+			msg = fmt.Sprintf("%d+%d+%d", seed, i, round)
+		}
 		s := string(xchainCryptoClient.HashUsingSha256([]byte(msg)))
 		idxHashMap[s] = i
 		hashes = append(hashes, s)
@@ -70,11 +97,9 @@ func randTrainSet(trainSet [][]float64, round int) [][]float64 {
 	// sort hash(idx, round)
 	sort.Strings(hashes)
 
-	// reorder train set
+	order := make([]int, len(hashes))
 	for i := 0; i < len(hashes); i++ {
-		idx := idxHashMap[hashes[i]]
-		reOrderedSet[i] = trainSet[idx]
+		order[i] = idxHashMap[hashes[i]]
 	}
-
-	return reOrderedSet
+	return order
 }
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestGetBatchSetBySizeSeed(t *testing.T) {
+	newSet := func() [][]float64 {
+		var set [][]float64
+		for i := 0; i < 20; i++ {
+			set = append(set, []float64{float64(i)})
+		}
+		return set
+	}
+	batches := func(seed int64) [][][]float64 {
+		params := pb_common.TrainParams{BatchSize: 5, Seed: seed}
+		set := newSet()
+		var res [][][]float64
+		for round := 0; round < 8; round++ {
+			var batch [][]float64
+			batch, set = GetBatchSetBySize(set, params, round, true)
+			res = append(res, batch)
+		}
+		return res
+	}
+
+	if !reflect.DeepEqual(batches(42), batches(42)) {
+		t.Error("mini-batches of the same seed differ")
+	}
+	if reflect.DeepEqual(batches(42), batches(7)) {
+		t.Error("mini-batches of different seeds are the same")
+	}
+	if reflect.DeepEqual(batches(42), batches(0)) {
+		t.Error("mini-batches of a seed are the same as the ones not seeded")
+	}
+}
+
+func TestGetSparseBatchBySize(t *testing.T) {
+	params := pb_common.TrainParams{BatchSize: 3, Seed: 42}
+	var set [][]float64
+	var samples []*SparseSample
+	for i := 0; i < 10; i++ {
+		set = append(set, []float64{float64(i)})
+		samples = append(samples, &SparseSample{ID: i})
+	}
+
+	// the batches of sparse samples are the same as the ones of the dense train set
+	for round := 0; round < 8; round++ {
+		var batch [][]float64
+		batch, set = GetBatchSetBySize(set, params, round, true)
+		sparseBatch := GetSparseBatchBySize(samples, params, round, true)
+		if len(batch) != len(sparseBatch) {
+			t.Fatalf("round %d: %d sparse samples, want %d", round, len(sparseBatch), len(batch))
+		}
+		for i := range batch {
+			if int(batch[i][0]) != sparseBatch[i].ID {
+				t.Errorf("round %d: sample %d of the sparse batch is %d, want %v", round, i, sparseBatch[i].ID, batch[i][0])
+			}
+		}
+	}
+}
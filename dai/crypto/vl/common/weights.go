@@ -0,0 +1,135 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// ExtractWeights removes the weight column from the training samples fileRows of the party with label,
+// and returns the weights keyed by the IDs of the samples, which is the index of the sample in fileRows minus 1.
+// Weights should be non-negative and not all 0.
+func ExtractWeights(fileRows [][]string, column string) ([][]string, map[int]float64, error) {
+	if len(fileRows) == 0 {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "empty samples")
+	}
+	index := -1
+	for i, name := range fileRows[0] {
+		if name == column {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "weight column %s does not exist in samples", column)
+	}
+
+	rows := make([][]string, len(fileRows))
+	weights := make(map[int]float64, len(fileRows)-1)
+	var total float64
+	for i, row := range fileRows {
+		rows[i] = append(append([]string{}, row[:index]...), row[index+1:]...)
+		if i == 0 {
+			continue
+		}
+		w, err := strconv.ParseFloat(row[index], 64)
+		if err != nil {
+			return nil, nil, errorx.New(errcodes.ErrCodeParam, "failed to parse weight of sample %d: %s", i, err.Error())
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, nil, errorx.New(errcodes.ErrCodeParam, "invalid weight %s of sample %d, should be non-negative", row[index], i)
+		}
+		weights[i-1] = w
+		total += w
+	}
+	if total == 0 {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "weights of samples are all 0")
+	}
+	return rows, weights, nil
+}
+
+// normalizeWeights returns the weights of the samples of ids divided by their sum.
+// The samples are equally weighted if weights is nil or the weights of the samples are all 0.
+func normalizeWeights(ids []int, weights map[int]float64) map[int]float64 {
+	normalized := make(map[int]float64, len(ids))
+	var total float64
+	for _, id := range ids {
+		total += weights[id]
+	}
+	for _, id := range ids {
+		if total == 0 {
+			normalized[id] = 1 / float64(len(ids))
+		} else {
+			normalized[id] = weights[id] / total
+		}
+	}
+	return normalized
+}
+
+// WeightValues scales the per-sample values of the party with label, such as its gradients and costs
+// retrieved from the decrypted ones, by the weights of the samples, so that their mean is the weighted mean
+// of the original values, values is returned as it is if weights is nil
+func WeightValues(values map[int]float64, weights map[int]float64) map[int]float64 {
+	if weights == nil {
+		return values
+	}
+	ids := make([]int, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	normalized := normalizeWeights(ids, weights)
+	weighted := make(map[int]float64, len(values))
+	for id, v := range values {
+		weighted[id] = v * normalized[id] * float64(len(values))
+	}
+	return weighted
+}
+
+// WeightDecrypted replaces each of the decrypted per-sample values of the party without label by their mean
+// weighted by the weights of the samples, values is returned as it is if weights is nil.
+// The party without label gets the weighted gradients and costs by the same mean of the values, and the noise
+// it added to each value is kept as the weights add up to 1, while the values of the samples are no longer revealed.
+func WeightDecrypted(values map[int]*big.Int, weights map[int]float64) map[int]*big.Int {
+	if weights == nil {
+		return values
+	}
+	ids := make([]int, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	normalized := normalizeWeights(ids, weights)
+	sum := new(big.Float)
+	for id, v := range values {
+		term := new(big.Float).SetInt(v)
+		sum.Add(sum, term.Mul(term, big.NewFloat(normalized[id])))
+	}
+	// rounded to the nearest integer, the error is negligible against the precision of the encoded values
+	if sum.Sign() >= 0 {
+		sum.Add(sum, big.NewFloat(0.5))
+	} else {
+		sum.Sub(sum, big.NewFloat(0.5))
+	}
+	mean, _ := sum.Int(nil)
+	weighted := make(map[int]*big.Int, len(values))
+	for id := range values {
+		weighted[id] = new(big.Int).Set(mean)
+	}
+	return weighted
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestExtractWeights(t *testing.T) {
+	fileRows := [][]string{
+		{"a", "w", "y"},
+		{"1", "0.5", "0"},
+		{"2", "0", "1"},
+		{"3", "1.5", "1"},
+	}
+	rows, weights, err := ExtractWeights(fileRows, "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rows[0], ",") != "a,y" || strings.Join(rows[3], ",") != "3,1" {
+		t.Errorf("expected weight column removed, got %v", rows)
+	}
+	if fileRows[0][1] != "w" {
+		t.Error("expected fileRows unchanged")
+	}
+	if len(weights) != 3 || weights[0] != 0.5 || weights[1] != 0 || weights[2] != 1.5 {
+		t.Errorf("unexpected weights %v", weights)
+	}
+
+	invalid := map[string][][]string{
+		"missing column": {{"a", "y"}, {"1", "0"}},
+		"negative":       {{"a", "w"}, {"1", "-1"}},
+		"not a number":   {{"a", "w"}, {"1", "x"}},
+		"NaN":            {{"a", "w"}, {"1", "NaN"}},
+		"all 0":          {{"a", "w"}, {"1", "0"}, {"2", "0"}},
+	}
+	for name, rows := range invalid {
+		if _, _, err := ExtractWeights(rows, "w"); err == nil {
+			t.Errorf("expected error for weights %s", name)
+		}
+	}
+}
+
+func TestWeightValues(t *testing.T) {
+	values := map[int]float64{0: 1, 1: 2, 2: 3}
+	if w := WeightValues(values, nil); len(w) != 3 || w[1] != 2 {
+		t.Errorf("expected values unchanged without weights, got %v", w)
+	}
+
+	// the mean of the weighted values is the weighted mean, the weights of samples out of values are ignored
+	weights := map[int]float64{0: 1, 1: 0, 2: 3, 3: 100}
+	var sum float64
+	for _, v := range WeightValues(values, weights) {
+		sum += v
+	}
+	if mean := sum / 3; math.Abs(mean-2.5) > 1e-9 {
+		t.Errorf("expected weighted mean 2.5, got %v", mean)
+	}
+
+	// samples are equally weighted if their weights are all 0
+	if w := WeightValues(values, map[int]float64{0: 0, 1: 0, 2: 0}); math.Abs(w[0]-1) > 1e-9 || math.Abs(w[2]-3) > 1e-9 {
+		t.Errorf("expected values unchanged with zero weights, got %v", w)
+	}
+}
+
+func TestWeightDecrypted(t *testing.T) {
+	// values are encoded by a precision and carry the same noise, the noise is kept in the weighted mean
+	noise, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	values := make(map[int]*big.Int)
+	for id, v := range []int64{100, 200, 300} {
+		values[id] = new(big.Int).Add(big.NewInt(v), noise)
+	}
+	weighted := WeightDecrypted(values, map[int]float64{0: 1, 1: 0, 2: 3})
+	expected := new(big.Int).Add(big.NewInt(250), noise)
+	if len(weighted) != 3 {
+		t.Fatalf("expected 3 values, got %d", len(weighted))
+	}
+	for id, v := range weighted {
+		if v.Cmp(expected) != 0 {
+			t.Errorf("expected weighted mean %v of sample %d, got %v", expected, id, v)
+		}
+	}
+
+	if w := WeightDecrypted(values, nil); w[1].Cmp(values[1]) != 0 {
+		t.Errorf("expected values unchanged without weights, got %v", w)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package estimate estimates the runtime, peak memory and bytes transferred of a task before it is published,
+// by a coarse model of the operations of PSI and the algorithms on the numbers of samples and features.
+// The estimate helps to right-size a task, the actual cost depends on the hardware, the network,
+// the distribution of samples and how fast the training converges.
+package estimate
+
+import (
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// DefaultRounds is the number of training rounds assumed for linear-vl and logistic-vl, and the number of epochs
+// for dnn-paddlefl-vl, if it's not bounded by the task, since the round training converges in is unknown in advance
+const DefaultRounds = 100
+
+// Costs of operations, measured roughly on one core of 2.5GHz with the default key length of Paillier
+const (
+	encryptCost  = 2 * time.Millisecond   // Paillier encryption or decryption of a value
+	homoMulCost  = 500 * time.Microsecond // multiplication of a ciphertext by a plaintext
+	homoAddCost  = 20 * time.Microsecond  // addition of two ciphertexts
+	psiCost      = 200 * time.Microsecond // encryption of an ID in PSI
+	mpcCost      = 50 * time.Microsecond  // operations on the secret shares of a value in an epoch of dnn-paddlefl-vl
+	messageCost  = 20 * time.Millisecond  // latency of a message between executors
+	predictCost  = 10 * time.Microsecond  // plaintext prediction of a value
+	startupCost  = 5 * time.Second        // downloading samples, exchanging keys and storing the model or outcomes
+	dnnStartCost = 60 * time.Second       // starting the PaddleFL containers of dnn-paddlefl-vl
+	roundLatency = 6 * messageCost        // messages exchanged in a round of linear-vl and logistic-vl
+	nodeLatency  = 2 * messageCost        // messages exchanged for a node of a tree of xgboost-vl
+	idLatency    = 4 * messageCost        // messages exchanged in PSI
+)
+
+// Sizes of values in bytes
+const (
+	ciphertextBytes = 300 // Paillier ciphertext encoded in messages
+	plaintextBytes  = 40  // decrypted value encoded in messages
+	psiIDBytes      = 100 // encrypted ID in PSI
+	shareBytes      = 24  // secret shares of a value of dnn-paddlefl-vl, sent in an epoch
+	fileCellBytes   = 8   // a value in the sample file
+	cellBytes       = 120 // a value of the samples loaded by the algorithms, with the original and the standardized copies
+)
+
+// Party is the sample file of a party of the task
+type Party struct {
+	Rows    int64 // number of samples
+	Columns int64 // number of columns used by the task, including the ID and the label
+	Bytes   int64 // size of the file, estimated by Rows and Columns if 0
+}
+
+// Estimate is a rough estimate of the cost of a task
+type Estimate struct {
+	Samples       int64         // samples aligned by PSI, no more than the rows of the smallest sample file
+	Rounds        int64         // rounds of a training, trees of xgboost-vl and epochs of dnn-paddlefl-vl, 0 for prediction
+	Trainings     int64         // number of trainings, including the ones for model evaluation
+	Duration      time.Duration // runtime of the task
+	PeakMemory    int64         // peak memory in bytes of the executor with the largest sample file
+	TransferBytes int64         // bytes transferred between all the parties
+}
+
+// Task estimates the cost of the task with params executed by parties, rounds is the number of rounds of each training,
+// and it's decided by params if it's 0, which is the number of trees of xgboost-vl, the rounds of incremental training
+// or differential privacy if they're set, otherwise DefaultRounds
+func Task(params *pb_common.TaskParams, parties []Party, rounds int64) (*Estimate, error) {
+	if len(parties) < 2 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "at least two parties are required, got: %d", len(parties))
+	}
+	if rounds < 0 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "rounds can not be negative")
+	}
+	samples := parties[0].Rows
+	for _, p := range parties {
+		if p.Rows < 0 || p.Columns < 0 || p.Bytes < 0 {
+			return nil, errorx.New(errcodes.ErrCodeParam, "rows, columns and bytes of sample files can not be negative")
+		}
+		if p.Rows < samples {
+			samples = p.Rows
+		}
+	}
+
+	e := &Estimate{Samples: samples}
+	duration, transfer := psi(parties)
+	duration += startupCost
+	if params.GetAlgo() == pb_common.Algorithm_DNN_PADDLEFL_VL {
+		duration += dnnStartCost
+	}
+
+	var roundDuration time.Duration
+	var roundTransfer, roundMemory int64
+	if params.GetTaskType() == pb_common.TaskType_PREDICT {
+		roundDuration, roundTransfer = prediction(params, parties, samples)
+		duration += roundDuration
+		transfer += roundTransfer
+	} else {
+		e.Rounds = trainingRounds(params, rounds)
+		e.Trainings = trainings(params, samples)
+		switch params.GetAlgo() {
+		case pb_common.Algorithm_LINEAR_REGRESSION_VL, pb_common.Algorithm_LOGIC_REGRESSION_VL:
+			roundDuration, roundTransfer, roundMemory = regressionRound(params, parties, samples)
+		case pb_common.Algorithm_XGBOOST_VL:
+			roundDuration, roundTransfer, roundMemory = xgboostTree(params, parties, samples)
+		case pb_common.Algorithm_DNN_PADDLEFL_VL:
+			roundDuration, roundTransfer, roundMemory = dnnEpoch(parties, samples)
+		default:
+			return nil, errorx.New(errcodes.ErrCodeParam, "unsupported algorithm %s", params.GetAlgo())
+		}
+		// the samples are split for model evaluation, so each training is assumed to take all of them
+		duration += time.Duration(e.Trainings*e.Rounds) * roundDuration
+		transfer += e.Trainings * e.Rounds * roundTransfer
+	}
+	e.Duration = duration
+	e.TransferBytes = transfer
+
+	// the samples of all parties are loaded, the largest one takes the most memory
+	for _, p := range parties {
+		fileBytes := p.Bytes
+		if fileBytes == 0 {
+			fileBytes = p.Rows * p.Columns * fileCellBytes
+		}
+		memory := fileBytes + p.Rows*p.Columns*cellBytes + 2*p.Rows*psiIDBytes + roundMemory
+		if memory > e.PeakMemory {
+			e.PeakMemory = memory
+		}
+	}
+	return e, nil
+}
+
+// trainingRounds returns the rounds of a training
+func trainingRounds(params *pb_common.TaskParams, rounds int64) int64 {
+	if rounds > 0 {
+		return rounds
+	}
+	tp := params.GetTrainParams()
+	if params.GetAlgo() == pb_common.Algorithm_XGBOOST_VL {
+		if n := tp.GetXgbParams().GetNEstimators(); n > 0 {
+			return n
+		}
+		return DefaultRounds
+	}
+	rounds = DefaultRounds
+	if tp.GetIncremental() && tp.GetUpdateRounds() > 0 {
+		rounds = tp.GetUpdateRounds()
+	}
+	// training stops when the privacy budget is used up
+	if dp := tp.GetDp().GetRounds(); dp > 0 && dp < rounds {
+		rounds = dp
+	}
+	return rounds
+}
+
+// trainings returns the number of trainings, model evaluation trains a model for each division of samples besides the final one
+func trainings(params *pb_common.TaskParams, samples int64) int64 {
+	ev := params.GetEvalParams()
+	if !ev.GetEnable() {
+		return 1
+	}
+	switch ev.GetEvalRule() {
+	case pb_common.EvaluationRule_ErCrossVal:
+		if folds := int64(ev.GetCv().GetFolds()); folds > 0 {
+			return 1 + folds
+		}
+		return 1 + 10
+	case pb_common.EvaluationRule_ErLOO:
+		return 1 + samples
+	default:
+		return 1 + 1
+	}
+}
+
+// psi estimates PSI, which aligns the samples of each pair of parties, each party encrypts its IDs and the other's
+func psi(parties []Party) (time.Duration, int64) {
+	var duration time.Duration
+	var transfer int64
+	for i := 0; i < len(parties); i++ {
+		for j := i + 1; j < len(parties); j++ {
+			ids := parties[i].Rows + parties[j].Rows
+			duration += time.Duration(ids)*psiCost + idLatency
+			transfer += 2 * ids * psiIDBytes
+		}
+	}
+	return duration, transfer
+}
+
+// features returns the number of features of a party, the ID column is removed and the label is replaced by the intercept
+func features(p Party) int64 {
+	if p.Columns <= 1 {
+		return 1
+	}
+	return p.Columns - 1
+}
+
+// regressionRound estimates a round of linear-vl and logistic-vl on batch samples, each party encrypts the partial
+// predictions of the batch, computes the encrypted gradient of each of its features on each sample,
+// and the other party decrypts them, the parties compute in parallel
+func regressionRound(params *pb_common.TaskParams, parties []Party, samples int64) (time.Duration, int64, int64) {
+	batch := params.GetTrainParams().GetBatchSize()
+	if batch <= 0 || batch > samples {
+		batch = samples
+	}
+	var duration time.Duration
+	var transfer, memory int64
+	for i, p := range parties {
+		other := parties[(i+1)%len(parties)]
+		fp, fo := features(p), features(other)
+		ops := time.Duration(2*batch+fo*batch+batch)*encryptCost + time.Duration(fp*batch+batch)*homoMulCost
+		if ops > duration {
+			duration = ops
+		}
+		ciphertexts := 2*batch + fp*batch + batch
+		transfer += ciphertexts*ciphertextBytes + (fp*batch+batch)*plaintextBytes
+		if m := 2 * ciphertexts * ciphertextBytes; m > memory {
+			memory = m
+		}
+	}
+	return duration + roundLatency, transfer, memory
+}
+
+// xgboostTree estimates a tree of xgboost-vl, the party with label encrypts the gradients and hessians of samples,
+// the other party sums them into the bins of its features for each node, which are decrypted by the party with label.
+// The party with label is unknown here, so the larger cost of the parties taken as the other one is estimated.
+func xgboostTree(params *pb_common.TaskParams, parties []Party, samples int64) (time.Duration, int64, int64) {
+	depth := params.GetTrainParams().GetXgbParams().GetMaxDepth()
+	if depth <= 0 {
+		depth = 3
+	}
+	nodes := int64(1)<<uint(depth) - 1
+	var duration time.Duration
+	var transfer int64
+	for _, p := range parties {
+		bins := 2 * nodes * features(p) * xgboost.MaxBins
+		d := time.Duration(2*samples)*encryptCost + time.Duration(2*depth*samples*features(p))*homoAddCost +
+			time.Duration(bins)*encryptCost + time.Duration(nodes)*nodeLatency
+		if d > duration {
+			duration = d
+		}
+		if t := (2*samples + bins) * ciphertextBytes; t > transfer {
+			transfer = t
+		}
+	}
+	return duration, transfer, transfer
+}
+
+// dnnEpoch estimates an epoch of dnn-paddlefl-vl, the parties compute on the secret shares of all the features
+func dnnEpoch(parties []Party, samples int64) (time.Duration, int64, int64) {
+	var values int64
+	for _, p := range parties {
+		values += samples * features(p)
+	}
+	return time.Duration(values) * mpcCost, int64(len(parties)) * values * shareBytes, values * shareBytes
+}
+
+// prediction estimates the prediction of samples, each party predicts its part and the parts are combined
+func prediction(params *pb_common.TaskParams, parties []Party, samples int64) (time.Duration, int64) {
+	var values int64
+	for _, p := range parties {
+		values += samples * features(p)
+	}
+	duration := time.Duration(values)*predictCost + roundLatency
+	transfer := int64(len(parties)) * samples * plaintextBytes
+	switch params.GetAlgo() {
+	case pb_common.Algorithm_XGBOOST_VL:
+		// each node of the trees on the path of a sample held by the other party is queried
+		trees := params.GetTrainParams().GetXgbParams().GetNEstimators()
+		depth := params.GetTrainParams().GetXgbParams().GetMaxDepth()
+		if trees <= 0 {
+			trees = 10
+		}
+		if depth <= 0 {
+			depth = 3
+		}
+		transfer = samples * trees * depth * plaintextBytes
+	case pb_common.Algorithm_DNN_PADDLEFL_VL:
+		duration = time.Duration(values)*mpcCost + roundLatency
+		transfer = int64(len(parties)) * values * shareBytes
+	}
+	return duration, transfer
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package estimate
+
+import (
+	"testing"
+
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func trainParams(algo pb_common.Algorithm, batchSize int64) *pb_common.TaskParams {
+	return &pb_common.TaskParams{
+		Algo:        algo,
+		TaskType:    pb_common.TaskType_LEARN,
+		TrainParams: &pb_common.TrainParams{BatchSize: batchSize},
+	}
+}
+
+func TestTask(t *testing.T) {
+	parties := []Party{{Rows: 1000, Columns: 10}, {Rows: 800, Columns: 5}}
+	params := trainParams(pb_common.Algorithm_LINEAR_REGRESSION_VL, 0)
+
+	e, err := Task(params, parties, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Samples != 800 || e.Rounds != DefaultRounds || e.Trainings != 1 {
+		t.Errorf("unexpected estimate: %+v", e)
+	}
+	if e.Duration <= 0 || e.PeakMemory <= 0 || e.TransferBytes <= 0 {
+		t.Errorf("costs should be positive: %+v", e)
+	}
+
+	// costs grow with samples, features, rounds and evaluation
+	more, _ := Task(params, []Party{{Rows: 10000, Columns: 10}, {Rows: 8000, Columns: 5}}, 0)
+	wider, _ := Task(params, []Party{{Rows: 1000, Columns: 100}, {Rows: 800, Columns: 50}}, 0)
+	longer, _ := Task(params, parties, 2*DefaultRounds)
+	for name, other := range map[string]*Estimate{"samples": more, "features": wider, "rounds": longer} {
+		if other.Duration <= e.Duration || other.TransferBytes <= e.TransferBytes {
+			t.Errorf("costs should grow with %s: %+v, base %+v", name, other, e)
+		}
+	}
+	if more.PeakMemory <= e.PeakMemory || wider.PeakMemory <= e.PeakMemory {
+		t.Errorf("memory should grow with samples and features: %+v, %+v, base %+v", more, wider, e)
+	}
+
+	// mini-batches take less in each round
+	batched, _ := Task(trainParams(pb_common.Algorithm_LINEAR_REGRESSION_VL, 10), parties, 0)
+	if batched.Duration >= e.Duration {
+		t.Errorf("mini-batch training should be faster: %v, BGD %v", batched.Duration, e.Duration)
+	}
+
+	params.EvalParams = &pb_common.EvaluationParams{
+		Enable:   true,
+		EvalRule: pb_common.EvaluationRule_ErCrossVal,
+		Cv:       &pb_common.CrossVal{Folds: 5},
+	}
+	evaluated, _ := Task(params, parties, 0)
+	if evaluated.Trainings != 6 {
+		t.Errorf("5-fold cross validation should train 6 models, got %d", evaluated.Trainings)
+	}
+}
+
+func TestTaskRounds(t *testing.T) {
+	parties := []Party{{Rows: 100, Columns: 4}, {Rows: 100, Columns: 4}}
+
+	xgb := trainParams(pb_common.Algorithm_XGBOOST_VL, 0)
+	xgb.TrainParams.XgbParams = &pb_common.XGBoostParams{MaxDepth: 3, NEstimators: 20}
+	e, err := Task(xgb, parties, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Rounds != 20 {
+		t.Errorf("xgboost-vl should take a round for each tree, got %d", e.Rounds)
+	}
+
+	dp := trainParams(pb_common.Algorithm_LOGIC_REGRESSION_VL, 0)
+	dp.TrainParams.Dp = &pb_common.DPParams{Rounds: 30}
+	if e, _ := Task(dp, parties, 0); e.Rounds != 30 {
+		t.Errorf("training should stop when the privacy budget is used up, got %d rounds", e.Rounds)
+	}
+
+	predict := &pb_common.TaskParams{Algo: pb_common.Algorithm_LOGIC_REGRESSION_VL, TaskType: pb_common.TaskType_PREDICT}
+	if e, _ := Task(predict, parties, 0); e.Rounds != 0 || e.Trainings != 0 || e.Duration <= 0 {
+		t.Errorf("unexpected estimate of prediction: %+v", e)
+	}
+
+	if _, err := Task(dp, parties[:1], 0); err == nil {
+		t.Error("task of one party should be rejected")
+	}
+	if _, err := Task(dp, parties, -1); err == nil {
+		t.Error("negative rounds should be rejected")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export converts the models of vertical linear and logistic regression into standard formats,
+// so that they can be served outside PaddleDTX. Each executor holds only the local part of a model,
+// the parts of all parties are combined into one model taking all the features.
+package export
+
+import (
+	"sort"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// Supported formats of the exported models
+const (
+	FormatONNX = "onnx"
+	FormatPMML = "pmml"
+)
+
+// intercept is the key of the intercept in the thetas of the party with label
+const intercept = "Intercept"
+
+// feature is an input of the combined model, scaled as (x-xbar)/sigma before multiplied by theta
+type feature struct {
+	name  string
+	xbar  float64
+	sigma float64
+	theta float64
+}
+
+// model is the combined model of all parties, whose output is link(intercept + sum(theta*(x-xbar)/sigma)),
+// link is the sigmoid function for logistic regression, and y*labelSigma+labelXbar for linear regression
+type model struct {
+	algo       pb_common.Algorithm
+	features   []feature
+	intercept  float64
+	label      string
+	labelXbar  float64
+	labelSigma float64
+}
+
+// CheckFormat checks if the format is supported
+func CheckFormat(format string) error {
+	switch format {
+	case FormatONNX, FormatPMML:
+		return nil
+	}
+	return errorx.New(errcodes.ErrCodeParam, "invalid model format %s, should be %s or %s", format, FormatONNX, FormatPMML)
+}
+
+// Export converts the model trained by algo into format, parts are the local models of all the parties in the order
+// of the inputs of the exported model, exactly one of them is the part with label.
+// The feature scaling is embedded in the exported model, so that it takes the original features,
+// and outputs the same as the prediction of PaddleDTX.
+func Export(algo pb_common.Algorithm, parts []*pb_common.TrainModels, format string) ([]byte, error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+	m, err := combine(algo, parts)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatONNX {
+		return m.onnx(), nil
+	}
+	return m.pmml()
+}
+
+// combine builds the model of all parts, the features of each part are sorted by name
+func combine(algo pb_common.Algorithm, parts []*pb_common.TrainModels) (*model, error) {
+	if algo != pb_common.Algorithm_LINEAR_REGRESSION_VL && algo != pb_common.Algorithm_LOGIC_REGRESSION_VL {
+		return nil, errorx.New(errcodes.ErrCodeParam, "models of %s can't be exported, only linear and logistic regression are supported", algo)
+	}
+	m := &model{algo: algo}
+	names := make(map[string]bool)
+	tagParts := 0
+	for _, part := range parts {
+		if len(part.Categories) > 0 {
+			return nil, errorx.New(errcodes.ErrCodeParam, "models with categorical columns can't be exported")
+		}
+		if part.IsTagPart {
+			tagParts++
+			m.intercept = part.Thetas[intercept]
+			m.label = part.Label
+			m.labelXbar, m.labelSigma = part.Xbars[part.Label], part.Sigmas[part.Label]
+		}
+		var features []string
+		for name := range part.Thetas {
+			if part.IsTagPart && name == intercept {
+				continue
+			}
+			if names[name] {
+				return nil, errorx.New(errcodes.ErrCodeParam, "feature %s is in more than one part of the model", name)
+			}
+			names[name] = true
+			features = append(features, name)
+		}
+		sort.Strings(features)
+		for _, name := range features {
+			m.features = append(m.features, feature{
+				name:  name,
+				xbar:  part.Xbars[name],
+				sigma: part.Sigmas[name],
+				theta: part.Thetas[name],
+			})
+		}
+	}
+	if tagParts != 1 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "the model should have exactly one part with label, got %d", tagParts)
+	}
+	return m, nil
+}
+
+// logistic returns whether the output is the probability of logistic regression
+func (m *model) logistic() bool {
+	return m.algo == pb_common.Algorithm_LOGIC_REGRESSION_VL
+}
+
+// featureNames returns the names of the features in the order of the inputs
+func (m *model) featureNames() []string {
+	names := make([]string, len(m.features))
+	for i, f := range m.features {
+		names[i] = f.name
+	}
+	return names
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/linear"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/logic"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+var (
+	tagPart = &pb_common.TrainModels{
+		Thetas:    map[string]float64{"Intercept": 0.3, "b": -1.2, "a": 0.8},
+		Xbars:     map[string]float64{"a": 2, "b": 10, "y": 5},
+		Sigmas:    map[string]float64{"a": 0.5, "b": 4, "y": 3},
+		Label:     "y",
+		IsTagPart: true,
+	}
+	otherPart = &pb_common.TrainModels{
+		Thetas: map[string]float64{"c": 2.5},
+		Xbars:  map[string]float64{"c": 0}, // not scaled
+		Sigmas: map[string]float64{"c": 1},
+		Label:  "y",
+	}
+	samples = [][]float64{{1, 3, 0.5}, {2.5, 12, -1}, {0, 0, 0}}
+)
+
+// partRows returns the sample rows of the features of the part, features are the columns of samples
+func partRows(features []string, columns ...int) [][]string {
+	rows := [][]string{features}
+	for _, sample := range samples {
+		var row []string
+		for _, c := range columns {
+			row = append(row, strconv.FormatFloat(sample[c], 'g', -1, 64))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// predict returns the predictions of PaddleDTX
+func predict(t *testing.T, algo pb_common.Algorithm) []float64 {
+	predictPart := linear.PredictLocalPart
+	if algo == pb_common.Algorithm_LOGIC_REGRESSION_VL {
+		predictPart = logic.PredictLocalPart
+	}
+	local, err := predictPart(partRows([]string{"a", "b"}, 0, 1), tagPart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := predictPart(partRows([]string{"c"}, 2), otherPart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if algo == pb_common.Algorithm_LOGIC_REGRESSION_VL {
+		return logic.CalRealPredictValue(local, other)
+	}
+	return linear.DeStandardizeOutput(tagPart, local, other)
+}
+
+// evalONNX evaluates the graph exported by model.onnx with the constants decoded from the initializers
+func evalONNX(t *testing.T, bs []byte, logistic bool) []float64 {
+	tensors := make(map[string][]float64)
+	var ops []string
+	forEach := func(bs []byte, f func(num protowire.Number, v []byte)) {
+		for len(bs) > 0 {
+			num, typ, n := protowire.ConsumeTag(bs)
+			bs = bs[n:]
+			n = protowire.ConsumeFieldValue(num, typ, bs)
+			if typ == protowire.BytesType {
+				v, _ := protowire.ConsumeBytes(bs)
+				f(num, v)
+			}
+			bs = bs[n:]
+		}
+	}
+	forEach(bs, func(num protowire.Number, graph []byte) {
+		if num != 7 {
+			return
+		}
+		forEach(graph, func(num protowire.Number, v []byte) {
+			switch num {
+			case 1:
+				forEach(v, func(num protowire.Number, v []byte) {
+					if num == 4 {
+						ops = append(ops, string(v))
+					}
+				})
+			case 5:
+				var name string
+				var values []float64
+				forEach(v, func(num protowire.Number, v []byte) {
+					switch num {
+					case 8:
+						name = string(v)
+					case 10:
+						for ; len(v) > 0; v = v[8:] {
+							bits, _ := protowire.ConsumeFixed64(v)
+							values = append(values, math.Float64frombits(bits))
+						}
+					}
+				})
+				tensors[name] = values
+			}
+		})
+	})
+	expectedOps := "Sub,Div,MatMul,Add,Mul,Add"
+	if logistic {
+		expectedOps = "Sub,Div,MatMul,Add,Sigmoid"
+	}
+	if strings.Join(ops, ",") != expectedOps {
+		t.Fatalf("expected operators %s, got %v", expectedOps, ops)
+	}
+
+	var out []float64
+	for _, sample := range samples {
+		y := tensors["intercept"][0]
+		for i, x := range sample {
+			y += (x - tensors["xbars"][i]) / tensors["sigmas"][i] * tensors["thetas"][i]
+		}
+		if logistic {
+			y = 1 / (1 + math.Exp(-y))
+		} else {
+			y = y*tensors["labelSigma"][0] + tensors["labelXbar"][0]
+		}
+		out = append(out, y)
+	}
+	return out
+}
+
+// evalPMML evaluates the RegressionModel exported by model.pmml
+func evalPMML(t *testing.T, bs []byte) []float64 {
+	var doc pmmlDoc
+	if err := xml.Unmarshal(bs, &doc); err != nil {
+		t.Fatal(err)
+	}
+	reg := doc.RegressionModel
+	var out []float64
+	for _, sample := range samples {
+		y := reg.RegressionTable.Intercept
+		for i, x := range sample {
+			field := reg.DerivedFields[i]
+			xbar, _ := strconv.ParseFloat(field.Apply.Apply.Constants[0].Value, 64)
+			sigma, _ := strconv.ParseFloat(field.Apply.Constants[0].Value, 64)
+			if field.Apply.Apply.FieldRef.Field != doc.DataDictionary.DataFields[i].Name ||
+				reg.RegressionTable.Predictors[i].Name != field.Name {
+				t.Fatalf("unexpected derived field %+v of input %d", field, i)
+			}
+			y += (x - xbar) / sigma * reg.RegressionTable.Predictors[i].Coefficient
+		}
+		switch reg.NormalizationMethod {
+		case "logit":
+			y = 1 / (1 + math.Exp(-y))
+		case "none":
+			y = y*reg.Targets[0].RescaleFactor + reg.Targets[0].RescaleConstant
+		}
+		out = append(out, y)
+	}
+	return out
+}
+
+func TestExport(t *testing.T) {
+	for _, algo := range []pb_common.Algorithm{pb_common.Algorithm_LINEAR_REGRESSION_VL, pb_common.Algorithm_LOGIC_REGRESSION_VL} {
+		expected := predict(t, algo)
+		logistic := algo == pb_common.Algorithm_LOGIC_REGRESSION_VL
+
+		bs, err := Export(algo, []*pb_common.TrainModels{tagPart, otherPart}, FormatONNX)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClose(t, algo.String()+" onnx", expected, evalONNX(t, bs, logistic))
+
+		bs, err = Export(algo, []*pb_common.TrainModels{tagPart, otherPart}, FormatPMML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClose(t, algo.String()+" pmml", expected, evalPMML(t, bs))
+	}
+
+	if _, err := Export(pb_common.Algorithm_XGBOOST_VL, []*pb_common.TrainModels{tagPart}, FormatONNX); err == nil {
+		t.Error("expected error for xgboost")
+	}
+	if _, err := Export(pb_common.Algorithm_LINEAR_REGRESSION_VL, []*pb_common.TrainModels{otherPart}, FormatONNX); err == nil {
+		t.Error("expected error for the model without the part with label")
+	}
+	if _, err := Export(pb_common.Algorithm_LINEAR_REGRESSION_VL, []*pb_common.TrainModels{tagPart, otherPart}, "pickle"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func checkClose(t *testing.T, name string, expected, got []float64) {
+	if len(expected) != len(got) {
+		t.Fatalf("%s: expected %d predictions, got %d", name, len(expected), len(got))
+	}
+	for i := range expected {
+		if math.Abs(expected[i]-got[i]) > 1e-9 {
+			t.Errorf("%s: expected prediction %v of sample %d, got %v", name, expected[i], i, got[i])
+		}
+	}
+}
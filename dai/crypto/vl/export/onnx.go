@@ -0,0 +1,134 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"math"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Versions of the ONNX model and the operator set
+const (
+	onnxIRVersion = 7
+	onnxOpset     = 13
+)
+
+// onnxDouble is the TensorProto.DataType of float64
+const onnxDouble = 11
+
+// Names of the input and output of the ONNX graph
+const (
+	onnxInput  = "X"
+	onnxOutput = "Y"
+)
+
+// pb appends the fields of a protobuf message, the field numbers are the ones of onnx.proto
+type pb []byte
+
+func (p pb) str(num protowire.Number, s string) pb {
+	p = protowire.AppendTag(p, num, protowire.BytesType)
+	return protowire.AppendString(p, s)
+}
+
+func (p pb) msg(num protowire.Number, m pb) pb {
+	p = protowire.AppendTag(p, num, protowire.BytesType)
+	return protowire.AppendBytes(p, m)
+}
+
+func (p pb) int(num protowire.Number, v int64) pb {
+	p = protowire.AppendTag(p, num, protowire.VarintType)
+	return protowire.AppendVarint(p, uint64(v))
+}
+
+func (p pb) doubles(num protowire.Number, vs []float64) pb {
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendFixed64(packed, math.Float64bits(v))
+	}
+	p = protowire.AppendTag(p, num, protowire.BytesType)
+	return protowire.AppendBytes(p, packed)
+}
+
+// onnxTensor returns the TensorProto of the constant named name
+func onnxTensor(name string, dims []int64, values []float64) pb {
+	var t pb
+	for _, d := range dims {
+		t = t.int(1, d)
+	}
+	return t.int(2, onnxDouble).str(8, name).doubles(10, values)
+}
+
+// onnxNode returns the NodeProto of op
+func onnxNode(op string, inputs []string, output string) pb {
+	var n pb
+	for _, in := range inputs {
+		n = n.str(1, in)
+	}
+	return n.str(2, output).str(3, output).str(4, op)
+}
+
+// onnxValueInfo returns the ValueInfoProto of the tensor of doubles named name, which has a row for each sample
+func onnxValueInfo(name string, columns int64) pb {
+	shape := pb{}.msg(1, pb{}.str(2, "N")).msg(1, pb{}.int(1, columns))
+	tensor := pb{}.int(1, onnxDouble).msg(2, shape)
+	return pb{}.str(1, name).msg(2, pb{}.msg(1, tensor))
+}
+
+// onnx encodes the model as an ONNX ModelProto, whose input X has a column for each feature in the order of
+// m.features, which is also recorded in the metadata 'features', and output Y has a column of the predictions
+func (m *model) onnx() []byte {
+	n := int64(len(m.features))
+	xbars, sigmas, thetas := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, f := range m.features {
+		xbars[i], sigmas[i], thetas[i] = f.xbar, f.sigma, f.theta
+	}
+
+	graph := pb{}.
+		msg(1, onnxNode("Sub", []string{onnxInput, "xbars"}, "centered")).
+		msg(1, onnxNode("Div", []string{"centered", "sigmas"}, "scaled")).
+		msg(1, onnxNode("MatMul", []string{"scaled", "thetas"}, "product"))
+	if m.logistic() {
+		graph = graph.
+			msg(1, onnxNode("Add", []string{"product", "intercept"}, "logit")).
+			msg(1, onnxNode("Sigmoid", []string{"logit"}, onnxOutput))
+	} else {
+		graph = graph.
+			msg(1, onnxNode("Add", []string{"product", "intercept"}, "standardized")).
+			msg(1, onnxNode("Mul", []string{"standardized", "labelSigma"}, "rescaled")).
+			msg(1, onnxNode("Add", []string{"rescaled", "labelXbar"}, onnxOutput))
+	}
+	graph = graph.str(2, strings.ToLower(m.algo.String())).
+		msg(5, onnxTensor("xbars", []int64{n}, xbars)).
+		msg(5, onnxTensor("sigmas", []int64{n}, sigmas)).
+		msg(5, onnxTensor("thetas", []int64{n, 1}, thetas)).
+		msg(5, onnxTensor("intercept", []int64{1}, []float64{m.intercept}))
+	if !m.logistic() {
+		graph = graph.
+			msg(5, onnxTensor("labelSigma", []int64{1}, []float64{m.labelSigma})).
+			msg(5, onnxTensor("labelXbar", []int64{1}, []float64{m.labelXbar}))
+	}
+	graph = graph.msg(11, onnxValueInfo(onnxInput, n)).msg(12, onnxValueInfo(onnxOutput, 1))
+
+	metadata := func(key, value string) pb {
+		return pb{}.str(1, key).str(2, value)
+	}
+	return pb{}.int(1, onnxIRVersion).
+		str(2, "PaddleDTX").
+		msg(7, graph).
+		msg(8, pb{}.str(1, "").int(2, onnxOpset)).
+		msg(14, metadata("features", strings.Join(m.featureNames(), ","))).
+		msg(14, metadata("label", m.label))
+}
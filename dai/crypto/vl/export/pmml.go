@@ -0,0 +1,156 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// Elements of PMML 4.4 used by the exported RegressionModel
+type (
+	pmmlDoc struct {
+		XMLName         xml.Name       `xml:"http://www.dmg.org/PMML-4_4 PMML"`
+		Version         string         `xml:"version,attr"`
+		Header          pmmlHeader     `xml:"Header"`
+		DataDictionary  pmmlDictionary `xml:"DataDictionary"`
+		RegressionModel pmmlRegression `xml:"RegressionModel"`
+	}
+	pmmlHeader struct {
+		Description string          `xml:"description,attr"`
+		Application pmmlApplication `xml:"Application"`
+	}
+	pmmlApplication struct {
+		Name string `xml:"name,attr"`
+	}
+	pmmlDictionary struct {
+		NumberOfFields int             `xml:"numberOfFields,attr"`
+		DataFields     []pmmlDataField `xml:"DataField"`
+	}
+	pmmlDataField struct {
+		Name     string `xml:"name,attr"`
+		Optype   string `xml:"optype,attr"`
+		DataType string `xml:"dataType,attr"`
+	}
+	pmmlRegression struct {
+		FunctionName        string              `xml:"functionName,attr"`
+		ModelName           string              `xml:"modelName,attr"`
+		NormalizationMethod string              `xml:"normalizationMethod,attr"`
+		MiningFields        []pmmlMiningField   `xml:"MiningSchema>MiningField"`
+		Targets             []pmmlTarget        `xml:"Targets>Target,omitempty"`
+		DerivedFields       []pmmlDerivedField  `xml:"LocalTransformations>DerivedField"`
+		RegressionTable     pmmlRegressionTable `xml:"RegressionTable"`
+	}
+	pmmlMiningField struct {
+		Name      string `xml:"name,attr"`
+		UsageType string `xml:"usageType,attr,omitempty"`
+	}
+	pmmlTarget struct {
+		Field           string  `xml:"field,attr"`
+		RescaleFactor   float64 `xml:"rescaleFactor,attr"`
+		RescaleConstant float64 `xml:"rescaleConstant,attr"`
+	}
+	pmmlDerivedField struct {
+		Name     string    `xml:"name,attr"`
+		Optype   string    `xml:"optype,attr"`
+		DataType string    `xml:"dataType,attr"`
+		Apply    pmmlApply `xml:"Apply"`
+	}
+	// pmmlApply applies Function to FieldRef or the nested Apply, followed by Constants
+	pmmlApply struct {
+		Function  string         `xml:"function,attr"`
+		Apply     *pmmlApply     `xml:"Apply,omitempty"`
+		FieldRef  *pmmlFieldRef  `xml:"FieldRef,omitempty"`
+		Constants []pmmlConstant `xml:"Constant"`
+	}
+	pmmlFieldRef struct {
+		Field string `xml:"field,attr"`
+	}
+	pmmlConstant struct {
+		DataType string `xml:"dataType,attr"`
+		Value    string `xml:",chardata"`
+	}
+	pmmlRegressionTable struct {
+		Intercept  float64                `xml:"intercept,attr"`
+		Predictors []pmmlNumericPredictor `xml:"NumericPredictor"`
+	}
+	pmmlNumericPredictor struct {
+		Name        string  `xml:"name,attr"`
+		Coefficient float64 `xml:"coefficient,attr"`
+	}
+)
+
+// pmmlDouble returns the Constant of v
+func pmmlDouble(v float64) pmmlConstant {
+	return pmmlConstant{DataType: "double", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+}
+
+// pmml encodes the model as a PMML RegressionModel, the features are scaled by LocalTransformations,
+// the output of linear regression is rescaled by Targets, and the one of logistic regression is normalized by 'logit'
+func (m *model) pmml() ([]byte, error) {
+	doc := pmmlDoc{
+		Version: "4.4",
+		Header: pmmlHeader{
+			Description: strings.ToLower(m.algo.String()) + " model exported by PaddleDTX",
+			Application: pmmlApplication{Name: "PaddleDTX"},
+		},
+	}
+	reg := pmmlRegression{
+		FunctionName:        "regression",
+		ModelName:           strings.ToLower(m.algo.String()),
+		NormalizationMethod: "none",
+		RegressionTable:     pmmlRegressionTable{Intercept: m.intercept},
+	}
+	if m.logistic() {
+		reg.NormalizationMethod = "logit"
+	} else {
+		reg.Targets = []pmmlTarget{{Field: m.label, RescaleFactor: m.labelSigma, RescaleConstant: m.labelXbar}}
+	}
+	for _, f := range m.features {
+		scaled := "scaled(" + f.name + ")"
+		doc.DataDictionary.DataFields = append(doc.DataDictionary.DataFields,
+			pmmlDataField{Name: f.name, Optype: "continuous", DataType: "double"})
+		reg.MiningFields = append(reg.MiningFields, pmmlMiningField{Name: f.name})
+		reg.DerivedFields = append(reg.DerivedFields, pmmlDerivedField{
+			Name:     scaled,
+			Optype:   "continuous",
+			DataType: "double",
+			Apply: pmmlApply{
+				Function: "/",
+				Apply: &pmmlApply{
+					Function:  "-",
+					FieldRef:  &pmmlFieldRef{Field: f.name},
+					Constants: []pmmlConstant{pmmlDouble(f.xbar)},
+				},
+				Constants: []pmmlConstant{pmmlDouble(f.sigma)},
+			},
+		})
+		reg.RegressionTable.Predictors = append(reg.RegressionTable.Predictors,
+			pmmlNumericPredictor{Name: scaled, Coefficient: f.theta})
+	}
+	doc.DataDictionary.DataFields = append(doc.DataDictionary.DataFields,
+		pmmlDataField{Name: m.label, Optype: "continuous", DataType: "double"})
+	doc.DataDictionary.NumberOfFields = len(doc.DataDictionary.DataFields)
+	reg.MiningFields = append(reg.MiningFields, pmmlMiningField{Name: m.label, UsageType: "target"})
+	doc.RegressionModel = reg
+
+	out, err := xml.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to encode the model as PMML")
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
@@ -132,15 +132,15 @@ func TestLinearReg(t *testing.T) {
 		encGradB, encCostB, gradientNoiseB, costNoiseB, err = CalEncGradientAndCost(rawPartB, otherPartBytesA, trainDataSetB, paramsB, homoPubA, thetasB, round)
 		checkErr(err, t)
 
-		gradBytesA, costBytesA, err := DecGradientAndCost(encGradA, encCostA, homoPrivB)
+		gradBytesA, costBytesA, err := DecGradientAndCost(encGradA, encCostA, homoPrivB, nil, nil, paramsB)
 		checkErr(err, t)
-		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, homoPrivA)
+		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, homoPrivA, nil, nil, paramsA)
 		checkErr(err, t)
 
 		if round != 0 {
-			costA, err = UpdateCost(costBytesA, costNoiseA, paramsA)
+			costA, err = UpdateCost(costBytesA, costNoiseA, nil, nil, paramsA)
 			checkErr(err, t)
-			costB, err = UpdateCost(costBytesB, costNoiseB, paramsB)
+			costB, err = UpdateCost(costBytesB, costNoiseB, nil, nil, paramsB)
 			checkErr(err, t)
 			if StopTraining(lastCostA, costA, paramsA) && StopTraining(lastCostB, costB, paramsB) {
 				break
@@ -149,9 +149,9 @@ func TestLinearReg(t *testing.T) {
 			log.Printf("round[%d], deltaA: %v, deltaB: %v", round, math.Abs(costA-lastCostA), math.Abs(costB-lastCostB))
 		}
 
-		thetasA, err = UpdateGradient(gradBytesA, gradientNoiseA, thetasA, paramsA)
+		thetasA, err = UpdateGradient(gradBytesA, gradientNoiseA, nil, thetasA, paramsA)
 		checkErr(err, t)
-		thetasB, err = UpdateGradient(gradBytesB, gradientNoiseB, thetasB, paramsB)
+		thetasB, err = UpdateGradient(gradBytesB, gradientNoiseB, nil, thetasB, paramsB)
 		checkErr(err, t)
 
 		lastCostA = costA
@@ -159,9 +159,9 @@ func TestLinearReg(t *testing.T) {
 		round++
 	}
 
-	modelBytesA, err := vl_common.TrainModelsToBytes(thetasA, trainDataSetA, paramsA)
+	modelBytesA, err := vl_common.TrainModelsToBytes(thetasA, trainDataSetA, paramsA, nil)
 	checkErr(err, t)
-	modelBytesB, err := vl_common.TrainModelsToBytes(thetasB, trainDataSetB, paramsB)
+	modelBytesB, err := vl_common.TrainModelsToBytes(thetasB, trainDataSetB, paramsB, nil)
 	checkErr(err, t)
 
 	t.Logf("model A: %s\n", modelBytesA)
@@ -209,6 +209,219 @@ func TestLinearReg(t *testing.T) {
 	t.Logf("r_squared: %f\n", rSquared)
 }
 
+// TestLinearRegHuber trains with huber loss, and compares the thetas and costs with the ones of
+// gradient descent on the joint samples in plaintext
+func TestLinearRegHuber(t *testing.T) {
+	fileContentA, err := ioutil.ReadFile("../testdata/linear_boston_housing/train_dataA.csv")
+	checkErr(err, t)
+	fileContentB, err := ioutil.ReadFile("../testdata/linear_boston_housing/train_dataB.csv")
+	checkErr(err, t)
+	rowsA, err := csv.ReadRowsFromFile(fileContentA)
+	checkErr(err, t)
+	rowsB, err := csv.ReadRowsFromFile(fileContentB)
+	checkErr(err, t)
+
+	privA, pubA, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+	privB, pubB, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+
+	loss := &pb_common.LossParams{Type: vl_common.LossHuber, Delta: 0.5}
+	pA := pb_common.TrainParams{Label: "MEDV", Alpha: 0.1, Accuracy: 10, BatchSize: 8, Loss: loss}
+	pB := pA
+	pB.IsTagPart = true
+
+	setA, err := GetTrainDataSetFromFile(rowsA, pA)
+	checkErr(err, t)
+	setB, err := GetTrainDataSetFromFile(rowsB, pB)
+	checkErr(err, t)
+	thA, thB := InitThetas(setA, pA), InitThetas(setB, pB)
+	refA, refB := InitThetas(setA, pA), InitThetas(setB, pB)
+
+	for round := 0; round < 5; round++ {
+		rawA, partA, newA, err := CalLocalGradientAndCost(setA, thA, pA, &privA.PublicKey, round)
+		checkErr(err, t)
+		rawB, partB, newB, err := CalLocalGradientAndCost(setB, thB, pB, &privB.PublicKey, round)
+		checkErr(err, t)
+		setA.TrainSet, setB.TrainSet = newA, newB
+
+		encGradA, encCostA, _, costNoiseA, err := CalEncGradientAndCost(rawA, partB, setA, pA, pubB, thA, round)
+		checkErr(err, t)
+		encGradB, encCostB, gradNoiseB, costNoiseB, err := CalEncGradientAndCost(rawB, partA, setB, pB, pubA, thB, round)
+		checkErr(err, t)
+
+		// the party with label decrypts the values of the other party with the residuals from its own gradients
+		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, privA, nil, nil, pA)
+		checkErr(err, t)
+		residuals, err := Residuals(gradBytesB, gradNoiseB, pB)
+		checkErr(err, t)
+		_, costBytesA, err := DecGradientAndCost(encGradA, encCostA, privB, nil, residuals, pB)
+		checkErr(err, t)
+
+		costA, err := UpdateCost(costBytesA, costNoiseA, nil, nil, pA)
+		checkErr(err, t)
+		costB, err := UpdateCost(costBytesB, costNoiseB, nil, residuals, pB)
+		checkErr(err, t)
+
+		pseudoResiduals := PseudoResiduals(residuals, nil, pB)
+		thA, err = UpdateGradientByResiduals(pseudoResiduals, setA, thA, pA)
+		checkErr(err, t)
+		thB, err = UpdateGradientByResiduals(pseudoResiduals, setB, thB, pB)
+		checkErr(err, t)
+
+		// gradient descent in plaintext, rows of the same index are the same sample
+		batchA, _ := vl_common.GetBatchSetBySize(setA.TrainSet, pA, round, false)
+		batchB, _ := vl_common.GetBatchSetBySize(setB.TrainSet, pB, round, false)
+		gradA, gradB := make([]float64, len(refA)), make([]float64, len(refB))
+		var cost float64
+		for j := range batchB {
+			r := -batchB[j][len(batchB[j])-1]
+			for i := range refB {
+				r += refB[i] * batchB[j][i+1]
+			}
+			for i := range refA {
+				r += refA[i] * batchA[j][i+1]
+			}
+			cost += vl_common.LossValue(loss, r) / float64(2*len(batchB))
+			psi := vl_common.PseudoResidual(loss, r)
+			for i := range refB {
+				gradB[i] += psi * batchB[j][i+1] / float64(len(batchB))
+			}
+			for i := range refA {
+				gradA[i] += psi * batchA[j][i+1] / float64(len(batchB))
+			}
+		}
+		for i := range refA {
+			refA[i] -= pA.Alpha * gradA[i]
+		}
+		for i := range refB {
+			refB[i] -= pB.Alpha * gradB[i]
+		}
+
+		if math.Abs(costA-cost) > 1e-6 || math.Abs(costB-cost) > 1e-6 {
+			t.Errorf("round %d: expected huber cost %v, got %v and %v", round, cost, costA, costB)
+		}
+	}
+	for i := range refA {
+		if math.Abs(thA[i]-refA[i]) > 1e-6 {
+			t.Errorf("expected theta %d of A %v, got %v", i, refA[i], thA[i])
+		}
+	}
+	for i := range refB {
+		if math.Abs(thB[i]-refB[i]) > 1e-6 {
+			t.Errorf("expected theta %d of B %v, got %v", i, refB[i], thB[i])
+		}
+	}
+}
+
+// TestSparseLinearReg trains with the sparse rows of the samples, and compares the thetas, costs and predictions
+// with the ones of the dense rows
+func TestSparseLinearReg(t *testing.T) {
+	fileContentA, err := ioutil.ReadFile("../testdata/linear_boston_housing/train_dataA.csv")
+	checkErr(err, t)
+	fileContentB, err := ioutil.ReadFile("../testdata/linear_boston_housing/train_dataB.csv")
+	checkErr(err, t)
+	rowsA, err := csv.ReadRowsFromFile(fileContentA)
+	checkErr(err, t)
+	rowsB, err := csv.ReadRowsFromFile(fileContentB)
+	checkErr(err, t)
+	sparseRowsA, sparseRowsB := vl_common.SparsifyRows(rowsA, ""), vl_common.SparsifyRows(rowsB, "")
+
+	privA, pubA, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+	privB, pubB, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+
+	pA := pb_common.TrainParams{Label: "MEDV", Alpha: 0.1, Accuracy: 10, BatchSize: 8, Scaling: vl_common.ScalingMaxAbs,
+		RegMode: ml_common.RegRidge, RegParam: 0.1}
+	pB := pA
+	pB.IsTagPart = true
+	if !vl_common.UseSparseTrainSet(sparseRowsA, pA) || !vl_common.UseSparseTrainSet(sparseRowsB, pB) {
+		t.Fatal("sparse rows should be trained with the sparse train set")
+	}
+
+	setA, err := GetTrainDataSetFromFile(rowsA, pA)
+	checkErr(err, t)
+	setB, err := GetTrainDataSetFromFile(rowsB, pB)
+	checkErr(err, t)
+	sparseA, err := GetSparseTrainSetFromFile(sparseRowsA, pA)
+	checkErr(err, t)
+	sparseB, err := GetSparseTrainSetFromFile(sparseRowsB, pB)
+	checkErr(err, t)
+	thA, thB := InitThetas(setA, pA), InitThetas(setB, pB)
+	sparseThA, sparseThB := InitSparseThetas(sparseA), InitSparseThetas(sparseB)
+
+	for round := 0; round < 5; round++ {
+		rawA, partA, newA, err := CalLocalGradientAndCost(setA, thA, pA, &privA.PublicKey, round)
+		checkErr(err, t)
+		rawB, partB, newB, err := CalLocalGradientAndCost(setB, thB, pB, &privB.PublicKey, round)
+		checkErr(err, t)
+		setA.TrainSet, setB.TrainSet = newA, newB
+		encGradA, encCostA, gradNoiseA, _, err := CalEncGradientAndCost(rawA, partB, setA, pA, pubB, thA, round)
+		checkErr(err, t)
+		encGradB, encCostB, gradNoiseB, costNoiseB, err := CalEncGradientAndCost(rawB, partA, setB, pB, pubA, thB, round)
+		checkErr(err, t)
+		gradBytesA, _, err := DecGradientAndCost(encGradA, encCostA, privB, nil, nil, pB)
+		checkErr(err, t)
+		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, privA, nil, nil, pA)
+		checkErr(err, t)
+		costB, err := UpdateCost(costBytesB, costNoiseB, nil, nil, pB)
+		checkErr(err, t)
+		thA, err = UpdateGradient(gradBytesA, gradNoiseA, nil, thA, pA)
+		checkErr(err, t)
+		thB, err = UpdateGradient(gradBytesB, gradNoiseB, nil, thB, pB)
+		checkErr(err, t)
+
+		sparseRawA, sparsePartA, err := CalSparseLocalGradientAndCost(sparseA, sparseThA, pA, &privA.PublicKey, round)
+		checkErr(err, t)
+		sparseRawB, sparsePartB, err := CalSparseLocalGradientAndCost(sparseB, sparseThB, pB, &privB.PublicKey, round)
+		checkErr(err, t)
+		encGradA, encCostA, gradNoiseA, _, err = CalSparseEncGradientAndCost(sparseRawA, sparsePartB, sparseA, pA, pubB, sparseThA, round)
+		checkErr(err, t)
+		encGradB, encCostB, gradNoiseB, costNoiseB, err = CalSparseEncGradientAndCost(sparseRawB, sparsePartA, sparseB, pB, pubA, sparseThB, round)
+		checkErr(err, t)
+		gradBytesA, _, err = DecGradientAndCost(encGradA, encCostA, privB, nil, nil, pB)
+		checkErr(err, t)
+		gradBytesB, costBytesB, err = DecGradientAndCost(encGradB, encCostB, privA, nil, nil, pA)
+		checkErr(err, t)
+		sparseCostB, err := UpdateCost(costBytesB, costNoiseB, nil, nil, pB)
+		checkErr(err, t)
+		sparseThA, err = UpdateSparseGradient(gradBytesA, gradNoiseA, sparseA, sparseThA, pA, round)
+		checkErr(err, t)
+		sparseThB, err = UpdateSparseGradient(gradBytesB, gradNoiseB, sparseB, sparseThB, pB, round)
+		checkErr(err, t)
+
+		if math.Abs(costB-sparseCostB) > 1e-6 {
+			t.Errorf("round %d: expected cost %v, got %v", round, costB, sparseCostB)
+		}
+	}
+	for i := range thA {
+		if math.Abs(thA[i]-sparseThA[i]) > 1e-6 {
+			t.Errorf("expected theta %d of A %v, got %v", i, thA[i], sparseThA[i])
+		}
+	}
+	for i := range thB {
+		if math.Abs(thB[i]-sparseThB[i]) > 1e-6 {
+			t.Errorf("expected theta %d of B %v, got %v", i, thB[i], sparseThB[i])
+		}
+	}
+
+	// the model trained with the sparse rows predicts the dense and sparse rows alike
+	modelBytes, err := vl_common.SparseTrainModelsToBytes(sparseThB, sparseB, pB)
+	checkErr(err, t)
+	model, err := vl_common.TrainModelsFromBytes(modelBytes)
+	checkErr(err, t)
+	predictions, err := PredictLocalPart(rowsB, model)
+	checkErr(err, t)
+	sparsePredictions, err := PredictLocalPart(sparseRowsB, model)
+	checkErr(err, t)
+	for i := range predictions {
+		if math.Abs(predictions[i]-sparsePredictions[i]) > 1e-9 {
+			t.Errorf("expected prediction of sample %d %v, got %v", i, predictions[i], sparsePredictions[i])
+		}
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
@@ -17,19 +17,32 @@ import (
 	"fmt"
 	"strconv"
 
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 )
 
 // PredictLocalPart calculate predict values for local part
-// fileRows is sample rows, first row is feature list, others are values for each sample
+// fileRows is sample rows, first row is feature list, others are values for each sample,
+// features should match the ones of params and are scaled by the parameters of params
 func PredictLocalPart(fileRows [][]string, params *pb_common.TrainModels) ([]float64, error) {
 	featureList := fileRows[0]
+	if err := vl_common.CheckPredictFeatures(featureList, params); err != nil {
+		return nil, err
+	}
+	// sparse rows are predicted without being converted into dense ones
+	if vl_common.HasSparseRows(fileRows) {
+		return vl_common.PredictSparseRows(fileRows, params)
+	}
 
 	var localPredictValues []float64
 	for i := 1; i < len(fileRows); i++ {
 		input := make(map[string]float64)
 		for j := 0; j < len(featureList); j++ {
 			featureName := featureList[j]
+			// weights of samples are only used in training
+			if featureName == params.WeightColumn {
+				continue
+			}
 			value, err := strconv.ParseFloat(fileRows[i][j], 64)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse value, err: %v", err)
@@ -66,3 +79,20 @@ func DeStandardizeOutput(params *pb_common.TrainModels, localPredict, otherPredi
 	}
 	return realPredictValue
 }
+
+// DeStandardizeContributions de-standardizes the contributions of features to the predict sums, returns the base
+// of the predictions and the contributions to the real predictions, a real prediction is base plus the contributions of
+// all features to it. Only party with label can de-standardize contributions.
+func DeStandardizeContributions(params *pb_common.TrainModels, contributions [][]float64) (float64, [][]float64) {
+	ybar := params.Xbars[params.Label]
+	sigma := params.Sigmas[params.Label]
+
+	deStandardized := make([][]float64, len(contributions))
+	for i, row := range contributions {
+		deStandardized[i] = make([]float64, len(row))
+		for j, c := range row {
+			deStandardized[i][j] = sigma * c
+		}
+	}
+	return ybar + sigma*params.Thetas["Intercept"], deStandardized
+}
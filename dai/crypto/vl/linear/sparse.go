@@ -0,0 +1,221 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linear
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+	ml_common "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/common"
+	linear_vertical "github.com/PaddlePaddle/PaddleDTX/crypto/core/machine_learning/linear_regression/gradient_descent/mpc_vertical"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// The functions below train with the sparse train set, see vl_common.SparseTrainSet, they are the counterparts of
+// the ones with the dense train set, and the intermediate results exchanged are the same except that the gradient
+// of a feature only has the samples of which the feature is non-zero. The library computes on dense rows, so the rows
+// passed to it are projected: the local part of the prediction of a sample is computed from its non-zero features
+// and passed as its only feature with theta 1, and the gradient of a feature is computed on the rows of its non-zero values.
+
+// GetSparseTrainSetFromFile retrieve the sparse train set from file for tag/no-tag part, see vl_common.NewSparseTrainSet
+func GetSparseTrainSetFromFile(fileRows [][]string, params pb_common.TrainParams) (*vl_common.SparseTrainSet, error) {
+	return vl_common.NewSparseTrainSet(fileRows, params, false)
+}
+
+// InitSparseThetas initialize model for tag/no-tag part, the thetas of the tag part start with the intercept,
+// and the last feature of the tag part is the label, so there is a theta for each feature name
+func InitSparseThetas(trainSet *vl_common.SparseTrainSet) []float64 {
+	return make([]float64, len(trainSet.FeatureNames))
+}
+
+// CalSparseLocalGradientAndCost calculate local gradient and cost part for tag/no-tag part with the sparse train set,
+// samples of trainSet are reordered for the batches of the following rounds
+func CalSparseLocalGradientAndCost(trainSet *vl_common.SparseTrainSet, thetas []float64, params pb_common.TrainParams,
+	publicKey *paillier.PublicKey, round int) (*linear_vertical.RawLocalGradientPart, []byte, error) {
+
+	batch := vl_common.GetSparseBatchBySize(trainSet.Samples, params, round, true)
+
+	// the projected thetas have no regularization cost, the one of thetas is computed after
+	var gradAndCostPart *linear_vertical.LocalGradientPart
+	var err error
+	if params.IsTagPart {
+		// rows are [id, 1, local part of prediction without the intercept, label]
+		rows := make([][]float64, len(batch))
+		for i, sample := range batch {
+			rows[i] = []float64{float64(sample.ID), 1, sample.Dot(thetas, 1), sample.Label}
+		}
+		gradAndCostPart, err = xchainCryptoClient.LinRegVLCalLocalGradAndCostTagPart([]float64{thetas[0], 1}, rows, int(params.Accuracy), ml_common.RegNone, 0, publicKey)
+	} else {
+		// rows are [id, local part of prediction]
+		rows := make([][]float64, len(batch))
+		for i, sample := range batch {
+			rows[i] = []float64{float64(sample.ID), sample.Dot(thetas, 0)}
+		}
+		gradAndCostPart, err = xchainCryptoClient.LinRegVLCalLocalGradAndCost([]float64{1}, rows, int(params.Accuracy), ml_common.RegNone, 0, publicKey)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	gradAndCostPart.RawPart.RawRegCost, gradAndCostPart.EncPart.EncRegCost, err = vl_common.SparseRegCost(thetas, len(batch), params, publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encPartBytes, err := vl_common.LinearEncGradientPartToBytes(gradAndCostPart.EncPart)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gradAndCostPart.RawPart, encPartBytes, nil
+}
+
+// CalSparseEncGradientAndCost calculate own encrypted gradient and cost with the sparse train set,
+// the encrypted gradient of a feature only has the samples of this round of which the feature is non-zero
+func CalSparseEncGradientAndCost(rawPart *linear_vertical.RawLocalGradientPart, otherPartBytes []byte, trainSet *vl_common.SparseTrainSet,
+	params pb_common.TrainParams, publicKeyBytes []byte, thetas []float64, round int) ([]byte, []byte, []*big.Int, *big.Int, error) {
+
+	otherEncPart, err := vl_common.LinearEncGradientPartFromBytes(otherPartBytes)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	publicKey, err := vl_common.HomoPubkeyFromBytes(publicKeyBytes)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	batch := vl_common.GetSparseBatchBySize(trainSet.Samples, params, round, false)
+	columns, ids := sparseColumns(batch, len(thetas), params.IsTagPart)
+
+	// gradients of features are encrypted in parallel, sharing the kernel worker pool with other tasks
+	encGradList := make([]map[int]*big.Int, len(thetas))
+	gradientNoise := make([]*big.Int, len(thetas))
+	errs := make([]error, len(thetas))
+	vl_common.Parallel(len(thetas), 0, func(i int) {
+		var encGrad *ml_common.EncLocalGradient
+		if params.IsTagPart {
+			encGrad, errs[i] = xchainCryptoClient.LinRegVLCalEncGradientTagPart(rawPart, otherEncPart, columns[i], 0, int(params.Accuracy), publicKey)
+		} else {
+			encGrad, errs[i] = xchainCryptoClient.LinRegVLCalEncGradient(rawPart, otherEncPart, columns[i], 0, int(params.Accuracy), publicKey)
+		}
+		if errs[i] == nil {
+			encGradList[i] = encGrad.EncGrad
+			gradientNoise[i] = encGrad.RandomNoise
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	var encCost *ml_common.EncLocalCost
+	if params.IsTagPart {
+		encCost, err = xchainCryptoClient.LinRegVLEvaluateEncCostTagPart(rawPart, otherEncPart, ids, int(params.Accuracy), publicKey)
+	} else {
+		encCost, err = xchainCryptoClient.LinRegVLEvaluateEncCost(rawPart, otherEncPart, ids, int(params.Accuracy), publicKey)
+	}
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	encGradListBytes, err := vl_common.GradListToBytes(encGradList)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	encCostBytes, err := vl_common.CostToBytes(encCost.EncCost)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return encGradListBytes, encCostBytes, gradientNoise, encCost.RandomNoise, nil
+}
+
+// sparseColumns returns the rows [id, value] of the non-zero values of each theta of the samples of batch,
+// which are computed on as the rows of the dense train set with the feature index 0, the intercept of the tag part
+// is 1 for all samples, and the rows [id] of all samples of batch
+func sparseColumns(batch []*vl_common.SparseSample, thetaNum int, isTagPart bool) ([][][]float64, [][]float64) {
+	offset := 0
+	if isTagPart {
+		offset = 1
+	}
+	columns := make([][][]float64, thetaNum)
+	ids := make([][]float64, len(batch))
+	for i, sample := range batch {
+		id := float64(sample.ID)
+		ids[i] = []float64{id}
+		if isTagPart {
+			columns[0] = append(columns[0], []float64{id, 1})
+		}
+		for k, f := range sample.Indices {
+			columns[f+offset] = append(columns[f+offset], []float64{id, sample.Values[k]})
+		}
+	}
+	return columns, ids
+}
+
+// UpdateSparseGradient retrieve and update thetas with the sparse train set, the gradient of a feature
+// is the mean of the gradients of all the samples of this round, which are 0 for the samples it is 0
+func UpdateSparseGradient(decGradBytes []byte, gradientNoise []*big.Int, trainSet *vl_common.SparseTrainSet, thetas []float64,
+	params pb_common.TrainParams, round int) ([]float64, error) {
+	grads, err := vl_common.GradListFromBytes(decGradBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := len(vl_common.GetSparseBatchBySize(trainSet.Samples, params, round, false))
+	realGrads := make([]float64, len(thetas))
+	for i := 0; i < len(thetas); i++ {
+		var sum float64
+		for _, grad := range xchainCryptoClient.LinRegVLRetrieveRealGradient(grads[i], int(params.Accuracy), gradientNoise[i]) {
+			sum += grad
+		}
+		realGrads[i] = vl_common.SparseGradientWithReg(thetas, sum, batchSize, i, params)
+	}
+	return updateThetas(realGrads, thetas, params)
+}
+
+// UpdateSparseGradientByResiduals updates thetas with the sparse train set by the gradients of the loss of params.Loss
+// other than squared error, see UpdateGradientByResiduals
+func UpdateSparseGradientByResiduals(pseudoResiduals map[int]float64, trainSet *vl_common.SparseTrainSet, thetas []float64,
+	params pb_common.TrainParams) ([]float64, error) {
+	offset := 0
+	if params.IsTagPart {
+		offset = 1
+	}
+	sums := make([]float64, len(thetas))
+	matched := 0
+	for _, sample := range trainSet.Samples {
+		psi, ok := pseudoResiduals[sample.ID]
+		if !ok {
+			continue
+		}
+		matched++
+		if params.IsTagPart {
+			sums[0] += psi
+		}
+		for k, f := range sample.Indices {
+			sums[f+offset] += psi * sample.Values[k]
+		}
+	}
+	if len(pseudoResiduals) == 0 || matched != len(pseudoResiduals) {
+		return nil, fmt.Errorf("pseudo residuals of %d samples mismatch the train set", len(pseudoResiduals))
+	}
+
+	realGrads := make([]float64, len(thetas))
+	for i := range thetas {
+		realGrads[i] = vl_common.SparseGradientWithReg(thetas, sums[i], matched, i, params)
+	}
+	return updateThetas(realGrads, thetas, params)
+}
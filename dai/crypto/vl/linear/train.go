@@ -14,6 +14,7 @@
 package linear
 
 import (
+	"fmt"
 	"math"
 	"math/big"
 
@@ -30,7 +31,8 @@ var xchainCryptoClient = new(xchain.XchainCryptoClient)
 
 // GetTrainDataSetFromFile retrieve train dataset from file for tag/no-tag part
 // fileRows is sample rows, first row is feature list, others are values for each sample
-// params includes all required parameters for training
+// params includes all required parameters for training, features are scaled by params.Scaling,
+// or by params.BaseModel if it is set
 func GetTrainDataSetFromFile(fileRows [][]string, params pb_common.TrainParams) (*ml_common.TrainDataSet, error) {
 	features, err := xchainCryptoClient.LinRegImportFeatures(fileRows)
 	if err != nil {
@@ -41,6 +43,13 @@ func GetTrainDataSetFromFile(fileRows [][]string, params pb_common.TrainParams)
 		Features: features,
 	}
 	standardizedData := xchainCryptoClient.LinRegVLStandardizeDataSet(dataSet)
+	if params.BaseModel != nil {
+		if err := vl_common.StandardizeByModel(standardizedData, params.BaseModel, ""); err != nil {
+			return nil, err
+		}
+	} else if err := vl_common.ScaleDataSet(standardizedData, params.Scaling, ""); err != nil {
+		return nil, err
+	}
 
 	if params.IsTagPart {
 		return xchainCryptoClient.LinRegVLPreProcessDataSetTagPart(standardizedData, params.Label), nil
@@ -111,21 +120,26 @@ func CalEncGradientAndCost(rawPart *linear_vertical.RawLocalGradientPart, otherP
 	// BGD, SGD or MBGD
 	trainSetThisRound, _ := vl_common.GetBatchSetBySize(trainSet.TrainSet, params, round, false)
 
-	var encGradList []map[int]*big.Int
-	var encGrad *ml_common.EncLocalGradient
-	var gradientNoise []*big.Int
-	for i := 0; i < len(thetas); i++ {
+	// gradients of features are encrypted in parallel, sharing the kernel worker pool with other tasks
+	encGradList := make([]map[int]*big.Int, len(thetas))
+	gradientNoise := make([]*big.Int, len(thetas))
+	errs := make([]error, len(thetas))
+	vl_common.Parallel(len(thetas), 0, func(i int) {
+		var encGrad *ml_common.EncLocalGradient
 		if params.IsTagPart {
-			encGrad, err = xchainCryptoClient.LinRegVLCalEncGradientTagPart(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+			encGrad, errs[i] = xchainCryptoClient.LinRegVLCalEncGradientTagPart(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
 		} else {
-			encGrad, err = xchainCryptoClient.LinRegVLCalEncGradient(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+			encGrad, errs[i] = xchainCryptoClient.LinRegVLCalEncGradient(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+		}
+		if errs[i] == nil {
+			encGradList[i] = encGrad.EncGrad
+			gradientNoise[i] = encGrad.RandomNoise
 		}
+	})
+	for _, err := range errs {
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
-
-		encGradList = append(encGradList, encGrad.EncGrad)
-		gradientNoise = append(gradientNoise, encGrad.RandomNoise)
 	}
 
 	var encCost *ml_common.EncLocalCost
@@ -156,24 +170,36 @@ func CalEncGradientAndCost(rawPart *linear_vertical.RawLocalGradientPart, otherP
 
 // DecGradientAndCost decrypt gradient list and cost for other part
 // encGradsBytes and encCostBytes are ciphertext received from other party, encrypted by local homomorphic public key
-// privateKey is local homomorphic private key, used to decrypt encGradsBytes and encCostBytes
-func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *paillier.PrivateKey) ([]byte, []byte, error) {
+// privateKey is local homomorphic private key, used to decrypt encGradsBytes and encCostBytes,
+// weights is the weights of samples of the party with label, the decrypted values are weighted if it is not nil,
+// residuals is the residuals of samples retrieved by Residuals on the party with label if params.Loss isn't squared error,
+// the decrypted costs, which are squared errors, are corrected to the loss of the residuals if it is not nil
+func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *paillier.PrivateKey, weights map[int]float64,
+	residuals map[int]float64, params pb_common.TrainParams) ([]byte, []byte, error) {
 	encGrads, err := vl_common.GradListFromBytes(encGradsBytes)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var decGradList []map[int]*big.Int
-	for i := 0; i < len(encGrads); i++ {
+	decGradList := make([]map[int]*big.Int, len(encGrads))
+	vl_common.Parallel(len(encGrads), 0, func(i int) {
 		grad := xchainCryptoClient.LinRegVLDecryptGradient(encGrads[i], privateKey)
-		decGradList = append(decGradList, grad)
-	}
+		decGradList[i] = vl_common.WeightDecrypted(grad, weights)
+	})
 
 	encCost, err := vl_common.CostFromBytes(encCostBytes)
 	if err != nil {
 		return nil, nil, err
 	}
-	cost := xchainCryptoClient.LinRegVLDecryptCost(encCost, privateKey)
+	decCost := xchainCryptoClient.LinRegVLDecryptCost(encCost, privateKey)
+	if residuals != nil {
+		for id, c := range decCost {
+			r := residuals[id]
+			correction := encodeCost(vl_common.LossValue(params.Loss, r)-r*r, int(params.Accuracy))
+			decCost[id] = new(big.Int).Add(c, correction)
+		}
+	}
+	cost := vl_common.WeightDecrypted(decCost, weights)
 
 	decGradBytes, err := vl_common.GradListToBytes(decGradList)
 	if err != nil {
@@ -187,36 +213,121 @@ func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *p
 	return decGradBytes, decCostBytes, nil
 }
 
+// encodeCost encodes cost as the decrypted costs, which are integers of 2 accuracies
+func encodeCost(cost float64, accuracy int) *big.Int {
+	scale := big.NewInt(int64(math.Round(math.Pow(10, float64(accuracy)))))
+	encoded := big.NewInt(int64(math.Round(cost * math.Pow(10, float64(accuracy)))))
+	return encoded.Mul(encoded, scale)
+}
+
+// Residuals retrieves the residuals of the samples of this round on the party with label, which are the predictions
+// minus the labels, the gradient of the intercept of each sample is its residual as the intercept column is 1
+// decGradBytes is decrypted gradient received from other party, with gradientNoise
+func Residuals(decGradBytes []byte, gradientNoise []*big.Int, params pb_common.TrainParams) (map[int]float64, error) {
+	grads, err := vl_common.GradListFromBytes(decGradBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !params.IsTagPart || len(grads) == 0 || len(gradientNoise) == 0 {
+		return nil, fmt.Errorf("residuals are only retrieved from the gradients of the intercept on the party with label")
+	}
+	return xchainCryptoClient.LinRegVLRetrieveRealGradient(grads[0], int(params.Accuracy), gradientNoise[0]), nil
+}
+
+// PseudoResiduals returns the derivatives of the loss of params.Loss at residuals, which are weighted by weights
+// if it is not nil, both parties calculate their gradients by them by UpdateGradientByResiduals
+func PseudoResiduals(residuals map[int]float64, weights map[int]float64, params pb_common.TrainParams) map[int]float64 {
+	pseudoResiduals := make(map[int]float64, len(residuals))
+	for id, r := range residuals {
+		pseudoResiduals[id] = vl_common.PseudoResidual(params.Loss, r)
+	}
+	return vl_common.WeightValues(pseudoResiduals, weights)
+}
+
 // UpdateCost retrieve and update cost
-// decCostBytes is decrypted cost received from other party, with costNoise
-func UpdateCost(decCostBytes []byte, costNoise *big.Int, params pb_common.TrainParams) (float64, error) {
+// decCostBytes is decrypted cost received from other party, with costNoise,
+// weights is the weights of local samples on the party with label, nil if samples are equally weighted,
+// residuals is the residuals of local samples on the party with label if params.Loss isn't squared error,
+// the costs, which are squared errors, are corrected to the loss of the residuals if it is not nil
+func UpdateCost(decCostBytes []byte, costNoise *big.Int, weights map[int]float64, residuals map[int]float64, params pb_common.TrainParams) (float64, error) {
 	// retrieve real cost
 	costMap, err := vl_common.CostFromBytes(decCostBytes)
 	if err != nil {
 		return 0, err
 	}
 	realCost := xchainCryptoClient.LinRegVLRetrieveRealCost(costMap, int(params.Accuracy), costNoise)
-	cost := xchainCryptoClient.LinRegVLCalCost(realCost)
+	if residuals != nil {
+		for id := range realCost {
+			r := residuals[id]
+			realCost[id] += vl_common.LossValue(params.Loss, r) - r*r
+		}
+	}
+	cost := xchainCryptoClient.LinRegVLCalCost(vl_common.WeightValues(realCost, weights))
 
 	return cost, nil
 }
 
 // UpdateGradient retrieve and update thetas
-// decGradBytes is decrypted gradient received from other party, with gradientNoise
-func UpdateGradient(decGradBytes []byte, gradientNoise []*big.Int, thetas []float64, params pb_common.TrainParams) ([]float64, error) {
+// decGradBytes is decrypted gradient received from other party, with gradientNoise,
+// Gaussian noise is added to the gradient if params.Dp is set, and the gradients of samples are weighted by weights if it is not nil
+func UpdateGradient(decGradBytes []byte, gradientNoise []*big.Int, weights map[int]float64, thetas []float64, params pb_common.TrainParams) ([]float64, error) {
 	grads, err := vl_common.GradListFromBytes(decGradBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	newThetas := make([]float64, len(thetas))
-	copy(newThetas[0:], thetas)
+	realGrads := make([]float64, len(thetas))
+	for i := 0; i < len(thetas); i++ {
+		realGradient := vl_common.WeightValues(xchainCryptoClient.LinRegVLRetrieveRealGradient(grads[i], int(params.Accuracy), gradientNoise[i]), weights)
+		//		grad := xchainCryptoClient.LinRegVLCalGradient(realGradient)
+		realGrads[i] = xchainCryptoClient.LinRegVLCalGradientWithReg(thetas, realGradient, i, int(params.RegMode), params.RegParam)
+	}
+	return updateThetas(realGrads, thetas, params)
+}
+
+// UpdateGradientByResiduals updates thetas by the gradients of the loss of params.Loss other than squared error,
+// which are the pseudo residuals of samples times their features, the pseudo residuals are sent by the party with label
+// pseudoResiduals is returned by PseudoResiduals for the samples of this round, trainSet is local train set
+func UpdateGradientByResiduals(pseudoResiduals map[int]float64, trainSet *ml_common.TrainDataSet, thetas []float64, params pb_common.TrainParams) ([]float64, error) {
+	gradMaps := make([]map[int]float64, len(thetas))
+	for i := range gradMaps {
+		gradMaps[i] = make(map[int]float64, len(pseudoResiduals))
+	}
+	for _, row := range trainSet.TrainSet {
+		id := int(math.Floor(row[0] + 0.5))
+		psi, ok := pseudoResiduals[id]
+		if !ok {
+			continue
+		}
+		// the first column is id, followed by the intercept column 1 on the party with label
+		for i := 0; i < len(thetas); i++ {
+			gradMaps[i][id] = psi * row[i+1]
+		}
+	}
+	if len(pseudoResiduals) == 0 || len(gradMaps[0]) != len(pseudoResiduals) {
+		return nil, fmt.Errorf("pseudo residuals of %d samples mismatch the train set", len(pseudoResiduals))
+	}
+
+	realGrads := make([]float64, len(thetas))
+	for i := 0; i < len(thetas); i++ {
+		realGrads[i] = xchainCryptoClient.LinRegVLCalGradientWithReg(thetas, gradMaps[i], i, int(params.RegMode), params.RegParam)
+	}
+	return updateThetas(realGrads, thetas, params)
+}
 
+// updateThetas updates thetas by the gradients of this round
+func updateThetas(realGrads []float64, thetas []float64, params pb_common.TrainParams) ([]float64, error) {
+	// gradients are privatized as a whole for differential privacy
+	if params.Dp != nil {
+		var err error
+		if realGrads, err = vl_common.PrivatizeGradient(realGrads, params.Dp); err != nil {
+			return nil, err
+		}
+	}
+
+	newThetas := make([]float64, len(thetas))
 	for i := 0; i < len(newThetas); i++ {
-		realGradient := xchainCryptoClient.LinRegVLRetrieveRealGradient(grads[i], int(params.Accuracy), gradientNoise[i])
-		//		grad := xchainCryptoClient.LinRegVLCalGradient(realGradient)
-		grad := xchainCryptoClient.LinRegVLCalGradientWithReg(thetas, realGradient, i, int(params.RegMode), params.RegParam)
-		newThetas[i] = newThetas[i] - params.Alpha*grad
+		newThetas[i] = thetas[i] - params.Alpha*realGrads[i]
 	}
 
 	return newThetas, nil
@@ -134,15 +134,15 @@ func TestLogicReg(t *testing.T) {
 		encGradB, encCostB, gradientNoiseB, costNoiseB, err = CalEncGradientAndCost(rawPartB, otherPartBytesA, trainDataSetB, paramsB, homoPubA, thetasB, round)
 		checkErr(err, t)
 
-		gradBytesA, costBytesA, err := DecGradientAndCost(encGradA, encCostA, homoPrivB)
+		gradBytesA, costBytesA, err := DecGradientAndCost(encGradA, encCostA, homoPrivB, nil)
 		checkErr(err, t)
-		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, homoPrivA)
+		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, homoPrivA, nil)
 		checkErr(err, t)
 
 		if round != 0 {
-			costA, err = UpdateCost(costBytesA, costNoiseA, paramsA)
+			costA, err = UpdateCost(costBytesA, costNoiseA, nil, paramsA)
 			checkErr(err, t)
-			costB, err = UpdateCost(costBytesB, costNoiseB, paramsB)
+			costB, err = UpdateCost(costBytesB, costNoiseB, nil, paramsB)
 			checkErr(err, t)
 			if StopTraining(lastCostA, costA, paramsA) && StopTraining(lastCostB, costB, paramsB) {
 				break
@@ -151,9 +151,9 @@ func TestLogicReg(t *testing.T) {
 			log.Printf("round[%d], deltaA: %v, deltaB: %v", round, math.Abs(costA-lastCostA), math.Abs(costB-lastCostB))
 		}
 
-		thetasA, err = UpdateGradient(gradBytesA, gradientNoiseA, thetasA, paramsA)
+		thetasA, err = UpdateGradient(gradBytesA, gradientNoiseA, nil, thetasA, paramsA, nil, round)
 		checkErr(err, t)
-		thetasB, err = UpdateGradient(gradBytesB, gradientNoiseB, thetasB, paramsB)
+		thetasB, err = UpdateGradient(gradBytesB, gradientNoiseB, nil, thetasB, paramsB, nil, round)
 		checkErr(err, t)
 
 		lastCostA = costA
@@ -161,9 +161,9 @@ func TestLogicReg(t *testing.T) {
 		round++
 	}
 
-	modelBytesA, err := vl_common.TrainModelsToBytes(thetasA, trainDataSetA, paramsA)
+	modelBytesA, err := vl_common.TrainModelsToBytes(thetasA, trainDataSetA, paramsA, nil)
 	checkErr(err, t)
-	modelBytesB, err := vl_common.TrainModelsToBytes(thetasB, trainDataSetB, paramsB)
+	modelBytesB, err := vl_common.TrainModelsToBytes(thetasB, trainDataSetB, paramsB, nil)
 	checkErr(err, t)
 
 	t.Logf("model A: %s\n", modelBytesA)
@@ -197,6 +197,115 @@ func TestLogicReg(t *testing.T) {
 	t.Logf("predict value: %v\n", output)
 }
 
+// TestSparseLogicReg trains with the sparse rows of the samples, and compares the thetas, costs and predictions
+// with the ones of the dense rows
+func TestSparseLogicReg(t *testing.T) {
+	fileContentA, err := ioutil.ReadFile("../testdata/logic_iris_plants/train_dataA.csv")
+	checkErr(err, t)
+	fileContentB, err := ioutil.ReadFile("../testdata/logic_iris_plants/train_dataB.csv")
+	checkErr(err, t)
+	rowsA, err := csv.ReadRowsFromFile(fileContentA)
+	checkErr(err, t)
+	rowsB, err := csv.ReadRowsFromFile(fileContentB)
+	checkErr(err, t)
+	sparseRowsA, sparseRowsB := vl_common.SparsifyRows(rowsA, ""), vl_common.SparsifyRows(rowsB, "")
+
+	privA, pubA, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+	privB, pubB, err := vl_common.GenerateHomoKeyPair()
+	checkErr(err, t)
+
+	pA := pb_common.TrainParams{Label: "Label", LabelName: "Iris-setosa", Alpha: 0.1, Accuracy: 10, BatchSize: 8,
+		Scaling: vl_common.ScalingNone, RegMode: ml_common.RegLasso, RegParam: 0.1}
+	pB := pA
+	pB.IsTagPart = true
+
+	setA, err := GetTrainDataSetFromFile(rowsA, pA)
+	checkErr(err, t)
+	setB, err := GetTrainDataSetFromFile(rowsB, pB)
+	checkErr(err, t)
+	sparseA, err := GetSparseTrainSetFromFile(sparseRowsA, pA)
+	checkErr(err, t)
+	sparseB, err := GetSparseTrainSetFromFile(sparseRowsB, pB)
+	checkErr(err, t)
+	thA, thB := InitThetas(setA, pA), InitThetas(setB, pB)
+	sparseThA, sparseThB := InitSparseThetas(sparseA), InitSparseThetas(sparseB)
+
+	for round := 0; round < 5; round++ {
+		rawA, partA, newA, err := CalLocalGradientAndCost(setA, thA, pA, &privA.PublicKey, round)
+		checkErr(err, t)
+		rawB, partB, newB, err := CalLocalGradientAndCost(setB, thB, pB, &privB.PublicKey, round)
+		checkErr(err, t)
+		setA.TrainSet, setB.TrainSet = newA, newB
+		encGradA, encCostA, gradNoiseA, _, err := CalEncGradientAndCost(rawA, partB, setA, pA, pubB, thA, round)
+		checkErr(err, t)
+		encGradB, encCostB, gradNoiseB, costNoiseB, err := CalEncGradientAndCost(rawB, partA, setB, pB, pubA, thB, round)
+		checkErr(err, t)
+		gradBytesA, _, err := DecGradientAndCost(encGradA, encCostA, privB, nil)
+		checkErr(err, t)
+		gradBytesB, costBytesB, err := DecGradientAndCost(encGradB, encCostB, privA, nil)
+		checkErr(err, t)
+		costB, err := UpdateCost(costBytesB, costNoiseB, nil, pB)
+		checkErr(err, t)
+		thA, err = UpdateGradient(gradBytesA, gradNoiseA, nil, thA, pA, nil, round)
+		checkErr(err, t)
+		thB, err = UpdateGradient(gradBytesB, gradNoiseB, nil, thB, pB, nil, round)
+		checkErr(err, t)
+
+		sparseRawA, sparsePartA, err := CalSparseLocalGradientAndCost(sparseA, sparseThA, pA, &privA.PublicKey, round)
+		checkErr(err, t)
+		sparseRawB, sparsePartB, err := CalSparseLocalGradientAndCost(sparseB, sparseThB, pB, &privB.PublicKey, round)
+		checkErr(err, t)
+		encGradA, encCostA, gradNoiseA, _, err = CalSparseEncGradientAndCost(sparseRawA, sparsePartB, sparseA, pA, pubB, sparseThA, round)
+		checkErr(err, t)
+		encGradB, encCostB, gradNoiseB, costNoiseB, err = CalSparseEncGradientAndCost(sparseRawB, sparsePartA, sparseB, pB, pubA, sparseThB, round)
+		checkErr(err, t)
+		gradBytesA, _, err = DecGradientAndCost(encGradA, encCostA, privB, nil)
+		checkErr(err, t)
+		gradBytesB, costBytesB, err = DecGradientAndCost(encGradB, encCostB, privA, nil)
+		checkErr(err, t)
+		sparseCostB, err := UpdateCost(costBytesB, costNoiseB, nil, pB)
+		checkErr(err, t)
+		sparseThA, err = UpdateSparseGradient(gradBytesA, gradNoiseA, sparseA, sparseThA, pA, nil, round)
+		checkErr(err, t)
+		sparseThB, err = UpdateSparseGradient(gradBytesB, gradNoiseB, sparseB, sparseThB, pB, nil, round)
+		checkErr(err, t)
+
+		if math.Abs(costB-sparseCostB) > 1e-6 {
+			t.Errorf("round %d: expected cost %v, got %v", round, costB, sparseCostB)
+		}
+	}
+	for i := range thA {
+		if math.Abs(thA[i]-sparseThA[i]) > 1e-6 {
+			t.Errorf("expected theta %d of A %v, got %v", i, thA[i], sparseThA[i])
+		}
+	}
+	for i := range thB {
+		if math.Abs(thB[i]-sparseThB[i]) > 1e-6 {
+			t.Errorf("expected theta %d of B %v, got %v", i, thB[i], sparseThB[i])
+		}
+	}
+
+	// the model trained with the sparse rows predicts the dense and sparse rows alike
+	modelBytes, err := vl_common.SparseTrainModelsToBytes(sparseThB, sparseB, pB)
+	checkErr(err, t)
+	model, err := vl_common.TrainModelsFromBytes(modelBytes)
+	checkErr(err, t)
+	fileContentB, err = ioutil.ReadFile("../testdata/logic_iris_plants/predict_dataB.csv")
+	checkErr(err, t)
+	rowsB, err = csv.ReadRowsFromFile(fileContentB)
+	checkErr(err, t)
+	predictions, err := PredictLocalPart(rowsB, model)
+	checkErr(err, t)
+	sparsePredictions, err := PredictLocalPart(vl_common.SparsifyRows(rowsB, ""), model)
+	checkErr(err, t)
+	for i := range predictions {
+		if math.Abs(predictions[i]-sparsePredictions[i]) > 1e-9 {
+			t.Errorf("expected prediction of sample %d %v, got %v", i, predictions[i], sparsePredictions[i])
+		}
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
@@ -18,19 +18,32 @@ import (
 	"math"
 	"strconv"
 
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 )
 
 // PredictLocalPart calculate predict values for local part
-// fileRows is sample rows, first row is feature list, others are values for each sample
+// fileRows is sample rows, first row is feature list, others are values for each sample,
+// features should match the ones of params and are scaled by the parameters of params
 func PredictLocalPart(fileRows [][]string, params *pb_common.TrainModels) ([]float64, error) {
 	featureList := fileRows[0]
+	if err := vl_common.CheckPredictFeatures(featureList, params); err != nil {
+		return nil, err
+	}
+	// sparse rows are predicted without being converted into dense ones
+	if vl_common.HasSparseRows(fileRows) {
+		return vl_common.PredictSparseRows(fileRows, params)
+	}
 
 	var localPredictValues []float64
 	for i := 1; i < len(fileRows); i++ {
 		input := make(map[string]float64)
 		for j := 0; j < len(featureList); j++ {
 			featureName := featureList[j]
+			// weights of samples are only used in training
+			if featureName == params.WeightColumn {
+				continue
+			}
 			value, err := strconv.ParseFloat(fileRows[i][j], 64)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse value, err: %v", err)
@@ -52,14 +65,34 @@ func PredictLocalPart(fileRows [][]string, params *pb_common.TrainModels) ([]flo
 	return localPredictValues, nil
 }
 
+// ClassThreshold is the probability from which samples are predicted as the positive class
+const ClassThreshold = 0.5
+
 // CalRealPredictValue calculate final predict value by sum of predict parts
 func CalRealPredictValue(localPredict, otherPredict []float64) []float64 {
 	var realPredictValue []float64
 
-	for i := 0; i < len(localPredict); i++ {
-		predictSum := localPredict[i] + otherPredict[i]
-		realValue := 1 / (1 + math.Exp(-1*predictSum))
+	for _, score := range CalPredictScore(localPredict, otherPredict) {
+		realValue := 1 / (1 + math.Exp(-1*score))
 		realPredictValue = append(realPredictValue, realValue)
 	}
 	return realPredictValue
 }
+
+// CalPredictScore calculate the raw scores, which are the sums of predict parts before the sigmoid,
+// the probabilities are got from them, so they reveal nothing more than the probabilities
+func CalPredictScore(localPredict, otherPredict []float64) []float64 {
+	var scores []float64
+	for i := 0; i < len(localPredict); i++ {
+		scores = append(scores, localPredict[i]+otherPredict[i])
+	}
+	return scores
+}
+
+// PredictClass returns 1 if the sample of probability is predicted as the positive class, 0 otherwise
+func PredictClass(probability float64) int {
+	if probability >= ClassThreshold {
+		return 1
+	}
+	return 0
+}
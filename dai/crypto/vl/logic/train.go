@@ -30,7 +30,8 @@ var xchainCryptoClient = new(xchain.XchainCryptoClient)
 
 // GetTrainDataSetFromFile retrieve train dataset from file for tag/no-tag part
 // fileRows is sample rows, first row is feature list, others are values for each sample
-// params includes all required parameters for training
+// params includes all required parameters for training, features are scaled by params.Scaling,
+// or by params.BaseModel if it is set
 func GetTrainDataSetFromFile(fileRows [][]string, params pb_common.TrainParams) (*ml_common.TrainDataSet, error) {
 	features, err := xchainCryptoClient.LogRegImportFeatures(fileRows, params.Label, params.LabelName)
 	if err != nil {
@@ -41,6 +42,13 @@ func GetTrainDataSetFromFile(fileRows [][]string, params pb_common.TrainParams)
 		Features: features,
 	}
 	standardizedData := xchainCryptoClient.LogRegVLStandardizeDataSet(dataSet, params.Label)
+	if params.BaseModel != nil {
+		if err := vl_common.StandardizeByModel(standardizedData, params.BaseModel, params.Label); err != nil {
+			return nil, err
+		}
+	} else if err := vl_common.ScaleDataSet(standardizedData, params.Scaling, params.Label); err != nil {
+		return nil, err
+	}
 
 	if params.IsTagPart {
 		return xchainCryptoClient.LogRegVLPreProcessDataSetTagPart(standardizedData, params.Label), nil
@@ -111,21 +119,26 @@ func CalEncGradientAndCost(rawPart *logic_vertical.RawLocalGradAndCostPart, othe
 	// BGD, SGD or MBGD
 	trainSetThisRound, _ := vl_common.GetBatchSetBySize(trainSet.TrainSet, params, round, false)
 
-	var encGradList []map[int]*big.Int
-	var encGrad *ml_common.EncLocalGradient
-	var gradientNoise []*big.Int
-	for i := 0; i < len(thetas); i++ {
+	// gradients of features are encrypted in parallel, sharing the kernel worker pool with other tasks
+	encGradList := make([]map[int]*big.Int, len(thetas))
+	gradientNoise := make([]*big.Int, len(thetas))
+	errs := make([]error, len(thetas))
+	vl_common.Parallel(len(thetas), 0, func(i int) {
+		var encGrad *ml_common.EncLocalGradient
 		if params.IsTagPart {
-			encGrad, err = xchainCryptoClient.LogRegVLCalEncGradientTagPart(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+			encGrad, errs[i] = xchainCryptoClient.LogRegVLCalEncGradientTagPart(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
 		} else {
-			encGrad, err = xchainCryptoClient.LogRegVLCalEncGradient(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+			encGrad, errs[i] = xchainCryptoClient.LogRegVLCalEncGradient(rawPart, otherEncPart, trainSetThisRound, i, int(params.Accuracy), publicKey)
+		}
+		if errs[i] == nil {
+			encGradList[i] = encGrad.EncGrad
+			gradientNoise[i] = encGrad.RandomNoise
 		}
+	})
+	for _, err := range errs {
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
-
-		encGradList = append(encGradList, encGrad.EncGrad)
-		gradientNoise = append(gradientNoise, encGrad.RandomNoise)
 	}
 
 	var encCost *ml_common.EncLocalCost
@@ -156,24 +169,25 @@ func CalEncGradientAndCost(rawPart *logic_vertical.RawLocalGradAndCostPart, othe
 
 // DecGradientAndCost decrypt gradient list and cost for other part
 // encGradsBytes and encCostBytes are ciphertext received from other party, encrypted by local homomorphic public key
-// privateKey is local homomorphic private key, used to decrypt encGradsBytes and encCostBytes
-func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *paillier.PrivateKey) ([]byte, []byte, error) {
+// privateKey is local homomorphic private key, used to decrypt encGradsBytes and encCostBytes,
+// weights is the weights of samples of the party with label, the decrypted values are weighted if it is not nil
+func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *paillier.PrivateKey, weights map[int]float64) ([]byte, []byte, error) {
 	encGrads, err := vl_common.GradListFromBytes(encGradsBytes)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var decGradList []map[int]*big.Int
-	for i := 0; i < len(encGrads); i++ {
+	decGradList := make([]map[int]*big.Int, len(encGrads))
+	vl_common.Parallel(len(encGrads), 0, func(i int) {
 		grad := xchainCryptoClient.LogRegVLDecryptGradient(encGrads[i], privateKey)
-		decGradList = append(decGradList, grad)
-	}
+		decGradList[i] = vl_common.WeightDecrypted(grad, weights)
+	})
 
 	encCost, err := vl_common.CostFromBytes(encCostBytes)
 	if err != nil {
 		return nil, nil, err
 	}
-	cost := xchainCryptoClient.LogRegVLDecryptCost(encCost, privateKey)
+	cost := vl_common.WeightDecrypted(xchainCryptoClient.LogRegVLDecryptCost(encCost, privateKey), weights)
 
 	decGradBytes, err := vl_common.GradListToBytes(decGradList)
 	if err != nil {
@@ -188,38 +202,48 @@ func DecGradientAndCost(encGradsBytes []byte, encCostBytes []byte, privateKey *p
 }
 
 // UpdateCost retrieve and update cost
-// decCostBytes is decrypted cost received from other party, with costNoise
-func UpdateCost(decCostBytes []byte, costNoise *big.Int, params pb_common.TrainParams) (float64, error) {
+// decCostBytes is decrypted cost received from other party, with costNoise,
+// weights is the weights of local samples on the party with label, nil if samples are equally weighted
+func UpdateCost(decCostBytes []byte, costNoise *big.Int, weights map[int]float64, params pb_common.TrainParams) (float64, error) {
 	// retrieve real cost
 	costMap, err := vl_common.CostFromBytes(decCostBytes)
 	if err != nil {
 		return 0, err
 	}
 	realCost := xchainCryptoClient.LogRegVLRetrieveRealCost(costMap, int(params.Accuracy), costNoise)
-	cost := xchainCryptoClient.LogRegVLCalCost(realCost)
+	cost := xchainCryptoClient.LogRegVLCalCost(vl_common.WeightValues(realCost, weights))
 
 	return cost, nil
 }
 
 // UpdateGradient retrieve and update thetas
-// decGradBytes is decrypted gradient received from other party, with gradientNoise
-func UpdateGradient(decGradBytes []byte, gradientNoise []*big.Int, thetas []float64, params pb_common.TrainParams) ([]float64, error) {
+// decGradBytes is decrypted gradient received from other party, with gradientNoise,
+// Gaussian noise is added to the gradient if params.Dp is set, and thetas are updated by opt in round,
+// or by SGD of params.Alpha if opt is nil, the gradients of samples are weighted by weights if it is not nil
+func UpdateGradient(decGradBytes []byte, gradientNoise []*big.Int, weights map[int]float64, thetas []float64, params pb_common.TrainParams,
+	opt *vl_common.Optimizer, round int) ([]float64, error) {
 	grads, err := vl_common.GradListFromBytes(decGradBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	newThetas := make([]float64, len(thetas))
-	copy(newThetas[0:], thetas)
-
-	for i := 0; i < len(newThetas); i++ {
-		realGradient := xchainCryptoClient.LogRegVLRetrieveRealGradient(grads[i], int(params.Accuracy), gradientNoise[i])
+	// gradients are privatized as a whole for differential privacy
+	realGrads := make([]float64, len(thetas))
+	for i := 0; i < len(thetas); i++ {
+		realGradient := vl_common.WeightValues(xchainCryptoClient.LogRegVLRetrieveRealGradient(grads[i], int(params.Accuracy), gradientNoise[i]), weights)
 		//	grad := xchainCryptoClient.LogRegVLCalGradient(realGradient)
-		grad := xchainCryptoClient.LogRegVLCalGradientWithReg(thetas, realGradient, i, int(params.RegMode), params.RegParam)
-		newThetas[i] = newThetas[i] - params.Alpha*grad
+		realGrads[i] = xchainCryptoClient.LogRegVLCalGradientWithReg(thetas, realGradient, i, int(params.RegMode), params.RegParam)
+	}
+	if params.Dp != nil {
+		if realGrads, err = vl_common.PrivatizeGradient(realGrads, params.Dp); err != nil {
+			return nil, err
+		}
 	}
 
-	return newThetas, nil
+	if opt == nil {
+		opt = vl_common.NewOptimizer(params.Alpha, nil)
+	}
+	return opt.Update(thetas, realGrads, round), nil
 }
 
 // StopTraining determine if train process should be stopped
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// NodeKey identifies a node in the model
+type NodeKey struct {
+	Tree   uint64
+	NodeID int32
+}
+
+// TrainModelsToBytes convert train models to bytes for transfer and save
+func TrainModelsToBytes(model *pb_common.XGBoostModel, params *pb_common.TrainParams) ([]byte, error) {
+	trainModels := pb_common.TrainModels{
+		Label:     params.Label,
+		IsTagPart: params.IsTagPart,
+		Xgboost:   model,
+	}
+	return json.Marshal(trainModels)
+}
+
+// PredictLocalDecisions tells which samples go left at each split owned by local party,
+// called by the party without label, and the decisions are sent to the party with label
+// fileRows is sample rows, first row is feature list, others are values for each sample
+func PredictLocalDecisions(fileRows [][]string, params *pb_common.TrainModels) (map[NodeKey][]bool, error) {
+	model := params.GetXgboost()
+	if model == nil {
+		return nil, errorx.New(errcodes.ErrCodeParam, "xgboost model is empty")
+	}
+	samples, err := parseSamples(fileRows)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make(map[NodeKey][]bool)
+	for t, tree := range model.Trees {
+		for _, node := range tree.Nodes {
+			if node.IsLeaf || !node.IsLocal {
+				continue
+			}
+			left := make([]bool, len(samples))
+			for i, sample := range samples {
+				value, ok := sample[node.Feature]
+				if !ok {
+					return nil, errorx.New(errcodes.ErrCodeParam, "feature %s not found in samples", node.Feature)
+				}
+				left[i] = value <= node.Threshold
+			}
+			decisions[NodeKey{Tree: uint64(t), NodeID: node.Id}] = left
+		}
+	}
+	return decisions, nil
+}
+
+// Predict calculates final predict values by traversing all trees,
+// called by the party with label, decisions at the splits owned by other party are received from it
+// fileRows is sample rows, first row is feature list, others are values for each sample
+func Predict(fileRows [][]string, params *pb_common.TrainModels, otherDecisions map[NodeKey][]bool) ([]float64, error) {
+	model := params.GetXgboost()
+	if model == nil {
+		return nil, errorx.New(errcodes.ErrCodeParam, "xgboost model is empty")
+	}
+	samples, err := parseSamples(fileRows)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]float64, len(samples))
+	for i := range outcomes {
+		outcomes[i] = model.BaseScore
+	}
+	for t, tree := range model.Trees {
+		nodes := make(map[int32]*pb_common.XGBoostNode, len(tree.Nodes))
+		for _, node := range tree.Nodes {
+			nodes[node.Id] = node
+		}
+		for i, sample := range samples {
+			var id int32
+			for {
+				node, ok := nodes[id]
+				if !ok {
+					return nil, errorx.New(errcodes.ErrCodeParam, "node %d of tree %d not found in model", id, t)
+				}
+				if node.IsLeaf {
+					outcomes[i] += node.Weight
+					break
+				}
+
+				var left bool
+				if node.IsLocal {
+					value, ok := sample[node.Feature]
+					if !ok {
+						return nil, errorx.New(errcodes.ErrCodeParam, "feature %s not found in samples", node.Feature)
+					}
+					left = value <= node.Threshold
+				} else {
+					decision, ok := otherDecisions[NodeKey{Tree: uint64(t), NodeID: id}]
+					if !ok || len(decision) != len(samples) {
+						return nil, errorx.New(errcodes.ErrCodeParam, "decisions of node %d of tree %d not found", id, t)
+					}
+					left = decision[i]
+				}
+				if left {
+					id = 2*id + 1
+				} else {
+					id = 2*id + 2
+				}
+			}
+		}
+	}
+
+	if model.BinaryClass {
+		for i := range outcomes {
+			outcomes[i] = Sigmoid(outcomes[i])
+		}
+	}
+	return outcomes, nil
+}
+
+// parseSamples parses sample rows into feature-value maps
+func parseSamples(fileRows [][]string) ([]map[string]float64, error) {
+	if len(fileRows) == 0 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "no samples for prediction")
+	}
+	featureList := fileRows[0]
+
+	samples := make([]map[string]float64, 0, len(fileRows)-1)
+	for i := 1; i < len(fileRows); i++ {
+		sample := make(map[string]float64, len(featureList))
+		for j, name := range featureList {
+			if j >= len(fileRows[i]) {
+				break
+			}
+			value, err := strconv.ParseFloat(fileRows[i][j], 64)
+			if err != nil {
+				// label column or other non-numeric columns are not used in prediction
+				continue
+			}
+			sample[name] = value
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+const (
+	// MaxBins is the maximum number of bins the values of a feature are divided into,
+	// split candidates are the upper bounds of bins
+	MaxBins = 32
+
+	// MaxDepth is the maximum depth of a tree allowed
+	MaxDepth = 16
+	// MaxEstimators is the maximum number of trees allowed
+	MaxEstimators = 1000
+
+	// minChildHess is the minimum sum of hessians of a child node,
+	// splits producing empty children are ignored
+	minChildHess = 1e-6
+)
+
+// TrainDataSet is the local part of aligned samples used for training
+type TrainDataSet struct {
+	FeatureNames []string    // local features, label excluded
+	Bins         [][]int     // Bins[i][j] is the bin of j-th feature that i-th sample falls into
+	Thresholds   [][]float64 // Thresholds[j][k] is the upper bound of k-th bin of j-th feature
+	Labels       []float64   // labels of samples, only set for the part with label
+}
+
+// Histogram contains the sums of gradients and hessians in each bin of all features for a node
+type Histogram struct {
+	GradSums [][]float64 // GradSums[j][k] is the sum of gradients of samples in k-th bin of j-th feature
+	HessSums [][]float64 // HessSums[j][k] is the sum of hessians of samples in k-th bin of j-th feature
+}
+
+// EncHistogram is the Histogram encrypted by the homomorphic public key of the party with label
+type EncHistogram struct {
+	GradSums [][]*big.Int
+	HessSums [][]*big.Int
+}
+
+// Split is the best split found in a Histogram
+type Split struct {
+	FeatureIdx int     // index of the feature in the Histogram
+	Bin        int     // samples in bins not greater than Bin go left
+	Gain       float64 // reduction of loss
+	GradLeft   float64 // sum of gradients of the left child
+	HessLeft   float64 // sum of hessians of the left child
+}
+
+// CheckParams checks the hyperparameters of vertical XGBoost
+func CheckParams(params *pb_common.TrainParams) error {
+	xgb := params.GetXgbParams()
+	if xgb == nil {
+		return errorx.New(errcodes.ErrCodeParam, "xgbParams can not be empty for xgboost-vl")
+	}
+	if xgb.MaxDepth < 1 || xgb.MaxDepth > MaxDepth {
+		return errorx.New(errcodes.ErrCodeParam, "invalid maxDepth %d, it should be in the range of [1,%d]", xgb.MaxDepth, MaxDepth)
+	}
+	if xgb.NEstimators < 1 || xgb.NEstimators > MaxEstimators {
+		return errorx.New(errcodes.ErrCodeParam, "invalid nEstimators %d, it should be in the range of [1,%d]", xgb.NEstimators, MaxEstimators)
+	}
+	if xgb.LearningRate <= 0 || xgb.LearningRate > 1 {
+		return errorx.New(errcodes.ErrCodeParam, "invalid learningRate %v, it should be in the range of (0,1]", xgb.LearningRate)
+	}
+	if xgb.Lambda < 0 {
+		return errorx.New(errcodes.ErrCodeParam, "invalid lambda %v, it should not be negative", xgb.Lambda)
+	}
+	if params.Accuracy < 1 || params.Accuracy > 15 {
+		return errorx.New(errcodes.ErrCodeParam, "invalid accuracy %d, it should be in the range of [1,15]", params.Accuracy)
+	}
+	return nil
+}
+
+// CheckTaskParams checks the parameters of a vertical XGBoost task before it is accepted,
+// hyperparameters of train tasks are checked by CheckParams, and model evaluation is not supported yet
+func CheckTaskParams(params *pb_common.TaskParams) error {
+	if params.GetTaskType() != pb_common.TaskType_LEARN {
+		return nil
+	}
+	if err := CheckParams(params.GetTrainParams()); err != nil {
+		return err
+	}
+	if params.GetEvalParams().GetEnable() || params.GetLivalParams().GetEnable() {
+		return errorx.New(errcodes.ErrCodeParam, "model evaluation is not supported for xgboost-vl yet")
+	}
+	return nil
+}
+
+// GetTrainDataSetFromFile retrieve train dataset from file for tag/no-tag part,
+// values of each feature are divided into at most MaxBins bins by quantiles
+// fileRows is sample rows, first row is feature list, others are values for each sample
+// params includes all required parameters for training
+func GetTrainDataSetFromFile(fileRows [][]string, params *pb_common.TrainParams) (*TrainDataSet, error) {
+	if len(fileRows) < 2 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "no samples for training")
+	}
+
+	labelIdx := -1
+	var featureIdxs []int
+	dataSet := &TrainDataSet{}
+	for j, name := range fileRows[0] {
+		if params.IsTagPart && name == params.Label {
+			labelIdx = j
+			continue
+		}
+		featureIdxs = append(featureIdxs, j)
+		dataSet.FeatureNames = append(dataSet.FeatureNames, name)
+	}
+	if params.IsTagPart && labelIdx < 0 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "label %s not found in samples", params.Label)
+	}
+
+	sampleNum := len(fileRows) - 1
+	values := make([][]float64, len(featureIdxs))
+	for j := range values {
+		values[j] = make([]float64, sampleNum)
+	}
+	for i := 0; i < sampleNum; i++ {
+		row := fileRows[i+1]
+		if len(row) != len(fileRows[0]) {
+			return nil, errorx.New(errcodes.ErrCodeParam, "invalid sample row %d, expected %d values but got %d", i+1, len(fileRows[0]), len(row))
+		}
+		for j, idx := range featureIdxs {
+			value, err := strconv.ParseFloat(row[idx], 64)
+			if err != nil {
+				return nil, errorx.New(errcodes.ErrCodeParam, "failed to parse value, err: %v", err)
+			}
+			values[j][i] = value
+		}
+		if labelIdx >= 0 {
+			label, err := parseLabel(row[labelIdx], params.LabelName)
+			if err != nil {
+				return nil, err
+			}
+			dataSet.Labels = append(dataSet.Labels, label)
+		}
+	}
+
+	dataSet.Thresholds = make([][]float64, len(featureIdxs))
+	for j := range values {
+		dataSet.Thresholds[j] = binThresholds(values[j])
+	}
+	dataSet.Bins = make([][]int, sampleNum)
+	for i := 0; i < sampleNum; i++ {
+		dataSet.Bins[i] = make([]int, len(featureIdxs))
+		for j := range values {
+			dataSet.Bins[i][j] = sort.SearchFloat64s(dataSet.Thresholds[j], values[j][i])
+		}
+	}
+	return dataSet, nil
+}
+
+// parseLabel parses label value, if labelName is set, the task is binary classification,
+// and label is 1 if value equals labelName, otherwise 0
+func parseLabel(value, labelName string) (float64, error) {
+	if labelName != "" {
+		if value == labelName {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	label, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errorx.New(errcodes.ErrCodeParam, "failed to parse label, err: %v", err)
+	}
+	return label, nil
+}
+
+// binThresholds returns the ascending upper bounds of bins divided by quantiles
+func binThresholds(values []float64) []float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var thresholds []float64
+	for k := 1; k <= MaxBins; k++ {
+		idx := k*len(sorted)/MaxBins - 1
+		if idx < 0 {
+			continue
+		}
+		t := sorted[idx]
+		if len(thresholds) == 0 || t > thresholds[len(thresholds)-1] {
+			thresholds = append(thresholds, t)
+		}
+	}
+	return thresholds
+}
+
+// BaseScore returns the initial prediction of all samples,
+// the mean of labels for regression, and the log odds of the mean for binary classification
+func BaseScore(labels []float64, binaryClass bool) float64 {
+	var sum float64
+	for _, l := range labels {
+		sum += l
+	}
+	mean := sum / float64(len(labels))
+	if !binaryClass {
+		return mean
+	}
+	mean = math.Min(math.Max(mean, 1e-6), 1-1e-6)
+	return math.Log(mean / (1 - mean))
+}
+
+// CalGradAndHess calculates first and second order gradients of loss for each sample,
+// squared error is used for regression, and logistic loss for binary classification
+func CalGradAndHess(labels, preds []float64, binaryClass bool) ([]float64, []float64) {
+	grads := make([]float64, len(labels))
+	hess := make([]float64, len(labels))
+	for i := range labels {
+		if binaryClass {
+			p := Sigmoid(preds[i])
+			grads[i] = p - labels[i]
+			hess[i] = p * (1 - p)
+		} else {
+			grads[i] = preds[i] - labels[i]
+			hess[i] = 1
+		}
+	}
+	return grads, hess
+}
+
+// Sigmoid returns 1/(1+e^-x)
+func Sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// EncryptGradAndHess encrypts gradients and hessians with local public key for the party without label,
+// values are amplified by 10^accuracy as integers before encryption
+func EncryptGradAndHess(grads, hess []float64, accuracy int64, publicKey *paillier.PublicKey) ([]*big.Int, []*big.Int, error) {
+	encrypt := func(values []float64) ([]*big.Int, error) {
+		encValues := make([]*big.Int, len(values))
+		for i, v := range values {
+			c, err := publicKey.EncryptSupNegNum(encode(v, accuracy))
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt, err: %v", err)
+			}
+			encValues[i] = c
+		}
+		return encValues, nil
+	}
+
+	encGrads, err := encrypt(grads)
+	if err != nil {
+		return nil, nil, err
+	}
+	encHess, err := encrypt(hess)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encGrads, encHess, nil
+}
+
+// CalEncHistogram sums up encrypted gradients and hessians in each bin of local features for the samples in a node,
+// the party without label calculates it without knowing the plaintext
+func CalEncHistogram(trainSet *TrainDataSet, encGrads, encHess []*big.Int, samples []int, publicKey *paillier.PublicKey) *EncHistogram {
+	featureNum := len(trainSet.FeatureNames)
+	binned := make([][][]*big.Int, featureNum)
+	binnedHess := make([][][]*big.Int, featureNum)
+	for j := 0; j < featureNum; j++ {
+		binned[j] = make([][]*big.Int, len(trainSet.Thresholds[j]))
+		binnedHess[j] = make([][]*big.Int, len(trainSet.Thresholds[j]))
+	}
+	for _, i := range samples {
+		for j, b := range trainSet.Bins[i] {
+			binned[j][b] = append(binned[j][b], encGrads[i])
+			binnedHess[j][b] = append(binnedHess[j][b], encHess[i])
+		}
+	}
+
+	hist := &EncHistogram{
+		GradSums: make([][]*big.Int, featureNum),
+		HessSums: make([][]*big.Int, featureNum),
+	}
+	for j := 0; j < featureNum; j++ {
+		for b := range binned[j] {
+			hist.GradSums[j] = append(hist.GradSums[j], publicKey.CyphersAdd(binned[j][b]...))
+			hist.HessSums[j] = append(hist.HessSums[j], publicKey.CyphersAdd(binnedHess[j][b]...))
+		}
+	}
+	return hist
+}
+
+// DecryptHistogram decrypts the histogram from the party without label
+func DecryptHistogram(encHist *EncHistogram, accuracy int64, privateKey *paillier.PrivateKey) *Histogram {
+	decrypt := func(encSums [][]*big.Int) [][]float64 {
+		sums := make([][]float64, len(encSums))
+		for j := range encSums {
+			sums[j] = make([]float64, len(encSums[j]))
+			for b, c := range encSums[j] {
+				sums[j][b] = decode(privateKey.DecryptSupNegNum(c), accuracy)
+			}
+		}
+		return sums
+	}
+	return &Histogram{
+		GradSums: decrypt(encHist.GradSums),
+		HessSums: decrypt(encHist.HessSums),
+	}
+}
+
+// CalHistogram sums up gradients and hessians in each bin of local features for the samples in a node
+func CalHistogram(trainSet *TrainDataSet, grads, hess []float64, samples []int) *Histogram {
+	featureNum := len(trainSet.FeatureNames)
+	hist := &Histogram{
+		GradSums: make([][]float64, featureNum),
+		HessSums: make([][]float64, featureNum),
+	}
+	for j := 0; j < featureNum; j++ {
+		hist.GradSums[j] = make([]float64, len(trainSet.Thresholds[j]))
+		hist.HessSums[j] = make([]float64, len(trainSet.Thresholds[j]))
+	}
+	for _, i := range samples {
+		for j, b := range trainSet.Bins[i] {
+			hist.GradSums[j][b] += grads[i]
+			hist.HessSums[j][b] += hess[i]
+		}
+	}
+	return hist
+}
+
+// BestSplit finds the split with maximum gain in the histogram,
+// gradSum and hessSum are the sums of gradients and hessians of all samples in the node
+// returns false if no split reduces the loss
+func BestSplit(hist *Histogram, gradSum, hessSum, lambda float64) (Split, bool) {
+	best := Split{}
+	found := false
+	parentScore := gradSum * gradSum / (hessSum + lambda)
+	for j := range hist.GradSums {
+		var gl, hl float64
+		// the last bin can't be a split, for all samples go left
+		for b := 0; b < len(hist.GradSums[j])-1; b++ {
+			gl += hist.GradSums[j][b]
+			hl += hist.HessSums[j][b]
+			gr, hr := gradSum-gl, hessSum-hl
+			if hl < minChildHess || hr < minChildHess {
+				continue
+			}
+			gain := (gl*gl/(hl+lambda) + gr*gr/(hr+lambda) - parentScore) / 2
+			if gain > best.Gain {
+				best = Split{FeatureIdx: j, Bin: b, Gain: gain, GradLeft: gl, HessLeft: hl}
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// LeafWeight calculates the weight of a leaf, shrunk by learning rate
+func LeafWeight(gradSum, hessSum, lambda, learningRate float64) float64 {
+	return -gradSum / (hessSum + lambda) * learningRate
+}
+
+// SplitSamples divides samples by the bin of a local feature, returns samples going left and right
+func SplitSamples(trainSet *TrainDataSet, samples []int, featureIdx, bin int) ([]int, []int) {
+	var left, right []int
+	for _, i := range samples {
+		if trainSet.Bins[i][featureIdx] <= bin {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	return left, right
+}
+
+// encode amplifies v by 10^accuracy to an integer
+func encode(v float64, accuracy int64) *big.Int {
+	f := new(big.Float).Mul(big.NewFloat(v), big.NewFloat(math.Pow10(int(accuracy))))
+	i, _ := f.Int(nil)
+	return i
+}
+
+// decode recovers the float value amplified by 10^accuracy
+func decode(i *big.Int, accuracy int64) float64 {
+	f := new(big.Float).Quo(new(big.Float).SetInt(i), big.NewFloat(math.Pow10(int(accuracy))))
+	v, _ := f.Float64()
+	return v
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	pb_common "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestCheckParams(t *testing.T) {
+	valid := func() *pb_common.TrainParams {
+		return &pb_common.TrainParams{
+			Accuracy: 10,
+			XgbParams: &pb_common.XGBoostParams{
+				MaxDepth:     3,
+				LearningRate: 0.3,
+				NEstimators:  10,
+				Lambda:       1,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		modify  func(p *pb_common.TrainParams)
+		wantErr bool
+	}{
+		"valid":              {modify: func(p *pb_common.TrainParams) {}},
+		"noXgbParams":        {modify: func(p *pb_common.TrainParams) { p.XgbParams = nil }, wantErr: true},
+		"zeroMaxDepth":       {modify: func(p *pb_common.TrainParams) { p.XgbParams.MaxDepth = 0 }, wantErr: true},
+		"tooDeep":            {modify: func(p *pb_common.TrainParams) { p.XgbParams.MaxDepth = MaxDepth + 1 }, wantErr: true},
+		"zeroEstimators":     {modify: func(p *pb_common.TrainParams) { p.XgbParams.NEstimators = 0 }, wantErr: true},
+		"zeroLearningRate":   {modify: func(p *pb_common.TrainParams) { p.XgbParams.LearningRate = 0 }, wantErr: true},
+		"largeLearningRate":  {modify: func(p *pb_common.TrainParams) { p.XgbParams.LearningRate = 1.5 }, wantErr: true},
+		"negativeLambda":     {modify: func(p *pb_common.TrainParams) { p.XgbParams.Lambda = -1 }, wantErr: true},
+		"zeroLambda":         {modify: func(p *pb_common.TrainParams) { p.XgbParams.Lambda = 0 }},
+		"zeroAccuracy":       {modify: func(p *pb_common.TrainParams) { p.Accuracy = 0 }, wantErr: true},
+		"unlimitedEstimator": {modify: func(p *pb_common.TrainParams) { p.XgbParams.NEstimators = MaxEstimators + 1 }, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			err := CheckParams(p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTaskParams(t *testing.T) {
+	train := &pb_common.TrainParams{
+		Accuracy:  10,
+		XgbParams: &pb_common.XGBoostParams{MaxDepth: 3, LearningRate: 0.3, NEstimators: 10, Lambda: 1},
+	}
+	tests := map[string]struct {
+		params  *pb_common.TaskParams
+		wantErr bool
+	}{
+		"valid":          {params: &pb_common.TaskParams{TrainParams: train}},
+		"predict":        {params: &pb_common.TaskParams{TaskType: pb_common.TaskType_PREDICT}},
+		"noXgbParams":    {params: &pb_common.TaskParams{TrainParams: &pb_common.TrainParams{Accuracy: 10}}, wantErr: true},
+		"evaluation":     {params: &pb_common.TaskParams{TrainParams: train, EvalParams: &pb_common.EvaluationParams{Enable: true}}, wantErr: true},
+		"liveEvaluation": {params: &pb_common.TaskParams{TrainParams: train, LivalParams: &pb_common.LiveEvaluationParams{Enable: true}}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckTaskParams(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckTaskParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBinThresholds(t *testing.T) {
+	values := []float64{3, 1, 2, 2, 1}
+	thresholds := binThresholds(values)
+	want := []float64{1, 2, 3}
+	if len(thresholds) != len(want) {
+		t.Fatalf("binThresholds() = %v, want %v", thresholds, want)
+	}
+	for i := range want {
+		if thresholds[i] != want[i] {
+			t.Fatalf("binThresholds() = %v, want %v", thresholds, want)
+		}
+	}
+
+	var many []float64
+	for i := 0; i < 1000; i++ {
+		many = append(many, float64(i))
+	}
+	if n := len(binThresholds(many)); n != MaxBins {
+		t.Errorf("got %d bins, want %d", n, MaxBins)
+	}
+}
+
+func TestEncHistogram(t *testing.T) {
+	fileRowsA := readFile(t, "../testdata/logic_iris_plants/train_dataA.csv")
+	fileRowsB := readFile(t, "../testdata/logic_iris_plants/train_dataB.csv")
+
+	paramsA := &pb_common.TrainParams{Label: "Label", LabelName: "Iris-setosa", Accuracy: 10}
+	paramsB := &pb_common.TrainParams{Label: "Label", LabelName: "Iris-setosa", Accuracy: 10, IsTagPart: true}
+	dataSetA, err := GetTrainDataSetFromFile(fileRowsA, paramsA)
+	checkErr(err, t)
+	dataSetB, err := GetTrainDataSetFromFile(fileRowsB, paramsB)
+	checkErr(err, t)
+	if len(dataSetB.FeatureNames) != 2 || len(dataSetB.Labels) != len(fileRowsB)-1 {
+		t.Fatalf("invalid data set of tag part, features %v, labels %d", dataSetB.FeatureNames, len(dataSetB.Labels))
+	}
+
+	homoPriv, err := paillier.GeneratePrivateKey(paillier.DefaultPrimeLength)
+	checkErr(err, t)
+
+	base := BaseScore(dataSetB.Labels, true)
+	preds := make([]float64, len(dataSetB.Labels))
+	for i := range preds {
+		preds[i] = base
+	}
+	grads, hess := CalGradAndHess(dataSetB.Labels, preds, true)
+	encGrads, encHess, err := EncryptGradAndHess(grads, hess, paramsB.Accuracy, &homoPriv.PublicKey)
+	checkErr(err, t)
+
+	var samples []int
+	for i := 0; i < len(grads); i += 2 {
+		samples = append(samples, i)
+	}
+	encHist := CalEncHistogram(dataSetA, encGrads, encHess, samples, &homoPriv.PublicKey)
+	hist := DecryptHistogram(encHist, paramsB.Accuracy, homoPriv)
+	plainHist := CalHistogram(dataSetA, grads, hess, samples)
+	for j := range plainHist.GradSums {
+		for b := range plainHist.GradSums[j] {
+			if math.Abs(hist.GradSums[j][b]-plainHist.GradSums[j][b]) > 1e-6 ||
+				math.Abs(hist.HessSums[j][b]-plainHist.HessSums[j][b]) > 1e-6 {
+				t.Fatalf("decrypted histogram mismatch at feature %d bin %d", j, b)
+			}
+		}
+	}
+
+	// setosa is separated by petal features of tag part better than by sepal features
+	var gradSum, hessSum float64
+	for _, i := range samples {
+		gradSum += grads[i]
+		hessSum += hess[i]
+	}
+	splitA, foundA := BestSplit(hist, gradSum, hessSum, 1)
+	splitB, foundB := BestSplit(CalHistogram(dataSetB, grads, hess, samples), gradSum, hessSum, 1)
+	if !foundA || !foundB {
+		t.Fatalf("no split found, %v %v", foundA, foundB)
+	}
+	if splitB.Gain <= splitA.Gain {
+		t.Errorf("expected split on petal features, gain of sepal %v, gain of petal %v", splitA.Gain, splitB.Gain)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	fileRowsA := [][]string{{"a"}, {"1"}, {"5"}, {"5"}}
+	fileRowsB := [][]string{{"b"}, {"1"}, {"1"}, {"9"}}
+
+	// root is split by feature `a` of party A, and its right child is split by feature `b` of party B
+	modelA := &pb_common.TrainModels{Xgboost: &pb_common.XGBoostModel{
+		Trees: []*pb_common.XGBoostTree{{Nodes: []*pb_common.XGBoostNode{
+			{Id: 0, IsLocal: true, Feature: "a", Threshold: 3},
+		}}},
+	}}
+	modelB := &pb_common.TrainModels{IsTagPart: true, Xgboost: &pb_common.XGBoostModel{
+		BaseScore: 0.5,
+		Trees: []*pb_common.XGBoostTree{{Nodes: []*pb_common.XGBoostNode{
+			{Id: 0},
+			{Id: 1, IsLeaf: true, Weight: -1},
+			{Id: 2, IsLocal: true, Feature: "b", Threshold: 5},
+			{Id: 5, IsLeaf: true, Weight: 1},
+			{Id: 6, IsLeaf: true, Weight: 2},
+		}}},
+	}}
+
+	decisions, err := PredictLocalDecisions(fileRowsA, modelA)
+	checkErr(err, t)
+	outcomes, err := Predict(fileRowsB, modelB, decisions)
+	checkErr(err, t)
+	want := []float64{-0.5, 1.5, 2.5}
+	for i := range want {
+		if math.Abs(outcomes[i]-want[i]) > 1e-9 {
+			t.Fatalf("Predict() = %v, want %v", outcomes, want)
+		}
+	}
+
+	if _, err := Predict(fileRowsB, modelB, nil); err == nil {
+		t.Error("expected error without decisions of other party")
+	}
+}
+
+func readFile(t *testing.T, path string) [][]string {
+	content, err := ioutil.ReadFile(path)
+	checkErr(err, t)
+	rows, err := csv.ReadRowsFromFile(content)
+	checkErr(err, t)
+	return rows
+}
+
+func checkErr(err error, t *testing.T) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -16,12 +16,13 @@ package errcodes
 // error code list
 const (
 	// 00xx common error
-	ErrCodeInternal = "PX0001" // internal error
-	ErrCodeParam    = "PX0002" // parameters error
-	ErrCodeConfig   = "PX0003" // configuration error
-	ErrCodeNotFound = "PX0004" // target not found
-	ErrCodeEncoding = "PX0005" // encoding error
-	ErrCodeUnknown  = "PX0006" // unknown error
+	ErrCodeInternal     = "PX0001" // internal error
+	ErrCodeParam        = "PX0002" // parameters error
+	ErrCodeConfig       = "PX0003" // configuration error
+	ErrCodeNotFound     = "PX0004" // target not found
+	ErrCodeEncoding     = "PX0005" // encoding error
+	ErrCodeUnknown      = "PX0006" // unknown error
+	ErrCodeNotSupported = "PX0007" // operation not supported
 
 	// mpc errors
 	ErrCodeTooMuchTasks          = "PX0011" // the number of task reach upper-limit
@@ -39,4 +40,21 @@ const (
 	ErrCodeGetPredictSet         = "PX0022" // failed to split predicting set when evaluate model
 	ErrCodeStartTask             = "PX0023" // failed to start task
 	ErrCodeTriggerTooMuch        = "PX0024" // LiveEvaluator be triggered more than once for same pause round
+	ErrCodeTaskCancelled         = "PX0025" // task is cancelled
+	ErrCodeShuttingDown          = "PX0026" // executor is shutting down and refuses new tasks
+	ErrCodeIntegrity             = "PX0027" // downloaded file does not match its checksum
+	ErrCodePSIAlgorithmMismatch  = "PX0028" // parties of a task use different PSI algorithms
+	ErrCodeModelDrift            = "PX0029" // the model updated by incremental training is worse than the base model
+	ErrCodeSampleFileTooLarge    = "PX0030" // sample file exceeds the size limit of the executor
+	ErrCodeObserverRole          = "PX0031" // the node is an observer, which never executes tasks
+	ErrCodeTaskMismatch          = "PX0032" // executors of a task disagree on its fingerprint before it starts
+	ErrCodePeerUnavailable       = "PX0033" // a peer executor of the task keeps failing and is cut off by the circuit breaker
+	ErrCodeColumnPolicy          = "PX0034" // the task uses columns of a sample file not allowed by the column policy of its owner
+	ErrCodeForbidden             = "PX0035" // the API token is not allowed to access the task by its labels
+	ErrCodeDryRun                = "PX0036" // the dry run before training finds the cost becomes NaN or Inf or diverges
+	ErrCodeTooManyFeatures       = "PX0037" // a sample file of the task has more features than the executor allows
+	ErrCodeChainDisconnected     = "PX0038" // the blockchain is unreachable, and task status updates are buffered
+	ErrCodeAccuracyMismatch      = "PX0039" // parties of a task use different accuracies of the fixed-point encoding
+	ErrCodeEncodingOverflow      = "PX0040" // values of a training task overflow the fixed-point encoding of its accuracy
+	ErrCodeRequesterNotAllowed   = "PX0041" // the requester is not in the requester allowlist of the executor
 )
@@ -16,11 +16,17 @@ package client
 import (
 	"context"
 	"encoding/hex"
+	"io"
 
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 	"google.golang.org/grpc"
 
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
 )
 
 type Client struct {
@@ -28,10 +34,14 @@ type Client struct {
 	conn           *grpc.ClientConn
 }
 
-// GetExecutorClient returns executor client
-func GetExecutorClient(executorPort string) (*Client, error) {
-	// check blockchain config yaml
-	conn, err := grpc.Dial(executorPort, grpc.WithInsecure())
+// GetExecutorClient returns executor client, tlsConf is required if the gRPC server of the executor requires TLS,
+// the executor is connected in plaintext if tlsConf is nil
+func GetExecutorClient(executorPort string, tlsConf *config.TLSConf) (*Client, error) {
+	dialOpt, err := tlsutil.DialOption(tlsConf)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(executorPort, dialOpt)
 	if err != nil {
 		return nil, errorx.Wrap(err, "CAN_NOT_CONNECT_EXECUTOR_SERVER")
 	}
@@ -41,13 +51,14 @@ func GetExecutorClient(executorPort string) (*Client, error) {
 }
 
 // GetTaskById gets task by id through executor server
-func (c *Client) GetTaskById(ctx context.Context, id string) (*pbTask.FLTask, error) {
+func (c *Client) GetTaskById(ctx context.Context, id, network string) (*pbTask.FLTask, error) {
 	if c.conn != nil {
 		defer c.conn.Close()
 	}
 
 	in := &pbTask.GetTaskRequest{
-		TaskID: id,
+		TaskID:  id,
+		Network: network,
 	}
 
 	out, err := c.executorClient.GetTaskById(ctx, in)
@@ -63,8 +74,9 @@ func (c *Client) GetTaskById(ctx context.Context, id string) (*pbTask.FLTask, er
 // status is task status to search
 // only task published after "start" before "end" will be listed
 // limit is the maximum number of tasks to response
+// network is the blockchain network to search, all networks the executor joins if it is empty
 func (c *Client) ListTask(ctx context.Context, rPubkeyStr, ePubkeyStr, status string, start, end,
-	limit int64) (ts *pbTask.FLTasks, err error) {
+	limit int64, network string) (ts *pbTask.FLTasks, err error) {
 	if c.conn != nil {
 		defer c.conn.Close()
 	}
@@ -86,6 +98,7 @@ func (c *Client) ListTask(ctx context.Context, rPubkeyStr, ePubkeyStr, status st
 		TimeEnd:   end,
 		Status:    status,
 		Limit:     limit,
+		Network:   network,
 	}
 
 	ts, err = c.executorClient.ListTask(ctx, in)
@@ -95,3 +108,127 @@ func (c *Client) ListTask(ctx context.Context, rPubkeyStr, ePubkeyStr, status st
 
 	return ts, nil
 }
+
+// ListTasks queries the history of tasks the executor participates in, filtered by status, task type, publish time
+// and labels, only the tasks with all the labels are listed
+func (c *Client) ListTasks(ctx context.Context, status, taskType string, start, end, limit,
+	offset int64, labels map[string]string, network string) (*pbTask.TaskSummaries, error) {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	in := &pbTask.ListTasksRequest{
+		Status:    status,
+		TaskType:  taskType,
+		TimeStart: start,
+		TimeEnd:   end,
+		Limit:     limit,
+		Offset:    offset,
+		Labels:    labels,
+		Network:   network,
+	}
+	out, err := c.executorClient.ListTasks(ctx, in)
+	if err != nil {
+		return &pbTask.TaskSummaries{}, err
+	}
+	return out, nil
+}
+
+// StreamLiveEvaluation watches the live evaluation of a task in execution,
+// handle is called with each metric score received, it returns when the task ends
+func (c *Client) StreamLiveEvaluation(ctx context.Context, taskID string, handle func(*pbTask.LiveEvaluationMetric)) error {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	stream, err := c.executorClient.StreamLiveEvaluation(ctx, &pbTask.LiveEvaluationRequest{TaskID: taskID})
+	if err != nil {
+		return err
+	}
+	for {
+		metric, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		handle(metric)
+	}
+}
+
+// GetNodeStatus gets tasks in execution and resources usage of the executor node
+func (c *Client) GetNodeStatus(ctx context.Context) (*pbTask.NodeStatus, error) {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	out, err := c.executorClient.GetNodeStatus(ctx, &pbTask.NodeStatusRequest{})
+	if err != nil {
+		return &pbTask.NodeStatus{}, err
+	}
+	return out, nil
+}
+
+// GetNodeInfo gets the name, public address, public key, supported algorithms and build information of the executor node
+func (c *Client) GetNodeInfo(ctx context.Context) (*pbTask.NodeInfo, error) {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	out, err := c.executorClient.GetNodeInfo(ctx, &pbTask.NodeInfoRequest{})
+	if err != nil {
+		return &pbTask.NodeInfo{}, err
+	}
+	return out, nil
+}
+
+// CancelQueuedTask cancels the task id waiting in the queue of the executor node,
+// privateKey is the private key of the node, as only the operator of the node manages its queue
+func (c *Client) CancelQueuedTask(ctx context.Context, privateKey, id string) error {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	in, err := signQueuedTaskRequest(privateKey, &pbTask.QueuedTaskRequest{TaskID: id})
+	if err != nil {
+		return err
+	}
+	_, err = c.executorClient.CancelQueuedTask(ctx, in)
+	return err
+}
+
+// SetQueuedTaskPriority changes the priority of the task id waiting in the queue of the executor node,
+// privateKey is the private key of the node
+func (c *Client) SetQueuedTaskPriority(ctx context.Context, privateKey, id string, priority int32) error {
+	if c.conn != nil {
+		defer c.conn.Close()
+	}
+
+	in, err := signQueuedTaskRequest(privateKey, &pbTask.QueuedTaskRequest{TaskID: id, Priority: priority})
+	if err != nil {
+		return err
+	}
+	_, err = c.executorClient.SetQueuedTaskPriority(ctx, in)
+	return err
+}
+
+// signQueuedTaskRequest signs the request to manage the queue with the private key of the executor node
+func signQueuedTaskRequest(privateKey string, in *pbTask.QueuedTaskRequest) (*pbTask.QueuedTaskRequest, error) {
+	privkey, err := ecdsa.DecodePrivateKeyFromString(privateKey)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to decode private key")
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(privkey)
+	in.PubKey = pubkey[:]
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to get the message to sign for queued task")
+	}
+	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to sign queued task request")
+	}
+	in.Signature = sig[:]
+	return in, nil
+}
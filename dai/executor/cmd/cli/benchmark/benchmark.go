@@ -0,0 +1,323 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// parties are the simulated parties, the last one holds the label
+var parties = []string{"party1", "party2"}
+
+var (
+	algorithm  string
+	samples    int
+	features   int
+	iterations int
+	amplitude  float64
+	batchSize  int64
+	seed       int64
+	timeout    time.Duration
+	cpuProfile string
+	memProfile string
+)
+
+// Workload describes the samples and the algorithm of the tasks benchmarked
+type Workload struct {
+	Algo     pbCom.Algorithm
+	Samples  int   // number of samples of each party
+	Features int   // number of features of each party
+	Seed     int64 // seed of the generated samples and mini-batch shuffling
+	// Amplitude and BatchSize are the training parameters of linear and logistic regression
+	Amplitude float64
+	BatchSize int64
+}
+
+// Result is the performance of the tasks of a type, allocations are of the whole process
+// in which all parties run, as they would be in production on each executor
+type Result struct {
+	Runs     int
+	Duration time.Duration // total duration of the runs
+	Samples  int           // samples processed by each run
+	Bytes    uint64        // bytes allocated by all runs
+	Mallocs  uint64        // heap objects allocated by all runs
+}
+
+// Throughput returns the samples processed per second
+func (r Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Samples*r.Runs) / r.Duration.Seconds()
+}
+
+// String formats r as a line of the report
+func (r Result) String() string {
+	runs := uint64(r.Runs)
+	return fmt.Sprintf("runs %d, %v/run, %.1f samples/s, %d B/run, %d allocs/run",
+		r.Runs, r.Duration/time.Duration(r.Runs), r.Throughput(), r.Bytes/runs, r.Mallocs/runs)
+}
+
+// rootCmd benchmarks training and prediction by simulated parties
+var rootCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "benchmark training and prediction of generated samples in simulation, and report throughput and allocations",
+	Run: func(cmd *cobra.Command, args []string) {
+		algo, ok := blockchain.VlAlgorithmListName[algorithm]
+		if !ok || algo == pbCom.Algorithm_DNN_PADDLEFL_VL {
+			fmt.Println("algorithm only support linear-vl, logistic-vl or xgboost-vl")
+			return
+		}
+		if samples < 2 || features < 1 || iterations < 1 {
+			fmt.Println("samples should be at least 2, features and iterations at least 1")
+			return
+		}
+		if cpuProfile != "" {
+			f, err := os.Create(cpuProfile)
+			if err != nil {
+				fmt.Printf("failed to create cpu profile: %v\n", err)
+				return
+			}
+			defer f.Close()
+			if err := pprof.StartCPUProfile(f); err != nil {
+				fmt.Printf("failed to start cpu profile: %v\n", err)
+				return
+			}
+			defer pprof.StopCPUProfile()
+		}
+
+		w := Workload{
+			Algo:      algo,
+			Samples:   samples,
+			Features:  features,
+			Seed:      seed,
+			Amplitude: amplitude,
+			BatchSize: batchSize,
+		}
+		fmt.Printf("Workload: %s, %d parties, %d samples, %d features of each party\n",
+			algorithm, len(parties), samples, features)
+		train, predict, err := Run(w, iterations, timeout)
+		if err != nil {
+			fmt.Printf("benchmark failed: %v\n", err)
+			return
+		}
+		fmt.Printf("Train:   %s\n", train)
+		fmt.Printf("Predict: %s\n", predict)
+
+		if memProfile != "" {
+			f, err := os.Create(memProfile)
+			if err != nil {
+				fmt.Printf("failed to create memory profile: %v\n", err)
+				return
+			}
+			defer f.Close()
+			if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+				fmt.Printf("failed to write memory profile: %v\n", err)
+			}
+		}
+	},
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// Run trains models of w by the simulated parties iterations times, then predicts the samples by the last models
+// iterations times, each task is run with the same samples and parameters, timeout is the limit of each task
+func Run(w Workload, iterations int, timeout time.Duration) (train, predict Result, err error) {
+	sim := mpc.NewSimulation(mpc.Config{
+		TrainTaskLimit:   1,
+		PredictTaskLimit: 1,
+		RpcTimeout:       time.Minute,
+	})
+	defer sim.Stop()
+	for _, p := range parties {
+		if err := sim.AddParty(p); err != nil {
+			return train, predict, err
+		}
+	}
+
+	files := generateSamples(w)
+	var results map[string]mpc.TaskResult
+	train, err = measure(iterations, w.Samples, func(i int) error {
+		results, err = sim.Run(startTaskRequests(w, files, fmt.Sprintf("benchmark-train-%d", i), nil), timeout)
+		return err
+	})
+	if err != nil {
+		return train, predict, errorx.Wrap(err, "training failed")
+	}
+
+	models := make(map[string]*pbCom.TrainModels)
+	for _, p := range parties {
+		if models[p], err = vl_common.TrainModelsFromBytes(results[p].Train.Model); err != nil {
+			return train, predict, errorx.Wrap(err, "invalid model of %s", p)
+		}
+	}
+	predict, err = measure(iterations, w.Samples, func(i int) error {
+		_, err := sim.Run(startTaskRequests(w, files, fmt.Sprintf("benchmark-predict-%d", i), models), timeout)
+		return err
+	})
+	if err != nil {
+		return train, predict, errorx.Wrap(err, "prediction failed")
+	}
+	return train, predict, nil
+}
+
+// measure runs f iterations times, and measures the duration and the allocations of all runs
+func measure(iterations, samples int, f func(i int) error) (Result, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		if err := f(i); err != nil {
+			return Result{}, err
+		}
+	}
+	r := Result{Runs: iterations, Duration: time.Since(start), Samples: samples}
+	runtime.ReadMemStats(&after)
+	r.Bytes = after.TotalAlloc - before.TotalAlloc
+	r.Mallocs = after.Mallocs - before.Mallocs
+	return r, nil
+}
+
+// generateSamples returns the sample files of the parties keyed by party, all parties have the same IDs in column 'id'
+// and features in columns 'x<party>_<index>', and the last party has the label 'y' as a linear function of all features
+// with noise, which is 1 or 0 by its sign for logistic regression
+func generateSamples(w Workload) map[string][]byte {
+	r := rand.New(rand.NewSource(w.Seed))
+	weights := make([]float64, len(parties)*w.Features)
+	for i := range weights {
+		weights[i] = r.Float64()*2 - 1
+	}
+
+	buffers := make([]bytes.Buffer, len(parties))
+	for i, p := range parties {
+		header := []string{"id"}
+		for j := 0; j < w.Features; j++ {
+			header = append(header, fmt.Sprintf("x%s_%d", strings.TrimPrefix(p, "party"), j))
+		}
+		if i == len(parties)-1 {
+			header = append(header, "y")
+		}
+		buffers[i].WriteString(strings.Join(header, ",") + "\n")
+	}
+	for n := 0; n < w.Samples; n++ {
+		y := r.NormFloat64() * 0.1
+		rows := make([][]string, len(parties))
+		for i := range parties {
+			rows[i] = []string{strconv.Itoa(n)}
+			for j := 0; j < w.Features; j++ {
+				x := r.NormFloat64()*10 + 50
+				y += weights[i*w.Features+j] * (x - 50) / 10
+				rows[i] = append(rows[i], strconv.FormatFloat(x, 'f', 4, 64))
+			}
+		}
+		label := strconv.FormatFloat(y, 'f', 4, 64)
+		if w.Algo == pbCom.Algorithm_LOGIC_REGRESSION_VL {
+			label = strconv.Itoa(int(math.Max(math.Copysign(1, y), 0)))
+		}
+		last := len(parties) - 1
+		rows[last] = append(rows[last], label)
+		for i := range parties {
+			buffers[i].WriteString(strings.Join(rows[i], ",") + "\n")
+		}
+	}
+
+	files := make(map[string][]byte, len(parties))
+	for i, p := range parties {
+		files[p] = buffers[i].Bytes()
+	}
+	return files
+}
+
+// startTaskRequests packs the requests to start the task taskID of each party,
+// it's a prediction task with models if models is not nil, otherwise a training task
+func startTaskRequests(w Workload, files map[string][]byte, taskID string, models map[string]*pbCom.TrainModels) map[string]*pbCom.StartTaskRequest {
+	reqs := make(map[string]*pbCom.StartTaskRequest, len(parties))
+	for i, p := range parties {
+		var hosts []string
+		for _, other := range parties {
+			if other != p {
+				hosts = append(hosts, other)
+			}
+		}
+		trainParams := &pbCom.TrainParams{
+			Label:        "y",
+			Alpha:        0.1,
+			Amplitude:    w.Amplitude,
+			Accuracy:     10,
+			IsTagPart:    i == len(parties)-1,
+			IdName:       "id",
+			BatchSize:    w.BatchSize,
+			Seed:         w.Seed,
+			PsiAlgorithm: "ecdh",
+		}
+		if w.Algo == pbCom.Algorithm_LOGIC_REGRESSION_VL {
+			trainParams.LabelName = "1"
+		}
+		if w.Algo == pbCom.Algorithm_XGBOOST_VL {
+			trainParams.XgbParams = &pbCom.XGBoostParams{MaxDepth: 3, LearningRate: 0.3, NEstimators: 10, Lambda: 1}
+		}
+		params := &pbCom.TaskParams{
+			Algo:        w.Algo,
+			TaskType:    pbCom.TaskType_LEARN,
+			TrainParams: trainParams,
+			ModelParams: &pbCom.TrainModels{},
+		}
+		if models != nil {
+			params.TaskType = pbCom.TaskType_PREDICT
+			params.ModelParams = models[p]
+			params.ModelParams.IdName = "id"
+			params.ModelParams.PsiAlgorithm = trainParams.PsiAlgorithm
+		}
+		reqs[p] = &pbCom.StartTaskRequest{
+			TaskID: taskID,
+			File:   files[p],
+			Hosts:  hosts,
+			Params: params,
+		}
+	}
+	return reqs
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&algorithm, "algorithm", "a", "linear-vl", "algorithm of tasks, 'linear-vl', 'logistic-vl' and 'xgboost-vl' are supported")
+	rootCmd.Flags().IntVarP(&samples, "samples", "s", 100, "number of samples generated for each party")
+	rootCmd.Flags().IntVar(&features, "features", 5, "number of features generated for each party")
+	rootCmd.Flags().IntVarP(&iterations, "iterations", "n", 3, "number of runs of training and prediction")
+	rootCmd.Flags().Float64Var(&amplitude, "amplitude", 0.1, "target difference of costs in two contiguous rounds that determines whether to stop training")
+	rootCmd.Flags().Int64VarP(&batchSize, "batchSize", "b", 0, "size of samples for one round of training loop, 0 for BGD(Batch Gradient Descent)")
+	rootCmd.Flags().Int64Var(&seed, "seed", 1, "seed of the generated samples and mini-batch shuffling, the same seed generates the same workload")
+	rootCmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "maximum execution time of each task")
+	rootCmd.Flags().StringVar(&cpuProfile, "cpuprofile", "", "file to write the cpu profile of the benchmark, analyzed by 'go tool pprof'")
+	rootCmd.Flags().StringVar(&memProfile, "memprofile", "", "file to write the allocation profile after the benchmark, analyzed by 'go tool pprof'")
+}
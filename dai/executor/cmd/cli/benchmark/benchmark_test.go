@@ -0,0 +1,53 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestRun(t *testing.T) {
+	for _, algo := range []pbCom.Algorithm{pbCom.Algorithm_LINEAR_REGRESSION_VL, pbCom.Algorithm_LOGIC_REGRESSION_VL} {
+		t.Run(algo.String(), func(t *testing.T) {
+			w := Workload{Algo: algo, Samples: 50, Features: 2, Seed: 1, Amplitude: 0.1, BatchSize: 10}
+			train, predict, err := Run(w, 2, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, r := range map[string]Result{"train": train, "predict": predict} {
+				if r.Runs != 2 || r.Samples != 50 || r.Throughput() <= 0 || r.Bytes == 0 || r.Mallocs == 0 {
+					t.Errorf("unexpected result of %s: %+v", name, r)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSamples(t *testing.T) {
+	w := Workload{Algo: pbCom.Algorithm_LOGIC_REGRESSION_VL, Samples: 3, Features: 2, Seed: 1}
+	files := generateSamples(w)
+	if !bytes.HasPrefix(files["party1"], []byte("id,x1_0,x1_1\n0,")) || !bytes.HasPrefix(files["party2"], []byte("id,x2_0,x2_1,y\n0,")) {
+		t.Errorf("unexpected headers:\n%s\n%s", files["party1"], files["party2"])
+	}
+	if n := bytes.Count(files["party2"], []byte("\n")); n != 4 {
+		t.Errorf("expected a header and 3 samples, got %d lines", n)
+	}
+	if again := generateSamples(w); !bytes.Equal(again["party2"], files["party2"]) {
+		t.Error("the same seed should generate the same samples")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkconf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	httpclient "github.com/PaddlePaddle/PaddleDTX/xdb/client/http"
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/fabric"
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/xchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// dialTimeout is the timeout of requesting XuperDB hosts and connecting to S3 endpoint
+const dialTimeout = 3 * time.Second
+
+var configPath string
+
+// chainReader is the read-only part of the blockchain client used to test the connection
+type chainReader interface {
+	ListExecutorNodes() (blockchain.ExecutorNodes, error)
+	Close()
+}
+
+// newChainReader creates the blockchain client, replaced by tests
+var newChainReader = func(conf *config.ExecutorBlockchainConf) (chainReader, error) {
+	switch conf.Type {
+	case "xchain":
+		return xchain.New(conf.Xchain)
+	case "fabric":
+		return fabric.New(conf.Fabric)
+	default:
+		return nil, fmt.Errorf("invalid blockchain type: %s", conf.Type)
+	}
+}
+
+// checkResult is a row of the summary table
+type checkResult struct {
+	name string
+	err  error
+}
+
+// rootCmd checks the executor's configuration file without starting the node
+var rootCmd = &cobra.Command{
+	Use:   "checkconf",
+	Short: "check the executor's configuration file and the connections to blockchain and XuperDB",
+	Run: func(cmd *cobra.Command, args []string) {
+		results := checkConf(configPath)
+		if !printResults(os.Stdout, results) {
+			os.Exit(1)
+		}
+	},
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// checkConf runs all checks, the connection tests are skipped if the config file is invalid
+func checkConf(path string) []checkResult {
+	// the config file is not watched even if hotReload is enabled
+	if err := config.LoadConfig(path); err != nil {
+		return []checkResult{{name: "config file", err: err}}
+	}
+	conf := config.GetExecutorConf()
+	results := []checkResult{
+		{name: "config file"},
+		{name: "private key", err: checkPrivateKey(conf.PrivateKey)},
+		{name: "blockchain " + conf.Blockchain.Type, err: checkBlockchain(conf.Blockchain)},
+	}
+	if conf.Mode.Type == "Self" {
+		results = append(results, checkResult{name: "mode.self host", err: checkXuperDB(conf.Mode.Self.Host)})
+	}
+	if conf.Storage.Type == "XuperDB" {
+		results = append(results, checkResult{name: "storage.xuperdb host", err: checkXuperDB(conf.Storage.XuperDB.Host)})
+	}
+	if conf.Storage.Type == "S3" && conf.Storage.S3.Endpoint != "" {
+		results = append(results, checkResult{name: "storage.s3 endpoint", err: checkHost(conf.Storage.S3.Endpoint)})
+	}
+	return results
+}
+
+// checkPrivateKey checks whether the executor's private key can be decoded
+func checkPrivateKey(privateKey string) error {
+	_, err := ecdsa.DecodePrivateKeyFromString(privateKey)
+	return err
+}
+
+// checkBlockchain connects to blockchain and lists executor nodes, nothing is written to the chain
+func checkBlockchain(conf *config.ExecutorBlockchainConf) error {
+	chain, err := newChainReader(conf)
+	if err != nil {
+		return err
+	}
+	defer chain.Close()
+
+	_, err = chain.ListExecutorNodes()
+	return err
+}
+
+// checkXuperDB lists storage nodes from the XuperDB host, which is read-only
+func checkXuperDB(host string) error {
+	client, err := httpclient.New(host)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	_, err = client.ListNodes(ctx)
+	return err
+}
+
+// checkHost checks whether the S3 endpoint is reachable
+func checkHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil {
+		return err
+	}
+	address := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			address = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			address = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// printResults prints the summary table to out, and returns false if any check failed
+func printResults(out io.Writer, results []checkResult) bool {
+	passed := true
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tRESULT\tDETAIL")
+	for _, r := range results {
+		if r.err != nil {
+			passed = false
+			fmt.Fprintf(w, "%s\t%s\t%v\n", r.name, "FAIL", r.err)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t\n", r.name, "PASS")
+		}
+	}
+	w.Flush()
+	return passed
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&configPath, "conf", "c", "./conf/config.toml", "path of the executor's configuration file, a directory or a list of files separated by ',' are merged in order")
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkconf
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+type fakeChain struct {
+	err error
+}
+
+func (c *fakeChain) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
+	return nil, c.err
+}
+
+func (c *fakeChain) Close() {}
+
+func TestCheckConf(t *testing.T) {
+	// xuperdb stub answers the read-only request listing storage nodes
+	xuperdb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/node/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"code":"0","message":"","data":[]}`))
+	}))
+	defer xuperdb.Close()
+	// nothing listens on port 1, so connections are refused
+	unreachable := "http://127.0.0.1:1"
+
+	template, err := ioutil.ReadFile("../../../../conf/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("PADDLEDTX_EXECUTOR_PRIVATEKEY", "858843291fe4ed4bd2afc1120efd7315f3cae2d3f79e582f7df843ac6eb0543b")
+	defer os.Unsetenv("PADDLEDTX_EXECUTOR_PRIVATEKEY")
+	writeConf := func(xuperdbHost string) string {
+		content := bytes.Replace(template, []byte("${BLOCKCHAIN_TYPE}"), []byte("xchain"), 1)
+		content = bytes.Replace(content, []byte("hotReload = false"), []byte("hotReload = true"), 1)
+		content = bytes.Replace(content, []byte("type = 'Local'"), []byte("type = 'XuperDB'"), 1)
+		content = bytes.Replace(content, []byte(`host = "http://10.144.94.17:8121"`), []byte(`host = "`+xuperdbHost+`"`), -1)
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	defer func(f func(*config.ExecutorBlockchainConf) (chainReader, error)) { newChainReader = f }(newChainReader)
+
+	cases := map[string]struct {
+		path     string
+		chainErr error
+		passed   bool
+		failed   string // name of the failed check
+	}{
+		"passed":             {path: writeConf(xuperdb.URL), passed: true},
+		"unreachableXuperDB": {path: writeConf(unreachable), failed: "storage.xuperdb host"},
+		"blockchainFailed":   {path: writeConf(xuperdb.URL), chainErr: errors.New("connection refused"), failed: "blockchain xchain"},
+		"missingConfigFile":  {path: filepath.Join(t.TempDir(), "not-exist.toml"), failed: "config file"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			newChainReader = func(conf *config.ExecutorBlockchainConf) (chainReader, error) {
+				return &fakeChain{err: c.chainErr}, nil
+			}
+			results := checkConf(c.path)
+			var out bytes.Buffer
+			if passed := printResults(&out, results); passed != c.passed {
+				t.Fatalf("expected passed: %v, got: %v\n%s", c.passed, passed, out.String())
+			}
+			if c.failed == "" {
+				return
+			}
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, c.failed+" ") && strings.Contains(line, "FAIL") {
+					return
+				}
+			}
+			t.Errorf("check %s should fail, got:\n%s", c.failed, out.String())
+		})
+	}
+}
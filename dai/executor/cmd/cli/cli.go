@@ -18,7 +18,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/benchmark"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/checkconf"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/key"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/migrateconf"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/node"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/rotatekey"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/simulate"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/cmd/cli/task"
 )
 
@@ -38,4 +44,10 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(task.RootCmd())
 	rootCmd.AddCommand(key.RootCmd())
+	rootCmd.AddCommand(checkconf.RootCmd())
+	rootCmd.AddCommand(migrateconf.RootCmd())
+	rootCmd.AddCommand(node.RootCmd())
+	rootCmd.AddCommand(simulate.RootCmd())
+	rootCmd.AddCommand(benchmark.RootCmd())
+	rootCmd.AddCommand(rotatekey.RootCmd())
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrateconf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+var (
+	inPath  string
+	outPath string
+)
+
+// rootCmd upgrades an old configuration file of the executor to the current version
+var rootCmd = &cobra.Command{
+	Use:   "migrateconf",
+	Short: "upgrade the executor's configuration file, filling new fields with defaults",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := migrate(inPath, outPath); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to migrate the configuration file: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// migrate reads the config file in, and writes the upgraded one to out, or stdout if out is empty.
+// The warnings about the changed fields are printed to stderr.
+func migrate(in, out string) error {
+	info, err := os.Stat(in)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	migrated, warnings, err := config.MigrateConfig(content)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "WARN: %s\n", w)
+	}
+	if len(warnings) == 0 {
+		fmt.Fprintln(os.Stderr, "the configuration file is up to date")
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(migrated)
+		return err
+	}
+	// keep the permission of the old file, as it may contain private keys
+	return ioutil.WriteFile(out, migrated, info.Mode().Perm())
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&inPath, "in", "i", "./conf/config.toml", "path of the configuration file to migrate")
+	rootCmd.Flags().StringVarP(&outPath, "out", "o", "", "path of the migrated configuration file, printed to stdout if not set")
+}
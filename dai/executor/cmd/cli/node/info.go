@@ -0,0 +1,49 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	executorClient "github.com/PaddlePaddle/PaddleDTX/dai/executor/client"
+)
+
+// infoCmd gets the identity of the executor node, with which other parties register the executor
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "get the name, public address, public key, supported algorithms and build information of the executor node",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+		info, err := client.GetNodeInfo(context.Background())
+		if err != nil {
+			fmt.Printf("GetNodeInfo failed：%v\n", err)
+			return
+		}
+		fmt.Printf("Name: %s\nPublicAddress: %s\nPublicKey: %s\nAlgorithms: %s\nVersion: %s\nGitCommit: %s\nBuildDate: %s\n\n",
+			info.Name, info.PublicAddress, info.PublicKey, strings.Join(info.Algorithms, ","),
+			info.Version, info.GitCommit, info.BuildDate)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
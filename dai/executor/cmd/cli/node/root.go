@@ -0,0 +1,54 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
+)
+
+var (
+	host string
+
+	// tls files to connect to the executor whose gRPC server requires TLS
+	tlsCA   string
+	tlsCert string
+	tlsKey  string
+)
+
+// rootCmd represents root command
+var rootCmd = &cobra.Command{
+	Use:   "node",
+	Short: "A command helps to query the executor node",
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// tlsConf returns the tls config of the tls flags, nil to connect to the executor in plaintext
+func tlsConf() *config.TLSConf {
+	return tlsutil.ClientConf(tlsCA, tlsCert, tlsKey)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&host, "host", "", "server grpc address of the executor node, example '127.0.0.1:8184'")
+	rootCmd.PersistentFlags().StringVar(&tlsCA, "tlsCA", "", "CA certificate file to verify the executor whose gRPC server requires TLS, the system roots if empty while tlsCert is set")
+	rootCmd.PersistentFlags().StringVar(&tlsCert, "tlsCert", "", "certificate file presented to the executor requiring mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsKey, "tlsKey", "", "private key file of tlsCert")
+
+	rootCmd.MarkPersistentFlagRequired("host")
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	executorClient "github.com/PaddlePaddle/PaddleDTX/dai/executor/client"
+)
+
+// statusCmd gets tasks in execution and resources usage of the executor node,
+// zero limits or budgets mean that the resource is not limited
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "get tasks in execution and resources usage of the executor node",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+		s, err := client.GetNodeStatus(context.Background())
+		if err != nil {
+			fmt.Printf("GetNodeStatus failed：%v\n", err)
+			return
+		}
+		fmt.Printf("TrainTasks: %d/%d\nPredictTasks: %d/%d\nQueuedTasks: %d/%d\n",
+			s.TrainTasks, s.TrainTaskLimit, s.PredictTasks, s.PredictTaskLimit, s.QueuedTasks, s.QueueSize)
+		fmt.Printf("MaxTaskMemoryMB: %d\nMaxTaskCPUCores: %d\nMemoryReservedMB: %d/%d\nCPUReservedCores: %d/%d\nMemoryUsedMB: %d\n\n",
+			s.MaxTaskMemoryMB, s.MaxTaskCPUCores, s.MemoryReservedMB, s.MemoryBudgetMB,
+			s.CpuReservedCores, s.CpuBudgetCores, s.MemoryUsedMB)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotatekey
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/fabric"
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/xchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// rotatingKeyDirName is the directory under the key path staging the new key pair until the rotation completes
+const rotatingKeyDirName = "rotating"
+
+var (
+	configPath string
+	grace      time.Duration
+)
+
+// keyRotator is the part of the blockchain client used to rotate the key of the executor node
+type keyRotator interface {
+	GetExecutorNodeByID(id string) (blockchain.ExecutorNode, error)
+	RotateExecutorKey(opt *blockchain.RotateKeyOptions) error
+	Close()
+}
+
+// newKeyRotator creates the blockchain client, replaced by tests
+var newKeyRotator = func(conf *config.ExecutorBlockchainConf) (keyRotator, error) {
+	switch conf.Type {
+	case "xchain":
+		return xchain.New(conf.Xchain)
+	case "fabric":
+		return fabric.New(conf.Fabric)
+	default:
+		return nil, fmt.Errorf("invalid blockchain type: %s", conf.Type)
+	}
+}
+
+// rootCmd rotates the private key of the executor node under keyPath
+var rootCmd = &cobra.Command{
+	Use:   "rotatekey",
+	Short: "rotate the executor node's key pair, the old key keeps executing the tasks published to it until they end",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.LoadConfig(configPath); err != nil {
+			fmt.Printf("failed to load config, err: %v\n", err)
+			os.Exit(1)
+		}
+		pubkey, err := rotateKey(config.GetExecutorConf(), grace)
+		if err != nil {
+			fmt.Printf("failed to rotate key, err: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("public-key:", pubkey)
+		fmt.Println("key rotated, restart the executor to use the new key")
+	},
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// rotateKey rotates the key of the executor node to a new key on all the blockchain networks it joins,
+// and swaps the key files under keyPath, the old key pair is moved to the previous directory.
+// An interrupted rotation is resumed by calling rotateKey again, see rotationKeys.
+func rotateKey(conf *config.ExecutorConf, grace time.Duration) (ecdsa.PublicKey, error) {
+	var pubkey ecdsa.PublicKey
+	if conf.KeyProvider != nil && conf.KeyProvider.Type == config.KeyProviderVault {
+		return pubkey, errorx.New(errorx.ErrCodeConfig, "keys in vault are not rotated by rotatekey")
+	}
+	if grace <= 0 {
+		return pubkey, errorx.New(errorx.ErrCodeParam, "grace period must be positive")
+	}
+	oldKey, newKey, err := rotationKeys(conf)
+	if err != nil {
+		return pubkey, err
+	}
+
+	networks := append([]*config.ExecutorBlockchainConf{conf.Blockchain}, conf.Blockchain.Networks...)
+	for _, network := range networks {
+		if err := rotateOnChain(network, oldKey, newKey, grace); err != nil {
+			return pubkey, errorx.Wrap(err, "failed to rotate key on blockchain network %s", network.NetworkName())
+		}
+	}
+	if err := swapKeyFiles(conf.KeyPath); err != nil {
+		return pubkey, errorx.Wrap(err, "failed to swap key files under %s", conf.KeyPath)
+	}
+	return ecdsa.PublicKeyFromPrivateKey(newKey), nil
+}
+
+// rotationKeys returns the key to rotate from and the key to rotate to. The new key pair is generated into
+// the rotating directory under keyPath, which is reused if it exists, so that an interrupted rotation is resumed
+// with the same key. The old key is the one under keyPath, or under the previous directory if the key files
+// were being swapped.
+func rotationKeys(conf *config.ExecutorConf) (oldKey, newKey ecdsa.PrivateKey, err error) {
+	keyPath := conf.KeyPath
+	current, err := readPrivateKey(keyPath)
+	if err != nil {
+		return oldKey, newKey, err
+	}
+	if current != conf.PrivateKey {
+		return oldKey, newKey, errorx.New(errorx.ErrCodeConfig,
+			"the private key set by the config file or environment variables is not rotated by rotatekey, "+
+				"only the key files under keyPath are")
+	}
+	rotatingPath := filepath.Join(keyPath, rotatingKeyDirName)
+	previousPath := filepath.Join(keyPath, file.PreviousKeyDirName)
+	staged, err := file.IsFileExisted(rotatingPath, file.PrivateKeyFileName)
+	if err != nil {
+		return oldKey, newKey, err
+	}
+	previous, err := file.IsFileExisted(previousPath, file.PrivateKeyFileName)
+	if err != nil {
+		return oldKey, newKey, err
+	}
+
+	switch {
+	case previous && !staged:
+		return oldKey, newKey, errorx.New(errorx.ErrCodeConfig, "the key pair before the last rotation is kept under %s, "+
+			"remove it after the tasks published to it end and its grace period is over", previousPath)
+	case previous && staged:
+		// the old key pair is copied to the previous directory already
+		if current, err = readPrivateKey(previousPath); err != nil {
+			return oldKey, newKey, err
+		}
+	case !staged:
+		// the staged key pair is written partly if private.key is missing
+		if err := os.RemoveAll(rotatingPath); err != nil {
+			return oldKey, newKey, err
+		}
+		sk, pk, err := ecdsa.GenerateKeyPair()
+		if err != nil {
+			return oldKey, newKey, errorx.Wrap(err, "failed to generate key pair")
+		}
+		if err := file.WriteFile(rotatingPath, file.PublicKeyFileName, []byte(pk.String())); err != nil {
+			return oldKey, newKey, err
+		}
+		if err := file.WriteFile(rotatingPath, file.PrivateKeyFileName, []byte(sk.String())); err != nil {
+			return oldKey, newKey, err
+		}
+	}
+	next, err := readPrivateKey(rotatingPath)
+	if err != nil {
+		return oldKey, newKey, err
+	}
+	if oldKey, err = ecdsa.DecodePrivateKeyFromString(current); err != nil {
+		return oldKey, newKey, errorx.Wrap(err, "failed to decode the old private key")
+	}
+	if newKey, err = ecdsa.DecodePrivateKeyFromString(next); err != nil {
+		return oldKey, newKey, errorx.Wrap(err, "failed to decode the new private key under %s", rotatingPath)
+	}
+	return oldKey, newKey, nil
+}
+
+// rotateOnChain registers the new key on the blockchain network conf, signed by both keys,
+// it's skipped if the old key is rotated to the new key already
+func rotateOnChain(conf *config.ExecutorBlockchainConf, oldKey, newKey ecdsa.PrivateKey, grace time.Duration) error {
+	chain, err := newKeyRotator(conf)
+	if err != nil {
+		return err
+	}
+	defer chain.Close()
+
+	oldPk := ecdsa.PublicKeyFromPrivateKey(oldKey)
+	newPk := ecdsa.PublicKeyFromPrivateKey(newKey)
+	node, err := chain.GetExecutorNodeByID(oldPk.String())
+	if err != nil {
+		return errorx.Wrap(err, "failed to get the executor node of the old key")
+	}
+	if bytes.Equal(node.RotatedTo, newPk[:]) {
+		return nil
+	}
+	now := time.Now()
+	opt := &blockchain.RotateKeyOptions{
+		OldID:       oldPk[:],
+		NewID:       newPk[:],
+		CurrentTime: now.UnixNano(),
+		GraceUntil:  now.Add(grace).UnixNano(),
+	}
+	if err := blockchain.SignKeyRotation(opt, oldKey, newKey); err != nil {
+		return err
+	}
+	return chain.RotateExecutorKey(opt)
+}
+
+// swapKeyFiles copies the old key pair under keyPath to the previous directory, then moves the staged
+// key pair into keyPath. private.key under keyPath is a valid key at any moment, the old one or the new one,
+// and private.key is copied or moved after public.key, so it marks the step is done.
+func swapKeyFiles(keyPath string) error {
+	rotatingPath := filepath.Join(keyPath, rotatingKeyDirName)
+	previousPath := filepath.Join(keyPath, file.PreviousKeyDirName)
+	previous, err := file.IsFileExisted(previousPath, file.PrivateKeyFileName)
+	if err != nil {
+		return err
+	}
+	if !previous {
+		if err := os.MkdirAll(previousPath, 0700); err != nil {
+			return err
+		}
+		for _, name := range []string{file.PublicKeyFileName, file.PrivateKeyFileName} {
+			if err := copyFile(filepath.Join(keyPath, name), filepath.Join(previousPath, name)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range []string{file.PublicKeyFileName, file.PrivateKeyFileName} {
+		src := filepath.Join(rotatingPath, name)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			// moved by the interrupted rotation
+			continue
+		}
+		if err := os.Rename(src, filepath.Join(keyPath, name)); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(rotatingPath)
+}
+
+// copyFile copies src to dst through a temporary file, so that dst is never written partly
+func copyFile(src, dst string) error {
+	content, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// readPrivateKey reads the private key file under path
+func readPrivateKey(path string) (string, error) {
+	content, err := file.ReadFile(path, file.PrivateKeyFileName)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&configPath, "conf", "c", "./conf/config.toml", "path of the executor's configuration file, a directory or a list of files separated by ',' are merged in order")
+	rootCmd.Flags().DurationVarP(&grace, "grace", "g", 24*time.Hour, "grace period when both keys are valid, the old key confirms no tasks after it")
+}
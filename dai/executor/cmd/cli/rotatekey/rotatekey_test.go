@@ -0,0 +1,167 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotatekey
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// fakeChain keeps the executor nodes of a blockchain network in memory
+type fakeChain struct {
+	nodes map[string]blockchain.ExecutorNode
+	err   error // error of rotating keys
+	calls int
+}
+
+func (c *fakeChain) GetExecutorNodeByID(id string) (blockchain.ExecutorNode, error) {
+	node, ok := c.nodes[id]
+	if !ok {
+		return node, errors.New("node not found")
+	}
+	return node, nil
+}
+
+func (c *fakeChain) RotateExecutorKey(opt *blockchain.RotateKeyOptions) error {
+	c.calls++
+	if c.err != nil {
+		return c.err
+	}
+	oldPk := ecdsa.PublicKey{}
+	copy(oldPk[:], opt.OldID)
+	rotated, next, err := blockchain.RotateNode(c.nodes[oldPk.String()], *opt)
+	if err != nil {
+		return err
+	}
+	newPk := ecdsa.PublicKey{}
+	copy(newPk[:], opt.NewID)
+	c.nodes[oldPk.String()], c.nodes[newPk.String()] = rotated, next
+	return nil
+}
+
+func (c *fakeChain) Close() {}
+
+func TestRotateKey(t *testing.T) {
+	keyPath := t.TempDir()
+	sk, pk, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkErr(t, file.WriteFile(keyPath, file.PublicKeyFileName, []byte(pk.String())))
+	checkErr(t, file.WriteFile(keyPath, file.PrivateKeyFileName, []byte(sk.String())))
+	node := blockchain.ExecutorNode{ID: pk[:], Name: "executor1"}
+	chains := map[string]*fakeChain{
+		"default":  {nodes: map[string]blockchain.ExecutorNode{pk.String(): node}},
+		"partners": {nodes: map[string]blockchain.ExecutorNode{pk.String(): node}, err: errors.New("connection refused")},
+	}
+	defer func(f func(*config.ExecutorBlockchainConf) (keyRotator, error)) { newKeyRotator = f }(newKeyRotator)
+	newKeyRotator = func(conf *config.ExecutorBlockchainConf) (keyRotator, error) {
+		return chains[conf.NetworkName()], nil
+	}
+	conf := &config.ExecutorConf{
+		KeyPath:    keyPath,
+		PrivateKey: sk.String(),
+		Blockchain: &config.ExecutorBlockchainConf{
+			Type:     "xchain",
+			Networks: []*config.ExecutorBlockchainConf{{Name: "partners", Type: "xchain"}},
+		},
+	}
+
+	// the private key set in the config file isn't rotated
+	other, _, _ := ecdsa.GenerateKeyPair()
+	if _, err := rotateKey(&config.ExecutorConf{KeyPath: keyPath, PrivateKey: other.String()}, time.Hour); err == nil {
+		t.Error("expected error if the private key isn't read from keyPath")
+	}
+
+	// the rotation fails midway, the old key is kept and the new key is staged
+	if _, err := rotateKey(conf, time.Hour); err == nil {
+		t.Fatal("expected error if the key fails to rotate on a network")
+	}
+	if key, _ := readPrivateKey(keyPath); key != sk.String() {
+		t.Error("expected the old key kept under keyPath")
+	}
+	staged, err := readPrivateKey(filepath.Join(keyPath, rotatingKeyDirName))
+	checkErr(t, err)
+
+	// the rotation is resumed with the staged key, the network rotated already is skipped
+	chains["partners"].err = nil
+	newPk, err := rotateKey(conf, time.Hour)
+	checkErr(t, err)
+	if chains["default"].calls != 1 || chains["partners"].calls != 2 {
+		t.Errorf("expected the default network rotated once, got calls %d and %d", chains["default"].calls, chains["partners"].calls)
+	}
+	for name, c := range chains {
+		if n := c.nodes[pk.String()]; string(n.RotatedTo) != string(newPk[:]) {
+			t.Errorf("expected the old key rotated to the new key on %s, got %x", name, n.RotatedTo)
+		}
+	}
+	if key, _ := readPrivateKey(keyPath); key != staged {
+		t.Error("expected the staged key moved into keyPath")
+	}
+	if key, _ := readPrivateKey(filepath.Join(keyPath, file.PreviousKeyDirName)); key != sk.String() {
+		t.Error("expected the old key moved into the previous directory")
+	}
+	if _, err := os.Stat(filepath.Join(keyPath, rotatingKeyDirName)); !os.IsNotExist(err) {
+		t.Errorf("expected the rotating directory removed, got %v", err)
+	}
+
+	// the key isn't rotated again until the previous key pair is removed
+	conf.PrivateKey = staged
+	if _, err := rotateKey(conf, time.Hour); err == nil {
+		t.Error("expected error if the previous key pair is kept")
+	}
+}
+
+func TestSwapKeyFilesResume(t *testing.T) {
+	keyPath := t.TempDir()
+	rotatingPath := filepath.Join(keyPath, rotatingKeyDirName)
+	checkErr(t, file.WriteFile(keyPath, file.PublicKeyFileName, []byte("old-public")))
+	checkErr(t, file.WriteFile(keyPath, file.PrivateKeyFileName, []byte("old-private")))
+	checkErr(t, file.WriteFile(rotatingPath, file.PrivateKeyFileName, []byte("new-private")))
+	// interrupted after the old key pair is copied and public.key is moved
+	checkErr(t, file.WriteFile(filepath.Join(keyPath, file.PreviousKeyDirName), file.PublicKeyFileName, []byte("old-public")))
+	checkErr(t, file.WriteFile(filepath.Join(keyPath, file.PreviousKeyDirName), file.PrivateKeyFileName, []byte("old-private")))
+	checkErr(t, os.Remove(filepath.Join(keyPath, file.PublicKeyFileName)))
+	checkErr(t, file.WriteFile(keyPath, file.PublicKeyFileName, []byte("new-public")))
+
+	checkErr(t, swapKeyFiles(keyPath))
+	expected := map[string]string{
+		filepath.Join(keyPath, file.PublicKeyFileName):                           "new-public",
+		filepath.Join(keyPath, file.PrivateKeyFileName):                          "new-private",
+		filepath.Join(keyPath, file.PreviousKeyDirName, file.PublicKeyFileName):  "old-public",
+		filepath.Join(keyPath, file.PreviousKeyDirName, file.PrivateKeyFileName): "old-private",
+	}
+	for path, content := range expected {
+		if got, err := ioutil.ReadFile(path); err != nil || string(got) != content {
+			t.Errorf("expected %s in %s, got %s, %v", content, path, got, err)
+		}
+	}
+}
+
+func checkErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
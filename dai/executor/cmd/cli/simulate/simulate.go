@@ -0,0 +1,283 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+var (
+	taskType   string
+	algorithm  string
+	files      string
+	models     string
+	label      string
+	labelName  string
+	psiLabel   string
+	psiAlgo    string
+	regMode    string
+	regParam   float64
+	alpha      float64
+	amplitude  float64
+	accuracy   uint64
+	batchSize  uint64
+	seed       int64
+	timeout    time.Duration
+	rpcTimeout time.Duration
+	output     string
+
+	// hyperparameters of xgboost-vl
+	maxDepth     int64
+	learningRate float64
+	nEstimators  int64
+	lambda       float64
+)
+
+// rootCmd runs a task by simulated parties in one process
+var rootCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "run a training or prediction task locally, all parties are simulated in one process without network and blockchain",
+	Run: func(cmd *cobra.Command, args []string) {
+		reqs, parties, err := startTaskRequests()
+		if err != nil {
+			fmt.Printf("invalid task parameters: %v\n", err)
+			return
+		}
+
+		sim := mpc.NewSimulation(mpc.Config{
+			TrainTaskLimit:   1,
+			PredictTaskLimit: 1,
+			RpcTimeout:       rpcTimeout,
+		})
+		defer sim.Stop()
+		for _, p := range parties {
+			if err := sim.AddParty(p); err != nil {
+				fmt.Printf("failed to add party %s: %v\n", p, err)
+				return
+			}
+		}
+
+		start := time.Now()
+		results, err := sim.Run(reqs, timeout)
+		if err != nil {
+			fmt.Printf("simulation failed: %v\n", err)
+			return
+		}
+		fmt.Printf("Task finished in %s\n", time.Since(start))
+
+		// files are named by the task and the party, so that results of different runs are kept
+		for _, p := range parties {
+			r := results[p]
+			prefix := reqs[p].TaskID + "_" + p
+			if r.Train != nil {
+				if err := file.WriteFile(output, prefix+".model", r.Train.Model); err != nil {
+					fmt.Printf("failed to save model of %s: %v\n", p, err)
+					return
+				}
+				fmt.Printf("%s: model saved in %s\n", p, filepath.Join(output, prefix+".model"))
+			} else if len(r.Predict.Outcomes) > 0 {
+				if err := savePredictOutcomes(r.Predict.Outcomes, filepath.Join(output, prefix+".csv")); err != nil {
+					fmt.Printf("failed to save prediction outcomes of %s: %v\n", p, err)
+					return
+				}
+				fmt.Printf("%s: prediction outcomes saved in %s\n", p, filepath.Join(output, prefix+".csv"))
+			}
+		}
+	},
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// startTaskRequests packs the requests to start the task of each party from the flags,
+// parties are named 'party1', 'party2'... in the order of sample files
+func startTaskRequests() (map[string]*pbCom.StartTaskRequest, []string, error) {
+	algo, ok := blockchain.VlAlgorithmListName[algorithm]
+	if !ok || algo == pbCom.Algorithm_DNN_PADDLEFL_VL {
+		return nil, nil, errorx.New(errorx.ErrCodeParam, "algorithm only support linear-vl, logistic-vl or xgboost-vl")
+	}
+	tType, ok := blockchain.TaskTypeListName[taskType]
+	if !ok {
+		return nil, nil, errorx.New(errorx.ErrCodeParam, "invalid task type: %s", taskType)
+	}
+	sampleFiles := strings.Split(files, ",")
+	psiLabels := strings.Split(psiLabel, ",")
+	if len(sampleFiles) < 2 || len(psiLabels) != len(sampleFiles) {
+		return nil, nil, errorx.New(errorx.ErrCodeParam, "at least two sample files are required, and each of them needs a PSI label")
+	}
+	var modelFiles []string
+	if tType == pbCom.TaskType_PREDICT {
+		modelFiles = strings.Split(models, ",")
+		if len(modelFiles) != len(sampleFiles) {
+			return nil, nil, errorx.New(errorx.ErrCodeParam, "prediction task requires a model file for each sample file")
+		}
+	}
+	if psiAlgo != "" && !blockchain.PSIAlgorithmSupported[psiAlgo] {
+		return nil, nil, errorx.New(errorx.ErrCodeParam, "invalid PSI algorithm: %s", psiAlgo)
+	}
+
+	var parties []string
+	for i := range sampleFiles {
+		parties = append(parties, fmt.Sprintf("party%d", i+1))
+	}
+	reqs := make(map[string]*pbCom.StartTaskRequest)
+	taskID := fmt.Sprintf("simulation-%d", time.Now().UnixNano())
+	for i, p := range parties {
+		samples, err := ioutil.ReadFile(sampleFiles[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		header := strings.Split(strings.TrimSpace(strings.SplitN(string(samples), "\n", 2)[0]), ",")
+
+		var hosts []string
+		for _, other := range parties {
+			if other != p {
+				hosts = append(hosts, other)
+			}
+		}
+		trainParams := &pbCom.TrainParams{
+			Label:        label,
+			LabelName:    labelName,
+			RegMode:      blockchain.RegModeListName[regMode],
+			RegParam:     regParam,
+			Alpha:        alpha,
+			Amplitude:    amplitude,
+			Accuracy:     int64(accuracy),
+			IsTagPart:    containsFeature(header, label),
+			IdName:       psiLabels[i],
+			BatchSize:    int64(batchSize),
+			Seed:         seed,
+			PsiAlgorithm: psiAlgo,
+		}
+		if trainParams.PsiAlgorithm == "" {
+			trainParams.PsiAlgorithm = "ecdh"
+		}
+		if algo == pbCom.Algorithm_XGBOOST_VL {
+			trainParams.XgbParams = &pbCom.XGBoostParams{
+				MaxDepth:     maxDepth,
+				LearningRate: learningRate,
+				NEstimators:  nEstimators,
+				Lambda:       lambda,
+			}
+		}
+		modelParams := &pbCom.TrainModels{}
+		if tType == pbCom.TaskType_PREDICT {
+			modelFile, err := ioutil.ReadFile(modelFiles[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			if modelParams, err = vl_common.TrainModelsFromBytes(modelFile); err != nil {
+				return nil, nil, errorx.New(errorx.ErrCodeParam, "invalid model file %s: %v", modelFiles[i], err)
+			}
+			modelParams.IdName = psiLabels[i]
+			modelParams.PsiAlgorithm = trainParams.PsiAlgorithm
+		}
+		reqs[p] = &pbCom.StartTaskRequest{
+			TaskID: taskID,
+			File:   samples,
+			Hosts:  hosts,
+			Params: &pbCom.TaskParams{
+				Algo:        algo,
+				TaskType:    tType,
+				TrainParams: trainParams,
+				ModelParams: modelParams,
+			},
+		}
+	}
+	// the parties select PSI algorithm auto by the larger number of their samples, as executors do
+	if psiAlgo == psi.AlgorithmAuto && len(parties) == 2 {
+		var sampleNums []int64
+		for _, req := range reqs {
+			sampleNums = append(sampleNums, psi.SampleNum(req.File))
+		}
+		resolved := psi.ResolveAlgorithm(psiAlgo, sampleNums...)
+		for _, req := range reqs {
+			req.Params.TrainParams.PsiAlgorithm = resolved
+			req.Params.ModelParams.PsiAlgorithm = resolved
+		}
+	}
+	return reqs, parties, nil
+}
+
+// savePredictOutcomes saves the prediction outcomes as csv file like the requester does
+func savePredictOutcomes(outcomes []byte, path string) error {
+	var rows [][]string
+	if err := json.Unmarshal(outcomes, &rows); err != nil {
+		return errorx.Wrap(err, "failed to unmarshal result to rows")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return csv.WriteRowsToFile(rows, path)
+}
+
+// containsFeature checks whether the features of sample file contain the label
+func containsFeature(features []string, label string) bool {
+	for _, f := range features {
+		if f == label {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&taskType, "type", "t", "", "task type, 'train' or 'predict'")
+	rootCmd.Flags().StringVarP(&algorithm, "algorithm", "a", "", "algorithm of task, 'linear-vl', 'logistic-vl' and 'xgboost-vl' are supported")
+	rootCmd.Flags().StringVarP(&files, "files", "f", "", "local sample files with ',' as delimiter, one for each simulated party, like './dataA.csv,./dataB.csv'")
+	rootCmd.Flags().StringVarP(&psiLabel, "psiLabel", "p", "", "ID feature name list with ',' as delimiter, one for each sample file, like 'id,id'")
+
+	// optional params
+	rootCmd.Flags().StringVarP(&models, "models", "m", "", "model files saved by the training simulation with ',' as delimiter, one for each sample file, required in predict task")
+	rootCmd.Flags().StringVarP(&label, "label", "l", "", "target feature for training task")
+	rootCmd.Flags().StringVar(&labelName, "labelName", "", "target variable required in logistic-vl training")
+	rootCmd.Flags().StringVar(&psiAlgo, "psiAlgorithm", "", "PSI algorithm to align samples, 'ecdh', 'oprf' or 'auto', 'ecdh' if not set")
+	rootCmd.Flags().StringVar(&regMode, "regMode", "", "regularization mode required in train task, no regularization if not set, options are l1(L1-norm) and l2(L2-norm)")
+	rootCmd.Flags().Float64Var(&regParam, "regParam", 0.1, "regularization parameter required in train task if set regMode")
+	rootCmd.Flags().Float64Var(&alpha, "alpha", 0.1, "learning rate required in train task")
+	rootCmd.Flags().Float64Var(&amplitude, "amplitude", 0.0001, "target difference of costs in two contiguous rounds that determines whether to stop training")
+	rootCmd.Flags().Uint64Var(&accuracy, "accuracy", 10, "accuracy of homomorphic encryption")
+	rootCmd.Flags().Uint64VarP(&batchSize, "batchSize", "b", 4, "size of samples for one round of training loop, 0 for BGD(Batch Gradient Descent)")
+	rootCmd.Flags().Int64Var(&seed, "seed", 0, "seed of mini-batch shuffling to make training reproducible, 0 means not seeded")
+	rootCmd.Flags().Int64Var(&maxDepth, "maxDepth", 3, "maximum depth of each tree in xgboost-vl train task")
+	rootCmd.Flags().Float64Var(&learningRate, "learningRate", 0.3, "shrinkage applied to leaf weights in xgboost-vl train task")
+	rootCmd.Flags().Int64Var(&nEstimators, "nEstimators", 10, "number of trees in xgboost-vl train task")
+	rootCmd.Flags().Float64Var(&lambda, "lambda", 1, "L2 regularization on leaf weights in xgboost-vl train task")
+	rootCmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "maximum execution time of the task")
+	rootCmd.Flags().DurationVar(&rpcTimeout, "rpcTimeout", 3*time.Second, "timeout of the messages between simulated parties, like 'executor.mpc.rpcTimeout' of executors")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "./simulation", "directory to save the models or prediction outcomes")
+
+	rootCmd.MarkFlagRequired("type")
+	rootCmd.MarkFlagRequired("algorithm")
+	rootCmd.MarkFlagRequired("files")
+	rootCmd.MarkFlagRequired("psiLabel")
+}
@@ -16,6 +16,7 @@ package task
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -30,12 +31,12 @@ var getByIDCmd = &cobra.Command{
 	Use:   "getbyid",
 	Short: "get the task by id",
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := executorClient.GetExecutorClient(host)
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
 		if err != nil {
 			fmt.Printf("GetExecutorClient failed: %v\n", err)
 			return
 		}
-		t, err := client.GetTaskById(context.Background(), id)
+		t, err := client.GetTaskById(context.Background(), id, network)
 		if err != nil {
 			fmt.Printf("GetTaskById failed：%v\n", err)
 			return
@@ -50,12 +51,29 @@ var getByIDCmd = &cobra.Command{
 			blockchain.VlAlgorithmListValue[t.AlgoParam.Algo], t.AlgoParam.TrainParams.Alpha, t.AlgoParam.TrainParams.Amplitude,
 			t.AlgoParam.TrainParams.Accuracy, t.AlgoParam.ModelTaskID, t.Status, ptime)
 
+		if len(t.AlgoParam.Labels) > 0 {
+			fmt.Printf("Labels: %s\n\n", blockchain.FormatLabels(t.AlgoParam.Labels))
+		}
+
 		if t.AlgoParam.EvalParams != nil && t.AlgoParam.EvalParams.Enable {
 			fmt.Printf("ModelEvaluationRule: %s\n",
 				t.AlgoParam.EvalParams.EvalRule)
-			if t.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
-				fmt.Printf("PercentageToLeaveOutAsValidation: %d\n\n",
-					t.AlgoParam.EvalParams.RandomSplit.PercentLO)
+			if len(t.AlgoParam.EvalParams.Metrics) > 0 {
+				fmt.Printf("EvaluationMetrics: %s\n", strings.Join(t.AlgoParam.EvalParams.Metrics, ","))
+			}
+			if rs := t.AlgoParam.EvalParams.RandomSplit; t.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
+				if rs.GetTestFraction() > 0 {
+					fmt.Printf("FractionToLeaveOutAsValidation: %v\n", rs.GetTestFraction())
+				} else {
+					fmt.Printf("PercentageToLeaveOutAsValidation: %d\n", rs.GetPercentLO())
+				}
+				if rs.GetStrategy() != "" {
+					fmt.Printf("SplitStrategy: %s\n", rs.GetStrategy())
+				}
+				if rs.GetTimeColumn() != "" {
+					fmt.Printf("TimeColumn: %s\n", rs.GetTimeColumn())
+				}
+				fmt.Print("\n")
 			} else if t.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErCrossVal {
 				fmt.Printf("Shuffled: %t\nFolds: %d\n\n",
 					t.AlgoParam.EvalParams.Cv.Shuffle, t.AlgoParam.EvalParams.Cv.Folds)
@@ -83,6 +101,9 @@ var getByIDCmd = &cobra.Command{
 		fmt.Printf("\nStartTime: %s\nEndTime: %s\n\n", startTime, endTime)
 
 		fmt.Printf("\nErrMessage: %s\nResult: %s\n\n", t.ErrMessage, t.Result)
+		if t.Network != "" {
+			fmt.Printf("Network: %s\n\n", t.Network)
+		}
 	},
 }
 
@@ -90,6 +111,7 @@ func init() {
 	rootCmd.AddCommand(getByIDCmd)
 
 	getByIDCmd.Flags().StringVarP(&id, "id", "i", "", "task id")
+	getByIDCmd.Flags().StringVar(&network, "network", "", "name of the blockchain network the task is published on, default for all networks the executor joins")
 
 	getByIDCmd.MarkFlagRequired("id")
 }
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	executorClient "github.com/PaddlePaddle/PaddleDTX/dai/executor/client"
+)
+
+var (
+	historyStatus string
+	taskType      string
+	offset        int64
+	labels        map[string]string
+)
+
+// formatTime formats the timestamp in nanoseconds, zero means the time is not reached
+func formatTime(t int64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(0, t).Format(timeTemplate)
+}
+
+// historyCmd queries the history of tasks the executor participates in
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "query the history of tasks the executor participates in, with filters on status, type, time and labels",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+		var startTime int64 = 0
+		if start != "" {
+			s, err := time.ParseInLocation(timeTemplate, start, time.Local)
+			if err != nil {
+				fmt.Printf("ParseInLocation failed：%v\n", err)
+				return
+			}
+			startTime = s.UnixNano()
+		}
+		endTime, err := time.ParseInLocation(timeTemplate, end, time.Local)
+		if err != nil {
+			fmt.Printf("ParseInLocation failed：%v\n", err)
+			return
+		}
+		if limit > blockchain.TaskListMaxNum {
+			fmt.Printf("invalid limit, the value must smaller than %v \n", blockchain.TaskListMaxNum)
+			return
+		}
+
+		tasks, err := client.ListTasks(context.Background(), historyStatus, taskType, startTime, endTime.UnixNano(),
+			limit, offset, labels, network)
+		if err != nil {
+			fmt.Printf("ListTasks failed：%v\n", err)
+			return
+		}
+
+		for _, task := range tasks.Tasks {
+			fmt.Printf("TaskID: %s\nTaskType: %s\nEvaluation: %v\nTaskStatus: %s\nRequester: %s\n"+
+				"PublishTime: %s\nStartTime: %s\nEndTime: %s\nInExecution: %v\nQueued: %v\n",
+				task.TaskID, task.TaskType, task.Evaluation, task.Status, hex.EncodeToString(task.Requester),
+				formatTime(task.PublishTime), formatTime(task.StartTime), formatTime(task.EndTime),
+				task.InExecution, task.Queued)
+			if task.Network != "" {
+				fmt.Printf("Network: %s\n", task.Network)
+			}
+			if len(task.Labels) > 0 {
+				fmt.Printf("Labels: %s\n", blockchain.FormatLabels(task.Labels))
+			}
+			if task.ErrMessage != "" {
+				fmt.Printf("ErrMessage: %s\n", task.ErrMessage)
+			}
+			fmt.Println()
+		}
+
+		fmt.Printf("taskNum : %d\n\n", len(tasks.Tasks))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyStatus, "status", "", "status of task, such as running, done, failed, cancelled, timeout, default for all")
+	historyCmd.Flags().StringVarP(&taskType, "type", "t", "", "type of task, such as train, predict, evaluation, default for all")
+	historyCmd.Flags().StringVarP(&start, "start", "s", "", "start of time range during which tasks were published, example '2021-06-10 12:00:00'")
+	historyCmd.Flags().StringVarP(&end, "end", "e", time.Unix(0, time.Now().UnixNano()).Format(timeTemplate), "end of time range during which tasks were published, example '2021-06-10 12:00:00'")
+	historyCmd.Flags().Int64VarP(&limit, "limit", "l", blockchain.TaskListMaxNum, "maximum of tasks can be queried")
+	historyCmd.Flags().Int64Var(&offset, "offset", 0, "number of matched tasks skipped")
+	historyCmd.Flags().StringToStringVar(&labels, "labels", nil, "labels of tasks, only tasks with all the labels are listed, example 'team=risk,project=p1'")
+	historyCmd.Flags().StringVar(&network, "network", "", "name of the blockchain network to query, default for all networks the executor joins")
+}
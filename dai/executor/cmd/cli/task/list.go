@@ -37,7 +37,7 @@ var listTasksCmd = &cobra.Command{
 	Use:   "list",
 	Short: "list tasks from blockchain through executor node",
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := executorClient.GetExecutorClient(host)
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
 		if err != nil {
 			fmt.Printf("GetExecutorClient failed: %v\n", err)
 			return
@@ -69,7 +69,7 @@ var listTasksCmd = &cobra.Command{
 			ePubkey = strings.TrimSpace(string(pubkeyBytes))
 		}
 
-		tasks, err := client.ListTask(context.Background(), rPubkey, ePubkey, status, startTime, endTime.UnixNano(), limit)
+		tasks, err := client.ListTask(context.Background(), rPubkey, ePubkey, status, startTime, endTime.UnixNano(), limit, network)
 		if err != nil {
 			fmt.Printf("ListTask failed：%v\n", err)
 			return
@@ -79,6 +79,9 @@ var listTasksCmd = &cobra.Command{
 			ptime := time.Unix(0, task.PublishTime).Format(timeTemplate)
 			fmt.Printf("TaskID: %s\nTaskType: %s\nTaskName: %s\nDescription: %s\nTaskStatus: %s\nPublishTime: %s\n\n",
 				task.TaskID, task.AlgoParam.TaskType, task.Name, task.Description, task.Status, ptime)
+			if task.Network != "" {
+				fmt.Printf("Network: %s\n\n", task.Network)
+			}
 		}
 
 		fmt.Printf("taskNum : %d\n\n", len(tasks.FLTasks))
@@ -95,4 +98,5 @@ func init() {
 	listTasksCmd.Flags().StringVarP(&end, "end", "e", time.Unix(0, time.Now().UnixNano()).Format(timeTemplate), "end of time range during which tasks were published, example '2021-06-10 12:00:00'")
 	listTasksCmd.Flags().Int64VarP(&limit, "limit", "l", blockchain.TaskListMaxNum, "limit of number for listing tasks")
 	listTasksCmd.Flags().StringVar(&status, "status", "", "status of task, such as Confirming, Ready, ToProcess, Processing, Finished, Failed, default for all types of status")
+	listTasksCmd.Flags().StringVar(&network, "network", "", "name of the blockchain network to query, default for all networks the executor joins")
 }
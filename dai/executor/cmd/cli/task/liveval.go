@@ -0,0 +1,68 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	executorClient "github.com/PaddlePaddle/PaddleDTX/dai/executor/client"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// curveWidth is the width of the bar of the largest metric score in the curve
+const curveWidth = 50
+
+// livevalCmd watches the live evaluation of a task in execution, and renders the curves of metric scores
+var livevalCmd = &cobra.Command{
+	Use:   "liveval",
+	Short: "watch the metric scores of live evaluation of a task in execution",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+
+		// the bars are scaled by the largest score of each metric received so far
+		maxScores := make(map[string]float64)
+		err = client.StreamLiveEvaluation(context.Background(), id, func(m *pbTask.LiveEvaluationMetric) {
+			if math.Abs(m.Value) > maxScores[m.MetricName] {
+				maxScores[m.MetricName] = math.Abs(m.Value)
+			}
+			width := 0
+			if maxScores[m.MetricName] > 0 {
+				width = int(math.Abs(m.Value) / maxScores[m.MetricName] * curveWidth)
+			}
+			fmt.Printf("Round: %-6d %-10s %-12f %s\n", m.Round, m.MetricName, m.Value, strings.Repeat("*", width))
+		})
+		if err != nil {
+			fmt.Printf("StreamLiveEvaluation failed：%v\n", err)
+			return
+		}
+		fmt.Println("task ended")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(livevalCmd)
+
+	livevalCmd.Flags().StringVarP(&id, "id", "i", "", "task's id")
+
+	livevalCmd.MarkFlagRequired("id")
+}
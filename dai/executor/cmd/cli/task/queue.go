@@ -0,0 +1,98 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	executorClient "github.com/PaddlePaddle/PaddleDTX/dai/executor/client"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+var priority int32
+
+// cancelQueuedCmd cancels a task waiting in the queue of the executor node, tasks in execution are cancelled by the requester
+var cancelQueuedCmd = &cobra.Command{
+	Use:   "cancelqueued",
+	Short: "cancel the task waiting in the queue of the executor node",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+		if err := readPrivateKey(); err != nil {
+			fmt.Printf("Read privateKey failed, err: %v\n", err)
+			return
+		}
+		if err := client.CancelQueuedTask(context.Background(), privateKey, id); err != nil {
+			fmt.Printf("CancelQueuedTask failed：%v\n", err)
+			return
+		}
+		fmt.Println("OK")
+	},
+}
+
+// setPriorityCmd changes the priority of a task waiting in the queue of the executor node
+var setPriorityCmd = &cobra.Command{
+	Use:   "setpriority",
+	Short: "change the priority of the task waiting in the queue of the executor node",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := executorClient.GetExecutorClient(host, tlsConf())
+		if err != nil {
+			fmt.Printf("GetExecutorClient failed: %v\n", err)
+			return
+		}
+		if err := readPrivateKey(); err != nil {
+			fmt.Printf("Read privateKey failed, err: %v\n", err)
+			return
+		}
+		if err := client.SetQueuedTaskPriority(context.Background(), privateKey, id, priority); err != nil {
+			fmt.Printf("SetQueuedTaskPriority failed：%v\n", err)
+			return
+		}
+		fmt.Println("OK")
+	},
+}
+
+// readPrivateKey reads the private key of the executor node from keyPath if it's not given
+func readPrivateKey() error {
+	if privateKey != "" {
+		return nil
+	}
+	privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
+	if err != nil {
+		return err
+	}
+	privateKey = strings.TrimSpace(string(privateKeyBytes))
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(cancelQueuedCmd)
+	rootCmd.AddCommand(setPriorityCmd)
+
+	for _, cmd := range []*cobra.Command{cancelQueuedCmd, setPriorityCmd} {
+		cmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "executor's private key hex string")
+		cmd.Flags().StringVarP(&keyPath, "keyPath", "", "./keys", "executor's key path")
+		cmd.Flags().StringVarP(&id, "id", "i", "", "id of the task waiting in the queue")
+		cmd.MarkFlagRequired("id")
+	}
+	setPriorityCmd.Flags().Int32VarP(&priority, "priority", "p", 0, "new priority of the task, tasks with higher priority start first")
+	setPriorityCmd.MarkFlagRequired("priority")
+}
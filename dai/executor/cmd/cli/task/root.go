@@ -15,6 +15,9 @@ package task
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
 )
 
 const timeTemplate = "2006-01-02 15:04:05"
@@ -27,6 +30,12 @@ var (
 	end        string
 	limit      int64
 	id         string
+	network    string
+
+	// tls files to connect to the executor whose gRPC server requires TLS
+	tlsCA   string
+	tlsCert string
+	tlsKey  string
 )
 
 // rootCmd represents root command
@@ -39,8 +48,16 @@ func RootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// tlsConf returns the tls config of the tls flags, nil to connect to the executor in plaintext
+func tlsConf() *config.TLSConf {
+	return tlsutil.ClientConf(tlsCA, tlsCert, tlsKey)
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVar(&host, "host", "", "server grpc address of the executorc node, example '127.0.0.1:8184'")
+	rootCmd.PersistentFlags().StringVar(&tlsCA, "tlsCA", "", "CA certificate file to verify the executor whose gRPC server requires TLS, the system roots if empty while tlsCert is set")
+	rootCmd.PersistentFlags().StringVar(&tlsCert, "tlsCert", "", "certificate file presented to the executor requiring mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsKey, "tlsKey", "", "private key file of tlsCert")
 
 	rootCmd.MarkPersistentFlagRequired("host")
 }
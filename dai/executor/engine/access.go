@@ -0,0 +1,82 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// labelSelectors returns the label selectors of the API token of the request forwarded by the httpserver,
+// nil if the request accesses all tasks, such as the requests from other executors.
+// gRPC callers are not authenticated, a request sent to the gRPC port directly carries no selector,
+// so the isolation by labels holds only if the gRPC port is not reachable by the clients of the httpserver.
+func labelSelectors(ctx context.Context) ([]map[string]string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	var selectors []map[string]string
+	for _, s := range md.Get(blockchain.LabelSelectorKey) {
+		selector, err := blockchain.ParseLabels(s)
+		if err != nil {
+			// never fall back to accessing all tasks
+			return nil, errorx.NewCode(err, errcodes.ErrCodeForbidden, "invalid label selector of the request")
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// taskAllowed returns whether the task has all the labels of all selectors
+func taskAllowed(task *pbTask.FLTask, selectors []map[string]string) bool {
+	for _, selector := range selectors {
+		if !blockchain.MatchLabels(task.AlgoParam.GetLabels(), selector) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTaskAccess returns ErrCodeForbidden if the request is not allowed to access the task by its labels
+func checkTaskAccess(ctx context.Context, task *pbTask.FLTask) error {
+	selectors, err := labelSelectors(ctx)
+	if err != nil {
+		return err
+	}
+	if !taskAllowed(task, selectors) {
+		return errorx.New(errcodes.ErrCodeForbidden, "forbidden: not allowed to access task %s", task.TaskID)
+	}
+	return nil
+}
+
+// allowedTasks returns the tasks with all the labels of all selectors, in the order of fts
+func allowedTasks(fts blockchain.FLTasks, selectors []map[string]string) blockchain.FLTasks {
+	if len(selectors) == 0 {
+		return fts
+	}
+	var allowed blockchain.FLTasks
+	for _, ft := range fts {
+		if taskAllowed(ft, selectors) {
+			allowed = append(allowed, ft)
+		}
+	}
+	return allowed
+}
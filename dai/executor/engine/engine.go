@@ -18,6 +18,8 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"sort"
+	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
@@ -31,13 +33,20 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/monitor"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/cluster"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/envelope"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/version"
 )
 
 var (
 	logger = logrus.WithField("module", "engine")
+
+	// errObserverRole is returned by the requests to execute tasks or get their results on observer nodes
+	errObserverRole = errorx.New(errcodes.ErrCodeObserverRole, "observer role: the node never executes tasks")
 )
 
 // Engine task processing engine
@@ -46,12 +55,27 @@ var (
 //  storage is the handler for results storage, which includes trained model and prediction result storage
 //  mpcHandler is the handler for mpc task execution, which includes task preparation, task execution, results storage...
 //  monitor is the handler for task monitoring, that is, monitoring tasks to be executed
+//  janitor removes local files of ended tasks by the retention policy, nil if it is not configured
+//  audit records the tasks confirmed and executed, nil if it is not configured
+//  outbox buffers the status of tasks while the blockchain is unreachable, nil for observers
+//  allowlist is the requesters allowed to use the node, nil if all requesters are allowed
+//  shutdownTimeout is the maximum time to wait for tasks in execution on shutdown
+//  ready caches the result of readiness check
+//  observer is true if the node is of observer role, which has no storage, mpcHandler and monitor
 type Engine struct {
-	chain      handler.Blockchain
-	node       handler.Node
-	storage    handler.FileStorage
-	mpcHandler handler.MpcHandler
-	monitor    *monitor.TaskMonitor
+	chain           handler.Blockchain
+	node            handler.Node
+	storage         handler.FileStorage
+	mpcHandler      handler.MpcHandler
+	monitor         *monitor.TaskMonitor
+	janitor         *handler.Janitor
+	audit           *handler.AuditLogger
+	outbox          *handler.StatusOutbox
+	allowlist       *handler.RequesterAllowlist
+	shutdownTimeout time.Duration
+	stopMonitor     context.CancelFunc
+	ready           readiness
+	observer        bool
 }
 
 // NewEngine initiates Engine by executor node configuration
@@ -59,14 +83,27 @@ func NewEngine(conf *config.ExecutorConf) (e *Engine, err error) {
 	return initEngine(conf)
 }
 
-// Start registers local node to blockchain and starts Monitor
+// Start registers local node to blockchain and starts Monitor,
+// observers are not registered, as they never execute tasks
 func (e *Engine) Start(ctx context.Context) error {
+	if e.observer {
+		logger.Info("engine of observer role started, tasks are monitored but never executed")
+		return nil
+	}
 	// register node
 	if err := e.node.Register(e.chain); err != nil {
 		return err
 	}
+	// the monitor can be stopped by Shutdown before ctx is done
+	ctx, e.stopMonitor = context.WithCancel(ctx)
 	// re-execute tasks in Processing status
 	go e.monitor.RetryProcessingTask(ctx)
+	// record the task status buffered during blockchain outages
+	go e.mpcHandler.RunOutbox(ctx)
+	// remove local files of ended tasks
+	if e.janitor != nil {
+		go e.janitor.Start(ctx)
+	}
 
 	// start timed task to find out tasks ready to execute,
 	// then starts Multi-Party Computation for each task
@@ -75,13 +112,51 @@ func (e *Engine) Start(ctx context.Context) error {
 	return nil
 }
 
-// GetMpcService returns mpc service to be registered to grpcServer
+// ReloadMpcConf applies the reloaded mpc configuration, new limits take effect for tasks started later
+func (e *Engine) ReloadMpcConf(conf *config.ExecutorMpcConf) {
+	if e.observer {
+		return
+	}
+	rpcTimeout, taskLimitTime, maxTaskLimitTime := mpcTimeouts(conf)
+	e.mpcHandler.UpdateMpcConf(conf.TrainTaskLimit, conf.PredictTaskLimit, rpcTimeout, taskLimitTime, maxTaskLimitTime)
+	logger.Infof("mpc config reloaded, trainTaskLimit: %d, predictTaskLimit: %d, rpcTimeout: %v, taskLimitTime: %v, maxTaskLimitTime: %v",
+		conf.TrainTaskLimit, conf.PredictTaskLimit, rpcTimeout, taskLimitTime, maxTaskLimitTime)
+}
+
+// ReloadRequesterAllowlist applies the reloaded requester allowlist, the current one is kept if it fails to load
+func (e *Engine) ReloadRequesterAllowlist(conf *config.RequesterAllowlistConf) {
+	if err := e.allowlist.Reload(conf); err != nil {
+		logger.WithError(err).Error("failed to reload the requester allowlist, keep the current one")
+	}
+}
+
+// GetMpcService returns mpc service to be registered to grpcServer, observers have no mpc service
 func (e *Engine) GetMpcService() *cluster.Service {
+	if e.observer {
+		return nil
+	}
 	return e.mpcHandler.GetMpcClusterService()
 }
 
-// ListTask lists tasks from blockchain by requester or executor's Public Key
+// networkChain returns the blockchain of the network named network, all networks joined if it's empty
+func (e *Engine) networkChain(network string) (handler.Blockchain, error) {
+	if network == "" {
+		return e.chain, nil
+	}
+	multi, ok := e.chain.(*handler.MultiChain)
+	if !ok {
+		return nil, errorx.New(errorx.ErrCodeParam, "the executor joins a single blockchain network, network should be empty")
+	}
+	return multi.Network(network)
+}
+
+// ListTask lists tasks from blockchain by requester or executor's Public Key, from the network in.Network if it's set.
+// Only the tasks the API token of the request is allowed to access by their labels are listed.
 func (e *Engine) ListTask(ctx context.Context, in *pbTask.ListTaskRequest) (*pbTask.FLTasks, error) {
+	selectors, err := labelSelectors(ctx)
+	if err != nil {
+		return &pbTask.FLTasks{}, err
+	}
 	listOptions := &blockchain.ListFLTaskOptions{
 		PubKey:     in.PubKey,
 		ExecPubKey: in.EPubKey,
@@ -90,11 +165,23 @@ func (e *Engine) ListTask(ctx context.Context, in *pbTask.ListTaskRequest) (*pbT
 		TimeEnd:    in.TimeEnd,
 		Limit:      in.Limit,
 	}
+	// the contract doesn't know the selectors, so all matched tasks are listed and limited after filtered
+	if len(selectors) > 0 {
+		listOptions.Limit = 0
+	}
+	chain, err := e.networkChain(in.Network)
+	if err != nil {
+		return &pbTask.FLTasks{}, err
+	}
 	// invoke contract to list tasks
-	fts, err := e.chain.ListTask(listOptions)
+	fts, err := chain.ListTask(listOptions)
 	if err != nil {
 		return &pbTask.FLTasks{}, errorx.Wrap(err, "failed list task")
 	}
+	fts = allowedTasks(fts, selectors)
+	if in.Limit > 0 && int64(len(fts)) > in.Limit {
+		fts = fts[:in.Limit]
+	}
 	// traverse tasks
 	resp := &pbTask.FLTasks{}
 	for _, ft := range fts {
@@ -103,24 +190,151 @@ func (e *Engine) ListTask(ctx context.Context, in *pbTask.ListTaskRequest) (*pbT
 	return resp, nil
 }
 
-// GetTaskById queries task details by taskID
+// taskTypeEvaluation filters the training tasks with model evaluation or live evaluation enabled
+const taskTypeEvaluation = "evaluation"
+
+// taskHistoryStatus maps the status filters of ListTasks to the task status stored in contract
+var taskHistoryStatus = map[string]string{
+	"running":   blockchain.TaskProcessing,
+	"done":      blockchain.TaskFinished,
+	"failed":    blockchain.TaskFailed,
+	"cancelled": blockchain.TaskCancelled,
+	"timeout":   blockchain.TaskTimeout,
+}
+
+// ListTasks queries the history of tasks the executor participates in from blockchain,
+//  filters them by network, status, type, publish time and labels, and returns the page specified by offset and limit.
+//  Tasks in the execution pool or the queue of the executor are marked with the local status.
+//  Only the tasks the API token of the request is allowed to access by their labels are listed.
+func (e *Engine) ListTasks(ctx context.Context, in *pbTask.ListTasksRequest) (*pbTask.TaskSummaries, error) {
+	selectors, err := labelSelectors(ctx)
+	if err != nil {
+		return &pbTask.TaskSummaries{}, err
+	}
+	status, ok := taskHistoryStatus[in.Status]
+	if in.Status != "" && !ok {
+		return &pbTask.TaskSummaries{}, errorx.New(errorx.ErrCodeParam,
+			"invalid status %s, should be one of running, done, failed, cancelled and timeout", in.Status)
+	}
+	if in.TaskType != "" && in.TaskType != blockchain.TaskTypeTrain && in.TaskType != blockchain.TaskTypePredict &&
+		in.TaskType != taskTypeEvaluation {
+		return &pbTask.TaskSummaries{}, errorx.New(errorx.ErrCodeParam,
+			"invalid task type %s, should be one of train, predict and evaluation", in.TaskType)
+	}
+	if err := blockchain.CheckTaskLabels(in.Labels); err != nil {
+		return &pbTask.TaskSummaries{}, err
+	}
+	if in.Limit < 0 || in.Limit > blockchain.TaskListMaxNum || in.Offset < 0 {
+		return &pbTask.TaskSummaries{}, errorx.New(errorx.ErrCodeParam,
+			"invalid limit or offset, limit should be in [0, %d] and offset should not be negative", blockchain.TaskListMaxNum)
+	}
+	limit := in.Limit
+	if limit == 0 {
+		limit = blockchain.TaskListMaxNum
+	}
+
+	chain, err := e.networkChain(in.Network)
+	if err != nil {
+		return &pbTask.TaskSummaries{}, err
+	}
+
+	pubkey := ecdsa.PublicKeyFromPrivateKey(e.node.PrivateKey)
+	listOptions := &blockchain.ListFLTaskOptions{
+		ExecPubKey: pubkey[:],
+		Status:     status,
+		TimeStart:  in.TimeStart,
+		TimeEnd:    in.TimeEnd,
+	}
+	// the contract doesn't know task types and labels, so all matched tasks are listed if they are filtered
+	if in.TaskType == "" && len(in.Labels) == 0 && len(selectors) == 0 {
+		listOptions.Limit = in.Offset + limit
+	}
+	fts, err := chain.ListTask(listOptions)
+	if err != nil {
+		return &pbTask.TaskSummaries{}, errorx.Wrap(err, "failed list task")
+	}
+	localStatus := func(string) (bool, bool) { return false, false }
+	if !e.observer {
+		localStatus = e.mpcHandler.GetLocalTaskStatus
+	}
+	return summarizeTasks(allowedTasks(fts, selectors), in.TaskType, in.Labels, in.Offset, limit, localStatus), nil
+}
+
+// summarizeTasks returns the summaries of tasks of taskType with all the labels, skipping the first offset ones,
+//  at most limit are returned. localStatus returns whether a task is in the execution pool or the queue of the executor
+func summarizeTasks(fts blockchain.FLTasks, taskType string, labels map[string]string, offset, limit int64,
+	localStatus func(taskID string) (bool, bool)) *pbTask.TaskSummaries {
+	resp := &pbTask.TaskSummaries{}
+	var skipped int64
+	for _, ft := range fts {
+		evaluation := ft.AlgoParam.TaskType == pbCom.TaskType_LEARN &&
+			(ft.AlgoParam.EvalParams.GetEnable() || ft.AlgoParam.LivalParams.GetEnable())
+		fType := blockchain.TaskTypeListValue[ft.AlgoParam.TaskType]
+		if taskType != "" && taskType != fType && !(taskType == taskTypeEvaluation && evaluation) {
+			continue
+		}
+		if !blockchain.MatchLabels(ft.AlgoParam.Labels, labels) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		if int64(len(resp.Tasks)) >= limit {
+			break
+		}
+		inExecution, queued := localStatus(ft.TaskID)
+		resp.Tasks = append(resp.Tasks, &pbTask.TaskSummary{
+			TaskID:      ft.TaskID,
+			TaskType:    fType,
+			Evaluation:  evaluation,
+			Status:      ft.Status,
+			Requester:   ft.Requester,
+			ErrMessage:  ft.ErrMessage,
+			PublishTime: ft.PublishTime,
+			StartTime:   ft.StartTime,
+			EndTime:     ft.EndTime,
+			InExecution: inExecution,
+			Queued:      queued,
+			Labels:      ft.AlgoParam.Labels,
+			Network:     ft.Network,
+		})
+	}
+	return resp
+}
+
+// GetTaskById queries task details by taskID, from the network in.Network if it's set
 func (e *Engine) GetTaskById(ctx context.Context, in *pbTask.GetTaskRequest) (*pbTask.FLTask, error) {
+	chain, err := e.networkChain(in.Network)
+	if err != nil {
+		return &pbTask.FLTask{}, err
+	}
 	// get task detail
-	task, err := e.chain.GetTaskById(in.TaskID)
+	task, err := chain.GetTaskById(in.TaskID)
 	if err != nil {
 		return &pbTask.FLTask{}, errorx.Wrap(err, "failed get task by id")
 	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.FLTask{}, err
+	}
 	return task, nil
 }
 
 // GetPredictResult checks task's initiator and gets prediction result from Xuper db.
 //  in.PubKey must matches task.Requester, only task.Requester can get prediction result.
+//  The result encrypted with the task's resultPublicKey is returned encrypted.
 func (e *Engine) GetPredictResult(ctx context.Context, in *pbTask.TaskRequest) (*pbTask.PredictResponse, error) {
+	if e.observer {
+		return &pbTask.PredictResponse{}, errObserverRole
+	}
 	// get task detail
 	task, err := e.chain.GetTaskById(in.TaskID)
 	if err != nil {
 		return &pbTask.PredictResponse{}, errorx.Wrap(err, "failed get predict result")
 	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.PredictResponse{}, err
+	}
 	// check task type
 	if task.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
 		return &pbTask.PredictResponse{}, errorx.New(errorx.ErrCodeParam, "illegal taskId, not a predict task")
@@ -136,14 +350,15 @@ func (e *Engine) GetPredictResult(ctx context.Context, in *pbTask.TaskRequest) (
 	if err := e.checkSign(in.Signature, in.PubKey, []byte(msg)); err != nil {
 		return &pbTask.PredictResponse{}, errorx.Wrap(err, "get predict result failed")
 	}
+	if err := e.allowlist.Check(in.PubKey); err != nil {
+		return &pbTask.PredictResponse{}, err
+	}
 
-	// get prediction result from XuperDB or LocalPath, if the prediction result is stored
-	// on XuperDB, task.Result is fileId, else get the file from LocalPath by prediction task's ID.
-	predictFileName := task.Result
-	if predictFileName == "" {
-		predictFileName = in.TaskID
+	predictFileName, err := e.predictResultKey(task, in.BatchIndex)
+	if err != nil {
+		return &pbTask.PredictResponse{}, err
 	}
-	r, err := e.storage.PredictStorage.Read(predictFileName)
+	r, err := e.storage.PredictStorage.Download(ctx, predictFileName)
 	if err != nil {
 		return &pbTask.PredictResponse{}, errorx.Wrap(err, "failed to get reader from xuperdb")
 	}
@@ -153,6 +368,14 @@ func (e *Engine) GetPredictResult(ctx context.Context, in *pbTask.TaskRequest) (
 	}
 	defer r.Close()
 
+	// the result encrypted with the requester's public key is returned as it is, and decrypted by the requester
+	if envelope.IsSealed(text) {
+		return &pbTask.PredictResponse{
+			TaskID:    task.TaskID,
+			Payload:   text,
+			Encrypted: true,
+		}, nil
+	}
 	// format result
 	rows, err := vl_common.PredictResultFromBytes(text)
 	if err != nil {
@@ -169,9 +392,198 @@ func (e *Engine) GetPredictResult(ctx context.Context, in *pbTask.TaskRequest) (
 	}, nil
 }
 
+// predictResultKey returns the key to download the prediction result of the input batchIndex of task.
+// If the prediction result is stored on XuperDB, the key is the fileId in task.Result,
+// else the key is the name of the prediction task's result in LocalPath.
+func (e *Engine) predictResultKey(task *pbTask.FLTask, batchIndex int32) (string, error) {
+	predictFileName := task.Result
+	if len(task.BatchResults) > 0 {
+		// the results of the inputs of a batch prediction are named after their indices
+		if batchIndex < 0 || int(batchIndex) >= len(task.BatchResults) {
+			return "", errorx.New(errorx.ErrCodeParam, "invalid batch index %d, the task has %d inputs",
+				batchIndex, len(task.BatchResults))
+		}
+		batchResult := task.BatchResults[batchIndex]
+		if batchResult.ErrMessage != "" {
+			return "", errorx.New(errorx.ErrCodeParam, "input %d is not predicted: %s",
+				batchIndex, batchResult.ErrMessage)
+		}
+		predictFileName = batchResult.Result
+	} else if batchIndex != 0 {
+		return "", errorx.New(errorx.ErrCodeParam, "task %s is not a batch prediction", task.TaskID)
+	}
+	if predictFileName == "" {
+		predictFileName = e.storage.FileName(storage.KindPrediction, task, handler.BatchResultName(task.TaskID, batchIndex))
+	}
+	return predictFileName, nil
+}
+
+// VerifyResult verifies the signature of the prediction result of the input in.BatchIndex of the task in.TaskID,
+// which is stored next to the result by the node, against the public key of the node.
+// The signature is returned along with whether it's valid, errors are returned if the result or its signature can't be read.
+func (e *Engine) VerifyResult(ctx context.Context, in *pbTask.VerifyResultRequest) (*pbTask.ResultSignature, error) {
+	if e.observer {
+		return &pbTask.ResultSignature{}, errObserverRole
+	}
+	task, err := e.chain.GetTaskById(in.TaskID)
+	if err != nil {
+		return &pbTask.ResultSignature{}, errorx.Wrap(err, "failed to get predict task")
+	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.ResultSignature{}, err
+	}
+	if task.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
+		return &pbTask.ResultSignature{}, errorx.New(errorx.ErrCodeParam, "illegal taskId, not a predict task")
+	}
+	key, err := e.predictResultKey(task, in.BatchIndex)
+	if err != nil {
+		return &pbTask.ResultSignature{}, err
+	}
+	r, err := e.storage.PredictStorage.Download(ctx, key)
+	if err != nil {
+		return &pbTask.ResultSignature{}, errorx.Wrap(err, "failed to get prediction result")
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &pbTask.ResultSignature{}, errorx.Wrap(err, "failed to read prediction result")
+	}
+
+	name := e.storage.FileName(storage.KindPrediction, task, handler.BatchResultName(in.TaskID, in.BatchIndex))
+	sig, err := e.storage.LoadResultSignature(ctx, name)
+	if err != nil {
+		return &pbTask.ResultSignature{}, err
+	}
+	// the result is signed by the key the task is published to, which may be rotated since
+	pubkey := e.node.Identity(task).ID
+	if err := blockchain.VerifyResultSignature(sig, pubkey, in.TaskID, in.BatchIndex, content); err != nil {
+		logger.WithField(logging.TaskIDKey, in.TaskID).WithError(err).Warn("invalid signature of prediction result")
+		return sig, nil
+	}
+	sig.Valid = true
+	return sig, nil
+}
+
+// ExportModel checks the requester of the training task and returns the local part of its model.
+//  in.PubKey must matches the requester of the task, only linear and logistic regression models can be exported.
+func (e *Engine) ExportModel(ctx context.Context, in *pbTask.ExportModelRequest) (*pbTask.ExportModelResponse, error) {
+	if e.observer {
+		return &pbTask.ExportModelResponse{}, errObserverRole
+	}
+	task, err := e.chain.GetTaskById(in.ModelID)
+	if err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Wrap(err, "failed to get model task")
+	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.ExportModelResponse{}, err
+	}
+	if task.AlgoParam.TaskType != pbCom.TaskType_LEARN || task.Status != blockchain.TaskFinished {
+		return &pbTask.ExportModelResponse{}, errorx.New(errcodes.ErrCodeParam, "illegal modelID, not a finished training task")
+	}
+	if task.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && task.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+		return &pbTask.ExportModelResponse{}, errorx.New(errcodes.ErrCodeParam, "models of %s can't be exported", task.AlgoParam.Algo)
+	}
+	if !bytes.Equal(task.Requester, in.PubKey) {
+		return &pbTask.ExportModelResponse{}, errorx.New(errorx.ErrCodeParam, "public key is invalid")
+	}
+	// check signature
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Internal(err, "failed to get the message to sign")
+	}
+	if err := e.checkSign(in.Signature, in.PubKey, []byte(msg)); err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Wrap(err, "export model failed")
+	}
+	if err := e.allowlist.Check(in.PubKey); err != nil {
+		return &pbTask.ExportModelResponse{}, err
+	}
+
+	r, err := e.storage.ModelStorage.Download(ctx, e.storage.FileName(storage.KindModel, task, in.ModelID))
+	if err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Wrap(err, "failed to get model %s", in.ModelID)
+	}
+	defer r.Close()
+	text, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Wrap(err, "failed to read model %s", in.ModelID)
+	}
+	model, err := vl_common.TrainModelsFromBytes(text)
+	if err != nil {
+		return &pbTask.ExportModelResponse{}, errorx.Wrap(err, "failed to parse model %s", in.ModelID)
+	}
+	return &pbTask.ExportModelResponse{
+		ModelID: in.ModelID,
+		Model:   model,
+	}, nil
+}
+
+// GetFeatureImportance returns the importance of the local features of the linear or logistic regression model
+// trained by the task in.ModelID, only the requester of the task is allowed to get it. The report stored after training
+// is returned, or computed from the model if it isn't stored, such as the models trained before it's supported.
+func (e *Engine) GetFeatureImportance(ctx context.Context, in *pbTask.FeatureImportanceRequest) (*pbTask.FeatureImportanceResponse, error) {
+	if e.observer {
+		return &pbTask.FeatureImportanceResponse{}, errObserverRole
+	}
+	task, err := e.chain.GetTaskById(in.ModelID)
+	if err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "failed to get model task")
+	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.FeatureImportanceResponse{}, err
+	}
+	if task.AlgoParam.TaskType != pbCom.TaskType_LEARN || task.Status != blockchain.TaskFinished {
+		return &pbTask.FeatureImportanceResponse{}, errorx.New(errcodes.ErrCodeParam, "illegal modelID, not a finished training task")
+	}
+	if task.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && task.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+		return &pbTask.FeatureImportanceResponse{}, errorx.New(errcodes.ErrCodeParam, "feature importance of %s is not supported", task.AlgoParam.Algo)
+	}
+	if !bytes.Equal(task.Requester, in.PubKey) {
+		return &pbTask.FeatureImportanceResponse{}, errorx.New(errorx.ErrCodeParam, "public key is invalid")
+	}
+	// check signature
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Internal(err, "failed to get the message to sign")
+	}
+	if err := e.checkSign(in.Signature, in.PubKey, []byte(msg)); err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "get feature importance failed")
+	}
+	if err := e.allowlist.Check(in.PubKey); err != nil {
+		return &pbTask.FeatureImportanceResponse{}, err
+	}
+
+	importances, err := e.storage.LoadFeatureImportances(ctx, task)
+	if err == nil {
+		return &pbTask.FeatureImportanceResponse{ModelID: in.ModelID, Importances: importances}, nil
+	}
+	if !storage.IsNotFound(err) {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "failed to get feature importance of model %s", in.ModelID)
+	}
+	r, err := e.storage.ModelStorage.Download(ctx, e.storage.FileName(storage.KindModel, task, in.ModelID))
+	if err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "failed to get model %s", in.ModelID)
+	}
+	defer r.Close()
+	text, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "failed to read model %s", in.ModelID)
+	}
+	model, err := vl_common.TrainModelsFromBytes(text)
+	if err != nil {
+		return &pbTask.FeatureImportanceResponse{}, errorx.Wrap(err, "failed to parse model %s", in.ModelID)
+	}
+	return &pbTask.FeatureImportanceResponse{
+		ModelID:     in.ModelID,
+		Importances: handler.FeatureImportances(model),
+	}, nil
+}
+
 // StartTask starts mpc-training or mpc-prediction after received "task starting" message from remote executor
 func (e *Engine) StartTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask.TaskResponse, error) {
 	logger.Debugf("got StartTaskRequest: %v", in)
+	if e.observer {
+		return &pbTask.TaskResponse{}, errObserverRole
+	}
 	// get task detail
 	task, err := e.chain.GetTaskById(in.TaskID)
 	if err != nil {
@@ -182,8 +594,11 @@ func (e *Engine) StartTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask
 		return &pbTask.TaskResponse{}, errorx.Wrap(err, "illegal task status")
 	}
 
-	// check sign
+	// check sign, the fingerprint is not signed
+	fingerprint := in.Fingerprint
+	in.Fingerprint = nil
 	msg, err := util.GetSigMessage(in)
+	in.Fingerprint = fingerprint
 	if err != nil {
 		return &pbTask.TaskResponse{}, errorx.Internal(err, "failed to get the message to sign for start mpc task")
 	}
@@ -191,10 +606,12 @@ func (e *Engine) StartTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask
 		return &pbTask.TaskResponse{}, errorx.Wrap(err, "start task failed, signature error")
 	}
 	// make sure the request must come from executor who confirmed the task
+	var requester string
 	isExecutorNodeExist := false
 	for _, ds := range task.DataSets {
 		if bytes.Equal(ds.Executor, in.PubKey) {
 			isExecutorNodeExist = true
+			requester = ds.Address
 			break
 		}
 	}
@@ -203,7 +620,7 @@ func (e *Engine) StartTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask
 	}
 
 	// prepare resources before start mpc
-	startRequest, err := e.mpcHandler.TaskStartPrepare(task)
+	startRequest, err := e.mpcHandler.TaskStartPrepare(ctx, task)
 	if err != nil {
 		if code, _ := errorx.Parse(err); code == errcodes.ErrCodeTaskExists {
 			logger.Info("Local mpc task already start")
@@ -214,21 +631,251 @@ func (e *Engine) StartTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask
 		logger.WithError(err).Error("failed to start local mpc, task start preparation error")
 		return &pbTask.TaskResponse{}, errorx.Wrap(err, "task start prepare error")
 	}
+	// fail the task before heavy computation begins if the executors disagree on it
+	if err := e.mpcHandler.CheckTaskFingerprint(startRequest, in.Fingerprint, requester); err != nil {
+		return &pbTask.TaskResponse{}, err
+	}
+	// both executors select PSI algorithm auto by the larger number of their samples
+	psiAlgorithm := e.mpcHandler.AgreePSIAlgorithm(startRequest, in.Fingerprint)
+	// skip PSI if both executors have cached the sample alignment of the task
+	alignmentKey, alignmentCached := e.mpcHandler.PrepareAlignment(startRequest, in.Fingerprint, requester)
 
 	// start local mpc
 	go func() {
 		if err := e.mpcHandler.StartLocalMpcTask(startRequest, false); err != nil {
-			logger.WithError(err).Errorf("failed to start local mpc , taskId: %s", task.TaskID)
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to start local mpc")
 		}
 	}()
 
 	logger.Info("Start local mpc successfully after receive task starting signal")
 
+	return &pbTask.TaskResponse{
+		TaskID:          in.TaskID,
+		AlignmentKey:    alignmentKey,
+		AlignmentCached: alignmentCached,
+		PsiAlgorithm:    psiAlgorithm,
+	}, nil
+}
+
+// CancelTask cancels a task in execution, the request comes from either the task requester
+// or an executor of the task forwarding the requester's cancellation.
+// Nothing is done if the task has already ended, and TaskResponse.Message explains why.
+func (e *Engine) CancelTask(ctx context.Context, in *pbTask.TaskRequest) (*pbTask.TaskResponse, error) {
+	logger.Debugf("got CancelTaskRequest: %v", in)
+	if e.observer {
+		return &pbTask.TaskResponse{}, errObserverRole
+	}
+	// get task detail
+	task, err := e.chain.GetTaskById(in.TaskID)
+	if err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "get task from chain error")
+	}
+	if err := checkTaskAccess(ctx, task); err != nil {
+		return &pbTask.TaskResponse{}, err
+	}
+
+	// check sign
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return &pbTask.TaskResponse{}, errorx.Internal(err, "failed to get the message to sign for cancel task")
+	}
+	if err := e.checkSign(in.Signature, in.PubKey, []byte(msg)); err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "cancel task failed, signature error")
+	}
+	// make sure the request comes from the requester or an executor of the task,
+	// and the local node is one of the executors
+	fromRequester := bytes.Equal(task.Requester, in.PubKey)
+	isExecutorNodeExist, isLocalExecutor := false, false
+	pubkey := e.node.Identity(task).ID
+	for _, ds := range task.DataSets {
+		if bytes.Equal(ds.Executor, in.PubKey) {
+			isExecutorNodeExist = true
+		}
+		if bytes.Equal(ds.Executor, pubkey) {
+			isLocalExecutor = true
+		}
+	}
+	if !fromRequester && !isExecutorNodeExist {
+		return &pbTask.TaskResponse{}, errorx.New(errcodes.ErrCodeParam, "wrong request source[%x]", in.PubKey)
+	}
+	if !isLocalExecutor {
+		return &pbTask.TaskResponse{}, errorx.New(errcodes.ErrCodeParam, "local node is not an executor of task %s", in.TaskID)
+	}
+
+	switch task.Status {
+	case blockchain.TaskFinished, blockchain.TaskFailed, blockchain.TaskRejected:
+		return &pbTask.TaskResponse{
+			TaskID:  in.TaskID,
+			Message: "task already ended with status " + task.Status + ", nothing to cancel",
+		}, nil
+	case blockchain.TaskCancelled, blockchain.TaskTimeout:
+		// the executor receiving the requester's cancellation or finding the task expired records the status first,
+		// the others still need to stop the task
+		if fromRequester {
+			return &pbTask.TaskResponse{
+				TaskID:  in.TaskID,
+				Message: "task already ended with status " + task.Status + ", nothing to cancel",
+			}, nil
+		}
+	case blockchain.TaskProcessing:
+	default:
+		return &pbTask.TaskResponse{}, errorx.New(errcodes.ErrCodeParam,
+			"illegal task status %s, only Processing tasks can be cancelled", task.Status)
+	}
+
+	if err := e.mpcHandler.CancelTask(task, "task cancelled by the requester", fromRequester); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to cancel task")
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "cancel task error")
+	}
+	return &pbTask.TaskResponse{
+		TaskID: in.TaskID,
+	}, nil
+}
+
+// CancelQueuedTask cancels a task waiting in the queue of the node, the request is signed by the private key of the node,
+// as only the operator of the node manages its queue. Tasks in execution are cancelled by CancelTask.
+func (e *Engine) CancelQueuedTask(ctx context.Context, in *pbTask.QueuedTaskRequest) (*pbTask.TaskResponse, error) {
+	logger.Debugf("got CancelQueuedTaskRequest: %v", in)
+	if err := e.checkQueuedTaskRequest(ctx, in); err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "cancel queued task failed")
+	}
+	if err := e.mpcHandler.CancelQueuedTask(in.TaskID, "task cancelled by the operator of executor before it starts"); err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "cancel queued task error")
+	}
+	return &pbTask.TaskResponse{
+		TaskID: in.TaskID,
+	}, nil
+}
+
+// SetQueuedTaskPriority changes the priority of a task waiting in the queue of the node, the request is signed by
+// the private key of the node. Only the order of the local queue is changed, not the priority recorded in blockchain.
+func (e *Engine) SetQueuedTaskPriority(ctx context.Context, in *pbTask.QueuedTaskRequest) (*pbTask.TaskResponse, error) {
+	logger.Debugf("got SetQueuedTaskPriorityRequest: %v", in)
+	if err := e.checkQueuedTaskRequest(ctx, in); err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "set priority of queued task failed")
+	}
+	if err := e.mpcHandler.SetQueuedTaskPriority(in.TaskID, in.Priority); err != nil {
+		return &pbTask.TaskResponse{}, errorx.Wrap(err, "set priority of queued task error")
+	}
 	return &pbTask.TaskResponse{
 		TaskID: in.TaskID,
 	}, nil
 }
 
+// checkQueuedTaskRequest checks the request to manage the queue is signed by the private key of the node,
+// and the API token of the request is allowed to access the task by its labels
+func (e *Engine) checkQueuedTaskRequest(ctx context.Context, in *pbTask.QueuedTaskRequest) error {
+	if e.observer {
+		return errObserverRole
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(e.node.PrivateKey)
+	if !bytes.Equal(pubkey[:], in.PubKey) {
+		return errorx.New(errcodes.ErrCodeParam, "wrong request source[%x], only the executor node manages its queue", in.PubKey)
+	}
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for queued task")
+	}
+	if err := e.checkSign(in.Signature, in.PubKey, []byte(msg)); err != nil {
+		return errorx.Wrap(err, "signature error")
+	}
+	selectors, err := labelSelectors(ctx)
+	if err != nil || len(selectors) == 0 {
+		return err
+	}
+	task, err := e.chain.GetTaskById(in.TaskID)
+	if err != nil {
+		return errorx.Wrap(err, "get task from chain error")
+	}
+	return checkTaskAccess(ctx, task)
+}
+
+// StreamLiveEvaluation pushes the metric scores of live evaluation of a task in execution to the client
+//  as they're calculated, starting with the latest ones already calculated. The stream ends when the task
+//  ends or is cancelled. Metric scores are buffered for a slow client, and the oldest ones are dropped
+//  when the buffer is full, so that training isn't stalled.
+func (e *Engine) StreamLiveEvaluation(in *pbTask.LiveEvaluationRequest, stream pbTask.Task_StreamLiveEvaluationServer) error {
+	if e.observer {
+		return errObserverRole
+	}
+	metrics, unsubscribe, err := e.mpcHandler.SubscribeLiveEvaluation(in.TaskID)
+	if err != nil {
+		return errorx.Wrap(err, "failed to watch live evaluation")
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case metric, ok := <-metrics:
+			if !ok {
+				logger.WithField(logging.TaskIDKey, in.TaskID).Debug("task ended, live evaluation stream closed")
+				return nil
+			}
+			if err := stream.Send(metric); err != nil {
+				return errorx.Wrap(err, "failed to send live evaluation metric")
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// GetNodeStatus returns tasks in execution and resources usage of the node, which are all zero for observers
+func (e *Engine) GetNodeStatus(ctx context.Context, in *pbTask.NodeStatusRequest) (*pbTask.NodeStatus, error) {
+	if e.observer {
+		return &pbTask.NodeStatus{}, nil
+	}
+	status := e.mpcHandler.GetResourceStatus()
+	return &pbTask.NodeStatus{
+		TrainTasks:       int64(status.TrainTasks),
+		PredictTasks:     int64(status.PredictTasks),
+		TrainTaskLimit:   int64(status.TrainTaskLimit),
+		PredictTaskLimit: int64(status.PredictTaskLimit),
+		MaxTaskMemoryMB:  int64(status.Limits.MaxMemoryMB),
+		MaxTaskCPUCores:  int64(status.Limits.MaxCPUCores),
+		MemoryBudgetMB:   int64(status.Limits.NodeMemoryMB),
+		CpuBudgetCores:   int64(status.Limits.NodeCPUCores),
+		MemoryReservedMB: int64(status.MemoryReservedMB),
+		CpuReservedCores: int64(status.CPUReservedCores),
+		MemoryUsedMB:     int64(status.MemoryUsedMB),
+		QueuedTasks:      int64(status.QueuedTasks),
+		QueueSize:        int64(status.QueueSize),
+	}, nil
+}
+
+// GetNodeInfo returns the identity of the node, only the public key derived from its private key is returned,
+// observers support no algorithms
+func (e *Engine) GetNodeInfo(ctx context.Context, in *pbTask.NodeInfoRequest) (*pbTask.NodeInfo, error) {
+	pubkey := ecdsa.PublicKeyFromPrivateKey(e.node.PrivateKey)
+	var algorithms []string
+	if !e.observer {
+		algorithms = supportedAlgorithms(e.node)
+	}
+	return &pbTask.NodeInfo{
+		Name:          e.node.Name,
+		PublicAddress: e.node.Address,
+		PublicKey:     pubkey.String(),
+		Algorithms:    algorithms,
+		Version:       version.Version,
+		GitCommit:     version.GitCommit,
+		BuildDate:     version.BuildDate,
+	}, nil
+}
+
+// supportedAlgorithms returns the names of algorithms the node supports in order,
+// 'dnn-paddlefl-vl' is supported only if the PaddleFL container is configured
+func supportedAlgorithms(node handler.Node) []string {
+	var algorithms []string
+	for name, algo := range blockchain.VlAlgorithmListName {
+		if algo == pbCom.Algorithm_DNN_PADDLEFL_VL && node.PaddleFLAddress == "" {
+			continue
+		}
+		algorithms = append(algorithms, name)
+	}
+	sort.Strings(algorithms)
+	return algorithms
+}
+
 // checkSign verify if signature is valid
 //  sign is the signature signed by private key
 //  owner is the public key of signer
@@ -248,6 +895,27 @@ func (e *Engine) checkSign(sign, owner, mes []byte) (err error) {
 	return nil
 }
 
+// Shutdown stops finding new tasks to execute and refuses requests to start tasks, then waits
+// at most shutdownTimeout for the tasks in execution to finish, the remaining ones are cancelled.
+// The gRPC server must keep serving until Shutdown returns, as tasks communicate with other executors.
+func (e *Engine) Shutdown() {
+	logger.Infof("engine shutting down, wait at most %v for tasks in execution", e.shutdownTimeout)
+	e.setShutdown()
+	if e.stopMonitor != nil {
+		e.stopMonitor()
+	}
+	if e.monitor != nil {
+		e.monitor.StopLoopReq()
+		e.monitor.StopRetryReq()
+	}
+	if e.mpcHandler != nil {
+		e.mpcHandler.Shutdown(e.shutdownTimeout)
+	}
+	if n := e.outbox.State().PendingUpdates; n > 0 {
+		logger.Warnf("%d task status updates buffered in blockchain outage are dropped, the tasks are re-executed on restart", n)
+	}
+}
+
 // Close waits until all inner services stop
 func (e *Engine) Close() {
 	if e.monitor != nil {
@@ -257,6 +925,11 @@ func (e *Engine) Close() {
 	if e.mpcHandler != nil {
 		e.mpcHandler.Close()
 	}
+	e.allowlist.Close()
+	// audit records are anchored before the blockchain client is closed
+	if err := e.audit.Close(); err != nil {
+		logger.WithError(err).Warn("failed to close audit log")
+	}
 	if e.chain != nil {
 		e.chain.Close()
 	}
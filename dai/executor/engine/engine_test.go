@@ -0,0 +1,246 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func newHistoryTask(id string, taskType pbCom.TaskType, evaluation bool) *pbTask.FLTask {
+	return &pbTask.FLTask{
+		TaskID: id,
+		Status: blockchain.TaskFinished,
+		AlgoParam: &pbCom.TaskParams{
+			TaskType:   taskType,
+			EvalParams: &pbCom.EvaluationParams{Enable: evaluation},
+		},
+	}
+}
+
+func TestSummarizeTasks(t *testing.T) {
+	fts := blockchain.FLTasks{
+		newHistoryTask("t1", pbCom.TaskType_LEARN, false),
+		newHistoryTask("t2", pbCom.TaskType_PREDICT, false),
+		newHistoryTask("t3", pbCom.TaskType_LEARN, true),
+		newHistoryTask("t4", pbCom.TaskType_LEARN, true),
+	}
+	fts[1].AlgoParam.Labels = map[string]string{"team": "risk"}
+	fts[2].AlgoParam.Labels = map[string]string{"team": "risk", "project": "p1"}
+	localStatus := func(taskID string) (bool, bool) {
+		return taskID == "t3", taskID == "t4"
+	}
+
+	ids := func(s *pbTask.TaskSummaries) (ids []string) {
+		for _, task := range s.Tasks {
+			ids = append(ids, task.TaskID)
+		}
+		return ids
+	}
+	testCases := []struct {
+		name     string
+		taskType string
+		labels   map[string]string
+		offset   int64
+		limit    int64
+		expected []string
+	}{
+		{"all", "", nil, 0, 100, []string{"t1", "t2", "t3", "t4"}},
+		{"train", blockchain.TaskTypeTrain, nil, 0, 100, []string{"t1", "t3", "t4"}},
+		{"predict", blockchain.TaskTypePredict, nil, 0, 100, []string{"t2"}},
+		{"evaluation", taskTypeEvaluation, nil, 0, 100, []string{"t3", "t4"}},
+		{"page", blockchain.TaskTypeTrain, nil, 1, 1, []string{"t3"}},
+		{"offsetBeyond", "", nil, 4, 100, nil},
+		{"label", "", map[string]string{"team": "risk"}, 0, 100, []string{"t2", "t3"}},
+		{"labels", "", map[string]string{"team": "risk", "project": "p1"}, 0, 100, []string{"t3"}},
+		{"trainLabel", blockchain.TaskTypeTrain, map[string]string{"team": "risk"}, 0, 100, []string{"t3"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ids(summarizeTasks(fts, tc.taskType, tc.labels, tc.offset, tc.limit, localStatus))
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("expected %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+
+	s := summarizeTasks(fts, taskTypeEvaluation, nil, 0, 100, localStatus)
+	if task := s.Tasks[0]; task.TaskType != blockchain.TaskTypeTrain || !task.Evaluation || !task.InExecution || task.Queued ||
+		task.Labels["project"] != "p1" {
+		t.Errorf("unexpected summary of t3: %v", task)
+	}
+	if task := s.Tasks[1]; task.InExecution || !task.Queued {
+		t.Errorf("expected t4 queued, got %v", task)
+	}
+}
+
+func TestGetNodeInfo(t *testing.T) {
+	sk, pk, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Engine{node: handler.Node{
+		Local: peer.Local{Name: "executor1", Address: "127.0.0.1:8184", ID: pk[:], PrivateKey: sk},
+	}}
+	info, err := e.GetNodeInfo(context.Background(), &pbTask.NodeInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "executor1" || info.PublicAddress != "127.0.0.1:8184" || info.PublicKey != pk.String() {
+		t.Errorf("unexpected node identity: %+v", info)
+	}
+	if strings.Contains(fmt.Sprint(info), sk.String()) {
+		t.Error("private key should never be returned")
+	}
+	if expected := "linear-vl,logistic-vl,xgboost-vl"; strings.Join(info.Algorithms, ",") != expected {
+		t.Errorf("expected algorithms %s without PaddleFL, got %v", expected, info.Algorithms)
+	}
+
+	e.node.PaddleFLAddress = "127.0.0.1:38302"
+	if algorithms := supportedAlgorithms(e.node); len(algorithms) != 4 {
+		t.Errorf("expected dnn-paddlefl-vl supported with PaddleFL, got %v", algorithms)
+	}
+}
+
+func TestObserverRole(t *testing.T) {
+	sk, pk, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Engine{observer: true, node: handler.Node{
+		Local: peer.Local{Name: "auditor1", Address: "127.0.0.1:8184", ID: pk[:], PrivateKey: sk},
+	}}
+	ctx := context.Background()
+
+	_, err = e.StartTask(ctx, &pbTask.TaskRequest{TaskID: "t1"})
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeObserverRole {
+		t.Errorf("expected task start rejected by observer role, got %v", err)
+	}
+	_, err = e.GetPredictResult(ctx, &pbTask.TaskRequest{TaskID: "t1"})
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeObserverRole {
+		t.Errorf("expected prediction result rejected by observer role, got %v", err)
+	}
+	if e.GetMpcService() != nil {
+		t.Error("observer should have no mpc service")
+	}
+
+	info, err := e.GetNodeInfo(ctx, &pbTask.NodeInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Algorithms) != 0 {
+		t.Errorf("observer should support no algorithms, got %v", info.Algorithms)
+	}
+	status, err := e.GetNodeStatus(ctx, &pbTask.NodeStatusRequest{})
+	if err != nil || status.TrainTasks != 0 || status.TrainTaskLimit != 0 {
+		t.Errorf("unexpected status of observer: %v, %v", status, err)
+	}
+}
+
+func TestNetworkChain(t *testing.T) {
+	single := &Engine{chain: handler.NewTracingChain(nil)}
+	if _, err := single.networkChain(""); err != nil {
+		t.Errorf("empty network should be accepted by single network executors, got %v", err)
+	}
+	if _, err := single.networkChain("consortium-b"); err == nil {
+		t.Error("network should be rejected by single network executors")
+	}
+
+	multi := &Engine{chain: handler.NewMultiChain([]handler.Network{{Name: "default"}, {Name: "consortium-b"}})}
+	if _, err := multi.networkChain("consortium-b"); err != nil {
+		t.Errorf("joined network should be accepted, got %v", err)
+	}
+	if _, err := multi.networkChain("consortium-c"); err == nil {
+		t.Error("unknown network should be rejected")
+	}
+}
+
+func TestTaskAccess(t *testing.T) {
+	fts := blockchain.FLTasks{
+		newHistoryTask("t1", pbCom.TaskType_LEARN, false),
+		newHistoryTask("t2", pbCom.TaskType_PREDICT, false),
+		newHistoryTask("t3", pbCom.TaskType_LEARN, false),
+	}
+	fts[1].AlgoParam.Labels = map[string]string{"team": "risk"}
+	fts[2].AlgoParam.Labels = map[string]string{"team": "risk", "project": "p1"}
+	withSelectors := func(selectors ...string) context.Context {
+		md := metadata.MD{}
+		for _, s := range selectors {
+			md.Append(blockchain.LabelSelectorKey, s)
+		}
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+	ids := func(fts blockchain.FLTasks) (ids []string) {
+		for _, ft := range fts {
+			ids = append(ids, ft.TaskID)
+		}
+		return ids
+	}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		expected []string
+	}{
+		{"noMetadata", context.Background(), []string{"t1", "t2", "t3"}},
+		{"noSelector", withSelectors(), []string{"t1", "t2", "t3"}},
+		{"selector", withSelectors("team=risk"), []string{"t2", "t3"}},
+		{"selectors", withSelectors("team=risk", "project=p1"), []string{"t3"}},
+		{"noMatch", withSelectors("team=growth"), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selectors, err := labelSelectors(tc.ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(allowedTasks(fts, selectors)); strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected tasks %v, got %v", tc.expected, got)
+			}
+			for _, ft := range fts {
+				err := checkTaskAccess(tc.ctx, ft)
+				if strings.Contains(strings.Join(tc.expected, ","), ft.TaskID) {
+					if err != nil {
+						t.Errorf("expected %s accessible, got %v", ft.TaskID, err)
+					}
+				} else if code, _ := errorx.Parse(err); err == nil || code != errcodes.ErrCodeForbidden {
+					t.Errorf("expected %s forbidden, got %v", ft.TaskID, err)
+				}
+			}
+		})
+	}
+
+	// invalid selectors never fall back to accessing all tasks
+	if err := checkTaskAccess(withSelectors("team"), fts[0]); err == nil {
+		t.Error("expected the invalid selector rejected")
+	}
+}
@@ -14,35 +14,51 @@
 package engine
 
 import (
+	"bytes"
+	"encoding/hex"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
+	"google.golang.org/grpc"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/fabric"
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain/xchain"
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/monitor"
-	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/local"
-	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/xuperdb"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/spill"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
 	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/httputil"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 const (
 	// The number of executing task concurrently
 	DefaultTrainTaskLimit   = 100
 	DefaultPredictTaskLimit = 100
-	DefaultRpcTimeout       = 3
+	DefaultRpcTimeout       = 3 * time.Second
+	// The number of tasks waiting for free slots
+	DefaultQueueSize = 100
 
 	// Task default max execution time
 	DefaultMpcTaskMaxExecTime = time.Hour * 2
+	// Default upper bound of max execution time requested by task
+	DefaultMpcTaskMaxTimeout = time.Hour * 24
 	// Task loop default interval time
 	DefaultRequestInterval = time.Second * 10
+	// Default time to wait for tasks in execution on shutdown
+	DefaultShutdownTimeout = time.Minute
 )
 
 // initEngine initiates Engine
@@ -57,8 +73,22 @@ func initEngine(conf *config.ExecutorConf) (e *Engine, err error) {
 	if err != nil {
 		return e, err
 	}
+	shutdownTimeout := conf.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+	// observers only query the blockchain, they neither store samples or models nor execute tasks
+	if conf.Role == config.RoleObserver {
+		logger.Info("initiate engine of observer role successfully")
+		return &Engine{
+			node:            node,
+			chain:           chain,
+			observer:        true,
+			shutdownTimeout: shutdownTimeout,
+		}, nil
+	}
 	// get storage instance to save model or prediction result
-	storage, err := newStorage(conf.Storage)
+	storage, err := newStorage(conf.Storage, conf.Mpc.CheckpointInterval > 0)
 	if err != nil {
 		return e, err
 	}
@@ -67,29 +97,126 @@ func initEngine(conf *config.ExecutorConf) (e *Engine, err error) {
 	if err != nil {
 		return e, err
 	}
+	// the download of sample files is retried on network errors if it is configured
+	if d := conf.Storage.Download; d != nil {
+		download.MaxRetries = d.MaxRetries
+		download.RetryInterval = d.RetryInterval
+	}
+	// connections to other executors use TLS if it is configured
+	dialOpt, err := tlsutil.DialOption(conf.TLS)
+	if err != nil {
+		return e, err
+	}
+	// get audit logger to record tasks
+	audit, err := newAudit(conf.Audit, node, chain)
+	if err != nil {
+		return e, err
+	}
+	// buffer the status of tasks while the blockchain is unreachable
+	outbox := newOutbox(conf.Outbox)
+	// the identity before the key rotation must be rotated to the current one on blockchain
+	var graceUntil time.Time
+	if node.Previous != nil {
+		if graceUntil, err = checkKeyRotation(node, chain); err != nil {
+			audit.Close()
+			return e, err
+		}
+	}
 	// get MPC instance to handle tasks
-	mpcHandler, err := newMpc(conf.Mpc, node, storage, download, chain)
+	mpcHandler, err := newMpc(conf.Mpc, conf.Callback, node, storage, download, chain, dialOpt, audit, outbox)
 	if err != nil {
+		audit.Close()
 		return e, err
 	}
 	// get Monitor to handle loop request
-	taskMonitor, err := newMonitor(download.Type, node.PrivateKey, chain, mpcHandler)
+	taskMonitor, err := newMonitor(download.Type, node.PrivateKey, chain, mpcHandler, audit)
 	if err != nil {
+		audit.Close()
 		return e, err
 	}
+	taskMonitor.Previous, taskMonitor.GraceUntil = node.Previous, graceUntil
+	// tasks of requesters not in the allowlist are rejected
+	allowlist, err := handler.NewRequesterAllowlist(conf.RequesterAllowlist)
+	if err != nil {
+		audit.Close()
+		return e, err
+	}
+	taskMonitor.Allowlist = allowlist
 	logger.Info("initiate engine successfully")
 
 	return &Engine{
-		node:       node,
-		chain:      chain,
-		storage:    storage,
-		mpcHandler: mpcHandler,
-		monitor:    taskMonitor,
+		node:            node,
+		chain:           chain,
+		storage:         storage,
+		mpcHandler:      mpcHandler,
+		monitor:         taskMonitor,
+		janitor:         newJanitor(conf.Storage, storage.Names, chain, mpcHandler),
+		audit:           audit,
+		outbox:          outbox,
+		allowlist:       allowlist,
+		shutdownTimeout: shutdownTimeout,
 	}, nil
 }
 
-// newBlockchain initiates blockchain client
-func newBlockchain(conf *config.ExecutorBlockchainConf) (b handler.Blockchain, err error) {
+// newOutbox returns the outbox of task status updates, the defaults are used if it is not configured
+func newOutbox(conf *config.OutboxConf) *handler.StatusOutbox {
+	if conf == nil {
+		return handler.NewStatusOutbox(0, 0, 0)
+	}
+	return handler.NewStatusOutbox(conf.Size, conf.RetryInterval, conf.MaxRetryInterval)
+}
+
+// newAudit opens the audit log, nil if it is not configured. Records are anchored on chain if Anchor is set.
+func newAudit(conf *config.AuditConf, node handler.Node, chain handler.Blockchain) (*handler.AuditLogger, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if !conf.Anchor {
+		chain = nil
+	}
+	return handler.NewAuditLogger(conf.Path, node, chain)
+}
+
+// newJanitor returns the janitor removing local files of ended tasks, nil if the retention policy is not configured
+func newJanitor(conf *config.ExecutorStorageConf, names map[string]*file.NameTemplate, chain handler.Blockchain,
+	mpcHandler handler.MpcHandler) *handler.Janitor {
+	if conf.Retention == nil {
+		return nil
+	}
+	return &handler.Janitor{
+		Dirs:         storage.RetentionDirs(conf),
+		Names:        names,
+		MaxAge:       conf.Retention.MaxAge,
+		MaxTotalSize: int64(conf.Retention.MaxTotalSizeMB) << 20,
+		Interval:     conf.Retention.Interval,
+		GetTask:      chain.GetTaskById,
+		LocalStatus:  mpcHandler.GetLocalTaskStatus,
+	}
+}
+
+// newBlockchain initiates blockchain clients, failed calls of which are recorded into metrics, and calls are traced.
+//  A MultiChain is returned if the executor joins multiple networks, the network of [executor.blockchain] is the default one.
+func newBlockchain(conf *config.ExecutorBlockchainConf) (handler.Blockchain, error) {
+	b, err := newNetworkChain(conf)
+	if err != nil || len(conf.Networks) == 0 {
+		return b, err
+	}
+	networks := []handler.Network{{Name: conf.NetworkName(), Chain: b}}
+	for _, c := range conf.Networks {
+		nb, err := newNetworkChain(c)
+		if err != nil {
+			for _, n := range networks {
+				n.Chain.Close()
+			}
+			return nil, errorx.Wrap(err, "failed to initiate blockchain network %s", c.Name)
+		}
+		networks = append(networks, handler.Network{Name: c.Name, Chain: nb})
+	}
+	return handler.NewMultiChain(networks), nil
+}
+
+// newNetworkChain initiates the blockchain client of a network
+func newNetworkChain(conf *config.ExecutorBlockchainConf) (b handler.Blockchain, err error) {
 	switch conf.Type {
 	case "xchain":
 		b, err = xchain.New(conf.Xchain)
@@ -98,7 +225,10 @@ func newBlockchain(conf *config.ExecutorBlockchainConf) (b handler.Blockchain, e
 	default:
 		return b, errorx.New(errorx.ErrCodeConfig, "invalid blockchain type: %s", conf.Type)
 	}
-	return b, err
+	if err != nil {
+		return b, err
+	}
+	return handler.NewTracingChain(handler.NewMetricsChain(b)), nil
 }
 
 // newNode loads executor node account, which includes node name, private key, host address...
@@ -120,6 +250,20 @@ func newNode(conf *config.ExecutorConf) (node handler.Node, err error) {
 		PaddleFLAddress: conf.PaddleFLAddress,
 		PaddleFLRole:    conf.PaddleFLRole,
 	}
+	// the identity before the key rotation executes the tasks published to it until they end
+	if conf.PreviousPrivateKey != "" {
+		previousSk, err := ecdsa.DecodePrivateKeyFromString(conf.PreviousPrivateKey)
+		if err != nil {
+			return node, errorx.Wrap(err, "failed to decode the private key before the key rotation")
+		}
+		previousPk := ecdsa.PublicKeyFromPrivateKey(previousSk)
+		local.Previous = &peer.Local{
+			Address:    conf.PublicAddress,
+			ID:         previousPk[:],
+			Name:       conf.Name,
+			PrivateKey: previousSk,
+		}
+	}
 	// if conf.HttpServer.Switch is "on", setting HttpAddress
 	if conf.HttpServer.Switch == "on" {
 		local.HttpAddress = strings.TrimRight(conf.PublicAddress, conf.ListenAddress) + conf.HttpServer.HttpPort
@@ -127,61 +271,64 @@ func newNode(conf *config.ExecutorConf) (node handler.Node, err error) {
 	return local, nil
 }
 
-// newStorage initiates local storage, contains train-model and prediction-result storage
-func newStorage(conf *config.ExecutorStorageConf) (fileStroage handler.FileStorage, err error) {
-	// train-model could only be stored locally
-	mStorage, err := local.New(conf.LocalModelStoragePath)
+// checkKeyRotation checks the previous identity of node is rotated to its current identity on blockchain,
+// and returns the end of the grace period of the rotation
+func checkKeyRotation(node handler.Node, chain handler.Blockchain) (time.Time, error) {
+	previous, err := chain.GetExecutorNodeByID(hex.EncodeToString(node.Previous.ID))
 	if err != nil {
-		return fileStroage, errorx.New(errorx.ErrCodeConfig, "invalid model storage path：%s", err)
+		return time.Time{}, errorx.Wrap(err, "failed to get the executor node of the key before the key rotation")
+	}
+	if !bytes.Equal(previous.RotatedTo, node.ID) {
+		return time.Time{}, errorx.New(errorx.ErrCodeConfig, "the key under the previous directory of keyPath is not "+
+			"rotated to the current key on blockchain, run 'executor-cli rotatekey' again to complete the rotation")
 	}
+	graceUntil := time.Unix(0, previous.GraceUntil)
+	logger.Infof("key rotated from %x, tasks published to it are executed until they end, and confirmed until %s",
+		node.Previous.ID, graceUntil.Format(time.RFC3339))
+	return graceUntil, nil
+}
 
-	// evaluation result could only be stored locally
-	eStorage, err := local.New(conf.LocalEvaluationStoragePath)
+// newStorage initiates storage, contains train-model, evaluation-result and prediction-result storage,
+// and checkpoint storage if checkpoints are enabled, along with the templates of file names
+func newStorage(conf *config.ExecutorStorageConf, checkpoints bool) (fileStroage handler.FileStorage, err error) {
+	mStorage, err := storage.NewStorageBackend(conf, storage.KindModel)
 	if err != nil {
-		return fileStroage, errorx.New(errorx.ErrCodeConfig, "invalid evaluation result storage path：%s", err)
+		return fileStroage, err
+	}
+	eStorage, err := storage.NewStorageBackend(conf, storage.KindEvaluation)
+	if err != nil {
+		return fileStroage, err
 	}
-	// prediction-result could stored in local path or in xuperdb
-	pStroage, err := newPredictStorage(conf)
+	pStroage, err := storage.NewStorageBackend(conf, storage.KindPrediction)
+	if err != nil {
+		return fileStroage, err
+	}
+	names, err := storage.NameTemplates(conf)
 	if err != nil {
 		return fileStroage, err
 	}
 
+	// uploads and downloads are traced
 	fileStroage = handler.FileStorage{
-		ModelStorage:      mStorage,
-		EvaluationStorage: eStorage,
-		PredictStorage:    pStroage,
+		ModelStorage:      handler.NewTracingStorage(storage.KindModel, mStorage),
+		EvaluationStorage: handler.NewTracingStorage(storage.KindEvaluation, eStorage),
+		PredictStorage:    handler.NewTracingStorage(storage.KindPrediction, pStroage),
+		Names:             names,
 	}
-	return fileStroage, nil
-}
-
-// newPredictStorage initiates prediction result store client
-func newPredictStorage(conf *config.ExecutorStorageConf) (s handler.Storage, err error) {
-	switch conf.Type {
-	case "Local":
-		s, err = local.New(conf.Local.LocalPredictStoragePath)
-		if err != nil {
-			return s, errorx.New(errorx.ErrCodeConfig, "invalid prediction result-path：%s", err)
-		}
-	case "XuperDB":
-		// if conf.XuperDB.PrivateKey is empty, get the dataOwner client privateKey from conf.XuperDB.KeyPath
-		if conf.XuperDB.PrivateKey == "" {
-			privateKeyBytes, err := file.ReadFile(conf.XuperDB.KeyPath, file.PrivateKeyFileName)
-			if err == nil && len(privateKeyBytes) != 0 {
-				conf.XuperDB.PrivateKey = strings.TrimSpace(string(privateKeyBytes))
-			} else {
-				return s, errorx.New(errorx.ErrCodeConfig, "invalid xuperdb privateKey-path：%s", err)
-			}
-		}
-		privateKey, err := ecdsa.DecodePrivateKeyFromString(conf.XuperDB.PrivateKey)
+	// signatures are stored next to prediction results, except that XuperDB files are downloaded by the ids
+	// returned by uploads, so the signatures of the results stored in XuperDB are kept with evaluation results
+	fileStroage.SignatureStorage = fileStroage.PredictStorage
+	if conf.Type == "XuperDB" {
+		fileStroage.SignatureStorage = fileStroage.EvaluationStorage
+	}
+	if checkpoints {
+		cStorage, err := storage.NewStorageBackend(conf, storage.KindCheckpoint)
 		if err != nil {
-			return s, errorx.Wrap(err, "failed to decode xuperdb private key")
+			return fileStroage, err
 		}
-		// get XuperDB instance to upload and download files
-		s = xuperdb.New(conf.XuperDB.ExpireTime, conf.XuperDB.NameSpace, conf.XuperDB.Host, privateKey)
-	default:
-		return s, errorx.New(errorx.ErrCodeConfig, "invalid predict stroage type: %s", conf.Type)
+		fileStroage.CheckpointStorage = handler.NewTracingStorage(storage.KindCheckpoint, cStorage)
 	}
-	return s, nil
+	return fileStroage, nil
 }
 
 // newExecutionMode initiates sample file download client.
@@ -216,38 +363,81 @@ func newExecutionMode(conf *config.ExecutorModeConf, nodePrivateKey ecdsa.Privat
 	default:
 		return fileDownload, errorx.New(errorx.ErrCodeConfig, "invalid executor mode type: %s", conf.Type)
 	}
+	// download sample files over mutual TLS if it is configured, through the proxy if it is configured
+	if conf.TLS != nil {
+		fileDownload.Client, err = tlsutil.HTTPClient(conf.TLS, conf.Identities, conf.Proxy)
+		if err != nil {
+			return fileDownload, errorx.Wrap(err, "failed to load tls config of executor mode")
+		}
+	} else if conf.Proxy != nil {
+		fileDownload.Client, err = httputil.ProxyClient(conf.Proxy)
+		if err != nil {
+			return fileDownload, errorx.Wrap(err, "failed to load proxy config of executor mode")
+		}
+	}
 	fileDownload.NodePrivateKey = nodePrivateKey
 	return fileDownload, nil
 }
 
 // newMpc starts MPC handler to do MPC-Training and MPC-Prediction tasks
-func newMpc(conf *config.ExecutorMpcConf, node handler.Node, fstorage handler.FileStorage,
-	fdownload handler.FileDownload, chain handler.Blockchain) (handler.MpcHandler, error) {
+// dialOpt is the transport credentials used to connect to other executors, callbackConf is the policy of task callbacks,
+// audit records the terminal status of tasks if it is not nil
+func newMpc(conf *config.ExecutorMpcConf, callbackConf *config.CallbackConf, node handler.Node, fstorage handler.FileStorage,
+	fdownload handler.FileDownload, chain handler.Blockchain, dialOpt grpc.DialOption, audit *handler.AuditLogger,
+	outbox *handler.StatusOutbox) (handler.MpcHandler, error) {
 
-	rpcTimeout := time.Duration(conf.RpcTimeout)
-	if rpcTimeout == 0 {
-		rpcTimeout = DefaultRpcTimeout
+	rpcTimeout, taskLimitTime, maxTaskLimitTime := mpcTimeouts(conf)
+	queueSize := conf.QueueSize
+	if queueSize == 0 {
+		queueSize = DefaultQueueSize
 	}
-	taskLimitTime := time.Duration(conf.TaskLimitTime) * time.Second
-	if taskLimitTime == 0 {
-		taskLimitTime = DefaultMpcTaskMaxExecTime
+	defaultAccuracy := int64(conf.DefaultAccuracy)
+	if defaultAccuracy == 0 {
+		defaultAccuracy = vl_common.DefaultAccuracy
+	}
+	fdownload.MaxFileSize = int64(conf.MaxSampleFileSizeMB) << 20
+	vl_common.SetPSIWorkers(conf.PSIWorkers)
+	vl_common.SetKernelWorkers(conf.KernelWorkers)
+	// the cache only saves the time of PSI, tasks run PSI every time without it
+	if err := psi.SetAlignmentCache(conf.PSICacheTTL, conf.PSICacheEntries); err != nil {
+		logger.WithError(err).Warn("failed to set the alignment cache, PSI alignments are not cached")
 	}
+	spill.SetSpill(conf.SpillDir, conf.SpillMemoryThresholdMB)
 	mpcHandler := &handler.MpcModelHandler{
 		Config: mpc.Config{
-			Address:          node.Address,
-			TrainTaskLimit:   conf.TrainTaskLimit,
-			PredictTaskLimit: conf.PredictTaskLimit,
-			RpcTimeout:       rpcTimeout,
+			Address:            node.Address,
+			TrainTaskLimit:     conf.TrainTaskLimit,
+			PredictTaskLimit:   conf.PredictTaskLimit,
+			RpcTimeout:         rpcTimeout,
+			CheckpointInterval: conf.CheckpointInterval,
 		},
 		Storage:            fstorage,
 		Download:           fdownload,
 		Node:               node,
 		Chain:              chain,
 		MpcTaskMaxExecTime: taskLimitTime,
+		MpcTaskMaxTimeout:  maxTaskLimitTime,
+		Resource:           resourceLimits(conf),
+		Queue:              handler.NewTaskQueue(queueSize, int32(conf.DefaultPriority)),
+		PSIAlgorithm:       conf.PSIAlgorithm,
+		DefaultAccuracy:    defaultAccuracy,
+		MaxFeatures:        conf.MaxFeatures,
+		LiveEvaluation:     handler.NewLiveEvaluationHub(handler.DefaultLiveEvaluationBuffer),
+		Callback:           handler.NewCallbackNotifier(callbackPolicy(callbackConf), node),
+		Audit:              audit,
+		Breaker:            handler.NewPeerBreaker(conf.BreakerThreshold, conf.BreakerWindow, conf.BreakerCooldown),
+		Outbox:             outbox,
 		MpcTasks:           make(map[string]*handler.FlTask),
 	}
 
-	clusterP2p := p2p.NewP2P()
+	metrics.SetTaskLimits(conf.TrainTaskLimit, conf.PredictTaskLimit)
+	metrics.SetPeakWindow(conf.PeakWindow)
+
+	// the trace context of tasks is propagated to other executors
+	dialOpts := append([]grpc.DialOption{dialOpt, grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor())},
+		p2p.CompressionDialOptions(conf.Compression)...)
+	dialOpts = append(dialOpts, p2p.NewTransport(conf).DialOptions()...)
+	clusterP2p := p2p.NewP2PWithDialOptions(dialOpts)
 	mpcServer := mpc.StartMpc(mpcHandler, clusterP2p, mpcHandler.Config)
 	mpcHandler.Mpc = mpcServer
 	mpcHandler.ClusterP2p = clusterP2p
@@ -255,10 +445,60 @@ func newMpc(conf *config.ExecutorMpcConf, node handler.Node, fstorage handler.Fi
 	return mpcHandler, nil
 }
 
+// callbackPolicy returns the policy of task callbacks, the defaults are used if it is not configured
+func callbackPolicy(conf *config.CallbackConf) handler.CallbackPolicy {
+	if conf == nil {
+		return handler.CallbackPolicy{}
+	}
+	return handler.CallbackPolicy{
+		Secret:        conf.Secret,
+		MaxRetries:    conf.MaxRetries,
+		RetryInterval: conf.RetryInterval,
+		Timeout:       conf.Timeout,
+	}
+}
+
+// mpcTimeouts returns the rpc timeout, the maximum execution time of mpc tasks and the upper bound of
+// the ones requested by tasks, the defaults are used if they are not configured,
+// and the upper bound is no less than the maximum execution time
+func mpcTimeouts(conf *config.ExecutorMpcConf) (rpcTimeout, taskLimitTime, maxTaskLimitTime time.Duration) {
+	rpcTimeout = conf.RpcTimeout
+	if rpcTimeout == 0 {
+		rpcTimeout = DefaultRpcTimeout
+	}
+	taskLimitTime = conf.TaskLimitTime
+	if taskLimitTime == 0 {
+		taskLimitTime = DefaultMpcTaskMaxExecTime
+	}
+	maxTaskLimitTime = conf.MaxTaskLimitTime
+	if maxTaskLimitTime == 0 {
+		maxTaskLimitTime = DefaultMpcTaskMaxTimeout
+	}
+	if maxTaskLimitTime < taskLimitTime {
+		maxTaskLimitTime = taskLimitTime
+	}
+	return rpcTimeout, taskLimitTime, maxTaskLimitTime
+}
+
+// resourceLimits returns the resources reserved by a task and the budget of the node,
+// the cpu budget is the number of cpus if it is not configured
+func resourceLimits(conf *config.ExecutorMpcConf) handler.ResourceLimits {
+	limits := handler.ResourceLimits{
+		MaxMemoryMB:  conf.MaxMemoryMB,
+		MaxCPUCores:  conf.MaxCPUCores,
+		NodeMemoryMB: conf.NodeMemoryMB,
+		NodeCPUCores: conf.NodeCPUCores,
+	}
+	if limits.NodeCPUCores == 0 {
+		limits.NodeCPUCores = runtime.NumCPU()
+	}
+	return limits
+}
+
 // newMonitor returns Monitor whose works are mainly monitoring status of tasks
 // and starting Mpc-Training and Mpc-Prediction tasks
 func newMonitor(fileDownloadType string, privateKey ecdsa.PrivateKey, chain handler.Blockchain,
-	mpcHandler handler.MpcHandler) (*monitor.TaskMonitor, error) {
+	mpcHandler handler.MpcHandler, audit *handler.AuditLogger) (*monitor.TaskMonitor, error) {
 	pubkey := ecdsa.PublicKeyFromPrivateKey(privateKey)
 	return &monitor.TaskMonitor{
 		ExecutionType:   fileDownloadType,
@@ -268,5 +508,6 @@ func newMonitor(fileDownloadType string, privateKey ecdsa.PrivateKey, chain hand
 
 		Blockchain: chain,
 		MpcHandler: mpcHandler,
+		Audit:      audit,
 	}, nil
 }
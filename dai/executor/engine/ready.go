@@ -0,0 +1,112 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
+)
+
+// readyCacheTime is how long the result of a readiness check is reused,
+// so that frequent probes do not query the blockchain and storage every time
+const readyCacheTime = 5 * time.Second
+
+// readiness caches the result of the last readiness check
+type readiness struct {
+	sync.Mutex
+	checkedAt time.Time
+	err       error
+	shutdown  bool // set by Shutdown, then the node is never ready
+}
+
+// Ready checks whether the node is ready to execute tasks, that is, the private key is loaded,
+// the blockchain is reachable and the node is registered, no task status is buffered in an outage of the blockchain,
+// and all storage backends are reachable.
+// Observers are ready once the private key is loaded and the blockchain is reachable.
+// The result is cached for readyCacheTime, and the node is not ready once it starts shutting down.
+func (e *Engine) Ready(ctx context.Context) error {
+	e.ready.Lock()
+	defer e.ready.Unlock()
+	if e.ready.shutdown {
+		return errorx.New(errcodes.ErrCodeShuttingDown, "executor is shutting down")
+	}
+	if !e.ready.checkedAt.IsZero() && time.Since(e.ready.checkedAt) < readyCacheTime {
+		return e.ready.err
+	}
+	e.ready.err = e.checkReady(ctx)
+	e.ready.checkedAt = time.Now()
+	return e.ready.err
+}
+
+// ReadyDetails returns the state of the connection to the blockchain, which is reported by '/readyz'
+func (e *Engine) ReadyDetails() interface{} {
+	return struct {
+		Blockchain handler.ChainState `json:"blockchain"`
+	}{Blockchain: e.outbox.State()}
+}
+
+// setShutdown marks the node not ready
+func (e *Engine) setShutdown() {
+	e.ready.Lock()
+	defer e.ready.Unlock()
+	e.ready.shutdown = true
+}
+
+// checkReady checks the private key, blockchain and storage backends
+func (e *Engine) checkReady(ctx context.Context) error {
+	if err := verifyUserID(e.node.ID, e.node.PrivateKey); err != nil {
+		return errorx.Wrap(err, "private key not loaded")
+	}
+	if e.observer {
+		if _, err := e.chain.ListExecutorNodes(); err != nil {
+			return errorx.Wrap(err, "failed to list nodes from blockchain")
+		}
+		return nil
+	}
+	// the status of tasks buffered in the outage is not recorded yet, even if the blockchain is reachable again
+	if state := e.outbox.State(); !state.Connected {
+		return errorx.New(errcodes.ErrCodeChainDisconnected, "blockchain disconnected since %s, %d task status updates buffered: %s",
+			time.Unix(0, state.DisconnectedSince).Format(time.RFC3339), state.PendingUpdates, state.LastError)
+	}
+	// the node is registered by Start, so it is not ready before that
+	if multi, ok := e.chain.(*handler.MultiChain); ok {
+		for _, network := range multi.Networks() {
+			chain, _ := multi.Network(network)
+			if _, err := chain.GetExecutorNodeByID(hex.EncodeToString(e.node.ID)); err != nil {
+				return errorx.Wrap(err, "failed to get local node from blockchain network %s", network)
+			}
+		}
+	} else if _, err := e.chain.GetExecutorNodeByID(hex.EncodeToString(e.node.ID)); err != nil {
+		return errorx.Wrap(err, "failed to get local node from blockchain")
+	}
+	backends := map[string]storage.StorageBackend{
+		storage.KindModel:      e.storage.ModelStorage,
+		storage.KindEvaluation: e.storage.EvaluationStorage,
+		storage.KindPrediction: e.storage.PredictStorage,
+	}
+	for kind, backend := range backends {
+		if err := storage.Check(ctx, backend); err != nil {
+			return errorx.Wrap(err, "storage of %s not reachable", kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// Sample alignments, the intersections of PSI, are cached across tasks to skip PSI of later tasks over the same samples.
+// Both Executors of a task must agree on reusing an alignment, so it's decided when the task starts:
+//  1. the initiator offers the keys of its cached alignments for its samples in the fingerprint of the task
+//  2. the other Executor computes the key of the alignment of both parties' samples, it reuses the alignment
+//     if the key is offered and cached locally as well, and replies with the key and its decision
+//  3. if the alignment is reused, both Executors keep only the aligned samples and run PSI by psi.AlgorithmCached,
+//     which checks they keep the same samples, otherwise the intersection of PSI is cached by the key
+
+// alignmentAlgorithms are the algorithms whose learners cache sample alignments
+var alignmentAlgorithms = map[pbCom.Algorithm]bool{
+	pbCom.Algorithm_LINEAR_REGRESSION_VL: true,
+	pbCom.Algorithm_LOGIC_REGRESSION_VL:  true,
+	pbCom.Algorithm_XGBOOST_VL:           true,
+}
+
+// alignmentCacheable returns whether the alignment of the samples of the training task of two parties may be cached
+func alignmentCacheable(startRequest *pbCom.StartTaskRequest) bool {
+	params := startRequest.GetParams()
+	return psi.AlignmentCacheEnabled() && params.GetTaskType() == pbCom.TaskType_LEARN &&
+		alignmentAlgorithms[params.GetAlgo()] && len(startRequest.Hosts) == 1 && len(startRequest.File) > 0
+}
+
+// offerAlignments sets the digest of the local samples and the keys of the cached alignments for them
+// into the fingerprint of the task, and returns the alignments by their keys. It's called by the initiator.
+func (m *MpcModelHandler) offerAlignments(startRequest *pbCom.StartTaskRequest, fingerprint *pbTask.TaskFingerprint) map[string][]string {
+	if !alignmentCacheable(startRequest) {
+		return nil
+	}
+	digest := psi.SamplesDigest(startRequest.File, startRequest.Params.TrainParams.IdName)
+	alignments := psi.CachedAlignments(fingerprint.PsiAlgorithm, m.Config.Address, digest, startRequest.Hosts)
+	fingerprint.SamplesDigest = digest
+	for key := range alignments {
+		fingerprint.AlignmentKeys = append(fingerprint.AlignmentKeys, key)
+	}
+	return alignments
+}
+
+// PrepareAlignment decides whether the task reuses the cached alignment with the initiator party, by the fingerprint
+// of the task from it, before the task started by startRequest begins computation. startRequest is updated
+// to reuse the alignment or to cache the intersection of PSI, and the key of the alignment is returned
+// with the decision to reply to the initiator, the key is empty if the alignment is not cached at all.
+func (m *MpcModelHandler) PrepareAlignment(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint, party string) (string, bool) {
+	if remote.GetSamplesDigest() == "" || !alignmentCacheable(startRequest) {
+		return "", false
+	}
+	trainParams := startRequest.Params.TrainParams
+	key := psi.AlignmentKey(remote.PsiAlgorithm, map[string]string{
+		party:            remote.SamplesDigest,
+		m.Config.Address: psi.SamplesDigest(startRequest.File, trainParams.IdName),
+	})
+
+	offered := false
+	for _, k := range remote.AlignmentKeys {
+		offered = offered || k == key
+	}
+	if ids, ok := psi.LoadAlignment(m.Config.Address, key); ok && offered {
+		err := reuseAlignment(startRequest, ids)
+		if err == nil {
+			return key, true
+		}
+		logger.WithField(logging.TaskIDKey, startRequest.TaskID).WithError(err).Warn("failed to reuse cached sample alignment, run PSI")
+	}
+	trainParams.AlignmentKey = key
+	return key, false
+}
+
+// applyAlignment updates startRequest by the decision of the other Executor on reusing the cached alignment,
+// alignments are the ones offered by offerAlignments. It's called by the initiator.
+func applyAlignment(startRequest *pbCom.StartTaskRequest, resp *pbTask.TaskResponse, alignments map[string][]string) error {
+	key := resp.GetAlignmentKey()
+	if key == "" || alignments == nil {
+		return nil
+	}
+	if !resp.GetAlignmentCached() {
+		startRequest.Params.TrainParams.AlignmentKey = key
+		return nil
+	}
+	ids, ok := alignments[key]
+	if !ok {
+		return errorx.New(errcodes.ErrCodeInternal, "sample alignment %s reused by the other party is not offered", key)
+	}
+	if err := reuseAlignment(startRequest, ids); err != nil {
+		return errorx.New(errcodes.ErrCodePSISamplesFile, "failed to reuse cached sample alignment: %s", err.Error())
+	}
+	return nil
+}
+
+// reuseAlignment keeps only the samples in the cached alignment ids, which are aligned by psi.AlgorithmCached
+func reuseAlignment(startRequest *pbCom.StartTaskRequest, ids []string) error {
+	trainParams := startRequest.Params.TrainParams
+	file, err := csv.FilterRows(startRequest.File, trainParams.IdName, ids)
+	if err != nil {
+		return err
+	}
+	startRequest.File = file
+	trainParams.PsiAlgorithm = psi.AlgorithmCached
+	trainParams.AlignmentKey = ""
+	logger.WithField(logging.TaskIDKey, startRequest.TaskID).Infof("reuse cached alignment of %d samples, skip PSI", len(ids))
+	return nil
+}
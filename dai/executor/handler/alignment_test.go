@@ -0,0 +1,116 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func TestAlignmentHandshake(t *testing.T) {
+	defer psi.SetAlignmentCache(0, 0)
+	if err := psi.SetAlignmentCache(time.Hour, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	initiator := &MpcModelHandler{Config: mpc.Config{Address: "127.0.0.1:8184"}}
+	receiver := &MpcModelHandler{Config: mpc.Config{Address: "127.0.0.1:8185"}}
+	fileA := []byte("id,x\n1,1\n2,2\n3,3\n4,4\n")
+	fileB := []byte("y,id\n5,5\n3,3\n2,2\n")
+	newRequest := func(file []byte, host string) *pbCom.StartTaskRequest {
+		return &pbCom.StartTaskRequest{
+			TaskID: "train-1",
+			File:   file,
+			Hosts:  []string{host},
+			Params: &pbCom.TaskParams{
+				Algo:        pbCom.Algorithm_LINEAR_REGRESSION_VL,
+				TaskType:    pbCom.TaskType_LEARN,
+				TrainParams: &pbCom.TrainParams{IdName: "id"},
+			},
+		}
+	}
+	// start starts the task on both executors, and returns the requests to start their learners
+	start := func(fileB []byte) (*pbCom.StartTaskRequest, *pbCom.StartTaskRequest, bool) {
+		reqA, reqB := newRequest(fileA, receiver.Config.Address), newRequest(fileB, initiator.Config.Address)
+		fingerprint, err := taskFingerprint(reqA)
+		checkErr(t, err)
+		alignments := initiator.offerAlignments(reqA, fingerprint)
+		key, cached := receiver.PrepareAlignment(reqB, fingerprint, initiator.Config.Address)
+		checkErr(t, applyAlignment(reqA, &pbTask.TaskResponse{AlignmentKey: key, AlignmentCached: cached}, alignments))
+		return reqA, reqB, cached
+	}
+
+	// the first task runs PSI, and caches the intersection by the same key on both executors
+	reqA, reqB, cached := start(fileB)
+	key := reqA.Params.TrainParams.AlignmentKey
+	if cached || key == "" || reqB.Params.TrainParams.AlignmentKey != key {
+		t.Fatalf("expected PSI cached by the same key, got %s and %s", key, reqB.Params.TrainParams.AlignmentKey)
+	}
+	runPSI(t, reqA, reqB)
+
+	// the second task over the same samples reuses the alignment
+	reqA, reqB, cached = start(fileB)
+	if !cached || reqA.Params.TrainParams.PsiAlgorithm != psi.AlgorithmCached || reqB.Params.TrainParams.PsiAlgorithm != psi.AlgorithmCached {
+		t.Fatal("expected cached alignment reused by both executors")
+	}
+	if string(reqA.File) != "id,x\n2,2\n3,3\n" || string(reqB.File) != "y,id\n3,3\n2,2\n" {
+		t.Errorf("unexpected aligned samples %q and %q", reqA.File, reqB.File)
+	}
+
+	// the alignment is not reused once the samples change
+	reqA, reqB, cached = start([]byte("y,id\n5,5\n3,3\n2,2\n1,1\n"))
+	if newKey := reqA.Params.TrainParams.AlignmentKey; cached || newKey == "" || newKey == key || reqB.Params.TrainParams.PsiAlgorithm == psi.AlgorithmCached {
+		t.Error("expected PSI of the changed samples")
+	}
+
+	// the receiver doesn't cache alignments for the initiators not offering any
+	if key, cached := receiver.PrepareAlignment(newRequest(fileB, initiator.Config.Address), &pbTask.TaskFingerprint{}, initiator.Config.Address); key != "" || cached {
+		t.Error("expected no alignment without the digest of the initiator")
+	}
+}
+
+// runPSI runs PSI of the learners started by reqA and reqB
+func runPSI(t *testing.T, reqA, reqB *pbCom.StartTaskRequest) {
+	newPSI := func(req *pbCom.StartTaskRequest, name string) psi.VLPSI {
+		params := req.Params.TrainParams
+		p, err := psi.NewVLTwoPartsPSIWithAlignment(params.PsiAlgorithm, params.AlignmentKey, name, req.File, params.IdName, req.Hosts)
+		checkErr(t, err)
+		return p
+	}
+	vpA, vpB := newPSI(reqA, reqB.Hosts[0]), newPSI(reqB, reqA.Hosts[0])
+	encA, err := vpA.EncryptSampleIDSet()
+	checkErr(t, err)
+	encB, err := vpB.EncryptSampleIDSet()
+	checkErr(t, err)
+	reEncA, err := vpB.ReEncryptIDSet(reqB.Hosts[0], encA)
+	checkErr(t, err)
+	reEncB, err := vpA.ReEncryptIDSet(reqA.Hosts[0], encB)
+	checkErr(t, err)
+	_, err = vpA.SetReEncryptIDSet(reqA.Hosts[0], reEncA)
+	checkErr(t, err)
+	_, err = vpB.SetReEncryptIDSet(reqB.Hosts[0], reEncB)
+	checkErr(t, err)
+	checkErr(t, vpA.SetOtherFinalReEncryptIDSet(reqA.Hosts[0], reEncB))
+	checkErr(t, vpB.SetOtherFinalReEncryptIDSet(reqB.Hosts[0], reEncA))
+	for _, vp := range []psi.VLPSI{vpA, vpB} {
+		if done, _, _, err := vp.IntersectParts(); !done || err != nil {
+			t.Fatalf("PSI not done: %v", err)
+		}
+	}
+}
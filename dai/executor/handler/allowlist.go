@@ -0,0 +1,131 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// RequesterAllowlist is the set of requesters allowed to use the executor, identified by their public keys.
+// A nil RequesterAllowlist allows all requesters.
+type RequesterAllowlist struct {
+	lock       sync.RWMutex
+	conf       config.RequesterAllowlistConf
+	requesters map[ecdsa.PublicKey]bool
+	watcher    *file.Watcher // watches the file of conf
+}
+
+// NewRequesterAllowlist loads the allowlist and watches its file, it returns nil if conf is nil
+func NewRequesterAllowlist(conf *config.RequesterAllowlistConf) (*RequesterAllowlist, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	a := &RequesterAllowlist{}
+	a.watcher = file.NewWatcher(func() error {
+		a.lock.RLock()
+		conf := a.conf
+		a.lock.RUnlock()
+		return a.Reload(&conf)
+	})
+	if err := a.Reload(conf); err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload replaces the requesters with the ones of conf, and watches the file of conf.
+// The current requesters are kept if the file can not be read.
+func (a *RequesterAllowlist) Reload(conf *config.RequesterAllowlistConf) error {
+	if a == nil || conf == nil {
+		return nil
+	}
+	requesters := make(map[ecdsa.PublicKey]bool)
+	for _, r := range conf.Requesters {
+		if err := addRequester(requesters, r); err != nil {
+			return err
+		}
+	}
+	if conf.File != "" {
+		if err := readAllowlistFile(conf.File, requesters); err != nil {
+			return err
+		}
+	}
+	a.lock.Lock()
+	a.conf = *conf
+	a.requesters = requesters
+	a.lock.Unlock()
+	logger.Infof("requester allowlist loaded, %d requesters allowed", len(requesters))
+
+	return a.watcher.Watch(conf.File)
+}
+
+// Allowed returns whether the requester with the public key pubkey is allowed
+func (a *RequesterAllowlist) Allowed(pubkey []byte) bool {
+	if a == nil {
+		return true
+	}
+	if len(pubkey) != ecdsa.PublicKeyLength {
+		return false
+	}
+	var pk ecdsa.PublicKey
+	copy(pk[:], pubkey)
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.requesters[pk]
+}
+
+// Check returns ErrCodeRequesterNotAllowed if the requester with the public key pubkey is not allowed
+func (a *RequesterAllowlist) Check(pubkey []byte) error {
+	if !a.Allowed(pubkey) {
+		return errorx.New(errcodes.ErrCodeRequesterNotAllowed, "forbidden: requester %x is not in the allowlist", pubkey)
+	}
+	return nil
+}
+
+// Close stops watching the allowlist file
+func (a *RequesterAllowlist) Close() {
+	if a == nil {
+		return
+	}
+	a.watcher.Close()
+}
+
+// readAllowlistFile adds the public keys in path to requesters, one public key per line,
+// empty lines and lines starting with '#' are ignored
+func readAllowlistFile(path string, requesters map[ecdsa.PublicKey]bool) error {
+	return file.ReadLines(path, func(line string) error {
+		if err := addRequester(requesters, line); err != nil {
+			return errorx.Wrap(err, "invalid public key in the allowlist file %s", path)
+		}
+		return nil
+	})
+}
+
+// addRequester adds the public key in hex to requesters
+func addRequester(requesters map[ecdsa.PublicKey]bool, pubkey string) error {
+	pk, err := ecdsa.DecodePublicKeyFromString(pubkey)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeConfig, "invalid public key of requester %s", pubkey)
+	}
+	requesters[pk] = true
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+func TestRequesterAllowlist(t *testing.T) {
+	var pks []ecdsa.PublicKey
+	for i := 0; i < 3; i++ {
+		_, pk, err := ecdsa.GenerateKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks = append(pks, pk)
+	}
+	// a nil allowlist allows all requesters
+	var all *RequesterAllowlist
+	if err := all.Check(pks[0][:]); err != nil {
+		t.Errorf("expected all requesters allowed without an allowlist, got %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "requesters")
+	if err := ioutil.WriteFile(file, []byte("# partner B\n"+pks[1].String()+"\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.RequesterAllowlistConf{Requesters: []string{pks[0].String()}, File: file}
+	a, err := NewRequesterAllowlist(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	for _, pk := range pks[:2] {
+		if err := a.Check(pk[:]); err != nil {
+			t.Errorf("expected requester %s allowed, got %v", pk.String(), err)
+		}
+	}
+	if err := a.Check(pks[2][:]); !errorx.Is(err, errcodes.ErrCodeRequesterNotAllowed) {
+		t.Errorf("expected requester not in the allowlist rejected, got %v", err)
+	}
+	if a.Allowed(nil) || a.Allowed([]byte("short")) {
+		t.Error("expected invalid public keys rejected")
+	}
+
+	// replace the requester in the file, the file is reloaded without restarting
+	if err := ioutil.WriteFile(file, []byte(pks[2].String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !a.Allowed(pks[2][:]) || a.Allowed(pks[1][:]) {
+		if time.Now().After(deadline) {
+			t.Fatal("allowlist file not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// an invalid file keeps the current requesters
+	if err := a.Reload(&config.RequesterAllowlistConf{File: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("expected error if the allowlist file is missing")
+	}
+	if !a.Allowed(pks[0][:]) {
+		t.Error("expected the current requesters kept")
+	}
+	// remove requesters by reloading the config
+	if err := a.Reload(&config.RequesterAllowlistConf{Requesters: []string{pks[1].String()}}); err != nil {
+		t.Fatal(err)
+	}
+	if a.Allowed(pks[0][:]) || a.Allowed(pks[2][:]) || !a.Allowed(pks[1][:]) {
+		t.Error("expected only the requesters of the reloaded config allowed")
+	}
+}
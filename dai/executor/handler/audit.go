@@ -0,0 +1,234 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// events of audit records
+const (
+	// AuditEventConfirm is recorded when the executor confirms or rejects a task submitted by a requester
+	AuditEventConfirm = "confirm"
+	// AuditEventEnd is recorded when the executor sees the terminal status of a task it executed
+	AuditEventEnd = "end"
+)
+
+// results of AuditEventConfirm records
+const (
+	AuditResultConfirmed = "Confirmed"
+	AuditResultRejected  = "Rejected"
+)
+
+// AuditRecord is a line of the audit log. Each record carries the hash of the previous one,
+// so that removing or modifying a record breaks the chain of hashes of the following records.
+type AuditRecord struct {
+	Time       int64  `json:"time"`      // in nanoseconds
+	Executor   string `json:"executor"`  // name of the executor writing the record
+	Event      string `json:"event"`     // "confirm" or "end"
+	Requester  string `json:"requester"` // hex encoded public key of the requester publishing the task
+	TaskID     string `json:"taskID"`
+	TaskType   string `json:"taskType"`
+	Algorithm  string `json:"algorithm"`
+	ParamsHash string `json:"paramsHash"`        // hex encoded SHA256 of the JSON of the task parameters
+	Result     string `json:"result"`            // Confirmed or Rejected for "confirm", the terminal status for "end"
+	Message    string `json:"message,omitempty"` // reason of the rejection or the failure
+	PrevHash   string `json:"prevHash"`          // hex encoded SHA256 of the previous line, empty for the first record
+}
+
+// AuditLogger appends audit records of tasks to a dedicated file as JSON lines. The file is opened in append-only
+// mode and is never rotated, truncated or overwritten. If the chain is set, the hash of each record is anchored
+// on the blockchain by the executor in background, which proves the record existed since then.
+type AuditLogger struct {
+	file     *os.File
+	lastHash string
+	node     Node
+	chain    Blockchain
+
+	anchoring sync.WaitGroup
+	sync.Mutex
+}
+
+// NewAuditLogger opens the audit log at path, creating it if it doesn't exist, and continues the chain of hashes
+// from its last line. Records are anchored on chain if it is not nil.
+func NewAuditLogger(path string, node Node, chain Blockchain) (*AuditLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errorx.Internal(err, "failed to create directory of audit log")
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to open audit log")
+	}
+	last, complete, err := lastLine(file)
+	if err != nil {
+		file.Close()
+		return nil, errorx.Internal(err, "failed to read audit log")
+	}
+	// a record partially written before a crash is kept as it is, and the next one starts on a new line
+	if !complete {
+		if _, err := file.Write([]byte("\n")); err != nil {
+			file.Close()
+			return nil, errorx.Internal(err, "failed to write audit log")
+		}
+	}
+	a := &AuditLogger{file: file, node: node, chain: chain}
+	if len(last) > 0 {
+		a.lastHash = hashLine(last)
+	}
+	return a, nil
+}
+
+// Record appends a record of event of task, it is a no-op if a is nil. Failures are logged but never fail tasks.
+func (a *AuditLogger) Record(task blockchain.FLTask, event, result, message string) {
+	if a == nil {
+		return
+	}
+	record := AuditRecord{
+		Time:      time.Now().UnixNano(),
+		Executor:  a.node.Name,
+		Event:     event,
+		Requester: hex.EncodeToString(task.Requester),
+		TaskID:    task.TaskID,
+		Result:    result,
+		Message:   message,
+	}
+	if task.AlgoParam != nil {
+		record.TaskType = blockchain.TaskTypeListValue[task.AlgoParam.TaskType]
+		record.Algorithm = blockchain.VlAlgorithmListValue[task.AlgoParam.Algo]
+		if params, err := json.Marshal(task.AlgoParam); err == nil {
+			sum := sha256.Sum256(params)
+			record.ParamsHash = hex.EncodeToString(sum[:])
+		}
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	record.PrevHash = a.lastHash
+	line, err := json.Marshal(record)
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to marshal audit record")
+		return
+	}
+	// the record is synced to disk before the task goes on
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to write audit record")
+		return
+	}
+	if err := a.file.Sync(); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to sync audit log")
+	}
+	a.lastHash = hashLine(line)
+
+	if a.chain != nil {
+		a.anchoring.Add(1)
+		go func(h string) {
+			defer a.anchoring.Done()
+			if err := a.anchor(h); err != nil {
+				logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warnf("failed to anchor audit record %s", h)
+			}
+		}(a.lastHash)
+	}
+}
+
+// anchor anchors the hex encoded hash of a record on the blockchain, signed by the executor
+func (a *AuditLogger) anchor(h string) error {
+	digest, err := hex.DecodeString(h)
+	if err != nil {
+		return errorx.Internal(err, "invalid hash")
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(a.node.PrivateKey)
+	opt := &blockchain.AnchorAuditOptions{
+		Executor:    pubkey[:],
+		Hash:        digest,
+		CurrentTime: time.Now().UnixNano(),
+	}
+	msg, err := util.GetSigMessage(opt)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for anchoring audit record")
+	}
+	sig, err := ecdsa.Sign(a.node.PrivateKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign audit anchor")
+	}
+	opt.Signature = sig[:]
+	return a.chain.AnchorAudit(opt)
+}
+
+// Close waits for the anchors in progress and closes the audit log
+func (a *AuditLogger) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.anchoring.Wait()
+	a.Lock()
+	defer a.Unlock()
+	return a.file.Close()
+}
+
+// hashLine returns the hex encoded SHA256 of a line of the audit log without the line break
+func hashLine(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// lastLine returns the last line of file without the line break, and whether the file ends with a line break,
+// which is true for an empty file. The file is read backwards, so that the audit log can grow large.
+func lastLine(file *os.File) ([]byte, bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, true, nil
+	}
+
+	const chunkSize = 4096
+	var tail []byte
+	for offset := size; offset > 0; {
+		n := int64(chunkSize)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		tail = append(chunk, tail...)
+		// the line break ending the file doesn't count
+		if i := bytes.LastIndexByte(bytes.TrimSuffix(tail, []byte("\n")), '\n'); i >= 0 {
+			tail = tail[i+1:]
+			break
+		}
+	}
+	complete := bytes.HasSuffix(tail, []byte("\n"))
+	return bytes.TrimSuffix(tail, []byte("\n")), complete, nil
+}
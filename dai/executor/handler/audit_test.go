@@ -0,0 +1,119 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// anchorChain records the hashes anchored
+type anchorChain struct {
+	Blockchain
+	hashes []string
+	sync.Mutex
+}
+
+func (c *anchorChain) AnchorAudit(opt *blockchain.AnchorAuditOptions) error {
+	c.Lock()
+	defer c.Unlock()
+	c.hashes = append(c.hashes, hex.EncodeToString(opt.Hash))
+	return nil
+}
+
+func TestAuditLogger(t *testing.T) {
+	privateKey, _, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := Node{Local: peer.Local{Name: "executor1", PrivateKey: privateKey}}
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	task := &pbTask.FLTask{
+		TaskID:    "task1",
+		Requester: []byte{1, 2, 3},
+		AlgoParam: &pbCom.TaskParams{TaskType: pbCom.TaskType_LEARN, Algo: pbCom.Algorithm_LOGIC_REGRESSION_VL},
+	}
+
+	chain := &anchorChain{}
+	a, err := NewAuditLogger(path, node, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Record(task, AuditEventConfirm, AuditResultConfirmed, "")
+	a.Record(task, AuditEventEnd, blockchain.TaskFailed, "psi failed")
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a partially written record is kept, and the audit log is continued after it when reopened
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`{"time":1`))
+	f.Close()
+	a, err = NewAuditLogger(path, node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Record(task, AuditEventEnd, blockchain.TaskFinished, "")
+	a.Close()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines in audit log, got %d", len(lines))
+	}
+	var records []AuditRecord
+	for _, i := range []int{0, 1, 3} {
+		var r AuditRecord
+		if err := json.Unmarshal(lines[i], &r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if r := records[1]; r.Executor != "executor1" || r.Requester != "010203" || r.TaskType != blockchain.TaskTypeTrain ||
+		r.Algorithm != blockchain.AlgorithmVLog || r.Result != blockchain.TaskFailed || r.Message != "psi failed" || r.ParamsHash == "" {
+		t.Errorf("unexpected audit record %+v", r)
+	}
+	if records[0].PrevHash != "" || records[1].PrevHash != hashLine(lines[0]) || records[2].PrevHash != hashLine(lines[2]) {
+		t.Error("expected each record to carry the hash of the previous line")
+	}
+
+	// only the records written with a chain are anchored
+	if len(chain.hashes) != 2 || !(chain.hashes[0] == hashLine(lines[0]) && chain.hashes[1] == hashLine(lines[1]) ||
+		chain.hashes[0] == hashLine(lines[1]) && chain.hashes[1] == hashLine(lines[0])) {
+		t.Errorf("expected hashes of the first 2 records anchored, got %v", chain.hashes)
+	}
+
+	// recording with a nil logger is a no-op
+	var nilLogger *AuditLogger
+	nilLogger.Record(task, AuditEventEnd, blockchain.TaskFinished, "")
+}
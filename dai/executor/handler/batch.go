@@ -0,0 +1,163 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// predictBatch records the inputs of a batch prediction merged into the sample file of the local party,
+// with which the outcomes of the prediction are split into the results of inputs
+type predictBatch struct {
+	inputs int            // number of inputs
+	index  map[string]int // index of the input each sample ID comes from
+	errs   []string       // why each input is not merged, empty if it is merged
+}
+
+// BatchResultName returns the name the prediction result of the input at index of a batch prediction task
+// is stored with, the result of the first input is stored with the task ID as the result of a prediction task
+func BatchResultName(taskID string, index int32) string {
+	if index == 0 {
+		return taskID
+	}
+	return fmt.Sprintf("%s-%d", taskID, index)
+}
+
+// mergeBatchInputs merges the sample files of the inputs of a batch prediction into one, so that they are
+// predicted in one session. contents[i] is the sample file of input i, nil if it fails to be read with errs[i].
+// An input is skipped if its columns differ from the first merged input or it shares sample IDs with
+// the inputs merged, the others are still merged, an error is returned only if no input is merged.
+func mergeBatchInputs(contents [][]byte, errs []string, idName string) ([]byte, *predictBatch, error) {
+	batch := &predictBatch{
+		inputs: len(contents),
+		index:  make(map[string]int),
+		errs:   errs,
+	}
+	var merged [][]string
+	idColumn := -1
+	for i, content := range contents {
+		if batch.errs[i] != "" {
+			continue
+		}
+		rows, err := csv.ReadSparseRowsFromFile(content)
+		if err != nil || len(rows) == 0 {
+			batch.errs[i] = fmt.Sprintf("failed to read rows of sample file: %v", err)
+			continue
+		}
+		if merged == nil {
+			for c, name := range rows[0] {
+				if name == idName {
+					idColumn = c
+				}
+			}
+			if idColumn < 0 {
+				batch.errs[i] = fmt.Sprintf("psiLabel %s not found in sample file", idName)
+				continue
+			}
+		} else if strings.Join(rows[0], ",") != strings.Join(merged[0], ",") {
+			batch.errs[i] = fmt.Sprintf("columns %s differ from the ones of the other inputs", strings.Join(rows[0], ","))
+			continue
+		}
+		if err := batch.addIDs(i, rows[1:], len(rows[0]), idColumn); err != nil {
+			batch.errs[i] = err.Error()
+			continue
+		}
+		if merged == nil {
+			merged = rows
+		} else {
+			merged = append(merged, rows[1:]...)
+		}
+	}
+	if merged == nil {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "no input of the batch prediction is available: %s",
+			strings.Join(batch.errs, "; "))
+	}
+	fileText, err := csv.WriteRows(merged)
+	if err != nil {
+		return nil, nil, errorx.Internal(err, "failed to encode merged sample file")
+	}
+	return fileText, batch, nil
+}
+
+// addIDs records the IDs of the samples of input, none of them is recorded if any is duplicate,
+// rows may be sparse rows of the file with columnNum columns
+func (b *predictBatch) addIDs(input int, rows [][]string, columnNum, idColumn int) error {
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		id, ok := vl_common.RowValue(row, columnNum, idColumn)
+		if !ok {
+			return errorx.New(errcodes.ErrCodeParam, "sample without psiLabel")
+		}
+		if j, ok := b.index[id]; ok {
+			return errorx.New(errcodes.ErrCodeParam, "sample ID %s is duplicate with input %d", id, j)
+		}
+		if seen[id] {
+			return errorx.New(errcodes.ErrCodeParam, "sample ID %s is duplicate", id)
+		}
+		seen[id] = true
+	}
+	for id := range seen {
+		b.index[id] = input
+	}
+	return nil
+}
+
+// split splits the outcomes of the prediction into the ones of each input, an input merged
+// whose samples are all dropped by PSI has no outcomes, as no other party has its samples
+func (b *predictBatch) split(outcomes []byte) ([][]byte, []*pbTask.BatchPredictResult, error) {
+	rows, err := vl_common.PredictResultFromBytes(outcomes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "empty prediction outcomes")
+	}
+	inputRows := make([][][]string, b.inputs)
+	for _, row := range rows[1:] {
+		i, ok := b.index[row[0]]
+		if !ok {
+			return nil, nil, errorx.New(errcodes.ErrCodeInternal, "sample ID %s of outcomes not found in the inputs", row[0])
+		}
+		inputRows[i] = append(inputRows[i], row)
+	}
+
+	contents := make([][]byte, b.inputs)
+	results := make([]*pbTask.BatchPredictResult, b.inputs)
+	for i := range inputRows {
+		results[i] = &pbTask.BatchPredictResult{Index: int32(i), ErrMessage: b.errs[i]}
+		if b.errs[i] != "" {
+			continue
+		}
+		if len(inputRows[i]) == 0 {
+			results[i].ErrMessage = "no sample of the input is aligned by executors"
+			continue
+		}
+		content, err := json.Marshal(append([][]string{rows[0]}, inputRows[i]...))
+		if err != nil {
+			return nil, nil, errorx.New(errcodes.ErrCodeEncoding, "encode predict results failed: %s", err.Error())
+		}
+		contents[i] = content
+		results[i].Samples = int64(len(inputRows[i]))
+	}
+	return contents, results, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+)
+
+func TestMergeBatchInputs(t *testing.T) {
+	contents := [][]byte{
+		[]byte("id,x1,x2\n1,0.1,0.2\n2,0.3,0.4\n"),
+		nil,
+		[]byte("id,x1,x2\n3,0.5,0.6\n"),
+		[]byte("id,x2,x1\n4,0.7,0.8\n"),
+		[]byte("id,x1,x2\n5,0.9,1.0\n1,1.1,1.2\n"),
+	}
+	errs := []string{"", "failed to get sample file", "", "", ""}
+	merged, batch, err := mergeBatchInputs(contents, errs, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.ReadRowsFromFile(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"id", "x1", "x2"}, {"1", "0.1", "0.2"}, {"2", "0.3", "0.4"}, {"3", "0.5", "0.6"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("merged rows: %v, want %v", rows, want)
+	}
+	// the input with different columns and the one with duplicate IDs are skipped
+	for i, failed := range []bool{false, true, false, true, true} {
+		if (batch.errs[i] != "") != failed {
+			t.Errorf("input %d: unexpected error %q", i, batch.errs[i])
+		}
+	}
+	if _, ok := batch.index["5"]; ok {
+		t.Error("IDs of the skipped input should not be recorded")
+	}
+
+	if _, _, err := mergeBatchInputs([][]byte{nil}, []string{"failed"}, "id"); err == nil {
+		t.Error("expected error when no input is merged")
+	}
+}
+
+func TestSplitBatchOutcomes(t *testing.T) {
+	batch := &predictBatch{
+		inputs: 3,
+		index:  map[string]int{"1": 0, "2": 0, "3": 2, "4": 2},
+		errs:   []string{"", "failed to get sample file", ""},
+	}
+	outcomes, err := vl_common.PredictResultToBytes("id", []string{"1", "2"}, []float64{1.5, 2.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, results, err := batch.split(outcomes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := vl_common.PredictResultFromBytes(contents[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"id", "value"}, {"1", "1.5"}, {"2", "2.5"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows of input 0: %v, want %v", rows, want)
+	}
+	if results[0].Samples != 2 || results[0].ErrMessage != "" {
+		t.Errorf("unexpected result of input 0: %v", results[0])
+	}
+	// input 1 fails locally, and no sample of input 2 is aligned
+	for _, i := range []int{1, 2} {
+		if contents[i] != nil || results[i].ErrMessage == "" {
+			t.Errorf("input %d should not be predicted: %v", i, results[i])
+		}
+	}
+
+	if BatchResultName("task1", 0) != "task1" || BatchResultName("task1", 2) != "task1-2" {
+		t.Error("unexpected batch result names")
+	}
+}
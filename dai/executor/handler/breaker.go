@@ -0,0 +1,179 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// Defaults of the circuit breaker of peer executors
+const (
+	DefaultBreakerWindow   = 5 * time.Minute
+	DefaultBreakerCooldown = time.Minute
+)
+
+// States of the circuit of a peer
+const (
+	breakerClosed   = "closed"    // tasks with the peer are accepted
+	breakerOpen     = "open"      // tasks with the peer are rejected until the cooldown ends
+	breakerHalfOpen = "half-open" // a task is let through to probe the peer
+)
+
+// peerCircuit is the state of the circuit of a peer
+type peerCircuit struct {
+	state       string
+	failures    int       // consecutive failures
+	lastFailure time.Time // time of the last failure, failures before the window are forgotten
+	openedAt    time.Time // time the circuit is opened, or the probe is let through in half-open state
+}
+
+// PeerBreaker is the circuit breaker of peer executors. After Threshold consecutive failed calls to a peer,
+// each within Window of the previous one, the circuit of the peer opens and new tasks with it are rejected
+// for Cooldown. Then the circuit is half-open, a task is let through to probe the peer, its first call
+// closes the circuit if it succeeds, or opens it again if it fails. If the probe makes no call within Cooldown,
+// another task is let through. A nil PeerBreaker accepts all tasks.
+type PeerBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	circuits map[string]*peerCircuit
+	lock     sync.Mutex
+	now      func() time.Time
+}
+
+// NewPeerBreaker returns the circuit breaker opening after threshold failures, nil if threshold is 0,
+// zero window and cooldown mean DefaultBreakerWindow and DefaultBreakerCooldown
+func NewPeerBreaker(threshold int, window, cooldown time.Duration) *PeerBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if window <= 0 {
+		window = DefaultBreakerWindow
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &PeerBreaker{
+		Threshold: threshold,
+		Window:    window,
+		Cooldown:  cooldown,
+		circuits:  make(map[string]*peerCircuit),
+		now:       time.Now,
+	}
+}
+
+// Allow returns an error if the circuit of any of peers is open, otherwise tasks with them are accepted,
+// and the peers in half-open state are probed by the task
+func (b *PeerBreaker) Allow(peers []string) error {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+	probes := make(map[string]*peerCircuit)
+	for _, peer := range peers {
+		c, ok := b.circuits[peer]
+		if !ok || c.state == breakerClosed {
+			continue
+		}
+		if now.Sub(c.openedAt) < b.Cooldown {
+			return errorx.New(errcodes.ErrCodePeerUnavailable, "peer executor %s failed %d times in a row, tasks with it are rejected until %s",
+				peer, c.failures, c.openedAt.Add(b.Cooldown).Format(time.RFC3339))
+		}
+		probes[peer] = c
+	}
+	// circuits are changed only if the task is accepted by all of them
+	for peer, c := range probes {
+		if c.state == breakerOpen {
+			logger.WithField(logging.PeerKey, peer).Info("circuit of peer executor is half-open, probing it with a task")
+		}
+		c.state = breakerHalfOpen
+		c.openedAt = now
+	}
+	return nil
+}
+
+// Observe records the result of a call to peer, only the failures showing the peer is unreachable are counted,
+// the errors answered by the peer, such as the task is rejected, prove it's reachable
+func (b *PeerBreaker) Observe(peer string, err error) {
+	if b == nil {
+		return
+	}
+	if err != nil && !peerUnreachable(err) {
+		err = nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+	c, ok := b.circuits[peer]
+	if err == nil {
+		if ok {
+			if c.state != breakerClosed {
+				logger.WithField(logging.PeerKey, peer).Info("circuit of peer executor is closed")
+			}
+			delete(b.circuits, peer)
+		}
+		return
+	}
+	if !ok {
+		c = &peerCircuit{state: breakerClosed}
+		b.circuits[peer] = c
+	}
+	if c.state == breakerClosed && now.Sub(c.lastFailure) > b.Window {
+		c.failures = 0
+	}
+	c.failures++
+	c.lastFailure = now
+	if c.state == breakerHalfOpen || (c.state == breakerClosed && c.failures >= b.Threshold) {
+		c.state = breakerOpen
+		c.openedAt = now
+		logger.WithField(logging.PeerKey, peer).WithError(err).Warnf("circuit of peer executor is open after %d failures, tasks with it are rejected for %v",
+			c.failures, b.Cooldown)
+	}
+}
+
+// State returns the state of the circuit of peer, "closed", "open" or "half-open"
+func (b *PeerBreaker) State(peer string) string {
+	if b == nil {
+		return breakerClosed
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if c, ok := b.circuits[peer]; ok {
+		return c.state
+	}
+	return breakerClosed
+}
+
+// peerUnreachable returns whether err shows the peer is unreachable, such as it's not connected or not responding
+func peerUnreachable(err error) bool {
+	if code, _ := errorx.Parse(err); code == errcodes.ErrCodeRPCFindNoPeer || code == errcodes.ErrCodeRPCConnect {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
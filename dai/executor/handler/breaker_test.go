@@ -0,0 +1,111 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func newTestBreaker(threshold int) (*PeerBreaker, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	b := NewPeerBreaker(threshold, time.Minute, 10*time.Second)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestPeerBreaker(t *testing.T) {
+	if NewPeerBreaker(0, 0, 0) != nil {
+		t.Error("circuit breaker should be disabled if threshold is 0")
+	}
+	var disabled *PeerBreaker
+	disabled.Observe("p1", status.Error(codes.Unavailable, "down"))
+	checkErr(t, disabled.Allow([]string{"p1"}))
+
+	b, now := newTestBreaker(3)
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	// errors answered by the peer and failures out of the window are not consecutive failures
+	b.Observe("p1", unavailable)
+	b.Observe("p1", errors.New("task rejected by peer"))
+	b.Observe("p1", unavailable)
+	b.Observe("p1", unavailable)
+	*now = now.Add(2 * time.Minute)
+	b.Observe("p1", unavailable)
+	checkErr(t, b.Allow([]string{"p1"}))
+
+	b.Observe("p1", errorx.New(errcodes.ErrCodeRPCConnect, "failed to get connection"))
+	b.Observe("p1", status.Error(codes.DeadlineExceeded, "timeout"))
+	if state := b.State("p1"); state != breakerOpen {
+		t.Fatalf("circuit should be open after 3 failures, got %s", state)
+	}
+	if err := b.Allow([]string{"p2", "p1"}); !errorx.Is(err, errcodes.ErrCodePeerUnavailable) {
+		t.Errorf("tasks with the peer should be rejected in cooldown, got %v", err)
+	}
+	checkErr(t, b.Allow([]string{"p2"}))
+
+	// a task is let through after cooldown, and others wait for its result
+	*now = now.Add(10 * time.Second)
+	checkErr(t, b.Allow([]string{"p1"}))
+	if state := b.State("p1"); state != breakerHalfOpen {
+		t.Errorf("circuit should be half-open after cooldown, got %s", state)
+	}
+	if err := b.Allow([]string{"p1"}); err == nil {
+		t.Error("only one task should probe the peer")
+	}
+	b.Observe("p1", unavailable)
+	if state := b.State("p1"); state != breakerOpen {
+		t.Errorf("circuit should be open again if the probe fails, got %s", state)
+	}
+
+	*now = now.Add(10 * time.Second)
+	checkErr(t, b.Allow([]string{"p1"}))
+	b.Observe("p1", nil)
+	if state := b.State("p1"); state != breakerClosed {
+		t.Errorf("circuit should be closed if the probe succeeds, got %s", state)
+	}
+	checkErr(t, b.Allow([]string{"p1"}))
+}
+
+func TestTaskRejectedByBreaker(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	b, _ := newTestBreaker(1)
+	h.Breaker = b
+	b.Observe("127.0.0.1:8185", status.Error(codes.Unavailable, "connection refused"))
+
+	task := &pbTask.FLTask{
+		TaskID:    "t1",
+		AlgoParam: &pbCom.TaskParams{TaskType: pbCom.TaskType_LEARN},
+		DataSets:  []*pbTask.DataForTask{{Executor: []byte("peer"), Address: "127.0.0.1:8185"}},
+	}
+	if _, err := h.TaskStartPrepare(context.Background(), task); !errorx.Is(err, errcodes.ErrCodePeerUnavailable) {
+		t.Fatalf("task with the failing peer should be rejected, got %v", err)
+	}
+	if _, ok := chain.finished["t1"]; !ok {
+		t.Error("task rejected should be failed on chain")
+	}
+	if len(h.MpcTasks) != 0 {
+		t.Errorf("task rejected should take no slot, got %v", h.MpcTasks)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+const (
+	// CallbackSignatureHeader is the header carrying the HMAC-SHA256 signature of the callback body
+	CallbackSignatureHeader = "X-DAI-Signature"
+
+	// DefaultCallbackRetryInterval is the interval before the first retry if it is not configured
+	DefaultCallbackRetryInterval = time.Second
+	// DefaultCallbackTimeout is the timeout of each callback request if it is not configured
+	DefaultCallbackTimeout = 10 * time.Second
+	// maxCallbackRetryInterval limits the interval growing by exponential backoff
+	maxCallbackRetryInterval = time.Minute
+
+	// CallbackStatusDrifted is the status of the callback sent by each executor of a prediction task
+	// which detects drift of its columns of the samples predicted, the task goes on as usual
+	CallbackStatusDrifted = "Drifted"
+)
+
+// CallbackPolicy defines how callbacks are signed and retried
+type CallbackPolicy struct {
+	Secret        string        // key of HMAC-SHA256 signature, callbacks are not signed if it is empty
+	MaxRetries    int           // the max number of retries, 0 means no retry
+	RetryInterval time.Duration // the interval before the first retry, doubled after each retry
+	Timeout       time.Duration // timeout of each request
+}
+
+// CallbackEvent is the JSON body POSTed to the callback URL of a task when it reaches a terminal status,
+// or when drift is detected in prediction
+type CallbackEvent struct {
+	TaskID     string `json:"taskID"`
+	TaskType   string `json:"taskType"`
+	Status     string `json:"status"` // Finished, Failed, Cancelled, Timeout or Drifted
+	ErrMessage string `json:"errMessage,omitempty"`
+	// ResultLocation is where the result of a finished task is, the ID of the prediction result file if it is
+	// stored in XuperDB, otherwise the address of the executor keeping the model or the prediction result
+	ResultLocation string `json:"resultLocation,omitempty"`
+	Executor       string `json:"executor"` // name of the executor sending the callback
+	Time           int64  `json:"time"`     // when the status was recorded, in nanoseconds
+	// Drift is the drift of the columns of the executor sending the callback, only for Drifted
+	Drift *pbCom.DriftReport `json:"drift,omitempty"`
+}
+
+// CallbackNotifier notifies the callback URLs of tasks. Notifications are sent asynchronously,
+// so that neither slow receivers nor retries delay the completion of tasks.
+type CallbackNotifier struct {
+	policy CallbackPolicy
+	client *http.Client
+	node   Node
+}
+
+// NewCallbackNotifier creates a CallbackNotifier sending callbacks on behalf of node
+func NewCallbackNotifier(policy CallbackPolicy, node Node) *CallbackNotifier {
+	if policy.RetryInterval <= 0 {
+		policy.RetryInterval = DefaultCallbackRetryInterval
+	}
+	if policy.Timeout <= 0 {
+		policy.Timeout = DefaultCallbackTimeout
+	}
+	return &CallbackNotifier{
+		policy: policy,
+		client: &http.Client{Timeout: policy.Timeout},
+		node:   node,
+	}
+}
+
+// Notify sends the terminal status of task to its callback URL in background, it is a no-op
+// if n is nil or the task has no callback URL. result is the result recorded in blockchain.
+func (n *CallbackNotifier) Notify(task blockchain.FLTask, status, errMessage, result string) {
+	if n == nil || task.AlgoParam == nil || task.AlgoParam.CallbackURL == "" {
+		return
+	}
+	event := CallbackEvent{
+		TaskID:     task.TaskID,
+		TaskType:   task.AlgoParam.TaskType.String(),
+		Status:     status,
+		ErrMessage: errMessage,
+		Executor:   n.node.Name,
+		Time:       time.Now().UnixNano(),
+	}
+	if status == blockchain.TaskFinished {
+		event.ResultLocation = result
+		if result == "" {
+			event.ResultLocation = n.node.Address
+		}
+	}
+	go func() {
+		if err := n.send(task.AlgoParam.CallbackURL, event); err != nil {
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("failed to notify task callback")
+		}
+	}()
+}
+
+// NotifyDrift sends the drift of the local columns of the samples predicted by task to its callback URL
+// in background, it is a no-op if n is nil or the task has no callback URL
+func (n *CallbackNotifier) NotifyDrift(task blockchain.FLTask, drift *pbCom.DriftReport) {
+	if n == nil || task.AlgoParam == nil || task.AlgoParam.CallbackURL == "" {
+		return
+	}
+	event := CallbackEvent{
+		TaskID:   task.TaskID,
+		TaskType: task.AlgoParam.TaskType.String(),
+		Status:   CallbackStatusDrifted,
+		Executor: n.node.Name,
+		Time:     time.Now().UnixNano(),
+		Drift:    drift,
+	}
+	go func() {
+		if err := n.send(task.AlgoParam.CallbackURL, event); err != nil {
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("failed to notify drift callback")
+		}
+	}()
+}
+
+// send POSTs event to url until it is accepted or retries are exhausted
+func (n *CallbackNotifier) send(url string, event CallbackEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errorx.Internal(err, "failed to marshal callback event")
+	}
+	interval := n.policy.RetryInterval
+	for attempt := 0; ; attempt++ {
+		err = n.post(url, body)
+		if err == nil || attempt >= n.policy.MaxRetries {
+			return err
+		}
+		logger.WithField(logging.TaskIDKey, event.TaskID).WithError(err).Debugf("failed to notify task callback, retry %d/%d after %v",
+			attempt+1, n.policy.MaxRetries, interval)
+		time.Sleep(interval)
+		if interval *= 2; interval > maxCallbackRetryInterval {
+			interval = maxCallbackRetryInterval
+		}
+	}
+}
+
+// post sends one callback request, responses with status codes other than 2xx are failures
+func (n *CallbackNotifier) post(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.policy.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errorx.Wrap(err, "failed to create callback request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.policy.Secret != "" {
+		req.Header.Set(CallbackSignatureHeader, SignCallback(n.policy.Secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errorx.Wrap(err, "failed to send callback request")
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errorx.New(errorx.ErrCodeInternal, "callback is rejected with status %s", resp.Status)
+	}
+	return nil
+}
+
+// SignCallback returns the value of the signature header of body signed with secret,
+// receivers verify callbacks by comparing it with the header
+func SignCallback(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func TestCallbackNotifier(t *testing.T) {
+	const secret = "callback-secret"
+	var attempts int32
+	events := make(chan CallbackEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt fails to check the notification is retried
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if sig := r.Header.Get(CallbackSignatureHeader); sig != SignCallback(secret, body) {
+			t.Errorf("unexpected signature %s", sig)
+		}
+		var event CallbackEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("failed to unmarshal callback event: %v", err)
+		}
+		events <- event
+	}))
+	defer server.Close()
+
+	node := Node{Local: peer.Local{Name: "executor1", Address: "127.0.0.1:8184"}}
+	n := NewCallbackNotifier(CallbackPolicy{Secret: secret, MaxRetries: 2, RetryInterval: time.Millisecond}, node)
+	task := &pbTask.FLTask{TaskID: "task1", AlgoParam: &pbCom.TaskParams{
+		TaskType:    pbCom.TaskType_LEARN,
+		CallbackURL: server.URL,
+	}}
+	n.Notify(task, blockchain.TaskFinished, "", "")
+
+	select {
+	case event := <-events:
+		if event.TaskID != "task1" || event.Status != blockchain.TaskFinished || event.Executor != "executor1" {
+			t.Errorf("unexpected callback event %+v", event)
+		}
+		if event.ResultLocation != node.Address {
+			t.Errorf("expected result location %s, got %s", node.Address, event.ResultLocation)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback is not received")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+
+	// no-op without callback URL or notifier
+	n.Notify(&pbTask.FLTask{TaskID: "task2", AlgoParam: &pbCom.TaskParams{}}, blockchain.TaskFailed, "failed", "")
+	var nilNotifier *CallbackNotifier
+	nilNotifier.Notify(task, blockchain.TaskFailed, "failed", "")
+}
+
+func TestCallbackNotifierRetriesExhausted(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := NewCallbackNotifier(CallbackPolicy{MaxRetries: 2, RetryInterval: time.Millisecond}, Node{})
+	if err := n.send(server.URL, CallbackEvent{TaskID: "task1"}); err == nil {
+		t.Error("expected error when the callback is always rejected")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestCallbackNotifierDrift(t *testing.T) {
+	events := make(chan CallbackEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event CallbackEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to unmarshal callback event: %v", err)
+		}
+		events <- event
+	}))
+	defer server.Close()
+
+	n := NewCallbackNotifier(CallbackPolicy{}, Node{Local: peer.Local{Name: "executor2"}})
+	task := &pbTask.FLTask{TaskID: "task1", AlgoParam: &pbCom.TaskParams{
+		TaskType:    pbCom.TaskType_PREDICT,
+		CallbackURL: server.URL,
+	}}
+	n.NotifyDrift(task, &pbCom.DriftReport{Drifted: true, Threshold: 2, Scores: map[string]float64{"CRIM": 3.5}})
+
+	select {
+	case event := <-events:
+		if event.Status != CallbackStatusDrifted || event.Executor != "executor2" || event.Drift.GetScores()["CRIM"] != 3.5 {
+			t.Errorf("unexpected callback event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback is not received")
+	}
+}
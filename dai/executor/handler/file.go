@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/cjqpker/slidewindow"
@@ -16,17 +20,45 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecies"
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/xuperdb"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/checksum"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/httputil"
 	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/engine/common"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/engine/types"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
-	"github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/http"
 	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 )
 
 var defaultConcurrency uint64 = 10
 
+// gcmTagSize is the size of the tag appended to the sample file encrypted by AES-GCM
+const gcmTagSize = 16
+
+// DefaultDownloadRetryInterval is the interval before the first retry of downloading a sample file if it is not configured
+const DefaultDownloadRetryInterval = time.Second
+
+// maxDownloadRetryInterval limits the interval growing by exponential backoff
+const maxDownloadRetryInterval = time.Minute
+
+// downloadNetworkErrors are the lower-case messages of errors caused by network or unavailable nodes,
+// sample files failed to be downloaded with these errors can be downloaded again
+var downloadNetworkErrors = []string{
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"no such host",
+	"network is unreachable",
+	"timeout",
+	"unexpected eof",
+	"server closed",
+}
+
 // Define the ExecutionType of the executor, used to download sample files during task training
 //  ProxyExecutionMode indicates to execute tasks using others' data
 //  SelfExecutionMode indicates to execute tasks using own data
@@ -35,18 +67,31 @@ const (
 	SelfExecutionMode  = "Self"
 )
 
-// Storage files operations, read and write
-//  supports local storage and xuperdb storage
-type Storage interface {
-	Write(value io.Reader, key string) (string, error)
-	Read(key string) (io.ReadCloser, error)
+// FileStorage contains model storage, evaluation storage, prediction result storage and checkpoint storage
+type FileStorage struct {
+	ModelStorage      storage.StorageBackend
+	EvaluationStorage storage.StorageBackend
+	PredictStorage    storage.StorageBackend
+	CheckpointStorage storage.StorageBackend // checkpoints of training tasks, nil if checkpoints are disabled
+	SignatureStorage  storage.StorageBackend // signatures of prediction results, nil if results are not signed
+
+	// Names are the templates of the names of files by their kinds, such as storage.KindModel,
+	// the files of a kind without template are named after the IDs of their tasks
+	Names map[string]*file.NameTemplate
 }
 
-// FileStorage contains model storage, evaluation storage and prediction result storage
-type FileStorage struct {
-	ModelStorage      Storage
-	EvaluationStorage Storage
-	PredictStorage    Storage
+// FileName returns the name of the file of kind stored for task, rendered by the template of kind from task,
+// or id if kind has no template. id is the ID of task, or the one of an input of a batch prediction by BatchResultName.
+func (s FileStorage) FileName(kind string, task blockchain.FLTask, id string) string {
+	t := s.Names[kind]
+	if t == nil {
+		return id
+	}
+	modelID := task.TaskID
+	if task.AlgoParam.GetTaskType() == pbCom.TaskType_PREDICT {
+		modelID = task.AlgoParam.GetModelTaskID()
+	}
+	return t.Render(id, modelID, time.Unix(0, task.PublishTime))
 }
 
 // FileDownload mode for download the sample file during the task execution
@@ -56,57 +101,180 @@ type FileDownload struct {
 
 	PrivateKey ecdsa.PrivateKey // key authorized by data owner node, used when the Type is 'Self'
 	Host       string           // data owner host address, used when the Type is 'Self'
+
+	// Client downloads files and slices over mutual TLS, plaintext HTTP is used if it is nil
+	Client *http.Client
+
+	// MaxFileSize is the maximum size of a sample file in bytes, 0 means no limit
+	MaxFileSize int64
+
+	// MaxRetries is the max number of retries of a sample file failed to be downloaded by network errors,
+	// 0 means no retry, the whole file is downloaded again in each retry
+	MaxRetries int
+	// RetryInterval is the interval before the first retry, doubled after each retry
+	RetryInterval time.Duration
 }
 
 // GetSampleFile download sample files, if f.Type is 'Self', download files from dataOwner nodes.
 // If f.Type is 'Proxy', download slices from storage nodes and recover the sample file, the key
 // required to decrypt the sample file and slices can be obtained through the file authorization application ID. only
 // after the file owner has confirmed the executor's file authorization application, the executor node can get the sample file.
+// If f.MaxFileSize is set, a sample file whose recorded size exceeds it is not downloaded, and reading the file fails
+// once more bytes than it are read, in case the recorded size is wrong.
 func (f *FileDownload) GetSampleFile(fileID string, chain Blockchain) (io.ReadCloser, error) {
+	r, _, err := f.GetSampleFileColumns(fileID, nil, chain)
+	return r, err
+}
+
+// GetSampleFileColumns downloads the sample file the same as GetSampleFile, and requests the dataOwner node
+// to return only columns of it if f.Type is 'Self', see xuperdb.DownloadColumns.
+// projected is false if the whole file is returned, as slices recovered in 'Proxy' mode can't be projected,
+// or the dataOwner node doesn't support column projection, the caller projects the file itself then.
+func (f *FileDownload) GetSampleFileColumns(fileID string, columns []string, chain Blockchain) (
+	r io.ReadCloser, projected bool, err error) {
+
 	if f.Type == SelfExecutionMode {
 		xuperdbClient := xuperdb.New(0, "", f.Host, f.PrivateKey)
-		plainText, err := xuperdbClient.Read(fileID)
-		if err != nil {
-			return nil, errorx.Wrap(err, "failed to download the sample file from the dataOwner node, fileID: %s", fileID)
+		xuperdbClient.Client = f.Client
+		if f.MaxFileSize > 0 {
+			size, known, err := xuperdbClient.FileSize(context.Background(), fileID)
+			if err != nil {
+				return nil, false, errorx.Wrap(err, "failed to get the size of the sample file from the dataOwner node, fileID: %s", fileID)
+			}
+			if known {
+				if err := f.checkFileSize(fileID, size); err != nil {
+					return nil, false, err
+				}
+			}
 		}
-		return plainText, nil
-	} else {
-		// 1. get the sample file info from chain
-		file, err := chain.GetFileByID(fileID)
+		plainText, projected, err := xuperdbClient.DownloadColumns(context.Background(), fileID, columns)
 		if err != nil {
-			return nil, errorx.New(errorx.ErrCodeInternal, "failed to get the sample file from contract, fileID: %s", fileID)
+			return nil, false, errorx.Wrap(err, "failed to download the sample file from the dataOwner node, fileID: %s", fileID)
 		}
-		// 2. get the authorization ID, use the authKey to decrypt the sample file
-		pubkey := ecdsa.PublicKeyFromPrivateKey(f.NodePrivateKey)
-		fileAuths, err := chain.ListFileAuthApplications(&xdbchain.ListFileAuthOptions{
-			Applier:    pubkey[:],
-			Authorizer: file.Owner,
-			Status:     xdbchain.FileAuthApproved,
-			FileID:     fileID,
-			TimeStart:  0,
-			TimeEnd:    time.Now().UnixNano(),
-			Limit:      1,
-		})
-		if err != nil {
-			return nil, errorx.Wrap(err,
-				"get the file authorization application failed, fileID: %s, Applier: %x, Authorizer: %x", fileID, pubkey[:], file.Owner)
+		return f.limitFileSize(plainText, fileID, 0), projected, nil
+	}
+	plainText, err := f.getProxySampleFile(fileID, chain)
+	return plainText, false, err
+}
+
+// getProxySampleFile downloads slices of the sample file from storage nodes and recovers it
+func (f *FileDownload) getProxySampleFile(fileID string, chain Blockchain) (io.ReadCloser, error) {
+	// 1. get the sample file info from chain
+	file, err := chain.GetFileByID(fileID)
+	if err != nil {
+		return nil, errorx.New(errorx.ErrCodeInternal, "failed to get the sample file from contract, fileID: %s", fileID)
+	}
+	if err := f.checkFileSize(fileID, file.Length); err != nil {
+		return nil, err
+	}
+	// 2. get the authorization ID, use the authKey to decrypt the sample file
+	pubkey := ecdsa.PublicKeyFromPrivateKey(f.NodePrivateKey)
+	fileAuths, err := chain.ListFileAuthApplications(&xdbchain.ListFileAuthOptions{
+		Applier:    pubkey[:],
+		Authorizer: file.Owner,
+		Status:     xdbchain.FileAuthApproved,
+		FileID:     fileID,
+		TimeStart:  0,
+		TimeEnd:    time.Now().UnixNano(),
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err,
+			"get the file authorization application failed, fileID: %s, Applier: %x, Authorizer: %x", fileID, pubkey[:], file.Owner)
+	}
+	if len(fileAuths) == 0 {
+		return nil, errorx.New(errorx.ErrCodeInternal,
+			"the file authorization application is empty, fileID: %s, Applier: %x, Authorizer: %x", fileID, pubkey[:], file.Owner)
+	}
+	// 3. obtain the derived key needed to decrypt the file through the AuthKey
+	firstKey, secKey, err := f.getDecryptAuthKey(fileAuths[0].AuthKey)
+	if err != nil {
+		return nil, err
+	}
+	// 4. download slices and decrypt
+	plainText, err := f.recoverFile(context.Background(), chain, file, firstKey, secKey)
+	if err != nil {
+		return nil, err
+	}
+	return plainText, nil
+}
+
+// withRetries calls download until it succeeds, or it fails with an error not caused by network,
+// or retries are exhausted, the last error is returned then
+func (f *FileDownload) withRetries(fileID string, download func() error) error {
+	interval := f.RetryInterval
+	if interval <= 0 {
+		interval = DefaultDownloadRetryInterval
+	}
+	for attempt := 0; ; attempt++ {
+		err := download()
+		if err == nil || !isDownloadNetworkError(err) {
+			return err
 		}
-		if len(fileAuths) == 0 {
-			return nil, errorx.New(errorx.ErrCodeInternal,
-				"the file authorization application is empty, fileID: %s, Applier: %x, Authorizer: %x", fileID, pubkey[:], file.Owner)
+		if attempt >= f.MaxRetries {
+			if attempt > 0 {
+				return errorx.Wrap(err, "failed to download the sample file %s after %d retries", fileID, attempt)
+			}
+			return err
 		}
-		// 3. obtain the derived key needed to decrypt the file through the AuthKey
-		firstKey, secKey, err := f.getDecryptAuthKey(fileAuths[0].AuthKey)
-		if err != nil {
-			return nil, err
+		logger.WithError(err).Warnf("failed to download the sample file %s, retry %d/%d after %v",
+			fileID, attempt+1, f.MaxRetries, interval)
+		time.Sleep(interval)
+		if interval *= 2; interval > maxDownloadRetryInterval {
+			interval = maxDownloadRetryInterval
 		}
-		// 4. download slices and decrypt
-		plainText, err := f.recoverFile(context.Background(), chain, file, firstKey, secKey)
-		if err != nil {
-			return nil, err
+	}
+}
+
+// isDownloadNetworkError checks whether err is caused by network, rather than rejected by the dataOwner or storage nodes
+func isDownloadNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, e := range downloadNetworkErrors {
+		if strings.Contains(msg, e) {
+			return true
 		}
-		return plainText, nil
 	}
+	return false
+}
+
+// checkFileSize checks the size of the sample file recorded by its metadata against f.MaxFileSize
+func (f *FileDownload) checkFileSize(fileID string, size uint64) error {
+	if f.MaxFileSize > 0 && size > uint64(f.MaxFileSize) {
+		return errorx.New(errcodes.ErrCodeSampleFileTooLarge,
+			"the sample file %s of %d bytes exceeds the limit of %d bytes", fileID, size, f.MaxFileSize)
+	}
+	return nil
+}
+
+// limitFileSize returns the reader failing once more than f.MaxFileSize plus overhead bytes are read from r,
+// or r itself if there is no limit
+func (f *FileDownload) limitFileSize(r io.ReadCloser, fileID string, overhead int64) io.ReadCloser {
+	if f.MaxFileSize <= 0 {
+		return r
+	}
+	return &sizeLimitedReader{ReadCloser: r, fileID: fileID, limit: f.MaxFileSize + overhead}
+}
+
+// sizeLimitedReader fails reading once more than limit bytes are read
+type sizeLimitedReader struct {
+	io.ReadCloser
+	fileID string
+	limit  int64
+	read   int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, errorx.New(errcodes.ErrCodeSampleFileTooLarge,
+			"more than %d bytes of the sample file %s are downloaded", r.limit, r.fileID)
+	}
+	return n, err
 }
 
 // getDecryptAuthKey get the authorization key for file decryption, return firKey and secKey.
@@ -227,8 +395,8 @@ func (f *FileDownload) recoverFile(ctx context.Context, chain Blockchain, file x
 			}
 			defer r.Close()
 
-			// read slice and check slice hash
-			cipherText, err := ioutil.ReadAll(r)
+			// read slice and check slice hash, the node can't send more than the length recorded
+			cipherText, err := ioutil.ReadAll(io.LimitReader(r, int64(target.Length)+1))
 			if err != nil {
 				logger.WithError(err).Warn("failed to read slice from target node")
 				continue
@@ -294,8 +462,8 @@ func (f *FileDownload) recoverFile(ctx context.Context, chain Blockchain, file x
 		}
 	}()
 
-	// decrypt recovered file
-	fileCipherText, err := ioutil.ReadAll(reader)
+	// decrypt recovered file, the slices are read no more than the limit of the sample file
+	fileCipherText, err := ioutil.ReadAll(f.limitFileSize(reader, file.ID, gcmTagSize))
 	if err != nil {
 		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read")
 	}
@@ -303,7 +471,12 @@ func (f *FileDownload) recoverFile(ctx context.Context, chain Blockchain, file x
 	if err != nil {
 		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "file decryption failed")
 	}
-	return ioutil.NopCloser(bytes.NewReader(plainText)), nil
+	plainReader := ioutil.NopCloser(bytes.NewReader(plainText))
+	// slices are verified by their hashes, and the recovered file by the checksum recorded by the dataOwner
+	if sum := checksum.FromExtra(file.Ext); sum != "" {
+		return checksum.NewReader(plainReader, sum, file.ID)
+	}
+	return plainReader, nil
 }
 
 // pull used pull slices from storage nodes
@@ -326,10 +499,14 @@ func (f *FileDownload) pull(ctx context.Context, id, storIndex, fileId, nodeAddr
 		return nil, errorx.Wrap(err, "failed to sign slice pull")
 	}
 	// assemble the pull request url
-	url := fmt.Sprintf("http://%s/v1/slice/pull?slice_id=%s&slice_stor_index=%s&file_id=%s&timestamp=%d&pubkey=%s&signature=%s",
-		nodeAddress, id, storIndex, fileId, timestamp, pubkey.String(), sig.String())
+	scheme := "http"
+	if f.Client != nil {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/v1/slice/pull?slice_id=%s&slice_stor_index=%s&file_id=%s&timestamp=%d&pubkey=%s&signature=%s",
+		scheme, nodeAddress, id, storIndex, fileId, timestamp, pubkey.String(), sig.String())
 
-	r, err := http.Get(ctx, url)
+	r, err := httputil.Get(ctx, f.Client, url)
 	if err != nil {
 		return nil, errorx.Wrap(err, "failed to do get slice")
 	}
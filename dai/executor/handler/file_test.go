@@ -0,0 +1,98 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+func TestSampleFileSizeLimit(t *testing.T) {
+	f := &FileDownload{MaxFileSize: 10}
+	if err := f.checkFileSize("f1", 10); err != nil {
+		t.Errorf("unexpected error of a file within the limit: %v", err)
+	}
+	if err := f.checkFileSize("f1", 11); !errorx.Is(err, errcodes.ErrCodeSampleFileTooLarge) {
+		t.Errorf("expected error of a file exceeding the limit, got %v", err)
+	}
+
+	// the recorded size may be wrong, so the download is limited as well
+	content, err := ioutil.ReadAll(f.limitFileSize(ioutil.NopCloser(bytes.NewReader(make([]byte, 10))), "f1", 0))
+	if err != nil || len(content) != 10 {
+		t.Errorf("failed to read a file within the limit, read %d bytes, err: %v", len(content), err)
+	}
+	_, err = ioutil.ReadAll(f.limitFileSize(ioutil.NopCloser(bytes.NewReader(make([]byte, 11))), "f1", 0))
+	if !errorx.Is(err, errcodes.ErrCodeSampleFileTooLarge) {
+		t.Errorf("expected error of reading a file exceeding the limit, got %v", err)
+	}
+	if _, err := ioutil.ReadAll(f.limitFileSize(ioutil.NopCloser(bytes.NewReader(make([]byte, 11))), "f1", 1)); err != nil {
+		t.Errorf("unexpected error of a file within the limit plus overhead: %v", err)
+	}
+
+	noLimit := &FileDownload{}
+	if err := noLimit.checkFileSize("f1", 1<<40); err != nil {
+		t.Errorf("unexpected error without limit: %v", err)
+	}
+}
+
+func TestDownloadRetries(t *testing.T) {
+	f := &FileDownload{MaxRetries: 2, RetryInterval: time.Millisecond}
+	refused := errorx.New(errorx.ErrCodeInternal, "failed to download slice: dial tcp 127.0.0.1:8122: connect: connection refused")
+
+	calls := 0
+	err := f.withRetries("f1", func() error {
+		if calls++; calls < 3 {
+			return refused
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected download succeeded in the last retry, got %d calls, err: %v", calls, err)
+	}
+
+	// the network error is surfaced once retries are exhausted
+	calls = 0
+	err = f.withRetries("f1", func() error {
+		calls++
+		return errors.New("read tcp 127.0.0.1:51324->127.0.0.1:8121: i/o timeout")
+	})
+	if calls != 3 || err == nil || !strings.Contains(err.Error(), "i/o timeout") {
+		t.Errorf("expected the network error after 2 retries, got %d calls, err: %v", calls, err)
+	}
+
+	// errors not caused by network are returned immediately
+	calls = 0
+	err = f.withRetries("f1", func() error {
+		calls++
+		return errorx.New(errcodes.ErrCodeSampleFileTooLarge, "the sample file f1 exceeds the limit")
+	})
+	if calls != 1 || !errorx.Is(err, errcodes.ErrCodeSampleFileTooLarge) {
+		t.Errorf("expected no retry of the error not caused by network, got %d calls, err: %v", calls, err)
+	}
+
+	// no retry if it is not configured
+	calls = 0
+	noRetry := &FileDownload{}
+	if err := noRetry.withRetries("f1", func() error { calls++; return refused }); calls != 1 || err != refused {
+		t.Errorf("expected no retry, got %d calls, err: %v", calls, err)
+	}
+}
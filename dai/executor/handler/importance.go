@@ -0,0 +1,98 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// FeatureImportanceSuffix is appended to the name of the evaluation result as the key of the feature importance report
+const FeatureImportanceSuffix = ".importance"
+
+// FeatureImportances returns the importance of the local features of model, the local part of a linear or logistic
+// regression model, sorted by importance in descending order. The coefficient of a feature is theta/sigma*std, where
+// theta is applied to the feature scaled by sigma, and std is the standard deviation of the training samples,
+// so that the coefficients of features scaled in different ways are comparable. The theta is taken as it is
+// if the distribution of the feature isn't summarized in model. Only the model itself is used, so the report
+// reveals nothing about the samples of other parties.
+func FeatureImportances(model *pbCom.TrainModels) []*pbTask.FeatureImportance {
+	var importances []*pbTask.FeatureImportance
+	for name, theta := range model.Thetas {
+		if model.IsTagPart && name == "Intercept" {
+			continue
+		}
+		coefficient := theta
+		if summary, ok := model.Summaries[name]; ok && model.Sigmas[name] != 0 {
+			coefficient = theta / model.Sigmas[name] * summary.Std
+		}
+		importances = append(importances, &pbTask.FeatureImportance{
+			Feature:     name,
+			Coefficient: coefficient,
+			Importance:  math.Abs(coefficient),
+		})
+	}
+	sort.Slice(importances, func(i, j int) bool {
+		if importances[i].Importance != importances[j].Importance {
+			return importances[i].Importance > importances[j].Importance
+		}
+		return importances[i].Feature < importances[j].Feature
+	})
+	return importances
+}
+
+// SaveFeatureImportances stores the feature importance report of the model trained by task,
+// next to its evaluation result with the key suffixed with FeatureImportanceSuffix
+func (s FileStorage) SaveFeatureImportances(ctx context.Context, task blockchain.FLTask, importances []*pbTask.FeatureImportance) error {
+	text, err := json.Marshal(importances)
+	if err != nil {
+		return errorx.Internal(err, "failed to marshal feature importance")
+	}
+	name := s.FileName(storage.KindEvaluation, task, task.TaskID) + FeatureImportanceSuffix
+	if _, err := s.EvaluationStorage.Upload(ctx, name, bytes.NewReader(text)); err != nil {
+		return errorx.Wrap(err, "failed to save feature importance")
+	}
+	return nil
+}
+
+// LoadFeatureImportances returns the feature importance report of the model trained by task,
+// the error satisfies storage.IsNotFound if the report isn't stored
+func (s FileStorage) LoadFeatureImportances(ctx context.Context, task blockchain.FLTask) ([]*pbTask.FeatureImportance, error) {
+	name := s.FileName(storage.KindEvaluation, task, task.TaskID) + FeatureImportanceSuffix
+	r, err := s.EvaluationStorage.Download(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	text, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to read feature importance")
+	}
+	var importances []*pbTask.FeatureImportance
+	if err := json.Unmarshal(text, &importances); err != nil {
+		return nil, errorx.Internal(err, "failed to unmarshal feature importance")
+	}
+	return importances, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+)
+
+// DefaultJanitorInterval is how often local files are checked if the interval is not configured
+const DefaultJanitorInterval = time.Hour
+
+// Janitor removes the local files of tasks ended on the chain by the retention policy, files are removed
+// if they're older than MaxAge, then the oldest ones are removed while the total size exceeds MaxTotalSize.
+// Files are named after the IDs of their tasks, or by the templates of Names, and the ones of tasks not ended,
+// in execution or waiting in the queue are never removed, nor are the files whose tasks can't be found.
+type Janitor struct {
+	Dirs         map[string]string             // local directories of the kinds of files to clean up
+	Names        map[string]*file.NameTemplate // templates of the names of the kinds of files, by kind
+	MaxAge       time.Duration                 // zero means files are not removed by age
+	MaxTotalSize int64                         // in bytes, zero means files are not removed by size
+	Interval     time.Duration
+
+	GetTask     blockchain.GetTaskFunc
+	LocalStatus func(taskID string) (inExecution, queued bool)
+}
+
+// localFile is a file found by the janitor
+type localFile struct {
+	kind    string
+	path    string
+	taskID  string
+	size    int64
+	modTime time.Time
+}
+
+// Start cleans up local files every Interval until ctx is done
+func (j *Janitor) Start(ctx context.Context) {
+	interval := j.Interval
+	if interval == 0 {
+		interval = DefaultJanitorInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		j.Clean(time.Now())
+	}
+}
+
+// Clean removes the files of ended tasks by the retention policy at now, and returns the bytes reclaimed
+func (j *Janitor) Clean(now time.Time) (reclaimed int64) {
+	var files []localFile
+	var total int64
+	for kind, dir := range j.Dirs {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			logrus.WithError(err).Warnf("failed to read directory %s of %s", dir, kind)
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			taskID := entry.Name()
+			if t := j.Names[kind]; t != nil {
+				// the files not named by the template are not the executor's
+				var ok bool
+				if taskID, ok = t.TaskID(entry.Name()); !ok {
+					continue
+				}
+			}
+			total += entry.Size()
+			files = append(files, localFile{kind: kind, path: filepath.Join(dir, entry.Name()),
+				taskID: taskID, size: entry.Size(), modTime: entry.ModTime()})
+		}
+	}
+	// the oldest files are removed first
+	sort.Slice(files, func(i, k int) bool { return files[i].modTime.Before(files[k].modTime) })
+
+	ended := make(map[string]bool)
+	for _, f := range files {
+		expired := j.MaxAge > 0 && now.Sub(f.modTime) > j.MaxAge
+		oversize := j.MaxTotalSize > 0 && total > j.MaxTotalSize
+		if !expired && !oversize {
+			continue
+		}
+		taskID, ok := j.endedTask(f.taskID, ended)
+		if !ok {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			logrus.WithField(logging.TaskIDKey, taskID).WithError(err).Warnf("failed to remove %s", f.path)
+			continue
+		}
+		total -= f.size
+		reclaimed += f.size
+		metrics.StorageReclaimed(f.kind, f.size)
+		logrus.WithField(logging.TaskIDKey, taskID).Infof("removed %s of %d bytes, modified at %v",
+			f.path, f.size, f.modTime.Format(time.RFC3339))
+	}
+	return reclaimed
+}
+
+// endedTask returns the ID of the task the file is named after if the task ended and isn't executed locally.
+// Batch prediction results are named after the task ID suffixed with the index of the input.
+// ended caches the results of the tasks checked.
+func (j *Janitor) endedTask(name string, ended map[string]bool) (string, bool) {
+	candidates := []string{name}
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			candidates = append(candidates, name[:i])
+		}
+	}
+	for _, id := range candidates {
+		if isEnded, ok := ended[id]; ok {
+			if isEnded {
+				return id, true
+			}
+			continue
+		}
+		task, err := j.GetTask(id)
+		if err != nil {
+			continue
+		}
+		inExecution, queued := j.LocalStatus(id)
+		isEnded := (task.Status == blockchain.TaskFinished || blockchain.TaskEndedUnfinished(task.Status)) &&
+			!inExecution && !queued
+		ended[id] = isEnded
+		if isEnded {
+			return id, true
+		}
+		// the file belongs to a task not ended
+		return id, false
+	}
+	return "", false
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+func TestJanitorClean(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	writeFile := func(name string, size int, age time.Duration) {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tasks := map[string]string{
+		"finished":  blockchain.TaskFinished,
+		"failed":    blockchain.TaskFailed,
+		"running":   blockchain.TaskProcessing,
+		"queued":    blockchain.TaskToProcess,
+		"restarted": blockchain.TaskFinished, // ended on chain, but still executed locally
+		"recent":    blockchain.TaskFinished,
+	}
+	j := &Janitor{
+		Dirs:   map[string]string{"predictions": dir},
+		MaxAge: 24 * time.Hour,
+		GetTask: func(id string) (blockchain.FLTask, error) {
+			status, ok := tasks[id]
+			if !ok {
+				return nil, errorx.New(errorx.ErrCodeNotFound, "task %s not found", id)
+			}
+			return &pbTask.FLTask{TaskID: id, Status: status}, nil
+		},
+		LocalStatus: func(taskID string) (bool, bool) { return taskID == "restarted", taskID == "queued" },
+	}
+
+	writeFile("finished", 100, 48*time.Hour)
+	writeFile("finished-1", 100, 48*time.Hour)
+	writeFile("failed", 100, 48*time.Hour)
+	writeFile("running", 100, 48*time.Hour)
+	writeFile("queued", 100, 48*time.Hour)
+	writeFile("restarted", 100, 48*time.Hour)
+	writeFile("unknown", 100, 48*time.Hour)
+	writeFile("recent", 100, time.Hour)
+
+	if reclaimed := j.Clean(now); reclaimed != 300 {
+		t.Errorf("expected 300 bytes reclaimed, got %d", reclaimed)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	for _, name := range []string{"finished", "finished-1", "failed"} {
+		if exists(name) {
+			t.Errorf("expired file %s of ended task should be removed", name)
+		}
+	}
+	for _, name := range []string{"running", "queued", "restarted", "unknown", "recent"} {
+		if !exists(name) {
+			t.Errorf("file %s should be kept", name)
+		}
+	}
+
+	// the oldest files of ended tasks are removed until the total size is within the limit
+	writeFile("failed", 100, 2*time.Hour)
+	j.MaxAge, j.MaxTotalSize = 0, 550
+	if reclaimed := j.Clean(now); reclaimed != 100 {
+		t.Errorf("expected 100 bytes reclaimed, got %d", reclaimed)
+	}
+	if exists("failed") || !exists("recent") {
+		t.Error("expected the oldest file of ended tasks removed only")
+	}
+	// the task IDs are parsed from the names rendered by the template, other files are kept
+	tmpl, err := file.ParseNameTemplate("{timestamp}_{task_id}.csv", "prediction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Names = map[string]*file.NameTemplate{"predictions": tmpl}
+	j.MaxAge, j.MaxTotalSize = 24*time.Hour, 0
+	named := tmpl.Render("finished", "train", now)
+	writeFile(named, 100, 48*time.Hour)
+	writeFile("finished", 100, 48*time.Hour)
+	if reclaimed := j.Clean(now); reclaimed != 100 {
+		t.Errorf("expected 100 bytes reclaimed, got %d", reclaimed)
+	}
+	if exists(named) || !exists("finished") {
+		t.Error("expected the file named by the template removed only")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/livaluator"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// DefaultLiveEvaluationBuffer is the number of metric scores buffered for a watcher of live evaluation,
+// and the number of latest metric scores of a task replayed to a new watcher
+const DefaultLiveEvaluationBuffer = 256
+
+// LiveEvaluationHub dispatches the metric scores of live evaluation to the watchers of tasks.
+// Publishing never blocks training, the oldest metric score buffered for a slow watcher is dropped
+// to make room for the new one.
+type LiveEvaluationHub struct {
+	buffer   int
+	history  map[string][]*pbTask.LiveEvaluationMetric
+	watchers map[string]map[chan *pbTask.LiveEvaluationMetric]struct{}
+	lock     sync.Mutex
+}
+
+// NewLiveEvaluationHub creates a LiveEvaluationHub, buffer is the number of metric scores kept for a watcher
+func NewLiveEvaluationHub(buffer int) *LiveEvaluationHub {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &LiveEvaluationHub{
+		buffer:   buffer,
+		history:  make(map[string][]*pbTask.LiveEvaluationMetric),
+		watchers: make(map[string]map[chan *pbTask.LiveEvaluationMetric]struct{}),
+	}
+}
+
+// Subscribe returns the channel of metric scores of a task, starting with the latest ones already calculated,
+// the channel is closed when the task ends. unsubscribe must be called when the watcher leaves.
+func (h *LiveEvaluationHub) Subscribe(taskID string) (metrics <-chan *pbTask.LiveEvaluationMetric, unsubscribe func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	c := make(chan *pbTask.LiveEvaluationMetric, h.buffer)
+	for _, m := range h.history[taskID] {
+		c <- m
+	}
+	if h.watchers[taskID] == nil {
+		h.watchers[taskID] = make(map[chan *pbTask.LiveEvaluationMetric]struct{})
+	}
+	h.watchers[taskID][c] = struct{}{}
+
+	return c, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if _, ok := h.watchers[taskID][c]; ok {
+			delete(h.watchers[taskID], c)
+			close(c)
+		}
+	}
+}
+
+// Publish sends the metric score to the watchers of the task without blocking
+func (h *LiveEvaluationHub) Publish(m *pbTask.LiveEvaluationMetric) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	history := append(h.history[m.TaskID], m)
+	if len(history) > h.buffer {
+		history = history[len(history)-h.buffer:]
+	}
+	h.history[m.TaskID] = history
+
+	for c := range h.watchers[m.TaskID] {
+		select {
+		case c <- m:
+		default:
+			// drop the oldest one, the channel is only sent to with the lock held, so there's room then
+			select {
+			case <-c:
+			default:
+			}
+			c <- m
+		}
+	}
+}
+
+// Close closes the channels of the watchers of the task, and drops its metric scores
+func (h *LiveEvaluationHub) Close(taskID string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for c := range h.watchers[taskID] {
+		close(c)
+	}
+	delete(h.watchers, taskID)
+	delete(h.history, taskID)
+}
+
+// ReportLiveEvaluation publishes the metric scores of live evaluation calculated at a pause round,
+// the ones of tasks not in execution pool are dropped. It's called by MPC.
+func (m *MpcModelHandler) ReportLiveEvaluation(taskID string, round uint64, metrics []livaluator.Metric) {
+	m.RLock()
+	defer m.RUnlock()
+	if _, ok := m.MpcTasks[taskID]; !ok || m.LiveEvaluation == nil {
+		return
+	}
+	for _, metric := range metrics {
+		m.LiveEvaluation.Publish(&pbTask.LiveEvaluationMetric{
+			TaskID:     taskID,
+			Round:      round,
+			MetricName: metric.Name,
+			Value:      metric.Value,
+		})
+	}
+}
+
+// SubscribeLiveEvaluation returns the channel of metric scores of live evaluation of a task in execution,
+// the channel is closed when the task is removed from execution pool, as it ends or is cancelled
+func (m *MpcModelHandler) SubscribeLiveEvaluation(taskID string) (<-chan *pbTask.LiveEvaluationMetric, func(), error) {
+	m.RLock()
+	defer m.RUnlock()
+	if m.LiveEvaluation == nil {
+		return nil, nil, errorx.New(errorx.ErrCodeConfig, "live evaluation streaming is not enabled on the executor")
+	}
+	task, ok := m.MpcTasks[taskID]
+	if !ok {
+		return nil, nil, errorx.New(errorx.ErrCodeNotFound, "task %s is not in execution on the executor", taskID)
+	}
+	if !task.AlgoParam.GetLivalParams().GetEnable() {
+		return nil, nil, errorx.New(errorx.ErrCodeParam, "live evaluation of task %s is not enabled", taskID)
+	}
+	metrics, unsubscribe := m.LiveEvaluation.Subscribe(taskID)
+	return metrics, unsubscribe, nil
+}
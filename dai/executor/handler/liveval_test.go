@@ -0,0 +1,93 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/livaluator"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func receiveRounds(c <-chan *pbTask.LiveEvaluationMetric, n int) (rounds []uint64) {
+	for i := 0; i < n; i++ {
+		rounds = append(rounds, (<-c).Round)
+	}
+	return rounds
+}
+
+func TestLiveEvaluationHub(t *testing.T) {
+	h := NewLiveEvaluationHub(2)
+	for round := uint64(1); round <= 3; round++ {
+		h.Publish(&pbTask.LiveEvaluationMetric{TaskID: "t1", Round: round, MetricName: "RMSE"})
+	}
+
+	// a new watcher starts with the latest metric scores
+	c, unsubscribe := h.Subscribe("t1")
+	defer unsubscribe()
+	if rounds := receiveRounds(c, 2); rounds[0] != 2 || rounds[1] != 3 {
+		t.Errorf("expected the latest rounds 2 and 3 replayed, got %v", rounds)
+	}
+
+	// publishing doesn't block on a slow watcher, the oldest metric score is dropped
+	for round := uint64(4); round <= 6; round++ {
+		h.Publish(&pbTask.LiveEvaluationMetric{TaskID: "t1", Round: round, MetricName: "RMSE"})
+	}
+	if rounds := receiveRounds(c, 2); rounds[0] != 5 || rounds[1] != 6 {
+		t.Errorf("expected rounds 5 and 6 kept for the slow watcher, got %v", rounds)
+	}
+
+	h.Close("t1")
+	if _, ok := <-c; ok {
+		t.Error("expected the channel closed when the task ends")
+	}
+}
+
+func TestSubscribeLiveEvaluation(t *testing.T) {
+	h, _, _ := newResourceHandler(t, ResourceLimits{})
+	h.LiveEvaluation = NewLiveEvaluationHub(DefaultLiveEvaluationBuffer)
+
+	if _, _, err := h.SubscribeLiveEvaluation("t1"); err == nil || !errorx.Is(err, errorx.ErrCodeNotFound) {
+		t.Errorf("expected error for task not in execution, got %v", err)
+	}
+	h.MpcTasks["t1"] = &FlTask{FLTask: *newTask("t1", pbCom.TaskType_LEARN)}
+	if _, _, err := h.SubscribeLiveEvaluation("t1"); err == nil || !errorx.Is(err, errorx.ErrCodeParam) {
+		t.Errorf("expected error for task without live evaluation, got %v", err)
+	}
+
+	h.MpcTasks["t1"].AlgoParam.LivalParams = &pbCom.LiveEvaluationParams{Enable: true}
+	c, unsubscribe, err := h.SubscribeLiveEvaluation("t1")
+	checkErr(t, err)
+	defer unsubscribe()
+	h.ReportLiveEvaluation("t1", 10, []livaluator.Metric{{Name: "Accuracy", Value: 0.8}, {Name: "F1Score", Value: 0.7}})
+	if m := <-c; m.TaskID != "t1" || m.Round != 10 || m.MetricName != "Accuracy" || m.Value != 0.8 {
+		t.Errorf("unexpected metric %v", m)
+	}
+	if m := <-c; m.MetricName != "F1Score" {
+		t.Errorf("expected F1Score, got %v", m)
+	}
+
+	// the stream closes when the task is removed from execution pool
+	h.stopLocalMpcTask("t1", false)
+	if _, ok := <-c; ok {
+		t.Error("expected the channel closed when the task is stopped")
+	}
+	h.ReportLiveEvaluation("t1", 20, []livaluator.Metric{{Name: "Accuracy", Value: 0.9}})
+	if n := len(h.LiveEvaluation.history["t1"]); n != 0 {
+		t.Errorf("expected metric scores of the stopped task dropped, got %d", n)
+	}
+}
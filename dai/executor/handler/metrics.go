@@ -0,0 +1,115 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+)
+
+// metricsChain counts the failed calls of the wrapped Blockchain
+type metricsChain struct {
+	Blockchain
+}
+
+// NewMetricsChain returns a Blockchain recording failed calls into metrics
+func NewMetricsChain(chain Blockchain) Blockchain {
+	return &metricsChain{Blockchain: chain}
+}
+
+// observe records err as a failure of the method if it is not nil, and returns it
+func observe(method string, err error) error {
+	if err != nil {
+		metrics.BlockchainCallFailed(method)
+	}
+	return err
+}
+
+func (c *metricsChain) RegisterExecutorNode(opt *blockchain.AddNodeOptions) error {
+	return observe("RegisterExecutorNode", c.Blockchain.RegisterExecutorNode(opt))
+}
+
+func (c *metricsChain) GetExecutorNodeByID(id string) (blockchain.ExecutorNode, error) {
+	node, err := c.Blockchain.GetExecutorNodeByID(id)
+	return node, observe("GetExecutorNodeByID", err)
+}
+
+func (c *metricsChain) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
+	nodes, err := c.Blockchain.ListExecutorNodes()
+	return nodes, observe("ListExecutorNodes", err)
+}
+
+func (c *metricsChain) RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error {
+	return observe("RevokeExecutorKey", c.Blockchain.RevokeExecutorKey(opt))
+}
+
+func (c *metricsChain) ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	tasks, err := c.Blockchain.ListTask(opt)
+	return tasks, observe("ListTask", err)
+}
+
+func (c *metricsChain) PublishTask(opt *blockchain.PublishFLTaskOptions) error {
+	return observe("PublishTask", c.Blockchain.PublishTask(opt))
+}
+
+func (c *metricsChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	task, err := c.Blockchain.GetTaskById(id)
+	return task, observe("GetTaskById", err)
+}
+
+func (c *metricsChain) ConfirmTask(opt *blockchain.FLTaskConfirmOptions) error {
+	return observe("ConfirmTask", c.Blockchain.ConfirmTask(opt))
+}
+
+func (c *metricsChain) RejectTask(opt *blockchain.FLTaskConfirmOptions) error {
+	return observe("RejectTask", c.Blockchain.RejectTask(opt))
+}
+
+func (c *metricsChain) ExecuteTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	return observe("ExecuteTask", c.Blockchain.ExecuteTask(opt))
+}
+
+func (c *metricsChain) FinishTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	return observe("FinishTask", c.Blockchain.FinishTask(opt))
+}
+
+func (c *metricsChain) GetFileByID(id string) (xdbchain.File, error) {
+	file, err := c.Blockchain.GetFileByID(id)
+	return file, observe("GetFileByID", err)
+}
+
+func (c *metricsChain) ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error) {
+	auths, err := c.Blockchain.ListFileAuthApplications(opt)
+	return auths, observe("ListFileAuthApplications", err)
+}
+
+func (c *metricsChain) PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error {
+	return observe("PublishFileAuthApplication", c.Blockchain.PublishFileAuthApplication(opt))
+}
+
+func (c *metricsChain) ListNodes() (xdbchain.Nodes, error) {
+	nodes, err := c.Blockchain.ListNodes()
+	return nodes, observe("ListNodes", err)
+}
+
+func (c *metricsChain) AnchorAudit(opt *blockchain.AnchorAuditOptions) error {
+	return observe("AnchorAudit", c.Blockchain.AnchorAudit(opt))
+}
+
+func (c *metricsChain) GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error) {
+	policy, err := c.Blockchain.GetColumnPolicy(fileID, executor)
+	return policy, observe("GetColumnPolicy", err)
+}
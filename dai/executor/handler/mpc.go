@@ -16,31 +16,47 @@ package handler
 import (
 	"bytes"
 	"context"
+	gocsv "encoding/csv"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/golang/protobuf/proto"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
 	reModel "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/cluster"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/spill"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/envelope"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 )
 
 var (
 	logger = logrus.WithField("module", "handler.mpc")
+
+	// shutdownCheckInterval is the interval to check if the tasks in execution finish on shutdown
+	shutdownCheckInterval = time.Second
 )
 
 // MpcHandler starts mpc-training or mpc-prediction when gets task from blockchain,
@@ -59,11 +75,44 @@ type MpcHandler interface {
 	GetMpcClusterService() *cluster.Service
 
 	// TaskStartPrepare prepares resources needed by task, and adds task to execution pool.
-	TaskStartPrepare(task blockchain.FLTask) (*pbCom.StartTaskRequest, error)
+	// ctx carries the trace context of the executor starting the task, if the task is started by a peer.
+	TaskStartPrepare(ctx context.Context, task blockchain.FLTask) (*pbCom.StartTaskRequest, error)
 
 	// StartLocalMpcTask executes task
 	StartLocalMpcTask(task *pbCom.StartTaskRequest, isSendTaskToOthers bool) error
 
+	// CheckTaskFingerprint checks the fingerprint of the task from the Executor of party requesting to start it
+	// agrees with the local one, the task is failed if they disagree
+	CheckTaskFingerprint(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint, party string) error
+
+	// AgreePSIAlgorithm resolves PSI algorithm auto of the task by the larger number of samples of both Executors,
+	// returns the algorithm to reply to the Executor requesting to start it
+	AgreePSIAlgorithm(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint) string
+
+	// PrepareAlignment decides whether the task reuses the sample alignment cached with the Executor of party
+	// requesting to start it, returns the key of the alignment and the decision to reply
+	PrepareAlignment(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint, party string) (string, bool)
+
+	// QueueTasks queues the tasks waiting for execution by priority, queued tasks missing in tasks are dropped.
+	// Tasks are failed if the queue is full, or they wait in the queue longer than the maximum execution time.
+	QueueTasks(tasks blockchain.FLTasks)
+
+	// NextQueuedTask removes and returns the queued task with the highest priority that is allowed to start,
+	// false is returned if there is no such task
+	NextQueuedTask() (blockchain.FLTask, bool)
+
+	// CancelQueuedTask removes a task waiting in the queue and records the 'Cancelled' status in blockchain,
+	// errors are returned if the task is in execution or not queued
+	CancelQueuedTask(taskID, reason string) error
+
+	// SetQueuedTaskPriority changes the priority of a task waiting in the queue,
+	// errors are returned if the task is in execution or not queued
+	SetQueuedTaskPriority(taskID string, priority int32) error
+
+	// CancelTask cancels a task in execution and records the 'Cancelled' status in blockchain,
+	// other executors of the task are requested to cancel it if notifyOthers is true
+	CancelTask(task blockchain.FLTask, reason string, notifyOthers bool) error
+
 	// GetAvailableTasksNum returns left number of tasks could be executed
 	GetAvailableTasksNum() (int, int)
 
@@ -71,6 +120,30 @@ type MpcHandler interface {
 	// and stops expired tasks
 	CheckMpcTimeOutTasks()
 
+	// CheckNodeMemoryUsage stops the latest added task if the memory usage of the executor exceeds the limit of the node
+	CheckNodeMemoryUsage()
+
+	// GetResourceStatus returns tasks in execution and resources usage of the node
+	GetResourceStatus() ResourceStatus
+
+	// SubscribeLiveEvaluation returns the channel of metric scores of live evaluation of a task in execution,
+	// which is closed when the task ends, unsubscribe must be called when the watcher leaves
+	SubscribeLiveEvaluation(taskID string) (metrics <-chan *pbTask.LiveEvaluationMetric, unsubscribe func(), err error)
+
+	// GetLocalTaskStatus returns whether the task is in the execution pool, or waits in the queue of the node
+	GetLocalTaskStatus(taskID string) (inExecution, queued bool)
+
+	// UpdateMpcConf updates tasks limits and timeouts at runtime, tasks already in execution pool
+	// keep running, and the rpc requests they send later use the new timeout
+	UpdateMpcConf(trainTaskLimit, predictTaskLimit int, rpcTimeout, taskMaxExecTime, taskMaxTimeout time.Duration)
+
+	// RunOutbox records the task status buffered during blockchain outages when it comes back, until ctx is done
+	RunOutbox(ctx context.Context)
+
+	// Shutdown refuses new tasks, and waits at most timeout for the tasks in execution to finish,
+	// the remaining ones are cancelled after the deadline
+	Shutdown(timeout time.Duration)
+
 	//Close closes all inner services
 	Close()
 }
@@ -81,20 +154,37 @@ type FlTask struct {
 	pbTask.FLTask
 	// timeout for task execution
 	ExpiredTime int64
+	// time when the task is added into execution pool
+	AddedTime int64
+	// inputs of the batch prediction merged into the local sample file, nil if the task isn't a batch
+	batch *predictBatch
 }
 
 // MpcModelHandler handler for mpc training or prediction tasks
 type MpcModelHandler struct {
 	Config             mpc.Config
-	Node               Node          // executor node information
-	Storage            FileStorage   // handler for computing results storage
-	Download           FileDownload  // handler for file download, 'proxy' or 'self'
-	Chain              Blockchain    // handler for blockchain operation
-	MpcTaskMaxExecTime time.Duration // maximum execution time for mpc task
+	Node               Node               // executor node information
+	Storage            FileStorage        // handler for computing results storage
+	Download           FileDownload       // handler for file download, 'proxy' or 'self'
+	Chain              Blockchain         // handler for blockchain operation
+	MpcTaskMaxExecTime time.Duration      // maximum execution time for mpc task
+	MpcTaskMaxTimeout  time.Duration      // upper bound of the execution time requested by tasks
+	Resource           ResourceLimits     // resources reserved by tasks and budget of the node
+	Queue              *TaskQueue         // tasks waiting for free slots
+	PSIAlgorithm       string             // PSI algorithm of tasks published without one
+	DefaultAccuracy    int64              // accuracy of the fixed-point encoding of tasks published without one
+	MaxFeatures        int                // maximum number of features of a sample file used by a task, zero means no limit
+	LiveEvaluation     *LiveEvaluationHub // metric scores of live evaluation of tasks in execution
+	Callback           *CallbackNotifier  // notifies the callback URLs of tasks whose terminal status is recorded locally
+	Audit              *AuditLogger       // records the terminal status of tasks, nil if audit logging is not configured
+	Breaker            *PeerBreaker       // rejects tasks with the peers failing repeatedly, nil if it's disabled
+	Outbox             *StatusOutbox      // buffers the status of tasks while the blockchain is unreachable
 	Mpc                mpc.Mpc
 	ClusterP2p         *p2p.P2P
 	// store execution mpc tasks
 	MpcTasks map[string]*FlTask
+	// draining is set on shutdown, then new tasks are refused
+	draining bool
 	sync.RWMutex
 }
 
@@ -106,6 +196,9 @@ type ParticipantParams struct {
 	psiLabel      string   // feature name for psi
 	PaddleFLRole  int
 	PaddleFLNodes [3]string
+
+	// inputs of the batch prediction merged into fileText, nil if the task isn't a batch
+	batch *predictBatch
 }
 
 // GetMpcClusterService returns mpc cluster service
@@ -114,7 +207,8 @@ func (m *MpcModelHandler) GetMpcClusterService() *cluster.Service {
 }
 
 // GetAvailableTasksNum returns left number of tasks could be executed
-// Returns the number of tasks that can participate in training or prediction
+// Returns the number of tasks that can participate in training or prediction,
+// which are no more than the number of tasks the resources budget allows
 func (m *MpcModelHandler) GetAvailableTasksNum() (tNum int, pNum int) {
 	trainTaskNum := 0
 	predictTaskNum := 0
@@ -127,52 +221,302 @@ func (m *MpcModelHandler) GetAvailableTasksNum() (tNum int, pNum int) {
 			predictTaskNum += 1
 		}
 	}
+	trainTaskLimit, predictTaskLimit := m.Config.TrainTaskLimit, m.Config.PredictTaskLimit
+	slots, limited := m.Resource.availableSlots(len(m.MpcTasks))
 	m.RUnlock()
-	if trainTaskNum >= m.Config.TrainTaskLimit {
+	if trainTaskNum >= trainTaskLimit {
 		tNum = 0
 	} else {
-		tNum = m.Config.TrainTaskLimit - trainTaskNum
+		tNum = trainTaskLimit - trainTaskNum
 	}
-	if predictTaskNum >= m.Config.PredictTaskLimit {
+	if predictTaskNum >= predictTaskLimit {
 		pNum = 0
 	} else {
-		pNum = m.Config.PredictTaskLimit - predictTaskNum
+		pNum = predictTaskLimit - predictTaskNum
+	}
+	if limited && tNum > slots {
+		tNum = slots
+	}
+	if limited && pNum > slots {
+		pNum = slots
 	}
 	return tNum, pNum
 }
 
+// UpdateMpcConf updates tasks limits and timeouts, called when the config file is reloaded
+func (m *MpcModelHandler) UpdateMpcConf(trainTaskLimit, predictTaskLimit int, rpcTimeout, taskMaxExecTime, taskMaxTimeout time.Duration) {
+	m.Lock()
+	m.Config.TrainTaskLimit = trainTaskLimit
+	m.Config.PredictTaskLimit = predictTaskLimit
+	m.Config.RpcTimeout = rpcTimeout
+	m.MpcTaskMaxExecTime = taskMaxExecTime
+	m.MpcTaskMaxTimeout = taskMaxTimeout
+	conf := m.Config
+	m.Unlock()
+	metrics.SetTaskLimits(trainTaskLimit, predictTaskLimit)
+
+	// push the new limits and timeout into the running mpc instance
+	if m.Mpc != nil {
+		m.Mpc.UpdateConfig(conf)
+	}
+}
+
 // addTaskIntoMpcHandler add task into execution pool
 // first count the number of current training or prediction task,
-// if the tasks number reaches the limit, it is not allowed to add task into execution pool
+// if the tasks number reaches the limit or the resources budget is not enough,
+// it is not allowed to add task into execution pool
 func (m *MpcModelHandler) addTaskIntoMpcHandler(task blockchain.FLTask) error {
+	m.RLock()
+	slots, limited := m.Resource.availableSlots(len(m.MpcTasks))
+	m.RUnlock()
+	if limited && slots == 0 {
+		m.limitReached(task, metrics.LimitActionRejected)
+		return errorx.New(errcodes.ErrCodeTooMuchTasks, "Insufficient memory or cpu budget of the node, add task into mpc handler error")
+	}
 	trainTaskNum, predictTaskNum := m.GetAvailableTasksNum()
 	if task.AlgoParam.TaskType == pbCom.TaskType_LEARN && trainTaskNum == 0 {
+		m.limitReached(task, metrics.LimitActionRejected)
 		return errorx.New(errcodes.ErrCodeTooMuchTasks, "Insufficient computing train resources, add task into mpc handler error")
 	}
 	if task.AlgoParam.TaskType == pbCom.TaskType_PREDICT && predictTaskNum == 0 {
+		m.limitReached(task, metrics.LimitActionRejected)
 		return errorx.New(errcodes.ErrCodeTooMuchTasks, "Insufficient computing predict resources, add task into mpc handler error")
 	}
 	m.Lock()
 	defer m.Unlock()
+	if m.draining {
+		return errorx.New(errcodes.ErrCodeShuttingDown, "executor is shutting down, refuse task %s", task.TaskID)
+	}
 	if _, ok := m.MpcTasks[task.TaskID]; ok {
 		return errorx.New(errcodes.ErrCodeTaskExists, "task already exists, taskId: %s", task.TaskID)
 	}
+	now := time.Now().UnixNano()
 	m.MpcTasks[task.TaskID] = &FlTask{
 		FLTask:      *task,
-		ExpiredTime: time.Now().UnixNano() + m.MpcTaskMaxExecTime.Nanoseconds(),
+		ExpiredTime: now + m.taskExecTime(task).Nanoseconds(),
+		AddedTime:   now,
+	}
+	// the task may be started by another executor when it's waiting in the queue
+	if m.Queue != nil {
+		m.Queue.remove(task.TaskID)
 	}
+	metrics.TaskStarted(task.AlgoParam.TaskType, task.AlgoParam.Labels)
 	return nil
 }
 
+// taskExecTime returns the maximum execution time of task, which is the timeout requested by the task
+// no more than MpcTaskMaxTimeout, or MpcTaskMaxExecTime if the task doesn't request one
+func (m *MpcModelHandler) taskExecTime(task blockchain.FLTask) time.Duration {
+	timeout := time.Duration(task.AlgoParam.Timeout) * time.Second
+	if timeout <= 0 {
+		return m.MpcTaskMaxExecTime
+	}
+	if m.MpcTaskMaxTimeout > 0 && timeout > m.MpcTaskMaxTimeout {
+		return m.MpcTaskMaxTimeout
+	}
+	return timeout
+}
+
+// QueueTasks queues the tasks waiting for execution by priority. The queued tasks missing in tasks are dropped,
+// as they're started by other executors or cancelled. Tasks that can't be queued as the queue is full,
+// and the ones waiting in the queue longer than the maximum execution time of a task are failed.
+// Tasks depending on upstream tasks are queued after all of them finish, and failed if any of them ends unfinished.
+func (m *MpcModelHandler) QueueTasks(tasks blockchain.FLTasks) {
+	m.RLock()
+	maxWaitTime := m.MpcTaskMaxExecTime
+	m.RUnlock()
+
+	waiting := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		waiting[task.TaskID] = true
+	}
+	m.Queue.retain(waiting)
+
+	now := time.Now().UnixNano()
+	for _, task := range m.Queue.expire(now - maxWaitTime.Nanoseconds()) {
+		delete(waiting, task.TaskID)
+		m.failQueuedTask(task.TaskID, fmt.Sprintf("task waited in the queue of executor for more than %s", maxWaitTime))
+	}
+	for _, task := range tasks {
+		if !waiting[task.TaskID] {
+			continue
+		}
+		// tasks depending on upstream tasks wait outside the queue until all of them finish
+		if len(task.DependsOn) > 0 && !m.Queue.Contains(task.TaskID) {
+			finished, reason, err := blockchain.CheckDependenciesFinished(task, m.Chain.GetTaskById)
+			if err != nil {
+				logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("failed to check upstream tasks")
+				continue
+			}
+			if reason != "" {
+				m.failQueuedTask(task.TaskID, reason)
+				continue
+			}
+			if !finished {
+				continue
+			}
+		}
+		if !m.Queue.push(task, now) {
+			m.failQueuedTask(task.TaskID, fmt.Sprintf("task queue of executor is full, the queue size is %d", m.Queue.Size()))
+		}
+	}
+}
+
+// NextQueuedTask removes and returns the queued task with the highest priority that is allowed to start,
+// false is returned if there is no such task or the executor is shutting down
+func (m *MpcModelHandler) NextQueuedTask() (blockchain.FLTask, bool) {
+	m.RLock()
+	draining := m.draining
+	m.RUnlock()
+	if draining {
+		return nil, false
+	}
+	trainTaskNum, predictTaskNum := m.GetAvailableTasksNum()
+	task, ok := m.Queue.popRunnable(trainTaskNum, predictTaskNum)
+	if !ok {
+		// the tasks left in the queue wait for free slots, each of them is reported once
+		for _, t := range m.Queue.markBlocked() {
+			m.limitReached(t, metrics.LimitActionQueued)
+		}
+	}
+	return task, ok
+}
+
+// limitReached records that task is not started but handled by action, as the task limit of its type
+// or the resources budget of the node is reached. It is warned so that under-provisioned limits are noticed.
+func (m *MpcModelHandler) limitReached(task blockchain.FLTask, action string) {
+	taskType := task.AlgoParam.TaskType
+	running := 0
+	m.RLock()
+	for _, t := range m.MpcTasks {
+		if (t.AlgoParam.TaskType == pbCom.TaskType_LEARN) == (taskType == pbCom.TaskType_LEARN) {
+			running++
+		}
+	}
+	limit := m.Config.PredictTaskLimit
+	if taskType == pbCom.TaskType_LEARN {
+		limit = m.Config.TrainTaskLimit
+	}
+	slots, limited := m.Resource.availableSlots(len(m.MpcTasks))
+	m.RUnlock()
+
+	reason := metrics.LimitReasonTaskLimit
+	if limited && slots == 0 {
+		reason = metrics.LimitReasonResources
+	}
+	metrics.TaskLimitReached(taskType, reason, action)
+	logger.WithFields(logrus.Fields{
+		logging.TaskIDKey: task.TaskID,
+		"taskType":        taskType.String(),
+		"running":         running,
+		"limit":           limit,
+		"reason":          reason,
+		"action":          action,
+	}).Warn("task limit reached")
+}
+
+// failQueuedTask records the 'Failed' status of a task waiting in the queue in blockchain
+func (m *MpcModelHandler) failQueuedTask(taskID, reason string) {
+	logger.WithField(logging.TaskIDKey, taskID).Warn(reason)
+	if err := m.endQueuedTask(taskID, reason, ""); err != nil {
+		logger.WithField(logging.TaskIDKey, taskID).WithError(err).Error("fail update task status into chain error")
+	}
+}
+
+// endQueuedTask records the 'Failed' status of a task not in execution in blockchain, or status if it is 'Cancelled',
+// the task is set 'Processing' first as only tasks in execution are allowed to finish
+func (m *MpcModelHandler) endQueuedTask(taskID, reason, status string) error {
+	identity := m.taskIdentity(taskID)
+	pubkey := ecdsa.PublicKeyFromPrivateKey(identity.PrivateKey)
+	execTaskOptions := &blockchain.FLTaskExeStatusOptions{
+		Executor:    pubkey[:],
+		TaskID:      taskID,
+		CurrentTime: time.Now().UnixNano(),
+	}
+	msg, err := util.GetSigMessage(execTaskOptions)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for execute task")
+	}
+	sig, err := ecdsa.Sign(identity.PrivateKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign exec task options")
+	}
+	execTaskOptions.Signature = sig[:]
+	if err := m.Chain.ExecuteTask(execTaskOptions); err != nil {
+		return errorx.Wrap(err, "failed to execute task")
+	}
+	return m.updateTaskFinishStatus(taskID, reason, "", status, nil, nil)
+}
+
+// CancelQueuedTask removes a task waiting in the queue of the node, and records the 'Cancelled' status in blockchain,
+// so that the other executors of the task drop it as well. Tasks in execution are cancelled by CancelTask.
+func (m *MpcModelHandler) CancelQueuedTask(taskID, reason string) error {
+	if m.Queue == nil || !m.Queue.remove(taskID) {
+		return m.notQueuedError(taskID)
+	}
+	if err := m.endQueuedTask(taskID, reason, blockchain.TaskCancelled); err != nil {
+		return errorx.Wrap(err, "failed to record the %s status of task %s", blockchain.TaskCancelled, taskID)
+	}
+	logger.WithField(logging.TaskIDKey, taskID).Infof("queued task cancelled: %s", reason)
+	return nil
+}
+
+// SetQueuedTaskPriority changes the priority of a task waiting in the queue of the node,
+// the task is moved behind the queued ones with higher or the same priority
+func (m *MpcModelHandler) SetQueuedTaskPriority(taskID string, priority int32) error {
+	if m.Queue == nil || !m.Queue.setPriority(taskID, priority) {
+		return m.notQueuedError(taskID)
+	}
+	logger.WithField(logging.TaskIDKey, taskID).Infof("priority of queued task changed to %d", priority)
+	return nil
+}
+
+// notQueuedError returns the error of managing a task not waiting in the queue,
+// which is either in execution or unknown to the queue
+func (m *MpcModelHandler) notQueuedError(taskID string) error {
+	m.RLock()
+	_, inExecution := m.MpcTasks[taskID]
+	m.RUnlock()
+	if inExecution {
+		return errorx.New(errcodes.ErrCodeParam, "task %s is already in execution, cancel it by CancelTask", taskID)
+	}
+	return errorx.New(errcodes.ErrCodeNotFound, "task %s is not waiting in the queue of the executor", taskID)
+}
+
 // TaskStartPrepare prepares resources needed by task, and adds task to execution pool.
-func (m *MpcModelHandler) TaskStartPrepare(task blockchain.FLTask) (*pbCom.StartTaskRequest, error) {
+// The root span of the task is started as the child of the span in ctx once the task is added.
+func (m *MpcModelHandler) TaskStartPrepare(ctx context.Context, task blockchain.FLTask) (*pbCom.StartTaskRequest, error) {
+	// 0. the task fails fast if any of its peers keeps failing, rather than after timeouts taking a slot
+	if err := m.Breaker.Allow(m.taskPeers(task)); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("task rejected by circuit breaker")
+		if err := m.UpdateTaskFinishStatus(task.TaskID, err.Error(), ""); err != nil {
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("fail update task status into chain error")
+		}
+		return nil, err
+	}
 	// 1. add task into mpc handler
 	if err := m.addTaskIntoMpcHandler(task); err != nil {
 		logger.WithError(err).Error("failed to add task into mpc tasks pool")
 		return nil, err
 	}
+	tracing.StartTask(ctx, task.TaskID, attribute.String("task.type", task.AlgoParam.TaskType.String()),
+		attribute.String("task.algorithm", task.AlgoParam.Algo.String()))
+
+	// 2. reject the task using columns not allowed by the owners of the sample files, before downloading them
+	if err := m.checkColumnPolicies(task); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("task rejected by column policy")
+		m.updateTaskStatusAndStopLocalMpc(task.TaskID, err.Error(), "")
+		return nil, err
+	}
+
+	// 3. reject the task using sample files with more features than allowed, before downloading them
+	if err := m.checkFeatureNum(task); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warn("task rejected by feature limit")
+		m.updateTaskStatusAndStopLocalMpc(task.TaskID, err.Error(), "")
+		return nil, err
+	}
 
-	// 2. get task start parameters
+	// 4. get task start parameters
 	startRequest, err := m.getMpcStartTaskParam(task)
 	if err != nil {
 		m.updateTaskStatusAndStopLocalMpc(task.TaskID, err.Error(), "")
@@ -181,12 +525,50 @@ func (m *MpcModelHandler) TaskStartPrepare(task blockchain.FLTask) (*pbCom.Start
 	return startRequest, err
 }
 
+// taskPeers returns the addresses of the other executors of task
+func (m *MpcModelHandler) taskPeers(task blockchain.FLTask) []string {
+	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.Identity(task).PrivateKey)
+	var peers []string
+	for _, dataset := range task.DataSets {
+		if !bytes.Equal(dataset.Executor, pubkey[:]) {
+			peers = append(peers, dataset.Address)
+		}
+	}
+	return peers
+}
+
+// taskIdentity returns the identity of the node executing the task taskID, see Node.Identity.
+// The task is looked up in the execution pool or blockchain only if the node has a previous identity,
+// and the current identity is returned if the task is not found.
+func (m *MpcModelHandler) taskIdentity(taskID string) *peer.Local {
+	if m.Node.Previous == nil {
+		return &m.Node.Local
+	}
+	m.RLock()
+	t, ok := m.MpcTasks[taskID]
+	m.RUnlock()
+	if ok {
+		return m.Node.Identity(&t.FLTask)
+	}
+	task, err := m.Chain.GetTaskById(taskID)
+	if err != nil {
+		return &m.Node.Local
+	}
+	return m.Node.Identity(task)
+}
+
+// ObservePeer records the result of a call to another executor into the circuit breaker, called by MPC
+func (m *MpcModelHandler) ObservePeer(peerName string, err error) {
+	m.Breaker.Observe(peerName, err)
+}
+
 // StartLocalMpcTask executes task
 func (m *MpcModelHandler) StartLocalMpcTask(startRequest *pbCom.StartTaskRequest, isSendTaskToOthers bool) error {
-	// 1. if executor is task initiator, send the start signal to other parties
+	// 1. if executor is task initiator, send the start signal to other parties,
+	// which check their fingerprints of the task agree with the local one before the task starts
 	if isSendTaskToOthers {
 		// send task start request to others
-		if err := m.sendTaskStartRequestToOthers(startRequest.Hosts, startRequest.TaskID); err != nil {
+		if err := m.sendTaskStartRequestToOthers(startRequest); err != nil {
 			m.updateTaskStatusAndStopLocalMpc(startRequest.TaskID, err.Error(), "")
 			return err
 		}
@@ -194,27 +576,30 @@ func (m *MpcModelHandler) StartLocalMpcTask(startRequest *pbCom.StartTaskRequest
 	// 2. start train or predict task
 	if mcpTaskError := m.Mpc.StartTask(startRequest); mcpTaskError != nil {
 		m.updateTaskStatusAndStopLocalMpc(startRequest.TaskID, mcpTaskError.Error(), "")
-		logger.WithError(mcpTaskError).Errorf("start mpc task error, taskId: %s", startRequest.TaskID)
+		logger.WithField(logging.TaskIDKey, startRequest.TaskID).WithError(mcpTaskError).Error("start mpc task error")
 		return mcpTaskError
 	}
 	return nil
 }
 
 // CheckMpcTimeOutTasks checks tasks in execution pool if they're expired,
-// and stops expired tasks
+// and cancels expired tasks with the 'Timeout' status recorded in blockchain,
+// the other executors of the tasks are requested to stop them
 func (m *MpcModelHandler) CheckMpcTimeOutTasks() {
-	var timeOutTaskList []string
+	var timeOutTaskList []*FlTask
 	m.RLock()
 	for _, task := range m.MpcTasks {
 		if task.ExpiredTime <= time.Now().UnixNano() {
-			timeOutTaskList = append(timeOutTaskList, task.TaskID)
+			timeOutTaskList = append(timeOutTaskList, task)
 		}
 	}
 	m.RUnlock()
 
-	// stop expired tasks and update status in blockchain
-	for _, taskID := range timeOutTaskList {
-		m.updateTaskStatusAndStopLocalMpc(taskID, "task execute time out", "")
+	for _, task := range timeOutTaskList {
+		reason := fmt.Sprintf("task execute time out after %s", time.Duration(task.ExpiredTime-task.AddedTime))
+		if err := m.endTask(&task.FLTask, reason, blockchain.TaskTimeout, true); err != nil {
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("failed to cancel expired task")
+		}
 	}
 }
 
@@ -223,90 +608,215 @@ func (m *MpcModelHandler) CheckMpcTimeOutTasks() {
 // executeResult is task result, only for prediction task
 func (m *MpcModelHandler) updateTaskStatusAndStopLocalMpc(taskID, executeErr, executeResult string) {
 	if err := m.UpdateTaskFinishStatus(taskID, executeErr, executeResult); err != nil {
-		logger.WithError(err).Errorf("fail update task status into chain error, taskId: %s", taskID)
+		logger.WithField(logging.TaskIDKey, taskID).WithError(err).Error("fail update task status into chain error")
 	} else {
-		logger.Infof("success update task status into chain, taskId: %s", taskID)
+		logger.WithField(logging.TaskIDKey, taskID).Info("success update task status into chain")
 	}
-	m.stopLocalMpcTask(taskID)
+	tracing.EndTask(taskID, executeErr)
+	m.stopLocalMpcTask(taskID, executeErr != "")
 }
 
-// stopLocalMpcTask stops mpc task
-func (m *MpcModelHandler) stopLocalMpcTask(taskId string) {
+// stopLocalMpcTask stops mpc task, failed indicates whether the task is failed
+func (m *MpcModelHandler) stopLocalMpcTask(taskId string, failed bool) {
 	if _, ok := m.MpcTasks[taskId]; !ok {
-		logger.Debugf("mpc task already stopped, taskId: %s", taskId)
+		logger.WithField(logging.TaskIDKey, taskId).Debug("mpc task already stopped")
 		return
 	}
 
 	// notify MPC to stop
 	taskType := m.MpcTasks[taskId].AlgoParam.TaskType
 	if err := m.Mpc.StopTask(&pbCom.StopTaskRequest{TaskID: taskId, Params: &pbCom.TaskParams{TaskType: taskType}}); err != nil {
-		logger.WithError(err).Errorf("failed to stop mpc handler task, taskId: %s", taskId)
+		logger.WithField(logging.TaskIDKey, taskId).WithError(err).Error("failed to stop mpc handler task")
 	} else {
-		logger.Debugf("stop mpc task, taskId: %s", taskId)
+		logger.WithField(logging.TaskIDKey, taskId).Debug("stop mpc task")
+	}
+	// the learners remove their spilled data when released, in case they fail to
+	spill.Remove(taskId)
+	m.Lock()
+	task, ok := m.MpcTasks[taskId]
+	delete(m.MpcTasks, taskId)
+	if m.LiveEvaluation != nil {
+		m.LiveEvaluation.Close(taskId)
 	}
+	m.Unlock()
+	if ok {
+		metrics.TaskFinished(taskType, failed, time.Duration(time.Now().UnixNano()-task.AddedTime))
+	}
+	// no-op if the root span is ended with the error of the task
+	tracing.EndTask(taskId, "")
+}
+
+// CancelTask cancels a task in execution. The task is removed from execution pool to free its slot,
+// and its mpc calculation is aborted, the other tasks in execution are not affected.
+// reason is recorded as the error message of the 'Cancelled' task in blockchain.
+// notifyOthers is true if the request comes from the requester, then the local executor requests
+// the other executors of the task to cancel it, failures of which are only logged,
+// as they stop the task when it expires anyway.
+func (m *MpcModelHandler) CancelTask(task blockchain.FLTask, reason string, notifyOthers bool) error {
+	return m.endTask(task, reason, blockchain.TaskCancelled, notifyOthers)
+}
+
+// endTask cancels a task in execution like CancelTask, and records status, 'Cancelled' or 'Timeout', in blockchain
+func (m *MpcModelHandler) endTask(task blockchain.FLTask, reason, status string, notifyOthers bool) error {
+	m.cancelLocalMpcTask(task.TaskID, reason)
+	if err := m.updateTaskFinishStatus(task.TaskID, reason, "", status, nil, nil); err != nil {
+		return errorx.Wrap(err, "failed to record the %s status of task %s", status, task.TaskID)
+	}
+	logger.WithField(logging.TaskIDKey, task.TaskID).Infof("task ended with status %s: %s", status, reason)
+	if !notifyOthers {
+		return nil
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.Identity(task).PrivateKey)
+	for _, dataset := range task.DataSets {
+		if bytes.Equal(dataset.Executor, pubkey[:]) {
+			continue
+		}
+		if _, err := m.sendTaskRequest(dataset.Address, task.TaskID, true, nil); err != nil {
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Warnf("failed to request %s to cancel task", dataset.Address)
+		}
+	}
+	return nil
+}
+
+// cancelLocalMpcTask removes the task from execution pool before aborting it,
+// so that the results computed later are dropped, reason is the error of the task's root span
+func (m *MpcModelHandler) cancelLocalMpcTask(taskId, reason string) {
 	m.Lock()
+	task, ok := m.MpcTasks[taskId]
 	delete(m.MpcTasks, taskId)
+	if m.LiveEvaluation != nil {
+		m.LiveEvaluation.Close(taskId)
+	}
 	m.Unlock()
+	if !ok {
+		logger.WithField(logging.TaskIDKey, taskId).Debug("mpc task not in execution")
+		return
+	}
+	tracing.EndTask(taskId, reason)
+
+	taskType := task.AlgoParam.TaskType
+	if err := m.Mpc.CancelTask(&pbCom.StopTaskRequest{TaskID: taskId, Params: &pbCom.TaskParams{TaskType: taskType}}); err != nil {
+		logger.WithField(logging.TaskIDKey, taskId).WithError(err).Error("failed to cancel mpc task")
+	}
+	spill.Remove(taskId)
+	metrics.TaskFinished(taskType, true, time.Duration(time.Now().UnixNano()-task.AddedTime))
 }
 
-// sendTaskStartRequestToOthers sends "start task" request to other Executors
-func (m *MpcModelHandler) sendTaskStartRequestToOthers(otherParts []string, taskID string) error {
-	for _, participant := range otherParts {
-		err := m.sendTaskStartRequest(participant, taskID)
+// sendTaskStartRequestToOthers sends "start task" request to other Executors, with the fingerprint of the task
+func (m *MpcModelHandler) sendTaskStartRequestToOthers(startRequest *pbCom.StartTaskRequest) error {
+	taskID := startRequest.TaskID
+	fingerprint, err := taskFingerprint(startRequest)
+	if err != nil {
+		return err
+	}
+	alignments := m.offerAlignments(startRequest, fingerprint)
+	for _, participant := range startRequest.Hosts {
+		resp, err := m.sendTaskRequest(participant, taskID, false, fingerprint)
 		if err != nil {
-			logger.WithError(err).Errorf("failed to start other participants task, taskId: %s", taskID)
+			logger.WithField(logging.TaskIDKey, taskID).WithError(err).Error("failed to start other participants task")
+			return err
+		}
+		applyPSIAlgorithm(startRequest, resp)
+		if err := applyAlignment(startRequest, resp, alignments); err != nil {
 			return err
 		}
 	}
 
-	logger.Infof("success send task request to others, taskId: %s ", taskID)
+	logger.WithField(logging.TaskIDKey, taskID).Info("success send task request to others")
 	return nil
 }
 
-// sendTaskStartRequest sends "start task" signal to other Executor
-// if task.AlgoParam.Algo is "dnn-paddlefl-vl", the model will be trained by three parties
-func (m *MpcModelHandler) sendTaskStartRequest(executorHost, taskID string) (err error) {
-	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.PrivateKey)
+// sendTaskRequest sends "start task" signal to other Executor, or "cancel task" signal if isCancel is true
+// if task.AlgoParam.Algo is "dnn-paddlefl-vl", the model will be trained by three parties.
+// fingerprint is set into the request after it's signed, see pbTask.TaskRequest.
+func (m *MpcModelHandler) sendTaskRequest(executorHost, taskID string, isCancel bool, fingerprint *pbTask.TaskFingerprint) (resp *pbTask.TaskResponse, err error) {
+	identity := m.taskIdentity(taskID)
+	pubkey := ecdsa.PublicKeyFromPrivateKey(identity.PrivateKey)
 	in := &pbTask.TaskRequest{
 		PubKey: pubkey[:],
 		TaskID: taskID,
 	}
 	msg, err := util.GetSigMessage(in)
 	if err != nil {
-		return errorx.Internal(err, "failed to get the message to sign for send task start request")
+		return nil, errorx.Internal(err, "failed to get the message to sign for send task start request")
 	}
 
-	sig, err := ecdsa.Sign(m.Node.PrivateKey, hash.HashUsingSha256([]byte(msg)))
+	sig, err := ecdsa.Sign(identity.PrivateKey, hash.HashUsingSha256([]byte(msg)))
 	if err != nil {
-		return errorx.Wrap(err, "failed to sign fl start task")
+		return nil, errorx.Wrap(err, "failed to sign fl start task")
 	}
 	in.Signature = sig[:]
+	in.Fingerprint = fingerprint
 	// send message to remote Executor
 	// reuse gRpc connection
 
 	// may StartTask needs more time
-	ctx, cancel := context.WithTimeout(context.Background(), m.Config.RpcTimeout*3*time.Second)
+	m.RLock()
+	rpcTimeout := m.Config.RpcTimeout
+	m.RUnlock()
+	// the request carries the trace context of the task
+	ctx, cancel := context.WithTimeout(tracing.TaskContext(taskID), rpcTimeout*3)
 	defer cancel()
 
 	peer, err := m.ClusterP2p.GetPeer(executorHost)
 	if err != nil {
-		return errorx.New(errcodes.ErrCodeRPCFindNoPeer, "failed to get peer %s when do rpc request: %s", executorHost, err.Error())
+		err = errorx.New(errcodes.ErrCodeRPCFindNoPeer, "failed to get peer %s when do rpc request: %s", executorHost, err.Error())
+		m.Breaker.Observe(executorHost, err)
+		return nil, err
 	}
 	defer m.ClusterP2p.FreePeer()
 	conn, err := peer.GetConnect()
 	if err != nil {
-		return errorx.New(errcodes.ErrCodeRPCConnect, "failed to get connection with %s: %s", executorHost, err.Error())
+		err = errorx.New(errcodes.ErrCodeRPCConnect, "failed to get connection with %s: %s", executorHost, err.Error())
+		m.Breaker.Observe(executorHost, err)
+		return nil, err
 	}
 	taskClient := pbTask.NewTaskClient(conn)
 
-	if _, err := taskClient.StartTask(ctx, in); err != nil {
-		return errorx.Wrap(err, "failed to send task to others")
+	if isCancel {
+		if _, err := taskClient.CancelTask(ctx, in); err != nil {
+			return nil, errorx.Wrap(err, "failed to send task cancellation to others")
+		}
+		return nil, nil
 	}
-	return nil
+	resp, err = taskClient.StartTask(ctx, in)
+	m.Breaker.Observe(executorHost, err)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to send task to others")
+	}
+	return resp, nil
 }
 
 // UpdateTaskFinishStatus updates task status in blockchain when task finished
 func (m *MpcModelHandler) UpdateTaskFinishStatus(taskId, taskErr, taskResult string) error {
+	return m.updateTaskFinishStatus(taskId, taskErr, taskResult, "", nil, nil)
+}
+
+// updateTaskFinishStatus updates task status in blockchain to 'Finished' or 'Failed',
+// or status if it is 'Cancelled' or 'Timeout', budget is the privacy budget consumed by the finished training task,
+// and batch is the results of the inputs of the finished batch prediction task.
+// The update is buffered by the outbox if the blockchain is unreachable, and recorded when it comes back.
+func (m *MpcModelHandler) updateTaskFinishStatus(taskId, taskErr, taskResult, status string, budget *pbCom.PrivacyBudget,
+	batch []*pbTask.BatchPredictResult) error {
+	u := &statusUpdate{
+		taskID:     taskId,
+		errMessage: taskErr,
+		result:     taskResult,
+		status:     status,
+		budget:     budget,
+		batch:      batch,
+	}
+	err := m.recordTaskStatus(u)
+	if err != nil && m.Outbox.buffer(u, err) {
+		logger.WithField(logging.TaskIDKey, taskId).WithError(err).Warn("blockchain unreachable, task status buffered")
+		return nil
+	}
+	return err
+}
+
+// recordTaskStatus records the terminal status u in blockchain, the status already committed on the chain wins
+func (m *MpcModelHandler) recordTaskStatus(u *statusUpdate) error {
+	taskId, taskErr, taskResult, status := u.taskID, u.errMessage, u.result, u.status
 	// get task details from chain
 	task, err := m.Chain.GetTaskById(taskId)
 	if err != nil {
@@ -314,28 +824,41 @@ func (m *MpcModelHandler) UpdateTaskFinishStatus(taskId, taskErr, taskResult str
 	}
 
 	// check task status, no need to repeatedly update task
-	if task.Status == blockchain.TaskFinished || task.Status == blockchain.TaskFailed {
-		logger.Infof("task status already update, taskId: %s, task.status: %s", taskId, task.Status)
+	if task.Status == blockchain.TaskFinished || task.Status == blockchain.TaskFailed || task.Status == blockchain.TaskCancelled ||
+		task.Status == blockchain.TaskTimeout {
+		if u.bufferedAt.IsZero() {
+			logger.WithField(logging.TaskIDKey, taskId).Infof("task status already update, task.status: %s", task.Status)
+		} else {
+			logger.WithField(logging.TaskIDKey, taskId).Warnf("task already ended with status %s on chain, the buffered status is dropped",
+				task.Status)
+		}
+		m.Audit.Record(task, AuditEventEnd, task.Status, task.ErrMessage)
 		return nil
 	}
 	if task.Status != blockchain.TaskProcessing {
 		return errorx.New(errorx.ErrCodeInternal, "update task status error, task status is not processing")
 	}
 	// invoke the contract to update the task's status
-	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.PrivateKey)
+	identity := m.Node.Identity(task)
+	pubkey := ecdsa.PublicKeyFromPrivateKey(identity.PrivateKey)
 	execTaskOptions := &blockchain.FLTaskExeStatusOptions{
 		Executor:    pubkey[:],
 		TaskID:      taskId,
 		CurrentTime: time.Now().UnixNano(),
 		ErrMessage:  taskErr,
 		Result:      taskResult,
+		Cancelled:   status == blockchain.TaskCancelled,
+		TimedOut:    status == blockchain.TaskTimeout,
+
+		PrivacyBudget: u.budget,
+		BatchResults:  u.batch,
 	}
 	msg, err := util.GetSigMessage(execTaskOptions)
 	if err != nil {
 		return errorx.Internal(err, "failed to get the message to sign for update mpc task")
 	}
 
-	sig, err := ecdsa.Sign(m.Node.PrivateKey, hash.HashUsingSha256([]byte(msg)))
+	sig, err := ecdsa.Sign(identity.PrivateKey, hash.HashUsingSha256([]byte(msg)))
 	if err != nil {
 		return err
 	}
@@ -343,16 +866,32 @@ func (m *MpcModelHandler) UpdateTaskFinishStatus(taskId, taskErr, taskResult str
 	if err := m.Chain.FinishTask(execTaskOptions); err != nil {
 		return err
 	}
+
+	// only the executor recording the terminal status notifies the callback, and never waits for it
+	if status == "" {
+		status = blockchain.TaskFinished
+		if taskErr != "" {
+			status = blockchain.TaskFailed
+		}
+	}
+	m.Callback.Notify(task, status, taskErr, taskResult)
+	m.Audit.Record(task, AuditEventEnd, status, taskErr)
 	return nil
 }
 
+// RunOutbox records the task status buffered during blockchain outages when it comes back, until ctx is done
+func (m *MpcModelHandler) RunOutbox(ctx context.Context) {
+	m.Outbox.Run(ctx, m.recordTaskStatus)
+}
+
 // SaveModel persists a model
 // called by MPC
 func (m *MpcModelHandler) SaveModel(result *pbCom.TrainTaskResult) error {
 	m.RLock()
-	if _, ok := m.MpcTasks[result.TaskID]; !ok {
+	task, ok := m.MpcTasks[result.TaskID]
+	if !ok {
 		m.RUnlock()
-		logger.Debugf("train task already execution complete, taskId: %s", result.TaskID)
+		logger.WithField(logging.TaskIDKey, result.TaskID).Debug("train task already execution complete")
 		return nil
 	}
 	m.RUnlock()
@@ -363,9 +902,23 @@ func (m *MpcModelHandler) SaveModel(result *pbCom.TrainTaskResult) error {
 		return nil
 	}
 
+	// the model updated by incremental training isn't committed if its cost
+	// is greater than the one of the base model by more than the DriftTolerance
+	if delta := result.MetricDelta; delta != nil {
+		logger.WithField(logging.TaskIDKey, result.TaskID).Infof("incremental training updated %s from %v to %v",
+			delta.Metric, delta.Base, delta.Updated)
+		if delta.Updated-delta.Base > task.AlgoParam.TrainParams.DriftTolerance {
+			err := errorx.New(errcodes.ErrCodeModelDrift, "%s of the updated model %v drifts from %v of the base model by more than %v",
+				delta.Metric, delta.Updated, delta.Base, task.AlgoParam.TrainParams.DriftTolerance)
+			m.updateTaskStatusAndStopLocalMpc(result.TaskID, err.Error(), "")
+			return err
+		}
+	}
+
 	// store model
 	r := bytes.NewReader(result.Model)
-	if _, err := m.Storage.ModelStorage.Write(r, result.TaskID); err != nil {
+	name := m.Storage.FileName(storage.KindModel, &task.FLTask, result.TaskID)
+	if _, err := m.Storage.ModelStorage.Upload(tracing.TaskContext(result.TaskID), name, r); err != nil {
 		err := errorx.New(errorx.ErrCodeInternal, "failed to locally save task model")
 		m.updateTaskStatusAndStopLocalMpc(result.TaskID, err.Error(), "")
 		return err
@@ -376,27 +929,157 @@ func (m *MpcModelHandler) SaveModel(result *pbCom.TrainTaskResult) error {
 		textEvalMetricScores, err := json.Marshal(result.EvalMetricScores)
 		if err == nil {
 			r := bytes.NewReader(textEvalMetricScores)
-			if _, errS := m.Storage.EvaluationStorage.Write(r, result.TaskID); errS != nil {
-				logger.Warnf("failed to locally save evaluation result: %s, taskId: %s, error: %s", string(textEvalMetricScores), result.TaskID, errS.Error())
+			name := m.Storage.FileName(storage.KindEvaluation, &task.FLTask, result.TaskID)
+			if _, errS := m.Storage.EvaluationStorage.Upload(tracing.TaskContext(result.TaskID), name, r); errS != nil {
+				logger.WithField(logging.TaskIDKey, result.TaskID).Warnf("failed to locally save evaluation result: %s, error: %s", string(textEvalMetricScores), errS.Error())
 			}
 		} else {
-			logger.Warnf("failed to jsonMarshal evaluation resul, taskId: %s, error: %s", result.TaskID, err.Error())
+			logger.WithField(logging.TaskIDKey, result.TaskID).Warnf("failed to jsonMarshal evaluation resul, error: %s", err.Error())
+		}
+
+	}
+	// store feature importance next to the evaluation result, and keep going forward even if some errors happen
+	m.saveFeatureImportances(&task.FLTask, result)
+	// store lineage of the model next to it, and keep going forward even if some errors happen
+	m.saveModelLineage(&task.FLTask, result)
+	logger.WithField(logging.TaskIDKey, result.TaskID).Debug("successfully saved model")
+	m.finishTrainTask(result)
+	return nil
+}
+
+// finishTrainTask updates the status of the training task into chain like updateTaskStatusAndStopLocalMpc,
+// along with the privacy budget consumed if the model is trained with differential privacy
+func (m *MpcModelHandler) finishTrainTask(result *pbCom.TrainTaskResult) {
+	if err := m.updateTaskFinishStatus(result.TaskID, "", "", "", result.PrivacyBudget, nil); err != nil {
+		logger.WithField(logging.TaskIDKey, result.TaskID).WithError(err).Error("fail update task status into chain error")
+	} else {
+		logger.WithField(logging.TaskIDKey, result.TaskID).Info("success update task status into chain")
+	}
+	tracing.EndTask(result.TaskID, "")
+	m.stopLocalMpcTask(result.TaskID, false)
+}
+
+// saveFeatureImportances stores the importance of the local features of the linear or logistic regression model
+// trained by task, the models of other algorithms are skipped
+func (m *MpcModelHandler) saveFeatureImportances(task blockchain.FLTask, result *pbCom.TrainTaskResult) {
+	algo := task.AlgoParam.Algo
+	if algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+		return
+	}
+	model, err := reModel.TrainModelsFromBytes(result.Model)
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).Warnf("failed to parse model to compute feature importance, error: %s", err.Error())
+		return
+	}
+	if err := m.Storage.SaveFeatureImportances(tracing.TaskContext(task.TaskID), task, FeatureImportances(model)); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).Warnf("failed to locally save feature importance, error: %s", err.Error())
+	}
+}
+
+// saveModelLineage stores the version and lineage metadata of the model trained by task,
+// under the key of the model suffixed with ModelLineageSuffix, along with the metric against
+// the base model if the model is trained incrementally, the privacy budget consumed, and the metric of early stopping
+func (m *MpcModelHandler) saveModelLineage(task blockchain.FLTask, result *pbCom.TrainTaskResult) {
+	lineage := blockchain.NewModelLineage(task)
+	lineage.MetricDelta = result.MetricDelta
+	lineage.PrivacyBudget = result.PrivacyBudget
+	lineage.EarlyStop = result.EarlyStop
+	textLineage, err := json.Marshal(lineage)
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).Warnf("failed to jsonMarshal model lineage, error: %s", err.Error())
+		return
+	}
+	key := m.Storage.FileName(storage.KindModel, task, task.TaskID) + blockchain.ModelLineageSuffix
+	if _, err := m.Storage.ModelStorage.Upload(tracing.TaskContext(task.TaskID), key, bytes.NewReader(textLineage)); err != nil {
+		logger.WithField(logging.TaskIDKey, task.TaskID).Warnf("failed to locally save model lineage: %s, error: %s", string(textLineage), err.Error())
+	}
+}
+
+// SaveCheckpoint replaces the checkpoint of a training task
+// called by MPC
+func (m *MpcModelHandler) SaveCheckpoint(taskID string, data []byte) error {
+	if m.Storage.CheckpointStorage == nil {
+		return errorx.New(errcodes.ErrCodeNotSupported, "checkpoint storage is not configured")
+	}
+	ctx := tracing.TaskContext(taskID)
+	name := m.checkpointName(taskID)
+	_, err := m.Storage.CheckpointStorage.Upload(ctx, name, bytes.NewReader(data))
+	if err != nil && errorx.Is(err, errorx.ErrCodeAlreadyExists) {
+		// local storage doesn't overwrite files, remove the last checkpoint first
+		if err := m.Storage.CheckpointStorage.Delete(ctx, name); err != nil {
+			return errorx.Wrap(err, "failed to delete the last checkpoint")
 		}
+		_, err = m.Storage.CheckpointStorage.Upload(ctx, name, bytes.NewReader(data))
+	}
+	if err != nil {
+		return errorx.Wrap(err, "failed to save checkpoint")
+	}
+	return nil
+}
 
+// LoadCheckpoint returns the checkpoint of a training task, or nil if there is none
+// called by MPC
+func (m *MpcModelHandler) LoadCheckpoint(taskID string) ([]byte, error) {
+	if m.Storage.CheckpointStorage == nil {
+		return nil, nil
+	}
+	r, err := m.Storage.CheckpointStorage.Download(tracing.TaskContext(taskID), m.checkpointName(taskID))
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to load checkpoint")
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to read checkpoint")
+	}
+	return data, nil
+}
+
+// DeleteCheckpoint removes the checkpoint of a training task
+// called by MPC
+func (m *MpcModelHandler) DeleteCheckpoint(taskID string) error {
+	if m.Storage.CheckpointStorage == nil {
+		return nil
+	}
+	if err := m.Storage.CheckpointStorage.Delete(tracing.TaskContext(taskID), m.checkpointName(taskID)); err != nil {
+		return errorx.Wrap(err, "failed to delete checkpoint")
 	}
-	logger.Debugf("successfully saved model, taskId: %s", result.TaskID)
-	m.updateTaskStatusAndStopLocalMpc(result.TaskID, "", "")
 	return nil
 }
 
+// checkpointName returns the name of the checkpoint of the training task taskID, which is rendered from the task
+// in execution, or from the one on the chain if it's no longer in execution
+func (m *MpcModelHandler) checkpointName(taskID string) string {
+	if m.Storage.Names[storage.KindCheckpoint] == nil {
+		return taskID
+	}
+	m.RLock()
+	task, ok := m.MpcTasks[taskID]
+	m.RUnlock()
+	if ok {
+		return m.Storage.FileName(storage.KindCheckpoint, &task.FLTask, taskID)
+	}
+	t, err := m.Chain.GetTaskById(taskID)
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, taskID).WithError(err).Warn("failed to get task to name its checkpoint")
+		return taskID
+	}
+	return m.Storage.FileName(storage.KindCheckpoint, t, taskID)
+}
+
 // SavePredictOut persists predicting outcomes
 // Outcomes will be zero-value if the holder does not have target feature
 // called by MPC
 func (m *MpcModelHandler) SavePredictOut(result *pbCom.PredictTaskResult) error {
 	m.RLock()
-	if _, ok := m.MpcTasks[result.TaskID]; !ok {
+	task, ok := m.MpcTasks[result.TaskID]
+	if !ok {
 		m.RUnlock()
-		logger.Debugf("predict task already execution complete, taskId: %s", result.TaskID)
+		logger.WithField(logging.TaskIDKey, result.TaskID).Debug("predict task already execution complete")
 		return nil
 	}
 	m.RUnlock()
@@ -405,39 +1088,137 @@ func (m *MpcModelHandler) SavePredictOut(result *pbCom.PredictTaskResult) error
 		m.updateTaskStatusAndStopLocalMpc(result.TaskID, result.ErrMsg, "")
 		return nil
 	}
+	// each party reports the drift of its own columns
+	if result.Drift.GetDrifted() {
+		logger.WithField(logging.TaskIDKey, result.TaskID).Warnf("drift detected in prediction, scores: %v, threshold: %v",
+			result.Drift.Scores, result.Drift.Threshold)
+		m.Callback.NotifyDrift(&task.FLTask, result.Drift)
+	}
 
 	if len(result.Outcomes) == 0 {
 		// predict successfully, but local node has no outcomes because its samples have no Label
 		logger.Debugf("no label parties do not need to store predict result")
-		m.stopLocalMpcTask(result.TaskID)
+		m.stopLocalMpcTask(result.TaskID, false)
 		return nil
 	}
+	if task.batch != nil {
+		return m.saveBatchPredictOut(&task.FLTask, result, task.batch)
+	}
 
 	// save prediction result
-	r := bytes.NewReader(result.Outcomes)
+	content, err := sealPredictOut(&task.FLTask, result.Outcomes)
+	if err != nil {
+		err := errorx.Wrap(err, "failed to encrypt task predict result, taskId: %s", result.TaskID)
+		m.updateTaskStatusAndStopLocalMpc(result.TaskID, err.Error(), "")
+		return err
+	}
 	// if the storage type of the prediction result is xuperdb, sResult is fileID, otherwise sResult is empty
-	psResult, err := m.Storage.PredictStorage.Write(r, result.TaskID)
+	name := m.Storage.FileName(storage.KindPrediction, &task.FLTask, result.TaskID)
+	psResult, err := m.Storage.PredictStorage.Upload(tracing.TaskContext(result.TaskID), name, bytes.NewReader(content))
 	if err != nil {
 		err := errorx.Wrap(err, "failed to save task predict result, taskId: %s", result.TaskID)
 		m.updateTaskStatusAndStopLocalMpc(result.TaskID, err.Error(), "")
 		return err
 	}
-	logger.Debugf("success save predict out, taskId: %s, psResult: %s", result.TaskID, psResult)
+	m.signPredictOut(name, result.TaskID, 0, content)
+	logger.WithField(logging.TaskIDKey, result.TaskID).Debugf("success save predict out, psResult: %s", psResult)
 	m.updateTaskStatusAndStopLocalMpc(result.TaskID, "", psResult)
 	return nil
 }
 
+// sealPredictOut encrypts the prediction result with the resultPublicKey of the task if it is set,
+// so that only the requester holding the private key decrypts it, wherever it is stored
+func sealPredictOut(task blockchain.FLTask, content []byte) ([]byte, error) {
+	if len(task.AlgoParam.GetResultPublicKey()) == 0 {
+		return content, nil
+	}
+	return envelope.Seal(task.AlgoParam.ResultPublicKey, content)
+}
+
+// signPredictOut signs the prediction result of the input batchIndex of the task taskID stored with name,
+// and stores the signature next to it, keep going forward even if some errors happen as the result is saved
+func (m *MpcModelHandler) signPredictOut(name, taskID string, batchIndex int32, content []byte) {
+	if m.Storage.SignatureStorage == nil {
+		return
+	}
+	sig, err := blockchain.SignResult(m.taskIdentity(taskID).PrivateKey, taskID, batchIndex, content)
+	if err == nil {
+		err = m.Storage.SaveResultSignature(tracing.TaskContext(taskID), name, sig)
+	}
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, taskID).WithError(err).Warnf("failed to sign prediction result %s", name)
+	}
+}
+
+// saveBatchPredictOut splits the outcomes of the batch prediction into the results of inputs, and saves them.
+// The task fails only if no input is predicted, the errors of the inputs not predicted are recorded in blockchain.
+func (m *MpcModelHandler) saveBatchPredictOut(task blockchain.FLTask, result *pbCom.PredictTaskResult, batch *predictBatch) error {
+	contents, results, err := batch.split(result.Outcomes)
+	if err != nil {
+		err := errorx.Wrap(err, "failed to split batch predict result, taskId: %s", result.TaskID)
+		m.updateTaskStatusAndStopLocalMpc(result.TaskID, err.Error(), "")
+		return err
+	}
+	taskErr := "no input of the batch prediction is predicted"
+	for i, content := range contents {
+		if content == nil {
+			continue
+		}
+		content, err := sealPredictOut(task, content)
+		if err != nil {
+			results[i].ErrMessage = fmt.Sprintf("failed to encrypt predict result: %s", err.Error())
+			results[i].Samples = 0
+			continue
+		}
+		name := m.Storage.FileName(storage.KindPrediction, task, BatchResultName(result.TaskID, int32(i)))
+		psResult, err := m.Storage.PredictStorage.Upload(tracing.TaskContext(result.TaskID), name, bytes.NewReader(content))
+		if err != nil {
+			results[i].ErrMessage = fmt.Sprintf("failed to save predict result: %s", err.Error())
+			results[i].Samples = 0
+			continue
+		}
+		m.signPredictOut(name, result.TaskID, int32(i), content)
+		results[i].Result = psResult
+		taskErr = ""
+	}
+	logger.WithField(logging.TaskIDKey, result.TaskID).Debugf("success save batch predict out, results: %v", results)
+
+	if err := m.updateTaskFinishStatus(result.TaskID, taskErr, results[0].Result, "", nil, results); err != nil {
+		logger.WithField(logging.TaskIDKey, result.TaskID).WithError(err).Error("fail update task status into chain error")
+	} else {
+		logger.WithField(logging.TaskIDKey, result.TaskID).Info("success update task status into chain")
+	}
+	tracing.EndTask(result.TaskID, taskErr)
+	m.stopLocalMpcTask(result.TaskID, taskErr != "")
+	return nil
+}
+
 // getMpcStartTaskParam get the parameters required for task startup
 func (m *MpcModelHandler) getMpcStartTaskParam(task blockchain.FLTask) (*pbCom.StartTaskRequest, error) {
 	partParam, err := m.getTaskParticipantParam(task)
 	if err != nil {
 		return nil, err
 	}
+	if partParam.batch != nil {
+		m.Lock()
+		if t, ok := m.MpcTasks[task.TaskID]; ok {
+			t.batch = partParam.batch
+		}
+		m.Unlock()
+	}
 
 	// train params
 	trainParam := task.AlgoParam.TrainParams
 	trainParam.IdName = partParam.psiLabel
 	trainParam.IsTagPart = partParam.isTagPart
+	if trainParam.PsiAlgorithm == "" {
+		trainParam.PsiAlgorithm = m.PSIAlgorithm
+	}
+	if trainParam.Accuracy == 0 {
+		trainParam.Accuracy = m.DefaultAccuracy
+	}
+	// set by the Executors agreeing on the sample alignment when the task starts, see PrepareAlignment
+	trainParam.AlignmentKey = ""
 
 	modeParam := &pbCom.TrainModels{}
 	// set task params
@@ -462,22 +1243,37 @@ func (m *MpcModelHandler) getMpcStartTaskParam(task blockchain.FLTask) (*pbCom.S
 	// for predict task, model is required
 	if task.AlgoParam.TaskType == pbCom.TaskType_PREDICT {
 		// get the model locally and convert it to the model parameters
-		model, err := m.getTaskModel(task.AlgoParam.ModelTaskID)
+		model, err := m.getTaskModel(task.TaskID, task.AlgoParam.ModelTaskID)
 		if err != nil {
 			return nil, err
 		}
 		startTaskReqs.Params.ModelParams = model
 		startTaskReqs.Params.ModelParams.IdName = partParam.psiLabel
+		startTaskReqs.Params.ModelParams.PsiAlgorithm = trainParam.PsiAlgorithm
+		startTaskReqs.Params.ModelParams.DriftThreshold = trainParam.DriftThreshold
+		startTaskReqs.Params.ModelParams.WithScores = trainParam.WithScores
+		startTaskReqs.Params.ModelParams.WithExplanations = trainParam.WithExplanations
 	}
-	logger.Infof("get mpc task start param success, taskId: %s, param is: %+v, otherParts: %+v",
-		task.TaskID, startTaskReqs, partParam.otherParts)
+	// for incremental training, the base model is updated with new samples,
+	// the task is cloned to keep the model out of the task and its lineage
+	if task.AlgoParam.TaskType == pbCom.TaskType_LEARN && trainParam.Incremental {
+		model, err := m.getTaskModel(task.TaskID, task.AlgoParam.ModelTaskID)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to get base model %s", task.AlgoParam.ModelTaskID)
+		}
+		trainParam = proto.Clone(trainParam).(*pbCom.TrainParams)
+		trainParam.BaseModel = model
+		startTaskReqs.Params.TrainParams = trainParam
+	}
+	logger.WithField(logging.TaskIDKey, task.TaskID).Infof("get mpc task start param success, param is: %+v, otherParts: %+v",
+		startTaskReqs, partParam.otherParts)
 
 	return startTaskReqs, nil
 }
 
 // getTaskParticipantParam get parameters for task execution
 func (m *MpcModelHandler) getTaskParticipantParam(task blockchain.FLTask) (partParam ParticipantParams, err error) {
-	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.PrivateKey)
+	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.Identity(task).PrivateKey)
 	var otherParts []string
 
 	for _, dataset := range task.DataSets {
@@ -487,14 +1283,16 @@ func (m *MpcModelHandler) getTaskParticipantParam(task blockchain.FLTask) (partP
 			if err != nil {
 				return partParam, err
 			}
-			reader, err := m.Download.GetSampleFile(dataset.DataID, m.Chain)
-			if err != nil {
-				logger.Debugf("get sample file error, taskId: %s, err: %v", task.TaskID, err)
-				return partParam, err
+			var columns []string
+			if len(dataset.Columns) > 0 {
+				columns = taskColumns(dataset, task.AlgoParam.TrainParams.Label, task.AlgoParam.TrainParams.WeightColumn, isTagPart && task.AlgoParam.TaskType == pbCom.TaskType_LEARN)
+			}
+			var fileText []byte
+			if len(dataset.BatchDataIDs) > 0 && task.AlgoParam.TaskType == pbCom.TaskType_PREDICT {
+				fileText, partParam.batch, err = m.getBatchSampleFile(task.TaskID, dataset, columns)
+			} else {
+				fileText, err = m.getSampleFileText(task.TaskID, dataset.DataID, columns)
 			}
-			fileText, err := m.getTextByReader(reader)
-			reader.Close()
-
 			if err != nil {
 				return partParam, err
 			}
@@ -509,24 +1307,153 @@ func (m *MpcModelHandler) getTaskParticipantParam(task blockchain.FLTask) (partP
 	partParam.otherParts = otherParts
 
 	// if task's execution use paddlefl
-	paddleFLNodes := [3]string{}
 	if task.AlgoParam.Algo == pbCom.Algorithm_DNN_PADDLEFL_VL {
 		nodes, err := m.Chain.ListExecutorNodes()
 		if err != nil {
 			return partParam, err
 		}
-		for _, v := range nodes {
-			paddleFLNodes[v.PaddleFLRole] = v.PaddleFLAddress
-			if bytes.Equal(v.ID, pubkey[:]) {
-				partParam.PaddleFLRole = v.PaddleFLRole
-			}
+		partParam.PaddleFLNodes, partParam.PaddleFLRole, err = paddleFLParties(task, nodes, pubkey[:])
+		if err != nil {
+			return partParam, err
 		}
 	}
-	partParam.PaddleFLNodes = paddleFLNodes
 
 	return partParam, nil
 }
 
+// getSampleFileText downloads the sample file dataID of task, projected to columns if they're set,
+// and retries the download failed by network errors, see FileDownload.MaxRetries.
+// The dataOwner node is requested to project the file if it supports, see FileDownload.GetSampleFileColumns,
+// otherwise the whole file is downloaded and projected locally.
+func (m *MpcModelHandler) getSampleFileText(taskID, dataID string, columns []string) ([]byte, error) {
+	_, span := tracing.StartSpan(taskID, "sample.Download", attribute.String("sample.id", dataID))
+	var fileText []byte
+	var projected bool
+	// files of the tasks published to the previous identity are authorized to it
+	download := m.Download
+	if m.Node.Previous != nil {
+		download.NodePrivateKey = m.taskIdentity(taskID).PrivateKey
+	}
+	// the file is downloaded again if the download or the reading fails by network errors
+	err := download.withRetries(dataID, func() error {
+		reader, p, err := download.GetSampleFileColumns(dataID, columns, m.Chain)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		projected = p
+		fileText, err = m.getTextByReader(reader)
+		return err
+	})
+	if err != nil {
+		tracing.End(span, err)
+		logger.WithField(logging.TaskIDKey, taskID).Debugf("get sample file error, err: %v", err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Bool("sample.projected", projected))
+	tracing.End(span, nil)
+	if len(columns) == 0 {
+		return fileText, nil
+	}
+	if projected {
+		if err := checkProjectedColumns(fileText, columns); err != nil {
+			return nil, errorx.New(errcodes.ErrCodeIntegrity, "sample file %s projected by the dataOwner node is invalid: %s", dataID, err.Error())
+		}
+		return fileText, nil
+	}
+	if fileText, err = csv.SelectColumns(fileText, columns); err != nil {
+		return nil, errorx.New(errcodes.ErrCodeParam, "failed to select columns of sample file %s: %s", dataID, err.Error())
+	}
+	return fileText, nil
+}
+
+// checkProjectedColumns checks the first row of the projected sample file consists of columns in order,
+// a column specified by the index matches any name
+func checkProjectedColumns(fileText []byte, columns []string) error {
+	header, err := gocsv.NewReader(bytes.NewReader(fileText)).Read()
+	if err != nil {
+		return fmt.Errorf("failed to read the header: %v", err)
+	}
+	if len(header) != len(columns) {
+		return fmt.Errorf("expected columns %s, got %s", strings.Join(columns, ","), strings.Join(header, ","))
+	}
+	for i, column := range columns {
+		if _, err := strconv.Atoi(column); err != nil && header[i] != column {
+			return fmt.Errorf("expected columns %s, got %s", strings.Join(columns, ","), strings.Join(header, ","))
+		}
+	}
+	return nil
+}
+
+// getBatchSampleFile downloads the sample files of the inputs of the batch prediction task, and merges them
+// into one sample file. The inputs failing to be downloaded are skipped, and their errors are reported
+// in the results of the task. The inputs are projected to columns if they're set, see getSampleFileText
+func (m *MpcModelHandler) getBatchSampleFile(taskID string, dataset *pbTask.DataForTask, columns []string) ([]byte, *predictBatch, error) {
+	dataIDs := append([]string{dataset.DataID}, dataset.BatchDataIDs...)
+	contents := make([][]byte, len(dataIDs))
+	errs := make([]string, len(dataIDs))
+	for i, dataID := range dataIDs {
+		content, err := m.getSampleFileText(taskID, dataID, columns)
+		if err != nil {
+			logger.WithField(logging.TaskIDKey, taskID).WithError(err).Warnf("failed to get sample file %s of batch input %d", dataID, i)
+			errs[i] = fmt.Sprintf("failed to get sample file %s: %s", dataID, err.Error())
+			continue
+		}
+		contents[i] = content
+	}
+	return mergeBatchInputs(contents, errs, dataset.PsiLabel)
+}
+
+// taskColumns returns the columns of the samples of dataset used by the task, made up of psiLabel,
+// the columns selected, and the label and the weight column if withLabel is true, which are kept even if they aren't selected
+func taskColumns(dataset *pbTask.DataForTask, label, weightColumn string, withLabel bool) []string {
+	columns := []string{dataset.PsiLabel}
+	for _, column := range dataset.Columns {
+		if column != dataset.PsiLabel && column != label && (column != weightColumn || !withLabel) {
+			columns = append(columns, column)
+		}
+	}
+	if withLabel {
+		if weightColumn != "" {
+			columns = append(columns, weightColumn)
+		}
+		columns = append(columns, label)
+	}
+	return columns
+}
+
+// paddleFLParties selects the PaddleFL containers of the task's executors from nodes, indexed by PaddleFLRole,
+// and returns the role of the local node. The network may have more executors than the task,
+// so only the task's executors are selected, and each of them must have a distinct role.
+func paddleFLParties(task blockchain.FLTask, nodes blockchain.ExecutorNodes, localID []byte) (
+	paddleFLNodes [3]string, localRole int, err error) {
+	for _, dataset := range task.DataSets {
+		var node *blockchain.ExecutorNode
+		for i := range nodes {
+			if bytes.Equal(nodes[i].ID, dataset.Executor) {
+				node = &nodes[i]
+				break
+			}
+		}
+		if node == nil {
+			return paddleFLNodes, localRole, errorx.New(errcodes.ErrCodeNotFound, "executor %s of the task not found", dataset.Address)
+		}
+		if node.PaddleFLRole < 0 || node.PaddleFLRole >= len(paddleFLNodes) {
+			return paddleFLNodes, localRole, errorx.New(errcodes.ErrCodeParam, "invalid paddleFLRole %d of executor %s, it must be 0, 1 or 2",
+				node.PaddleFLRole, node.Name)
+		}
+		if paddleFLNodes[node.PaddleFLRole] != "" {
+			return paddleFLNodes, localRole, errorx.New(errcodes.ErrCodeParam, "executors of the task have the same paddleFLRole %d",
+				node.PaddleFLRole)
+		}
+		paddleFLNodes[node.PaddleFLRole] = node.PaddleFLAddress
+		if bytes.Equal(node.ID, localID) {
+			localRole = node.PaddleFLRole
+		}
+	}
+	return paddleFLNodes, localRole, nil
+}
+
 // getTargetPart determine whether local sample has label by parsing file extra information
 func (m *MpcModelHandler) getTargetPart(fileID, labelName string) (bool, error) {
 	sampleFile, err := m.Chain.GetFileByID(fileID)
@@ -548,14 +1475,23 @@ func (m *MpcModelHandler) getTargetPart(fileID, labelName string) (bool, error)
 func (m *MpcModelHandler) getTextByReader(reader io.ReadCloser) ([]byte, error) {
 	text, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return nil, errorx.New(errorx.ErrCodeInternal, "failed to get text by reader")
+		// keep the code of integrity errors returned by verified readers
+		return nil, errorx.Wrap(err, "failed to get text by reader")
 	}
 	return text, nil
 }
 
-// getTaskModel get model for prediction task
-func (m *MpcModelHandler) getTaskModel(taskId string) (*pbCom.TrainModels, error) {
-	model, err := m.Storage.ModelStorage.Read(taskId)
+// getTaskModel get model trained by task modelTaskID for prediction or incremental training task taskID
+func (m *MpcModelHandler) getTaskModel(taskID, modelTaskID string) (*pbCom.TrainModels, error) {
+	name := modelTaskID
+	if m.Storage.Names[storage.KindModel] != nil {
+		modelTask, err := m.Chain.GetTaskById(modelTaskID)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to get the training task of the model")
+		}
+		name = m.Storage.FileName(storage.KindModel, modelTask, modelTaskID)
+	}
+	model, err := m.Storage.ModelStorage.Download(tracing.TaskContext(taskID), name)
 	if err != nil {
 		return nil, err
 	}
@@ -572,6 +1508,52 @@ func (m *MpcModelHandler) getTaskModel(taskId string) (*pbCom.TrainModels, error
 	return reTrainModel, nil
 }
 
+// Shutdown refuses new tasks, and waits at most timeout for the tasks in execution to finish,
+// so that their models and prediction results are saved completely.
+// Tasks still in execution after the deadline are cancelled and the other executors are notified,
+// then their status is 'Cancelled' in blockchain instead of staying in 'Processing'.
+func (m *MpcModelHandler) Shutdown(timeout time.Duration) {
+	m.Lock()
+	m.draining = true
+	m.Unlock()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(shutdownCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.RLock()
+		taskIDs := make([]string, 0, len(m.MpcTasks))
+		for taskID := range m.MpcTasks {
+			taskIDs = append(taskIDs, taskID)
+		}
+		m.RUnlock()
+		if len(taskIDs) == 0 {
+			logger.Info("no task in execution, mpc handler drained")
+			return
+		}
+
+		select {
+		case <-deadline.C:
+			logger.Warnf("%d tasks still in execution after %v, cancel them", len(taskIDs), timeout)
+			reason := fmt.Sprintf("executor %s shut down before the task finished", m.Node.Name)
+			for _, taskID := range taskIDs {
+				task, err := m.Chain.GetTaskById(taskID)
+				if err != nil {
+					logger.WithField(logging.TaskIDKey, taskID).WithError(err).Error("failed to get task from chain, stop it locally")
+					m.cancelLocalMpcTask(taskID, reason)
+					continue
+				}
+				if err := m.CancelTask(task, reason, true); err != nil {
+					logger.WithField(logging.TaskIDKey, taskID).WithError(err).Error("failed to cancel task on shutdown")
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // Close waits until all inner services stop
 func (m *MpcModelHandler) Close() {
 	m.Mpc.Stop()
@@ -0,0 +1,291 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/memory"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/envelope"
+)
+
+func TestCancelTask(t *testing.T) {
+	h, chain, m := newResourceHandler(t, ResourceLimits{})
+	task := newTask("train-1", pbCom.TaskType_LEARN)
+	checkErr(t, h.addTaskIntoMpcHandler(task))
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-2", pbCom.TaskType_LEARN)))
+
+	checkErr(t, h.CancelTask(task, "task cancelled by the requester", false))
+	if len(m.cancelled) != 1 || m.cancelled[0] != "train-1" {
+		t.Fatalf("expected mpc task cancelled, got: %v", m.cancelled)
+	}
+	if _, ok := h.MpcTasks["train-1"]; ok {
+		t.Error("cancelled task should be removed from execution pool")
+	}
+	if _, ok := h.MpcTasks["train-2"]; !ok {
+		t.Error("other tasks should keep running")
+	}
+	if len(chain.cancelled) != 1 || chain.finished["train-1"] != "task cancelled by the requester" {
+		t.Errorf("expected cancelled status recorded with the reason, got: %v, %v", chain.cancelled, chain.finished)
+	}
+
+	// the slot is freed for new tasks
+	if tNum, _ := h.GetAvailableTasksNum(); tNum != 9 {
+		t.Errorf("expected 9 available train tasks, got %d", tNum)
+	}
+}
+
+func TestCheckMpcTimeOutTasks(t *testing.T) {
+	h, chain, m := newResourceHandler(t, ResourceLimits{})
+	h.MpcTaskMaxExecTime = time.Hour
+	h.MpcTaskMaxTimeout = 2 * time.Hour
+
+	expired := newTask("train-1", pbCom.TaskType_LEARN)
+	expired.AlgoParam.Timeout = 1
+	unbounded := newTask("train-2", pbCom.TaskType_LEARN)
+	unbounded.AlgoParam.Timeout = int64((10 * time.Hour).Seconds())
+	checkErr(t, h.addTaskIntoMpcHandler(expired))
+	checkErr(t, h.addTaskIntoMpcHandler(unbounded))
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("predict-1", pbCom.TaskType_PREDICT)))
+
+	for id, expected := range map[string]time.Duration{"train-1": time.Second, "train-2": 2 * time.Hour, "predict-1": time.Hour} {
+		task := h.MpcTasks[id]
+		if timeout := time.Duration(task.ExpiredTime - task.AddedTime); timeout != expected {
+			t.Errorf("expected timeout %v of task %s, got %v", expected, id, timeout)
+		}
+	}
+
+	// train-1 was added a second ago
+	h.MpcTasks["train-1"].AddedTime -= int64(time.Second)
+	h.MpcTasks["train-1"].ExpiredTime -= int64(time.Second)
+	h.CheckMpcTimeOutTasks()
+	if len(m.cancelled) != 1 || m.cancelled[0] != "train-1" {
+		t.Fatalf("expected the expired task cancelled, got: %v", m.cancelled)
+	}
+	if len(chain.timedOut) != 1 || !strings.Contains(chain.finished["train-1"], "time out after 1s") {
+		t.Errorf("expected timeout status recorded with the reason, got: %v, %v", chain.timedOut, chain.finished)
+	}
+	if len(h.MpcTasks) != 2 {
+		t.Errorf("other tasks should keep running, got: %v", h.MpcTasks)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	h, chain, m := newResourceHandler(t, ResourceLimits{})
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-1", pbCom.TaskType_LEARN)))
+
+	h.Shutdown(10 * time.Millisecond)
+	if len(m.cancelled) != 1 || m.cancelled[0] != "train-1" {
+		t.Fatalf("expected the task in execution cancelled after the deadline, got: %v", m.cancelled)
+	}
+	if len(chain.cancelled) != 1 || !strings.Contains(chain.finished["train-1"], "shut down") {
+		t.Errorf("expected cancelled status recorded with the reason, got: %v, %v", chain.cancelled, chain.finished)
+	}
+
+	// new tasks are refused after shutdown
+	err := h.addTaskIntoMpcHandler(newTask("train-2", pbCom.TaskType_LEARN))
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeShuttingDown {
+		t.Errorf("expected error code %s, got: %v", errcodes.ErrCodeShuttingDown, err)
+	}
+}
+
+func TestTaskColumns(t *testing.T) {
+	dataset := &pbTask.DataForTask{PsiLabel: "id", Columns: []string{"MEDV", "AGE", "id", "TAX"}}
+	if columns := taskColumns(dataset, "MEDV", "", true); strings.Join(columns, ",") != "id,AGE,TAX,MEDV" {
+		t.Errorf("unexpected columns of the tag part for training: %v", columns)
+	}
+	if columns := taskColumns(dataset, "MEDV", "", false); strings.Join(columns, ",") != "id,AGE,TAX" {
+		t.Errorf("unexpected columns for prediction: %v", columns)
+	}
+	// the weight column is kept for training on the tag part
+	if columns := taskColumns(dataset, "MEDV", "W", true); strings.Join(columns, ",") != "id,AGE,TAX,W,MEDV" {
+		t.Errorf("unexpected columns of the tag part for weighted training: %v", columns)
+	}
+}
+
+func TestCheckProjectedColumns(t *testing.T) {
+	fileText := []byte("id,age,income\n1,20,100\n")
+	checkErr(t, checkProjectedColumns(fileText, []string{"id", "age", "income"}))
+	checkErr(t, checkProjectedColumns(fileText, []string{"id", "1", "income"}))
+	if err := checkProjectedColumns(fileText, []string{"id", "income", "age"}); err == nil {
+		t.Error("expected error for columns out of order")
+	}
+	if err := checkProjectedColumns(fileText, []string{"id", "age"}); err == nil {
+		t.Error("expected error for extra columns")
+	}
+}
+
+func TestPaddleFLParties(t *testing.T) {
+	nodes := blockchain.ExecutorNodes{
+		{ID: []byte("e1"), Name: "executor1", PaddleFLAddress: "paddlefl-env1:38302", PaddleFLRole: 0},
+		{ID: []byte("e2"), Name: "executor2", PaddleFLAddress: "paddlefl-env2:38303", PaddleFLRole: 1},
+		{ID: []byte("e3"), Name: "executor3", PaddleFLAddress: "paddlefl-env3:38304", PaddleFLRole: 2},
+		// executor of other tasks
+		{ID: []byte("e4"), Name: "executor4", PaddleFLAddress: "paddlefl-env4:38305", PaddleFLRole: 0},
+	}
+	newDNNTask := func(executors ...string) blockchain.FLTask {
+		task := newTask("dnn", pbCom.TaskType_LEARN)
+		for _, e := range executors {
+			task.DataSets = append(task.DataSets, &pbTask.DataForTask{Executor: []byte(e), Address: e})
+		}
+		return task
+	}
+
+	paddleFLNodes, role, err := paddleFLParties(newDNNTask("e3", "e1", "e2"), nodes, []byte("e2"))
+	checkErr(t, err)
+	if paddleFLNodes != [3]string{"paddlefl-env1:38302", "paddlefl-env2:38303", "paddlefl-env3:38304"} || role != 1 {
+		t.Errorf("unexpected paddlefl nodes %v and role %d", paddleFLNodes, role)
+	}
+
+	if _, _, err := paddleFLParties(newDNNTask("e1", "e2", "e4"), nodes, []byte("e1")); err == nil {
+		t.Error("expected error for executors with the same paddleFLRole")
+	}
+	if _, _, err := paddleFLParties(newDNNTask("e1", "e2", "e5"), nodes, []byte("e1")); err == nil {
+		t.Error("expected error for unregistered executor")
+	}
+}
+
+func TestCheckpointStorage(t *testing.T) {
+	backend, err := storage.NewStorageBackend(&config.ExecutorStorageConf{
+		Type:                  "Local",
+		LocalModelStoragePath: t.TempDir(),
+	}, storage.KindCheckpoint)
+	checkErr(t, err)
+	h := &MpcModelHandler{Storage: FileStorage{CheckpointStorage: backend}}
+
+	taskID := "f581c9ef-778f-4d15-87ae-26ba6da93b86"
+	if data, err := h.LoadCheckpoint(taskID); err != nil || data != nil {
+		t.Fatalf("expected no checkpoint, got %s, err: %v", data, err)
+	}
+	// a checkpoint replaces the last one
+	checkErr(t, h.SaveCheckpoint(taskID, []byte("round-1")))
+	checkErr(t, h.SaveCheckpoint(taskID, []byte("round-3")))
+	if data, err := h.LoadCheckpoint(taskID); err != nil || string(data) != "round-3" {
+		t.Fatalf("expected the last checkpoint, got %s, err: %v", data, err)
+	}
+	checkErr(t, h.DeleteCheckpoint(taskID))
+	if data, err := h.LoadCheckpoint(taskID); err != nil || data != nil {
+		t.Errorf("expected checkpoint deleted, got %s, err: %v", data, err)
+	}
+}
+
+func TestSignPredictOut(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	backend := memory.New()
+	h.Storage = FileStorage{PredictStorage: backend, SignatureStorage: backend}
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("predict-1", pbCom.TaskType_PREDICT)))
+
+	outcomes := []byte("id,y\n1,0.5\n")
+	checkErr(t, h.SavePredictOut(&pbCom.PredictTaskResult{TaskID: "predict-1", Success: true, Outcomes: outcomes}))
+	if msg, ok := chain.finished["predict-1"]; !ok || msg != "" {
+		t.Fatalf("expected task finished, got %q", msg)
+	}
+
+	sig, err := h.Storage.LoadResultSignature(context.Background(), "predict-1")
+	checkErr(t, err)
+	pubkey := ecdsa.PublicKeyFromPrivateKey(h.Node.PrivateKey)
+	if err := blockchain.VerifyResultSignature(sig, pubkey[:], "predict-1", 0, outcomes); err != nil {
+		t.Errorf("failed to verify signature of prediction result: %v", err)
+	}
+}
+
+func TestSealPredictOut(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	backend := memory.New()
+	h.Storage = FileStorage{PredictStorage: backend, SignatureStorage: backend}
+	privkey, pubkey, err := ecdsa.GenerateKeyPair()
+	checkErr(t, err)
+	task := newTask("predict-1", pbCom.TaskType_PREDICT)
+	task.AlgoParam.ResultPublicKey = pubkey[:]
+	checkErr(t, h.addTaskIntoMpcHandler(task))
+
+	outcomes := []byte("id,y\n1,0.5\n")
+	checkErr(t, h.SavePredictOut(&pbCom.PredictTaskResult{TaskID: "predict-1", Success: true, Outcomes: outcomes}))
+	if msg, ok := chain.finished["predict-1"]; !ok || msg != "" {
+		t.Fatalf("expected task finished, got %q", msg)
+	}
+	r, err := backend.Download(context.Background(), "predict-1")
+	checkErr(t, err)
+	defer r.Close()
+	stored, err := ioutil.ReadAll(r)
+	checkErr(t, err)
+	if !envelope.IsSealed(stored) {
+		t.Fatal("expected prediction result encrypted")
+	}
+	content, err := envelope.Open(privkey, stored)
+	checkErr(t, err)
+	if !bytes.Equal(content, outcomes) {
+		t.Errorf("expected %q decrypted, got %q", outcomes, content)
+	}
+	// the signature is of the result stored
+	sig, err := h.Storage.LoadResultSignature(context.Background(), "predict-1")
+	checkErr(t, err)
+	nodePubkey := ecdsa.PublicKeyFromPrivateKey(h.Node.PrivateKey)
+	if err := blockchain.VerifyResultSignature(sig, nodePubkey[:], "predict-1", 0, stored); err != nil {
+		t.Errorf("failed to verify signature of prediction result: %v", err)
+	}
+}
+
+func TestFeatureImportances(t *testing.T) {
+	model := &pbCom.TrainModels{
+		Thetas:    map[string]float64{"Intercept": 5, "a": 0.5, "b": -2, "c": 1},
+		Sigmas:    map[string]float64{"a": 1, "b": 10, "c": 2},
+		IsTagPart: true,
+		// c is scaled by minmax, its coefficient is rescaled by the standard deviation
+		Summaries: map[string]*pbCom.FeatureSummary{"a": {Std: 1}, "c": {Std: 4}},
+	}
+	importances := FeatureImportances(model)
+	var got []string
+	for _, i := range importances {
+		got = append(got, i.Feature)
+	}
+	if strings.Join(got, ",") != "b,c,a" {
+		t.Fatalf("expected features sorted by importance without intercept, got %v", got)
+	}
+	if importances[0].Coefficient != -2 || importances[0].Importance != 2 || importances[1].Coefficient != 2 {
+		t.Errorf("unexpected importances: %v", importances)
+	}
+
+	h, _, _ := newResourceHandler(t, ResourceLimits{})
+	h.Storage = FileStorage{ModelStorage: memory.New(), EvaluationStorage: memory.New()}
+	task := newTask("train-1", pbCom.TaskType_LEARN)
+	task.AlgoParam.Algo = pbCom.Algorithm_LINEAR_REGRESSION_VL
+	checkErr(t, h.addTaskIntoMpcHandler(task))
+	if _, err := h.Storage.LoadFeatureImportances(context.Background(), task); !storage.IsNotFound(err) {
+		t.Fatalf("expected no feature importance, got %v", err)
+	}
+	text, err := json.Marshal(model)
+	checkErr(t, err)
+	checkErr(t, h.SaveModel(&pbCom.TrainTaskResult{TaskID: "train-1", Success: true, Model: text}))
+	stored, err := h.Storage.LoadFeatureImportances(context.Background(), task)
+	checkErr(t, err)
+	if len(stored) != 3 || stored[0].Feature != "b" || stored[1].Importance != 2 {
+		t.Errorf("unexpected stored importances: %v", stored)
+	}
+}
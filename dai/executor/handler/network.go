@@ -0,0 +1,361 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sort"
+	"sync"
+
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// Network is a blockchain network joined by the executor
+type Network struct {
+	Name  string
+	Chain Blockchain
+}
+
+// networkChain sets the name of the network on the tasks got from the wrapped Blockchain
+type networkChain struct {
+	Blockchain
+	name string
+}
+
+func (c *networkChain) ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	tasks, err := c.Blockchain.ListTask(opt)
+	for _, task := range tasks {
+		task.Network = c.name
+	}
+	return tasks, err
+}
+
+func (c *networkChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	task, err := c.Blockchain.GetTaskById(id)
+	if task != nil {
+		task.Network = c.name
+	}
+	return task, err
+}
+
+// MultiChain is a Blockchain joining multiple blockchain networks, such as the chains of the consortia
+// the executor participates in. Tasks are listed from all networks, and the calls about a task or a file are made
+// on the network it's found on. The node is registered on all networks, and the calls about neither tasks nor files,
+// such as anchoring audit records, are made on the first network, which is the default one.
+type MultiChain struct {
+	networks []*networkChain
+	// networks of the tasks and files found, by their IDs
+	taskNetworks map[string]*networkChain
+	fileNetworks map[string]*networkChain
+	lock         sync.RWMutex
+}
+
+// NewMultiChain returns a Blockchain joining networks, the first of which is the default one
+func NewMultiChain(networks []Network) *MultiChain {
+	c := &MultiChain{
+		taskNetworks: make(map[string]*networkChain),
+		fileNetworks: make(map[string]*networkChain),
+	}
+	for _, network := range networks {
+		c.networks = append(c.networks, &networkChain{Blockchain: network.Chain, name: network.Name})
+	}
+	return c
+}
+
+// Networks returns the names of the networks
+func (c *MultiChain) Networks() []string {
+	var names []string
+	for _, network := range c.networks {
+		names = append(names, network.name)
+	}
+	return names
+}
+
+// Network returns the Blockchain of a network, the tasks got from which are marked with the name of the network
+func (c *MultiChain) Network(name string) (Blockchain, error) {
+	for _, network := range c.networks {
+		if network.name == name {
+			return network, nil
+		}
+	}
+	return nil, errorx.New(errcodes.ErrCodeParam, "unknown blockchain network %s, should be one of %v", name, c.Networks())
+}
+
+// taskNetwork returns the network a task is published on, the networks are searched if the task isn't found before
+func (c *MultiChain) taskNetwork(taskID string) (*networkChain, error) {
+	c.lock.RLock()
+	network, ok := c.taskNetworks[taskID]
+	c.lock.RUnlock()
+	if ok {
+		return network, nil
+	}
+	_, network, err := c.getTask(taskID)
+	return network, err
+}
+
+// getTask gets a task from the networks in order, and records the network it's found on
+func (c *MultiChain) getTask(taskID string) (task blockchain.FLTask, network *networkChain, err error) {
+	for _, network = range c.networks {
+		if task, err = network.GetTaskById(taskID); err == nil {
+			c.lock.Lock()
+			c.taskNetworks[taskID] = network
+			c.lock.Unlock()
+			return task, network, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// fileNetwork returns the network a file is stored on, the networks are searched if the file isn't found before
+func (c *MultiChain) fileNetwork(fileID string) (*networkChain, error) {
+	c.lock.RLock()
+	network, ok := c.fileNetworks[fileID]
+	c.lock.RUnlock()
+	if ok {
+		return network, nil
+	}
+	var err error
+	for _, network = range c.networks {
+		if _, err = network.GetFileByID(fileID); err == nil {
+			c.lock.Lock()
+			c.fileNetworks[fileID] = network
+			c.lock.Unlock()
+			return network, nil
+		}
+	}
+	return nil, err
+}
+
+// RegisterExecutorNode registers the node on all networks
+func (c *MultiChain) RegisterExecutorNode(opt *blockchain.AddNodeOptions) error {
+	for _, network := range c.networks {
+		if err := network.RegisterExecutorNode(opt); err != nil {
+			return errorx.Wrap(err, "failed to register node on blockchain network %s", network.name)
+		}
+	}
+	return nil
+}
+
+// RevokeExecutorKey revokes the old key of the node on all networks, the ones it's already revoked on are skipped
+func (c *MultiChain) RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error {
+	for _, network := range c.networks {
+		if err := network.RevokeExecutorKey(opt); err != nil && !errorx.Is(err, errorx.ErrCodeAlreadyUpdate) {
+			return errorx.Wrap(err, "failed to revoke key on blockchain network %s", network.name)
+		}
+	}
+	return nil
+}
+
+// GetExecutorNodeByID gets the node from the first network it's registered on
+func (c *MultiChain) GetExecutorNodeByID(id string) (node blockchain.ExecutorNode, err error) {
+	for _, network := range c.networks {
+		if node, err = network.GetExecutorNodeByID(id); err == nil {
+			return node, nil
+		}
+	}
+	return node, err
+}
+
+// ListExecutorNodes lists the nodes of all networks, the nodes registered on multiple networks are listed once
+func (c *MultiChain) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
+	var nodes blockchain.ExecutorNodes
+	listed := make(map[string]bool)
+	for _, network := range c.networks {
+		networkNodes, err := network.ListExecutorNodes()
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to list nodes of blockchain network %s", network.name)
+		}
+		for _, node := range networkNodes {
+			if !listed[string(node.ID)] {
+				listed[string(node.ID)] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// ListTask lists the tasks of all networks in the descending order of publish time, at most opt.Limit are returned.
+// The networks failed to list tasks are skipped with a warning, so that tasks of other networks are still executed,
+// and an error is returned only if all of them fail.
+func (c *MultiChain) ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	var tasks blockchain.FLTasks
+	var lastErr error
+	failed := 0
+	for _, network := range c.networks {
+		networkTasks, err := network.ListTask(opt)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"network": network.name}).WithError(err).Warn("failed to list tasks of blockchain network")
+			lastErr = err
+			failed++
+			continue
+		}
+		c.lock.Lock()
+		for _, task := range networkTasks {
+			c.taskNetworks[task.TaskID] = network
+		}
+		c.lock.Unlock()
+		tasks = append(tasks, networkTasks...)
+	}
+	if failed == len(c.networks) && lastErr != nil {
+		return nil, lastErr
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].PublishTime > tasks[j].PublishTime
+	})
+	if opt.Limit > 0 && int64(len(tasks)) > opt.Limit {
+		tasks = tasks[:opt.Limit]
+	}
+	return tasks, nil
+}
+
+// PublishTask publishes the task on the network named by its Network, the default network if it's empty
+func (c *MultiChain) PublishTask(opt *blockchain.PublishFLTaskOptions) error {
+	network := c.networks[0]
+	if opt.FLTask.Network != "" {
+		chain, err := c.Network(opt.FLTask.Network)
+		if err != nil {
+			return err
+		}
+		network = chain.(*networkChain)
+	}
+	return network.PublishTask(opt)
+}
+
+// GetTaskById gets the task from the network it's published on
+func (c *MultiChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	c.lock.RLock()
+	network, ok := c.taskNetworks[id]
+	c.lock.RUnlock()
+	if ok {
+		return network.GetTaskById(id)
+	}
+	task, _, err := c.getTask(id)
+	return task, err
+}
+
+func (c *MultiChain) ConfirmTask(opt *blockchain.FLTaskConfirmOptions) error {
+	network, err := c.taskNetwork(opt.TaskID)
+	if err != nil {
+		return err
+	}
+	return network.ConfirmTask(opt)
+}
+
+func (c *MultiChain) RejectTask(opt *blockchain.FLTaskConfirmOptions) error {
+	network, err := c.taskNetwork(opt.TaskID)
+	if err != nil {
+		return err
+	}
+	return network.RejectTask(opt)
+}
+
+func (c *MultiChain) ExecuteTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	network, err := c.taskNetwork(opt.TaskID)
+	if err != nil {
+		return err
+	}
+	return network.ExecuteTask(opt)
+}
+
+func (c *MultiChain) FinishTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	network, err := c.taskNetwork(opt.TaskID)
+	if err != nil {
+		return err
+	}
+	return network.FinishTask(opt)
+}
+
+// GetFileByID gets the file from the network it's stored on
+func (c *MultiChain) GetFileByID(id string) (xdbchain.File, error) {
+	network, err := c.fileNetwork(id)
+	if err != nil {
+		return xdbchain.File{}, err
+	}
+	return network.GetFileByID(id)
+}
+
+// GetColumnPolicy gets the column policy of the file from the network it's stored on
+func (c *MultiChain) GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error) {
+	network, err := c.fileNetwork(fileID)
+	if err != nil {
+		return blockchain.ColumnPolicy{}, err
+	}
+	return network.GetColumnPolicy(fileID, executor)
+}
+
+// ListFileAuthApplications lists the applications of the file on the network it's stored on if opt.FileID is set,
+// otherwise the applications of all networks
+func (c *MultiChain) ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error) {
+	if opt.FileID != "" {
+		network, err := c.fileNetwork(opt.FileID)
+		if err != nil {
+			return nil, err
+		}
+		return network.ListFileAuthApplications(opt)
+	}
+	var auths xdbchain.FileAuthApplications
+	for _, network := range c.networks {
+		networkAuths, err := network.ListFileAuthApplications(opt)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to list file authorization applications of blockchain network %s", network.name)
+		}
+		auths = append(auths, networkAuths...)
+	}
+	return auths, nil
+}
+
+// PublishFileAuthApplication publishes the application on the network the file is stored on
+func (c *MultiChain) PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error {
+	network, err := c.fileNetwork(opt.FileAuthApplication.FileID)
+	if err != nil {
+		return err
+	}
+	return network.PublishFileAuthApplication(opt)
+}
+
+// ListNodes lists the storage nodes of all networks, the nodes of multiple networks are listed once
+func (c *MultiChain) ListNodes() (xdbchain.Nodes, error) {
+	var nodes xdbchain.Nodes
+	listed := make(map[string]bool)
+	for _, network := range c.networks {
+		networkNodes, err := network.ListNodes()
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to list storage nodes of blockchain network %s", network.name)
+		}
+		for _, node := range networkNodes {
+			if !listed[string(node.ID)] {
+				listed[string(node.ID)] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// AnchorAudit anchors the hash of an audit record on the default network
+func (c *MultiChain) AnchorAudit(opt *blockchain.AnchorAuditOptions) error {
+	return c.networks[0].AnchorAudit(opt)
+}
+
+// Close closes the clients of all networks
+func (c *MultiChain) Close() {
+	for _, network := range c.networks {
+		network.Close()
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// networkFakeChain is a network holding tasks and files, tasks are listed in the descending order of publish time
+type networkFakeChain struct {
+	Blockchain
+	tasks    []*pbTask.FLTask
+	files    map[string]bool
+	listErr  error
+	executed []string
+	closed   bool
+}
+
+func (c *networkFakeChain) ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+	var tasks blockchain.FLTasks
+	for _, task := range c.tasks {
+		// copied as the contract returns new tasks on each call
+		t := *task
+		tasks = append(tasks, &t)
+	}
+	return tasks, nil
+}
+
+func (c *networkFakeChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	for _, task := range c.tasks {
+		if task.TaskID == id {
+			t := *task
+			return &t, nil
+		}
+	}
+	return nil, errorx.New(errcodes.ErrCodeNotFound, "task not found")
+}
+
+func (c *networkFakeChain) ExecuteTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	c.executed = append(c.executed, opt.TaskID)
+	return nil
+}
+
+func (c *networkFakeChain) GetFileByID(id string) (xdbchain.File, error) {
+	if c.files[id] {
+		return xdbchain.File{ID: id}, nil
+	}
+	return xdbchain.File{}, errorx.New(errcodes.ErrCodeNotFound, "file not found")
+}
+
+func (c *networkFakeChain) Close() {
+	c.closed = true
+}
+
+func newFakeNetworks() (*MultiChain, *networkFakeChain, *networkFakeChain) {
+	a := &networkFakeChain{
+		tasks: []*pbTask.FLTask{{TaskID: "a2", PublishTime: 4}, {TaskID: "a1", PublishTime: 1}},
+		files: map[string]bool{"fa": true},
+	}
+	b := &networkFakeChain{
+		tasks: []*pbTask.FLTask{{TaskID: "b2", PublishTime: 3}, {TaskID: "b1", PublishTime: 2}},
+		files: map[string]bool{"fb": true},
+	}
+	return NewMultiChain([]Network{{Name: "a", Chain: a}, {Name: "b", Chain: b}}), a, b
+}
+
+func TestMultiChainListTask(t *testing.T) {
+	chain, _, b := newFakeNetworks()
+
+	tasks, err := chain.ListTask(&blockchain.ListFLTaskOptions{Limit: 3})
+	checkErr(t, err)
+	var ids, networks []string
+	for _, task := range tasks {
+		ids = append(ids, task.TaskID)
+		networks = append(networks, task.Network)
+	}
+	if !reflect.DeepEqual(ids, []string{"a2", "b2", "b1"}) || !reflect.DeepEqual(networks, []string{"a", "b", "b"}) {
+		t.Errorf("tasks should be merged by publish time and limited, got %v of %v", ids, networks)
+	}
+
+	// tasks of other networks are still listed if a network fails
+	b.listErr = errors.New("network unreachable")
+	tasks, err = chain.ListTask(&blockchain.ListFLTaskOptions{})
+	checkErr(t, err)
+	if len(tasks) != 2 || tasks[0].Network != "a" {
+		t.Errorf("tasks of network a should be listed, got %v", tasks)
+	}
+
+	network, err := chain.Network("b")
+	checkErr(t, err)
+	if _, err := network.ListTask(&blockchain.ListFLTaskOptions{}); err == nil {
+		t.Error("failure of the network should be returned if it's specified")
+	}
+	if _, err := chain.Network("c"); err == nil {
+		t.Error("unknown network should be rejected")
+	}
+}
+
+func TestMultiChainRouting(t *testing.T) {
+	chain, a, b := newFakeNetworks()
+
+	task, err := chain.GetTaskById("b1")
+	checkErr(t, err)
+	if task.Network != "b" {
+		t.Errorf("task b1 should be got from network b, got %s", task.Network)
+	}
+	checkErr(t, chain.ExecuteTask(&blockchain.FLTaskExeStatusOptions{TaskID: "b1"}))
+	checkErr(t, chain.ExecuteTask(&blockchain.FLTaskExeStatusOptions{TaskID: "a1"}))
+	if !reflect.DeepEqual(a.executed, []string{"a1"}) || !reflect.DeepEqual(b.executed, []string{"b1"}) {
+		t.Errorf("tasks should be executed on their networks, got %v and %v", a.executed, b.executed)
+	}
+	if err := chain.ExecuteTask(&blockchain.FLTaskExeStatusOptions{TaskID: "c1"}); !errorx.Is(err, errcodes.ErrCodeNotFound) {
+		t.Errorf("unknown task should not be found, got %v", err)
+	}
+
+	file, err := chain.GetFileByID("fb")
+	checkErr(t, err)
+	if file.ID != "fb" {
+		t.Errorf("file fb should be got from network b, got %v", file)
+	}
+
+	chain.Close()
+	if !a.closed || !b.closed {
+		t.Error("clients of all networks should be closed")
+	}
+}
@@ -14,6 +14,7 @@
 package handler
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -34,6 +35,8 @@ type Blockchain interface {
 	RegisterExecutorNode(opt *blockchain.AddNodeOptions) error
 	GetExecutorNodeByID(id string) (blockchain.ExecutorNode, error)
 	ListExecutorNodes() (blockchain.ExecutorNodes, error)
+	// revoke the old key of the node once the grace period of its key rotation ends
+	RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error
 
 	// task operation
 	ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error)
@@ -51,6 +54,10 @@ type Blockchain interface {
 	PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error
 	// query the list of storage nodes
 	ListNodes() (xdbchain.Nodes, error)
+	// anchor the hash of an audit record
+	AnchorAudit(opt *blockchain.AnchorAuditOptions) error
+	// get the column policy of sample file for executor
+	GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error)
 
 	Close()
 }
@@ -60,6 +67,30 @@ type Node struct {
 	HttpAddress     string
 	PaddleFLAddress string
 	PaddleFLRole    int
+
+	// Previous is the identity of the node before its latest key rotation, which executes the tasks published to it
+	// until they end, nil if the key has never been rotated. See 'executor-cli rotatekey'.
+	Previous *peer.Local
+}
+
+// Identity returns the identity of the node executing task, the previous one if only it is an executor of task,
+// otherwise the current one
+func (n *Node) Identity(task blockchain.FLTask) *peer.Local {
+	if n.Previous == nil {
+		return &n.Local
+	}
+	isExecutor := func(id []byte) bool {
+		for _, ds := range task.DataSets {
+			if bytes.Equal(ds.Executor, id) {
+				return true
+			}
+		}
+		return false
+	}
+	if !isExecutor(n.ID) && isExecutor(n.Previous.ID) {
+		return n.Previous
+	}
+	return &n.Local
 }
 
 // Register registers local node to blockchain
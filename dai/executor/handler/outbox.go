@@ -0,0 +1,221 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// Defaults of the outbox of task status updates
+const (
+	DefaultOutboxSize             = 1000
+	DefaultOutboxRetryInterval    = time.Second
+	DefaultOutboxMaxRetryInterval = time.Minute
+)
+
+// ChainState is the state of the connection to the blockchain seen by the outbox, reported by '/readyz'
+type ChainState struct {
+	Connected bool `json:"connected"`
+	// DisconnectedSince is when the first status update failed in the outage, in nanoseconds, zero if connected
+	DisconnectedSince int64  `json:"disconnectedSince,omitempty"`
+	LastError         string `json:"lastError,omitempty"`
+	PendingUpdates    int    `json:"pendingUpdates"` // the number of status updates buffered
+}
+
+// statusUpdate is the terminal status of a task to be recorded in blockchain, see updateTaskFinishStatus
+type statusUpdate struct {
+	taskID     string
+	errMessage string
+	result     string
+	status     string // 'Cancelled' or 'Timeout', empty means 'Finished' or 'Failed' by errMessage
+	budget     *pbCom.PrivacyBudget
+	batch      []*pbTask.BatchPredictResult
+	bufferedAt time.Time // zero if the update is not buffered
+}
+
+// StatusOutbox buffers the terminal status of tasks that fails to be recorded because the blockchain is unreachable,
+// so that a transient outage doesn't fail the tasks finished locally. Run replays the buffered updates with
+// exponential backoff until the blockchain comes back, and reconciles them against it: the status committed on
+// the chain wins, so the update of a task already ended on the chain is dropped, and the others are signed again
+// and recorded in the order they were buffered. Updates are kept in memory, the ones lost by a restart are recovered
+// by re-executing the tasks left in 'Processing'. A nil StatusOutbox buffers nothing and is always connected.
+type StatusOutbox struct {
+	Size             int           // the max number of updates buffered, later ones fail as before
+	RetryInterval    time.Duration // the interval before the first replay, doubled after each failed one
+	MaxRetryInterval time.Duration // limits the interval growing by exponential backoff
+
+	updates        map[string]*statusUpdate
+	disconnectedAt time.Time // zero if connected
+	lastErr        string
+	wake           chan struct{}
+	lock           sync.Mutex
+	now            func() time.Time
+}
+
+// NewStatusOutbox creates a StatusOutbox, zero values mean DefaultOutboxSize, DefaultOutboxRetryInterval
+// and DefaultOutboxMaxRetryInterval
+func NewStatusOutbox(size int, retryInterval, maxRetryInterval time.Duration) *StatusOutbox {
+	if size <= 0 {
+		size = DefaultOutboxSize
+	}
+	if retryInterval <= 0 {
+		retryInterval = DefaultOutboxRetryInterval
+	}
+	if maxRetryInterval <= 0 {
+		maxRetryInterval = DefaultOutboxMaxRetryInterval
+	}
+	if maxRetryInterval < retryInterval {
+		maxRetryInterval = retryInterval
+	}
+	return &StatusOutbox{
+		Size:             size,
+		RetryInterval:    retryInterval,
+		MaxRetryInterval: maxRetryInterval,
+		updates:          make(map[string]*statusUpdate),
+		wake:             make(chan struct{}, 1),
+		now:              time.Now,
+	}
+}
+
+// buffer keeps u to be replayed if err shows the blockchain is unreachable, and returns whether u is buffered.
+// Updates rejected by the chain are not buffered, nor are the ones arriving when the outbox is full.
+// Only the first update of a task is kept, as the first terminal status recorded locally is the one of the task.
+func (o *StatusOutbox) buffer(u *statusUpdate, err error) bool {
+	if o == nil || !blockchain.IsTransientError(err) {
+		return false
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.setDisconnected(err)
+	if _, ok := o.updates[u.taskID]; ok {
+		return true
+	}
+	if len(o.updates) >= o.Size {
+		return false
+	}
+	u.bufferedAt = o.now()
+	o.updates[u.taskID] = u
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// setDisconnected records the outage, the lock must be held
+func (o *StatusOutbox) setDisconnected(err error) {
+	if o.disconnectedAt.IsZero() {
+		o.disconnectedAt = o.now()
+		logger.WithError(err).Warn("blockchain unreachable, buffer task status updates until it comes back")
+	}
+	o.lastErr = err.Error()
+}
+
+// State returns the state of the connection to the blockchain
+func (o *StatusOutbox) State() ChainState {
+	if o == nil {
+		return ChainState{Connected: true}
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	state := ChainState{
+		Connected:      o.disconnectedAt.IsZero(),
+		PendingUpdates: len(o.updates),
+	}
+	if !state.Connected {
+		state.DisconnectedSince = o.disconnectedAt.UnixNano()
+		state.LastError = o.lastErr
+	}
+	return state
+}
+
+// pending returns the buffered updates in the order they're buffered, ties are broken by task IDs
+func (o *StatusOutbox) pending() []*statusUpdate {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	updates := make([]*statusUpdate, 0, len(o.updates))
+	for _, u := range o.updates {
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		if !updates[i].bufferedAt.Equal(updates[j].bufferedAt) {
+			return updates[i].bufferedAt.Before(updates[j].bufferedAt)
+		}
+		return updates[i].taskID < updates[j].taskID
+	})
+	return updates
+}
+
+// Run replays the buffered updates by replay until ctx is done. A round of replays starts RetryInterval after
+// an update is buffered, and stops at the first update failing because the blockchain is still unreachable,
+// the interval before the next round is doubled, at most MaxRetryInterval.
+func (o *StatusOutbox) Run(ctx context.Context, replay func(u *statusUpdate) error) {
+	if o == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		}
+		for interval := o.RetryInterval; ; {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			if o.reconcile(replay) {
+				break
+			}
+			if interval *= 2; interval > o.MaxRetryInterval {
+				interval = o.MaxRetryInterval
+			}
+		}
+	}
+}
+
+// reconcile replays the buffered updates in order, returns false if the blockchain is still unreachable.
+// Updates answered by the chain are removed, whether they're recorded, or dropped as the chain wins.
+func (o *StatusOutbox) reconcile(replay func(u *statusUpdate) error) bool {
+	for _, u := range o.pending() {
+		err := replay(u)
+		o.lock.Lock()
+		if blockchain.IsTransientError(err) {
+			o.setDisconnected(err)
+			o.lock.Unlock()
+			return false
+		}
+		delete(o.updates, u.taskID)
+		if !o.disconnectedAt.IsZero() {
+			logger.Infof("blockchain reachable again after %v, reconcile buffered task status updates",
+				o.now().Sub(o.disconnectedAt))
+			o.disconnectedAt = time.Time{}
+			o.lastErr = ""
+		}
+		o.lock.Unlock()
+		if err != nil {
+			logger.WithField(logging.TaskIDKey, u.taskID).WithError(err).Error("failed to record buffered task status, dropped")
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// outageChain fails all calls with a transient error while down, or with err if it's set
+type outageChain struct {
+	*fakeChain
+	down bool
+	err  error
+	sync.Mutex
+}
+
+// failure returns the error of calls, the lock must be held
+func (c *outageChain) failure() error {
+	if c.down {
+		return errors.New("rpc error: code = Unavailable desc = connection refused")
+	}
+	return c.err
+}
+
+func (c *outageChain) setDown(down bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.down = down
+}
+
+func (c *outageChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.failure(); err != nil {
+		return nil, err
+	}
+	return c.fakeChain.GetTaskById(id)
+}
+
+func (c *outageChain) FinishTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.failure(); err != nil {
+		return err
+	}
+	return c.fakeChain.FinishTask(opt)
+}
+
+func newOutageHandler(t *testing.T, size int) (*MpcModelHandler, *outageChain) {
+	h, fake, _ := newResourceHandler(t, ResourceLimits{})
+	chain := &outageChain{fakeChain: fake, down: true}
+	h.Chain = chain
+	h.Outbox = NewStatusOutbox(size, time.Millisecond, 4*time.Millisecond)
+	return h, chain
+}
+
+func TestOutboxReconcile(t *testing.T) {
+	h, chain := newOutageHandler(t, 10)
+	task := newTask("train-1", pbCom.TaskType_LEARN)
+	checkErr(t, h.addTaskIntoMpcHandler(task))
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-2", pbCom.TaskType_LEARN)))
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-3", pbCom.TaskType_LEARN)))
+
+	// the tasks end locally while the blockchain is unreachable, the others keep running
+	checkErr(t, h.CancelTask(task, "task cancelled by the requester", false))
+	checkErr(t, h.UpdateTaskFinishStatus("train-2", "", ""))
+	if _, ok := h.MpcTasks["train-3"]; !ok || len(chain.finished) != 0 {
+		t.Fatalf("expected train-3 running and no status recorded, got %v, %v", h.MpcTasks, chain.finished)
+	}
+	state := h.Outbox.State()
+	if state.Connected || state.PendingUpdates != 2 || state.DisconnectedSince == 0 || state.LastError == "" {
+		t.Fatalf("expected disconnected with 2 updates buffered, got %+v", state)
+	}
+
+	// the blockchain is unreachable still
+	if h.Outbox.reconcile(h.recordTaskStatus) {
+		t.Fatal("expected reconciliation fails while the blockchain is unreachable")
+	}
+
+	// train-2 is failed by another executor in the outage, the status committed on the chain wins
+	chain.tasks = map[string]blockchain.FLTask{"train-2": &pbTask.FLTask{TaskID: "train-2", Status: blockchain.TaskFailed}}
+	chain.setDown(false)
+	if !h.Outbox.reconcile(h.recordTaskStatus) {
+		t.Fatal("expected reconciliation succeeds after the blockchain comes back")
+	}
+	if len(chain.cancelled) != 1 || chain.cancelled[0] != "train-1" {
+		t.Errorf("expected the buffered cancellation of train-1 recorded, got %v", chain.cancelled)
+	}
+	if _, ok := chain.finished["train-2"]; ok {
+		t.Error("expected the buffered status of train-2 dropped, as it's failed on the chain")
+	}
+	if state := h.Outbox.State(); !state.Connected || state.PendingUpdates != 0 || state.LastError != "" {
+		t.Errorf("expected connected with no updates buffered, got %+v", state)
+	}
+}
+
+func TestOutboxBuffer(t *testing.T) {
+	h, chain := newOutageHandler(t, 1)
+
+	// errors of the chain rejecting the update are returned as before
+	chain.setDown(false)
+	chain.err = errors.New("task not found")
+	if err := h.UpdateTaskFinishStatus("train-1", "", ""); err == nil {
+		t.Error("expected error rejected by the chain returned")
+	}
+	if state := h.Outbox.State(); !state.Connected || state.PendingUpdates != 0 {
+		t.Errorf("expected rejected update not buffered, got %+v", state)
+	}
+
+	// the updates arriving when the outbox is full fail, the first update of a task is kept
+	chain.err = nil
+	chain.setDown(true)
+	checkErr(t, h.UpdateTaskFinishStatus("train-1", "", ""))
+	checkErr(t, h.UpdateTaskFinishStatus("train-1", "failed again", ""))
+	if err := h.UpdateTaskFinishStatus("train-2", "", ""); err == nil {
+		t.Error("expected error when the outbox is full")
+	}
+	if u := h.Outbox.pending(); len(u) != 1 || u[0].taskID != "train-1" || u[0].errMessage != "" {
+		t.Errorf("expected the first update of train-1 buffered, got %+v", u)
+	}
+
+	// a nil outbox buffers nothing
+	h.Outbox = nil
+	if err := h.UpdateTaskFinishStatus("train-3", "", ""); err == nil {
+		t.Error("expected error without outbox")
+	}
+	if state := h.Outbox.State(); !state.Connected {
+		t.Errorf("expected nil outbox connected, got %+v", state)
+	}
+}
+
+func TestOutboxRun(t *testing.T) {
+	h, chain := newOutageHandler(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.RunOutbox(ctx)
+
+	checkErr(t, h.UpdateTaskFinishStatus("train-1", "", ""))
+	time.Sleep(20 * time.Millisecond)
+	chain.setDown(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for h.Outbox.State().PendingUpdates > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the buffered update replayed after the blockchain comes back")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	chain.Lock()
+	defer chain.Unlock()
+	if _, ok := chain.finished["train-1"]; !ok {
+		t.Errorf("expected train-1 finished, got %v", chain.finished)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// ProtocolVersion is the version of the messages between the learners and models of Executors,
+// it is increased once the messages change incompatibly, so that tasks fail before they start
+// if their Executors are not compatible
+const ProtocolVersion = 1
+
+// taskFingerprint returns the fingerprint of the task to start by startRequest,
+// which is made up of what the Executors of the task should agree on
+func taskFingerprint(startRequest *pbCom.StartTaskRequest) (*pbTask.TaskFingerprint, error) {
+	params := startRequest.GetParams()
+	hash, err := paramsHash(params)
+	if err != nil {
+		return nil, err
+	}
+	// psi.AlgorithmAuto is compared as is, it's resolved by the samples of both Executors, see AgreePSIAlgorithm
+	psiAlgorithm := params.GetTrainParams().GetPsiAlgorithm()
+	if psiAlgorithm == "" {
+		psiAlgorithm = psi.AlgorithmECDH
+	}
+	if len(startRequest.Hosts) > 1 {
+		// PSI of multiple parties is ECDH only
+		psiAlgorithm = psi.AlgorithmECDH
+	}
+	hasLabel := params.GetTrainParams().GetIsTagPart()
+	if params.GetTaskType() == pbCom.TaskType_PREDICT {
+		hasLabel = params.GetModelParams().GetIsTagPart()
+	}
+	return &pbTask.TaskFingerprint{
+		ProtocolVersion: ProtocolVersion,
+		Algo:            params.GetAlgo().String(),
+		TaskType:        params.GetTaskType().String(),
+		PsiAlgorithm:    psiAlgorithm,
+		ParamsHash:      hash,
+		HasLabel:        hasLabel,
+		SampleNum:       psi.SampleNum(startRequest.File),
+	}, nil
+}
+
+// paramsHash returns the hex encoded hash of the hyperparameters of the task shared by its Executors,
+// the ones set by each Executor by its own samples and models are excluded
+func paramsHash(params *pbCom.TaskParams) (string, error) {
+	shared := &pbCom.TaskParams{
+		Algo:        params.GetAlgo(),
+		TaskType:    params.GetTaskType(),
+		EvalParams:  params.GetEvalParams(),
+		LivalParams: params.GetLivalParams(),
+	}
+	if params.GetTrainParams() != nil {
+		trainParams := proto.Clone(params.GetTrainParams()).(*pbCom.TrainParams)
+		trainParams.IdName, trainParams.IsTagPart, trainParams.PsiAlgorithm, trainParams.BaseModel = "", false, "", nil
+		trainParams.AlignmentKey = ""
+		shared.TrainParams = trainParams
+	}
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(shared); err != nil {
+		return "", errorx.Internal(err, "failed to marshal task params")
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkFingerprint compares the fingerprint of the task from the Executor of party with the local one,
+// the error lists everything they disagree on. twoParties is true if the task is executed by two Executors,
+// then exactly one of them should hold the label.
+func checkFingerprint(local, remote *pbTask.TaskFingerprint, party string, twoParties bool) error {
+	var mismatches []string
+	compare := func(name string, l, r interface{}) {
+		if l != r {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %v locally but %v on %s", name, l, r, party))
+		}
+	}
+	compare("protocol version", local.ProtocolVersion, remote.ProtocolVersion)
+	compare("algorithm", local.Algo, remote.Algo)
+	compare("task type", local.TaskType, remote.TaskType)
+	compare("PSI algorithm", local.PsiAlgorithm, remote.PsiAlgorithm)
+	compare("hash of hyperparameters", local.ParamsHash, remote.ParamsHash)
+	if local.HasLabel && remote.HasLabel {
+		mismatches = append(mismatches, fmt.Sprintf("label is held both locally and on %s", party))
+	} else if twoParties && !local.HasLabel && !remote.HasLabel {
+		mismatches = append(mismatches, fmt.Sprintf("label is held neither locally nor on %s", party))
+	}
+	if len(mismatches) > 0 {
+		return errorx.New(errcodes.ErrCodeTaskMismatch, "preflight check failed, %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// CheckTaskFingerprint checks the fingerprint of the task from the Executor requesting to start it agrees with
+// the local one, before the task started by startRequest begins computation. The task is failed if they disagree.
+// Executors not sending fingerprints are not checked.
+func (m *MpcModelHandler) CheckTaskFingerprint(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint, party string) error {
+	if remote == nil {
+		return nil
+	}
+	local, err := taskFingerprint(startRequest)
+	if err == nil {
+		err = checkFingerprint(local, remote, party, len(startRequest.Hosts) == 1)
+	}
+	if err != nil {
+		logger.WithField(logging.TaskIDKey, startRequest.TaskID).WithError(err).Warnf("task mismatches, local fingerprint: %v, remote: %v", local, remote)
+		m.updateTaskStatusAndStopLocalMpc(startRequest.TaskID, err.Error(), "")
+		return err
+	}
+	return nil
+}
+
+// AgreePSIAlgorithm resolves psi.AlgorithmAuto of the task started by startRequest by the larger number of samples
+// of both Executors, the remote one from the fingerprint of the task, so both of them select the same algorithm.
+// startRequest is updated, and the algorithm is returned to reply to the initiator, empty if nothing is resolved.
+func (m *MpcModelHandler) AgreePSIAlgorithm(startRequest *pbCom.StartTaskRequest, remote *pbTask.TaskFingerprint) string {
+	if remote == nil || len(startRequest.Hosts) != 1 || startRequest.GetParams().GetTrainParams().GetPsiAlgorithm() != psi.AlgorithmAuto {
+		return ""
+	}
+	algorithm := psi.ResolveAlgorithm(psi.AlgorithmAuto, psi.SampleNum(startRequest.File), remote.SampleNum)
+	setPSIAlgorithm(startRequest, algorithm)
+	logger.WithField(logging.TaskIDKey, startRequest.TaskID).Infof("PSI algorithm %s is selected by %d local samples and %d remote ones",
+		algorithm, psi.SampleNum(startRequest.File), remote.SampleNum)
+	return algorithm
+}
+
+// applyPSIAlgorithm updates startRequest by the PSI algorithm resolved by the other Executor, see AgreePSIAlgorithm.
+// It's called by the initiator.
+func applyPSIAlgorithm(startRequest *pbCom.StartTaskRequest, resp *pbTask.TaskResponse) {
+	if resp.GetPsiAlgorithm() != "" {
+		setPSIAlgorithm(startRequest, resp.GetPsiAlgorithm())
+	}
+}
+
+// setPSIAlgorithm sets the PSI algorithm of the task started by startRequest, the model of a prediction uses it as well
+func setPSIAlgorithm(startRequest *pbCom.StartTaskRequest, algorithm string) {
+	startRequest.Params.TrainParams.PsiAlgorithm = algorithm
+	if startRequest.Params.ModelParams != nil {
+		startRequest.Params.ModelParams.PsiAlgorithm = algorithm
+	}
+}
+
+// checkColumnPolicies checks the columns of the local sample files used by task are allowed by the column policies
+// published by the owners of the files, before the files are downloaded. It's a part of the preflight of tasks
+// in proxy mode, and a file without column policy is restricted by the file authorization only.
+func (m *MpcModelHandler) checkColumnPolicies(task blockchain.FLTask) error {
+	if m.Download.Type != ProxyExecutionMode {
+		return nil
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.Identity(task).PrivateKey)
+	for _, dataset := range task.DataSets {
+		if !bytes.Equal(dataset.Executor, pubkey[:]) {
+			continue
+		}
+		// the whole file is used if no columns are selected
+		var columns []string
+		if len(dataset.Columns) > 0 {
+			isTagPart, err := m.getTargetPart(dataset.DataID, task.AlgoParam.TrainParams.Label)
+			if err != nil {
+				return err
+			}
+			columns = taskColumns(dataset, task.AlgoParam.TrainParams.Label, task.AlgoParam.TrainParams.WeightColumn,
+				isTagPart && task.AlgoParam.TaskType == pbCom.TaskType_LEARN)
+		}
+		dataIDs := []string{dataset.DataID}
+		if task.AlgoParam.TaskType == pbCom.TaskType_PREDICT {
+			dataIDs = append(dataIDs, dataset.BatchDataIDs...)
+		}
+		for _, dataID := range dataIDs {
+			if err := m.checkColumnPolicy(dataID, pubkey[:], columns); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkColumnPolicy checks columns of the sample file dataID are allowed by its column policy for executor,
+// columns is nil if all the columns of the file are used
+func (m *MpcModelHandler) checkColumnPolicy(dataID string, executor []byte, columns []string) error {
+	policy, err := m.Chain.GetColumnPolicy(dataID, executor)
+	if err != nil {
+		if errorx.Is(err, errorx.ErrCodeNotFound) {
+			return nil
+		}
+		return errorx.Wrap(err, "failed to get the column policy of sample file %s", dataID)
+	}
+	if len(columns) == 0 {
+		return errorx.New(errcodes.ErrCodeColumnPolicy, "the task uses all columns of sample file %s, but its owner "+
+			"allows only columns %s, select the columns allowed", dataID, strings.Join(policy.Columns, ","))
+	}
+	if disallowed := blockchain.DisallowedColumns(policy, columns); len(disallowed) > 0 {
+		return errorx.New(errcodes.ErrCodeColumnPolicy, "columns %s of sample file %s are not allowed by the column "+
+			"policy of its owner, which allows only columns %s", strings.Join(disallowed, ","), dataID, strings.Join(policy.Columns, ","))
+	}
+	return nil
+}
+
+// checkFeatureNum checks the number of features of the local sample files used by task doesn't exceed m.MaxFeatures,
+// before the files are downloaded. The features are counted from the columns selected by the task, or from the
+// features of the files recorded on the blockchain if no columns are selected, the ID, label and weight columns excluded.
+func (m *MpcModelHandler) checkFeatureNum(task blockchain.FLTask) error {
+	if m.MaxFeatures <= 0 {
+		return nil
+	}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(m.Node.Identity(task).PrivateKey)
+	label, weightColumn := task.AlgoParam.TrainParams.Label, task.AlgoParam.TrainParams.WeightColumn
+	for _, dataset := range task.DataSets {
+		if !bytes.Equal(dataset.Executor, pubkey[:]) {
+			continue
+		}
+		dataIDs := []string{dataset.DataID}
+		if task.AlgoParam.TaskType == pbCom.TaskType_PREDICT {
+			dataIDs = append(dataIDs, dataset.BatchDataIDs...)
+		}
+		for _, dataID := range dataIDs {
+			columns := dataset.Columns
+			if len(columns) == 0 {
+				features, err := m.getFileFeatures(dataID)
+				if err != nil {
+					return err
+				}
+				columns = features
+			}
+			num := 0
+			for _, column := range columns {
+				if column != dataset.PsiLabel && column != label && column != weightColumn {
+					num++
+				}
+			}
+			if num > m.MaxFeatures {
+				return errorx.New(errcodes.ErrCodeTooManyFeatures, "sample file %s of the task has %d features, "+
+					"exceeding the limit %d of the executor", dataID, num, m.MaxFeatures)
+			}
+		}
+	}
+	return nil
+}
+
+// getFileFeatures returns the features of the sample file fileID recorded on the blockchain
+func (m *MpcModelHandler) getFileFeatures(fileID string) ([]string, error) {
+	sampleFile, err := m.Chain.GetFileByID(fileID)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to get sample file %s", fileID)
+	}
+	fileExtra := blockchain.FLInfo{}
+	if err := json.Unmarshal(sampleFile.Ext, &fileExtra); err != nil {
+		return nil, errorx.New(errorx.ErrCodeInternal, "failed to get extra info of sample file %s", fileID)
+	}
+	return strings.Split(fileExtra.Features, ","), nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// policyChain serves the column policies of sample files, the files have features id, x1, x2 and y
+type policyChain struct {
+	*fakeChain
+	policies map[string]blockchain.ColumnPolicy
+}
+
+func (c *policyChain) GetFileByID(id string) (xdbchain.File, error) {
+	ext, _ := json.Marshal(blockchain.FLInfo{FileType: "csv", Features: "id,x1,x2,y"})
+	return xdbchain.File{ID: id, Ext: ext}, nil
+}
+
+func (c *policyChain) GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error) {
+	if policy, ok := c.policies[fileID]; ok {
+		return policy, nil
+	}
+	return blockchain.ColumnPolicy{}, errorx.New(errorx.ErrCodeNotFound, "column policy not found")
+}
+
+func TestCheckTaskFingerprint(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	task := newTask("train-1", pbCom.TaskType_LEARN)
+	checkErr(t, h.addTaskIntoMpcHandler(task))
+
+	newRequest := func(psiAlgorithm string, isTagPart bool, batchSize int64) *pbCom.StartTaskRequest {
+		return &pbCom.StartTaskRequest{
+			TaskID: task.TaskID,
+			Hosts:  []string{"127.0.0.1:8184"},
+			Params: &pbCom.TaskParams{
+				Algo:     pbCom.Algorithm_LINEAR_REGRESSION_VL,
+				TaskType: pbCom.TaskType_LEARN,
+				TrainParams: &pbCom.TrainParams{Label: "y", IsTagPart: isTagPart, IdName: "id",
+					PsiAlgorithm: psiAlgorithm, BatchSize: batchSize},
+			},
+		}
+	}
+	local := newRequest("", true, 4)
+	// empty PSI algorithm is resolved to ECDH, and the label is held by one party
+	agreed, err := taskFingerprint(newRequest("ecdh", false, 4))
+	checkErr(t, err)
+	checkErr(t, h.CheckTaskFingerprint(local, agreed, "127.0.0.1:8184"))
+	checkErr(t, h.CheckTaskFingerprint(local, nil, "127.0.0.1:8184"))
+
+	remote, err := taskFingerprint(newRequest("oprf", true, 8))
+	checkErr(t, err)
+	err = h.CheckTaskFingerprint(local, remote, "127.0.0.1:8184")
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeTaskMismatch {
+		t.Fatalf("expected mismatch error, got %v", err)
+	}
+	for _, mismatch := range []string{"PSI algorithm is ecdh locally but oprf on 127.0.0.1:8184",
+		"hash of hyperparameters", "label is held both"} {
+		if !strings.Contains(err.Error(), mismatch) {
+			t.Errorf("expected mismatch [%s] in error: %v", mismatch, err)
+		}
+	}
+	if _, ok := h.MpcTasks[task.TaskID]; ok || chain.finished[task.TaskID] != err.Error() {
+		t.Error("expected task failed")
+	}
+}
+
+func TestAgreePSIAlgorithm(t *testing.T) {
+	h, _, _ := newResourceHandler(t, ResourceLimits{})
+	newRequest := func(file string) *pbCom.StartTaskRequest {
+		return &pbCom.StartTaskRequest{
+			TaskID: "train-1",
+			File:   []byte(file),
+			Hosts:  []string{"127.0.0.1:8184"},
+			Params: &pbCom.TaskParams{
+				Algo:     pbCom.Algorithm_LINEAR_REGRESSION_VL,
+				TaskType: pbCom.TaskType_LEARN,
+				TrainParams: &pbCom.TrainParams{Label: "y", IdName: "id", PsiAlgorithm: psi.AlgorithmAuto,
+					IsTagPart: strings.Contains(file, ",y")},
+			},
+		}
+	}
+	initiator, receiver := newRequest("id,x1\n1,0.5\n2,0.3\n"), newRequest("id,x2,y\n1,0.1,1\n")
+
+	// auto is agreed on by the fingerprints, though the parties have different numbers of samples
+	remote, err := taskFingerprint(initiator)
+	checkErr(t, err)
+	local, err := taskFingerprint(receiver)
+	checkErr(t, err)
+	checkErr(t, checkFingerprint(local, remote, "127.0.0.1:8184", true))
+	if remote.SampleNum != 3 || remote.PsiAlgorithm != psi.AlgorithmAuto {
+		t.Fatalf("unexpected fingerprint %v", remote)
+	}
+
+	// small sets of both parties select ECDH
+	if algorithm := h.AgreePSIAlgorithm(receiver, remote); algorithm != psi.AlgorithmECDH {
+		t.Errorf("expected %s, got %s", psi.AlgorithmECDH, algorithm)
+	}
+
+	// the larger set of the initiator selects OPRF on both parties
+	receiver = newRequest("id,x2,y\n1,0.1,1\n")
+	remote.SampleNum = psi.AutoOPRFMinSamples
+	algorithm := h.AgreePSIAlgorithm(receiver, remote)
+	applyPSIAlgorithm(initiator, &pbTask.TaskResponse{PsiAlgorithm: algorithm})
+	if algorithm != psi.AlgorithmOPRF || receiver.Params.TrainParams.PsiAlgorithm != psi.AlgorithmOPRF ||
+		initiator.Params.TrainParams.PsiAlgorithm != psi.AlgorithmOPRF {
+		t.Errorf("expected %s on both parties, got %s and %s", psi.AlgorithmOPRF,
+			initiator.Params.TrainParams.PsiAlgorithm, receiver.Params.TrainParams.PsiAlgorithm)
+	}
+
+	// algorithms other than auto are kept
+	receiver = newRequest("id,x2,y\n1,0.1,1\n")
+	receiver.Params.TrainParams.PsiAlgorithm = psi.AlgorithmECDH
+	if algorithm := h.AgreePSIAlgorithm(receiver, remote); algorithm != "" || receiver.Params.TrainParams.PsiAlgorithm != psi.AlgorithmECDH {
+		t.Errorf("expected %s kept, got %s", psi.AlgorithmECDH, receiver.Params.TrainParams.PsiAlgorithm)
+	}
+}
+
+func TestCheckColumnPolicies(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	h.Chain = &policyChain{fakeChain: chain, policies: map[string]blockchain.ColumnPolicy{
+		"file-1": {FileID: "file-1", Columns: []string{"id", "x1", "y"}},
+	}}
+	h.Download.Type = ProxyExecutionMode
+	pubkey := ecdsa.PublicKeyFromPrivateKey(h.Node.PrivateKey)
+	newPolicyTask := func(taskType pbCom.TaskType, dataID string, columns ...string) blockchain.FLTask {
+		return &pbTask.FLTask{
+			TaskID:    "task-1",
+			AlgoParam: &pbCom.TaskParams{TaskType: taskType, TrainParams: &pbCom.TrainParams{Label: "y"}},
+			DataSets: []*pbTask.DataForTask{
+				{Executor: pubkey[:], DataID: dataID, PsiLabel: "id", Columns: columns},
+				{Executor: []byte("other"), DataID: "file-2", PsiLabel: "id"},
+			},
+		}
+	}
+
+	// the label of the training task is used by the party with label
+	checkErr(t, h.checkColumnPolicies(newPolicyTask(pbCom.TaskType_LEARN, "file-1", "x1")))
+	checkErr(t, h.checkColumnPolicies(newPolicyTask(pbCom.TaskType_PREDICT, "file-1", "x1")))
+	// files without column policy are not restricted
+	checkErr(t, h.checkColumnPolicies(newPolicyTask(pbCom.TaskType_LEARN, "file-3")))
+
+	for name, task := range map[string]blockchain.FLTask{
+		"disallowedColumn": newPolicyTask(pbCom.TaskType_LEARN, "file-1", "x1", "x2"),
+		"allColumns":       newPolicyTask(pbCom.TaskType_PREDICT, "file-1"),
+	} {
+		err := h.checkColumnPolicies(task)
+		if code, _ := errorx.Parse(err); code != errcodes.ErrCodeColumnPolicy {
+			t.Errorf("%s: expected column policy error, got %v", name, err)
+		}
+	}
+
+	// policies are honored in proxy mode only
+	h.Download.Type = SelfExecutionMode
+	checkErr(t, h.checkColumnPolicies(newPolicyTask(pbCom.TaskType_LEARN, "file-1", "x2")))
+}
+
+func TestCheckFeatureNum(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	h.Chain = &policyChain{fakeChain: chain}
+	pubkey := ecdsa.PublicKeyFromPrivateKey(h.Node.PrivateKey)
+	newTask := func(columns ...string) blockchain.FLTask {
+		return &pbTask.FLTask{
+			TaskID:    "task-1",
+			AlgoParam: &pbCom.TaskParams{TaskType: pbCom.TaskType_LEARN, TrainParams: &pbCom.TrainParams{Label: "y"}},
+			DataSets: []*pbTask.DataForTask{
+				{Executor: pubkey[:], DataID: "file-1", PsiLabel: "id", Columns: columns},
+				{Executor: []byte("other"), DataID: "file-2", PsiLabel: "id"},
+			},
+		}
+	}
+
+	// no limit by default
+	checkErr(t, h.checkFeatureNum(newTask()))
+
+	// the ID and label columns are not features
+	h.MaxFeatures = 2
+	checkErr(t, h.checkFeatureNum(newTask()))
+	checkErr(t, h.checkFeatureNum(newTask("x1")))
+
+	h.MaxFeatures = 1
+	checkErr(t, h.checkFeatureNum(newTask("x2")))
+	err := h.checkFeatureNum(newTask())
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeTooManyFeatures {
+		t.Fatalf("expected too many features error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "has 2 features, exceeding the limit 1") {
+		t.Errorf("expected the count and the limit in the error, got %v", err)
+	}
+}
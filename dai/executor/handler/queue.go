@@ -0,0 +1,197 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// queuedTask is a task waiting in the queue for free slots
+type queuedTask struct {
+	task     blockchain.FLTask
+	priority int32
+	queuedAt int64 // time when the task is queued
+	blocked  bool  // whether the task is reported waiting for free slots
+}
+
+// TaskQueue is a bounded priority queue of tasks waiting for free slots of the node,
+// tasks with higher priority are started first, and the ones with the same priority in the order they're queued
+type TaskQueue struct {
+	size            int           // maximum number of tasks in the queue
+	defaultPriority int32         // priority of tasks published without priority
+	tasks           []*queuedTask // sorted by priority in descending order, then by queued time
+	lock            sync.Mutex
+}
+
+// NewTaskQueue returns a queue holding at most size tasks,
+// tasks published without priority are given defaultPriority
+func NewTaskQueue(size int, defaultPriority int32) *TaskQueue {
+	return &TaskQueue{
+		size:            size,
+		defaultPriority: defaultPriority,
+	}
+}
+
+// Len returns the number of tasks in the queue
+func (q *TaskQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.tasks)
+}
+
+// Contains returns whether the task waits in the queue
+func (q *TaskQueue) Contains(taskID string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.index(taskID) >= 0
+}
+
+// Size returns the maximum number of tasks in the queue
+func (q *TaskQueue) Size() int {
+	return q.size
+}
+
+// push adds a task queued at now into the queue, false is returned if the queue is full.
+// A task already in the queue keeps its position.
+func (q *TaskQueue) push(task blockchain.FLTask, now int64) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.index(task.TaskID) >= 0 {
+		return true
+	}
+	if len(q.tasks) >= q.size {
+		return false
+	}
+	priority := task.AlgoParam.GetPriority()
+	if priority == 0 {
+		priority = q.defaultPriority
+	}
+	// insert the task behind the ones with higher or the same priority
+	i := sort.Search(len(q.tasks), func(i int) bool {
+		return q.tasks[i].priority < priority
+	})
+	q.tasks = append(q.tasks, nil)
+	copy(q.tasks[i+1:], q.tasks[i:])
+	q.tasks[i] = &queuedTask{task: task, priority: priority, queuedAt: now}
+	return true
+}
+
+// popRunnable removes and returns the first task in the queue allowed to start,
+// trainSlots and predictSlots are the numbers of training and predicting tasks could be added into execution pool
+func (q *TaskQueue) popRunnable(trainSlots, predictSlots int) (blockchain.FLTask, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for i, t := range q.tasks {
+		if t.task.AlgoParam.TaskType == pbCom.TaskType_LEARN && trainSlots == 0 {
+			continue
+		}
+		if t.task.AlgoParam.TaskType == pbCom.TaskType_PREDICT && predictSlots == 0 {
+			continue
+		}
+		q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+		return t.task, true
+	}
+	return nil, false
+}
+
+// markBlocked marks the tasks in the queue blocked, and returns the ones not marked before,
+// it is called when none of the tasks is allowed to start
+func (q *TaskQueue) markBlocked() (blocked []blockchain.FLTask) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for _, t := range q.tasks {
+		if !t.blocked {
+			t.blocked = true
+			blocked = append(blocked, t.task)
+		}
+	}
+	return blocked
+}
+
+// remove removes a task from the queue, false is returned if the task is not in the queue
+func (q *TaskQueue) remove(taskID string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	i := q.index(taskID)
+	if i < 0 {
+		return false
+	}
+	q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+	return true
+}
+
+// setPriority changes the priority of a task in the queue, and moves the task behind the ones with higher or
+// the same priority, the time it is queued is kept. false is returned if the task is not in the queue
+func (q *TaskQueue) setPriority(taskID string, priority int32) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	i := q.index(taskID)
+	if i < 0 {
+		return false
+	}
+	t := q.tasks[i]
+	t.priority = priority
+	q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+	i = sort.Search(len(q.tasks), func(i int) bool {
+		return q.tasks[i].priority < priority
+	})
+	q.tasks = append(q.tasks, nil)
+	copy(q.tasks[i+1:], q.tasks[i:])
+	q.tasks[i] = t
+	return true
+}
+
+// retain removes the tasks not in waiting from the queue, they are no longer waiting for execution,
+// as they're started by other executors or cancelled
+func (q *TaskQueue) retain(waiting map[string]bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	tasks := q.tasks[:0]
+	for _, t := range q.tasks {
+		if waiting[t.task.TaskID] {
+			tasks = append(tasks, t)
+		}
+	}
+	q.tasks = tasks
+}
+
+// expire removes and returns the tasks queued before deadline
+func (q *TaskQueue) expire(deadline int64) (expired []blockchain.FLTask) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	tasks := q.tasks[:0]
+	for _, t := range q.tasks {
+		if t.queuedAt < deadline {
+			expired = append(expired, t.task)
+		} else {
+			tasks = append(tasks, t)
+		}
+	}
+	q.tasks = tasks
+	return expired
+}
+
+// index returns the position of a task in the queue, or -1 if the task is not in the queue
+func (q *TaskQueue) index(taskID string) int {
+	for i, t := range q.tasks {
+		if t.task.TaskID == taskID {
+			return i
+		}
+	}
+	return -1
+}
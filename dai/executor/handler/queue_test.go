@@ -0,0 +1,221 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func newPriorityTask(id string, taskType pbCom.TaskType, priority int32) blockchain.FLTask {
+	return &pbTask.FLTask{TaskID: id, AlgoParam: &pbCom.TaskParams{TaskType: taskType, Priority: priority}}
+}
+
+// popAll pops runnable tasks until the queue has none, and returns their IDs
+func popAll(q *TaskQueue, trainSlots, predictSlots int) []string {
+	var ids []string
+	for {
+		task, ok := q.popRunnable(trainSlots, predictSlots)
+		if !ok {
+			return ids
+		}
+		ids = append(ids, task.TaskID)
+	}
+}
+
+func TestTaskQueueOrder(t *testing.T) {
+	q := NewTaskQueue(10, 5)
+	q.push(newPriorityTask("low", pbCom.TaskType_LEARN, 1), 1)
+	q.push(newPriorityTask("default1", pbCom.TaskType_LEARN, 0), 2)
+	q.push(newPriorityTask("high", pbCom.TaskType_LEARN, 9), 3)
+	q.push(newPriorityTask("default2", pbCom.TaskType_LEARN, 5), 4)
+	// pushing a queued task again keeps its position
+	q.push(newPriorityTask("default1", pbCom.TaskType_LEARN, 0), 5)
+
+	if q.Len() != 4 {
+		t.Fatalf("expected 4 queued tasks, got %d", q.Len())
+	}
+	got := popAll(q, 1, 1)
+	expected := []string{"high", "default1", "default2", "low"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected tasks popped in order %v, got %v", expected, got)
+	}
+}
+
+func TestTaskQueuePopRunnable(t *testing.T) {
+	q := NewTaskQueue(10, 0)
+	q.push(newPriorityTask("train", pbCom.TaskType_LEARN, 2), 1)
+	q.push(newPriorityTask("predict", pbCom.TaskType_PREDICT, 1), 2)
+
+	// the training task is skipped as there are no free training slots
+	if got := popAll(q, 0, 1); !reflect.DeepEqual(got, []string{"predict"}) {
+		t.Errorf("expected only the predicting task popped, got %v", got)
+	}
+	if got := popAll(q, 0, 0); len(got) != 0 {
+		t.Errorf("expected no task popped without free slots, got %v", got)
+	}
+	if got := popAll(q, 1, 0); !reflect.DeepEqual(got, []string{"train"}) {
+		t.Errorf("expected the training task popped, got %v", got)
+	}
+}
+
+func TestTaskQueueBoundAndExpiry(t *testing.T) {
+	q := NewTaskQueue(2, 0)
+	if !q.push(newTask("t1", pbCom.TaskType_LEARN), 1) || !q.push(newTask("t2", pbCom.TaskType_LEARN), 2) {
+		t.Fatal("failed to push tasks into the queue")
+	}
+	if q.push(newTask("t3", pbCom.TaskType_LEARN), 3) {
+		t.Error("expected pushing into a full queue to fail")
+	}
+
+	expired := q.expire(2)
+	if len(expired) != 1 || expired[0].TaskID != "t1" {
+		t.Errorf("expected t1 expired, got %v", expired)
+	}
+	q.retain(map[string]bool{"t3": true})
+	if q.Len() != 0 {
+		t.Errorf("expected tasks not waiting dropped, got %d queued tasks", q.Len())
+	}
+}
+
+func TestQueueTasks(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	h.Config.TrainTaskLimit = 1
+	h.MpcTaskMaxExecTime = time.Hour
+	h.Queue = NewTaskQueue(2, 0)
+
+	tasks := blockchain.FLTasks{
+		newPriorityTask("t1", pbCom.TaskType_LEARN, 1),
+		newPriorityTask("t2", pbCom.TaskType_LEARN, 3),
+		newPriorityTask("t3", pbCom.TaskType_LEARN, 2),
+	}
+	h.QueueTasks(tasks)
+	// the queue is full when t3 is queued
+	if reason, ok := chain.finished["t3"]; !ok || !strings.Contains(reason, "queue of executor is full") {
+		t.Errorf("expected t3 failed as the queue is full, got %q", reason)
+	}
+	if !reflect.DeepEqual(chain.executed, []string{"t3"}) {
+		t.Errorf("expected t3 set Processing before failed, got %v", chain.executed)
+	}
+	if status := h.GetResourceStatus(); status.QueuedTasks != 2 || status.QueueSize != 2 {
+		t.Errorf("expected 2/2 queued tasks in status, got %d/%d", status.QueuedTasks, status.QueueSize)
+	}
+	if inExecution, queued := h.GetLocalTaskStatus("t2"); inExecution || !queued {
+		t.Errorf("expected t2 queued, got inExecution %v, queued %v", inExecution, queued)
+	}
+
+	task, ok := h.NextQueuedTask()
+	if !ok || task.TaskID != "t2" {
+		t.Fatalf("expected t2 with the highest priority to start, got %v", task)
+	}
+	h.MpcTasks[task.TaskID] = &FlTask{FLTask: *task}
+	if inExecution, queued := h.GetLocalTaskStatus("t2"); !inExecution || queued {
+		t.Errorf("expected t2 in execution, got inExecution %v, queued %v", inExecution, queued)
+	}
+	if task, ok := h.NextQueuedTask(); ok {
+		t.Errorf("expected no task to start when the training task limit is reached, got %s", task.TaskID)
+	}
+	// t1 waiting for a free slot is reported when no task is allowed to start, and only once
+	if blocked := h.Queue.markBlocked(); len(blocked) != 0 {
+		t.Errorf("expected t1 already reported blocked, got %d tasks", len(blocked))
+	}
+
+	// t1 expires when it waits in the queue longer than the maximum execution time
+	h.MpcTaskMaxExecTime = 0
+	h.QueueTasks(tasks[:1])
+	if reason, ok := chain.finished["t1"]; !ok || !strings.Contains(reason, "waited in the queue") {
+		t.Errorf("expected t1 failed for waiting too long, got %q", reason)
+	}
+	if h.Queue.Len() != 0 {
+		t.Errorf("expected the queue empty, got %d tasks", h.Queue.Len())
+	}
+}
+
+func TestQueueTasksWithDependencies(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	h.MpcTaskMaxExecTime = time.Hour
+	h.Queue = NewTaskQueue(10, 0)
+	chain.tasks = map[string]blockchain.FLTask{
+		"train": {TaskID: "train", Status: blockchain.TaskProcessing},
+		"other": {TaskID: "other", Status: blockchain.TaskCancelled, ErrMessage: "task cancelled by the requester"},
+	}
+
+	predict := newTask("predict", pbCom.TaskType_PREDICT)
+	predict.DependsOn = []string{"train"}
+	downstream := newTask("downstream", pbCom.TaskType_LEARN)
+	downstream.DependsOn = []string{"train", "other"}
+	h.QueueTasks(blockchain.FLTasks{predict, downstream})
+
+	if h.Queue.Contains("predict") {
+		t.Error("expected predict waiting outside the queue until train finishes")
+	}
+	if reason := chain.finished["downstream"]; !strings.Contains(reason, "upstream task other is Cancelled") {
+		t.Errorf("expected downstream failed as an upstream task is cancelled, got %q", reason)
+	}
+
+	chain.tasks["train"].Status = blockchain.TaskFinished
+	h.QueueTasks(blockchain.FLTasks{predict})
+	if !h.Queue.Contains("predict") {
+		t.Error("expected predict queued after train finishes")
+	}
+	if _, ok := chain.finished["predict"]; ok {
+		t.Error("expected predict not failed")
+	}
+}
+
+func TestManageQueuedTask(t *testing.T) {
+	h, chain, _ := newResourceHandler(t, ResourceLimits{})
+	h.MpcTaskMaxExecTime = time.Hour
+	h.Queue = NewTaskQueue(10, 0)
+	h.QueueTasks(blockchain.FLTasks{
+		newPriorityTask("t1", pbCom.TaskType_LEARN, 3),
+		newPriorityTask("t2", pbCom.TaskType_LEARN, 2),
+		newPriorityTask("t3", pbCom.TaskType_LEARN, 1),
+	})
+	h.MpcTasks["running"] = &FlTask{FLTask: *newTask("running", pbCom.TaskType_LEARN)}
+
+	// t3 is moved behind t1 with the same priority
+	if err := h.SetQueuedTaskPriority("t3", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.CancelQueuedTask("t2", "cancelled by operator"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(chain.cancelled, []string{"t2"}) || chain.finished["t2"] != "cancelled by operator" {
+		t.Errorf("expected t2 recorded Cancelled, got %v", chain.cancelled)
+	}
+	if got := popAll(h.Queue, 1, 1); !reflect.DeepEqual(got, []string{"t1", "t3"}) {
+		t.Errorf("expected tasks popped in order [t1 t3], got %v", got)
+	}
+
+	for _, id := range []string{"running", "unknown"} {
+		errCancel := h.CancelQueuedTask(id, "cancelled by operator")
+		errPriority := h.SetQueuedTaskPriority(id, 1)
+		if errCancel == nil || errPriority == nil {
+			t.Errorf("expected errors managing task %s not queued", id)
+		}
+	}
+	if err := h.CancelQueuedTask("running", ""); !strings.Contains(err.Error(), "already in execution") {
+		t.Errorf("expected the task in execution reported, got %v", err)
+	}
+	if len(chain.cancelled) != 1 {
+		t.Errorf("expected only t2 cancelled, got %v", chain.cancelled)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"runtime"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// ResourceLimits defines the resources reserved by a task in execution and the budget of the node,
+// zero values mean no limit. The reservations admit tasks into the node's budget only, the memory and cpu
+// a task actually uses are not limited, as tasks share the executor process.
+type ResourceLimits struct {
+	MaxMemoryMB  int // memory reserved by a task, in MB
+	MaxCPUCores  int // cpu cores reserved by a task
+	NodeMemoryMB int // memory budget of all tasks in execution, in MB
+	NodeCPUCores int // cpu budget of all tasks in execution
+}
+
+// ResourceStatus describes tasks in execution and resources usage of the node
+type ResourceStatus struct {
+	TrainTasks       int
+	PredictTasks     int
+	TrainTaskLimit   int
+	PredictTaskLimit int
+	Limits           ResourceLimits
+	MemoryReservedMB int // memory reserved by tasks in execution
+	CPUReservedCores int // cpu cores reserved by tasks in execution
+	MemoryUsedMB     int // memory in use by the executor process
+	QueuedTasks      int // number of tasks waiting in the queue for free slots
+	QueueSize        int // maximum number of tasks in the queue
+}
+
+// readMemoryUsageMB returns the memory in use by the executor process, in MB
+var readMemoryUsageMB = func() int {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return int((ms.HeapInuse + ms.StackInuse) >> 20)
+}
+
+// availableSlots returns the number of tasks the node's budget allows to add besides running ones,
+// limited is false if neither memory nor cpu budget is limited
+func (r ResourceLimits) availableSlots(running int) (slots int, limited bool) {
+	if r.MaxMemoryMB > 0 && r.NodeMemoryMB > 0 {
+		slots, limited = r.NodeMemoryMB/r.MaxMemoryMB-running, true
+	}
+	if r.MaxCPUCores > 0 && r.NodeCPUCores > 0 {
+		if s := r.NodeCPUCores/r.MaxCPUCores - running; !limited || s < slots {
+			slots, limited = s, true
+		}
+	}
+	if slots < 0 {
+		slots = 0
+	}
+	return slots, limited
+}
+
+// GetResourceStatus returns tasks in execution and resources usage of the node
+func (m *MpcModelHandler) GetResourceStatus() ResourceStatus {
+	m.RLock()
+	status := ResourceStatus{
+		TrainTaskLimit:   m.Config.TrainTaskLimit,
+		PredictTaskLimit: m.Config.PredictTaskLimit,
+		Limits:           m.Resource,
+	}
+	for _, task := range m.MpcTasks {
+		if task.AlgoParam.TaskType == pbCom.TaskType_LEARN {
+			status.TrainTasks++
+		} else {
+			status.PredictTasks++
+		}
+	}
+	m.RUnlock()
+
+	running := status.TrainTasks + status.PredictTasks
+	status.MemoryReservedMB = status.Limits.MaxMemoryMB * running
+	status.CPUReservedCores = status.Limits.MaxCPUCores * running
+	status.MemoryUsedMB = readMemoryUsageMB()
+	if m.Queue != nil {
+		status.QueuedTasks = m.Queue.Len()
+		status.QueueSize = m.Queue.Size()
+	}
+	return status
+}
+
+// GetLocalTaskStatus returns whether the task is in the execution pool, or waits in the queue of the node
+func (m *MpcModelHandler) GetLocalTaskStatus(taskID string) (inExecution, queued bool) {
+	m.RLock()
+	_, inExecution = m.MpcTasks[taskID]
+	m.RUnlock()
+	if m.Queue != nil {
+		queued = m.Queue.Contains(taskID)
+	}
+	return inExecution, queued
+}
+
+// CheckNodeMemoryUsage is a node-wide memory guard, it stops the latest added task if the memory usage
+// of the executor process exceeds the node's memory budget NodeMemoryMB,
+// so that the other tasks keep running instead of the executor being killed for out of memory.
+// Tasks share the executor process and their memory is not accounted separately, so the latest task
+// is stopped whichever task uses the memory, MaxMemoryMB is a reservation for admission rather than a limit.
+func (m *MpcModelHandler) CheckNodeMemoryUsage() {
+	m.RLock()
+	var latest *FlTask
+	for _, task := range m.MpcTasks {
+		if latest == nil || task.AddedTime > latest.AddedTime {
+			latest = task
+		}
+	}
+	running := len(m.MpcTasks)
+	limit := m.Resource.NodeMemoryMB
+	m.RUnlock()
+	if latest == nil || limit == 0 {
+		return
+	}
+
+	used := readMemoryUsageMB()
+	if used <= limit {
+		return
+	}
+	// the memory may be garbage of finished tasks
+	runtime.GC()
+	if used = readMemoryUsageMB(); used <= limit {
+		return
+	}
+	reason := fmt.Sprintf("task stopped for exceeding memory limit, memory usage %dMB of the executor exceeds the node's budget %dMB "+
+		"with %d tasks in execution, the latest added task is stopped", used, limit, running)
+	logger.WithField(logging.TaskIDKey, latest.TaskID).Warn(reason)
+	m.updateTaskStatusAndStopLocalMpc(latest.TaskID, reason, "")
+}
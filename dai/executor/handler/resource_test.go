@@ -0,0 +1,182 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// fakeChain records the error message of finished tasks, and the cancelled and executed ones
+type fakeChain struct {
+	Blockchain
+	finished  map[string]string
+	cancelled []string
+	timedOut  []string
+	executed  []string
+	tasks     map[string]blockchain.FLTask // upstream tasks of the tasks with dependencies
+}
+
+func (c *fakeChain) ExecuteTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	c.executed = append(c.executed, opt.TaskID)
+	return nil
+}
+
+func (c *fakeChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	if task, ok := c.tasks[id]; ok {
+		return task, nil
+	}
+	return &pbTask.FLTask{TaskID: id, Status: blockchain.TaskProcessing}, nil
+}
+
+func (c *fakeChain) FinishTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	c.finished[opt.TaskID] = opt.ErrMessage
+	if opt.Cancelled {
+		c.cancelled = append(c.cancelled, opt.TaskID)
+	}
+	if opt.TimedOut {
+		c.timedOut = append(c.timedOut, opt.TaskID)
+	}
+	return nil
+}
+
+// fakeMpc records the stopped and cancelled tasks
+type fakeMpc struct {
+	mpc.Mpc
+	stopped   []string
+	cancelled []string
+}
+
+func (m *fakeMpc) StopTask(req *pbCom.StopTaskRequest) error {
+	m.stopped = append(m.stopped, req.TaskID)
+	return nil
+}
+
+func (m *fakeMpc) CancelTask(req *pbCom.StopTaskRequest) error {
+	m.cancelled = append(m.cancelled, req.TaskID)
+	return nil
+}
+
+func newResourceHandler(t *testing.T, limits ResourceLimits) (*MpcModelHandler, *fakeChain, *fakeMpc) {
+	privateKey, _, err := ecdsa.GenerateKeyPair()
+	checkErr(t, err)
+	chain := &fakeChain{finished: make(map[string]string)}
+	m := &fakeMpc{}
+	return &MpcModelHandler{
+		Config:   mpc.Config{TrainTaskLimit: 10, PredictTaskLimit: 10},
+		Node:     Node{Local: peer.Local{PrivateKey: privateKey}},
+		Chain:    chain,
+		Mpc:      m,
+		Resource: limits,
+		MpcTasks: make(map[string]*FlTask),
+	}, chain, m
+}
+
+func newTask(id string, taskType pbCom.TaskType) blockchain.FLTask {
+	return &pbTask.FLTask{TaskID: id, AlgoParam: &pbCom.TaskParams{TaskType: taskType}}
+}
+
+func TestAvailableSlots(t *testing.T) {
+	cases := []struct {
+		name    string
+		limits  ResourceLimits
+		running int
+		slots   int
+		limited bool
+	}{
+		{"noLimit", ResourceLimits{}, 3, 0, false},
+		{"reservationWithoutBudget", ResourceLimits{MaxMemoryMB: 1024}, 3, 0, false},
+		{"memory", ResourceLimits{MaxMemoryMB: 1024, NodeMemoryMB: 4096}, 1, 3, true},
+		{"cpu", ResourceLimits{MaxCPUCores: 2, NodeCPUCores: 8}, 1, 3, true},
+		{"cpuTighter", ResourceLimits{MaxMemoryMB: 1024, NodeMemoryMB: 4096, MaxCPUCores: 4, NodeCPUCores: 8}, 1, 1, true},
+		{"exhausted", ResourceLimits{MaxMemoryMB: 1024, NodeMemoryMB: 2048}, 3, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			slots, limited := c.limits.availableSlots(c.running)
+			if slots != c.slots || limited != c.limited {
+				t.Errorf("expected (%d, %t), got (%d, %t)", c.slots, c.limited, slots, limited)
+			}
+		})
+	}
+}
+
+func TestAddTaskWithinBudget(t *testing.T) {
+	h, _, _ := newResourceHandler(t, ResourceLimits{MaxMemoryMB: 1024, NodeMemoryMB: 2048})
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-1", pbCom.TaskType_LEARN)))
+	if tNum, pNum := h.GetAvailableTasksNum(); tNum != 1 || pNum != 1 {
+		t.Errorf("expected 1 available task of each type limited by budget, got %d and %d", tNum, pNum)
+	}
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("predict-1", pbCom.TaskType_PREDICT)))
+
+	err := h.addTaskIntoMpcHandler(newTask("train-2", pbCom.TaskType_LEARN))
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeTooMuchTasks {
+		t.Fatalf("expected error code %s when budget is exhausted, got: %v", errcodes.ErrCodeTooMuchTasks, err)
+	}
+	if tNum, pNum := h.GetAvailableTasksNum(); tNum != 0 || pNum != 0 {
+		t.Errorf("expected no available tasks, got %d and %d", tNum, pNum)
+	}
+
+	status := h.GetResourceStatus()
+	if status.TrainTasks != 1 || status.PredictTasks != 1 || status.MemoryReservedMB != 2048 {
+		t.Errorf("unexpected resource status: %+v", status)
+	}
+}
+
+func TestCheckNodeMemoryUsage(t *testing.T) {
+	usage := 1000
+	defer func(f func() int) { readMemoryUsageMB = f }(readMemoryUsageMB)
+	readMemoryUsageMB = func() int { return usage }
+
+	h, chain, m := newResourceHandler(t, ResourceLimits{MaxMemoryMB: 1024, NodeMemoryMB: 2048})
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-1", pbCom.TaskType_LEARN)))
+	checkErr(t, h.addTaskIntoMpcHandler(newTask("train-2", pbCom.TaskType_LEARN)))
+	h.MpcTasks["train-2"].AddedTime = h.MpcTasks["train-1"].AddedTime + 1
+
+	// within the node's budget, though over the memory reserved by a task
+	usage = 2000
+	h.CheckNodeMemoryUsage()
+	if len(m.stopped) != 0 {
+		t.Fatalf("no task should be stopped, got: %v", m.stopped)
+	}
+
+	// the latest added task is stopped with a clear reason
+	usage = 3000
+	h.CheckNodeMemoryUsage()
+	if len(m.stopped) != 1 || m.stopped[0] != "train-2" {
+		t.Fatalf("expected the latest task stopped, got: %v", m.stopped)
+	}
+	if reason := chain.finished["train-2"]; !strings.Contains(reason, "exceeding memory limit") {
+		t.Errorf("unexpected task error message: %s", reason)
+	}
+	if _, ok := h.MpcTasks["train-1"]; !ok {
+		t.Error("the other task should keep running")
+	}
+}
+
+func checkErr(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// SaveResultSignature stores sig of the prediction result stored with name, under the key of the name suffixed with blockchain.ResultSignatureSuffix
+func (s FileStorage) SaveResultSignature(ctx context.Context, name string, sig *pbTask.ResultSignature) error {
+	if s.SignatureStorage == nil {
+		return nil
+	}
+	text, err := json.Marshal(sig)
+	if err != nil {
+		return errorx.Internal(err, "failed to marshal signature of prediction result")
+	}
+	if _, err := s.SignatureStorage.Upload(ctx, name+blockchain.ResultSignatureSuffix, bytes.NewReader(text)); err != nil {
+		return errorx.Wrap(err, "failed to save signature of prediction result")
+	}
+	return nil
+}
+
+// LoadResultSignature returns the signature of the prediction result stored with name
+func (s FileStorage) LoadResultSignature(ctx context.Context, name string) (*pbTask.ResultSignature, error) {
+	if s.SignatureStorage == nil {
+		return nil, errorx.New(errorx.ErrCodeNotFound, "signatures of prediction results are not stored")
+	}
+	r, err := s.SignatureStorage.Download(ctx, name+blockchain.ResultSignatureSuffix)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to get signature of prediction result")
+	}
+	defer r.Close()
+	text, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to read signature of prediction result")
+	}
+	var sig pbTask.ResultSignature
+	if err := json.Unmarshal(text, &sig); err != nil {
+		return nil, errorx.Internal(err, "failed to unmarshal signature of prediction result")
+	}
+	return &sig, nil
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"io"
+
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
+)
+
+// tracingChain traces the calls of the wrapped Blockchain, the calls about a task
+// are children of the task's root span
+type tracingChain struct {
+	Blockchain
+}
+
+// NewTracingChain returns a Blockchain tracing the calls
+func NewTracingChain(chain Blockchain) Blockchain {
+	return &tracingChain{Blockchain: chain}
+}
+
+// startCall starts the span of a call of method, taskID is empty if the call is not about a task
+func startCall(method, taskID string) trace.Span {
+	_, span := tracing.StartSpan(taskID, "blockchain."+method, attribute.String("blockchain.method", method))
+	return span
+}
+
+func (c *tracingChain) RegisterExecutorNode(opt *blockchain.AddNodeOptions) error {
+	span := startCall("RegisterExecutorNode", "")
+	err := c.Blockchain.RegisterExecutorNode(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) GetExecutorNodeByID(id string) (blockchain.ExecutorNode, error) {
+	span := startCall("GetExecutorNodeByID", "")
+	node, err := c.Blockchain.GetExecutorNodeByID(id)
+	tracing.End(span, err)
+	return node, err
+}
+
+func (c *tracingChain) ListExecutorNodes() (blockchain.ExecutorNodes, error) {
+	span := startCall("ListExecutorNodes", "")
+	nodes, err := c.Blockchain.ListExecutorNodes()
+	tracing.End(span, err)
+	return nodes, err
+}
+
+func (c *tracingChain) RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error {
+	span := startCall("RevokeExecutorKey", "")
+	err := c.Blockchain.RevokeExecutorKey(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) ListTask(opt *blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	span := startCall("ListTask", "")
+	tasks, err := c.Blockchain.ListTask(opt)
+	tracing.End(span, err)
+	return tasks, err
+}
+
+func (c *tracingChain) PublishTask(opt *blockchain.PublishFLTaskOptions) error {
+	span := startCall("PublishTask", opt.FLTask.TaskID)
+	err := c.Blockchain.PublishTask(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) GetTaskById(id string) (blockchain.FLTask, error) {
+	span := startCall("GetTaskById", id)
+	task, err := c.Blockchain.GetTaskById(id)
+	tracing.End(span, err)
+	return task, err
+}
+
+func (c *tracingChain) ConfirmTask(opt *blockchain.FLTaskConfirmOptions) error {
+	span := startCall("ConfirmTask", opt.TaskID)
+	err := c.Blockchain.ConfirmTask(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) RejectTask(opt *blockchain.FLTaskConfirmOptions) error {
+	span := startCall("RejectTask", opt.TaskID)
+	err := c.Blockchain.RejectTask(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) ExecuteTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	span := startCall("ExecuteTask", opt.TaskID)
+	err := c.Blockchain.ExecuteTask(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) FinishTask(opt *blockchain.FLTaskExeStatusOptions) error {
+	span := startCall("FinishTask", opt.TaskID)
+	err := c.Blockchain.FinishTask(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) AnchorAudit(opt *blockchain.AnchorAuditOptions) error {
+	span := startCall("AnchorAudit", "")
+	err := c.Blockchain.AnchorAudit(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error) {
+	span := startCall("GetColumnPolicy", "")
+	policy, err := c.Blockchain.GetColumnPolicy(fileID, executor)
+	tracing.End(span, err)
+	return policy, err
+}
+
+func (c *tracingChain) GetFileByID(id string) (xdbchain.File, error) {
+	span := startCall("GetFileByID", "")
+	file, err := c.Blockchain.GetFileByID(id)
+	tracing.End(span, err)
+	return file, err
+}
+
+func (c *tracingChain) ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error) {
+	span := startCall("ListFileAuthApplications", "")
+	auths, err := c.Blockchain.ListFileAuthApplications(opt)
+	tracing.End(span, err)
+	return auths, err
+}
+
+func (c *tracingChain) PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error {
+	span := startCall("PublishFileAuthApplication", "")
+	err := c.Blockchain.PublishFileAuthApplication(opt)
+	tracing.End(span, err)
+	return err
+}
+
+func (c *tracingChain) ListNodes() (xdbchain.Nodes, error) {
+	span := startCall("ListNodes", "")
+	nodes, err := c.Blockchain.ListNodes()
+	tracing.End(span, err)
+	return nodes, err
+}
+
+// tracingStorage traces uploads and downloads of the wrapped storage as children of the span in ctx,
+// a download ends when the returned reader is closed
+type tracingStorage struct {
+	storage.StorageBackend
+	kind string
+}
+
+// NewTracingStorage returns a storage tracing uploads and downloads, kind is the kind of files it stores
+func NewTracingStorage(kind string, s storage.StorageBackend) storage.StorageBackend {
+	return &tracingStorage{StorageBackend: s, kind: kind}
+}
+
+func (s *tracingStorage) Upload(ctx context.Context, key string, value io.Reader) (string, error) {
+	ctx, span := tracing.Start(ctx, "storage.Upload", attribute.String("storage.kind", s.kind), attribute.String("storage.key", key))
+	id, err := s.StorageBackend.Upload(ctx, key, value)
+	tracing.End(span, err)
+	return id, err
+}
+
+func (s *tracingStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	ctx, span := tracing.Start(ctx, "storage.Download", attribute.String("storage.kind", s.kind), attribute.String("storage.key", key))
+	r, err := s.StorageBackend.Download(ctx, key)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	return &tracingReader{ReadCloser: r, span: span}, nil
+}
+
+// tracingReader ends the span when it's closed, records the error of reading if any
+type tracingReader struct {
+	io.ReadCloser
+	span trace.Span
+	err  error
+}
+
+func (r *tracingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+func (r *tracingReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.err == nil {
+		r.err = err
+	}
+	tracing.End(r.span, r.err)
+	return err
+}
@@ -14,14 +14,17 @@
 package monitor
 
 import (
+	"bytes"
 	"context"
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
 	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/peer"
 	"github.com/sirupsen/logrus"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 )
 
@@ -39,20 +42,27 @@ type Blockchain interface {
 	ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error)
 	// publish sample file's authorization application
 	PublishFileAuthApplication(opt *xdbchain.PublishFileAuthOptions) error
+	// revoke the old key of the node once the grace period of its key rotation ends
+	RevokeExecutorKey(opt *blockchain.RevokeKeyOptions) error
 }
 
 type MpcHandler interface {
 	// TaskStartPrepare prepare resources before starting local MPC task, like parameters and sample data
-	TaskStartPrepare(task blockchain.FLTask) (*pbCom.StartTaskRequest, error)
+	TaskStartPrepare(ctx context.Context, task blockchain.FLTask) (*pbCom.StartTaskRequest, error)
 	// StartLocalMpcTask start local mpc task
 	// task required parameters passed when starting local task training
 	StartLocalMpcTask(task *pbCom.StartTaskRequest, isSendTaskToOthers bool) error
-	// GetAvailableTasksNum get available numbers of task execution resources
-	// used check how many tasks could be handled this round
-	GetAvailableTasksNum() (int, int)
+	// QueueTasks queues the tasks waiting for execution by priority,
+	// tasks are failed if the queue is full or they wait too long, or their upstream tasks end unfinished
+	QueueTasks(tasks blockchain.FLTasks)
+	// NextQueuedTask removes and returns the queued task with the highest priority that is allowed to start
+	NextQueuedTask() (blockchain.FLTask, bool)
 	// CheckMpcTimeOutTasks checks tasks in execution pool if they're expired,
 	// and stops expired tasks
 	CheckMpcTimeOutTasks()
+	// CheckNodeMemoryUsage checks memory usage of the executor with tasks in execution pool,
+	// and stops the latest added task if the usage exceeds the limit of the node
+	CheckNodeMemoryUsage()
 }
 
 // TaskMonitor
@@ -64,11 +74,67 @@ type TaskMonitor struct {
 
 	Blockchain Blockchain // task contract invoke
 	MpcHandler MpcHandler
+	Audit      *handler.AuditLogger        // records the tasks confirmed or rejected, nil if audit logging is not configured
+	Allowlist  *handler.RequesterAllowlist // the requesters whose tasks are confirmed, nil if all requesters are allowed
+
+	// Previous is the identity of the node before its latest key rotation, the tasks published to which are executed
+	// until they end, nil if the key has never been rotated. It confirms no tasks after GraceUntil, when it's revoked
+	// on blockchain by the current key.
+	Previous        *peer.Local
+	GraceUntil      time.Time
+	previousRevoked bool
 
 	doneLoopReqC  chan struct{} // doneLoopReqC closed when loop breaks
 	doneRetryReqC chan struct{} // doneRetryReqC closed when processing task retry end
 }
 
+// previous returns the monitor of the node's identity before its key rotation, nil if there's none
+func (t *TaskMonitor) previous() *TaskMonitor {
+	if t.Previous == nil {
+		return nil
+	}
+	p := *t
+	p.PrivateKey = t.Previous.PrivateKey
+	p.PublicKey = ecdsa.PublicKeyFromPrivateKey(t.Previous.PrivateKey)
+	p.Previous = nil
+	return &p
+}
+
+// identity returns the monitor of the node's identity executing task, see handler.Node.Identity
+func (t *TaskMonitor) identity(task blockchain.FLTask) *TaskMonitor {
+	p := t.previous()
+	if p == nil {
+		return t
+	}
+	var current, previous bool
+	for _, ds := range task.DataSets {
+		current = current || bytes.Equal(ds.Executor, t.PublicKey[:])
+		previous = previous || bytes.Equal(ds.Executor, p.PublicKey[:])
+	}
+	if previous && !current {
+		return p
+	}
+	return t
+}
+
+// listTasks lists the tasks of the node by opt, along with the ones published to its previous identity
+func (t *TaskMonitor) listTasks(opt blockchain.ListFLTaskOptions) (blockchain.FLTasks, error) {
+	opt.ExecPubKey = t.PublicKey[:]
+	tasks, err := t.Blockchain.ListTask(&opt)
+	if err != nil {
+		return nil, err
+	}
+	if p := t.previous(); p != nil {
+		opt.ExecPubKey = p.PublicKey[:]
+		previousTasks, err := t.Blockchain.ListTask(&opt)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, previousTasks...)
+	}
+	return tasks, nil
+}
+
 // StartTaskLoopRequest starts timed task which will block until receive Stop signal
 func (t *TaskMonitor) StartTaskLoopRequest(ctx context.Context) {
 	go t.loopRequest(ctx)
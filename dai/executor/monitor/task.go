@@ -28,9 +28,13 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/handler"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/envelope"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
 )
 
 // loopRequest checks blockchain every some seconds to find tasks ready to execute,
@@ -60,6 +64,17 @@ func (t *TaskMonitor) loopRequest(ctx context.Context) {
 		if err := t.getUnconfirmedTaskAndConfirm(); err != nil {
 			logger.WithError(err).Error("failed to find confirming tasks to confirm")
 		}
+		// the previous identity confirms tasks in the grace period of the key rotation only,
+		// and is revoked once the grace period ends
+		if p := t.previous(); p != nil && time.Now().Before(t.GraceUntil) {
+			if err := p.getUnconfirmedTaskAndConfirm(); err != nil {
+				logger.WithError(err).Error("failed to find confirming tasks of the previous identity to confirm")
+			}
+		} else if p != nil && !t.previousRevoked {
+			if err := t.revokePrevious(p); err != nil {
+				logger.WithError(err).Error("failed to revoke the previous identity")
+			}
+		}
 
 		//checks blockchain every some seconds to find tasks ready to execute,
 		//then starts Multi-Party Computation for each task.
@@ -70,9 +85,31 @@ func (t *TaskMonitor) loopRequest(ctx context.Context) {
 		//checks tasks in execution pool if they're expired,
 		// then stops expired tasks
 		t.MpcHandler.CheckMpcTimeOutTasks()
+
+		// checks memory usage of the executor with tasks in execution pool,
+		// stops the latest added task if the usage exceeds the limit of the node
+		t.MpcHandler.CheckNodeMemoryUsage()
 	}
 }
 
+// revokePrevious revokes the previous identity p on blockchain with the current key after the grace period
+// of the key rotation, so it confirms no tasks whatever time it claims, it's retried in the next loop if it fails
+func (t *TaskMonitor) revokePrevious(p *TaskMonitor) error {
+	opt := &blockchain.RevokeKeyOptions{
+		OldID:       p.PublicKey[:],
+		CurrentTime: time.Now().UnixNano(),
+	}
+	if err := blockchain.SignKeyRevocation(opt, t.PrivateKey); err != nil {
+		return err
+	}
+	if err := t.Blockchain.RevokeExecutorKey(opt); err != nil && !errorx.Is(err, errorx.ErrCodeAlreadyUpdate) {
+		return errorx.Wrap(err, "failed to revoke key %x on blockchain", p.PublicKey[:])
+	}
+	t.previousRevoked = true
+	logger.Infof("grace period of the key rotation ended, key %x is revoked", p.PublicKey[:])
+	return nil
+}
+
 // getUnconfirmedTaskAndConfirm query confirming tasks that need to be confirmed by the
 // executor node from chain, check whether the executor node has permission to use the sample file,
 // if not, publishes a file authorization application, otherwise confrims or rejects the task
@@ -94,9 +131,25 @@ func (t *TaskMonitor) getUnconfirmedTaskAndConfirm() error {
 	}
 	// 2. confirm tasks by the executor node's ExecutionType
 	for _, task := range taskList {
+		// reject tasks of requesters not allowed to use the node
+		if err := t.Allowlist.Check(task.Requester); err != nil {
+			_, reason := errorx.Parse(err)
+			if err := t.confirmTaskOnChain(task, reason, false); err != nil {
+				return errorx.Wrap(err, "reject task failed, taskID: %s, Executor: %x", task.TaskID, t.PublicKey[:])
+			}
+			continue
+		}
+		// reject tasks with invalid parameters, which may be published by clients other than requester-cli
+		if err := checkTaskParams(task); err != nil {
+			_, reason := errorx.Parse(err)
+			if err := t.confirmTaskOnChain(task, reason, false); err != nil {
+				return errorx.Wrap(err, "reject task failed, taskID: %s, Executor: %x", task.TaskID, t.PublicKey[:])
+			}
+			continue
+		}
 		for _, ds := range task.DataSets {
 			if bytes.Equal(ds.Executor, t.PublicKey[:]) {
-				if err := t.confirmTaskByExecutionType(task.TaskID, ds); err != nil {
+				if err := t.confirmTaskByExecutionType(task, ds); err != nil {
 					return err
 				}
 			}
@@ -109,9 +162,10 @@ func (t *TaskMonitor) getUnconfirmedTaskAndConfirm() error {
 // If t.ExecutionType is "Self", means the dataOwner node has authorized sample files to the executor
 // node, the executor node can directly confirm tasks. if t.ExecutionType is "Proxy",
 // the executor node confirms or rejects tasks by the file authorization application.
-func (t *TaskMonitor) confirmTaskByExecutionType(taskID string, ds *pbTask.DataForTask) error {
+func (t *TaskMonitor) confirmTaskByExecutionType(task blockchain.FLTask, ds *pbTask.DataForTask) error {
+	taskID := task.TaskID
 	if t.ExecutionType == handler.SelfExecutionMode {
-		if err := t.confirmTaskOnChain(taskID, "", true); err != nil {
+		if err := t.confirmTaskOnChain(task, "", true); err != nil {
 			return errorx.Wrap(err, "confirm task failed, taskID: %s, ExecutionType: %s, Executor: %x",
 				taskID, t.ExecutionType, t.PublicKey[:])
 		}
@@ -143,16 +197,16 @@ func (t *TaskMonitor) confirmTaskByExecutionType(taskID string, ds *pbTask.DataF
 			if fileAuths[0].Status == xdbchain.FileAuthRejected {
 				rejectReason := fmt.Sprintf("File authorization application is refused, authID: %s, reason: %s",
 					fileAuths[0].ID, fileAuths[0].RejectReason)
-				if err := t.confirmTaskOnChain(taskID, rejectReason, false); err != nil {
+				if err := t.confirmTaskOnChain(task, rejectReason, false); err != nil {
 					return errorx.Wrap(err, "reject task failed, taskID: %s, Executor: %x", taskID, t.PublicKey[:])
 				}
 			} else if fileAuths[0].Status == xdbchain.FileAuthApproved && fileAuths[0].ExpireTime > currentTime {
 				// if the authorization application has been passed and has not expired, then confirm the task
-				if err := t.confirmTaskOnChain(taskID, "", true); err != nil {
+				if err := t.confirmTaskOnChain(task, "", true); err != nil {
 					return errorx.Wrap(err, "confirm task failed, taskID: %s, Executor: %x", taskID, t.PublicKey[:])
 				}
 			} else {
-				logger.Infof("the file authorization application is Unapproved, fileAuthID: %s, taskID: %s", fileAuths[0].ID, taskID)
+				logger.WithField(logging.TaskIDKey, taskID).Infof("the file authorization application is Unapproved, fileAuthID: %s", fileAuths[0].ID)
 			}
 		}
 	}
@@ -160,8 +214,9 @@ func (t *TaskMonitor) confirmTaskByExecutionType(taskID string, ds *pbTask.DataF
 }
 
 // confirmTask after the file owner confirms or rejects the executor's file authorization application
-// then the executor node confirms or rejects the task
-func (t *TaskMonitor) confirmTaskOnChain(taskID, rejectReason string, isConfirm bool) error {
+// then the executor node confirms or rejects the task, and records it in the audit log
+func (t *TaskMonitor) confirmTaskOnChain(task blockchain.FLTask, rejectReason string, isConfirm bool) error {
+	taskID := task.TaskID
 	currentTime := time.Now().UnixNano()
 	confirmOptions := &blockchain.FLTaskConfirmOptions{
 		Pubkey:       t.PublicKey[:],
@@ -184,21 +239,23 @@ func (t *TaskMonitor) confirmTaskOnChain(taskID, rejectReason string, isConfirm
 	if isConfirm {
 		if err := t.Blockchain.ConfirmTask(confirmOptions); err != nil {
 			if code, _ := errorx.Parse(err); code == errorx.ErrCodeAlreadyUpdate {
-				logger.Debugf("task already confirmed, taskID: %s, Executor: %x", taskID, t.PublicKey[:])
+				logger.WithField(logging.TaskIDKey, taskID).Debugf("task already confirmed, Executor: %x", t.PublicKey[:])
 				return nil
 			}
 			return err
 		}
-		logger.Infof("confrims the task successfully, taskID: %s", taskID)
+		logger.WithField(logging.TaskIDKey, taskID).Info("confrims the task successfully")
+		t.Audit.Record(task, handler.AuditEventConfirm, handler.AuditResultConfirmed, "")
 	} else {
 		if err := t.Blockchain.RejectTask(confirmOptions); err != nil {
 			if code, _ := errorx.Parse(err); code == errorx.ErrCodeAlreadyUpdate {
-				logger.Debugf("task already rejected, taskID: %s, Executor: %x", taskID, t.PublicKey[:])
+				logger.WithField(logging.TaskIDKey, taskID).Debugf("task already rejected, Executor: %x", t.PublicKey[:])
 				return nil
 			}
 			return err
 		}
-		logger.Infof("rejects the task successfully, taskID: %s, rejectReason: %s", taskID, rejectReason)
+		logger.WithField(logging.TaskIDKey, taskID).Infof("rejects the task successfully, rejectReason: %s", rejectReason)
+		t.Audit.Record(task, handler.AuditEventConfirm, handler.AuditResultRejected, rejectReason)
 	}
 	return nil
 }
@@ -244,12 +301,11 @@ func (t *TaskMonitor) publishFileAuthApplication(fileID, taskID string, fileOwne
 //  the task may be in Processing stage forever
 func (t *TaskMonitor) getToProcessTaskAndStart() error {
 	// 1. find all ready tasks from chain
-	taskList, err := t.Blockchain.ListTask(&blockchain.ListFLTaskOptions{
-		ExecPubKey: t.PublicKey[:],
-		Status:     blockchain.TaskToProcess,
-		TimeStart:  0,
-		TimeEnd:    time.Now().UnixNano(),
-		Limit:      blockchain.TaskListMaxNum,
+	taskList, err := t.listTasks(blockchain.ListFLTaskOptions{
+		Status:    blockchain.TaskToProcess,
+		TimeStart: 0,
+		TimeEnd:   time.Now().UnixNano(),
+		Limit:     blockchain.TaskListMaxNum,
 	})
 	if err != nil {
 		return errorx.Wrap(err, "failed to find ToProcess task list")
@@ -259,32 +315,29 @@ func (t *TaskMonitor) getToProcessTaskAndStart() error {
 		return nil
 	}
 
-	for _, task := range taskList {
-		// 2. verify whether the training or predicting task resources pool is full
-		trainAvailableNum, predictAvailableNum := t.MpcHandler.GetAvailableTasksNum()
-		if task.AlgoParam.TaskType == pbCom.TaskType_LEARN && trainAvailableNum == 0 {
-			logger.Info("Training task resources is full")
-			continue
-		}
-		if task.AlgoParam.TaskType == pbCom.TaskType_PREDICT && predictAvailableNum == 0 {
-			logger.Info("Predicting task resources is full")
-			continue
-		}
+	// 2. queue tasks by priority, the ones can't be queued as the queue is full are failed
+	t.MpcHandler.QueueTasks(taskList)
 
-		// 3. update task status
-		if err := t.updateTaskExecStatus(task.TaskID); err != nil {
+	// start queued tasks in priority order while the training or predicting task resources pool is not full
+	for {
+		task, ok := t.MpcHandler.NextQueuedTask()
+		if !ok {
+			break
+		}
+		// 3. update task status, fails if the task is started by another executor
+		if err := t.identity(task).updateTaskExecStatus(task.TaskID); err != nil {
 			continue
 		}
 		// 4. prepare resources before starting local MPC task
-		startRequest, err := t.MpcHandler.TaskStartPrepare(task)
+		startRequest, err := t.MpcHandler.TaskStartPrepare(context.Background(), task)
 		if err != nil {
-			logger.WithError(err).Errorf("error occurred when task start prepare, and taskId: %s", task.TaskID)
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("error occurred when task start prepare")
 			continue
 		}
 		// 5. start local task
-		logger.Infof("start ToProcess task of loop, taskId: %s", task.TaskID)
+		logger.WithField(logging.TaskIDKey, task.TaskID).Info("start ToProcess task of loop")
 		if err := t.MpcHandler.StartLocalMpcTask(startRequest, true); err != nil {
-			logger.WithError(err).Errorf("error occurred when execute task, and taskId: %s", task.TaskID)
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("error occurred when execute task")
 			continue
 		}
 	}
@@ -308,11 +361,10 @@ func (t *TaskMonitor) RetryProcessingTask(ctx context.Context) {
 	defer logger.Info("processing tasks retry end")
 
 	// 1. find all processing task
-	taskList, err := t.Blockchain.ListTask(&blockchain.ListFLTaskOptions{
-		ExecPubKey: t.PublicKey[:],
-		Status:     blockchain.TaskProcessing,
-		TimeStart:  0,
-		TimeEnd:    time.Now().UnixNano(),
+	taskList, err := t.listTasks(blockchain.ListFLTaskOptions{
+		Status:    blockchain.TaskProcessing,
+		TimeStart: 0,
+		TimeEnd:   time.Now().UnixNano(),
 	})
 
 	if err != nil {
@@ -332,15 +384,15 @@ func (t *TaskMonitor) RetryProcessingTask(ctx context.Context) {
 		default:
 		}
 		// 2. prepare resources before starting local MPC task
-		startRequest, err := t.MpcHandler.TaskStartPrepare(task)
+		startRequest, err := t.MpcHandler.TaskStartPrepare(context.Background(), task)
 		if err != nil {
-			logger.WithError(err).Errorf("error occurred when retry prepare task, and taskId: %s", task.TaskID)
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("error occurred when retry prepare task")
 			continue
 		}
 		// 3. start local mpc task
-		logger.Infof("retry start Processing task, taskId: %s", task.TaskID)
+		logger.WithField(logging.TaskIDKey, task.TaskID).Info("retry start Processing task")
 		if err := t.MpcHandler.StartLocalMpcTask(startRequest, true); err != nil {
-			logger.WithError(err).Errorf("error occurred when retry execute task, and taskId: %s", task.TaskID)
+			logger.WithField(logging.TaskIDKey, task.TaskID).WithError(err).Error("error occurred when retry execute task")
 			continue
 		}
 	}
@@ -365,14 +417,45 @@ func (t *TaskMonitor) updateTaskExecStatus(taskId string) error {
 	}
 	sig, err := ecdsa.Sign(t.PrivateKey, hash.HashUsingSha256([]byte(msg)))
 	if err != nil {
-		logger.WithError(err).Errorf("failed to sign exec task options, taskId: %s", taskId)
+		logger.WithField(logging.TaskIDKey, taskId).WithError(err).Error("failed to sign exec task options")
 		return err
 	}
 	execTaskOptions.Signature = sig[:]
 
 	if err := t.Blockchain.ExecuteTask(execTaskOptions); err != nil {
-		logger.WithError(err).Errorf("failed to execute task, taskID: %s", taskId)
+		logger.WithField(logging.TaskIDKey, taskId).WithError(err).Error("failed to execute task")
 		return err
 	}
 	return nil
 }
+
+// checkTaskParams checks the algorithm parameters of a task before it is confirmed,
+// so that invalid tasks are rejected instead of failing when the training starts
+func checkTaskParams(task blockchain.FLTask) error {
+	if err := blockchain.CheckTaskLabels(task.AlgoParam.GetLabels()); err != nil {
+		return err
+	}
+	if err := checkResultPublicKey(task.AlgoParam); err != nil {
+		return err
+	}
+	if op := task.AlgoParam.GetTrainParams().GetOptimizer(); op != nil && task.AlgoParam.GetTaskType() == pbCom.TaskType_LEARN {
+		if err := vl_common.CheckAlgoOptimizer(task.AlgoParam.GetAlgo(), op); err != nil {
+			return err
+		}
+	}
+	if task.AlgoParam.GetAlgo() == pbCom.Algorithm_XGBOOST_VL {
+		return xgboost.CheckTaskParams(task.AlgoParam)
+	}
+	return nil
+}
+
+// checkResultPublicKey checks the public key the prediction result is encrypted with, which only makes sense for prediction tasks
+func checkResultPublicKey(params *pbCom.TaskParams) error {
+	if len(params.GetResultPublicKey()) == 0 {
+		return nil
+	}
+	if params.GetTaskType() != pbCom.TaskType_PREDICT {
+		return errorx.New(errorx.ErrCodeParam, "resultPublicKey is only supported by predict task")
+	}
+	return envelope.CheckPublicKey(params.GetResultPublicKey())
+}
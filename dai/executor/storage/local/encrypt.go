@@ -0,0 +1,103 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// magicHeader marks the files encrypted by Storage, the files without it are read as plaintext,
+// such as the ones written before encryption is enabled
+var magicHeader = []byte("PDTXAES1")
+
+// KeySize is the size of the AES-256 key encrypting files
+const KeySize = 32
+
+// NewEncrypted initiates Storage which encrypts files with AES-GCM using key, the file's key is authenticated
+// along with its content, so that one file can not be replaced by another. The encrypted file is
+// made up of magicHeader, the nonce and the sealed content.
+func NewEncrypted(rootPath string, key []byte) (*Storage, error) {
+	if len(key) != KeySize {
+		return nil, errorx.New(errorx.ErrCodeConfig, "invalid encryption key size %d, it should be %d bytes", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to new aes cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to new gcm")
+	}
+	s, err := New(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	s.aead = aead
+	return s, nil
+}
+
+// encrypt seals the content of file key
+func (s *Storage) encrypt(key string, value io.Reader) (io.Reader, error) {
+	plaintext, err := ioutil.ReadAll(value)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read file %s", key)
+	}
+	nonce := make([]byte, s.aead.NonceSize(), len(magicHeader)+s.aead.NonceSize()+len(plaintext)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to generate nonce")
+	}
+	sealed := append(append([]byte{}, magicHeader...), nonce...)
+	sealed = s.aead.Seal(sealed, nonce, plaintext, []byte(key))
+	return bytes.NewReader(sealed), nil
+}
+
+// decrypt opens the content of file key if it is encrypted, plaintext files are returned as they are
+func (s *Storage) decrypt(key string, file io.ReadCloser) (io.ReadCloser, error) {
+	r := bufio.NewReader(file)
+	if header, _ := r.Peek(len(magicHeader)); !bytes.Equal(header, magicHeader) {
+		return &readCloser{Reader: r, Closer: file}, nil
+	}
+	defer file.Close()
+	if s.aead == nil {
+		return nil, errorx.New(errorx.ErrCodeConfig, "file %s is encrypted, but no encryption key is configured", key)
+	}
+	sealed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read file %s", key)
+	}
+	sealed = sealed[len(magicHeader):]
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, errorx.New(errorx.ErrCodeCrypto, "encrypted file %s is truncated", key)
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to decrypt file %s, the encryption key may be wrong", key)
+	}
+	return ioutil.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// readCloser reads the buffered file and closes it
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
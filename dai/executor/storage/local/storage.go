@@ -14,6 +14,8 @@
 package local
 
 import (
+	"context"
+	"crypto/cipher"
 	"io"
 	"os"
 
@@ -24,6 +26,7 @@ import (
 // Storage stores files locally
 type Storage struct {
 	localstorage.Storage
+	aead cipher.AEAD // encrypts files if it is not nil
 }
 
 // New initiates Storage
@@ -46,15 +49,45 @@ func New(rootPath string) (*Storage, error) {
 	return storage, nil
 }
 
-// Write writes target to local
-func (s *Storage) Write(value io.Reader, key string) (string, error) {
+// Upload writes target to local, the returned id is empty because the file is read by key.
+// The file is encrypted if the storage is initiated by NewEncrypted.
+func (s *Storage) Upload(ctx context.Context, key string, value io.Reader) (string, error) {
+	if s.aead != nil {
+		encrypted, err := s.encrypt(key, value)
+		if err != nil {
+			return "", err
+		}
+		value = encrypted
+	}
 	if err := s.Save(key, value); err != nil {
 		return "", err
 	}
 	return "", nil
 }
 
-// Read retrieves a target from local
-func (s *Storage) Read(key string) (io.ReadCloser, error) {
-	return s.Load(key)
+// Download retrieves a target from local, encrypted files are decrypted
+func (s *Storage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := s.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(key, file)
+}
+
+// Check checks if the root path is an accessible directory
+func (s *Storage) Check(ctx context.Context) error {
+	info, err := os.Stat(s.RootPath)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeInternal, "failed to access storage path %s", s.RootPath)
+	}
+	if !info.IsDir() {
+		return errorx.New(errorx.ErrCodeInternal, "storage path %s is not a directory", s.RootPath)
+	}
+	return nil
+}
+
+// Delete removes a target from local
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.Storage.Delete(key)
+	return err
 }
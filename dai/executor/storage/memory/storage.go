@@ -0,0 +1,69 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// Storage stores files in memory, used for testing
+type Storage struct {
+	files map[string][]byte
+	sync.RWMutex
+}
+
+// New initiates an empty memory Storage
+func New() *Storage {
+	return &Storage{files: make(map[string][]byte)}
+}
+
+// Upload stores value by key, the returned id is empty because the file is read by key
+func (s *Storage) Upload(ctx context.Context, key string, value io.Reader) (string, error) {
+	content, err := ioutil.ReadAll(value)
+	if err != nil {
+		return "", errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read file %s", key)
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.files[key] = content
+	return "", nil
+}
+
+// Download retrieves a file by key
+func (s *Storage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.RLock()
+	defer s.RUnlock()
+	content, ok := s.files[key]
+	if !ok {
+		return nil, errorx.New(errorx.ErrCodeNotFound, "file %s not found", key)
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Delete removes a file by key
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.files[key]; !ok {
+		return errorx.New(errorx.ErrCodeNotFound, "file %s not found", key)
+	}
+	delete(s.files, key)
+	return nil
+}
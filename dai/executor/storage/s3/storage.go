@@ -0,0 +1,117 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// Storage stores files in an S3-compatible object store, such as AWS S3 and MinIO
+type Storage struct {
+	Bucket string // the bucket files are stored in, must be created before storage
+	Prefix string // the key prefix, separates models, evaluations and prediction results in one bucket
+
+	client   *awss3.S3
+	uploader *s3manager.Uploader
+}
+
+// New initiates S3 Storage, files are stored with keys starting with prefix
+func New(conf *config.S3Conf, prefix string) (*Storage, error) {
+	awsConf := aws.NewConfig().
+		WithRegion(conf.Region).
+		WithS3ForcePathStyle(conf.PathStyle)
+	if conf.Endpoint != "" {
+		awsConf = awsConf.WithEndpoint(conf.Endpoint)
+	}
+	// if access key is not set, use the default credential chain, such as environment variables and IAM role
+	if conf.AccessKey != "" {
+		awsConf = awsConf.WithCredentials(credentials.NewStaticCredentials(conf.AccessKey, conf.SecretKey, ""))
+	}
+	sess, err := session.NewSession(awsConf)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to create s3 session")
+	}
+
+	return &Storage{
+		Bucket:   conf.Bucket,
+		Prefix:   prefix,
+		client:   awss3.New(sess),
+		uploader: s3manager.NewUploader(sess),
+	}, nil
+}
+
+// Upload uploads value to the bucket, the object key is the prefix joined with key,
+// the returned id is empty because the file is read by key
+func (s *Storage) Upload(ctx context.Context, key string, value io.Reader) (string, error) {
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+		Body:   value,
+	})
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to upload %s to s3 bucket %s", s.objectKey(key), s.Bucket)
+	}
+	return "", nil
+}
+
+// Download downloads a file from the bucket
+func (s *Storage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &awss3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to download %s from s3 bucket %s", s.objectKey(key), s.Bucket)
+	}
+	return out.Body, nil
+}
+
+// Delete removes a file from the bucket
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &awss3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return errorx.Wrap(err, "failed to delete %s from s3 bucket %s", s.objectKey(key), s.Bucket)
+	}
+	return nil
+}
+
+// Check checks if the bucket exists and is accessible
+func (s *Storage) Check(ctx context.Context) error {
+	_, err := s.client.HeadBucketWithContext(ctx, &awss3.HeadBucketInput{
+		Bucket: aws.String(s.Bucket),
+	})
+	if err != nil {
+		return errorx.Wrap(err, "failed to access s3 bucket %s", s.Bucket)
+	}
+	return nil
+}
+
+// objectKey returns the key of the object stored in the bucket
+func (s *Storage) objectKey(key string) string {
+	return path.Join(s.Prefix, key)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// s3Stub is an in-memory S3-compatible server with path-style addressing,
+// it supports PutObject, GetObject and DeleteObject
+type s3Stub struct {
+	lock    sync.Mutex
+	objects map[string][]byte // path-style object paths like /bucket/key
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestStorage(t *testing.T, prefix string) (*Storage, *s3Stub) {
+	stub := &s3Stub{objects: make(map[string][]byte)}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	s, err := New(&config.S3Conf{
+		Endpoint:  server.URL,
+		Bucket:    "dai",
+		Region:    "us-east-1",
+		AccessKey: "minio",
+		SecretKey: "minio123",
+		PathStyle: true,
+	}, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, stub
+}
+
+func TestStorage(t *testing.T) {
+	taskID := "f581c9ef-778f-4d15-87ae-26ba6da93b86"
+	ctx := context.Background()
+	for _, prefix := range []string{"models", "evaluations", "predictions"} {
+		t.Run(prefix, func(t *testing.T) {
+			s, stub := newTestStorage(t, prefix)
+
+			id, err := s.Upload(ctx, taskID, strings.NewReader("1,0.5\n2,0.7"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != "" {
+				t.Errorf("expected empty id, got %s", id)
+			}
+			// files are stored with keys starting with prefix
+			if _, ok := stub.objects["/dai/"+prefix+"/"+taskID]; !ok {
+				t.Errorf("object not stored with prefix %s, objects: %v", prefix, stub.objects)
+			}
+
+			r, err := s.Download(ctx, taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadAll(r)
+			r.Close()
+			if err != nil || string(content) != "1,0.5\n2,0.7" {
+				t.Errorf("unexpected content: %s, err: %v", content, err)
+			}
+
+			if err := s.Delete(ctx, taskID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Download(ctx, taskID); err == nil {
+				t.Error("deleted file is still downloadable")
+			}
+		})
+	}
+}
+
+func TestStorageErrors(t *testing.T) {
+	s, _ := newTestStorage(t, "models")
+	_, err := s.Download(context.Background(), "not-exist")
+	if err == nil {
+		t.Fatal("expected error when downloading a missing file")
+	}
+	if !strings.Contains(err.Error(), "failed to download models/not-exist from s3 bucket dai") {
+		t.Errorf("error is not wrapped with the object key: %v", err)
+	}
+	// the error of aws sdk is kept in the chain
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) || reqErr.StatusCode() != http.StatusNotFound {
+		t.Errorf("expected 404 request failure in the error chain, got: %v", err)
+	}
+}
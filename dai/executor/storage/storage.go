@@ -0,0 +1,214 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/local"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/s3"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/xuperdb"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// StorageBackend stores the files generated by tasks, such as models, evaluation results and prediction results.
+// Local, XuperDB and S3 backends are interchangeable.
+type StorageBackend interface {
+	// Upload stores value by key, and returns the id to download the file.
+	// The id is empty if the file is downloaded by key, e.g. XuperDB returns the file id and others return empty.
+	Upload(ctx context.Context, key string, value io.Reader) (string, error)
+
+	// Download retrieves a file by key or by the id returned by Upload
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes a file by key or by the id returned by Upload
+	Delete(ctx context.Context, key string) error
+}
+
+// Checker is implemented by the backends which can check if they are reachable,
+// it is called by readiness probes, so it must be cheap
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Check checks if backend is reachable, backends not implementing Checker are always reachable
+func Check(ctx context.Context, backend StorageBackend) error {
+	if checker, ok := backend.(Checker); ok {
+		return checker.Check(ctx)
+	}
+	return nil
+}
+
+// IsNotFound checks if err is returned by Download because the file does not exist
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == awss3.ErrCodeNoSuchKey {
+		return true
+	}
+	return errorx.Is(err, errorx.ErrCodeNotFound)
+}
+
+// Kinds of files stored by the executor, also used as key prefixes when files are stored in S3
+const (
+	KindModel      = "models"
+	KindEvaluation = "evaluations"
+	KindPrediction = "predictions"
+	KindCheckpoint = "checkpoints"
+)
+
+// NewStorageBackend returns the backend of files of kind, selected by conf.Type.
+// If conf.Type is 'S3', all files are stored in the same bucket and separated by key prefixes.
+// Otherwise models, evaluation results and checkpoints are stored locally,
+// and prediction results are stored in local path or in XuperDB.
+// Locally stored files are encrypted if conf.EncryptionKey is set.
+func NewStorageBackend(conf *config.ExecutorStorageConf, kind string) (StorageBackend, error) {
+	switch conf.Type {
+	case "Local", "XuperDB":
+	case "S3":
+		return s3.New(conf.S3, kind)
+	default:
+		return nil, errorx.New(errorx.ErrCodeConfig, "invalid storage type: %s", conf.Type)
+	}
+
+	switch kind {
+	case KindModel:
+		s, err := newLocal(conf.LocalModelStoragePath, conf)
+		if err != nil {
+			return nil, errorx.New(errorx.ErrCodeConfig, "invalid model storage path：%s", err)
+		}
+		return s, nil
+	case KindEvaluation:
+		s, err := newLocal(conf.LocalEvaluationStoragePath, conf)
+		if err != nil {
+			return nil, errorx.New(errorx.ErrCodeConfig, "invalid evaluation result storage path：%s", err)
+		}
+		return s, nil
+	case KindCheckpoint:
+		s, err := newLocal(checkpointStoragePath(conf), conf)
+		if err != nil {
+			return nil, errorx.New(errorx.ErrCodeConfig, "invalid checkpoint storage path：%s", err)
+		}
+		return s, nil
+	case KindPrediction:
+		if conf.Type == "XuperDB" {
+			return newXuperDB(conf.XuperDB)
+		}
+		s, err := newLocal(conf.Local.LocalPredictStoragePath, conf)
+		if err != nil {
+			return nil, errorx.New(errorx.ErrCodeConfig, "invalid prediction result-path：%s", err)
+		}
+		return s, nil
+	default:
+		return nil, errorx.New(errorx.ErrCodeInternal, "unknown kind of files: %s", kind)
+	}
+}
+
+// newLocal returns the local backend at rootPath, files are encrypted if the encryption key is configured
+func newLocal(rootPath string, conf *config.ExecutorStorageConf) (*local.Storage, error) {
+	if conf.EncryptionKey == "" {
+		return local.New(rootPath)
+	}
+	key, err := hex.DecodeString(conf.EncryptionKey)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "the encryption key should be hex encoded")
+	}
+	return local.NewEncrypted(rootPath, key)
+}
+
+// checkpointStoragePath returns the local path of checkpoints,
+// the default is the directory 'checkpoints' under the model storage path
+func checkpointStoragePath(conf *config.ExecutorStorageConf) string {
+	if conf.LocalCheckpointStoragePath != "" {
+		return conf.LocalCheckpointStoragePath
+	}
+	return filepath.Join(conf.LocalModelStoragePath, KindCheckpoint)
+}
+
+// NameTemplates returns the templates of the names of files by their kinds, the kinds without templates are omitted
+func NameTemplates(conf *config.ExecutorStorageConf) (map[string]*file.NameTemplate, error) {
+	templates := make(map[string]*file.NameTemplate)
+	if conf.FileNames == nil {
+		return templates, nil
+	}
+	texts := map[string]string{
+		KindModel:      conf.FileNames.Model,
+		KindEvaluation: conf.FileNames.Evaluation,
+		KindCheckpoint: conf.FileNames.Checkpoint,
+		KindPrediction: conf.FileNames.Prediction,
+	}
+	for kind, text := range texts {
+		if text == "" {
+			continue
+		}
+		t, err := file.ParseNameTemplate(text, FileType(kind))
+		if err != nil {
+			return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "invalid template of %s names", FileType(kind))
+		}
+		templates[kind] = t
+	}
+	return templates, nil
+}
+
+// FileType returns the type of the files of kind in file names, such as "model" of KindModel
+func FileType(kind string) string {
+	return strings.TrimSuffix(kind, "s")
+}
+
+// RetentionDirs returns the local directories of the kinds of files removed by the retention policy,
+// that is checkpoints and prediction results stored locally, models and evaluation results are always kept
+func RetentionDirs(conf *config.ExecutorStorageConf) map[string]string {
+	dirs := make(map[string]string)
+	if conf.Type == "S3" {
+		return dirs
+	}
+	dirs[KindCheckpoint] = checkpointStoragePath(conf)
+	if conf.Type == "Local" {
+		dirs[KindPrediction] = conf.Local.LocalPredictStoragePath
+	}
+	return dirs
+}
+
+// newXuperDB returns XuperDB backend, if conf.PrivateKey is empty, get the dataOwner client privateKey from conf.KeyPath
+func newXuperDB(conf *config.XuperDBConf) (StorageBackend, error) {
+	if conf.PrivateKey == "" {
+		privateKeyBytes, err := file.ReadFile(conf.KeyPath, file.PrivateKeyFileName)
+		if err == nil && len(privateKeyBytes) != 0 {
+			conf.PrivateKey = strings.TrimSpace(string(privateKeyBytes))
+		} else {
+			return nil, errorx.New(errorx.ErrCodeConfig, "invalid xuperdb privateKey-path：%s", err)
+		}
+	}
+	privateKey, err := ecdsa.DecodePrivateKeyFromString(conf.PrivateKey)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to decode xuperdb private key")
+	}
+	// get XuperDB instance to upload and download files
+	x := xuperdb.New(conf.ExpireTime, conf.NameSpace, conf.Host, privateKey)
+	x.ChunkSize = int64(conf.ChunkSizeMB) << 20
+	return x, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/executor/storage/memory"
+)
+
+func TestStorageBackend(t *testing.T) {
+	localBackend, err := NewStorageBackend(&config.ExecutorStorageConf{
+		Type:  "Local",
+		Local: &config.PredictLocalConf{LocalPredictStoragePath: filepath.Join(t.TempDir(), "predictions")},
+	}, KindPrediction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backends := map[string]StorageBackend{
+		"Local":  localBackend,
+		"Memory": memory.New(),
+	}
+
+	// local storage only accepts uuid keys, which is the format of task id
+	taskID := "f581c9ef-778f-4d15-87ae-26ba6da93b86"
+	ctx := context.Background()
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			if _, err := backend.Upload(ctx, taskID, strings.NewReader("1,0.5\n2,0.7")); err != nil {
+				t.Fatal(err)
+			}
+			r, err := backend.Download(ctx, taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadAll(r)
+			r.Close()
+			if err != nil || string(content) != "1,0.5\n2,0.7" {
+				t.Errorf("unexpected content: %s, err: %v", content, err)
+			}
+			if err := backend.Delete(ctx, taskID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := backend.Download(ctx, taskID); err == nil {
+				t.Error("deleted file is still downloadable")
+			}
+		})
+	}
+}
+
+func TestEncryptedLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	conf := &config.ExecutorStorageConf{
+		Type:                  "Local",
+		LocalModelStoragePath: dir,
+		EncryptionKey:         strings.Repeat("ab", 32),
+	}
+	encrypted, err := NewStorageBackend(conf, KindModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := NewStorageBackend(&config.ExecutorStorageConf{Type: "Local", LocalModelStoragePath: dir}, KindModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(backend StorageBackend, key string) (string, error) {
+		r, err := backend.Download(context.Background(), key)
+		if err != nil {
+			return "", err
+		}
+		defer r.Close()
+		content, err := ioutil.ReadAll(r)
+		return string(content), err
+	}
+
+	ctx := context.Background()
+	newFile, oldFile := "f581c9ef-778f-4d15-87ae-26ba6da93b86", "0b1d6b9c-0e53-4d0f-9c8e-3a4b5f6a7b8c"
+	if _, err := encrypted.Upload(ctx, newFile, strings.NewReader("model params")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := ioutil.ReadFile(filepath.Join(dir, newFile)); err != nil || strings.Contains(string(content), "model params") {
+		t.Errorf("file stored in plaintext or not stored, err: %v", err)
+	}
+	if content, err := read(encrypted, newFile); err != nil || content != "model params" {
+		t.Errorf("unexpected content: %s, err: %v", content, err)
+	}
+	// files written before encryption is enabled are still readable
+	if _, err := plain.Upload(ctx, oldFile, strings.NewReader("old model")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := read(encrypted, oldFile); err != nil || content != "old model" {
+		t.Errorf("unexpected content of plaintext file: %s, err: %v", content, err)
+	}
+
+	if _, err := read(plain, newFile); err == nil {
+		t.Error("encrypted file read without the key")
+	}
+	conf.EncryptionKey = strings.Repeat("cd", 32)
+	wrongKey, err := NewStorageBackend(conf, KindModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := read(wrongKey, newFile); err == nil {
+		t.Error("encrypted file read with a wrong key")
+	}
+	for _, key := range []string{"not hex", strings.Repeat("ab", 16)} {
+		conf.EncryptionKey = key
+		if _, err := NewStorageBackend(conf, KindModel); err == nil {
+			t.Errorf("invalid encryption key %q accepted", key)
+		}
+	}
+}
+
+func TestNewStorageBackendKinds(t *testing.T) {
+	dir := t.TempDir()
+	conf := &config.ExecutorStorageConf{
+		Type:                       "Local",
+		LocalModelStoragePath:      filepath.Join(dir, "models"),
+		LocalEvaluationStoragePath: filepath.Join(dir, "evalus"),
+		Local:                      &config.PredictLocalConf{LocalPredictStoragePath: filepath.Join(dir, "predictions")},
+	}
+	// each kind of files is stored in its own path
+	taskID := "f581c9ef-778f-4d15-87ae-26ba6da93b86"
+	for kind, path := range map[string]string{
+		KindModel:      conf.LocalModelStoragePath,
+		KindEvaluation: conf.LocalEvaluationStoragePath,
+		KindPrediction: conf.Local.LocalPredictStoragePath,
+	} {
+		backend, err := NewStorageBackend(conf, kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := backend.Upload(context.Background(), taskID, strings.NewReader(kind)); err != nil {
+			t.Fatal(err)
+		}
+		if content, err := ioutil.ReadFile(filepath.Join(path, taskID)); err != nil || string(content) != kind {
+			t.Errorf("%s not stored in %s, content: %s, err: %v", kind, path, content, err)
+		}
+	}
+
+	// checkpoints are stored under the model storage path by default
+	backend, err := NewStorageBackend(conf, KindCheckpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backend.Upload(context.Background(), taskID, strings.NewReader(KindCheckpoint)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(conf.LocalModelStoragePath, KindCheckpoint, taskID)); err != nil {
+		t.Errorf("checkpoint not stored under model storage path: %v", err)
+	}
+	_, err = backend.Download(context.Background(), "0f8fad5b-d9cb-469f-a165-70867728950e")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+
+	if _, err := NewStorageBackend(conf, "logs"); err == nil {
+		t.Error("unknown kind of files accepted")
+	}
+}
+
+func TestNewStorageBackendInvalidType(t *testing.T) {
+	if _, err := NewStorageBackend(&config.ExecutorStorageConf{Type: "IPFS"}, KindModel); err == nil {
+		t.Error("invalid storage type accepted")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "models")
+	backend, err := NewStorageBackend(&config.ExecutorStorageConf{Type: "Local", LocalModelStoragePath: root}, KindModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := Check(ctx, backend); err != nil {
+		t.Errorf("expected local storage reachable, got: %v", err)
+	}
+	if err := Check(ctx, memory.New()); err != nil {
+		t.Errorf("expected backend without Checker reachable, got: %v", err)
+	}
+
+	// the mounted directory is removed
+	if err := os.Remove(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(ctx, backend); err == nil {
+		t.Error("expected error when storage path is removed")
+	}
+}
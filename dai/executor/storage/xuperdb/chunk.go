@@ -0,0 +1,221 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xuperdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	httpclient "github.com/PaddlePaddle/PaddleDTX/xdb/client/http"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/checksum"
+)
+
+var (
+	logger = logrus.WithField("module", "storage.xuperdb")
+
+	// chunkRetryInterval is how much the interval between retries of uploading a chunk increases each time
+	chunkRetryInterval = time.Second
+)
+
+// chunkUploadRetries is the max number of retries to upload a chunk
+const chunkUploadRetries = 3
+
+// chunk is a part of a file uploaded as a separate file
+type chunk struct {
+	ID       string
+	Checksum string
+}
+
+// marshalManifest returns the content of the file uploaded in chunks, it lists the chunks in order,
+// one '<id> <checksum>' per line. It is not JSON, as XuperDB clients take JSON objects downloaded as errors.
+func marshalManifest(chunks []chunk) []byte {
+	var buf bytes.Buffer
+	for _, c := range chunks {
+		fmt.Fprintf(&buf, "%s %s\n", c.ID, c.Checksum)
+	}
+	return buf.Bytes()
+}
+
+// unmarshalManifest parses the chunks listed by marshalManifest
+func unmarshalManifest(manifest []byte) ([]chunk, error) {
+	var chunks []chunk
+	for _, line := range strings.Split(strings.TrimSpace(string(manifest)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errorx.New(errorx.ErrCodeEncoding, "invalid chunk '%s'", line)
+		}
+		chunks = append(chunks, chunk{ID: fields[0], Checksum: fields[1]})
+	}
+	return chunks, nil
+}
+
+// uploadChunks uploads the file of size bytes in chunks of x.ChunkSize, then uploads the manifest listing them
+// as fileName. Each chunk is retried on failure, and chunks uploaded by a failed upload are kept in memory,
+// so uploading the same file with the same name again resumes from the last successful chunk.
+func (x *XuperDB) uploadChunks(ctx context.Context, client httpclient.Client, fileName string, r io.Reader,
+	size int64, sum string) (string, error) {
+
+	key := fileName + " " + sum
+	x.lock.Lock()
+	uploaded := x.uploaded[key]
+	x.lock.Unlock()
+
+	var chunks []chunk
+	content := make([]byte, x.ChunkSize)
+	for i, offset := 0, int64(0); offset < size; i, offset = i+1, offset+x.ChunkSize {
+		n := x.ChunkSize
+		if size-offset < n {
+			n = size - offset
+		}
+		if _, err := io.ReadFull(r, content[:n]); err != nil {
+			return "", errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read chunk %d of %s", i, fileName)
+		}
+		if i < len(uploaded) {
+			chunks = append(chunks, uploaded[i])
+			continue
+		}
+		chunkSum, err := checksum.Sum(bytes.NewReader(content[:n]))
+		if err != nil {
+			return "", err
+		}
+		id, err := x.writeWithRetry(ctx, client, fmt.Sprintf("%s.part%d", fileName, i), content[:n],
+			fileExtra{Checksum: chunkSum})
+		if err != nil {
+			return "", errorx.Wrap(err, "failed to upload chunk %d of %s, upload it again to resume", i, fileName)
+		}
+		chunks = append(chunks, chunk{ID: id, Checksum: chunkSum})
+		x.lock.Lock()
+		x.uploaded[key] = append([]chunk(nil), chunks...)
+		x.lock.Unlock()
+	}
+
+	id, err := x.writeWithRetry(ctx, client, fileName, marshalManifest(chunks), fileExtra{Checksum: sum, Chunked: true})
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to upload the manifest of %s, upload it again to resume", fileName)
+	}
+	x.lock.Lock()
+	delete(x.uploaded, key)
+	x.lock.Unlock()
+	return id, nil
+}
+
+// writeWithRetry uploads content as fileName, and retries at most chunkUploadRetries times on failure.
+// If a previous attempt was stored although its response was lost, the stored file is returned.
+func (x *XuperDB) writeWithRetry(ctx context.Context, client httpclient.Client, fileName string, content []byte,
+	extra fileExtra) (string, error) {
+
+	var err error
+	for attempt := 0; attempt <= chunkUploadRetries; attempt++ {
+		if attempt > 0 {
+			logger.WithError(err).Warnf("failed to upload %s, retry %d", fileName, attempt)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt) * chunkRetryInterval):
+			}
+		}
+		var id string
+		if id, err = x.write(ctx, client, fileName, bytes.NewReader(content), extra); err == nil {
+			return id, nil
+		}
+		if errorx.Is(err, errorx.ErrCodeAlreadyExists) {
+			return x.existingFile(ctx, client, fileName, extra.Checksum)
+		}
+	}
+	return "", err
+}
+
+// existingFile returns the id of the file named fileName uploaded by the executor,
+// it must have the same checksum, otherwise it is another file
+func (x *XuperDB) existingFile(ctx context.Context, client httpclient.Client, fileName, sum string) (string, error) {
+	owner := ecdsa.PublicKeyFromPrivateKey(x.PrivateKey).String()
+	file, err := client.GetFileByName(ctx, owner, x.Ns, fileName)
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to get the existing file %s", fileName)
+	}
+	var extra fileExtra
+	if err := json.Unmarshal(file.File.Ext, &extra); err != nil || extra.Checksum != sum {
+		return "", errorx.New(errorx.ErrCodeAlreadyExists, "file %s already exists with different content", fileName)
+	}
+	return file.File.ID, nil
+}
+
+// openChunks reads the manifest and returns the reader of the chunks in order, each chunk is verified
+func (x *XuperDB) openChunks(ctx context.Context, fileID string, manifestReader io.ReadCloser) (io.ReadCloser, error) {
+	defer manifestReader.Close()
+	bs, err := ioutil.ReadAll(manifestReader)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read the manifest of %s", fileID)
+	}
+	chunks, err := unmarshalManifest(bs)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to parse the manifest of %s", fileID)
+	}
+	return &chunkReader{ctx: ctx, x: x, chunks: chunks}, nil
+}
+
+// chunkReader downloads chunks one by one while it is read
+type chunkReader struct {
+	ctx    context.Context
+	x      *XuperDB
+	chunks []chunk // chunks not opened yet
+	cur    io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			c := r.chunks[0]
+			r.chunks = r.chunks[1:]
+			reader, err := r.x.download(r.ctx, c.ID)
+			if err != nil {
+				return 0, errorx.Wrap(err, "failed to download chunk %s", c.ID)
+			}
+			if r.cur, err = checksum.NewReader(reader, c.Checksum, c.ID); err != nil {
+				reader.Close()
+				return 0, err
+			}
+		}
+		n, err := r.cur.Read(p)
+		if err == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
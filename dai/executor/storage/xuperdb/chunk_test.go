@@ -0,0 +1,173 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xuperdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/checksum"
+)
+
+// fakeXuperDB serves the file APIs of a dataOwner node in memory,
+// writes of the names in failures fail the given times, reads are projected if project is true,
+// without the checksum of the projected file if noChecksum is true
+type fakeXuperDB struct {
+	lock       sync.Mutex
+	files      map[string]xdbchain.File
+	contents   map[string][]byte
+	failures   map[string]int
+	writes     int
+	project    bool
+	noChecksum bool
+}
+
+func (f *fakeXuperDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	q := r.URL.Query()
+	reply := func(data interface{}) {
+		bs, _ := json.Marshal(data)
+		fmt.Fprintf(w, `{"code":"0","message":"","data":%s}`, bs)
+	}
+	switch r.URL.Path {
+	case "/v1/file/write":
+		name := q.Get("name")
+		if f.failures[name] > 0 {
+			f.failures[name]--
+			w.Write([]byte(`{"code":"10001","message":"connection reset"}`))
+			return
+		}
+		for _, file := range f.files {
+			if file.Name == name {
+				w.Write([]byte(`{"code":"10007","message":"duplicated name"}`))
+				return
+			}
+		}
+		content, _ := ioutil.ReadAll(r.Body)
+		f.writes++
+		id := fmt.Sprintf("file-%d", f.writes)
+		f.files[id] = xdbchain.File{ID: id, Name: name, Namespace: q.Get("ns"), Ext: []byte(q.Get("ext"))}
+		f.contents[id] = content
+		reply(map[string]string{"file_id": id})
+	case "/v1/file/read":
+		content := f.contents[q.Get("file_id")]
+		if columns := q.Get("columns"); f.project && columns != "" {
+			projected, err := csv.SelectColumns(content, strings.Split(columns, ","))
+			if err != nil {
+				w.Write([]byte(`{"code":"10001","message":"unknown columns"}`))
+				return
+			}
+			sum, _ := checksum.Sum(bytes.NewReader(projected))
+			w.Header().Set(ProjectedColumnsHeader, columns)
+			if !f.noChecksum {
+				w.Header().Set(ProjectedChecksumHeader, sum)
+			}
+			content = projected
+		}
+		w.Write(content)
+	case "/v1/file/getbyid":
+		reply(xdbchain.FileH{File: f.files[q.Get("id")]})
+	case "/v1/file/getbyname":
+		for _, file := range f.files {
+			if file.Name == q.Get("name") {
+				reply(xdbchain.FileH{File: file})
+				return
+			}
+		}
+		w.Write([]byte(`{"code":"10004","message":"file not found"}`))
+	}
+}
+
+func TestChunkedUpload(t *testing.T) {
+	chunkRetryInterval = time.Millisecond
+	defer func() { chunkRetryInterval = time.Second }()
+	fake := &fakeXuperDB{
+		files:    make(map[string]xdbchain.File),
+		contents: make(map[string][]byte),
+		// the second chunk fails once and is retried, the third one fails until the upload gives up
+		failures: map[string]int{"task1.csv.part1": 1, "task1.csv.part2": chunkUploadRetries + 1},
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	privateKey, _, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := New(1, "mpc", srv.URL, privateKey)
+	x.ChunkSize = 10
+	content := []byte(strings.Repeat("id,label\n1,0\n", 3)) // 39 bytes, 4 chunks
+	ctx := context.Background()
+
+	if _, err := x.Upload(ctx, "task1", bytes.NewReader(content)); err == nil {
+		t.Fatal("expected upload fails on the third chunk")
+	}
+	if fake.writes != 2 {
+		t.Fatalf("expected 2 chunks uploaded before failure, got %d", fake.writes)
+	}
+	// resume from the third chunk
+	fileID, err := x.Upload(ctx, "task1", bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.writes != 5 {
+		t.Errorf("expected the remaining 2 chunks and the manifest uploaded, got %d writes", fake.writes)
+	}
+
+	r, err := x.Download(ctx, fileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("expected the chunks downloaded as the file, got %q, err: %v", got, err)
+	}
+
+	// files not larger than the chunk size are uploaded as a whole
+	smallID, err := x.Upload(ctx, "task2", strings.NewReader("id,label\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(fake.files[smallID].Ext), "chunked") {
+		t.Error("expected the small file not chunked")
+	}
+
+	// a tampered chunk fails the download
+	fake.contents["file-2"][0] ^= 1
+	r, err = x.Download(ctx, fileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ioutil.ReadAll(r)
+	r.Close()
+	if err == nil || !errorx.Is(err, errcodes.ErrCodeIntegrity) {
+		t.Errorf("expected integrity error, got: %v", err)
+	}
+}
@@ -14,17 +14,42 @@
 package xuperdb
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
 	httpclient "github.com/PaddlePaddle/PaddleDTX/xdb/client/http"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/checksum"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/httputil"
 )
 
 // Maximum default time for saving predict file results
 const DefaultFileRetentionTime = time.Hour * 72
 
+// Headers of the response of dataOwner nodes supporting column projection, see DownloadColumns
+const (
+	// ProjectedColumnsHeader lists the columns of the projected file returned, separated by ','
+	ProjectedColumnsHeader = "X-Projected-Columns"
+	// ProjectedChecksumHeader is the SHA-256 checksum of the projected file, projected files without it are discarded
+	ProjectedChecksumHeader = "X-Projected-Checksum"
+)
+
 // XuperDB defines xuperdb client
 // Only the prediction files supports storing to xuperdb
 type XuperDB struct {
@@ -32,6 +57,17 @@ type XuperDB struct {
 	Address    string           // the dataOwner node host
 	Ns         string           // it defines which namespace in XuperDB prediction file will be stored
 	ExpireTime int64            // the expiration time of the files stored in XuperDB
+	Client     *http.Client     // downloads files over mutual TLS if it is set, see tlsutil.HTTPClient
+	ChunkSize  int64            // files larger than ChunkSize bytes are uploaded in chunks, 0 means no chunking
+
+	lock     sync.Mutex
+	uploaded map[string][]chunk // chunks uploaded by failed uploads, used to resume, key is the name and checksum
+}
+
+// fileExtra is the extra information of the files uploaded by the executor
+type fileExtra struct {
+	Checksum string `json:"checksum"`          // checksum of the whole file
+	Chunked  bool   `json:"chunked,omitempty"` // whether the file is the manifest of chunks
 }
 
 // New initiates xuperDB Storage
@@ -45,37 +81,130 @@ func New(expireTime int64, ns, host string, privateKey ecdsa.PrivateKey) *XuperD
 		Address:    host,
 		Ns:         ns,
 		ExpireTime: time.Now().UnixNano() + expiretime.Nanoseconds(),
+		uploaded:   make(map[string][]chunk),
 	}
 }
 
-// Write stores files in xuperDB, name is prediction task's ID
+// Upload stores files in xuperDB, name is prediction task's ID
 // return the id of the file stored in xuperdb
-func (x *XuperDB) Write(r io.Reader, name string) (string, error) {
+// The SHA-256 checksum of the file is recorded in the extra information, and verified by Download.
+// Files larger than ChunkSize are uploaded in chunks, see uploadChunks.
+func (x *XuperDB) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
 	// new xuperdb http client
 	client, err := httpclient.New(x.Address)
 	if err != nil {
 		return "", err
 	}
+	rs, sum, size, err := sumReader(r)
+	if err != nil {
+		return "", err
+	}
 	// FileName is prediction task's ID, e.g. 'f581c9ef-778f-4d15-87ae-26ba6da93b86.csv'
+	if x.ChunkSize > 0 && size > x.ChunkSize {
+		return x.uploadChunks(ctx, client, name+".csv", rs, size, sum)
+	}
+	return x.write(ctx, client, name+".csv", rs, fileExtra{Checksum: sum})
+}
+
+// write requests the dataOwner node to upload the file
+func (x *XuperDB) write(ctx context.Context, client httpclient.Client, fileName string, r io.Reader,
+	extra fileExtra) (string, error) {
+
+	ext, _ := json.Marshal(&extra)
 	// Description default setting "store samples"
 	opt := httpclient.WriteOptions{
 		PrivateKey: x.PrivateKey.String(),
 
 		Namespace:   x.Ns,
-		FileName:    name + ".csv",
+		FileName:    fileName,
 		ExpireTime:  x.ExpireTime,
 		Description: "store samples",
+		Extra:       string(ext),
 	}
 	// request the dataOwner node to upload prediction file
-	resp, err := client.Write(context.Background(), r, opt)
+	resp, err := client.Write(ctx, r, opt)
 	if err != nil {
 		return "", err
 	}
 	return resp.FileID, nil
 }
 
-// Read gets files from xuperDB
-func (x *XuperDB) Read(fileID string) (io.ReadCloser, error) {
+// Download gets files from xuperDB, the file is verified while it is read
+// if its extra information records the checksum, see blockchain.FLInfo.
+// Files uploaded in chunks are downloaded chunk by chunk transparently.
+func (x *XuperDB) Download(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	extra, err := x.extra(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := x.download(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if extra.Chunked {
+		if reader, err = x.openChunks(ctx, fileID, reader); err != nil {
+			return nil, err
+		}
+	}
+	if extra.Checksum == "" {
+		return reader, nil
+	}
+	verified, err := checksum.NewReader(reader, extra.Checksum, fileID)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return verified, nil
+}
+
+// DownloadColumns requests the dataOwner node to return only columns of the sample file, the same as
+// Download otherwise. The columns are sent as the query parameter 'columns', a node supporting column
+// projection returns the projected file with ProjectedColumnsHeader set to the columns requested,
+// projected is false if the node ignores it, or the file is uploaded in chunks.
+// The projected file is verified by ProjectedChecksumHeader, as the checksum recorded in the extra information
+// is the one of the whole file. If the node doesn't set it, the projected file can't be verified, so the whole
+// file is downloaded instead and projected is false, leaving the projection to the caller.
+func (x *XuperDB) DownloadColumns(ctx context.Context, fileID string, columns []string) (
+	r io.ReadCloser, projected bool, err error) {
+
+	extra, err := x.extra(ctx, fileID)
+	if err != nil {
+		return nil, false, err
+	}
+	if extra.Chunked || len(columns) == 0 {
+		r, err := x.Download(ctx, fileID)
+		return r, false, err
+	}
+	want := strings.Join(columns, ",")
+	reader, header, err := x.read(ctx, fileID, url.Values{"columns": []string{want}})
+	if err != nil {
+		return nil, false, err
+	}
+	sum := extra.Checksum
+	if projected = header.Get(ProjectedColumnsHeader) == want; projected {
+		if sum = header.Get(ProjectedChecksumHeader); sum == "" {
+			reader.Close()
+			r, err := x.Download(ctx, fileID)
+			return r, false, err
+		}
+	}
+	if sum == "" {
+		return reader, projected, nil
+	}
+	verified, err := checksum.NewReader(reader, sum, fileID)
+	if err != nil {
+		reader.Close()
+		return nil, false, err
+	}
+	return verified, projected, nil
+}
+
+// download requests the dataOwner node to download the file
+func (x *XuperDB) download(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	if x.Client != nil {
+		reader, _, err := x.read(ctx, fileID, nil)
+		return reader, err
+	}
 	client, err := httpclient.New(x.Address)
 	if err != nil {
 		return nil, err
@@ -86,9 +215,153 @@ func (x *XuperDB) Read(fileID string) (io.ReadCloser, error) {
 		FileID:     fileID,
 	}
 	// request the dataOwner node to download prediction file
-	reader, err := client.Read(context.Background(), opt)
+	reader, err := client.Read(ctx, opt)
 	if err != nil {
 		return nil, err
 	}
 	return reader, nil
 }
+
+// Check lists storage nodes from the dataOwner node, which is read-only
+func (x *XuperDB) Check(ctx context.Context) error {
+	client, err := httpclient.New(x.Address)
+	if err != nil {
+		return errorx.Wrap(err, "failed to create xuperdb client of %s", x.Address)
+	}
+	if _, err := client.ListNodes(ctx); err != nil {
+		return errorx.Wrap(err, "failed to reach xuperdb %s", x.Address)
+	}
+	return nil
+}
+
+// Delete is not supported by xuperDB, files are removed after they expire
+func (x *XuperDB) Delete(ctx context.Context, fileID string) error {
+	return errorx.New(errcodes.ErrCodeNotSupported, "xuperdb does not support deleting files, file %s is removed after it expires", fileID)
+}
+
+// extra returns the extra information of the file, the checksum is empty if the file has none.
+// The extra information of sample files is blockchain.FLInfo, whose checksum is parsed the same way.
+func (x *XuperDB) extra(ctx context.Context, fileID string) (fileExtra, error) {
+	file, err := x.getFile(ctx, fileID)
+	if err != nil {
+		return fileExtra{}, errorx.Wrap(err, "failed to get file %s from xuperdb", fileID)
+	}
+	var extra fileExtra
+	if err := json.Unmarshal(file.Ext, &extra); err != nil {
+		return fileExtra{}, nil
+	}
+	return extra, nil
+}
+
+// FileSize returns the size of the file recorded by the dataOwner node, known is false if the file is uploaded
+// in chunks, as the recorded size is the one of the manifest
+func (x *XuperDB) FileSize(ctx context.Context, fileID string) (size uint64, known bool, err error) {
+	file, err := x.getFile(ctx, fileID)
+	if err != nil {
+		return 0, false, errorx.Wrap(err, "failed to get file %s from xuperdb", fileID)
+	}
+	var extra fileExtra
+	if err := json.Unmarshal(file.Ext, &extra); err == nil && extra.Chunked {
+		return 0, false, nil
+	}
+	return file.Length, true, nil
+}
+
+// getFile gets the file info from the dataOwner node, using x.Client if it is set
+func (x *XuperDB) getFile(ctx context.Context, fileID string) (xdbchain.File, error) {
+	if x.Client == nil {
+		client, err := httpclient.New(x.Address)
+		if err != nil {
+			return xdbchain.File{}, err
+		}
+		file, err := client.GetFileByID(ctx, fileID)
+		return file.File, err
+	}
+
+	u, err := url.Parse(x.Address)
+	if err != nil {
+		return xdbchain.File{}, errorx.NewCode(err, errorx.ErrCodeParam, "invalid addr")
+	}
+	u.Path = path.Join(u.Path, "v1", "file", "getbyid")
+	u.RawQuery = url.Values{"id": []string{fileID}}.Encode()
+	body, err := httputil.Get(ctx, x.Client, u.String())
+	if err != nil {
+		return xdbchain.File{}, err
+	}
+	defer body.Close()
+	var resp struct {
+		Data xdbchain.FileH `json:"data"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return xdbchain.File{}, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to decode file %s", fileID)
+	}
+	return resp.Data.File, nil
+}
+
+// sumReader returns the checksum and size of r, and a reader of the same content at its start,
+// r is read twice if it is seekable, otherwise it is buffered
+func sumReader(r io.Reader) (io.ReadSeeker, string, int64, error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if start, err := rs.Seek(0, io.SeekCurrent); err == nil {
+			sum, err := checksum.Sum(rs)
+			if err != nil {
+				return nil, "", 0, err
+			}
+			end, err := rs.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, "", 0, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to seek file")
+			}
+			if _, err := rs.Seek(start, io.SeekStart); err != nil {
+				return nil, "", 0, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to seek file")
+			}
+			return rs, sum, end - start, nil
+		}
+	}
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, "", 0, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read file")
+	}
+	sum, err := checksum.Sum(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return bytes.NewReader(content), sum, int64(len(content)), nil
+}
+
+// read requests the dataOwner node to download the file using x.Client,
+// the request is signed the same as the XuperDB http client does, query is added to the unsigned parameters
+func (x *XuperDB) read(ctx context.Context, fileID string, query url.Values) (io.ReadCloser, http.Header, error) {
+	reqParams := map[string]string{
+		"user":      ecdsa.PublicKeyFromPrivateKey(x.PrivateKey).String(),
+		"ns":        "",
+		"name":      "",
+		"file_id":   fileID,
+		"timestamp": strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+	msg, err := util.GetSigMessage(reqParams)
+	if err != nil {
+		return nil, nil, errorx.Internal(err, "failed to get the message to sign")
+	}
+	sig, err := ecdsa.Sign(x.PrivateKey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return nil, nil, errorx.Wrap(err, "failed to sign")
+	}
+	reqParams["token"] = sig.String()
+
+	u, err := url.Parse(x.Address)
+	if err != nil {
+		return nil, nil, errorx.NewCode(err, errorx.ErrCodeParam, "invalid addr")
+	}
+	u.Path = path.Join(u.Path, "v1", "file", "read")
+	q := u.Query()
+	for k, v := range reqParams {
+		q.Add(k, v)
+	}
+	for k, vs := range query {
+		for _, v := range vs {
+			q.Add(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return httputil.GetWithHeader(ctx, x.Client, u.String())
+}
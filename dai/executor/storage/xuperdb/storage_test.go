@@ -0,0 +1,77 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xuperdb
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
+)
+
+func TestDownloadColumns(t *testing.T) {
+	fake := &fakeXuperDB{
+		files:    make(map[string]xdbchain.File),
+		contents: make(map[string][]byte),
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	privateKey, _, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := New(1, "mpc", srv.URL, privateKey)
+	ctx := context.Background()
+	content := []byte("id,age,income,label\n1,20,100,0\n2,30,200,1\n")
+	fileID, err := x.Upload(ctx, "samples", bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		project    bool
+		noChecksum bool
+		columns    []string
+		projected  bool
+		expected   string
+	}{
+		{"pushdown", true, false, []string{"id", "income"}, true, "id,income\n1,100\n2,200\n"},
+		{"notSupported", false, false, []string{"id", "income"}, false, string(content)},
+		{"noColumns", true, false, nil, false, string(content)},
+		// the projected file can't be verified without its checksum, so the whole file is downloaded
+		{"noChecksum", true, true, []string{"id", "income"}, false, string(content)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.project, fake.noChecksum = tt.project, tt.noChecksum
+			r, projected, err := x.DownloadColumns(ctx, fileID, tt.columns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if projected != tt.projected || string(got) != tt.expected {
+				t.Errorf("expected %q projected: %v, got %q projected: %v", tt.expected, tt.projected, got, projected)
+			}
+		})
+	}
+}
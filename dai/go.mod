@@ -5,26 +5,37 @@ go 1.16
 require (
 	github.com/PaddlePaddle/PaddleDTX/crypto v0.0.0-20230817074501-597f03fd9f80
 	github.com/PaddlePaddle/PaddleDTX/xdb v0.0.0-20230419070454-b5afd37bf0bb
+	github.com/aws/aws-sdk-go v1.29.2
 	github.com/cjqpker/slidewindow v1.0.2
 	github.com/docker/docker v1.4.2-0.20191101170500-ac7306503d23
 	github.com/docker/go-connections v0.4.1-0.20180821093606-97c2040d34df
 	github.com/docker/go-metrics v0.0.1 // indirect
+	github.com/fsnotify/fsnotify v1.4.9
 	github.com/golang/glog v1.0.0 // indirect
 	github.com/golang/protobuf v1.5.2
+	github.com/golang/snappy v0.0.4
 	github.com/google/uuid v1.2.0
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0
 	github.com/hyperledger/fabric v1.4.4
 	github.com/hyperledger/fabric-sdk-go v1.0.0-beta1
-	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible
+	github.com/mitchellh/mapstructure v1.1.2
+	github.com/pelletier/go-toml v1.2.0
+	github.com/prometheus/client_golang v1.1.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/cobra v1.1.3
 	github.com/spf13/viper v1.7.1
+	github.com/xuperchain/xuper-sdk-go v0.0.0-20210430070222-16051cc40b09
 	github.com/xuperchain/xuperchain v0.0.0-20210208123615-2d08ff11de3e
+	go.opentelemetry.io/otel v1.1.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.1.0
+	go.opentelemetry.io/otel/sdk v1.1.0
+	go.opentelemetry.io/otel/trace v1.1.0
 	golang.org/x/net v0.0.0-20210917221730-978cfadd31cf
 	google.golang.org/genproto v0.0.0-20211208223120-3a66f561d7aa
 	google.golang.org/grpc v1.41.0
 	google.golang.org/grpc/cmd/protoc-gen-go-grpc v1.2.0 // indirect
-	google.golang.org/protobuf v1.28.0 // indirect
+	google.golang.org/protobuf v1.28.0
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
 replace github.com/go-kit/kit => github.com/go-kit/kit v0.8.0
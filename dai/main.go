@@ -15,28 +15,45 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"github.com/sirupsen/logrus"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/executor/engine"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
 	"github.com/PaddlePaddle/PaddleDTX/dai/server"
 	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/version"
 )
 
-// init reads config file
+var (
+	// logStd writes logs into the log file, closed when the executor exits
+	logStd *logging.Logging
+)
+
+// init reads config files of config.ConfigPath, or prints the build information and exits if run as `executor version`
 func init() {
-	err := config.InitConfig("conf/config.toml")
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		fmt.Println(version.String())
+		os.Exit(0)
+	}
+	err := config.InitConfig(config.ConfigPath())
 	if err != nil {
 		appExit(err)
 	}
 
 	logConf := config.GetLogConf()
-	logStd, err := logging.InitLog(logConf, "executor.log", true)
+	logStd, err = logging.InitLog(logConf, "executor.log", true)
 	if err != nil {
 		appExit(err)
 	}
@@ -48,22 +65,75 @@ func init() {
 
 // main is where execution of the program begins
 func main() {
+	// flush logs after all the services stop
+	defer func() {
+		logrus.Info("executor exits")
+		if err := logStd.Close(); err != nil {
+			logrus.SetOutput(os.Stderr)
+			logrus.WithError(err).Error("failed to close log file")
+		}
+	}()
+	logrus.Infof("executor starts, %s", version.String())
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, os.Kill, syscall.SIGTERM)
-	go func() {
-		<-quit
-		cancel()
-	}()
-
 	executorConf := config.GetExecutorConf()
+	// export spans of tasks if '[executor.tracing]' is configured, the remaining spans are exported on exit
+	if executorConf.Tracing != nil {
+		shutdownTracing, err := tracing.Init(tracing.Options{
+			ServiceName: executorConf.Name,
+			Endpoint:    executorConf.Tracing.Endpoint,
+			Insecure:    executorConf.Tracing.Insecure,
+			SampleRate:  executorConf.Tracing.SampleRate,
+		})
+		if err != nil {
+			appExit(err)
+		}
+		defer shutdownTracing()
+	}
+	// send metrics to StatsD if '[executor.statsd]' is configured, the gauges are sent for the last time on exit
+	if executorConf.StatsD != nil {
+		stopStatsD, err := metrics.StartStatsD(metrics.StatsDOptions{
+			Address:       net.JoinHostPort(executorConf.StatsD.Host, strconv.Itoa(executorConf.StatsD.Port)),
+			Prefix:        executorConf.StatsD.Prefix,
+			FlushInterval: executorConf.StatsD.FlushInterval,
+		})
+		if err != nil {
+			appExit(err)
+		}
+		defer stopStatsD()
+	}
 	taskEngine, err := engine.NewEngine(executorConf)
 	if err != nil {
 		appExit(err)
 	}
 
+	// drain tasks in execution before stopping the server when receiving SIGTERM or interrupt,
+	// a second signal exits immediately
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-quit
+		logrus.Infof("received signal %v, executor shutting down", sig)
+		go func() {
+			<-quit
+			appExit(errorx.New(errcodes.ErrCodeShuttingDown, "received signal again, exit without waiting for tasks"))
+		}()
+		taskEngine.Shutdown()
+		cancel()
+	}()
+
+	// apply reloaded settings if 'executor.hotReload' is enabled
+	config.OnReload(func(conf *config.ExecutorConf, logConf *config.Log) {
+		taskEngine.ReloadMpcConf(conf.Mpc)
+		taskEngine.ReloadRequesterAllowlist(conf.RequesterAllowlist)
+		if level, err := logrus.ParseLevel(logConf.Level); err == nil {
+			logrus.SetLevel(level)
+		} else {
+			logrus.SetLevel(logging.DefaultLevel)
+		}
+	})
+
 	// start engine
 	if err := taskEngine.Start(ctx); err != nil {
 		appExit(err)
@@ -78,8 +148,17 @@ func main() {
 		// register executor service to gRPC server.
 		pbTask.RegisterTaskServer(srv.GrpcServer, taskEngine)
 
-		// register MPC service to gRPC server.
-		taskEngine.GetMpcService().RegisterClusterServer(srv.GrpcServer)
+		// register MPC service to gRPC server, observers have none as they never execute tasks.
+		if mpcService := taskEngine.GetMpcService(); mpcService != nil {
+			mpcService.RegisterClusterServer(srv.GrpcServer)
+		}
+
+		// the engine is ready after it connects to blockchain and storage
+		srv.SetReadiness(taskEngine)
+		// apply reloaded tokens of the http server if 'executor.hotReload' is enabled
+		config.OnReload(func(conf *config.ExecutorConf, logConf *config.Log) {
+			srv.ReloadHttpAuth(conf.HttpServer)
+		})
 
 		// start server, include grpc and http server
 		if err := srv.Serve(ctx); err != nil && err != context.Canceled {
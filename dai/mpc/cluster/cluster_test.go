@@ -18,8 +18,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"google.golang.org/grpc"
 
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
@@ -82,6 +84,101 @@ func TestRpc(t *testing.T) {
 	testP2P.Stop()
 }
 
+// blockingMpc blocks the training of task "Blocking-Task" until the test ends
+type blockingMpc struct {
+	mpc
+	releaseC chan struct{}
+}
+
+func (m *blockingMpc) Train(req *pb.TrainRequest) (resp *pb.TrainResponse, err error) {
+	if req.GetTaskID() == "Blocking-Task" {
+		<-m.releaseC
+	}
+	return m.mpc.Train(req)
+}
+
+func TestCancelTask(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	checkErr(err, t)
+	server := grpc.NewServer()
+	testMpc := &blockingMpc{releaseC: make(chan struct{})}
+	NewService(testMpc).RegisterClusterServer(server)
+	go server.Serve(lis)
+	defer server.Stop()
+	defer close(testMpc.releaseC)
+
+	clusterP2P := p2p.NewP2P()
+	defer clusterP2P.Stop()
+	rpcH := NewRpcClient(clusterP2P, 10*time.Second)
+	address := lis.Addr().String()
+
+	errC := make(chan error, 1)
+	go func() {
+		_, err := rpcH.StepTrain(&pb.TrainRequest{TaskID: "Blocking-Task"}, address)
+		errC <- err
+	}()
+	time.Sleep(500 * time.Millisecond)
+	rpcH.CancelTask("Blocking-Task")
+	select {
+	case err := <-errC:
+		if code, _ := errorx.Parse(err); code != errcodes.ErrCodeTaskCancelled {
+			t.Errorf("expected error code %s of the call in progress, got: %v", errcodes.ErrCodeTaskCancelled, err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("the call in progress is not aborted by CancelTask")
+	}
+
+	// the derived tasks are cancelled together with the source task
+	_, err = rpcH.StepTrainWithRetry(&pb.TrainRequest{TaskID: "Blocking-Task_0_train_Eva"}, address, 2, 1)
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeTaskCancelled {
+		t.Errorf("expected error code %s of the derived task, got: %v", errcodes.ErrCodeTaskCancelled, err)
+	}
+	// other tasks are not affected
+	if _, err := rpcH.StepTrain(&pb.TrainRequest{TaskID: "Other-Task"}, address); err != nil {
+		t.Errorf("the call of other task failed: %v", err)
+	}
+}
+
+func TestLocalTransport(t *testing.T) {
+	transport := NewLocalTransport()
+	testMpc := &blockingMpc{releaseC: make(chan struct{})}
+	defer close(testMpc.releaseC)
+	transport.Register("party1", testMpc)
+	rpcH := NewRpcClientWithTransport(transport, time.Second)
+
+	payload := []byte("Hello-This-Is-TrainRequest-Test")
+	resp, err := rpcH.StepTrain(&pb.TrainRequest{TaskID: "Local-Task", Payload: payload}, "party1")
+	checkErr(err, t)
+	if resp.TaskID != "Local-Task-TrainResponse" || string(resp.Payload) != string(payload) {
+		t.Errorf("unexpected response %v", resp)
+	}
+	// the response doesn't share the payload with the request
+	resp.Payload[0] = 'h'
+	if payload[0] != 'H' {
+		t.Error("the payload of request is modified by the response")
+	}
+	respP, err := rpcH.StepPredict(&pb.PredictRequest{TaskID: "Local-Task"}, "party1")
+	checkErr(err, t)
+	if respP.TaskID != "Local-Task-PredictResponse" {
+		t.Errorf("unexpected response %v", respP)
+	}
+
+	// the calls time out and are cancelled like remote ones
+	if _, err := rpcH.StepTrain(&pb.TrainRequest{TaskID: "Blocking-Task"}, "party1"); err == nil {
+		t.Error("the blocked call didn't time out")
+	}
+	rpcH.CancelTask("Local-Task")
+	if _, err := rpcH.StepTrain(&pb.TrainRequest{TaskID: "Local-Task"}, "party1"); err == nil {
+		t.Error("the call of cancelled task succeeded")
+	}
+
+	transport.Unregister("party1")
+	_, err = rpcH.StepTrain(&pb.TrainRequest{TaskID: "Other-Task"}, "party1")
+	if code, _ := errorx.Parse(err); code != errcodes.ErrCodeRPCFindNoPeer {
+		t.Errorf("expected error code %s of the missing node, got: %v", errcodes.ErrCodeRPCFindNoPeer, err)
+	}
+}
+
 func runServer(t *testing.T) {
 	var rpcOptions []grpc.ServerOption
 
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+)
+
+// LocalTransport implements Transport for the cluster nodes running in one process,
+// step messages are delivered in memory without network, used to simulate MPC tasks locally.
+// Messages are copied as if they were sent by network, so nodes never share the payloads.
+type LocalTransport struct {
+	services sync.Map // key is the address of node, and value is '*Service'
+}
+
+// NewLocalTransport creates LocalTransport without any node
+func NewLocalTransport() *LocalTransport {
+	return &LocalTransport{}
+}
+
+// Register adds the node m listening on address, replaces the existing one
+func (lt *LocalTransport) Register(address string, m Mpc) {
+	lt.services.Store(address, NewService(m))
+}
+
+// Unregister removes the node listening on address
+func (lt *LocalTransport) Unregister(address string) {
+	lt.services.Delete(address)
+}
+
+// Step delivers step message to the node peerName, and returns when the node responds or ctx is done
+func (lt *LocalTransport) Step(ctx context.Context, peerName string, req *pb.StepRequest) (*pb.StepResponse, error) {
+	s, ok := lt.services.Load(peerName)
+	if !ok {
+		return nil, errorx.New(errcodes.ErrCodeRPCFindNoPeer, "failed to get peer %s when do rpc request: node not found", peerName)
+	}
+
+	type result struct {
+		resp *pb.StepResponse
+		err  error
+	}
+	resultC := make(chan result, 1)
+	go func() {
+		resp, err := s.(*Service).Step(ctx, proto.Clone(req).(*pb.StepRequest))
+		if resp != nil {
+			resp = proto.Clone(resp).(*pb.StepResponse)
+		}
+		resultC <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case r := <-resultC:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
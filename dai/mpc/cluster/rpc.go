@@ -14,6 +14,9 @@
 package cluster
 
 import (
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
@@ -22,7 +25,11 @@ import (
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 var (
@@ -35,6 +42,13 @@ var (
 type Rpc interface {
 	PredictHandler
 	TrainHandler
+
+	// SetTimeout changes the timeout of remote procedure calls performed later
+	SetTimeout(timeout time.Duration)
+
+	// CancelTask aborts the remote procedure calls in progress of a task and the tasks derived from it,
+	// such as tasks of evaluation, and calls of the task performed later fail immediately
+	CancelTask(taskID string)
 }
 
 type PredictHandler interface {
@@ -55,6 +69,12 @@ type TrainHandler interface {
 	StepTrainWithRetry(req *pb.TrainRequest, peerName string, times int, inteSec int64) (*pb.TrainResponse, error)
 }
 
+// PeerObserver observes the results of calls to remote cluster nodes, such as to find out the failing ones,
+// the calls aborted as their tasks are cancelled are not observed
+type PeerObserver interface {
+	ObservePeer(peerName string, err error)
+}
+
 // P2P is used to get rpc connection to remote cluster nodes,
 // remember to call FreePeer() when rpc requests finish
 type P2P interface {
@@ -62,19 +82,24 @@ type P2P interface {
 	FreePeer()
 }
 
-// RpcClient implements Rpc interface,
-//  performs remote procedure calls to remote cluster nodes.
-type RpcClient struct {
-	timeout time.Duration
+// Transport carries step messages to cluster nodes,
+// by gRPC connections between nodes, or in memory when all nodes run in one process
+type Transport interface {
+	Step(ctx context.Context, peerName string, req *pb.StepRequest) (*pb.StepResponse, error)
+}
+
+// p2pTransport sends step messages by gRPC connections to remote cluster nodes
+type p2pTransport struct {
 	cluster P2P
 }
 
-func (rc *RpcClient) StepPredict(req *pb.PredictRequest, peerName string) (*pb.PredictResponse, error) {
-	peer, err := rc.cluster.GetPeer(peerName)
+// Step sends step message to the remote node peerName
+func (pt *p2pTransport) Step(ctx context.Context, peerName string, req *pb.StepRequest) (*pb.StepResponse, error) {
+	peer, err := pt.cluster.GetPeer(peerName)
 	if err != nil {
 		return nil, errorx.New(errcodes.ErrCodeRPCFindNoPeer, "failed to get peer %s when do rpc request: %s", peerName, err.Error())
 	}
-	defer rc.cluster.FreePeer()
+	defer pt.cluster.FreePeer()
 
 	conn, err := peer.GetConnect()
 	if err != nil {
@@ -82,8 +107,114 @@ func (rc *RpcClient) StepPredict(req *pb.PredictRequest, peerName string) (*pb.P
 	}
 
 	c := pb.NewClusterClient(conn)
+	return c.Step(ctx, req)
+}
+
+// RpcClient implements Rpc interface,
+//  performs remote procedure calls to remote cluster nodes.
+type RpcClient struct {
+	timeout   int64 // time.Duration, accessed atomically because it may be changed at runtime
+	transport Transport
+	observer  PeerObserver // nil if calls are not observed
+
+	lock      sync.Mutex
+	calls     map[string]*taskCalls // calls in progress, key is the source task id
+	cancelled map[string]time.Time  // cancelled tasks and the time they are cancelled
+}
+
+// taskCalls is the context shared by the calls in progress of a task
+type taskCalls struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	count  int
+}
+
+// cancelledRetention is how long a cancelled task is remembered, during
+// which the remaining goroutines of the task find out it is cancelled
+const cancelledRetention = time.Hour
+
+// sourceTaskID returns the id of the task from a user, tasks derived by Evaluator and LiveEvaluator
+// have ids like `{uuid}_{k}_train_Eva`
+func sourceTaskID(taskID string) string {
+	return strings.SplitN(taskID, "_", 2)[0]
+}
+
+// CancelTask aborts the calls in progress of the task, and rejects its calls performed later
+func (rc *RpcClient) CancelTask(taskID string) {
+	id := sourceTaskID(taskID)
+	now := time.Now()
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	for t, cancelledAt := range rc.cancelled {
+		if now.Sub(cancelledAt) > cancelledRetention {
+			delete(rc.cancelled, t)
+		}
+	}
+	rc.cancelled[id] = now
+	if calls, ok := rc.calls[id]; ok {
+		calls.cancel()
+		delete(rc.calls, id)
+	}
+}
+
+// startCall returns the context of a call of the task, remember to call the returned function when the call finishes
+func (rc *RpcClient) startCall(taskID string) (context.Context, func(), error) {
+	id := sourceTaskID(taskID)
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if _, ok := rc.cancelled[id]; ok {
+		return nil, nil, errorx.New(errcodes.ErrCodeTaskCancelled, "task %s is cancelled", taskID)
+	}
+	calls, ok := rc.calls[id]
+	if !ok {
+		// calls carry the trace context of the task to other executors
+		ctx, cancel := context.WithCancel(tracing.TaskContext(id))
+		calls = &taskCalls{ctx: ctx, cancel: cancel}
+		rc.calls[id] = calls
+	}
+	calls.count++
+	done := func() {
+		rc.lock.Lock()
+		defer rc.lock.Unlock()
+		calls.count--
+		// the entry is removed by CancelTask if the task is cancelled
+		if calls.count == 0 && rc.calls[id] == calls {
+			calls.cancel()
+			delete(rc.calls, id)
+		}
+	}
+	return calls.ctx, done, nil
+}
+
+// SetTimeout changes the timeout of remote procedure calls performed later
+func (rc *RpcClient) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&rc.timeout, int64(timeout))
+}
+
+func (rc *RpcClient) getTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&rc.timeout))
+}
+
+// SetPeerObserver sets the observer of the results of calls performed later
+func (rc *RpcClient) SetPeerObserver(observer PeerObserver) {
+	rc.observer = observer
+}
+
+// observe reports the result of a call to peerName to the observer
+func (rc *RpcClient) observe(peerName string, err error) {
+	if rc.observer != nil {
+		rc.observer.ObservePeer(peerName, err)
+	}
+}
+
+func (rc *RpcClient) StepPredict(req *pb.PredictRequest, peerName string) (*pb.PredictResponse, error) {
+	taskCtx, done, err := rc.startCall(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 
-	ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+	ctx, cancel := context.WithTimeout(taskCtx, rc.getTimeout())
 	defer cancel()
 
 	stepReq := &pb.StepRequest{
@@ -91,11 +222,18 @@ func (rc *RpcClient) StepPredict(req *pb.PredictRequest, peerName string) (*pb.P
 			PredictRequest: req,
 		},
 	}
-	stepResp, err := c.Step(ctx, stepReq)
+	start := time.Now()
+	stepResp, err := rc.transport.Step(ctx, peerName, stepReq)
+	metrics.MpcRpcObserved(pbCom.TaskType_PREDICT, time.Since(start), err)
 	if err != nil {
-		logger.Warningf("Step response is error: %s", err.Error())
+		if taskCtx.Err() != nil {
+			return nil, errorx.NewCode(err, errcodes.ErrCodeTaskCancelled, "task %s is cancelled", req.TaskID)
+		}
+		rc.observe(peerName, err)
+		logger.WithField(logging.PeerKey, peerName).Warningf("Step response is error: %s", err.Error())
 		return nil, err
 	}
+	rc.observe(peerName, nil)
 	resp := stepResp.GetPredictResponse()
 	return resp, err
 }
@@ -122,26 +260,23 @@ func (rc *RpcClient) StepPredictWithRetry(req *pb.PredictRequest, peerName strin
 			return resp, err
 		}
 		errR = err
+		// no need to retry the calls of a cancelled task
+		if code, _ := errorx.Parse(err); code == errcodes.ErrCodeTaskCancelled {
+			break
+		}
 	}
 
 	return nil, errR
 }
 
 func (rc *RpcClient) StepTrain(req *pb.TrainRequest, peerName string) (*pb.TrainResponse, error) {
-	peer, err := rc.cluster.GetPeer(peerName)
-	if err != nil {
-		return nil, errorx.New(errcodes.ErrCodeRPCFindNoPeer, "failed to get peer %s when do rpc request: %s", peerName, err.Error())
-	}
-	defer rc.cluster.FreePeer()
-
-	conn, err := peer.GetConnect()
+	taskCtx, done, err := rc.startCall(req.TaskID)
 	if err != nil {
-		return nil, errorx.New(errcodes.ErrCodeRPCConnect, "failed to get connection with %s: %s", peerName, err.Error())
+		return nil, err
 	}
+	defer done()
 
-	c := pb.NewClusterClient(conn)
-
-	ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+	ctx, cancel := context.WithTimeout(taskCtx, rc.getTimeout())
 	defer cancel()
 
 	stepReq := &pb.StepRequest{
@@ -149,11 +284,18 @@ func (rc *RpcClient) StepTrain(req *pb.TrainRequest, peerName string) (*pb.Train
 			TrainRequest: req,
 		},
 	}
-	stepResp, err := c.Step(ctx, stepReq)
+	start := time.Now()
+	stepResp, err := rc.transport.Step(ctx, peerName, stepReq)
+	metrics.MpcRpcObserved(pbCom.TaskType_LEARN, time.Since(start), err)
 	if err != nil {
-		logger.Warningf("Step response is error: %s", err.Error())
+		if taskCtx.Err() != nil {
+			return nil, errorx.NewCode(err, errcodes.ErrCodeTaskCancelled, "task %s is cancelled", req.TaskID)
+		}
+		rc.observe(peerName, err)
+		logger.WithField(logging.PeerKey, peerName).Warningf("Step response is error: %s", err.Error())
 		return nil, err
 	}
+	rc.observe(peerName, nil)
 
 	resp := stepResp.GetTrainResponse()
 	return resp, err
@@ -181,6 +323,10 @@ func (rc *RpcClient) StepTrainWithRetry(req *pb.TrainRequest, peerName string, t
 			return resp, err
 		}
 		errR = err
+		// no need to retry the calls of a cancelled task
+		if code, _ := errorx.Parse(err); code == errcodes.ErrCodeTaskCancelled {
+			break
+		}
 	}
 
 	return nil, errR
@@ -190,9 +336,17 @@ func (rc *RpcClient) StepTrainWithRetry(req *pb.TrainRequest, peerName string, t
 // timeout eg. 3*time.Second
 // connection releases when timeout elapses
 func NewRpcClient(clu P2P, timeout time.Duration) Rpc {
+	return NewRpcClientWithTransport(&p2pTransport{cluster: clu}, timeout)
+}
+
+// NewRpcClientWithTransport returns RpcClient instance sending step messages by transport,
+// such as LocalTransport which runs all cluster nodes in one process
+func NewRpcClientWithTransport(transport Transport, timeout time.Duration) Rpc {
 	rc := &RpcClient{
-		cluster: clu,
-		timeout: timeout,
+		transport: transport,
+		timeout:   int64(timeout),
+		calls:     make(map[string]*taskCalls),
+		cancelled: make(map[string]time.Time),
 	}
 	return rc
 }
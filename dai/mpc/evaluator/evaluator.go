@@ -26,6 +26,7 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"github.com/sirupsen/logrus"
 
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
 	convert "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
@@ -173,6 +174,12 @@ type evaluator struct {
 	splitter                   Splitter
 	calMetricScoresAndCallback func(index int, res *pbCom.PredictTaskResult)
 	predicResults              sync.Map //if obtained prediction result for each validation set
+	metrics                    map[string]bool // metrics to compute, all supported ones if empty
+}
+
+// wants returns whether the metric is to be computed
+func (e *evaluator) wants(metric string) bool {
+	return len(e.metrics) == 0 || e.metrics[metric]
 }
 
 // Start starts model evaluation, segment the training set according to a certain strategy (cross validation, proportional random division),
@@ -181,10 +188,26 @@ type evaluator struct {
 func (e *evaluator) Start(fileRows [][]string) error {
 	logger.WithFields(logrus.Fields{"evaluator": e.id}).Infof("start evaluation[caseType:%s, trainParams:%v], and samples are:[%v]", e.caseType, e.taskParams.TrainParams, fileRows[0:2])
 
+	// the folds are split and stored as dense rows
+	fileRows, err := convert.DensifyRows(fileRows)
+	if err != nil {
+		return errorx.New(errcodes.ErrCodeParam, "evaluator[%s] failed to read samples: %s", e.id, err.Error())
+	}
+
 	// add ID back to file, because it had been removed after Sample Alignment
 	fileRows = e.rebuildFileForEvaluation(fileRows)
 	logger.WithFields(logrus.Fields{"evaluator": e.id}).Infof("samples added IDs are:[%v], and total number is[%d]", fileRows[0:10], len(fileRows))
 
+	// the validator keeps only the validation set if it is held out by testFraction
+	var holdout [2][][]string
+	if e.usesHoldout() {
+		holdout, err = e.splitHoldout(fileRows)
+		if err != nil {
+			return errorx.New(errcodes.ErrCodeDataSetSplit, "evaluator[%s] failed to split dataset: %s", e.id, err.Error())
+		}
+		fileRows = holdout[0]
+	}
+
 	if e.caseType == pbCom.CaseType_Regression {
 		vcr, err := validation.NewRegressionValidation(fileRows, e.taskParams.TrainParams.Label, e.taskParams.TrainParams.IdName)
 		if err != nil {
@@ -200,6 +223,9 @@ func (e *evaluator) Start(fileRows [][]string) error {
 		e.validatorCaseBinClass = vcb
 		e.splitter = vcb
 	}
+	if e.usesHoldout() {
+		e.splitter = &holdoutSplitter{Splitter: e.splitter, label: e.taskParams.TrainParams.Label, folds: holdout[:]}
+	}
 
 	return e.splitAndTrain()
 }
@@ -221,6 +247,15 @@ func (e *evaluator) rebuildFileForEvaluation(f [][]string) [][]string {
 	return rebuiltF
 }
 
+// splitSeed returns the seed shuffling the dataset, the seed of the task if it is set, otherwise the evaluator's id,
+// so that the splits are the same for all parties
+func (e *evaluator) splitSeed() string {
+	if seed := e.taskParams.TrainParams.GetSeed(); seed != 0 {
+		return strconv.FormatInt(seed, 10)
+	}
+	return e.id
+}
+
 func (e *evaluator) splitAndTrain() error {
 	// divide the dataset according to `EvaluationRule`
 	logger.WithFields(logrus.Fields{
@@ -232,7 +267,7 @@ func (e *evaluator) splitAndTrain() error {
 	case pbCom.EvaluationRule_ErCrossVal:
 		var err error
 		if e.evalParams.Cv.Shuffle {
-			err = e.splitter.ShuffleKFoldsSplit(int(e.evalParams.Cv.Folds), e.id)
+			err = e.splitter.ShuffleKFoldsSplit(int(e.evalParams.Cv.Folds), e.splitSeed())
 		} else {
 			err = e.splitter.KFoldsSplit(int(e.evalParams.Cv.Folds))
 		}
@@ -261,7 +296,12 @@ func (e *evaluator) splitAndTrain() error {
 		e.numValidates = len(folds)
 
 	case pbCom.EvaluationRule_ErRandomSplit:
-		err := e.splitter.ShuffleSplit(int(e.evalParams.RandomSplit.PercentLO), e.id)
+		var err error
+		if h, ok := e.splitter.(*holdoutSplitter); ok {
+			err = h.split()
+		} else {
+			err = e.splitter.ShuffleSplit(int(e.evalParams.RandomSplit.PercentLO), e.splitSeed())
+		}
 		if err != nil {
 			logger.WithFields(logrus.Fields{
 				"evaluator":      e.id,
@@ -421,8 +461,9 @@ func (e *evaluator) SaveModel(res *pbCom.TrainTaskResult) error {
 }
 
 func (e *evaluator) packParamsForPredict(index int, model *pbCom.TrainModels, file []byte) *pbCom.StartTaskRequest {
-	// set idName
+	// set idName and PSI algorithm
 	model.IdName = e.taskParams.TrainParams.IdName
+	model.PsiAlgorithm = e.taskParams.TrainParams.PsiAlgorithm
 
 	// set task params
 	taskParams := pbCom.TaskParams{
@@ -542,23 +583,34 @@ func (e *evaluator) calMetricScoresAndCallbackCaseRegression(index int, res *pbC
 
 		// calculate metric scores and callback trainer
 		logger.Infof("evaluator[%s] got enough prediction outcomes, and start to calculate metric scores.", e.id)
-		rmses, mean, stdDev, err := e.validatorCaseRegression.GetAllRMSE()
-		if err != nil {
-			logger.Warningf("evaluator[%s] failed to calculate metric scores and error is[%s].", e.id, err.Error())
-			return
+		metricScores := &pbCom.RegressionCaseMetricScores{
+			CaseType: pbCom.CaseType_Regression,
 		}
-
-		// callback trainer to notify the end of evaluation
-		rRMSEs := make(map[int32]float64, len(rmses))
-		for k, v := range rmses {
-			rRMSEs[int32(k)] = v
+		if e.wants(blockchain.MetricRMSE) {
+			rmses, mean, stdDev, err := e.validatorCaseRegression.GetAllRMSE()
+			if err != nil {
+				logger.Warningf("evaluator[%s] failed to calculate metric scores and error is[%s].", e.id, err.Error())
+				return
+			}
+			rRMSEs := make(map[int32]float64, len(rmses))
+			for k, v := range rmses {
+				rRMSEs[int32(k)] = v
+			}
+			metricScores.RMSEs = rRMSEs
+			metricScores.MeanRMSE = mean
+			metricScores.StdDevRMSE = stdDev
 		}
-		metricScores := &pbCom.RegressionCaseMetricScores{
-			CaseType:   pbCom.CaseType_Regression,
-			RMSEs:      rRMSEs,
-			MeanRMSE:   mean,
-			StdDevRMSE: stdDev,
+		if e.wants(blockchain.MetricR2) {
+			r2s, mean, err := e.getAllR2(posSet)
+			if err != nil {
+				logger.Warningf("evaluator[%s] failed to calculate R2 scores and error is[%s].", e.id, err.Error())
+				return
+			}
+			metricScores.R2Scores = r2s
+			metricScores.MeanR2 = mean
 		}
+
+		// callback trainer to notify the end of evaluation
 		ems := &pbCom.EvaluationMetricScores{
 			Payload: &pbCom.EvaluationMetricScores_RegressionCaseMetricScores{
 				RegressionCaseMetricScores: metricScores,
@@ -687,22 +739,32 @@ func (e *evaluator) calMetricScoresAndCallbackCaseBinClass(index int, res *pbCom
 			return
 		}
 
-		// calculate metric scores and callback trainer
+		// calculate metric scores and callback trainer, the report of confusion matrix and ROC are
+		// only computed if the metrics derived from them are wanted
 		logger.Infof("evaluator[%s] got enough prediction outcomes, and start to calculate metric scores.", e.id)
-		reports, err := e.validatorCaseBinClass.GetOverallReport()
-		if err != nil {
-			logger.Warningf("evaluator[%s] failed to calculate metric scores and error is[%s].", e.id, err.Error())
-			return
+		var reports, repsRocAuc map[int][]byte
+		if e.wants(blockchain.MetricAccuracy) || e.wants(blockchain.MetricPrecision) ||
+			e.wants(blockchain.MetricRecall) || e.wants(blockchain.MetricF1Score) {
+			reports, err = e.validatorCaseBinClass.GetOverallReport()
+			if err != nil {
+				logger.Warningf("evaluator[%s] failed to calculate metric scores and error is[%s].", e.id, err.Error())
+				return
+			}
 		}
-		repsRocAuc, err := e.validatorCaseBinClass.GetAllROCAndAUC()
-		if err != nil {
-			logger.Warningf("evaluator[%s] failed to calculate AUC scores and error is[%s].", e.id, err.Error())
-			return
+		if e.wants(blockchain.MetricAUC) {
+			repsRocAuc, err = e.validatorCaseBinClass.GetAllROCAndAUC()
+			if err != nil {
+				logger.Warningf("evaluator[%s] failed to calculate AUC scores and error is[%s].", e.id, err.Error())
+				return
+			}
 		}
 
 		// callback trainer to notify the end of evaluation
-		lreps := len(reports)
+		lreps := len(posSet)
 		metricsPerFold := make(map[int32]*pbCom.BinaryClassCaseMetricScores_MetricsPerFold, lreps)
+		for k := range posSet {
+			metricsPerFold[int32(k)] = &pbCom.BinaryClassCaseMetricScores_MetricsPerFold{}
+		}
 		var avgAccuracy float64
 		var avgPrecision float64
 		var avgRecall float64
@@ -764,6 +826,7 @@ func (e *evaluator) calMetricScoresAndCallbackCaseBinClass(index int, res *pbCom
 			AvgAUC:         avgAUC,
 			MetricsPerFold: metricsPerFold,
 		}
+		e.dropUnwantedMetrics(metricScores)
 		ems := &pbCom.EvaluationMetricScores{
 			Payload: &pbCom.EvaluationMetricScores_BinaryClassCaseMetricScores{
 				BinaryClassCaseMetricScores: metricScores,
@@ -799,6 +862,87 @@ func (e *evaluator) calMetricScoresAndCallbackCaseBinClass(index int, res *pbCom
 	}
 }
 
+// dropUnwantedMetrics resets the scores of metrics not wanted, so that they're left out of the evaluation result
+func (e *evaluator) dropUnwantedMetrics(scores *pbCom.BinaryClassCaseMetricScores) {
+	if !e.wants(blockchain.MetricAccuracy) {
+		scores.AvgAccuracy = 0
+	}
+	if !e.wants(blockchain.MetricPrecision) {
+		scores.AvgPrecision = 0
+	}
+	if !e.wants(blockchain.MetricRecall) {
+		scores.AvgRecall = 0
+	}
+	if !e.wants(blockchain.MetricF1Score) {
+		scores.AvgF1Score = 0
+	}
+	for _, m := range scores.MetricsPerFold {
+		if !e.wants(blockchain.MetricAccuracy) {
+			m.Accuracy = 0
+		}
+		if !e.wants(blockchain.MetricPrecision) {
+			m.Precision = 0
+		}
+		if !e.wants(blockchain.MetricRecall) {
+			m.Recall = 0
+		}
+		if !e.wants(blockchain.MetricF1Score) {
+			m.F1Score = 0
+		}
+	}
+}
+
+// getAllR2 returns scores of R2 (coefficient of determination) over all split folds and their mean,
+// yPreds are the prediction outcomes of validation sets
+func (e *evaluator) getAllR2(yPreds map[int][]float64) (map[int32]float64, float64, error) {
+	r2s := make(map[int32]float64, len(yPreds))
+	var sum float64
+	for idx, yPred := range yPreds {
+		validSet, err := e.splitter.GetValidSet(idx)
+		if err != nil {
+			return nil, 0, err
+		}
+		labelIdx := fundIDIndex(validSet, e.taskParams.TrainParams.Label)
+		if labelIdx < 0 || len(validSet)-1 != len(yPred) {
+			return nil, 0, errorx.New(errcodes.ErrCodeParam, "invalid validation set[%d]", idx)
+		}
+		yReal := make([]float64, 0, len(yPred))
+		for _, row := range validSet[1:] {
+			y, err := strconv.ParseFloat(row[labelIdx], 64)
+			if err != nil {
+				return nil, 0, errorx.New(errcodes.ErrCodeParam, "invalid label in validation set[%d]: %s", idx, err.Error())
+			}
+			yReal = append(yReal, y)
+		}
+		r2 := getR2(yReal, yPred)
+		r2s[int32(idx)] = r2
+		sum += r2
+	}
+	if len(r2s) == 0 {
+		return r2s, 0, nil
+	}
+	return r2s, sum / float64(len(r2s)), nil
+}
+
+// getR2 returns the coefficient of determination, 1 - SSres/SStot,
+// it's 0 if the real values are all the same
+func getR2(yReal, yPred []float64) float64 {
+	var mean float64
+	for _, y := range yReal {
+		mean += y
+	}
+	mean /= float64(len(yReal))
+	var ssRes, ssTot float64
+	for i, y := range yReal {
+		ssRes += (y - yPred[i]) * (y - yPred[i])
+		ssTot += (y - mean) * (y - mean)
+	}
+	if ssTot == 0 {
+		return 0
+	}
+	return 1 - ssRes/ssTot
+}
+
 func fundIDIndex(fileRows [][]string, idName string) int {
 	// find where the IDs are
 	idx := -1
@@ -839,6 +983,9 @@ func NewEvaluator(req *pbCom.StartTaskRequest, mpc Mpc, trainer Trainer) (Evalua
 		if req.Params.EvalParams.RandomSplit == nil {
 			return nil, errorx.New(errcodes.ErrCodeParam, "invalid evaluation rule: %s", req.Params.EvalParams.EvalRule)
 		}
+		if err := blockchain.CheckRandomSplit(req.Params.Algo, req.Params.EvalParams.RandomSplit); err != nil {
+			return nil, err
+		}
 	case pbCom.EvaluationRule_ErCrossVal:
 		if req.Params.EvalParams.Cv == nil {
 			return nil, errorx.New(errcodes.ErrCodeParam, "invalid evaluation rule: %s", req.Params.EvalParams.EvalRule)
@@ -848,6 +995,22 @@ func NewEvaluator(req *pbCom.StartTaskRequest, mpc Mpc, trainer Trainer) (Evalua
 		return nil, errorx.New(errcodes.ErrCodeParam, "unknown evaluation rule: %s", req.Params.EvalParams.EvalRule)
 	}
 
+	// check the metrics to compute are supported by the algorithm
+	var metrics map[string]bool
+	if len(req.Params.EvalParams.Metrics) > 0 {
+		supported := make(map[string]bool)
+		for _, m := range blockchain.EvalMetricsSupported[req.Params.Algo] {
+			supported[m] = true
+		}
+		metrics = make(map[string]bool)
+		for _, m := range req.Params.EvalParams.Metrics {
+			if !supported[m] {
+				return nil, errorx.New(errcodes.ErrCodeParam, "unsupported evaluation metric: %s", m)
+			}
+			metrics[m] = true
+		}
+	}
+
 	e := &evaluator{
 		id:         req.TaskID,
 		caseType:   caseType,
@@ -857,6 +1020,7 @@ func NewEvaluator(req *pbCom.StartTaskRequest, mpc Mpc, trainer Trainer) (Evalua
 		taskParams: req.Params,
 		evalParams: req.Params.EvalParams,
 		evalRule:   req.Params.EvalParams.EvalRule,
+		metrics:    metrics,
 	}
 	if caseType == pbCom.CaseType_Regression {
 		e.calMetricScoresAndCallback = e.calMetricScoresAndCallbackCaseRegression
@@ -16,7 +16,11 @@ package evaluator
 import (
 	"bytes"
 	"encoding/csv"
+	"math"
 	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 )
 
 func TestCSV(t *testing.T) {
@@ -52,6 +56,62 @@ func TestCSV(t *testing.T) {
 	}
 }
 
+func TestGetR2(t *testing.T) {
+	yReal := []float64{1, 2, 3, 4}
+	if r2 := getR2(yReal, yReal); r2 != 1 {
+		t.Errorf("expected R2 1 for perfect prediction, got %f", r2)
+	}
+	// predicting the mean gets R2 0
+	if r2 := getR2(yReal, []float64{2.5, 2.5, 2.5, 2.5}); r2 != 0 {
+		t.Errorf("expected R2 0 for mean prediction, got %f", r2)
+	}
+	if r2 := getR2(yReal, []float64{1, 2, 3, 5}); math.Abs(r2-0.8) > 1e-9 {
+		t.Errorf("expected R2 0.8, got %f", r2)
+	}
+}
+
+func TestNewEvaluatorMetrics(t *testing.T) {
+	newRequest := func(algo pbCom.Algorithm, metrics ...string) *pbCom.StartTaskRequest {
+		return &pbCom.StartTaskRequest{
+			TaskID: "t1",
+			Params: &pbCom.TaskParams{
+				Algo:        algo,
+				TrainParams: &pbCom.TrainParams{},
+				EvalParams: &pbCom.EvaluationParams{
+					Enable:   true,
+					EvalRule: pbCom.EvaluationRule_ErLOO,
+					Metrics:  metrics,
+				},
+			},
+		}
+	}
+
+	e, err := NewEvaluator(newRequest(pbCom.Algorithm_LOGIC_REGRESSION_VL), nil, nil)
+	checkErr(err, t)
+	if !e.(*evaluator).wants(blockchain.MetricAUC) {
+		t.Error("expected all metrics computed if none is specified")
+	}
+
+	e, err = NewEvaluator(newRequest(pbCom.Algorithm_LOGIC_REGRESSION_VL, blockchain.MetricAUC, blockchain.MetricF1Score), nil, nil)
+	checkErr(err, t)
+	ev := e.(*evaluator)
+	if !ev.wants(blockchain.MetricAUC) || !ev.wants(blockchain.MetricF1Score) || ev.wants(blockchain.MetricAccuracy) {
+		t.Errorf("expected only AUC and F1Score computed, got %v", ev.metrics)
+	}
+	scores := &pbCom.BinaryClassCaseMetricScores{
+		AvgAccuracy: 0.9, AvgF1Score: 0.8, AvgAUC: 0.7,
+		MetricsPerFold: map[int32]*pbCom.BinaryClassCaseMetricScores_MetricsPerFold{0: {Accuracy: 0.9, F1Score: 0.8}},
+	}
+	ev.dropUnwantedMetrics(scores)
+	if scores.AvgAccuracy != 0 || scores.MetricsPerFold[0].Accuracy != 0 || scores.AvgF1Score != 0.8 || scores.AvgAUC != 0.7 {
+		t.Errorf("expected Accuracy dropped only, got %v", scores)
+	}
+
+	if _, err := NewEvaluator(newRequest(pbCom.Algorithm_LINEAR_REGRESSION_VL, blockchain.MetricAUC), nil, nil); err == nil {
+		t.Error("expected error for AUC of linear regression")
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
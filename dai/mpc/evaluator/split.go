@@ -0,0 +1,224 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// holdoutSplitter holds out the validation set chosen by the fraction and strategy of RandomSplit, see splitHoldout.
+// It wraps the Splitter of the validator computing metric scores, which keeps the validation set only,
+// and overrides the folds with the validation set and the training set.
+type holdoutSplitter struct {
+	Splitter
+	label string
+	folds [][][]string // the validation set and the training set, each starts with the names of features
+}
+
+// split makes the validation set kept by the wrapped Splitter its only fold, so that the metric scores
+// of the first fold are computed over the validation set
+func (s *holdoutSplitter) split() error {
+	return s.Splitter.Split(100)
+}
+
+// GetAllFolds returns the validation set and the training set
+func (s *holdoutSplitter) GetAllFolds() ([][][]string, error) {
+	return s.folds, nil
+}
+
+// GetTrainSet returns the training set, idxHO should be 0 which refers to the validation set held out
+func (s *holdoutSplitter) GetTrainSet(idxHO int) ([][]string, error) {
+	if idxHO != 0 {
+		return nil, errors.New("invalid index referring to subset held out")
+	}
+	return s.folds[1], nil
+}
+
+// GetValidSet returns the validation set, idx should be 0
+func (s *holdoutSplitter) GetValidSet(idx int) ([][]string, error) {
+	if idx != 0 {
+		return nil, errors.New("invalid index referring to validation set")
+	}
+	return s.folds[0], nil
+}
+
+// GetPredictSet returns the validation set without label feature, idx should be 0
+func (s *holdoutSplitter) GetPredictSet(idx int) ([][]string, error) {
+	validSet, err := s.GetValidSet(idx)
+	if err != nil {
+		return nil, err
+	}
+	idxL := -1
+	for i, v := range validSet[0] {
+		if v == s.label {
+			idxL = i
+			break
+		}
+	}
+	if idxL < 0 {
+		return validSet, nil
+	}
+	predictSet := make([][]string, 0, len(validSet))
+	for _, r := range validSet {
+		if len(r) <= idxL {
+			return nil, errors.New("invalid file")
+		}
+		newR := make([]string, 0, len(r)-1)
+		newR = append(newR, r[:idxL]...)
+		newR = append(newR, r[idxL+1:]...)
+		predictSet = append(predictSet, newR)
+	}
+	return predictSet, nil
+}
+
+// usesHoldout returns whether the validation set is held out by splitHoldout instead of the Splitter,
+// which is the case of Random Split with testFraction
+func (e *evaluator) usesHoldout() bool {
+	return e.evalRule == pbCom.EvaluationRule_ErRandomSplit && e.evalParams.RandomSplit.GetTestFraction() > 0
+}
+
+// splitHoldout divides the aligned samples fileRows into the validation set of testFraction of them and the training set,
+// both start with the names of features and keep the order of samples in fileRows.
+//
+// The samples are aligned in the same order on all parties, so with strategy "random" every party leaves out the same
+// samples, shuffled by the shared seed. With "stratified" and "time" the partition depends on the label or the time
+// column, so it's made by the party with label, and the other parties keep all samples in both sets. The sample
+// alignment of the training and prediction tasks of evaluation then narrows their sets down to the partition,
+// they learn only which samples are left out, never the labels nor the times.
+func (e *evaluator) splitHoldout(fileRows [][]string) ([2][][]string, error) {
+	rs := e.evalParams.RandomSplit
+	header, samples := fileRows[0], fileRows[1:]
+	n := len(samples)
+	numTest := int(math.Round(rs.TestFraction * float64(n)))
+
+	var testIdx []int
+	switch rs.Strategy {
+	case "", blockchain.SplitStrategyRandom:
+		testIdx = seededPerm(e.splitSeed(), n)[:numTest]
+	case blockchain.SplitStrategyStratified, blockchain.SplitStrategyTime:
+		if !e.taskParams.TrainParams.IsTagPart {
+			all := append([][]string{header}, samples...)
+			return [2][][]string{all, all}, nil
+		}
+		var err error
+		if rs.Strategy == blockchain.SplitStrategyStratified {
+			testIdx, err = stratifiedTestSet(header, samples, e.taskParams.TrainParams.Label, rs.TestFraction, e.splitSeed())
+		} else {
+			testIdx, err = latestTestSet(header, samples, rs.TimeColumn, numTest)
+		}
+		if err != nil {
+			return [2][][]string{}, err
+		}
+	default:
+		return [2][][]string{}, fmt.Errorf("unsupported split strategy %s", rs.Strategy)
+	}
+
+	inTest := make([]bool, n)
+	for _, i := range testIdx {
+		inTest[i] = true
+	}
+	testSet := [][]string{header}
+	trainSet := [][]string{header}
+	for i, r := range samples {
+		if inTest[i] {
+			testSet = append(testSet, r)
+		} else {
+			trainSet = append(trainSet, r)
+		}
+	}
+	return [2][][]string{testSet, trainSet}, nil
+}
+
+// stratifiedTestSet returns the indexes of samples left out, which are fraction of the samples of each class of label,
+// chosen randomly by seed
+func stratifiedTestSet(header []string, samples [][]string, label string, fraction float64, seed string) ([]int, error) {
+	idx := columnIndex(header, label)
+	if idx < 0 {
+		return nil, fmt.Errorf("no label %s found", label)
+	}
+	classes := make(map[string][]int)
+	for i, r := range samples {
+		if len(r) <= idx {
+			return nil, errors.New("invalid file")
+		}
+		classes[r[idx]] = append(classes[r[idx]], i)
+	}
+	names := make([]string, 0, len(classes))
+	for c := range classes {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+
+	var testIdx []int
+	for _, c := range names {
+		members := classes[c]
+		numTest := int(math.Round(fraction * float64(len(members))))
+		for _, p := range seededPerm(seed+"/"+c, len(members))[:numTest] {
+			testIdx = append(testIdx, members[p])
+		}
+	}
+	return testIdx, nil
+}
+
+// latestTestSet returns the indexes of the numTest samples with the largest values of timeColumn,
+// samples of the same time are ordered as they are aligned
+func latestTestSet(header []string, samples [][]string, timeColumn string, numTest int) ([]int, error) {
+	idx := columnIndex(header, timeColumn)
+	if idx < 0 {
+		return nil, fmt.Errorf("no time column %s found", timeColumn)
+	}
+	times := make([]float64, len(samples))
+	for i, r := range samples {
+		if len(r) <= idx {
+			return nil, errors.New("invalid file")
+		}
+		t, err := strconv.ParseFloat(r[idx], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q of sample %d, it should be numeric, such as a unix timestamp", r[idx], i+1)
+		}
+		times[i] = t
+	}
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return times[order[i]] < times[order[j]] })
+	return order[len(order)-numTest:], nil
+}
+
+// seededPerm returns a permutation of [0, n) determined by seed
+func seededPerm(seed string, n int) []int {
+	h := sha256.Sum256([]byte(seed))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(h[:8])))).Perm(n)
+}
+
+// columnIndex returns the index of name in header, -1 if not found
+func columnIndex(header []string, name string) int {
+	for i, v := range header {
+		if v == name {
+			return i
+		}
+	}
+	return -1
+}
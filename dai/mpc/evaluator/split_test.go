@@ -0,0 +1,138 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// splitRows returns the aligned samples of 20 IDs, with ids in the first column and times counting down,
+// the first 5 samples are labeled "yes" and the others "no"
+func splitRows() [][]string {
+	rows := [][]string{{"id", "x", "t", "y"}}
+	for i := 1; i <= 20; i++ {
+		label := "no"
+		if i <= 5 {
+			label = "yes"
+		}
+		rows = append(rows, []string{strconv.Itoa(i), strconv.Itoa(i * 10), strconv.Itoa(100 - i), label})
+	}
+	return rows
+}
+
+func newSplitEvaluator(strategy string, isTagPart bool) *evaluator {
+	rs := &pbCom.RandomSplit{TestFraction: 0.2, Strategy: strategy}
+	if strategy == blockchain.SplitStrategyTime {
+		rs.TimeColumn = "t"
+	}
+	return &evaluator{
+		id:         "task-1",
+		evalRule:   pbCom.EvaluationRule_ErRandomSplit,
+		evalParams: &pbCom.EvaluationParams{Enable: true, RandomSplit: rs},
+		taskParams: &pbCom.TaskParams{TrainParams: &pbCom.TrainParams{Label: "y", IdName: "id", IsTagPart: isTagPart, Seed: 7}},
+	}
+}
+
+// ids returns the IDs of the samples of set
+func ids(set [][]string) []string {
+	var ret []string
+	for _, r := range set[1:] {
+		ret = append(ret, r[0])
+	}
+	return ret
+}
+
+func TestSplitHoldout(t *testing.T) {
+	// random split leaves out the same samples on all parties, the order of samples kept
+	tagSets, err := newSplitEvaluator("", true).splitHoldout(splitRows())
+	checkErr(err, t)
+	otherSets, err := newSplitEvaluator(blockchain.SplitStrategyRandom, false).splitHoldout(splitRows())
+	checkErr(err, t)
+	if len(tagSets[0]) != 5 || len(tagSets[1]) != 17 {
+		t.Fatalf("expected 4 samples left out and 16 trained on, got %d and %d", len(tagSets[0])-1, len(tagSets[1])-1)
+	}
+	if !reflect.DeepEqual(ids(tagSets[0]), ids(otherSets[0])) {
+		t.Errorf("expected the same validation set on all parties, got %v and %v", ids(tagSets[0]), ids(otherSets[0]))
+	}
+	for _, set := range tagSets {
+		for i := 2; i < len(set); i++ {
+			a, _ := strconv.Atoi(set[i-1][0])
+			b, _ := strconv.Atoi(set[i][0])
+			if a >= b {
+				t.Fatalf("expected samples in the aligned order, got %v", ids(set))
+			}
+		}
+	}
+
+	// stratified split leaves out 1 of the 5 "yes" and 3 of the 15 "no"
+	sets, err := newSplitEvaluator(blockchain.SplitStrategyStratified, true).splitHoldout(splitRows())
+	checkErr(err, t)
+	yes := 0
+	for _, r := range sets[0][1:] {
+		if r[3] == "yes" {
+			yes++
+		}
+	}
+	if len(sets[0]) != 5 || yes != 1 {
+		t.Errorf("expected 1 yes and 3 no left out, got %v", sets[0][1:])
+	}
+
+	// time-ordered split leaves out the latest samples, which have the smallest IDs
+	sets, err = newSplitEvaluator(blockchain.SplitStrategyTime, true).splitHoldout(splitRows())
+	checkErr(err, t)
+	if got := ids(sets[0]); !reflect.DeepEqual(got, []string{"1", "2", "3", "4"}) {
+		t.Errorf("expected the latest 4 samples left out, got %v", got)
+	}
+
+	// the parties without label keep all samples, the partition is made by the party with label
+	for _, strategy := range []string{blockchain.SplitStrategyStratified, blockchain.SplitStrategyTime} {
+		sets, err := newSplitEvaluator(strategy, false).splitHoldout(splitRows())
+		checkErr(err, t)
+		if len(sets[0]) != 21 || len(sets[1]) != 21 {
+			t.Errorf("%s: expected all samples kept by the party without label, got %d and %d", strategy, len(sets[0])-1, len(sets[1])-1)
+		}
+	}
+
+	rows := splitRows()
+	rows[3][2] = "yesterday"
+	if _, err := newSplitEvaluator(blockchain.SplitStrategyTime, true).splitHoldout(rows); err == nil {
+		t.Error("expected error for non-numeric time")
+	}
+}
+
+func TestHoldoutSplitter(t *testing.T) {
+	e := newSplitEvaluator("", true)
+	sets, err := e.splitHoldout(splitRows())
+	checkErr(err, t)
+	s := &holdoutSplitter{label: "y", folds: sets[:]}
+
+	ts, err := s.GetTrainSet(0)
+	checkErr(err, t)
+	if len(ts) != 17 {
+		t.Errorf("expected 16 samples in training set, got %d", len(ts)-1)
+	}
+	ps, err := s.GetPredictSet(0)
+	checkErr(err, t)
+	if !reflect.DeepEqual(ps[0], []string{"id", "x", "t"}) || len(ps) != 5 || len(ps[1]) != 3 {
+		t.Errorf("expected validation set without label, got %v", ps)
+	}
+	if _, err := s.GetValidSet(1); err == nil {
+		t.Error("expected error for the index of the training set")
+	}
+}
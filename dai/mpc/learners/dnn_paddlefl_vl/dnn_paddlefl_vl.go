@@ -33,10 +33,13 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/spill"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 	pbDnnVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/dnn_paddlefl_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/util/docker"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 )
 
@@ -117,8 +120,8 @@ type Learner struct {
 
 	status learnerStatusType
 
-	featureRows [][]string // Actual samples for training, converted by the result of psi.IntersectParts
-	labelRows   [][]string
+	featureRows *spill.Rows // Actual samples for training, converted by the result of psi.IntersectParts
+	labelRows   *spill.Rows // spilled under memory pressure until they're exported for PaddleFL
 	fvSize      [3]int64 // feature vector size for every learner in mpc
 	lvSize      int64    // label vector size
 
@@ -152,6 +155,7 @@ func (l *Learner) advance(message *pbDnnVl.Message) (*pb.TrainResponse, error) {
 	var ret *pb.TrainResponse
 	switch mType {
 	case pbDnnVl.MessageType_MsgPsiEnc: // local message
+		tracing.StartStage(l.id, tracing.StagePSI)
 		encIDs, err := l.psi.EncryptSampleIDSet()
 		if err != nil {
 			go handleError(err)
@@ -237,12 +241,18 @@ func (l *Learner) advance(message *pbDnnVl.Message) (*pb.TrainResponse, error) {
 		}
 	case pbDnnVl.MessageType_MsgPsiIntersect: // local message
 		done, newRows, _, err := l.psi.IntersectParts()
+		if err == nil && done {
+			// PaddleFL takes dense rows, sparse rows are converted into dense ones
+			newRows, err = crypCom.DensifyRows(newRows)
+		}
 		if err != nil {
 			go handleError(err)
+			tracing.EndStage(l.id, tracing.StagePSI, err)
 			return nil, err
 		}
 
 		if done {
+			tracing.EndStage(l.id, tracing.StagePSI, nil)
 			l.status = learnerStatusEndPSI
 			l.setSamples(newRows)
 			l.batchNum = len(newRows)
@@ -544,6 +554,7 @@ func (l *Learner) advance(message *pbDnnVl.Message) (*pb.TrainResponse, error) {
 				"--output_size", strconv.Itoa(int(l.lvSize)),
 				"--model_dir", l.containerWorkspace + LOCAL_MODEL_FOLDER,
 			}
+			cmd = append(cmd, optimizerArgs(l.trainParams.GetAlpha(), l.trainParams.GetOptimizer())...)
 			logger.WithFields(logrus.Fields{
 				"paddlefl role": l.role,
 			}).Infof("learner[%s] execute docker cmd [%s]", l.id, strings.Join(cmd, " "))
@@ -629,6 +640,42 @@ func (l *Learner) sendMessage(message *pbDnnVl.Message, address string) (*pbDnnV
 	return m, nil
 }
 
+// optimizerArgs returns the arguments of train.py for the optimizer and learning rate schedule,
+// which are applied by all the PaddleFL parties, the learning rate starts from alpha,
+// and the defaults of train.py are used if op is nil
+func optimizerArgs(alpha float64, op *pbCom.OptimizerParams) []string {
+	if op == nil {
+		return nil
+	}
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	var args []string
+	if alpha > 0 {
+		args = append(args, "--base_lr", formatFloat(alpha))
+	}
+	if op.Optimizer != "" {
+		args = append(args, "--optimizer", op.Optimizer)
+	}
+	if op.Beta1 != 0 {
+		args = append(args, "--beta1", formatFloat(op.Beta1))
+	}
+	if op.Beta2 != 0 {
+		args = append(args, "--beta2", formatFloat(op.Beta2))
+	}
+	if op.Epsilon != 0 {
+		args = append(args, "--epsilon", formatFloat(op.Epsilon))
+	}
+	if op.Schedule != "" {
+		args = append(args, "--lr_schedule", op.Schedule)
+	}
+	if op.StepSize != 0 {
+		args = append(args, "--step_size", strconv.FormatInt(op.StepSize, 10), "--gamma", formatFloat(op.Gamma))
+	}
+	if op.TotalRounds != 0 {
+		args = append(args, "--total_epochs", strconv.FormatInt(op.TotalRounds, 10), "--min_lr", formatFloat(op.MinAlpha))
+	}
+	return args
+}
+
 // NewLearner returns a VerticalLinearDnn Learner based PaddleFL. PaddleFL's addresses will be obtained by invoking smart contract.
 // id is the assigned id for Learner
 // address indicates local mpc-node
@@ -641,6 +688,9 @@ func (l *Learner) sendMessage(message *pbDnnVl.Message, address string) (*pbDnnV
 func NewLearner(id string, address string, params *pbCom.TrainParams, samplesFile []byte,
 	parties []string, paddleFLParams *pbCom.PaddleFLParams, rpc RpcHandler, rh ResultHandler) (*Learner, error) {
 
+	if err := psi.CheckMultiPartsAlgorithm(params.GetPsiAlgorithm()); err != nil {
+		return nil, err
+	}
 	p, err := psi.NewVLPSIByPairs(address, samplesFile, params.GetIdName(), parties)
 	if err != nil {
 		return nil, err
@@ -712,14 +762,27 @@ func (l *Learner) setSamples(fileRows [][]string) {
 			labelRows = append(labelRows, row[lable_position:lable_position+1])
 			featureRows = append(featureRows, append(row[0:lable_position], row[lable_position+1:dataLen]...))
 		}
-		l.featureRows = featureRows
-		l.labelRows = labelRows
+		l.featureRows = spill.Keep(l.id, "features", featureRows)
+		l.labelRows = spill.Keep(l.id, "labels", labelRows)
 		l.fvSize[l.role] = int64(len(featureRows[0]))
 		l.lvSize = int64(len(labelRows[0]))
 	} else {
-		l.featureRows = fileRows
+		l.featureRows = spill.Keep(l.id, "features", fileRows)
 		l.fvSize[l.role] = int64(len(fileRows[0]))
 	}
+	// the samples are held by PSI and the rows from now on
+	l.samplesFile = nil
+}
+
+// Release removes the raw and encrypted samples stored in the local workspace, and the samples spilled
+// under memory pressure, called when the task finishes or is cancelled
+func (l *Learner) Release() {
+	spill.Remove(l.id)
+	for _, folder := range []string{LOCAL_SAMPLE_FOLDER, LOCAL_SAMPLE_MPC_FOLDER} {
+		if err := os.RemoveAll(l.localWorkspace + folder); err != nil {
+			logger.WithField(logging.TaskIDKey, l.id).WithError(err).Warnf("failed to remove samples in %s", folder)
+		}
+	}
 }
 
 func (l *Learner) exportRawSamples() error {
@@ -734,17 +797,28 @@ func (l *Learner) exportRawSamples() error {
 	}
 
 	featureFile := sampleFolder + PADDLEFL_TASK_SAMPLE_FILE
-	err = csv.WriteRowsToFile(l.featureRows, featureFile)
+	featureRows, err := l.featureRows.Get()
+	if err != nil {
+		return err
+	}
+	err = csv.WriteRowsToFile(featureRows, featureFile)
 	if err != nil {
 		return err
 	}
 
 	if l.trainParams.GetIsTagPart() {
 		labelDataFile := sampleFolder + PADDLEFL_TASK_LABEL_FILE
-		err := csv.WriteRowsToFile(l.labelRows, labelDataFile)
+		labelRows, err := l.labelRows.Get()
+		if err != nil {
+			return err
+		}
+		err = csv.WriteRowsToFile(labelRows, labelDataFile)
 		if err != nil {
 			return err
 		}
 	}
+	// the rows are in the local workspace now, PaddleFL reads them from there
+	l.featureRows, l.labelRows = nil, nil
+	spill.Remove(l.id)
 	return nil
 }
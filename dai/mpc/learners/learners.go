@@ -14,9 +14,13 @@
 package learners
 
 import (
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners/dnn_paddlefl_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners/linear_reg_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners/logic_reg_vl"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners/xgboost_vl"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 )
@@ -45,6 +49,22 @@ type ResultHandler interface {
 	SaveResult(*pbCom.TrainTaskResult)
 }
 
+// Checkpointer persists the checkpoints of a training task,
+// so that the task resumes from the last checkpoint after the executor restarts
+type Checkpointer interface {
+	// Interval returns the number of rounds between two checkpoints
+	Interval() uint64
+
+	// SaveCheckpoint replaces the checkpoint of the task
+	SaveCheckpoint(data []byte) error
+
+	// LoadCheckpoint returns the checkpoint of the task, or nil if there is none
+	LoadCheckpoint() ([]byte, error)
+
+	// DeleteCheckpoint removes the checkpoint of the task
+	DeleteCheckpoint() error
+}
+
 // LiveEvaluator performs staged evaluation during training.
 // The basic steps of LiveEvaluator:
 //  Divide the dataset in the way of proportional random division.
@@ -72,17 +92,20 @@ type LiveEvaluator interface {
 // params are parameters for training model
 // samplesFile contains samples for training model
 // le is an LiveEvaluator, and LiveEvaluation should be performed by learner if it is assigned without nil
+// cp persists checkpoints of the training, only linear and logistic regression learners support it, and it could be nil
 func NewLearner(id string, address string, algo pbCom.Algorithm,
 	params *pbCom.TrainParams, samplesFile []byte,
-	parties []string, paddleFLParams *pbCom.PaddleFLParams, rpc RpcHandler, rh ResultHandler, le LiveEvaluator) (Learner, error) {
+	parties []string, paddleFLParams *pbCom.PaddleFLParams, rpc RpcHandler, rh ResultHandler, le LiveEvaluator, cp Checkpointer) (Learner, error) {
 	if pbCom.Algorithm_LINEAR_REGRESSION_VL == algo {
 		return linear_reg_vl.NewLearner(id, address, params, samplesFile,
-			parties, rpc, rh, le)
+			parties, rpc, rh, le, cp)
 	} else if pbCom.Algorithm_DNN_PADDLEFL_VL == algo {
 		return dnn_paddlefl_vl.NewLearner(id, address, params, samplesFile, parties, paddleFLParams, rpc, rh)
+	} else if pbCom.Algorithm_XGBOOST_VL == algo {
+		return xgboost_vl.NewLearner(id, address, params, samplesFile, parties, rpc, rh)
 	} else { // pbCom.Algorithm_LOGIC_REGRESSION_VL
 		return logic_reg_vl.NewLearner(id, address, params, samplesFile,
-			parties, rpc, rh, le)
+			parties, rpc, rh, le, cp)
 	}
 }
 
@@ -102,6 +125,8 @@ func NewLearnerWithoutSamples(id string, address string, algo pbCom.Algorithm,
 			parties, rpc, rh)
 	} else if pbCom.Algorithm_DNN_PADDLEFL_VL == algo {
 		panic("Algorithm_DNN_PADDLEFL_VL NewLearnerWithoutSamples")
+	} else if pbCom.Algorithm_XGBOOST_VL == algo {
+		return nil, errorx.New(errcodes.ErrCodeNotSupported, "evaluation is not supported by %s yet", algo.String())
 	} else { // pbCom.Algorithm_LOGIC_REGRESSION_VL
 		return logic_reg_vl.NewLearnerWithoutSamples(id, address, params,
 			parties, rpc, rh)
@@ -14,6 +14,7 @@
 package linear_reg_vl
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -21,13 +22,16 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"github.com/golang/protobuf/proto"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	crypCom "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/spill"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 	pbLinearRegVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/linear_reg_vl"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 var (
@@ -75,6 +79,22 @@ type ResultHandler interface {
 	SaveResult(*pbCom.TrainTaskResult)
 }
 
+// Checkpointer persists the checkpoints of a training task,
+// so that the task resumes from the last checkpoint after the executor restarts
+type Checkpointer interface {
+	// Interval returns the number of rounds between two checkpoints
+	Interval() uint64
+
+	// SaveCheckpoint replaces the checkpoint of the task
+	SaveCheckpoint(data []byte) error
+
+	// LoadCheckpoint returns the checkpoint of the task, or nil if there is none
+	LoadCheckpoint() ([]byte, error)
+
+	// DeleteCheckpoint removes the checkpoint of the task
+	DeleteCheckpoint() error
+}
+
 // LiveEvaluator performs staged evaluation during training.
 // The basic steps of LiveEvaluator:
 //  Divide the dataset in the way of proportional random division.
@@ -92,6 +112,15 @@ type LiveEvaluator interface {
 	Trigger(*pb.LiveEvaluationTriggerMsg) error
 }
 
+// EarlyStopper is optionally implemented by LiveEvaluator, which tracks the metric of live evaluation,
+// and stops training when the metric stops improving
+type EarlyStopper interface {
+	// EarlyStopped returns whether the metric hasn't improved for the patience rounds
+	EarlyStopped() bool
+	// EarlyStopResult returns the metric tracked, nil if it isn't tracked locally
+	EarlyStopResult() *pbCom.EarlyStopResult
+}
+
 type learnerStatusType uint8
 
 const (
@@ -116,11 +145,15 @@ type Learner struct {
 	loopRound    uint64
 	rpc          RpcHandler    // rpc is used to request remote mpc-node
 	rh           ResultHandler // rh handles final result which is successful or failed
+	cp           Checkpointer  // cp persists checkpoints of the training, nil if checkpoints are disabled
+	checkpointed bool          // checkpointed means the learner resumes from the checkpoint of resumeRound
+	resumeRound  uint64        // resumeRound is the round of the checkpoint which the learner resumes from
+	cpExists     bool          // cpExists means a checkpoint of the task has been persisted
 	lEvaluated   bool          // lEvaluated means whether to perform LiveEvaluation
 	lEvaluator   LiveEvaluator
-	triggerInter uint64     // triggerInter is the number of interval rounds of triggering `LiveEvaluation`
-	triggerRound uint64     // if in `triggerRound`, `LiveEvaluation` will be triggered
-	fileRows     [][]string // fileRows returned by psi.IntersectParts
+	triggerInter uint64      // triggerInter is the number of interval rounds of triggering `LiveEvaluation`
+	triggerRound uint64      // if in `triggerRound`, `LiveEvaluation` will be triggered
+	fileRows     *spill.Rows // fileRows returned by psi.IntersectParts, spilled under memory pressure once training starts
 
 	status learnerStatusType
 
@@ -142,20 +175,30 @@ func (l *Learner) Advance(payload []byte) (*pb.TrainResponse, error) {
 }
 
 // getTrainSet returns training set after Sample Alignment
-func (l *Learner) getTrainSet() []*pbCom.TrainTaskResult_FileRow {
+func (l *Learner) getTrainSet() ([]*pbCom.TrainTaskResult_FileRow, error) {
+	fileRows, err := l.fileRows.Get()
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to read spilled training set")
+	}
 	var frs []*pbCom.TrainTaskResult_FileRow
-	for _, fr := range l.fileRows {
+	for _, fr := range fileRows {
 		frs = append(frs, &pbCom.TrainTaskResult_FileRow{Row: fr})
 	}
-	return frs
+	return frs, nil
 }
 
 // setTrainSet set training set
 func (l *Learner) setTrainSet(file []*pbCom.TrainTaskResult_FileRow) {
-	l.fileRows = make([][]string, 0, len(file))
+	fileRows := make([][]string, 0, len(file))
 	for _, r := range file {
-		l.fileRows = append(l.fileRows, r.Row)
+		fileRows = append(fileRows, r.Row)
 	}
+	l.fileRows = spill.NewRows(fileRows)
+}
+
+// Release removes the intermediate data spilled under memory pressure, called when the task finishes or is cancelled
+func (l *Learner) Release() {
+	spill.Remove(l.id)
 }
 
 // advance handles all kinds of message
@@ -171,6 +214,7 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 	var ret *pb.TrainResponse
 	switch mType {
 	case pbLinearRegVl.MessageType_MsgPsiEnc: // local message
+		tracing.StartStage(l.id, tracing.StagePSI)
 		encIDs, err := l.psi.EncryptSampleIDSet()
 		if err != nil {
 			go handleError(err)
@@ -259,10 +303,12 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 		done, newRows, _, err := l.psi.IntersectParts()
 		if err != nil {
 			go handleError(err)
+			tracing.EndStage(l.id, tracing.StagePSI, err)
 			return nil, err
 		}
 		if done {
-			l.fileRows = newRows
+			tracing.EndStage(l.id, tracing.StagePSI, nil)
+			l.fileRows = spill.NewRows(newRows)
 			l.status = learnerStatusEndPSI
 			go func() {
 				m := &pbLinearRegVl.Message{
@@ -277,22 +323,38 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 		defer l.procMutex.Unlock()
 		if learnerStatusEndPSI == l.status {
 			l.status = learnerStatusStartTrain
-			err := l.process.init(l.fileRows)
+			fileRows, err := l.fileRows.Get()
+			if err == nil {
+				err = l.process.init(fileRows)
+			}
 			if err != nil {
 				go handleError(err)
 				return nil, err
 			}
+			// the aligned samples are only needed for evaluation from now on
+			l.fileRows.Spill(l.id, "train_set")
 
 			m := &pbLinearRegVl.Message{
-				Type:       pbLinearRegVl.MessageType_MsgHomoPubkey,
-				HomoPubkey: l.homoPub,
-				LoopRound:  l.loopRound,
+				Type:         pbLinearRegVl.MessageType_MsgHomoPubkey,
+				HomoPubkey:   l.homoPub,
+				LoopRound:    l.resumeRound,
+				Checkpointed: l.checkpointed,
+				Accuracy:     l.trainParams.GetAccuracy(),
 			}
-			_, err = l.sendMessageWithRetry(m, l.parties[0])
+			reM, err := l.sendMessageWithRetry(m, l.parties[0])
 			if err != nil {
 				go handleError(err)
 				return nil, err
 			}
+			// the accuracy of tasks published without one is the default of each executor, which may differ
+			if err := crypCom.CheckAccuracyAgreed(l.trainParams.GetAccuracy(), reM.Accuracy, l.parties[0]); err != nil {
+				go handleError(err)
+				return nil, err
+			}
+			if err := l.checkResume(reM); err != nil {
+				go handleError(err)
+				return nil, err
+			}
 
 			go func() {
 				if !l.lEvaluated {
@@ -300,6 +362,9 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 						Type:      pbLinearRegVl.MessageType_MsgTrainLoop,
 						LoopRound: 0, // start Round-0
 					}
+					if l.checkpointed {
+						m.LoopRound = l.resumeRound + 1 // start the round after the checkpoint
+					}
 					l.advance(m)
 				} else {
 					cbm := &pbLinearRegVl.Message{
@@ -361,8 +426,27 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 	case pbLinearRegVl.MessageType_MsgHomoPubkey:
 		homoPubkeyOfOther := message.HomoPubkey
 		l.process.setHomoPubOfOther(homoPubkeyOfOther)
+
+		// reply with local checkpoint and accuracy, and the other party checks whether both resume from the same round
+		// with the same accuracy
+		retM := &pbLinearRegVl.Message{
+			Type:         pbLinearRegVl.MessageType_MsgHomoPubkey,
+			To:           message.From,
+			From:         l.address,
+			LoopRound:    l.resumeRound,
+			Checkpointed: l.checkpointed,
+			Accuracy:     l.trainParams.GetAccuracy(),
+		}
+		payload, err := proto.Marshal(retM)
+		if err != nil {
+			err = errorx.New(errcodes.ErrCodeInternal, "failed to Marshal payload: %s", err.Error())
+			go handleError(err)
+			return nil, err
+		}
+
 		ret = &pb.TrainResponse{
-			TaskID: l.id,
+			TaskID:  l.id,
+			Payload: payload,
 		}
 
 	case pbLinearRegVl.MessageType_MsgTrainLoop: // local message
@@ -370,7 +454,11 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 		l.procMutex.Lock()
 		defer l.procMutex.Unlock()
 		if newRound == 0 || newRound == l.loopRound+1 {
+			if newRound > 0 {
+				l.saveCheckpoint()
+			}
 			l.loopRound = newRound
+			tracing.StartStage(l.id, tracing.StageRound, attribute.Int64("round", int64(l.loopRound)))
 			err := l.process.upRound(l.loopRound)
 			if err != nil {
 				go handleError(err)
@@ -487,7 +575,7 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 	case pbLinearRegVl.MessageType_MsgTrainDecLocalGradCost: // local message
 		loopRound := message.LoopRound
 		if loopRound == l.loopRound {
-			gradBytesForOther, costBytesForOther, t, err := l.process.decGradientAndCost()
+			gradBytesForOther, costBytesForOther, residualsBytesForOther, t, err := l.process.decGradientAndCost()
 			if err != nil {
 				go handleError(err)
 				return nil, err
@@ -495,10 +583,11 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 
 			if t == 1 {
 				m := &pbLinearRegVl.Message{
-					Type:      pbLinearRegVl.MessageType_MsgTrainGradAndCost,
-					GradBytes: gradBytesForOther,
-					CostBytes: costBytesForOther,
-					LoopRound: loopRound,
+					Type:           pbLinearRegVl.MessageType_MsgTrainGradAndCost,
+					GradBytes:      gradBytesForOther,
+					CostBytes:      costBytesForOther,
+					ResidualsBytes: residualsBytesForOther,
+					LoopRound:      loopRound,
 				}
 				_, err = l.sendMessageWithRetry(m, l.parties[0])
 				if err != nil {
@@ -513,7 +602,7 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 		gradBytesFromOther := message.GradBytes
 		costBytesFromOther := message.CostBytes
 		if loopRound == l.loopRound {
-			t := l.process.SetGradientAndCostFromOther(gradBytesFromOther, costBytesFromOther)
+			t := l.process.SetGradientAndCostFromOther(gradBytesFromOther, costBytesFromOther, message.ResidualsBytes)
 			if t == 1 {
 				go func() {
 					m := &pbLinearRegVl.Message{
@@ -522,6 +611,16 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 					}
 					l.advance(m)
 				}()
+				// the party waiting for its residuals decrypts the gradients and costs of the other party now
+				if l.process.waitsResiduals() {
+					go func() {
+						m := &pbLinearRegVl.Message{
+							Type:      pbLinearRegVl.MessageType_MsgTrainDecLocalGradCost,
+							LoopRound: loopRound,
+						}
+						l.advance(m)
+					}()
+				}
 			}
 		}
 		ret = &pb.TrainResponse{
@@ -531,6 +630,9 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 	case pbLinearRegVl.MessageType_MsgTrainUpdCostGrad: // local message
 		loopRound := message.LoopRound
 		if loopRound == l.loopRound {
+			if es, ok := l.lEvaluator.(EarlyStopper); ok && es.EarlyStopped() {
+				l.process.setEarlyStopped()
+			}
 			stopped, err := l.process.updateCostAndGradient()
 			if err != nil {
 				go handleError(err)
@@ -655,6 +757,7 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 		}
 
 	case pbLinearRegVl.MessageType_MsgTrainModels: // local message
+		tracing.EndStage(l.id, tracing.StageRound, nil)
 		l.procMutex.Lock()
 		defer l.procMutex.Unlock()
 		if learnerStatusStartTrain == l.status {
@@ -664,14 +767,23 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 				go handleError(err)
 				return nil, err
 			}
+			trainSet, err := l.getTrainSet()
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
 			logger.WithField("loopRound", l.loopRound).Infof("learner[%s] trained out model[%v] successfully.", l.id, model)
 			res := &pbCom.TrainTaskResult{
-				TaskID:   l.id,
-				Success:  true,
-				Model:    model,
-				TrainSet: l.getTrainSet(),
+				TaskID:      l.id,
+				Success:     true,
+				Model:       model,
+				TrainSet:    trainSet,
+				MetricDelta:   l.process.metricDelta(),
+				PrivacyBudget: l.process.privacyBudget(),
+				EarlyStop:     l.earlyStopResult(),
 			}
 			l.rh.SaveResult(res)
+			l.deleteCheckpoint()
 		}
 	case pbLinearRegVl.MessageType_MsgCheckPauseRound: // local message
 		loopRound := message.LoopRound
@@ -685,12 +797,17 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 				go handleError(err)
 				return nil, err
 			}
+			trainSet, err := l.getTrainSet()
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
 			logger.WithField("loopRound", l.loopRound).Infof("learner[%s] trained out a staged model[%v] at the pause round[%d].", l.id, model, l.pauseRound)
 			res := &pbCom.TrainTaskResult{
 				TaskID:   l.id,
 				Success:  true,
 				Model:    model,
-				TrainSet: l.getTrainSet(),
+				TrainSet: trainSet,
 			}
 			l.rh.SaveResult(res)
 		} else {
@@ -713,6 +830,79 @@ func (l *Learner) advance(message *pbLinearRegVl.Message) (*pb.TrainResponse, er
 	return ret, nil
 }
 
+// saveCheckpoint persists the state of finished round l.loopRound when the checkpoint interval is reached,
+// and training goes on even if the checkpoint fails to be saved
+func (l *Learner) saveCheckpoint() {
+	if l.cp == nil || (l.loopRound+1)%l.cp.Interval() != 0 {
+		return
+	}
+	// the checkpoint which the learner resumes from is unchanged
+	if l.checkpointed && l.loopRound == l.resumeRound {
+		return
+	}
+
+	data, err := l.process.checkpoint()
+	if err == nil {
+		err = l.cp.SaveCheckpoint(data)
+	}
+	if err != nil {
+		logger.WithField("loopRound", l.loopRound).Warnf("learner[%s] failed to save checkpoint: %s", l.id, err.Error())
+		return
+	}
+	l.cpExists = true
+	logger.WithField("loopRound", l.loopRound).Infof("learner[%s] saved checkpoint of round[%d].", l.id, l.loopRound)
+}
+
+// deleteCheckpoint removes the checkpoint of the task if it exists
+func (l *Learner) deleteCheckpoint() {
+	if l.cp == nil || !l.cpExists {
+		return
+	}
+	if err := l.cp.DeleteCheckpoint(); err != nil {
+		logger.WithField("loopRound", l.loopRound).Warnf("learner[%s] failed to delete checkpoint: %s", l.id, err.Error())
+		return
+	}
+	l.cpExists = false
+}
+
+// checkResume checks whether both parties resume from the checkpoints of the same round, or both start from Round-0,
+// other is the reply of the other party to MsgHomoPubkey.
+// If not, the training fails and local checkpoint is deleted, so that the task starts from Round-0 when it is started again
+func (l *Learner) checkResume(other *pbLinearRegVl.Message) error {
+	if l.checkpointed == other.Checkpointed && l.resumeRound == other.LoopRound {
+		if l.checkpointed {
+			logger.WithField("loopRound", l.loopRound).Infof("learner[%s] resumes from the checkpoint of round[%d] together with remote learner[%s].", l.id, l.resumeRound, l.parties[0])
+		}
+		return nil
+	}
+
+	l.deleteCheckpoint()
+	return errorx.New(errcodes.ErrCodeParam, "failed to resume training, local learner %s, but remote learner[%s] %s",
+		resumeState(l.checkpointed, l.resumeRound), l.parties[0], resumeState(other.Checkpointed, other.LoopRound))
+}
+
+// resumeState describes the checkpoint a learner resumes from
+func resumeState(checkpointed bool, round uint64) string {
+	if checkpointed {
+		return fmt.Sprintf("resumes from the checkpoint of round[%d]", round)
+	}
+	return "has no checkpoint"
+}
+
+// earlyStopResult returns the metric tracked by early stopping with the round the training stops at,
+// nil if early stopping is disabled or the metric isn't evaluated locally
+func (l *Learner) earlyStopResult() *pbCom.EarlyStopResult {
+	es, ok := l.lEvaluator.(EarlyStopper)
+	if !ok {
+		return nil
+	}
+	result := es.EarlyStopResult()
+	if result != nil && result.Stopped {
+		result.StoppedRound = l.loopRound
+	}
+	return result
+}
+
 // triggerLiveEvaluation packs message and trigger `LiveEvaluation`
 func (l *Learner) triggerLiveEvaluation(msgType pb.TriggerMsgType, callbackMsg *pbLinearRegVl.Message, forward *pbLinearRegVl.Message) error {
 	callbackPayload, err := proto.Marshal(callbackMsg)
@@ -733,7 +923,9 @@ func (l *Learner) triggerLiveEvaluation(msgType pb.TriggerMsgType, callbackMsg *
 		Payload:         payload,
 	}
 	if msgType == pb.TriggerMsgType_MsgSetAndRun {
-		m.TrainSet = l.getTrainSet()
+		if m.TrainSet, err = l.getTrainSet(); err != nil {
+			return err
+		}
 	}
 
 	err = l.lEvaluator.Trigger(m)
@@ -799,6 +991,33 @@ func (l *Learner) sendMessage(message *pbLinearRegVl.Message, address string) (*
 	return m, nil
 }
 
+// loadCheckpoint restores the process from the last checkpoint if there is one,
+// a broken checkpoint is deleted and the training starts from Round-0
+func (l *Learner) loadCheckpoint() error {
+	if l.cp == nil {
+		return nil
+	}
+	data, err := l.cp.LoadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	l.cpExists = true
+	if err := l.process.restore(data); err != nil {
+		logger.Warnf("learner[%s] deleted the broken checkpoint: %s", l.id, err.Error())
+		l.deleteCheckpoint()
+		return nil
+	}
+	l.loopRound = l.process.round
+	l.resumeRound = l.process.round
+	l.checkpointed = true
+	logger.WithField("loopRound", l.loopRound).Infof("learner[%s] found checkpoint of round[%d].", l.id, l.resumeRound)
+	return nil
+}
+
 // NewLearner returns a VerticalLinearRegression Learner
 // id is the assigned id for Learner
 // address indicates local mpc-node
@@ -808,10 +1027,11 @@ func (l *Learner) sendMessage(message *pbLinearRegVl.Message, address string) (*
 // params are parameters for training model
 // samplesFile contains samples for training model
 // le is an LiveEvaluator, and LiveEvaluation will be performed by learner if it is assigned without nil
+// cp persists checkpoints of the training, and the learner resumes from the last checkpoint if there is one
 func NewLearner(id string, address string, params *pbCom.TrainParams, samplesFile []byte,
-	parties []string, rpc RpcHandler, rh ResultHandler, le LiveEvaluator) (*Learner, error) {
+	parties []string, rpc RpcHandler, rh ResultHandler, le LiveEvaluator, cp Checkpointer) (*Learner, error) {
 
-	p, err := psi.NewVLTwoPartsPSI(address, samplesFile, params.GetIdName(), parties)
+	p, err := psi.NewVLTwoPartsPSIWithAlignment(params.GetPsiAlgorithm(), params.GetAlignmentKey(), address, samplesFile, params.GetIdName(), parties)
 	if err != nil {
 		return nil, err
 	}
@@ -835,6 +1055,10 @@ func NewLearner(id string, address string, params *pbCom.TrainParams, samplesFil
 		rpc:         rpc,
 		rh:          rh,
 		status:      learnerStatusStartPSI,
+		cp:          cp,
+	}
+	if err := l.loadCheckpoint(); err != nil {
+		return nil, err
 	}
 	if le != nil {
 		l.lEvaluated = true
@@ -131,14 +131,14 @@ func TestAdvance(t *testing.T) {
 
 	// test starts
 	go func() {
-		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, nil)
+		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, nil, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
 		}
 	}()
 	go func() {
-		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, nil)
+		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, nil, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -430,7 +430,7 @@ func TestAdvanceLiveEvaluation(t *testing.T) {
 
 	// test starts
 	go func() {
-		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, le1)
+		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, le1, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -438,7 +438,7 @@ func TestAdvanceLiveEvaluation(t *testing.T) {
 		le1.learnerEvaluated = learner1
 	}()
 	go func() {
-		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, le2)
+		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, le2, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -578,6 +578,83 @@ func TestAdvanceLiveEvaluation(t *testing.T) {
 	}
 }
 
+type checkpointer struct {
+	interval uint64
+	data     []byte
+	saved    int
+}
+
+func (c *checkpointer) Interval() uint64 { return c.interval }
+
+func (c *checkpointer) SaveCheckpoint(data []byte) error {
+	c.data = data
+	c.saved++
+	return nil
+}
+
+func (c *checkpointer) LoadCheckpoint() ([]byte, error) { return c.data, nil }
+
+func (c *checkpointer) DeleteCheckpoint() error {
+	c.data = nil
+	return nil
+}
+
+func TestCheckpoint(t *testing.T) {
+	params := &pbCom.TrainParams{Label: "MEDV", IdName: "id", BatchSize: 4}
+	cp := &checkpointer{interval: 2}
+	l := &Learner{id: "test-learner-1", parties: []string{"127.0.0.1:8081"}, process: newProcess(nil, params), cp: cp}
+
+	// checkpoints are saved after round 1 and round 3
+	for round := uint64(0); round < 4; round++ {
+		l.loopRound = round
+		l.process.round = round
+		l.process.nextThetas = []float64{float64(round), 0.5}
+		l.process.cost = float64(round) / 10
+		l.saveCheckpoint()
+	}
+	if cp.saved != 2 || !l.cpExists {
+		t.Fatalf("expected 2 checkpoints saved, got %d", cp.saved)
+	}
+
+	resumed := &Learner{id: "test-learner-1", parties: []string{"127.0.0.1:8081"}, process: newProcess(nil, params), cp: cp}
+	if err := resumed.loadCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.checkpointed || resumed.resumeRound != 3 || resumed.loopRound != 3 {
+		t.Fatalf("expected to resume from round 3, got checkpointed[%t] round[%d]", resumed.checkpointed, resumed.resumeRound)
+	}
+	if p := resumed.process; p.cost != 0.3 || len(p.nextThetas) != 2 || p.nextThetas[0] != 3 {
+		t.Errorf("unexpected process restored, cost[%v] nextThetas[%v]", p.cost, p.nextThetas)
+	}
+
+	// the checkpoint resumed from is not saved again
+	resumed.saveCheckpoint()
+	if cp.saved != 2 {
+		t.Errorf("checkpoint of round 3 saved again")
+	}
+
+	// both parties resume from round 3
+	if err := resumed.checkResume(&pbLinearRegVl.Message{LoopRound: 3, Checkpointed: true}); err != nil {
+		t.Errorf("expected to resume, got: %v", err)
+	}
+	// the other party has no checkpoint, then local checkpoint is deleted
+	if err := resumed.checkResume(&pbLinearRegVl.Message{}); err == nil {
+		t.Error("expected error when the other party has no checkpoint")
+	}
+	if cp.data != nil {
+		t.Error("local checkpoint is not deleted")
+	}
+
+	// neither party has checkpoint
+	fresh := &Learner{id: "test-learner-1", parties: []string{"127.0.0.1:8081"}, process: newProcess(nil, params), cp: cp}
+	if err := fresh.loadCheckpoint(); err != nil || fresh.checkpointed {
+		t.Fatalf("expected no checkpoint, got checkpointed[%t] err[%v]", fresh.checkpointed, err)
+	}
+	if err := fresh.checkResume(&pbLinearRegVl.Message{}); err != nil {
+		t.Errorf("expected to start from Round-0, got: %v", err)
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
@@ -14,6 +14,7 @@
 package linear_reg_vl
 
 import (
+	"encoding/json"
 	"math/big"
 	"sync"
 
@@ -35,6 +36,7 @@ type process struct {
 	fileRows [][]string // file rows obtained from sample file
 
 	trainDataSet   *mlCom.TrainDataSet // own data set for training, formatted from filesRows
+	sparseSet      *vlCom.SparseTrainSet // own sparse data set for training instead of trainDataSet, see vlCom.UseSparseTrainSet
 	homoPubOfOther []byte // public key of other part
 
 	mutex sync.Mutex
@@ -52,6 +54,11 @@ type process struct {
 	gradBytesForOther, gradBytesFromOther []byte
 	costBytesForOther, costBytesFromOther []byte
 
+	// for the losses other than squared error, the party with label retrieves the residuals of samples of this round
+	// from its gradients decrypted by the other party, and sends the pseudo residuals to the other party
+	residuals                                       map[int]float64
+	residualsBytesForOther, residualsBytesFromOther []byte
+
 	stopped      int8 // 0 means not decided, 1 means received `Stopped`, 2 means received `NotStopped`
 	otherStopped int8 // 0 means not received decision, 1 means received `Stopped`, 2 means received `NotStopped`
 
@@ -63,6 +70,32 @@ type process struct {
 
 	// cache intermediate result for next round
 	partBytesFromOtherNextRound []byte
+
+	resumed bool // resumed means the process is restored from a checkpoint
+
+	// baseCost is the cost of the base model on the samples in the first round of incremental training
+	baseCost float64
+
+	// categories are the encodings of the categorical columns in fileRows
+	categories []*pbCom.CategoryMapping
+
+	// weights are the weights of samples from the weight column on the party with label, nil if samples are equally weighted
+	weights map[int]float64
+
+	// earlyStopped means the metric of live evaluation has stopped improving, then the training stops
+	earlyStopped bool
+
+	// lossyWarned means the warning that values lose digits of the accuracy in the fixed-point encoding is logged
+	lossyWarned bool
+}
+
+// checkpoint is the state of process persisted at the end of a round,
+// the training resumes from the next round with NextThetas
+type checkpoint struct {
+	Round      uint64
+	Cost       float64
+	NextThetas []float64
+	BaseCost   float64 `json:",omitempty"`
 }
 
 // init initialize Process, after PSI, before training
@@ -70,8 +103,31 @@ func (p *process) init(fileRows [][]string) error {
 	// fileRows
 	p.fileRows = fileRows
 
+	// sparse samples are trained with the gradients of their non-zero features
+	if vlCom.UseSparseTrainSet(fileRows, *p.params) {
+		return p.initSparse()
+	}
+
+	// convert sparse rows into dense ones and encode categorical columns, fileRows is kept as it is for evaluation
+	rows, err := vlCom.DensifyRows(p.fileRows)
+	if err != nil {
+		return err
+	}
+	rows, categories, err := vlCom.EncodeCategorical(rows, p.params)
+	if err != nil {
+		return err
+	}
+	p.categories = categories
+
+	// the weight column is removed from the samples to train, its weights scale the gradients and costs of samples
+	if p.params.IsTagPart && p.params.WeightColumn != "" {
+		if rows, p.weights, err = vlCom.ExtractWeights(rows, p.params.WeightColumn); err != nil {
+			return err
+		}
+	}
+
 	// resolve data set from fileRows
-	trainDataSet, err := linear.GetTrainDataSetFromFile(p.fileRows, *p.params)
+	trainDataSet, err := linear.GetTrainDataSetFromFile(rows, *p.params)
 
 	if err != nil {
 		return errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl GetTrainDataSetFromFile", err.Error())
@@ -81,8 +137,46 @@ func (p *process) init(fileRows [][]string) error {
 
 	// init thetas
 	thetas := linear.InitThetas(trainDataSet, *p.params)
+	// incremental training starts from the thetas of the base model
+	if p.params.BaseModel != nil {
+		thetas, err = vlCom.ThetasFromModel(p.params.BaseModel, trainDataSet, p.params.IsTagPart)
+		if err != nil {
+			return err
+		}
+	}
 	p.thetas = thetas
 
+	// replay the reordering of samples in the rounds before the checkpoint,
+	// so that the batches of the following rounds are the same as those of an uninterrupted training
+	if p.resumed {
+		for r := 0; r <= int(p.round); r++ {
+			_, p.trainDataSet.TrainSet = vlCom.GetBatchSetBySize(p.trainDataSet.TrainSet, *p.params, r, true)
+		}
+	}
+
+	return nil
+}
+
+// initSparse initializes Process with the sparse data set
+func (p *process) initSparse() error {
+	sparseSet, err := linear.GetSparseTrainSetFromFile(p.fileRows, *p.params)
+	if err != nil {
+		return errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl GetSparseTrainSetFromFile", err.Error())
+	}
+	p.sparseSet = sparseSet
+
+	p.thetas = linear.InitSparseThetas(sparseSet)
+	if p.params.BaseModel != nil {
+		if p.thetas, err = vlCom.ThetasFromModel(p.params.BaseModel, sparseSet.TrainDataSet, p.params.IsTagPart); err != nil {
+			return err
+		}
+	}
+
+	if p.resumed {
+		for r := 0; r <= int(p.round); r++ {
+			vlCom.GetSparseBatchBySize(sparseSet.Samples, *p.params, r, true)
+		}
+	}
 	return nil
 }
 
@@ -130,6 +224,10 @@ func (p *process) upRound(newRound uint64) error {
 	p.costBytesForOther = []byte{}
 	p.costBytesFromOther = []byte{}
 
+	p.residuals = nil
+	p.residualsBytesForOther = []byte{}
+	p.residualsBytesFromOther = []byte{}
+
 	p.stopped = 0
 	p.otherStopped = 0
 
@@ -150,13 +248,25 @@ func (p *process) calLocalGradientAndCost() ([]byte, int, error) {
 		return p.partBytesForOther, p.calLocalGradientAndCostTimes, nil
 	}
 
-	rawPart, otherPartBytes, newSet, err := linear.CalLocalGradientAndCost(p.trainDataSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+	var rawPart *linearVert.RawLocalGradientPart
+	var otherPartBytes []byte
+	var err error
+	if p.sparseSet != nil {
+		rawPart, otherPartBytes, err = linear.CalSparseLocalGradientAndCost(p.sparseSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+	} else {
+		if err := p.checkEncodingRange(); err != nil {
+			return []byte{}, p.calLocalGradientAndCostTimes, err
+		}
+		var newSet [][]float64
+		rawPart, otherPartBytes, newSet, err = linear.CalLocalGradientAndCost(p.trainDataSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+		if err == nil {
+			p.trainDataSet.TrainSet = newSet
+		}
+	}
 	if err != nil {
 		return []byte{}, p.calLocalGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl calLocalGradientAndCost", err.Error())
 	}
 
-	p.trainDataSet.TrainSet = newSet
-
 	p.rawPart = rawPart
 	p.partBytesForOther = otherPartBytes
 
@@ -165,6 +275,22 @@ func (p *process) calLocalGradientAndCost() ([]byte, int, error) {
 	return p.partBytesForOther, p.calLocalGradientAndCostTimes, nil
 }
 
+// checkEncodingRange checks the values encoded as fixed-point integers in this round don't overflow the accuracy,
+// the warning that they lose digits of the accuracy is logged once, as they grow with thetas usually
+func (p *process) checkEncodingRange() error {
+	magnitude := vlCom.EncodedMagnitude(p.trainDataSet.TrainSet, p.thetas, p.params.IsTagPart)
+	lossy, err := vlCom.CheckEncodingRange(magnitude, p.params.Accuracy)
+	if err != nil {
+		return err
+	}
+	if lossy && !p.lossyWarned {
+		p.lossyWarned = true
+		logger.Warnf("values of magnitude %g lose digits of accuracy %d in the fixed-point encoding at round %d, "+
+			"they approach the range of the encoding, lower the accuracy or scale the features", magnitude, p.params.Accuracy, p.round)
+	}
+	return nil
+}
+
 func (p *process) setPartBytesFromOther(partBytesFromOther []byte, round uint64) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -197,7 +323,15 @@ func (p *process) calEncGradientAndCost() ([]byte, []byte, int, error) {
 		return []byte{}, []byte{}, p.calEncGradientAndCostTimes, nil
 	}
 
-	encGradForOther, encCostForOther, gradientNoise, costNoise, err := linear.CalEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.trainDataSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	var encGradForOther, encCostForOther []byte
+	var gradientNoise []*big.Int
+	var costNoise *big.Int
+	var err error
+	if p.sparseSet != nil {
+		encGradForOther, encCostForOther, gradientNoise, costNoise, err = linear.CalSparseEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.sparseSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	} else {
+		encGradForOther, encCostForOther, gradientNoise, costNoise, err = linear.CalEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.trainDataSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	}
 	if err != nil {
 		return []byte{}, []byte{}, p.calEncGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl calEncGradientAndCost", err.Error())
 	}
@@ -228,19 +362,54 @@ func (p *process) setEncGradientAndCostFromOther(encGradFromOther, encCostFromOt
 	return p.setEncGradientAndCostFromOtherTimes
 }
 
-func (p *process) decGradientAndCost() ([]byte, []byte, int, error) {
+// waitsResiduals returns whether the party decrypts the gradients and costs of the other party after its own ones
+// are decrypted, which is the case of the party with label if the loss isn't squared error, as it retrieves
+// the residuals from its own gradients to correct the costs of the other party
+func (p *process) waitsResiduals() bool {
+	return p.params.IsTagPart && !vlCom.IsSquaredLoss(p.params.Loss)
+}
+
+// localResiduals returns the residuals of samples of this round on the party with label, called with mutex held
+func (p *process) localResiduals() (map[int]float64, error) {
+	if p.residuals == nil {
+		residuals, err := linear.Residuals(p.gradBytesFromOther, p.gradientNoise, *p.params)
+		if err != nil {
+			return nil, err
+		}
+		p.residuals = residuals
+	}
+	return p.residuals, nil
+}
+
+func (p *process) decGradientAndCost() ([]byte, []byte, []byte, int, error) {
 
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	if p.decGradientAndCostTimes != 0 {
 		p.decGradientAndCostTimes++
-		return p.gradBytesForOther, p.costBytesForOther, p.decGradientAndCostTimes, nil
+		return p.gradBytesForOther, p.costBytesForOther, p.residualsBytesForOther, p.decGradientAndCostTimes, nil
+	}
+
+	var residuals map[int]float64
+	if p.waitsResiduals() {
+		if len(p.encGradFromOther) == 0 || len(p.gradBytesFromOther) == 0 {
+			return []byte{}, []byte{}, []byte{}, p.decGradientAndCostTimes, nil
+		}
+		var err error
+		if residuals, err = p.localResiduals(); err != nil {
+			return []byte{}, []byte{}, []byte{}, p.decGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl retrieve residuals", err.Error())
+		}
+		residualsBytesForOther, err := vlCom.ResidualsToBytes(linear.PseudoResiduals(residuals, p.weights, *p.params))
+		if err != nil {
+			return []byte{}, []byte{}, []byte{}, p.decGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl residualsToBytes", err.Error())
+		}
+		p.residualsBytesForOther = residualsBytesForOther
 	}
 
-	gradBytesForOther, costBytesForOther, err := linear.DecGradientAndCost(p.encGradFromOther, p.encCostFromOther, p.homoPriv)
+	gradBytesForOther, costBytesForOther, err := linear.DecGradientAndCost(p.encGradFromOther, p.encCostFromOther, p.homoPriv, p.weights, residuals, *p.params)
 	if err != nil {
-		return []byte{}, []byte{}, p.decGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl decGradientAndCost", err.Error())
+		return []byte{}, []byte{}, []byte{}, p.decGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl decGradientAndCost", err.Error())
 	}
 
 	p.gradBytesForOther = gradBytesForOther
@@ -248,10 +417,12 @@ func (p *process) decGradientAndCost() ([]byte, []byte, int, error) {
 
 	p.decGradientAndCostTimes++
 
-	return p.gradBytesForOther, p.costBytesForOther, p.decGradientAndCostTimes, nil
+	return p.gradBytesForOther, p.costBytesForOther, p.residualsBytesForOther, p.decGradientAndCostTimes, nil
 }
 
-func (p *process) SetGradientAndCostFromOther(gradBytesFromOther, costBytesFromOther []byte) int {
+// SetGradientAndCostFromOther saves the gradients and costs decrypted by the other party,
+// residualsBytesFromOther is the pseudo residuals sent by the party with label if the loss isn't squared error
+func (p *process) SetGradientAndCostFromOther(gradBytesFromOther, costBytesFromOther, residualsBytesFromOther []byte) int {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -261,12 +432,40 @@ func (p *process) SetGradientAndCostFromOther(gradBytesFromOther, costBytesFromO
 	}
 	p.gradBytesFromOther = gradBytesFromOther
 	p.costBytesFromOther = costBytesFromOther
+	p.residualsBytesFromOther = residualsBytesFromOther
 
 	p.setGradientAndCostFromOtherTimes++
 
 	return p.setGradientAndCostFromOtherTimes
 }
 
+// updateGradientByResiduals updates thetas by the pseudo residuals of the loss other than squared error,
+// which the party with label derives from its residuals and the other party receives from the party with label,
+// the residuals are returned on the party with label to correct its cost
+func (p *process) updateGradientByResiduals() ([]float64, map[int]float64, error) {
+	var residuals, pseudoResiduals map[int]float64
+	var err error
+	if p.params.IsTagPart {
+		if residuals, err = p.localResiduals(); err != nil {
+			return nil, nil, err
+		}
+		pseudoResiduals = linear.PseudoResiduals(residuals, p.weights, *p.params)
+	} else if pseudoResiduals, err = vlCom.ResidualsFromBytes(p.residualsBytesFromOther); err != nil {
+		return nil, nil, err
+	}
+
+	var nextThetas []float64
+	if p.sparseSet != nil {
+		nextThetas, err = linear.UpdateSparseGradientByResiduals(pseudoResiduals, p.sparseSet, p.thetas, *p.params)
+	} else {
+		nextThetas, err = linear.UpdateGradientByResiduals(pseudoResiduals, p.trainDataSet, p.thetas, *p.params)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return nextThetas, residuals, nil
+}
+
 func (p *process) updateCostAndGradient() (bool, error) {
 
 	var stopped bool
@@ -285,20 +484,55 @@ func (p *process) updateCostAndGradient() (bool, error) {
 		logger.Panicf("gradBytesFromOther is [%v], gradientNoise is [%v], thetas is [%v], round [%v]", p.gradBytesFromOther, p.gradientNoise, p.thetas, p.round)
 	}
 
-	nextThetas, err := linear.UpdateGradient(p.gradBytesFromOther, p.gradientNoise, p.thetas, *p.params)
+	var nextThetas []float64
+	var residuals map[int]float64
+	var err error
+	if vlCom.IsSquaredLoss(p.params.Loss) && p.sparseSet != nil {
+		nextThetas, err = linear.UpdateSparseGradient(p.gradBytesFromOther, p.gradientNoise, p.sparseSet, p.thetas, *p.params, int(p.round))
+	} else if vlCom.IsSquaredLoss(p.params.Loss) {
+		nextThetas, err = linear.UpdateGradient(p.gradBytesFromOther, p.gradientNoise, p.weights, p.thetas, *p.params)
+	} else {
+		nextThetas, residuals, err = p.updateGradientByResiduals()
+	}
 	if err != nil {
 		return stopped, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl updateGradient", err.Error())
 	}
 	p.nextThetas = nextThetas
 
-	if p.round > 0 {
-		cost, err := linear.UpdateCost(p.costBytesFromOther, p.costNoise, *p.params)
+	// the cost of the first round of incremental training is the cost of the base model,
+	// and the one of the dry run is the cost of the initial thetas the dry run checks divergence against
+	if p.round > 0 || p.params.Incremental || p.params.DryRun != nil {
+		cost, err := linear.UpdateCost(p.costBytesFromOther, p.costNoise, p.weights, residuals, *p.params)
 		if err != nil {
 			return stopped, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl updateCost", err.Error())
 		}
 
 		p.cost = cost
-		stopped = linear.StopTraining(p.lastCost, p.cost, *p.params)
+		if p.round == 0 {
+			p.baseCost = cost
+		} else {
+			stopped = linear.StopTraining(p.lastCost, p.cost, *p.params)
+		}
+	}
+	// incremental training runs updateRounds rounds at most
+	if p.params.Incremental && p.params.UpdateRounds > 0 && p.round+1 >= uint64(p.params.UpdateRounds) {
+		stopped = true
+	}
+	// the dry run fails once the cost or thetas go wrong, and stops after its iterations
+	if p.params.DryRun != nil {
+		if err := vlCom.CheckDryRunCost(p.baseCost, p.cost, p.nextThetas); err != nil {
+			return stopped, err
+		}
+		if p.round+1 >= uint64(vlCom.DryRunIterations(p.params.DryRun)) {
+			stopped = true
+		}
+	}
+	// differentially private training stops when the privacy budget is used up
+	if p.params.Dp != nil && p.round+1 >= uint64(p.params.Dp.Rounds) {
+		stopped = true
+	}
+	if p.earlyStopped {
+		stopped = true
 	}
 	if stopped {
 		p.stopped = 1
@@ -338,14 +572,31 @@ func (p *process) stop() (decided bool, stopped bool) {
 		if p.otherStopped == 1 && p.stopped == 1 {
 			stopped = true
 		}
+		// only the party with label evaluates the metric of early stopping, the others stop along with it
+		if p.params.EarlyStopping != nil && (p.otherStopped == 1 || p.stopped == 1) {
+			stopped = true
+		}
 	}
 
 	return
 }
 
+// setEarlyStopped makes the training stop in the current round, as the metric of live evaluation stops improving
+func (p *process) setEarlyStopped() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.earlyStopped = true
+}
+
 // getTrainModels retrieve own model
 func (p *process) getTrainModels() ([]byte, error) {
-	modelBytes, err := vlCom.TrainModelsToBytes(p.thetas, p.trainDataSet, *p.params)
+	var modelBytes []byte
+	var err error
+	if p.sparseSet != nil {
+		modelBytes, err = vlCom.SparseTrainModelsToBytes(p.thetas, p.sparseSet, *p.params)
+	} else {
+		modelBytes, err = vlCom.TrainModelsToBytes(p.thetas, p.trainDataSet, *p.params, p.categories)
+	}
 	if err != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when linear_reg_vl trainModelsToBytes", err.Error())
 	}
@@ -353,6 +604,61 @@ func (p *process) getTrainModels() ([]byte, error) {
 	return modelBytes, nil
 }
 
+// metricDelta returns the cost of the trained model against the one of the base model,
+// nil if it isn't incremental training
+func (p *process) metricDelta() *pbCom.MetricDelta {
+	if !p.params.Incremental {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return &pbCom.MetricDelta{Metric: "cost", Base: p.baseCost, Updated: p.cost}
+}
+
+// privacyBudget returns the differential privacy budget consumed by the rounds trained,
+// nil if differential privacy is disabled
+func (p *process) privacyBudget() *pbCom.PrivacyBudget {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return vlCom.ConsumedPrivacyBudget(p.params.Dp, int64(p.round)+1)
+}
+
+// checkpoint returns the state of current round, called after the round finished
+func (p *process) checkpoint() ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cp := checkpoint{
+		Round:      p.round,
+		Cost:       p.cost,
+		NextThetas: p.nextThetas,
+		BaseCost:   p.baseCost,
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "failed to marshal checkpoint: %s", err.Error())
+	}
+	return data, nil
+}
+
+// restore restores the state of process from a checkpoint, called before init
+func (p *process) restore(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return errorx.New(errcodes.ErrCodeParam, "failed to unmarshal checkpoint: %s", err.Error())
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.round = cp.Round
+	p.cost = cp.Cost
+	p.nextThetas = cp.NextThetas
+	p.baseCost = cp.BaseCost
+	p.resumed = true
+	return nil
+}
+
 // setHomoPubOfOther save homomorphic public key from other party, used for secret transmission
 func (p *process) setHomoPubOfOther(homoPubOfOther []byte) {
 	p.homoPubOfOther = homoPubOfOther
@@ -133,14 +133,14 @@ func TestAdvance(t *testing.T) {
 
 	// test starts
 	go func() {
-		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, nil)
+		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, nil, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
 		}
 	}()
 	go func() {
-		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, nil)
+		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, nil, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -432,7 +432,7 @@ func TestAdvanceLiveEvaluation(t *testing.T) {
 
 	// test starts
 	go func() {
-		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, le1)
+		learner1, err = NewLearner(id1, address1, params1, samplesFile1, parties1, rpc1, rh1, le1, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -440,7 +440,7 @@ func TestAdvanceLiveEvaluation(t *testing.T) {
 		le1.learnerEvaluated = learner1
 	}()
 	go func() {
-		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, le2)
+		learner2, err = NewLearner(id2, address2, params2, samplesFile2, parties2, rpc2, rh2, le2, nil)
 		if err != nil {
 			t.Error(err)
 			t.FailNow()
@@ -14,6 +14,7 @@
 package logic_reg_vl
 
 import (
+	"encoding/json"
 	"math/big"
 	"sync"
 
@@ -35,6 +36,7 @@ type process struct {
 	fileRows [][]string // file rows obtained from sample file
 
 	trainDataSet   *mlCom.TrainDataSet // own data set for training, formatted from filesRow
+	sparseSet      *vlCom.SparseTrainSet // own sparse data set for training instead of trainDataSet, see vlCom.UseSparseTrainSet
 	homoPubOfOther []byte // public key of other part
 
 	mutex sync.Mutex
@@ -63,6 +65,37 @@ type process struct {
 
 	// cache intermediate result for next round
 	partBytesFromOtherNextRound []byte
+
+	resumed bool // resumed means the process is restored from a checkpoint
+
+	// baseCost is the cost of the base model on the samples in the first round of incremental training
+	baseCost float64
+
+	// categories are the encodings of the categorical columns in fileRows
+	categories []*pbCom.CategoryMapping
+
+	// weights are the weights of samples from the weight column on the party with label, nil if samples are equally weighted
+	weights map[int]float64
+
+	// earlyStopped means the metric of live evaluation has stopped improving, then the training stops
+	earlyStopped bool
+
+	// lossyWarned means the warning that values lose digits of the accuracy in the fixed-point encoding is logged
+	lossyWarned bool
+
+	// optimizer updates thetas with the gradients by the optimizer and learning rate schedule of the task
+	optimizer *vlCom.Optimizer
+}
+
+// checkpoint is the state of process persisted at the end of a round,
+// the training resumes from the next round with NextThetas
+type checkpoint struct {
+	Round      uint64
+	Cost       float64
+	NextThetas []float64
+	BaseCost   float64 `json:",omitempty"`
+	// Optimizer is the state of the optimizer after the round
+	Optimizer vlCom.OptimizerState
 }
 
 // init initialize Process, after PSI, before training
@@ -70,8 +103,31 @@ func (p *process) init(fileRows [][]string) error {
 	// fileRows
 	p.fileRows = fileRows
 
+	// sparse samples are trained with the gradients of their non-zero features
+	if vlCom.UseSparseTrainSet(fileRows, *p.params) {
+		return p.initSparse()
+	}
+
+	// convert sparse rows into dense ones and encode categorical columns, fileRows is kept as it is for evaluation
+	rows, err := vlCom.DensifyRows(p.fileRows)
+	if err != nil {
+		return err
+	}
+	rows, categories, err := vlCom.EncodeCategorical(rows, p.params)
+	if err != nil {
+		return err
+	}
+	p.categories = categories
+
+	// the weight column is removed from the samples to train, its weights scale the gradients and costs of samples
+	if p.params.IsTagPart && p.params.WeightColumn != "" {
+		if rows, p.weights, err = vlCom.ExtractWeights(rows, p.params.WeightColumn); err != nil {
+			return err
+		}
+	}
+
 	// resolve data set from fileRows
-	trainDataSet, err := logic.GetTrainDataSetFromFile(p.fileRows, *p.params)
+	trainDataSet, err := logic.GetTrainDataSetFromFile(rows, *p.params)
 
 	if err != nil {
 		return errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl GetTrainDataSetFromFile", err.Error())
@@ -81,8 +137,46 @@ func (p *process) init(fileRows [][]string) error {
 
 	// init thetas
 	thetas := logic.InitThetas(trainDataSet, *p.params)
+	// incremental training starts from the thetas of the base model
+	if p.params.BaseModel != nil {
+		thetas, err = vlCom.ThetasFromModel(p.params.BaseModel, trainDataSet, p.params.IsTagPart)
+		if err != nil {
+			return err
+		}
+	}
 	p.thetas = thetas
 
+	// replay the reordering of samples in the rounds before the checkpoint,
+	// so that the batches of the following rounds are the same as those of an uninterrupted training
+	if p.resumed {
+		for r := 0; r <= int(p.round); r++ {
+			_, p.trainDataSet.TrainSet = vlCom.GetBatchSetBySize(p.trainDataSet.TrainSet, *p.params, r, true)
+		}
+	}
+
+	return nil
+}
+
+// initSparse initializes Process with the sparse data set
+func (p *process) initSparse() error {
+	sparseSet, err := logic.GetSparseTrainSetFromFile(p.fileRows, *p.params)
+	if err != nil {
+		return errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl GetSparseTrainSetFromFile", err.Error())
+	}
+	p.sparseSet = sparseSet
+
+	p.thetas = logic.InitSparseThetas(sparseSet)
+	if p.params.BaseModel != nil {
+		if p.thetas, err = vlCom.ThetasFromModel(p.params.BaseModel, sparseSet.TrainDataSet, p.params.IsTagPart); err != nil {
+			return err
+		}
+	}
+
+	if p.resumed {
+		for r := 0; r <= int(p.round); r++ {
+			vlCom.GetSparseBatchBySize(sparseSet.Samples, *p.params, r, true)
+		}
+	}
 	return nil
 }
 
@@ -150,13 +244,25 @@ func (p *process) calLocalGradientAndCost() ([]byte, int, error) {
 		return p.partBytesForOther, p.calLocalGradientAndCostTimes, nil
 	}
 
-	rawPart, otherPartBytes, newSet, err := logic.CalLocalGradientAndCost(p.trainDataSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+	var rawPart *logicVert.RawLocalGradAndCostPart
+	var otherPartBytes []byte
+	var err error
+	if p.sparseSet != nil {
+		rawPart, otherPartBytes, err = logic.CalSparseLocalGradientAndCost(p.sparseSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+	} else {
+		if err := p.checkEncodingRange(); err != nil {
+			return []byte{}, p.calLocalGradientAndCostTimes, err
+		}
+		var newSet [][]float64
+		rawPart, otherPartBytes, newSet, err = logic.CalLocalGradientAndCost(p.trainDataSet, p.thetas, *p.params, &p.homoPriv.PublicKey, int(p.round))
+		if err == nil {
+			p.trainDataSet.TrainSet = newSet
+		}
+	}
 	if err != nil {
 		return []byte{}, p.calLocalGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl calLocalGradientAndCost", err.Error())
 	}
 
-	p.trainDataSet.TrainSet = newSet
-
 	p.rawPart = rawPart
 	p.partBytesForOther = otherPartBytes
 
@@ -165,6 +271,22 @@ func (p *process) calLocalGradientAndCost() ([]byte, int, error) {
 	return p.partBytesForOther, p.calLocalGradientAndCostTimes, nil
 }
 
+// checkEncodingRange checks the values encoded as fixed-point integers in this round don't overflow the accuracy,
+// the warning that they lose digits of the accuracy is logged once, as they grow with thetas usually
+func (p *process) checkEncodingRange() error {
+	magnitude := vlCom.EncodedMagnitude(p.trainDataSet.TrainSet, p.thetas, p.params.IsTagPart)
+	lossy, err := vlCom.CheckEncodingRange(magnitude, p.params.Accuracy)
+	if err != nil {
+		return err
+	}
+	if lossy && !p.lossyWarned {
+		p.lossyWarned = true
+		logger.Warnf("values of magnitude %g lose digits of accuracy %d in the fixed-point encoding at round %d, "+
+			"they approach the range of the encoding, lower the accuracy or scale the features", magnitude, p.params.Accuracy, p.round)
+	}
+	return nil
+}
+
 func (p *process) setPartBytesFromOther(partBytesFromOther []byte, round uint64) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -197,7 +319,15 @@ func (p *process) calEncGradientAndCost() ([]byte, []byte, int, error) {
 		return []byte{}, []byte{}, p.calEncGradientAndCostTimes, nil
 	}
 
-	encGradForOther, encCostForOther, gradientNoise, costNoise, err := logic.CalEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.trainDataSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	var encGradForOther, encCostForOther []byte
+	var gradientNoise []*big.Int
+	var costNoise *big.Int
+	var err error
+	if p.sparseSet != nil {
+		encGradForOther, encCostForOther, gradientNoise, costNoise, err = logic.CalSparseEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.sparseSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	} else {
+		encGradForOther, encCostForOther, gradientNoise, costNoise, err = logic.CalEncGradientAndCost(p.rawPart, p.partBytesFromOther, p.trainDataSet, *p.params, p.homoPubOfOther, p.thetas, int(p.round))
+	}
 	if err != nil {
 		return []byte{}, []byte{}, p.calEncGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl calEncGradientAndCost", err.Error())
 	}
@@ -238,7 +368,7 @@ func (p *process) decGradientAndCost() ([]byte, []byte, int, error) {
 		return p.gradBytesForOther, p.costBytesForOther, p.decGradientAndCostTimes, nil
 	}
 
-	gradBytesForOther, costBytesForOther, err := logic.DecGradientAndCost(p.encGradFromOther, p.encCostFromOther, p.homoPriv)
+	gradBytesForOther, costBytesForOther, err := logic.DecGradientAndCost(p.encGradFromOther, p.encCostFromOther, p.homoPriv, p.weights)
 	if err != nil {
 		return []byte{}, []byte{}, p.decGradientAndCostTimes, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl decGradientAndCost", err.Error())
 	}
@@ -285,20 +415,52 @@ func (p *process) updateCostAndGradient() (bool, error) {
 		logger.Panicf("gradBytesFromOther is [%v], gradientNoise is [%v], thetas is [%v], round [%v]", p.gradBytesFromOther, p.gradientNoise, p.thetas, p.round)
 	}
 
-	nextThetas, err := logic.UpdateGradient(p.gradBytesFromOther, p.gradientNoise, p.thetas, *p.params)
+	var nextThetas []float64
+	var err error
+	if p.sparseSet != nil {
+		nextThetas, err = logic.UpdateSparseGradient(p.gradBytesFromOther, p.gradientNoise, p.sparseSet, p.thetas, *p.params, p.optimizer, int(p.round))
+	} else {
+		nextThetas, err = logic.UpdateGradient(p.gradBytesFromOther, p.gradientNoise, p.weights, p.thetas, *p.params, p.optimizer, int(p.round))
+	}
 	if err != nil {
 		return stopped, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl updateGradient", err.Error())
 	}
 	p.nextThetas = nextThetas
 
-	if p.round > 0 {
-		cost, err := logic.UpdateCost(p.costBytesFromOther, p.costNoise, *p.params)
+	// the cost of the first round of incremental training is the cost of the base model,
+	// and the one of the dry run is the cost of the initial thetas the dry run checks divergence against
+	if p.round > 0 || p.params.Incremental || p.params.DryRun != nil {
+		cost, err := logic.UpdateCost(p.costBytesFromOther, p.costNoise, p.weights, *p.params)
 		if err != nil {
 			return stopped, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl updateCost", err.Error())
 		}
 
 		p.cost = cost
-		stopped = logic.StopTraining(p.lastCost, p.cost, *p.params)
+		if p.round == 0 {
+			p.baseCost = cost
+		} else {
+			stopped = logic.StopTraining(p.lastCost, p.cost, *p.params)
+		}
+	}
+	// incremental training runs updateRounds rounds at most
+	if p.params.Incremental && p.params.UpdateRounds > 0 && p.round+1 >= uint64(p.params.UpdateRounds) {
+		stopped = true
+	}
+	// the dry run fails once the cost or thetas go wrong, and stops after its iterations
+	if p.params.DryRun != nil {
+		if err := vlCom.CheckDryRunCost(p.baseCost, p.cost, p.nextThetas); err != nil {
+			return stopped, err
+		}
+		if p.round+1 >= uint64(vlCom.DryRunIterations(p.params.DryRun)) {
+			stopped = true
+		}
+	}
+	// differentially private training stops when the privacy budget is used up
+	if p.params.Dp != nil && p.round+1 >= uint64(p.params.Dp.Rounds) {
+		stopped = true
+	}
+	if p.earlyStopped {
+		stopped = true
 	}
 	if stopped {
 		p.stopped = 1
@@ -338,14 +500,31 @@ func (p *process) stop() (decided bool, stopped bool) {
 		if p.otherStopped == 1 && p.stopped == 1 {
 			stopped = true
 		}
+		// only the party with label evaluates the metric of early stopping, the others stop along with it
+		if p.params.EarlyStopping != nil && (p.otherStopped == 1 || p.stopped == 1) {
+			stopped = true
+		}
 	}
 
 	return
 }
 
+// setEarlyStopped makes the training stop in the current round, as the metric of live evaluation stops improving
+func (p *process) setEarlyStopped() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.earlyStopped = true
+}
+
 // getTrainModels retrieve own model
 func (p *process) getTrainModels() ([]byte, error) {
-	modelBytes, err := vlCom.TrainModelsToBytes(p.thetas, p.trainDataSet, *p.params)
+	var modelBytes []byte
+	var err error
+	if p.sparseSet != nil {
+		modelBytes, err = vlCom.SparseTrainModelsToBytes(p.thetas, p.sparseSet, *p.params)
+	} else {
+		modelBytes, err = vlCom.TrainModelsToBytes(p.thetas, p.trainDataSet, *p.params, p.categories)
+	}
 	if err != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when logic_reg_vl trainModelsToBytes", err.Error())
 	}
@@ -353,6 +532,63 @@ func (p *process) getTrainModels() ([]byte, error) {
 	return modelBytes, nil
 }
 
+// metricDelta returns the cost of the trained model against the one of the base model,
+// nil if it isn't incremental training
+func (p *process) metricDelta() *pbCom.MetricDelta {
+	if !p.params.Incremental {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return &pbCom.MetricDelta{Metric: "cost", Base: p.baseCost, Updated: p.cost}
+}
+
+// privacyBudget returns the differential privacy budget consumed by the rounds trained,
+// nil if differential privacy is disabled
+func (p *process) privacyBudget() *pbCom.PrivacyBudget {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return vlCom.ConsumedPrivacyBudget(p.params.Dp, int64(p.round)+1)
+}
+
+// checkpoint returns the state of current round, called after the round finished
+func (p *process) checkpoint() ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cp := checkpoint{
+		Round:      p.round,
+		Cost:       p.cost,
+		NextThetas: p.nextThetas,
+		BaseCost:   p.baseCost,
+		Optimizer:  p.optimizer.State(),
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "failed to marshal checkpoint: %s", err.Error())
+	}
+	return data, nil
+}
+
+// restore restores the state of process from a checkpoint, called before init
+func (p *process) restore(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return errorx.New(errcodes.ErrCodeParam, "failed to unmarshal checkpoint: %s", err.Error())
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.round = cp.Round
+	p.cost = cp.Cost
+	p.nextThetas = cp.NextThetas
+	p.baseCost = cp.BaseCost
+	p.optimizer.Restore(cp.Optimizer)
+	p.resumed = true
+	return nil
+}
+
 // setHomoPubOfOther save homomorphic public key from other party, used for secret transmission
 func (p *process) setHomoPubOfOther(homoPubOfOther []byte) {
 	p.homoPubOfOther = homoPubOfOther
@@ -361,8 +597,9 @@ func (p *process) setHomoPubOfOther(homoPubOfOther []byte) {
 // newProcess init process by homomorphic key and training task params
 func newProcess(homoPriv *paillier.PrivateKey, params *pbCom.TrainParams) *process {
 	return &process{
-		round:    0,
-		params:   params,
-		homoPriv: homoPriv,
+		round:     0,
+		params:    params,
+		homoPriv:  homoPriv,
+		optimizer: vlCom.NewOptimizer(params.Alpha, params.Optimizer),
 	}
 }
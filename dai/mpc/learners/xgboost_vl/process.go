@@ -0,0 +1,418 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost_vl
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	vlCom "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbXgbVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/xgboost_vl"
+)
+
+// openNode is a node waiting to be split at current depth
+type openNode struct {
+	id      int32
+	samples []int
+	gradSum float64
+	hessSum float64
+}
+
+// otherSplit is a split found on the features of the party without label,
+// and the party should be asked for the samples going left
+type otherSplit struct {
+	nodeID     int32
+	featureIdx int
+	bin        int
+	node       *openNode
+}
+
+// process builds trees level by level.
+// The party with label calculates gradients and hessians, decides all splits and holds leaf weights,
+// the party without label only sums up encrypted gradients and hessians for its own features,
+// and records the thresholds of the splits it owns.
+type process struct {
+	homoPriv *paillier.PrivateKey // homomorphic private key, only used by the party with label
+	params   *pbCom.TrainParams   // params for the training task
+	fileRows [][]string           // file rows obtained from sample file
+
+	trainDataSet   *xgboost.TrainDataSet // own data set for training, formatted from filesRow
+	homoPubOfOther *paillier.PublicKey   // public key of the party with label
+
+	mutex sync.Mutex
+
+	model *pbCom.XGBoostModel
+	tree  uint64 // index of the tree being built
+	depth uint64 // depth of the nodes being split
+
+	// intermediate results of the party with label
+	preds           []float64 // predictions of aligned samples by trees built
+	grads, hess     []float64
+	openNodes       []*openNode
+	children        []*openNode
+	otherHistograms map[int32]*pbXgbVl.NodeHistogram
+
+	// intermediate results of the party without label
+	encGrads, encHess []*big.Int
+	nodeSamples       map[int32][]int
+}
+
+// init initialize Process, after PSI, before training
+func (p *process) init(fileRows [][]string) error {
+	p.fileRows = fileRows
+
+	trainDataSet, err := xgboost.GetTrainDataSetFromFile(p.fileRows, p.params)
+	if err != nil {
+		return errorx.ParseAndWrap(err, "mistake happened when xgboost_vl GetTrainDataSetFromFile")
+	}
+	p.trainDataSet = trainDataSet
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.model.BinaryClass = p.params.LabelName != ""
+	if p.params.IsTagPart {
+		p.model.BaseScore = xgboost.BaseScore(trainDataSet.Labels, p.model.BinaryClass)
+		p.preds = make([]float64, len(trainDataSet.Labels))
+		for i := range p.preds {
+			p.preds[i] = p.model.BaseScore
+		}
+	}
+	return nil
+}
+
+// setHomoPubOfOther sets the public key of the party with label
+func (p *process) setHomoPubOfOther(pubBytes []byte) error {
+	pub, err := vlCom.HomoPubkeyFromBytes(pubBytes)
+	if err != nil {
+		return errorx.New(errcodes.ErrCodeParam, "failed to resolve homomorphic public key: %s", err.Error())
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.homoPubOfOther = pub
+	return nil
+}
+
+// startTree calculates gradients and hessians by current predictions, and encrypts them for the party without label,
+// the root node with all samples is the only node to split
+func (p *process) startTree(tree uint64) ([][]byte, [][]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.tree = tree
+	p.depth = 0
+	p.model.Trees = append(p.model.Trees, &pbCom.XGBoostTree{})
+	p.grads, p.hess = xgboost.CalGradAndHess(p.trainDataSet.Labels, p.preds, p.model.BinaryClass)
+
+	root := &openNode{id: 0}
+	for i := range p.grads {
+		root.samples = append(root.samples, i)
+	}
+	root.gradSum, root.hessSum = p.sumGradAndHess(root.samples)
+	p.openNodes = []*openNode{root}
+	p.otherHistograms = nil
+
+	encGrads, encHess, err := xgboost.EncryptGradAndHess(p.grads, p.hess, p.params.Accuracy, &p.homoPriv.PublicKey)
+	if err != nil {
+		return nil, nil, errorx.New(errcodes.ErrCodeInternal, "failed to encrypt gradients: %s", err.Error())
+	}
+	return bigIntsToBytes(encGrads), bigIntsToBytes(encHess), nil
+}
+
+// nodesToSplit returns the nodes at current depth with their samples
+func (p *process) nodesToSplit() []*pbXgbVl.NodeSamples {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var nodes []*pbXgbVl.NodeSamples
+	for _, n := range p.openNodes {
+		ns := &pbXgbVl.NodeSamples{NodeID: n.id}
+		for _, i := range n.samples {
+			ns.Samples = append(ns.Samples, int32(i))
+		}
+		nodes = append(nodes, ns)
+	}
+	return nodes
+}
+
+// setOtherHistograms sets encrypted histograms from the party without label
+func (p *process) setOtherHistograms(hists []*pbXgbVl.NodeHistogram) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.otherHistograms = make(map[int32]*pbXgbVl.NodeHistogram, len(hists))
+	for _, h := range hists {
+		p.otherHistograms[h.NodeID] = h
+	}
+}
+
+// findSplits finds the best split for each node at current depth,
+// nodes split by local features are divided directly, and nodes without any gain become leaves,
+// returns the splits on features of the party without label, which should be applied by applyOtherSplit
+func (p *process) findSplits() ([]*otherSplit, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	lambda := p.params.XgbParams.Lambda
+	tree := p.model.Trees[p.tree]
+	p.children = nil
+
+	var others []*otherSplit
+	for _, n := range p.openNodes {
+		localHist := xgboost.CalHistogram(p.trainDataSet, p.grads, p.hess, n.samples)
+		localSplit, localFound := xgboost.BestSplit(localHist, n.gradSum, n.hessSum, lambda)
+
+		var oSplit xgboost.Split
+		var otherFound bool
+		if encHist, ok := p.otherHistograms[n.id]; ok {
+			hist, err := p.decryptHistogram(encHist)
+			if err != nil {
+				return nil, err
+			}
+			oSplit, otherFound = xgboost.BestSplit(hist, n.gradSum, n.hessSum, lambda)
+		}
+
+		switch {
+		case otherFound && (!localFound || oSplit.Gain > localSplit.Gain):
+			tree.Nodes = append(tree.Nodes, &pbCom.XGBoostNode{Id: n.id})
+			others = append(others, &otherSplit{
+				nodeID:     n.id,
+				featureIdx: oSplit.FeatureIdx,
+				bin:        oSplit.Bin,
+				node:       n,
+			})
+		case localFound:
+			tree.Nodes = append(tree.Nodes, &pbCom.XGBoostNode{
+				Id:        n.id,
+				IsLocal:   true,
+				Feature:   p.trainDataSet.FeatureNames[localSplit.FeatureIdx],
+				Threshold: p.trainDataSet.Thresholds[localSplit.FeatureIdx][localSplit.Bin],
+			})
+			left, right := xgboost.SplitSamples(p.trainDataSet, n.samples, localSplit.FeatureIdx, localSplit.Bin)
+			p.addChildren(n, left, right)
+		default:
+			p.addLeaf(n)
+		}
+	}
+	return others, nil
+}
+
+// applyOtherSplit divides the node by the samples going left, which are received from the party without label
+func (p *process) applyOtherSplit(s *otherSplit, leftSamples []int32) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	isLeft := make(map[int]bool, len(leftSamples))
+	for _, i := range leftSamples {
+		isLeft[int(i)] = true
+	}
+	var left, right []int
+	for _, i := range s.node.samples {
+		if isLeft[i] {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	if len(left) == 0 || len(right) == 0 {
+		return errorx.New(errcodes.ErrCodeInternal, "invalid split of node %d in tree %d, left %d samples, right %d samples", s.nodeID, p.tree, len(left), len(right))
+	}
+	p.addChildren(s.node, left, right)
+	return nil
+}
+
+// nextDepth enters next depth, nodes reaching the max depth become leaves,
+// returns true if the tree is finished
+func (p *process) nextDepth() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.depth++
+	p.openNodes = p.children
+	p.children = nil
+	p.otherHistograms = nil
+	if p.depth < uint64(p.params.XgbParams.MaxDepth) && len(p.openNodes) > 0 {
+		return false
+	}
+
+	for _, n := range p.openNodes {
+		p.addLeaf(n)
+	}
+	p.openNodes = nil
+	return true
+}
+
+// addChildren adds children of a split node for next depth
+func (p *process) addChildren(n *openNode, left, right []int) {
+	for k, samples := range [][]int{left, right} {
+		child := &openNode{id: 2*n.id + 1 + int32(k), samples: samples}
+		child.gradSum, child.hessSum = p.sumGradAndHess(samples)
+		p.children = append(p.children, child)
+	}
+}
+
+// addLeaf turns the node into a leaf, and updates predictions of samples in it
+func (p *process) addLeaf(n *openNode) {
+	weight := xgboost.LeafWeight(n.gradSum, n.hessSum, p.params.XgbParams.Lambda, p.params.XgbParams.LearningRate)
+	tree := p.model.Trees[p.tree]
+	tree.Nodes = append(tree.Nodes, &pbCom.XGBoostNode{Id: n.id, IsLeaf: true, Weight: weight})
+	for _, i := range n.samples {
+		p.preds[i] += weight
+	}
+}
+
+// sumGradAndHess sums up gradients and hessians of samples
+func (p *process) sumGradAndHess(samples []int) (gradSum, hessSum float64) {
+	for _, i := range samples {
+		gradSum += p.grads[i]
+		hessSum += p.hess[i]
+	}
+	return
+}
+
+// decryptHistogram decrypts the histogram from the party without label
+func (p *process) decryptHistogram(h *pbXgbVl.NodeHistogram) (*xgboost.Histogram, error) {
+	encHist := &xgboost.EncHistogram{}
+	for _, f := range h.Features {
+		if len(f.GradSums) != len(f.HessSums) {
+			return nil, errorx.New(errcodes.ErrCodeParam, "invalid histogram of node %d", h.NodeID)
+		}
+		encHist.GradSums = append(encHist.GradSums, bytesToBigInts(f.GradSums))
+		encHist.HessSums = append(encHist.HessSums, bytesToBigInts(f.HessSums))
+	}
+	return xgboost.DecryptHistogram(encHist, p.params.Accuracy, p.homoPriv), nil
+}
+
+// setGradAndHess sets encrypted gradients and hessians of a new tree from the party with label
+func (p *process) setGradAndHess(tree uint64, encGrads, encHess [][]byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.tree = tree
+	for uint64(len(p.model.Trees)) <= tree {
+		p.model.Trees = append(p.model.Trees, &pbCom.XGBoostTree{})
+	}
+	p.encGrads = bytesToBigInts(encGrads)
+	p.encHess = bytesToBigInts(encHess)
+	p.nodeSamples = nil
+}
+
+// calHistograms sums up encrypted gradients and hessians for the nodes to split
+func (p *process) calHistograms(nodes []*pbXgbVl.NodeSamples) ([]*pbXgbVl.NodeHistogram, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.homoPubOfOther == nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "homomorphic public key of other party is not received")
+	}
+	sampleNum := len(p.trainDataSet.Bins)
+	if len(p.encGrads) != sampleNum || len(p.encHess) != sampleNum {
+		return nil, errorx.New(errcodes.ErrCodeParam, "number of gradients %d mismatch number of samples %d", len(p.encGrads), sampleNum)
+	}
+
+	p.nodeSamples = make(map[int32][]int, len(nodes))
+	var hists []*pbXgbVl.NodeHistogram
+	for _, n := range nodes {
+		samples := make([]int, 0, len(n.Samples))
+		for _, i := range n.Samples {
+			if int(i) < 0 || int(i) >= sampleNum {
+				return nil, errorx.New(errcodes.ErrCodeParam, "invalid sample index %d of node %d", i, n.NodeID)
+			}
+			samples = append(samples, int(i))
+		}
+		p.nodeSamples[n.NodeID] = samples
+
+		encHist := xgboost.CalEncHistogram(p.trainDataSet, p.encGrads, p.encHess, samples, p.homoPubOfOther)
+		h := &pbXgbVl.NodeHistogram{NodeID: n.NodeID}
+		for j := range encHist.GradSums {
+			h.Features = append(h.Features, &pbXgbVl.FeatureHistogram{
+				GradSums: bigIntsToBytes(encHist.GradSums[j]),
+				HessSums: bigIntsToBytes(encHist.HessSums[j]),
+			})
+		}
+		hists = append(hists, h)
+	}
+	return hists, nil
+}
+
+// split records the split owned by local party, and returns the samples going left
+func (p *process) split(tree uint64, nodeID int32, featureIdx, bin int) ([]int32, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	samples, ok := p.nodeSamples[nodeID]
+	if !ok || tree != p.tree {
+		return nil, errorx.New(errcodes.ErrCodeParam, "node %d of tree %d not found", nodeID, tree)
+	}
+	if featureIdx < 0 || featureIdx >= len(p.trainDataSet.FeatureNames) ||
+		bin < 0 || bin >= len(p.trainDataSet.Thresholds[featureIdx]) {
+		return nil, errorx.New(errcodes.ErrCodeParam, "invalid split of node %d, feature %d, bin %d", nodeID, featureIdx, bin)
+	}
+
+	p.model.Trees[tree].Nodes = append(p.model.Trees[tree].Nodes, &pbCom.XGBoostNode{
+		Id:        nodeID,
+		IsLocal:   true,
+		Feature:   p.trainDataSet.FeatureNames[featureIdx],
+		Threshold: p.trainDataSet.Thresholds[featureIdx][bin],
+	})
+	left, _ := xgboost.SplitSamples(p.trainDataSet, samples, featureIdx, bin)
+	leftSamples := make([]int32, 0, len(left))
+	for _, i := range left {
+		leftSamples = append(leftSamples, int32(i))
+	}
+	return leftSamples, nil
+}
+
+// getTrainModels returns trained out model
+func (p *process) getTrainModels() ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	model, err := xgboost.TrainModelsToBytes(p.model, p.params)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "failed to convert model to bytes: %s", err.Error())
+	}
+	return model, nil
+}
+
+func bigIntsToBytes(ints []*big.Int) [][]byte {
+	bs := make([][]byte, len(ints))
+	for i, v := range ints {
+		bs[i] = v.Bytes()
+	}
+	return bs
+}
+
+func bytesToBigInts(bs [][]byte) []*big.Int {
+	ints := make([]*big.Int, len(bs))
+	for i, b := range bs {
+		ints[i] = new(big.Int).SetBytes(b)
+	}
+	return ints
+}
+
+func newProcess(homoPriv *paillier.PrivateKey, params *pbCom.TrainParams) *process {
+	return &process{
+		homoPriv: homoPriv,
+		params:   params,
+		// trees may be received from the party with label before samples are aligned
+		model: &pbCom.XGBoostModel{},
+	}
+}
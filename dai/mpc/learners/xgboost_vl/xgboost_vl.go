@@ -0,0 +1,640 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost_vl
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/common/math/homomorphism/paillier"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	crypCom "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+	pbXgbVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/xgboost_vl"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
+)
+
+var (
+	logger = logrus.WithField("module", "mpc.learners.xgboost_vl")
+)
+
+// PSI is for vertical learning,
+// initialized at the beginning of training by Learner
+type PSI interface {
+	// EncryptSampleIDSet to encrypt local IDs
+	EncryptSampleIDSet() ([]byte, error)
+
+	// SetReEncryptIDSet sets re-encrypted IDs from other party,
+	// and tries to calculate final re-encrypted IDs
+	// returns True if calculation is Done, otherwise False if still waiting for others' parts
+	// returns Error if any mistake happens
+	SetReEncryptIDSet(party string, reEncIDs []byte) (bool, error)
+
+	// ReEncryptIDSet to encrypt encrypted IDs for other party
+	ReEncryptIDSet(party string, encIDs []byte) ([]byte, error)
+
+	// SetOtherFinalReEncryptIDSet sets final re-encrypted IDs of other party
+	SetOtherFinalReEncryptIDSet(party string, reEncIDs []byte) error
+
+	// IntersectParts tries to calculate intersection with all parties' samples
+	// returns True with final result if calculation is Done, otherwise False if still waiting for others' samples
+	// returns Error if any mistake happens
+	// You'd better call it when SetReEncryptIDSet returns Done or SetOtherFinalReEncryptIDSet finishes
+	IntersectParts() (bool, [][]string, []string, error)
+}
+
+// RpcHandler used to request remote mpc-node
+type RpcHandler interface {
+	StepTrain(req *pb.TrainRequest, peerName string) (*pb.TrainResponse, error)
+
+	// StepTrainWithRetry sends training message to remote mpc-node
+	// retries 2 times at most
+	// inteSec indicates the interval between retry requests, in seconds
+	StepTrainWithRetry(req *pb.TrainRequest, peerName string, times int, inteSec int64) (*pb.TrainResponse, error)
+}
+
+// ResultHandler handles final result which is successful or failed
+// Should be called when learning finished
+type ResultHandler interface {
+	SaveResult(*pbCom.TrainTaskResult)
+}
+
+type learnerStatusType uint8
+
+const (
+	learnerStatusStartPSI learnerStatusType = iota
+	learnerStatusEndPSI
+	learnerStatusStartTrain
+	learnerStatusEndTrain
+)
+
+// Learner trains a vertical XGBoost model with another party.
+// The party with label encrypts gradients and hessians with its homomorphic public key,
+// the party without label sums them up in bins of its own features,
+// then the party with label decrypts the sums and finds the best splits.
+// Features and thresholds of splits are only known by the owners,
+// while the samples falling into each node are known by both parties.
+type Learner struct {
+	id          string
+	algo        pbCom.Algorithm
+	address     string               // address indicates local mpc-node
+	parties     []string             // parties are other learners who participates in MPC, assigned with mpc-node address usually
+	homoPriv    *paillier.PrivateKey // homomorphic private key, only generated by the party with label
+	homoPub     []byte               // homomorphic public key for transfer
+	trainParams *pbCom.TrainParams
+	samplesFile []byte // sample file content for training model
+	psi         PSI
+	procMutex   sync.Mutex
+	process     *process      // process of training model
+	rpc         RpcHandler    // rpc is used to request remote mpc-node
+	rh          ResultHandler // rh handles final result which is successful or failed
+	fileRows    [][]string    // fileRows returned by psi.IntersectParts
+
+	tree  uint64 // index of the tree being built
+	depth uint64 // depth of the nodes being split
+
+	status learnerStatusType
+	// trainReady is closed when samples are aligned and the process is initialized,
+	// messages from other party may arrive before that
+	trainReady chan struct{}
+}
+
+func (l *Learner) Advance(payload []byte) (*pb.TrainResponse, error) {
+	m := &pbXgbVl.Message{}
+	err := proto.Unmarshal(payload, m)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeParam, "failed to Unmarshal payload: %s", err.Error())
+	}
+
+	return l.advance(m)
+}
+
+// getTrainSet returns training set after Sample Alignment
+func (l *Learner) getTrainSet() []*pbCom.TrainTaskResult_FileRow {
+	var frs []*pbCom.TrainTaskResult_FileRow
+	for _, fr := range l.fileRows {
+		frs = append(frs, &pbCom.TrainTaskResult_FileRow{Row: fr})
+	}
+	return frs
+}
+
+// advance handles all kinds of message
+func (l *Learner) advance(message *pbXgbVl.Message) (*pb.TrainResponse, error) {
+	mType := message.Type
+
+	handleError := func(err error) {
+		logger.WithField("error", err.Error()).Warning("failed to train out a model")
+		res := &pbCom.TrainTaskResult{TaskID: l.id, ErrMsg: err.Error()}
+		l.rh.SaveResult(res)
+	}
+
+	var ret *pb.TrainResponse
+	switch mType {
+	case pbXgbVl.MessageType_MsgPsiEnc: // local message
+		tracing.StartStage(l.id, tracing.StagePSI)
+		encIDs, err := l.psi.EncryptSampleIDSet()
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		go func() {
+			m := &pbXgbVl.Message{
+				Type: pbXgbVl.MessageType_MsgPsiAskReEnc,
+				VlLPsiReEncIDsReq: &pb.VLPsiReEncIDsRequest{
+					TaskID: l.id,
+					EncIDs: encIDs,
+				},
+			}
+			l.advance(m)
+		}()
+
+	case pbXgbVl.MessageType_MsgPsiAskReEnc: // local message
+		newMess := &pbXgbVl.Message{
+			Type:              pbXgbVl.MessageType_MsgPsiReEnc,
+			VlLPsiReEncIDsReq: message.VlLPsiReEncIDsReq,
+		}
+		reM, err := l.sendMessageWithRetry(newMess, l.parties[0])
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		done, err := l.psi.SetReEncryptIDSet(l.parties[0], reM.VlLPsiReEncIDsResp.ReEncIDs)
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		if done {
+			go func() {
+				m := &pbXgbVl.Message{
+					Type: pbXgbVl.MessageType_MsgPsiIntersect,
+				}
+				l.advance(m)
+			}()
+		}
+
+	case pbXgbVl.MessageType_MsgPsiReEnc:
+		reEncIDs, err := l.psi.ReEncryptIDSet(message.From, message.VlLPsiReEncIDsReq.EncIDs)
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		retM := &pbXgbVl.Message{
+			Type: pbXgbVl.MessageType_MsgPsiReEnc,
+			To:   message.From,
+			From: l.address,
+			VlLPsiReEncIDsResp: &pb.VLPsiReEncIDsResponse{
+				TaskID:   l.id,
+				ReEncIDs: reEncIDs,
+			},
+		}
+		payload, err := proto.Marshal(retM)
+		if err != nil {
+			err = errorx.New(errcodes.ErrCodeInternal, "failed to Marshal payload: %s", err.Error())
+			go handleError(err)
+			return nil, err
+		}
+
+		ret = &pb.TrainResponse{
+			TaskID:  l.id,
+			Payload: payload,
+		}
+
+		err = l.psi.SetOtherFinalReEncryptIDSet(message.From, reEncIDs)
+		if err != nil {
+			go handleError(err)
+		} else {
+			go func() {
+				m := &pbXgbVl.Message{
+					Type: pbXgbVl.MessageType_MsgPsiIntersect,
+				}
+				l.advance(m)
+			}()
+		}
+
+	case pbXgbVl.MessageType_MsgPsiIntersect: // local message
+		done, newRows, _, err := l.psi.IntersectParts()
+		if err == nil && done {
+			// the trees are built on dense rows, sparse rows are converted into dense ones
+			newRows, err = crypCom.DensifyRows(newRows)
+		}
+		if err != nil {
+			go handleError(err)
+			tracing.EndStage(l.id, tracing.StagePSI, err)
+			return nil, err
+		}
+
+		if done {
+			tracing.EndStage(l.id, tracing.StagePSI, nil)
+			l.procMutex.Lock()
+			defer l.procMutex.Unlock()
+			if learnerStatusStartPSI == l.status {
+				l.fileRows = newRows
+				l.status = learnerStatusEndPSI
+				go func() {
+					m := &pbXgbVl.Message{
+						Type: pbXgbVl.MessageType_MsgTrainHup,
+					}
+					l.advance(m)
+				}()
+			}
+		}
+
+	case pbXgbVl.MessageType_MsgTrainHup: // local message
+		l.procMutex.Lock()
+		defer l.procMutex.Unlock()
+		if learnerStatusEndPSI == l.status {
+			l.status = learnerStatusStartTrain
+			err := l.process.init(l.fileRows)
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
+			close(l.trainReady)
+
+			// the party with label drives the training, and the party without label waits for messages
+			if l.trainParams.IsTagPart {
+				m := &pbXgbVl.Message{
+					Type:       pbXgbVl.MessageType_MsgHomoPubkey,
+					HomoPubkey: l.homoPub,
+				}
+				_, err = l.sendMessageWithRetry(m, l.parties[0])
+				if err != nil {
+					go handleError(err)
+					return nil, err
+				}
+
+				go func() {
+					m := &pbXgbVl.Message{
+						Type: pbXgbVl.MessageType_MsgTrainBoost,
+						Tree: 0, // start Tree-0
+					}
+					l.advance(m)
+				}()
+			}
+		}
+
+	case pbXgbVl.MessageType_MsgHomoPubkey:
+		err := l.process.setHomoPubOfOther(message.HomoPubkey)
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+		ret = &pb.TrainResponse{
+			TaskID: l.id,
+		}
+
+	case pbXgbVl.MessageType_MsgTrainBoost: // local message
+		tree := message.Tree
+		if tree >= uint64(l.trainParams.XgbParams.NEstimators) {
+			m := &pbXgbVl.Message{
+				Type:    pbXgbVl.MessageType_MsgTrainStatus,
+				Stopped: true,
+				Tree:    tree,
+			}
+			logger.Infof("learner[%s] built %d trees, send stop status to remote learner[%s].", l.id, tree, l.parties[0])
+			_, err := l.sendMessageWithRetry(m, l.parties[0])
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
+
+			go func() {
+				m := &pbXgbVl.Message{
+					Type: pbXgbVl.MessageType_MsgTrainModels,
+				}
+				l.advance(m)
+			}()
+			break
+		}
+
+		l.tree = tree
+		l.depth = 0
+		tracing.StartStage(l.id, tracing.StageRound, attribute.Int64("tree", int64(tree)))
+		encGrads, encHess, err := l.process.startTree(tree)
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+		m := &pbXgbVl.Message{
+			Type:     pbXgbVl.MessageType_MsgTrainGradHess,
+			Tree:     tree,
+			EncGrads: encGrads,
+			EncHess:  encHess,
+		}
+		_, err = l.sendMessageWithRetry(m, l.parties[0])
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		err = l.requestHistograms()
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+	case pbXgbVl.MessageType_MsgTrainGradHess:
+		l.process.setGradAndHess(message.Tree, message.EncGrads, message.EncHess)
+		l.tree = message.Tree
+		tracing.StartStage(l.id, tracing.StageRound, attribute.Int64("tree", int64(message.Tree)))
+		ret = &pb.TrainResponse{
+			TaskID: l.id,
+		}
+
+	case pbXgbVl.MessageType_MsgTrainHistReq:
+		tree, depth := message.Tree, message.Depth
+		nodes := message.Nodes
+		ret = &pb.TrainResponse{
+			TaskID: l.id,
+		}
+
+		go func() {
+			// wait for local samples aligned
+			<-l.trainReady
+			hists, err := l.process.calHistograms(nodes)
+			if err != nil {
+				handleError(err)
+				return
+			}
+			m := &pbXgbVl.Message{
+				Type:       pbXgbVl.MessageType_MsgTrainHistograms,
+				Tree:       tree,
+				Depth:      depth,
+				Histograms: hists,
+			}
+			_, err = l.sendMessageWithRetry(m, l.parties[0])
+			if err != nil {
+				handleError(err)
+			}
+		}()
+
+	case pbXgbVl.MessageType_MsgTrainHistograms:
+		if message.Tree == l.tree && message.Depth == l.depth {
+			l.process.setOtherHistograms(message.Histograms)
+			go func() {
+				m := &pbXgbVl.Message{
+					Type:  pbXgbVl.MessageType_MsgTrainFindSplits,
+					Tree:  message.Tree,
+					Depth: message.Depth,
+				}
+				l.advance(m)
+			}()
+		}
+		ret = &pb.TrainResponse{
+			TaskID: l.id,
+		}
+
+	case pbXgbVl.MessageType_MsgTrainFindSplits: // local message
+		others, err := l.process.findSplits()
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		// ask the party without label to divide samples of the nodes split by its features
+		for _, s := range others {
+			m := &pbXgbVl.Message{
+				Type:       pbXgbVl.MessageType_MsgTrainSplit,
+				Tree:       l.tree,
+				Depth:      l.depth,
+				NodeID:     s.nodeID,
+				FeatureIdx: int32(s.featureIdx),
+				Bin:        int32(s.bin),
+			}
+			reM, err := l.sendMessageWithRetry(m, l.parties[0])
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
+			err = l.process.applyOtherSplit(s, reM.LeftSamples)
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
+		}
+
+		if l.process.nextDepth() {
+			logger.WithField("tree", l.tree).Infof("learner[%s] built tree[%d], got ready to start new tree[%d].", l.id, l.tree, l.tree+1)
+			go func() {
+				m := &pbXgbVl.Message{
+					Type: pbXgbVl.MessageType_MsgTrainBoost,
+					Tree: l.tree + 1, //for starting new tree
+				}
+				l.advance(m)
+			}()
+			break
+		}
+
+		l.depth++
+		err = l.requestHistograms()
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+	case pbXgbVl.MessageType_MsgTrainSplit:
+		leftSamples, err := l.process.split(message.Tree, message.NodeID, int(message.FeatureIdx), int(message.Bin))
+		if err != nil {
+			go handleError(err)
+			return nil, err
+		}
+
+		retM := &pbXgbVl.Message{
+			Type:        pbXgbVl.MessageType_MsgTrainSplit,
+			To:          message.From,
+			From:        l.address,
+			Tree:        message.Tree,
+			NodeID:      message.NodeID,
+			LeftSamples: leftSamples,
+		}
+		payload, err := proto.Marshal(retM)
+		if err != nil {
+			err = errorx.New(errcodes.ErrCodeInternal, "failed to Marshal payload: %s", err.Error())
+			go handleError(err)
+			return nil, err
+		}
+		ret = &pb.TrainResponse{
+			TaskID:  l.id,
+			Payload: payload,
+		}
+
+	case pbXgbVl.MessageType_MsgTrainStatus:
+		logger.Infof("learner[%s] got remote learner[%s]'s status[%t], tree[%d].", l.id, message.From, message.Stopped, message.Tree)
+		if message.Stopped {
+			go func() {
+				m := &pbXgbVl.Message{
+					Type: pbXgbVl.MessageType_MsgTrainModels,
+				}
+				l.advance(m)
+			}()
+		}
+		ret = &pb.TrainResponse{
+			TaskID: l.id,
+		}
+
+	case pbXgbVl.MessageType_MsgTrainModels: // local message
+		tracing.EndStage(l.id, tracing.StageRound, nil)
+		l.procMutex.Lock()
+		defer l.procMutex.Unlock()
+		if learnerStatusStartTrain == l.status {
+			l.status = learnerStatusEndTrain
+			model, err := l.process.getTrainModels()
+			if err != nil {
+				go handleError(err)
+				return nil, err
+			}
+			logger.WithField("tree", l.tree).Infof("learner[%s] trained out model successfully.", l.id)
+			res := &pbCom.TrainTaskResult{
+				TaskID:   l.id,
+				Success:  true,
+				Model:    model,
+				TrainSet: l.getTrainSet(),
+			}
+			l.rh.SaveResult(res)
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"address": l.address,
+		"tree":    l.tree,
+		"depth":   l.depth,
+	}).Infof("learner[%s] finished advance . message %s", l.id, message.Type.String())
+	return ret, nil
+}
+
+// requestHistograms asks the party without label for histograms of the nodes at current depth
+func (l *Learner) requestHistograms() error {
+	m := &pbXgbVl.Message{
+		Type:  pbXgbVl.MessageType_MsgTrainHistReq,
+		Tree:  l.tree,
+		Depth: l.depth,
+		Nodes: l.process.nodesToSplit(),
+	}
+	_, err := l.sendMessageWithRetry(m, l.parties[0])
+	return err
+}
+
+// sendMessageWithRetry sends message to remote mpc-node
+// retries 2 times at most
+func (l *Learner) sendMessageWithRetry(message *pbXgbVl.Message, address string) (*pbXgbVl.Message, error) {
+	times := 3
+
+	var m *pbXgbVl.Message
+	var err error
+	for i := 0; i < times; i++ {
+		if i > 0 {
+			time.Sleep(3 * time.Second)
+		}
+		m, err = l.sendMessage(message, address)
+		if err == nil {
+			break
+		}
+	}
+
+	return m, err
+}
+
+// sendMessage sends message to remote mpc-node
+func (l *Learner) sendMessage(message *pbXgbVl.Message, address string) (*pbXgbVl.Message, error) {
+	message.From = l.address
+	message.To = address
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "failed to Marshal payload: %s", err.Error())
+	}
+
+	trainReq := &pb.TrainRequest{
+		TaskID:  l.id,
+		Algo:    l.algo,
+		Payload: payload,
+	}
+	resp, err := l.rpc.StepTrain(trainReq, address)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &pbXgbVl.Message{}
+	if len(resp.Payload) != 0 {
+		err := proto.Unmarshal(resp.Payload, m)
+		if err != nil {
+			return nil, errorx.New(errcodes.ErrCodeInternal, "failed to Unmarshal payload[%s] from[%s] and err is[%s] ", string(resp.Payload), address, err.Error())
+		}
+	}
+	return m, nil
+}
+
+// NewLearner returns a VerticalXGBoost Learner
+// id is the assigned id for Learner
+// address indicates local mpc-node
+// parties are other learners who participates in MPC, assigned with mpc-node address usually
+// rpc is used to request remote mpc-node
+// rh handles final result which is successful or failed
+// params are parameters for training model
+// samplesFile contains samples for training model
+func NewLearner(id string, address string, params *pbCom.TrainParams, samplesFile []byte,
+	parties []string, rpc RpcHandler, rh ResultHandler) (*Learner, error) {
+
+	if err := xgboost.CheckParams(params); err != nil {
+		return nil, err
+	}
+
+	p, err := psi.NewVLTwoPartsPSIWithAlignment(params.GetPsiAlgorithm(), params.GetAlignmentKey(), address, samplesFile, params.GetIdName(), parties)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Learner{
+		id:          id,
+		algo:        pbCom.Algorithm_XGBOOST_VL,
+		address:     address,
+		parties:     parties,
+		psi:         p,
+		trainParams: params,
+		samplesFile: samplesFile,
+		rpc:         rpc,
+		rh:          rh,
+		status:      learnerStatusStartPSI,
+		trainReady:  make(chan struct{}),
+	}
+	// only the party with label encrypts gradients and hessians
+	if params.IsTagPart {
+		l.homoPriv, l.homoPub, err = crypCom.GenerateHomoKeyPair()
+		if err != nil {
+			return nil, err
+		}
+	}
+	l.process = newProcess(l.homoPriv, params)
+
+	// start training
+	go func() {
+		m := &pbXgbVl.Message{
+			Type: pbXgbVl.MessageType_MsgPsiEnc,
+		}
+		l.advance(m)
+	}()
+	return l, nil
+}
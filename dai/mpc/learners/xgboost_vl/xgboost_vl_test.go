@@ -0,0 +1,173 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost_vl
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+)
+
+type rpc struct {
+	reqC  chan *pb.TrainRequest
+	respC chan *pb.TrainResponse
+}
+
+func (r *rpc) StepTrainWithRetry(req *pb.TrainRequest, peerName string, times int, inteSec int64) (*pb.TrainResponse, error) {
+	return r.StepTrain(req, peerName)
+}
+
+func (r *rpc) StepTrain(req *pb.TrainRequest, peerName string) (*pb.TrainResponse, error) {
+	r.reqC <- req
+	resp := <-r.respC
+	if resp != nil {
+		return resp, nil
+	}
+	return nil, errors.New("test response error")
+}
+
+type resHandler struct {
+	modelC chan *pbCom.TrainTaskResult
+}
+
+func (rd *resHandler) SaveResult(res *pbCom.TrainTaskResult) {
+	rd.modelC <- res
+}
+
+func TestAdvance(t *testing.T) {
+	xgbParams := &pbCom.XGBoostParams{
+		MaxDepth:     2,
+		LearningRate: 0.3,
+		NEstimators:  3,
+		Lambda:       1,
+	}
+	// learner1 has no label
+	address1 := "127.0.0.1:8080"
+	params1 := &pbCom.TrainParams{
+		Label:     "Label",
+		LabelName: "Iris-setosa",
+		Accuracy:  10,
+		IsTagPart: false,
+		IdName:    "id",
+		XgbParams: xgbParams,
+	}
+	rpc1 := &rpc{reqC: make(chan *pb.TrainRequest), respC: make(chan *pb.TrainResponse)}
+	rh1 := &resHandler{modelC: make(chan *pbCom.TrainTaskResult)}
+	samplesFile1, err := ioutil.ReadFile("../../testdata/vl/logic_iris_plants/train_dataA.csv")
+	checkErr(err, t)
+
+	// learner2 has label
+	address2 := "127.0.0.1:8081"
+	params2 := &pbCom.TrainParams{
+		Label:     "Label",
+		LabelName: "Iris-setosa",
+		Accuracy:  10,
+		IsTagPart: true,
+		IdName:    "id",
+		XgbParams: xgbParams,
+	}
+	rpc2 := &rpc{reqC: make(chan *pb.TrainRequest), respC: make(chan *pb.TrainResponse)}
+	rh2 := &resHandler{modelC: make(chan *pbCom.TrainTaskResult)}
+	samplesFile2, err := ioutil.ReadFile("../../testdata/vl/logic_iris_plants/train_dataB.csv")
+	checkErr(err, t)
+
+	learner1, err := NewLearner("test-learner-1", address1, params1, samplesFile1, []string{address2}, rpc1, rh1)
+	checkErr(err, t)
+	learner2, err := NewLearner("test-learner-2", address2, params2, samplesFile2, []string{address1}, rpc2, rh2)
+	checkErr(err, t)
+
+	var res1, res2 *pbCom.TrainTaskResult
+	timeout := time.After(2 * time.Minute)
+	for res1 == nil || res2 == nil {
+		select {
+		case req := <-rpc1.reqC:
+			resp, err := learner2.Advance(req.GetPayload())
+			if err != nil {
+				log.Printf("learner2.Advance err: %s", err.Error())
+			}
+			rpc1.respC <- resp
+		case req := <-rpc2.reqC:
+			resp, err := learner1.Advance(req.GetPayload())
+			if err != nil {
+				log.Printf("learner1.Advance err: %s", err.Error())
+			}
+			rpc2.respC <- resp
+		case res1 = <-rh1.modelC:
+		case res2 = <-rh2.modelC:
+		case <-timeout:
+			t.Fatal("training timeout")
+		}
+	}
+	if !res1.Success || !res2.Success {
+		t.Fatalf("training failed, learner1: %s, learner2: %s", res1.ErrMsg, res2.ErrMsg)
+	}
+
+	model1, err := vl_common.TrainModelsFromBytes(res1.Model)
+	checkErr(err, t)
+	model2, err := vl_common.TrainModelsFromBytes(res2.Model)
+	checkErr(err, t)
+	if len(model2.Xgboost.GetTrees()) != int(xgbParams.NEstimators) {
+		t.Fatalf("expected %d trees, got %d", xgbParams.NEstimators, len(model2.Xgboost.GetTrees()))
+	}
+	for _, tree := range model2.Xgboost.Trees {
+		for _, node := range tree.Nodes {
+			if node.IsLeaf && node.Weight == 0 {
+				t.Errorf("leaf %d without weight", node.Id)
+			}
+		}
+	}
+
+	// predict on aligned training samples, setosa should be well classified
+	decisions, err := xgboost.PredictLocalDecisions(learner1.fileRows, model1)
+	checkErr(err, t)
+	outcomes, err := xgboost.Predict(learner2.fileRows, model2, decisions)
+	checkErr(err, t)
+	right := 0
+	labelIdx := len(learner2.fileRows[0]) - 1
+	for i, p := range outcomes {
+		isSetosa := learner2.fileRows[i+1][labelIdx] == "Iris-setosa"
+		if (p > 0.5) == isSetosa {
+			right++
+		}
+	}
+	if accuracy := float64(right) / float64(len(outcomes)); accuracy < 0.9 {
+		t.Errorf("accuracy %v on training samples is too low", accuracy)
+	}
+}
+
+func TestNewLearnerInvalidParams(t *testing.T) {
+	params := &pbCom.TrainParams{
+		Label:     "Label",
+		Accuracy:  10,
+		IdName:    "id",
+		XgbParams: &pbCom.XGBoostParams{MaxDepth: 0, LearningRate: 0.3, NEstimators: 3, Lambda: 1},
+	}
+	_, err := NewLearner("test-learner", "127.0.0.1:8080", params, []byte("id,a\n1,1\n"), []string{"127.0.0.1:8081"}, nil, nil)
+	if err == nil {
+		t.Error("expected error for invalid maxDepth")
+	}
+}
+
+func checkErr(err error, t *testing.T) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
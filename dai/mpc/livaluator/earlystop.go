@@ -0,0 +1,97 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livaluator
+
+import (
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// earlyStopper tracks the metric of live evaluation at each pause round, and decides to stop training
+// when the metric hasn't improved by more than MinDelta for Patience rounds
+type earlyStopper struct {
+	params    *pbCom.EarlyStoppingParams
+	minimized bool // whether the metric is better when lower
+
+	mutex     sync.Mutex
+	evaluated bool // evaluated means the metric has been evaluated at least once
+	best      float64
+	bestRound uint64
+	final     float64
+	stopped   bool
+}
+
+// newEarlyStopper creates an earlyStopper, nil if early stopping is disabled
+func newEarlyStopper(params *pbCom.EarlyStoppingParams) *earlyStopper {
+	if params == nil {
+		return nil
+	}
+	return &earlyStopper{
+		params:    params,
+		minimized: blockchain.MetricsMinimized[params.Metric],
+	}
+}
+
+// update records the metric scores evaluated at round, the ones other than the tracked metric are ignored
+func (s *earlyStopper) update(round uint64, metrics []Metric) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, m := range metrics {
+		if m.Name != s.params.Metric {
+			continue
+		}
+		if !s.evaluated || s.improved(m.Value) {
+			s.best = m.Value
+			s.bestRound = round
+		}
+		s.evaluated = true
+		s.final = m.Value
+		if round-s.bestRound >= uint64(s.params.Patience) {
+			s.stopped = true
+		}
+	}
+}
+
+// improved checks whether value is better than the best one by more than MinDelta
+func (s *earlyStopper) improved(value float64) bool {
+	if s.minimized {
+		return value < s.best-s.params.MinDelta
+	}
+	return value > s.best+s.params.MinDelta
+}
+
+// isStopped returns whether training should stop
+func (s *earlyStopper) isStopped() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stopped
+}
+
+// result returns the metric tracked, nil if it hasn't been evaluated
+func (s *earlyStopper) result() *pbCom.EarlyStopResult {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.evaluated {
+		return nil
+	}
+	return &pbCom.EarlyStopResult{
+		Metric:     s.params.Metric,
+		BestValue:  s.best,
+		BestRound:  s.bestRound,
+		FinalValue: s.final,
+		Stopped:    s.stopped,
+	}
+}
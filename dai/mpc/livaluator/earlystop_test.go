@@ -0,0 +1,83 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livaluator
+
+import (
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestEarlyStopper(t *testing.T) {
+	if s := newEarlyStopper(nil); s != nil {
+		t.Fatal("early stopper should be nil if early stopping is disabled")
+	}
+
+	cases := map[string]struct {
+		params     *pbCom.EarlyStoppingParams
+		values     []float64
+		stopRound  uint64 // 0 means not stopped
+		bestValue  float64
+		bestRound  uint64
+		finalValue float64
+	}{
+		"minimized": {
+			params:     &pbCom.EarlyStoppingParams{Metric: blockchain.MetricRMSE, Patience: 10, MinDelta: 0.01},
+			values:     []float64{1.0, 0.8, 0.795, 0.7, 0.72, 0.71, 0.705},
+			stopRound:  25,
+			bestValue:  0.7,
+			bestRound:  15,
+			finalValue: 0.71,
+		},
+		"maximized": {
+			params:     &pbCom.EarlyStoppingParams{Metric: blockchain.MetricAccuracy, Patience: 5},
+			values:     []float64{0.5, 0.6, 0.6, 0.7},
+			stopRound:  10,
+			bestValue:  0.6,
+			bestRound:  5,
+			finalValue: 0.6,
+		},
+		"improving": {
+			params:     &pbCom.EarlyStoppingParams{Metric: blockchain.MetricAccuracy, Patience: 10},
+			values:     []float64{0.5, 0.6, 0.7},
+			bestValue:  0.7,
+			bestRound:  10,
+			finalValue: 0.7,
+		},
+	}
+	for name, c := range cases {
+		s := newEarlyStopper(c.params)
+		if s.result() != nil {
+			t.Errorf("%s: result should be nil before evaluated", name)
+		}
+		var stopRound uint64
+		for i, v := range c.values {
+			round := uint64(i * 5)
+			s.update(round, []Metric{{Name: "other", Value: -1}, {Name: c.params.Metric, Value: v}})
+			if s.isStopped() {
+				stopRound = round
+				break
+			}
+		}
+		if stopRound != c.stopRound {
+			t.Errorf("%s: expected stop round %d, got %d", name, c.stopRound, stopRound)
+		}
+		r := s.result()
+		if r.Stopped != (c.stopRound != 0) || r.BestValue != c.bestValue || r.BestRound != c.bestRound ||
+			r.FinalValue != c.finalValue || r.Metric != c.params.Metric {
+			t.Errorf("%s: unexpected result %v", name, r)
+		}
+	}
+}
@@ -65,6 +65,19 @@ type Mpc interface {
 	Train(*pb.TrainRequest) (*pb.TrainResponse, error)
 }
 
+// Metric is a metric score calculated by LiveEvaluator at a pause round
+type Metric struct {
+	Name  string
+	Value float64
+}
+
+// MetricsReporter receives the metric scores calculated by LiveEvaluator at each pause round,
+// it's optionally implemented by the Mpc set into LiveEvaluator.
+// Reporting is called synchronously during training, so the implementation mustn't block.
+type MetricsReporter interface {
+	ReportLiveEvaluation(taskID string, round uint64, metrics []Metric)
+}
+
 type BinClassValidation interface {
 	// Splitter divides data set into several subsets with some strategies (such as KFolds, LOO),
 	// and hold out one subset as validation set and others as training set
@@ -157,6 +170,7 @@ type liveEvaluator struct {
 	mutex                      sync.Mutex               // mutex makes sure that LiveEvaluator is triggered only once for each `PauseRound`
 	trainRes                   *pbCom.TrainTaskResult   // training task result for each `PauseRound`
 	predicRes                  *pbCom.PredictTaskResult // prediction task result for each `PauseRound`
+	stopper                    *earlyStopper            // tracks the metric of early stopping, nil if it is disabled
 }
 
 // Trigger triggers model evaluation.
@@ -222,6 +236,11 @@ func (le *liveEvaluator) splitAndGetTrainSetVL(msg *pb.LiveEvaluationTriggerMsg)
 	for _, r := range msg.TrainSet {
 		ts = append(ts, r.GetRow())
 	}
+	// the training sets are split and sent back to learners as dense rows
+	ts, err := convert.DensifyRows(ts)
+	if err != nil {
+		return [][]string{}, errorx.New(errcodes.ErrCodeParam, "live evaluator[%s] failed to read samples: %s", le.id, err.Error())
+	}
 	fileRows := le.rebuildFileForShuffle(ts)
 
 	// segment the training set
@@ -241,7 +260,7 @@ func (le *liveEvaluator) splitAndGetTrainSetVL(msg *pb.LiveEvaluationTriggerMsg)
 		le.splitter = vcb
 	}
 
-	err := le.splitter.ShuffleSplit(int(le.livalParams.RandomSplit.PercentLO), le.id)
+	err = le.splitter.ShuffleSplit(int(le.livalParams.RandomSplit.PercentLO), le.splitSeed())
 	if err != nil {
 		logger.Warnf("live evaluator[%s] failed to divide the dataset, and error is[%s].",
 			le.id, err.Error())
@@ -372,6 +391,15 @@ func (le *liveEvaluator) runLogicRegVL(msg *pb.LiveEvaluationTriggerMsg) error {
 	return nil
 }
 
+// splitSeed returns the seed shuffling the dataset, the seed of the task if it is set, otherwise the evaluator's id,
+// so that the splits are the same for all parties
+func (le *liveEvaluator) splitSeed() string {
+	if seed := le.taskParams.TrainParams.GetSeed(); seed != 0 {
+		return strconv.FormatInt(seed, 10)
+	}
+	return le.id
+}
+
 // rebuildFileForEvaluation adds ID back to file in order to keep the same order when shuffle samples for parties,
 // because it had been removed after Sample Alignment
 func (le *liveEvaluator) rebuildFileForShuffle(f [][]string) [][]string {
@@ -474,8 +502,9 @@ func (le *liveEvaluator) SaveModel(res *pbCom.TrainTaskResult) error {
 }
 
 func (le *liveEvaluator) packParamsForPredict(index int, model *pbCom.TrainModels, file []byte) *pbCom.StartTaskRequest {
-	// set idName
+	// set idName and PSI algorithm
 	model.IdName = le.taskParams.TrainParams.IdName
+	model.PsiAlgorithm = le.taskParams.TrainParams.PsiAlgorithm
 
 	taskParams := pbCom.TaskParams{
 		Algo:        le.taskParams.Algo,
@@ -593,6 +622,7 @@ func (le *liveEvaluator) calMetricScoresAndCallbackCaseRegression(res *pbCom.Pre
 			return
 		}
 		logger.Infof("live evaluator[%s] finish prediction at loopRound[%d], and RMSE is[%f], PredictOut is[%v], ValidationSet is[%v].", le.id, le.pauseRound, rmse, yPreds, validSet)
+		le.reportMetrics([]Metric{{Name: "RMSE", Value: rmse}})
 	}
 }
 
@@ -704,6 +734,12 @@ func (le *liveEvaluator) calMetricScoresAndCallbackCaseBinClass(res *pbCom.Predi
 		accuracy = summary.Accuracy
 		logger.Infof("live evaluator[%s] finish prediction at loopRound[%d], and Accuracy is[%f], Precision is[%f], Recall is[%f], F1Score is[%f], and PredictOut is[%v], ValidationSet is[%v].",
 			le.id, le.pauseRound, accuracy, precision, recall, f1score, predProba, validSet)
+		le.reportMetrics([]Metric{
+			{Name: "Accuracy", Value: accuracy},
+			{Name: "Precision", Value: precision},
+			{Name: "Recall", Value: recall},
+			{Name: "F1Score", Value: f1score},
+		})
 
 		// callback learner to go on training
 		go le.callbackLearner()
@@ -714,6 +750,33 @@ func (le *liveEvaluator) calMetricScoresAndCallbackCaseBinClass(res *pbCom.Predi
 
 }
 
+// reportMetrics reports the metric scores of the pause round if the Mpc implements MetricsReporter,
+// and tracks the metric of early stopping
+func (le *liveEvaluator) reportMetrics(metrics []Metric) {
+	if le.stopper != nil {
+		le.stopper.update(le.pauseRound, metrics)
+	}
+	if r, ok := le.mpc.(MetricsReporter); ok {
+		r.ReportLiveEvaluation(le.id, le.pauseRound, metrics)
+	}
+}
+
+// EarlyStopped returns whether the metric of early stopping hasn't improved for the patience rounds,
+// then the evaluated learner stops training. It's always false for the parties without label,
+// which stop along with the party with label
+func (le *liveEvaluator) EarlyStopped() bool {
+	return le.stopper != nil && le.stopper.isStopped()
+}
+
+// EarlyStopResult returns the metric tracked by early stopping, nil if early stopping is disabled
+// or the metric hasn't been evaluated
+func (le *liveEvaluator) EarlyStopResult() *pbCom.EarlyStopResult {
+	if le.stopper == nil {
+		return nil
+	}
+	return le.stopper.result()
+}
+
 // callbackLearner calls back learner to go on training
 func (le *liveEvaluator) callbackLearner() {
 	resp, err := le.mpc.Train(&pb.TrainRequest{
@@ -775,6 +838,7 @@ func NewLiveEvaluator(req *pbCom.StartTaskRequest, mpc Mpc) (LiveEvaluator, erro
 	le.livalParams = req.Params.LivalParams
 	le.evalRule = pbCom.EvaluationRule_ErRandomSplit
 	le.learnerID = req.TaskID
+	le.stopper = newEarlyStopper(req.Params.TrainParams.GetEarlyStopping())
 
 	//if the request to create Learner from LiveEvaluator, the TaskID conforms such form like `{uuid}_{k}_train_LEv`,
 	le.evalLearnerID = fmt.Sprintf("%s_%d_train_LEv", req.TaskID, 0)
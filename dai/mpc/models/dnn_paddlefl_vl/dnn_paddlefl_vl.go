@@ -39,6 +39,7 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
 	pbDnnVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/dnn_paddlefl_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/util/docker"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 var (
@@ -158,6 +159,7 @@ func (model *Model) advance(message *pbDnnVl.PredictMessage) (*pb.PredictRespons
 	var ret *pb.PredictResponse
 	switch mType {
 	case pbDnnVl.MessageType_MsgPsiEnc: // local message
+		tracing.StartStage(model.id, tracing.StagePSI)
 		encIDs, err := model.psi.EncryptSampleIDSet()
 		if err != nil {
 			go handleError(err)
@@ -245,12 +247,18 @@ func (model *Model) advance(message *pbDnnVl.PredictMessage) (*pb.PredictRespons
 
 	case pbDnnVl.MessageType_MsgPsiIntersect: // local message
 		done, newRows, intersect, err := model.psi.IntersectParts()
+		if err == nil && done {
+			// PaddleFL takes dense rows, sparse rows are converted into dense ones
+			newRows, err = vl_common.DensifyRows(newRows)
+		}
 		if err != nil {
 			go handleError(err)
+			tracing.EndStage(model.id, tracing.StagePSI, err)
 			return nil, err
 		}
 
 		if done {
+			tracing.EndStage(model.id, tracing.StagePSI, nil)
 			model.status = modelStatusEndPSI
 			model.fileRows = newRows
 			model.intersect = intersect
@@ -720,6 +728,9 @@ func NewModel(id string, address string,
 	params *pbCom.TrainModels, samplesFile []byte,
 	parties []string, paddleFLParams *pbCom.PaddleFLParams, rpc RpcHandler, rh ResultHandler) (*Model, error) {
 
+	if err := psi.CheckMultiPartsAlgorithm(params.GetPsiAlgorithm()); err != nil {
+		return nil, err
+	}
 	p, err := psi.NewVLPSIByPairs(address, samplesFile, params.GetIdName(), parties)
 	if err != nil {
 		return nil, err
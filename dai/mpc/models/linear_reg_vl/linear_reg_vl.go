@@ -28,6 +28,7 @@ import (
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 	pbLinearRegVl "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc/learners/linear_reg_vl"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 var (
@@ -103,6 +104,13 @@ type Model struct {
 	predictPart          []float64 // local prediction part
 	predictPartFromOther []float64 // prediction part from other party
 
+	// explanations of the predictions if params.WithExplanations is set, which are the contributions of the local
+	// features and the ones of the features of other party, only the party who has target tag receives the latter
+	features               []string
+	contributions          [][]float64
+	featuresFromOther      []string
+	contributionsFromOther [][]float64
+
 	outcomes []float64 // final result
 
 	procMutex sync.Mutex
@@ -135,6 +143,7 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 	var ret *pb.PredictResponse
 	switch mType {
 	case pbLinearRegVl.MessageType_MsgPsiEnc: // local message
+		tracing.StartStage(model.id, tracing.StagePSI)
 		encIDs, err := model.psi.EncryptSampleIDSet()
 		if err != nil {
 			go handleError(err)
@@ -221,10 +230,12 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 		done, newRows, intersect, err := model.psi.IntersectParts()
 		if err != nil {
 			go handleError(err)
+			tracing.EndStage(model.id, tracing.StagePSI, err)
 			return nil, err
 		}
 
 		if done {
+			tracing.EndStage(model.id, tracing.StagePSI, nil)
 			model.fileRows = newRows
 			model.intersect = intersect
 			model.status = modelStatusEndPSI
@@ -252,8 +263,10 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 			if !model.params.IsTagPart {
 
 				newMess := &pbLinearRegVl.PredictMessage{
-					Type:        pbLinearRegVl.MessageType_MsgPredictPart,
-					PredictPart: predictPart,
+					Type:              pbLinearRegVl.MessageType_MsgPredictPart,
+					PredictPart:       predictPart,
+					ExplainedFeatures: model.features,
+					Contributions:     vl_common.FlattenContributions(model.contributions),
 				}
 				_, err = model.sendMessageWithRetry(newMess, model.parties[0])
 				if err != nil {
@@ -271,6 +284,10 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 
 	case pbLinearRegVl.MessageType_MsgPredictPart:
 		partFromOther := message.PredictPart
+		if err := model.setExplanationsFromOther(len(partFromOther), message.ExplainedFeatures, message.Contributions); err != nil {
+			go handleError(err)
+			return nil, err
+		}
 		model.setPredictPartFromOther(partFromOther)
 		ret = &pb.PredictResponse{
 			TaskID: model.id,
@@ -295,11 +312,13 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 		if modelStatusStartPredict == model.status {
 			if !model.params.IsTagPart {
 				model.status = modelStatusEndPredict
+				drift := model.detectDrift(nil)
 				go func() {
 					logger.WithField("IsTagPart", model.params.IsTagPart).Infof("model[%s] finished prediction.", model.id)
 					model.rh.SaveResult(&pbCom.PredictTaskResult{
 						TaskID:  model.id,
 						Success: true,
+						Drift:   drift,
 					})
 				}()
 
@@ -307,17 +326,19 @@ func (model *Model) advance(message *pbLinearRegVl.PredictMessage) (*pb.PredictR
 				done, outcomes := model.deStandardizeOutput()
 				if done {
 					model.status = modelStatusEndPredict
-					outs, err := vl_common.PredictResultToBytes(model.params.IdName, model.intersect, outcomes)
+					outs, err := model.predictResultToBytes(outcomes)
 					if err != nil {
 						go handleError(err)
 						return nil, err
 					}
+					drift := model.detectDrift(outcomes)
 					go func() {
 						logger.WithField("IsTagPart", model.params.IsTagPart).Infof("model[%s] finished prediction and outcomes are[%v].", model.id, outcomes)
 						model.rh.SaveResult(&pbCom.PredictTaskResult{
 							TaskID:   model.id,
 							Success:  true,
 							Outcomes: outs,
+							Drift:    drift,
 						})
 					}()
 				}
@@ -338,6 +359,9 @@ func (model *Model) predictLocalPart() (predictPart []float64, err error) {
 	}
 	model.predictPart = predictPart
 
+	if model.params.WithExplanations {
+		model.features, model.contributions, err = vl_common.FeatureContributions(model.fileRows, model.params)
+	}
 	return
 }
 
@@ -345,6 +369,20 @@ func (model *Model) setPredictPartFromOther(predictPart []float64) {
 	model.predictPartFromOther = predictPart
 }
 
+// setExplanationsFromOther sets the contributions of the features of other party to the predictions of sampleNum
+// samples, flattened row by row of samples, they are ignored if params.WithExplanations isn't set
+func (model *Model) setExplanationsFromOther(sampleNum int, features []string, contributions []float64) error {
+	if !model.params.WithExplanations {
+		return nil
+	}
+	unflattened, err := vl_common.UnflattenContributions(contributions, sampleNum, len(features))
+	if err != nil {
+		return errorx.Wrap(err, "invalid explanations from other party")
+	}
+	model.featuresFromOther, model.contributionsFromOther = features, unflattened
+	return nil
+}
+
 func (model *Model) deStandardizeOutput() (done bool, outcomes []float64) {
 	if len(model.predictPart) == 0 || len(model.predictPartFromOther) == 0 {
 		return
@@ -356,6 +394,33 @@ func (model *Model) deStandardizeOutput() (done bool, outcomes []float64) {
 	return
 }
 
+// predictResultToBytes converts outcomes to bytes for storage, the explanations of outcomes
+// are included if params.WithExplanations is set
+func (model *Model) predictResultToBytes(outcomes []float64) ([]byte, error) {
+	outs, err := vl_common.PredictResultToBytes(model.params.IdName, model.intersect, outcomes)
+	if err != nil || !model.params.WithExplanations {
+		return outs, err
+	}
+	base, contributions := linear.DeStandardizeContributions(model.params,
+		vl_common.JoinContributions(model.contributions, model.contributionsFromOther))
+	features := append(append([]string{}, model.features...), model.featuresFromOther...)
+	return vl_common.AppendExplanations(outs, base, features, contributions)
+}
+
+// detectDrift compares the local columns of the samples predicted with the training ones, and the predictions
+// with the label on the party with label. Drift is only reported, it never fails the prediction.
+func (model *Model) detectDrift(predictions []float64) *pbCom.DriftReport {
+	report, err := vl_common.DetectDrift(model.params, model.fileRows, predictions)
+	if err != nil {
+		logger.WithError(err).Warnf("model[%s] failed to detect drift", model.id)
+		return nil
+	}
+	if report.GetDrifted() {
+		logger.WithField("scores", report.Scores).Warnf("model[%s] detected drift of the samples predicted", model.id)
+	}
+	return report
+}
+
 // sendMessageWithRetry sends message to remote mpc-node
 // retries 2 times at most
 func (model *Model) sendMessageWithRetry(message *pbLinearRegVl.PredictMessage, address string) (*pbLinearRegVl.PredictMessage, error) {
@@ -418,7 +483,7 @@ func NewModel(id string, address string,
 	params *pbCom.TrainModels, samplesFile []byte,
 	parties []string, rpc RpcHandler, rh ResultHandler) (*Model, error) {
 
-	p, err := psi.NewVLTwoPartsPSI(address, samplesFile, params.GetIdName(), parties)
+	p, err := psi.NewVLTwoPartsPSIWithAlgorithm(params.GetPsiAlgorithm(), address, samplesFile, params.GetIdName(), parties)
 	if err != nil {
 		return nil, err
 	}
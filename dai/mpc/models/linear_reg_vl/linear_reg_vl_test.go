@@ -17,10 +17,14 @@ import (
 	"errors"
 	"io/ioutil"
 	"log"
+	"math"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
 	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/linear"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 )
@@ -270,6 +274,50 @@ func TestAdvance(t *testing.T) {
 	}
 }
 
+func TestPredictResultWithExplanations(t *testing.T) {
+	model := &Model{
+		params: &pbCom.TrainModels{
+			IdName: "id",
+			Label:  "y",
+			Thetas: map[string]float64{"Intercept": 0.25},
+			Xbars:  map[string]float64{"y": 10},
+			Sigmas: map[string]float64{"y": 2},
+		},
+		intersect:            []string{"1", "2"},
+		predictPart:          []float64{1, -0.5},
+		predictPartFromOther: []float64{0.5, -1},
+		features:             []string{"x1", "x2"},
+		contributions:        [][]float64{{0.5, 0.25}, {-0.5, -0.25}},
+	}
+	// explanations from other party are ignored without withExplanations
+	checkErr(model.setExplanationsFromOther(2, []string{"y1"}, []float64{0.5}), t)
+	model.params.WithExplanations = true
+	if err := model.setExplanationsFromOther(2, []string{"y1"}, []float64{0.5}); err == nil {
+		t.Error("expected error if explanations from other party don't match the samples")
+	}
+	checkErr(model.setExplanationsFromOther(2, []string{"y1"}, []float64{0.5, -1}), t)
+	outcomes := linear.DeStandardizeOutput(model.params, model.predictPart, model.predictPartFromOther)
+
+	content, err := model.predictResultToBytes(outcomes)
+	checkErr(err, t)
+	rows, err := vl_common.PredictResultFromBytes(content)
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[0], []string{"id", "value", "base", "contrib_x1", "contrib_x2", "contrib_y1"}) {
+		t.Fatalf("unexpected header with withExplanations: %v", rows[0])
+	}
+	// the base plus the de-standardized contributions is the prediction
+	for i, row := range rows[1:] {
+		sum := 0.0
+		for _, cell := range row[2:] {
+			v, _ := strconv.ParseFloat(cell, 64)
+			sum += v
+		}
+		if math.Abs(sum-outcomes[i]) > 1e-9 {
+			t.Errorf("explanations of %s sum to %v, expected the prediction %v", row[0], sum, outcomes[i])
+		}
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
@@ -17,10 +17,14 @@ import (
 	"errors"
 	"io/ioutil"
 	"log"
+	"math"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
 	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/logic"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 )
@@ -243,6 +247,83 @@ func TestAdvance(t *testing.T) {
 	}
 }
 
+func TestPredictResultWithScores(t *testing.T) {
+	model := &Model{
+		params:               &pbCom.TrainModels{IdName: "id"},
+		intersect:            []string{"1", "2"},
+		predictPart:          []float64{1, -0.5},
+		predictPartFromOther: []float64{0.5, -1},
+	}
+	outcomes := logic.CalRealPredictValue(model.predictPart, model.predictPartFromOther)
+
+	content, err := model.predictResultToBytes(outcomes)
+	checkErr(err, t)
+	rows, err := vl_common.PredictResultFromBytes(content)
+	checkErr(err, t)
+	if len(rows[0]) != 2 {
+		t.Errorf("outcomes should be [id, value] without withScores, got %v", rows[0])
+	}
+
+	model.params.WithScores = true
+	content, err = model.predictResultToBytes(outcomes)
+	checkErr(err, t)
+	rows, err = vl_common.PredictResultFromBytes(content)
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[0], []string{"id", "value", "class", "score"}) {
+		t.Fatalf("unexpected header with withScores: %v", rows[0])
+	}
+	for i, expected := range [][]string{{"1", "1", "1.5"}, {"2", "0", "-1.5"}} {
+		row := rows[i+1]
+		if row[0] != expected[0] || row[2] != expected[1] || row[3] != expected[2] {
+			t.Errorf("expected id, class and score %v, got %v", expected, row)
+		}
+		if value, _ := strconv.ParseFloat(row[1], 64); value != outcomes[i] {
+			t.Errorf("probability of %s should stay in the value column, got %v", row[0], row)
+		}
+	}
+}
+
+func TestPredictResultWithExplanations(t *testing.T) {
+	model := &Model{
+		params: &pbCom.TrainModels{
+			IdName:           "id",
+			Thetas:           map[string]float64{"Intercept": 0.25},
+			WithScores:       true,
+			WithExplanations: true,
+		},
+		intersect:            []string{"1", "2"},
+		predictPart:          []float64{1, -0.5},
+		predictPartFromOther: []float64{0.5, -1},
+		features:             []string{"x1", "x2"},
+		contributions:        [][]float64{{0.5, 0.25}, {-0.5, -0.25}},
+	}
+	if err := model.setExplanationsFromOther(2, []string{"y1"}, []float64{0.5}); err == nil {
+		t.Error("expected error if explanations from other party don't match the samples")
+	}
+	checkErr(model.setExplanationsFromOther(2, []string{"y1"}, []float64{0.5, -1}), t)
+	outcomes := logic.CalRealPredictValue(model.predictPart, model.predictPartFromOther)
+
+	content, err := model.predictResultToBytes(outcomes)
+	checkErr(err, t)
+	rows, err := vl_common.PredictResultFromBytes(content)
+	checkErr(err, t)
+	if !reflect.DeepEqual(rows[0], []string{"id", "value", "class", "score", "base", "contrib_x1", "contrib_x2", "contrib_y1"}) {
+		t.Fatalf("unexpected header with withExplanations: %v", rows[0])
+	}
+	// the base plus the contributions is the raw score
+	for _, row := range rows[1:] {
+		score, _ := strconv.ParseFloat(row[3], 64)
+		sum := 0.0
+		for _, cell := range row[4:] {
+			v, _ := strconv.ParseFloat(cell, 64)
+			sum += v
+		}
+		if math.Abs(sum-score) > 1e-9 {
+			t.Errorf("explanations of %s sum to %v, expected the score %v", row[0], sum, score)
+		}
+	}
+}
+
 func checkErr(err error, t *testing.T) {
 	if err != nil {
 		t.Error(err)
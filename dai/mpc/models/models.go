@@ -17,6 +17,7 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/models/dnn_paddlefl_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/models/linear_reg_vl"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/models/logic_reg_vl"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/models/xgboost_vl"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
 )
@@ -65,6 +66,8 @@ func NewModel(id string, address string, algo pbCom.Algorithm,
 			parties, rpc, rh)
 	} else if pbCom.Algorithm_DNN_PADDLEFL_VL == algo {
 		return dnn_paddlefl_vl.NewModel(id, address, params, samplesFile, parties, paddleFLParams, rpc, rh)
+	} else if pbCom.Algorithm_XGBOOST_VL == algo {
+		return xgboost_vl.NewModel(id, address, params, samplesFile, parties, rpc, rh)
 	} else { // pbCom.Algorithm_LOGIC_REGRESSION_VL
 		return logic_reg_vl.NewModel(id, address, params, samplesFile,
 			parties, rpc, rh)
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgboost_vl
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"testing"
+	"time"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+)
+
+type rpc struct {
+	reqC  chan *pb.PredictRequest
+	respC chan *pb.PredictResponse
+}
+
+func (r *rpc) StepPredict(req *pb.PredictRequest, peerName string) (*pb.PredictResponse, error) {
+	r.reqC <- req
+	resp := <-r.respC
+	if resp != nil {
+		return resp, nil
+	}
+	return nil, errors.New("test response error")
+}
+
+func (r *rpc) StepPredictWithRetry(req *pb.PredictRequest, peerName string, times int, inteSec int64) (*pb.PredictResponse, error) {
+	return r.StepPredict(req, peerName)
+}
+
+type resHandler struct {
+	resC chan *pbCom.PredictTaskResult
+}
+
+func (rd *resHandler) SaveResult(result *pbCom.PredictTaskResult) {
+	rd.resC <- result
+}
+
+func TestAdvance(t *testing.T) {
+	// model1 has no label, and owns the root split of the tree
+	address1 := "127.0.0.1:8080"
+	params1 := &pbCom.TrainModels{
+		Label:     "Label",
+		IsTagPart: false,
+		IdName:    "id",
+		Xgboost: &pbCom.XGBoostModel{
+			Trees: []*pbCom.XGBoostTree{{Nodes: []*pbCom.XGBoostNode{
+				{Id: 0, IsLocal: true, Feature: "Sepal Length", Threshold: 5.5},
+			}}},
+		},
+	}
+	rpc1 := &rpc{reqC: make(chan *pb.PredictRequest), respC: make(chan *pb.PredictResponse)}
+	rh1 := &resHandler{resC: make(chan *pbCom.PredictTaskResult)}
+	samplesFile1, err := ioutil.ReadFile("../../testdata/vl/logic_iris_plants/predict_dataA.csv")
+	checkErr(err, t)
+
+	// model2 has label, and owns the tree structure and the leaves
+	address2 := "127.0.0.1:8081"
+	params2 := &pbCom.TrainModels{
+		Label:     "Label",
+		IsTagPart: true,
+		IdName:    "id",
+		Xgboost: &pbCom.XGBoostModel{
+			BinaryClass: true,
+			Trees: []*pbCom.XGBoostTree{{Nodes: []*pbCom.XGBoostNode{
+				{Id: 0},
+				{Id: 1, IsLocal: true, Feature: "Petal Length", Threshold: 2.5},
+				{Id: 2, IsLeaf: true, Weight: -2},
+				{Id: 3, IsLeaf: true, Weight: 2},
+				{Id: 4, IsLeaf: true, Weight: -1},
+			}}},
+		},
+	}
+	rpc2 := &rpc{reqC: make(chan *pb.PredictRequest), respC: make(chan *pb.PredictResponse)}
+	rh2 := &resHandler{resC: make(chan *pbCom.PredictTaskResult)}
+	samplesFile2, err := ioutil.ReadFile("../../testdata/vl/logic_iris_plants/predict_dataB.csv")
+	checkErr(err, t)
+
+	model1, err := NewModel("test-model-1", address1, params1, samplesFile1, []string{address2}, rpc1, rh1)
+	checkErr(err, t)
+	model2, err := NewModel("test-model-2", address2, params2, samplesFile2, []string{address1}, rpc2, rh2)
+	checkErr(err, t)
+
+	var res1, res2 *pbCom.PredictTaskResult
+	timeout := time.After(time.Minute)
+	for res1 == nil || res2 == nil {
+		select {
+		case req := <-rpc1.reqC:
+			resp, err := model2.Advance(req.GetPayload())
+			if err != nil {
+				log.Printf("model2.Advance err: %s", err.Error())
+			}
+			rpc1.respC <- resp
+		case req := <-rpc2.reqC:
+			resp, err := model1.Advance(req.GetPayload())
+			if err != nil {
+				log.Printf("model1.Advance err: %s", err.Error())
+			}
+			rpc2.respC <- resp
+		case res1 = <-rh1.resC:
+		case res2 = <-rh2.resC:
+		case <-timeout:
+			t.Fatal("prediction timeout")
+		}
+	}
+	if !res1.Success || !res2.Success {
+		t.Fatalf("prediction failed, model1: %s, model2: %s", res1.ErrMsg, res2.ErrMsg)
+	}
+	if len(res1.Outcomes) != 0 {
+		t.Error("party without label should not get outcomes")
+	}
+
+	outcomes, err := vl_common.PredictResultFromBytes(res2.Outcomes)
+	checkErr(err, t)
+	if len(outcomes) != 16 { // header and 15 samples
+		t.Fatalf("expected 16 rows, got %d", len(outcomes))
+	}
+	for _, row := range outcomes[1:] {
+		p, err := strconv.ParseFloat(row[1], 64)
+		checkErr(err, t)
+		if p <= 0 || p >= 1 {
+			t.Errorf("outcome %v of sample %s is not a probability", p, row[0])
+		}
+	}
+}
+
+func TestNewModelWithoutXgboost(t *testing.T) {
+	params := &pbCom.TrainModels{Label: "Label", IdName: "id"}
+	if _, err := NewModel("test-model", "127.0.0.1:8080", params, []byte("id,a\n1,1\n"), []string{"127.0.0.1:8081"}, nil, nil); err == nil {
+		t.Error("expected error without xgboost model")
+	}
+}
+
+func checkErr(err error, t *testing.T) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/cluster"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/livaluator"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/predictor"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/trainer"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
@@ -40,10 +41,18 @@ type Mpc interface {
 	// StopTask stops a specific task of training or prediction
 	StopTask(*pbCom.StopTaskRequest) error
 
+	// CancelTask aborts the remote procedure calls of a task in progress, then stops it,
+	// other tasks keep running
+	CancelTask(*pbCom.StopTaskRequest) error
+
 	// Validate writes the prediction results to the Evaluator or LiveEvaluator,
 	//  then trigger the subsequent verification process.
 	Validate(*pb.ValidateRequest) error
 
+	// UpdateConfig applies new task limits and rpc timeout at runtime,
+	// the limits take effect for tasks started later, and the timeout for rpc requests sent later
+	UpdateConfig(conf Config)
+
 	// Stop performs any necessary termination of the node
 	Stop()
 }
@@ -72,6 +81,9 @@ type Trainer interface {
 	// Validate saves the prediction results to the Evaluator or LiveEvaluator,
 	// then trigger the subsequent verification process.
 	Validate(*pb.ValidateRequest, chan *trainer.TrainResponse)
+
+	// SetLearnerLimit changes the upper limit of the number of Learners
+	SetLearnerLimit(int)
 }
 
 // Predictor manages Models, such as to create or to delete a model
@@ -86,6 +98,9 @@ type Predictor interface {
 	// Predict dispatches requests to different Models by taskId during prediction processes
 	// Response channel returns the result, and couldn't be set with nil
 	Predict(*pb.PredictRequest, chan *predictor.PredictResponse)
+
+	// SetModelLimit changes the upper limit of the number of Models
+	SetModelLimit(int)
 }
 
 type trainRequest struct {
@@ -109,6 +124,8 @@ type mpc struct {
 	doneC     chan struct{}       // Closes when the mpc-node is stopped
 	trainC    chan trainRequest   // Signal to handle training request
 	predictC  chan predictRequest // Signal to handle prediction request
+	configC   chan Config         // Signal to apply new task limits
+	rpc       cluster.Rpc
 	trainer   Trainer
 	predictor Predictor
 }
@@ -276,6 +293,27 @@ func (m *mpc) StopTask(req *pbCom.StopTaskRequest) error {
 	return nil
 }
 
+// CancelTask aborts the remote procedure calls of a task in progress, then stops it.
+// The goroutines of the task waiting for remote nodes return immediately,
+// and the Learner or Model is deleted, as well as the sample files it stores.
+func (m *mpc) CancelTask(req *pbCom.StopTaskRequest) error {
+	if err := m.isRunning(); err != nil {
+		return err
+	}
+	m.rpc.CancelTask(req.TaskID)
+	return m.StopTask(req)
+}
+
+// UpdateConfig applies new task limits and rpc timeout at runtime.
+// Limits are changed by the running goroutine, because Trainer and Predictor are not thread-safe
+func (m *mpc) UpdateConfig(conf Config) {
+	m.rpc.SetTimeout(conf.RpcTimeout)
+	select {
+	case m.configC <- conf:
+	case <-m.doneC:
+	}
+}
+
 // run listens message and processes it
 func (m *mpc) run() {
 	for {
@@ -314,6 +352,9 @@ func (m *mpc) run() {
 			} else { // to do local prediction with outcomes from remote node
 				m.predictor.Predict(pReq.predictRequest, pReq.responseC)
 			}
+		case conf := <-m.configC:
+			m.trainer.SetLearnerLimit(learnerLimit(conf))
+			m.predictor.SetModelLimit(modelLimit(conf))
 		case <-m.stopC:
 			close(m.doneC)
 			return
@@ -351,6 +392,14 @@ type TrainCallBack struct {
 	Mpc
 }
 
+// ReportLiveEvaluation passes the metric scores of live evaluation to ModelHolder
+// if it implements livaluator.MetricsReporter
+func (c *TrainCallBack) ReportLiveEvaluation(taskID string, round uint64, metrics []livaluator.Metric) {
+	if r, ok := c.ModelHolder.(livaluator.MetricsReporter); ok {
+		r.ReportLiveEvaluation(taskID, round, metrics)
+	}
+}
+
 // PredictCallBack contains some methods would be called when finish prediction
 type PredictCallBack struct {
 	//modelHolder
@@ -364,31 +413,58 @@ type Config struct {
 	Address          string        // local address, like ip:port
 	TrainTaskLimit   int           // indicates the upper limit of the number of training task
 	PredictTaskLimit int           // indicates the upper limit of the number of prediction task
-	RpcTimeout       time.Duration // rpc connection releases when timeout elapses. eg. 3 means 3*time.Second
+	RpcTimeout       time.Duration // rpc connection releases when timeout elapses. eg. 3*time.Second
+	// CheckpointInterval is the number of rounds between two checkpoints of a training task, zero disables checkpoints.
+	// Checkpoints are persisted by ModelHolder which implements trainer.CheckpointHolder
+	CheckpointInterval int
 }
 
 func newMpc(mh ModelHolder, p2p P2P, conf Config) *mpc {
-	rpcHandler := cluster.NewRpcClient(p2p, conf.RpcTimeout*time.Second)
+	return newMpcWithRpc(mh, cluster.NewRpcClient(p2p, conf.RpcTimeout), conf)
+}
 
+// newMpcWithRpc creates a mpc instance communicating with other nodes by rpcHandler
+func newMpcWithRpc(mh ModelHolder, rpcHandler cluster.Rpc, conf Config) *mpc {
 	m := &mpc{
 		stopC:    make(chan struct{}),
 		doneC:    make(chan struct{}),
 		trainC:   make(chan trainRequest),
 		predictC: make(chan predictRequest),
+		configC:  make(chan Config),
+		rpc:      rpcHandler,
 	}
 	trainCallback := TrainCallBack{ModelHolder: mh, Mpc: m}
-	m.trainer = trainer.NewTrainer(conf.Address, rpcHandler, &trainCallback, conf.TrainTaskLimit*21+600)
+	t := trainer.NewTrainer(conf.Address, rpcHandler, &trainCallback, learnerLimit(conf))
+	if ch, ok := mh.(trainer.CheckpointHolder); ok && conf.CheckpointInterval > 0 {
+		t.SetCheckpointHolder(ch, conf.CheckpointInterval)
+	}
+	if po, ok := mh.(cluster.PeerObserver); ok {
+		if rc, ok := rpcHandler.(*cluster.RpcClient); ok {
+			rc.SetPeerObserver(po)
+		}
+	}
+	m.trainer = t
+
+	predictCallBack := PredictCallBack{ModelHolder: mh, Mpc: m}
+	m.predictor = predictor.NewPredictor(conf.Address, rpcHandler, &predictCallBack, modelLimit(conf))
+
+	return m
+}
+
+// learnerLimit returns the upper limit of the number of Learners
+func learnerLimit(conf Config) int {
 	//there will be 10 more learners running in parallel if one 10-fold cross validation is invoked
 	//there will be 1 more learners running in parallel if one live evaluation is invoked
 	//and, reserve 600 positions for LOO(one way to evaluate model)
+	return conf.TrainTaskLimit*21 + 600
+}
 
-	predictCallBack := PredictCallBack{ModelHolder: mh, Mpc: m}
-	m.predictor = predictor.NewPredictor(conf.Address, rpcHandler, &predictCallBack, conf.PredictTaskLimit+conf.TrainTaskLimit*21+600)
+// modelLimit returns the upper limit of the number of Models
+func modelLimit(conf Config) int {
 	//there will be 10 more models running in parallel if one 10-fold cross validation is invoked
 	//there will be several more models running if one live evaluation is invoked
 	//and, reserve 600 positions for LOO(one way to evaluate model)
-
-	return m
+	return conf.PredictTaskLimit + conf.TrainTaskLimit*21 + 600
 }
 
 // StartMpc creates a mpc instance and run it
@@ -61,7 +61,7 @@ func TestLinearRegVlLe(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -105,7 +105,7 @@ func TestLinearRegVlLe(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -336,7 +336,7 @@ func TestLogisticRegVlLe(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -380,7 +380,7 @@ func TestLogisticRegVlLe(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
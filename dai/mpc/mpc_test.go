@@ -21,6 +21,7 @@ import (
 	"time"
 
 	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/cluster"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/predictor"
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/trainer"
 	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
@@ -52,7 +53,7 @@ func TestMpc(t *testing.T) {
 		Address:          "127.0.0.1:8080",
 		TrainTaskLimit:   5,
 		PredictTaskLimit: 10,
-		RpcTimeout:       3,
+		RpcTimeout:       3 * time.Second,
 	}
 	testMpc := StartMpc(mh, testP2P, config)
 
@@ -141,6 +142,56 @@ func TestMpc(t *testing.T) {
 	testMpc.Stop()
 }
 
+type limitRecorder struct {
+	Trainer
+	Predictor
+	learnerLimit int
+	modelLimit   int
+}
+
+func (lr *limitRecorder) SetLearnerLimit(limit int) {
+	lr.learnerLimit = limit
+}
+
+func (lr *limitRecorder) SetModelLimit(limit int) {
+	lr.modelLimit = limit
+}
+
+type timeoutRecorder struct {
+	cluster.Rpc
+	timeout time.Duration
+}
+
+func (tr *timeoutRecorder) SetTimeout(timeout time.Duration) {
+	tr.timeout = timeout
+}
+
+func TestUpdateConfig(t *testing.T) {
+	testMpc := newMpc(&testModelHolder{}, testP2P, Config{
+		Address:          "127.0.0.1:8080",
+		TrainTaskLimit:   5,
+		PredictTaskLimit: 10,
+		RpcTimeout:       3 * time.Second,
+	})
+	recorder := &limitRecorder{}
+	testMpc.trainer = recorder
+	testMpc.predictor = recorder
+	rpc := &timeoutRecorder{}
+	testMpc.rpc = rpc
+	go testMpc.run()
+
+	conf := Config{TrainTaskLimit: 1, PredictTaskLimit: 2, RpcTimeout: 5 * time.Second}
+	testMpc.UpdateConfig(conf)
+	testMpc.Stop()
+
+	if recorder.learnerLimit != learnerLimit(conf) || recorder.modelLimit != modelLimit(conf) {
+		t.Errorf("limits not updated, learnerLimit: %d, modelLimit: %d", recorder.learnerLimit, recorder.modelLimit)
+	}
+	if rpc.timeout != conf.RpcTimeout {
+		t.Errorf("rpc timeout not updated, got %v", rpc.timeout)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	mh := &testModelHolder{}
 
@@ -148,7 +199,7 @@ func TestValidate(t *testing.T) {
 		Address:          "127.0.0.1:8080",
 		TrainTaskLimit:   5,
 		PredictTaskLimit: 10,
-		RpcTimeout:       3,
+		RpcTimeout:       3 * time.Second,
 	}
 	testMpc := StartMpc(mh, testP2P, config)
 
@@ -310,7 +361,7 @@ func TestEvaluRegressionRandomSplit(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -354,7 +405,7 @@ func TestEvaluRegressionRandomSplit(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -573,7 +624,7 @@ func TestEvaluRegressionCV(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -617,7 +668,7 @@ func TestEvaluRegressionCV(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -839,7 +890,7 @@ func TestEvaluRegressionLoo(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -883,7 +934,7 @@ func TestEvaluRegressionLoo(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -1096,7 +1147,7 @@ func TestEvaluBinClassRandomSplit(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -1140,7 +1191,7 @@ func TestEvaluBinClassRandomSplit(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -1361,7 +1412,7 @@ func TestEvaluBinClassCV(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -1405,7 +1456,7 @@ func TestEvaluBinClassCV(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -1628,7 +1679,7 @@ func TestEvaluBinClassLoo(t *testing.T) {
 		Address:          ":8080",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback1 := TrainCallBack{ModelHolder: mh1, Mpc: mpc1}
@@ -1672,7 +1723,7 @@ func TestEvaluBinClassLoo(t *testing.T) {
 		Address:          ":8081",
 		TrainTaskLimit:   1000,
 		PredictTaskLimit: 2000,
-		RpcTimeout:       time.Duration(3),
+		RpcTimeout:       3 * time.Second,
 	}
 
 	trainCallback2 := TrainCallBack{ModelHolder: mh2, Mpc: mpc2}
@@ -24,6 +24,7 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/models"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
 )
 
 var (
@@ -117,7 +118,7 @@ func (p *Predictor) DeleteModel(req *pbCom.StopTaskRequest) error {
 	taskId := req.TaskID
 	p.deleteModel(taskId)
 
-	logger.WithField("taskId", taskId).Info("task deleted")
+	logger.WithField(logging.TaskIDKey, taskId).Info("task deleted")
 	return nil
 }
 
@@ -157,11 +158,11 @@ func (p *Predictor) SaveResult(result *pbCom.PredictTaskResult) {
 		if result.Success {
 			err := p.callback.Validate(validReq)
 			if err != nil {
-				logger.WithField("taskId", result.TaskID).Errorf("failed to trigger validation with prediction outcomes[%v], and error is[%s]",
+				logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to trigger validation with prediction outcomes[%v], and error is[%s]",
 					result.Outcomes, err.Error())
 			}
 		} else {
-			logger.WithField("taskId", result.TaskID).Errorf("prediction task from evaluation has failed and error is[%s]", result.ErrMsg)
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("prediction task from evaluation has failed and error is[%s]", result.ErrMsg)
 		}
 	} else {
 		// the prediction task is a common task from user
@@ -169,12 +170,12 @@ func (p *Predictor) SaveResult(result *pbCom.PredictTaskResult) {
 
 		err := p.callback.SavePredictOut(result)
 		if err != nil {
-			logger.WithField("taskId", result.TaskID).Errorf("failed to save outcomes[%v] and prediction result[%t], and error is[%s]",
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to save outcomes[%v] and prediction result[%t], and error is[%s]",
 				result.Outcomes, result.Success, err.Error())
 		}
 	}
 
-	logger.WithField("taskId", result.TaskID).Infof("Stop prediction task. And delete outcomes[%v] and prediction result[%t]",
+	logger.WithField(logging.TaskIDKey, result.TaskID).Infof("Stop prediction task. And delete outcomes[%v] and prediction result[%t]",
 		result.Outcomes, result.Success)
 
 	// stop the related task
@@ -246,6 +247,12 @@ func (p *Predictor) deleteModel(taskId string) {
 	delete(p.models, taskId)
 }
 
+// SetModelLimit changes the upper limit of the number of Models,
+// running Models are kept even if the number exceeds the new limit
+func (p *Predictor) SetModelLimit(modelLimit int) {
+	p.modelLimit = modelLimit
+}
+
 // NewPredictor creates a Predictor instance,
 // address indicates local mpc-node address
 // modelLimit indicates the upper limit of the number of Models
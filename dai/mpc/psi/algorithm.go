@@ -0,0 +1,163 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package psi
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+)
+
+const (
+	// AlgorithmECDH aligns samples by ECDH based commutative encryption of IDs, the default one
+	AlgorithmECDH = "ecdh"
+	// AlgorithmOPRF aligns samples by OPRF of IDs, which computes on all cpus in parallel
+	AlgorithmOPRF = "oprf"
+	// AlgorithmAuto selects OPRF if the samples are no less than AutoOPRFMinSamples, otherwise ECDH,
+	// the Executors of a task resolve it by the larger number of their samples, so they select the same one
+	AlgorithmAuto = "auto"
+	// AlgorithmCached reuses the cached alignment of the samples, it's set by executors agreeing on the alignment
+	// rather than by tasks, see CachedAlignments
+	AlgorithmCached = "cached"
+
+	// AutoOPRFMinSamples is the number of samples from which AlgorithmAuto selects OPRF,
+	// the speedup of parallel computation is not worth its overhead for smaller sets
+	AutoOPRFMinSamples = 50000
+)
+
+// oprfMagic prefixes the messages of OPRF based PSI, while ones of ECDH based PSI are JSON objects
+var oprfMagic = []byte("OPRF")
+
+// NewVLTwoPartsPSIWithAlgorithm creates a VLPSI instance of the algorithm for two parties,
+// empty algorithm means AlgorithmECDH, and AlgorithmAuto not resolved by the Executors yet selects one
+// by the number of local samples.
+// All parties should use the same algorithm, otherwise PSI fails with ErrCodePSIAlgorithmMismatch.
+func NewVLTwoPartsPSIWithAlgorithm(algorithm string, name string, samplesFile []byte, samplesIdName string,
+	parties []string) (VLPSI, error) {
+	switch ResolveAlgorithm(algorithm, SampleNum(samplesFile)) {
+	case AlgorithmECDH:
+		return NewVLTwoPartsPSI(name, samplesFile, samplesIdName, parties)
+	case AlgorithmOPRF:
+		return NewVLTwoPartsOPRFPSI(name, samplesFile, samplesIdName, parties)
+	case AlgorithmCached:
+		return NewVLCachedPSI(name, samplesFile, samplesIdName, parties)
+	default:
+		return nil, errorx.New(errcodes.ErrCodeParam, "unknown PSI algorithm: %s", algorithm)
+	}
+}
+
+// NewVLTwoPartsPSIWithAlignment creates a VLPSI instance of the algorithm for two parties like
+// NewVLTwoPartsPSIWithAlgorithm, and the intersection is cached as the alignment of alignmentKey
+// if it's not empty and the algorithm is not AlgorithmCached
+func NewVLTwoPartsPSIWithAlignment(algorithm, alignmentKey string, name string, samplesFile []byte, samplesIdName string,
+	parties []string) (VLPSI, error) {
+	p, err := NewVLTwoPartsPSIWithAlgorithm(algorithm, name, samplesFile, samplesIdName, parties)
+	if err != nil {
+		return nil, err
+	}
+	algorithm = ResolveAlgorithm(algorithm, SampleNum(samplesFile))
+	if alignmentKey == "" || algorithm == AlgorithmCached || !AlignmentCacheEnabled() {
+		return p, nil
+	}
+	return &vlCachingPsi{
+		VLPSI:     p,
+		key:       alignmentKey,
+		algorithm: algorithm,
+		name:      name,
+		digest:    SamplesDigest(samplesFile, samplesIdName),
+		peers:     parties,
+	}, nil
+}
+
+// ResolveAlgorithm returns the algorithm of two parties PSI, which is AlgorithmECDH for empty algorithm,
+// and is selected by the largest of sampleNums, the numbers of samples of the parties, for AlgorithmAuto
+func ResolveAlgorithm(algorithm string, sampleNums ...int64) string {
+	switch algorithm {
+	case "":
+		return AlgorithmECDH
+	case AlgorithmAuto:
+		return selectAlgorithm(sampleNums)
+	}
+	return algorithm
+}
+
+// SampleNum returns the number of samples of samplesFile counted by lines, by which AlgorithmAuto is resolved
+func SampleNum(samplesFile []byte) int64 {
+	return int64(bytes.Count(samplesFile, []byte("\n")))
+}
+
+// CheckMultiPartsAlgorithm checks the algorithm is supported by PSI of multiple parties, which is ECDH only
+func CheckMultiPartsAlgorithm(algorithm string) error {
+	if algorithm != "" && algorithm != AlgorithmECDH && algorithm != AlgorithmAuto {
+		return errorx.New(errcodes.ErrCodeParam, "PSI algorithm %s is not supported by multiple parties, use %s",
+			algorithm, AlgorithmECDH)
+	}
+	return nil
+}
+
+// selectAlgorithm selects the algorithm by the largest of sampleNums
+func selectAlgorithm(sampleNums []int64) string {
+	for _, n := range sampleNums {
+		if n >= AutoOPRFMinSamples {
+			return AlgorithmOPRF
+		}
+	}
+	return AlgorithmECDH
+}
+
+// algorithmOf returns the algorithm of a PSI message
+func algorithmOf(message []byte) string {
+	if bytes.HasPrefix(message, oprfMagic) {
+		return AlgorithmOPRF
+	}
+	if bytes.HasPrefix(message, cachedMagic) {
+		return AlgorithmCached
+	}
+	return AlgorithmECDH
+}
+
+// checkAlgorithm checks the message from party is of the local algorithm
+func checkAlgorithm(algorithm string, party string, message []byte) error {
+	if remote := algorithmOf(message); remote != algorithm {
+		return errorx.New(errcodes.ErrCodePSIAlgorithmMismatch, "PSI algorithm of party[%s] is %s, but local one is %s",
+			party, remote, algorithm)
+	}
+	return nil
+}
+
+// timer measures the time of PSI from encrypting local IDs to the intersection
+type timer struct {
+	start    time.Time
+	observed bool
+}
+
+// begin starts timing once
+func (t *timer) begin() {
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+}
+
+// end observes the time of algorithm once
+func (t *timer) end(algorithm string) {
+	if t.observed || t.start.IsZero() {
+		return
+	}
+	t.observed = true
+	metrics.PSIObserved(algorithm, time.Since(t.start))
+}
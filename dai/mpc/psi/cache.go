@@ -0,0 +1,216 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package psi
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// alignment is an intersection of two parties PSI cached by the local party
+type alignment struct {
+	algorithm string
+	name      string   // local party
+	digest    string   // SamplesDigest of the local samples
+	peers     string   // sorted other parties joined by ","
+	ids       []string // sorted IDs of the intersection
+	expires   time.Time
+	used      time.Time
+}
+
+// alignmentCache caches the intersections of PSI by the local party and the keys computed from the samples of all parties,
+// so a task over the same samples as an earlier one may reuse its intersection instead of running PSI.
+// An alignment is never hit once the samples of either party change, as its key changes with them,
+// and it's dropped when it expires or the least recently used alignments are evicted for new ones.
+type alignmentCache struct {
+	lock       sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*alignment // by the local party and the key, see entryKey
+	secret     []byte                // key of SamplesDigest, never leaves the node
+	now        func() time.Time
+}
+
+// DefaultAlignmentCacheTTL is how long a cached alignment is kept if the TTL is not set
+const DefaultAlignmentCacheTTL = 24 * time.Hour
+
+var cache, _ = newAlignmentCache(0, 0)
+
+// newAlignmentCache returns a cache of at most maxEntries alignments, a disabled cache needs no secret,
+// as SamplesDigest is only computed when alignments are cached
+func newAlignmentCache(ttl time.Duration, maxEntries int) (*alignmentCache, error) {
+	if ttl <= 0 {
+		ttl = DefaultAlignmentCacheTTL
+	}
+	var secret []byte
+	if maxEntries > 0 {
+		// the cache is in memory, so is the secret, digests are only compared with the ones computed by the same cache
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, errorx.Internal(err, "failed to generate the secret of alignment cache")
+		}
+	}
+	return &alignmentCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*alignment),
+		secret:     secret,
+		now:        time.Now,
+	}, nil
+}
+
+// SetAlignmentCache sets the TTL of cached alignments, DefaultAlignmentCacheTTL if ttl is zero,
+// and the maximum number of them, zero disables the cache. Cached alignments are dropped.
+// The cache is disabled if it fails to be set.
+func SetAlignmentCache(ttl time.Duration, maxEntries int) error {
+	c, err := newAlignmentCache(ttl, maxEntries)
+	if err != nil {
+		cache, _ = newAlignmentCache(ttl, 0)
+		return err
+	}
+	cache = c
+	return nil
+}
+
+// AlignmentCacheEnabled returns whether alignments are cached
+func AlignmentCacheEnabled() bool {
+	return cache.maxEntries > 0
+}
+
+// SamplesDigest returns the hex encoded HMAC of the samples file and the name of its ID column under the secret
+// of the local cache, which identifies the samples of a party in the keys of alignments. It's sent to the other party,
+// who can't recompute it to confirm guesses about the samples.
+func SamplesDigest(samplesFile []byte, samplesIdName string) string {
+	h := hmac.New(sha256.New, cache.secret)
+	h.Write([]byte(samplesIdName))
+	h.Write([]byte{0})
+	h.Write(samplesFile)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AlignmentKey returns the key of the alignment of the parties by algorithm,
+// digests maps the name of each party to the SamplesDigest of its samples.
+// All parties compute the same key for the same samples.
+func AlignmentKey(algorithm string, digests map[string]string) string {
+	var parties []string
+	for name, digest := range digests {
+		parties = append(parties, name+"="+digest)
+	}
+	sort.Strings(parties)
+	sum := sha256.Sum256([]byte(algorithm + "\n" + strings.Join(parties, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedAlignments returns the alignments cached by party name with the samples of digest, keyed by their keys,
+// whose other parties are peers and PSI algorithm is algorithm
+func CachedAlignments(algorithm, name, digest string, peers []string) map[string][]string {
+	return cache.list(algorithm, name, digest, joinPeers(peers))
+}
+
+// LoadAlignment returns the IDs of the alignment of key cached by party name, which are sorted
+func LoadAlignment(name, key string) ([]string, bool) {
+	return cache.load(entryKey(name, key))
+}
+
+// storeAlignment caches the intersection ids of PSI by party name with the samples of digest as the alignment of key
+func storeAlignment(key, algorithm, name, digest string, peers []string, ids []string) {
+	if key == "" {
+		return
+	}
+	cache.store(entryKey(name, key), &alignment{
+		algorithm: algorithm,
+		name:      name,
+		digest:    digest,
+		peers:     joinPeers(peers),
+		ids:       ids,
+	})
+}
+
+func entryKey(name, key string) string {
+	return name + "/" + key
+}
+
+func joinPeers(peers []string) string {
+	sorted := append([]string{}, peers...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func (c *alignmentCache) list(algorithm, name, digest, peers string) map[string][]string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
+
+	alignments := make(map[string][]string)
+	for k, a := range c.entries {
+		if a.algorithm == algorithm && a.name == name && a.digest == digest && a.peers == peers {
+			alignments[strings.TrimPrefix(k, name+"/")] = a.ids
+		}
+	}
+	return alignments
+}
+
+func (c *alignmentCache) load(key string) ([]string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
+
+	a, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	a.used = c.now()
+	return a.ids, true
+}
+
+func (c *alignmentCache) store(key string, a *alignment) {
+	if c.maxEntries <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
+
+	now := c.now()
+	a.expires, a.used = now.Add(c.ttl), now
+	c.entries[key] = a
+	// evict the least recently used alignments
+	for len(c.entries) > c.maxEntries {
+		var lru string
+		for k, e := range c.entries {
+			if lru == "" || e.used.Before(c.entries[lru].used) {
+				lru = k
+			}
+		}
+		delete(c.entries, lru)
+	}
+}
+
+// expire drops the expired alignments, the caller should hold the lock
+func (c *alignmentCache) expire() {
+	now := c.now()
+	for k, a := range c.entries {
+		if !now.Before(a.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
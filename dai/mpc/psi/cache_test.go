@@ -0,0 +1,184 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package psi
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+func TestAlignmentCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c, err := newAlignmentCache(time.Hour, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now }
+
+	store := func(key, digest string) {
+		c.store(entryKey("a", key), &alignment{algorithm: AlgorithmECDH, name: "a", digest: digest, peers: "b", ids: []string{key}})
+	}
+	store("k1", "d1")
+	now = now.Add(time.Minute)
+	store("k2", "d1")
+	now = now.Add(time.Minute)
+	// k1 is used later than k2, so k2 is evicted
+	if _, ok := c.load(entryKey("a", "k1")); !ok {
+		t.Fatal("k1 not cached")
+	}
+	now = now.Add(time.Minute)
+	store("k3", "d2")
+	if _, ok := c.load(entryKey("a", "k2")); ok {
+		t.Error("the least recently used k2 not evicted")
+	}
+	if got := c.list(AlgorithmECDH, "a", "d1", "b"); !reflect.DeepEqual(got, map[string][]string{"k1": {"k1"}}) {
+		t.Errorf("unexpected alignments of d1: %v", got)
+	}
+	if got := c.list(AlgorithmOPRF, "a", "d2", "b"); len(got) != 0 {
+		t.Errorf("alignments of another algorithm listed: %v", got)
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := c.load(entryKey("a", "k3")); ok {
+		t.Error("expired k3 still cached")
+	}
+
+	disabled, err := newAlignmentCache(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled.store(entryKey("a", "k1"), &alignment{})
+	if _, ok := disabled.load(entryKey("a", "k1")); ok || disabled.ttl != DefaultAlignmentCacheTTL {
+		t.Error("disabled cache stores alignments")
+	}
+}
+
+func TestAlignmentKey(t *testing.T) {
+	digestA := SamplesDigest([]byte("id,x\n1,2\n"), "id")
+	digestB := SamplesDigest([]byte("id,y\n1,3\n"), "id")
+	key := AlignmentKey(AlgorithmECDH, map[string]string{"address1": digestA, "address2": digestB})
+	if key != AlignmentKey(AlgorithmECDH, map[string]string{"address2": digestB, "address1": digestA}) {
+		t.Error("key depends on the order of parties")
+	}
+	changed := SamplesDigest([]byte("id,x\n1,2\n2,4\n"), "id")
+	for _, other := range []string{
+		AlignmentKey(AlgorithmOPRF, map[string]string{"address1": digestA, "address2": digestB}),
+		AlignmentKey(AlgorithmECDH, map[string]string{"address1": changed, "address2": digestB}),
+		AlignmentKey(AlgorithmECDH, map[string]string{"address1": SamplesDigest([]byte("id,x\n1,2\n"), "x"), "address2": digestB}),
+	} {
+		if other == key {
+			t.Error("key doesn't change with the algorithm or samples")
+		}
+	}
+}
+
+func TestCachedPSI(t *testing.T) {
+	defer SetAlignmentCache(0, 0)
+	if err := SetAlignmentCache(time.Hour, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	path, _ := os.Getwd()
+	fileA := readTestData(path + "/testdata/dataA.csv")
+	fileB := readTestData(path + "/testdata/dataB.csv")
+	key := AlignmentKey(AlgorithmECDH, map[string]string{
+		"address1": SamplesDigest(fileA, "id"),
+		"address2": SamplesDigest(fileB, "id"),
+	})
+
+	// the intersection of ECDH is cached by both parties
+	vpA, err := NewVLTwoPartsPSIWithAlignment(AlgorithmECDH, key, "address1", fileA, "id", []string{"address2"})
+	checkErr(err)
+	vpB, err := NewVLTwoPartsPSIWithAlignment(AlgorithmECDH, key, "address2", fileB, "id", []string{"address1"})
+	checkErr(err)
+	rowsA, rowsB, err := runTwoPartsPSI(vpA, vpB)
+	checkErr(err)
+	alignments := CachedAlignments(AlgorithmECDH, "address1", SamplesDigest(fileA, "id"), []string{"address2"})
+	ids, ok := alignments[key]
+	if !ok || len(ids) != len(rowsA)-1 {
+		t.Fatalf("alignment not cached, got %v", alignments)
+	}
+
+	// the cached alignment aligns the samples as ECDH does
+	cachedA, err := csv.FilterRows(fileA, "id", ids)
+	checkErr(err)
+	cachedB, err := csv.FilterRows(fileB, "id", ids)
+	checkErr(err)
+	newCached := func(name, peer string, file []byte) VLPSI {
+		vp, err := NewVLTwoPartsPSIWithAlignment(AlgorithmCached, key, name, file, "id", []string{peer})
+		checkErr(err)
+		return vp
+	}
+	cachedRowsA, cachedRowsB, err := runTwoPartsPSI(newCached("address1", "address2", cachedA), newCached("address2", "address1", cachedB))
+	checkErr(err)
+	if !reflect.DeepEqual(cachedRowsA, rowsA) || !reflect.DeepEqual(cachedRowsB, rowsB) {
+		t.Error("samples aligned by the cached alignment differ from the ones by ECDH")
+	}
+
+	// parties fail if they keep different samples, or one of them runs PSI
+	lessB, err := csv.FilterRows(fileB, "id", ids[1:])
+	checkErr(err)
+	_, _, err = runTwoPartsPSI(newCached("address1", "address2", cachedA), newCached("address2", "address1", lessB))
+	if !errorx.Is(err, errcodes.ErrCodePSIIntersectParts) {
+		t.Errorf("expected error of different alignments, got: %v", err)
+	}
+	vpB, err = NewVLTwoPartsPSIWithAlgorithm(AlgorithmECDH, "address2", fileB, "id", []string{"address1"})
+	checkErr(err)
+	_, _, err = runTwoPartsPSI(newCached("address1", "address2", cachedA), vpB)
+	if !errorx.Is(err, errcodes.ErrCodePSIAlgorithmMismatch) {
+		t.Errorf("expected mismatch error of cached and ecdh, got: %v", err)
+	}
+}
+
+// runTwoPartsPSI runs PSI of party address1 by vpA and address2 by vpB, and returns their aligned rows
+func runTwoPartsPSI(vpA, vpB VLPSI) ([][]string, [][]string, error) {
+	encA, err := vpA.EncryptSampleIDSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	encB, err := vpB.EncryptSampleIDSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	reEncA, err := vpB.ReEncryptIDSet("address1", encA)
+	if err != nil {
+		return nil, nil, err
+	}
+	reEncB, err := vpA.ReEncryptIDSet("address2", encB)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := vpA.SetReEncryptIDSet("address2", reEncA); err != nil {
+		return nil, nil, err
+	}
+	if _, err := vpB.SetReEncryptIDSet("address1", reEncB); err != nil {
+		return nil, nil, err
+	}
+	checkErr(vpA.SetOtherFinalReEncryptIDSet("address2", reEncB))
+	checkErr(vpB.SetOtherFinalReEncryptIDSet("address1", reEncA))
+
+	_, rowsA, _, err := vpA.IntersectParts()
+	if err != nil {
+		return nil, nil, err
+	}
+	_, rowsB, _, err := vpB.IntersectParts()
+	return rowsA, rowsB, err
+}
@@ -66,6 +66,7 @@ type vlTwoPartsPsi struct {
 	finalReEncIDs                []byte
 	reEncryptIDSetsFromOthers    sync.Map // stores re-encrypted ID-Sets returned by other parties
 	finalReEncryptIDSetsOfOthers sync.Map // stores final re-encrypted ID-Sets for other parties
+	timer                        timer
 
 	// final results
 	done      bool
@@ -75,6 +76,7 @@ type vlTwoPartsPsi struct {
 
 // EncryptSampleIDSet encrypt sample ID list using own public key
 func (vp *vlTwoPartsPsi) EncryptSampleIDSet() ([]byte, error) {
+	vp.timer.begin()
 	err := vp.readSamples()
 	if err != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodePSISamplesFile, "mistake[%s] happened when PSI read IDs from file", err.Error())
@@ -95,6 +97,9 @@ func (vp *vlTwoPartsPsi) SetReEncryptIDSet(party string, reEncIDs []byte) (bool,
 		// if from unknown party, ignore
 		return false, nil
 	}
+	if err := checkAlgorithm(AlgorithmECDH, party, reEncIDs); err != nil {
+		return false, err
+	}
 
 	vp.reEncryptIDSetsFromOthers.LoadOrStore(party, reEncIDs)
 	vp.finalReEncIDs = reEncIDs
@@ -115,6 +120,9 @@ func (vp *vlTwoPartsPsi) ReEncryptIDSet(party string, encIDs []byte) ([]byte, er
 		return reEncIDs, nil
 	}
 
+	if err := checkAlgorithm(AlgorithmECDH, party, encIDs); err != nil {
+		return []byte{}, err
+	}
 	if errRe != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodePSIReEncryptIDSet, "mistake[%s] happened when PSI encrypt EncryptedSampleIDSet for other party[%s]", errRe.Error(), party)
 	}
@@ -169,6 +177,7 @@ func (vp *vlTwoPartsPsi) IntersectParts() (bool, [][]string, []string, error) {
 	vp.newRows = newRows
 	vp.intersect = intersect
 	vp.done = true
+	vp.timer.end(AlgorithmECDH)
 
 	return vp.done, vp.newRows, intersect, nil
 }
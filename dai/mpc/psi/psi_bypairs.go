@@ -28,6 +28,7 @@ type vlPsiByPairs struct {
 	reEncryptIDSetsFromOthers    sync.Map // stores re-encrypted ID-Sets returned by other parties
 	finalReEncryptIDSetsOfOthers sync.Map // stores final re-encrypted ID-Sets for other parties
 	middleIntersect              sync.Map // stores the intersections of the current and the one other for other parties
+	timer                        timer
 
 	// final results
 	done      bool
@@ -37,6 +38,7 @@ type vlPsiByPairs struct {
 
 // EncryptSampleIDSet encrypt sample ID list using own public key
 func (vp *vlPsiByPairs) EncryptSampleIDSet() ([]byte, error) {
+	vp.timer.begin()
 	err := vp.readSamples()
 	if err != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodePSISamplesFile, "mistake[%s] happened when PSI read IDs from file", err.Error())
@@ -56,6 +58,9 @@ func (vp *vlPsiByPairs) SetReEncryptIDSet(party string, reEncIDs []byte) (bool,
 		// if from unknown party, ignore
 		return false, nil
 	}
+	if err := checkAlgorithm(AlgorithmECDH, party, reEncIDs); err != nil {
+		return false, err
+	}
 
 	vp.reEncryptIDSetsFromOthers.LoadOrStore(party, reEncIDs)
 
@@ -75,6 +80,9 @@ func (vp *vlPsiByPairs) ReEncryptIDSet(party string, encIDs []byte) ([]byte, err
 		return reEncIDs, nil
 	}
 
+	if err := checkAlgorithm(AlgorithmECDH, party, encIDs); err != nil {
+		return []byte{}, err
+	}
 	if errRe != nil {
 		return []byte{}, errorx.New(errcodes.ErrCodePSIReEncryptIDSet, "mistake[%s] happened when PSI encrypt EncryptedSampleIDSet for other party[%s]", errRe.Error(), party)
 	}
@@ -143,6 +151,7 @@ func (vp *vlPsiByPairs) IntersectParts() (bool, [][]string, []string, error) {
 	}
 	vp.newRows = newRows
 	vp.done = true
+	vp.timer.end(AlgorithmECDH)
 
 	return vp.done, vp.newRows, vp.intersect, nil
 }
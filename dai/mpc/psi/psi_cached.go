@@ -0,0 +1,172 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package psi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	csv "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// cachedMagic prefixes the messages of PSI reusing cached alignments
+var cachedMagic = []byte("CACH")
+
+// vlCachedPsi implements VLPSI for two parties whose samples files are already aligned by a cached alignment,
+// the parties exchange the hash of their IDs instead of the encrypted IDs, and fail if the hashes differ
+type vlCachedPsi struct {
+	name          string
+	samplesIdName string
+	parties       map[string]bool
+
+	rows    [][]string
+	ids     []string // sorted
+	message []byte   // cachedMagic followed by the hash of ids
+
+	lock       sync.Mutex
+	checked    bool // the hash of the other party is checked
+	otherFinal bool // the other party checked the local hash
+	done       bool
+	newRows    [][]string
+	timer      timer
+}
+
+// NewVLCachedPSI creates a VLPSI instance for two parties reusing the cached alignment,
+// samplesFile contains only the samples in the alignment, see NewVLTwoPartsPSI for the other parameters
+func NewVLCachedPSI(name string, samplesFile []byte, samplesIdName string, parties []string) (VLPSI, error) {
+	if len(parties) != 1 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "invalid parties number, should be 1, got %d", len(parties))
+	}
+	rows, ids, err := csv.ReadIDsFromFileRows(samplesFile, samplesIdName)
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodePSISamplesFile, "mistake[%s] happened when PSI read IDs from file", err.Error())
+	}
+	ids = append([]string{}, ids...)
+	sort.Strings(ids)
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+
+	return &vlCachedPsi{
+		name:          name,
+		samplesIdName: samplesIdName,
+		parties:       map[string]bool{parties[0]: true},
+		rows:          rows,
+		ids:           ids,
+		message:       append(append([]byte{}, cachedMagic...), sum[:]...),
+	}, nil
+}
+
+// EncryptSampleIDSet returns the hash of local IDs
+func (vp *vlCachedPsi) EncryptSampleIDSet() ([]byte, error) {
+	vp.timer.begin()
+	return vp.message, nil
+}
+
+// SetReEncryptIDSet checks the hash of IDs of the other party, returned by ReEncryptIDSet, equals the local one
+func (vp *vlCachedPsi) SetReEncryptIDSet(party string, reEncIDs []byte) (bool, error) {
+	if _, ok := vp.parties[party]; !ok {
+		return false, nil
+	}
+	if err := vp.check(party, reEncIDs); err != nil {
+		return false, err
+	}
+	vp.lock.Lock()
+	vp.checked = true
+	vp.lock.Unlock()
+	return true, nil
+}
+
+// ReEncryptIDSet checks the hash of IDs of the other party equals the local one, and returns the local hash
+func (vp *vlCachedPsi) ReEncryptIDSet(party string, encIDs []byte) ([]byte, error) {
+	if _, ok := vp.parties[party]; !ok {
+		return []byte{}, nil
+	}
+	if err := vp.check(party, encIDs); err != nil {
+		return []byte{}, err
+	}
+	return vp.message, nil
+}
+
+// SetOtherFinalReEncryptIDSet records the other party checked the local hash
+func (vp *vlCachedPsi) SetOtherFinalReEncryptIDSet(party string, reEncIDs []byte) error {
+	if _, ok := vp.parties[party]; !ok {
+		return nil
+	}
+	vp.lock.Lock()
+	vp.otherFinal = true
+	vp.lock.Unlock()
+	return nil
+}
+
+// IntersectParts re-arranges the samples by the cached alignment once both parties have checked the hashes
+func (vp *vlCachedPsi) IntersectParts() (bool, [][]string, []string, error) {
+	vp.lock.Lock()
+	defer vp.lock.Unlock()
+	if vp.done {
+		return vp.done, vp.newRows, vp.ids, nil
+	}
+	if !vp.checked || !vp.otherFinal {
+		return false, nil, nil, nil
+	}
+
+	newRows, err := vl_common.RearrangeFileWithIntersectIDs(vp.rows, vp.samplesIdName, vp.ids)
+	if err != nil {
+		return false, newRows, vp.ids, errorx.New(errcodes.ErrCodePSIRearrangeFile, "mistake[%s] happened when PSI rearrange file with intersected IDs", err.Error())
+	}
+	vp.newRows = newRows
+	vp.done = true
+	vp.timer.end(AlgorithmCached)
+
+	return vp.done, vp.newRows, vp.ids, nil
+}
+
+// check checks the message from party is of the cached alignment with the same IDs as the local one
+func (vp *vlCachedPsi) check(party string, message []byte) error {
+	if err := checkAlgorithm(AlgorithmCached, party, message); err != nil {
+		return err
+	}
+	if !bytes.Equal(message, vp.message) {
+		return errorx.New(errcodes.ErrCodePSIIntersectParts, "cached alignment of party[%s] differs from the local one", party)
+	}
+	return nil
+}
+
+// vlCachingPsi caches the intersection of the VLPSI as the alignment of key once it's done
+type vlCachingPsi struct {
+	VLPSI
+	key       string
+	algorithm string
+	name      string
+	digest    string
+	peers     []string
+	stored    bool
+}
+
+// IntersectParts calls IntersectParts of the VLPSI, and caches the intersection once it's done
+func (vp *vlCachingPsi) IntersectParts() (bool, [][]string, []string, error) {
+	done, newRows, intersect, err := vp.VLPSI.IntersectParts()
+	if done && err == nil && !vp.stored {
+		vp.stored = true
+		ids := append([]string{}, intersect...)
+		sort.Strings(ids)
+		storeAlignment(vp.key, vp.algorithm, vp.name, vp.digest, vp.peers, ids)
+	}
+	return done, newRows, intersect, err
+}
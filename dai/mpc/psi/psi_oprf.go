@@ -0,0 +1,240 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package psi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	csv "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// compressedPointSize is the length of compressed points of the default curve
+const compressedPointSize = 33
+
+// vlTwoPartsOprfPsi implements VLPSI by OPRF, see vl_common.oprf for more
+// EncryptSampleIDSet blinds local IDs, ReEncryptIDSet evaluates IDs blinded by the other party
+// and appends the digests of local IDs, SetReEncryptIDSet removes the blinding and intersects the digests
+type vlTwoPartsOprfPsi struct {
+	name          string
+	key           *big.Int        // local OPRF key for evaluating IDs
+	blind         *big.Int        // random scalar to blind local IDs
+	samplesFile   []byte          // csv file content subjected to specified form
+	samplesIdName string          // feature name for samples ID, used to extract IDs
+	parties       map[string]bool // names of other parties who participate MPC
+
+	// intermediate results
+	readOnce   sync.Once
+	readErr    error
+	ids        []string
+	rows       [][]string
+	intersects sync.Map // stores the intersection computed with the evaluation returned by other party
+	answered   sync.Map // stores the parties whose blinded IDs are evaluated
+	timer      timer
+
+	// final results
+	done      bool
+	newRows   [][]string
+	intersect []string
+}
+
+// EncryptSampleIDSet blinds local IDs
+func (vp *vlTwoPartsOprfPsi) EncryptSampleIDSet() ([]byte, error) {
+	vp.timer.begin()
+	if err := vp.readSamples(); err != nil {
+		return []byte{}, errorx.New(errcodes.ErrCodePSISamplesFile, "mistake[%s] happened when PSI read IDs from file", err.Error())
+	}
+
+	var buf bytes.Buffer
+	buf.Write(oprfMagic)
+	writeItems(&buf, vl_common.BlindIDSet(vp.ids, vp.blind))
+	return buf.Bytes(), nil
+}
+
+// SetReEncryptIDSet removes the blinding from local IDs evaluated by the other party,
+// and intersects them with the digests of its IDs
+func (vp *vlTwoPartsOprfPsi) SetReEncryptIDSet(party string, reEncIDs []byte) (bool, error) {
+	if _, ok := vp.parties[party]; !ok {
+		// if from unknown party, ignore
+		return false, nil
+	}
+	if err := checkAlgorithm(AlgorithmOPRF, party, reEncIDs); err != nil {
+		return false, err
+	}
+
+	r := bytes.NewReader(reEncIDs[len(oprfMagic):])
+	points, err := readItems(r, compressedPointSize)
+	if err != nil {
+		return false, errorx.New(errcodes.ErrCodePSIIntersectParts, "mistake[%s] happened when PSI read evaluated IDs from party[%s]", err.Error(), party)
+	}
+	otherDigests, err := readItems(r, vl_common.OPRFDigestSize)
+	if err != nil {
+		return false, errorx.New(errcodes.ErrCodePSIIntersectParts, "mistake[%s] happened when PSI read IDs digests from party[%s]", err.Error(), party)
+	}
+	digests, err := vl_common.UnblindIDSet(vp.ids, points, vp.blind)
+	if err != nil {
+		return false, errorx.New(errcodes.ErrCodePSIIntersectParts, "mistake[%s] happened when PSI unblind IDs evaluated by party[%s]", err.Error(), party)
+	}
+
+	vp.intersects.LoadOrStore(party, vl_common.IntersectOPRFDigests(vp.ids, digests, otherDigests))
+	return true, nil
+}
+
+// ReEncryptIDSet evaluates blinded IDs of other party with local key, and appends the digests of local IDs
+func (vp *vlTwoPartsOprfPsi) ReEncryptIDSet(party string, encIDs []byte) ([]byte, error) {
+	// if from unknown party, don't care about any Error
+	if _, ok := vp.parties[party]; !ok {
+		return []byte{}, nil
+	}
+	if err := checkAlgorithm(AlgorithmOPRF, party, encIDs); err != nil {
+		return []byte{}, err
+	}
+	if err := vp.readSamples(); err != nil {
+		return []byte{}, errorx.New(errcodes.ErrCodePSISamplesFile, "mistake[%s] happened when PSI read IDs from file", err.Error())
+	}
+
+	points, err := readItems(bytes.NewReader(encIDs[len(oprfMagic):]), compressedPointSize)
+	if err == nil {
+		points, err = vl_common.EvaluateBlindedIDSet(points, vp.key)
+	}
+	if err != nil {
+		return []byte{}, errorx.New(errcodes.ErrCodePSIReEncryptIDSet, "mistake[%s] happened when PSI evaluate blinded IDs for other party[%s]", err.Error(), party)
+	}
+
+	// digests are sorted so that their order reveals nothing about the samples
+	digests := vl_common.OPRFDigests(vp.ids, vp.key)
+	sort.Slice(digests, func(i, j int) bool { return bytes.Compare(digests[i], digests[j]) < 0 })
+
+	var buf bytes.Buffer
+	buf.Write(oprfMagic)
+	writeItems(&buf, points)
+	writeItems(&buf, digests)
+	return buf.Bytes(), nil
+}
+
+// SetOtherFinalReEncryptIDSet marks the blinded IDs of other party evaluated,
+// the intersection needs nothing of the evaluation
+func (vp *vlTwoPartsOprfPsi) SetOtherFinalReEncryptIDSet(party string, reEncIDs []byte) error {
+	// if from unknown party, don't store result
+	if _, ok := vp.parties[party]; !ok {
+		return nil
+	}
+	vp.answered.LoadOrStore(party, true)
+	return nil
+}
+
+// IntersectParts re-arranges sample files with the intersection, once local IDs are evaluated by the other party
+// and the other party's IDs are evaluated
+func (vp *vlTwoPartsOprfPsi) IntersectParts() (bool, [][]string, []string, error) {
+	if vp.done {
+		return vp.done, vp.newRows, vp.intersect, nil
+	}
+
+	var newRows [][]string
+	var intersect []string
+	for party := range vp.parties {
+		if _, ok := vp.answered.Load(party); !ok {
+			return false, newRows, nil, nil
+		}
+		v, ok := vp.intersects.Load(party)
+		if !ok {
+			return false, newRows, nil, nil
+		}
+		intersect = v.([]string)
+	}
+
+	newRows, err := vl_common.RearrangeFileWithIntersectIDs(vp.rows, vp.samplesIdName, intersect)
+	if err != nil {
+		return false, newRows, intersect, errorx.New(errcodes.ErrCodePSIRearrangeFile, "mistake[%s] happened when PSI rearrange file with intersected IDs", err.Error())
+	}
+
+	vp.newRows = newRows
+	vp.intersect = intersect
+	vp.done = true
+	vp.timer.end(AlgorithmOPRF)
+
+	return vp.done, vp.newRows, intersect, nil
+}
+
+// readSamples retrieve ID list from sample file rows once,
+// the IDs are required by both blinding and evaluating for other party
+func (vp *vlTwoPartsOprfPsi) readSamples() error {
+	vp.readOnce.Do(func() {
+		vp.rows, vp.ids, vp.readErr = csv.ReadIDsFromFileRows(vp.samplesFile, vp.samplesIdName)
+	})
+	return vp.readErr
+}
+
+// writeItems writes the number of items and the items of fixed length
+func writeItems(buf *bytes.Buffer, items [][]byte) {
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutUvarint(n[:], uint64(len(items)))])
+	for _, item := range items {
+		buf.Write(item)
+	}
+}
+
+// readItems reads the items of length size written by writeItems
+func readItems(r *bytes.Reader, size int) ([][]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()/size) {
+		return nil, fmt.Errorf("%d items of %d bytes exceed the message", n, size)
+	}
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = make([]byte, size)
+		r.Read(items[i])
+	}
+	return items, nil
+}
+
+// NewVLTwoPartsOPRFPSI create a VLPSI instance based on OPRF
+// name is to name the PSI instance
+// parties are names of other parties who participate MPC
+// sampleFile is csv file content subjected to specified form
+// sampleIdName is used to extract IDs
+func NewVLTwoPartsOPRFPSI(name string, samplesFile []byte, samplesIdName string, parties []string) (VLPSI, error) {
+	if len(parties) <= 0 {
+		return nil, errorx.New(errcodes.ErrCodeParam, "no parties in PSI")
+	}
+
+	key, err := vl_common.GenerateOPRFScalar()
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when PSI generate OPRF key", err.Error())
+	}
+	blind, err := vl_common.GenerateOPRFScalar()
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeInternal, "mistake[%s] happened when PSI generate blinding factor", err.Error())
+	}
+
+	return &vlTwoPartsOprfPsi{
+		name:          name,
+		key:           key,
+		blind:         blind,
+		samplesFile:   samplesFile,
+		samplesIdName: samplesIdName,
+		parties:       map[string]bool{parties[0]: true},
+	}, nil
+}
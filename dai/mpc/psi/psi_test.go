@@ -4,7 +4,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 )
 
 func TestVlThreePartsPsi(t *testing.T) {
@@ -105,6 +111,75 @@ func TestVLTwoPartsPsi(t *testing.T) {
 
 }
 
+func TestVLTwoPartsPSIWithAlgorithm(t *testing.T) {
+	path, _ := os.Getwd()
+	fileA := readTestData(path + "/testdata/dataA.csv")
+	fileB := readTestData(path + "/testdata/dataB.csv")
+
+	intersect := func(algoA, algoB string) ([]string, []string, error) {
+		vpA, err := NewVLTwoPartsPSIWithAlgorithm(algoA, "address1", fileA, "id", []string{"address2"})
+		checkErr(err)
+		vpB, err := NewVLTwoPartsPSIWithAlgorithm(algoB, "address2", fileB, "id", []string{"address1"})
+		checkErr(err)
+
+		encA, err := vpA.EncryptSampleIDSet()
+		checkErr(err)
+		encB, err := vpB.EncryptSampleIDSet()
+		checkErr(err)
+		reEncA, err := vpB.ReEncryptIDSet("address1", encA)
+		if err != nil {
+			return nil, nil, err
+		}
+		reEncB, err := vpA.ReEncryptIDSet("address2", encB)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, step := range []struct {
+			vp            VLPSI
+			party         string
+			reEnc, reEncO []byte
+		}{{vpA, "address2", reEncA, reEncB}, {vpB, "address1", reEncB, reEncA}} {
+			if _, err := step.vp.SetReEncryptIDSet(step.party, step.reEnc); err != nil {
+				return nil, nil, err
+			}
+			checkErr(step.vp.SetOtherFinalReEncryptIDSet(step.party, step.reEncO))
+		}
+
+		doneA, _, idsA, err := vpA.IntersectParts()
+		checkErr(err)
+		doneB, _, idsB, err := vpB.IntersectParts()
+		checkErr(err)
+		if !doneA || !doneB {
+			t.Fatalf("%s PSI not done", algoA)
+		}
+		sort.Strings(idsA)
+		sort.Strings(idsB)
+		return idsA, idsB, nil
+	}
+
+	ecdhA, ecdhB, err := intersect(AlgorithmECDH, AlgorithmECDH)
+	checkErr(err)
+	oprfA, oprfB, err := intersect(AlgorithmOPRF, AlgorithmOPRF)
+	checkErr(err)
+	if len(oprfA) == 0 || !reflect.DeepEqual(oprfA, oprfB) || !reflect.DeepEqual(oprfA, ecdhA) || !reflect.DeepEqual(ecdhA, ecdhB) {
+		t.Errorf("intersections differ, ecdh: %d and %d IDs, oprf: %d and %d IDs", len(ecdhA), len(ecdhB), len(oprfA), len(oprfB))
+	}
+
+	// small sample sets select ECDH automatically
+	if _, _, err := intersect(AlgorithmAuto, AlgorithmECDH); err != nil {
+		t.Errorf("auto failed to intersect with ecdh: %v", err)
+	}
+	for _, algos := range [][2]string{{AlgorithmOPRF, AlgorithmECDH}, {AlgorithmECDH, AlgorithmOPRF}} {
+		_, _, err := intersect(algos[0], algos[1])
+		if err == nil || !errorx.Is(err, errcodes.ErrCodePSIAlgorithmMismatch) {
+			t.Errorf("expected mismatch error of %s and %s, got: %v", algos[0], algos[1], err)
+		}
+	}
+	if _, err := NewVLTwoPartsPSIWithAlgorithm("rsa", "address1", fileA, "id", []string{"address2"}); err == nil {
+		t.Error("expected error of unknown algorithm")
+	}
+}
+
 func readTestData(filename string) []byte {
 	file, err := os.Open(filename)
 	checkErr(err)
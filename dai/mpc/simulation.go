@@ -0,0 +1,219 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/cluster"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+// Simulation runs all parties of MPC tasks in one process, so that algorithms can be tested and
+// benchmarked end-to-end locally. The parties run the same Trainer and Predictor as executors,
+// but communicate in memory by cluster.LocalTransport instead of network, and tasks are started
+// by Simulation directly instead of being published to blockchain.
+type Simulation struct {
+	transport *cluster.LocalTransport
+	conf      Config
+
+	lock    sync.Mutex
+	parties map[string]*mpc                  // parties in simulation, key is the address of party
+	waiting map[string]chan simulationResult // results of tasks in Run, key is taskID
+}
+
+// TaskResult is the result of a task of a party in simulation,
+// Train is set for training task, and Predict for prediction task
+type TaskResult struct {
+	Train   *pbCom.TrainTaskResult
+	Predict *pbCom.PredictTaskResult
+}
+
+type simulationResult struct {
+	address string
+	result  TaskResult
+}
+
+// simulationHolder is the ModelHolder of a party, which passes the results to Run
+type simulationHolder struct {
+	address string
+	sim     *Simulation
+}
+
+// SaveModel passes the trained model to Run
+func (h *simulationHolder) SaveModel(result *pbCom.TrainTaskResult) error {
+	h.sim.done(h.address, result.TaskID, pbCom.TaskType_LEARN, TaskResult{Train: result})
+	return nil
+}
+
+// SavePredictOut passes the prediction outcomes to Run
+func (h *simulationHolder) SavePredictOut(result *pbCom.PredictTaskResult) error {
+	h.sim.done(h.address, result.TaskID, pbCom.TaskType_PREDICT, TaskResult{Predict: result})
+	return nil
+}
+
+// NewSimulation creates Simulation without any party,
+// conf is used by all parties except Address, which is assigned by AddParty
+func NewSimulation(conf Config) *Simulation {
+	return &Simulation{
+		transport: cluster.NewLocalTransport(),
+		conf:      conf,
+		parties:   make(map[string]*mpc),
+		waiting:   make(map[string]chan simulationResult),
+	}
+}
+
+// AddParty starts a party identified by address, which is used as Hosts of StartTaskRequest
+// by the other parties, it needn't be a network address
+func (s *Simulation) AddParty(address string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.parties[address]; ok {
+		return errorx.New(errcodes.ErrCodeParam, "party %s already exists", address)
+	}
+
+	conf := s.conf
+	conf.Address = address
+	mh := &simulationHolder{address: address, sim: s}
+	m := newMpcWithRpc(mh, cluster.NewRpcClientWithTransport(s.transport, conf.RpcTimeout), conf)
+	go m.run()
+
+	s.transport.Register(address, m)
+	s.parties[address] = m
+	return nil
+}
+
+// Run executes a task by the parties and waits for the results of all of them,
+// reqs are the requests to start the task keyed by the addresses of parties, and are sent
+// with the same TaskID. If any party fails or timeout elapses, the task is cancelled by all parties.
+func (s *Simulation) Run(reqs map[string]*pbCom.StartTaskRequest, timeout time.Duration) (map[string]TaskResult, error) {
+	var taskID string
+	var taskType pbCom.TaskType
+	for _, req := range reqs {
+		taskID, taskType = req.TaskID, req.GetParams().GetTaskType()
+		break
+	}
+
+	s.lock.Lock()
+	for address, req := range reqs {
+		if _, ok := s.parties[address]; !ok {
+			s.lock.Unlock()
+			return nil, errorx.New(errcodes.ErrCodeNotFound, "party %s not found", address)
+		}
+		if req.TaskID != taskID {
+			s.lock.Unlock()
+			return nil, errorx.New(errcodes.ErrCodeParam, "parties start different tasks %s and %s", taskID, req.TaskID)
+		}
+	}
+	if _, ok := s.waiting[taskID]; ok {
+		s.lock.Unlock()
+		return nil, errorx.New(errcodes.ErrCodeTaskExists, "task %s is running", taskID)
+	}
+	resultC := make(chan simulationResult, len(reqs))
+	s.waiting[taskID] = resultC
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.waiting, taskID)
+		s.lock.Unlock()
+	}()
+
+	// parties start the task in parallel as executors do, because some algorithms wait for others in start
+	errC := make(chan error, len(reqs))
+	for address, req := range reqs {
+		go func(m *mpc, req *pbCom.StartTaskRequest) {
+			errC <- m.StartTask(req)
+		}(s.party(address), req)
+	}
+	for range reqs {
+		if err := <-errC; err != nil {
+			s.cancel(reqs, taskID, taskType)
+			return nil, errorx.Wrap(err, "failed to start task %s", taskID)
+		}
+	}
+
+	results := make(map[string]TaskResult)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for len(results) < len(reqs) {
+		select {
+		case r := <-resultC:
+			results[r.address] = r.result
+			if errMsg, failed := r.result.failed(); failed {
+				s.cancel(reqs, taskID, taskType)
+				return results, errorx.New(errcodes.ErrCodeInternal, "task %s failed on party %s: %s", taskID, r.address, errMsg)
+			}
+		case <-deadline.C:
+			s.cancel(reqs, taskID, taskType)
+			return results, errorx.New(errcodes.ErrCodeInternal, "task %s is still running after %s", taskID, timeout)
+		}
+	}
+	return results, nil
+}
+
+// Stop stops all parties
+func (s *Simulation) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for address, m := range s.parties {
+		s.transport.Unregister(address)
+		m.Stop()
+		delete(s.parties, address)
+	}
+}
+
+// done stops the finished task of the party as executors do, and passes the result to Run
+func (s *Simulation) done(address, taskID string, taskType pbCom.TaskType, result TaskResult) {
+	if m := s.party(address); m != nil {
+		m.StopTask(&pbCom.StopTaskRequest{TaskID: taskID, Params: &pbCom.TaskParams{TaskType: taskType}})
+	}
+
+	s.lock.Lock()
+	resultC, ok := s.waiting[taskID]
+	s.lock.Unlock()
+	if ok {
+		resultC <- simulationResult{address: address, result: result}
+	}
+}
+
+// cancel cancels the task on the parties which are still running it
+func (s *Simulation) cancel(reqs map[string]*pbCom.StartTaskRequest, taskID string, taskType pbCom.TaskType) {
+	for address := range reqs {
+		if m := s.party(address); m != nil {
+			m.CancelTask(&pbCom.StopTaskRequest{TaskID: taskID, Params: &pbCom.TaskParams{TaskType: taskType}})
+		}
+	}
+}
+
+func (s *Simulation) party(address string) *mpc {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.parties[address]
+}
+
+// failed returns the error message if the task failed
+func (r TaskResult) failed() (string, bool) {
+	if r.Train != nil && !r.Train.Success {
+		return r.Train.ErrMsg, true
+	}
+	if r.Predict != nil && !r.Predict.Success {
+		return r.Predict.ErrMsg, true
+	}
+	return "", false
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpc
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestSimulation(t *testing.T) {
+	sim := NewSimulation(Config{
+		TrainTaskLimit:   10,
+		PredictTaskLimit: 10,
+		RpcTimeout:       3 * time.Second,
+	})
+	defer sim.Stop()
+	parties := []string{"party1", "party2"}
+	for _, p := range parties {
+		checkErr(sim.AddParty(p), t)
+	}
+	if err := sim.AddParty("party1"); err == nil {
+		t.Error("added the existing party")
+	}
+
+	// train
+	trainReqs := make(map[string]*pbCom.StartTaskRequest)
+	for i, file := range []string{"train_dataA.csv", "train_dataB.csv"} {
+		samples, err := ioutil.ReadFile("./testdata/vl/linear_boston_housing/" + file)
+		checkErr(err, t)
+		trainReqs[parties[i]] = &pbCom.StartTaskRequest{
+			TaskID: "TestSimulationTrain",
+			File:   samples,
+			Hosts:  []string{parties[1-i]},
+			Params: &pbCom.TaskParams{
+				Algo:     pbCom.Algorithm_LINEAR_REGRESSION_VL,
+				TaskType: pbCom.TaskType_LEARN,
+				TrainParams: &pbCom.TrainParams{
+					Label:     "MEDV",
+					RegParam:  0.1,
+					Alpha:     0.1,
+					Amplitude: 0.1,
+					Accuracy:  10,
+					IsTagPart: i == 1,
+					IdName:    "id",
+					BatchSize: 4,
+				},
+			},
+		}
+	}
+	trainResults, err := sim.Run(trainReqs, 5*time.Minute)
+	checkErr(err, t)
+	if len(trainResults) != len(parties) {
+		t.Fatalf("expected results of %d parties, got %d", len(parties), len(trainResults))
+	}
+
+	// predict with the trained models
+	predictReqs := make(map[string]*pbCom.StartTaskRequest)
+	for i, file := range []string{"predict_dataA.csv", "predict_dataB.csv"} {
+		samples, err := ioutil.ReadFile("./testdata/vl/linear_boston_housing/" + file)
+		checkErr(err, t)
+		model, err := vl_common.TrainModelsFromBytes(trainResults[parties[i]].Train.Model)
+		checkErr(err, t)
+		model.IdName = "id"
+		predictReqs[parties[i]] = &pbCom.StartTaskRequest{
+			TaskID: "TestSimulationPredict",
+			File:   samples,
+			Hosts:  []string{parties[1-i]},
+			Params: &pbCom.TaskParams{
+				Algo:        pbCom.Algorithm_LINEAR_REGRESSION_VL,
+				TaskType:    pbCom.TaskType_PREDICT,
+				TrainParams: trainReqs[parties[i]].Params.TrainParams,
+				ModelParams: model,
+			},
+		}
+	}
+	predictResults, err := sim.Run(predictReqs, time.Minute)
+	checkErr(err, t)
+	if len(predictResults["party2"].Predict.Outcomes) == 0 {
+		t.Error("the party with target feature got no outcomes")
+	}
+
+	// parties that are not in simulation can't run tasks
+	if _, err := sim.Run(map[string]*pbCom.StartTaskRequest{"party3": predictReqs["party1"]}, time.Minute); err == nil {
+		t.Error("task is run by unknown party")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spill keeps intermediate data of tasks, such as the aligned samples, in files of the spill directory
+// instead of memory when the memory in use by the executor crosses a threshold, trading speed for the ability
+// of memory-constrained nodes to complete large tasks.
+package spill
+
+import (
+	"bufio"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+var (
+	logger = logrus.WithField("module", "mpc.spill")
+
+	// conf is set by SetSpill, data is never spilled by default
+	conf struct {
+		dir         string
+		thresholdMB int
+		sync.RWMutex
+	}
+)
+
+// memoryUsageMB returns the memory in use by the executor process, in MB
+var memoryUsageMB = func() int {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return int((ms.HeapInuse + ms.StackInuse) >> 20)
+}
+
+// SetSpill sets the directory which intermediate data is spilled to, and the threshold of the memory in use
+// by the executor in MB, above which data is spilled, zero threshold disables spilling
+func SetSpill(dir string, thresholdMB int) {
+	conf.Lock()
+	defer conf.Unlock()
+	conf.dir = dir
+	conf.thresholdMB = thresholdMB
+}
+
+// Enabled returns whether intermediate data is spilled under memory pressure
+func Enabled() bool {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.thresholdMB > 0 && conf.dir != ""
+}
+
+// underPressure returns whether the memory in use exceeds the threshold, and the directory to spill to
+func underPressure() (string, bool) {
+	conf.RLock()
+	defer conf.RUnlock()
+	if conf.thresholdMB <= 0 || conf.dir == "" {
+		return "", false
+	}
+	return conf.dir, memoryUsageMB() > conf.thresholdMB
+}
+
+// Rows holds rows of intermediate data of a task, in memory, or in a csv file of the spill directory once they're
+// spilled. Rows are immutable, they're read back from the file by Get every time they're needed.
+type Rows struct {
+	rows [][]string
+	path string // the file the rows are spilled to, empty if they're in memory
+}
+
+// NewRows returns Rows holding rows in memory
+func NewRows(rows [][]string) *Rows {
+	return &Rows{rows: rows}
+}
+
+// Keep keeps rows as the data named name of task taskID, they're spilled to the spill directory if the memory
+// in use exceeds the threshold, otherwise they're kept in memory. Rows are kept in memory if they fail to spill.
+func Keep(taskID, name string, rows [][]string) *Rows {
+	r := NewRows(rows)
+	r.Spill(taskID, name)
+	return r
+}
+
+// Spill writes the rows held in memory to the spill directory and drops them from memory,
+// if the memory in use exceeds the threshold. It's a no-op if r is nil or the rows are spilled already.
+func (r *Rows) Spill(taskID, name string) {
+	if r == nil || r.path != "" || len(r.rows) == 0 {
+		return
+	}
+	dir, ok := underPressure()
+	if !ok {
+		return
+	}
+	path := filepath.Join(dir, taskID, name+".csv")
+	if err := writeRows(path, r.rows); err != nil {
+		logger.WithField(logging.TaskIDKey, taskID).WithError(err).Warnf("failed to spill %s, keep it in memory", name)
+		os.Remove(path)
+		return
+	}
+	logger.WithField(logging.TaskIDKey, taskID).Infof("%d rows of %s spilled to %s under memory pressure", len(r.rows), name, path)
+	r.rows = nil
+	r.path = path
+}
+
+// Spilled returns whether the rows are in the spill directory
+func (r *Rows) Spilled() bool {
+	return r != nil && r.path != ""
+}
+
+// Get returns the rows, which are read from the spill directory if they're spilled, nil if r is nil
+func (r *Rows) Get() ([][]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.path == "" {
+		return r.rows, nil
+	}
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(bufio.NewReader(f))
+	// sparse rows have variable numbers of cells
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// writeRows writes rows into the csv file at path, creating its directory
+func writeRows(path string, rows [][]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(f)
+	if err := csv.NewWriter(buf).WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Remove removes the data spilled by task taskID, along with the data of the tasks it derives,
+// such as the training and prediction tasks of its evaluation, whose IDs start with taskID and "_"
+func Remove(taskID string) {
+	conf.RLock()
+	dir := conf.dir
+	conf.RUnlock()
+	if dir == "" || taskID == "" {
+		return
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, taskID+"_*"))
+	paths = append(paths, filepath.Join(dir, taskID))
+	for _, p := range paths {
+		if err := os.RemoveAll(p); err != nil {
+			logger.WithField(logging.TaskIDKey, taskID).WithError(err).Warnf("failed to remove spilled data in %s", p)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spill
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSpill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	usage := 100
+	memoryUsageMB = func() int { return usage }
+	defer SetSpill("", 0)
+
+	// sparse rows have variable numbers of cells
+	rows := [][]string{{"id", "x1", "x2"}, {"1", "0.5", "2"}, {"2", "1:3"}}
+
+	// never spilled if it's disabled
+	if r := Keep("task-1", "train_set", rows); r.Spilled() {
+		t.Error("expected rows kept in memory if spilling is disabled")
+	}
+
+	// kept in memory below the threshold
+	SetSpill(dir, 200)
+	r := Keep("task-1", "train_set", rows)
+	if r.Spilled() {
+		t.Error("expected rows kept in memory below the threshold")
+	}
+
+	// spilled above the threshold, and read back
+	usage = 300
+	r.Spill("task-1", "train_set")
+	if !r.Spilled() || r.rows != nil {
+		t.Fatal("expected rows spilled above the threshold")
+	}
+	got, err := r.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("expected spilled rows %v read back, got %v", rows, got)
+	}
+	evalRows := Keep("task-1_0_train_Eva", "train_set", rows)
+	other := Keep("task-10", "train_set", rows)
+	if !evalRows.Spilled() || !other.Spilled() {
+		t.Fatal("expected rows of other tasks spilled")
+	}
+
+	// the data of the task and its evaluation is removed, the other tasks' is kept
+	Remove("task-1")
+	for _, p := range []string{"task-1", "task-1_0_train_Eva"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); !os.IsNotExist(err) {
+			t.Errorf("expected spilled data of %s removed, got %v", p, err)
+		}
+	}
+	if _, err := other.Get(); err != nil {
+		t.Errorf("expected spilled data of task-10 kept, got %v", err)
+	}
+
+	var nilRows *Rows
+	if got, err := nilRows.Get(); got != nil || err != nil {
+		t.Errorf("expected nil rows, got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trainer
+
+import (
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners"
+)
+
+// CheckpointHolder persists the checkpoints of training tasks,
+// set into Trainer by SetCheckpointHolder if checkpoints are enabled
+type CheckpointHolder interface {
+	// SaveCheckpoint replaces the checkpoint of a task
+	SaveCheckpoint(taskID string, data []byte) error
+
+	// LoadCheckpoint returns the checkpoint of a task, or nil if there is none
+	LoadCheckpoint(taskID string) ([]byte, error)
+
+	// DeleteCheckpoint removes the checkpoint of a task
+	DeleteCheckpoint(taskID string) error
+}
+
+// taskCheckpointer persists the checkpoints of a task by CheckpointHolder,
+// it is assigned to the Learner of the task
+type taskCheckpointer struct {
+	taskID   string
+	interval uint64
+	holder   CheckpointHolder
+}
+
+// Interval returns the number of rounds between two checkpoints
+func (c *taskCheckpointer) Interval() uint64 {
+	return c.interval
+}
+
+// SaveCheckpoint replaces the checkpoint of the task
+func (c *taskCheckpointer) SaveCheckpoint(data []byte) error {
+	return c.holder.SaveCheckpoint(c.taskID, data)
+}
+
+// LoadCheckpoint returns the checkpoint of the task, or nil if there is none
+func (c *taskCheckpointer) LoadCheckpoint() ([]byte, error) {
+	return c.holder.LoadCheckpoint(c.taskID)
+}
+
+// DeleteCheckpoint removes the checkpoint of the task
+func (c *taskCheckpointer) DeleteCheckpoint() error {
+	return c.holder.DeleteCheckpoint(c.taskID)
+}
+
+// SetCheckpointHolder enables checkpoints of training tasks,
+// a checkpoint is saved every interval rounds, and zero interval disables checkpoints
+func (t *Trainer) SetCheckpointHolder(holder CheckpointHolder, interval int) {
+	t.checkpointHolder = holder
+	t.checkpointInterval = uint64(interval)
+}
+
+// newCheckpointer returns the Checkpointer for the learner of a task,
+// only the tasks from users are checkpointed, and those with LiveEvaluation are not,
+// because the learners for evaluation can't resume
+func (t *Trainer) newCheckpointer(taskId string, lEvaluated bool) learners.Checkpointer {
+	if t.checkpointHolder == nil || t.checkpointInterval == 0 || lEvaluated {
+		return nil
+	}
+	if fromEvaluator, fromLiveEvaluator, _ := t.checkOrigin(taskId); fromEvaluator || fromLiveEvaluator {
+		return nil
+	}
+
+	return &taskCheckpointer{
+		taskID:   taskId,
+		interval: t.checkpointInterval,
+		holder:   t.checkpointHolder,
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trainer
+
+import (
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/learners"
+	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/psi"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
+)
+
+// dryRunSuffix is the suffix of the TaskID of the learner of the dry run, like `{uuid}_0_train_Dry`
+const dryRunSuffix = "_0_train_Dry"
+
+// dryRunSource returns the TaskID of the task from user if taskId is of the learner of a dry run
+func dryRunSource(taskId string) (string, bool) {
+	if strings.HasSuffix(taskId, dryRunSuffix) {
+		return strings.TrimSuffix(taskId, dryRunSuffix), true
+	}
+	return "", false
+}
+
+// dryRunEnabled returns whether the task starts with a dry run, which is only for the tasks from user with samples,
+// the learners for evaluation are created with the parameters of the task after its dry run
+func (t *Trainer) dryRunEnabled(req *pbCom.StartTaskRequest) bool {
+	if len(req.GetFile()) == 0 || req.GetParams().GetTrainParams().GetDryRun() == nil {
+		return false
+	}
+	fromEvaluator, fromLiveEvaluator, _ := t.checkOrigin(req.TaskID)
+	return !fromEvaluator && !fromLiveEvaluator
+}
+
+// newDryRunLearner creates the learner of the dry run of a task from user, which trains on the subset of the samples
+// selected by the TaskID, so all parties keep the same samples. The request is stored, and the task is started
+// without the dry run once the dry run succeeds. The learner has neither evaluation nor checkpoints
+func (t *Trainer) newDryRunLearner(req *pbCom.StartTaskRequest) (Learner, error) {
+	taskId := req.TaskID
+	params := *req.Params.TrainParams
+	// the algorithm of PSI is the one of the task, which the Executors agree on by the numbers of all their samples,
+	// and is selected by the number of all the local samples if it's not agreed on
+	params.PsiAlgorithm = psi.ResolveAlgorithm(params.PsiAlgorithm, psi.SampleNum(req.File))
+	// the intersection of the subset is not cached, the task caches the one of all the samples
+	params.AlignmentKey = ""
+
+	file, err := csv.SampleRows(req.File, params.IdName, taskId, vl_common.DryRunFraction(params.DryRun))
+	if err != nil {
+		return nil, errorx.New(errcodes.ErrCodeDryRun, "failed to select samples of dry run: %s", err.Error())
+	}
+	learner, err := learners.NewLearner(taskId+dryRunSuffix, t.address, req.Params.Algo, &params, file,
+		req.Hosts, req.PaddleFLParams, t.rpcHandler, t, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.dryRunRequests.Store(taskId, req)
+	logger.WithField(logging.TaskIDKey, taskId).Infof("dry run of %d iterations started", vl_common.DryRunIterations(params.DryRun))
+	return learner, nil
+}
+
+// saveDryRunResult fails the task from user if its dry run fails, or starts it without the dry run otherwise,
+// the learner of the dry run is stopped in both cases
+func (t *Trainer) saveDryRunResult(result *pbCom.TrainTaskResult, sourceTaskId string) {
+	t.callback.StopTask(&pbCom.StopTaskRequest{
+		TaskID: result.TaskID,
+		Params: &pbCom.TaskParams{
+			TaskType: pbCom.TaskType_LEARN,
+		},
+	})
+
+	fail := func(errMsg string) {
+		logger.WithField(logging.TaskIDKey, sourceTaskId).Error(errMsg)
+		res := &pbCom.TrainTaskResult{
+			TaskID: sourceTaskId,
+			ErrMsg: errMsg,
+		}
+		if err := t.callback.SaveModel(res); err != nil {
+			logger.WithField(logging.TaskIDKey, sourceTaskId).Errorf("failed to save training result, and error is[%s]", err.Error())
+		}
+		t.callback.StopTask(&pbCom.StopTaskRequest{
+			TaskID: sourceTaskId,
+			Params: &pbCom.TaskParams{
+				TaskType: pbCom.TaskType_LEARN,
+			},
+		})
+	}
+	if !result.Success {
+		fail("dry run failed: " + result.ErrMsg)
+		return
+	}
+
+	v, ok := t.dryRunRequests.Load(sourceTaskId)
+	if !ok {
+		logger.WithField(logging.TaskIDKey, sourceTaskId).Warn("dry run finished, but the task was stopped")
+		return
+	}
+	logger.WithField(logging.TaskIDKey, sourceTaskId).Info("dry run succeeded, start training")
+	t.dryRunRequests.Delete(sourceTaskId)
+
+	// restart the task with the request without the dry run
+	req := *v.(*pbCom.StartTaskRequest)
+	taskParams := *req.Params
+	trainParams := *taskParams.TrainParams
+	trainParams.DryRun = nil
+	taskParams.TrainParams = &trainParams
+	req.Params = &taskParams
+	if err := t.callback.StartTask(&req); err != nil {
+		fail("failed to start training after dry run: " + err.Error())
+	}
+}
+
+// deleteDryRun deletes the learner and the stored request of the dry run of a task from user
+func (t *Trainer) deleteDryRun(taskId string) {
+	if l, ok := t.learnerExists(taskId + dryRunSuffix); ok {
+		if r, ok := l.(Releaser); ok {
+			r.Release()
+		}
+		t.deleteLearner(taskId + dryRunSuffix)
+	}
+	t.dryRunRequests.Delete(taskId)
+}
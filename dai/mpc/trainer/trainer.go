@@ -26,6 +26,7 @@ import (
 	"github.com/PaddlePaddle/PaddleDTX/dai/mpc/livaluator"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pb "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/logging"
 )
 
 var (
@@ -41,6 +42,12 @@ type Learner interface {
 	Advance(payload []byte) (*pb.TrainResponse, error)
 }
 
+// Releaser is implemented by Learners which store samples in local files,
+// Release removes the files when the Learner is deleted
+type Releaser interface {
+	Release()
+}
+
 // Evaluator performs model evaluation, supports cross-validation, LOO, validation by proportional random division.
 // The basic steps of evaluation:
 //  Divide the dataset in some way
@@ -144,9 +151,13 @@ type Trainer struct {
 	evaluators     sync.Map
 	liveEvaluators sync.Map
 	trainResults   sync.Map
+	dryRunRequests sync.Map // requests of the tasks from user during their dry runs, by TaskID
 	rpcHandler     RpcHandler
 	callback       Callback
 	address        string
+
+	checkpointHolder   CheckpointHolder
+	checkpointInterval uint64 // number of rounds between two checkpoints, zero disables checkpoints
 }
 
 // NewLearner creates a Learner instance related to TaskId and stores it into Memory Storage
@@ -175,9 +186,24 @@ func (t *Trainer) NewLearner(req *pbCom.StartTaskRequest) error {
 
 	var learner Learner
 	var errL error
-	if len(file) > 0 {
+	if t.dryRunEnabled(req) {
+		// the task starts with the learner of the dry run, and is started again when the dry run succeeds
+		learner, errL = t.newDryRunLearner(req)
+		if errL != nil {
+			return errL
+		}
+		t.learners[taskId+dryRunSuffix] = learner
+		return nil
+	} else if len(file) > 0 {
+		if fromEvaluator, _, _ := t.checkOrigin(taskId); fromEvaluator && params.GetAlignmentKey() != "" {
+			// only the intersection of all the samples of the task is cached, not the ones of the subsets
+			p := *params
+			p.AlignmentKey = ""
+			params = &p
+		}
 		le := t.newLiveEvaluator(req)
-		learner, errL = learners.NewLearner(taskId, t.address, algo, params, file, hosts, paddleParams, t.rpcHandler, t, le)
+		cp := t.newCheckpointer(taskId, le != nil)
+		learner, errL = learners.NewLearner(taskId, t.address, algo, params, file, hosts, paddleParams, t.rpcHandler, t, le, cp)
 	} else {
 		learner, errL = learners.NewLearnerWithoutSamples(taskId, t.address, algo, params, hosts, paddleParams, t.rpcHandler, t)
 	}
@@ -187,7 +213,7 @@ func (t *Trainer) NewLearner(req *pbCom.StartTaskRequest) error {
 
 	t.learners[taskId] = learner
 	t.newEvaluator(req)
-	logger.WithField("taskId", taskId).Infof("task stored")
+	logger.WithField(logging.TaskIDKey, taskId).Infof("task stored")
 
 	return nil
 }
@@ -195,7 +221,16 @@ func (t *Trainer) NewLearner(req *pbCom.StartTaskRequest) error {
 // DeleteLearner deletes a task from Memory Storage
 func (t *Trainer) DeleteLearner(req *pbCom.StopTaskRequest) error {
 	taskId := req.TaskID
+	if l, ok := t.learnerExists(taskId); ok {
+		if r, ok := l.(Releaser); ok {
+			r.Release()
+		}
+	}
 	t.deleteLearner(taskId)
+	if _, ok := dryRunSource(taskId); ok {
+		logger.WithField(logging.TaskIDKey, taskId).Info("task deleted")
+		return nil
+	}
 
 	// If the training task came from LiveEvaluator,
 	// didn't create Evaluator or LiveEvaluator for it,
@@ -209,6 +244,7 @@ func (t *Trainer) DeleteLearner(req *pbCom.StopTaskRequest) error {
 				t.deleteEvaluator(taskId)
 			}
 			t.deleteTrainResult(taskId)
+			t.deleteDryRun(taskId)
 		}
 
 		if e, ok := t.liveEvaluatorExists(taskId); ok {
@@ -217,7 +253,7 @@ func (t *Trainer) DeleteLearner(req *pbCom.StopTaskRequest) error {
 		}
 	}
 
-	logger.WithField("taskId", taskId).Info("task deleted")
+	logger.WithField(logging.TaskIDKey, taskId).Info("task deleted")
 	return nil
 }
 
@@ -291,7 +327,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 	// So if find a created Evaluator related to the training task, start the evaluation process.
 	if eva, ok := t.evaluatorExists(result.TaskID); ok && result.Success {
 		// store training result for further use when evaluation is finished
-		logger.WithField("taskId", result.TaskID).Info("Start evaluation")
+		logger.WithField(logging.TaskIDKey, result.TaskID).Info("Start evaluation")
 		t.storeTrainResult(result.TaskID, result)
 		go func() {
 			var ts [][]string
@@ -305,7 +341,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 
 			// evaluation failed, and only save the training result
 			if err != nil {
-				logger.WithField("taskId", result.TaskID).Errorf("failed to start evaluation, and error is[%s]", err.Error())
+				logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to start evaluation, and error is[%s]", err.Error())
 				t.SavePredictAndEvaluatResult(result)
 			}
 		}()
@@ -314,7 +350,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 	}
 
 	stopTask := func() {
-		logger.WithField("taskId", result.TaskID).Infof("Stop training task. And delete model[%s] and training result[%t]",
+		logger.WithField(logging.TaskIDKey, result.TaskID).Infof("Stop training task. And delete model[%s] and training result[%t]",
 			string(result.Model), result.Success)
 
 		req := &pbCom.StopTaskRequest{
@@ -327,6 +363,11 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 		t.callback.StopTask(req)
 	}
 
+	if sourceTaskId, ok := dryRunSource(result.TaskID); ok {
+		go t.saveDryRunResult(result, sourceTaskId)
+		return
+	}
+
 	fromEvaluator, fromLiveEvaluator, sourceTaskId := t.checkOrigin(result.TaskID)
 	if fromEvaluator {
 		// If training task is from Evaluator,
@@ -337,7 +378,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 				eva.SaveModel(result)
 			}()
 		} else {
-			logger.WithField("taskId", result.TaskID).Errorf("failed to find evaluator[%s]", sourceTaskId)
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to find evaluator[%s]", sourceTaskId)
 		}
 
 		// For training task from Evaluator, it's better to stop the task immediately for resource sake.
@@ -351,7 +392,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 				eva.SaveModel(result)
 			}()
 		} else {
-			logger.WithField("taskId", result.TaskID).Errorf("failed to find evaluator[%s]", sourceTaskId)
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to find evaluator[%s]", sourceTaskId)
 		}
 		// For training task from LiveEvaluator, the result is a staged model,
 		// and the task should be stopped after the source task finishing.
@@ -360,7 +401,7 @@ func (t *Trainer) SaveResult(result *pbCom.TrainTaskResult) {
 		// persist the prediction results locally.
 
 		if err := t.callback.SaveModel(result); err != nil {
-			logger.WithField("taskId", result.TaskID).Errorf("failed to save model[%s] and training result[%t], and error is[%s]",
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to save model[%s] and training result[%t], and error is[%s]",
 				string(result.Model), result.Success, err.Error())
 		}
 
@@ -377,18 +418,21 @@ func (t *Trainer) SavePredictAndEvaluatResult(result *pbCom.TrainTaskResult) {
 	// and then store the entire result locally and persistently.
 	if trainResStored, ok := t.trainResultExists(result.TaskID); ok {
 		result.Model = trainResStored.Model
+		result.MetricDelta = trainResStored.MetricDelta
+		result.PrivacyBudget = trainResStored.PrivacyBudget
+		result.EarlyStop = trainResStored.EarlyStop
 		result.Success = true
 
 		if err := t.callback.SaveModel(result); err != nil {
-			logger.WithField("taskId", result.TaskID).Errorf("failed to save model[%s] and training result[%t], and error is[%s]",
+			logger.WithField(logging.TaskIDKey, result.TaskID).Errorf("failed to save model[%s] and training result[%t], and error is[%s]",
 				string(result.Model), result.Success, err.Error())
 		}
 	} else {
-		logger.WithField("taskId", result.TaskID).Error("failed to get stored model.")
+		logger.WithField(logging.TaskIDKey, result.TaskID).Error("failed to get stored model.")
 	}
 
 	// The result has been saved (but maybe failed), stop the task.
-	logger.WithField("taskId", result.TaskID).Infof("Stop training task. And delete model[%s] and training result[%t]",
+	logger.WithField(logging.TaskIDKey, result.TaskID).Infof("Stop training task. And delete model[%s] and training result[%t]",
 		string(result.Model), result.Success)
 
 	req := &pbCom.StopTaskRequest{
@@ -457,7 +501,7 @@ func (t *Trainer) newEvaluator(req *pbCom.StartTaskRequest) {
 		if err == nil {
 			t.evaluators.LoadOrStore(taskId, ev)
 		} else {
-			logger.WithField("taskId", taskId).Errorf("failed to create evaluator, and error is[%s]",
+			logger.WithField(logging.TaskIDKey, taskId).Errorf("failed to create evaluator, and error is[%s]",
 				err.Error())
 		}
 	}
@@ -472,7 +516,7 @@ func (t *Trainer) newLiveEvaluator(req *pbCom.StartTaskRequest) LiveEvaluator {
 			t.liveEvaluators.LoadOrStore(taskId, lev)
 			return lev
 		} else {
-			logger.WithField("taskId", taskId).Errorf("failed to create live evaluator, and error is[%s]",
+			logger.WithField(logging.TaskIDKey, taskId).Errorf("failed to create live evaluator, and error is[%s]",
 				err.Error())
 			return nil
 		}
@@ -512,6 +556,12 @@ func (t *Trainer) deleteTrainResult(taskId string) {
 	t.trainResults.Delete(taskId)
 }
 
+// SetLearnerLimit changes the upper limit of the number of Learners,
+// running Learners are kept even if the number exceeds the new limit
+func (t *Trainer) SetLearnerLimit(learnerLimit int) {
+	t.learnerLimit = learnerLimit
+}
+
 // NewTrainer creates a Trainer instance,
 // address indicates local mpc-node address
 // learnerLimit indicates the upper limit of the number of Learners
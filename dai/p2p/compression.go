@@ -0,0 +1,147 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CompressionNone disables message compression
+	CompressionNone = "none"
+	// CompressionGzip compresses messages with gzip
+	CompressionGzip = "gzip"
+	// CompressionSnappy compresses messages with snappy
+	CompressionSnappy = "snappy"
+
+	// grpcHeaderLen is the length of the prefix of each message on the wire
+	grpcHeaderLen = 5
+)
+
+var logger = logrus.WithField("module", "p2p")
+
+func init() {
+	// registered on both sides, so that servers decompress requests and reply with the same codec
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// snappyCompressor implements encoding.Compressor with snappy framing format
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string {
+	return CompressionSnappy
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+// CompressionDialOptions returns options to compress requests sent to peers with the compressor name,
+// the server replies with the same compressor. Peers which don't support the compressor get requests
+// uncompressed since then. It returns nil if name is empty or "none".
+func CompressionDialOptions(name string) []grpc.DialOption {
+	if name == "" || name == CompressionNone {
+		return nil
+	}
+	c := &compression{name: name}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(c.intercept),
+		grpc.WithStatsHandler(compressionStats{}),
+	}
+}
+
+// compression negotiates the compressor with peers
+type compression struct {
+	name        string
+	unsupported sync.Map // targets of peers not supporting the compressor
+}
+
+// intercept sends the request compressed unless the peer is known not to support the compressor,
+// and resends it uncompressed if the peer fails to decompress it
+func (c *compression) intercept(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	target := cc.Target()
+	if _, ok := c.unsupported.Load(target); !ok {
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.UseCompressor(c.name))...)
+		if !compressorUnsupported(err) {
+			return err
+		}
+		logger.WithField("peer", target).Warnf("peer doesn't support %s compression, fall back to none", c.name)
+		c.unsupported.Store(target, struct{}{})
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// compressorUnsupported checks whether err is returned by a server without the compressor,
+// the request is rejected before it's handled so it's safe to resend
+func compressorUnsupported(err error) bool {
+	if status.Code(err) != codes.Unimplemented {
+		return false
+	}
+	return strings.Contains(status.Convert(err).Message(), "Decompressor is not installed")
+}
+
+type methodKey struct{}
+
+// compressionStats logs the compression ratio of messages
+type compressionStats struct{}
+
+func (compressionStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (compressionStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	switch p := s.(type) {
+	case *stats.OutPayload:
+		logRatio(ctx, "sent", p.Length, p.WireLength)
+	case *stats.InPayload:
+		logRatio(ctx, "received", p.Length, p.WireLength)
+	}
+}
+
+func (compressionStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (compressionStats) HandleConn(context.Context, stats.ConnStats) {}
+
+// logRatio logs the ratio of the message size to its size on the wire
+func logRatio(ctx context.Context, direction string, length, wireLength int) {
+	if !logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	compressed := wireLength - grpcHeaderLen
+	if compressed <= 0 {
+		return
+	}
+	method, _ := ctx.Value(methodKey{}).(string)
+	logger.WithField("method", method).Debugf("%s message of %d bytes as %d bytes, compression ratio %.2f",
+		direction, length, compressed, float64(length)/float64(compressed))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestCompressionDialOptions(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	defer s.Stop()
+
+	if opts := CompressionDialOptions(CompressionNone); opts != nil {
+		t.Errorf("expected no options for none, got %d", len(opts))
+	}
+	for _, name := range []string{CompressionGzip, CompressionSnappy} {
+		opts := append([]grpc.DialOption{grpc.WithInsecure()}, CompressionDialOptions(name)...)
+		conn, err := grpc.Dial(lis.Addr().String(), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		conn.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("%s: unexpected status %v", name, resp.Status)
+		}
+	}
+}
+
+func TestCompressionFallback(t *testing.T) {
+	conn, err := grpc.Dial("127.0.0.1:1", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := &compression{name: CompressionSnappy}
+	var calls, compressed int
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		calls++
+		for _, o := range opts {
+			if _, ok := o.(grpc.CompressorCallOption); ok {
+				compressed++
+				return status.Error(codes.Unimplemented, `grpc: Decompressor is not installed for grpc-encoding "snappy"`)
+			}
+		}
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.intercept(context.Background(), "/test", nil, nil, conn, invoker); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+	// the first request is resent uncompressed, and the second one is sent uncompressed directly
+	if calls != 3 || compressed != 1 {
+		t.Errorf("expected 3 calls with 1 compressed, got %d calls with %d compressed", calls, compressed)
+	}
+
+	unimplemented := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		return status.Error(codes.Unimplemented, "unknown method")
+	}
+	c = &compression{name: CompressionGzip}
+	if err := c.intercept(context.Background(), "/test", nil, nil, conn, unimplemented); status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected unimplemented error, got %v", err)
+	}
+	if _, ok := c.unsupported.Load(conn.Target()); ok {
+		t.Error("peer marked as not supporting gzip by other errors")
+	}
+}
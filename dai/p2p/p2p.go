@@ -17,6 +17,8 @@ import (
 	"errors"
 	"log"
 	"sync"
+
+	"google.golang.org/grpc"
 )
 
 // State is the state of the P2P
@@ -32,18 +34,26 @@ const (
 
 // P2P defines p2p network
 type P2P struct {
-	peers sync.Map       // list of nodes, key is 'ip:port', and value is '*Peer'
-	state State          // state of p2p network
-	wg    sync.WaitGroup // for waiting all connections closed when get stop signal
+	peers    sync.Map          // list of nodes, key is 'ip:port', and value is '*Peer'
+	state    State             // state of p2p network
+	wg       sync.WaitGroup    // for waiting all connections closed when get stop signal
+	dialOpts []grpc.DialOption // options used to connect to peers
 }
 
-// NewP2P creates P2P instance
+// NewP2P creates P2P instance, connections to peers are plaintext
 func NewP2P(addrs ...string) *P2P {
+	return NewP2PWithDialOptions([]grpc.DialOption{grpc.WithInsecure()}, addrs...)
+}
+
+// NewP2PWithDialOptions creates P2P instance connecting to peers with dialOpts,
+// which should include the transport credentials, such as TLS
+func NewP2PWithDialOptions(dialOpts []grpc.DialOption, addrs ...string) *P2P {
 	p := &P2P{
-		state: NEW,
+		state:    NEW,
+		dialOpts: dialOpts,
 	}
 	for _, a := range addrs {
-		p.peers.Store(a, newPeer(a))
+		p.peers.Store(a, newPeer(a, dialOpts))
 	}
 	return p
 }
@@ -77,7 +87,7 @@ func (p *P2P) GetPeer(address string) (*Peer, error) {
 
 // getPeerNotExist gets an available peer, creates one if peer does not exist
 func (p *P2P) getPeerNotExist(address string) *Peer {
-	peer, _ := p.peers.LoadOrStore(address, newPeer(address))
+	peer, _ := p.peers.LoadOrStore(address, newPeer(address, p.dialOpts))
 	return peer.(*Peer)
 }
 
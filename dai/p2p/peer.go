@@ -26,6 +26,8 @@ type Peer struct {
 	address string
 	// grpc Connection
 	grpcConn *grpc.ClientConn
+	// options used to create connection
+	dialOpts []grpc.DialOption
 	// lock
 	lock sync.Mutex
 }
@@ -69,7 +71,7 @@ func (p *Peer) needReconnect() bool {
 
 // getConn creates grpc connection
 func (p *Peer) getConn() error {
-	conn, err := grpc.Dial(p.address, grpc.WithInsecure())
+	conn, err := grpc.Dial(p.address, p.dialOpts...)
 	if err != nil {
 		log.Printf("Failed to connect server! error: %v", err.Error())
 		return err
@@ -84,9 +86,10 @@ func (p *Peer) closeConn() {
 }
 
 // newPeer creates peer
-func newPeer(address string) *Peer {
+func newPeer(address string, dialOpts []grpc.DialOption) *Peer {
 	p := &Peer{
-		address: address,
+		address:  address,
+		dialOpts: dialOpts,
 	}
 
 	return p
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+const (
+	// DefaultKeepaliveTime is the idle time after which a connection is pinged to check it's alive
+	DefaultKeepaliveTime = 30 * time.Second
+	// DefaultKeepaliveTimeout is the time to wait for the ack of a ping before closing the connection
+	DefaultKeepaliveTimeout = 10 * time.Second
+	// DefaultMaxMsgSizeMB is the maximum size of messages sent and received, in MB,
+	// large enough for gradients of DNN tasks, gRPC receives at most 4MB by default
+	DefaultMaxMsgSizeMB = 1024
+
+	// minKeepaliveTime is the minimum interval of pings accepted by the server, which is less than
+	// the minimum keepalive time of gRPC clients, so that peers with any keepalive time are not disconnected
+	minKeepaliveTime = 5 * time.Second
+)
+
+// Transport is the keepalive and message size settings of gRPC connections between executors
+type Transport struct {
+	KeepaliveTime       time.Duration
+	KeepaliveTimeout    time.Duration
+	PermitWithoutStream bool // whether to ping connections without active RPCs
+	MaxRecvMsgSize      int  // in bytes
+	MaxSendMsgSize      int  // in bytes
+}
+
+// NewTransport returns the transport settings of conf, the defaults are used for unset ones or if conf is nil
+func NewTransport(conf *config.ExecutorMpcConf) Transport {
+	t := Transport{
+		KeepaliveTime:    DefaultKeepaliveTime,
+		KeepaliveTimeout: DefaultKeepaliveTimeout,
+		MaxRecvMsgSize:   DefaultMaxMsgSizeMB << 20,
+		MaxSendMsgSize:   DefaultMaxMsgSizeMB << 20,
+	}
+	if conf == nil {
+		return t
+	}
+	if conf.KeepaliveTime > 0 {
+		t.KeepaliveTime = conf.KeepaliveTime
+	}
+	if conf.KeepaliveTimeout > 0 {
+		t.KeepaliveTimeout = conf.KeepaliveTimeout
+	}
+	if conf.MaxRecvMsgSizeMB > 0 {
+		t.MaxRecvMsgSize = conf.MaxRecvMsgSizeMB << 20
+	}
+	if conf.MaxSendMsgSizeMB > 0 {
+		t.MaxSendMsgSize = conf.MaxSendMsgSizeMB << 20
+	}
+	t.PermitWithoutStream = conf.PermitWithoutStream
+	return t
+}
+
+// DialOptions returns options to connect to other executors with the transport settings
+func (t Transport) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                t.KeepaliveTime,
+			Timeout:             t.KeepaliveTimeout,
+			PermitWithoutStream: t.PermitWithoutStream,
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(t.MaxRecvMsgSize), grpc.MaxCallSendMsgSize(t.MaxSendMsgSize)),
+	}
+}
+
+// ServerOptions returns options of the gRPC server with the transport settings.
+// The server pings idle connections as clients do, and accepts pings of clients without active RPCs.
+func (t Transport) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    t.KeepaliveTime,
+			Timeout: t.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minKeepaliveTime,
+			PermitWithoutStream: true,
+		}),
+		grpc.MaxRecvMsgSize(t.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(t.MaxSendMsgSize),
+	}
+}
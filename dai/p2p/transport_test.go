@@ -0,0 +1,78 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+func TestNewTransport(t *testing.T) {
+	defaults := Transport{
+		KeepaliveTime:    DefaultKeepaliveTime,
+		KeepaliveTimeout: DefaultKeepaliveTimeout,
+		MaxRecvMsgSize:   DefaultMaxMsgSizeMB << 20,
+		MaxSendMsgSize:   DefaultMaxMsgSizeMB << 20,
+	}
+	if tr := NewTransport(nil); tr != defaults {
+		t.Errorf("expected defaults %+v, got %+v", defaults, tr)
+	}
+	if tr := NewTransport(&config.ExecutorMpcConf{}); tr != defaults {
+		t.Errorf("expected defaults %+v, got %+v", defaults, tr)
+	}
+
+	tr := NewTransport(&config.ExecutorMpcConf{KeepaliveTime: time.Minute, PermitWithoutStream: true, MaxRecvMsgSizeMB: 8})
+	expected := Transport{time.Minute, DefaultKeepaliveTimeout, true, 8 << 20, DefaultMaxMsgSizeMB << 20}
+	if tr != expected {
+		t.Errorf("expected %+v, got %+v", expected, tr)
+	}
+}
+
+func TestTransportMaxMsgSize(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(NewTransport(&config.ExecutorMpcConf{MaxRecvMsgSizeMB: 1}).ServerOptions()...)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), append([]grpc.DialOption{grpc.WithInsecure()},
+		NewTransport(&config.ExecutorMpcConf{PermitWithoutStream: true}).DialOptions()...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	// the request larger than the limit of the server is rejected
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("a", 2<<20)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+}
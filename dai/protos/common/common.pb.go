@@ -27,18 +27,21 @@ const (
 	Algorithm_LINEAR_REGRESSION_VL Algorithm = 0
 	Algorithm_LOGIC_REGRESSION_VL  Algorithm = 1
 	Algorithm_DNN_PADDLEFL_VL      Algorithm = 2
+	Algorithm_XGBOOST_VL           Algorithm = 3
 )
 
 var Algorithm_name = map[int32]string{
 	0: "LINEAR_REGRESSION_VL",
 	1: "LOGIC_REGRESSION_VL",
 	2: "DNN_PADDLEFL_VL",
+	3: "XGBOOST_VL",
 }
 
 var Algorithm_value = map[string]int32{
 	"LINEAR_REGRESSION_VL": 0,
 	"LOGIC_REGRESSION_VL":  1,
 	"DNN_PADDLEFL_VL":      2,
+	"XGBOOST_VL":           3,
 }
 
 func (x Algorithm) String() string {
@@ -165,16 +168,45 @@ func (CaseType) EnumDescriptor() ([]byte, []int) {
 
 // TrainParams lists all the parameters for training
 type TrainParams struct {
-	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
-	LabelName            string   `protobuf:"bytes,2,opt,name=labelName,proto3" json:"labelName,omitempty"`
-	RegMode              RegMode  `protobuf:"varint,3,opt,name=regMode,proto3,enum=common.RegMode" json:"regMode,omitempty"`
-	RegParam             float64  `protobuf:"fixed64,4,opt,name=regParam,proto3" json:"regParam,omitempty"`
-	Alpha                float64  `protobuf:"fixed64,5,opt,name=alpha,proto3" json:"alpha,omitempty"`
-	Amplitude            float64  `protobuf:"fixed64,6,opt,name=amplitude,proto3" json:"amplitude,omitempty"`
-	Accuracy             int64    `protobuf:"varint,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
-	IsTagPart            bool     `protobuf:"varint,8,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
-	IdName               string   `protobuf:"bytes,9,opt,name=idName,proto3" json:"idName,omitempty"`
-	BatchSize            int64    `protobuf:"varint,10,opt,name=batchSize,proto3" json:"batchSize,omitempty"`
+	Label        string         `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	LabelName    string         `protobuf:"bytes,2,opt,name=labelName,proto3" json:"labelName,omitempty"`
+	RegMode      RegMode        `protobuf:"varint,3,opt,name=regMode,proto3,enum=common.RegMode" json:"regMode,omitempty"`
+	RegParam     float64        `protobuf:"fixed64,4,opt,name=regParam,proto3" json:"regParam,omitempty"`
+	Alpha        float64        `protobuf:"fixed64,5,opt,name=alpha,proto3" json:"alpha,omitempty"`
+	Amplitude    float64        `protobuf:"fixed64,6,opt,name=amplitude,proto3" json:"amplitude,omitempty"`
+	Accuracy     int64          `protobuf:"varint,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	IsTagPart    bool           `protobuf:"varint,8,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
+	IdName       string         `protobuf:"bytes,9,opt,name=idName,proto3" json:"idName,omitempty"`
+	BatchSize    int64          `protobuf:"varint,10,opt,name=batchSize,proto3" json:"batchSize,omitempty"`
+	XgbParams    *XGBoostParams `protobuf:"bytes,11,opt,name=xgbParams,proto3" json:"xgbParams,omitempty"`
+	PsiAlgorithm string         `protobuf:"bytes,12,opt,name=psiAlgorithm,proto3" json:"psiAlgorithm,omitempty"`
+	// for incremental training, which updates the model of TaskParams.modelTaskID with the samples of the task
+	Incremental    bool                 `protobuf:"varint,13,opt,name=incremental,proto3" json:"incremental,omitempty"`
+	UpdateRounds   int64                `protobuf:"varint,14,opt,name=updateRounds,proto3" json:"updateRounds,omitempty"`
+	DriftTolerance float64              `protobuf:"fixed64,15,opt,name=driftTolerance,proto3" json:"driftTolerance,omitempty"`
+	BaseModel      *TrainModels         `protobuf:"bytes,16,opt,name=baseModel,proto3" json:"baseModel,omitempty"`
+	Scaling        string               `protobuf:"bytes,17,opt,name=scaling,proto3" json:"scaling,omitempty"`
+	Dp             *DPParams            `protobuf:"bytes,18,opt,name=dp,proto3" json:"dp,omitempty"`
+	Categorical    *CategoricalParams   `protobuf:"bytes,19,opt,name=categorical,proto3" json:"categorical,omitempty"`
+	EarlyStopping  *EarlyStoppingParams `protobuf:"bytes,20,opt,name=earlyStopping,proto3" json:"earlyStopping,omitempty"`
+	// seed of mini-batch shuffling and dataset splits of evaluation and live evaluation, which makes training reproducible,
+	// the splits are seeded by the task ID and mini-batches by the round only if 0
+	Seed      int64            `protobuf:"varint,21,opt,name=seed,proto3" json:"seed,omitempty"`
+	Optimizer *OptimizerParams `protobuf:"bytes,22,opt,name=optimizer,proto3" json:"optimizer,omitempty"`
+	// for prediction with linear and logistic regression, the drift score above which a column of the samples to predict
+	// is flagged as drifted from the training samples, drift is not detected if 0
+	DriftThreshold float64 `protobuf:"fixed64,23,opt,name=driftThreshold,proto3" json:"driftThreshold,omitempty"`
+	// for linear and logistic regression, column of the samples of the party with label whose non-negative values
+	// weight the samples in the loss and gradients, samples are equally weighted if empty
+	WeightColumn string      `protobuf:"bytes,24,opt,name=weightColumn,proto3" json:"weightColumn,omitempty"`
+	Loss         *LossParams `protobuf:"bytes,25,opt,name=loss,proto3" json:"loss,omitempty"`
+	WithScores   bool        `protobuf:"varint,26,opt,name=withScores,proto3" json:"withScores,omitempty"`
+	// for linear and logistic regression, the dry run before training, there's no dry run if not set
+	DryRun       *DryRunParams `protobuf:"bytes,27,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	AlignmentKey string        `protobuf:"bytes,28,opt,name=alignmentKey,proto3" json:"alignmentKey,omitempty"`
+	// for prediction with linear and logistic regression, the outcomes include the contribution of each feature
+	// of each party to the prediction, each party computes the ones of its own features
+	WithExplanations     bool     `protobuf:"varint,29,opt,name=withExplanations,proto3" json:"withExplanations,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -275,92 +307,791 @@ func (m *TrainParams) GetBatchSize() int64 {
 	return 0
 }
 
+func (m *TrainParams) GetXgbParams() *XGBoostParams {
+	if m != nil {
+		return m.XgbParams
+	}
+	return nil
+}
+
+func (m *TrainParams) GetPsiAlgorithm() string {
+	if m != nil {
+		return m.PsiAlgorithm
+	}
+	return ""
+}
+
+func (m *TrainParams) GetIncremental() bool {
+	if m != nil {
+		return m.Incremental
+	}
+	return false
+}
+
+func (m *TrainParams) GetUpdateRounds() int64 {
+	if m != nil {
+		return m.UpdateRounds
+	}
+	return 0
+}
+
+func (m *TrainParams) GetDriftTolerance() float64 {
+	if m != nil {
+		return m.DriftTolerance
+	}
+	return 0
+}
+
+func (m *TrainParams) GetBaseModel() *TrainModels {
+	if m != nil {
+		return m.BaseModel
+	}
+	return nil
+}
+
+func (m *TrainParams) GetScaling() string {
+	if m != nil {
+		return m.Scaling
+	}
+	return ""
+}
+
+func (m *TrainParams) GetDp() *DPParams {
+	if m != nil {
+		return m.Dp
+	}
+	return nil
+}
+
+func (m *TrainParams) GetCategorical() *CategoricalParams {
+	if m != nil {
+		return m.Categorical
+	}
+	return nil
+}
+
+func (m *TrainParams) GetEarlyStopping() *EarlyStoppingParams {
+	if m != nil {
+		return m.EarlyStopping
+	}
+	return nil
+}
+
+func (m *TrainParams) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *TrainParams) GetOptimizer() *OptimizerParams {
+	if m != nil {
+		return m.Optimizer
+	}
+	return nil
+}
+
+func (m *TrainParams) GetDriftThreshold() float64 {
+	if m != nil {
+		return m.DriftThreshold
+	}
+	return 0
+}
+
+func (m *TrainParams) GetWeightColumn() string {
+	if m != nil {
+		return m.WeightColumn
+	}
+	return ""
+}
+
+func (m *TrainParams) GetLoss() *LossParams {
+	if m != nil {
+		return m.Loss
+	}
+	return nil
+}
+
+func (m *TrainParams) GetWithScores() bool {
+	if m != nil {
+		return m.WithScores
+	}
+	return false
+}
+
+func (m *TrainParams) GetDryRun() *DryRunParams {
+	if m != nil {
+		return m.DryRun
+	}
+	return nil
+}
+
+func (m *TrainParams) GetAlignmentKey() string {
+	if m != nil {
+		return m.AlignmentKey
+	}
+	return ""
+}
+
+func (m *TrainParams) GetWithExplanations() bool {
+	if m != nil {
+		return m.WithExplanations
+	}
+	return false
+}
+
+// EarlyStoppingParams defines when training stops early, which is when the metric of live evaluation
+// on the validation set hasn't improved by more than minDelta for patience rounds
+type EarlyStoppingParams struct {
+	Metric               string   `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Patience             int64    `protobuf:"varint,2,opt,name=patience,proto3" json:"patience,omitempty"`
+	MinDelta             float64  `protobuf:"fixed64,3,opt,name=minDelta,proto3" json:"minDelta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EarlyStoppingParams) Reset()         { *m = EarlyStoppingParams{} }
+func (m *EarlyStoppingParams) String() string { return proto.CompactTextString(m) }
+func (*EarlyStoppingParams) ProtoMessage()    {}
+func (*EarlyStoppingParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{1}
+}
+
+func (m *EarlyStoppingParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EarlyStoppingParams.Unmarshal(m, b)
+}
+func (m *EarlyStoppingParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EarlyStoppingParams.Marshal(b, m, deterministic)
+}
+func (m *EarlyStoppingParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EarlyStoppingParams.Merge(m, src)
+}
+func (m *EarlyStoppingParams) XXX_Size() int {
+	return xxx_messageInfo_EarlyStoppingParams.Size(m)
+}
+func (m *EarlyStoppingParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_EarlyStoppingParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EarlyStoppingParams proto.InternalMessageInfo
+
+func (m *EarlyStoppingParams) GetMetric() string {
+	if m != nil {
+		return m.Metric
+	}
+	return ""
+}
+
+func (m *EarlyStoppingParams) GetPatience() int64 {
+	if m != nil {
+		return m.Patience
+	}
+	return 0
+}
+
+func (m *EarlyStoppingParams) GetMinDelta() float64 {
+	if m != nil {
+		return m.MinDelta
+	}
+	return 0
+}
+
+// CategoricalParams lists the categorical columns and how they are encoded, each party encodes the ones
+// in its samples by the categories found in the training samples, which are stored with the model
+type CategoricalParams struct {
+	Columns              []string `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Encoding             string   `protobuf:"bytes,2,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	Unknown              string   `protobuf:"bytes,3,opt,name=unknown,proto3" json:"unknown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CategoricalParams) Reset()         { *m = CategoricalParams{} }
+func (m *CategoricalParams) String() string { return proto.CompactTextString(m) }
+func (*CategoricalParams) ProtoMessage()    {}
+func (*CategoricalParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{2}
+}
+
+func (m *CategoricalParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CategoricalParams.Unmarshal(m, b)
+}
+func (m *CategoricalParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CategoricalParams.Marshal(b, m, deterministic)
+}
+func (m *CategoricalParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CategoricalParams.Merge(m, src)
+}
+func (m *CategoricalParams) XXX_Size() int {
+	return xxx_messageInfo_CategoricalParams.Size(m)
+}
+func (m *CategoricalParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_CategoricalParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CategoricalParams proto.InternalMessageInfo
+
+func (m *CategoricalParams) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *CategoricalParams) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
+
+func (m *CategoricalParams) GetUnknown() string {
+	if m != nil {
+		return m.Unknown
+	}
+	return ""
+}
+
+// DPParams lists the parameters of differential privacy, which adds Gaussian noise to the gradients in each round
+type DPParams struct {
+	Epsilon              float64  `protobuf:"fixed64,1,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Delta                float64  `protobuf:"fixed64,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	ClipNorm             float64  `protobuf:"fixed64,3,opt,name=clipNorm,proto3" json:"clipNorm,omitempty"`
+	Rounds               int64    `protobuf:"varint,4,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DPParams) Reset()         { *m = DPParams{} }
+func (m *DPParams) String() string { return proto.CompactTextString(m) }
+func (*DPParams) ProtoMessage()    {}
+func (*DPParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{3}
+}
+
+func (m *DPParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DPParams.Unmarshal(m, b)
+}
+func (m *DPParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DPParams.Marshal(b, m, deterministic)
+}
+func (m *DPParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DPParams.Merge(m, src)
+}
+func (m *DPParams) XXX_Size() int {
+	return xxx_messageInfo_DPParams.Size(m)
+}
+func (m *DPParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_DPParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DPParams proto.InternalMessageInfo
+
+func (m *DPParams) GetEpsilon() float64 {
+	if m != nil {
+		return m.Epsilon
+	}
+	return 0
+}
+
+func (m *DPParams) GetDelta() float64 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+func (m *DPParams) GetClipNorm() float64 {
+	if m != nil {
+		return m.ClipNorm
+	}
+	return 0
+}
+
+func (m *DPParams) GetRounds() int64 {
+	if m != nil {
+		return m.Rounds
+	}
+	return 0
+}
+
+// XGBoostParams lists the hyperparameters of vertical XGBoost
+type XGBoostParams struct {
+	MaxDepth             int64    `protobuf:"varint,1,opt,name=maxDepth,proto3" json:"maxDepth,omitempty"`
+	LearningRate         float64  `protobuf:"fixed64,2,opt,name=learningRate,proto3" json:"learningRate,omitempty"`
+	NEstimators          int64    `protobuf:"varint,3,opt,name=nEstimators,proto3" json:"nEstimators,omitempty"`
+	Lambda               float64  `protobuf:"fixed64,4,opt,name=lambda,proto3" json:"lambda,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *XGBoostParams) Reset()         { *m = XGBoostParams{} }
+func (m *XGBoostParams) String() string { return proto.CompactTextString(m) }
+func (*XGBoostParams) ProtoMessage()    {}
+func (*XGBoostParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{4}
+}
+
+func (m *XGBoostParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_XGBoostParams.Unmarshal(m, b)
+}
+func (m *XGBoostParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_XGBoostParams.Marshal(b, m, deterministic)
+}
+func (m *XGBoostParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_XGBoostParams.Merge(m, src)
+}
+func (m *XGBoostParams) XXX_Size() int {
+	return xxx_messageInfo_XGBoostParams.Size(m)
+}
+func (m *XGBoostParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_XGBoostParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_XGBoostParams proto.InternalMessageInfo
+
+func (m *XGBoostParams) GetMaxDepth() int64 {
+	if m != nil {
+		return m.MaxDepth
+	}
+	return 0
+}
+
+func (m *XGBoostParams) GetLearningRate() float64 {
+	if m != nil {
+		return m.LearningRate
+	}
+	return 0
+}
+
+func (m *XGBoostParams) GetNEstimators() int64 {
+	if m != nil {
+		return m.NEstimators
+	}
+	return 0
+}
+
+func (m *XGBoostParams) GetLambda() float64 {
+	if m != nil {
+		return m.Lambda
+	}
+	return 0
+}
+
 // TrainModels is final result of distributed training
 type TrainModels struct {
-	Thetas               map[string]float64 `protobuf:"bytes,1,rep,name=thetas,proto3" json:"thetas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
-	Xbars                map[string]float64 `protobuf:"bytes,2,rep,name=xbars,proto3" json:"xbars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
-	Sigmas               map[string]float64 `protobuf:"bytes,3,rep,name=sigmas,proto3" json:"sigmas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
-	Label                string             `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
-	IsTagPart            bool               `protobuf:"varint,5,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
-	IdName               string             `protobuf:"bytes,6,opt,name=idName,proto3" json:"idName,omitempty"`
-	Path                 string             `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Thetas       map[string]float64 `protobuf:"bytes,1,rep,name=thetas,proto3" json:"thetas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Xbars        map[string]float64 `protobuf:"bytes,2,rep,name=xbars,proto3" json:"xbars,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Sigmas       map[string]float64 `protobuf:"bytes,3,rep,name=sigmas,proto3" json:"sigmas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Label        string             `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	IsTagPart    bool               `protobuf:"varint,5,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
+	IdName       string             `protobuf:"bytes,6,opt,name=idName,proto3" json:"idName,omitempty"`
+	Path         string             `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
+	Xgboost      *XGBoostModel      `protobuf:"bytes,8,opt,name=xgboost,proto3" json:"xgboost,omitempty"`
+	PsiAlgorithm string             `protobuf:"bytes,9,opt,name=psiAlgorithm,proto3" json:"psiAlgorithm,omitempty"`
+	Scaling      string             `protobuf:"bytes,10,opt,name=scaling,proto3" json:"scaling,omitempty"`
+	Categories   []*CategoryMapping `protobuf:"bytes,11,rep,name=categories,proto3" json:"categories,omitempty"`
+	// distributions of the columns of the training samples of the party, including the label on the party with label,
+	// which predictions are compared with to detect drift
+	Summaries            map[string]*FeatureSummary `protobuf:"bytes,12,rep,name=summaries,proto3" json:"summaries,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DriftThreshold       float64                    `protobuf:"fixed64,13,opt,name=driftThreshold,proto3" json:"driftThreshold,omitempty"`
+	WeightColumn         string                     `protobuf:"bytes,14,opt,name=weightColumn,proto3" json:"weightColumn,omitempty"`
+	Loss                 *LossParams                `protobuf:"bytes,15,opt,name=loss,proto3" json:"loss,omitempty"`
+	WithScores           bool                       `protobuf:"varint,16,opt,name=withScores,proto3" json:"withScores,omitempty"`
+	WithExplanations     bool                       `protobuf:"varint,17,opt,name=withExplanations,proto3" json:"withExplanations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *TrainModels) Reset()         { *m = TrainModels{} }
+func (m *TrainModels) String() string { return proto.CompactTextString(m) }
+func (*TrainModels) ProtoMessage()    {}
+func (*TrainModels) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{5}
+}
+
+func (m *TrainModels) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrainModels.Unmarshal(m, b)
+}
+func (m *TrainModels) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrainModels.Marshal(b, m, deterministic)
+}
+func (m *TrainModels) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrainModels.Merge(m, src)
+}
+func (m *TrainModels) XXX_Size() int {
+	return xxx_messageInfo_TrainModels.Size(m)
+}
+func (m *TrainModels) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrainModels.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrainModels proto.InternalMessageInfo
+
+func (m *TrainModels) GetThetas() map[string]float64 {
+	if m != nil {
+		return m.Thetas
+	}
+	return nil
+}
+
+func (m *TrainModels) GetXbars() map[string]float64 {
+	if m != nil {
+		return m.Xbars
+	}
+	return nil
+}
+
+func (m *TrainModels) GetSigmas() map[string]float64 {
+	if m != nil {
+		return m.Sigmas
+	}
+	return nil
+}
+
+func (m *TrainModels) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *TrainModels) GetIsTagPart() bool {
+	if m != nil {
+		return m.IsTagPart
+	}
+	return false
+}
+
+func (m *TrainModels) GetIdName() string {
+	if m != nil {
+		return m.IdName
+	}
+	return ""
+}
+
+func (m *TrainModels) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TrainModels) GetXgboost() *XGBoostModel {
+	if m != nil {
+		return m.Xgboost
+	}
+	return nil
+}
+
+func (m *TrainModels) GetPsiAlgorithm() string {
+	if m != nil {
+		return m.PsiAlgorithm
+	}
+	return ""
+}
+
+func (m *TrainModels) GetScaling() string {
+	if m != nil {
+		return m.Scaling
+	}
+	return ""
+}
+
+func (m *TrainModels) GetCategories() []*CategoryMapping {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+func (m *TrainModels) GetSummaries() map[string]*FeatureSummary {
+	if m != nil {
+		return m.Summaries
+	}
+	return nil
+}
+
+func (m *TrainModels) GetDriftThreshold() float64 {
+	if m != nil {
+		return m.DriftThreshold
+	}
+	return 0
+}
+
+func (m *TrainModels) GetWeightColumn() string {
+	if m != nil {
+		return m.WeightColumn
+	}
+	return ""
+}
+
+func (m *TrainModels) GetLoss() *LossParams {
+	if m != nil {
+		return m.Loss
+	}
+	return nil
+}
+
+func (m *TrainModels) GetWithScores() bool {
+	if m != nil {
+		return m.WithScores
+	}
+	return false
+}
+
+func (m *TrainModels) GetWithExplanations() bool {
+	if m != nil {
+		return m.WithExplanations
+	}
+	return false
+}
+
+// CategoryMapping is the encoding of a categorical column built from the training samples
+type CategoryMapping struct {
+	Column               string   `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	Categories           []string `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	Encoding             string   `protobuf:"bytes,3,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	Unknown              string   `protobuf:"bytes,4,opt,name=unknown,proto3" json:"unknown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CategoryMapping) Reset()         { *m = CategoryMapping{} }
+func (m *CategoryMapping) String() string { return proto.CompactTextString(m) }
+func (*CategoryMapping) ProtoMessage()    {}
+func (*CategoryMapping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{6}
+}
+
+func (m *CategoryMapping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CategoryMapping.Unmarshal(m, b)
+}
+func (m *CategoryMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CategoryMapping.Marshal(b, m, deterministic)
+}
+func (m *CategoryMapping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CategoryMapping.Merge(m, src)
+}
+func (m *CategoryMapping) XXX_Size() int {
+	return xxx_messageInfo_CategoryMapping.Size(m)
+}
+func (m *CategoryMapping) XXX_DiscardUnknown() {
+	xxx_messageInfo_CategoryMapping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CategoryMapping proto.InternalMessageInfo
+
+func (m *CategoryMapping) GetColumn() string {
+	if m != nil {
+		return m.Column
+	}
+	return ""
+}
+
+func (m *CategoryMapping) GetCategories() []string {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+func (m *CategoryMapping) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
+
+func (m *CategoryMapping) GetUnknown() string {
+	if m != nil {
+		return m.Unknown
+	}
+	return ""
+}
+
+// XGBoostModel is the local part of a vertical XGBoost model,
+// the party with label holds the structure and leaf weights of all trees,
+// and each party holds the features and thresholds of the splits it owns
+type XGBoostModel struct {
+	Trees                []*XGBoostTree `protobuf:"bytes,1,rep,name=trees,proto3" json:"trees,omitempty"`
+	BaseScore            float64        `protobuf:"fixed64,2,opt,name=baseScore,proto3" json:"baseScore,omitempty"`
+	BinaryClass          bool           `protobuf:"varint,3,opt,name=binaryClass,proto3" json:"binaryClass,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *XGBoostModel) Reset()         { *m = XGBoostModel{} }
+func (m *XGBoostModel) String() string { return proto.CompactTextString(m) }
+func (*XGBoostModel) ProtoMessage()    {}
+func (*XGBoostModel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{7}
+}
+
+func (m *XGBoostModel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_XGBoostModel.Unmarshal(m, b)
+}
+func (m *XGBoostModel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_XGBoostModel.Marshal(b, m, deterministic)
+}
+func (m *XGBoostModel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_XGBoostModel.Merge(m, src)
+}
+func (m *XGBoostModel) XXX_Size() int {
+	return xxx_messageInfo_XGBoostModel.Size(m)
+}
+func (m *XGBoostModel) XXX_DiscardUnknown() {
+	xxx_messageInfo_XGBoostModel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_XGBoostModel proto.InternalMessageInfo
+
+func (m *XGBoostModel) GetTrees() []*XGBoostTree {
+	if m != nil {
+		return m.Trees
+	}
+	return nil
+}
+
+func (m *XGBoostModel) GetBaseScore() float64 {
+	if m != nil {
+		return m.BaseScore
+	}
+	return 0
+}
+
+func (m *XGBoostModel) GetBinaryClass() bool {
+	if m != nil {
+		return m.BinaryClass
+	}
+	return false
+}
+
+// XGBoostTree is a tree of vertical XGBoost
+type XGBoostTree struct {
+	Nodes                []*XGBoostNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func (m *TrainModels) Reset()         { *m = TrainModels{} }
-func (m *TrainModels) String() string { return proto.CompactTextString(m) }
-func (*TrainModels) ProtoMessage()    {}
-func (*TrainModels) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{1}
+func (m *XGBoostTree) Reset()         { *m = XGBoostTree{} }
+func (m *XGBoostTree) String() string { return proto.CompactTextString(m) }
+func (*XGBoostTree) ProtoMessage()    {}
+func (*XGBoostTree) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{8}
 }
 
-func (m *TrainModels) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TrainModels.Unmarshal(m, b)
+func (m *XGBoostTree) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_XGBoostTree.Unmarshal(m, b)
 }
-func (m *TrainModels) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TrainModels.Marshal(b, m, deterministic)
+func (m *XGBoostTree) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_XGBoostTree.Marshal(b, m, deterministic)
 }
-func (m *TrainModels) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TrainModels.Merge(m, src)
+func (m *XGBoostTree) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_XGBoostTree.Merge(m, src)
 }
-func (m *TrainModels) XXX_Size() int {
-	return xxx_messageInfo_TrainModels.Size(m)
+func (m *XGBoostTree) XXX_Size() int {
+	return xxx_messageInfo_XGBoostTree.Size(m)
 }
-func (m *TrainModels) XXX_DiscardUnknown() {
-	xxx_messageInfo_TrainModels.DiscardUnknown(m)
+func (m *XGBoostTree) XXX_DiscardUnknown() {
+	xxx_messageInfo_XGBoostTree.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TrainModels proto.InternalMessageInfo
+var xxx_messageInfo_XGBoostTree proto.InternalMessageInfo
 
-func (m *TrainModels) GetThetas() map[string]float64 {
+func (m *XGBoostTree) GetNodes() []*XGBoostNode {
 	if m != nil {
-		return m.Thetas
+		return m.Nodes
 	}
 	return nil
 }
 
-func (m *TrainModels) GetXbars() map[string]float64 {
+// XGBoostNode is a node of a tree, children of node i are 2i+1 and 2i+2
+type XGBoostNode struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IsLeaf               bool     `protobuf:"varint,2,opt,name=isLeaf,proto3" json:"isLeaf,omitempty"`
+	Weight               float64  `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	IsLocal              bool     `protobuf:"varint,4,opt,name=isLocal,proto3" json:"isLocal,omitempty"`
+	Feature              string   `protobuf:"bytes,5,opt,name=feature,proto3" json:"feature,omitempty"`
+	Threshold            float64  `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *XGBoostNode) Reset()         { *m = XGBoostNode{} }
+func (m *XGBoostNode) String() string { return proto.CompactTextString(m) }
+func (*XGBoostNode) ProtoMessage()    {}
+func (*XGBoostNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{9}
+}
+
+func (m *XGBoostNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_XGBoostNode.Unmarshal(m, b)
+}
+func (m *XGBoostNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_XGBoostNode.Marshal(b, m, deterministic)
+}
+func (m *XGBoostNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_XGBoostNode.Merge(m, src)
+}
+func (m *XGBoostNode) XXX_Size() int {
+	return xxx_messageInfo_XGBoostNode.Size(m)
+}
+func (m *XGBoostNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_XGBoostNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_XGBoostNode proto.InternalMessageInfo
+
+func (m *XGBoostNode) GetId() int32 {
 	if m != nil {
-		return m.Xbars
+		return m.Id
 	}
-	return nil
+	return 0
 }
 
-func (m *TrainModels) GetSigmas() map[string]float64 {
+func (m *XGBoostNode) GetIsLeaf() bool {
 	if m != nil {
-		return m.Sigmas
+		return m.IsLeaf
 	}
-	return nil
+	return false
 }
 
-func (m *TrainModels) GetLabel() string {
+func (m *XGBoostNode) GetWeight() float64 {
 	if m != nil {
-		return m.Label
+		return m.Weight
 	}
-	return ""
+	return 0
 }
 
-func (m *TrainModels) GetIsTagPart() bool {
+func (m *XGBoostNode) GetIsLocal() bool {
 	if m != nil {
-		return m.IsTagPart
+		return m.IsLocal
 	}
 	return false
 }
 
-func (m *TrainModels) GetIdName() string {
+func (m *XGBoostNode) GetFeature() string {
 	if m != nil {
-		return m.IdName
+		return m.Feature
 	}
 	return ""
 }
 
-func (m *TrainModels) GetPath() string {
+func (m *XGBoostNode) GetThreshold() float64 {
 	if m != nil {
-		return m.Path
+		return m.Threshold
 	}
-	return ""
+	return 0
 }
 
 // TaskParams lists all the parameters in a task
@@ -372,6 +1103,11 @@ type TaskParams struct {
 	ModelParams          *TrainModels          `protobuf:"bytes,5,opt,name=modelParams,proto3" json:"modelParams,omitempty"`
 	EvalParams           *EvaluationParams     `protobuf:"bytes,6,opt,name=evalParams,proto3" json:"evalParams,omitempty"`
 	LivalParams          *LiveEvaluationParams `protobuf:"bytes,7,opt,name=livalParams,proto3" json:"livalParams,omitempty"`
+	Priority             int32                 `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	Timeout              int64                 `protobuf:"varint,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	CallbackURL          string                `protobuf:"bytes,10,opt,name=callbackURL,proto3" json:"callbackURL,omitempty"`
+	Labels               map[string]string     `protobuf:"bytes,11,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ResultPublicKey      []byte                `protobuf:"bytes,12,opt,name=resultPublicKey,proto3" json:"resultPublicKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -381,7 +1117,7 @@ func (m *TaskParams) Reset()         { *m = TaskParams{} }
 func (m *TaskParams) String() string { return proto.CompactTextString(m) }
 func (*TaskParams) ProtoMessage()    {}
 func (*TaskParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{2}
+	return fileDescriptor_8f954d82c0b891f6, []int{10}
 }
 
 func (m *TaskParams) XXX_Unmarshal(b []byte) error {
@@ -451,12 +1187,48 @@ func (m *TaskParams) GetLivalParams() *LiveEvaluationParams {
 	return nil
 }
 
+func (m *TaskParams) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *TaskParams) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *TaskParams) GetCallbackURL() string {
+	if m != nil {
+		return m.CallbackURL
+	}
+	return ""
+}
+
+func (m *TaskParams) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *TaskParams) GetResultPublicKey() []byte {
+	if m != nil {
+		return m.ResultPublicKey
+	}
+	return nil
+}
+
 // EvaluationParams lists all the parameters for model evaluation
 type EvaluationParams struct {
 	Enable               bool           `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
 	EvalRule             EvaluationRule `protobuf:"varint,2,opt,name=evalRule,proto3,enum=common.EvaluationRule" json:"evalRule,omitempty"`
 	RandomSplit          *RandomSplit   `protobuf:"bytes,3,opt,name=randomSplit,proto3" json:"randomSplit,omitempty"`
 	Cv                   *CrossVal      `protobuf:"bytes,4,opt,name=cv,proto3" json:"cv,omitempty"`
+	Metrics              []string       `protobuf:"bytes,5,rep,name=metrics,proto3" json:"metrics,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -466,7 +1238,7 @@ func (m *EvaluationParams) Reset()         { *m = EvaluationParams{} }
 func (m *EvaluationParams) String() string { return proto.CompactTextString(m) }
 func (*EvaluationParams) ProtoMessage()    {}
 func (*EvaluationParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{3}
+	return fileDescriptor_8f954d82c0b891f6, []int{11}
 }
 
 func (m *EvaluationParams) XXX_Unmarshal(b []byte) error {
@@ -515,6 +1287,13 @@ func (m *EvaluationParams) GetCv() *CrossVal {
 	return nil
 }
 
+func (m *EvaluationParams) GetMetrics() []string {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
 // LiveEvaluationParams lists all the parameters for live model evaluation
 type LiveEvaluationParams struct {
 	Enable               bool         `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
@@ -528,7 +1307,7 @@ func (m *LiveEvaluationParams) Reset()         { *m = LiveEvaluationParams{} }
 func (m *LiveEvaluationParams) String() string { return proto.CompactTextString(m) }
 func (*LiveEvaluationParams) ProtoMessage()    {}
 func (*LiveEvaluationParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{4}
+	return fileDescriptor_8f954d82c0b891f6, []int{12}
 }
 
 func (m *LiveEvaluationParams) XXX_Unmarshal(b []byte) error {
@@ -566,6 +1345,9 @@ func (m *LiveEvaluationParams) GetRandomSplit() *RandomSplit {
 // RandomSplit defines the way to divide the dataset randomly by percentage
 type RandomSplit struct {
 	PercentLO            int32    `protobuf:"varint,1,opt,name=percentLO,proto3" json:"percentLO,omitempty"`
+	TestFraction         float64  `protobuf:"fixed64,2,opt,name=testFraction,proto3" json:"testFraction,omitempty"`
+	Strategy             string   `protobuf:"bytes,3,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	TimeColumn           string   `protobuf:"bytes,4,opt,name=timeColumn,proto3" json:"timeColumn,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -575,7 +1357,7 @@ func (m *RandomSplit) Reset()         { *m = RandomSplit{} }
 func (m *RandomSplit) String() string { return proto.CompactTextString(m) }
 func (*RandomSplit) ProtoMessage()    {}
 func (*RandomSplit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{5}
+	return fileDescriptor_8f954d82c0b891f6, []int{13}
 }
 
 func (m *RandomSplit) XXX_Unmarshal(b []byte) error {
@@ -603,6 +1385,27 @@ func (m *RandomSplit) GetPercentLO() int32 {
 	return 0
 }
 
+func (m *RandomSplit) GetTestFraction() float64 {
+	if m != nil {
+		return m.TestFraction
+	}
+	return 0
+}
+
+func (m *RandomSplit) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *RandomSplit) GetTimeColumn() string {
+	if m != nil {
+		return m.TimeColumn
+	}
+	return ""
+}
+
 // CrossVal lists all parameters required in Cross Validation
 type CrossVal struct {
 	Folds                int32    `protobuf:"varint,1,opt,name=folds,proto3" json:"folds,omitempty"`
@@ -618,7 +1421,7 @@ func (m *CrossVal) Reset()         { *m = CrossVal{} }
 func (m *CrossVal) String() string { return proto.CompactTextString(m) }
 func (*CrossVal) ProtoMessage()    {}
 func (*CrossVal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{6}
+	return fileDescriptor_8f954d82c0b891f6, []int{14}
 }
 
 func (m *CrossVal) XXX_Unmarshal(b []byte) error {
@@ -682,7 +1485,7 @@ func (m *EvaluationMetricScores) Reset()         { *m = EvaluationMetricScores{}
 func (m *EvaluationMetricScores) String() string { return proto.CompactTextString(m) }
 func (*EvaluationMetricScores) ProtoMessage()    {}
 func (*EvaluationMetricScores) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{7}
+	return fileDescriptor_8f954d82c0b891f6, []int{15}
 }
 
 func (m *EvaluationMetricScores) XXX_Unmarshal(b []byte) error {
@@ -766,7 +1569,7 @@ func (m *BinaryClassCaseMetricScores) Reset()         { *m = BinaryClassCaseMetr
 func (m *BinaryClassCaseMetricScores) String() string { return proto.CompactTextString(m) }
 func (*BinaryClassCaseMetricScores) ProtoMessage()    {}
 func (*BinaryClassCaseMetricScores) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{8}
+	return fileDescriptor_8f954d82c0b891f6, []int{16}
 }
 
 func (m *BinaryClassCaseMetricScores) XXX_Unmarshal(b []byte) error {
@@ -848,7 +1651,7 @@ func (m *BinaryClassCaseMetricScores_Point) Reset()         { *m = BinaryClassCa
 func (m *BinaryClassCaseMetricScores_Point) String() string { return proto.CompactTextString(m) }
 func (*BinaryClassCaseMetricScores_Point) ProtoMessage()    {}
 func (*BinaryClassCaseMetricScores_Point) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{8, 0}
+	return fileDescriptor_8f954d82c0b891f6, []int{16, 0}
 }
 
 func (m *BinaryClassCaseMetricScores_Point) XXX_Unmarshal(b []byte) error {
@@ -896,7 +1699,7 @@ func (m *BinaryClassCaseMetricScores_MetricsPerFold) String() string {
 }
 func (*BinaryClassCaseMetricScores_MetricsPerFold) ProtoMessage() {}
 func (*BinaryClassCaseMetricScores_MetricsPerFold) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{8, 1}
+	return fileDescriptor_8f954d82c0b891f6, []int{16, 1}
 }
 
 func (m *BinaryClassCaseMetricScores_MetricsPerFold) XXX_Unmarshal(b []byte) error {
@@ -965,6 +1768,8 @@ type RegressionCaseMetricScores struct {
 	RMSEs                map[int32]float64 `protobuf:"bytes,2,rep,name=RMSEs,proto3" json:"RMSEs,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
 	MeanRMSE             float64           `protobuf:"fixed64,3,opt,name=meanRMSE,proto3" json:"meanRMSE,omitempty"`
 	StdDevRMSE           float64           `protobuf:"fixed64,4,opt,name=stdDevRMSE,proto3" json:"stdDevRMSE,omitempty"`
+	R2Scores             map[int32]float64 `protobuf:"bytes,5,rep,name=R2Scores,proto3" json:"R2Scores,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	MeanR2               float64           `protobuf:"fixed64,6,opt,name=meanR2,proto3" json:"meanR2,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -974,7 +1779,7 @@ func (m *RegressionCaseMetricScores) Reset()         { *m = RegressionCaseMetric
 func (m *RegressionCaseMetricScores) String() string { return proto.CompactTextString(m) }
 func (*RegressionCaseMetricScores) ProtoMessage()    {}
 func (*RegressionCaseMetricScores) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{9}
+	return fileDescriptor_8f954d82c0b891f6, []int{17}
 }
 
 func (m *RegressionCaseMetricScores) XXX_Unmarshal(b []byte) error {
@@ -1023,6 +1828,20 @@ func (m *RegressionCaseMetricScores) GetStdDevRMSE() float64 {
 	return 0
 }
 
+func (m *RegressionCaseMetricScores) GetR2Scores() map[int32]float64 {
+	if m != nil {
+		return m.R2Scores
+	}
+	return nil
+}
+
+func (m *RegressionCaseMetricScores) GetMeanR2() float64 {
+	if m != nil {
+		return m.MeanR2
+	}
+	return 0
+}
+
 // TrainTaskResult defines final result of training
 type TrainTaskResult struct {
 	TaskID           string                  `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
@@ -1033,6 +1852,9 @@ type TrainTaskResult struct {
 	// trainSet is training set after Sample Alignment, and will be used in evaluation,
 	// and it will be deleted from TrainTaskResult after evaluation
 	TrainSet             []*TrainTaskResult_FileRow `protobuf:"bytes,5,rep,name=trainSet,proto3" json:"trainSet,omitempty"`
+	MetricDelta          *MetricDelta               `protobuf:"bytes,7,opt,name=metricDelta,proto3" json:"metricDelta,omitempty"`
+	PrivacyBudget        *PrivacyBudget             `protobuf:"bytes,8,opt,name=privacyBudget,proto3" json:"privacyBudget,omitempty"`
+	EarlyStop            *EarlyStopResult           `protobuf:"bytes,9,opt,name=earlyStop,proto3" json:"earlyStop,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
 	XXX_unrecognized     []byte                     `json:"-"`
 	XXX_sizecache        int32                      `json:"-"`
@@ -1042,7 +1864,7 @@ func (m *TrainTaskResult) Reset()         { *m = TrainTaskResult{} }
 func (m *TrainTaskResult) String() string { return proto.CompactTextString(m) }
 func (*TrainTaskResult) ProtoMessage()    {}
 func (*TrainTaskResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{10}
+	return fileDescriptor_8f954d82c0b891f6, []int{18}
 }
 
 func (m *TrainTaskResult) XXX_Unmarshal(b []byte) error {
@@ -1077,89 +1899,304 @@ func (m *TrainTaskResult) GetSuccess() bool {
 	return false
 }
 
-func (m *TrainTaskResult) GetModel() []byte {
-	if m != nil {
-		return m.Model
-	}
-	return nil
+func (m *TrainTaskResult) GetModel() []byte {
+	if m != nil {
+		return m.Model
+	}
+	return nil
+}
+
+func (m *TrainTaskResult) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+func (m *TrainTaskResult) GetEvalMetricScores() *EvaluationMetricScores {
+	if m != nil {
+		return m.EvalMetricScores
+	}
+	return nil
+}
+
+func (m *TrainTaskResult) GetTrainSet() []*TrainTaskResult_FileRow {
+	if m != nil {
+		return m.TrainSet
+	}
+	return nil
+}
+
+func (m *TrainTaskResult) GetMetricDelta() *MetricDelta {
+	if m != nil {
+		return m.MetricDelta
+	}
+	return nil
+}
+
+func (m *TrainTaskResult) GetPrivacyBudget() *PrivacyBudget {
+	if m != nil {
+		return m.PrivacyBudget
+	}
+	return nil
+}
+
+func (m *TrainTaskResult) GetEarlyStop() *EarlyStopResult {
+	if m != nil {
+		return m.EarlyStop
+	}
+	return nil
+}
+
+type TrainTaskResult_FileRow struct {
+	Row                  []string `protobuf:"bytes,1,rep,name=row,proto3" json:"row,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrainTaskResult_FileRow) Reset()         { *m = TrainTaskResult_FileRow{} }
+func (m *TrainTaskResult_FileRow) String() string { return proto.CompactTextString(m) }
+func (*TrainTaskResult_FileRow) ProtoMessage()    {}
+func (*TrainTaskResult_FileRow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{18, 0}
+}
+
+func (m *TrainTaskResult_FileRow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrainTaskResult_FileRow.Unmarshal(m, b)
+}
+func (m *TrainTaskResult_FileRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrainTaskResult_FileRow.Marshal(b, m, deterministic)
+}
+func (m *TrainTaskResult_FileRow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrainTaskResult_FileRow.Merge(m, src)
+}
+func (m *TrainTaskResult_FileRow) XXX_Size() int {
+	return xxx_messageInfo_TrainTaskResult_FileRow.Size(m)
+}
+func (m *TrainTaskResult_FileRow) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrainTaskResult_FileRow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrainTaskResult_FileRow proto.InternalMessageInfo
+
+func (m *TrainTaskResult_FileRow) GetRow() []string {
+	if m != nil {
+		return m.Row
+	}
+	return nil
+}
+
+// EarlyStopResult is the validation metric tracked by early stopping
+type EarlyStopResult struct {
+	Metric               string   `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	BestValue            float64  `protobuf:"fixed64,2,opt,name=bestValue,proto3" json:"bestValue,omitempty"`
+	BestRound            uint64   `protobuf:"varint,3,opt,name=bestRound,proto3" json:"bestRound,omitempty"`
+	FinalValue           float64  `protobuf:"fixed64,4,opt,name=finalValue,proto3" json:"finalValue,omitempty"`
+	Stopped              bool     `protobuf:"varint,5,opt,name=stopped,proto3" json:"stopped,omitempty"`
+	StoppedRound         uint64   `protobuf:"varint,6,opt,name=stoppedRound,proto3" json:"stoppedRound,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EarlyStopResult) Reset()         { *m = EarlyStopResult{} }
+func (m *EarlyStopResult) String() string { return proto.CompactTextString(m) }
+func (*EarlyStopResult) ProtoMessage()    {}
+func (*EarlyStopResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{19}
+}
+
+func (m *EarlyStopResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EarlyStopResult.Unmarshal(m, b)
+}
+func (m *EarlyStopResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EarlyStopResult.Marshal(b, m, deterministic)
+}
+func (m *EarlyStopResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EarlyStopResult.Merge(m, src)
+}
+func (m *EarlyStopResult) XXX_Size() int {
+	return xxx_messageInfo_EarlyStopResult.Size(m)
+}
+func (m *EarlyStopResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_EarlyStopResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EarlyStopResult proto.InternalMessageInfo
+
+func (m *EarlyStopResult) GetMetric() string {
+	if m != nil {
+		return m.Metric
+	}
+	return ""
+}
+
+func (m *EarlyStopResult) GetBestValue() float64 {
+	if m != nil {
+		return m.BestValue
+	}
+	return 0
+}
+
+func (m *EarlyStopResult) GetBestRound() uint64 {
+	if m != nil {
+		return m.BestRound
+	}
+	return 0
+}
+
+func (m *EarlyStopResult) GetFinalValue() float64 {
+	if m != nil {
+		return m.FinalValue
+	}
+	return 0
+}
+
+func (m *EarlyStopResult) GetStopped() bool {
+	if m != nil {
+		return m.Stopped
+	}
+	return false
+}
+
+func (m *EarlyStopResult) GetStoppedRound() uint64 {
+	if m != nil {
+		return m.StoppedRound
+	}
+	return 0
+}
+
+// PrivacyBudget is the differential privacy budget consumed by a training task
+type PrivacyBudget struct {
+	Epsilon              float64  `protobuf:"fixed64,1,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Delta                float64  `protobuf:"fixed64,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	Rounds               int64    `protobuf:"varint,3,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrivacyBudget) Reset()         { *m = PrivacyBudget{} }
+func (m *PrivacyBudget) String() string { return proto.CompactTextString(m) }
+func (*PrivacyBudget) ProtoMessage()    {}
+func (*PrivacyBudget) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{20}
 }
 
-func (m *TrainTaskResult) GetErrMsg() string {
+func (m *PrivacyBudget) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivacyBudget.Unmarshal(m, b)
+}
+func (m *PrivacyBudget) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivacyBudget.Marshal(b, m, deterministic)
+}
+func (m *PrivacyBudget) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivacyBudget.Merge(m, src)
+}
+func (m *PrivacyBudget) XXX_Size() int {
+	return xxx_messageInfo_PrivacyBudget.Size(m)
+}
+func (m *PrivacyBudget) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivacyBudget.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivacyBudget proto.InternalMessageInfo
+
+func (m *PrivacyBudget) GetEpsilon() float64 {
 	if m != nil {
-		return m.ErrMsg
+		return m.Epsilon
 	}
-	return ""
+	return 0
 }
 
-func (m *TrainTaskResult) GetEvalMetricScores() *EvaluationMetricScores {
+func (m *PrivacyBudget) GetDelta() float64 {
 	if m != nil {
-		return m.EvalMetricScores
+		return m.Delta
 	}
-	return nil
+	return 0
 }
 
-func (m *TrainTaskResult) GetTrainSet() []*TrainTaskResult_FileRow {
+func (m *PrivacyBudget) GetRounds() int64 {
 	if m != nil {
-		return m.TrainSet
+		return m.Rounds
 	}
-	return nil
+	return 0
 }
 
-type TrainTaskResult_FileRow struct {
-	Row                  []string `protobuf:"bytes,1,rep,name=row,proto3" json:"row,omitempty"`
+// MetricDelta compares the metric of models on the same samples,
+// the metric is "cost" of the training, and the lower the better
+type MetricDelta struct {
+	Metric               string   `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Base                 float64  `protobuf:"fixed64,2,opt,name=base,proto3" json:"base,omitempty"`
+	Updated              float64  `protobuf:"fixed64,3,opt,name=updated,proto3" json:"updated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TrainTaskResult_FileRow) Reset()         { *m = TrainTaskResult_FileRow{} }
-func (m *TrainTaskResult_FileRow) String() string { return proto.CompactTextString(m) }
-func (*TrainTaskResult_FileRow) ProtoMessage()    {}
-func (*TrainTaskResult_FileRow) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{10, 0}
+func (m *MetricDelta) Reset()         { *m = MetricDelta{} }
+func (m *MetricDelta) String() string { return proto.CompactTextString(m) }
+func (*MetricDelta) ProtoMessage()    {}
+func (*MetricDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{21}
 }
 
-func (m *TrainTaskResult_FileRow) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TrainTaskResult_FileRow.Unmarshal(m, b)
+func (m *MetricDelta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetricDelta.Unmarshal(m, b)
 }
-func (m *TrainTaskResult_FileRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TrainTaskResult_FileRow.Marshal(b, m, deterministic)
+func (m *MetricDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetricDelta.Marshal(b, m, deterministic)
 }
-func (m *TrainTaskResult_FileRow) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TrainTaskResult_FileRow.Merge(m, src)
+func (m *MetricDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricDelta.Merge(m, src)
 }
-func (m *TrainTaskResult_FileRow) XXX_Size() int {
-	return xxx_messageInfo_TrainTaskResult_FileRow.Size(m)
+func (m *MetricDelta) XXX_Size() int {
+	return xxx_messageInfo_MetricDelta.Size(m)
 }
-func (m *TrainTaskResult_FileRow) XXX_DiscardUnknown() {
-	xxx_messageInfo_TrainTaskResult_FileRow.DiscardUnknown(m)
+func (m *MetricDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricDelta.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TrainTaskResult_FileRow proto.InternalMessageInfo
+var xxx_messageInfo_MetricDelta proto.InternalMessageInfo
 
-func (m *TrainTaskResult_FileRow) GetRow() []string {
+func (m *MetricDelta) GetMetric() string {
 	if m != nil {
-		return m.Row
+		return m.Metric
 	}
-	return nil
+	return ""
+}
+
+func (m *MetricDelta) GetBase() float64 {
+	if m != nil {
+		return m.Base
+	}
+	return 0
+}
+
+func (m *MetricDelta) GetUpdated() float64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
 }
 
 // PredictTaskResult defines final result of prediction
 type PredictTaskResult struct {
-	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Outcomes             []byte   `protobuf:"bytes,3,opt,name=outcomes,proto3" json:"outcomes,omitempty"`
-	ErrMsg               string   `protobuf:"bytes,4,opt,name=errMsg,proto3" json:"errMsg,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	TaskID               string       `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Success              bool         `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Outcomes             []byte       `protobuf:"bytes,3,opt,name=outcomes,proto3" json:"outcomes,omitempty"`
+	ErrMsg               string       `protobuf:"bytes,4,opt,name=errMsg,proto3" json:"errMsg,omitempty"`
+	Drift                *DriftReport `protobuf:"bytes,5,opt,name=drift,proto3" json:"drift,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *PredictTaskResult) Reset()         { *m = PredictTaskResult{} }
 func (m *PredictTaskResult) String() string { return proto.CompactTextString(m) }
 func (*PredictTaskResult) ProtoMessage()    {}
 func (*PredictTaskResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{11}
+	return fileDescriptor_8f954d82c0b891f6, []int{22}
 }
 
 func (m *PredictTaskResult) XXX_Unmarshal(b []byte) error {
@@ -1208,6 +2245,13 @@ func (m *PredictTaskResult) GetErrMsg() string {
 	return ""
 }
 
+func (m *PredictTaskResult) GetDrift() *DriftReport {
+	if m != nil {
+		return m.Drift
+	}
+	return nil
+}
+
 // StartTaskRequest is message sent to a cluster member to start a training task or predicting task.
 type StartTaskRequest struct {
 	TaskID               string          `protobuf:"bytes,2,opt,name=taskID,proto3" json:"taskID,omitempty"`
@@ -1224,7 +2268,7 @@ func (m *StartTaskRequest) Reset()         { *m = StartTaskRequest{} }
 func (m *StartTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*StartTaskRequest) ProtoMessage()    {}
 func (*StartTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{12}
+	return fileDescriptor_8f954d82c0b891f6, []int{23}
 }
 
 func (m *StartTaskRequest) XXX_Unmarshal(b []byte) error {
@@ -1293,7 +2337,7 @@ func (m *PaddleFLParams) Reset()         { *m = PaddleFLParams{} }
 func (m *PaddleFLParams) String() string { return proto.CompactTextString(m) }
 func (*PaddleFLParams) ProtoMessage()    {}
 func (*PaddleFLParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{13}
+	return fileDescriptor_8f954d82c0b891f6, []int{24}
 }
 
 func (m *PaddleFLParams) XXX_Unmarshal(b []byte) error {
@@ -1341,7 +2385,7 @@ func (m *StopTaskRequest) Reset()         { *m = StopTaskRequest{} }
 func (m *StopTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*StopTaskRequest) ProtoMessage()    {}
 func (*StopTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f954d82c0b891f6, []int{14}
+	return fileDescriptor_8f954d82c0b891f6, []int{25}
 }
 
 func (m *StopTaskRequest) XXX_Unmarshal(b []byte) error {
@@ -1376,6 +2420,355 @@ func (m *StopTaskRequest) GetParams() *TaskParams {
 	return nil
 }
 
+// OptimizerParams defines the optimizer and the learning rate schedule of training, the learning rate starts from alpha,
+// and a round is an epoch for dnn. All parties apply the same parameters to update their parts of the model.
+type OptimizerParams struct {
+	Optimizer            string   `protobuf:"bytes,1,opt,name=optimizer,proto3" json:"optimizer,omitempty"`
+	Beta1                float64  `protobuf:"fixed64,2,opt,name=beta1,proto3" json:"beta1,omitempty"`
+	Beta2                float64  `protobuf:"fixed64,3,opt,name=beta2,proto3" json:"beta2,omitempty"`
+	Epsilon              float64  `protobuf:"fixed64,4,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Rho                  float64  `protobuf:"fixed64,5,opt,name=rho,proto3" json:"rho,omitempty"`
+	Schedule             string   `protobuf:"bytes,6,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	StepSize             int64    `protobuf:"varint,7,opt,name=stepSize,proto3" json:"stepSize,omitempty"`
+	Gamma                float64  `protobuf:"fixed64,8,opt,name=gamma,proto3" json:"gamma,omitempty"`
+	TotalRounds          int64    `protobuf:"varint,9,opt,name=totalRounds,proto3" json:"totalRounds,omitempty"`
+	MinAlpha             float64  `protobuf:"fixed64,10,opt,name=minAlpha,proto3" json:"minAlpha,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OptimizerParams) Reset()         { *m = OptimizerParams{} }
+func (m *OptimizerParams) String() string { return proto.CompactTextString(m) }
+func (*OptimizerParams) ProtoMessage()    {}
+func (*OptimizerParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{26}
+}
+
+func (m *OptimizerParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OptimizerParams.Unmarshal(m, b)
+}
+func (m *OptimizerParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OptimizerParams.Marshal(b, m, deterministic)
+}
+func (m *OptimizerParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OptimizerParams.Merge(m, src)
+}
+func (m *OptimizerParams) XXX_Size() int {
+	return xxx_messageInfo_OptimizerParams.Size(m)
+}
+func (m *OptimizerParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_OptimizerParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OptimizerParams proto.InternalMessageInfo
+
+func (m *OptimizerParams) GetOptimizer() string {
+	if m != nil {
+		return m.Optimizer
+	}
+	return ""
+}
+
+func (m *OptimizerParams) GetBeta1() float64 {
+	if m != nil {
+		return m.Beta1
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetBeta2() float64 {
+	if m != nil {
+		return m.Beta2
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetEpsilon() float64 {
+	if m != nil {
+		return m.Epsilon
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetRho() float64 {
+	if m != nil {
+		return m.Rho
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetSchedule() string {
+	if m != nil {
+		return m.Schedule
+	}
+	return ""
+}
+
+func (m *OptimizerParams) GetStepSize() int64 {
+	if m != nil {
+		return m.StepSize
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetGamma() float64 {
+	if m != nil {
+		return m.Gamma
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetTotalRounds() int64 {
+	if m != nil {
+		return m.TotalRounds
+	}
+	return 0
+}
+
+func (m *OptimizerParams) GetMinAlpha() float64 {
+	if m != nil {
+		return m.MinAlpha
+	}
+	return 0
+}
+
+// FeatureSummary summarizes the distribution of the values of a column
+type FeatureSummary struct {
+	Mean                 float64  `protobuf:"fixed64,1,opt,name=mean,proto3" json:"mean,omitempty"`
+	Std                  float64  `protobuf:"fixed64,2,opt,name=std,proto3" json:"std,omitempty"`
+	Min                  float64  `protobuf:"fixed64,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  float64  `protobuf:"fixed64,4,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeatureSummary) Reset()         { *m = FeatureSummary{} }
+func (m *FeatureSummary) String() string { return proto.CompactTextString(m) }
+func (*FeatureSummary) ProtoMessage()    {}
+func (*FeatureSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{27}
+}
+
+func (m *FeatureSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureSummary.Unmarshal(m, b)
+}
+func (m *FeatureSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureSummary.Marshal(b, m, deterministic)
+}
+func (m *FeatureSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureSummary.Merge(m, src)
+}
+func (m *FeatureSummary) XXX_Size() int {
+	return xxx_messageInfo_FeatureSummary.Size(m)
+}
+func (m *FeatureSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureSummary proto.InternalMessageInfo
+
+func (m *FeatureSummary) GetMean() float64 {
+	if m != nil {
+		return m.Mean
+	}
+	return 0
+}
+
+func (m *FeatureSummary) GetStd() float64 {
+	if m != nil {
+		return m.Std
+	}
+	return 0
+}
+
+func (m *FeatureSummary) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *FeatureSummary) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+// DriftReport compares the distributions of the local columns of the samples predicted with the training ones,
+// the score of a column is the shift of its mean from the training mean in training standard deviations,
+// the column of the label on the party with label is the predictions
+type DriftReport struct {
+	Drifted              bool                       `protobuf:"varint,1,opt,name=drifted,proto3" json:"drifted,omitempty"`
+	Threshold            float64                    `protobuf:"fixed64,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Scores               map[string]float64         `protobuf:"bytes,3,rep,name=scores,proto3" json:"scores,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	Summaries            map[string]*FeatureSummary `protobuf:"bytes,4,rep,name=summaries,proto3" json:"summaries,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *DriftReport) Reset()         { *m = DriftReport{} }
+func (m *DriftReport) String() string { return proto.CompactTextString(m) }
+func (*DriftReport) ProtoMessage()    {}
+func (*DriftReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{28}
+}
+
+func (m *DriftReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriftReport.Unmarshal(m, b)
+}
+func (m *DriftReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriftReport.Marshal(b, m, deterministic)
+}
+func (m *DriftReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriftReport.Merge(m, src)
+}
+func (m *DriftReport) XXX_Size() int {
+	return xxx_messageInfo_DriftReport.Size(m)
+}
+func (m *DriftReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriftReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriftReport proto.InternalMessageInfo
+
+func (m *DriftReport) GetDrifted() bool {
+	if m != nil {
+		return m.Drifted
+	}
+	return false
+}
+
+func (m *DriftReport) GetThreshold() float64 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *DriftReport) GetScores() map[string]float64 {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
+func (m *DriftReport) GetSummaries() map[string]*FeatureSummary {
+	if m != nil {
+		return m.Summaries
+	}
+	return nil
+}
+
+// LossParams selects the loss function of linear regression, the residual is the prediction minus the label
+type LossParams struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Delta                float64  `protobuf:"fixed64,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	Tau                  float64  `protobuf:"fixed64,3,opt,name=tau,proto3" json:"tau,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LossParams) Reset()         { *m = LossParams{} }
+func (m *LossParams) String() string { return proto.CompactTextString(m) }
+func (*LossParams) ProtoMessage()    {}
+func (*LossParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{29}
+}
+
+func (m *LossParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LossParams.Unmarshal(m, b)
+}
+func (m *LossParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LossParams.Marshal(b, m, deterministic)
+}
+func (m *LossParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LossParams.Merge(m, src)
+}
+func (m *LossParams) XXX_Size() int {
+	return xxx_messageInfo_LossParams.Size(m)
+}
+func (m *LossParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_LossParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LossParams proto.InternalMessageInfo
+
+func (m *LossParams) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LossParams) GetDelta() float64 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+func (m *LossParams) GetTau() float64 {
+	if m != nil {
+		return m.Tau
+	}
+	return 0
+}
+
+// DryRunParams defines the dry run before training, which trains a few iterations on a small random subset
+// of the aligned samples and fails the task early if the cost becomes NaN or Inf or diverges. Each party
+// selects the subset by the hashes of the sample IDs, so the parties keep the same samples
+type DryRunParams struct {
+	Fraction             float64  `protobuf:"fixed64,1,opt,name=fraction,proto3" json:"fraction,omitempty"`
+	Iterations           int64    `protobuf:"varint,2,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DryRunParams) Reset()         { *m = DryRunParams{} }
+func (m *DryRunParams) String() string { return proto.CompactTextString(m) }
+func (*DryRunParams) ProtoMessage()    {}
+func (*DryRunParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f954d82c0b891f6, []int{30}
+}
+
+func (m *DryRunParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DryRunParams.Unmarshal(m, b)
+}
+func (m *DryRunParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DryRunParams.Marshal(b, m, deterministic)
+}
+func (m *DryRunParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DryRunParams.Merge(m, src)
+}
+func (m *DryRunParams) XXX_Size() int {
+	return xxx_messageInfo_DryRunParams.Size(m)
+}
+func (m *DryRunParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_DryRunParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DryRunParams proto.InternalMessageInfo
+
+func (m *DryRunParams) GetFraction() float64 {
+	if m != nil {
+		return m.Fraction
+	}
+	return 0
+}
+
+func (m *DryRunParams) GetIterations() int64 {
+	if m != nil {
+		return m.Iterations
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("common.Algorithm", Algorithm_name, Algorithm_value)
 	proto.RegisterEnum("common.TaskType", TaskType_name, TaskType_value)
@@ -1383,11 +2776,21 @@ func init() {
 	proto.RegisterEnum("common.EvaluationRule", EvaluationRule_name, EvaluationRule_value)
 	proto.RegisterEnum("common.CaseType", CaseType_name, CaseType_value)
 	proto.RegisterType((*TrainParams)(nil), "common.TrainParams")
+	proto.RegisterType((*EarlyStoppingParams)(nil), "common.EarlyStoppingParams")
+	proto.RegisterType((*CategoricalParams)(nil), "common.CategoricalParams")
+	proto.RegisterType((*DPParams)(nil), "common.DPParams")
+	proto.RegisterType((*XGBoostParams)(nil), "common.XGBoostParams")
 	proto.RegisterType((*TrainModels)(nil), "common.TrainModels")
 	proto.RegisterMapType((map[string]float64)(nil), "common.TrainModels.SigmasEntry")
+	proto.RegisterMapType((map[string]*FeatureSummary)(nil), "common.TrainModels.SummariesEntry")
 	proto.RegisterMapType((map[string]float64)(nil), "common.TrainModels.ThetasEntry")
 	proto.RegisterMapType((map[string]float64)(nil), "common.TrainModels.XbarsEntry")
+	proto.RegisterType((*CategoryMapping)(nil), "common.CategoryMapping")
+	proto.RegisterType((*XGBoostModel)(nil), "common.XGBoostModel")
+	proto.RegisterType((*XGBoostTree)(nil), "common.XGBoostTree")
+	proto.RegisterType((*XGBoostNode)(nil), "common.XGBoostNode")
 	proto.RegisterType((*TaskParams)(nil), "common.TaskParams")
+	proto.RegisterMapType((map[string]string)(nil), "common.TaskParams.LabelsEntry")
 	proto.RegisterType((*EvaluationParams)(nil), "common.EvaluationParams")
 	proto.RegisterType((*LiveEvaluationParams)(nil), "common.LiveEvaluationParams")
 	proto.RegisterType((*RandomSplit)(nil), "common.RandomSplit")
@@ -1398,111 +2801,210 @@ func init() {
 	proto.RegisterType((*BinaryClassCaseMetricScores_Point)(nil), "common.BinaryClassCaseMetricScores.Point")
 	proto.RegisterType((*BinaryClassCaseMetricScores_MetricsPerFold)(nil), "common.BinaryClassCaseMetricScores.MetricsPerFold")
 	proto.RegisterType((*RegressionCaseMetricScores)(nil), "common.RegressionCaseMetricScores")
+	proto.RegisterMapType((map[int32]float64)(nil), "common.RegressionCaseMetricScores.R2ScoresEntry")
 	proto.RegisterMapType((map[int32]float64)(nil), "common.RegressionCaseMetricScores.RMSEsEntry")
 	proto.RegisterType((*TrainTaskResult)(nil), "common.TrainTaskResult")
 	proto.RegisterType((*TrainTaskResult_FileRow)(nil), "common.TrainTaskResult.FileRow")
+	proto.RegisterType((*EarlyStopResult)(nil), "common.EarlyStopResult")
+	proto.RegisterType((*PrivacyBudget)(nil), "common.PrivacyBudget")
+	proto.RegisterType((*MetricDelta)(nil), "common.MetricDelta")
 	proto.RegisterType((*PredictTaskResult)(nil), "common.PredictTaskResult")
 	proto.RegisterType((*StartTaskRequest)(nil), "common.StartTaskRequest")
 	proto.RegisterType((*PaddleFLParams)(nil), "common.PaddleFLParams")
 	proto.RegisterType((*StopTaskRequest)(nil), "common.StopTaskRequest")
+	proto.RegisterType((*OptimizerParams)(nil), "common.OptimizerParams")
+	proto.RegisterType((*FeatureSummary)(nil), "common.FeatureSummary")
+	proto.RegisterType((*DriftReport)(nil), "common.DriftReport")
+	proto.RegisterMapType((map[string]float64)(nil), "common.DriftReport.ScoresEntry")
+	proto.RegisterMapType((map[string]*FeatureSummary)(nil), "common.DriftReport.SummariesEntry")
+	proto.RegisterType((*LossParams)(nil), "common.LossParams")
+	proto.RegisterType((*DryRunParams)(nil), "common.DryRunParams")
 }
 
 //func init() { proto.RegisterFile("common/common.proto", fileDescriptor_8f954d82c0b891f6) }
 
 var fileDescriptor_8f954d82c0b891f6 = []byte{
-	// 1498 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xcd, 0x6e, 0x1b, 0x47,
-	0x12, 0xd6, 0x50, 0xe2, 0x5f, 0x51, 0xa6, 0xc6, 0x2d, 0xaf, 0x77, 0x40, 0x1b, 0x5e, 0x62, 0x16,
-	0x0b, 0xc8, 0xf2, 0x46, 0x42, 0xe8, 0x18, 0xfe, 0x03, 0x0c, 0xc8, 0x14, 0x65, 0x2b, 0xa0, 0x24,
-	0xa2, 0x49, 0x1b, 0x46, 0x2e, 0x42, 0x6b, 0xa6, 0x35, 0x1c, 0x78, 0xc8, 0x61, 0xa6, 0x87, 0xb4,
-	0x95, 0x7b, 0x9e, 0x21, 0x2f, 0x90, 0x63, 0xee, 0xb9, 0xe6, 0x92, 0x53, 0x1e, 0x25, 0xb7, 0x3c,
-	0x41, 0x50, 0xdd, 0x3d, 0x3f, 0x94, 0x44, 0x59, 0x42, 0x2e, 0x52, 0x7f, 0xd5, 0x55, 0x5d, 0x55,
-	0x5f, 0xf5, 0x74, 0x15, 0x61, 0xdd, 0x09, 0x47, 0xa3, 0x70, 0xbc, 0xad, 0xfe, 0x6d, 0x4d, 0xa2,
-	0x30, 0x0e, 0x49, 0x49, 0x21, 0xfb, 0x97, 0x02, 0xd4, 0x06, 0x11, 0xf3, 0xc7, 0x3d, 0x16, 0xb1,
-	0x91, 0x20, 0x77, 0xa0, 0x18, 0xb0, 0x13, 0x1e, 0x58, 0x46, 0xd3, 0xd8, 0xa8, 0x52, 0x05, 0xc8,
-	0x7d, 0xa8, 0xca, 0xc5, 0x21, 0x1b, 0x71, 0xab, 0x20, 0x77, 0x32, 0x01, 0x79, 0x08, 0xe5, 0x88,
-	0x7b, 0x07, 0xa1, 0xcb, 0xad, 0xe5, 0xa6, 0xb1, 0x51, 0x6f, 0xad, 0x6d, 0x69, 0x5f, 0x54, 0x89,
-	0x69, 0xb2, 0x4f, 0x1a, 0x50, 0x89, 0xb8, 0x27, 0x7d, 0x59, 0x2b, 0x4d, 0x63, 0xc3, 0xa0, 0x29,
-	0x46, 0xd7, 0x2c, 0x98, 0x0c, 0x99, 0x55, 0x94, 0x1b, 0x0a, 0xa0, 0x6b, 0x36, 0x9a, 0x04, 0x7e,
-	0x3c, 0x75, 0xb9, 0x55, 0x92, 0x3b, 0x99, 0x00, 0xcf, 0x63, 0x8e, 0x33, 0x8d, 0x98, 0x73, 0x66,
-	0x95, 0x9b, 0xc6, 0xc6, 0x32, 0x4d, 0x31, 0x5a, 0xfa, 0x62, 0xc0, 0xf0, 0xf4, 0xd8, 0xaa, 0x34,
-	0x8d, 0x8d, 0x0a, 0xcd, 0x04, 0xe4, 0x2e, 0x94, 0x7c, 0x57, 0xe6, 0x53, 0x95, 0xf9, 0x68, 0x84,
-	0x56, 0x27, 0x2c, 0x76, 0x86, 0x7d, 0xff, 0x07, 0x6e, 0x81, 0x3c, 0x32, 0x13, 0xd8, 0xbf, 0x2f,
-	0x6b, 0xba, 0x30, 0x9b, 0x40, 0x90, 0xa7, 0x50, 0x8a, 0x87, 0x3c, 0x66, 0xc2, 0x32, 0x9a, 0xcb,
-	0x1b, 0xb5, 0xd6, 0x7f, 0x92, 0xcc, 0x73, 0x4a, 0x5b, 0x03, 0xa9, 0xd1, 0x19, 0xc7, 0xd1, 0x19,
-	0xd5, 0xea, 0xe4, 0x1b, 0x28, 0x7e, 0x3e, 0x61, 0x91, 0xb0, 0x0a, 0xd2, 0xee, 0xc1, 0x65, 0x76,
-	0x1f, 0x50, 0x41, 0x99, 0x29, 0x65, 0x74, 0x27, 0x7c, 0x6f, 0xc4, 0x84, 0xb5, 0xbc, 0xd8, 0x5d,
-	0x5f, 0x6a, 0x68, 0x77, 0x4a, 0x3d, 0x2b, 0xeb, 0xca, 0xb9, 0xb2, 0x66, 0x0c, 0x15, 0x17, 0x33,
-	0x54, 0x9a, 0x63, 0x88, 0xc0, 0xca, 0x84, 0xc5, 0x43, 0xc9, 0x77, 0x95, 0xca, 0x75, 0xe3, 0x39,
-	0xd4, 0x72, 0x59, 0x12, 0x13, 0x96, 0x3f, 0xf2, 0x33, 0x7d, 0x87, 0x70, 0x89, 0x01, 0xcc, 0x58,
-	0x30, 0x55, 0xb7, 0xc7, 0xa0, 0x0a, 0xbc, 0x28, 0x3c, 0x33, 0x1a, 0xcf, 0x00, 0xb2, 0x44, 0x6f,
-	0x64, 0xf9, 0x1c, 0x6a, 0xb9, 0x5c, 0x6f, 0x62, 0x6a, 0xff, 0x59, 0x00, 0x18, 0x30, 0xf1, 0x51,
-	0xdf, 0xfa, 0xff, 0xc1, 0x0a, 0x0b, 0xbc, 0x50, 0xda, 0xd6, 0x5b, 0xb7, 0x13, 0x56, 0x77, 0x02,
-	0x2f, 0x8c, 0xfc, 0x78, 0x38, 0xa2, 0x72, 0x9b, 0xfc, 0x1f, 0x2a, 0x31, 0x13, 0x1f, 0x07, 0x67,
-	0x13, 0x75, 0x64, 0xbd, 0x65, 0xa6, 0x05, 0xd0, 0x72, 0x9a, 0x6a, 0x90, 0x27, 0x50, 0x8b, 0xb3,
-	0x2f, 0x4b, 0x7e, 0x1a, 0xb5, 0xd6, 0xfa, 0x5c, 0xc5, 0xd4, 0x16, 0xcd, 0xeb, 0x91, 0x26, 0xd4,
-	0x46, 0x58, 0x48, 0x3c, 0x71, 0x7f, 0x57, 0x17, 0x2c, 0x2f, 0xc2, 0x83, 0x25, 0xd4, 0x07, 0x17,
-	0x2f, 0x39, 0x58, 0x5d, 0x05, 0x9a, 0xd7, 0x23, 0xcf, 0x00, 0xf8, 0x8c, 0x25, 0x56, 0x25, 0x69,
-	0x65, 0x25, 0x56, 0x1d, 0xe4, 0x86, 0xc5, 0x7e, 0x98, 0xc4, 0x94, 0xd3, 0x25, 0xaf, 0xa0, 0x16,
-	0xf8, 0x99, 0x69, 0x59, 0x9a, 0xde, 0x4f, 0x4c, 0xbb, 0xfe, 0x8c, 0x5f, 0x30, 0xcf, 0x1b, 0xd8,
-	0xbf, 0x1a, 0x60, 0x9e, 0xd7, 0xc0, 0xeb, 0xc5, 0xc7, 0xec, 0x24, 0xe0, 0x92, 0xf5, 0x0a, 0xd5,
-	0x88, 0xb4, 0xa0, 0x82, 0xae, 0xe9, 0x34, 0x48, 0x48, 0xbe, 0x7b, 0x31, 0x48, 0xdc, 0xa5, 0xa9,
-	0x1e, 0x32, 0x12, 0xb1, 0xb1, 0x1b, 0x8e, 0xfa, 0xf8, 0x30, 0x9c, 0xa7, 0x9a, 0x66, 0x5b, 0x34,
-	0xaf, 0x47, 0x9a, 0x50, 0x70, 0x66, 0x92, 0xe1, 0x5a, 0x56, 0xc9, 0x76, 0x14, 0x0a, 0xf1, 0x9e,
-	0x05, 0xb4, 0xe0, 0xcc, 0x6c, 0x0e, 0x77, 0x2e, 0x4b, 0x6f, 0x61, 0xf0, 0xe7, 0x02, 0x29, 0x5c,
-	0x2f, 0x10, 0xfb, 0x11, 0xd4, 0x72, 0x7b, 0xf8, 0x5d, 0x4e, 0x78, 0xe4, 0xf0, 0x71, 0xdc, 0x3d,
-	0x92, 0x0e, 0x8a, 0x34, 0x13, 0xd8, 0x9f, 0xa1, 0x92, 0xc4, 0x88, 0x37, 0xfc, 0x34, 0x0c, 0x5c,
-	0xa1, 0xb5, 0x14, 0x20, 0x16, 0x94, 0xc5, 0x70, 0x7a, 0x7a, 0xaa, 0x19, 0xac, 0xd0, 0x04, 0xaa,
-	0xf7, 0x77, 0xc2, 0x59, 0xcc, 0x5d, 0xc9, 0x52, 0x85, 0xa6, 0x18, 0x2f, 0x9e, 0x5a, 0x0f, 0xfc,
-	0x11, 0x17, 0x92, 0x96, 0x22, 0xcd, 0x8b, 0xec, 0xbf, 0x0c, 0xb8, 0x9b, 0x51, 0x71, 0xc0, 0xe3,
-	0xc8, 0x77, 0xfa, 0x4e, 0x18, 0x71, 0x41, 0x3c, 0xb8, 0x77, 0xe2, 0x8f, 0x59, 0x74, 0xd6, 0x0e,
-	0x98, 0x10, 0x6d, 0x26, 0x78, 0x7e, 0x5b, 0x86, 0x57, 0x6b, 0xfd, 0x37, 0x21, 0xe2, 0xf5, 0x62,
-	0xd5, 0xb7, 0x4b, 0xf4, 0xaa, 0x93, 0x88, 0x0b, 0x0d, 0xca, 0xbd, 0x88, 0x0b, 0xe1, 0x87, 0xe3,
-	0x0b, 0x7e, 0x14, 0xe1, 0x76, 0xae, 0xff, 0x2c, 0xd0, 0x7c, 0xbb, 0x44, 0xaf, 0x38, 0xe7, 0x75,
-	0x15, 0xca, 0x13, 0x76, 0x16, 0x84, 0xcc, 0xb5, 0x7f, 0x2e, 0xc2, 0xbd, 0x2b, 0xe2, 0xc5, 0x47,
-	0xc1, 0x61, 0x82, 0xcb, 0x47, 0xc1, 0x98, 0x7f, 0x14, 0xda, 0x5a, 0x4e, 0x53, 0x0d, 0x24, 0x99,
-	0xcd, 0xbc, 0x9d, 0xa4, 0x67, 0xa9, 0x87, 0x29, 0x2f, 0x22, 0x36, 0xac, 0xb2, 0x99, 0xd7, 0x8b,
-	0xb8, 0xe3, 0x63, 0x68, 0xb2, 0x4c, 0x06, 0x9d, 0x93, 0xc9, 0xa6, 0x38, 0xf3, 0x28, 0x77, 0x58,
-	0x10, 0xe8, 0x3e, 0x9a, 0x09, 0xc8, 0x03, 0x00, 0x36, 0xf3, 0xf6, 0xbe, 0x96, 0x01, 0xea, 0x6e,
-	0x9a, 0x93, 0xe0, 0xe5, 0x45, 0x87, 0xef, 0xda, 0xba, 0x9f, 0x6a, 0x44, 0x8e, 0xa1, 0x3e, 0x92,
-	0x99, 0x89, 0x1e, 0x8f, 0xf6, 0xc2, 0xc0, 0xb5, 0xca, 0xb2, 0xcb, 0x3c, 0xbd, 0x46, 0xd9, 0xb6,
-	0x0e, 0xe6, 0x2c, 0x55, 0xf7, 0x39, 0x77, 0x5c, 0xe3, 0x5f, 0x50, 0xec, 0x85, 0xfe, 0x38, 0x26,
-	0xab, 0x60, 0x4c, 0x64, 0xc7, 0x34, 0xa8, 0x31, 0x69, 0xfc, 0x61, 0x40, 0x7d, 0xde, 0x7c, 0xae,
-	0xaf, 0x1b, 0x6a, 0x4e, 0xc8, 0xf7, 0xf5, 0x49, 0xca, 0x8e, 0x22, 0x30, 0x13, 0x60, 0x72, 0x91,
-	0xe2, 0x45, 0x11, 0xa7, 0x11, 0x7e, 0x13, 0x09, 0x23, 0x8a, 0xb0, 0x04, 0x62, 0xdf, 0x40, 0x2e,
-	0x14, 0x4f, 0xb8, 0x24, 0x2f, 0x61, 0x99, 0x1e, 0x21, 0x3b, 0x98, 0xfd, 0xc3, 0xeb, 0x64, 0x2f,
-	0xd3, 0xa2, 0x68, 0xd5, 0x98, 0xc2, 0xfa, 0x25, 0x5c, 0xe4, 0xbb, 0x53, 0x51, 0x75, 0xa7, 0xb7,
-	0xf9, 0xee, 0x54, 0x6b, 0xb5, 0x6e, 0xce, 0x72, 0xbe, 0xa3, 0xfd, 0x58, 0xb8, 0xea, 0xc3, 0xb8,
-	0xe1, 0x2d, 0x6d, 0x43, 0x91, 0x1e, 0xf4, 0x3b, 0xc9, 0x74, 0xf2, 0xd5, 0x97, 0xbf, 0xa7, 0x2d,
-	0xa9, 0xaf, 0x87, 0x15, 0xb9, 0xc6, 0x1a, 0x8e, 0x38, 0x1b, 0x23, 0xd0, 0xb5, 0x48, 0x31, 0x5e,
-	0x51, 0x11, 0xbb, 0xbb, 0x7c, 0x26, 0x77, 0x55, 0x41, 0x72, 0x12, 0x1c, 0x0a, 0xb2, 0x03, 0x2f,
-	0xe1, 0x6e, 0x71, 0x67, 0xff, 0xa9, 0x00, 0x6b, 0xb2, 0x05, 0x62, 0xb3, 0xa4, 0x5c, 0x4c, 0x03,
-	0x39, 0xc9, 0xc4, 0xaa, 0x9b, 0xaa, 0xe1, 0x40, 0x23, 0xf9, 0x4e, 0x4e, 0x1d, 0x87, 0x0b, 0x91,
-	0xbe, 0x93, 0x0a, 0xe2, 0xf9, 0xb2, 0x75, 0xca, 0xc0, 0x57, 0xa9, 0x02, 0xf2, 0xd5, 0x8f, 0xa2,
-	0x03, 0xe1, 0xe9, 0xae, 0xac, 0x11, 0xf9, 0x16, 0x4c, 0x6c, 0x45, 0x73, 0x2f, 0x91, 0xea, 0xaf,
-	0x0f, 0x2e, 0xb6, 0xae, 0xbc, 0x16, 0xbd, 0x60, 0x47, 0x5e, 0x42, 0x45, 0x4e, 0x03, 0x7d, 0x8e,
-	0x23, 0xd9, 0xc5, 0x21, 0x2f, 0x4b, 0x6b, 0x6b, 0xcf, 0x0f, 0x38, 0x0d, 0x3f, 0xd1, 0xd4, 0xa0,
-	0x71, 0x0f, 0xca, 0x5a, 0x88, 0x9c, 0x45, 0xe1, 0x27, 0xf9, 0x91, 0x55, 0x29, 0x2e, 0xed, 0x33,
-	0xb8, 0xdd, 0x8b, 0xb8, 0xeb, 0x3b, 0xf1, 0x3f, 0xa2, 0xa6, 0x01, 0x95, 0x70, 0x1a, 0x3b, 0x21,
-	0xf6, 0x08, 0xc5, 0x4e, 0x8a, 0x17, 0x11, 0x64, 0xff, 0x66, 0x80, 0xd9, 0x8f, 0x59, 0xa4, 0x3d,
-	0x7f, 0x3f, 0xe5, 0x22, 0xef, 0xba, 0x30, 0xe7, 0x9a, 0xc0, 0xca, 0xa9, 0x1f, 0x70, 0x7d, 0xb8,
-	0x5c, 0x63, 0x3d, 0x86, 0xa1, 0x88, 0xb1, 0x2b, 0x61, 0x3e, 0x0a, 0x90, 0x4d, 0x28, 0x4d, 0xf2,
-	0x33, 0x10, 0xc9, 0x4f, 0x63, 0x7a, 0x10, 0xd1, 0x1a, 0xe4, 0x15, 0xd4, 0x27, 0xcc, 0x75, 0x03,
-	0xbe, 0xd7, 0x9d, 0x9b, 0x80, 0xd2, 0xe1, 0xa2, 0x37, 0xb7, 0x4b, 0xcf, 0x69, 0xdb, 0x2f, 0xa0,
-	0x3e, 0xaf, 0x81, 0x71, 0x46, 0xa1, 0x9e, 0x00, 0x8a, 0x54, 0xae, 0x31, 0xce, 0x71, 0xe8, 0x72,
-	0xf5, 0xe1, 0x54, 0xa9, 0x02, 0xf6, 0x3b, 0x58, 0xeb, 0xc7, 0xe1, 0xe4, 0x3a, 0xc9, 0x67, 0x29,
-	0xad, 0x7c, 0x29, 0xa5, 0xcd, 0x3e, 0x54, 0xd3, 0x09, 0x95, 0x58, 0x70, 0xa7, 0xbb, 0x7f, 0xd8,
-	0xd9, 0xa1, 0xc7, 0xb4, 0xf3, 0x86, 0x76, 0xfa, 0xfd, 0xfd, 0xa3, 0xc3, 0xe3, 0xf7, 0x5d, 0x73,
-	0x89, 0xfc, 0x1b, 0xd6, 0xbb, 0x47, 0x6f, 0xf6, 0xdb, 0xe7, 0x36, 0x0c, 0xb2, 0x0e, 0x6b, 0xbb,
-	0x87, 0x87, 0xc7, 0xbd, 0x9d, 0xdd, 0xdd, 0x6e, 0x67, 0xaf, 0x8b, 0xc2, 0xc2, 0xa6, 0x0d, 0x95,
-	0x64, 0x96, 0x25, 0x55, 0x28, 0x76, 0x3b, 0x3b, 0xf4, 0xd0, 0x5c, 0x22, 0x35, 0x28, 0xf7, 0x68,
-	0x67, 0x77, 0xbf, 0x3d, 0x30, 0x8d, 0xcd, 0x27, 0x50, 0xd6, 0xbf, 0xec, 0xc8, 0x2a, 0x54, 0x28,
-	0xf7, 0x8e, 0x0f, 0xc3, 0x31, 0x37, 0x97, 0xc8, 0x2d, 0xa8, 0x22, 0xea, 0x32, 0x21, 0x42, 0xd3,
-	0x48, 0x20, 0xf5, 0x5d, 0x8f, 0x9b, 0x85, 0xcd, 0x57, 0x50, 0x9f, 0x9f, 0xe0, 0xc8, 0x6d, 0xb8,
-	0xd5, 0x89, 0x72, 0x93, 0x8f, 0xb9, 0x44, 0xea, 0x00, 0x9d, 0x28, 0x99, 0x6f, 0x4c, 0x03, 0x63,
-	0xe8, 0x44, 0xdd, 0xa3, 0x23, 0xb3, 0xb0, 0xf9, 0x08, 0x2a, 0xc9, 0x5b, 0x85, 0x6a, 0xd9, 0x63,
-	0x64, 0x2e, 0x91, 0x35, 0xa8, 0xe5, 0xde, 0x4d, 0xd3, 0x78, 0xfd, 0xe4, 0xbb, 0xc7, 0x9e, 0x1f,
-	0x0f, 0xa7, 0x27, 0x48, 0xe0, 0xb6, 0x2a, 0x9d, 0xfa, 0xab, 0xc1, 0xee, 0xe0, 0xc3, 0xb6, 0xcb,
-	0xfc, 0x6d, 0xf9, 0x7b, 0x58, 0xe8, 0x5f, 0xc7, 0x27, 0x25, 0x09, 0x1f, 0xff, 0x1d, 0x00, 0x00,
-	0xff, 0xff, 0x70, 0x53, 0x63, 0x30, 0x35, 0x0f, 0x00, 0x00,
+	// 2905 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcd, 0x72, 0x1c, 0xc7,
+	0x91, 0x66, 0xcf, 0x0f, 0x30, 0x93, 0x83, 0x9f, 0x61, 0x81, 0xa2, 0x5a, 0xa0, 0x96, 0x8b, 0xe8,
+	0x8d, 0x55, 0x90, 0x5c, 0x2d, 0xb8, 0x82, 0x96, 0x2b, 0x4a, 0x8c, 0x50, 0x2c, 0x09, 0x80, 0x3f,
+	0xda, 0x21, 0x80, 0x28, 0x40, 0x5a, 0x86, 0x2e, 0x8c, 0x42, 0x77, 0x61, 0xa6, 0x83, 0x3d, 0xdd,
+	0xe3, 0xee, 0x1a, 0x90, 0xa3, 0x8b, 0xcf, 0x0e, 0x1f, 0xfd, 0x00, 0xbe, 0xe8, 0xe0, 0xb7, 0xb0,
+	0xc3, 0xf6, 0xcd, 0x27, 0xbd, 0x81, 0x9f, 0xc1, 0x4f, 0xe0, 0xc8, 0xac, 0xaa, 0xee, 0xea, 0xc1,
+	0x80, 0x22, 0xc3, 0x07, 0x5f, 0x80, 0xce, 0xac, 0xac, 0xac, 0xac, 0xcc, 0xac, 0xaf, 0xb2, 0x72,
+	0x60, 0x23, 0xcc, 0xc6, 0xe3, 0x2c, 0xbd, 0xab, 0xff, 0x6d, 0x4f, 0xf2, 0x4c, 0x65, 0x6c, 0x49,
+	0x53, 0xc1, 0xef, 0x3b, 0xd0, 0x3b, 0xc9, 0x45, 0x9c, 0x1e, 0x89, 0x5c, 0x8c, 0x0b, 0x76, 0x0d,
+	0xda, 0x89, 0x38, 0x95, 0x89, 0xef, 0x6d, 0x79, 0xb7, 0xba, 0x5c, 0x13, 0xec, 0x63, 0xe8, 0xd2,
+	0xc7, 0x81, 0x18, 0x4b, 0xbf, 0x41, 0x23, 0x15, 0x83, 0xdd, 0x86, 0xe5, 0x5c, 0x0e, 0x9f, 0x67,
+	0x91, 0xf4, 0x9b, 0x5b, 0xde, 0xad, 0xb5, 0x9d, 0xf5, 0x6d, 0xb3, 0x16, 0xd7, 0x6c, 0x6e, 0xc7,
+	0xd9, 0x26, 0x74, 0x72, 0x39, 0xa4, 0xb5, 0xfc, 0xd6, 0x96, 0x77, 0xcb, 0xe3, 0x25, 0x8d, 0x4b,
+	0x8b, 0x64, 0x32, 0x12, 0x7e, 0x9b, 0x06, 0x34, 0x81, 0x4b, 0x8b, 0xf1, 0x24, 0x89, 0xd5, 0x34,
+	0x92, 0xfe, 0x12, 0x8d, 0x54, 0x0c, 0xd4, 0x27, 0xc2, 0x70, 0x9a, 0x8b, 0x70, 0xe6, 0x2f, 0x6f,
+	0x79, 0xb7, 0x9a, 0xbc, 0xa4, 0x71, 0x66, 0x5c, 0x9c, 0x08, 0xd4, 0xae, 0xfc, 0xce, 0x96, 0x77,
+	0xab, 0xc3, 0x2b, 0x06, 0xbb, 0x0e, 0x4b, 0x71, 0x44, 0xfb, 0xe9, 0xd2, 0x7e, 0x0c, 0x85, 0xb3,
+	0x4e, 0x85, 0x0a, 0x47, 0xc7, 0xf1, 0x0f, 0xd2, 0x07, 0x52, 0x59, 0x31, 0xd8, 0xe7, 0xd0, 0x7d,
+	0x33, 0x3c, 0xd5, 0xbe, 0xf2, 0x7b, 0x5b, 0xde, 0xad, 0xde, 0xce, 0x07, 0x76, 0xb3, 0x2f, 0x9e,
+	0x3c, 0xca, 0xb2, 0x42, 0xe9, 0x41, 0x5e, 0xc9, 0xb1, 0x00, 0x56, 0x26, 0x45, 0xfc, 0x30, 0x19,
+	0x66, 0x79, 0xac, 0x46, 0x63, 0x7f, 0x85, 0x16, 0xac, 0xf1, 0xd8, 0x16, 0xf4, 0xe2, 0x34, 0xcc,
+	0xe5, 0x58, 0xa6, 0x4a, 0x24, 0xfe, 0x2a, 0x99, 0xeb, 0xb2, 0x50, 0xcb, 0x74, 0x12, 0x09, 0x25,
+	0x79, 0x36, 0x4d, 0xa3, 0xc2, 0x5f, 0x23, 0xdb, 0x6a, 0x3c, 0xf6, 0x09, 0xac, 0x45, 0x79, 0x7c,
+	0xa6, 0x4e, 0xb2, 0x44, 0xe6, 0x22, 0x0d, 0xa5, 0xbf, 0x4e, 0x1e, 0x9b, 0xe3, 0xb2, 0xcf, 0x70,
+	0x93, 0x85, 0xc4, 0x90, 0x24, 0x7e, 0x9f, 0xb6, 0xb1, 0x61, 0xb7, 0x41, 0xd9, 0x40, 0x23, 0x05,
+	0xaf, 0xa4, 0x98, 0x0f, 0xcb, 0x45, 0x28, 0x92, 0x38, 0x1d, 0xfa, 0x57, 0xc9, 0x7e, 0x4b, 0xb2,
+	0x2d, 0x68, 0x44, 0x13, 0x9f, 0x91, 0x96, 0xbe, 0xd5, 0xb2, 0x77, 0x64, 0xfc, 0xd0, 0x88, 0x26,
+	0xec, 0x01, 0xf4, 0x42, 0xa1, 0x24, 0xee, 0x35, 0x14, 0x89, 0xbf, 0x41, 0xa2, 0x1f, 0x59, 0xd1,
+	0xdd, 0x6a, 0xc8, 0xcc, 0x71, 0xa5, 0xd9, 0x43, 0x58, 0x95, 0x22, 0x4f, 0x66, 0xc7, 0x2a, 0x9b,
+	0x4c, 0x70, 0xf9, 0x6b, 0x34, 0xfd, 0x86, 0x9d, 0xbe, 0xef, 0x0e, 0x1a, 0x05, 0xf5, 0x19, 0x8c,
+	0x41, 0xab, 0x90, 0x32, 0xf2, 0x3f, 0x20, 0x97, 0xd1, 0x37, 0xbb, 0x07, 0xdd, 0x6c, 0xa2, 0xe2,
+	0x71, 0xfc, 0x83, 0xcc, 0xfd, 0xeb, 0xa4, 0xf2, 0x43, 0xab, 0xf2, 0xd0, 0x0e, 0xd8, 0x58, 0x96,
+	0x92, 0x95, 0x87, 0x47, 0xb9, 0x2c, 0x46, 0x59, 0x12, 0xf9, 0x1f, 0xba, 0x1e, 0xb6, 0x5c, 0x8c,
+	0xd6, 0x6b, 0x19, 0x0f, 0x47, 0x6a, 0x37, 0x4b, 0xa6, 0xe3, 0xd4, 0xf7, 0x75, 0xcc, 0x5d, 0x1e,
+	0xfb, 0x04, 0x5a, 0x49, 0x56, 0x14, 0xfe, 0x47, 0xb4, 0x3a, 0xb3, 0xab, 0x0f, 0xb2, 0xa2, 0x30,
+	0x0b, 0xd3, 0x38, 0xbb, 0x09, 0xf0, 0x3a, 0x56, 0xa3, 0xe3, 0x30, 0xcb, 0x65, 0xe1, 0x6f, 0x52,
+	0x6a, 0x38, 0x1c, 0xf6, 0x29, 0x2c, 0x45, 0xf9, 0x8c, 0x4f, 0x53, 0xff, 0x06, 0x69, 0xba, 0x56,
+	0x06, 0x81, 0xb8, 0x46, 0x97, 0x91, 0x41, 0xcb, 0x44, 0x12, 0x0f, 0x53, 0x4c, 0xab, 0xff, 0x93,
+	0x33, 0xff, 0x63, 0x6d, 0x99, 0xcb, 0x63, 0x77, 0xa0, 0x8f, 0xfa, 0xf7, 0xdf, 0x4c, 0x12, 0x91,
+	0x0a, 0x15, 0x67, 0x69, 0xe1, 0xff, 0x0b, 0xad, 0x7b, 0x81, 0x1f, 0x48, 0xd8, 0x58, 0x10, 0x02,
+	0x3c, 0x5f, 0x63, 0xa9, 0xf2, 0x38, 0x34, 0x48, 0x62, 0x28, 0x3c, 0xb1, 0x13, 0xa1, 0x62, 0x99,
+	0x86, 0x1a, 0x49, 0x9a, 0xbc, 0xa4, 0x71, 0x6c, 0x1c, 0xa7, 0x7b, 0x32, 0x51, 0x82, 0x90, 0xc4,
+	0xe3, 0x25, 0x1d, 0x84, 0x70, 0xf5, 0x42, 0xa2, 0x60, 0x52, 0x86, 0xe4, 0xcb, 0xc2, 0xf7, 0xb6,
+	0x9a, 0x98, 0x94, 0x86, 0x44, 0x55, 0x32, 0x0d, 0xb3, 0x08, 0x13, 0x46, 0x03, 0x56, 0x49, 0xe3,
+	0xac, 0x69, 0xfa, 0x2a, 0xcd, 0x5e, 0xa7, 0xb4, 0x4a, 0x97, 0x5b, 0x32, 0x48, 0xa1, 0x63, 0x13,
+	0x17, 0xa5, 0xe4, 0xa4, 0x88, 0x93, 0x2c, 0xa5, 0x1d, 0x78, 0xdc, 0x92, 0x08, 0x54, 0x11, 0xd9,
+	0xd8, 0xd0, 0x40, 0x45, 0x04, 0xae, 0x18, 0x26, 0xf1, 0xe4, 0x20, 0xcb, 0xc7, 0xd6, 0x78, 0x4b,
+	0xa3, 0x33, 0x72, 0x7d, 0x6a, 0x5b, 0xb4, 0x65, 0x43, 0x05, 0xbf, 0xf2, 0x60, 0xb5, 0x06, 0x1b,
+	0xe4, 0x02, 0xf1, 0x66, 0x4f, 0x4e, 0xd4, 0x88, 0x96, 0x6d, 0xf2, 0x92, 0xc6, 0xc8, 0x25, 0x52,
+	0xe4, 0x69, 0x9c, 0x0e, 0xb9, 0x50, 0xd2, 0x2c, 0x5f, 0xe3, 0x21, 0x8e, 0xa4, 0xfb, 0x85, 0x8a,
+	0xc7, 0x42, 0x65, 0x79, 0x41, 0x86, 0x34, 0xb9, 0xcb, 0x42, 0x5b, 0x12, 0x31, 0x3e, 0x8d, 0x84,
+	0x01, 0x60, 0x43, 0x05, 0x7f, 0x5e, 0x36, 0x37, 0x81, 0x3e, 0xfb, 0xec, 0x0b, 0x58, 0x52, 0x23,
+	0xa9, 0x84, 0x76, 0x6d, 0x6f, 0xe7, 0x5f, 0x17, 0x00, 0xc4, 0xf6, 0x09, 0x49, 0xec, 0xa7, 0x2a,
+	0x9f, 0x71, 0x23, 0xce, 0xfe, 0x1b, 0xda, 0x6f, 0x4e, 0x45, 0x5e, 0xf8, 0x0d, 0x9a, 0x77, 0x73,
+	0xd1, 0xbc, 0x17, 0x28, 0xa0, 0xa7, 0x69, 0x61, 0x5c, 0xae, 0x88, 0x87, 0x63, 0x81, 0x36, 0x5f,
+	0xba, 0xdc, 0x31, 0x49, 0x98, 0xe5, 0xb4, 0x78, 0x75, 0x63, 0xb5, 0xe6, 0x6e, 0xac, 0x0a, 0xfc,
+	0xdb, 0x97, 0x83, 0xff, 0x52, 0x0d, 0xfc, 0x19, 0xb4, 0x26, 0x42, 0x8d, 0xe8, 0x2a, 0xe9, 0x72,
+	0xfa, 0x66, 0xdb, 0xb0, 0xfc, 0x66, 0x78, 0x8a, 0x21, 0xf2, 0x3b, 0xf5, 0xe3, 0x65, 0x22, 0x47,
+	0xb6, 0x71, 0x2b, 0x74, 0x01, 0xed, 0xbb, 0x0b, 0xd0, 0xde, 0x01, 0x53, 0xa8, 0x83, 0xe9, 0x17,
+	0x00, 0x16, 0xfc, 0x24, 0xde, 0x30, 0x4d, 0x17, 0x97, 0xcc, 0x01, 0x98, 0x3d, 0x17, 0x74, 0xd2,
+	0xb8, 0x23, 0xba, 0x00, 0x98, 0x56, 0x17, 0x02, 0xd3, 0xff, 0x42, 0xb7, 0x98, 0x8e, 0xc7, 0x82,
+	0xf4, 0xaf, 0x90, 0xfe, 0x60, 0xa1, 0xab, 0xad, 0x90, 0xf6, 0x76, 0x35, 0xe9, 0x02, 0xb4, 0xad,
+	0xbd, 0x05, 0xda, 0xd6, 0xdf, 0x0b, 0xda, 0xfa, 0x17, 0xa0, 0x6d, 0x11, 0x10, 0x5d, 0x5d, 0x0c,
+	0x44, 0x9b, 0x5f, 0x42, 0xcf, 0x49, 0x47, 0xd6, 0x87, 0xe6, 0x2b, 0x39, 0x33, 0xe8, 0x83, 0x9f,
+	0x98, 0x29, 0xe7, 0x22, 0x99, 0xda, 0x83, 0xa3, 0x89, 0xaf, 0x1a, 0xf7, 0xbd, 0xcd, 0xfb, 0x00,
+	0x55, 0x46, 0xbe, 0xd7, 0xcc, 0x2f, 0xa1, 0xe7, 0x24, 0xe5, 0x7b, 0x4d, 0x3d, 0x81, 0xb5, 0xba,
+	0x93, 0x17, 0xcc, 0xfe, 0xd4, 0x9d, 0xdd, 0xdb, 0xb9, 0x6e, 0x1d, 0xf9, 0x58, 0x0a, 0x35, 0xcd,
+	0xa5, 0x9e, 0x3f, 0x73, 0xb4, 0x06, 0xbf, 0x84, 0xf5, 0xb9, 0x34, 0xc1, 0x6c, 0xd7, 0xb0, 0x68,
+	0xa1, 0x58, 0x53, 0xe8, 0x7c, 0x27, 0xd7, 0x1a, 0x04, 0xa0, 0x0e, 0xa7, 0x86, 0xa1, 0xcd, 0xcb,
+	0x31, 0xb4, 0x55, 0xc7, 0xd0, 0x19, 0xac, 0xb8, 0x07, 0x83, 0xdd, 0x86, 0xb6, 0xca, 0xa5, 0xb4,
+	0x30, 0xb2, 0x31, 0x77, 0x7a, 0x4e, 0x72, 0x29, 0xb9, 0x96, 0xd0, 0xb5, 0x57, 0x21, 0x29, 0xf6,
+	0xc6, 0x5f, 0x15, 0x03, 0xa1, 0xed, 0x34, 0x4e, 0x45, 0x3e, 0xdb, 0x4d, 0x44, 0xa1, 0xa1, 0xad,
+	0xc3, 0x5d, 0x56, 0x70, 0x1f, 0x7a, 0x8e, 0x56, 0x5c, 0x39, 0xcd, 0xa2, 0x4b, 0x57, 0x3e, 0xc0,
+	0xca, 0x54, 0x4b, 0x04, 0xbf, 0xf5, 0xa0, 0xe7, 0xb0, 0xd9, 0x1a, 0x34, 0xe2, 0x88, 0xdc, 0xd5,
+	0xe6, 0x8d, 0x38, 0x22, 0xc0, 0x28, 0x06, 0x52, 0x9c, 0x91, 0x59, 0x1d, 0x6e, 0x28, 0xe4, 0xeb,
+	0xbc, 0x37, 0x90, 0x6f, 0x28, 0x74, 0x4f, 0x5c, 0x0c, 0x32, 0xac, 0x76, 0x5a, 0x34, 0xc1, 0x92,
+	0x38, 0x72, 0xa6, 0x83, 0x47, 0xb0, 0xd4, 0xe5, 0x96, 0xc4, 0xdd, 0xab, 0xf2, 0xf0, 0x9a, 0x4a,
+	0xb7, 0x64, 0x04, 0x7f, 0x6d, 0x01, 0x9c, 0x88, 0xe2, 0x95, 0xb9, 0x27, 0xfe, 0x1d, 0x5a, 0x22,
+	0x19, 0x66, 0x64, 0xe2, 0xda, 0xce, 0x55, 0xbb, 0xb5, 0x12, 0x62, 0x38, 0x0d, 0xb3, 0x4f, 0xa1,
+	0xa3, 0x44, 0xf1, 0xea, 0x64, 0x36, 0xd1, 0x0e, 0x5d, 0xab, 0x2a, 0xb4, 0x13, 0xc3, 0xe7, 0xa5,
+	0x04, 0xbb, 0x07, 0x3d, 0x55, 0xbd, 0x05, 0x68, 0x4b, 0xf3, 0x85, 0xa1, 0xad, 0xd0, 0x1c, 0x39,
+	0x0c, 0xcc, 0x18, 0x43, 0x8d, 0x1a, 0x9f, 0xed, 0x99, 0x7c, 0x70, 0x59, 0xa8, 0x98, 0x48, 0xa3,
+	0xb8, 0x7d, 0x79, 0xc5, 0xe9, 0xca, 0xb1, 0xfb, 0x00, 0xf2, 0xdc, 0x5e, 0xf6, 0xe4, 0x92, 0xde,
+	0x8e, 0x5f, 0xd6, 0x7d, 0x98, 0xf3, 0x74, 0xf4, 0x8d, 0x4d, 0x8e, 0x2c, 0xfb, 0x1a, 0x7a, 0x49,
+	0x5c, 0x4d, 0x5d, 0xa6, 0xa9, 0x1f, 0x97, 0x30, 0x14, 0x9f, 0xcb, 0x0b, 0xd3, 0xdd, 0x09, 0x54,
+	0xa5, 0xe4, 0x31, 0xba, 0x72, 0x46, 0xa8, 0xdf, 0xe6, 0x25, 0x8d, 0x11, 0x54, 0xf1, 0x58, 0x66,
+	0x53, 0x45, 0xd8, 0xde, 0xe4, 0x96, 0x44, 0x47, 0x84, 0x22, 0x49, 0x4e, 0x45, 0xf8, 0xea, 0x5b,
+	0x3e, 0x30, 0xd0, 0xee, 0xb2, 0xd8, 0xff, 0xe0, 0xe5, 0x7b, 0x2a, 0x13, 0x0b, 0xed, 0x37, 0xdd,
+	0x68, 0xe8, 0xb5, 0xb7, 0x07, 0x24, 0x60, 0x2e, 0x39, 0x2d, 0xcd, 0x6e, 0xc1, 0x7a, 0x2e, 0x8b,
+	0x69, 0xa2, 0x8e, 0xa6, 0xa7, 0x49, 0x1c, 0x62, 0xdd, 0x86, 0xaf, 0x88, 0x15, 0x3e, 0xcf, 0x46,
+	0x40, 0x72, 0x14, 0xfc, 0x1c, 0x20, 0x75, 0x5d, 0xe8, 0xf8, 0xc9, 0x83, 0xfe, 0xbc, 0x5b, 0x30,
+	0xc3, 0x65, 0x2a, 0x4e, 0x13, 0x49, 0x3a, 0x3a, 0xdc, 0x50, 0x6c, 0x07, 0x3a, 0xe8, 0x6f, 0x3e,
+	0x4d, 0x6c, 0x66, 0x5d, 0xbf, 0x18, 0x19, 0x1c, 0xe5, 0xa5, 0x1c, 0xa6, 0x41, 0x2e, 0xd2, 0x28,
+	0x1b, 0x1f, 0xe3, 0xfb, 0x6d, 0x3e, 0xbf, 0x78, 0x35, 0xc4, 0x5d, 0x39, 0x7c, 0x60, 0x84, 0xe7,
+	0x7e, 0xab, 0xfe, 0xc0, 0xd8, 0xcd, 0xb3, 0xa2, 0xf8, 0x4e, 0x24, 0xbc, 0x11, 0x9e, 0x63, 0x48,
+	0x74, 0x79, 0x89, 0xb9, 0x45, 0x75, 0xa0, 0x21, 0x03, 0x09, 0xd7, 0x16, 0x45, 0xfb, 0xd2, 0x6d,
+	0xcd, 0x99, 0xd8, 0x78, 0x37, 0x13, 0x83, 0x5f, 0x7b, 0xd0, 0x73, 0x06, 0xf1, 0x2c, 0x4f, 0x64,
+	0x1e, 0xca, 0x54, 0x0d, 0x0e, 0x0d, 0x8c, 0x54, 0x0c, 0xbc, 0x41, 0x95, 0x2c, 0xd4, 0xe3, 0x5c,
+	0x84, 0x68, 0x92, 0x2d, 0xe4, 0x5c, 0x1e, 0x66, 0x60, 0xa1, 0x72, 0x04, 0xe3, 0x99, 0x05, 0x5f,
+	0x4b, 0x23, 0x70, 0x63, 0xca, 0x99, 0xfb, 0x57, 0x9f, 0x37, 0x87, 0x13, 0xbc, 0x81, 0x8e, 0x75,
+	0x0f, 0x86, 0xfb, 0x2c, 0x4b, 0xa2, 0xc2, 0x58, 0xa1, 0x09, 0x2a, 0x40, 0x46, 0xd3, 0xb3, 0x33,
+	0x13, 0xbc, 0x0e, 0xb7, 0xa4, 0x7e, 0xa1, 0x4f, 0xa4, 0x50, 0x32, 0x32, 0x10, 0x5b, 0xd2, 0x98,
+	0xdf, 0xfa, 0xfb, 0x24, 0x1e, 0x4b, 0x5d, 0xcb, 0xb6, 0xb9, 0xcb, 0x0a, 0xfe, 0xe6, 0xc1, 0xf5,
+	0xca, 0xd7, 0xcf, 0x29, 0x08, 0xe6, 0x2a, 0x1f, 0xc2, 0x0d, 0x07, 0xab, 0x77, 0xf1, 0x61, 0xe9,
+	0x0c, 0x93, 0x79, 0xbd, 0x9d, 0x7f, 0xb3, 0x9e, 0x7e, 0x74, 0xb9, 0xe8, 0xd3, 0x2b, 0xfc, 0x6d,
+	0x9a, 0x58, 0x04, 0x9b, 0x5c, 0x0e, 0x73, 0x59, 0x14, 0x71, 0x96, 0x5e, 0x58, 0x47, 0x47, 0x34,
+	0x70, 0x3a, 0x14, 0x97, 0x48, 0x3e, 0xbd, 0xc2, 0xdf, 0xa2, 0xe7, 0x51, 0x17, 0x96, 0x27, 0x62,
+	0x96, 0x64, 0x22, 0x0a, 0x7e, 0x6c, 0xc3, 0x8d, 0xb7, 0xd8, 0x8b, 0x20, 0x1c, 0x8a, 0x42, 0x12,
+	0x08, 0x7b, 0x75, 0x10, 0xde, 0x35, 0x7c, 0x5e, 0x4a, 0xa0, 0x93, 0xc5, 0xf9, 0xf0, 0xa1, 0xed,
+	0x6a, 0xe8, 0xdc, 0x70, 0x59, 0xf4, 0x82, 0x3b, 0x1f, 0x1e, 0xe5, 0x32, 0x8c, 0xd1, 0x34, 0x73,
+	0xf5, 0xd4, 0x78, 0xd4, 0x36, 0x39, 0x1f, 0x72, 0x89, 0xe0, 0x63, 0x0a, 0xfd, 0x8a, 0x81, 0x09,
+	0x24, 0xce, 0x87, 0x8f, 0x3f, 0xd3, 0x37, 0xad, 0xee, 0xb7, 0x38, 0x1c, 0x3c, 0x1d, 0xb8, 0xe0,
+	0xb7, 0xbb, 0xe6, 0x1e, 0x32, 0x14, 0x7b, 0x09, 0x6b, 0xe6, 0x60, 0x1d, 0xc9, 0xfc, 0x31, 0xde,
+	0x53, 0xcb, 0x04, 0x63, 0x5f, 0xbc, 0x43, 0xd8, 0xb6, 0x9f, 0xd7, 0x66, 0x6a, 0x7c, 0x9b, 0x53,
+	0xb7, 0xf9, 0x01, 0xb4, 0x8f, 0xb2, 0x38, 0x55, 0x6c, 0x05, 0xbc, 0x09, 0xdd, 0xdb, 0x1e, 0xf7,
+	0x26, 0x9b, 0x7f, 0xf1, 0x60, 0xad, 0x3e, 0xbd, 0xd6, 0xf9, 0xd1, 0xef, 0xb3, 0x5a, 0xe7, 0x67,
+	0x52, 0x7a, 0xc7, 0xd4, 0x11, 0x25, 0x83, 0x1e, 0x63, 0xda, 0x2f, 0xe6, 0xce, 0xd6, 0x14, 0x9e,
+	0x09, 0xeb, 0x11, 0xed, 0x30, 0x4b, 0x22, 0x88, 0xa2, 0x2f, 0xb4, 0x9f, 0xf0, 0x93, 0x3d, 0x80,
+	0x26, 0x3f, 0x44, 0xef, 0xe0, 0xee, 0x6f, 0xbf, 0xcb, 0xee, 0x69, 0x5b, 0x1c, 0x67, 0x6d, 0x4e,
+	0x61, 0x63, 0x81, 0x2f, 0x5c, 0xa8, 0x6e, 0x6b, 0xa8, 0x7e, 0x5a, 0xaf, 0xfe, 0x76, 0xde, 0xdf,
+	0xcb, 0x2e, 0xbc, 0xff, 0xae, 0xf9, 0xb6, 0x83, 0xf1, 0x9e, 0x59, 0xba, 0x0b, 0x6d, 0xfe, 0xfc,
+	0x78, 0xdf, 0x3e, 0xf2, 0xfe, 0xf3, 0xe7, 0xcf, 0xd3, 0x36, 0xc9, 0x9b, 0x37, 0x1f, 0x7d, 0xd3,
+	0x63, 0x57, 0x8a, 0x14, 0x89, 0xf2, 0xbd, 0x6f, 0x68, 0x4c, 0xd1, 0x42, 0x45, 0x7b, 0xf2, 0x9c,
+	0x46, 0x75, 0x40, 0x1c, 0x0e, 0x1b, 0x40, 0x87, 0xef, 0x98, 0x33, 0xdd, 0x26, 0x1b, 0xfe, 0xeb,
+	0x5d, 0x6c, 0x30, 0x53, 0xb4, 0x19, 0xa5, 0x06, 0xdd, 0xad, 0x10, 0x29, 0xdf, 0xb1, 0x09, 0xaf,
+	0x29, 0x7c, 0x18, 0x54, 0x66, 0x2f, 0x88, 0xd0, 0xe5, 0xd5, 0xfd, 0x03, 0x58, 0xad, 0x2d, 0xf6,
+	0x3e, 0x93, 0x83, 0x3f, 0x36, 0x61, 0x9d, 0xaa, 0x22, 0x2c, 0x0b, 0x38, 0xdd, 0xf0, 0x68, 0xa2,
+	0xd2, 0x05, 0x96, 0xa9, 0xe2, 0x35, 0x45, 0x50, 0x3e, 0x0d, 0x43, 0x59, 0x14, 0x25, 0x94, 0x6b,
+	0x12, 0xf5, 0x53, 0x35, 0x45, 0xbe, 0x5d, 0xe1, 0x9a, 0x40, 0x3d, 0x32, 0xcf, 0x9f, 0x17, 0x43,
+	0x73, 0x71, 0x18, 0x8a, 0x7d, 0x03, 0x7d, 0xbc, 0xa8, 0x6b, 0x60, 0xa9, 0x4b, 0xae, 0x9b, 0x17,
+	0x2f, 0x76, 0x57, 0x8a, 0x5f, 0x98, 0xc7, 0x1e, 0x40, 0x87, 0x0a, 0xc4, 0x63, 0xa9, 0xfc, 0xf6,
+	0x82, 0xe7, 0x7c, 0xb5, 0xad, 0xed, 0xc7, 0x71, 0x22, 0x79, 0xf6, 0x9a, 0x97, 0x13, 0xa8, 0x58,
+	0x24, 0x65, 0xba, 0x11, 0xb4, 0x5c, 0xbf, 0x82, 0x9f, 0x57, 0x43, 0xdc, 0x95, 0x63, 0x0f, 0x60,
+	0x75, 0x92, 0xc7, 0xe7, 0x22, 0x9c, 0x3d, 0x9a, 0x46, 0x43, 0x69, 0x5f, 0xeb, 0x65, 0x7b, 0xf6,
+	0xc8, 0x1d, 0xe4, 0x75, 0x59, 0xec, 0x06, 0x96, 0x2d, 0x43, 0xaa, 0xea, 0x9c, 0x57, 0x77, 0xd9,
+	0xdd, 0xd2, 0x16, 0xf3, 0x4a, 0x72, 0xf3, 0x06, 0x2c, 0x1b, 0xfb, 0x31, 0xbc, 0x79, 0xf6, 0xda,
+	0xb4, 0xa1, 0xf0, 0x33, 0xf8, 0x93, 0x07, 0xeb, 0x73, 0x73, 0x2f, 0xed, 0x8a, 0xe1, 0xcb, 0x47,
+	0x16, 0xea, 0x3b, 0x27, 0x1d, 0x2a, 0x86, 0x1d, 0xa5, 0x26, 0x2f, 0x05, 0xb3, 0xc5, 0x2b, 0x06,
+	0x9e, 0x94, 0xb3, 0x38, 0x15, 0x89, 0x9e, 0x6c, 0x4e, 0x4a, 0xc5, 0xa1, 0x04, 0xc1, 0xde, 0x9c,
+	0x8c, 0x4c, 0x23, 0xc4, 0x92, 0x78, 0x91, 0x98, 0x4f, 0xad, 0x7a, 0x89, 0x54, 0xd7, 0x78, 0xc1,
+	0xff, 0xc3, 0x6a, 0xcd, 0x73, 0xef, 0xdd, 0x17, 0xab, 0x7a, 0x5f, 0xcd, 0x5a, 0xef, 0xeb, 0x18,
+	0x7a, 0x4e, 0x2c, 0x2f, 0xf5, 0x0c, 0x83, 0x16, 0x3e, 0x01, 0x8d, 0x4e, 0xfa, 0xa6, 0xc7, 0x27,
+	0xb5, 0xbd, 0x23, 0x03, 0x1b, 0x96, 0x0c, 0x7e, 0xf4, 0xe0, 0xea, 0x51, 0x2e, 0xa3, 0x38, 0x54,
+	0xff, 0xd0, 0xd1, 0xd9, 0x84, 0x4e, 0x36, 0x55, 0x61, 0x86, 0x65, 0x8e, 0x3e, 0x3d, 0x25, 0x7d,
+	0xe9, 0x01, 0xba, 0x0d, 0x6d, 0xea, 0xb5, 0xcc, 0x3f, 0x6f, 0xf6, 0x90, 0xc9, 0xe5, 0x24, 0xcb,
+	0x15, 0xd7, 0x12, 0xc1, 0x1f, 0x3c, 0xe8, 0x1f, 0x2b, 0x91, 0x1b, 0x23, 0x7f, 0x31, 0x95, 0x85,
+	0x6b, 0x65, 0xa3, 0x66, 0x25, 0x83, 0xd6, 0x59, 0x9c, 0x48, 0x63, 0x07, 0x7d, 0xa3, 0xab, 0x47,
+	0x59, 0xa1, 0xb0, 0x06, 0xc3, 0x7c, 0xd3, 0x04, 0xbb, 0x03, 0x4b, 0x13, 0xf7, 0x85, 0xc5, 0x2e,
+	0xbe, 0x2e, 0xb8, 0x91, 0x60, 0x5f, 0xc3, 0xda, 0x44, 0x44, 0x51, 0x22, 0x1f, 0x0f, 0x6a, 0xef,
+	0xab, 0xb2, 0x8a, 0x3f, 0xaa, 0x8d, 0xf2, 0x39, 0xe9, 0xe0, 0x2b, 0x58, 0xab, 0x4b, 0xa0, 0x9d,
+	0x79, 0x66, 0x0a, 0xea, 0x36, 0xa7, 0x6f, 0xb4, 0x53, 0x3f, 0xc1, 0x75, 0x77, 0x41, 0x13, 0xc1,
+	0xb7, 0xb0, 0x8e, 0x67, 0xe2, 0x5d, 0x36, 0x5f, 0x6d, 0xa9, 0xf5, 0x73, 0x5b, 0x0a, 0x7e, 0xd3,
+	0x80, 0xf5, 0xb9, 0xd6, 0x3d, 0x1e, 0x9d, 0xaa, 0xcd, 0xaf, 0xa3, 0x5f, 0x31, 0xd0, 0xbc, 0x53,
+	0xa9, 0xc4, 0x67, 0x36, 0x63, 0x89, 0xb0, 0xdc, 0x1d, 0x93, 0x5c, 0x9a, 0x70, 0xf3, 0xbe, 0x55,
+	0xcf, 0x7b, 0x3c, 0xfa, 0xa3, 0xcc, 0x96, 0x07, 0xf9, 0x28, 0xa3, 0xe2, 0x3d, 0x1c, 0xc9, 0x08,
+	0x1f, 0x47, 0x4b, 0xa6, 0x78, 0x37, 0x34, 0x8d, 0x29, 0x39, 0xa1, 0xdf, 0x97, 0xcc, 0x4f, 0x56,
+	0x96, 0xc6, 0x95, 0x87, 0x62, 0x3c, 0x16, 0x84, 0x5d, 0x1e, 0xd7, 0x04, 0x56, 0x84, 0x2a, 0x53,
+	0x22, 0x31, 0x3f, 0xfc, 0xe8, 0x47, 0xa7, 0xcb, 0x32, 0x8d, 0xf3, 0x87, 0xf4, 0xeb, 0x19, 0x94,
+	0x8d, 0x73, 0xa2, 0x83, 0xef, 0x61, 0xad, 0xde, 0x2d, 0xc2, 0x40, 0xe1, 0xf5, 0x66, 0x8e, 0x2f,
+	0x7d, 0xe3, 0x1e, 0x0a, 0x15, 0x19, 0x3f, 0xe0, 0x27, 0x72, 0xc6, 0xb1, 0x2d, 0x2e, 0xf1, 0x93,
+	0x38, 0xe2, 0x8d, 0xd9, 0x3d, 0x7e, 0x06, 0x3f, 0x35, 0xa0, 0xe7, 0xa4, 0x37, 0xfa, 0x88, 0x12,
+	0x5c, 0x46, 0xe6, 0x59, 0x65, 0xc9, 0x7a, 0x73, 0xa3, 0x31, 0xd7, 0xdc, 0xa0, 0xe6, 0xaf, 0xbe,
+	0x71, 0xe6, 0x9a, 0xbf, 0x8e, 0xf2, 0x6d, 0xf7, 0xe6, 0x36, 0xe2, 0xf5, 0x6e, 0x66, 0xab, 0xde,
+	0xcd, 0xac, 0xcd, 0xbd, 0xac, 0x9b, 0x49, 0x0d, 0xbc, 0xc5, 0xb7, 0xf4, 0x3f, 0xa9, 0x81, 0xf7,
+	0x14, 0xa0, 0x6a, 0x93, 0x62, 0xac, 0x94, 0xad, 0xc8, 0xba, 0x9c, 0xbe, 0x2f, 0xc1, 0xd9, 0x3e,
+	0x34, 0x95, 0x98, 0xda, 0x78, 0x29, 0x31, 0x0d, 0xbe, 0x81, 0x15, 0xf7, 0x17, 0x20, 0xcc, 0x92,
+	0x33, 0xfb, 0xe4, 0x34, 0x25, 0xb3, 0xa5, 0xf1, 0x12, 0x89, 0x95, 0xcc, 0x4d, 0x8b, 0x55, 0xff,
+	0x30, 0xe3, 0x70, 0xee, 0x84, 0xd0, 0x75, 0xdb, 0xd7, 0xd7, 0x06, 0xcf, 0x0e, 0xf6, 0x1f, 0xf2,
+	0x97, 0x7c, 0xff, 0x09, 0xdf, 0x3f, 0x3e, 0x7e, 0x76, 0x78, 0xf0, 0xf2, 0xbb, 0x41, 0xff, 0x0a,
+	0xfb, 0x10, 0x36, 0x06, 0x87, 0x4f, 0x9e, 0xed, 0xce, 0x0d, 0x78, 0x6c, 0x03, 0xd6, 0xf7, 0x0e,
+	0x0e, 0x5e, 0x1e, 0x3d, 0xdc, 0xdb, 0x1b, 0xec, 0x3f, 0x1e, 0x20, 0xb3, 0xc1, 0xd6, 0x00, 0x5e,
+	0x3c, 0x79, 0x74, 0x78, 0x78, 0x7c, 0x82, 0x74, 0xf3, 0x4e, 0x00, 0x1d, 0xdb, 0x95, 0x62, 0x5d,
+	0x68, 0x0f, 0xf6, 0x1f, 0xf2, 0x83, 0xfe, 0x15, 0xd6, 0x83, 0xe5, 0x23, 0xbe, 0xbf, 0xf7, 0x6c,
+	0xf7, 0xa4, 0xef, 0xdd, 0xb9, 0x07, 0xcb, 0xe6, 0x57, 0x65, 0xb6, 0x02, 0x1d, 0x2e, 0x87, 0x2f,
+	0x0f, 0xb2, 0x54, 0xf6, 0xaf, 0xb0, 0x55, 0xe8, 0x22, 0x35, 0x10, 0x45, 0x91, 0xf5, 0x3d, 0x4b,
+	0xf2, 0x38, 0x1a, 0xca, 0x7e, 0xe3, 0xce, 0xd7, 0xb0, 0x56, 0x6f, 0x4b, 0xb0, 0xab, 0xb0, 0xba,
+	0x9f, 0x3b, 0x6f, 0xf6, 0xfe, 0x15, 0xb4, 0x67, 0x3f, 0xb7, 0x2f, 0xe7, 0xbe, 0x87, 0x36, 0xec,
+	0xe7, 0x83, 0xc3, 0xc3, 0x7e, 0xe3, 0xce, 0x7f, 0x40, 0xc7, 0x56, 0xc1, 0x28, 0x56, 0x95, 0x98,
+	0xfd, 0x2b, 0x6c, 0x1d, 0x7a, 0x4e, 0x45, 0xde, 0xf7, 0x1e, 0xdd, 0xfb, 0xfe, 0xf3, 0x61, 0xac,
+	0x46, 0xd3, 0x53, 0x8c, 0xf6, 0x5d, 0x0d, 0x93, 0xfa, 0xaf, 0x21, 0xf6, 0x4e, 0x5e, 0xdc, 0x8d,
+	0x44, 0x7c, 0x97, 0x7e, 0x8b, 0x2f, 0xcc, 0x2f, 0xf3, 0xa7, 0x4b, 0x44, 0x7e, 0xfe, 0xf7, 0x01,
+	0x00, 0x26, 0xe5, 0x27, 0xfb, 0xb1, 0x1f, 0x00, 0x00,
 }
@@ -22,9 +22,9 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-//MessageType defines the type of message with which communicate with nodes in cluster,
+// MessageType defines the type of message with which communicate with nodes in cluster,
 // and in some way it indicates the phase of learning
-//Some types are for local message which is not passed between nodes
+// Some types are for local message which is not passed between nodes
 type MessageType int32
 
 const (
@@ -130,6 +130,9 @@ type Message struct {
 	TrainSet             []*common.TrainTaskResult_FileRow `protobuf:"bytes,14,rep,name=trainSet,proto3" json:"trainSet,omitempty"`
 	PauseRound           uint64                            `protobuf:"varint,15,opt,name=pauseRound,proto3" json:"pauseRound,omitempty"`
 	TriggerRound         uint64                            `protobuf:"varint,16,opt,name=triggerRound,proto3" json:"triggerRound,omitempty"`
+	Checkpointed         bool                              `protobuf:"varint,17,opt,name=checkpointed,proto3" json:"checkpointed,omitempty"`
+	ResidualsBytes       []byte                            `protobuf:"bytes,18,opt,name=residualsBytes,proto3" json:"residualsBytes,omitempty"`
+	Accuracy             int64                             `protobuf:"varint,19,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
 	XXX_unrecognized     []byte                            `json:"-"`
 	XXX_sizecache        int32                             `json:"-"`
@@ -272,6 +275,27 @@ func (m *Message) GetTriggerRound() uint64 {
 	return 0
 }
 
+func (m *Message) GetCheckpointed() bool {
+	if m != nil {
+		return m.Checkpointed
+	}
+	return false
+}
+
+func (m *Message) GetResidualsBytes() []byte {
+	if m != nil {
+		return m.ResidualsBytes
+	}
+	return nil
+}
+
+func (m *Message) GetAccuracy() int64 {
+	if m != nil {
+		return m.Accuracy
+	}
+	return 0
+}
+
 type PredictMessage struct {
 	Type                 MessageType                `protobuf:"varint,1,opt,name=type,proto3,enum=linear_reg_vl.MessageType" json:"type,omitempty"`
 	To                   string                     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
@@ -279,6 +303,8 @@ type PredictMessage struct {
 	VlLPsiReEncIDsReq    *mpc.VLPsiReEncIDsRequest  `protobuf:"bytes,4,opt,name=vlLPsiReEncIDsReq,proto3" json:"vlLPsiReEncIDsReq,omitempty"`
 	VlLPsiReEncIDsResp   *mpc.VLPsiReEncIDsResponse `protobuf:"bytes,5,opt,name=vlLPsiReEncIDsResp,proto3" json:"vlLPsiReEncIDsResp,omitempty"`
 	PredictPart          []float64                  `protobuf:"fixed64,6,rep,packed,name=predictPart,proto3" json:"predictPart,omitempty"`
+	ExplainedFeatures    []string                   `protobuf:"bytes,7,rep,name=explainedFeatures,proto3" json:"explainedFeatures,omitempty"`
+	Contributions        []float64                  `protobuf:"fixed64,8,rep,packed,name=contributions,proto3" json:"contributions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
 	XXX_unrecognized     []byte                     `json:"-"`
 	XXX_sizecache        int32                      `json:"-"`
@@ -351,6 +377,20 @@ func (m *PredictMessage) GetPredictPart() []float64 {
 	return nil
 }
 
+func (m *PredictMessage) GetExplainedFeatures() []string {
+	if m != nil {
+		return m.ExplainedFeatures
+	}
+	return nil
+}
+
+func (m *PredictMessage) GetContributions() []float64 {
+	if m != nil {
+		return m.Contributions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("linear_reg_vl.MessageType", MessageType_name, MessageType_value)
 	proto.RegisterType((*Message)(nil), "linear_reg_vl.Message")
@@ -362,50 +402,55 @@ func init() {
 }
 
 var fileDescriptor_93418147b2b47a20 = []byte{
-	// 709 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xdd, 0x6f, 0xda, 0x3a,
-	0x18, 0xc6, 0x4f, 0x80, 0xf2, 0x61, 0xbe, 0x8c, 0xd1, 0xe9, 0xc9, 0x41, 0xd5, 0x39, 0x51, 0xaf,
-	0xa2, 0x5e, 0x80, 0xd4, 0x9e, 0x73, 0xb5, 0xab, 0x96, 0x7e, 0x4e, 0x45, 0x43, 0x81, 0x4d, 0xd3,
-	0x6e, 0xaa, 0x34, 0x79, 0x17, 0xa2, 0x86, 0xd8, 0xb3, 0x9d, 0x4e, 0xfc, 0x2d, 0xfb, 0x5b, 0xa7,
-	0x4d, 0xb6, 0x03, 0x84, 0xb6, 0xbb, 0x99, 0xb6, 0x1b, 0xc0, 0xbf, 0xe7, 0x79, 0x63, 0xfc, 0xbe,
-	0x8f, 0x01, 0x0d, 0x97, 0x2c, 0x18, 0x25, 0xe0, 0xf3, 0x14, 0xb8, 0x18, 0x25, 0x71, 0x0a, 0x3e,
-	0xbf, 0xe3, 0x10, 0xdd, 0x3d, 0x26, 0xbb, 0xab, 0x21, 0xe3, 0x54, 0x52, 0xd2, 0xde, 0x81, 0x83,
-	0xb6, 0x2a, 0x67, 0x22, 0x36, 0xea, 0xa0, 0x1f, 0xd0, 0xe5, 0x92, 0xa6, 0x23, 0xf3, 0x66, 0xe0,
-	0xe1, 0xd7, 0x0a, 0xaa, 0x4d, 0x40, 0x08, 0x3f, 0x02, 0x32, 0x44, 0x15, 0xb9, 0x62, 0x60, 0x5b,
-	0x8e, 0xe5, 0x76, 0x8e, 0x07, 0xc3, 0xdd, 0x2d, 0x72, 0xd7, 0x7c, 0xc5, 0xc0, 0xd3, 0x3e, 0xd2,
-	0x41, 0x25, 0x49, 0xed, 0x92, 0x63, 0xb9, 0x0d, 0xaf, 0x24, 0x29, 0x21, 0xa8, 0xf2, 0x91, 0xd3,
-	0xa5, 0x5d, 0xd6, 0x44, 0x7f, 0x26, 0x07, 0xa8, 0x91, 0x50, 0xca, 0x3c, 0x9a, 0xa5, 0xa1, 0x5d,
-	0x71, 0x2c, 0xb7, 0xe2, 0x6d, 0x01, 0xb9, 0x42, 0xbd, 0xc7, 0xe4, 0x76, 0x2a, 0x62, 0x0f, 0x2e,
-	0xd2, 0xe0, 0xe6, 0x5c, 0x78, 0xf0, 0xc9, 0xde, 0x73, 0x2c, 0xb7, 0x79, 0xfc, 0xb7, 0x3a, 0xfc,
-	0xf0, 0xdd, 0x13, 0x31, 0x03, 0x21, 0xbd, 0xe7, 0x35, 0xe4, 0x35, 0x22, 0x4f, 0xa1, 0x60, 0x76,
-	0x55, 0x3f, 0x69, 0xf0, 0xd2, 0x93, 0x04, 0xa3, 0xa9, 0x00, 0xef, 0x85, 0x2a, 0xf2, 0x0f, 0x42,
-	0x0b, 0xba, 0xa4, 0xd3, 0xec, 0xfe, 0x01, 0x56, 0x76, 0xcd, 0xb1, 0xdc, 0x96, 0x57, 0x20, 0xea,
-	0x48, 0x53, 0x9f, 0xcb, 0xb3, 0x95, 0x04, 0x61, 0xd7, 0xb5, 0xbc, 0x05, 0xe4, 0x08, 0x61, 0x48,
-	0x83, 0x2b, 0xee, 0x87, 0x97, 0x9c, 0x2e, 0xdf, 0xc8, 0x05, 0x70, 0xbb, 0xa1, 0x4d, 0xcf, 0x78,
-	0xee, 0x1d, 0x53, 0x21, 0xb7, 0x5e, 0xb4, 0xf1, 0xee, 0x70, 0xb5, 0x6b, 0xc4, 0xfd, 0xd0, 0xec,
-	0xda, 0x34, 0xbb, 0x6e, 0x80, 0x52, 0x03, 0x2a, 0xf2, 0xef, 0xd4, 0x32, 0xea, 0x06, 0x10, 0x1b,
-	0xd5, 0x84, 0xa4, 0x8c, 0x41, 0x68, 0xb7, 0x1d, 0xcb, 0xad, 0x7b, 0xeb, 0x25, 0x79, 0x85, 0xea,
-	0x92, 0xfb, 0x71, 0x3a, 0x03, 0x69, 0x77, 0x9c, 0xb2, 0xdb, 0x3c, 0xfe, 0x77, 0x98, 0xe7, 0x63,
-	0xae, 0xf8, 0xdc, 0x17, 0x0f, 0x1e, 0x88, 0x2c, 0x91, 0xc3, 0xcb, 0x38, 0x01, 0x8f, 0x7e, 0xf6,
-	0x36, 0x05, 0xaa, 0x51, 0xcc, 0xcf, 0x04, 0x98, 0xe1, 0x76, 0xf5, 0x70, 0x0b, 0x84, 0x1c, 0xa2,
-	0x96, 0xe4, 0x71, 0x14, 0x01, 0x37, 0x0e, 0xac, 0x1d, 0x3b, 0xec, 0xf0, 0x4b, 0x09, 0x75, 0xa6,
-	0x1c, 0xc2, 0x38, 0x90, 0xbf, 0x33, 0x86, 0x2f, 0x06, 0xad, 0xf2, 0xcb, 0x82, 0xb6, 0xf7, 0x53,
-	0x41, 0x73, 0x50, 0x93, 0x99, 0xa3, 0xab, 0xf8, 0xd8, 0x55, 0xa7, 0xec, 0x5a, 0x5e, 0x11, 0x1d,
-	0x7d, 0x2b, 0xa3, 0x66, 0xe1, 0xc0, 0xa4, 0x8d, 0x1a, 0x13, 0x11, 0x4d, 0x45, 0x7c, 0x91, 0x06,
-	0xf8, 0x0f, 0x42, 0x50, 0xc7, 0x2c, 0x4f, 0xd5, 0x94, 0x14, 0xb3, 0x48, 0x17, 0x35, 0x0d, 0x33,
-	0xa0, 0x44, 0xfa, 0xa8, 0x6b, 0xc0, 0x4d, 0x2a, 0x81, 0x0b, 0x08, 0x24, 0x2e, 0xe7, 0x2e, 0x3d,
-	0xe2, 0xeb, 0x8c, 0xe1, 0x0a, 0xe9, 0xa1, 0xf6, 0x44, 0x44, 0xd7, 0x9b, 0x94, 0xe3, 0x3d, 0x82,
-	0x51, 0x6b, 0xed, 0xb9, 0xa5, 0x94, 0xe1, 0x2a, 0x39, 0x40, 0xf6, 0x9a, 0x8c, 0xfd, 0xe4, 0x96,
-	0x06, 0x7e, 0xa2, 0x02, 0xad, 0x82, 0x8a, 0x6b, 0xe4, 0x4f, 0xd4, 0x5b, 0xab, 0x9b, 0xeb, 0x80,
-	0xeb, 0x64, 0x80, 0xf6, 0x0b, 0x45, 0x17, 0xe6, 0x0e, 0xe8, 0x92, 0x06, 0xf9, 0x0b, 0xf5, 0xd7,
-	0x5a, 0x51, 0x40, 0xc5, 0x9d, 0xce, 0x21, 0xd8, 0xdd, 0xa9, 0x59, 0x2c, 0x53, 0xf4, 0x34, 0x35,
-	0x42, 0xab, 0x28, 0xbc, 0x65, 0x1a, 0x2a, 0x1d, 0xb7, 0xf3, 0x4e, 0x69, 0x61, 0x26, 0x7d, 0x99,
-	0x09, 0xdc, 0x29, 0x9a, 0xc7, 0x0b, 0x08, 0x1e, 0x72, 0xa1, 0x5b, 0x34, 0x4f, 0x68, 0x08, 0x89,
-	0xc0, 0x98, 0xec, 0x23, 0x32, 0x11, 0x91, 0xf6, 0x4d, 0x37, 0x09, 0xc7, 0xbd, 0x62, 0x23, 0x67,
-	0x20, 0x31, 0xc9, 0xdb, 0x3d, 0xa6, 0xa9, 0x8c, 0xd3, 0x0c, 0x74, 0xe3, 0xfa, 0x79, 0x77, 0xf3,
-	0x9c, 0xab, 0x86, 0x9f, 0xac, 0x67, 0xb7, 0x1d, 0x36, 0xfe, 0x6f, 0xd7, 0x36, 0xcb, 0x96, 0xf8,
-	0xff, 0xb3, 0x9b, 0x0f, 0x57, 0x51, 0x2c, 0x17, 0xd9, 0xbd, 0xba, 0x96, 0xa3, 0xa9, 0x1f, 0x86,
-	0x09, 0x98, 0xd7, 0x7c, 0x71, 0x3e, 0x7f, 0x3f, 0x0a, 0xfd, 0x78, 0xa4, 0x7f, 0xce, 0xc5, 0xe8,
-	0xc7, 0xff, 0x18, 0xf7, 0x55, 0x6d, 0x39, 0xf9, 0x1e, 0x00, 0x00, 0xff, 0xff, 0xcb, 0x98, 0xbb,
-	0xac, 0x56, 0x06, 0x00, 0x00,
+	// 796 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xdf, 0x6f, 0xe3, 0x44,
+	0x10, 0xc7, 0x71, 0x93, 0x36, 0xc9, 0xe4, 0x47, 0x37, 0x1b, 0x71, 0x98, 0xe8, 0x04, 0x56, 0x85,
+	0x90, 0x75, 0x42, 0x89, 0xd4, 0x83, 0x27, 0x9e, 0xee, 0xfa, 0xeb, 0x8a, 0x1a, 0x11, 0xb9, 0x05,
+	0x21, 0x5e, 0x4e, 0x5b, 0x7b, 0x70, 0xad, 0x3a, 0xbb, 0xcb, 0xee, 0xfa, 0x20, 0x7f, 0x30, 0xcf,
+	0xfc, 0x09, 0xa0, 0x5d, 0x3b, 0xae, 0xdd, 0x96, 0x17, 0x04, 0x2f, 0x49, 0xf6, 0xf3, 0xfd, 0xce,
+	0x8e, 0x77, 0x76, 0x26, 0x86, 0xc5, 0x46, 0xc6, 0xcb, 0x1c, 0x99, 0xe2, 0xa8, 0xf4, 0x32, 0xcf,
+	0x38, 0x32, 0xf5, 0x5e, 0x61, 0xfa, 0xfe, 0x43, 0xde, 0x5e, 0x2d, 0xa4, 0x12, 0x46, 0xd0, 0x71,
+	0x0b, 0xce, 0xc7, 0x36, 0x5c, 0xea, 0xac, 0x54, 0xe7, 0xb3, 0x58, 0x6c, 0x36, 0x82, 0x2f, 0xcb,
+	0xaf, 0x12, 0x1e, 0xfd, 0xb1, 0x0f, 0xbd, 0x15, 0x6a, 0xcd, 0x52, 0xa4, 0x0b, 0xe8, 0x9a, 0xad,
+	0x44, 0xdf, 0x0b, 0xbc, 0x70, 0x72, 0x3c, 0x5f, 0xb4, 0x53, 0x54, 0xae, 0x9b, 0xad, 0xc4, 0xc8,
+	0xf9, 0xe8, 0x04, 0xf6, 0x8c, 0xf0, 0xf7, 0x02, 0x2f, 0x1c, 0x44, 0x7b, 0x46, 0x50, 0x0a, 0xdd,
+	0x5f, 0x94, 0xd8, 0xf8, 0x1d, 0x47, 0xdc, 0x6f, 0xfa, 0x12, 0x06, 0xb9, 0x10, 0x32, 0x12, 0x05,
+	0x4f, 0xfc, 0x6e, 0xe0, 0x85, 0xdd, 0xe8, 0x01, 0xd0, 0x0b, 0x98, 0x7e, 0xc8, 0xaf, 0xd6, 0x3a,
+	0x8b, 0xf0, 0x8c, 0xc7, 0x97, 0xa7, 0x3a, 0xc2, 0x5f, 0xfd, 0xfd, 0xc0, 0x0b, 0x87, 0xc7, 0x9f,
+	0xda, 0xc3, 0x2f, 0x7e, 0x7c, 0x24, 0x16, 0xa8, 0x4d, 0xf4, 0x34, 0x86, 0x7e, 0x07, 0xf4, 0x31,
+	0xd4, 0xd2, 0x3f, 0x70, 0x3b, 0xcd, 0x9f, 0xdb, 0x49, 0x4b, 0xc1, 0x35, 0x46, 0xcf, 0x44, 0xd1,
+	0xcf, 0x00, 0xee, 0xc4, 0x46, 0xac, 0x8b, 0xdb, 0x7b, 0xdc, 0xfa, 0xbd, 0xc0, 0x0b, 0x47, 0x51,
+	0x83, 0xd8, 0x23, 0xad, 0x99, 0x32, 0x6f, 0xb7, 0x06, 0xb5, 0xdf, 0x77, 0xf2, 0x03, 0xa0, 0xaf,
+	0x80, 0x20, 0x8f, 0x2f, 0x14, 0x4b, 0xce, 0x95, 0xd8, 0x7c, 0x6f, 0xee, 0x50, 0xf9, 0x03, 0x67,
+	0x7a, 0xc2, 0x2b, 0xef, 0x89, 0xd0, 0xe6, 0xc1, 0x0b, 0xb5, 0xb7, 0xc5, 0x6d, 0xd6, 0x54, 0xb1,
+	0xa4, 0xcc, 0x3a, 0x2c, 0xb3, 0xd6, 0xc0, 0xaa, 0xb1, 0xd0, 0xd5, 0x33, 0x8d, 0x4a, 0xb5, 0x06,
+	0xd4, 0x87, 0x9e, 0x36, 0x42, 0x4a, 0x4c, 0xfc, 0x71, 0xe0, 0x85, 0xfd, 0x68, 0xb7, 0xa4, 0xdf,
+	0x42, 0xdf, 0x28, 0x96, 0xf1, 0x6b, 0x34, 0xfe, 0x24, 0xe8, 0x84, 0xc3, 0xe3, 0xcf, 0x17, 0x55,
+	0x7f, 0xdc, 0x58, 0x7e, 0xc3, 0xf4, 0x7d, 0x84, 0xba, 0xc8, 0xcd, 0xe2, 0x3c, 0xcb, 0x31, 0x12,
+	0xbf, 0x45, 0x75, 0x80, 0x2d, 0x94, 0x64, 0x85, 0xc6, 0xf2, 0x72, 0x0f, 0xdd, 0xe5, 0x36, 0x08,
+	0x3d, 0x82, 0x91, 0x51, 0x59, 0x9a, 0xa2, 0x2a, 0x1d, 0xc4, 0x39, 0x5a, 0xcc, 0x7a, 0xe2, 0x3b,
+	0x8c, 0xef, 0xa5, 0xc8, 0xb8, 0xc1, 0xc4, 0x9f, 0xba, 0xe7, 0x6b, 0x31, 0xfa, 0x25, 0x4c, 0x14,
+	0xea, 0x2c, 0x29, 0x58, 0xae, 0xcb, 0x13, 0x52, 0x77, 0xc2, 0x47, 0x94, 0xce, 0xa1, 0xcf, 0xe2,
+	0xb8, 0x50, 0x2c, 0xde, 0xfa, 0xb3, 0xc0, 0x0b, 0x3b, 0x51, 0xbd, 0x3e, 0xfa, 0x73, 0x0f, 0x26,
+	0x6b, 0x85, 0x49, 0x16, 0x9b, 0xff, 0xb3, 0xdd, 0x9f, 0x6d, 0xe8, 0xee, 0x7f, 0xd6, 0xd0, 0xfb,
+	0xff, 0xaa, 0xa1, 0x03, 0x18, 0xca, 0xf2, 0xe8, 0xb6, 0x4d, 0xfd, 0x83, 0xa0, 0x13, 0x7a, 0x51,
+	0x13, 0xd1, 0xaf, 0x60, 0x8a, 0xbf, 0xcb, 0x9c, 0x65, 0x1c, 0x93, 0x73, 0x64, 0xa6, 0x50, 0xa8,
+	0xfd, 0x5e, 0xd0, 0x09, 0x07, 0xd1, 0x53, 0x81, 0x7e, 0x01, 0xe3, 0x58, 0x70, 0xa3, 0xb2, 0xdb,
+	0xc2, 0x64, 0x82, 0xdb, 0x21, 0xb0, 0x3b, 0xb6, 0xe1, 0xab, 0xbf, 0x3a, 0x30, 0x6c, 0x14, 0x91,
+	0x8e, 0x61, 0xb0, 0xd2, 0xe9, 0x5a, 0x67, 0x67, 0x3c, 0x26, 0x1f, 0x51, 0x0a, 0x93, 0x72, 0xf9,
+	0xc6, 0x76, 0x98, 0x65, 0x1e, 0x3d, 0x84, 0x61, 0xc9, 0x4a, 0xb0, 0x47, 0x67, 0x70, 0x58, 0x82,
+	0x4b, 0x6e, 0x50, 0x69, 0x8c, 0x0d, 0xe9, 0x54, 0x2e, 0xd7, 0x9e, 0xef, 0x0a, 0x49, 0xba, 0x74,
+	0x0a, 0xe3, 0x95, 0x4e, 0xdf, 0xd5, 0x13, 0x4a, 0xf6, 0x29, 0x81, 0xd1, 0xce, 0x73, 0x25, 0x84,
+	0x24, 0x07, 0xf4, 0x25, 0xf8, 0x3b, 0x72, 0xc2, 0xf2, 0x2b, 0x11, 0xb3, 0xdc, 0x0e, 0xa3, 0x1d,
+	0x32, 0xd2, 0xa3, 0x1f, 0xc3, 0x74, 0xa7, 0xd6, 0xa3, 0x4c, 0xfa, 0x74, 0x0e, 0x2f, 0x1a, 0x41,
+	0x67, 0x3c, 0xae, 0x43, 0x06, 0xf4, 0x13, 0x98, 0xed, 0xb4, 0xa6, 0x00, 0xcd, 0x4c, 0xa7, 0x18,
+	0xb7, 0x33, 0x0d, 0x9b, 0x61, 0x96, 0xbe, 0xe1, 0xa5, 0x30, 0x6a, 0x0a, 0x3f, 0x48, 0x07, 0xad,
+	0x4e, 0xc6, 0x55, 0xa5, 0x9c, 0x70, 0x6d, 0x98, 0x29, 0x34, 0x99, 0x34, 0xcd, 0x27, 0x76, 0x54,
+	0x2a, 0xe1, 0xb0, 0x69, 0x5e, 0x89, 0x04, 0x73, 0x4d, 0x08, 0x7d, 0x01, 0x74, 0xa5, 0x53, 0xe7,
+	0x5b, 0xd7, 0xd3, 0x49, 0xa6, 0xcd, 0x42, 0x5e, 0xa3, 0x21, 0xb4, 0x2a, 0xf7, 0x89, 0xe0, 0x26,
+	0xe3, 0x05, 0xba, 0xc2, 0xcd, 0xaa, 0xea, 0x56, 0xb3, 0x63, 0x0b, 0xfe, 0x7a, 0x77, 0x77, 0x0f,
+	0x0d, 0x44, 0xbe, 0x6e, 0xdb, 0xae, 0x8b, 0x0d, 0xf9, 0xe6, 0xed, 0xe5, 0xcf, 0x17, 0x69, 0x66,
+	0xee, 0x8a, 0x5b, 0xfb, 0x97, 0xb2, 0x5c, 0xb3, 0x24, 0xc9, 0xb1, 0xfc, 0xac, 0x16, 0xa7, 0x37,
+	0x3f, 0x2d, 0x13, 0x96, 0x2d, 0xdd, 0xab, 0x48, 0x2f, 0xff, 0xf9, 0x6d, 0x77, 0x7b, 0xe0, 0x2c,
+	0xaf, 0xff, 0x1e, 0x00, 0x3a, 0xcd, 0x6b, 0x0c, 0x12, 0x07, 0x00, 0x00,
 }
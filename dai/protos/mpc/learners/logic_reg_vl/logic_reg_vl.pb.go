@@ -22,9 +22,9 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-//MessageType defines the type of message with which communicate with nodes in cluster,
+// MessageType defines the type of message with which communicate with nodes in cluster,
 // and in some way it indicates the phase of learning
-//Some types are for local message which is not passed between nodes
+// Some types are for local message which is not passed between nodes
 type MessageType int32
 
 const (
@@ -130,6 +130,8 @@ type Message struct {
 	TrainSet             []*common.TrainTaskResult_FileRow `protobuf:"bytes,14,rep,name=trainSet,proto3" json:"trainSet,omitempty"`
 	PauseRound           uint64                            `protobuf:"varint,15,opt,name=pauseRound,proto3" json:"pauseRound,omitempty"`
 	TriggerRound         uint64                            `protobuf:"varint,16,opt,name=triggerRound,proto3" json:"triggerRound,omitempty"`
+	Checkpointed         bool                              `protobuf:"varint,17,opt,name=checkpointed,proto3" json:"checkpointed,omitempty"`
+	Accuracy             int64                             `protobuf:"varint,18,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
 	XXX_unrecognized     []byte                            `json:"-"`
 	XXX_sizecache        int32                             `json:"-"`
@@ -272,6 +274,20 @@ func (m *Message) GetTriggerRound() uint64 {
 	return 0
 }
 
+func (m *Message) GetCheckpointed() bool {
+	if m != nil {
+		return m.Checkpointed
+	}
+	return false
+}
+
+func (m *Message) GetAccuracy() int64 {
+	if m != nil {
+		return m.Accuracy
+	}
+	return 0
+}
+
 type PredictMessage struct {
 	Type                 MessageType                `protobuf:"varint,1,opt,name=type,proto3,enum=logic_reg_vl.MessageType" json:"type,omitempty"`
 	To                   string                     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
@@ -279,6 +295,8 @@ type PredictMessage struct {
 	VlLPsiReEncIDsReq    *mpc.VLPsiReEncIDsRequest  `protobuf:"bytes,4,opt,name=vlLPsiReEncIDsReq,proto3" json:"vlLPsiReEncIDsReq,omitempty"`
 	VlLPsiReEncIDsResp   *mpc.VLPsiReEncIDsResponse `protobuf:"bytes,5,opt,name=vlLPsiReEncIDsResp,proto3" json:"vlLPsiReEncIDsResp,omitempty"`
 	PredictPart          []float64                  `protobuf:"fixed64,6,rep,packed,name=predictPart,proto3" json:"predictPart,omitempty"`
+	ExplainedFeatures    []string                   `protobuf:"bytes,7,rep,name=explainedFeatures,proto3" json:"explainedFeatures,omitempty"`
+	Contributions        []float64                  `protobuf:"fixed64,8,rep,packed,name=contributions,proto3" json:"contributions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
 	XXX_unrecognized     []byte                     `json:"-"`
 	XXX_sizecache        int32                      `json:"-"`
@@ -351,6 +369,20 @@ func (m *PredictMessage) GetPredictPart() []float64 {
 	return nil
 }
 
+func (m *PredictMessage) GetExplainedFeatures() []string {
+	if m != nil {
+		return m.ExplainedFeatures
+	}
+	return nil
+}
+
+func (m *PredictMessage) GetContributions() []float64 {
+	if m != nil {
+		return m.Contributions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("logic_reg_vl.MessageType", MessageType_name, MessageType_value)
 	proto.RegisterType((*Message)(nil), "logic_reg_vl.Message")
@@ -362,50 +394,55 @@ func init() {
 }
 
 var fileDescriptor_cba41b5f67b9a4c9 = []byte{
-	// 716 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0x4f, 0x6f, 0xda, 0x4a,
-	0x14, 0xc5, 0x9f, 0xc1, 0xe1, 0xcf, 0xf0, 0x6f, 0x18, 0xf4, 0xf2, 0x1c, 0x14, 0xbd, 0x67, 0x65,
-	0x65, 0x45, 0xaf, 0x20, 0x25, 0xed, 0xaa, 0xab, 0x84, 0x84, 0x24, 0x55, 0x50, 0x91, 0x43, 0xab,
-	0xaa, 0x9b, 0xc8, 0xb1, 0x6f, 0x8d, 0x15, 0xe3, 0x99, 0x7a, 0xc6, 0xa9, 0x58, 0xf6, 0x6b, 0xf4,
-	0xb3, 0x76, 0x51, 0xcd, 0x8c, 0x01, 0x93, 0xa4, 0x9b, 0xaa, 0xdd, 0x00, 0xf3, 0x3b, 0xe7, 0xfa,
-	0xe2, 0x7b, 0x8f, 0x01, 0xfd, 0xbf, 0x60, 0xfe, 0x30, 0x06, 0x2f, 0x4d, 0x20, 0xe5, 0xc3, 0x98,
-	0x86, 0x91, 0x7f, 0x9b, 0x42, 0x78, 0xfb, 0x10, 0x6f, 0x1d, 0x06, 0x2c, 0xa5, 0x82, 0x92, 0x66,
-	0x91, 0xf5, 0x5b, 0xb2, 0x96, 0xf1, 0x48, 0x8b, 0xfd, 0x9e, 0x4f, 0x17, 0x0b, 0x9a, 0x0c, 0xf5,
-	0x9b, 0x86, 0x07, 0xdf, 0x4d, 0x54, 0x9d, 0x00, 0xe7, 0x5e, 0x08, 0xe4, 0x05, 0x32, 0xc5, 0x92,
-	0x81, 0x65, 0xd8, 0x86, 0xd3, 0x3e, 0xda, 0x1b, 0x6c, 0x35, 0xc8, 0x4d, 0xb3, 0x25, 0x03, 0x57,
-	0xd9, 0x48, 0x1b, 0x95, 0x04, 0xb5, 0x4a, 0xb6, 0xe1, 0xd4, 0xdd, 0x92, 0xa0, 0x84, 0x20, 0xf3,
-	0x53, 0x4a, 0x17, 0x56, 0x59, 0x11, 0xf5, 0x99, 0xec, 0xa3, 0x7a, 0x4c, 0x29, 0x73, 0x69, 0x96,
-	0x04, 0x96, 0x69, 0x1b, 0x8e, 0xe9, 0x6e, 0x00, 0xb9, 0x40, 0xdd, 0x87, 0xf8, 0x7a, 0xca, 0x23,
-	0x17, 0xce, 0x13, 0xff, 0xea, 0x8c, 0xbb, 0xf0, 0xd9, 0xda, 0xb1, 0x0d, 0xa7, 0x71, 0xb4, 0x37,
-	0x58, 0x30, 0x7f, 0xf0, 0xfe, 0x91, 0x98, 0x01, 0x17, 0xee, 0xd3, 0x1a, 0xf2, 0x06, 0x91, 0xc7,
-	0x90, 0x33, 0xab, 0xa2, 0xae, 0xd4, 0x7f, 0xee, 0x4a, 0x9c, 0xd1, 0x84, 0x83, 0xfb, 0x4c, 0x15,
-	0xf9, 0x17, 0xa1, 0x39, 0x5d, 0xd0, 0x69, 0x76, 0x77, 0x0f, 0x4b, 0xab, 0x6a, 0x1b, 0x4e, 0xd3,
-	0x2d, 0x10, 0x79, 0x4b, 0x53, 0x2f, 0x15, 0xa7, 0x4b, 0x01, 0xdc, 0xaa, 0x29, 0x79, 0x03, 0xc8,
-	0x21, 0xc2, 0x90, 0xf8, 0x17, 0xa9, 0x17, 0x8c, 0x53, 0xba, 0x78, 0x2b, 0xe6, 0x90, 0x5a, 0x75,
-	0x65, 0x7a, 0xc2, 0x73, 0xef, 0x88, 0x72, 0xb1, 0xf1, 0xa2, 0xb5, 0x77, 0x8b, 0xcb, 0xae, 0x61,
-	0xea, 0x05, 0xba, 0x6b, 0x43, 0x77, 0x5d, 0x03, 0xa9, 0xfa, 0x94, 0xe7, 0xdf, 0xa9, 0xa9, 0xd5,
-	0x35, 0x20, 0x16, 0xaa, 0x72, 0x41, 0x19, 0x83, 0xc0, 0x6a, 0xd9, 0x86, 0x53, 0x73, 0x57, 0x47,
-	0xf2, 0x1a, 0xd5, 0x44, 0xea, 0x45, 0xc9, 0x0d, 0x08, 0xab, 0x6d, 0x97, 0x9d, 0xc6, 0xd1, 0x7f,
-	0x83, 0x3c, 0x1e, 0x33, 0xc9, 0x67, 0x1e, 0xbf, 0x77, 0x81, 0x67, 0xb1, 0x18, 0x8c, 0xa3, 0x18,
-	0x5c, 0xfa, 0xc5, 0x5d, 0x17, 0xc8, 0x41, 0x31, 0x2f, 0xe3, 0xa0, 0x97, 0xdb, 0x51, 0xcb, 0x2d,
-	0x10, 0x72, 0x80, 0x9a, 0x22, 0x8d, 0xc2, 0x10, 0x52, 0xed, 0xc0, 0xca, 0xb1, 0xc5, 0x0e, 0xbe,
-	0x95, 0x50, 0x7b, 0x9a, 0x42, 0x10, 0xf9, 0xe2, 0x0f, 0xa6, 0xf0, 0xd9, 0x9c, 0x99, 0xbf, 0x2d,
-	0x67, 0x3b, 0xbf, 0x94, 0x33, 0x1b, 0x35, 0x98, 0xbe, 0x73, 0x99, 0x1e, 0xab, 0x62, 0x97, 0x1d,
-	0xc3, 0x2d, 0xa2, 0xc3, 0xaf, 0x26, 0x6a, 0x14, 0x6e, 0x98, 0xb4, 0x50, 0x7d, 0xc2, 0xc3, 0x29,
-	0x8f, 0xce, 0x13, 0x1f, 0xff, 0x45, 0x08, 0x6a, 0xeb, 0xe3, 0x89, 0x5c, 0x92, 0x64, 0x06, 0xe9,
-	0xa0, 0x86, 0x66, 0x1a, 0x94, 0x48, 0x0f, 0x75, 0x34, 0xb8, 0x4a, 0x04, 0xa4, 0x1c, 0x7c, 0x81,
-	0xcb, 0xb9, 0x4b, 0x6d, 0xf8, 0x32, 0x63, 0xd8, 0x24, 0x5d, 0xd4, 0x9a, 0xf0, 0xf0, 0x72, 0x1d,
-	0x72, 0xbc, 0x43, 0x30, 0x6a, 0xae, 0x3c, 0xd7, 0x94, 0x32, 0x5c, 0x21, 0xfb, 0xc8, 0x5a, 0x91,
-	0x91, 0x17, 0x5f, 0x53, 0xdf, 0x8b, 0x65, 0x9e, 0x65, 0x4e, 0x71, 0x95, 0xfc, 0x8d, 0xba, 0x2b,
-	0x75, 0xfd, 0x34, 0xe0, 0x1a, 0xe9, 0xa3, 0xdd, 0x42, 0xd1, 0xb9, 0x7e, 0x04, 0x54, 0x49, 0x9d,
-	0xfc, 0x83, 0x7a, 0x2b, 0xad, 0x28, 0xa0, 0x62, 0xa7, 0x33, 0xf0, 0xb7, 0x3b, 0x35, 0x8a, 0x65,
-	0x92, 0x9e, 0x24, 0x5a, 0x68, 0x16, 0x85, 0x77, 0x4c, 0x41, 0xa9, 0xe3, 0x56, 0x3e, 0x29, 0x25,
-	0xdc, 0x08, 0x4f, 0x64, 0x1c, 0xb7, 0x8b, 0xe6, 0xd1, 0x1c, 0xfc, 0xfb, 0x5c, 0xe8, 0x14, 0xcd,
-	0x13, 0x1a, 0x40, 0xcc, 0x31, 0x26, 0xbb, 0x88, 0x4c, 0x78, 0xa8, 0x7c, 0xd3, 0x75, 0xc0, 0x71,
-	0xb7, 0x38, 0xc8, 0x1b, 0x10, 0x98, 0xe4, 0xe3, 0x1e, 0xd1, 0x44, 0x44, 0x49, 0x06, 0x6a, 0x70,
-	0xbd, 0x7c, 0xba, 0x79, 0xcc, 0xe5, 0xc0, 0x8f, 0x57, 0xbb, 0xdb, 0x2c, 0x1b, 0xbf, 0x5c, 0xad,
-	0x4a, 0xb3, 0x71, 0x94, 0x78, 0x31, 0x7e, 0x75, 0x7a, 0xf9, 0x71, 0x1c, 0x46, 0x62, 0x9e, 0xdd,
-	0xc9, 0xe7, 0x72, 0x38, 0xf5, 0x82, 0x20, 0x06, 0xfd, 0x9a, 0x1f, 0xce, 0x66, 0x1f, 0x86, 0x81,
-	0x17, 0x0d, 0xd5, 0xcf, 0x39, 0x1f, 0xfe, 0xf4, 0xef, 0xe2, 0xae, 0xa2, 0x1c, 0xc7, 0x3f, 0x02,
-	0x00, 0x00, 0xff, 0xff, 0x3f, 0x6a, 0x0a, 0xa0, 0x52, 0x06, 0x00, 0x00,
+	// 787 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xdf, 0x6f, 0xe3, 0x44,
+	0x10, 0xc7, 0x71, 0x92, 0x36, 0xc9, 0xe4, 0x47, 0x37, 0x5b, 0x71, 0xf8, 0xa2, 0x13, 0x58, 0x15,
+	0x0f, 0xd6, 0xe9, 0x48, 0xa4, 0x1e, 0x3c, 0xf1, 0x74, 0xd7, 0x36, 0xd7, 0x43, 0x8d, 0x88, 0xdc,
+	0x82, 0x10, 0x2f, 0xa7, 0xed, 0x7a, 0x70, 0xad, 0x3a, 0xbb, 0xcb, 0xee, 0xfa, 0x20, 0x8f, 0xfc,
+	0x5f, 0xfc, 0x4d, 0xfc, 0x0d, 0x68, 0x6d, 0xc7, 0x71, 0xda, 0xf2, 0x82, 0xe0, 0x25, 0xc9, 0x7c,
+	0xbe, 0xdf, 0xf1, 0xd8, 0xb3, 0x33, 0x31, 0xbc, 0x5a, 0x2b, 0x3e, 0xcf, 0x90, 0x69, 0x81, 0xda,
+	0xcc, 0x33, 0x99, 0xa4, 0xfc, 0x83, 0xc6, 0xe4, 0xc3, 0xc7, 0x6c, 0x2f, 0x98, 0x29, 0x2d, 0xad,
+	0xa4, 0xc3, 0x26, 0x9b, 0x8e, 0x5c, 0xae, 0x32, 0x69, 0x29, 0x4e, 0x8f, 0xb9, 0x5c, 0xaf, 0xa5,
+	0x98, 0x97, 0x5f, 0x25, 0x3c, 0xf9, 0xf3, 0x00, 0xba, 0x4b, 0x34, 0x86, 0x25, 0x48, 0xbf, 0x82,
+	0x8e, 0xdd, 0x28, 0xf4, 0xbd, 0xc0, 0x0b, 0xc7, 0xa7, 0xcf, 0x67, 0x7b, 0x05, 0x2a, 0xd3, 0xcd,
+	0x46, 0x61, 0x54, 0xd8, 0xe8, 0x18, 0x5a, 0x56, 0xfa, 0xad, 0xc0, 0x0b, 0xfb, 0x51, 0xcb, 0x4a,
+	0x4a, 0xa1, 0xf3, 0x8b, 0x96, 0x6b, 0xbf, 0x5d, 0x90, 0xe2, 0x37, 0x7d, 0x01, 0xfd, 0x4c, 0x4a,
+	0x15, 0xc9, 0x5c, 0xc4, 0x7e, 0x27, 0xf0, 0xc2, 0x4e, 0xb4, 0x03, 0xf4, 0x1d, 0x4c, 0x3e, 0x66,
+	0x57, 0x2b, 0x93, 0x46, 0x78, 0x21, 0xf8, 0xfb, 0x73, 0x13, 0xe1, 0xaf, 0xfe, 0x41, 0xe0, 0x85,
+	0x83, 0xd3, 0xe7, 0xb3, 0xb5, 0xe2, 0xb3, 0x1f, 0x1f, 0x88, 0x39, 0x1a, 0x1b, 0x3d, 0xce, 0xa1,
+	0xdf, 0x01, 0x7d, 0x08, 0x8d, 0xf2, 0x0f, 0x8b, 0x2b, 0x4d, 0x9f, 0xba, 0x92, 0x51, 0x52, 0x18,
+	0x8c, 0x9e, 0xc8, 0xa2, 0x9f, 0x03, 0xdc, 0xc9, 0xb5, 0x5c, 0xe5, 0xb7, 0xf7, 0xb8, 0xf1, 0xbb,
+	0x81, 0x17, 0x0e, 0xa3, 0x06, 0x71, 0x8f, 0xb4, 0x62, 0xda, 0xbe, 0xdd, 0x58, 0x34, 0x7e, 0xaf,
+	0x90, 0x77, 0x80, 0xbe, 0x04, 0x82, 0x82, 0xbf, 0xd3, 0x2c, 0x5e, 0x68, 0xb9, 0xfe, 0xde, 0xde,
+	0xa1, 0xf6, 0xfb, 0x85, 0xe9, 0x11, 0xaf, 0xbc, 0x67, 0xd2, 0xd8, 0x9d, 0x17, 0x6a, 0xef, 0x1e,
+	0x77, 0x55, 0x13, 0xcd, 0xe2, 0xb2, 0xea, 0xa0, 0xac, 0x5a, 0x03, 0xa7, 0x72, 0x69, 0xaa, 0x7b,
+	0x1a, 0x96, 0x6a, 0x0d, 0xa8, 0x0f, 0x5d, 0x63, 0xa5, 0x52, 0x18, 0xfb, 0xa3, 0xc0, 0x0b, 0x7b,
+	0xd1, 0x36, 0xa4, 0xdf, 0x42, 0xcf, 0x6a, 0x96, 0x8a, 0x6b, 0xb4, 0xfe, 0x38, 0x68, 0x87, 0x83,
+	0xd3, 0x2f, 0x66, 0xd5, 0x78, 0xdc, 0x38, 0x7e, 0xc3, 0xcc, 0x7d, 0x84, 0x26, 0xcf, 0xec, 0x6c,
+	0x91, 0x66, 0x18, 0xc9, 0xdf, 0xa2, 0x3a, 0xc1, 0x35, 0x4a, 0xb1, 0xdc, 0x60, 0x79, 0xb8, 0x47,
+	0xc5, 0xe1, 0x36, 0x08, 0x3d, 0x81, 0xa1, 0xd5, 0x69, 0x92, 0xa0, 0x2e, 0x1d, 0xa4, 0x70, 0xec,
+	0x31, 0xe7, 0xe1, 0x77, 0xc8, 0xef, 0x95, 0x4c, 0x85, 0xc5, 0xd8, 0x9f, 0x14, 0xf7, 0xb7, 0xc7,
+	0xe8, 0x14, 0x7a, 0x8c, 0xf3, 0x5c, 0x33, 0xbe, 0xf1, 0x69, 0xe0, 0x85, 0xed, 0xa8, 0x8e, 0x4f,
+	0xfe, 0x6a, 0xc1, 0x78, 0xa5, 0x31, 0x4e, 0xb9, 0xfd, 0x1f, 0xa7, 0xf8, 0xc9, 0x39, 0xed, 0xfc,
+	0x67, 0x73, 0x7a, 0xf0, 0xaf, 0xe6, 0x34, 0x80, 0x81, 0x2a, 0x9f, 0xdc, 0x4d, 0x9f, 0x7f, 0x18,
+	0xb4, 0x43, 0x2f, 0x6a, 0x22, 0xfa, 0x0a, 0x26, 0xf8, 0xbb, 0xca, 0x58, 0x2a, 0x30, 0x5e, 0x20,
+	0xb3, 0xb9, 0x46, 0xe3, 0x77, 0x83, 0x76, 0xd8, 0x8f, 0x1e, 0x0b, 0xf4, 0x4b, 0x18, 0x71, 0x29,
+	0xac, 0x4e, 0x6f, 0x73, 0x9b, 0x4a, 0xe1, 0x66, 0xdb, 0x5d, 0x71, 0x1f, 0xbe, 0xfc, 0xa3, 0x03,
+	0x83, 0x46, 0x13, 0xe9, 0x08, 0xfa, 0x4b, 0x93, 0xac, 0x4c, 0x7a, 0x21, 0x38, 0xf9, 0x84, 0x52,
+	0x18, 0x97, 0xe1, 0x1b, 0x37, 0x38, 0x8e, 0x79, 0xf4, 0x08, 0x06, 0x25, 0x2b, 0x41, 0x8b, 0x1e,
+	0xc3, 0x51, 0x09, 0xde, 0x0b, 0x8b, 0xda, 0x20, 0xb7, 0xa4, 0x5d, 0xb9, 0x8a, 0xa9, 0xbb, 0xcc,
+	0x15, 0xe9, 0xd0, 0x09, 0x8c, 0x96, 0x26, 0xb9, 0xac, 0x17, 0x8f, 0x1c, 0x50, 0x02, 0xc3, 0xad,
+	0xe7, 0x4a, 0x4a, 0x45, 0x0e, 0xe9, 0x0b, 0xf0, 0xb7, 0xe4, 0x8c, 0x65, 0x57, 0x92, 0xb3, 0xcc,
+	0xed, 0x98, 0xdb, 0x1d, 0xd2, 0xa5, 0x9f, 0xc2, 0x64, 0xab, 0xd6, 0x1b, 0x4a, 0x7a, 0x74, 0x0a,
+	0xcf, 0x1a, 0x49, 0x17, 0x82, 0xd7, 0x29, 0x7d, 0xfa, 0x19, 0x1c, 0x6f, 0xb5, 0xa6, 0x00, 0xcd,
+	0x4a, 0xe7, 0xc8, 0xf7, 0x2b, 0x0d, 0x9a, 0x69, 0x8e, 0xbe, 0x11, 0xa5, 0x30, 0x6c, 0x0a, 0x3f,
+	0xa8, 0x02, 0x3a, 0x9d, 0x8c, 0xaa, 0x4e, 0x15, 0xc2, 0xb5, 0x65, 0x36, 0x37, 0x64, 0xdc, 0x34,
+	0x9f, 0xb9, 0x0d, 0xa8, 0x84, 0xa3, 0xa6, 0x79, 0x29, 0x63, 0xcc, 0x0c, 0x21, 0xf4, 0x19, 0xd0,
+	0xa5, 0x49, 0x0a, 0xdf, 0xaa, 0x5e, 0x3a, 0x32, 0x69, 0x36, 0xf2, 0x1a, 0x2d, 0xa1, 0x55, 0xbb,
+	0xcf, 0xa4, 0xb0, 0xa9, 0xc8, 0xb1, 0x68, 0xdc, 0x71, 0xd5, 0xdd, 0x6a, 0x75, 0x5c, 0xc3, 0x5f,
+	0x6f, 0xcf, 0x6e, 0x37, 0x40, 0xe4, 0xeb, 0xed, 0x51, 0x95, 0x6c, 0x91, 0x0a, 0x96, 0x91, 0x6f,
+	0xde, 0x5e, 0xfe, 0xbc, 0x48, 0x52, 0x7b, 0x97, 0xdf, 0xba, 0xff, 0x8a, 0xf9, 0x8a, 0xc5, 0x71,
+	0x86, 0xe5, 0x67, 0x15, 0x9c, 0xdf, 0xfc, 0x34, 0x8f, 0x59, 0x3a, 0x2f, 0x5e, 0x31, 0x66, 0xfe,
+	0x8f, 0xaf, 0xb0, 0xdb, 0xc3, 0xc2, 0xf1, 0xfa, 0xef, 0x01, 0x00, 0xe7, 0xe8, 0x45, 0x80, 0xe6,
+	0x06, 0x00, 0x00,
 }
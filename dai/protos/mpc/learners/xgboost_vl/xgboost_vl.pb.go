@@ -0,0 +1,606 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: mpc/learners/xgboost_vl/xgboost_vl.proto
+
+package xgboost_vl
+
+import (
+	fmt "fmt"
+	mpc "github.com/PaddlePaddle/PaddleDTX/dai/protos/mpc"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// MessageType defines the type of message with which communicate with nodes in cluster,
+// and in some way it indicates the phase of learning
+// Some types are for local message which is not passed between nodes
+type MessageType int32
+
+const (
+	MessageType_MsgPsiEnc          MessageType = 0
+	MessageType_MsgPsiAskReEnc     MessageType = 1
+	MessageType_MsgPsiReEnc        MessageType = 2
+	MessageType_MsgPsiIntersect    MessageType = 3
+	MessageType_MsgTrainHup        MessageType = 4
+	MessageType_MsgHomoPubkey      MessageType = 5
+	MessageType_MsgTrainBoost      MessageType = 6
+	MessageType_MsgTrainGradHess   MessageType = 7
+	MessageType_MsgTrainHistReq    MessageType = 8
+	MessageType_MsgTrainHistograms MessageType = 9
+	MessageType_MsgTrainFindSplits MessageType = 10
+	MessageType_MsgTrainSplit      MessageType = 11
+	MessageType_MsgTrainStatus     MessageType = 12
+	MessageType_MsgTrainModels     MessageType = 13
+	MessageType_MsgPredictHup      MessageType = 51
+	MessageType_MsgPredictPart     MessageType = 52
+	MessageType_MsgPredictFinal    MessageType = 53
+)
+
+var MessageType_name = map[int32]string{
+	0:  "MsgPsiEnc",
+	1:  "MsgPsiAskReEnc",
+	2:  "MsgPsiReEnc",
+	3:  "MsgPsiIntersect",
+	4:  "MsgTrainHup",
+	5:  "MsgHomoPubkey",
+	6:  "MsgTrainBoost",
+	7:  "MsgTrainGradHess",
+	8:  "MsgTrainHistReq",
+	9:  "MsgTrainHistograms",
+	10: "MsgTrainFindSplits",
+	11: "MsgTrainSplit",
+	12: "MsgTrainStatus",
+	13: "MsgTrainModels",
+	51: "MsgPredictHup",
+	52: "MsgPredictPart",
+	53: "MsgPredictFinal",
+}
+
+var MessageType_value = map[string]int32{
+	"MsgPsiEnc":          0,
+	"MsgPsiAskReEnc":     1,
+	"MsgPsiReEnc":        2,
+	"MsgPsiIntersect":    3,
+	"MsgTrainHup":        4,
+	"MsgHomoPubkey":      5,
+	"MsgTrainBoost":      6,
+	"MsgTrainGradHess":   7,
+	"MsgTrainHistReq":    8,
+	"MsgTrainHistograms": 9,
+	"MsgTrainFindSplits": 10,
+	"MsgTrainSplit":      11,
+	"MsgTrainStatus":     12,
+	"MsgTrainModels":     13,
+	"MsgPredictHup":      51,
+	"MsgPredictPart":     52,
+	"MsgPredictFinal":    53,
+}
+
+func (x MessageType) String() string {
+	return proto.EnumName(MessageType_name, int32(x))
+}
+
+func (MessageType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{0}
+}
+
+// NodeSamples lists the indexes of aligned samples which fall into a node
+type NodeSamples struct {
+	NodeID               int32    `protobuf:"varint,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Samples              []int32  `protobuf:"varint,2,rep,packed,name=samples,proto3" json:"samples,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeSamples) Reset()         { *m = NodeSamples{} }
+func (m *NodeSamples) String() string { return proto.CompactTextString(m) }
+func (*NodeSamples) ProtoMessage()    {}
+func (*NodeSamples) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{0}
+}
+
+func (m *NodeSamples) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeSamples.Unmarshal(m, b)
+}
+func (m *NodeSamples) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeSamples.Marshal(b, m, deterministic)
+}
+func (m *NodeSamples) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeSamples.Merge(m, src)
+}
+func (m *NodeSamples) XXX_Size() int {
+	return xxx_messageInfo_NodeSamples.Size(m)
+}
+func (m *NodeSamples) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeSamples.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeSamples proto.InternalMessageInfo
+
+func (m *NodeSamples) GetNodeID() int32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *NodeSamples) GetSamples() []int32 {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+// FeatureHistogram contains the encrypted sums of gradients and hessians in each bin of a feature
+type FeatureHistogram struct {
+	GradSums             [][]byte `protobuf:"bytes,1,rep,name=gradSums,proto3" json:"gradSums,omitempty"`
+	HessSums             [][]byte `protobuf:"bytes,2,rep,name=hessSums,proto3" json:"hessSums,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeatureHistogram) Reset()         { *m = FeatureHistogram{} }
+func (m *FeatureHistogram) String() string { return proto.CompactTextString(m) }
+func (*FeatureHistogram) ProtoMessage()    {}
+func (*FeatureHistogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{1}
+}
+
+func (m *FeatureHistogram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureHistogram.Unmarshal(m, b)
+}
+func (m *FeatureHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureHistogram.Marshal(b, m, deterministic)
+}
+func (m *FeatureHistogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureHistogram.Merge(m, src)
+}
+func (m *FeatureHistogram) XXX_Size() int {
+	return xxx_messageInfo_FeatureHistogram.Size(m)
+}
+func (m *FeatureHistogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureHistogram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureHistogram proto.InternalMessageInfo
+
+func (m *FeatureHistogram) GetGradSums() [][]byte {
+	if m != nil {
+		return m.GradSums
+	}
+	return nil
+}
+
+func (m *FeatureHistogram) GetHessSums() [][]byte {
+	if m != nil {
+		return m.HessSums
+	}
+	return nil
+}
+
+// NodeHistogram contains the histograms of all local features for a node
+type NodeHistogram struct {
+	NodeID               int32               `protobuf:"varint,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Features             []*FeatureHistogram `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *NodeHistogram) Reset()         { *m = NodeHistogram{} }
+func (m *NodeHistogram) String() string { return proto.CompactTextString(m) }
+func (*NodeHistogram) ProtoMessage()    {}
+func (*NodeHistogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{2}
+}
+
+func (m *NodeHistogram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeHistogram.Unmarshal(m, b)
+}
+func (m *NodeHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeHistogram.Marshal(b, m, deterministic)
+}
+func (m *NodeHistogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeHistogram.Merge(m, src)
+}
+func (m *NodeHistogram) XXX_Size() int {
+	return xxx_messageInfo_NodeHistogram.Size(m)
+}
+func (m *NodeHistogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeHistogram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeHistogram proto.InternalMessageInfo
+
+func (m *NodeHistogram) GetNodeID() int32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *NodeHistogram) GetFeatures() []*FeatureHistogram {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+type Message struct {
+	Type                 MessageType                `protobuf:"varint,1,opt,name=type,proto3,enum=xgboost_vl.MessageType" json:"type,omitempty"`
+	To                   string                     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	From                 string                     `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	VlLPsiReEncIDsReq    *mpc.VLPsiReEncIDsRequest  `protobuf:"bytes,4,opt,name=vlLPsiReEncIDsReq,proto3" json:"vlLPsiReEncIDsReq,omitempty"`
+	VlLPsiReEncIDsResp   *mpc.VLPsiReEncIDsResponse `protobuf:"bytes,5,opt,name=vlLPsiReEncIDsResp,proto3" json:"vlLPsiReEncIDsResp,omitempty"`
+	HomoPubkey           []byte                     `protobuf:"bytes,6,opt,name=homoPubkey,proto3" json:"homoPubkey,omitempty"`
+	Tree                 uint64                     `protobuf:"varint,7,opt,name=tree,proto3" json:"tree,omitempty"`
+	Depth                uint64                     `protobuf:"varint,8,opt,name=depth,proto3" json:"depth,omitempty"`
+	EncGrads             [][]byte                   `protobuf:"bytes,9,rep,name=encGrads,proto3" json:"encGrads,omitempty"`
+	EncHess              [][]byte                   `protobuf:"bytes,10,rep,name=encHess,proto3" json:"encHess,omitempty"`
+	Nodes                []*NodeSamples             `protobuf:"bytes,11,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Histograms           []*NodeHistogram           `protobuf:"bytes,12,rep,name=histograms,proto3" json:"histograms,omitempty"`
+	NodeID               int32                      `protobuf:"varint,13,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	FeatureIdx           int32                      `protobuf:"varint,14,opt,name=featureIdx,proto3" json:"featureIdx,omitempty"`
+	Bin                  int32                      `protobuf:"varint,15,opt,name=bin,proto3" json:"bin,omitempty"`
+	LeftSamples          []int32                    `protobuf:"varint,16,rep,packed,name=leftSamples,proto3" json:"leftSamples,omitempty"`
+	Stopped              bool                       `protobuf:"varint,17,opt,name=stopped,proto3" json:"stopped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{3}
+}
+
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_MsgPsiEnc
+}
+
+func (m *Message) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *Message) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Message) GetVlLPsiReEncIDsReq() *mpc.VLPsiReEncIDsRequest {
+	if m != nil {
+		return m.VlLPsiReEncIDsReq
+	}
+	return nil
+}
+
+func (m *Message) GetVlLPsiReEncIDsResp() *mpc.VLPsiReEncIDsResponse {
+	if m != nil {
+		return m.VlLPsiReEncIDsResp
+	}
+	return nil
+}
+
+func (m *Message) GetHomoPubkey() []byte {
+	if m != nil {
+		return m.HomoPubkey
+	}
+	return nil
+}
+
+func (m *Message) GetTree() uint64 {
+	if m != nil {
+		return m.Tree
+	}
+	return 0
+}
+
+func (m *Message) GetDepth() uint64 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *Message) GetEncGrads() [][]byte {
+	if m != nil {
+		return m.EncGrads
+	}
+	return nil
+}
+
+func (m *Message) GetEncHess() [][]byte {
+	if m != nil {
+		return m.EncHess
+	}
+	return nil
+}
+
+func (m *Message) GetNodes() []*NodeSamples {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *Message) GetHistograms() []*NodeHistogram {
+	if m != nil {
+		return m.Histograms
+	}
+	return nil
+}
+
+func (m *Message) GetNodeID() int32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *Message) GetFeatureIdx() int32 {
+	if m != nil {
+		return m.FeatureIdx
+	}
+	return 0
+}
+
+func (m *Message) GetBin() int32 {
+	if m != nil {
+		return m.Bin
+	}
+	return 0
+}
+
+func (m *Message) GetLeftSamples() []int32 {
+	if m != nil {
+		return m.LeftSamples
+	}
+	return nil
+}
+
+func (m *Message) GetStopped() bool {
+	if m != nil {
+		return m.Stopped
+	}
+	return false
+}
+
+// SplitDecision tells which samples go left at a node owned by the party without label
+type SplitDecision struct {
+	Tree                 uint64   `protobuf:"varint,1,opt,name=tree,proto3" json:"tree,omitempty"`
+	NodeID               int32    `protobuf:"varint,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Left                 []bool   `protobuf:"varint,3,rep,packed,name=left,proto3" json:"left,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SplitDecision) Reset()         { *m = SplitDecision{} }
+func (m *SplitDecision) String() string { return proto.CompactTextString(m) }
+func (*SplitDecision) ProtoMessage()    {}
+func (*SplitDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{4}
+}
+
+func (m *SplitDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SplitDecision.Unmarshal(m, b)
+}
+func (m *SplitDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SplitDecision.Marshal(b, m, deterministic)
+}
+func (m *SplitDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SplitDecision.Merge(m, src)
+}
+func (m *SplitDecision) XXX_Size() int {
+	return xxx_messageInfo_SplitDecision.Size(m)
+}
+func (m *SplitDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_SplitDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SplitDecision proto.InternalMessageInfo
+
+func (m *SplitDecision) GetTree() uint64 {
+	if m != nil {
+		return m.Tree
+	}
+	return 0
+}
+
+func (m *SplitDecision) GetNodeID() int32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *SplitDecision) GetLeft() []bool {
+	if m != nil {
+		return m.Left
+	}
+	return nil
+}
+
+type PredictMessage struct {
+	Type                 MessageType                `protobuf:"varint,1,opt,name=type,proto3,enum=xgboost_vl.MessageType" json:"type,omitempty"`
+	To                   string                     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	From                 string                     `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	VlLPsiReEncIDsReq    *mpc.VLPsiReEncIDsRequest  `protobuf:"bytes,4,opt,name=vlLPsiReEncIDsReq,proto3" json:"vlLPsiReEncIDsReq,omitempty"`
+	VlLPsiReEncIDsResp   *mpc.VLPsiReEncIDsResponse `protobuf:"bytes,5,opt,name=vlLPsiReEncIDsResp,proto3" json:"vlLPsiReEncIDsResp,omitempty"`
+	Decisions            []*SplitDecision           `protobuf:"bytes,6,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *PredictMessage) Reset()         { *m = PredictMessage{} }
+func (m *PredictMessage) String() string { return proto.CompactTextString(m) }
+func (*PredictMessage) ProtoMessage()    {}
+func (*PredictMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52a9dbf25101de02, []int{5}
+}
+
+func (m *PredictMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PredictMessage.Unmarshal(m, b)
+}
+func (m *PredictMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PredictMessage.Marshal(b, m, deterministic)
+}
+func (m *PredictMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PredictMessage.Merge(m, src)
+}
+func (m *PredictMessage) XXX_Size() int {
+	return xxx_messageInfo_PredictMessage.Size(m)
+}
+func (m *PredictMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PredictMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PredictMessage proto.InternalMessageInfo
+
+func (m *PredictMessage) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_MsgPsiEnc
+}
+
+func (m *PredictMessage) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *PredictMessage) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *PredictMessage) GetVlLPsiReEncIDsReq() *mpc.VLPsiReEncIDsRequest {
+	if m != nil {
+		return m.VlLPsiReEncIDsReq
+	}
+	return nil
+}
+
+func (m *PredictMessage) GetVlLPsiReEncIDsResp() *mpc.VLPsiReEncIDsResponse {
+	if m != nil {
+		return m.VlLPsiReEncIDsResp
+	}
+	return nil
+}
+
+func (m *PredictMessage) GetDecisions() []*SplitDecision {
+	if m != nil {
+		return m.Decisions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("xgboost_vl.MessageType", MessageType_name, MessageType_value)
+	proto.RegisterType((*NodeSamples)(nil), "xgboost_vl.NodeSamples")
+	proto.RegisterType((*FeatureHistogram)(nil), "xgboost_vl.FeatureHistogram")
+	proto.RegisterType((*NodeHistogram)(nil), "xgboost_vl.NodeHistogram")
+	proto.RegisterType((*Message)(nil), "xgboost_vl.Message")
+	proto.RegisterType((*SplitDecision)(nil), "xgboost_vl.SplitDecision")
+	proto.RegisterType((*PredictMessage)(nil), "xgboost_vl.PredictMessage")
+}
+
+func init() {
+	proto.RegisterFile("mpc/learners/xgboost_vl/xgboost_vl.proto", fileDescriptor_52a9dbf25101de02)
+}
+
+var fileDescriptor_52a9dbf25101de02 = []byte{
+	// 743 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x55, 0xdd, 0x8e, 0xdb, 0x44,
+	0x14, 0xc6, 0xce, 0xcf, 0x26, 0xc7, 0x9b, 0xec, 0xec, 0x50, 0x95, 0xe9, 0x0a, 0x55, 0x56, 0xae,
+	0x2c, 0x10, 0x89, 0xb4, 0x05, 0x01, 0x57, 0x88, 0x2a, 0xdd, 0xee, 0x56, 0x04, 0xa2, 0xd9, 0x15,
+	0x42, 0xdc, 0x20, 0xc7, 0x3e, 0x9b, 0x58, 0xb5, 0x3d, 0xae, 0xcf, 0xa4, 0xea, 0x3e, 0x15, 0x8f,
+	0xd4, 0x57, 0x41, 0x9e, 0xb1, 0x13, 0x27, 0xed, 0x3e, 0x01, 0x37, 0xd6, 0xf9, 0xbe, 0xf3, 0xff,
+	0x33, 0x32, 0x04, 0x59, 0x11, 0xcd, 0x52, 0x0c, 0xcb, 0x1c, 0x4b, 0x9a, 0x7d, 0x58, 0xaf, 0x94,
+	0x22, 0xfd, 0xcf, 0xfb, 0xb4, 0x25, 0x4e, 0x8b, 0x52, 0x69, 0xc5, 0x61, 0xcf, 0x5c, 0x8c, 0x2a,
+	0xaf, 0x82, 0x12, 0xab, 0x9a, 0xfc, 0x02, 0xde, 0xef, 0x2a, 0xc6, 0xdb, 0x30, 0x2b, 0x52, 0x24,
+	0xfe, 0x14, 0xfa, 0xb9, 0x8a, 0xf1, 0x66, 0x2e, 0x1c, 0xdf, 0x09, 0x7a, 0xb2, 0x46, 0x5c, 0xc0,
+	0x09, 0x59, 0x13, 0xe1, 0xfa, 0x9d, 0xa0, 0x27, 0x1b, 0x38, 0x79, 0x03, 0xec, 0x0a, 0x43, 0xbd,
+	0x2d, 0xf1, 0x3a, 0x21, 0xad, 0xd6, 0x65, 0x98, 0xf1, 0x0b, 0x18, 0xac, 0xcb, 0x30, 0xbe, 0xdd,
+	0x66, 0x24, 0x1c, 0xbf, 0x13, 0x9c, 0xca, 0x1d, 0xae, 0x74, 0x1b, 0x24, 0x32, 0x3a, 0xd7, 0xea,
+	0x1a, 0x3c, 0x09, 0x61, 0x54, 0x15, 0xb3, 0x0f, 0xf4, 0x58, 0x39, 0x3f, 0xc1, 0xe0, 0xde, 0x26,
+	0xb5, 0x41, 0xbc, 0xcb, 0xaf, 0xa7, 0xad, 0xae, 0x8f, 0x0b, 0x92, 0x3b, 0xeb, 0xc9, 0xc7, 0x2e,
+	0x9c, 0x2c, 0x90, 0x28, 0x5c, 0x23, 0xff, 0x16, 0xba, 0xfa, 0xa1, 0x40, 0x13, 0x7b, 0x7c, 0xf9,
+	0x55, 0x3b, 0x42, 0x6d, 0x72, 0xf7, 0x50, 0xa0, 0x34, 0x46, 0x7c, 0x0c, 0xae, 0x56, 0xc2, 0xf5,
+	0x9d, 0x60, 0x28, 0x5d, 0xad, 0x38, 0x87, 0xee, 0x7d, 0xa9, 0x32, 0xd1, 0x31, 0x8c, 0x91, 0xf9,
+	0x6b, 0x38, 0x7f, 0x9f, 0xfe, 0xb6, 0xa4, 0x44, 0xe2, 0xab, 0x3c, 0xba, 0x99, 0x93, 0xc4, 0x77,
+	0xa2, 0xeb, 0x3b, 0x81, 0x77, 0xf9, 0x6c, 0x9a, 0x15, 0xd1, 0xf4, 0xcf, 0x23, 0xe5, 0x16, 0x49,
+	0xcb, 0x4f, 0x7d, 0xf8, 0x1b, 0xe0, 0xc7, 0x24, 0x15, 0xa2, 0x67, 0x22, 0x5d, 0x7c, 0x2e, 0x12,
+	0x15, 0x2a, 0x27, 0x94, 0x9f, 0xf1, 0xe2, 0xcf, 0x01, 0x36, 0x2a, 0x53, 0xcb, 0xed, 0xea, 0x2d,
+	0x3e, 0x88, 0xbe, 0xef, 0x04, 0xa7, 0xb2, 0xc5, 0x54, 0x8d, 0xe8, 0x12, 0x51, 0x9c, 0xf8, 0x4e,
+	0xd0, 0x95, 0x46, 0xe6, 0x4f, 0xa0, 0x17, 0x63, 0xa1, 0x37, 0x62, 0x60, 0x48, 0x0b, 0xaa, 0xd5,
+	0x61, 0x1e, 0xbd, 0x2e, 0xc3, 0x98, 0xc4, 0xd0, 0xae, 0xae, 0xc1, 0xd5, 0x81, 0x60, 0x1e, 0x5d,
+	0x23, 0x91, 0x00, 0xa3, 0x6a, 0x20, 0xff, 0x0e, 0x7a, 0xd5, 0xd6, 0x48, 0x78, 0x66, 0x51, 0x07,
+	0x63, 0x6e, 0x9d, 0x9e, 0xb4, 0x56, 0xfc, 0x67, 0x80, 0x4d, 0xb3, 0x37, 0x12, 0xa7, 0xc6, 0xe7,
+	0xd9, 0xb1, 0xcf, 0x7e, 0xb3, 0x2d, 0xe3, 0xd6, 0xb5, 0x8c, 0x0e, 0xae, 0xe5, 0x39, 0x40, 0xbd,
+	0xff, 0x9b, 0xf8, 0x83, 0x18, 0x1b, 0x5d, 0x8b, 0xe1, 0x0c, 0x3a, 0xab, 0x24, 0x17, 0x67, 0x46,
+	0x51, 0x89, 0xdc, 0x07, 0x2f, 0xc5, 0x7b, 0x5d, 0x97, 0x26, 0x98, 0x39, 0xf9, 0x36, 0x65, 0x1e,
+	0x84, 0x56, 0x45, 0x81, 0xb1, 0x38, 0xf7, 0x9d, 0x60, 0x20, 0x1b, 0x38, 0xf9, 0x03, 0x46, 0xb7,
+	0x45, 0x9a, 0xe8, 0x39, 0x46, 0x09, 0x25, 0x2a, 0xdf, 0x0d, 0xd8, 0x69, 0x0d, 0x78, 0x5f, 0xaa,
+	0x7b, 0x50, 0x2a, 0x87, 0x6e, 0x95, 0x45, 0x74, 0xfc, 0x4e, 0x30, 0x90, 0x46, 0x9e, 0xfc, 0xeb,
+	0xc2, 0x78, 0x59, 0x62, 0x9c, 0x44, 0xfa, 0xff, 0x75, 0xb9, 0x3f, 0xc2, 0x30, 0xae, 0x87, 0x48,
+	0xa2, 0xff, 0xe9, 0x25, 0x1c, 0x8c, 0x59, 0xee, 0x6d, 0xbf, 0xf9, 0xe8, 0x82, 0xd7, 0x9a, 0x03,
+	0x1f, 0xc1, 0x70, 0x41, 0xeb, 0x25, 0x25, 0xaf, 0xf2, 0x88, 0x7d, 0xc1, 0x39, 0x8c, 0x2d, 0xfc,
+	0x95, 0xde, 0x9a, 0x84, 0xcc, 0xe1, 0x67, 0xe0, 0x59, 0xce, 0x12, 0x2e, 0xff, 0x12, 0xce, 0x2c,
+	0x71, 0x93, 0x6b, 0x2c, 0x09, 0x23, 0xcd, 0x3a, 0xb5, 0xd5, 0x5d, 0x19, 0x26, 0xf9, 0xf5, 0xb6,
+	0x60, 0x5d, 0x7e, 0x0e, 0xa3, 0x05, 0xad, 0xaf, 0x77, 0xaf, 0x89, 0xf5, 0x6a, 0xca, 0xd8, 0xbc,
+	0xac, 0x2a, 0x65, 0x7d, 0xfe, 0x04, 0x58, 0x43, 0x55, 0xaf, 0xa5, 0x7a, 0x16, 0xec, 0xa4, 0xce,
+	0x60, 0x83, 0x25, 0xa4, 0x25, 0xbe, 0x63, 0x03, 0xfe, 0x14, 0x78, 0x9b, 0xb4, 0x97, 0xcd, 0x86,
+	0x6d, 0xfe, 0x2a, 0xc9, 0x63, 0xd3, 0x3a, 0x31, 0x68, 0x67, 0x33, 0x1c, 0xf3, 0xea, 0xf6, 0x2c,
+	0xa5, 0x43, 0xbd, 0x25, 0x76, 0xda, 0xe6, 0x16, 0x2a, 0xc6, 0x94, 0xd8, 0xa8, 0x76, 0xad, 0x2f,
+	0xab, 0x6a, 0xe7, 0x45, 0x33, 0x19, 0x4b, 0x2d, 0xc3, 0x52, 0xb3, 0xef, 0x9b, 0x41, 0x58, 0xee,
+	0x2a, 0xc9, 0xc3, 0x94, 0xfd, 0xf0, 0xf2, 0xea, 0xef, 0xf9, 0x3a, 0xd1, 0x9b, 0xed, 0x6a, 0x1a,
+	0xa9, 0x6c, 0xb6, 0x0c, 0xe3, 0x38, 0x45, 0xfb, 0xad, 0xc1, 0xfc, 0xee, 0xaf, 0x59, 0x1c, 0x26,
+	0x33, 0xf3, 0x97, 0xa1, 0xd9, 0x23, 0xbf, 0xaa, 0x55, 0xdf, 0xe8, 0x5f, 0xfc, 0x37, 0x00, 0x28,
+	0xa2, 0xb5, 0x2e, 0xcc, 0x06, 0x00, 0x00,
+}
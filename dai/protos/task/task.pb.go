@@ -28,12 +28,16 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 // TaskRequest is message sent between Executors to request to start a task.
 type TaskRequest struct {
-	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
-	TaskID               string   `protobuf:"bytes,2,opt,name=taskID,proto3" json:"taskID,omitempty"`
-	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PubKey     []byte `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	TaskID     string `protobuf:"bytes,2,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Signature  []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	BatchIndex int32  `protobuf:"varint,5,opt,name=batchIndex,proto3" json:"batchIndex,omitempty"`
+	// fingerprint of the task on the Executor requesting to start it, checked by the receiver before the task starts,
+	// it is not signed so that Executors not checking it verify the signature as before
+	Fingerprint          *TaskFingerprint `protobuf:"bytes,6,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *TaskRequest) Reset()         { *m = TaskRequest{} }
@@ -82,9 +86,27 @@ func (m *TaskRequest) GetSignature() []byte {
 	return nil
 }
 
+func (m *TaskRequest) GetBatchIndex() int32 {
+	if m != nil {
+		return m.BatchIndex
+	}
+	return 0
+}
+
+func (m *TaskRequest) GetFingerprint() *TaskFingerprint {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return nil
+}
+
 // TaskResponse is a message received from Executor.
 type TaskResponse struct {
 	TaskID               string   `protobuf:"bytes,2,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	AlignmentKey         string   `protobuf:"bytes,4,opt,name=alignmentKey,proto3" json:"alignmentKey,omitempty"`
+	AlignmentCached      bool     `protobuf:"varint,5,opt,name=alignmentCached,proto3" json:"alignmentCached,omitempty"`
+	PsiAlgorithm         string   `protobuf:"bytes,6,opt,name=psiAlgorithm,proto3" json:"psiAlgorithm,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -122,6 +144,34 @@ func (m *TaskResponse) GetTaskID() string {
 	return ""
 }
 
+func (m *TaskResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *TaskResponse) GetAlignmentKey() string {
+	if m != nil {
+		return m.AlignmentKey
+	}
+	return ""
+}
+
+func (m *TaskResponse) GetAlignmentCached() bool {
+	if m != nil {
+		return m.AlignmentCached
+	}
+	return false
+}
+
+func (m *TaskResponse) GetPsiAlgorithm() string {
+	if m != nil {
+		return m.PsiAlgorithm
+	}
+	return ""
+}
+
 // ListTaskRequest is message sent to Executor server to list tasks
 type ListTaskRequest struct {
 	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
@@ -130,6 +180,7 @@ type ListTaskRequest struct {
 	TimeStart            int64    `protobuf:"varint,4,opt,name=timeStart,proto3" json:"timeStart,omitempty"`
 	TimeEnd              int64    `protobuf:"varint,5,opt,name=timeEnd,proto3" json:"timeEnd,omitempty"`
 	Limit                int64    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Network              string   `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -202,16 +253,301 @@ func (m *ListTaskRequest) GetLimit() int64 {
 	return 0
 }
 
+func (m *ListTaskRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+// ListTasksRequest is message sent to Executor server to query the history of tasks
+type ListTasksRequest struct {
+	Status               string            `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	TaskType             string            `protobuf:"bytes,2,opt,name=taskType,proto3" json:"taskType,omitempty"`
+	TimeStart            int64             `protobuf:"varint,3,opt,name=timeStart,proto3" json:"timeStart,omitempty"`
+	TimeEnd              int64             `protobuf:"varint,4,opt,name=timeEnd,proto3" json:"timeEnd,omitempty"`
+	Limit                int64             `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int64             `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Network              string            `protobuf:"bytes,8,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListTasksRequest) Reset()         { *m = ListTasksRequest{} }
+func (m *ListTasksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTasksRequest) ProtoMessage()    {}
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{3}
+}
+
+func (m *ListTasksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTasksRequest.Unmarshal(m, b)
+}
+func (m *ListTasksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTasksRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTasksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTasksRequest.Merge(m, src)
+}
+func (m *ListTasksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTasksRequest.Size(m)
+}
+func (m *ListTasksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTasksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTasksRequest proto.InternalMessageInfo
+
+func (m *ListTasksRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ListTasksRequest) GetTaskType() string {
+	if m != nil {
+		return m.TaskType
+	}
+	return ""
+}
+
+func (m *ListTasksRequest) GetTimeStart() int64 {
+	if m != nil {
+		return m.TimeStart
+	}
+	return 0
+}
+
+func (m *ListTasksRequest) GetTimeEnd() int64 {
+	if m != nil {
+		return m.TimeEnd
+	}
+	return 0
+}
+
+func (m *ListTasksRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListTasksRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ListTasksRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ListTasksRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+// TaskSummary is a message received from Executor, describes a task in the history of tasks
+type TaskSummary struct {
+	TaskID               string            `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	TaskType             string            `protobuf:"bytes,2,opt,name=taskType,proto3" json:"taskType,omitempty"`
+	Evaluation           bool              `protobuf:"varint,3,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	Status               string            `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Requester            []byte            `protobuf:"bytes,5,opt,name=requester,proto3" json:"requester,omitempty"`
+	ErrMessage           string            `protobuf:"bytes,6,opt,name=errMessage,proto3" json:"errMessage,omitempty"`
+	PublishTime          int64             `protobuf:"varint,7,opt,name=publishTime,proto3" json:"publishTime,omitempty"`
+	StartTime            int64             `protobuf:"varint,8,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              int64             `protobuf:"varint,9,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	InExecution          bool              `protobuf:"varint,10,opt,name=inExecution,proto3" json:"inExecution,omitempty"`
+	Queued               bool              `protobuf:"varint,11,opt,name=queued,proto3" json:"queued,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Network              string            `protobuf:"bytes,13,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TaskSummary) Reset()         { *m = TaskSummary{} }
+func (m *TaskSummary) String() string { return proto.CompactTextString(m) }
+func (*TaskSummary) ProtoMessage()    {}
+func (*TaskSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{4}
+}
+
+func (m *TaskSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskSummary.Unmarshal(m, b)
+}
+func (m *TaskSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskSummary.Marshal(b, m, deterministic)
+}
+func (m *TaskSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskSummary.Merge(m, src)
+}
+func (m *TaskSummary) XXX_Size() int {
+	return xxx_messageInfo_TaskSummary.Size(m)
+}
+func (m *TaskSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskSummary proto.InternalMessageInfo
+
+func (m *TaskSummary) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *TaskSummary) GetTaskType() string {
+	if m != nil {
+		return m.TaskType
+	}
+	return ""
+}
+
+func (m *TaskSummary) GetEvaluation() bool {
+	if m != nil {
+		return m.Evaluation
+	}
+	return false
+}
+
+func (m *TaskSummary) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TaskSummary) GetRequester() []byte {
+	if m != nil {
+		return m.Requester
+	}
+	return nil
+}
+
+func (m *TaskSummary) GetErrMessage() string {
+	if m != nil {
+		return m.ErrMessage
+	}
+	return ""
+}
+
+func (m *TaskSummary) GetPublishTime() int64 {
+	if m != nil {
+		return m.PublishTime
+	}
+	return 0
+}
+
+func (m *TaskSummary) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *TaskSummary) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *TaskSummary) GetInExecution() bool {
+	if m != nil {
+		return m.InExecution
+	}
+	return false
+}
+
+func (m *TaskSummary) GetQueued() bool {
+	if m != nil {
+		return m.Queued
+	}
+	return false
+}
+
+func (m *TaskSummary) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *TaskSummary) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+// TaskSummaries is list of TaskSummary received from Executor
+type TaskSummaries struct {
+	Tasks                []*TaskSummary `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TaskSummaries) Reset()         { *m = TaskSummaries{} }
+func (m *TaskSummaries) String() string { return proto.CompactTextString(m) }
+func (*TaskSummaries) ProtoMessage()    {}
+func (*TaskSummaries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{5}
+}
+
+func (m *TaskSummaries) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskSummaries.Unmarshal(m, b)
+}
+func (m *TaskSummaries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskSummaries.Marshal(b, m, deterministic)
+}
+func (m *TaskSummaries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskSummaries.Merge(m, src)
+}
+func (m *TaskSummaries) XXX_Size() int {
+	return xxx_messageInfo_TaskSummaries.Size(m)
+}
+func (m *TaskSummaries) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskSummaries.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskSummaries proto.InternalMessageInfo
+
+func (m *TaskSummaries) GetTasks() []*TaskSummary {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
 // DataForTask is a message received from Executor
 type DataForTask struct {
-	Owner                []byte   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
-	Executor             []byte   `protobuf:"bytes,2,opt,name=executor,proto3" json:"executor,omitempty"`
-	DataID               string   `protobuf:"bytes,3,opt,name=dataID,proto3" json:"dataID,omitempty"`
-	PsiLabel             string   `protobuf:"bytes,4,opt,name=psiLabel,proto3" json:"psiLabel,omitempty"`
-	ConfirmedAt          int64    `protobuf:"varint,5,opt,name=confirmedAt,proto3" json:"confirmedAt,omitempty"`
-	RejectedAt           int64    `protobuf:"varint,6,opt,name=rejectedAt,proto3" json:"rejectedAt,omitempty"`
-	Address              string   `protobuf:"bytes,7,opt,name=address,proto3" json:"address,omitempty"`
-	IsTagPart            bool     `protobuf:"varint,8,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
+	Owner       []byte `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Executor    []byte `protobuf:"bytes,2,opt,name=executor,proto3" json:"executor,omitempty"`
+	DataID      string `protobuf:"bytes,3,opt,name=dataID,proto3" json:"dataID,omitempty"`
+	PsiLabel    string `protobuf:"bytes,4,opt,name=psiLabel,proto3" json:"psiLabel,omitempty"`
+	ConfirmedAt int64  `protobuf:"varint,5,opt,name=confirmedAt,proto3" json:"confirmedAt,omitempty"`
+	RejectedAt  int64  `protobuf:"varint,6,opt,name=rejectedAt,proto3" json:"rejectedAt,omitempty"`
+	Address     string `protobuf:"bytes,7,opt,name=address,proto3" json:"address,omitempty"`
+	IsTagPart   bool   `protobuf:"varint,8,opt,name=isTagPart,proto3" json:"isTagPart,omitempty"`
+	// feature columns of samples used by the task, names or 0-based indices, all columns if empty,
+	// and psiLabel and the label of the tag part are always used
+	Columns []string `protobuf:"bytes,9,rep,name=columns,proto3" json:"columns,omitempty"`
+	// sample files of the other inputs of a batch prediction, predicted along with dataID in one session,
+	// inputs are matched among parties by their order, dataID is the first input
+	BatchDataIDs         []string `protobuf:"bytes,10,rep,name=batchDataIDs,proto3" json:"batchDataIDs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -221,7 +557,7 @@ func (m *DataForTask) Reset()         { *m = DataForTask{} }
 func (m *DataForTask) String() string { return proto.CompactTextString(m) }
 func (*DataForTask) ProtoMessage()    {}
 func (*DataForTask) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e8f2b86464a95fe, []int{3}
+	return fileDescriptor_8e8f2b86464a95fe, []int{6}
 }
 
 func (m *DataForTask) XXX_Unmarshal(b []byte) error {
@@ -298,30 +634,114 @@ func (m *DataForTask) GetIsTagPart() bool {
 	return false
 }
 
+func (m *DataForTask) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *DataForTask) GetBatchDataIDs() []string {
+	if m != nil {
+		return m.BatchDataIDs
+	}
+	return nil
+}
+
+// BatchPredictResult is the result of an input of a batch prediction
+type BatchPredictResult struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Result               string   `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	ErrMessage           string   `protobuf:"bytes,3,opt,name=errMessage,proto3" json:"errMessage,omitempty"`
+	Samples              int64    `protobuf:"varint,4,opt,name=samples,proto3" json:"samples,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchPredictResult) Reset()         { *m = BatchPredictResult{} }
+func (m *BatchPredictResult) String() string { return proto.CompactTextString(m) }
+func (*BatchPredictResult) ProtoMessage()    {}
+func (*BatchPredictResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{7}
+}
+
+func (m *BatchPredictResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchPredictResult.Unmarshal(m, b)
+}
+func (m *BatchPredictResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchPredictResult.Marshal(b, m, deterministic)
+}
+func (m *BatchPredictResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchPredictResult.Merge(m, src)
+}
+func (m *BatchPredictResult) XXX_Size() int {
+	return xxx_messageInfo_BatchPredictResult.Size(m)
+}
+func (m *BatchPredictResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchPredictResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchPredictResult proto.InternalMessageInfo
+
+func (m *BatchPredictResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BatchPredictResult) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *BatchPredictResult) GetErrMessage() string {
+	if m != nil {
+		return m.ErrMessage
+	}
+	return ""
+}
+
+func (m *BatchPredictResult) GetSamples() int64 {
+	if m != nil {
+		return m.Samples
+	}
+	return 0
+}
+
 // FLTask is a message received from Executor and defines Federated Learning Task based on MPC
 type FLTask struct {
-	TaskID               string             `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
-	Name                 string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description          string             `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Requester            []byte             `protobuf:"bytes,4,opt,name=requester,proto3" json:"requester,omitempty"`
-	DataSets             []*DataForTask     `protobuf:"bytes,5,rep,name=dataSets,proto3" json:"dataSets,omitempty"`
-	AlgoParam            *common.TaskParams `protobuf:"bytes,6,opt,name=algoParam,proto3" json:"algoParam,omitempty"`
-	Status               string             `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	ErrMessage           string             `protobuf:"bytes,8,opt,name=errMessage,proto3" json:"errMessage,omitempty"`
-	Result               string             `protobuf:"bytes,9,opt,name=result,proto3" json:"result,omitempty"`
-	PublishTime          int64              `protobuf:"varint,10,opt,name=publishTime,proto3" json:"publishTime,omitempty"`
-	StartTime            int64              `protobuf:"varint,11,opt,name=startTime,proto3" json:"startTime,omitempty"`
-	EndTime              int64              `protobuf:"varint,12,opt,name=endTime,proto3" json:"endTime,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	TaskID               string                `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Name                 string                `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description          string                `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Requester            []byte                `protobuf:"bytes,4,opt,name=requester,proto3" json:"requester,omitempty"`
+	DataSets             []*DataForTask        `protobuf:"bytes,5,rep,name=dataSets,proto3" json:"dataSets,omitempty"`
+	AlgoParam            *common.TaskParams    `protobuf:"bytes,6,opt,name=algoParam,proto3" json:"algoParam,omitempty"`
+	Status               string                `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ErrMessage           string                `protobuf:"bytes,8,opt,name=errMessage,proto3" json:"errMessage,omitempty"`
+	Result               string                `protobuf:"bytes,9,opt,name=result,proto3" json:"result,omitempty"`
+	PublishTime          int64                 `protobuf:"varint,10,opt,name=publishTime,proto3" json:"publishTime,omitempty"`
+	StartTime            int64                 `protobuf:"varint,11,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              int64                 `protobuf:"varint,12,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	IdempotencyKey       string                `protobuf:"bytes,13,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	ModelVersion         int64                 `protobuf:"varint,14,opt,name=modelVersion,proto3" json:"modelVersion,omitempty"`
+	PrivacyBudget        *common.PrivacyBudget `protobuf:"bytes,15,opt,name=privacyBudget,proto3" json:"privacyBudget,omitempty"`
+	BatchResults         []*BatchPredictResult `protobuf:"bytes,16,rep,name=batchResults,proto3" json:"batchResults,omitempty"`
+	DependsOn            []string              `protobuf:"bytes,17,rep,name=dependsOn,proto3" json:"dependsOn,omitempty"`
+	Network              string                `protobuf:"bytes,18,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *FLTask) Reset()         { *m = FLTask{} }
 func (m *FLTask) String() string { return proto.CompactTextString(m) }
 func (*FLTask) ProtoMessage()    {}
 func (*FLTask) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e8f2b86464a95fe, []int{4}
+	return fileDescriptor_8e8f2b86464a95fe, []int{8}
 }
 
 func (m *FLTask) XXX_Unmarshal(b []byte) error {
@@ -426,19 +846,61 @@ func (m *FLTask) GetEndTime() int64 {
 	return 0
 }
 
-// FLTasks is list of FLTasks received from Executor
-type FLTasks struct {
-	FLTasks              []*FLTask `protobuf:"bytes,1,rep,name=fLTasks,proto3" json:"fLTasks,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+func (m *FLTask) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
 }
 
-func (m *FLTasks) Reset()         { *m = FLTasks{} }
-func (m *FLTasks) String() string { return proto.CompactTextString(m) }
-func (*FLTasks) ProtoMessage()    {}
+func (m *FLTask) GetModelVersion() int64 {
+	if m != nil {
+		return m.ModelVersion
+	}
+	return 0
+}
+
+func (m *FLTask) GetPrivacyBudget() *common.PrivacyBudget {
+	if m != nil {
+		return m.PrivacyBudget
+	}
+	return nil
+}
+
+func (m *FLTask) GetBatchResults() []*BatchPredictResult {
+	if m != nil {
+		return m.BatchResults
+	}
+	return nil
+}
+
+func (m *FLTask) GetDependsOn() []string {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
+func (m *FLTask) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+// FLTasks is list of FLTasks received from Executor
+type FLTasks struct {
+	FLTasks              []*FLTask `protobuf:"bytes,1,rep,name=fLTasks,proto3" json:"fLTasks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *FLTasks) Reset()         { *m = FLTasks{} }
+func (m *FLTasks) String() string { return proto.CompactTextString(m) }
+func (*FLTasks) ProtoMessage()    {}
 func (*FLTasks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e8f2b86464a95fe, []int{5}
+	return fileDescriptor_8e8f2b86464a95fe, []int{9}
 }
 
 func (m *FLTasks) XXX_Unmarshal(b []byte) error {
@@ -469,6 +931,7 @@ func (m *FLTasks) GetFLTasks() []*FLTask {
 // GetTaskRequest is message sent to Executor server to get a task
 type GetTaskRequest struct {
 	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Network              string   `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -478,7 +941,7 @@ func (m *GetTaskRequest) Reset()         { *m = GetTaskRequest{} }
 func (m *GetTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*GetTaskRequest) ProtoMessage()    {}
 func (*GetTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e8f2b86464a95fe, []int{6}
+	return fileDescriptor_8e8f2b86464a95fe, []int{10}
 }
 
 func (m *GetTaskRequest) XXX_Unmarshal(b []byte) error {
@@ -506,50 +969,1029 @@ func (m *GetTaskRequest) GetTaskID() string {
 	return ""
 }
 
+func (m *GetTaskRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
 // PredictResponse is a message received from Executor
 type PredictResponse struct {
 	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
 	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Encrypted            bool     `protobuf:"varint,3,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PredictResponse) Reset()         { *m = PredictResponse{} }
+func (m *PredictResponse) String() string { return proto.CompactTextString(m) }
+func (*PredictResponse) ProtoMessage()    {}
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{11}
+}
+
+func (m *PredictResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PredictResponse.Unmarshal(m, b)
+}
+func (m *PredictResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PredictResponse.Marshal(b, m, deterministic)
+}
+func (m *PredictResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PredictResponse.Merge(m, src)
+}
+func (m *PredictResponse) XXX_Size() int {
+	return xxx_messageInfo_PredictResponse.Size(m)
+}
+func (m *PredictResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PredictResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PredictResponse proto.InternalMessageInfo
+
+func (m *PredictResponse) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *PredictResponse) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *PredictResponse) GetEncrypted() bool {
+	if m != nil {
+		return m.Encrypted
+	}
+	return false
+}
+
+// ExportModelRequest is message sent to Executor server to get the local part of a model,
+// pubKey should be the one of the requester of the training task modelID
+type ExportModelRequest struct {
+	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	ModelID              string   `protobuf:"bytes,2,opt,name=modelID,proto3" json:"modelID,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportModelRequest) Reset()         { *m = ExportModelRequest{} }
+func (m *ExportModelRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportModelRequest) ProtoMessage()    {}
+func (*ExportModelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{12}
+}
+
+func (m *ExportModelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportModelRequest.Unmarshal(m, b)
+}
+func (m *ExportModelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportModelRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportModelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportModelRequest.Merge(m, src)
+}
+func (m *ExportModelRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportModelRequest.Size(m)
+}
+func (m *ExportModelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportModelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportModelRequest proto.InternalMessageInfo
+
+func (m *ExportModelRequest) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *ExportModelRequest) GetModelID() string {
+	if m != nil {
+		return m.ModelID
+	}
+	return ""
+}
+
+func (m *ExportModelRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// ExportModelResponse is a message received from Executor, the local part of the model
+type ExportModelResponse struct {
+	ModelID              string              `protobuf:"bytes,1,opt,name=modelID,proto3" json:"modelID,omitempty"`
+	Model                *common.TrainModels `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ExportModelResponse) Reset()         { *m = ExportModelResponse{} }
+func (m *ExportModelResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportModelResponse) ProtoMessage()    {}
+func (*ExportModelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{13}
+}
+
+func (m *ExportModelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportModelResponse.Unmarshal(m, b)
+}
+func (m *ExportModelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportModelResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportModelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportModelResponse.Merge(m, src)
+}
+func (m *ExportModelResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportModelResponse.Size(m)
+}
+func (m *ExportModelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportModelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportModelResponse proto.InternalMessageInfo
+
+func (m *ExportModelResponse) GetModelID() string {
+	if m != nil {
+		return m.ModelID
+	}
+	return ""
+}
+
+func (m *ExportModelResponse) GetModel() *common.TrainModels {
+	if m != nil {
+		return m.Model
+	}
+	return nil
+}
+
+// LiveEvaluationRequest is message sent to Executor server to watch the live evaluation of a task in execution
+type LiveEvaluationRequest struct {
+	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LiveEvaluationRequest) Reset()         { *m = LiveEvaluationRequest{} }
+func (m *LiveEvaluationRequest) String() string { return proto.CompactTextString(m) }
+func (*LiveEvaluationRequest) ProtoMessage()    {}
+func (*LiveEvaluationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{14}
+}
+
+func (m *LiveEvaluationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LiveEvaluationRequest.Unmarshal(m, b)
+}
+func (m *LiveEvaluationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LiveEvaluationRequest.Marshal(b, m, deterministic)
+}
+func (m *LiveEvaluationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LiveEvaluationRequest.Merge(m, src)
+}
+func (m *LiveEvaluationRequest) XXX_Size() int {
+	return xxx_messageInfo_LiveEvaluationRequest.Size(m)
+}
+func (m *LiveEvaluationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LiveEvaluationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LiveEvaluationRequest proto.InternalMessageInfo
+
+func (m *LiveEvaluationRequest) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+// LiveEvaluationMetric is a message received from Executor, a metric score calculated at a pause round of
+// live evaluation. Only the executor holding the label feature calculates metric scores.
+type LiveEvaluationMetric struct {
+	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Round                uint64   `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	MetricName           string   `protobuf:"bytes,3,opt,name=metricName,proto3" json:"metricName,omitempty"`
+	Value                float64  `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LiveEvaluationMetric) Reset()         { *m = LiveEvaluationMetric{} }
+func (m *LiveEvaluationMetric) String() string { return proto.CompactTextString(m) }
+func (*LiveEvaluationMetric) ProtoMessage()    {}
+func (*LiveEvaluationMetric) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{15}
+}
+
+func (m *LiveEvaluationMetric) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LiveEvaluationMetric.Unmarshal(m, b)
+}
+func (m *LiveEvaluationMetric) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LiveEvaluationMetric.Marshal(b, m, deterministic)
+}
+func (m *LiveEvaluationMetric) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LiveEvaluationMetric.Merge(m, src)
+}
+func (m *LiveEvaluationMetric) XXX_Size() int {
+	return xxx_messageInfo_LiveEvaluationMetric.Size(m)
+}
+func (m *LiveEvaluationMetric) XXX_DiscardUnknown() {
+	xxx_messageInfo_LiveEvaluationMetric.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LiveEvaluationMetric proto.InternalMessageInfo
+
+func (m *LiveEvaluationMetric) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *LiveEvaluationMetric) GetRound() uint64 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *LiveEvaluationMetric) GetMetricName() string {
+	if m != nil {
+		return m.MetricName
+	}
+	return ""
+}
+
+func (m *LiveEvaluationMetric) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+// NodeStatusRequest is message sent to Executor server to query the node status
+type NodeStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeStatusRequest) Reset()         { *m = NodeStatusRequest{} }
+func (m *NodeStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeStatusRequest) ProtoMessage()    {}
+func (*NodeStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{16}
+}
+
+func (m *NodeStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatusRequest.Unmarshal(m, b)
+}
+func (m *NodeStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *NodeStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatusRequest.Merge(m, src)
+}
+func (m *NodeStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeStatusRequest.Size(m)
+}
+func (m *NodeStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStatusRequest proto.InternalMessageInfo
+
+// NodeStatus is a message received from Executor, describes tasks in execution and resources usage.
+// MB and cores values of zero mean that the resource is not limited.
+type NodeStatus struct {
+	TrainTasks           int64    `protobuf:"varint,1,opt,name=trainTasks,proto3" json:"trainTasks,omitempty"`
+	PredictTasks         int64    `protobuf:"varint,2,opt,name=predictTasks,proto3" json:"predictTasks,omitempty"`
+	TrainTaskLimit       int64    `protobuf:"varint,3,opt,name=trainTaskLimit,proto3" json:"trainTaskLimit,omitempty"`
+	PredictTaskLimit     int64    `protobuf:"varint,4,opt,name=predictTaskLimit,proto3" json:"predictTaskLimit,omitempty"`
+	MaxTaskMemoryMB      int64    `protobuf:"varint,5,opt,name=maxTaskMemoryMB,proto3" json:"maxTaskMemoryMB,omitempty"`
+	MaxTaskCPUCores      int64    `protobuf:"varint,6,opt,name=maxTaskCPUCores,proto3" json:"maxTaskCPUCores,omitempty"`
+	MemoryBudgetMB       int64    `protobuf:"varint,7,opt,name=memoryBudgetMB,proto3" json:"memoryBudgetMB,omitempty"`
+	CpuBudgetCores       int64    `protobuf:"varint,8,opt,name=cpuBudgetCores,proto3" json:"cpuBudgetCores,omitempty"`
+	MemoryReservedMB     int64    `protobuf:"varint,9,opt,name=memoryReservedMB,proto3" json:"memoryReservedMB,omitempty"`
+	CpuReservedCores     int64    `protobuf:"varint,10,opt,name=cpuReservedCores,proto3" json:"cpuReservedCores,omitempty"`
+	MemoryUsedMB         int64    `protobuf:"varint,11,opt,name=memoryUsedMB,proto3" json:"memoryUsedMB,omitempty"`
+	QueuedTasks          int64    `protobuf:"varint,12,opt,name=queuedTasks,proto3" json:"queuedTasks,omitempty"`
+	QueueSize            int64    `protobuf:"varint,13,opt,name=queueSize,proto3" json:"queueSize,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PredictResponse) Reset()         { *m = PredictResponse{} }
-func (m *PredictResponse) String() string { return proto.CompactTextString(m) }
-func (*PredictResponse) ProtoMessage()    {}
-func (*PredictResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e8f2b86464a95fe, []int{7}
+func (m *NodeStatus) Reset()         { *m = NodeStatus{} }
+func (m *NodeStatus) String() string { return proto.CompactTextString(m) }
+func (*NodeStatus) ProtoMessage()    {}
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{17}
+}
+
+func (m *NodeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatus.Unmarshal(m, b)
+}
+func (m *NodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatus.Marshal(b, m, deterministic)
+}
+func (m *NodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatus.Merge(m, src)
+}
+func (m *NodeStatus) XXX_Size() int {
+	return xxx_messageInfo_NodeStatus.Size(m)
+}
+func (m *NodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStatus proto.InternalMessageInfo
+
+func (m *NodeStatus) GetTrainTasks() int64 {
+	if m != nil {
+		return m.TrainTasks
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetPredictTasks() int64 {
+	if m != nil {
+		return m.PredictTasks
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetTrainTaskLimit() int64 {
+	if m != nil {
+		return m.TrainTaskLimit
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetPredictTaskLimit() int64 {
+	if m != nil {
+		return m.PredictTaskLimit
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetMaxTaskMemoryMB() int64 {
+	if m != nil {
+		return m.MaxTaskMemoryMB
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetMaxTaskCPUCores() int64 {
+	if m != nil {
+		return m.MaxTaskCPUCores
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetMemoryBudgetMB() int64 {
+	if m != nil {
+		return m.MemoryBudgetMB
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetCpuBudgetCores() int64 {
+	if m != nil {
+		return m.CpuBudgetCores
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetMemoryReservedMB() int64 {
+	if m != nil {
+		return m.MemoryReservedMB
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetCpuReservedCores() int64 {
+	if m != nil {
+		return m.CpuReservedCores
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetMemoryUsedMB() int64 {
+	if m != nil {
+		return m.MemoryUsedMB
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetQueuedTasks() int64 {
+	if m != nil {
+		return m.QueuedTasks
+	}
+	return 0
+}
+
+func (m *NodeStatus) GetQueueSize() int64 {
+	if m != nil {
+		return m.QueueSize
+	}
+	return 0
+}
+
+// NodeInfoRequest is message sent to Executor to query the identity of the node.
+type NodeInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeInfoRequest) Reset()         { *m = NodeInfoRequest{} }
+func (m *NodeInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeInfoRequest) ProtoMessage()    {}
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{18}
+}
+
+func (m *NodeInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeInfoRequest.Unmarshal(m, b)
+}
+func (m *NodeInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *NodeInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeInfoRequest.Merge(m, src)
+}
+func (m *NodeInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeInfoRequest.Size(m)
+}
+func (m *NodeInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeInfoRequest proto.InternalMessageInfo
+
+// NodeInfo is a message received from Executor, describes the identity of the node.
+// Only the public key derived from the private key of the node is returned.
+type NodeInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PublicAddress        string   `protobuf:"bytes,2,opt,name=publicAddress,proto3" json:"publicAddress,omitempty"`
+	PublicKey            string   `protobuf:"bytes,3,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	Algorithms           []string `protobuf:"bytes,4,rep,name=algorithms,proto3" json:"algorithms,omitempty"`
+	Version              string   `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	GitCommit            string   `protobuf:"bytes,6,opt,name=gitCommit,proto3" json:"gitCommit,omitempty"`
+	BuildDate            string   `protobuf:"bytes,7,opt,name=buildDate,proto3" json:"buildDate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeInfo) Reset()         { *m = NodeInfo{} }
+func (m *NodeInfo) String() string { return proto.CompactTextString(m) }
+func (*NodeInfo) ProtoMessage()    {}
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{19}
+}
+
+func (m *NodeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeInfo.Unmarshal(m, b)
+}
+func (m *NodeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeInfo.Marshal(b, m, deterministic)
+}
+func (m *NodeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeInfo.Merge(m, src)
+}
+func (m *NodeInfo) XXX_Size() int {
+	return xxx_messageInfo_NodeInfo.Size(m)
+}
+func (m *NodeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeInfo proto.InternalMessageInfo
+
+func (m *NodeInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetPublicAddress() string {
+	if m != nil {
+		return m.PublicAddress
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetAlgorithms() []string {
+	if m != nil {
+		return m.Algorithms
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetGitCommit() string {
+	if m != nil {
+		return m.GitCommit
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetBuildDate() string {
+	if m != nil {
+		return m.BuildDate
+	}
+	return ""
+}
+
+// TaskFingerprint summarizes how an Executor executes a task, Executors of the task exchange it to check
+// they agree on the task before heavy computation begins.
+type TaskFingerprint struct {
+	ProtocolVersion      int32    `protobuf:"varint,1,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	Algo                 string   `protobuf:"bytes,2,opt,name=algo,proto3" json:"algo,omitempty"`
+	TaskType             string   `protobuf:"bytes,3,opt,name=taskType,proto3" json:"taskType,omitempty"`
+	PsiAlgorithm         string   `protobuf:"bytes,4,opt,name=psiAlgorithm,proto3" json:"psiAlgorithm,omitempty"`
+	ParamsHash           string   `protobuf:"bytes,5,opt,name=paramsHash,proto3" json:"paramsHash,omitempty"`
+	HasLabel             bool     `protobuf:"varint,6,opt,name=hasLabel,proto3" json:"hasLabel,omitempty"`
+	SamplesDigest        string   `protobuf:"bytes,7,opt,name=samplesDigest,proto3" json:"samplesDigest,omitempty"`
+	AlignmentKeys        []string `protobuf:"bytes,8,rep,name=alignmentKeys,proto3" json:"alignmentKeys,omitempty"`
+	SampleNum            int64    `protobuf:"varint,9,opt,name=sampleNum,proto3" json:"sampleNum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskFingerprint) Reset()         { *m = TaskFingerprint{} }
+func (m *TaskFingerprint) String() string { return proto.CompactTextString(m) }
+func (*TaskFingerprint) ProtoMessage()    {}
+func (*TaskFingerprint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{20}
+}
+
+func (m *TaskFingerprint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskFingerprint.Unmarshal(m, b)
+}
+func (m *TaskFingerprint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskFingerprint.Marshal(b, m, deterministic)
+}
+func (m *TaskFingerprint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskFingerprint.Merge(m, src)
+}
+func (m *TaskFingerprint) XXX_Size() int {
+	return xxx_messageInfo_TaskFingerprint.Size(m)
+}
+func (m *TaskFingerprint) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskFingerprint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskFingerprint proto.InternalMessageInfo
+
+func (m *TaskFingerprint) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *TaskFingerprint) GetAlgo() string {
+	if m != nil {
+		return m.Algo
+	}
+	return ""
+}
+
+func (m *TaskFingerprint) GetTaskType() string {
+	if m != nil {
+		return m.TaskType
+	}
+	return ""
+}
+
+func (m *TaskFingerprint) GetPsiAlgorithm() string {
+	if m != nil {
+		return m.PsiAlgorithm
+	}
+	return ""
+}
+
+func (m *TaskFingerprint) GetParamsHash() string {
+	if m != nil {
+		return m.ParamsHash
+	}
+	return ""
+}
+
+func (m *TaskFingerprint) GetHasLabel() bool {
+	if m != nil {
+		return m.HasLabel
+	}
+	return false
+}
+
+func (m *TaskFingerprint) GetSamplesDigest() string {
+	if m != nil {
+		return m.SamplesDigest
+	}
+	return ""
+}
+
+func (m *TaskFingerprint) GetAlignmentKeys() []string {
+	if m != nil {
+		return m.AlignmentKeys
+	}
+	return nil
+}
+
+func (m *TaskFingerprint) GetSampleNum() int64 {
+	if m != nil {
+		return m.SampleNum
+	}
+	return 0
+}
+
+// VerifyResultRequest is message sent to Executor server to verify the signature of a prediction result,
+// the result is identified by the prediction task and the index of the input if it's a batch prediction
+type VerifyResultRequest struct {
+	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	BatchIndex           int32    `protobuf:"varint,2,opt,name=batchIndex,proto3" json:"batchIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyResultRequest) Reset()         { *m = VerifyResultRequest{} }
+func (m *VerifyResultRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyResultRequest) ProtoMessage()    {}
+func (*VerifyResultRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{21}
+}
+
+func (m *VerifyResultRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyResultRequest.Unmarshal(m, b)
+}
+func (m *VerifyResultRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyResultRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyResultRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyResultRequest.Merge(m, src)
+}
+func (m *VerifyResultRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyResultRequest.Size(m)
+}
+func (m *VerifyResultRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyResultRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyResultRequest proto.InternalMessageInfo
+
+func (m *VerifyResultRequest) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *VerifyResultRequest) GetBatchIndex() int32 {
+	if m != nil {
+		return m.BatchIndex
+	}
+	return 0
+}
+
+// ResultSignature is a message received from Executor, the signature of a prediction result signed by the
+// executor storing it, valid is whether the signature matches the result stored and the public key of the node
+type ResultSignature struct {
+	TaskID               string   `protobuf:"bytes,1,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	BatchIndex           int32    `protobuf:"varint,2,opt,name=batchIndex,proto3" json:"batchIndex,omitempty"`
+	Executor             []byte   `protobuf:"bytes,3,opt,name=executor,proto3" json:"executor,omitempty"`
+	Digest               []byte   `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Valid                bool     `protobuf:"varint,6,opt,name=valid,proto3" json:"valid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResultSignature) Reset()         { *m = ResultSignature{} }
+func (m *ResultSignature) String() string { return proto.CompactTextString(m) }
+func (*ResultSignature) ProtoMessage()    {}
+func (*ResultSignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{22}
+}
+
+func (m *ResultSignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResultSignature.Unmarshal(m, b)
+}
+func (m *ResultSignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResultSignature.Marshal(b, m, deterministic)
+}
+func (m *ResultSignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResultSignature.Merge(m, src)
+}
+func (m *ResultSignature) XXX_Size() int {
+	return xxx_messageInfo_ResultSignature.Size(m)
+}
+func (m *ResultSignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResultSignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResultSignature proto.InternalMessageInfo
+
+func (m *ResultSignature) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *ResultSignature) GetBatchIndex() int32 {
+	if m != nil {
+		return m.BatchIndex
+	}
+	return 0
+}
+
+func (m *ResultSignature) GetExecutor() []byte {
+	if m != nil {
+		return m.Executor
+	}
+	return nil
+}
+
+func (m *ResultSignature) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (m *ResultSignature) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ResultSignature) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+// QueuedTaskRequest is message sent to Executor server to manage a task waiting in the queue of the node,
+// it is signed by the private key of the node, as only the operator of the node is allowed to manage the queue
+type QueuedTaskRequest struct {
+	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	TaskID               string   `protobuf:"bytes,2,opt,name=taskID,proto3" json:"taskID,omitempty"`
+	Priority             int32    `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueuedTaskRequest) Reset()         { *m = QueuedTaskRequest{} }
+func (m *QueuedTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*QueuedTaskRequest) ProtoMessage()    {}
+func (*QueuedTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{23}
+}
+
+func (m *QueuedTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueuedTaskRequest.Unmarshal(m, b)
+}
+func (m *QueuedTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueuedTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *QueuedTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueuedTaskRequest.Merge(m, src)
+}
+func (m *QueuedTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_QueuedTaskRequest.Size(m)
+}
+func (m *QueuedTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueuedTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueuedTaskRequest proto.InternalMessageInfo
+
+func (m *QueuedTaskRequest) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *QueuedTaskRequest) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *QueuedTaskRequest) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *QueuedTaskRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// FeatureImportanceRequest is message sent to Executor server to get the importance of the local features of a model,
+// only the requester of the training task is allowed to get it
+type FeatureImportanceRequest struct {
+	PubKey               []byte   `protobuf:"bytes,1,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	ModelID              string   `protobuf:"bytes,2,opt,name=modelID,proto3" json:"modelID,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeatureImportanceRequest) Reset()         { *m = FeatureImportanceRequest{} }
+func (m *FeatureImportanceRequest) String() string { return proto.CompactTextString(m) }
+func (*FeatureImportanceRequest) ProtoMessage()    {}
+func (*FeatureImportanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{24}
+}
+
+func (m *FeatureImportanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureImportanceRequest.Unmarshal(m, b)
+}
+func (m *FeatureImportanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureImportanceRequest.Marshal(b, m, deterministic)
+}
+func (m *FeatureImportanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureImportanceRequest.Merge(m, src)
+}
+func (m *FeatureImportanceRequest) XXX_Size() int {
+	return xxx_messageInfo_FeatureImportanceRequest.Size(m)
+}
+func (m *FeatureImportanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureImportanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureImportanceRequest proto.InternalMessageInfo
+
+func (m *FeatureImportanceRequest) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *FeatureImportanceRequest) GetModelID() string {
+	if m != nil {
+		return m.ModelID
+	}
+	return ""
+}
+
+func (m *FeatureImportanceRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// FeatureImportance is the importance of a feature of a linear or logistic regression model, coefficient is the
+// standardized coefficient, the change of the output in the scaled label per standard deviation of the feature,
+// so that the coefficients of all parties are comparable, and importance is its absolute value
+type FeatureImportance struct {
+	Feature              string   `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	Coefficient          float64  `protobuf:"fixed64,2,opt,name=coefficient,proto3" json:"coefficient,omitempty"`
+	Importance           float64  `protobuf:"fixed64,3,opt,name=importance,proto3" json:"importance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeatureImportance) Reset()         { *m = FeatureImportance{} }
+func (m *FeatureImportance) String() string { return proto.CompactTextString(m) }
+func (*FeatureImportance) ProtoMessage()    {}
+func (*FeatureImportance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{25}
+}
+
+func (m *FeatureImportance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureImportance.Unmarshal(m, b)
+}
+func (m *FeatureImportance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureImportance.Marshal(b, m, deterministic)
+}
+func (m *FeatureImportance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureImportance.Merge(m, src)
+}
+func (m *FeatureImportance) XXX_Size() int {
+	return xxx_messageInfo_FeatureImportance.Size(m)
+}
+func (m *FeatureImportance) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureImportance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeatureImportance proto.InternalMessageInfo
+
+func (m *FeatureImportance) GetFeature() string {
+	if m != nil {
+		return m.Feature
+	}
+	return ""
+}
+
+func (m *FeatureImportance) GetCoefficient() float64 {
+	if m != nil {
+		return m.Coefficient
+	}
+	return 0
+}
+
+func (m *FeatureImportance) GetImportance() float64 {
+	if m != nil {
+		return m.Importance
+	}
+	return 0
+}
+
+// FeatureImportanceResponse is a message received from Executor, importances are the ones of the local features
+// of the model, sorted by importance in descending order
+type FeatureImportanceResponse struct {
+	ModelID              string               `protobuf:"bytes,1,opt,name=modelID,proto3" json:"modelID,omitempty"`
+	Importances          []*FeatureImportance `protobuf:"bytes,2,rep,name=importances,proto3" json:"importances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *PredictResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PredictResponse.Unmarshal(m, b)
+func (m *FeatureImportanceResponse) Reset()         { *m = FeatureImportanceResponse{} }
+func (m *FeatureImportanceResponse) String() string { return proto.CompactTextString(m) }
+func (*FeatureImportanceResponse) ProtoMessage()    {}
+func (*FeatureImportanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e8f2b86464a95fe, []int{26}
 }
-func (m *PredictResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PredictResponse.Marshal(b, m, deterministic)
+
+func (m *FeatureImportanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeatureImportanceResponse.Unmarshal(m, b)
 }
-func (m *PredictResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PredictResponse.Merge(m, src)
+func (m *FeatureImportanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeatureImportanceResponse.Marshal(b, m, deterministic)
 }
-func (m *PredictResponse) XXX_Size() int {
-	return xxx_messageInfo_PredictResponse.Size(m)
+func (m *FeatureImportanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeatureImportanceResponse.Merge(m, src)
 }
-func (m *PredictResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_PredictResponse.DiscardUnknown(m)
+func (m *FeatureImportanceResponse) XXX_Size() int {
+	return xxx_messageInfo_FeatureImportanceResponse.Size(m)
+}
+func (m *FeatureImportanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeatureImportanceResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PredictResponse proto.InternalMessageInfo
+var xxx_messageInfo_FeatureImportanceResponse proto.InternalMessageInfo
 
-func (m *PredictResponse) GetTaskID() string {
+func (m *FeatureImportanceResponse) GetModelID() string {
 	if m != nil {
-		return m.TaskID
+		return m.ModelID
 	}
 	return ""
 }
 
-func (m *PredictResponse) GetPayload() []byte {
+func (m *FeatureImportanceResponse) GetImportances() []*FeatureImportance {
 	if m != nil {
-		return m.Payload
+		return m.Importances
 	}
 	return nil
 }
@@ -558,64 +2000,173 @@ func init() {
 	proto.RegisterType((*TaskRequest)(nil), "task.TaskRequest")
 	proto.RegisterType((*TaskResponse)(nil), "task.TaskResponse")
 	proto.RegisterType((*ListTaskRequest)(nil), "task.ListTaskRequest")
+	proto.RegisterType((*ListTasksRequest)(nil), "task.ListTasksRequest")
+	proto.RegisterMapType((map[string]string)(nil), "task.ListTasksRequest.LabelsEntry")
+	proto.RegisterType((*TaskSummary)(nil), "task.TaskSummary")
+	proto.RegisterMapType((map[string]string)(nil), "task.TaskSummary.LabelsEntry")
+	proto.RegisterType((*TaskSummaries)(nil), "task.TaskSummaries")
 	proto.RegisterType((*DataForTask)(nil), "task.DataForTask")
+	proto.RegisterType((*BatchPredictResult)(nil), "task.BatchPredictResult")
 	proto.RegisterType((*FLTask)(nil), "task.FLTask")
 	proto.RegisterType((*FLTasks)(nil), "task.FLTasks")
 	proto.RegisterType((*GetTaskRequest)(nil), "task.GetTaskRequest")
 	proto.RegisterType((*PredictResponse)(nil), "task.PredictResponse")
+	proto.RegisterType((*ExportModelRequest)(nil), "task.ExportModelRequest")
+	proto.RegisterType((*ExportModelResponse)(nil), "task.ExportModelResponse")
+	proto.RegisterType((*LiveEvaluationRequest)(nil), "task.LiveEvaluationRequest")
+	proto.RegisterType((*LiveEvaluationMetric)(nil), "task.LiveEvaluationMetric")
+	proto.RegisterType((*NodeStatusRequest)(nil), "task.NodeStatusRequest")
+	proto.RegisterType((*NodeStatus)(nil), "task.NodeStatus")
+	proto.RegisterType((*NodeInfoRequest)(nil), "task.NodeInfoRequest")
+	proto.RegisterType((*NodeInfo)(nil), "task.NodeInfo")
+	proto.RegisterType((*TaskFingerprint)(nil), "task.TaskFingerprint")
+	proto.RegisterType((*VerifyResultRequest)(nil), "task.VerifyResultRequest")
+	proto.RegisterType((*ResultSignature)(nil), "task.ResultSignature")
+	proto.RegisterType((*QueuedTaskRequest)(nil), "task.QueuedTaskRequest")
+	proto.RegisterType((*FeatureImportanceRequest)(nil), "task.FeatureImportanceRequest")
+	proto.RegisterType((*FeatureImportance)(nil), "task.FeatureImportance")
+	proto.RegisterType((*FeatureImportanceResponse)(nil), "task.FeatureImportanceResponse")
 }
 
 func init() { proto.RegisterFile("task/task.proto", fileDescriptor_8e8f2b86464a95fe) }
 
 var fileDescriptor_8e8f2b86464a95fe = []byte{
-	// 742 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdf, 0x6e, 0xd3, 0x3e,
-	0x14, 0x56, 0xba, 0xae, 0x7f, 0x4e, 0xbb, 0xdf, 0xf6, 0x33, 0x1b, 0x44, 0x65, 0x9a, 0xaa, 0x5c,
-	0x4c, 0x15, 0x12, 0x0b, 0xeb, 0xee, 0xb8, 0x63, 0x94, 0x4d, 0x83, 0x81, 0xaa, 0xac, 0x17, 0x08,
-	0x6e, 0x70, 0x9b, 0xb3, 0xcc, 0x2c, 0x89, 0x83, 0xed, 0x00, 0xbd, 0xe5, 0x15, 0x78, 0x0a, 0xde,
-	0x80, 0xf7, 0xe0, 0x15, 0xb8, 0xe5, 0x19, 0x40, 0xb6, 0x93, 0x36, 0x9d, 0x86, 0xc4, 0x4d, 0x9b,
-	0xef, 0x1c, 0xfb, 0xf3, 0x77, 0xce, 0xf9, 0x6c, 0xd8, 0x54, 0x54, 0x5e, 0xfb, 0xfa, 0xe7, 0x20,
-	0x13, 0x5c, 0x71, 0x52, 0xd7, 0xdf, 0xbd, 0x3b, 0x33, 0x9e, 0x24, 0x3c, 0xf5, 0xed, 0x9f, 0x4d,
-	0xf5, 0x76, 0x23, 0xce, 0xa3, 0x18, 0x7d, 0x9a, 0x31, 0x9f, 0xa6, 0x29, 0x57, 0x54, 0x31, 0x9e,
-	0x4a, 0x9b, 0xf5, 0xde, 0x42, 0x67, 0x42, 0xe5, 0x75, 0x80, 0x1f, 0x72, 0x94, 0x8a, 0xdc, 0x85,
-	0x46, 0x96, 0x4f, 0x5f, 0xe0, 0xdc, 0x75, 0xfa, 0xce, 0xa0, 0x1b, 0x14, 0x48, 0xc7, 0xf5, 0x09,
-	0x67, 0x23, 0xb7, 0xd6, 0x77, 0x06, 0xed, 0xa0, 0x40, 0x64, 0x17, 0xda, 0x92, 0x45, 0x29, 0x55,
-	0xb9, 0x40, 0xb7, 0x6e, 0xb6, 0x2c, 0x03, 0xde, 0x3e, 0x74, 0x2d, 0xb9, 0xcc, 0x78, 0x2a, 0xf1,
-	0x6f, 0x2c, 0xde, 0x37, 0x07, 0x36, 0xcf, 0x99, 0x54, 0xff, 0xa2, 0xc4, 0x85, 0x26, 0x8e, 0x6d,
-	0xa2, 0x66, 0x12, 0x25, 0xd4, 0x3b, 0xa4, 0xa2, 0x2a, 0x97, 0xee, 0x9a, 0x65, 0xb7, 0x48, 0x6b,
-	0x54, 0x2c, 0xc1, 0x0b, 0x45, 0x85, 0x32, 0x1a, 0xd7, 0x82, 0x65, 0x40, 0xf3, 0x69, 0xf0, 0x2c,
-	0x0d, 0xdd, 0x75, 0x93, 0x2b, 0x21, 0xd9, 0x86, 0xf5, 0x98, 0x25, 0x4c, 0xb9, 0x0d, 0x13, 0xb7,
-	0xc0, 0xfb, 0xe5, 0x40, 0x67, 0x44, 0x15, 0x3d, 0xe1, 0x42, 0xcb, 0xd5, 0xab, 0xf8, 0xa7, 0x14,
-	0x45, 0x21, 0xd3, 0x02, 0xd2, 0x83, 0x16, 0x7e, 0xc6, 0x59, 0xae, 0xb8, 0x28, 0x64, 0x2e, 0xb0,
-	0xd6, 0x19, 0x52, 0x45, 0xcf, 0x46, 0xa5, 0x4e, 0x8b, 0xf4, 0x9e, 0x4c, 0xb2, 0x73, 0x3a, 0xc5,
-	0xd8, 0xc8, 0x6c, 0x07, 0x0b, 0x4c, 0xfa, 0xd0, 0x99, 0xf1, 0xf4, 0x92, 0x89, 0x04, 0xc3, 0x27,
-	0xaa, 0x50, 0x5a, 0x0d, 0x91, 0x3d, 0x00, 0x81, 0xef, 0x71, 0xa6, 0xcc, 0x02, 0x2b, 0xb9, 0x12,
-	0xd1, 0x75, 0xd2, 0x30, 0x14, 0x28, 0xa5, 0xdb, 0x34, 0xe4, 0x25, 0xd4, 0xfd, 0x61, 0x72, 0x42,
-	0xa3, 0xb1, 0xee, 0x4f, 0xab, 0xef, 0x0c, 0x5a, 0xc1, 0x32, 0xe0, 0xfd, 0xae, 0x41, 0xe3, 0xe4,
-	0xdc, 0x94, 0xba, 0x1c, 0x9f, 0xb3, 0x62, 0x02, 0x02, 0xf5, 0x94, 0x26, 0x58, 0x0c, 0xd5, 0x7c,
-	0x6b, 0xc1, 0x21, 0xca, 0x99, 0x60, 0x99, 0x76, 0x5b, 0x51, 0x69, 0x35, 0xa4, 0x8f, 0x15, 0x76,
-	0xd6, 0x28, 0x4a, 0xeb, 0x2c, 0x02, 0xe4, 0x21, 0xb4, 0x74, 0x5b, 0x2e, 0x50, 0x49, 0x77, 0xbd,
-	0xbf, 0x36, 0xe8, 0x0c, 0xff, 0x3f, 0x30, 0x7e, 0xaf, 0xf4, 0x3e, 0x58, 0x2c, 0x21, 0x8f, 0xa0,
-	0x4d, 0xe3, 0x88, 0x8f, 0xa9, 0xa0, 0x89, 0x29, 0xbe, 0x33, 0x24, 0x07, 0xc5, 0x35, 0xd0, 0x4b,
-	0x4d, 0x42, 0x06, 0xcb, 0x45, 0x15, 0xb7, 0x34, 0x57, 0xdc, 0xb2, 0x07, 0x80, 0x42, 0xbc, 0x44,
-	0x29, 0x69, 0x84, 0xa6, 0x1d, 0xed, 0xa0, 0x12, 0xd1, 0xfb, 0x04, 0xca, 0x3c, 0x56, 0x6e, 0xdb,
-	0xee, 0xb3, 0x48, 0x17, 0x9c, 0xe5, 0xd3, 0x98, 0xc9, 0xab, 0x09, 0x4b, 0xd0, 0x05, 0x3b, 0xa1,
-	0x4a, 0xc8, 0xdc, 0x15, 0x6d, 0x39, 0x93, 0xef, 0x58, 0x1f, 0x2e, 0x02, 0xc6, 0xd7, 0x69, 0x68,
-	0x72, 0x5d, 0xeb, 0xc3, 0x02, 0x7a, 0x87, 0xd0, 0xb4, 0x03, 0x90, 0x64, 0x1f, 0x9a, 0x97, 0xf6,
-	0xd3, 0x75, 0x4c, 0x53, 0xba, 0xb6, 0x29, 0x36, 0x1f, 0x94, 0x49, 0x6f, 0x00, 0xff, 0x9d, 0xe2,
-	0xcd, 0xeb, 0x74, 0xdb, 0xec, 0xbc, 0xa7, 0xb0, 0x39, 0x16, 0x18, 0xb2, 0x99, 0xba, 0xe5, 0x96,
-	0xae, 0x8e, 0xd9, 0x85, 0x66, 0x46, 0xe7, 0x31, 0xa7, 0x61, 0x79, 0xf3, 0x0a, 0x38, 0xfc, 0x5e,
-	0x83, 0xba, 0x71, 0xc8, 0x73, 0x68, 0x95, 0xf7, 0x98, 0xec, 0x58, 0x69, 0x37, 0xee, 0x75, 0x6f,
-	0xa3, 0xaa, 0x58, 0x7a, 0xee, 0x97, 0x1f, 0x3f, 0xbf, 0xd6, 0x88, 0xb7, 0xe1, 0x7f, 0x3c, 0x34,
-	0x0f, 0x9a, 0x1f, 0x33, 0xa9, 0x1e, 0x3b, 0x0f, 0xc8, 0x2b, 0xe8, 0x14, 0x35, 0x1c, 0xcf, 0xcf,
-	0x42, 0xb2, 0x6d, 0xf7, 0xad, 0x96, 0xd5, 0x5b, 0xa9, 0xdf, 0xbb, 0x6f, 0xc8, 0x76, 0xbc, 0xad,
-	0x05, 0x59, 0x84, 0x6a, 0x3a, 0x67, 0xa1, 0xe6, 0x7b, 0x07, 0x5b, 0xa7, 0xa8, 0x96, 0xc5, 0xea,
-	0xa1, 0x15, 0x9e, 0xaa, 0x32, 0x16, 0xb2, 0x6f, 0x34, 0xc5, 0xf3, 0x0c, 0xf5, 0xae, 0x77, 0x6f,
-	0x41, 0x9d, 0xd9, 0x15, 0x02, 0xa5, 0x3e, 0x45, 0x9f, 0x30, 0x84, 0xb6, 0x79, 0x53, 0x4c, 0xf9,
-	0xb7, 0x50, 0x93, 0x6a, 0xc8, 0xf2, 0x1e, 0x1f, 0xbd, 0x39, 0x8c, 0x98, 0xba, 0xca, 0xa7, 0xda,
-	0xad, 0xfe, 0x98, 0x86, 0x61, 0x8c, 0xf6, 0xb7, 0x00, 0xa3, 0xc9, 0x6b, 0x3f, 0xa4, 0xcc, 0x37,
-	0xcf, 0xb5, 0x34, 0x47, 0x4f, 0x1b, 0x06, 0x1c, 0xfd, 0x09, 0x00, 0x00, 0xff, 0xff, 0xb8, 0xf7,
-	0x49, 0x52, 0x07, 0x06, 0x00, 0x00,
+	// 2155 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x6e, 0x1c, 0x49,
+	0xf5, 0x57, 0xcf, 0x87, 0x3d, 0x53, 0x63, 0xc7, 0x76, 0xd9, 0x4e, 0x3a, 0xb3, 0xd9, 0xc4, 0x6a,
+	0xfd, 0xff, 0x8b, 0x59, 0x89, 0xcc, 0x26, 0x2b, 0xc4, 0x6e, 0xe0, 0x26, 0x8e, 0x9d, 0x60, 0xb0,
+	0xb3, 0xde, 0xb6, 0x13, 0xa1, 0x15, 0x12, 0x94, 0xbb, 0x6b, 0xc6, 0xb5, 0x99, 0xfe, 0xd8, 0xae,
+	0x6a, 0xaf, 0x67, 0x05, 0x37, 0x88, 0x37, 0xe0, 0x31, 0xb8, 0x02, 0xc1, 0x25, 0x57, 0x3c, 0x02,
+	0x17, 0x70, 0x8d, 0x78, 0x0e, 0x84, 0xce, 0xa9, 0xea, 0xee, 0xea, 0x9e, 0x71, 0xbc, 0xbb, 0x82,
+	0x1b, 0x7b, 0xce, 0xaf, 0xaa, 0x4e, 0x9d, 0xef, 0x73, 0xaa, 0xc9, 0x9a, 0x62, 0xf2, 0xcd, 0x08,
+	0xfe, 0x3c, 0x4c, 0xb3, 0x44, 0x25, 0xb4, 0x03, 0xbf, 0x87, 0x9b, 0x41, 0x12, 0x45, 0x49, 0x3c,
+	0xd2, 0xff, 0xf4, 0xd2, 0xf0, 0xde, 0x24, 0x49, 0x26, 0x53, 0x3e, 0x62, 0xa9, 0x18, 0xb1, 0x38,
+	0x4e, 0x14, 0x53, 0x22, 0x89, 0xa5, 0x5e, 0xf5, 0xfe, 0xe4, 0x90, 0xc1, 0x19, 0x93, 0x6f, 0x7c,
+	0xfe, 0x45, 0xce, 0xa5, 0xa2, 0xb7, 0xc9, 0x52, 0x9a, 0x9f, 0xff, 0x94, 0xcf, 0x5c, 0x67, 0xc7,
+	0xd9, 0x5d, 0xf1, 0x0d, 0x05, 0x38, 0x5c, 0x71, 0xb8, 0xef, 0xb6, 0x76, 0x9c, 0xdd, 0xbe, 0x6f,
+	0x28, 0x7a, 0x8f, 0xf4, 0xa5, 0x98, 0xc4, 0x4c, 0xe5, 0x19, 0x77, 0x3b, 0x78, 0xa4, 0x02, 0xe8,
+	0x7d, 0x42, 0xce, 0x99, 0x0a, 0x2e, 0x0e, 0xe3, 0x90, 0x5f, 0xb9, 0xdd, 0x1d, 0x67, 0xb7, 0xeb,
+	0x5b, 0x08, 0xfd, 0x01, 0x19, 0x8c, 0x45, 0x3c, 0xe1, 0x59, 0x9a, 0x89, 0x58, 0xb9, 0x4b, 0x3b,
+	0xce, 0xee, 0xe0, 0xf1, 0xf6, 0x43, 0x54, 0x0c, 0xa4, 0x7a, 0x5e, 0x2d, 0xfa, 0xf6, 0x4e, 0xef,
+	0x8f, 0x0e, 0x59, 0xd1, 0x62, 0xcb, 0x34, 0x89, 0x25, 0xbf, 0x56, 0x3e, 0x97, 0x2c, 0x47, 0x5c,
+	0x4a, 0x36, 0xe1, 0x6e, 0x1b, 0x17, 0x0a, 0x92, 0x7a, 0x64, 0x85, 0x4d, 0xc5, 0x24, 0x8e, 0x78,
+	0xac, 0x40, 0xdf, 0x0e, 0x2e, 0xd7, 0x30, 0xba, 0x4b, 0xd6, 0x4a, 0xfa, 0x19, 0x0b, 0x2e, 0x78,
+	0x88, 0x4a, 0xf4, 0xfc, 0x26, 0x0c, 0xdc, 0x52, 0x29, 0x9e, 0x4e, 0x27, 0x49, 0x26, 0xd4, 0x45,
+	0x84, 0xaa, 0xf4, 0xfd, 0x1a, 0xe6, 0xfd, 0xd5, 0x21, 0x6b, 0x47, 0x42, 0xaa, 0xaf, 0x63, 0x6f,
+	0x97, 0x2c, 0xf3, 0x13, 0xbd, 0xd0, 0xc2, 0x85, 0x82, 0x84, 0x13, 0x52, 0x31, 0x95, 0x4b, 0xa3,
+	0x90, 0xa1, 0xc0, 0x13, 0x4a, 0x44, 0xfc, 0x54, 0xb1, 0x4c, 0xa1, 0x32, 0x6d, 0xbf, 0x02, 0x80,
+	0x1f, 0x10, 0x07, 0xb1, 0xd6, 0xa0, 0xed, 0x17, 0x24, 0xdd, 0x22, 0xdd, 0xa9, 0x88, 0x84, 0xb6,
+	0x7e, 0xdb, 0xd7, 0x04, 0xec, 0x8f, 0xb9, 0xfa, 0x32, 0xc9, 0xde, 0xb8, 0xcb, 0xda, 0x6e, 0x86,
+	0xf4, 0xfe, 0xd2, 0x22, 0xeb, 0x85, 0x16, 0xd2, 0x52, 0xc3, 0x08, 0xe5, 0xd4, 0x84, 0x1a, 0x92,
+	0x1e, 0x38, 0xe2, 0x6c, 0x96, 0x72, 0xe3, 0x98, 0x92, 0xae, 0x0b, 0xdc, 0x7e, 0x8b, 0xc0, 0x9d,
+	0x6b, 0x04, 0xee, 0xda, 0x02, 0xdf, 0x26, 0x4b, 0xc9, 0x78, 0x2c, 0x79, 0xa1, 0x87, 0xa1, 0xe8,
+	0x13, 0xb2, 0x34, 0x65, 0xe7, 0x7c, 0x2a, 0xdd, 0xe5, 0x9d, 0xf6, 0xee, 0xe0, 0xb1, 0xa7, 0xa3,
+	0xab, 0xa9, 0xc1, 0xc3, 0x23, 0xdc, 0x74, 0x10, 0xab, 0x6c, 0xe6, 0x9b, 0x13, 0xb6, 0x11, 0x7a,
+	0x35, 0x23, 0x0c, 0x3f, 0x26, 0x03, 0xeb, 0x00, 0x5d, 0x27, 0xed, 0x37, 0xc6, 0x85, 0x7d, 0x1f,
+	0x7e, 0x82, 0x90, 0x97, 0x6c, 0x9a, 0x17, 0x5a, 0x6b, 0xe2, 0x49, 0xeb, 0x23, 0xc7, 0xfb, 0x7b,
+	0x5b, 0x67, 0xdc, 0x69, 0x1e, 0x45, 0x2c, 0xb3, 0x33, 0xcb, 0xa9, 0x45, 0xee, 0xdb, 0x4c, 0x77,
+	0x9f, 0x10, 0x0e, 0x1c, 0x31, 0x95, 0xd1, 0x76, 0x3d, 0xdf, 0x42, 0x2c, 0x77, 0x74, 0x9a, 0x31,
+	0x92, 0x69, 0x7d, 0x79, 0x86, 0xe6, 0x5b, 0xf1, 0x2b, 0x00, 0xb9, 0x66, 0xd9, 0xb1, 0x49, 0x17,
+	0x1d, 0xc1, 0x16, 0x42, 0x77, 0xc8, 0x20, 0xcd, 0xcf, 0xa7, 0x42, 0x5e, 0x9c, 0x89, 0x88, 0x63,
+	0x5c, 0xb4, 0x7d, 0x1b, 0x02, 0xfe, 0x12, 0xbc, 0x87, 0xeb, 0x3d, 0xed, 0xd2, 0x12, 0xc0, 0x98,
+	0x8e, 0x43, 0x5c, 0xeb, 0x6b, 0x97, 0x1a, 0x12, 0x38, 0x8b, 0xf8, 0xe0, 0x8a, 0x07, 0x39, 0x2a,
+	0x44, 0x50, 0x21, 0x1b, 0x02, 0x8d, 0xbe, 0xc8, 0x79, 0xce, 0x43, 0x77, 0x80, 0x8b, 0x86, 0xa2,
+	0xdf, 0x2f, 0xdd, 0xbb, 0x82, 0xee, 0x7d, 0xb7, 0x2a, 0x1e, 0xc6, 0xc0, 0x37, 0x79, 0x76, 0xf5,
+	0xbf, 0xe6, 0xd9, 0x8f, 0xc8, 0x6a, 0x75, 0xaf, 0xe0, 0x92, 0x7e, 0x87, 0x74, 0x41, 0x1a, 0x48,
+	0x0a, 0x90, 0x6d, 0x63, 0x4e, 0x36, 0x5f, 0xaf, 0x7b, 0xbf, 0x6f, 0x91, 0xc1, 0x3e, 0x53, 0xec,
+	0x79, 0x92, 0xc1, 0x2a, 0xdc, 0x91, 0x7c, 0x19, 0xf3, 0xcc, 0x14, 0x05, 0x4d, 0x40, 0x44, 0x70,
+	0x34, 0x48, 0x92, 0x99, 0xa2, 0x50, 0xd2, 0x60, 0x9f, 0x90, 0x29, 0x76, 0xb8, 0x5f, 0x54, 0x05,
+	0x4d, 0xc1, 0x99, 0x54, 0x0a, 0xd4, 0xc8, 0xc4, 0x42, 0x49, 0x83, 0xd5, 0x83, 0x24, 0x1e, 0x8b,
+	0x2c, 0xe2, 0xe1, 0xd3, 0x22, 0x9d, 0x6c, 0x08, 0x22, 0x22, 0xe3, 0x9f, 0xf3, 0x40, 0xe1, 0x06,
+	0x9d, 0x58, 0x16, 0x02, 0x66, 0x64, 0x61, 0x98, 0x71, 0x29, 0x8b, 0x2a, 0x61, 0x48, 0x88, 0x04,
+	0x21, 0xcf, 0xd8, 0xe4, 0x04, 0x92, 0xbb, 0x87, 0x2e, 0xab, 0x00, 0x38, 0x17, 0x24, 0xd3, 0x3c,
+	0x8a, 0xa5, 0xdb, 0xdf, 0x69, 0xc3, 0x39, 0x43, 0x42, 0x1d, 0xc5, 0xfe, 0xb0, 0x8f, 0xe2, 0x4b,
+	0x97, 0xe0, 0x72, 0x0d, 0xf3, 0x7e, 0x45, 0xe8, 0x1e, 0xd0, 0x27, 0x19, 0x0f, 0x45, 0xa0, 0x7c,
+	0x2e, 0xf3, 0xa9, 0x02, 0x9b, 0x09, 0x6c, 0x33, 0x0e, 0xb6, 0x19, 0x4d, 0x80, 0x5d, 0x32, 0x5c,
+	0x2f, 0xfa, 0x82, 0xa6, 0x1a, 0xb1, 0xde, 0x9e, 0x8b, 0x75, 0x97, 0x2c, 0x4b, 0x16, 0xa5, 0x53,
+	0x2e, 0x8b, 0xf2, 0x63, 0x48, 0xef, 0xdf, 0x1d, 0xb2, 0xf4, 0xfc, 0x08, 0xdd, 0x74, 0x5d, 0xea,
+	0x52, 0xd2, 0x89, 0x59, 0x54, 0x44, 0x08, 0xfe, 0x06, 0x63, 0x87, 0x5c, 0x06, 0x99, 0x48, 0xcb,
+	0x9c, 0xed, 0xfb, 0x36, 0x54, 0x4f, 0xce, 0x4e, 0x33, 0x39, 0xbf, 0x47, 0x7a, 0xe0, 0xd2, 0x53,
+	0xae, 0xa4, 0xdb, 0xb5, 0xc3, 0xc9, 0x8a, 0x1b, 0xbf, 0xdc, 0x42, 0x3f, 0x20, 0x7d, 0x36, 0x9d,
+	0x24, 0x27, 0x2c, 0x63, 0x91, 0xe9, 0xab, 0xf4, 0xa1, 0x99, 0x0b, 0x60, 0x2b, 0x2e, 0x48, 0xbf,
+	0xda, 0x64, 0xd5, 0x8c, 0xe5, 0x5a, 0xcd, 0xa8, 0x5b, 0xaa, 0x37, 0x67, 0xa9, 0xca, 0xc2, 0xfd,
+	0x9a, 0x85, 0x1b, 0xd5, 0x82, 0xdc, 0x50, 0x2d, 0x06, 0x6f, 0xa9, 0x16, 0x2b, 0xf5, 0x6a, 0xf1,
+	0x1e, 0xb9, 0x25, 0x42, 0x1e, 0xa5, 0x89, 0xe2, 0x71, 0x30, 0x83, 0x16, 0xa9, 0x73, 0xb8, 0x81,
+	0x42, 0x2c, 0x45, 0x49, 0xc8, 0xa7, 0xaf, 0x79, 0x26, 0xc1, 0xe6, 0xb7, 0x90, 0x4d, 0x0d, 0xa3,
+	0x3f, 0x24, 0xab, 0x69, 0x26, 0x2e, 0x59, 0x30, 0xdb, 0xcb, 0xc3, 0x09, 0x57, 0xee, 0x9a, 0x99,
+	0x41, 0x8c, 0xad, 0x4e, 0xec, 0x45, 0xbf, 0xbe, 0x97, 0xfe, 0xc8, 0x04, 0xab, 0x8e, 0x40, 0xe9,
+	0xae, 0xa3, 0x5f, 0x5c, 0xed, 0x97, 0xf9, 0x10, 0xf5, 0x6b, 0xbb, 0x41, 0xfd, 0x90, 0xa7, 0x3c,
+	0x0e, 0xe5, 0x27, 0xb1, 0xbb, 0x81, 0x71, 0x5e, 0x01, 0x76, 0x85, 0xa2, 0xf5, 0x06, 0xfc, 0x88,
+	0x2c, 0xeb, 0xf8, 0x93, 0xf4, 0x3d, 0xb2, 0x3c, 0x3e, 0x3a, 0xb3, 0x4a, 0xcc, 0x8a, 0xbe, 0x5b,
+	0xaf, 0xfb, 0xc5, 0xa2, 0xb7, 0x47, 0x6e, 0xbd, 0xe0, 0xcd, 0xb9, 0x63, 0x61, 0xe8, 0x5a, 0xd7,
+	0xb6, 0xea, 0xd7, 0x32, 0xb2, 0x56, 0x69, 0xd3, 0x1c, 0xba, 0xe6, 0x98, 0xa4, 0x6c, 0x36, 0x4d,
+	0x58, 0x58, 0x0c, 0x2f, 0x86, 0x04, 0x9d, 0x79, 0x1c, 0x64, 0xb3, 0x54, 0xf1, 0xd0, 0xf4, 0xad,
+	0x0a, 0xf0, 0x42, 0x42, 0x0f, 0xae, 0xd2, 0x24, 0x53, 0xc7, 0xe0, 0xa2, 0xaf, 0x31, 0x22, 0xa1,
+	0x2b, 0xcb, 0x99, 0xaf, 0x20, 0xeb, 0x43, 0x69, 0xbb, 0x31, 0x94, 0x7a, 0x9f, 0x91, 0xcd, 0xda,
+	0x2d, 0x46, 0x19, 0x8b, 0x9d, 0x53, 0x67, 0xf7, 0x5d, 0xd2, 0xc5, 0x9f, 0x78, 0xcd, 0xe0, 0xf1,
+	0x66, 0x99, 0x47, 0x19, 0x13, 0x31, 0x32, 0x91, 0xbe, 0xde, 0xe1, 0x8d, 0xc8, 0xf6, 0x91, 0xb8,
+	0xe4, 0x07, 0x65, 0x2b, 0xbe, 0xc1, 0xde, 0xde, 0x57, 0x64, 0xab, 0x7e, 0xe0, 0x98, 0xab, 0x4c,
+	0x04, 0xd7, 0x9a, 0x76, 0x8b, 0x74, 0xb3, 0x24, 0x8f, 0xb5, 0x61, 0x3b, 0xbe, 0x26, 0x20, 0x47,
+	0x23, 0x3c, 0xf7, 0x92, 0x45, 0x5a, 0xe3, 0xbe, 0x6f, 0x21, 0x55, 0xcf, 0x82, 0xb2, 0xe2, 0x98,
+	0x9e, 0xe5, 0x6d, 0x92, 0x8d, 0x97, 0x49, 0x08, 0xf3, 0x96, 0xca, 0x8b, 0x39, 0xc8, 0xfb, 0x6d,
+	0x87, 0x90, 0x0a, 0x05, 0xce, 0x0a, 0xd4, 0x2c, 0x82, 0x0c, 0x3b, 0x40, 0x85, 0xe0, 0xdc, 0xab,
+	0xa3, 0x42, 0xef, 0x68, 0xe9, 0x1c, 0xb3, 0x31, 0xc8, 0xd7, 0xf2, 0xc4, 0x11, 0x4e, 0x6e, 0x7a,
+	0xda, 0x6b, 0xa0, 0xf4, 0x7d, 0xb2, 0x6e, 0x9d, 0xd3, 0x3b, 0x75, 0xf1, 0x9d, 0xc3, 0x61, 0x32,
+	0x8f, 0xd8, 0x15, 0xd0, 0xc7, 0x3c, 0x4a, 0xb2, 0xd9, 0xf1, 0x9e, 0xe9, 0x5f, 0x4d, 0xd8, 0xda,
+	0xf9, 0xec, 0xe4, 0xd5, 0xb3, 0x24, 0xe3, 0xd2, 0x34, 0xb2, 0x26, 0x0c, 0x72, 0x46, 0x78, 0x4a,
+	0xa7, 0xf7, 0xf1, 0x9e, 0x19, 0x71, 0x1a, 0x28, 0xec, 0x0b, 0xd2, 0x5c, 0x93, 0x9a, 0xa1, 0x1e,
+	0x75, 0x1a, 0x28, 0xe8, 0xa3, 0x4f, 0xfa, 0x5c, 0xf2, 0xec, 0x92, 0x87, 0xc7, 0x7b, 0x66, 0xf0,
+	0x99, 0xc3, 0x61, 0x6f, 0x90, 0xe6, 0x05, 0xa0, 0xb9, 0xea, 0x92, 0x39, 0x87, 0x63, 0x5d, 0xc3,
+	0xf3, 0xaf, 0x24, 0xf2, 0x1c, 0x98, 0xba, 0x66, 0x61, 0x50, 0x7d, 0xf5, 0x84, 0xa4, 0xdd, 0xa2,
+	0x2b, 0xa8, 0x0d, 0x41, 0x92, 0x20, 0x79, 0x2a, 0xbe, 0xe2, 0x58, 0x40, 0xdb, 0x7e, 0x05, 0x78,
+	0x1b, 0x64, 0x0d, 0xa2, 0xe0, 0x30, 0x1e, 0x27, 0x45, 0x64, 0xfc, 0xc3, 0x21, 0xbd, 0x02, 0x2b,
+	0x5b, 0x9c, 0x63, 0xb5, 0xb8, 0xff, 0x23, 0xab, 0x58, 0xde, 0x83, 0xa7, 0x66, 0x26, 0xd0, 0x69,
+	0x59, 0x07, 0xe1, 0x5e, 0x0d, 0x40, 0x46, 0xeb, 0x50, 0xad, 0x00, 0x88, 0x37, 0x56, 0x3c, 0x98,
+	0xa0, 0xf5, 0x42, 0x55, 0xb4, 0x10, 0xc8, 0xd2, 0x4b, 0x53, 0xce, 0xbb, 0x3a, 0x4b, 0x0d, 0x09,
+	0x7c, 0x27, 0x42, 0x3d, 0x4b, 0xa2, 0xe2, 0x2d, 0xd3, 0xf7, 0x2b, 0x00, 0x56, 0xcf, 0x73, 0x31,
+	0x0d, 0xf7, 0x99, 0xe2, 0xa6, 0xc1, 0x55, 0x80, 0xf7, 0xe7, 0x16, 0x59, 0x6b, 0xbc, 0x37, 0x21,
+	0x6e, 0xf0, 0x89, 0x1c, 0x24, 0x65, 0x03, 0xd1, 0x93, 0x45, 0x13, 0x06, 0x5b, 0x80, 0x84, 0x45,
+	0xbb, 0x87, 0xdf, 0xb5, 0xe9, 0xbd, 0xdd, 0x98, 0xde, 0x9b, 0x6f, 0xc5, 0xce, 0xfc, 0x5b, 0x11,
+	0xec, 0x90, 0x62, 0x8b, 0xfe, 0x31, 0x93, 0x17, 0x46, 0x55, 0x0b, 0x01, 0xfe, 0x17, 0x4c, 0xea,
+	0xb9, 0x6e, 0x09, 0xeb, 0x68, 0x49, 0x83, 0x1f, 0xcc, 0xb0, 0xb2, 0x2f, 0x26, 0x5c, 0x2a, 0xa3,
+	0x6f, 0x1d, 0x84, 0x5d, 0xf6, 0x5b, 0x17, 0x82, 0x18, 0x8c, 0x5d, 0x07, 0xb1, 0x94, 0xe2, 0xb1,
+	0x97, 0x79, 0x64, 0x82, 0xb7, 0x02, 0xbc, 0x63, 0xb2, 0xf9, 0x9a, 0x67, 0x62, 0x3c, 0x33, 0x0d,
+	0xee, 0x86, 0xe6, 0x52, 0xff, 0x1c, 0xd0, 0x6a, 0x7e, 0x0e, 0xf0, 0xfe, 0xe0, 0x90, 0x35, 0xcd,
+	0xe9, 0xb4, 0xfc, 0x84, 0xf0, 0x2d, 0x79, 0xd5, 0x86, 0xe5, 0xf6, 0x82, 0x61, 0x59, 0x5b, 0x46,
+	0x8f, 0x59, 0x86, 0xaa, 0xf7, 0x8d, 0x6e, 0xf3, 0x63, 0x86, 0x2e, 0xa2, 0x22, 0x34, 0xf6, 0xd6,
+	0x84, 0xf7, 0x6b, 0xb2, 0xf1, 0x69, 0x99, 0x55, 0xdf, 0xf6, 0x2b, 0x0a, 0x4c, 0xe9, 0x99, 0x00,
+	0xd7, 0xeb, 0x94, 0xe8, 0xfa, 0x25, 0xfd, 0xf6, 0x2f, 0x2c, 0xde, 0xe7, 0xc4, 0x7d, 0xce, 0xf1,
+	0xe7, 0x61, 0x04, 0x3d, 0x8d, 0xc5, 0x01, 0xff, 0x5f, 0x35, 0xce, 0x84, 0x6c, 0xcc, 0xdd, 0x05,
+	0xcc, 0xc6, 0x1a, 0x2c, 0xda, 0xa6, 0x21, 0xf5, 0xf3, 0x82, 0x8f, 0xc7, 0x22, 0x10, 0x3c, 0xd6,
+	0xf3, 0xb7, 0xe3, 0xdb, 0x10, 0xf8, 0x50, 0x94, 0x9c, 0xf0, 0x3e, 0xc7, 0xb7, 0x10, 0x2f, 0x25,
+	0x77, 0x17, 0x28, 0x77, 0x63, 0xbf, 0xfe, 0x98, 0x0c, 0x2a, 0x26, 0x50, 0x85, 0x60, 0x32, 0xba,
+	0x63, 0x26, 0xa3, 0x39, 0x7e, 0xf6, 0xde, 0xc7, 0xff, 0xec, 0x93, 0x0e, 0x8e, 0xf6, 0x3f, 0x21,
+	0xbd, 0xe2, 0x13, 0x01, 0xdd, 0xae, 0x7f, 0x32, 0x30, 0xe6, 0x1d, 0xae, 0xda, 0xb3, 0x96, 0xf4,
+	0xdc, 0xdf, 0xfc, 0xed, 0x5f, 0xbf, 0x6b, 0xd1, 0x27, 0xce, 0xfb, 0xde, 0xea, 0xe8, 0xf2, 0x11,
+	0x7e, 0x9d, 0x1b, 0x4d, 0x85, 0x54, 0xf4, 0x15, 0xe9, 0x17, 0x67, 0x25, 0xbd, 0xbd, 0xf8, 0xfb,
+	0xc3, 0x70, 0xb3, 0xf9, 0x38, 0x14, 0x5c, 0x7a, 0xef, 0x20, 0xcf, 0x6d, 0xe0, 0xb9, 0x5e, 0xf2,
+	0xbc, 0x10, 0x52, 0x25, 0xd9, 0x8c, 0xbe, 0x24, 0x03, 0x33, 0xd4, 0xed, 0xcd, 0x0e, 0x43, 0xba,
+	0xa5, 0x19, 0xd4, 0xe7, 0xbc, 0x61, 0x6d, 0x20, 0x5c, 0xcc, 0x6f, 0xc2, 0xd5, 0xf9, 0x4c, 0x84,
+	0xf4, 0x97, 0x64, 0xfd, 0x05, 0x57, 0xf5, 0x47, 0x95, 0xf5, 0x64, 0x2d, 0x38, 0x1a, 0x6b, 0x34,
+	0x66, 0x41, 0xcf, 0x43, 0xd6, 0xf7, 0x80, 0xf5, 0x9d, 0x92, 0xb5, 0x69, 0xdb, 0x19, 0x97, 0x70,
+	0x0b, 0x7d, 0x4c, 0xfa, 0xf8, 0x71, 0x07, 0xad, 0xba, 0x80, 0x35, 0xb5, 0x21, 0xe3, 0xe6, 0x4f,
+	0x08, 0x79, 0x06, 0xbe, 0x99, 0x7e, 0x83, 0x43, 0xde, 0x10, 0x85, 0xd9, 0x02, 0x61, 0xd6, 0x4a,
+	0x61, 0x02, 0x64, 0x43, 0x3f, 0x25, 0x5b, 0xa7, 0x2a, 0xe3, 0x2c, 0xaa, 0xcf, 0x5d, 0xf4, 0x9d,
+	0xc2, 0x31, 0x0b, 0xc6, 0xb7, 0xe1, 0x70, 0xd1, 0xa2, 0x1e, 0xd5, 0x3e, 0x70, 0xe8, 0x6b, 0xb2,
+	0xfa, 0x82, 0x2b, 0x6b, 0x6a, 0x32, 0xc1, 0x36, 0x37, 0x5d, 0x0d, 0xd7, 0x9b, 0x0b, 0x73, 0xa2,
+	0xc6, 0x49, 0xc8, 0x47, 0xe6, 0xe9, 0xf5, 0x0b, 0x32, 0xb0, 0x26, 0x55, 0x6a, 0x1e, 0x16, 0xf3,
+	0x23, 0xf2, 0xf0, 0xee, 0x82, 0x15, 0x63, 0x8a, 0xa6, 0xcb, 0x31, 0x49, 0x46, 0x1c, 0x77, 0x9a,
+	0x10, 0x2a, 0x9b, 0xfa, 0x76, 0x25, 0x9d, 0xd5, 0xf8, 0x87, 0xb7, 0xea, 0xf0, 0x5c, 0xa4, 0xa3,
+	0xc8, 0x02, 0x18, 0x4c, 0xc8, 0x8a, 0xdd, 0x0f, 0xa8, 0x91, 0x6b, 0x41, 0x8f, 0x28, 0xc2, 0xa8,
+	0x51, 0xee, 0xbd, 0xff, 0x47, 0xde, 0x0f, 0x80, 0xf7, 0x70, 0x51, 0x18, 0x5d, 0x22, 0x2b, 0xfa,
+	0x73, 0xb2, 0xae, 0xa3, 0xa2, 0xaa, 0xbd, 0x85, 0xd1, 0xe7, 0xaa, 0xf1, 0xc2, 0x08, 0x69, 0x9a,
+	0x05, 0x27, 0x9f, 0x22, 0x44, 0x02, 0xb2, 0x7d, 0xca, 0x55, 0xc5, 0xe8, 0xa4, 0xa8, 0xc5, 0xdf,
+	0xe8, 0x8a, 0x77, 0xf1, 0x8a, 0x3b, 0x70, 0x05, 0xad, 0xae, 0x28, 0xeb, 0xfa, 0x15, 0xd9, 0x7a,
+	0xc1, 0xd5, 0x7c, 0x41, 0xbd, 0x7f, 0x5d, 0xa1, 0x32, 0x57, 0x3d, 0xb8, 0x76, 0xdd, 0xdc, 0xfb,
+	0x00, 0xef, 0xbd, 0x0b, 0xf7, 0x6e, 0x55, 0x1e, 0xaf, 0xaa, 0xdc, 0xde, 0x87, 0x9f, 0x3d, 0x9a,
+	0x08, 0x75, 0x91, 0x9f, 0xc3, 0x43, 0x66, 0x74, 0xc2, 0xc2, 0x70, 0xca, 0xf5, 0x5f, 0x43, 0xec,
+	0x9f, 0xfd, 0x6c, 0x14, 0x32, 0x31, 0xc2, 0x41, 0x47, 0xa2, 0x0f, 0xce, 0x97, 0x90, 0xf8, 0xf0,
+	0x3f, 0x03, 0x00, 0xa1, 0x7f, 0x52, 0xb8, 0x7b, 0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -632,12 +2183,42 @@ const _ = grpc.SupportPackageIsVersion4
 type TaskClient interface {
 	// ListTask is provided by Executor server for Executor client to list tasks with filters.
 	ListTask(ctx context.Context, in *ListTaskRequest, opts ...grpc.CallOption) (*FLTasks, error)
+	// ListTasks is provided by Executor server to query the history of tasks the executor participates in,
+	// with filters on status, type and time window, and pagination.
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*TaskSummaries, error)
 	// GetTaskById is provided by Executor server for Executor client to query a task.
 	GetTaskById(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*FLTask, error)
 	// GetPredictResult is provided by Executor server for Executor client to get prediction result.
 	GetPredictResult(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*PredictResponse, error)
 	// StartTask is for Executors to request remote ones to start a task.
 	StartTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// CancelTask is provided by Executor server for the requester to cancel a task in execution,
+	// and for Executors to request remote ones to cancel the task.
+	CancelTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// StreamLiveEvaluation is provided by Executor server to push the metric scores of live evaluation
+	// as they're calculated during training, the stream ends when the task ends or is cancelled.
+	StreamLiveEvaluation(ctx context.Context, in *LiveEvaluationRequest, opts ...grpc.CallOption) (Task_StreamLiveEvaluationClient, error)
+	// GetNodeStatus is provided by Executor server to query tasks in execution and resources usage.
+	GetNodeStatus(ctx context.Context, in *NodeStatusRequest, opts ...grpc.CallOption) (*NodeStatus, error)
+	// ExportModel is provided by Executor server for the requester of a training task to get the local part
+	// of the linear or logistic regression model, the requester combines the parts of all executors
+	// into a model of a standard format, see crypto/vl/export.
+	ExportModel(ctx context.Context, in *ExportModelRequest, opts ...grpc.CallOption) (*ExportModelResponse, error)
+	// GetNodeInfo is provided by Executor server to query the identity of the node,
+	// with which other parties register the executor.
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	// VerifyResult is provided by Executor server to verify the signature of a prediction result stored by the node,
+	// against the public key of the node, so that downstream consumers can check where the result comes from.
+	VerifyResult(ctx context.Context, in *VerifyResultRequest, opts ...grpc.CallOption) (*ResultSignature, error)
+	// CancelQueuedTask is provided by Executor server for the operator of the node to cancel a task waiting in
+	// the queue of the node, which is recorded 'Cancelled' in blockchain. Tasks in execution are cancelled by CancelTask.
+	CancelQueuedTask(ctx context.Context, in *QueuedTaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// SetQueuedTaskPriority is provided by Executor server for the operator of the node to change the priority
+	// of a task waiting in the queue of the node, the priority recorded in blockchain is not changed.
+	SetQueuedTaskPriority(ctx context.Context, in *QueuedTaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// GetFeatureImportance is provided by Executor server for the requester of a training task to get the importance
+	// of the local features of the linear or logistic regression model, each executor reports only its own features.
+	GetFeatureImportance(ctx context.Context, in *FeatureImportanceRequest, opts ...grpc.CallOption) (*FeatureImportanceResponse, error)
 }
 
 type taskClient struct {
@@ -657,6 +2238,15 @@ func (c *taskClient) ListTask(ctx context.Context, in *ListTaskRequest, opts ...
 	return out, nil
 }
 
+func (c *taskClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*TaskSummaries, error) {
+	out := new(TaskSummaries)
+	err := c.cc.Invoke(ctx, "/task.Task/ListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taskClient) GetTaskById(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*FLTask, error) {
 	out := new(FLTask)
 	err := c.cc.Invoke(ctx, "/task.Task/GetTaskById", in, out, opts...)
@@ -684,16 +2274,150 @@ func (c *taskClient) StartTask(ctx context.Context, in *TaskRequest, opts ...grp
 	return out, nil
 }
 
+func (c *taskClient) CancelTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error) {
+	out := new(TaskResponse)
+	err := c.cc.Invoke(ctx, "/task.Task/CancelTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) StreamLiveEvaluation(ctx context.Context, in *LiveEvaluationRequest, opts ...grpc.CallOption) (Task_StreamLiveEvaluationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Task_serviceDesc.Streams[0], "/task.Task/StreamLiveEvaluation", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskStreamLiveEvaluationClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Task_StreamLiveEvaluationClient interface {
+	Recv() (*LiveEvaluationMetric, error)
+	grpc.ClientStream
+}
+
+type taskStreamLiveEvaluationClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskStreamLiveEvaluationClient) Recv() (*LiveEvaluationMetric, error) {
+	m := new(LiveEvaluationMetric)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskClient) GetNodeStatus(ctx context.Context, in *NodeStatusRequest, opts ...grpc.CallOption) (*NodeStatus, error) {
+	out := new(NodeStatus)
+	err := c.cc.Invoke(ctx, "/task.Task/GetNodeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) ExportModel(ctx context.Context, in *ExportModelRequest, opts ...grpc.CallOption) (*ExportModelResponse, error) {
+	out := new(ExportModelResponse)
+	err := c.cc.Invoke(ctx, "/task.Task/ExportModel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := c.cc.Invoke(ctx, "/task.Task/GetNodeInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) VerifyResult(ctx context.Context, in *VerifyResultRequest, opts ...grpc.CallOption) (*ResultSignature, error) {
+	out := new(ResultSignature)
+	err := c.cc.Invoke(ctx, "/task.Task/VerifyResult", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) CancelQueuedTask(ctx context.Context, in *QueuedTaskRequest, opts ...grpc.CallOption) (*TaskResponse, error) {
+	out := new(TaskResponse)
+	err := c.cc.Invoke(ctx, "/task.Task/CancelQueuedTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) SetQueuedTaskPriority(ctx context.Context, in *QueuedTaskRequest, opts ...grpc.CallOption) (*TaskResponse, error) {
+	out := new(TaskResponse)
+	err := c.cc.Invoke(ctx, "/task.Task/SetQueuedTaskPriority", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskClient) GetFeatureImportance(ctx context.Context, in *FeatureImportanceRequest, opts ...grpc.CallOption) (*FeatureImportanceResponse, error) {
+	out := new(FeatureImportanceResponse)
+	err := c.cc.Invoke(ctx, "/task.Task/GetFeatureImportance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskServer is the server API for Task service.
 type TaskServer interface {
 	// ListTask is provided by Executor server for Executor client to list tasks with filters.
 	ListTask(context.Context, *ListTaskRequest) (*FLTasks, error)
+	// ListTasks is provided by Executor server to query the history of tasks the executor participates in,
+	// with filters on status, type and time window, and pagination.
+	ListTasks(context.Context, *ListTasksRequest) (*TaskSummaries, error)
 	// GetTaskById is provided by Executor server for Executor client to query a task.
 	GetTaskById(context.Context, *GetTaskRequest) (*FLTask, error)
 	// GetPredictResult is provided by Executor server for Executor client to get prediction result.
 	GetPredictResult(context.Context, *TaskRequest) (*PredictResponse, error)
 	// StartTask is for Executors to request remote ones to start a task.
 	StartTask(context.Context, *TaskRequest) (*TaskResponse, error)
+	// CancelTask is provided by Executor server for the requester to cancel a task in execution,
+	// and for Executors to request remote ones to cancel the task.
+	CancelTask(context.Context, *TaskRequest) (*TaskResponse, error)
+	// StreamLiveEvaluation is provided by Executor server to push the metric scores of live evaluation
+	// as they're calculated during training, the stream ends when the task ends or is cancelled.
+	StreamLiveEvaluation(*LiveEvaluationRequest, Task_StreamLiveEvaluationServer) error
+	// GetNodeStatus is provided by Executor server to query tasks in execution and resources usage.
+	GetNodeStatus(context.Context, *NodeStatusRequest) (*NodeStatus, error)
+	// ExportModel is provided by Executor server for the requester of a training task to get the local part
+	// of the linear or logistic regression model, the requester combines the parts of all executors
+	// into a model of a standard format, see crypto/vl/export.
+	ExportModel(context.Context, *ExportModelRequest) (*ExportModelResponse, error)
+	// GetNodeInfo is provided by Executor server to query the identity of the node,
+	// with which other parties register the executor.
+	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
+	// VerifyResult is provided by Executor server to verify the signature of a prediction result stored by the node,
+	// against the public key of the node, so that downstream consumers can check where the result comes from.
+	VerifyResult(context.Context, *VerifyResultRequest) (*ResultSignature, error)
+	// CancelQueuedTask is provided by Executor server for the operator of the node to cancel a task waiting in
+	// the queue of the node, which is recorded 'Cancelled' in blockchain. Tasks in execution are cancelled by CancelTask.
+	CancelQueuedTask(context.Context, *QueuedTaskRequest) (*TaskResponse, error)
+	// SetQueuedTaskPriority is provided by Executor server for the operator of the node to change the priority
+	// of a task waiting in the queue of the node, the priority recorded in blockchain is not changed.
+	SetQueuedTaskPriority(context.Context, *QueuedTaskRequest) (*TaskResponse, error)
+	// GetFeatureImportance is provided by Executor server for the requester of a training task to get the importance
+	// of the local features of the linear or logistic regression model, each executor reports only its own features.
+	GetFeatureImportance(context.Context, *FeatureImportanceRequest) (*FeatureImportanceResponse, error)
 }
 
 // UnimplementedTaskServer can be embedded to have forward compatible implementations.
@@ -703,6 +2427,9 @@ type UnimplementedTaskServer struct {
 func (*UnimplementedTaskServer) ListTask(ctx context.Context, req *ListTaskRequest) (*FLTasks, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTask not implemented")
 }
+func (*UnimplementedTaskServer) ListTasks(ctx context.Context, req *ListTasksRequest) (*TaskSummaries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
 func (*UnimplementedTaskServer) GetTaskById(ctx context.Context, req *GetTaskRequest) (*FLTask, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTaskById not implemented")
 }
@@ -712,6 +2439,33 @@ func (*UnimplementedTaskServer) GetPredictResult(ctx context.Context, req *TaskR
 func (*UnimplementedTaskServer) StartTask(ctx context.Context, req *TaskRequest) (*TaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartTask not implemented")
 }
+func (*UnimplementedTaskServer) CancelTask(ctx context.Context, req *TaskRequest) (*TaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTask not implemented")
+}
+func (*UnimplementedTaskServer) StreamLiveEvaluation(req *LiveEvaluationRequest, srv Task_StreamLiveEvaluationServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLiveEvaluation not implemented")
+}
+func (*UnimplementedTaskServer) GetNodeStatus(ctx context.Context, req *NodeStatusRequest) (*NodeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeStatus not implemented")
+}
+func (*UnimplementedTaskServer) ExportModel(ctx context.Context, req *ExportModelRequest) (*ExportModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportModel not implemented")
+}
+func (*UnimplementedTaskServer) GetNodeInfo(ctx context.Context, req *NodeInfoRequest) (*NodeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (*UnimplementedTaskServer) VerifyResult(ctx context.Context, req *VerifyResultRequest) (*ResultSignature, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyResult not implemented")
+}
+func (*UnimplementedTaskServer) CancelQueuedTask(ctx context.Context, req *QueuedTaskRequest) (*TaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelQueuedTask not implemented")
+}
+func (*UnimplementedTaskServer) SetQueuedTaskPriority(ctx context.Context, req *QueuedTaskRequest) (*TaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQueuedTaskPriority not implemented")
+}
+func (*UnimplementedTaskServer) GetFeatureImportance(ctx context.Context, req *FeatureImportanceRequest) (*FeatureImportanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeatureImportance not implemented")
+}
 
 func RegisterTaskServer(s *grpc.Server, srv TaskServer) {
 	s.RegisterService(&_Task_serviceDesc, srv)
@@ -735,6 +2489,24 @@ func _Task_ListTask_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Task_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Task_GetTaskById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTaskRequest)
 	if err := dec(in); err != nil {
@@ -789,6 +2561,171 @@ func _Task_StartTask_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Task_CancelTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).CancelTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/CancelTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).CancelTask(ctx, req.(*TaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_StreamLiveEvaluation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LiveEvaluationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskServer).StreamLiveEvaluation(m, &taskStreamLiveEvaluationServer{stream})
+}
+
+type Task_StreamLiveEvaluationServer interface {
+	Send(*LiveEvaluationMetric) error
+	grpc.ServerStream
+}
+
+type taskStreamLiveEvaluationServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskStreamLiveEvaluationServer) Send(m *LiveEvaluationMetric) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Task_GetNodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).GetNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/GetNodeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).GetNodeStatus(ctx, req.(*NodeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_ExportModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).ExportModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/ExportModel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).ExportModel(ctx, req.(*ExportModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/GetNodeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_VerifyResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).VerifyResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/VerifyResult",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).VerifyResult(ctx, req.(*VerifyResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_CancelQueuedTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueuedTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).CancelQueuedTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/CancelQueuedTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).CancelQueuedTask(ctx, req.(*QueuedTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_SetQueuedTaskPriority_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueuedTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).SetQueuedTaskPriority(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/SetQueuedTaskPriority",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).SetQueuedTaskPriority(ctx, req.(*QueuedTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Task_GetFeatureImportance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeatureImportanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServer).GetFeatureImportance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/task.Task/GetFeatureImportance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServer).GetFeatureImportance(ctx, req.(*FeatureImportanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Task_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "task.Task",
 	HandlerType: (*TaskServer)(nil),
@@ -797,6 +2734,10 @@ var _Task_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListTask",
 			Handler:    _Task_ListTask_Handler,
 		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _Task_ListTasks_Handler,
+		},
 		{
 			MethodName: "GetTaskById",
 			Handler:    _Task_GetTaskById_Handler,
@@ -809,7 +2750,45 @@ var _Task_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StartTask",
 			Handler:    _Task_StartTask_Handler,
 		},
+		{
+			MethodName: "CancelTask",
+			Handler:    _Task_CancelTask_Handler,
+		},
+		{
+			MethodName: "GetNodeStatus",
+			Handler:    _Task_GetNodeStatus_Handler,
+		},
+		{
+			MethodName: "ExportModel",
+			Handler:    _Task_ExportModel_Handler,
+		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _Task_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "VerifyResult",
+			Handler:    _Task_VerifyResult_Handler,
+		},
+		{
+			MethodName: "CancelQueuedTask",
+			Handler:    _Task_CancelQueuedTask_Handler,
+		},
+		{
+			MethodName: "SetQueuedTaskPriority",
+			Handler:    _Task_SetQueuedTaskPriority_Handler,
+		},
+		{
+			MethodName: "GetFeatureImportance",
+			Handler:    _Task_GetFeatureImportance_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLiveEvaluation",
+			Handler:       _Task_StreamLiveEvaluation_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "task/task.proto",
 }
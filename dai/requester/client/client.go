@@ -14,11 +14,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
@@ -32,9 +34,14 @@ import (
 	fabricblockchain "github.com/PaddlePaddle/PaddleDTX/dai/blockchain/fabric"
 	xchainblockchain "github.com/PaddlePaddle/PaddleDTX/dai/blockchain/xchain"
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	vl_common "github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common"
 	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/common/csv"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/export"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/xgboost"
 	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/envelope"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
 	xdbchain "github.com/PaddlePaddle/PaddleDTX/xdb/blockchain"
 	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
 )
@@ -56,11 +63,14 @@ type Blockchain interface {
 	GetFileByID(id string) (xdbchain.File, error)
 	ListFileAuthApplications(opt *xdbchain.ListFileAuthOptions) (xdbchain.FileAuthApplications, error)
 	GetAuthApplicationByID(authID string) (xdbchain.FileAuthApplication, error)
+	PublishColumnPolicy(opt *blockchain.PublishColumnPolicyOptions) error
+	GetColumnPolicy(fileID string, executor []byte) (blockchain.ColumnPolicy, error)
 }
 
 // Client requester client, used to publish task and retrieve task result
 type Client struct {
 	chainClient Blockchain
+	dialOpt     grpc.DialOption // transport credentials to connect to executors, plaintext if nil
 }
 
 func newChainClient(conf *config.ExecutorBlockchainConf) (b Blockchain, err error) {
@@ -91,6 +101,26 @@ func GetRequestClient(configPath string) (*Client, error) {
 	return &Client{chainClient: chainClient}, nil
 }
 
+// SetExecutorTLS sets the TLS credentials to connect to executors whose gRPC servers require TLS,
+// executors are connected in plaintext if conf is nil
+func (c *Client) SetExecutorTLS(conf *config.TLSConf) error {
+	dialOpt, err := tlsutil.DialOption(conf)
+	if err != nil {
+		return err
+	}
+	c.dialOpt = dialOpt
+	return nil
+}
+
+// dialExecutor connects to the gRPC server of the executor at address
+func (c *Client) dialExecutor(address string) (*grpc.ClientConn, error) {
+	dialOpt := c.dialOpt
+	if dialOpt == nil {
+		dialOpt = grpc.WithInsecure()
+	}
+	return grpc.Dial(address, dialOpt)
+}
+
 // PublishOptions define parameters used to publishing a task
 type PublishOptions struct {
 	PrivateKey  string           // requester private key
@@ -99,7 +129,27 @@ type PublishOptions struct {
 	TaskName    string           // task name, not unique for one requester
 	AlgoParam   pbCom.TaskParams // parameters required for training or prediction
 	PSILabels   string           // ID feature name list with "," as delimiter, used for PSI
+	Columns     string           // feature columns of each file with ";" between files and "," between columns, all if empty
 	Description string           // task description
+	// IdempotencyKey identifies the submission of a task, a task submitted again with the same key
+	// is not published twice, and the existing one is returned instead
+	IdempotencyKey string
+	// BatchFiles lists the other inputs of a batch prediction, predicted along with Files in one session,
+	// with ";" between inputs and "," between the files of an input, in the order of Executors
+	BatchFiles string
+	// DependsOn lists the IDs of the upstream tasks with "," as delimiter, the task starts after all of them finish,
+	// and fails if any of them ends unfinished. A prediction task can use the model trained by an upstream task.
+	DependsOn string
+	// EncryptResult encrypts the result of a prediction task with the requester's public key, so that only the
+	// requester decrypts it with the private key, even if it is stored in untrusted storage
+	EncryptResult bool
+}
+
+// PublishResult is the task published, or the existing one with the same idempotency key
+type PublishResult struct {
+	TaskID  string // ID of the task
+	Status  string // status of the task on chain
+	Existed bool   // whether the task has been published with the idempotency key before
 }
 
 // checkPublishTaskOptions checks params for publishing task
@@ -107,15 +157,49 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 	if opt.TaskName == "" {
 		return nil, errorx.New(errorx.ErrCodeParam, "taskName can not be empty")
 	}
+	if cb := opt.AlgoParam.CallbackURL; cb != "" {
+		if u, err := url.Parse(cb); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errorx.New(errorx.ErrCodeParam, "invalid callbackURL %s, it should be an http or https URL", cb)
+		}
+	}
+	if err := blockchain.CheckTaskLabels(opt.AlgoParam.Labels); err != nil {
+		return nil, err
+	}
+	if opt.EncryptResult && opt.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
+		return nil, errorx.New(errorx.ErrCodeParam, "encryptResult is only supported by predict task")
+	}
 	// 1. check taskID for predict task
+	var parent *pbTask.FLTask
 	if opt.AlgoParam.TaskType == pbCom.TaskType_PREDICT {
 		if opt.AlgoParam.ModelTaskID == "" {
 			return nil, errorx.New(errorx.ErrCodeParam, "taskID can not empty for predict task")
 		}
 		task, err := c.GetTaskById(opt.AlgoParam.ModelTaskID)
-		if err != nil || task.Status != blockchain.TaskFinished {
+		if err != nil {
+			return nil, errorx.New(errorx.ErrCodeParam, "failed to get task or task status is not finished")
+		}
+		// the model trained by an upstream task is used after the training task finishes
+		if task.Status != blockchain.TaskFinished && !util.IsContain(taskDependencies(opt.DependsOn), task.TaskID) {
 			return nil, errorx.New(errorx.ErrCodeParam, "failed to get task or task status is not finished")
 		}
+		if task.AlgoParam.TaskType != pbCom.TaskType_LEARN {
+			return nil, errorx.New(errorx.ErrCodeParam, "task %s is not a training task", task.TaskID)
+		}
+		if threshold := opt.AlgoParam.TrainParams.GetDriftThreshold(); threshold != 0 {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "drift detection is only supported by linear-vl and logistic-vl")
+			}
+			if threshold < 0 {
+				return nil, errorx.New(errorx.ErrCodeParam, "driftThreshold can not be negative")
+			}
+		}
+		if opt.AlgoParam.TrainParams.GetWithScores() && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+			return nil, errorx.New(errorx.ErrCodeParam, "withScores is only supported by logistic-vl")
+		}
+		if opt.AlgoParam.TrainParams.GetWithExplanations() && opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL &&
+			opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+			return nil, errorx.New(errorx.ErrCodeParam, "withExplanations is only supported by linear-vl and logistic-vl")
+		}
 	} else {
 		if opt.AlgoParam.TrainParams.Label == "" {
 			return nil, errorx.New(errorx.ErrCodeParam, "label can not empty for train task")
@@ -123,6 +207,104 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 		if opt.AlgoParam.Algo == pbCom.Algorithm_LOGIC_REGRESSION_VL && opt.AlgoParam.TrainParams.LabelName == "" {
 			return nil, errorx.New(errorx.ErrCodeParam, "labelName can not be empty for logistic-vl")
 		}
+		// tasks published without accuracy use the default of the executors
+		if accuracy := opt.AlgoParam.TrainParams.Accuracy; accuracy != 0 {
+			if err := vl_common.CheckAccuracy(accuracy); err != nil {
+				return nil, err
+			}
+		}
+		if opt.AlgoParam.Algo == pbCom.Algorithm_XGBOOST_VL {
+			if err := xgboost.CheckTaskParams(&opt.AlgoParam); err != nil {
+				return nil, err
+			}
+		}
+		if scaling := opt.AlgoParam.TrainParams.GetScaling(); scaling != "" {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "feature scaling is only supported by linear-vl and logistic-vl")
+			}
+			if err := vl_common.CheckScaling(scaling); err != nil {
+				return nil, err
+			}
+		}
+		if cp := opt.AlgoParam.TrainParams.GetCategorical(); cp != nil {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "categorical encoding is only supported by linear-vl and logistic-vl")
+			}
+			if err := vl_common.CheckCategoricalParams(cp); err != nil {
+				return nil, err
+			}
+			if util.IsContain(cp.Columns, opt.AlgoParam.TrainParams.Label) {
+				return nil, errorx.New(errorx.ErrCodeParam, "label can not be categorical")
+			}
+		}
+		if dp := opt.AlgoParam.TrainParams.GetDp(); dp != nil {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "differential privacy is only supported by linear-vl and logistic-vl")
+			}
+			if err := vl_common.CheckDPParams(dp); err != nil {
+				return nil, err
+			}
+		}
+		if dr := opt.AlgoParam.TrainParams.GetDryRun(); dr != nil {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "dry run is only supported by linear-vl and logistic-vl")
+			}
+			if err := vl_common.CheckDryRunParams(dr); err != nil {
+				return nil, err
+			}
+		}
+		if weightColumn := opt.AlgoParam.TrainParams.GetWeightColumn(); weightColumn != "" {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "sample weights are only supported by linear-vl and logistic-vl")
+			}
+			if weightColumn == opt.AlgoParam.TrainParams.Label {
+				return nil, errorx.New(errorx.ErrCodeParam, "label can not be the weight column")
+			}
+			if util.IsContain(opt.AlgoParam.TrainParams.GetCategorical().GetColumns(), weightColumn) {
+				return nil, errorx.New(errorx.ErrCodeParam, "weight column can not be categorical")
+			}
+		}
+		if loss := opt.AlgoParam.TrainParams.GetLoss(); loss != nil {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && !vl_common.IsSquaredLoss(loss) {
+				return nil, errorx.New(errorx.ErrCodeParam, "loss functions other than squared are only supported by linear-vl")
+			}
+			if err := vl_common.CheckLoss(loss); err != nil {
+				return nil, err
+			}
+		}
+		if op := opt.AlgoParam.TrainParams.GetOptimizer(); op != nil {
+			if err := vl_common.CheckAlgoOptimizer(opt.AlgoParam.Algo, op); err != nil {
+				return nil, err
+			}
+		}
+		if es := opt.AlgoParam.TrainParams.GetEarlyStopping(); es != nil {
+			if err := checkEarlyStoppingParams(&opt.AlgoParam, es); err != nil {
+				return nil, err
+			}
+		}
+		if opt.AlgoParam.TrainParams.Incremental {
+			if opt.AlgoParam.Algo != pbCom.Algorithm_LINEAR_REGRESSION_VL && opt.AlgoParam.Algo != pbCom.Algorithm_LOGIC_REGRESSION_VL {
+				return nil, errorx.New(errorx.ErrCodeParam, "incremental training is only supported by linear-vl and logistic-vl")
+			}
+			if opt.AlgoParam.ModelTaskID == "" {
+				return nil, errorx.New(errorx.ErrCodeParam, "base model can not be empty for incremental training")
+			}
+			if opt.AlgoParam.TrainParams.UpdateRounds < 0 || opt.AlgoParam.TrainParams.DriftTolerance < 0 {
+				return nil, errorx.New(errorx.ErrCodeParam, "updateRounds and driftTolerance can not be negative")
+			}
+		}
+		// the training task continuing from a parent model trains its next version
+		if opt.AlgoParam.ModelTaskID != "" {
+			var err error
+			parent, err = c.GetTaskById(opt.AlgoParam.ModelTaskID)
+			if err != nil {
+				return nil, errorx.Wrap(err, "failed to get parent model")
+			}
+			task := &pbTask.FLTask{AlgoParam: &opt.AlgoParam}
+			if _, err := blockchain.NextModelVersion(task, parent); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// 2. check data sets number and executor nodes number, at least two parties
@@ -142,6 +324,19 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 	if util.IsContainDuplicateItems(executors) {
 		return nil, errorx.New(errorx.ErrCodeParam, "executor node names cannot be the same")
 	}
+	// two-party algorithms and the three-party dnn can not be executed by other numbers of parties
+	if parties, ok := blockchain.VlAlgorithmParties[opt.AlgoParam.Algo]; ok && len(fileIDs) != parties {
+		return nil, errorx.New(errorx.ErrCodeParam, "algorithm %s requires %d parties, got: %d",
+			blockchain.VlAlgorithmListValue[opt.AlgoParam.Algo], parties, len(fileIDs))
+	}
+	if psi := opt.AlgoParam.TrainParams.GetPsiAlgorithm(); psi != "" {
+		if !blockchain.PSIAlgorithmSupported[psi] {
+			return nil, errorx.New(errorx.ErrCodeParam, "unsupported PSI algorithm %s, valid options are: ecdh, oprf, auto", psi)
+		}
+		if psi == "oprf" && opt.AlgoParam.Algo == pbCom.Algorithm_DNN_PADDLEFL_VL {
+			return nil, errorx.New(errorx.ErrCodeParam, "PSI algorithm oprf is not supported by dnn-paddlefl-vl")
+		}
+	}
 
 	// 3. check if algorithm exists
 	psiLabels := strings.Split(strings.TrimSpace(opt.PSILabels), ",")
@@ -154,10 +349,24 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 		}
 	}
 
+	var columns []string
+	if opt.Columns != "" {
+		columns = strings.Split(opt.Columns, ";")
+		if len(columns) != len(fileIDs) {
+			return nil, errorx.New(errorx.ErrCodeParam, "sample file num not match columns num, got: %d", len(columns))
+		}
+	}
+
 	// 4. check if dataset and specified label exist
 	var dataSets []*pbTask.DataForTask
 	var isTagPart bool
+	// categorical columns not found in the data sets
+	categorical := make(map[string]bool)
+	for _, column := range opt.AlgoParam.TrainParams.GetCategorical().GetColumns() {
+		categorical[column] = true
+	}
 	isLabelExist := 0
+	var minRows int64 = -1
 	for index, fileID := range fileIDs {
 		file, err := c.chainClient.GetFileByID(fileID)
 		if err != nil {
@@ -168,17 +377,41 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 		if err := json.Unmarshal(file.Ext, &fileExtra); err != nil {
 			return nil, errorx.New(errorx.ErrCodeInternal, "failed to get file extra info: %v", err)
 		}
+		if minRows < 0 || fileExtra.TotalRows < minRows {
+			minRows = fileExtra.TotalRows
+		}
 		fileFeatures := strings.Split(fileExtra.Features, ",")
 		// check if psiLabel exists in feature list
 		if !util.IsContain(fileFeatures, psiLabels[index]) {
 			return nil, errorx.New(errorx.ErrCodeParam, "features of file does not contain psiLabel")
 		}
 
+		for _, feature := range fileFeatures {
+			if categorical[feature] && feature == psiLabels[index] {
+				return nil, errorx.New(errorx.ErrCodeParam, "psiLabel %s can not be categorical", feature)
+			}
+			delete(categorical, feature)
+		}
+
 		// check if label exists in one of the datasets
 		if util.IsContain(fileFeatures, opt.AlgoParam.TrainParams.Label) {
 			isLabelExist += 1
 			isTagPart = true
 		}
+		// the weight column is on the party with label
+		weightColumn := opt.AlgoParam.TrainParams.GetWeightColumn()
+		if weightColumn != "" && util.IsContain(fileFeatures, opt.AlgoParam.TrainParams.Label) {
+			if !util.IsContain(fileFeatures, weightColumn) || weightColumn == psiLabels[index] {
+				return nil, errorx.New(errorx.ErrCodeParam, "weight column %s does not exist in the sample file with label", weightColumn)
+			}
+		}
+		// the time column of time-ordered split is on the party with label, which makes the partition
+		timeColumn := opt.AlgoParam.EvalParams.GetRandomSplit().GetTimeColumn()
+		if timeColumn != "" && opt.AlgoParam.EvalParams.GetEnable() && util.IsContain(fileFeatures, opt.AlgoParam.TrainParams.Label) {
+			if !util.IsContain(fileFeatures, timeColumn) || timeColumn == psiLabels[index] || timeColumn == opt.AlgoParam.TrainParams.Label {
+				return nil, errorx.New(errorx.ErrCodeParam, "time column %s does not exist in the sample file with label", timeColumn)
+			}
+		}
 		// only one party is allowed to have label
 		if isLabelExist > 1 {
 			return nil, errorx.New(errorx.ErrCodeParam, "invalid fileIDs, only one sample file is allowed to have label")
@@ -196,58 +429,278 @@ func (c *Client) checkPublishTaskOptions(opt PublishOptions) ([]*pbTask.DataForT
 			DataID:    fileID,
 			Address:   executorNode.Address,
 			IsTagPart: isTagPart,
+			Columns:   fileColumns(columns, index),
 		})
 	}
 	if opt.AlgoParam.TaskType == pbCom.TaskType_LEARN && isLabelExist < 1 {
 		return nil, errorx.New(errorx.ErrCodeParam, "invalid label, dataSets label doest not exist")
 	}
+	for _, column := range opt.AlgoParam.TrainParams.GetCategorical().GetColumns() {
+		if categorical[column] {
+			return nil, errorx.New(errorx.ErrCodeParam, "categorical column %s does not exist in dataSets", column)
+		}
+	}
+	if opt.AlgoParam.TrainParams.GetIncremental() {
+		if err := checkIncrementalDataSets(parent, dataSets); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.BatchFiles != "" {
+		if err := c.addBatchDataSets(opt, dataSets); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. check evaluation params, the aligned samples are no more than the smallest data set
+	if err := checkEvaluationParams(opt.AlgoParam.Algo, opt.AlgoParam.EvalParams, minRows); err != nil {
+		return nil, err
+	}
 	return dataSets, nil
 }
 
+// addBatchDataSets adds the files of the other inputs of a batch prediction to dataSets, the files of each
+// input are matched with dataSets by order, and must be owned by the owners of the files in dataSets
+func (c *Client) addBatchDataSets(opt PublishOptions, dataSets []*pbTask.DataForTask) error {
+	if opt.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
+		return errorx.New(errorx.ErrCodeParam, "batch files are only supported by prediction tasks")
+	}
+	seen := make(map[string]bool)
+	for _, ds := range dataSets {
+		seen[ds.DataID] = true
+	}
+	for n, input := range strings.Split(opt.BatchFiles, ";") {
+		fileIDs := strings.Split(strings.TrimSpace(input), ",")
+		if len(fileIDs) != len(dataSets) {
+			return errorx.New(errorx.ErrCodeParam, "batch input %d has %d files, expected %d", n+1, len(fileIDs), len(dataSets))
+		}
+		for index, fileID := range fileIDs {
+			fileID = strings.TrimSpace(fileID)
+			if seen[fileID] {
+				return errorx.New(errorx.ErrCodeParam, "sample file %s is used more than once", fileID)
+			}
+			seen[fileID] = true
+			file, err := c.chainClient.GetFileByID(fileID)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(file.Owner, dataSets[index].Owner) {
+				return errorx.New(errorx.ErrCodeParam, "batch file %s is not owned by the owner of %s", fileID, dataSets[index].DataID)
+			}
+			fileExtra := blockchain.FLInfo{}
+			if err := json.Unmarshal(file.Ext, &fileExtra); err != nil {
+				return errorx.New(errorx.ErrCodeInternal, "failed to get file extra info: %v", err)
+			}
+			if !util.IsContain(strings.Split(fileExtra.Features, ","), dataSets[index].PsiLabel) {
+				return errorx.New(errorx.ErrCodeParam, "features of batch file %s does not contain psiLabel", fileID)
+			}
+			dataSets[index].BatchDataIDs = append(dataSets[index].BatchDataIDs, fileID)
+		}
+	}
+	return nil
+}
+
+// fileColumns returns the columns of the index-th file selected by columns, nil if all of them are used
+func fileColumns(columns []string, index int) []string {
+	if len(columns) == 0 || strings.TrimSpace(columns[index]) == "" {
+		return nil
+	}
+	var fc []string
+	for _, c := range strings.Split(columns[index], ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			fc = append(fc, c)
+		}
+	}
+	return fc
+}
+
+// checkIncrementalDataSets checks that the new samples of incremental training are held by
+// the executors of the base model, and the label is held by the same executor,
+// since each executor updates its own part of the base model
+func checkIncrementalDataSets(base *pbTask.FLTask, dataSets []*pbTask.DataForTask) error {
+	if len(base.DataSets) != len(dataSets) {
+		return errorx.New(errorx.ErrCodeParam, "base model %s is trained by %d executors, got: %d",
+			base.TaskID, len(base.DataSets), len(dataSets))
+	}
+	for _, ds := range dataSets {
+		found := false
+		for _, bds := range base.DataSets {
+			if bytes.Equal(ds.Executor, bds.Executor) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errorx.New(errorx.ErrCodeParam, "executor %s doesn't hold a part of base model %s", ds.Address, base.TaskID)
+		}
+	}
+	if !bytes.Equal(tagExecutor(base.DataSets), tagExecutor(dataSets)) {
+		return errorx.New(errorx.ErrCodeParam, "label must be held by the executor holding it in base model %s", base.TaskID)
+	}
+	return nil
+}
+
+// tagExecutor returns the executor of the first data set with label
+func tagExecutor(dataSets []*pbTask.DataForTask) []byte {
+	for _, ds := range dataSets {
+		if ds.IsTagPart {
+			return ds.Executor
+		}
+	}
+	return nil
+}
+
+// checkEvaluationParams checks that the metrics are supported by the algorithm, the validation set of random split,
+// the number of folds of K-fold cross validation is supported,
+// and the data sets of minRows samples are large enough to be divided into the folds
+func checkEvaluationParams(algo pbCom.Algorithm, params *pbCom.EvaluationParams, minRows int64) error {
+	if !params.GetEnable() {
+		return nil
+	}
+	supported := blockchain.EvalMetricsSupported[algo]
+	for _, m := range params.Metrics {
+		if !util.IsContain(supported, m) {
+			return errorx.New(errorx.ErrCodeParam, "unsupported evaluation metric %s for %s, valid options are: %s",
+				m, blockchain.VlAlgorithmListValue[algo], strings.Join(supported, ","))
+		}
+	}
+	if params.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
+		return blockchain.CheckRandomSplit(algo, params.RandomSplit)
+	}
+	if params.EvalRule != pbCom.EvaluationRule_ErCrossVal {
+		return nil
+	}
+	if params.Cv == nil || !blockchain.KFoldSupported[params.Cv.Folds] {
+		return errorx.New(errorx.ErrCodeParam, "invalid folds of cross validation, 5 or 10 supported")
+	}
+	if minRows >= 0 && minRows < int64(params.Cv.Folds) {
+		return errorx.New(errorx.ErrCodeParam, "data set of %d samples is too small for %d folds", minRows, params.Cv.Folds)
+	}
+	return nil
+}
+
+// checkEarlyStoppingParams checks early stopping is based on a metric of live evaluation of the algorithm
+func checkEarlyStoppingParams(params *pbCom.TaskParams, es *pbCom.EarlyStoppingParams) error {
+	if !params.LivalParams.GetEnable() {
+		return errorx.New(errorx.ErrCodeParam, "early stopping requires live evaluation")
+	}
+	supported, ok := blockchain.LiveMetricsSupported[params.Algo]
+	if !ok {
+		return errorx.New(errorx.ErrCodeParam, "early stopping is only supported by linear-vl and logistic-vl")
+	}
+	if !util.IsContain(supported, es.Metric) {
+		return errorx.New(errorx.ErrCodeParam, "unsupported early stopping metric %s for %s, valid options are: %s",
+			es.Metric, blockchain.VlAlgorithmListValue[params.Algo], strings.Join(supported, ","))
+	}
+	if es.Patience <= 0 {
+		return errorx.New(errorx.ErrCodeParam, "patience of early stopping should be positive")
+	}
+	if es.MinDelta < 0 {
+		return errorx.New(errorx.ErrCodeParam, "minDelta of early stopping can not be negative")
+	}
+	return nil
+}
+
 // Publish publishes a task, returns taskID
 func (c *Client) Publish(opt PublishOptions) (taskId string, err error) {
+	result, err := c.PublishTask(opt)
+	return result.TaskID, err
+}
+
+// PublishTask publishes a task, returns the task published.
+// If opt.IdempotencyKey is set, the taskID is derived from the requester and the key, and the existing task
+// is returned if the requester has published one with the same key, which is also guaranteed by the contract
+// rejecting duplicated taskIDs when the same task is submitted concurrently.
+func (c *Client) PublishTask(opt PublishOptions) (result PublishResult, err error) {
 	pubkey, privkey, err := checkUserPrivateKey(opt.PrivateKey)
 	if err != nil {
-		return taskId, err
+		return result, err
+	}
+	if opt.IdempotencyKey != "" {
+		result.TaskID = blockchain.IdempotentTaskID(pubkey[:], opt.IdempotencyKey)
+		if existing, ok, err := c.getExistingTask(result.TaskID); ok || err != nil {
+			return existing, err
+		}
 	}
 	dataSets, err := c.checkPublishTaskOptions(opt)
 	if err != nil {
-		return taskId, err
+		return result, err
+	}
+	if opt.EncryptResult {
+		opt.AlgoParam.ResultPublicKey = pubkey[:]
 	}
 
 	task := pbTask.FLTask{
-		Name:        opt.TaskName,
-		Description: opt.Description,
-		Requester:   pubkey[:],
-		AlgoParam:   &opt.AlgoParam,
-		PublishTime: time.Now().UnixNano(),
-		DataSets:    dataSets,
+		TaskID:         result.TaskID,
+		Name:           opt.TaskName,
+		Description:    opt.Description,
+		Requester:      pubkey[:],
+		AlgoParam:      &opt.AlgoParam,
+		PublishTime:    time.Now().UnixNano(),
+		DataSets:       dataSets,
+		IdempotencyKey: opt.IdempotencyKey,
+		DependsOn:      taskDependencies(opt.DependsOn),
 	}
 
-	// generate a uuid as taskId
-	taskUuid, err := uuid.NewRandom()
-	if err != nil {
-		return taskId, errorx.Internal(err, "failed to get uuid")
+	// generate a uuid as taskId if there is no idempotency key
+	if task.TaskID == "" {
+		taskUuid, err := uuid.NewRandom()
+		if err != nil {
+			return result, errorx.Internal(err, "failed to get uuid")
+		}
+		task.TaskID = taskUuid.String()
+	}
+	if err := blockchain.CheckTaskDependencies(&task, c.chainClient.GetTaskById); err != nil {
+		return result, err
 	}
-	task.TaskID = taskUuid.String()
 
 	// sign task info
 	m, err := util.GetSigMessage(task)
 	if err != nil {
-		return taskId, errorx.Internal(err, "failed to get fl task signature message")
+		return result, errorx.Internal(err, "failed to get fl task signature message")
 	}
 	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(m)))
 	if err != nil {
-		return taskId, errorx.Wrap(err, "failed to sign fl task")
+		return result, errorx.Wrap(err, "failed to sign fl task")
 	}
 	pubOpt := &blockchain.PublishFLTaskOptions{
 		FLTask:    &task,
 		Signature: sig[:],
 	}
 	if err := c.chainClient.PublishTask(pubOpt); err != nil {
-		return taskId, err
+		// the task with the same key may be published concurrently, or by a timed out attempt
+		if opt.IdempotencyKey != "" && errorx.Is(err, errorx.ErrCodeAlreadyExists) {
+			if existing, ok, _ := c.getExistingTask(task.TaskID); ok {
+				return existing, nil
+			}
+		}
+		return result, err
+	}
+	return PublishResult{TaskID: task.TaskID, Status: blockchain.TaskConfirming}, nil
+}
+
+// taskDependencies splits the upstream task IDs separated by ","
+func taskDependencies(dependsOn string) []string {
+	var ids []string
+	for _, id := range strings.Split(dependsOn, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
 	}
-	return task.TaskID, nil
+	return ids
+}
+
+// getExistingTask gets the task published with the taskID derived from an idempotency key,
+// returns false if the task is not found
+func (c *Client) getExistingTask(id string) (PublishResult, bool, error) {
+	t, err := c.chainClient.GetTaskById(id)
+	if err != nil {
+		if errorx.Is(err, errorx.ErrCodeNotFound) {
+			return PublishResult{}, false, nil
+		}
+		return PublishResult{}, false, err
+	}
+	return PublishResult{TaskID: t.TaskID, Status: t.Status, Existed: true}, true, nil
 }
 
 // GetTaskById gets task by taskID
@@ -259,6 +712,31 @@ func (c *Client) GetTaskById(id string) (t blockchain.FLTask, err error) {
 	return t, nil
 }
 
+// GetModelLineage gets the lineage of a model by the ID of the task training it,
+// returns the versions from the model to its first version, each one is the parent of the previous one
+func (c *Client) GetModelLineage(modelID string) ([]blockchain.ModelLineage, error) {
+	var lineage []blockchain.ModelLineage
+	visited := make(map[string]bool)
+	for id := modelID; id != ""; {
+		if visited[id] {
+			return lineage, errorx.New(errorx.ErrCodeInternal, "cyclic lineage of model %s", modelID)
+		}
+		visited[id] = true
+
+		task, err := c.chainClient.GetTaskById(id)
+		if err != nil {
+			return lineage, errorx.Wrap(err, "failed to get model %s", id)
+		}
+		if task.AlgoParam == nil || task.AlgoParam.TaskType != pbCom.TaskType_LEARN {
+			return lineage, errorx.New(errorx.ErrCodeParam, "task %s is not a training task", id)
+		}
+		l := blockchain.NewModelLineage(task)
+		lineage = append(lineage, l)
+		id = l.ParentModelID
+	}
+	return lineage, nil
+}
+
 // ListTask lists tasks by requester or executor's public key hex string
 // support listing tasks a requester published or tasks an executor involved
 // status is task status to search
@@ -305,75 +783,236 @@ func (c *Client) StartTask(privateKey, id string) (err error) {
 	return err
 }
 
-// GetPredictResult gets predict result by taskID
-// output is the path to save predict result
-func (c *Client) GetPredictResult(privateKey, taskID, output string) (err error) {
+// CancelTask cancels a task in execution by taskID. The cancellation is sent to an executor
+// of the task, which forwards it to the others.
+// message explains why nothing is done if the task has already ended
+func (c *Client) CancelTask(privateKey, id string) (message string, err error) {
 	pubkey, privkey, err := checkUserPrivateKey(privateKey)
 	if err != nil {
-		return err
+		return "", err
+	}
+	task, err := c.chainClient.GetTaskById(id)
+	if err != nil {
+		return "", err
 	}
+	if len(task.DataSets) == 0 {
+		return "", errorx.New(errorx.ErrCodeParam, "invalid task, no executor found")
+	}
+	executorHost := task.DataSets[0].Address
 
-	// get prediction task
-	task, err := c.chainClient.GetTaskById(taskID)
+	conn, err := c.dialExecutor(executorHost)
 	if err != nil {
-		return err
+		return "", errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
 	}
-	// check task type
-	if task.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
-		return errorx.New(errorx.ErrCodeParam, "invalid task type, not a predict task")
+	defer conn.Close()
+	taskClient := pbTask.NewTaskClient(conn)
+
+	in := &pbTask.TaskRequest{
+		PubKey: pubkey[:],
+		TaskID: id,
 	}
-	// get training task
-	modelTask, err := c.chainClient.GetTaskById(task.AlgoParam.ModelTaskID)
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return "", errorx.Internal(err, "failed to get the message to sign for cancel task")
+	}
+	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to sign cancel task")
+	}
+	in.Signature = sig[:]
+
+	out, err := taskClient.CancelTask(context.Background(), in)
+	if err != nil {
+		return "", err
+	}
+	return out.Message, nil
+}
+
+// GetPredictResult gets predict result by taskID
+// output is the path to save predict result, batchIndex is the index of the input of a batch prediction
+func (c *Client) GetPredictResult(privateKey, taskID, output string, batchIndex int32) (err error) {
+	rows, err := c.GetPredictRows(privateKey, taskID, batchIndex)
 	if err != nil {
 		return err
 	}
-	var executorHost string
-	for _, dataset := range modelTask.DataSets {
-		if dataset.IsTagPart {
-			executorHost = dataset.Address
-			break
-		}
+	// save result to csv file
+	if err := csv.WriteRowsToFile(rows, output); err != nil {
+		return errorx.Wrap(err, "failed to unmarshal result to rows")
+	}
+	return nil
+}
+
+// GetPredictRows gets the rows of predict result by taskID from the executor holding it,
+// the first row is the header, batchIndex is the index of the input of a batch prediction
+func (c *Client) GetPredictRows(privateKey, taskID string, batchIndex int32) ([][]string, error) {
+	pubkey, privkey, err := checkUserPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	holder, err := c.resultHolder(taskID)
+	if err != nil {
+		return nil, err
 	}
 
 	// connect to result owner
-	conn, err := grpc.Dial(executorHost, grpc.WithInsecure())
+	conn, err := c.dialExecutor(holder.Address)
 	if err != nil {
-		return errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
+		return nil, errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
 	}
 	defer conn.Close()
 	taskClient := pbTask.NewTaskClient(conn)
 
 	in := &pbTask.TaskRequest{
-		PubKey: pubkey[:],
-		TaskID: taskID,
+		PubKey:     pubkey[:],
+		TaskID:     taskID,
+		BatchIndex: batchIndex,
 	}
 
 	// verify signature
 	msg, err := util.GetSigMessage(in)
 	if err != nil {
-		return errorx.Internal(err, "failed to get the message to sign for download prediction result")
+		return nil, errorx.Internal(err, "failed to get the message to sign for download prediction result")
 	}
 	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
 	if err != nil {
-		return errorx.Wrap(err, "failed to sign predict task")
+		return nil, errorx.Wrap(err, "failed to sign predict task")
 	}
 	in.Signature = sig[:]
 
 	// request data node to download predict file
 	out, err := taskClient.GetPredictResult(context.Background(), in)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// the result encrypted with the requester's public key is decrypted with the private key
+	if out.Encrypted {
+		content, err := envelope.Open(privkey, out.Payload)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to decrypt predict result")
+		}
+		return vl_common.PredictResultFromBytes(content)
 	}
 	var rows [][]string
 	if err := json.Unmarshal(out.Payload, &rows); err != nil {
-		return errorx.Wrap(err, "failed to unmarshal result to rows")
+		return nil, errorx.Wrap(err, "failed to unmarshal result to rows")
 	}
-	// save result to csv file
-	if err := csv.WriteRowsToFile(rows, output); err != nil {
-		return errorx.Wrap(err, "failed to unmarshal result to rows")
+	return rows, nil
+}
+
+// resultHolder returns the dataset of the executor holding the result of the prediction task taskID,
+// which is the one holding the label in the training task
+func (c *Client) resultHolder(taskID string) (*pbTask.DataForTask, error) {
+	// get prediction task
+	task, err := c.chainClient.GetTaskById(taskID)
+	if err != nil {
+		return nil, err
+	}
+	// check task type
+	if task.AlgoParam.TaskType != pbCom.TaskType_PREDICT {
+		return nil, errorx.New(errorx.ErrCodeParam, "invalid task type, not a predict task")
+	}
+	// get training task
+	modelTask, err := c.chainClient.GetTaskById(task.AlgoParam.ModelTaskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dataset := range modelTask.DataSets {
+		if dataset.IsTagPart {
+			return dataset, nil
+		}
 	}
+	return nil, errorx.New(errorx.ErrCodeInternal, "no executor holds the result of task %s", taskID)
+}
 
-	return nil
+// VerifyResult verifies the signature of the prediction result of the input batchIndex of the task taskID.
+// The executor holding the result verifies the signature against the result it stores and its public key,
+// then the signature is verified again against the public key of the executor recorded in the training task,
+// so the result is valid only if it is signed by the executor expected and not modified since.
+func (c *Client) VerifyResult(taskID string, batchIndex int32) (*pbTask.ResultSignature, error) {
+	holder, err := c.resultHolder(taskID)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.dialExecutor(holder.Address)
+	if err != nil {
+		return nil, errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
+	}
+	defer conn.Close()
+	sig, err := pbTask.NewTaskClient(conn).VerifyResult(context.Background(), &pbTask.VerifyResultRequest{
+		TaskID:     taskID,
+		BatchIndex: batchIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Valid {
+		return sig, errorx.New(errorx.ErrCodeBadSignature, "result doesn't match its signature in executor %s", holder.Address)
+	}
+	// the digest is checked against the result by the executor, as the requester gets the outcomes but not the file
+	if err := blockchain.VerifyResultSignature(sig, holder.Executor, taskID, batchIndex, nil); err != nil {
+		sig.Valid = false
+		return sig, err
+	}
+	return sig, nil
+}
+
+// ExportModel exports the linear or logistic regression model trained by task modelID into format, 'onnx' or 'pmml',
+// the local parts of the model are got from all the executors of the task, and combined into one model
+// taking the features of all parties, in the order of the task's datasets. Only the requester of the task can export it.
+func (c *Client) ExportModel(privateKey, modelID, format string) ([]byte, error) {
+	if err := export.CheckFormat(format); err != nil {
+		return nil, err
+	}
+	pubkey, privkey, err := checkUserPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	task, err := c.chainClient.GetTaskById(modelID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AlgoParam.TaskType != pbCom.TaskType_LEARN {
+		return nil, errorx.New(errorx.ErrCodeParam, "invalid task type, not a training task")
+	}
+
+	in := &pbTask.ExportModelRequest{
+		PubKey:  pubkey[:],
+		ModelID: modelID,
+	}
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to get the message to sign for export model")
+	}
+	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to sign export model")
+	}
+	in.Signature = sig[:]
+
+	var parts []*pbCom.TrainModels
+	for _, dataset := range task.DataSets {
+		part, err := c.exportModelPart(dataset.Address, in)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to export model part from executor %s", dataset.Address)
+		}
+		parts = append(parts, part)
+	}
+	return export.Export(task.AlgoParam.Algo, parts, format)
+}
+
+// exportModelPart requests the executor to return its local part of the model
+func (c *Client) exportModelPart(executorHost string, in *pbTask.ExportModelRequest) (*pbCom.TrainModels, error) {
+	conn, err := c.dialExecutor(executorHost)
+	if err != nil {
+		return nil, errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
+	}
+	defer conn.Close()
+	out, err := pbTask.NewTaskClient(conn).ExportModel(context.Background(), in)
+	if err != nil {
+		return nil, err
+	}
+	return out.Model, nil
 }
 
 // ListExecutorNodes list all executor nodes
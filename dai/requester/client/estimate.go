@@ -0,0 +1,94 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/estimate"
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+)
+
+// EstimateTask estimates the runtime, peak memory and bytes transferred of the task to publish with opt,
+// by the numbers of rows and columns of the sample files recorded on chain, rounds is the number of rounds
+// of each training, decided by the task parameters if it's 0, see estimate.Task
+func (c *Client) EstimateTask(opt PublishOptions, rounds int64) (*estimate.Estimate, error) {
+	dataSets, err := c.checkPublishTaskOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	params := opt.AlgoParam
+	// the trees of xgboost-vl predicted with are decided by the training task
+	if params.TaskType == pbCom.TaskType_PREDICT && params.Algo == pbCom.Algorithm_XGBOOST_VL {
+		task, err := c.GetTaskById(params.ModelTaskID)
+		if err != nil {
+			return nil, err
+		}
+		var trainParams pbCom.TrainParams
+		if tp := params.GetTrainParams(); tp != nil {
+			trainParams = *tp
+		}
+		trainParams.XgbParams = task.AlgoParam.GetTrainParams().GetXgbParams()
+		params.TrainParams = &trainParams
+	}
+
+	parties := make([]estimate.Party, 0, len(dataSets))
+	for _, ds := range dataSets {
+		file, err := c.chainClient.GetFileByID(ds.DataID)
+		if err != nil {
+			return nil, err
+		}
+		fileExtra := blockchain.FLInfo{}
+		if err := json.Unmarshal(file.Ext, &fileExtra); err != nil {
+			return nil, errorx.New(errorx.ErrCodeInternal, "failed to get file extra info: %v", err)
+		}
+		features := strings.Split(fileExtra.Features, ",")
+		columns := int64(len(features))
+		bytes := int64(file.Length)
+		// only the selected columns, the ID and the label are used, the size of the file is unknown then
+		if len(ds.Columns) > 0 {
+			selected := append([]string{ds.PsiLabel}, ds.Columns...)
+			if util.IsContain(features, params.GetTrainParams().GetLabel()) {
+				selected = append(selected, params.GetTrainParams().GetLabel())
+			}
+			columns = int64(len(removeDuplicates(selected)))
+			bytes = 0
+		}
+		parties = append(parties, estimate.Party{
+			Rows:    fileExtra.TotalRows,
+			Columns: columns,
+			Bytes:   bytes,
+		})
+	}
+	return estimate.Task(&params, parties, rounds)
+}
+
+// removeDuplicates returns the distinct items of items
+func removeDuplicates(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var distinct []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			distinct = append(distinct, item)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sort"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+)
+
+// FeatureImportance is the importance of a feature, reported by the executor holding the feature
+type FeatureImportance struct {
+	Executor string // address of the executor
+	*pbTask.FeatureImportance
+}
+
+// GetFeatureImportance gets the importance of the features of the linear or logistic regression model modelID,
+// each executor of the training task reports its own features, and the features of all executors are sorted
+// by importance in descending order
+func (c *Client) GetFeatureImportance(privateKey, modelID string) ([]FeatureImportance, error) {
+	pubkey, privkey, err := checkUserPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	task, err := c.chainClient.GetTaskById(modelID)
+	if err != nil {
+		return nil, err
+	}
+	if task.AlgoParam.TaskType != pbCom.TaskType_LEARN {
+		return nil, errorx.New(errorx.ErrCodeParam, "invalid task type, not a training task")
+	}
+
+	in := &pbTask.FeatureImportanceRequest{
+		PubKey:  pubkey[:],
+		ModelID: modelID,
+	}
+	msg, err := util.GetSigMessage(in)
+	if err != nil {
+		return nil, errorx.Internal(err, "failed to get the message to sign for get feature importance")
+	}
+	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to sign get feature importance")
+	}
+	in.Signature = sig[:]
+
+	var importances []FeatureImportance
+	for _, dataset := range task.DataSets {
+		part, err := c.getFeatureImportancePart(dataset.Address, in)
+		if err != nil {
+			return nil, errorx.Wrap(err, "failed to get feature importance from executor %s", dataset.Address)
+		}
+		for _, importance := range part {
+			importances = append(importances, FeatureImportance{Executor: dataset.Address, FeatureImportance: importance})
+		}
+	}
+	sort.SliceStable(importances, func(i, j int) bool {
+		return importances[i].Importance > importances[j].Importance
+	})
+	return importances, nil
+}
+
+// getFeatureImportancePart requests the executor to return the importance of its local features
+func (c *Client) getFeatureImportancePart(executorHost string, in *pbTask.FeatureImportanceRequest) ([]*pbTask.FeatureImportance, error) {
+	conn, err := c.dialExecutor(executorHost)
+	if err != nil {
+		return nil, errorx.New(errorx.ErrCodeInternal, "CAN_NOT_CONNECT_EXECUTOR_SERVER: %v", err)
+	}
+	defer conn.Close()
+	out, err := pbTask.NewTaskClient(conn).GetFeatureImportance(context.Background(), in)
+	if err != nil {
+		return nil, err
+	}
+	return out.Importances, nil
+}
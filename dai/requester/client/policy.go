@@ -0,0 +1,77 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/hash"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	util "github.com/PaddlePaddle/PaddleDTX/xdb/pkgs/strings"
+)
+
+// PublishColumnPolicy publishes the column policy of the sample file fileID, which allows the Executor to use
+// only columns of the file in proxy mode. privateKey is the key of the owner of the file, and executor is
+// the name of the Executor node, the policy applies to all Executors if it's empty
+func (c *Client) PublishColumnPolicy(privateKey, fileID, executor string, columns []string) error {
+	pubkey, privkey, err := checkUserPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	policy := blockchain.ColumnPolicy{
+		FileID:     fileID,
+		Owner:      pubkey[:],
+		Columns:    columns,
+		CreateTime: time.Now().UnixNano(),
+	}
+	if executor != "" {
+		node, err := c.chainClient.GetExecutorNodeByName(executor)
+		if err != nil {
+			return errorx.Wrap(err, "failed to get executor node %s", executor)
+		}
+		policy.Executor = node.ID
+	}
+	if err := blockchain.CheckColumnPolicy(policy); err != nil {
+		return err
+	}
+	msg, err := util.GetSigMessage(policy)
+	if err != nil {
+		return errorx.Internal(err, "failed to get the message to sign for publish column policy")
+	}
+	sig, err := ecdsa.Sign(privkey, hash.HashUsingSha256([]byte(msg)))
+	if err != nil {
+		return errorx.Wrap(err, "failed to sign column policy")
+	}
+	return c.chainClient.PublishColumnPolicy(&blockchain.PublishColumnPolicyOptions{
+		Policy:    policy,
+		Signature: sig[:],
+	})
+}
+
+// GetColumnPolicy gets the column policy of the sample file fileID the Executor honors, executor is the name of
+// the Executor node, the policy for all Executors is returned if the Executor has no policy of its own
+func (c *Client) GetColumnPolicy(fileID, executor string) (blockchain.ColumnPolicy, error) {
+	var executorID []byte
+	if executor != "" {
+		node, err := c.chainClient.GetExecutorNodeByName(executor)
+		if err != nil {
+			return blockchain.ColumnPolicy{}, errorx.Wrap(err, "failed to get executor node %s", executor)
+		}
+		executorID = node.ID
+	}
+	return c.chainClient.GetColumnPolicy(fileID, executorID)
+}
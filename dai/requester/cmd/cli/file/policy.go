@@ -0,0 +1,106 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	requestClient "github.com/PaddlePaddle/PaddleDTX/dai/requester/client"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+var (
+	privateKey     string
+	keyPath        string
+	policyFileID   string
+	policyExecutor string
+	policyColumns  string
+)
+
+// setPolicyCmd publishes the column policy of a sample file, signed by the file owner
+var setPolicyCmd = &cobra.Command{
+	Use:   "setpolicy",
+	Short: "publish the column policy of a sample file, which restricts the columns executors use in proxy mode",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := requestClient.GetRequestClient(configPath)
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+		if privateKey == "" {
+			privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
+			if err != nil {
+				fmt.Printf("Read privateKey failed, err: %v\n", err)
+				return
+			}
+			privateKey = strings.TrimSpace(string(privateKeyBytes))
+		}
+		var columns []string
+		for _, column := range strings.Split(policyColumns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				columns = append(columns, column)
+			}
+		}
+
+		if err := client.PublishColumnPolicy(privateKey, policyFileID, policyExecutor, columns); err != nil {
+			fmt.Printf("PublishColumnPolicy failed：%v\n", err)
+			return
+		}
+		fmt.Println("OK")
+	},
+}
+
+// getPolicyCmd gets the column policy of a sample file an executor honors
+var getPolicyCmd = &cobra.Command{
+	Use:   "getpolicy",
+	Short: "get the column policy of a sample file an executor honors",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := requestClient.GetRequestClient(configPath)
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+
+		policy, err := client.GetColumnPolicy(policyFileID, policyExecutor)
+		if err != nil {
+			fmt.Printf("err：%v\n", err)
+			return
+		}
+		ctime := time.Unix(0, policy.CreateTime).Format(timeTemplate)
+		fmt.Printf("FileID: %s\nOwner: %x\nExecutor: %x\nColumns: %s\nCreateTime: %s\n\n",
+			policy.FileID, policy.Owner, policy.Executor, strings.Join(policy.Columns, ","), ctime)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setPolicyCmd)
+	rootCmd.AddCommand(getPolicyCmd)
+
+	setPolicyCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "private key hex string of the sample file owner")
+	setPolicyCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./keys", "key path of the sample file owner")
+	setPolicyCmd.Flags().StringVarP(&policyFileID, "fileID", "f", "", "sample file ID")
+	setPolicyCmd.Flags().StringVarP(&policyExecutor, "executor", "e", "", "executor node name the policy applies to, all executors if empty")
+	setPolicyCmd.Flags().StringVar(&policyColumns, "columns", "", "columns allowed with ',' as delimiter, the ID column and the label included, like 'id,CRIM,ZN'")
+
+	getPolicyCmd.Flags().StringVarP(&policyFileID, "fileID", "f", "", "sample file ID")
+	getPolicyCmd.Flags().StringVarP(&policyExecutor, "executor", "e", "", "executor node name, the policy for all executors if empty")
+
+	setPolicyCmd.MarkFlagRequired("fileID")
+	setPolicyCmd.MarkFlagRequired("columns")
+	getPolicyCmd.MarkFlagRequired("fileID")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// cancelTaskByIDCmd cancels a task in execution
+var cancelTaskByIDCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "cancel the task in execution",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getRequestClient()
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+		if privateKey == "" {
+			privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
+			if err != nil {
+				fmt.Printf("Read privateKey failed, err: %v\n", err)
+				return
+			}
+			privateKey = strings.TrimSpace(string(privateKeyBytes))
+		}
+
+		message, err := client.CancelTask(privateKey, id)
+		if err != nil {
+			fmt.Printf("CancelTask failed：%v\n", err)
+			return
+		}
+		if message != "" {
+			fmt.Println(message)
+			return
+		}
+		fmt.Println("OK")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cancelTaskByIDCmd)
+
+	cancelTaskByIDCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "requester private key hex string")
+	cancelTaskByIDCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./reqkeys", "key path")
+	cancelTaskByIDCmd.Flags().StringVarP(&id, "id", "i", "", "id of task to cancel, but only Processing tasks can be cancelled")
+
+	cancelTaskByIDCmd.MarkFlagRequired("id")
+}
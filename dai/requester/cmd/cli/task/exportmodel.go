@@ -0,0 +1,73 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/crypto/vl/export"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+var modelFormat string // format of the exported model, 'onnx' or 'pmml'
+
+// exportModelCmd exports a model from the executors of its training task
+var exportModelCmd = &cobra.Command{
+	Use:   "exportmodel",
+	Short: "export a linear or logistic regression model in ONNX or PMML format",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getRequestClient()
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+		if privateKey == "" {
+			privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
+			if err != nil {
+				fmt.Printf("Read privateKey failed, err: %v\n", err)
+				return
+			}
+			privateKey = strings.TrimSpace(string(privateKeyBytes))
+		}
+
+		model, err := client.ExportModel(privateKey, id, modelFormat)
+		if err != nil {
+			fmt.Printf("ExportModel failed：%v\n", err)
+			return
+		}
+		if err := ioutil.WriteFile(output, model, 0644); err != nil {
+			fmt.Printf("failed to write %s: %v\n", output, err)
+			return
+		}
+
+		fmt.Println("OK")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportModelCmd)
+
+	exportModelCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "requester private key hex string")
+	exportModelCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./reqkeys", "requester's key path")
+	exportModelCmd.Flags().StringVarP(&id, "id", "i", "", "model id, the id of the training task")
+	exportModelCmd.Flags().StringVarP(&output, "output", "o", "", "file to store the exported model")
+	exportModelCmd.Flags().StringVar(&modelFormat, "format", export.FormatONNX, "format of the exported model, 'onnx' or 'pmml'")
+
+	exportModelCmd.MarkFlagRequired("id")
+	exportModelCmd.MarkFlagRequired("output")
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+// output formats of the commands printing tasks or prediction outcomes
+const (
+	formatTable = "table" // human-readable
+	formatJSON  = "json"
+	formatCSV   = "csv" // with a header row
+)
+
+var format string
+
+// checkFormat checks the output format is supported
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("invalid format %q, it should be 'table', 'json' or 'csv'", format)
+	}
+}
+
+// taskSummary is the task printed by list, the field names are stable
+type taskSummary struct {
+	TaskID      string `json:"taskID"`
+	TaskType    string `json:"taskType"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	PublishTime string `json:"publishTime"`
+}
+
+// taskDetail is the task printed by getbyid, the field names are stable
+type taskDetail struct {
+	taskSummary
+	Requester    string            `json:"requester"`
+	Algorithm    string            `json:"algorithm"`
+	ModelTaskID  string            `json:"modelTaskID"`
+	StartTime    string            `json:"startTime"`
+	EndTime      string            `json:"endTime"`
+	ErrMessage   string            `json:"errMessage"`
+	Result       string            `json:"result"`
+	DependsOn    []string          `json:"dependsOn"`
+	Labels       map[string]string `json:"labels"`
+	DataSets     []taskDataSet     `json:"dataSets"`
+	BatchResults []taskBatchResult `json:"batchResults"`
+}
+
+// taskDataSet is a sample file of the task
+type taskDataSet struct {
+	DataID      string `json:"dataID"`
+	Owner       string `json:"owner"`
+	Executor    string `json:"executor"`
+	Address     string `json:"address"`
+	PSILabel    string `json:"psiLabel"`
+	ConfirmedAt string `json:"confirmedAt"`
+	RejectedAt  string `json:"rejectedAt"`
+}
+
+// taskBatchResult is the result of an input of a batch prediction
+type taskBatchResult struct {
+	Index      int32  `json:"index"`
+	Samples    int64  `json:"samples"`
+	Result     string `json:"result"`
+	ErrMessage string `json:"errMessage"`
+}
+
+// summaryHeader is the CSV header of taskSummary, detailHeader the one of taskDetail without the data sets and batch results
+var (
+	summaryHeader = []string{"taskID", "taskType", "name", "description", "status", "publishTime"}
+	detailHeader  = append(append([]string{}, summaryHeader...),
+		"requester", "algorithm", "modelTaskID", "startTime", "endTime", "errMessage", "result", "dependsOn", "labels")
+)
+
+// formatTime formats the timestamp in nanoseconds as RFC3339, empty if it is 0
+func formatTime(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(0, t).Format(time.RFC3339)
+}
+
+func newTaskSummary(task *pbTask.FLTask) taskSummary {
+	return taskSummary{
+		TaskID:      task.TaskID,
+		TaskType:    blockchain.TaskTypeListValue[task.AlgoParam.TaskType],
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      task.Status,
+		PublishTime: formatTime(task.PublishTime),
+	}
+}
+
+func newTaskDetail(task *pbTask.FLTask) taskDetail {
+	d := taskDetail{
+		taskSummary:  newTaskSummary(task),
+		Requester:    hex.EncodeToString(task.Requester),
+		Algorithm:    blockchain.VlAlgorithmListValue[task.AlgoParam.Algo],
+		ModelTaskID:  task.AlgoParam.ModelTaskID,
+		StartTime:    formatTime(task.StartTime),
+		EndTime:      formatTime(task.EndTime),
+		ErrMessage:   task.ErrMessage,
+		Result:       task.Result,
+		DependsOn:    append([]string{}, task.DependsOn...),
+		Labels:       map[string]string{},
+		DataSets:     []taskDataSet{},
+		BatchResults: []taskBatchResult{},
+	}
+	for k, v := range task.AlgoParam.Labels {
+		d.Labels[k] = v
+	}
+	for _, data := range task.DataSets {
+		d.DataSets = append(d.DataSets, taskDataSet{
+			DataID:      data.DataID,
+			Owner:       hex.EncodeToString(data.Owner),
+			Executor:    hex.EncodeToString(data.Executor),
+			Address:     data.Address,
+			PSILabel:    data.PsiLabel,
+			ConfirmedAt: formatTime(data.ConfirmedAt),
+			RejectedAt:  formatTime(data.RejectedAt),
+		})
+	}
+	for _, r := range task.BatchResults {
+		d.BatchResults = append(d.BatchResults, taskBatchResult{
+			Index:      r.Index,
+			Samples:    r.Samples,
+			Result:     r.Result,
+			ErrMessage: r.ErrMessage,
+		})
+	}
+	return d
+}
+
+func (s taskSummary) row() []string {
+	return []string{s.TaskID, s.TaskType, s.Name, s.Description, s.Status, s.PublishTime}
+}
+
+func (d taskDetail) row() []string {
+	return append(d.taskSummary.row(), d.Requester, d.Algorithm, d.ModelTaskID, d.StartTime, d.EndTime, d.ErrMessage, d.Result,
+		strings.Join(d.DependsOn, ","), blockchain.FormatLabels(d.Labels))
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeCSV writes the rows, the first of which is the header
+func writeCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// writeTable writes the rows aligned in columns, the first of which is the header
+func writeTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// writeOutcomes writes the rows of prediction outcomes in format, the first row is the header,
+// each outcome is a JSON object keyed by the header in the json format
+func writeOutcomes(w io.Writer, format string, rows [][]string) error {
+	switch format {
+	case formatJSON:
+		outcomes := []map[string]string{}
+		if len(rows) > 0 {
+			for _, row := range rows[1:] {
+				outcome := make(map[string]string, len(row))
+				for i, cell := range row {
+					if i < len(rows[0]) {
+						outcome[rows[0][i]] = cell
+					} else {
+						outcome[strconv.Itoa(i)] = cell
+					}
+				}
+				outcomes = append(outcomes, outcome)
+			}
+		}
+		return writeJSON(w, outcomes)
+	case formatTable:
+		return writeTable(w, rows)
+	default:
+		return writeCSV(w, rows)
+	}
+}
@@ -15,6 +15,9 @@ package task
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -29,6 +32,10 @@ var getByIDCmd = &cobra.Command{
 	Use:   "getbyid",
 	Short: "get the task by id",
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkFormat(format); err != nil {
+			fmt.Println(err)
+			return
+		}
 		client, err := requestClient.GetRequestClient(configPath)
 		if err != nil {
 			fmt.Printf("GetRequestClient failed: %v\n", err)
@@ -39,6 +46,12 @@ var getByIDCmd = &cobra.Command{
 			fmt.Printf("GetTaskById failed：%v\n", err)
 			return
 		}
+		if format != formatTable {
+			if err := writeTask(os.Stdout, format, task); err != nil {
+				fmt.Printf("failed to write task: %v\n", err)
+			}
+			return
+		}
 
 		publishTime := time.Unix(0, task.PublishTime).Format(timeTemplate)
 
@@ -50,12 +63,32 @@ var getByIDCmd = &cobra.Command{
 			blockchain.VlAlgorithmListValue[task.AlgoParam.Algo], task.AlgoParam.TrainParams.Alpha, task.AlgoParam.TrainParams.Amplitude,
 			task.AlgoParam.TrainParams.Accuracy, task.AlgoParam.ModelTaskID, task.Status, publishTime)
 
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("DependsOn: %s\n\n", strings.Join(task.DependsOn, ","))
+		}
+		if len(task.AlgoParam.Labels) > 0 {
+			fmt.Printf("Labels: %s\n\n", blockchain.FormatLabels(task.AlgoParam.Labels))
+		}
+
 		if task.AlgoParam.EvalParams != nil && task.AlgoParam.EvalParams.Enable {
 			fmt.Printf("ModelEvaluationRule: %s\n",
 				task.AlgoParam.EvalParams.EvalRule)
-			if task.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
-				fmt.Printf("PercentageToLeaveOutAsValidation: %d\n\n",
-					task.AlgoParam.EvalParams.RandomSplit.PercentLO)
+			if len(task.AlgoParam.EvalParams.Metrics) > 0 {
+				fmt.Printf("EvaluationMetrics: %s\n", strings.Join(task.AlgoParam.EvalParams.Metrics, ","))
+			}
+			if rs := task.AlgoParam.EvalParams.RandomSplit; task.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
+				if rs.GetTestFraction() > 0 {
+					fmt.Printf("FractionToLeaveOutAsValidation: %v\n", rs.GetTestFraction())
+				} else {
+					fmt.Printf("PercentageToLeaveOutAsValidation: %d\n", rs.GetPercentLO())
+				}
+				if rs.GetStrategy() != "" {
+					fmt.Printf("SplitStrategy: %s\n", rs.GetStrategy())
+				}
+				if rs.GetTimeColumn() != "" {
+					fmt.Printf("TimeColumn: %s\n", rs.GetTimeColumn())
+				}
+				fmt.Print("\n")
 			} else if task.AlgoParam.EvalParams.EvalRule == pbCom.EvaluationRule_ErCrossVal {
 				fmt.Printf("Shuffled: %t\nFolds: %d\n\n",
 					task.AlgoParam.EvalParams.Cv.Shuffle, task.AlgoParam.EvalParams.Cv.Folds)
@@ -73,8 +106,9 @@ var getByIDCmd = &cobra.Command{
 			if data.RejectedAt > 0 {
 				rt = time.Unix(0, data.RejectedAt).Format(timeTemplate)
 			}
-			fmt.Printf("DataID: %s\nOwner: %x\nExecutor: %x\nAddress: %s\nPSILabel: %s\nConfirmedAt: %s\nRejectedAt: %s\n\n",
-				data.DataID, data.Owner, data.Executor, data.Address, data.PsiLabel, ct, rt)
+			fmt.Printf("DataID: %s\nOwner: %x\nExecutor: %x\nAddress: %s\nPSILabel: %s\nColumns: %s\nBatchDataIDs: %s\nConfirmedAt: %s\nRejectedAt: %s\n\n",
+				data.DataID, data.Owner, data.Executor, data.Address, data.PsiLabel, strings.Join(data.Columns, ","),
+				strings.Join(data.BatchDataIDs, ","), ct, rt)
 		}
 
 		var startTime, endTime string
@@ -86,14 +120,30 @@ var getByIDCmd = &cobra.Command{
 		}
 		fmt.Printf("StartTime: %s\nEndTime: %s\n", startTime, endTime)
 
+		if b := task.PrivacyBudget; b != nil {
+			fmt.Printf("PrivacyBudget: epsilon %v, delta %v, rounds %d\n", b.Epsilon, b.Delta, b.Rounds)
+		}
 		fmt.Printf("ErrMessage: %s\nResult: %s\n\n", task.ErrMessage, task.Result)
+		for _, r := range task.BatchResults {
+			fmt.Printf("BatchInput: %d\nSamples: %d\nResult: %s\nErrMessage: %s\n\n", r.Index, r.Samples, r.Result, r.ErrMessage)
+		}
 	},
 }
 
+// writeTask writes the task as a JSON object, or a CSV row with a header row, which has no data sets and batch results
+func writeTask(w io.Writer, format string, task blockchain.FLTask) error {
+	d := newTaskDetail(task)
+	if format == formatJSON {
+		return writeJSON(w, d)
+	}
+	return writeCSV(w, [][]string{detailHeader, d.row()})
+}
+
 func init() {
 	rootCmd.AddCommand(getByIDCmd)
 
 	getByIDCmd.Flags().StringVarP(&id, "id", "i", "", "task id")
+	getByIDCmd.Flags().StringVar(&format, "format", formatTable, "output format, 'table', 'json' or 'csv'")
 
 	getByIDCmd.MarkFlagRequired("id")
 }
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// importanceCmd gets the feature importance of a model from the executors of its training task
+var importanceCmd = &cobra.Command{
+	Use:   "importance",
+	Short: "get the feature importance of a linear or logistic regression model",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getRequestClient()
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+		if privateKey == "" {
+			privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
+			if err != nil {
+				fmt.Printf("Read privateKey failed, err: %v\n", err)
+				return
+			}
+			privateKey = strings.TrimSpace(string(privateKeyBytes))
+		}
+
+		importances, err := client.GetFeatureImportance(privateKey, id)
+		if err != nil {
+			fmt.Printf("GetFeatureImportance failed：%v\n", err)
+			return
+		}
+		for _, i := range importances {
+			fmt.Printf("Feature: %s\nExecutor: %s\nCoefficient: %v\nImportance: %v\n\n",
+				i.Feature, i.Executor, i.Coefficient, i.Importance)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importanceCmd)
+
+	importanceCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "requester private key hex string")
+	importanceCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./reqkeys", "requester's key path")
+	importanceCmd.Flags().StringVarP(&id, "id", "i", "", "model id, the id of the training task")
+
+	importanceCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	requestClient "github.com/PaddlePaddle/PaddleDTX/dai/requester/client"
+)
+
+// lineageCmd gets the versions of a model
+var lineageCmd = &cobra.Command{
+	Use:   "lineage",
+	Short: "get the lineage of a model, from the given version to the first one",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := requestClient.GetRequestClient(configPath)
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+		lineage, err := client.GetModelLineage(id)
+		if err != nil {
+			fmt.Printf("GetModelLineage failed：%v\n", err)
+			return
+		}
+
+		for _, l := range lineage {
+			var endTime string
+			if l.EndTime != 0 {
+				endTime = time.Unix(0, l.EndTime).Format(timeTemplate)
+			}
+			fmt.Printf("ModelID: %s\nVersion: %d\nParentModelID: %s\nAlgorithm: %s\nDataIDs: %s\nPublishTime: %s\nEndTime: %s\n",
+				l.ModelID, l.Version, l.ParentModelID, l.Algorithm, strings.Join(l.DataIDs, ","),
+				time.Unix(0, l.PublishTime).Format(timeTemplate), endTime)
+			if l.TrainParams != nil {
+				fmt.Printf("Label: %s\nRegMode: %v\nRegParam: %v\nAlpha: %f\nAmplitude: %f\nAccuracy: %v\nBatchSize: %v\nSeed: %d\n",
+					l.TrainParams.Label, blockchain.RegModeListValue[l.TrainParams.RegMode], l.TrainParams.RegParam, l.TrainParams.Alpha,
+					l.TrainParams.Amplitude, l.TrainParams.Accuracy, l.TrainParams.BatchSize, l.TrainParams.Seed)
+				if o := l.TrainParams.Optimizer; o != nil {
+					fmt.Printf("Optimizer: %s, schedule %s\n", o.Optimizer, o.Schedule)
+				}
+			}
+			if b := l.PrivacyBudget; b != nil {
+				fmt.Printf("PrivacyBudget: epsilon %v, delta %v, rounds %d\n", b.Epsilon, b.Delta, b.Rounds)
+			}
+			if e := l.EarlyStop; e != nil {
+				fmt.Printf("EarlyStop: %s best %v at round %d, final %v, stopped %t at round %d\n",
+					e.Metric, e.BestValue, e.BestRound, e.FinalValue, e.Stopped, e.StoppedRound)
+			}
+			fmt.Print("\n")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lineageCmd)
+
+	lineageCmd.Flags().StringVarP(&id, "id", "i", "", "model id, the id of the training task")
+
+	lineageCmd.MarkFlagRequired("id")
+}
@@ -15,6 +15,8 @@ package task
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -39,6 +41,10 @@ var listTasksCmd = &cobra.Command{
 	Use:   "list",
 	Short: "list all tasks",
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkFormat(format); err != nil {
+			fmt.Println(err)
+			return
+		}
 		client, err := requestClient.GetRequestClient(configPath)
 		if err != nil {
 			fmt.Printf("GetRequestClient failed: %v\n", err)
@@ -77,6 +83,12 @@ var listTasksCmd = &cobra.Command{
 			fmt.Printf("ListTask failed：%v\n", err)
 			return
 		}
+		if format != formatTable {
+			if err := writeTasks(os.Stdout, format, tasks); err != nil {
+				fmt.Printf("failed to write tasks: %v\n", err)
+			}
+			return
+		}
 		for _, task := range tasks {
 			ptime := time.Unix(0, task.PublishTime).Format(timeTemplate)
 			fmt.Printf("TaskID: %s\nTaskType: %s\nTaskName: %s\nDescription: %s\nTaskStatus: %s\nPublishTime: %s\n\n",
@@ -87,6 +99,21 @@ var listTasksCmd = &cobra.Command{
 	},
 }
 
+// writeTasks writes the summaries of tasks as a JSON array, or CSV rows with a header row
+func writeTasks(w io.Writer, format string, tasks blockchain.FLTasks) error {
+	summaries := []taskSummary{}
+	rows := [][]string{summaryHeader}
+	for _, task := range tasks {
+		s := newTaskSummary(task)
+		summaries = append(summaries, s)
+		rows = append(rows, s.row())
+	}
+	if format == formatJSON {
+		return writeJSON(w, summaries)
+	}
+	return writeCSV(w, rows)
+}
+
 func init() {
 	rootCmd.AddCommand(listTasksCmd)
 
@@ -96,6 +123,7 @@ func init() {
 	listTasksCmd.Flags().StringVarP(&start, "st", "s", "", "start of time range during which tasks were published, example '2021-06-10 12:00:00'")
 	listTasksCmd.Flags().StringVarP(&end, "et", "e", time.Unix(0, time.Now().UnixNano()).Format(timeTemplate), "end of time range during which tasks were published, example '2021-06-10 12:00:00'")
 	listTasksCmd.Flags().Int64VarP(&limit, "limit", "l", blockchain.TaskListMaxNum, "maximum of tasks can be queried")
-	listTasksCmd.Flags().StringVar(&status, "status", "", "status of task, such as Confirming, Ready, ToProcess, Processing, Finished, Failed, default for all types of status")
+	listTasksCmd.Flags().StringVar(&status, "status", "", "status of task, such as Confirming, Ready, ToProcess, Processing, Finished, Failed, Cancelled, Timeout, default for all types of status")
+	listTasksCmd.Flags().StringVar(&format, "format", formatTable, "output format, 'table', 'json' or 'csv'")
 
 }
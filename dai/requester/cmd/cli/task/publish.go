@@ -16,6 +16,7 @@ package task
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -42,15 +43,98 @@ var (
 	taskId      string
 	description string // task description
 	psiLabel    string // id features list
+	columns     string // feature columns of each sample file, ';' between files and ',' between columns
+	batchFiles  string // other inputs of a batch prediction, ';' between inputs and ',' between files
+	psiAlgo     string // PSI algorithm, 'ecdh', 'oprf' or 'auto'
 	batchSize   uint64 // batch size for each round
+	seed        int64  // seed of shuffling samples for reproducible training, not seeded if 0
 	ev          bool   // whether perform model evaluation
 	evRule      int32  // evRule is the way to evaluate model, 0 means `Random Split`, 1 means `Cross Validation`, 2 means `Leave One Out`
+	evMode      string // evMode is the name of the way to evaluate model, 'random', 'kfold' or 'loo', overrides evRule if set
 	percentLO   int32  // percentage to leave out as validation set when perform model evaluation in the way of `Random Split`
 	folds       int32  // number of folds, 5 or 10 supported, default `10`, a optional parameter when perform model evaluation in the way of `Cross Validation`
+	evMetrics   string // metrics to compute with ',' as delimiter, all supported ones if empty
 	shuffle     bool   // whether to randomly disorder the samples before division, default `false`, a optional parameter when perform model evaluation in the way of `Cross Validation`
 
+	// validation set of `Random Split` chosen by fraction and strategy
+	testFrac   float64 // fraction of samples to leave out as validation set, overrides percentLO if set
+	splitStrat string  // strategy of choosing the validation set, 'random', 'stratified' or 'time'
+	timeColumn string  // column ordering samples by time in the time-ordered split
+
 	le         bool  // whether perform live model evaluation
 	lPercentLO int32 // percentage to leave out as validation set when perform live model evaluation
+
+	// early stopping based on the metric of live evaluation
+	esMetric   string  // metric of live evaluation, early stopping is disabled if empty
+	esPatience int64   // rounds without improvement before training stops
+	esMinDelta float64 // minimum change of the metric counted as improvement
+
+	priority    int32         // scheduling priority of the task on executors
+	taskTimeout time.Duration // maximum execution time of the task on executors
+	callbackURL string        // URL notified when the task reaches a terminal status
+
+	labels map[string]string // metadata of the task for filtering and billing
+
+	idempotencyKey string // key to avoid publishing the same task twice when submission is retried
+	dependsOn      string // upstream task IDs with ',' as delimiter, the task starts after all of them finish
+
+	incremental    bool    // whether to update the model of taskId with new samples
+	updateRounds   int64   // maximum rounds of incremental training
+	driftTolerance float64 // maximum increase of cost against the base model allowed in incremental training
+
+	scaling string // feature scaling method of linear-vl and logistic-vl
+
+	driftThreshold float64 // drift score above which columns predicted by linear-vl and logistic-vl are flagged as drifted
+
+	withScores       bool // whether outcomes of logistic-vl prediction include the predicted classes and raw scores
+	withExplanations bool // whether outcomes of linear-vl and logistic-vl prediction include the contributions of features
+
+	encryptResult bool // whether the prediction result is encrypted with the requester's public key
+
+	// estimate the cost of the task instead of publishing it
+	estimateOnly   bool
+	estimateRounds int64 // rounds of each training assumed by the estimate, decided by the task if 0
+
+	weightColumn string // column of sample weights of linear-vl and logistic-vl on the party with label
+
+	// loss function of linear-vl
+	loss       string  // 'squared', 'huber' or 'quantile'
+	huberDelta float64 // residuals beyond huberDelta are penalized linearly by huber loss
+	quantile   float64 // quantile predicted by quantile loss
+
+	// categorical columns of linear-vl and logistic-vl
+	categorical     string // categorical columns with ',' as delimiter
+	encoding        string // 'onehot' or 'ordinal'
+	unknownCategory string // policy for categories unseen in training, 'error' or 'unknown'
+
+	// differential privacy of linear-vl and logistic-vl
+	dpEpsilon  float64 // privacy budget epsilon, differential privacy is disabled if 0
+	dpDelta    float64 // privacy budget delta
+	dpClipNorm float64 // L2 norm the gradients are clipped to
+	dpRounds   int64   // rounds the budget is split among
+
+	// dry run of linear-vl and logistic-vl before training
+	dryRun           bool    // whether to run a dry run before training
+	dryRunFraction   float64 // fraction of the samples in the subset of the dry run
+	dryRunIterations int64   // iterations of the dry run
+
+	// optimizer and learning rate schedule of logistic-vl and dnn-paddlefl-vl
+	optimizer     string  // 'sgd', 'adam' or 'rmsprop'
+	beta1         float64 // decay rate of the first moment of adam
+	beta2         float64 // decay rate of the second moment of adam
+	optEpsilon    float64 // epsilon of adam and rmsprop
+	rho           float64 // decay rate of the squared gradients of rmsprop
+	lrSchedule    string  // 'constant', 'step' or 'cosine'
+	lrStepSize    int64   // rounds between decays of step schedule
+	lrGamma       float64 // decay factor of step schedule
+	lrTotalRounds int64   // rounds the learning rate anneals in by cosine schedule
+	minAlpha      float64 // minimum learning rate of cosine schedule
+
+	// hyperparameters of xgboost-vl
+	maxDepth     int64   // maximum depth of each tree
+	learningRate float64 // shrinkage applied to leaf weights
+	nEstimators  int64   // number of trees
+	lambda       float64 // L2 regularization on leaf weights
 )
 
 // checkTaskPublishParams check mpc task parameters
@@ -63,7 +147,7 @@ func checkTaskPublishParams() (pbCom.Algorithm, pbCom.TaskType, pbCom.RegMode, e
 	if algo, ok := blockchain.VlAlgorithmListName[algorithm]; ok {
 		pAlgo = algo
 	} else {
-		return pAlgo, pType, pRegMode, errorx.New(errorx.ErrCodeParam, "algorithm only support linear-vl or logistic-vl")
+		return pAlgo, pType, pRegMode, errorx.New(errorx.ErrCodeParam, "algorithm only support linear-vl, logistic-vl, dnn-paddlefl-vl or xgboost-vl")
 	}
 	// task type check
 	if taskType, ok := blockchain.TaskTypeListName[taskType]; ok {
@@ -97,6 +181,14 @@ var publishCmd = &cobra.Command{
 			return
 		}
 		// check params about evaluation
+		if evMode != "" {
+			rule, ok := blockchain.EvalModeListName[evMode]
+			if !ok {
+				fmt.Printf("invalid `evMode`, it should be random, kfold or loo")
+				return
+			}
+			evRule = int32(rule)
+		}
 		if evRule < 0 || evRule > 2 {
 			fmt.Printf("invalid `evRule`, it should be 0 or 1 or 2")
 			return
@@ -105,7 +197,7 @@ var publishCmd = &cobra.Command{
 			fmt.Printf("invalid `plo`, it should in the range of (0,100)")
 			return
 		}
-		if folds != 5 && folds != 10 {
+		if !blockchain.KFoldSupported[folds] {
 			fmt.Printf("invalid `folds`, it should be 5 or 10")
 			return
 		}
@@ -113,31 +205,115 @@ var publishCmd = &cobra.Command{
 			fmt.Printf("invalid `lplo`, it should in the range of (0,100)")
 			return
 		}
+		if taskTimeout < 0 || (taskTimeout > 0 && taskTimeout < time.Second) {
+			fmt.Printf("invalid `timeout`, it should be no less than 1s")
+			return
+		}
 
 		// pack `pbCom.TaskParams`
 		algorithmParams := pbCom.TaskParams{
 			Algo:        algo,
 			TaskType:    taskType,
 			ModelTaskID: taskId,
+			Priority:    priority,
+			Timeout:     int64(taskTimeout.Seconds()),
+			CallbackURL: callbackURL,
+			Labels:      labels,
 			TrainParams: &pbCom.TrainParams{
-				Label:     label,
-				LabelName: labelName,
-				RegMode:   regMode,
-				RegParam:  regParam,
-				Alpha:     alpha,
-				Amplitude: amplitude,
-				Accuracy:  int64(accuracy),
-				BatchSize: int64(batchSize),
+				Label:        label,
+				LabelName:    labelName,
+				RegMode:      regMode,
+				RegParam:     regParam,
+				Alpha:        alpha,
+				Amplitude:    amplitude,
+				Accuracy:     int64(accuracy),
+				BatchSize:    int64(batchSize),
+				PsiAlgorithm: psiAlgo,
+				Scaling:      scaling,
+				Seed:         seed,
+
+				Incremental:      incremental,
+				UpdateRounds:     updateRounds,
+				DriftTolerance:   driftTolerance,
+				DriftThreshold:   driftThreshold,
+				WeightColumn:     weightColumn,
+				WithScores:       withScores,
+				WithExplanations: withExplanations,
 			},
 		}
+		if categorical != "" {
+			algorithmParams.TrainParams.Categorical = &pbCom.CategoricalParams{
+				Columns:  strings.Split(categorical, ","),
+				Encoding: encoding,
+				Unknown:  unknownCategory,
+			}
+		}
+		if dpEpsilon != 0 {
+			algorithmParams.TrainParams.Dp = &pbCom.DPParams{
+				Epsilon:  dpEpsilon,
+				Delta:    dpDelta,
+				ClipNorm: dpClipNorm,
+				Rounds:   dpRounds,
+			}
+		}
+		if dryRun {
+			algorithmParams.TrainParams.DryRun = &pbCom.DryRunParams{
+				Fraction:   dryRunFraction,
+				Iterations: dryRunIterations,
+			}
+		}
+		if loss != "" {
+			algorithmParams.TrainParams.Loss = &pbCom.LossParams{
+				Type:  loss,
+				Delta: huberDelta,
+				Tau:   quantile,
+			}
+		}
+		if esMetric != "" {
+			algorithmParams.TrainParams.EarlyStopping = &pbCom.EarlyStoppingParams{
+				Metric:   esMetric,
+				Patience: esPatience,
+				MinDelta: esMinDelta,
+			}
+		}
+		if optimizer != "" || lrSchedule != "" {
+			algorithmParams.TrainParams.Optimizer = &pbCom.OptimizerParams{
+				Optimizer:   optimizer,
+				Beta1:       beta1,
+				Beta2:       beta2,
+				Epsilon:     optEpsilon,
+				Rho:         rho,
+				Schedule:    lrSchedule,
+				StepSize:    lrStepSize,
+				Gamma:       lrGamma,
+				TotalRounds: lrTotalRounds,
+				MinAlpha:    minAlpha,
+			}
+		}
+		if algo == pbCom.Algorithm_XGBOOST_VL {
+			algorithmParams.TrainParams.XgbParams = &pbCom.XGBoostParams{
+				MaxDepth:     maxDepth,
+				LearningRate: learningRate,
+				NEstimators:  nEstimators,
+				Lambda:       lambda,
+			}
+		}
 		// set `Evaluation` part
 		if ev {
 			algorithmParams.EvalParams = &pbCom.EvaluationParams{
 				Enable:   true,
 				EvalRule: pbCom.EvaluationRule(evRule),
 			}
+			if evMetrics != "" {
+				algorithmParams.EvalParams.Metrics = strings.Split(evMetrics, ",")
+			}
 			if algorithmParams.EvalParams.EvalRule == pbCom.EvaluationRule_ErRandomSplit {
-				algorithmParams.EvalParams.RandomSplit = &pbCom.RandomSplit{PercentLO: percentLO}
+				algorithmParams.EvalParams.RandomSplit = &pbCom.RandomSplit{
+					PercentLO:    percentLO,
+					TestFraction: testFrac,
+					Strategy:     splitStrat,
+					TimeColumn:   timeColumn,
+				}
 			} else if algorithmParams.EvalParams.EvalRule == pbCom.EvaluationRule_ErCrossVal {
 				algorithmParams.EvalParams.Cv = &pbCom.CrossVal{
 					Folds:   folds,
@@ -155,6 +331,19 @@ var publishCmd = &cobra.Command{
 			}
 		}
 
+		if estimateOnly {
+			printEstimate(client, requestClient.PublishOptions{
+				Files:     files,
+				Executors: executors,
+				TaskName:  taskName,
+				AlgoParam: algorithmParams,
+				PSILabels: psiLabel,
+				Columns:   columns,
+				DependsOn: dependsOn,
+			})
+			return
+		}
+
 		if privateKey == "" {
 			privateKeyBytes, err := file.ReadFile(keyPath, file.PrivateKeyFileName)
 			if err != nil {
@@ -164,23 +353,49 @@ var publishCmd = &cobra.Command{
 			privateKey = strings.TrimSpace(string(privateKeyBytes))
 		}
 
-		taskID, err := client.Publish(requestClient.PublishOptions{
-			PrivateKey:  privateKey,
-			Files:       files,
-			Executors:   executors,
-			TaskName:    taskName,
-			AlgoParam:   algorithmParams,
-			Description: description,
-			PSILabels:   psiLabel,
+		result, err := client.PublishTask(requestClient.PublishOptions{
+			PrivateKey:     privateKey,
+			Files:          files,
+			Executors:      executors,
+			TaskName:       taskName,
+			AlgoParam:      algorithmParams,
+			Description:    description,
+			PSILabels:      psiLabel,
+			Columns:        columns,
+			IdempotencyKey: idempotencyKey,
+			BatchFiles:     batchFiles,
+			DependsOn:      dependsOn,
+			EncryptResult:  encryptResult,
 		})
 		if err != nil {
 			fmt.Printf("Publish task failed: %v\n", err)
 			return
 		}
-		fmt.Println("TaskID:", taskID)
+		if result.Existed {
+			fmt.Printf("Task with the idempotency key has been published, status: %s\n", result.Status)
+		}
+		fmt.Println("TaskID:", result.TaskID)
 	},
 }
 
+// printEstimate prints the estimated cost of the task to publish with opt
+func printEstimate(client *requestClient.Client, opt requestClient.PublishOptions) {
+	e, err := client.EstimateTask(opt, estimateRounds)
+	if err != nil {
+		fmt.Printf("Estimate task failed: %v\n", err)
+		return
+	}
+	fmt.Println("The estimate is rough, the actual cost depends on the hardware, the network and how fast the training converges.")
+	fmt.Println("Aligned samples:", e.Samples)
+	if opt.AlgoParam.TaskType == pbCom.TaskType_LEARN {
+		fmt.Println("Rounds of each training:", e.Rounds)
+		fmt.Println("Trainings:", e.Trainings)
+	}
+	fmt.Println("Runtime:", e.Duration.Round(time.Second))
+	fmt.Printf("Peak memory of an executor: %.1fMB\n", float64(e.PeakMemory)/(1<<20))
+	fmt.Printf("Bytes transferred between executors: %.1fMB\n", float64(e.TransferBytes)/(1<<20))
+}
+
 func init() {
 	rootCmd.AddCommand(publishCmd)
 
@@ -188,7 +403,7 @@ func init() {
 	publishCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "requester's private key hex string")
 	publishCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./reqkeys", "requester's key path")
 	publishCmd.Flags().StringVarP(&taskType, "type", "t", "", "task type, 'train' or 'predict'")
-	publishCmd.Flags().StringVarP(&algorithm, "algorithm", "a", "", "algorithm assigned to task, 'linear-vl' and 'logistic-vl' are supported")
+	publishCmd.Flags().StringVarP(&algorithm, "algorithm", "a", "", "algorithm assigned to task, 'linear-vl', 'logistic-vl', 'dnn-paddlefl-vl' and 'xgboost-vl' are supported")
 	publishCmd.Flags().StringVarP(&files, "files", "f", "", "sample files IDs with ',' as delimiter, like '123,456'")
 	publishCmd.Flags().StringVarP(&executors, "executors", "e", "", "executor node names with ',' as delimiter, like 'executor1,executor2'")
 
@@ -196,25 +411,91 @@ func init() {
 	publishCmd.Flags().StringVarP(&label, "label", "l", "", "target feature for training task")
 	publishCmd.Flags().StringVar(&labelName, "labelName", "", "target variable required in logistic-vl training")
 	publishCmd.Flags().StringVarP(&psiLabel, "psiLabel", "p", "", "ID feature name list with ',' as delimiter, like 'id,id', required in vertical task")
-	publishCmd.Flags().StringVarP(&taskId, "taskId", "i", "", "finished train task ID from which obtain the model, required for predict task")
+	publishCmd.Flags().StringVar(&batchFiles, "batchFiles", "", "other inputs of a batch prediction predicted along with --files in one session, files of an input with ',' as delimiter in the order of executors, and inputs separated by ';', the outcomes of each input are got by 'result --index'")
+	publishCmd.Flags().StringVar(&columns, "columns", "", "feature columns of each sample file used by the task, names or 0-based indices with ',' as delimiter, and files separated by ';', like 'CRIM,ZN;AGE,DIS', all columns of a file if empty, psiLabel and label are always used")
+	publishCmd.Flags().StringVar(&psiAlgo, "psiAlgorithm", "", "PSI algorithm to align samples, 'ecdh', 'oprf' or 'auto' which selects 'oprf' for large sample sets, the executors' default if not set")
+	publishCmd.Flags().StringVarP(&taskId, "taskId", "i", "", "finished train task ID from which obtain the model, required for predict task, or the parent model a train task continues from")
+	publishCmd.Flags().StringVar(&scaling, "scaling", "", "feature scaling method of linear-vl and logistic-vl stored with the model, 'zscore', 'minmax', 'maxabs' or 'none', 'zscore' if not set, the base model's in incremental training")
+	publishCmd.Flags().Float64Var(&driftThreshold, "driftThreshold", 0, "for linear-vl and logistic-vl predict task, the shift of the mean of a column of the samples from the training one, in training standard deviations, above which drift is flagged and notified to callbackURL, drift is not detected if 0")
+	publishCmd.Flags().BoolVar(&withScores, "withScores", false, "for logistic-vl predict task, the outcomes include the predicted class, 1 for labelName and 0 otherwise, and the raw score before the sigmoid besides the probability")
+	publishCmd.Flags().BoolVar(&withExplanations, "withExplanations", false, "for linear-vl and logistic-vl predict task, the outcomes include the base and the contribution of each feature of each party to the prediction, before the sigmoid for logistic-vl, the party with label learns the names of the features of the other party and their contributions")
+	publishCmd.Flags().BoolVar(&encryptResult, "encryptResult", false, "for predict task, the result is encrypted with the requester's public key wherever it is stored, and only decrypted by 'result' with the requester's private key")
+	publishCmd.Flags().StringVar(&weightColumn, "weightColumn", "", "for linear-vl and logistic-vl train task, column of the sample file with label whose non-negative values weight the samples in the loss and gradients, samples are equally weighted if empty")
+	publishCmd.Flags().StringVar(&loss, "loss", "", "loss function of linear-vl train task, 'squared', 'huber' or 'quantile', 'squared' if not set")
+	publishCmd.Flags().Float64Var(&huberDelta, "huberDelta", 1, "for huber loss, residuals beyond it are penalized linearly, in units of the scaled label")
+	publishCmd.Flags().Float64Var(&quantile, "quantile", 0.5, "for quantile loss, the quantile to predict, in (0,1)")
+	publishCmd.Flags().StringVar(&categorical, "categorical", "", "categorical columns of linear-vl and logistic-vl with ',' as delimiter, encoded by the categories of the training samples which are stored with the model")
+	publishCmd.Flags().StringVar(&encoding, "encoding", "onehot", "encoding of categorical columns, 'onehot' or 'ordinal'")
+	publishCmd.Flags().StringVar(&unknownCategory, "unknownCategory", "error", "policy for categories unseen in training, 'error' fails the task, 'unknown' maps them to a bucket of unknown")
+	publishCmd.Flags().Float64Var(&dpEpsilon, "dpEpsilon", 0, "privacy budget epsilon of the task, enables differential privacy which adds Gaussian noise to the gradients of linear-vl and logistic-vl if not 0")
+	publishCmd.Flags().Float64Var(&dpDelta, "dpDelta", 1e-5, "privacy budget delta of the task for differential privacy")
+	publishCmd.Flags().Float64Var(&dpClipNorm, "dpClipNorm", 1, "L2 norm the gradients are clipped to before noise is added for differential privacy")
+	publishCmd.Flags().Int64Var(&dpRounds, "dpRounds", 100, "rounds the privacy budget is split among, training stops when the budget is used up")
+	publishCmd.Flags().BoolVar(&dryRun, "dryRun", false, "run linear-vl and logistic-vl train task for a few iterations on a small random subset of the samples before training, the task fails early if the cost becomes NaN or Inf or diverges")
+	publishCmd.Flags().Float64Var(&dryRunFraction, "dryRunFraction", 0.05, "fraction of the samples in the subset of the dry run, in (0,1)")
+	publishCmd.Flags().Int64Var(&dryRunIterations, "dryRunIterations", 2, "iterations of the dry run, 10 at most")
+	publishCmd.Flags().BoolVar(&incremental, "incremental", false, "update the model of taskId with the new samples instead of training from scratch, only for linear-vl and logistic-vl")
+	publishCmd.Flags().Int64Var(&updateRounds, "updateRounds", 10, "maximum rounds of incremental training")
+	publishCmd.Flags().Float64Var(&driftTolerance, "driftTolerance", 0, "maximum increase of cost against the base model allowed in incremental training, the updated model isn't saved otherwise")
 	publishCmd.Flags().StringVar(&regMode, "regMode", "", "regularization mode required in train task, no regularization if not set, options are l1(L1-norm) and l2(L2-norm)")
 	publishCmd.Flags().Float64Var(&regParam, "regParam", 0.1, "regularization parameter required in train task if set regMode")
 	publishCmd.Flags().Float64Var(&alpha, "alpha", 0.1, "learning rate required in train task")
 	publishCmd.Flags().Float64Var(&amplitude, "amplitude", 0.0001, "target difference of costs in two contiguous rounds that determines whether to stop training")
-	publishCmd.Flags().Uint64Var(&accuracy, "accuracy", 10, "accuracy of homomorphic encryption")
+	publishCmd.Flags().Uint64Var(&accuracy, "accuracy", 10, "accuracy of homomorphic encryption, the number of decimal digits kept in the fixed-point encoding in [1,15], 0 means the default of executors")
 	publishCmd.Flags().StringVarP(&description, "description", "d", "", "task description")
 	publishCmd.Flags().Uint64VarP(&batchSize, "batchSize", "b", 4,
 		"size of samples for one round of training loop, 0 for BGD(Batch Gradient Descent), non-zero for SGD(Stochastic Gradient Descent) or MBGD(Mini-Batch Gradient Descent)")
+	publishCmd.Flags().Int64Var(&seed, "seed", 0, "seed of mini-batch shuffling and dataset splits of evaluation and live evaluation to make training reproducible, 0 means not seeded")
+	// optional params about the optimizer of logistic-vl and dnn-paddlefl-vl
+	publishCmd.Flags().StringVar(&optimizer, "optimizer", "", "optimizer of logistic-vl and dnn-paddlefl-vl train task, 'sgd', 'adam' or 'rmsprop' which is not supported by dnn-paddlefl-vl, 'sgd' if not set")
+	publishCmd.Flags().Float64Var(&beta1, "beta1", 0, "decay rate of the first moment of adam, 0.9 if not set")
+	publishCmd.Flags().Float64Var(&beta2, "beta2", 0, "decay rate of the second moment of adam, 0.999 if not set")
+	publishCmd.Flags().Float64Var(&optEpsilon, "optEpsilon", 0, "epsilon of adam and rmsprop, 1e-8 if not set")
+	publishCmd.Flags().Float64Var(&rho, "rho", 0, "decay rate of the squared gradients of rmsprop, 0.9 if not set")
+	publishCmd.Flags().StringVar(&lrSchedule, "lrSchedule", "", "learning rate schedule starting from alpha, 'constant', 'step' or 'cosine', 'constant' if not set, a round is an epoch for dnn-paddlefl-vl")
+	publishCmd.Flags().Int64Var(&lrStepSize, "lrStepSize", 0, "rounds between decays of the learning rate for step schedule")
+	publishCmd.Flags().Float64Var(&lrGamma, "lrGamma", 0, "factor in (0,1) the learning rate is multiplied by at each decay of step schedule")
+	publishCmd.Flags().Int64Var(&lrTotalRounds, "lrTotalRounds", 0, "rounds in which the learning rate anneals to minAlpha for cosine schedule")
+	publishCmd.Flags().Float64Var(&minAlpha, "minAlpha", 0, "minimum learning rate of cosine schedule")
+	// optional params about xgboost-vl
+	publishCmd.Flags().Int64Var(&maxDepth, "maxDepth", 3, "maximum depth of each tree in xgboost-vl train task")
+	publishCmd.Flags().Float64Var(&learningRate, "learningRate", 0.3, "shrinkage applied to leaf weights in xgboost-vl train task")
+	publishCmd.Flags().Int64Var(&nEstimators, "nEstimators", 10, "number of trees in xgboost-vl train task")
+	publishCmd.Flags().Float64Var(&lambda, "lambda", 1, "L2 regularization on leaf weights in xgboost-vl train task")
 	// optional params about evaluation
 	publishCmd.Flags().BoolVar(&ev, "ev", false, "perform model evaluation")
 	publishCmd.Flags().Int32Var(&evRule, "evRule", 0, "the way to evaluate model, 0 means 'Random Split', 1 means 'Cross Validation', 2 means 'Leave One Out'")
+	publishCmd.Flags().StringVar(&evMode, "evMode", "", "the name of the way to evaluate model, 'random', 'kfold' (K-fold cross validation) or 'loo', overrides 'evRule' if set")
 	publishCmd.Flags().Int32Var(&folds, "folds", 10, "number of folds, 5 or 10 supported, a optional parameter when perform model evaluation in the way of 'Cross Validation'")
+	publishCmd.Flags().StringVar(&evMetrics, "metrics", "", "evaluation metrics to compute with ',' as delimiter, 'RMSE' and 'R2' for linear-vl, 'Accuracy', 'Precision', 'Recall', 'F1Score' and 'AUC' for logistic-vl, all supported ones if not set")
 	publishCmd.Flags().BoolVar(&shuffle, "shuffle", false, "shuffle the samples before division when perform model evaluation in the way of 'Cross Validation'")
 	publishCmd.Flags().Int32Var(&percentLO, "plo", 30, "percentage to leave out as validation set when perform model evaluation in the way of 'Random Split'")
+	publishCmd.Flags().Float64Var(&testFrac, "testFraction", 0, "fraction in (0,1) of samples to leave out as validation set in 'Random Split', overrides 'plo' if set")
+	publishCmd.Flags().StringVar(&splitStrat, "splitStrategy", "", "the way to choose the validation set in 'Random Split', 'random', 'stratified' (by label, logistic-vl only) or 'time' (the latest samples by 'timeColumn'), 'random' if not set")
+	publishCmd.Flags().StringVar(&timeColumn, "timeColumn", "", "numeric column of the sample file with label ordering samples by time, required by time-ordered 'Random Split'")
 
 	// optional params about live evaluation
 	publishCmd.Flags().BoolVar(&le, "le", false, "perform live model evaluation")
 	publishCmd.Flags().Int32Var(&lPercentLO, "lplo", 30, "percentage to leave out as validation set when perform live model evaluation")
+	publishCmd.Flags().StringVar(&esMetric, "esMetric", "", "metric of live evaluation early stopping is based on, 'RMSE' for linear-vl, 'Accuracy', 'Precision', 'Recall' or 'F1Score' for logistic-vl, requires 'le', early stopping is disabled if not set")
+	publishCmd.Flags().Int64Var(&esPatience, "esPatience", 10, "rounds without improvement of the metric before training stops early")
+	publishCmd.Flags().Float64Var(&esMinDelta, "esMinDelta", 0, "minimum change of the metric counted as improvement for early stopping")
+
+	// optional params about scheduling
+	publishCmd.Flags().DurationVar(&taskTimeout, "timeout", 0, "maximum execution time of the task like '30m' or '12h', clamped to the executors' maxTaskLimitTime, 0 means the executors' taskLimitTime")
+	publishCmd.Flags().StringVar(&callbackURL, "callbackURL", "", "http or https URL the task's status is POSTed to when it is Finished, Failed, Cancelled or Timeout")
+	publishCmd.Flags().StringVar(&dependsOn, "dependsOn", "", "upstream task IDs with ',' as delimiter, the task starts after all of them finish and fails if any of them doesn't, a predict task can use the model of an upstream train task by 'taskId'")
+	publishCmd.Flags().Int32Var(&priority, "priority", 0, "scheduling priority of the task on executors, tasks with higher priority are started first when executors' task limits are reached, 0 means the executors' default")
+
+	// optional params about metadata
+	publishCmd.Flags().StringToStringVar(&labels, "labels", nil, "labels attributing the task to teams or projects for filtering and billing, example 'team=risk,project=p1', which never affect the computation")
+
+	// optional params about estimate
+	publishCmd.Flags().BoolVar(&estimateOnly, "estimate", false, "print the estimated runtime, peak memory and bytes transferred of the task by the sizes of the sample files instead of publishing it, the private key is not required")
+	publishCmd.Flags().Int64Var(&estimateRounds, "rounds", 0, "rounds of each training assumed by --estimate, the number of trees of xgboost-vl, the rounds of incremental training or differential privacy if set, otherwise 100")
+
+	// optional params about submission
+	publishCmd.Flags().StringVar(&idempotencyKey, "idempotencyKey", "", "key identifying the submission, the task published before with the same key is returned instead of publishing a new one")
 
 	publishCmd.MarkFlagRequired("name")
 	publishCmd.MarkFlagRequired("type")
@@ -15,16 +15,18 @@ package task
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
-	requestClient "github.com/PaddlePaddle/PaddleDTX/dai/requester/client"
 	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
 )
 
 var (
-	output string
+	output       string
+	batchIndex   int32  // index of the input of a batch prediction
+	resultFormat string // format of prediction outcomes, 'csv', 'json' or 'table'
 )
 
 // getPredictResCmd gets predict task result from Executor
@@ -32,7 +34,11 @@ var getPredictResCmd = &cobra.Command{
 	Use:   "result",
 	Short: "get predict task result from executor node",
 	Run: func(cmd *cobra.Command, args []string) {
-		client, err := requestClient.GetRequestClient(configPath)
+		if err := checkFormat(resultFormat); err != nil {
+			fmt.Println(err)
+			return
+		}
+		client, err := getRequestClient()
 		if err != nil {
 			fmt.Printf("GetRequestClient failed: %v\n", err)
 			return
@@ -46,10 +52,28 @@ var getPredictResCmd = &cobra.Command{
 			privateKey = strings.TrimSpace(string(privateKeyBytes))
 		}
 
-		if err := client.GetPredictResult(privateKey, id, output); err != nil {
+		rows, err := client.GetPredictRows(privateKey, id, batchIndex)
+		if err != nil {
 			fmt.Printf("GetPredictResult failed：%v\n", err)
 			return
 		}
+		// the outcomes are written to stdout if output is '-'
+		if output == "-" {
+			if err := writeOutcomes(os.Stdout, resultFormat, rows); err != nil {
+				fmt.Printf("failed to write prediction outcomes: %v\n", err)
+			}
+			return
+		}
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Printf("failed to create %s: %v\n", output, err)
+			return
+		}
+		defer f.Close()
+		if err := writeOutcomes(f, resultFormat, rows); err != nil {
+			fmt.Printf("failed to write prediction outcomes: %v\n", err)
+			return
+		}
 
 		fmt.Println("OK")
 	},
@@ -61,7 +85,9 @@ func init() {
 	getPredictResCmd.Flags().StringVarP(&privateKey, "privkey", "k", "", "requester private key hex string")
 	getPredictResCmd.Flags().StringVarP(&keyPath, "keyPath", "", "./reqkeys", "requester's key path")
 	getPredictResCmd.Flags().StringVarP(&id, "id", "i", "", "prediction task id")
-	getPredictResCmd.Flags().StringVarP(&output, "output", "o", "", "file to store prediction outcomes")
+	getPredictResCmd.Flags().StringVarP(&output, "output", "o", "", "file to store prediction outcomes, '-' for stdout")
+	getPredictResCmd.Flags().StringVar(&resultFormat, "format", formatCSV, "format of prediction outcomes, 'csv', 'json' or 'table'")
+	getPredictResCmd.Flags().Int32Var(&batchIndex, "index", 0, "index of the input of a batch prediction whose outcomes are got, 0 is the input of --files")
 
 	getPredictResCmd.MarkFlagRequired("id")
 	getPredictResCmd.MarkFlagRequired("output")
@@ -15,6 +15,9 @@ package task
 
 import (
 	"github.com/spf13/cobra"
+
+	requestClient "github.com/PaddlePaddle/PaddleDTX/dai/requester/client"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
 )
 
 const timeTemplate = "2006-01-02 15:04:05"
@@ -24,6 +27,11 @@ var (
 	privateKey string
 	keyPath    string
 	id         string
+
+	// tls files to connect to executors whose gRPC servers require TLS
+	tlsCA   string
+	tlsCert string
+	tlsKey  string
 )
 
 // rootCmd represents task command
@@ -36,7 +44,22 @@ func RootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// getRequestClient returns the requester client, which connects to executors with the tls flags
+func getRequestClient() (*requestClient.Client, error) {
+	client, err := requestClient.GetRequestClient(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetExecutorTLS(tlsutil.ClientConf(tlsCA, tlsCert, tlsKey)); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configPath, "conf", "c", "./conf/config.toml", "configuration file")
+	rootCmd.PersistentFlags().StringVar(&tlsCA, "tlsCA", "", "CA certificate file to verify executors whose gRPC servers require TLS, the system roots if empty while tlsCert is set")
+	rootCmd.PersistentFlags().StringVar(&tlsCert, "tlsCert", "", "certificate file presented to executors requiring mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsKey, "tlsKey", "", "private key file of tlsCert")
 	rootCmd.MarkPersistentFlagRequired("config")
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyResultCmd verifies the signature of a prediction result signed by the executor storing it
+var verifyResultCmd = &cobra.Command{
+	Use:   "verifyresult",
+	Short: "verify the signature of a prediction result against the public key of the executor storing it",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := getRequestClient()
+		if err != nil {
+			fmt.Printf("GetRequestClient failed: %v\n", err)
+			return
+		}
+
+		sig, err := client.VerifyResult(id, batchIndex)
+		if sig != nil {
+			fmt.Printf("TaskID: %s\nBatchIndex: %d\nExecutor: %x\nDigest: %x\nSignature: %x\nValid: %t\n",
+				sig.TaskID, sig.BatchIndex, sig.Executor, sig.Digest, sig.Signature, sig.Valid)
+		}
+		if err != nil {
+			fmt.Printf("VerifyResult failed：%v\n", err)
+			return
+		}
+
+		fmt.Println("OK")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyResultCmd)
+
+	verifyResultCmd.Flags().StringVarP(&id, "id", "i", "", "prediction task id")
+	verifyResultCmd.Flags().Int32Var(&batchIndex, "index", 0, "index of the input of a batch prediction whose result is verified, 0 is the input of --files")
+
+	verifyResultCmd.MarkFlagRequired("id")
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/file"
+)
+
+// authExempt are the paths accessible without a token, probes usually can not carry one
+var authExempt = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// tokenAuth accepts requests with a token in the header 'Authorization: Bearer <token>'.
+// Tokens are stored by their SHA-256 digests, the identity of a token is the prefix of its digest,
+// which is safe to log and is used as the rate limit key.
+type tokenAuth struct {
+	lock    sync.RWMutex
+	conf    config.HttpAuthConf
+	tokens  map[[sha256.Size]byte]tokenScope // digest -> scope
+	watcher *file.Watcher                    // watches the token file of conf
+}
+
+// tokenScope is the identity of a token and the label selector restricting the tasks it accesses
+type tokenScope struct {
+	identity string
+	selector map[string]string // nil if the token accesses all tasks
+}
+
+// selectorContextKey is the key of the label selector of the token of a request in its context
+type selectorContextKey struct{}
+
+// newTokenAuth loads the tokens and watches the token file, it returns nil if conf is nil
+func newTokenAuth(conf *config.HttpAuthConf) (*tokenAuth, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	a := &tokenAuth{}
+	a.watcher = file.NewWatcher(func() error {
+		a.lock.RLock()
+		conf := a.conf
+		a.lock.RUnlock()
+		return a.reload(&conf)
+	})
+	if err := a.reload(conf); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload replaces the tokens with the ones of conf, and watches the token file of conf.
+// The current tokens are kept if the token file can not be read.
+func (a *tokenAuth) reload(conf *config.HttpAuthConf) error {
+	tokens := make(map[[sha256.Size]byte]tokenScope)
+	for _, t := range conf.Tokens {
+		if err := addToken(tokens, t); err != nil {
+			return err
+		}
+	}
+	if conf.TokenFile != "" {
+		if err := readTokenFile(conf.TokenFile, tokens); err != nil {
+			return err
+		}
+	}
+	a.lock.Lock()
+	a.conf = *conf
+	a.tokens = tokens
+	a.lock.Unlock()
+	logger.Infof("http server auth tokens loaded, %d tokens accepted", len(tokens))
+	for _, scope := range tokens {
+		if scope.selector != nil {
+			logger.Warn("label selectors of tokens only restrict the requests through the http server, " +
+				"the gRPC port accessing all tasks should only be reachable by other executors and trusted clients")
+			break
+		}
+	}
+
+	return a.watcher.Watch(conf.TokenFile)
+}
+
+// close stops watching the token file
+func (a *tokenAuth) close() {
+	a.watcher.Close()
+}
+
+// readTokenFile adds the tokens in path to tokens, one token per line,
+// empty lines and lines starting with '#' are ignored
+func readTokenFile(path string, tokens map[[sha256.Size]byte]tokenScope) error {
+	return file.ReadLines(path, func(line string) error {
+		if err := addToken(tokens, line); err != nil {
+			return errorx.Wrap(err, "invalid token in the token file %s", path)
+		}
+		return nil
+	})
+}
+
+// addToken adds token to tokens by its digest, the token may be followed by a label selector separated by spaces,
+// like "<token> team=risk,project=p1", then it only accesses the tasks with all the labels
+func addToken(tokens map[[sha256.Size]byte]tokenScope, token string) error {
+	fields := strings.Fields(token)
+	if len(fields) == 0 {
+		return nil
+	}
+	if len(fields) > 2 {
+		return errorx.New(errorx.ErrCodeConfig, "a token should be followed by at most one label selector")
+	}
+	digest := sha256.Sum256([]byte(fields[0]))
+	scope := tokenScope{identity: hex.EncodeToString(digest[:4])}
+	if len(fields) == 2 {
+		selector, err := blockchain.ParseLabels(fields[1])
+		if err != nil {
+			return errorx.NewCode(err, errorx.ErrCodeConfig, "invalid label selector of token %s", scope.identity)
+		}
+		scope.selector = selector
+	}
+	tokens[digest] = scope
+	return nil
+}
+
+// identify returns the identity of the bearer token of r, or an empty string if the token is missing or unknown.
+// Tokens are compared by their digests, so the time taken does not reveal how much of a token matches.
+func (a *tokenAuth) identify(r *http.Request) string {
+	scope, _ := a.lookup(r)
+	return scope.identity
+}
+
+// lookup returns the scope of the bearer token of r, and whether the token is valid
+func (a *tokenAuth) lookup(r *http.Request) (tokenScope, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return tokenScope{}, false
+	}
+	digest := sha256.Sum256([]byte(strings.TrimSpace(header[len(prefix):])))
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	scope, ok := a.tokens[digest]
+	return scope, ok
+}
+
+// handler rejects requests without a valid token with 401,
+// the label selector of the token is stored in the context of the request to be forwarded to the gRPC server
+func (a *tokenAuth) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authExempt[r.URL.Path] {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if scope, ok := a.lookup(r); ok {
+			if scope.selector != nil {
+				r = r.WithContext(context.WithValue(r.Context(), selectorContextKey{}, scope.selector))
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+		resp := response{
+			Code:    errorx.ErrCodeNotAuthorized,
+			Message: "missing or invalid bearer token",
+		}
+		bs, _ := json.Marshal(&resp)
+		w.Header().Set("Content-type", "application/json")
+		w.Header().Set("WWW-Authenticate", `Bearer realm="executor"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write(bs)
+		logger.Warnf("http request rejected without a valid token, ip: %v, url: %v", r.RemoteAddr, r.URL.Path)
+	})
+}
+
+// labelSelectorMetadata forwards the label selector of the token of r to the gRPC server, no metadata is forwarded
+// if the token accesses all tasks. A selector sent by the client itself in the header 'Grpc-Metadata-*' only
+// narrows the tasks accessible further, as the gRPC server requires all selectors to match.
+func labelSelectorMetadata(ctx context.Context, r *http.Request) metadata.MD {
+	selector, ok := r.Context().Value(selectorContextKey{}).(map[string]string)
+	if !ok {
+		return nil
+	}
+	return metadata.Pairs(blockchain.LabelSelectorKey, blockchain.FormatLabels(selector))
+}
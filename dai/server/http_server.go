@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
 	"github.com/golang/protobuf/proto"
@@ -25,7 +26,10 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
 	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/metrics"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
 )
 
 const (
@@ -37,6 +41,8 @@ const (
 	ReadBufferSize = 32 << 20
 	// WriteBufferSize write buffer size, default 32 MB.
 	WriteBufferSize = 32 << 20
+	// ReadyTimeout timeout of the readiness check of '/readyz'
+	ReadyTimeout = 3 * time.Second
 )
 
 // response defines the return format of the http requests
@@ -52,6 +58,23 @@ type HttpServer struct {
 	rpcEndpoint string
 	httpPort    string
 	allowCROS   bool
+	metrics     bool            // whether to expose metrics on '/metrics'
+	rpcDialOpt  grpc.DialOption // transport credentials to connect to rpcEndpoint
+	readiness   Readiness       // checks whether the service is ready on '/readyz'
+	limiter     *rateLimiter    // nil if requests are not limited
+	auth        *tokenAuth      // nil if requests do not require a token
+}
+
+// Readiness is implemented by the service proxied by HttpServer
+type Readiness interface {
+	// Ready returns nil if the service is ready, otherwise the error explains why
+	Ready(ctx context.Context) error
+}
+
+// ReadinessDetails is optionally implemented by Readiness, the details are returned as the data of '/readyz'
+type ReadinessDetails interface {
+	// ReadyDetails returns the details of the state of the service, such as its connections
+	ReadyDetails() interface{}
 }
 
 // NewHttpServer initiates gRPC-Gateway, allowCROS is used to determine whether to allow cross-domain requests
@@ -60,10 +83,31 @@ func NewHttpServer(conf *config.ExecutorConf) (*HttpServer, error) {
 		return nil, errorx.New(errorx.ErrCodeConfig,
 			"invalid httpserver config, httpPort or publicAddress can not be empty")
 	}
+	rpcDialOpt, err := tlsutil.DialOption(conf.TLS)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := newTokenAuth(conf.HttpServer.Auth)
+	if err != nil {
+		return nil, err
+	}
 	ser := &HttpServer{
 		rpcEndpoint: conf.PublicAddress,
+		rpcDialOpt:  rpcDialOpt,
 		httpPort:    conf.HttpServer.HttpPort,
 		allowCROS:   conf.HttpServer.AllowCros,
+		metrics:     conf.HttpServer.MetricsSwitch == "on",
+		limiter:     newRateLimiter(conf.HttpServer.RateLimit),
+		auth:        auth,
+	}
+	// limit requests with the same token together, and requests without a valid one by the client IP
+	if ser.limiter != nil && auth != nil && conf.HttpServer.RateLimit.KeyBy == "token" {
+		ser.limiter.keyFunc = func(r *http.Request) string {
+			if identity := auth.identify(r); identity != "" {
+				return "token " + identity
+			}
+			return clientIP(r)
+		}
 	}
 
 	return ser, nil
@@ -116,6 +160,10 @@ func httpErrorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Mars
 		Message: message,
 	}
 	bs, _ := json.Marshal(&resp)
+	// the API token is not allowed to access the task, or the requester is not allowed to use the executor
+	if code == errcodes.ErrCodeForbidden || code == errcodes.ErrCodeRequesterNotAllowed {
+		w.WriteHeader(http.StatusForbidden)
+	}
 	w.Write(bs)
 }
 
@@ -129,9 +177,10 @@ func (s *HttpServer) runHttpServer() error {
 	mux := runtime.NewServeMux(
 		runtime.WithForwardResponseOption(httpSuccHandler),
 		runtime.WithProtoErrorHandler(httpErrorHandler),
+		runtime.WithMetadata(labelSelectorMetadata),
 	)
 	opts := []grpc.DialOption{
-		grpc.WithInsecure(),
+		s.rpcDialOpt,
 		grpc.WithInitialWindowSize(InitialWindowSize),
 		grpc.WithWriteBufferSize(WriteBufferSize),
 		grpc.WithInitialConnWindowSize(InitialConnWindowSize),
@@ -142,10 +191,25 @@ func (s *HttpServer) runHttpServer() error {
 	if err != nil {
 		return err
 	}
+	router := http.NewServeMux()
+	router.Handle("/", mux)
+	s.registerProbes(router)
+	// expose Prometheus metrics if conf.HttpServer.MetricsSwitch is "on"
+	if s.metrics {
+		router.Handle("/metrics", metrics.Handler())
+	}
+	var h http.Handler = router
+	if s.auth != nil {
+		h = s.auth.handler(h)
+	}
+	// requests are limited before authentication, so that guessing tokens is limited too
+	if s.limiter != nil {
+		h = s.limiter.handler(h)
+	}
 	// listen on the port and start the httpServer
 	s.server = &http.Server{
 		Addr:    s.httpPort,
-		Handler: s.handler(mux),
+		Handler: s.handler(h),
 	}
 	if err = s.server.ListenAndServe(); err != http.ErrServerClosed {
 		return err
@@ -153,11 +217,65 @@ func (s *HttpServer) runHttpServer() error {
 	return nil
 }
 
+// registerProbes registers '/healthz' and '/readyz' for liveness and readiness probes.
+// '/healthz' returns 200 as long as the process is alive,
+// '/readyz' returns 200 if the service is ready, otherwise 503 with the reason, along with the details
+// of the state of the service if it implements ReadinessDetails.
+func (s *HttpServer) registerProbes(router *http.ServeMux) {
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeProbeResponse(w, nil, nil)
+	})
+	router.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		err := errorx.New(errorx.ErrCodeInternal, "service not started")
+		if s.readiness != nil {
+			ctx, cancel := context.WithTimeout(r.Context(), ReadyTimeout)
+			defer cancel()
+			err = s.readiness.Ready(ctx)
+			if d, ok := s.readiness.(ReadinessDetails); ok {
+				writeProbeResponse(w, err, d.ReadyDetails())
+				return
+			}
+		}
+		writeProbeResponse(w, err, nil)
+	})
+}
+
+// writeProbeResponse writes the result of probes with data, the status code is 503 if err is not nil
+func writeProbeResponse(w http.ResponseWriter, err error, data interface{}) {
+	resp := response{
+		Code:    errorx.SuccessCode,
+		Message: "ok",
+		Data:    data,
+	}
+	status := http.StatusOK
+	if err != nil {
+		resp.Code, resp.Message = errorx.Parse(err)
+		status = http.StatusServiceUnavailable
+	}
+	bs, _ := json.Marshal(&resp)
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(status)
+	w.Write(bs)
+}
+
 // Stop exits the gateway service
 func (s *HttpServer) Stop() {
 	if s.server != nil {
 		s.server.Shutdown(context.Background())
 	}
+	if s.auth != nil {
+		s.auth.close()
+	}
+}
+
+// ReloadAuth applies the reloaded tokens, auth can not be enabled or disabled without restarting
+func (s *HttpServer) ReloadAuth(conf *config.HttpServerConf) {
+	if s.auth == nil || conf == nil || conf.Auth == nil {
+		return
+	}
+	if err := s.auth.reload(conf.Auth); err != nil {
+		logger.WithError(err).Error("failed to reload auth tokens, keep the current ones")
+	}
 }
 
 // handler defines http request handler
@@ -186,7 +304,7 @@ func (s *HttpServer) handler(h http.Handler) http.Handler {
 // preflightHandler handles browser-initiated' OPTIONS preflight requests
 // The request returns the browser whether the server allows cross-domain requests
 func (s *HttpServer) preflightHandler(w http.ResponseWriter, r *http.Request) {
-	headers := []string{"Content-Type", "Accept"}
+	headers := []string{"Content-Type", "Accept", "Authorization"}
 	w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ","))
 	methods := []string{"GET", "HEAD", "POST", "PUT", "DELETE"}
 	w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ","))
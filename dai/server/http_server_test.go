@@ -0,0 +1,312 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/blockchain"
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// fakeReadiness returns err as the readiness
+type fakeReadiness struct {
+	err error
+}
+
+func (r *fakeReadiness) Ready(ctx context.Context) error {
+	return r.err
+}
+
+// detailedReadiness returns details along with the readiness
+type detailedReadiness struct {
+	fakeReadiness
+	details interface{}
+}
+
+func (r *detailedReadiness) ReadyDetails() interface{} {
+	return r.details
+}
+
+func TestProbes(t *testing.T) {
+	s := &HttpServer{}
+	router := http.NewServeMux()
+	s.registerProbes(router)
+	status := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	if code := status("/healthz"); code != http.StatusOK {
+		t.Errorf("expected /healthz returns 200, got %d", code)
+	}
+	if code := status("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected /readyz returns 503 before readiness is set, got %d", code)
+	}
+
+	readiness := &fakeReadiness{err: errorx.New(errorx.ErrCodeInternal, "blockchain not reachable")}
+	s.readiness = readiness
+	if code := status("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected /readyz returns 503 if not ready, got %d", code)
+	}
+	readiness.err = nil
+	if code := status("/readyz"); code != http.StatusOK {
+		t.Errorf("expected /readyz returns 200 if ready, got %d", code)
+	}
+
+	// the details are returned as data whether the service is ready or not
+	s.readiness = &detailedReadiness{
+		fakeReadiness: fakeReadiness{err: errorx.New(errcodes.ErrCodeChainDisconnected, "blockchain disconnected")},
+		details:       map[string]interface{}{"connected": false},
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var resp response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusServiceUnavailable || resp.Code != errcodes.ErrCodeChainDisconnected {
+		t.Errorf("expected /readyz returns 503 with the code of disconnection, got %d, %s", w.Code, resp.Code)
+	}
+	if d, ok := resp.Data.(map[string]interface{}); !ok || d["connected"] != false {
+		t.Errorf("expected details of readiness returned, got %v", resp.Data)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	l := newRateLimiter(&config.RateLimitConf{
+		RequestsPerSecond: 2,
+		Burst:             2,
+		Endpoints:         []config.EndpointRateLimitConf{{Path: "/v1/task/predict", RequestsPerSecond: 0.5, Burst: 1}},
+	})
+	l.now = func() time.Time { return now }
+	h := l.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	do := func(path, ip string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		r.RemoteAddr = ip + ":34567"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := do("/v1/task/list", "10.0.0.1").Code; code != http.StatusOK {
+			t.Fatalf("expected request %d within burst returns 200, got %d", i, code)
+		}
+	}
+	w := do("/v1/task/list", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected request over burst returns 429, got %d", w.Code)
+	}
+	if retry := w.Header().Get("Retry-After"); retry != "1" {
+		t.Errorf("expected Retry-After 1, got %q", retry)
+	}
+	// other clients and the endpoint configured separately have their own buckets
+	if code := do("/v1/task/list", "10.0.0.2").Code; code != http.StatusOK {
+		t.Errorf("expected request of another client returns 200, got %d", code)
+	}
+	if code := do("/v1/task/predict", "10.0.0.1").Code; code != http.StatusOK {
+		t.Errorf("expected request of the endpoint limited separately returns 200, got %d", code)
+	}
+	w = do("/v1/task/predict", "10.0.0.1")
+	if retry := w.Header().Get("Retry-After"); w.Code != http.StatusTooManyRequests || retry != "2" {
+		t.Errorf("expected 429 with Retry-After 2, got %d and %q", w.Code, retry)
+	}
+	for _, path := range []string{"/healthz", "/readyz", "/metrics"} {
+		if code := do(path, "10.0.0.1").Code; code != http.StatusOK {
+			t.Errorf("expected %s is not limited, got %d", path, code)
+		}
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if code := do("/v1/task/list", "10.0.0.1").Code; code != http.StatusOK {
+		t.Errorf("expected request after refill returns 200, got %d", code)
+	}
+	now = now.Add(bucketSweepInterval)
+	do("/v1/task/list", "10.0.0.3")
+	if n := len(l.buckets); n != 1 {
+		t.Errorf("expected idle buckets are removed, %d left", n)
+	}
+
+	if newRateLimiter(&config.RateLimitConf{}) != nil {
+		t.Error("expected no limiter without limits")
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens")
+	if err := ioutil.WriteFile(tokenFile, []byte("# tokens of requesters\nfile-token\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newTokenAuth(&config.HttpAuthConf{Tokens: []string{"inline-token"}, TokenFile: tokenFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.close()
+	h := a.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	do := func(path, authorization string) int {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for _, authorization := range []string{"Bearer inline-token", "bearer file-token"} {
+		if code := do("/v1/task/list", authorization); code != http.StatusOK {
+			t.Errorf("expected %q accepted, got %d", authorization, code)
+		}
+	}
+	for _, authorization := range []string{"", "Bearer ", "Bearer unknown", "Basic inline-token", "inline-token"} {
+		if code := do("/v1/task/list", authorization); code != http.StatusUnauthorized {
+			t.Errorf("expected %q rejected with 401, got %d", authorization, code)
+		}
+	}
+	if code := do("/healthz", ""); code != http.StatusOK {
+		t.Errorf("expected /healthz accessible without a token, got %d", code)
+	}
+
+	// revoke file-token by rewriting the token file
+	if err := ioutil.WriteFile(tokenFile, []byte("new-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for do("/v1/task/list", "Bearer file-token") != http.StatusUnauthorized {
+		if time.Now().After(deadline) {
+			t.Fatal("token file not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if code := do("/v1/task/list", "Bearer new-token"); code != http.StatusOK {
+		t.Errorf("expected the new token accepted, got %d", code)
+	}
+
+	// revoke inline-token by reloading the config
+	if err := a.reload(&config.HttpAuthConf{TokenFile: tokenFile}); err != nil {
+		t.Fatal(err)
+	}
+	if code := do("/v1/task/list", "Bearer inline-token"); code != http.StatusUnauthorized {
+		t.Errorf("expected the reloaded-out token rejected, got %d", code)
+	}
+	// the current tokens are kept if the token file can not be read
+	if err := a.reload(&config.HttpAuthConf{TokenFile: tokenFile + ".missing"}); err == nil {
+		t.Error("expected error of the missing token file")
+	}
+	if code := do("/v1/task/list", "Bearer new-token"); code != http.StatusOK {
+		t.Errorf("expected the current token kept, got %d", code)
+	}
+}
+
+func TestTokenLabelSelector(t *testing.T) {
+	a, err := newTokenAuth(&config.HttpAuthConf{Tokens: []string{"admin-token", "risk-token team=risk,project=p1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.close()
+	var md metadata.MD
+	h := a.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md = labelSelectorMetadata(r.Context(), r)
+	}))
+	do := func(token string) {
+		md = nil
+		r := httptest.NewRequest(http.MethodPost, "/v1/task/list", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	do("admin-token")
+	if md != nil {
+		t.Errorf("expected no selector forwarded for the token accessing all tasks, got %v", md)
+	}
+	do("risk-token")
+	if selectors := md.Get(blockchain.LabelSelectorKey); len(selectors) != 1 || selectors[0] != "project=p1,team=risk" {
+		t.Errorf("unexpected selector forwarded: %v", md)
+	}
+
+	if _, err := newTokenAuth(&config.HttpAuthConf{Tokens: []string{"bad-token team"}}); err == nil {
+		t.Error("expected error of the invalid label selector")
+	}
+	if _, err := newTokenAuth(&config.HttpAuthConf{Tokens: []string{"bad-token team=risk project=p1"}}); err == nil {
+		t.Error("expected error of more than one label selector")
+	}
+
+	// ErrCodeForbidden is returned with 403
+	w := httptest.NewRecorder()
+	httpErrorHandler(context.Background(), nil, &runtime.JSONPb{}, w, httptest.NewRequest(http.MethodPost, "/v1/task/get", nil),
+		errorx.New(errcodes.ErrCodeForbidden, "forbidden"))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestPprofServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	s := newPprofServer(&config.PprofConf{Address: addr, Duration: 500 * time.Millisecond})
+	stopped := make(chan struct{})
+	go func() {
+		s.Serve()
+		close(stopped)
+	}()
+	get := func() (int, error) {
+		resp, err := http.Get("http://" + addr + "/debug/pprof/cmdline")
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+	var code int
+	for i := 0; i < 20; i++ {
+		if code, err = get(); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if code != http.StatusOK {
+		t.Fatalf("expected pprof endpoint returns 200, got %d, err: %v", code, err)
+	}
+
+	// the endpoints are closed after the duration
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pprof endpoints were not closed after the duration")
+	}
+	if _, err := get(); err == nil {
+		t.Error("expected pprof endpoints closed")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// pprofServer serves the profiling endpoints of net/http/pprof on an address of its own,
+// and closes them after duration, see config.PprofConf
+type pprofServer struct {
+	server   *http.Server
+	duration time.Duration
+}
+
+// newPprofServer creates the server of the profiling endpoints, which has not started yet
+func newPprofServer(conf *config.PprofConf) *pprofServer {
+	// the handlers are registered on a mux of their own, never on http.DefaultServeMux
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return &pprofServer{
+		server:   &http.Server{Addr: conf.Address, Handler: mux},
+		duration: conf.Duration,
+	}
+}
+
+// Serve serves the endpoints until duration elapses or Stop is called, errors are logged
+// but never stop the executor, as the endpoints are only for diagnosis
+func (s *pprofServer) Serve() {
+	lis, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		logger.WithError(err).Errorf("failed to listen pprof endpoints on %s", s.server.Addr)
+		return
+	}
+	logger.Warnf("pprof endpoints are exposed on http://%s/debug/pprof/, they will be closed in %v", lis.Addr(), s.duration)
+	timer := time.AfterFunc(s.duration, func() {
+		logger.Infof("pprof endpoints are closed after %v", s.duration)
+		s.Stop()
+	})
+	defer timer.Stop()
+	if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+		logger.WithError(err).Error("failed to serve pprof endpoints")
+	}
+}
+
+// Stop closes the endpoints, profiles in progress are interrupted
+func (s *pprofServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// bucketSweepInterval is how often the buckets refilled to burst are removed
+const bucketSweepInterval = time.Minute
+
+// rateLimitExempt are the paths not limited, probes and metrics scraping should never be rejected
+var rateLimitExempt = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// tokenRate defines a token bucket refilled with rate tokens per second and holding at most burst tokens
+type tokenRate struct {
+	rate  float64
+	burst float64
+}
+
+// newTokenRate returns nil if requestsPerSecond is not positive, which means no limit.
+// The burst defaults to requestsPerSecond rounded up, and one at least.
+func newTokenRate(requestsPerSecond float64, burst int) *tokenRate {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	return &tokenRate{rate: requestsPerSecond, burst: float64(burst)}
+}
+
+// tokenBucket holds the tokens left for one client of an endpoint
+type tokenBucket struct {
+	limit  *tokenRate
+	tokens float64
+	last   time.Time // when tokens was refilled
+}
+
+// refill adds the tokens accumulated since the last refill
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.limit.burst, b.tokens+now.Sub(b.last).Seconds()*b.limit.rate)
+	b.last = now
+}
+
+// rateLimiter limits requests of each client with token buckets,
+// endpoints configured separately have their own buckets, others share the global ones.
+type rateLimiter struct {
+	global    *tokenRate
+	endpoints map[string]*tokenRate
+	keyFunc   func(r *http.Request) string // identifies the client, the default is the client IP
+	now       func() time.Time
+
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter returns nil if no limit is configured
+func newRateLimiter(conf *config.RateLimitConf) *rateLimiter {
+	if conf == nil {
+		return nil
+	}
+	l := &rateLimiter{
+		global:    newTokenRate(conf.RequestsPerSecond, conf.Burst),
+		endpoints: make(map[string]*tokenRate),
+		keyFunc:   clientIP,
+		now:       time.Now,
+		buckets:   make(map[string]*tokenBucket),
+	}
+	for _, e := range conf.Endpoints {
+		// an endpoint without a positive rate is not limited even if the global limit is set
+		l.endpoints[e.Path] = newTokenRate(e.RequestsPerSecond, e.Burst)
+	}
+	if l.global == nil && len(conf.Endpoints) == 0 {
+		return nil
+	}
+	return l
+}
+
+// clientIP returns the host of the remote address of r
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allow takes a token for the request, if there is none left,
+// it returns false and how long to wait until a token is available.
+func (l *rateLimiter) allow(r *http.Request) (bool, time.Duration) {
+	path := r.URL.Path
+	if rateLimitExempt[path] {
+		return true, 0
+	}
+	limit, ok := l.endpoints[path]
+	key := l.keyFunc(r)
+	if ok {
+		key = path + " " + key
+	} else {
+		limit = l.global
+	}
+	if limit == nil {
+		return true, 0
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: limit.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / limit.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep removes the buckets refilled to burst, they are the same as new ones
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= b.limit.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// handler rejects requests over the limit with 429, and Retry-After in seconds
+func (l *rateLimiter) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := l.allow(r)
+		if ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		resp := response{
+			Code:    errorx.ErrCodeNotAuthorized,
+			Message: "too many requests, retry after " + strconv.Itoa(retryAfter) + "s",
+		}
+		bs, _ := json.Marshal(&resp)
+		w.Header().Set("Content-type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write(bs)
+		logger.Warnf("http request rejected by rate limit, ip: %v, url: %v", r.RemoteAddr, r.URL.Path)
+	})
+}
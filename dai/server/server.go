@@ -20,13 +20,15 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/p2p"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tlsutil"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/tracing"
 )
 
 const (
-	// MaxRecvMsgSize max message size
-	MaxRecvMsgSize = 1024 * 1024 * 1024
 	// MaxConcurrentStreams max concurrent
 	MaxConcurrentStreams = 1000
 	// GRPCTIMEOUT grpc timeout
@@ -43,14 +45,33 @@ type Server struct {
 	GrpcServer *grpc.Server
 
 	httpServer *HttpServer
+	pprof      *pprofServer // nil if the profiling endpoints are not configured
 }
 
 // New creates GRPC and HTTP server which has no service registered and has not
 // started to accept requests yet.
 func New(conf *config.ExecutorConf) (*Server, error) {
 	// define grpc server
-	ser := grpc.NewServer(grpc.MaxRecvMsgSize(MaxRecvMsgSize),
-		grpc.MaxConcurrentStreams(MaxConcurrentStreams), grpc.ConnectionTimeout(time.Second*time.Duration(GRPCTIMEOUT)))
+	opts := []grpc.ServerOption{grpc.MaxConcurrentStreams(MaxConcurrentStreams),
+		grpc.ConnectionTimeout(time.Second * time.Duration(GRPCTIMEOUT)),
+		// continue traces of tasks started by other executors
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor())}
+	// keepalive and message sizes are the same as the connections to other executors
+	opts = append(opts, p2p.NewTransport(conf.Mpc).ServerOptions()...)
+	// serve over TLS if conf.TLS is configured, otherwise plaintext
+	if conf.TLS != nil {
+		creds, err := tlsutil.ServerCredentials(conf.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	ser := grpc.NewServer(opts...)
+	// reflection lists the services registered when it's queried, so those registered after New are included
+	if conf.GrpcReflection {
+		reflection.Register(ser)
+		logger.Warn("gRPC reflection is enabled, all the services are exposed to clients reaching " + conf.ListenAddress)
+	}
 	server := &Server{
 		listenAddr: conf.ListenAddress,
 		GrpcServer: ser,
@@ -64,9 +85,26 @@ func New(conf *config.ExecutorConf) (*Server, error) {
 		}
 		server.httpServer = httpServe
 	}
+	if conf.Pprof != nil {
+		server.pprof = newPprofServer(conf.Pprof)
+	}
 	return server, nil
 }
 
+// SetReadiness sets the readiness checked by '/readyz' of the http server, call it before Serve
+func (s *Server) SetReadiness(r Readiness) {
+	if s.httpServer != nil {
+		s.httpServer.readiness = r
+	}
+}
+
+// ReloadHttpAuth applies the reloaded token settings of the http server
+func (s *Server) ReloadHttpAuth(conf *config.HttpServerConf) {
+	if s.httpServer != nil {
+		s.httpServer.ReloadAuth(conf)
+	}
+}
+
 // Serve runs Server and blocks current routine
 func (s *Server) Serve(ctx context.Context) error {
 	errCh := make(chan error)
@@ -83,6 +121,11 @@ func (s *Server) Serve(ctx context.Context) error {
 		}()
 	}
 
+	// the profiling endpoints never stop the server, even if they fail
+	if s.pprof != nil {
+		go s.pprof.Serve()
+	}
+
 	// interrupt signal, gracefully shuts down the server
 	go func() {
 		<-ctx.Done()
@@ -119,13 +162,27 @@ func (s *Server) startHttpServer(ctx context.Context) error {
 	return ctx.Err()
 }
 
-// Stop when get interrupt signal, stop grpc server and http server
+// Stop when get interrupt signal, stop http server and grpc server
+// grpc server waits at most GRPCTIMEOUT seconds for pending requests, then closes the connections
 func (s *Server) Stop() {
-	if s.GrpcServer != nil {
-		s.GrpcServer.Stop()
+	if s.pprof != nil {
+		s.pprof.Stop()
 	}
-
 	if s.httpServer != nil {
 		s.httpServer.Stop()
 	}
+
+	if s.GrpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.GrpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second * time.Duration(GRPCTIMEOUT)):
+			logger.Warn("grpc server graceful stop timeout, close connections")
+			s.GrpcServer.Stop()
+		}
+	}
 }
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	pbTask "github.com/PaddlePaddle/PaddleDTX/dai/protos/task"
+)
+
+func TestGrpcReflection(t *testing.T) {
+	// listServices lists the services of a server through reflection
+	listServices := func(enabled bool) ([]string, error) {
+		srv, err := New(&config.ExecutorConf{
+			HttpServer:     &config.HttpServerConf{Switch: "off"},
+			GrpcReflection: enabled,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// registered after New, as the executor does
+		pbTask.RegisterTaskServer(srv.GrpcServer, &pbTask.UnimplementedTaskServer{})
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go srv.GrpcServer.Serve(lis)
+		defer srv.GrpcServer.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+		}); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		var services []string
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			services = append(services, s.GetName())
+		}
+		return services, nil
+	}
+
+	services, err := listServices(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range services {
+		found = found || s == "task.Task"
+	}
+	if !found {
+		t.Errorf("expected the task service listed, got %v", services)
+	}
+
+	if _, err := listServices(false); status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected reflection unimplemented if it's disabled, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checksum computes and verifies checksums of files in the form of '<algorithm>:<hex digest>',
+// checksums are recorded in the extra information of files stored in XuperDB.
+package checksum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+// SHA256 is the default and only supported algorithm
+const SHA256 = "sha256"
+
+// extra is the checksum field of the extra information of files, see blockchain.FLInfo
+type extra struct {
+	Checksum string `json:"checksum"`
+}
+
+// FromExtra returns the checksum in the extra information of a file,
+// it is empty if the extra information is not JSON or has no checksum
+func FromExtra(ext []byte) string {
+	var e extra
+	if err := json.Unmarshal(ext, &e); err != nil {
+		return ""
+	}
+	return e.Checksum
+}
+
+// Sum returns the SHA-256 checksum of the content of r
+func Sum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read file to compute checksum")
+	}
+	return SHA256 + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// parse returns the hash and the expected digest of checksum
+func parse(checksum string) (hash.Hash, []byte, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], SHA256) {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "unsupported checksum '%s', it should be like 'sha256:<hex digest>'", checksum)
+	}
+	digest, err := hex.DecodeString(parts[1])
+	if err != nil || len(digest) != sha256.Size {
+		return nil, nil, errorx.New(errcodes.ErrCodeParam, "invalid sha256 digest '%s'", parts[1])
+	}
+	return sha256.New(), digest, nil
+}
+
+// NewReader returns a reader which computes the checksum of rc while it is read,
+// and returns an integrity error instead of io.EOF if the content does not match checksum,
+// so the file is verified without being buffered. name identifies the file in the error.
+func NewReader(rc io.ReadCloser, checksum, name string) (io.ReadCloser, error) {
+	h, digest, err := parse(checksum)
+	if err != nil {
+		return nil, errorx.Wrap(err, "failed to verify file %s", name)
+	}
+	return &reader{rc: rc, hash: h, expected: digest, checksum: checksum, name: name}, nil
+}
+
+// reader verifies the checksum at the end of the content
+type reader struct {
+	rc       io.ReadCloser
+	hash     hash.Hash
+	expected []byte
+	checksum string
+	name     string
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if got := r.hash.Sum(nil); !bytes.Equal(got, r.expected) {
+			return n, errorx.New(errcodes.ErrCodeIntegrity,
+				"file %s is corrupted or tampered, checksum expected %s, got %s:%x", r.name, r.checksum, SHA256, got)
+		}
+	}
+	return n, err
+}
+
+func (r *reader) Close() error {
+	return r.rc.Close()
+}
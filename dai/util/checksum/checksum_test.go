@@ -0,0 +1,65 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checksum
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/errcodes"
+)
+
+func TestReader(t *testing.T) {
+	content := "id,CRIM,ZN\n1,0.00632,18\n"
+	sum, err := Sum(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sum, "sha256:") {
+		t.Fatalf("unexpected checksum %s", sum)
+	}
+	if got := FromExtra([]byte(`{"fileType":"csv","features":"id,CRIM,ZN","totalRows":1,"checksum":"` + sum + `"}`)); got != sum {
+		t.Errorf("expected checksum parsed from the extra information, got %q", got)
+	}
+	if got := FromExtra([]byte("not json")); got != "" {
+		t.Errorf("expected no checksum, got %q", got)
+	}
+
+	r, err := NewReader(ioutil.NopCloser(strings.NewReader(content)), sum, "file1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ioutil.ReadAll(r); err != nil || string(got) != content {
+		t.Errorf("expected content read, got %q, err: %v", got, err)
+	}
+
+	// truncated download
+	r, err = NewReader(ioutil.NopCloser(strings.NewReader(content[:10])), sum, "file1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ioutil.ReadAll(r)
+	if err == nil || !errorx.Is(err, errcodes.ErrCodeIntegrity) {
+		t.Errorf("expected integrity error, got: %v", err)
+	}
+
+	for _, invalid := range []string{"md5:0123", "sha256:xyz", "sha256:0123", "0123"} {
+		if _, err := NewReader(ioutil.NopCloser(strings.NewReader(content)), invalid, "file1"); err == nil {
+			t.Errorf("expected error of invalid checksum %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envelope encrypts contents with a random AES-256 key by AES-GCM, and wraps the key with
+// the public key of the recipient by ECIES, so that only the holder of the private key decrypts them.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecies"
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// magicHeader marks the contents sealed by Seal
+var magicHeader = []byte("PDTXENV1")
+
+// keySize is the size of the AES-256 key encrypting the content
+const keySize = 32
+
+// IsSealed returns whether content is sealed by Seal
+func IsSealed(content []byte) bool {
+	return len(content) >= len(magicHeader) && string(content[:len(magicHeader)]) == string(magicHeader)
+}
+
+// CheckPublicKey checks whether publicKey is a valid public key to seal contents with
+func CheckPublicKey(publicKey []byte) error {
+	if len(publicKey) != ecdsa.PublicKeyLength {
+		return errorx.New(errorx.ErrCodeParam, "invalid public key length %d, it should be %d bytes",
+			len(publicKey), ecdsa.PublicKeyLength)
+	}
+	var pubkey ecdsa.PublicKey
+	copy(pubkey[:], publicKey)
+	if _, err := ecdsa.ParsePublicKey(pubkey); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeParam, "invalid public key")
+	}
+	return nil
+}
+
+// Seal encrypts content with a random key, and wraps the key with publicKey. The sealed content is made up of
+// magicHeader, the length of the wrapped key in 2 bytes, the wrapped key, the nonce and the encrypted content.
+func Seal(publicKey []byte, content []byte) ([]byte, error) {
+	if err := CheckPublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	var pubkey ecdsa.PublicKey
+	copy(pubkey[:], publicKey)
+	pk, _ := ecdsa.ParsePublicKey(pubkey)
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to generate key")
+	}
+	wrappedKey, err := ecies.Encrypt(&pk, key)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to wrap key")
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to generate nonce")
+	}
+
+	sealed := make([]byte, 0, len(magicHeader)+2+len(wrappedKey)+len(nonce)+len(content)+aead.Overhead())
+	sealed = append(sealed, magicHeader...)
+	sealed = append(sealed, 0, 0)
+	binary.BigEndian.PutUint16(sealed[len(magicHeader):], uint16(len(wrappedKey)))
+	sealed = append(sealed, wrappedKey...)
+	sealed = append(sealed, nonce...)
+	// the header along with the wrapped key is authenticated
+	return aead.Seal(sealed, nonce, content, sealed[:len(sealed)-len(nonce)]), nil
+}
+
+// Open unwraps the key with privateKey, and decrypts the content sealed by Seal
+func Open(privateKey ecdsa.PrivateKey, sealed []byte) ([]byte, error) {
+	if !IsSealed(sealed) {
+		return nil, errorx.New(errorx.ErrCodeParam, "content is not sealed")
+	}
+	rest := sealed[len(magicHeader):]
+	if len(rest) < 2 {
+		return nil, errorx.New(errorx.ErrCodeCrypto, "sealed content is truncated")
+	}
+	keyLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < keyLen {
+		return nil, errorx.New(errorx.ErrCodeCrypto, "sealed content is truncated")
+	}
+	wrappedKey, rest := rest[:keyLen], rest[keyLen:]
+
+	sk := ecdsa.ParsePrivateKey(privateKey)
+	key, err := ecies.Decrypt(&sk, wrappedKey)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to unwrap key, the private key may be wrong")
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, errorx.New(errorx.ErrCodeCrypto, "sealed content is truncated")
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	content, err := aead.Open(nil, nonce, ciphertext, sealed[:len(sealed)-len(rest)])
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to decrypt sealed content")
+	}
+	return content, nil
+}
+
+// newGCM returns AES-GCM with key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, errorx.New(errorx.ErrCodeCrypto, "invalid key size %d, it should be %d bytes", len(key), keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to new aes cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeCrypto, "failed to new gcm")
+	}
+	return aead, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/crypto/core/ecdsa"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	privkey, pubkey, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("id,value\n1,0.5\n2,0.25\n")
+	sealed, err := Seal(pubkey[:], content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || IsSealed(content) {
+		t.Fatal("expected only the sealed content to be sealed")
+	}
+	if bytes.Contains(sealed, content) {
+		t.Fatal("expected content encrypted")
+	}
+	opened, err := Open(privkey, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, content) {
+		t.Errorf("expected %q, got %q", content, opened)
+	}
+
+	// others can't open it
+	otherKey, _, err := ecdsa.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(otherKey, sealed); err == nil {
+		t.Error("expected error opening with another private key")
+	}
+	// tampered or truncated contents are rejected
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := Open(privkey, tampered); err == nil {
+		t.Error("expected error opening tampered content")
+	}
+	if _, err := Open(privkey, sealed[:len(magicHeader)+1]); err == nil {
+		t.Error("expected error opening truncated content")
+	}
+
+	if err := CheckPublicKey(pubkey[:10]); err == nil {
+		t.Error("expected error of short public key")
+	}
+	if _, err := Seal(make([]byte, ecdsa.PublicKeyLength), content); err == nil {
+		t.Error("expected error sealing with a public key not on curve")
+	}
+}
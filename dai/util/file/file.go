@@ -9,6 +9,10 @@ import (
 
 const PrivateKeyFileName = "private.key"
 const PublicKeyFileName = "public.key"
+const EncryptionKeyFileName = "encryption.key"
+
+// PreviousKeyDirName is the directory under the key path keeping the key pair before the latest key rotation
+const PreviousKeyDirName = "previous"
 
 // ReadFile read the file contents
 func ReadFile(path, filename string) ([]byte, error) {
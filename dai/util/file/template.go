@@ -0,0 +1,112 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Placeholders of file name templates
+const (
+	PlaceholderTaskID    = "{task_id}"   // ID of the task the file belongs to
+	PlaceholderModelID   = "{model_id}"  // ID of the training task of the model, the task itself for training tasks
+	PlaceholderTimestamp = "{timestamp}" // publish time of the task in UTC, in TimestampLayout
+	PlaceholderType      = "{type}"      // type of the file, such as model and prediction
+)
+
+// TimestampLayout is the layout of the timestamp in file names
+const TimestampLayout = "20060102150405"
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// NameTemplate renders the names of the files of a type, such as "{task_id}_{type}.json".
+// Names are rendered from the task, so that the file of a task is found by rendering its name again.
+type NameTemplate struct {
+	text string
+	typ  string
+	// pattern matches the names rendered, and captures the task ID
+	pattern *regexp.Regexp
+}
+
+// ParseNameTemplate checks text and returns the template of the names of files of typ.
+// Placeholders other than the ones defined are rejected, and {task_id} is required so that
+// the files of different tasks are named differently. Names are not allowed to contain path separators.
+func ParseNameTemplate(text, typ string) (*NameTemplate, error) {
+	if strings.ContainsAny(text, `/\`) || text == "." || text == ".." {
+		return nil, fmt.Errorf("template '%s' can not contain path separators", text)
+	}
+	var expr strings.Builder
+	expr.WriteString("^")
+	hasTaskID := false
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringIndex(text, -1) {
+		expr.WriteString(regexp.QuoteMeta(text[last:loc[0]]))
+		switch placeholder := text[loc[0]:loc[1]]; placeholder {
+		case PlaceholderTaskID:
+			if hasTaskID {
+				expr.WriteString(`(?:.+?)`)
+			} else {
+				expr.WriteString(`(.+?)`)
+			}
+			hasTaskID = true
+		case PlaceholderModelID:
+			expr.WriteString(`.+?`)
+		case PlaceholderTimestamp:
+			expr.WriteString(`[0-9]{14}`)
+		case PlaceholderType:
+			expr.WriteString(regexp.QuoteMeta(typ))
+		default:
+			return nil, fmt.Errorf("unknown placeholder %s in template '%s', supported: %s, %s, %s and %s", placeholder, text,
+				PlaceholderTaskID, PlaceholderModelID, PlaceholderTimestamp, PlaceholderType)
+		}
+		last = loc[1]
+	}
+	if !hasTaskID {
+		return nil, fmt.Errorf("template '%s' should contain %s", text, PlaceholderTaskID)
+	}
+	expr.WriteString(regexp.QuoteMeta(text[last:]))
+	expr.WriteString("$")
+	pattern, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %v", text, err)
+	}
+	return &NameTemplate{text: text, typ: typ, pattern: pattern}, nil
+}
+
+// Render returns the name of the file of task taskID, whose model is trained by task modelID, and which is published at publishTime
+func (t *NameTemplate) Render(taskID, modelID string, publishTime time.Time) string {
+	return strings.NewReplacer(
+		PlaceholderTaskID, taskID,
+		PlaceholderModelID, modelID,
+		PlaceholderTimestamp, publishTime.UTC().Format(TimestampLayout),
+		PlaceholderType, t.typ,
+	).Replace(t.text)
+}
+
+// TaskID returns the task ID of a name rendered by the template, false if name doesn't match the template
+func (t *NameTemplate) TaskID(name string) (string, bool) {
+	m := t.pattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// String returns the text of the template
+func (t *NameTemplate) String() string {
+	return t.text
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNameTemplate(t *testing.T) {
+	invalid := []string{
+		"{task_id}_{date}.json", // unknown placeholder
+		"{model_id}.csv",        // without task ID
+		"models/{task_id}.json", // path separator
+		`{task_id}\model.json`,
+	}
+	for _, text := range invalid {
+		if _, err := ParseNameTemplate(text, "model"); err == nil {
+			t.Errorf("expected error for template %s", text)
+		}
+	}
+
+	tmpl, err := ParseNameTemplate("{model_id}_{task_id}_{timestamp}.{type}", "prediction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishTime := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	name := tmpl.Render("predict-1", "train", publishTime)
+	if name != "train_predict-1_20220304050607.prediction" {
+		t.Errorf("unexpected name %s", name)
+	}
+	if taskID, ok := tmpl.TaskID(name); !ok || taskID != "predict-1" {
+		t.Errorf("expected task ID predict-1 parsed from %s, got %s", name, taskID)
+	}
+	if _, ok := tmpl.TaskID("predict-1.prediction"); ok {
+		t.Error("expected name not rendered by the template mismatched")
+	}
+}
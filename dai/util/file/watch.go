@@ -0,0 +1,135 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+var logger = logrus.WithField("module", "util.file")
+
+// Watcher calls reload when the file it watches changes. The directory of the file is watched,
+// so that the file is reloaded even if it is replaced by renaming, like Kubernetes configmaps and secrets do.
+type Watcher struct {
+	reload func() error
+
+	lock    sync.Mutex
+	watcher *fsnotify.Watcher
+	file    string // the file being watched, empty if no file is watched
+}
+
+// NewWatcher returns a Watcher calling reload when the file it watches changes, it watches no file until Watch.
+// The content loaded before is expected to be kept if reload fails.
+func NewWatcher(reload func() error) *Watcher {
+	return &Watcher{reload: reload}
+}
+
+// Watch watches file instead of the one being watched, nothing is done if file is being watched already,
+// and no file is watched if file is empty
+func (w *Watcher) Watch(file string) error {
+	w.lock.Lock()
+	watching := w.file
+	w.lock.Unlock()
+	if file == watching {
+		return nil
+	}
+	w.Close()
+	if file == "" {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errorx.Wrap(err, "failed to watch file %s", file)
+	}
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return errorx.Wrap(err, "failed to watch file %s", file)
+	}
+	w.lock.Lock()
+	w.watcher, w.file = watcher, file
+	w.lock.Unlock()
+	go w.loop(watcher, filepath.Clean(file))
+	return nil
+}
+
+// loop calls reload when file changes, until watcher is closed
+func (w *Watcher) loop(watcher *fsnotify.Watcher, file string) {
+	for {
+		select {
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			// Kubernetes updates configmaps and secrets by swapping the symlink '..data' in the same directory
+			if filepath.Clean(e.Name) != file && filepath.Base(e.Name) != "..data" {
+				continue
+			}
+			if e.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			if err := w.reload(); err != nil {
+				logger.WithError(err).Errorf("failed to reload file %s, keep the content loaded before", file)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.WithError(err).Errorf("failed to watch file %s", file)
+		}
+	}
+}
+
+// Close stops watching the file, the watcher is closed without holding the lock,
+// as closing waits for the pending events to be consumed by the loop
+func (w *Watcher) Close() {
+	w.lock.Lock()
+	watcher := w.watcher
+	w.watcher, w.file = nil, ""
+	w.lock.Unlock()
+	if watcher != nil {
+		watcher.Close()
+	}
+}
+
+// ReadLines calls add with each line of file trimmed of spaces, empty lines and lines starting with '#' are skipped.
+// The error of add is returned as it is.
+func ReadLines(file string, add func(line string) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeConfig, "failed to read file %s", file)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := add(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeConfig, "failed to read file %s", file)
+	}
+	return nil
+}
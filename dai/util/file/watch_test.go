@@ -0,0 +1,96 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens")
+	if err := ioutil.WriteFile(path, []byte("a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var reloads int32
+	w := NewWatcher(func() error {
+		atomic.AddInt32(&reloads, 1)
+		return nil
+	})
+	defer w.Close()
+	if err := w.Watch(path); err != nil {
+		t.Fatal(err)
+	}
+	waitReload := func(name string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.SwapInt32(&reloads, 0) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("file not reloaded after %s", name)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if err := ioutil.WriteFile(path, []byte("b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitReload("writing")
+
+	// the file replaced by renaming is reloaded as well
+	tmp := filepath.Join(dir, "tokens.tmp")
+	if err := ioutil.WriteFile(tmp, []byte("c\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	waitReload("renaming")
+
+	// nothing is watched after closing
+	w.Close()
+	atomic.StoreInt32(&reloads, 0)
+	if err := ioutil.WriteFile(path, []byte("d\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&reloads); n != 0 {
+		t.Errorf("expected no reload after closing, got %d", n)
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requesters")
+	if err := ioutil.WriteFile(path, []byte("# partner B\n  key1 \n\nkey2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	if err := ReadLines(path, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"key1", "key2"}) {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+	if err := ReadLines(path+".missing", func(string) error { return nil }); err == nil {
+		t.Error("expected error if the file is missing")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httputil sends requests to XuperDB nodes with a given http client.
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// Get sends a GET request using client, http.DefaultClient is used if client is nil.
+// The same as XuperDB's http package, the error returned by the server is parsed from the body.
+func Get(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	body, _, err := GetWithHeader(ctx, client, url)
+	return body, err
+}
+
+// GetWithHeader is the same as Get, and returns the header of the response as well
+func GetWithHeader(ctx context.Context, client *http.Client, url string) (io.ReadCloser, http.Header, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to new request")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to do request")
+	}
+	defer resp.Body.Close()
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read response")
+	}
+	code, message, _ := errorx.TryParseFromString(string(bs))
+	if code != errorx.SuccessCode {
+		return nil, nil, errorx.New(code, message)
+	}
+	return ioutil.NopCloser(bytes.NewReader(bs)), resp.Header, nil
+}
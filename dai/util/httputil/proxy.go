@@ -0,0 +1,117 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httputil
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// ProxyFunc returns the proxy to use for a request URL, nil means connecting directly
+type ProxyFunc func(reqURL *url.URL) (*url.URL, error)
+
+// NewProxyFunc returns the ProxyFunc of conf, hosts matching conf.NoProxy, or NO_PROXY if it is empty,
+// and loopback addresses are connected directly
+func NewProxyFunc(conf *config.ProxyConf) (ProxyFunc, error) {
+	proxyURL, err := url.Parse(conf.URL)
+	if err != nil || proxyURL.Scheme != "http" || proxyURL.Host == "" {
+		return nil, errorx.New(errorx.ErrCodeConfig, "invalid proxy '%s', it should be like 'http://host:port'", conf.URL)
+	}
+	if conf.Username != "" {
+		proxyURL.User = url.UserPassword(conf.Username, conf.Password)
+	}
+	noProxy := conf.NoProxy
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+	}
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	pc := &httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    noProxy,
+	}
+	return pc.ProxyFunc(), nil
+}
+
+// ProxyClient returns the http client sending requests through the proxy of conf
+func ProxyClient(conf *config.ProxyConf) (*http.Client, error) {
+	proxy, err := NewProxyFunc(conf)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// DialTunnel connects to addr through the HTTP CONNECT tunnel of proxyURL using dial,
+// the returned connection is a raw TCP stream to addr, so TLS is negotiated with addr end to end.
+func DialTunnel(ctx context.Context, dial func(ctx context.Context, network, addr string) (net.Conn, error),
+	proxyURL *url.URL, addr string) (net.Conn, error) {
+
+	conn, err := dial(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to connect to proxy %s", proxyURL.Host)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u := proxyURL.User; u != nil {
+		password, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to send CONNECT to proxy %s", proxyURL.Host)
+	}
+	// the server sends nothing before the client starts the TLS handshake, so no data is left in the buffer
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read CONNECT response of proxy %s", proxyURL.Host)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		if resp.StatusCode == http.StatusProxyAuthRequired {
+			return nil, errorx.New(errorx.ErrCodeNotAuthorized,
+				"proxy %s requires authentication, check username and password of [executor.mode.proxy]", proxyURL.Host)
+		}
+		return nil, errorx.New(errorx.ErrCodeInternal, "proxy %s refused to connect to %s: %s", proxyURL.Host, addr, resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
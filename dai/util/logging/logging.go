@@ -21,8 +21,8 @@ import (
 	"time"
 
 	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
-	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/PaddlePaddle/PaddleDTX/dai/config"
 )
@@ -31,8 +31,8 @@ import (
 type Logging struct {
 	// Format is the log record format specifier for the Logging instance
 	// If Format is not provided, a default format that provides basic information will
-	// be used.
-	Format *logrus.TextFormatter
+	// be used. It is a text formatter by default, and a json formatter if log.format is "json".
+	Format logrus.Formatter
 
 	// logrus log level, default info
 	Level logrus.Level
@@ -44,51 +44,93 @@ type Logging struct {
 const (
 	TimeFormat   = "2006-01-02 15:04:05"
 	DefaultLevel = logrus.InfoLevel
+
+	// FormatText and FormatJSON are the supported values of log.format
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// TaskIDKey and PeerKey are the keys of structured fields shared by all modules,
+	// so that logs can be filtered by them
+	TaskIDKey = "task_id"
+	PeerKey   = "peer"
+
+	// defaults of log rotation, used if they are not configured
+	DefaultMaxSizeMB  = 100
+	DefaultMaxBackups = 7
+	DefaultMaxAgeDays = 30
 )
 
 // InitLog initiates Logging instance.
 func InitLog(conf *config.Log, fileName string, isSetFormat bool) (*Logging, error) {
 	logging := &Logging{}
-	logPath, level, err := logging.checkLogConf(conf)
+	logPath, level, format, err := logging.checkLogConf(conf)
 	if err != nil {
 		return nil, errorx.Wrap(err, "check log conf error")
 	}
 	logging.Level = level
 
-	writer, err := logging.writer(logPath, fileName)
+	writer, err := logging.writer(logPath, fileName, conf)
 	if err != nil {
 		return nil, errorx.Wrap(err, "get log writer error")
 	}
 	logging.Writer = writer
 
 	if isSetFormat {
-		logging.Format = &logrus.TextFormatter{
-			ForceColors:     true,
-			FullTimestamp:   true,
-			TimestampFormat: TimeFormat,
-		}
+		logging.Format = newFormatter(format)
 	}
 	return logging, nil
 }
 
+// Close flushes and closes the log file, logs written later reopen it
+func (l *Logging) Close() error {
+	if closer, ok := l.Writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// newFormatter returns the formatter of format, a json formatter writes every record as
+// a single JSON object with timestamp, level, message and structured fields
+func newFormatter(format string) logrus.Formatter {
+	if format == FormatJSON {
+		return &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime: "timestamp",
+				logrus.FieldKeyMsg:  "message",
+			},
+		}
+	}
+	return &logrus.TextFormatter{
+		ForceColors:     true,
+		FullTimestamp:   true,
+		TimestampFormat: TimeFormat,
+	}
+}
+
 // writer satisfies the io.Write contract. It delegates to the writer argument
 // of SetWriter or the Writer field of Config. The Core uses this when encoding
 // log records.
-func (l *Logging) writer(logPath, fileName string) (io.Writer, error) {
-	logFileName := filepath.Join(logPath, fileName)
-
-	// Generate a soft chain and point to the latest log file
-	// Keep log files for 30 days
-	// Log cutting interval 1 hour
-	logStd, err := rotatelogs.New(
-		logFileName+".%Y%m%d%H",
-		rotatelogs.WithLinkName(logFileName),
-		rotatelogs.WithMaxAge(720*time.Hour),
-		rotatelogs.WithRotationTime(time.Hour),
-	)
-
-	if err != nil {
-		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "new rotatelogs error")
+// The log file is rotated when its size reaches MaxSizeMB, rotated files are named with
+// the rotation time, and removed if they are older than MaxAgeDays or more than MaxBackups.
+// Writes are serialized by the writer, so no lines are lost during rotation.
+func (l *Logging) writer(logPath, fileName string, conf *config.Log) (io.Writer, error) {
+	logStd := &lumberjack.Logger{
+		Filename:   filepath.Join(logPath, fileName),
+		MaxSize:    conf.MaxSizeMB,
+		MaxBackups: conf.MaxBackups,
+		MaxAge:     conf.MaxAgeDays,
+		Compress:   conf.Compress,
+		LocalTime:  true,
+	}
+	if logStd.MaxSize == 0 {
+		logStd.MaxSize = DefaultMaxSizeMB
+	}
+	if logStd.MaxBackups == 0 {
+		logStd.MaxBackups = DefaultMaxBackups
+	}
+	if logStd.MaxAge == 0 {
+		logStd.MaxAge = DefaultMaxAgeDays
 	}
 	return logStd, nil
 }
@@ -96,10 +138,21 @@ func (l *Logging) writer(logPath, fileName string) (io.Writer, error) {
 // checkLogConf is used to verify the log configuration in the configuration file
 // Level is used to log the extremely detailed message. If the level name is
 // empty, default level is info
-func (l *Logging) checkLogConf(conf *config.Log) (string, logrus.Level, error) {
+// Format is "text" or "json", the default is "text"
+func (l *Logging) checkLogConf(conf *config.Log) (string, logrus.Level, string, error) {
 	path := conf.Path
 	if len(path) == 0 {
-		return "", 0, errorx.New(errorx.ErrCodeConfig, "missing config: log.path")
+		return "", 0, "", errorx.New(errorx.ErrCodeConfig, "missing config: log.path")
+	}
+	format := strings.ToLower(conf.Format)
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		return "", 0, "", errorx.New(errorx.ErrCodeConfig, "invalid config: log.format %s, supports 'text' and 'json'", conf.Format)
+	}
+	if conf.MaxSizeMB < 0 || conf.MaxBackups < 0 || conf.MaxAgeDays < 0 {
+		return "", 0, "", errorx.New(errorx.ErrCodeConfig, "invalid config: log.maxSizeMB, log.maxBackups and log.maxAgeDays can not be negative")
 	}
 
 	if strings.LastIndex(path, "/") != len([]rune(path))-1 {
@@ -108,7 +161,7 @@ func (l *Logging) checkLogConf(conf *config.Log) (string, logrus.Level, error) {
 	// create if the log file directory does not exist
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		if err := os.Mkdir(path, 0777); err != nil {
-			return "", 0, errorx.New(errorx.ErrCodeConfig, "mkdir logs error, err :%v", err)
+			return "", 0, "", errorx.New(errorx.ErrCodeConfig, "mkdir logs error, err :%v", err)
 		}
 	}
 	var level logrus.Level
@@ -117,5 +170,5 @@ func (l *Logging) checkLogConf(conf *config.Log) (string, logrus.Level, error) {
 		level = DefaultLevel
 	}
 
-	return path, level, nil
+	return path, level, format, nil
 }
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+func TestInitLogFormat(t *testing.T) {
+	for _, format := range []string{"", "text", "json", "JSON"} {
+		logStd, err := InitLog(&config.Log{Level: "info", Path: t.TempDir(), Format: format}, "executor.log", true)
+		if err != nil {
+			t.Fatalf("format %q rejected: %v", format, err)
+		}
+		_, isJSON := logStd.Format.(*logrus.JSONFormatter)
+		if isJSON != (format == "json" || format == "JSON") {
+			t.Errorf("unexpected formatter %T of format %q", logStd.Format, format)
+		}
+	}
+
+	if _, err := InitLog(&config.Log{Path: t.TempDir(), Format: "xml"}, "executor.log", true); err == nil {
+		t.Error("invalid format passed")
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(newFormatter(FormatJSON))
+
+	logger.WithField("module", "handler.mpc").WithField(TaskIDKey, "task-1").Info("start mpc task")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log record is not a JSON object: %s", buf.String())
+	}
+	for key, value := range map[string]string{"level": "info", "message": "start mpc task",
+		"module": "handler.mpc", TaskIDKey: "task-1"} {
+		if record[key] != value {
+			t.Errorf("expected %s to be %s, got: %v", key, value, record[key])
+		}
+	}
+	if _, ok := record["timestamp"]; !ok {
+		t.Error("timestamp not found")
+	}
+}
+
+func TestRotateConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	logStd, err := InitLog(&config.Log{Level: "info", Path: dir, MaxSizeMB: 1, MaxBackups: 100}, "executor.log", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(logStd.Writer)
+	logger.SetFormatter(newFormatter(FormatJSON))
+
+	// about 4MB of logs, so that the file is rotated several times
+	const goroutines, lines = 20, 2000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			entry := logger.WithField(TaskIDKey, fmt.Sprintf("task-%d", g))
+			for i := 0; i < lines; i++ {
+				entry.Infof("line %d of a task goroutine, padded to make the record longer", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	logStd.Writer.(io.Closer).Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "executor*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("expected the log file to be rotated, got files: %v", files)
+	}
+	count := 0
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+			var record map[string]interface{}
+			if err := json.Unmarshal(line, &record); err != nil {
+				t.Fatalf("broken log line in %s: %s", f, line)
+			}
+			count++
+		}
+	}
+	if count != goroutines*lines {
+		t.Errorf("expected %d log lines, got %d", goroutines*lines, count)
+	}
+}
+
+func TestInitLogNegativeRotation(t *testing.T) {
+	if _, err := InitLog(&config.Log{Path: t.TempDir(), MaxBackups: -1}, "executor.log", true); err == nil {
+		t.Error("negative maxBackups passed")
+	}
+}
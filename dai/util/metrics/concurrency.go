@@ -0,0 +1,127 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultPeakWindow is the default window of the peak number of tasks in execution
+const DefaultPeakWindow = time.Hour
+
+// Reasons of tasks not started
+const (
+	LimitReasonTaskLimit = "task_limit"      // the task limit of the task type is reached
+	LimitReasonResources = "resource_budget" // the memory or cpu budget of the node is used up
+)
+
+// Actions taken on tasks not started
+const (
+	LimitActionRejected = "rejected" // the task is refused to be added into the execution pool
+	LimitActionQueued   = "queued"   // the task waits in the queue for free slots
+)
+
+// runningSample is the number of tasks in execution since a time
+type runningSample struct {
+	at      time.Time
+	running int
+}
+
+// concurrencyTracker tracks the number of tasks in execution of a type against its task limit,
+// and the changes of the number in the window to get the peak
+type concurrencyTracker struct {
+	window  time.Duration
+	running int
+	limit   int
+	// samples are sorted by time, the first one is the number at the start of the window
+	samples []runningSample
+	lock    sync.Mutex
+}
+
+func newConcurrencyTracker() *concurrencyTracker {
+	return &concurrencyTracker{window: DefaultPeakWindow}
+}
+
+// add changes the number of tasks in execution by delta at now
+func (c *concurrencyTracker) add(delta int, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.running += delta
+	c.samples = append(c.samples, runningSample{at: now, running: c.running})
+	c.prune(now)
+}
+
+// setLimit sets the task limit
+func (c *concurrencyTracker) setLimit(limit int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.limit = limit
+}
+
+// setWindow sets the window of the peak, DefaultPeakWindow is used if window is not positive
+func (c *concurrencyTracker) setWindow(window time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if window <= 0 {
+		window = DefaultPeakWindow
+	}
+	c.window = window
+}
+
+// state returns the number of tasks in execution and the task limit
+func (c *concurrencyTracker) state() (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.running, c.limit
+}
+
+// utilization returns the number of tasks in execution divided by the task limit,
+// it is 1 if the limit is 0 and there are tasks in execution, as no more task can start
+func (c *concurrencyTracker) utilization() float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.limit <= 0 {
+		if c.running > 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(c.running) / float64(c.limit)
+}
+
+// peak returns the max number of tasks in execution in the window before now
+func (c *concurrencyTracker) peak(now time.Time) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.prune(now)
+	peak := c.running
+	for _, s := range c.samples {
+		if s.running > peak {
+			peak = s.running
+		}
+	}
+	return peak
+}
+
+// prune removes the samples before the window, except the last one of them,
+// which is the number of tasks in execution at the start of the window
+func (c *concurrencyTracker) prune(now time.Time) {
+	start := now.Add(-c.window)
+	i := 0
+	for i+1 < len(c.samples) && !c.samples[i+1].at.After(start) {
+		i++
+	}
+	c.samples = c.samples[i:]
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics defines the Prometheus metrics of the executor node,
+// which are exposed by the httpserver on '/metrics' if 'executor.httpserver.metricsSwitch' is "on",
+// and sent to StatsD if '[executor.statsd]' is configured, see StartStatsD.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+const (
+	namespace = "paddledtx"
+	subsystem = "executor"
+)
+
+var (
+	tasksStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "tasks_started_total",
+		Help:      "Number of tasks added into the execution pool.",
+	}, []string{"type"})
+	tasksStartedByLabel = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "tasks_started_by_label_total",
+		Help:      "Number of tasks added into the execution pool, by each of their labels.",
+	}, []string{"key", "value"})
+	tasksCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "tasks_completed_total",
+		Help:      "Number of tasks executed successfully.",
+	}, []string{"type"})
+	tasksFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "tasks_failed_total",
+		Help:      "Number of tasks failed, including expired and stopped ones.",
+	}, []string{"type"})
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "task_duration_seconds",
+		Help:      "Time from a task being added into the execution pool to its end.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"type", "result"})
+	runningTasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "running_tasks",
+		Help:      "Number of tasks in execution.",
+	}, []string{"type"})
+	taskLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "task_limit",
+		Help:      "Configured max number of tasks in execution concurrently.",
+	}, []string{"type"})
+	taskLimitReached = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "task_limit_reached_total",
+		Help:      "Number of times tasks are rejected or queued as the task limit or the resources budget is reached.",
+	}, []string{"type", "reason", "action"})
+	blockchainFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "blockchain_call_failures_total",
+		Help:      "Number of failed blockchain calls.",
+	}, []string{"method"})
+	mpcRpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "mpc_rpc_duration_seconds",
+		Help:      "Round-trip time of rpc requests between mpc nodes.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type", "result"})
+	psiDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "psi_duration_seconds",
+		Help:      "Time of sample alignment of a task, from encrypting local IDs to the intersection.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+	}, []string{"algorithm"})
+	storageReclaimed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "storage_reclaimed_bytes_total",
+		Help:      "Bytes of local files of ended tasks removed by the retention policy.",
+	}, []string{"kind"})
+)
+
+// concurrency tracks the tasks in execution of each type, by the label of task type
+var concurrency = map[string]*concurrencyTracker{
+	taskTypeLabel(pbCom.TaskType_LEARN):   newConcurrencyTracker(),
+	taskTypeLabel(pbCom.TaskType_PREDICT): newConcurrencyTracker(),
+}
+
+func init() {
+	prometheus.MustRegister(tasksStarted, tasksStartedByLabel, tasksCompleted, tasksFailed, taskDuration,
+		runningTasks, taskLimit, taskLimitReached, blockchainFailures, mpcRpcDuration, psiDuration, storageReclaimed)
+
+	// utilization and peak are computed when scraped
+	for label, tracker := range concurrency {
+		tracker := tracker
+		prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "task_utilization",
+			Help:        "Number of tasks in execution divided by the task limit.",
+			ConstLabels: prometheus.Labels{"type": label},
+		}, tracker.utilization))
+		prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "peak_running_tasks",
+			Help:        "Max number of tasks in execution over the peak window, which is 1h by default.",
+			ConstLabels: prometheus.Labels{"type": label},
+		}, func() float64 {
+			return float64(tracker.peak(time.Now()))
+		}))
+	}
+}
+
+// Handler returns the http handler exposing the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// taskTypeLabel returns the label of task type
+func taskTypeLabel(taskType pbCom.TaskType) string {
+	if taskType == pbCom.TaskType_LEARN {
+		return "train"
+	}
+	return "predict"
+}
+
+// resultLabel returns the label of a result
+func resultLabel(failed bool) string {
+	if failed {
+		return "failed"
+	}
+	return "success"
+}
+
+// TaskStarted records a task added into the execution pool, which is counted once for each of its labels
+func TaskStarted(taskType pbCom.TaskType, labels map[string]string) {
+	tasksStarted.WithLabelValues(taskTypeLabel(taskType)).Inc()
+	statsdCount("tasks_started_total", 1, taskTypeLabel(taskType))
+	for k, v := range labels {
+		tasksStartedByLabel.WithLabelValues(k, v).Inc()
+		statsdCount("tasks_started_by_label_total", 1, k, v)
+	}
+	runningTasks.WithLabelValues(taskTypeLabel(taskType)).Inc()
+	concurrency[taskTypeLabel(taskType)].add(1, time.Now())
+}
+
+// TaskFinished records a task removed from the execution pool, d is the time it stayed in the pool
+func TaskFinished(taskType pbCom.TaskType, failed bool, d time.Duration) {
+	label := taskTypeLabel(taskType)
+	if failed {
+		tasksFailed.WithLabelValues(label).Inc()
+		statsdCount("tasks_failed_total", 1, label)
+	} else {
+		tasksCompleted.WithLabelValues(label).Inc()
+		statsdCount("tasks_completed_total", 1, label)
+	}
+	taskDuration.WithLabelValues(label, resultLabel(failed)).Observe(d.Seconds())
+	statsdTiming("task_duration", d, label, resultLabel(failed))
+	runningTasks.WithLabelValues(label).Dec()
+	concurrency[label].add(-1, time.Now())
+}
+
+// SetTaskLimits records the configured task limits
+func SetTaskLimits(trainTaskLimit, predictTaskLimit int) {
+	taskLimit.WithLabelValues(taskTypeLabel(pbCom.TaskType_LEARN)).Set(float64(trainTaskLimit))
+	taskLimit.WithLabelValues(taskTypeLabel(pbCom.TaskType_PREDICT)).Set(float64(predictTaskLimit))
+	concurrency[taskTypeLabel(pbCom.TaskType_LEARN)].setLimit(trainTaskLimit)
+	concurrency[taskTypeLabel(pbCom.TaskType_PREDICT)].setLimit(predictTaskLimit)
+}
+
+// SetPeakWindow sets the window of the peak number of tasks in execution, DefaultPeakWindow is used if it is not positive
+func SetPeakWindow(window time.Duration) {
+	for _, tracker := range concurrency {
+		tracker.setWindow(window)
+	}
+}
+
+// TaskLimitReached records a task not started as the task limit or the resources budget is reached,
+// reason is LimitReasonTaskLimit or LimitReasonResources, and action is LimitActionRejected or LimitActionQueued
+func TaskLimitReached(taskType pbCom.TaskType, reason, action string) {
+	taskLimitReached.WithLabelValues(taskTypeLabel(taskType), reason, action).Inc()
+	statsdCount("task_limit_reached_total", 1, taskTypeLabel(taskType), reason, action)
+}
+
+// BlockchainCallFailed records a failed blockchain call
+func BlockchainCallFailed(method string) {
+	blockchainFailures.WithLabelValues(method).Inc()
+	statsdCount("blockchain_call_failures_total", 1, method)
+}
+
+// MpcRpcObserved records the round-trip time of an rpc request between mpc nodes
+func MpcRpcObserved(taskType pbCom.TaskType, d time.Duration, err error) {
+	mpcRpcDuration.WithLabelValues(taskTypeLabel(taskType), resultLabel(err != nil)).Observe(d.Seconds())
+	statsdTiming("mpc_rpc_duration", d, taskTypeLabel(taskType), resultLabel(err != nil))
+}
+
+// PSIObserved records the time of sample alignment by the PSI algorithm
+func PSIObserved(algorithm string, d time.Duration) {
+	psiDuration.WithLabelValues(algorithm).Observe(d.Seconds())
+	statsdTiming("psi_duration", d, algorithm)
+}
+
+// StorageReclaimed records a local file of kind removed by the retention policy
+func StorageReclaimed(kind string, bytes int64) {
+	storageReclaimed.WithLabelValues(kind).Add(float64(bytes))
+	statsdCount("storage_reclaimed_bytes_total", float64(bytes), kind)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pbCom "github.com/PaddlePaddle/PaddleDTX/dai/protos/common"
+)
+
+func TestHandler(t *testing.T) {
+	SetTaskLimits(100, 50)
+	TaskStarted(pbCom.TaskType_LEARN, map[string]string{"team": "risk"})
+	TaskStarted(pbCom.TaskType_PREDICT, nil)
+	TaskFinished(pbCom.TaskType_LEARN, false, 3*time.Second)
+	TaskFinished(pbCom.TaskType_PREDICT, true, time.Second)
+	BlockchainCallFailed("GetTaskById")
+	MpcRpcObserved(pbCom.TaskType_LEARN, 10*time.Millisecond, errors.New("timeout"))
+	PSIObserved("oprf", 2*time.Second)
+	TaskLimitReached(pbCom.TaskType_PREDICT, LimitReasonTaskLimit, LimitActionQueued)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`paddledtx_executor_tasks_started_total{type="train"} 1`,
+		`paddledtx_executor_tasks_started_by_label_total{key="team",value="risk"} 1`,
+		`paddledtx_executor_tasks_completed_total{type="train"} 1`,
+		`paddledtx_executor_tasks_failed_total{type="predict"} 1`,
+		`paddledtx_executor_task_duration_seconds_count{result="success",type="train"} 1`,
+		`paddledtx_executor_running_tasks{type="train"} 0`,
+		`paddledtx_executor_task_limit{type="predict"} 50`,
+		`paddledtx_executor_blockchain_call_failures_total{method="GetTaskById"} 1`,
+		`paddledtx_executor_mpc_rpc_duration_seconds_count{result="failed",type="train"} 1`,
+		`paddledtx_executor_psi_duration_seconds_count{algorithm="oprf"} 1`,
+		`paddledtx_executor_task_limit_reached_total{action="queued",reason="task_limit",type="predict"} 1`,
+		`paddledtx_executor_task_utilization{type="train"} 0`,
+		`paddledtx_executor_peak_running_tasks{type="train"} 1`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(body), e) {
+			t.Errorf("metric %s not found", e)
+		}
+	}
+}
+
+func TestConcurrencyTracker(t *testing.T) {
+	c := newConcurrencyTracker()
+	c.setWindow(time.Minute)
+	c.setLimit(4)
+	start := time.Now()
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	c.add(1, at(0))
+	c.add(1, at(10))
+	c.add(1, at(20))
+	c.add(-1, at(30))
+	if u := c.utilization(); u != 0.5 {
+		t.Errorf("expected utilization 0.5, got %v", u)
+	}
+	if p := c.peak(at(40)); p != 3 {
+		t.Errorf("expected peak 3, got %d", p)
+	}
+	// 3 tasks in execution from 20s to 30s, which is out of the window at 91s
+	if p := c.peak(at(85)); p != 3 {
+		t.Errorf("expected peak 3 in the window, got %d", p)
+	}
+	if p := c.peak(at(91)); p != 2 {
+		t.Errorf("expected peak 2 after the window passes, got %d", p)
+	}
+	c.add(-2, at(100))
+	if p := c.peak(at(200)); p != 0 {
+		t.Errorf("expected peak 0 without tasks in the window, got %d", p)
+	}
+
+	c.setLimit(0)
+	c.add(1, at(210))
+	if u := c.utilization(); u != 1 {
+		t.Errorf("expected utilization 1 when the limit is 0, got %v", u)
+	}
+}
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	stop, err := StartStatsD(StatsDOptions{Address: conn.LocalAddr().String(), Prefix: "dtx.", FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetTaskLimits(10, 5)
+	TaskStarted(pbCom.TaskType_LEARN, map[string]string{"team": "risk.a"})
+	TaskFinished(pbCom.TaskType_LEARN, true, 1500*time.Millisecond)
+	PSIObserved("ecdh", 2*time.Second)
+	stop()
+	// nothing is sent after stopped
+	BlockchainCallFailed("GetTaskById")
+
+	received := make(map[string]bool)
+	buf := make([]byte, 1024)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		received[string(buf[:n])] = true
+	}
+	expected := []string{
+		"dtx.tasks_started_total.train:1|c",
+		"dtx.tasks_started_by_label_total.team.risk_a:1|c",
+		"dtx.tasks_failed_total.train:1|c",
+		"dtx.task_duration.train.failed:1500|ms",
+		"dtx.psi_duration.ecdh:2000|ms",
+		"dtx.task_limit.predict:5|g",
+		"dtx.running_tasks.train:0|g",
+	}
+	for _, e := range expected {
+		if !received[e] {
+			t.Errorf("metric %s not received", e)
+		}
+	}
+	for line := range received {
+		if strings.HasPrefix(line, "dtx.blockchain_call_failures_total") {
+			t.Errorf("metric %s sent after stopped", line)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultStatsDPrefix is the prefix of the metrics sent to StatsD if it is not configured
+	DefaultStatsDPrefix = namespace + "." + subsystem
+	// DefaultStatsDFlushInterval is the interval the gauges are sent to StatsD if it is not configured
+	DefaultStatsDFlushInterval = 10 * time.Second
+)
+
+// StatsDOptions defines the StatsD server the metrics are sent to, see StartStatsD
+type StatsDOptions struct {
+	Address       string        // address of the StatsD server, in the form of 'host:port'
+	Prefix        string        // prefix of the names of metrics, DefaultStatsDPrefix is used if it is empty
+	FlushInterval time.Duration // interval of sending gauges, DefaultStatsDFlushInterval is used if it is not positive
+}
+
+// statsdClient sends the metrics to a StatsD server over UDP in the plain StatsD line protocol,
+// the label values of a Prometheus metric are appended to its name, e.g. "paddledtx.executor.tasks_started_total.train",
+// and durations are sent as timers in milliseconds, named without the suffix "_seconds"
+type statsdClient struct {
+	conn   net.Conn
+	prefix string
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+var (
+	logger = logrus.WithField("module", "util.metrics")
+
+	statsdLock sync.RWMutex
+	statsd     *statsdClient // nil if metrics are not sent to StatsD
+)
+
+// StartStatsD starts sending the metrics to the StatsD server of opts, in addition to the Prometheus ones.
+// Counters and durations are sent when they are recorded, and gauges every opts.FlushInterval.
+// It returns the function to stop sending, which sends the gauges for the last time.
+func StartStatsD(opts StatsDOptions) (func(), error) {
+	conn, err := net.Dial("udp", opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect StatsD server %s: %v", opts.Address, err)
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultStatsDPrefix
+	}
+	interval := opts.FlushInterval
+	if interval <= 0 {
+		interval = DefaultStatsDFlushInterval
+	}
+	c := &statsdClient{
+		conn:   conn,
+		prefix: strings.TrimSuffix(prefix, "."),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	statsdLock.Lock()
+	statsd = c
+	statsdLock.Unlock()
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.sendGauges()
+			select {
+			case <-ticker.C:
+			case <-c.stop:
+				c.sendGauges()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			statsdLock.Lock()
+			statsd = nil
+			statsdLock.Unlock()
+			close(c.stop)
+			<-c.done
+			c.conn.Close()
+		})
+	}, nil
+}
+
+// sendGauges sends the gauges of the tasks in execution of each type
+func (c *statsdClient) sendGauges() {
+	now := time.Now()
+	for label, tracker := range concurrency {
+		running, limit := tracker.state()
+		c.send("running_tasks", float64(running), "g", label)
+		c.send("task_limit", float64(limit), "g", label)
+		c.send("task_utilization", tracker.utilization(), "g", label)
+		c.send("peak_running_tasks", float64(tracker.peak(now)), "g", label)
+	}
+}
+
+// send sends the value of the metric name with the labels, in the StatsD type metricType
+func (c *statsdClient) send(name string, value float64, metricType string, labels ...string) {
+	parts := append([]string{c.prefix, name}, labels...)
+	for i := 2; i < len(parts); i++ {
+		parts[i] = statsdEscaper.Replace(parts[i])
+	}
+	line := strings.Join(parts, ".") + ":" + strconv.FormatFloat(value, 'f', -1, 64) + "|" + metricType
+	// a lost metric doesn't affect tasks, so failures are only logged
+	if _, err := c.conn.Write([]byte(line)); err != nil {
+		logger.WithError(err).Debugf("failed to send %s to StatsD", name)
+	}
+}
+
+// statsdEscaper replaces the characters of label values which are separators in the StatsD line protocol
+var statsdEscaper = strings.NewReplacer(".", "_", ":", "_", "|", "_", "@", "_", " ", "_", "\n", "_")
+
+// statsdCount sends the increment of a counter if StatsD is enabled
+func statsdCount(name string, value float64, labels ...string) {
+	statsdLock.RLock()
+	defer statsdLock.RUnlock()
+	if statsd != nil {
+		statsd.send(name, value, "c", labels...)
+	}
+}
+
+// statsdTiming sends a duration as a timer in milliseconds if StatsD is enabled
+func statsdTiming(name string, d time.Duration, labels ...string) {
+	statsdLock.RLock()
+	defer statsdLock.RUnlock()
+	if statsd != nil {
+		statsd.send(name, float64(d)/float64(time.Millisecond), "ms", labels...)
+	}
+}
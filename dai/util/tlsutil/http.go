@@ -0,0 +1,160 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+	"github.com/PaddlePaddle/PaddleDTX/dai/util/httputil"
+)
+
+// HTTPClient returns the https client used to download sample files from dataOwner and storage nodes,
+// it presents the local certificate and verifies servers against conf.CAFile.
+// The certificate of a host listed in identities must be issued to the mapped identity instead of the host,
+// so that a node holding another certificate of the same CA can not impersonate the host.
+// If proxy is not nil, connections are tunneled through it, and certificates are verified the same way.
+func HTTPClient(conf *config.TLSConf, identities []*config.HostIdentity, proxy *config.ProxyConf) (*http.Client, error) {
+	tlsConf, err := clientTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	d := &dialer{
+		tlsConf:    tlsConf,
+		caFile:     conf.CAFile,
+		identities: make(map[string]string),
+		netDialer:  &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	for _, id := range identities {
+		d.identities[id.Host] = id.Identity
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialTLSContext = d.dialTLS
+	if proxy != nil {
+		if d.proxy, err = httputil.NewProxyFunc(proxy); err != nil {
+			return nil, err
+		}
+		// https requests are tunneled by dialTLS, as the transport skips DialTLSContext for proxied requests
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if req.URL.Scheme == "https" {
+				return nil, nil
+			}
+			return d.proxy(req.URL)
+		}
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// dialer establishes TLS connections and checks the identities of servers
+type dialer struct {
+	tlsConf    *tls.Config
+	caFile     string
+	identities map[string]string // key is 'host:port', value is the expected identity
+	netDialer  *net.Dialer
+	proxy      httputil.ProxyFunc // nil if connecting directly
+}
+
+// dialTLS connects to addr and completes the handshake, the server certificate is verified
+// against the identity mapped to addr, or the host of addr if addr is not mapped
+func (d *dialer) dialTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeParam, "invalid address %s", addr)
+	}
+	tlsConf := d.tlsConf.Clone()
+	identity, mapped := d.identities[addr]
+	if mapped {
+		tlsConf.ServerName = identity
+	} else {
+		tlsConf.ServerName = host
+	}
+
+	rawConn, err := d.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		rawConn.SetDeadline(deadline)
+	}
+	conn := tls.Client(rawConn, tlsConf)
+	if err := conn.Handshake(); err != nil {
+		rawConn.Close()
+		return nil, d.handshakeError(err, addr, identity, mapped)
+	}
+	rawConn.SetDeadline(time.Time{})
+	return &alertConn{Conn: conn, addr: addr}, nil
+}
+
+// dial connects to addr directly, or through the proxy if it applies to addr
+func (d *dialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.proxy != nil {
+		proxyURL, err := d.proxy(&url.URL{Scheme: "https", Host: addr})
+		if err != nil {
+			return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to get the proxy of %s", addr)
+		}
+		if proxyURL != nil {
+			return httputil.DialTunnel(ctx, d.netDialer.DialContext, proxyURL, addr)
+		}
+	}
+	return d.netDialer.DialContext(ctx, network, addr)
+}
+
+// alertConn explains the alerts sent by the server after the handshake. In TLS 1.3,
+// the server verifies the client certificate after the client completes the handshake,
+// so a rejected certificate is reported by the first read.
+type alertConn struct {
+	*tls.Conn
+	addr string
+}
+
+func (c *alertConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil && strings.HasPrefix(err.Error(), "remote error: tls:") {
+		err = errorx.NewCode(err, errorx.ErrCodeNotAuthorized,
+			"%s rejected the certificate of the executor, check that it is signed by the CA trusted by the server", c.addr)
+	}
+	return n, err
+}
+
+// handshakeError explains why the handshake with addr failed and how to fix it
+func (d *dialer) handshakeError(err error, addr, identity string, mapped bool) error {
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	switch {
+	case errors.As(err, &hostErr) && mapped:
+		return errorx.NewCode(err, errorx.ErrCodeNotAuthorized,
+			"the certificate of %s is not issued to its expected identity %s, the server may be impersonated by another node, "+
+				"check the certificate of the server or the identity of %s in [executor.mode.identities]", addr, identity, addr)
+	case errors.As(err, &hostErr):
+		return errorx.NewCode(err, errorx.ErrCodeNotAuthorized,
+			"the certificate of %s is not issued to the host, issue the certificate to the host "+
+				"or map %s to the identity of the certificate in [executor.mode.identities]", addr, addr)
+	case errors.As(err, &authErr):
+		return errorx.NewCode(err, errorx.ErrCodeNotAuthorized,
+			"the certificate of %s is not signed by the CA in %s", addr, d.caFile)
+	default:
+		return errorx.NewCode(err, errorx.ErrCodeNotAuthorized,
+			"tls handshake with %s failed, check that the server enables https and trusts the certificate of the executor", addr)
+	}
+}
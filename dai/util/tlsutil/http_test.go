@@ -0,0 +1,180 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// startHTTPServer starts an https server requiring client certificates signed by ca,
+// and returns its address in the form of 'host:port'
+func startHTTPServer(t *testing.T, ca *testCA, certFile, keyFile string) string {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	checkErr(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.Listener.Addr().String()
+}
+
+func TestHTTPClient(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	otherCA := newTestCA(t, dir, "other-ca")
+	serverCert, serverKey := ca.issue(t, dir, "dataowner1")
+	clientCert, clientKey := ca.issue(t, dir, "executor1")
+	addr := startHTTPServer(t, ca, serverCert, serverKey)
+
+	mtls := &config.TLSConf{CertFile: clientCert, KeyFile: clientKey, CAFile: ca.file}
+	cases := []struct {
+		name       string
+		conf       *config.TLSConf
+		identities []*config.HostIdentity
+		errMsg     string // empty means success
+	}{
+		{"unmappedHost", mtls, nil, ""},
+		{"expectedIdentity", mtls, []*config.HostIdentity{{Host: addr, Identity: "dataowner1"}}, ""},
+		{"otherHostMapped", mtls, []*config.HostIdentity{{Host: "127.0.0.1:1", Identity: "dataowner2"}}, ""},
+		{"impersonated", mtls, []*config.HostIdentity{{Host: addr, Identity: "dataowner2"}}, "expected identity dataowner2"},
+		{"untrustedServer", &config.TLSConf{CertFile: clientCert, KeyFile: clientKey, CAFile: otherCA.file}, nil, "not signed by the CA"},
+		{"withoutClientCert", &config.TLSConf{CAFile: ca.file}, nil, "rejected the certificate of the executor"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := HTTPClient(c.conf, c.identities, nil)
+			checkErr(t, err)
+			resp, err := client.Get("https://" + addr)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if c.errMsg == "" && err != nil {
+				t.Errorf("expected success, got: %v", err)
+			}
+			if c.errMsg != "" && (err == nil || !strings.Contains(err.Error(), c.errMsg)) {
+				t.Errorf("expected error containing %q, got: %v", c.errMsg, err)
+			}
+		})
+	}
+}
+
+// startProxy starts an HTTP proxy which tunnels CONNECT requests with the basic credentials user:pass,
+// the host 'dataowner1' is resolved to 127.0.0.1. It returns the proxy URL and the hosts requested.
+func startProxy(t *testing.T) (string, chan string) {
+	requested := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+		if r.Header.Get("Proxy-Authorization") != "Basic "+auth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		requested <- r.Host
+		target, err := net.Dial("tcp", strings.Replace(r.Host, "dataowner1", "127.0.0.1", 1))
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			io.Copy(target, conn)
+			target.Close()
+		}()
+		io.Copy(conn, target)
+		conn.Close()
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, requested
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	serverCert, serverKey := ca.issue(t, dir, "dataowner1")
+	clientCert, clientKey := ca.issue(t, dir, "executor1")
+	_, port, err := net.SplitHostPort(startHTTPServer(t, ca, serverCert, serverKey))
+	checkErr(t, err)
+	addr := "dataowner1:" + port
+	proxyURL, requested := startProxy(t)
+
+	mtls := &config.TLSConf{CertFile: clientCert, KeyFile: clientKey, CAFile: ca.file}
+	cases := []struct {
+		name       string
+		proxy      *config.ProxyConf
+		identities []*config.HostIdentity
+		proxied    bool   // whether the request goes through the proxy
+		errMsg     string // empty means success
+	}{
+		{"tunneled", &config.ProxyConf{URL: proxyURL, Username: "user", Password: "pass"}, nil, true, ""},
+		{"impersonated", &config.ProxyConf{URL: proxyURL, Username: "user", Password: "pass"},
+			[]*config.HostIdentity{{Host: addr, Identity: "dataowner2"}}, true, "expected identity dataowner2"},
+		{"wrongPassword", &config.ProxyConf{URL: proxyURL, Username: "user", Password: "wrong"}, nil, false,
+			"requires authentication"},
+		// dataowner1 is not resolvable without the proxy
+		{"noProxy", &config.ProxyConf{URL: proxyURL, Username: "user", Password: "pass", NoProxy: "dataowner1"}, nil,
+			false, "dataowner1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := HTTPClient(mtls, c.identities, c.proxy)
+			checkErr(t, err)
+			resp, err := client.Get("https://" + addr)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if c.errMsg == "" && err != nil {
+				t.Errorf("expected success, got: %v", err)
+			}
+			if c.errMsg != "" && (err == nil || !strings.Contains(err.Error(), c.errMsg)) {
+				t.Errorf("expected error containing %q, got: %v", c.errMsg, err)
+			}
+			select {
+			case host := <-requested:
+				if !c.proxied || host != addr {
+					t.Errorf("unexpected request of %s through the proxy", host)
+				}
+			default:
+				if c.proxied {
+					t.Error("expected the request goes through the proxy")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// reloadDelay is the time to wait for more changes after a certificate file changed,
+// as the certificate and the key are usually replaced one after another
+const reloadDelay = 500 * time.Millisecond
+
+var (
+	logger = logrus.WithField("module", "util.tlsutil")
+
+	reloadersLock sync.Mutex
+	// reloaders are shared by the servers and clients presenting the same certificate,
+	// the key is the certificate file and the key file, so that the files are watched once
+	reloaders = make(map[[2]string]*CertReloader)
+)
+
+// CertReloader presents the certificate loaded from certFile and keyFile, and reloads it when the files change,
+// so that rotated certificates apply to new connections without restarting, and established connections are kept.
+// A certificate failed to be loaded is rejected, and the current one is kept in use.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.RWMutex
+	cert    *tls.Certificate
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NewCertReloader loads the certificate from certFile and keyFile, it fails if the certificate is invalid
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate again, and replaces the current one if the files changed,
+// changed is false if the files are the same as the ones loaded
+func (r *CertReloader) Reload() (changed bool, err error) {
+	certPEM, err := ioutil.ReadFile(r.certFile)
+	if err != nil {
+		return false, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to load tls certificate %s", r.certFile)
+	}
+	keyPEM, err := ioutil.ReadFile(r.keyFile)
+	if err != nil {
+		return false, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to load tls key %s", r.keyFile)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM) {
+		return false, nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to load tls certificate %s", r.certFile)
+	}
+	r.cert, r.certPEM, r.keyPEM = &cert, certPEM, keyPEM
+	return true, nil
+}
+
+// Certificate returns the certificate in use
+func (r *CertReloader) Certificate() *tls.Certificate {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cert
+}
+
+// GetCertificate is set to tls.Config of servers, it's called on each handshake
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate is set to tls.Config of clients, it's called on each handshake requiring client certificates
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// Watch reloads the certificate when the files change, and every interval if it's positive, until stop is closed.
+// The directories of the files are watched, so that the files replaced by renaming are reloaded too.
+func (r *CertReloader) Watch(interval time.Duration, stop <-chan struct{}) {
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer watcher.Close()
+		for _, dir := range []string{filepath.Dir(r.certFile), filepath.Dir(r.keyFile)} {
+			if err = watcher.Add(dir); err != nil {
+				break
+			}
+		}
+		events, errs = watcher.Events, watcher.Errors
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("failed to watch tls certificate %s, it's reloaded only every %v", r.certFile, interval)
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	delay := time.NewTimer(reloadDelay)
+	delay.Stop()
+	defer delay.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0 {
+				delay.Reset(reloadDelay)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			logger.WithError(err).Errorf("error watching tls certificate %s", r.certFile)
+		case <-delay.C:
+			r.reloadAndLog()
+		case <-tick:
+			r.reloadAndLog()
+		}
+	}
+}
+
+// reloadAndLog reloads the certificate, a certificate failed to be loaded is logged as an error
+func (r *CertReloader) reloadAndLog() {
+	changed, err := r.Reload()
+	if err != nil {
+		logger.WithError(err).Errorf("failed to reload tls certificate %s, keep using the current one, "+
+			"fix the certificate and the key %s", r.certFile, r.keyFile)
+		return
+	}
+	if changed {
+		logger.Infof("tls certificate %s reloaded, it applies to new connections", r.certFile)
+	}
+}
+
+// certReloader returns the reloader of the certificate of conf, which is watched for the life of the process.
+// The ReloadInterval of the first conf with the same certificate applies.
+func certReloader(conf *config.TLSConf) (*CertReloader, error) {
+	reloadersLock.Lock()
+	defer reloadersLock.Unlock()
+	key := [2]string{conf.CertFile, conf.KeyFile}
+	if r, ok := reloaders[key]; ok {
+		return r, nil
+	}
+	r, err := NewCertReloader(conf.CertFile, conf.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	go r.Watch(conf.ReloadInterval, nil)
+	reloaders[key] = r
+	return r, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// serverSerial returns the serial number of the certificate presented by the server at addr
+func serverSerial(t *testing.T, addr string, ca *testCA) string {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: pool, NextProtos: []string{"h2"}})
+	checkErr(t, err)
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.String()
+}
+
+func TestCertReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	certFile, keyFile := ca.issue(t, dir, "executor1")
+	addr := startServer(t, &config.TLSConf{CertFile: certFile, KeyFile: keyFile})
+	clientConf := &config.TLSConf{CAFile: ca.file}
+
+	// a connection established before the rotation is kept
+	dialOpt, err := DialOption(clientConf)
+	checkErr(t, err)
+	conn, err := grpc.Dial(addr, dialOpt)
+	checkErr(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	checkErr(t, err)
+
+	old := serverSerial(t, addr, ca)
+	ca.issue(t, dir, "executor1")
+	deadline := time.Now().Add(5 * time.Second)
+	for serverSerial(t, addr, ca) == old {
+		if time.Now().After(deadline) {
+			t.Fatal("the rotated certificate was not reloaded")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	rotated := serverSerial(t, addr, ca)
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	checkErr(t, err)
+
+	// an invalid certificate is rejected and the current one is kept
+	checkErr(t, ioutil.WriteFile(certFile, []byte("invalid"), 0600))
+	time.Sleep(3 * reloadDelay)
+	if serial := serverSerial(t, addr, ca); serial != rotated {
+		t.Errorf("expected the current certificate kept, got serial %s, want %s", serial, rotated)
+	}
+	checkErr(t, check(addr, clientConf))
+}
+
+func TestCertReloaderInterval(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	certFile, keyFile := ca.issue(t, dir, "executor1")
+	r, err := NewCertReloader(certFile, keyFile)
+	checkErr(t, err)
+	if changed, err := r.Reload(); err != nil || changed {
+		t.Fatalf("expected unchanged certificate, got changed %t, err: %v", changed, err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go r.Watch(50*time.Millisecond, stop)
+	old := r.Certificate()
+	ca.issue(t, dir, "executor1")
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Certificate() == old {
+		if time.Now().After(deadline) {
+			t.Fatal("the rotated certificate was not reloaded")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if _, err := NewCertReloader(certFile, ca.file); err == nil {
+		t.Error("mismatched key passed")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tlsutil loads the TLS credentials of gRPC servers and clients, and https clients from TLSConf.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// ServerCredentials returns the transport credentials of the gRPC server,
+// client certificates are required and verified against CAFile if ClientAuth is true.
+// The certificate is reloaded when CertFile or KeyFile changes, see CertReloader.
+func ServerCredentials(conf *config.TLSConf) (credentials.TransportCredentials, error) {
+	reloader, err := certReloader(conf)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if conf.ClientAuth {
+		pool, err := loadCertPool(conf.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.ClientCAs = pool
+		tlsConf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(tlsConf), nil
+}
+
+// ClientCredentials returns the transport credentials used to connect to TLS servers,
+// the server certificate is verified against CAFile, or the system roots if CAFile is empty,
+// and the local certificate is presented in case the server requires mutual TLS
+func ClientCredentials(conf *config.TLSConf) (credentials.TransportCredentials, error) {
+	tlsConf, err := clientTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConf), nil
+}
+
+// DialOption returns the transport dial option of gRPC clients, which is plaintext if conf is nil
+func DialOption(conf *config.TLSConf) (grpc.DialOption, error) {
+	if conf == nil {
+		return grpc.WithInsecure(), nil
+	}
+	creds, err := ClientCredentials(conf)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(creds), nil
+}
+
+// clientTLSConfig returns the tls config presenting the local certificate and verifying servers against CAFile,
+// the certificate is reloaded when CertFile or KeyFile changes
+func clientTLSConfig(conf *config.TLSConf) (*tls.Config, error) {
+	tlsConf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if conf.CertFile != "" {
+		reloader, err := certReloader(conf)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.GetClientCertificate = reloader.GetClientCertificate
+	}
+	if conf.CAFile != "" {
+		pool, err := loadCertPool(conf.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.RootCAs = pool
+	}
+	return tlsConf, nil
+}
+
+// loadCertPool reads PEM encoded CA certificates from caFile
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to read tls ca file %s", caFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errorx.New(errorx.ErrCodeConfig, "no certificate found in tls ca file %s", caFile)
+	}
+	return pool, nil
+}
+
+// ClientConf returns the TLSConf of command line clients connecting to executors whose gRPC servers require TLS,
+// the certificate is only needed if the servers require mutual TLS. It returns nil if no file is given,
+// so that clients connect in plaintext.
+func ClientConf(caFile, certFile, keyFile string) *config.TLSConf {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil
+	}
+	return &config.TLSConf{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}
+}
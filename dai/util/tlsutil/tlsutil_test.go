@@ -0,0 +1,181 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/PaddlePaddle/PaddleDTX/dai/config"
+)
+
+// testCA signs certificates for tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	checkErr(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	checkErr(t, err)
+	cert, err := x509.ParseCertificate(der)
+	checkErr(t, err)
+	file := filepath.Join(dir, name+".pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// issue writes a certificate for 127.0.0.1 and the DNS name of name signed by ca, and returns the certificate and key files
+func (ca *testCA) issue(t *testing.T, dir, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	checkErr(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{name},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	checkErr(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	checkErr(t, err)
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDer)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file, typ string, der []byte) {
+	checkErr(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+}
+
+// startServer starts a gRPC server with health service over TLS, and returns its address
+func startServer(t *testing.T, conf *config.TLSConf) string {
+	creds, err := ServerCredentials(conf)
+	checkErr(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	checkErr(t, err)
+	srv := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// check connects to addr with conf and calls the health service
+func check(addr string, conf *config.TLSConf) error {
+	dialOpt, err := DialOption(conf)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, dialOpt)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	otherCA := newTestCA(t, dir, "other-ca")
+	serverCert, serverKey := ca.issue(t, dir, "executor1")
+	clientCert, clientKey := ca.issue(t, dir, "executor2")
+	untrustedCert, untrustedKey := otherCA.issue(t, dir, "executor3")
+
+	tlsAddr := startServer(t, &config.TLSConf{CertFile: serverCert, KeyFile: serverKey})
+	mtlsAddr := startServer(t, &config.TLSConf{CertFile: serverCert, KeyFile: serverKey, CAFile: ca.file, ClientAuth: true})
+
+	cases := []struct {
+		name    string
+		addr    string
+		conf    *config.TLSConf
+		success bool
+	}{
+		{"tls", tlsAddr, &config.TLSConf{CAFile: ca.file}, true},
+		{"plaintextToTLS", tlsAddr, nil, false},
+		{"untrustedServer", tlsAddr, &config.TLSConf{CAFile: otherCA.file}, false},
+		{"mtls", mtlsAddr, &config.TLSConf{CertFile: clientCert, KeyFile: clientKey, CAFile: ca.file}, true},
+		{"mtlsWithoutClientCert", mtlsAddr, &config.TLSConf{CAFile: ca.file}, false},
+		{"mtlsUntrustedClient", mtlsAddr, &config.TLSConf{CertFile: untrustedCert, KeyFile: untrustedKey, CAFile: ca.file}, false},
+		// command line clients
+		{"cliTLS", tlsAddr, ClientConf(ca.file, "", ""), true},
+		{"cliMTLS", mtlsAddr, ClientConf(ca.file, clientCert, clientKey), true},
+		{"cliPlaintextToTLS", tlsAddr, ClientConf("", "", ""), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := check(c.addr, c.conf)
+			if c.success && err != nil {
+				t.Errorf("expected success, got: %v", err)
+			}
+			if !c.success && err == nil {
+				t.Error("expected failure, got success")
+			}
+		})
+	}
+}
+
+func TestLoadInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	cert, key := ca.issue(t, dir, "executor1")
+	if _, err := ServerCredentials(&config.TLSConf{CertFile: cert, KeyFile: filepath.Join(dir, "missing.key")}); err == nil {
+		t.Error("missing key file passed")
+	}
+	if _, err := ServerCredentials(&config.TLSConf{CertFile: cert, KeyFile: key, CAFile: key, ClientAuth: true}); err == nil {
+		t.Error("ca file without certificates passed")
+	}
+}
+
+func checkErr(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
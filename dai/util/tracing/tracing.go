@@ -0,0 +1,242 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing traces the execution of tasks with OpenTelemetry, spans are exported by OTLP/gRPC
+// if '[executor.tracing]' is configured, otherwise they are no-ops.
+// Each task has a root span on each executor, which is the parent of the spans of PSI, training rounds,
+// storage and blockchain calls, and the trace context is propagated to peer executors over gRPC metadata.
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// StagePSI is the stage aligning samples of parties
+	StagePSI = "psi"
+	// StageRound is a round of training, such as an iteration of gradient descent, or building a tree
+	StageRound = "train.round"
+
+	// shutdownTimeout is the maximum time to export the remaining spans on shutdown
+	shutdownTimeout = 5 * time.Second
+)
+
+var (
+	tracer     = otel.Tracer("github.com/PaddlePaddle/PaddleDTX/dai")
+	propagator = propagation.TraceContext{}
+
+	tasks  sync.Map // root spans of tasks in execution, key is taskID, and value is 'context.Context'
+	stages sync.Map // spans of stages in execution, key is stageKey, and value is 'trace.Span'
+)
+
+// stageKey identifies a stage of task, such as PSI, a task has at most one stage of the same name in execution
+type stageKey struct {
+	taskID string
+	name   string
+}
+
+// Options defines how spans are sampled and exported
+type Options struct {
+	ServiceName string  // name of the node, e.g. the executor's name
+	Endpoint    string  // OTLP/gRPC endpoint of the collector, like "localhost:4317"
+	Insecure    bool    // whether to connect to the collector without TLS
+	SampleRate  float64 // fraction of traces sampled, in [0, 1]
+}
+
+// Init exports spans to the collector, traces started by peers follow the sampling decision of peers.
+// The returned function exports the remaining spans and stops the exporter.
+func Init(opts Options) (func(), error) {
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	// the exporter connects to the collector in background, the node starts even if the collector is down
+	exporter, err := otlptracegrpc.New(context.Background(), clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(opts.ServiceName))),
+	)
+	setProvider(provider)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		provider.Shutdown(ctx)
+	}, nil
+}
+
+// setProvider sets the global tracer provider, replaced by tests to record spans
+func setProvider(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+}
+
+// sourceTaskID returns the id of the task from a user, tasks derived by Evaluator and LiveEvaluator
+// have ids like `{uuid}_{k}_train_Eva`, and share the root span of the source task
+func sourceTaskID(taskID string) string {
+	return strings.SplitN(taskID, "_", 2)[0]
+}
+
+// StartTask starts the root span of task on the node as the child of the span in ctx, such as the span
+// of the executor sending the request to start the task. It's ended by EndTask.
+func StartTask(ctx context.Context, taskID string, attrs ...attribute.KeyValue) {
+	attrs = append([]attribute.KeyValue{attribute.String("task.id", taskID)}, attrs...)
+	_, span := tracer.Start(ctx, "task", trace.WithAttributes(attrs...))
+	// ctx may be the context of a request, the task lives longer than it
+	taskCtx := trace.ContextWithSpan(context.Background(), span)
+	if previous, loaded := tasks.LoadOrStore(taskID, taskCtx); loaded {
+		// the task is started again before it ends, e.g. it's resumed from checkpoint
+		trace.SpanFromContext(previous.(context.Context)).End()
+		tasks.Store(taskID, taskCtx)
+	}
+}
+
+// EndTask ends the root span of task, the task is failed if errMsg is not empty.
+// Stages of the task and its derived tasks still in execution end with the same status.
+func EndTask(taskID, errMsg string) {
+	taskCtx, ok := tasks.LoadAndDelete(taskID)
+	if !ok {
+		return
+	}
+	stages.Range(func(key, value interface{}) bool {
+		if sourceTaskID(key.(stageKey).taskID) == taskID {
+			stages.Delete(key)
+			endSpan(value.(trace.Span), errMsg)
+		}
+		return true
+	})
+	endSpan(trace.SpanFromContext(taskCtx.(context.Context)), errMsg)
+}
+
+// endSpan ends span, which is failed if errMsg is not empty
+func endSpan(span trace.Span, errMsg string) {
+	if errMsg != "" {
+		span.SetStatus(codes.Error, errMsg)
+	}
+	span.End()
+}
+
+// StartStage starts the span of a stage of task, which is named name, as the child of the task's root span.
+// It's ended by EndStage, or by StartStage of the next stage of the same name, e.g. the next training round.
+// Stages are used when the start and the end of a stage are in different functions.
+func StartStage(taskID, name string, attrs ...attribute.KeyValue) {
+	_, span := StartSpan(taskID, name, attrs...)
+	key := stageKey{taskID: taskID, name: name}
+	if previous, loaded := stages.LoadOrStore(key, span); loaded {
+		previous.(trace.Span).End()
+		stages.Store(key, span)
+	}
+}
+
+// EndStage ends the span of a stage of task, err is recorded if it is not nil
+func EndStage(taskID, name string, err error) {
+	if span, ok := stages.LoadAndDelete(stageKey{taskID: taskID, name: name}); ok {
+		End(span.(trace.Span), err)
+	}
+}
+
+// TaskContext returns the context carrying the root span of task,
+// context.Background() is returned if the task has no span on the node
+func TaskContext(taskID string) context.Context {
+	if taskCtx, ok := tasks.Load(sourceTaskID(taskID)); ok {
+		return taskCtx.(context.Context)
+	}
+	return context.Background()
+}
+
+// StartSpan starts a span as the child of the task's root span, remember to call End when it finishes
+func StartSpan(taskID, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Start(TaskContext(taskID), name, attrs...)
+}
+
+// Start starts a span as the child of the span in ctx, remember to call End when it finishes
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, err is recorded if it is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// UnaryClientInterceptor injects the trace context of ctx into the metadata of outgoing requests
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if trace.SpanContextFromContext(ctx).IsValid() {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			if ok {
+				md = md.Copy()
+			} else {
+				md = metadata.MD{}
+			}
+			propagator.Inject(ctx, metadataCarrier(md))
+			ctx = metadata.NewOutgoingContext(ctx, md)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor extracts the trace context from the metadata of incoming requests into ctx
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = propagator.Extract(ctx, metadataCarrier(md))
+		}
+		return handler(ctx, req)
+	}
+}
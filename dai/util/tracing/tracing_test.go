@@ -0,0 +1,144 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// recorder records spans ended by tests, the tracer delegates to the first provider set only
+var recorder = tracetest.NewSpanRecorder()
+
+func init() {
+	setProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+}
+
+// endedSpans returns the ended spans of the trace by name
+func endedSpans(traceID trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() == traceID {
+			spans[s.Name()] = s
+		}
+	}
+	return spans
+}
+
+func TestTaskSpans(t *testing.T) {
+	StartTask(context.Background(), "task1")
+	root := trace.SpanContextFromContext(TaskContext("task1"))
+	if !root.IsValid() {
+		t.Fatal("root span of task is not started")
+	}
+	if trace.SpanContextFromContext(TaskContext("task2")).IsValid() {
+		t.Error("unexpected root span of a task not started")
+	}
+	// derived tasks share the root span of the source task
+	if TaskContext("task1_0_train_Eva") != TaskContext("task1") {
+		t.Error("derived task has no root span of the source task")
+	}
+
+	StartStage("task1", StagePSI)
+	EndStage("task1", StagePSI, nil)
+	StartStage("task1", StageRound)
+	StartStage("task1", StageRound) // ends the first round
+	StartStage("task1_0_train_Eva", StagePSI)
+	_, span := StartSpan("task1", "storage.Upload")
+	End(span, errors.New("disk full"))
+	EndTask("task1", "task failed")
+
+	if trace.SpanContextFromContext(TaskContext("task1")).IsValid() {
+		t.Error("root span of task is not removed after the task ends")
+	}
+	spans := endedSpans(root.TraceID())
+	for _, name := range []string{"task", StagePSI, StageRound, "storage.Upload"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Fatalf("span %s is not ended", name)
+		}
+		if name != "task" && s.Parent().SpanID() != root.SpanID() {
+			t.Errorf("span %s is not the child of the root span", name)
+		}
+	}
+	if spans["task"].Status().Code != codes.Error || spans["storage.Upload"].Status().Code != codes.Error {
+		t.Error("failures are not recorded")
+	}
+	var rounds, failedPSI int
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() != root.TraceID() {
+			continue
+		}
+		if s.Name() == StageRound {
+			rounds++
+		}
+		if s.Name() == StagePSI && s.Status().Code == codes.Error {
+			failedPSI++
+		}
+	}
+	if rounds != 2 || failedPSI != 1 {
+		t.Errorf("expected 2 rounds and 1 failed PSI of the derived task, got %d rounds and %d failed PSI", rounds, failedPSI)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	StartTask(context.Background(), "task3")
+	defer EndTask("task3", "")
+	root := trace.SpanContextFromContext(TaskContext("task3"))
+
+	// the client injects the trace context into metadata, which the server extracts
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := UnaryClientInterceptor()(TaskContext("task3"), "/task.Task/StartTask", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if len(outgoing.Get("traceparent")) == 0 {
+		t.Fatal("trace context is not injected into metadata")
+	}
+
+	var remote trace.SpanContext
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		StartTask(ctx, "task4")
+		remote = trace.SpanContextFromContext(TaskContext("task4"))
+		EndTask("task4", "")
+		return nil, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), outgoing)
+	if _, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if remote.TraceID() != root.TraceID() || remote.SpanID() == root.SpanID() {
+		t.Error("the task on the peer is not in the same trace")
+	}
+
+	// nothing is injected without a span
+	outgoing = nil
+	if err := UnaryClientInterceptor()(context.Background(), "/task.Task/StartTask", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if len(outgoing.Get("traceparent")) != 0 {
+		t.Error("unexpected trace context")
+	}
+}
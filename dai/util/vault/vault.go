@@ -0,0 +1,170 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault reads secrets from the KV secrets engine of HashiCorp Vault over its HTTP API.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// DefaultTimeout is the timeout of requests to Vault if it is not configured
+const DefaultTimeout = 10 * time.Second
+
+// Options defines the Vault server and the credentials used to authenticate.
+// If Token is empty, the client logs in with RoleID and SecretID using the AppRole auth method.
+type Options struct {
+	Address  string        // address of the Vault server, e.g. "https://127.0.0.1:8200"
+	Token    string        // Vault token
+	RoleID   string        // role ID of the AppRole auth method
+	SecretID string        // secret ID of the AppRole auth method
+	CAFile   string        // CA certificates to verify the Vault server, the system roots are used if it is empty
+	Timeout  time.Duration // timeout of each request, the default is DefaultTimeout
+}
+
+// Client reads secrets from Vault with a token
+type Client struct {
+	address    string
+	token      string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// response is the common part of Vault's responses
+type response struct {
+	Data map[string]interface{} `json:"data"`
+	Auth *struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewClient returns a client of the Vault server, the client logs in with AppRole if no token is given
+func NewClient(opt Options) (*Client, error) {
+	if opt.Address == "" {
+		return nil, errorx.New(errorx.ErrCodeConfig, "vault address is empty")
+	}
+	if opt.Token == "" && (opt.RoleID == "" || opt.SecretID == "") {
+		return nil, errorx.New(errorx.ErrCodeConfig, "vault token or roleID and secretID are required")
+	}
+	c := &Client{
+		address:    strings.TrimRight(opt.Address, "/"),
+		token:      opt.Token,
+		timeout:    opt.Timeout,
+		httpClient: &http.Client{},
+	}
+	if c.timeout == 0 {
+		c.timeout = DefaultTimeout
+	}
+	if opt.CAFile != "" {
+		pem, err := ioutil.ReadFile(opt.CAFile)
+		if err != nil {
+			return nil, errorx.NewCode(err, errorx.ErrCodeConfig, "failed to read vault ca file %s", opt.CAFile)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errorx.New(errorx.ErrCodeConfig, "no certificate found in vault ca file %s", opt.CAFile)
+		}
+		c.httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+	if c.token == "" {
+		if err := c.login(opt.RoleID, opt.SecretID); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// login gets a token by the AppRole auth method
+func (c *Client) login(roleID, secretID string) error {
+	body, err := json.Marshal(map[string]string{"role_id": roleID, "secret_id": secretID})
+	if err != nil {
+		return errorx.NewCode(err, errorx.ErrCodeEncoding, "failed to marshal vault login request")
+	}
+	resp, err := c.do(http.MethodPost, "auth/approle/login", body)
+	if err != nil {
+		return errorx.Wrap(err, "failed to login vault with approle")
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return errorx.New(errorx.ErrCodeNotAuthorized, "no token returned by vault approle login")
+	}
+	c.token = resp.Auth.ClientToken
+	return nil
+}
+
+// ReadField returns the string value of field in the secret at path. Both versions of the KV secrets engine
+// are supported, the path of a KV version 2 secret includes "data", e.g. "secret/data/executor1".
+func (c *Client) ReadField(path, field string) (string, error) {
+	resp, err := c.do(http.MethodGet, strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", errorx.Wrap(err, "failed to read vault secret %s", path)
+	}
+	data := resp.Data
+	// KV version 2 nests the secret in "data" along with its "metadata"
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	value, ok := data[field].(string)
+	if !ok || value == "" {
+		return "", errorx.New(errorx.ErrCodeNotFound, "field %s not found in vault secret %s", field, path)
+	}
+	return value, nil
+}
+
+// do sends a request to the Vault API and decodes the response
+func (c *Client) do(method, path string, body []byte) (*response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to new request")
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to do request")
+	}
+	defer resp.Body.Close()
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errorx.NewCode(err, errorx.ErrCodeInternal, "failed to read response")
+	}
+	// the errors of a failed request are only informative, they may not be returned by Vault but a proxy
+	var r response
+	if err := json.Unmarshal(bs, &r); err != nil && resp.StatusCode < 300 {
+		return nil, errorx.NewCode(err, errorx.ErrCodeEncoding, "failed to decode response")
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errorx.New(errorx.ErrCodeNotFound, "not found in vault")
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return nil, errorx.New(errorx.ErrCodeNotAuthorized, "permission denied by vault: %s", strings.Join(r.Errors, "; "))
+	case resp.StatusCode >= 300:
+		return nil, errorx.New(errorx.ErrCodeInternal, "vault returned status %d: %s", resp.StatusCode, strings.Join(r.Errors, "; "))
+	}
+	return &r, nil
+}
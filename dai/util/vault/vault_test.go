@@ -0,0 +1,117 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PaddlePaddle/PaddleDTX/xdb/errorx"
+)
+
+// newServer starts a fake Vault server, which issues "s.approle" to the AppRole "executor",
+// and serves a KV version 1 secret at "kv/executor1" and a version 2 one at "secret/data/executor1"
+func newServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/approle/login" {
+			var req map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req["role_id"] != "executor" || req["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+			w.Write([]byte(`{"auth":{"client_token":"s.approle"}}`))
+			return
+		}
+		if token := r.Header.Get("X-Vault-Token"); token != "s.token" && token != "s.approle" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/executor1":
+			w.Write([]byte(`{"data":{"privateKey":"kv1"}}`))
+		case "/v1/secret/data/executor1":
+			w.Write([]byte(`{"data":{"data":{"privateKey":"kv2"},"metadata":{"version":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReadField(t *testing.T) {
+	server := newServer(t)
+	for name, opt := range map[string]Options{
+		"token":   {Address: server.URL, Token: "s.token"},
+		"approle": {Address: server.URL + "/", RoleID: "executor", SecretID: "secret"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClient(opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for path, expected := range map[string]string{"kv/executor1": "kv1", "/secret/data/executor1": "kv2"} {
+				value, err := client.ReadField(path, "privateKey")
+				if err != nil {
+					t.Fatalf("failed to read %s: %v", path, err)
+				}
+				if value != expected {
+					t.Errorf("expected %s of %s, got %s", expected, path, value)
+				}
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server := newServer(t)
+	if _, err := NewClient(Options{Address: server.URL}); err == nil {
+		t.Error("expected error without credentials")
+	}
+	if _, err := NewClient(Options{Address: server.URL, RoleID: "executor", SecretID: "wrong"}); err == nil {
+		t.Error("expected error of invalid secret ID")
+	}
+
+	client, err := NewClient(Options{Address: server.URL, Token: "s.token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		path, field, code string
+	}{
+		"secretNotFound": {"secret/data/executor2", "privateKey", errorx.ErrCodeNotFound},
+		"fieldNotFound":  {"kv/executor1", "key", errorx.ErrCodeNotFound},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.ReadField(c.path, c.field)
+			if err == nil || !errorx.Is(err, c.code) {
+				t.Errorf("expected error of code %s, got %v", c.code, err)
+			}
+		})
+	}
+
+	denied, err := NewClient(Options{Address: server.URL, Token: "s.revoked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := denied.ReadField("kv/executor1", "privateKey"); err == nil || !errorx.Is(err, errorx.ErrCodeNotAuthorized) {
+		t.Errorf("expected error of permission denied, got %v", err)
+	}
+}
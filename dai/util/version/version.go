@@ -0,0 +1,32 @@
+// Copyright (c) 2021 PaddlePaddle Authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package version holds the build information of PaddleDTX dai binaries,
+// GitCommit and BuildDate are embedded by ldflags when built by `make build`
+package version
+
+import "fmt"
+
+var (
+	// Version is the release version of the binaries
+	Version = "1.1.0"
+	// GitCommit is the commit the binaries are built from
+	GitCommit = "unknown"
+	// BuildDate is the time when the binaries are built, in RFC3339
+	BuildDate = "unknown"
+)
+
+// String returns the build information in one line
+func String() string {
+	return fmt.Sprintf("version: %s, git commit: %s, build date: %s", Version, GitCommit, BuildDate)
+}
@@ -3,6 +3,7 @@ import paddle_fl.mpc as pfl_mpc
 import numpy as np
 import paddle.fluid as fluid
 import logging
+import math
 import mpc_network
 import os
 import time
@@ -30,6 +31,15 @@ def parse_args():
     parser.add_argument('--output_size', type=int, default=1, help='output_size')
     parser.add_argument('--output_file', help='output_file')
     parser.add_argument('--epochs', type=int, default=5, help='epochs')
+    parser.add_argument('--optimizer', type=str, default='sgd', help='sgd or adam')
+    parser.add_argument('--beta1', type=float, default=0.9, help='beta1 of adam')
+    parser.add_argument('--beta2', type=float, default=0.999, help='beta2 of adam')
+    parser.add_argument('--epsilon', type=float, default=1e-8, help='epsilon of adam')
+    parser.add_argument('--lr_schedule', type=str, default='constant', help='constant, step or cosine')
+    parser.add_argument('--step_size', type=int, default=1, help='epochs between decays of step schedule')
+    parser.add_argument('--gamma', type=float, default=0.1, help='decay factor of step schedule')
+    parser.add_argument('--total_epochs', type=int, default=1, help='epochs of cosine schedule')
+    parser.add_argument('--min_lr', type=float, default=0.0, help='minimum learning rate of cosine schedule')
 
     args = parser.parse_args()
     return args
@@ -47,9 +57,14 @@ def train(args):
                                       int(part_size[2]),
                                       args.output_size)
     loss, l3 = dnn_model.net(inputs)
-    lr = args.base_lr
-    sgd = pfl_mpc.optimizer.SGD(learning_rate=lr)
-    sgd.minimize(loss)
+    # learning rate is a variable updated at the beginning of each epoch by the schedule
+    lr = fluid.layers.create_global_var(shape=[1], value=args.base_lr, dtype='float32',
+                                        persistable=True, name='learning_rate')
+    if args.optimizer == 'adam':
+        optimizer = pfl_mpc.optimizer.Adam(learning_rate=lr, beta1=args.beta1, beta2=args.beta2, epsilon=args.epsilon)
+    else:
+        optimizer = pfl_mpc.optimizer.SGD(learning_rate=lr)
+    optimizer.minimize(loss)
 
     place = fluid.CUDAPlace(0) if args.use_gpu else fluid.CPUPlace()
     exe = fluid.Executor(place)
@@ -91,6 +106,8 @@ def train(args):
     logger.info('Start training...')
     begin = time.time()
     for epoch in range(args.epochs):
+        fluid.global_scope().find_var(lr.name).get_tensor().set(
+            np.array([learning_rate(args, epoch)], dtype='float32'), place)
         for i in range(args.batch_num):
             loss_data = exe.run(fluid.default_main_program(),
                                 feed={'part0': part0_vecs[i],
@@ -116,6 +133,19 @@ def train(args):
 
 
 
+def learning_rate(args, epoch):
+    """
+    learning rate of epoch by the schedule, same as that of linear and logistic regression
+    """
+    if args.lr_schedule == 'step':
+        return args.base_lr * args.gamma ** (epoch // args.step_size)
+    if args.lr_schedule == 'cosine':
+        if epoch >= args.total_epochs:
+            return args.min_lr
+        return args.min_lr + (args.base_lr - args.min_lr) * (1 + math.cos(math.pi * epoch / args.total_epochs)) / 2
+    return args.base_lr
+
+
 def read_share(file, shape):
     """
     prepare share reader